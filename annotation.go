@@ -0,0 +1,205 @@
+package influxdb
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrAnnotationNotFound is the error msg for a missing annotation.
+const ErrAnnotationNotFound = "annotation not found"
+
+// DefaultAnnotationStream is the stream of annotations written without one.
+const DefaultAnnotationStream = "default"
+
+// ops for annotation errors and op log.
+const (
+	OpFindAnnotationByID = "FindAnnotationByID"
+	OpFindAnnotations    = "FindAnnotations"
+	OpCreateAnnotations  = "CreateAnnotations"
+	OpUpdateAnnotation   = "UpdateAnnotation"
+	OpDeleteAnnotation   = "DeleteAnnotation"
+)
+
+// Annotation marks an event, such as a deploy or an incident, over a range of
+// time so it can be overlaid on graphs. An annotation that ends when it starts
+// marks a point in time. Annotations are grouped into streams, such as
+// deploys, that graphs select the annotations they overlay by.
+type Annotation struct {
+	ID    ID `json:"id,omitempty"`
+	OrgID ID `json:"orgID,omitempty"`
+
+	Stream  string            `json:"stream"`
+	Summary string            `json:"summary"`
+	Message string            `json:"message,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+
+	// CreatedBy is the user that wrote the annotation.
+	CreatedBy ID `json:"createdBy,omitempty"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the annotation is missing what it needs.
+func (a *Annotation) Valid() error {
+	if !a.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if strings.TrimSpace(a.Stream) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "annotation stream is required",
+		}
+	}
+	if strings.TrimSpace(a.Summary) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "annotation summary is required",
+		}
+	}
+	for k := range a.Tags {
+		if strings.TrimSpace(k) == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "annotation tag keys must not be empty",
+			}
+		}
+	}
+	if a.StartTime.IsZero() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "annotation startTime is required",
+		}
+	}
+	if a.EndTime.Before(a.StartTime) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "annotation must not end before it starts",
+		}
+	}
+	return nil
+}
+
+// HasTags reports whether the annotation has each of the tags.
+func (a *Annotation) HasTags(tags map[string]string) bool {
+	for k, v := range tags {
+		if value, ok := a.Tags[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// SortAnnotations sorts annotations by their start time, then by ID.
+func SortAnnotations(as []*Annotation) {
+	sort.Slice(as, func(i, j int) bool {
+		if !as[i].StartTime.Equal(as[j].StartTime) {
+			return as[i].StartTime.Before(as[j].StartTime)
+		}
+		return as[i].ID < as[j].ID
+	})
+}
+
+// AnnotationUpdate is the set of changes to an annotation. The tags of an
+// update replace the tags of the annotation.
+type AnnotationUpdate struct {
+	Stream    *string            `json:"stream,omitempty"`
+	Summary   *string            `json:"summary,omitempty"`
+	Message   *string            `json:"message,omitempty"`
+	Tags      *map[string]string `json:"tags,omitempty"`
+	StartTime *time.Time         `json:"startTime,omitempty"`
+	EndTime   *time.Time         `json:"endTime,omitempty"`
+}
+
+// Apply applies the update to the annotation.
+func (u AnnotationUpdate) Apply(a *Annotation) {
+	if u.Stream != nil {
+		a.Stream = *u.Stream
+	}
+	if u.Summary != nil {
+		a.Summary = *u.Summary
+	}
+	if u.Message != nil {
+		a.Message = *u.Message
+	}
+	if u.Tags != nil {
+		a.Tags = *u.Tags
+	}
+	if u.StartTime != nil {
+		a.StartTime = *u.StartTime
+	}
+	if u.EndTime != nil {
+		a.EndTime = *u.EndTime
+	}
+}
+
+// AnnotationFilter selects annotations. Start and Stop select the annotations
+// that overlap the time range between them, in part or in whole; the start of
+// the range is inclusive and its stop exclusive.
+type AnnotationFilter struct {
+	ID      *ID
+	OrgID   *ID
+	Streams []string
+	Start   *time.Time
+	Stop    *time.Time
+	// Tags selects the annotations that have each of the tags.
+	Tags map[string]string
+}
+
+// Matches reports whether the annotation is selected by the filter.
+func (f AnnotationFilter) Matches(a *Annotation) bool {
+	if f.ID != nil && *f.ID != a.ID {
+		return false
+	}
+	if f.OrgID != nil && *f.OrgID != a.OrgID {
+		return false
+	}
+	if len(f.Streams) > 0 {
+		found := false
+		for _, s := range f.Streams {
+			if s == a.Stream {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Start != nil && a.EndTime.Before(*f.Start) {
+		return false
+	}
+	if f.Stop != nil && !a.StartTime.Before(*f.Stop) {
+		return false
+	}
+	return a.HasTags(f.Tags)
+}
+
+// AnnotationService manages the annotations of organizations.
+type AnnotationService interface {
+	// FindAnnotationByID returns a single annotation by ID.
+	FindAnnotationByID(ctx context.Context, id ID) (*Annotation, error)
+
+	// FindAnnotations returns the annotations that match the filter ordered by
+	// their start time, and the total count of matching annotations.
+	FindAnnotations(ctx context.Context, filter AnnotationFilter, opt ...FindOptions) ([]*Annotation, int, error)
+
+	// CreateAnnotations creates the annotations and sets their IDs; either all
+	// of them are created or none are. Annotations without a stream are written
+	// to the DefaultAnnotationStream, annotations without a start time start now,
+	// and annotations without an end time mark their start time.
+	CreateAnnotations(ctx context.Context, as []*Annotation) error
+
+	// UpdateAnnotation updates a single annotation with changeset.
+	UpdateAnnotation(ctx context.Context, id ID, upd AnnotationUpdate) (*Annotation, error)
+
+	// DeleteAnnotation removes an annotation by ID.
+	DeleteAnnotation(ctx context.Context, id ID) error
+}
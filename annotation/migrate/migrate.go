@@ -0,0 +1,300 @@
+// Package migrate imports the annotations of Chronograf, the UI of the 1.x TICK stack,
+// into the annotations service.
+//
+// Chronograf stores annotations as points of the annotations measurement of its
+// chronograf database: each point is tagged with the id of the annotation, is written at
+// the end of the annotation and has the deleted, start_time, modified_time_ns, text and
+// type fields. Editing or deleting an annotation writes a new point rather than changing
+// the old one, so the importer keeps only the latest point of each annotation.
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/models"
+)
+
+const (
+	// DefaultStream is the stream annotations are imported into when the options don't name one.
+	DefaultStream = "chronograf"
+	// Measurement is the measurement Chronograf stores annotations in.
+	Measurement = "annotations"
+
+	// IDTag is the tag of an imported annotation that holds its Chronograf id.
+	IDTag = "chronografID"
+	// TypeTag is the tag of an imported annotation that holds its Chronograf type.
+	TypeTag = "type"
+)
+
+// Options configure an import.
+type Options struct {
+	// OrgID is the organization the annotations are imported into.
+	OrgID influxdb.ID
+	// Stream is the stream the annotations are imported into.
+	Stream string
+}
+
+func (o Options) stream() string {
+	if o.Stream == "" {
+		return DefaultStream
+	}
+	return o.Stream
+}
+
+// Result counts the annotations of an import.
+type Result struct {
+	// Imported is the number of annotations created.
+	Imported int `json:"imported"`
+	// Skipped is the number of annotations already imported by an earlier import.
+	Skipped int `json:"skipped"`
+}
+
+// Importer imports Chronograf annotations into an annotations service. Importing the
+// same annotations again skips those already imported, so an import can be retried.
+type Importer struct {
+	AnnotationService influxdb.AnnotationService
+	Options
+}
+
+// ImportStore imports the annotations of a Chronograf annotation store between start and stop.
+func (i *Importer) ImportStore(ctx context.Context, store chronograf.AnnotationStore, start, stop time.Time) (*Result, error) {
+	as, err := store.All(ctx, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return i.Import(ctx, as)
+}
+
+// ImportLineProtocol imports the annotations of a line protocol export of the chronograf database.
+func (i *Importer) ImportLineProtocol(ctx context.Context, r io.Reader) (*Result, error) {
+	as, err := ReadLineProtocol(r)
+	if err != nil {
+		return nil, err
+	}
+	return i.Import(ctx, as)
+}
+
+// Import imports the Chronograf annotations.
+func (i *Importer) Import(ctx context.Context, as []chronograf.Annotation) (*Result, error) {
+	converted, err := Convert(as, i.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, stream := i.OrgID, i.stream()
+	existing, _, err := i.AnnotationService.FindAnnotations(ctx, influxdb.AnnotationFilter{
+		OrgID:   &orgID,
+		Streams: []string{stream},
+	})
+	if err != nil {
+		return nil, err
+	}
+	imported := make(map[string]bool, len(existing))
+	for _, a := range existing {
+		if id, ok := a.Tags[IDTag]; ok {
+			imported[id] = true
+		}
+	}
+
+	res := &Result{}
+	create := make([]*influxdb.Annotation, 0, len(converted))
+	for _, a := range converted {
+		if imported[a.Tags[IDTag]] {
+			res.Skipped++
+			continue
+		}
+		create = append(create, a)
+	}
+	if len(create) > 0 {
+		if err := i.AnnotationService.CreateAnnotations(ctx, create); err != nil {
+			return nil, err
+		}
+	}
+	res.Imported = len(create)
+	return res, nil
+}
+
+// Convert converts Chronograf annotations into annotations of the organization and stream
+// of the options. Annotations without text are summarized by their type.
+func Convert(as []chronograf.Annotation, opts Options) ([]*influxdb.Annotation, error) {
+	if !opts.OrgID.Valid() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+
+	converted := make([]*influxdb.Annotation, 0, len(as))
+	for _, a := range as {
+		if a.ID == "" {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "chronograf annotation id is required",
+			}
+		}
+
+		summary := strings.TrimSpace(a.Text)
+		if summary == "" {
+			summary = a.Type
+		}
+		if summary == "" {
+			summary = "chronograf annotation " + a.ID
+		}
+
+		tags := map[string]string{IDTag: a.ID}
+		if a.Type != "" {
+			tags[TypeTag] = a.Type
+		}
+
+		end := a.EndTime
+		if end.Before(a.StartTime) {
+			end = a.StartTime
+		}
+
+		converted = append(converted, &influxdb.Annotation{
+			OrgID:     opts.OrgID,
+			Stream:    opts.stream(),
+			Summary:   summary,
+			Message:   a.Text,
+			Tags:      tags,
+			StartTime: a.StartTime.UTC(),
+			EndTime:   end.UTC(),
+		})
+	}
+	return converted, nil
+}
+
+// annotationPoint is a point of the annotations measurement.
+type annotationPoint struct {
+	chronograf.Annotation
+	deleted  bool
+	modified int64
+}
+
+// ReadLineProtocol reads the annotations of a line protocol export of the chronograf
+// database, such as the one written by influx_inspect export. Comments, statements and
+// points of other measurements are ignored.
+func ReadLineProtocol(r io.Reader) ([]chronograf.Annotation, error) {
+	// The point of an annotation is identified by its id and time; an annotation that
+	// was moved has a deleted point at its old end and a new point at its new end.
+	type key struct {
+		id   string
+		time int64
+	}
+	points := map[key]annotationPoint{}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), models.MaxKeyLength*4)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if !strings.HasPrefix(text, Measurement+",") && !strings.HasPrefix(text, Measurement+" ") {
+			continue
+		}
+
+		pts, err := models.ParsePointsWithPrecisionV1([]byte(text), nil, time.Time{}, "n")
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("unable to parse annotation on line %d", line),
+				Err:  err,
+			}
+		}
+		p, err := decodePoint(pts)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("invalid annotation on line %d", line),
+				Err:  err,
+			}
+		}
+		k := key{id: p.ID, time: p.EndTime.UnixNano()}
+		if cur, ok := points[k]; !ok || p.modified >= cur.modified {
+			points[k] = p
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	latest := map[string]annotationPoint{}
+	for _, p := range points {
+		if p.deleted {
+			continue
+		}
+		cur, ok := latest[p.ID]
+		if !ok || p.modified > cur.modified || (p.modified == cur.modified && p.EndTime.After(cur.EndTime)) {
+			latest[p.ID] = p
+		}
+	}
+
+	as := make([]chronograf.Annotation, 0, len(latest))
+	for _, p := range latest {
+		as = append(as, p.Annotation)
+	}
+	sort.Slice(as, func(i, j int) bool {
+		if !as[i].StartTime.Equal(as[j].StartTime) {
+			return as[i].StartTime.Before(as[j].StartTime)
+		}
+		return as[i].ID < as[j].ID
+	})
+	return as, nil
+}
+
+// decodePoint decodes the points parsed from a line of line protocol, which hold one
+// field each of the same series and time.
+func decodePoint(pts []models.Point) (annotationPoint, error) {
+	p := annotationPoint{}
+	fields := models.Fields{}
+	for _, pt := range pts {
+		fs, err := pt.Fields()
+		if err != nil {
+			return p, err
+		}
+		for name, v := range fs {
+			fields[name] = v
+		}
+	}
+	if len(pts) > 0 {
+		p.ID = string(pts[0].Tags().Get([]byte("id")))
+		p.EndTime = pts[0].Time().UTC()
+	}
+	if p.ID == "" {
+		return p, fmt.Errorf("missing id tag")
+	}
+
+	for name, v := range fields {
+		var ok bool
+		switch name {
+		case "deleted":
+			p.deleted, ok = v.(bool)
+		case "start_time":
+			var ns int64
+			if ns, ok = v.(int64); ok {
+				p.StartTime = time.Unix(0, ns).UTC()
+			}
+		case "modified_time_ns":
+			p.modified, ok = v.(int64)
+		case "text":
+			p.Text, ok = v.(string)
+		case "type":
+			p.Type, ok = v.(string)
+		default:
+			ok = true
+		}
+		if !ok {
+			return p, fmt.Errorf("field %s has the wrong type %T", name, v)
+		}
+	}
+	if p.StartTime.IsZero() {
+		p.StartTime = p.EndTime
+	}
+	return p, nil
+}
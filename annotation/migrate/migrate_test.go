@@ -0,0 +1,145 @@
+package migrate_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/annotation/migrate"
+	"github.com/influxdata/influxdb/chronograf"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+)
+
+// export is a line protocol export of a chronograf database: the deploy annotation was
+// edited, the outage annotation was moved to a new end and the test annotation was deleted.
+const export = `# DDL
+CREATE DATABASE chronograf WITH NAME autogen
+# DML
+# CONTEXT-DATABASE:chronograf
+# CONTEXT-RETENTION-POLICY:autogen
+annotations,id=deploy deleted=false,start_time=1561982400000000000i,modified_time_ns=1i,text="deploy",type="deploys" 1561982400000000000
+annotations,id=deploy deleted=false,start_time=1561982400000000000i,modified_time_ns=2i,text="deployed v1.2",type="deploys" 1561982400000000000
+annotations,id=outage deleted=false,start_time=1561986000000000000i,modified_time_ns=3i,text="outage",type="" 1561989600000000000
+annotations,id=outage deleted=true,start_time=1561986000000000000i,modified_time_ns=4i,text="outage",type="" 1561989600000000000
+annotations,id=outage deleted=false,start_time=1561986000000000000i,modified_time_ns=5i,text="",type="incidents" 1561993200000000000
+annotations,id=test deleted=false,start_time=1561982400000000000i,modified_time_ns=6i,text="test",type="" 1561982400000000000
+annotations,id=test deleted=true,start_time=1561982400000000000i,modified_time_ns=7i,text="test",type="" 1561982400000000000
+cpu,host=a usage_user=10 1561982400000000000
+`
+
+// annotationStore is a Chronograf annotation store that only lists annotations.
+type annotationStore func(ctx context.Context, start, stop time.Time) ([]chronograf.Annotation, error)
+
+func (fn annotationStore) All(ctx context.Context, start, stop time.Time) ([]chronograf.Annotation, error) {
+	return fn(ctx, start, stop)
+}
+
+func (annotationStore) Add(context.Context, *chronograf.Annotation) (*chronograf.Annotation, error) {
+	return nil, chronograf.ErrUpstreamTimeout
+}
+
+func (annotationStore) Delete(context.Context, string) error { return chronograf.ErrUpstreamTimeout }
+
+func (annotationStore) Get(context.Context, string) (*chronograf.Annotation, error) {
+	return nil, chronograf.ErrAnnotationNotFound
+}
+
+func (annotationStore) Update(context.Context, *chronograf.Annotation) error {
+	return chronograf.ErrUpstreamTimeout
+}
+
+func TestReadLineProtocol(t *testing.T) {
+	as, err := migrate.ReadLineProtocol(strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []chronograf.Annotation{
+		{
+			ID:        "deploy",
+			StartTime: time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC),
+			Text:      "deployed v1.2",
+			Type:      "deploys",
+		},
+		{
+			ID:        "outage",
+			StartTime: time.Date(2019, 7, 1, 13, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(2019, 7, 1, 15, 0, 0, 0, time.UTC),
+			Type:      "incidents",
+		},
+	}
+	if !reflect.DeepEqual(as, want) {
+		t.Errorf("got annotations %+v, want %+v", as, want)
+	}
+
+	if _, err := migrate.ReadLineProtocol(strings.NewReader(`annotations,id=a text=1i 1`)); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("got error %v, want an invalid annotation", err)
+	}
+	if _, err := migrate.ReadLineProtocol(strings.NewReader(`annotations text="a" 1`)); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("got error %v, want an invalid annotation", err)
+	}
+}
+
+func TestImporter(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &influxdb.Organization{Name: "org"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	i := &migrate.Importer{
+		AnnotationService: svc,
+		Options:           migrate.Options{OrgID: org.ID},
+	}
+	res, err := i.ImportLineProtocol(ctx, strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (migrate.Result{Imported: 2}); *res != want {
+		t.Errorf("got result %+v, want %+v", *res, want)
+	}
+
+	as, _, err := svc.FindAnnotations(ctx, influxdb.AnnotationFilter{OrgID: &org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as) != 2 {
+		t.Fatalf("got %d annotations, want 2", len(as))
+	}
+	if a := as[0]; a.Stream != migrate.DefaultStream || a.Summary != "deployed v1.2" || !reflect.DeepEqual(a.Tags, map[string]string{migrate.IDTag: "deploy", migrate.TypeTag: "deploys"}) {
+		t.Errorf("got annotation %+v", a)
+	}
+	if a := as[1]; a.Summary != "incidents" || a.Message != "" || !a.EndTime.Equal(time.Date(2019, 7, 1, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("got annotation %+v", a)
+	}
+
+	// Annotations imported before are skipped, so an import can be retried.
+	store := annotationStore(func(ctx context.Context, start, stop time.Time) ([]chronograf.Annotation, error) {
+		return []chronograf.Annotation{
+			{ID: "deploy", StartTime: start, EndTime: start, Text: "deployed v1.2"},
+			{ID: "rollback", StartTime: start, EndTime: start, Text: "rolled back v1.2"},
+		}, nil
+	})
+	start := time.Date(2019, 7, 2, 0, 0, 0, 0, time.UTC)
+	res, err = i.ImportStore(ctx, store, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (migrate.Result{Imported: 1, Skipped: 1}); *res != want {
+		t.Errorf("got result %+v, want %+v", *res, want)
+	}
+
+	i.OrgID = 0
+	if _, err := i.Import(ctx, nil); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("got error %v, want an invalid import", err)
+	}
+}
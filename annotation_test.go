@@ -0,0 +1,85 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestAnnotationFilter_Matches(t *testing.T) {
+	t0 := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	deploy := &influxdb.Annotation{
+		ID:        1,
+		OrgID:     2,
+		Stream:    "deploys",
+		Summary:   "deployed v1.2",
+		Tags:      map[string]string{"service": "api", "env": "prod"},
+		StartTime: t0,
+		EndTime:   t0.Add(10 * time.Minute),
+	}
+	point := &influxdb.Annotation{ID: 3, OrgID: 2, Stream: "deploys", Summary: "restarted", StartTime: t0, EndTime: t0}
+
+	at := func(d time.Duration) *time.Time {
+		t := t0.Add(d)
+		return &t
+	}
+	orgID := influxdb.ID(2)
+
+	tests := []struct {
+		name   string
+		a      *influxdb.Annotation
+		filter influxdb.AnnotationFilter
+		want   bool
+	}{
+		{name: "no filter", a: deploy, want: true},
+		{name: "organization and stream", a: deploy, filter: influxdb.AnnotationFilter{OrgID: &orgID, Streams: []string{"incidents", "deploys"}}, want: true},
+		{name: "other stream", a: deploy, filter: influxdb.AnnotationFilter{Streams: []string{"incidents"}}},
+		{name: "overlaps the start of the range", a: deploy, filter: influxdb.AnnotationFilter{Start: at(5 * time.Minute), Stop: at(time.Hour)}, want: true},
+		{name: "ends at the start of the range", a: deploy, filter: influxdb.AnnotationFilter{Start: at(10 * time.Minute), Stop: at(time.Hour)}, want: true},
+		{name: "ends before the range", a: deploy, filter: influxdb.AnnotationFilter{Start: at(11 * time.Minute)}},
+		{name: "starts at the stop of the range", a: deploy, filter: influxdb.AnnotationFilter{Stop: at(0)}},
+		{name: "point within the range", a: point, filter: influxdb.AnnotationFilter{Start: at(0), Stop: at(time.Minute)}, want: true},
+		{name: "tags", a: deploy, filter: influxdb.AnnotationFilter{Tags: map[string]string{"service": "api"}}, want: true},
+		{name: "other tag value", a: deploy, filter: influxdb.AnnotationFilter{Tags: map[string]string{"service": "web"}}},
+		{name: "missing tag", a: point, filter: influxdb.AnnotationFilter{Tags: map[string]string{"service": "api"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.a); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnotation_Valid(t *testing.T) {
+	t0 := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	valid := influxdb.Annotation{OrgID: 1, Stream: "deploys", Summary: "deployed", StartTime: t0, EndTime: t0}
+
+	tests := []struct {
+		name   string
+		update func(a *influxdb.Annotation)
+		valid  bool
+	}{
+		{name: "valid", update: func(a *influxdb.Annotation) {}, valid: true},
+		{name: "missing organization", update: func(a *influxdb.Annotation) { a.OrgID = 0 }},
+		{name: "missing stream", update: func(a *influxdb.Annotation) { a.Stream = " " }},
+		{name: "missing summary", update: func(a *influxdb.Annotation) { a.Summary = "" }},
+		{name: "empty tag key", update: func(a *influxdb.Annotation) { a.Tags = map[string]string{"": "x"} }},
+		{name: "ends before it starts", update: func(a *influxdb.Annotation) { a.EndTime = t0.Add(-time.Second) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := valid
+			tt.update(&a)
+			err := a.Valid()
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+			if !tt.valid && influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected an invalid error, got %v", err)
+			}
+		})
+	}
+}
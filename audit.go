@@ -0,0 +1,206 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ErrAuditExportNotFound is the error msg for an organization without an audit export.
+const ErrAuditExportNotFound = "audit export not found"
+
+// DefaultAuditRecordLimit is the number of audit records kept per organization
+// when the store has no limit of its own. Older records are removed, so
+// records that must be kept longer are exported.
+const DefaultAuditRecordLimit = 10000
+
+// MinAuditExportEvery is the shortest interval audit records can be exported at.
+const MinAuditExportEvery = time.Minute
+
+// ops for audit errors and op log.
+const (
+	OpRecordAudit       = "RecordAudit"
+	OpFindAuditRecords  = "FindAuditRecords"
+	OpFindAuditExport   = "FindAuditExport"
+	OpFindAuditExports  = "FindAuditExports"
+	OpPutAuditExport    = "PutAuditExport"
+	OpDeleteAuditExport = "DeleteAuditExport"
+)
+
+// AuditKind is the kind of event an audit record is of.
+type AuditKind string
+
+const (
+	// AuditAuth records the creation, change and deletion of authorizations.
+	AuditAuth AuditKind = "auth"
+	// AuditResource records the creation, change and deletion of resources.
+	AuditResource AuditKind = "resource"
+	// AuditSecret records the access to and the change of secrets.
+	AuditSecret AuditKind = "secret"
+)
+
+// AuditRecord is an event of an organization that is kept for compliance.
+type AuditRecord struct {
+	ID           ID           `json:"id"`
+	OrgID        ID           `json:"orgID"`
+	Time         time.Time    `json:"time"`
+	Kind         AuditKind    `json:"kind"`
+	Action       string       `json:"action"`
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   ID           `json:"resourceID,omitempty"`
+	// Key is the key of the secret of secret events.
+	Key string `json:"key,omitempty"`
+	// UserID is the user of the authorization that caused the event, if any.
+	UserID ID `json:"userID,omitempty"`
+}
+
+// AuditRecordFilter selects the audit records of an organization.
+type AuditRecordFilter struct {
+	OrgID ID
+	// After selects the records recorded after the record with the ID.
+	After *ID
+	// Limit is the maximum number of records returned; zero returns all.
+	Limit int
+}
+
+// AuditService records the audit records of organizations.
+type AuditService interface {
+	// RecordAudit records r and sets its ID, and its time if it has none.
+	RecordAudit(ctx context.Context, r *AuditRecord) error
+
+	// FindAuditRecords returns the audit records of an organization that match
+	// the filter, oldest first.
+	FindAuditRecords(ctx context.Context, filter AuditRecordFilter) ([]*AuditRecord, error)
+}
+
+// AuditDestinationType is where audit records are exported to.
+type AuditDestinationType string
+
+const (
+	// AuditDestinationHTTP posts the records to an HTTP(S) URL.
+	AuditDestinationHTTP AuditDestinationType = "http"
+	// AuditDestinationSyslog sends the records to a syslog server at a tcp:// or udp:// URL.
+	AuditDestinationSyslog AuditDestinationType = "syslog"
+	// AuditDestinationS3 writes the records as objects at an s3://bucket/prefix URL.
+	AuditDestinationS3 AuditDestinationType = "s3"
+)
+
+// AuditDestination is an external destination of audit records.
+type AuditDestination struct {
+	Type AuditDestinationType `json:"type"`
+	URL  string               `json:"url"`
+}
+
+// Valid returns an error if the URL of the destination does not fit its type.
+func (d AuditDestination) Valid() error {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "audit destination url is invalid",
+			Err:  err,
+		}
+	}
+
+	var schemes []string
+	switch d.Type {
+	case AuditDestinationHTTP:
+		schemes = []string{"http", "https"}
+	case AuditDestinationSyslog:
+		schemes = []string{"tcp", "udp"}
+	case AuditDestinationS3:
+		schemes = []string{"s3"}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unknown audit destination type %q", d.Type),
+		}
+	}
+	for _, s := range schemes {
+		if u.Scheme == s && u.Host != "" {
+			return nil
+		}
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("%s audit destination url must be a %v url with a host", d.Type, schemes),
+	}
+}
+
+// AuditExport exports the audit records of an organization to an external
+// destination every interval, so that they are archived independently of the
+// retention of the records by influxdb. Every batch of records is signed with
+// HMAC-SHA256 keyed by the value of a secret of the organization.
+type AuditExport struct {
+	OrgID       ID               `json:"orgID"`
+	Destination AuditDestination `json:"destination"`
+	// SigningSecretKey is the key of the secret of the organization that signs the batches.
+	SigningSecretKey string `json:"signingSecretKey"`
+	Every            string `json:"every"`
+	Status           Status `json:"status"`
+
+	// LastExportedID is the ID of the last record that was exported.
+	LastExportedID ID         `json:"lastExportedID,omitempty"`
+	LastExportedAt *time.Time `json:"lastExportedAt,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+}
+
+// Valid returns an error if the audit export has no organization, destination,
+// signing secret or interval.
+func (e *AuditExport) Valid() error {
+	if !e.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if err := e.Destination.Valid(); err != nil {
+		return err
+	}
+	if e.SigningSecretKey == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "signingSecretKey is required",
+		}
+	}
+	if _, err := e.Interval(); err != nil {
+		return err
+	}
+	return e.Status.Valid()
+}
+
+// Interval returns the interval the records are exported at.
+func (e *AuditExport) Interval() (time.Duration, error) {
+	every, err := time.ParseDuration(e.Every)
+	if err != nil {
+		return 0, &Error{
+			Code: EInvalid,
+			Msg:  "every must be a duration",
+			Err:  err,
+		}
+	}
+	if every < MinAuditExportEvery {
+		return 0, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("every must be at least %s", MinAuditExportEvery),
+		}
+	}
+	return every, nil
+}
+
+// AuditExportService manages the audit exports of organizations. An
+// organization has at most one audit export.
+type AuditExportService interface {
+	// FindAuditExport returns the audit export of an organization.
+	FindAuditExport(ctx context.Context, orgID ID) (*AuditExport, error)
+
+	// FindAuditExports returns the audit exports of all organizations.
+	FindAuditExports(ctx context.Context) ([]*AuditExport, error)
+
+	// PutAuditExport creates or replaces the audit export of an organization.
+	PutAuditExport(ctx context.Context, e *AuditExport) error
+
+	// DeleteAuditExport removes the audit export of an organization.
+	DeleteAuditExport(ctx context.Context, orgID ID) error
+}
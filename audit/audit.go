@@ -0,0 +1,32 @@
+// Package audit records the auth events, resource changes and secret access
+// of organizations, and exports the records to external destinations.
+package audit
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// recorder records the events of the services it wraps. A record that cannot
+// be recorded is logged; the event it records has already happened, so the
+// operation does not fail.
+type recorder struct {
+	audit  influxdb.AuditService
+	logger *zap.Logger
+}
+
+func (r *recorder) record(ctx context.Context, rec *influxdb.AuditRecord) {
+	if a, err := influxdbcontext.GetAuthorizer(ctx); err == nil {
+		rec.UserID = a.GetUserID()
+	}
+	if err := r.audit.RecordAudit(ctx, rec); err != nil {
+		r.logger.Error("Unable to record audit",
+			zap.String("org_id", rec.OrgID.String()),
+			zap.String("kind", string(rec.Kind)),
+			zap.String("action", rec.Action),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/influxdata/influxdb"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap"
+)
+
+func TestRecordingServices(t *testing.T) {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutOrganization(ctx, &influxdb.Organization{ID: 1, Name: "theorg"}); err != nil {
+		t.Fatal(err)
+	}
+	ctx = influxdbcontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: 7})
+
+	buckets := NewBucketService(svc, svc, zap.NewNop())
+	b := &influxdb.Bucket{OrgID: 1, Name: "telegraf"}
+	if err := buckets.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := buckets.DeleteBucket(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	secrets := NewSecretService(svc, svc, zap.NewNop())
+	if err := secrets.PutSecrets(ctx, 1, map[string]string{"b": "2", "a": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := secrets.LoadSecret(ctx, 1, "a"); err != nil {
+		t.Fatal(err)
+	}
+	// Failed operations are not recorded.
+	if _, err := secrets.LoadSecret(ctx, 1, "missing"); err == nil {
+		t.Fatal("expected the missing secret not to load")
+	}
+
+	rs, err := svc.FindAuditRecords(ctx, influxdb.AuditRecordFilter{OrgID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bucketRecord := func(action string) *influxdb.AuditRecord {
+		return &influxdb.AuditRecord{OrgID: 1, Kind: influxdb.AuditResource, Action: action, ResourceType: influxdb.BucketsResourceType, ResourceID: b.ID, UserID: 7}
+	}
+	secretRecord := func(action, k string) *influxdb.AuditRecord {
+		return &influxdb.AuditRecord{OrgID: 1, Kind: influxdb.AuditSecret, Action: action, ResourceType: influxdb.SecretsResourceType, Key: k, UserID: 7}
+	}
+	want := []*influxdb.AuditRecord{
+		bucketRecord("create"),
+		bucketRecord("delete"),
+		secretRecord("put", "a"),
+		secretRecord("put", "b"),
+		secretRecord("read", "a"),
+	}
+	if diff := cmp.Diff(rs, want, cmpopts.IgnoreFields(influxdb.AuditRecord{}, "ID", "Time")); diff != "" {
+		t.Errorf("unexpected audit records -got/+want\n%s", diff)
+	}
+}
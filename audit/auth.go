@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.AuthorizationService = (*AuthorizationService)(nil)
+
+// AuthorizationService wraps an influxdb.AuthorizationService and records the
+// creation, change and deletion of authorizations.
+type AuthorizationService struct {
+	influxdb.AuthorizationService
+	recorder
+}
+
+// NewAuthorizationService returns an AuthorizationService recording the auth
+// events of s in audit.
+func NewAuthorizationService(s influxdb.AuthorizationService, audit influxdb.AuditService, logger *zap.Logger) *AuthorizationService {
+	return &AuthorizationService{
+		AuthorizationService: s,
+		recorder:             recorder{audit: audit, logger: logger},
+	}
+}
+
+func (s *AuthorizationService) recordAuth(ctx context.Context, a *influxdb.Authorization, action string) {
+	s.record(ctx, &influxdb.AuditRecord{
+		OrgID:        a.OrgID,
+		Kind:         influxdb.AuditAuth,
+		Action:       action,
+		ResourceType: influxdb.AuthorizationsResourceType,
+		ResourceID:   a.ID,
+	})
+}
+
+// CreateAuthorization creates an authorization and records its creation.
+func (s *AuthorizationService) CreateAuthorization(ctx context.Context, a *influxdb.Authorization) error {
+	if err := s.AuthorizationService.CreateAuthorization(ctx, a); err != nil {
+		return err
+	}
+	s.recordAuth(ctx, a, "create")
+	return nil
+}
+
+// UpdateAuthorization updates an authorization and records the change.
+func (s *AuthorizationService) UpdateAuthorization(ctx context.Context, id influxdb.ID, upd *influxdb.AuthorizationUpdate) (*influxdb.Authorization, error) {
+	a, err := s.AuthorizationService.UpdateAuthorization(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.recordAuth(ctx, a, "update")
+	return a, nil
+}
+
+// DeleteAuthorization deletes an authorization and records its deletion.
+func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id influxdb.ID) error {
+	a, err := s.AuthorizationService.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.AuthorizationService.DeleteAuthorization(ctx, id); err != nil {
+		return err
+	}
+	s.recordAuth(ctx, a, "delete")
+	return nil
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.BucketService = (*BucketService)(nil)
+
+// BucketService wraps an influxdb.BucketService and records the creation,
+// change and deletion of buckets.
+type BucketService struct {
+	influxdb.BucketService
+	recorder
+}
+
+// NewBucketService returns a BucketService recording the resource changes of
+// s in audit.
+func NewBucketService(s influxdb.BucketService, audit influxdb.AuditService, logger *zap.Logger) *BucketService {
+	return &BucketService{
+		BucketService: s,
+		recorder:      recorder{audit: audit, logger: logger},
+	}
+}
+
+func (s *BucketService) recordBucket(ctx context.Context, b *influxdb.Bucket, action string) {
+	s.record(ctx, &influxdb.AuditRecord{
+		OrgID:        b.OrgID,
+		Kind:         influxdb.AuditResource,
+		Action:       action,
+		ResourceType: influxdb.BucketsResourceType,
+		ResourceID:   b.ID,
+	})
+}
+
+// CreateBucket creates a bucket and records its creation.
+func (s *BucketService) CreateBucket(ctx context.Context, b *influxdb.Bucket) error {
+	if err := s.BucketService.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+	s.recordBucket(ctx, b, "create")
+	return nil
+}
+
+// UpdateBucket updates a bucket and records the change.
+func (s *BucketService) UpdateBucket(ctx context.Context, id influxdb.ID, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	b, err := s.BucketService.UpdateBucket(ctx, id, upd)
+	if err != nil {
+		return nil, err
+	}
+	s.recordBucket(ctx, b, "update")
+	return b, nil
+}
+
+// DeleteBucket deletes a bucket and records its deletion.
+func (s *BucketService) DeleteBucket(ctx context.Context, id influxdb.ID) error {
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.BucketService.DeleteBucket(ctx, id); err != nil {
+		return err
+	}
+	s.recordBucket(ctx, b, "delete")
+	return nil
+}
@@ -0,0 +1,191 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/influxdata/influxdb"
+)
+
+// SignatureHeader is the HTTP header of the signature of a batch of audit records.
+const SignatureHeader = "X-Influxdb-Audit-Signature"
+
+// Sign returns the HMAC-SHA256 signature of p keyed by secret, in the form
+// sha256=<hex>. Receivers of audit records verify them with the same secret.
+func Sign(secret, p []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(p)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Batch is a batch of audit records of an organization, encoded as
+// newline-delimited JSON and signed.
+type Batch struct {
+	OrgID   influxdb.ID
+	Records []*influxdb.AuditRecord
+	// Lines are the JSON encodings of the records.
+	Lines [][]byte
+	// Secret is the key the batch is signed with.
+	Secret []byte
+}
+
+// Body returns the records as newline-delimited JSON.
+func (b *Batch) Body() []byte {
+	var buf bytes.Buffer
+	for _, l := range b.Lines {
+		buf.Write(l)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// Destination sends batches of audit records to an external destination.
+type Destination interface {
+	Send(ctx context.Context, d influxdb.AuditDestination, b *Batch) error
+}
+
+// HTTPDestination posts the body of every batch to the URL of the destination,
+// with its signature in the SignatureHeader.
+type HTTPDestination struct {
+	Client *http.Client
+}
+
+// Send posts b to the URL of d.
+func (h *HTTPDestination) Send(ctx context.Context, d influxdb.AuditDestination, b *Batch) error {
+	body := b.Body()
+	req, err := http.NewRequest(http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set(SignatureHeader, Sign(b.Secret, body))
+
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("audit destination responded with %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// SyslogDestination sends every record as an RFC 5424 message to the syslog
+// server at the tcp:// or udp:// URL of the destination. The signature of the
+// record is in the structured data of its message.
+type SyslogDestination struct {
+	Dial     func(network, address string) (net.Conn, error)
+	Hostname string
+}
+
+// syslogPriority is the priority of audit messages, the security/authorization
+// facility (10) at the notice severity (5).
+const syslogPriority = 10*8 + 5
+
+// Send sends the records of b to the syslog server at the URL of d.
+func (s *SyslogDestination) Send(ctx context.Context, d influxdb.AuditDestination, b *Batch) error {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return err
+	}
+
+	dial := s.Dial
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 10 * time.Second}).Dial
+	}
+	conn, err := dial(u.Scheme, u.Host)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	hostname := s.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+	for i, r := range b.Lines {
+		msg := fmt.Sprintf("<%d>1 %s %s influxd - audit [audit@influxdb org=\"%s\" signature=\"%s\"] %s",
+			syslogPriority, b.Records[i].Time.UTC().Format(time.RFC3339Nano), hostname, b.OrgID, Sign(b.Secret, r), r)
+		// Messages over TCP are framed by their length, RFC 6587.
+		if u.Scheme == "tcp" {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		if _, err := io.WriteString(conn, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// S3Destination writes every batch as an object at the s3://bucket/prefix URL
+// of the destination, with its signature in the metadata of the object. The
+// client is configured by the standard AWS environment variables when it is
+// not set.
+type S3Destination struct {
+	Client s3iface.S3API
+}
+
+// Send writes b to the bucket of the URL of d.
+func (s *S3Destination) Send(ctx context.Context, d influxdb.AuditDestination, b *Batch) error {
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return err
+	}
+
+	client := s.Client
+	if client == nil {
+		sess, err := session.NewSessionWithOptions(session.Options{
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			return err
+		}
+		client = s3.New(sess)
+		s.Client = client
+	}
+
+	// The objects of an organization are named by the IDs of their first
+	// and last records, so they sort in the order of the records.
+	first, last := b.Records[0].ID, b.Records[len(b.Records)-1].ID
+	key := path.Join(strings.TrimPrefix(u.Path, "/"), b.OrgID.String(), first.String()+"-"+last.String()+".ndjson")
+	body := b.Body()
+	_, err = client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(u.Host),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/x-ndjson"),
+		Metadata: map[string]*string{
+			"Signature": aws.String(Sign(b.Secret, body)),
+		},
+	})
+	return err
+}
@@ -0,0 +1,200 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// DefaultBatchSize is the number of audit records sent to a destination at once.
+const DefaultBatchSize = 1000
+
+// DefaultTimeout is how long a batch of audit records is given to be sent.
+const DefaultTimeout = 30 * time.Second
+
+// Exporter exports the audit records of organizations with an active audit
+// export to their destinations, every interval of their export. Every export
+// sends the records recorded since the last record that was exported, so the
+// records are exported once each in order, and a failed export is retried
+// with the same records at the next interval.
+type Exporter struct {
+	AuditService       influxdb.AuditService
+	AuditExportService influxdb.AuditExportService
+	// SecretService loads the secrets that sign the batches. Loading them
+	// is not itself recorded, so it is not the recording SecretService.
+	SecretService influxdb.SecretService
+	Destinations  map[influxdb.AuditDestinationType]Destination
+	BatchSize     int
+
+	now    func() time.Time
+	logger *zap.Logger
+	// next is when the audit export of an organization is next due.
+	next map[influxdb.ID]time.Time
+}
+
+// NewExporter returns an Exporter sending the records to the http, syslog
+// and s3 destinations.
+func NewExporter(audit influxdb.AuditService, exports influxdb.AuditExportService, secrets influxdb.SecretService, logger *zap.Logger) *Exporter {
+	return &Exporter{
+		AuditService:       audit,
+		AuditExportService: exports,
+		SecretService:      secrets,
+		Destinations: map[influxdb.AuditDestinationType]Destination{
+			influxdb.AuditDestinationHTTP:   &HTTPDestination{Client: &http.Client{Timeout: DefaultTimeout}},
+			influxdb.AuditDestinationSyslog: &SyslogDestination{},
+			influxdb.AuditDestinationS3:     &S3Destination{},
+		},
+		BatchSize: DefaultBatchSize,
+		now:       time.Now,
+		logger:    logger.With(zap.String("service", "audit_export")),
+		next:      map[influxdb.ID]time.Time{},
+	}
+}
+
+// Run runs the audit exports that are due every interval until ctx is done.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.exportDue(ctx)
+		}
+	}
+}
+
+// exportDue runs the active audit exports that are due.
+func (e *Exporter) exportDue(ctx context.Context) {
+	exports, err := e.AuditExportService.FindAuditExports(ctx)
+	if err != nil {
+		e.logger.Error("Unable to find audit exports", zap.Error(err))
+		return
+	}
+
+	now := e.now()
+	active := map[influxdb.ID]bool{}
+	for _, x := range exports {
+		if x.Status != influxdb.Active {
+			continue
+		}
+		active[x.OrgID] = true
+
+		every, err := x.Interval()
+		if err != nil {
+			continue
+		}
+		next, ok := e.next[x.OrgID]
+		if !ok && x.LastExportedAt != nil {
+			next = x.LastExportedAt.Add(every)
+		}
+		if now.Before(next) {
+			continue
+		}
+		e.next[x.OrgID] = now.Add(every)
+
+		if err := e.export(ctx, x); err != nil {
+			e.logger.Error("Unable to export audit records", zap.String("org_id", x.OrgID.String()), zap.Error(err))
+		}
+	}
+	// Exports that are no longer active start over once they are active again.
+	for id := range e.next {
+		if !active[id] {
+			delete(e.next, id)
+		}
+	}
+}
+
+// export sends the records of the organization of x recorded since its last
+// exported record, in batches, and saves how far it got.
+func (e *Exporter) export(ctx context.Context, x *influxdb.AuditExport) error {
+	lastID := x.LastExportedID
+	err := e.sendRecords(ctx, x, &lastID)
+	if lastID != x.LastExportedID || err != nil || x.LastError != "" {
+		if serr := e.saveState(ctx, x.OrgID, lastID, err); serr != nil {
+			e.logger.Error("Unable to save audit export", zap.String("org_id", x.OrgID.String()), zap.Error(serr))
+		}
+	}
+	return err
+}
+
+func (e *Exporter) sendRecords(ctx context.Context, x *influxdb.AuditExport, lastID *influxdb.ID) error {
+	dest, ok := e.Destinations[x.Destination.Type]
+	if !ok {
+		return fmt.Errorf("unknown audit destination type %q", x.Destination.Type)
+	}
+	secret, err := e.SecretService.LoadSecret(ctx, x.OrgID, x.SigningSecretKey)
+	if err != nil {
+		return err
+	}
+
+	batchSize := e.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	for {
+		filter := influxdb.AuditRecordFilter{OrgID: x.OrgID, Limit: batchSize}
+		if lastID.Valid() {
+			after := *lastID
+			filter.After = &after
+		}
+		rs, err := e.AuditService.FindAuditRecords(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if len(rs) == 0 {
+			return nil
+		}
+
+		b := &Batch{
+			OrgID:   x.OrgID,
+			Records: rs,
+			Lines:   make([][]byte, len(rs)),
+			Secret:  []byte(secret),
+		}
+		for i, r := range rs {
+			if b.Lines[i], err = json.Marshal(r); err != nil {
+				return err
+			}
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+		err = dest.Send(sendCtx, x.Destination, b)
+		cancel()
+		if err != nil {
+			return err
+		}
+		*lastID = rs[len(rs)-1].ID
+
+		if len(rs) < batchSize {
+			return nil
+		}
+	}
+}
+
+// saveState saves the last exported record and the error of an export. The
+// export is found again, so that changes to it while it ran are kept.
+func (e *Exporter) saveState(ctx context.Context, orgID, lastID influxdb.ID, exportErr error) error {
+	x, err := e.AuditExportService.FindAuditExport(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	if lastID != x.LastExportedID {
+		now := e.now()
+		x.LastExportedID = lastID
+		x.LastExportedAt = &now
+	}
+	x.LastError = ""
+	if exportErr != nil {
+		x.LastError = exportErr.Error()
+	}
+	return e.AuditExportService.PutAuditExport(ctx, x)
+}
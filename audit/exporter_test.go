@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"go.uber.org/zap"
+)
+
+type exporterTest struct {
+	svc      *kv.Service
+	exporter *Exporter
+	now      time.Time
+}
+
+func newExporterTest(t *testing.T, dest influxdb.AuditDestination) *exporterTest {
+	ctx := context.Background()
+	svc := kv.NewService(inmem.NewKVStore())
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutOrganization(ctx, &influxdb.Organization{ID: 1, Name: "theorg"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutSecret(ctx, 1, "audit-signing", "s3cr3t"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.PutAuditExport(ctx, &influxdb.AuditExport{
+		OrgID:            1,
+		Destination:      dest,
+		SigningSecretKey: "audit-signing",
+		Every:            "1h",
+		Status:           influxdb.Active,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	et := &exporterTest{
+		svc:      svc,
+		exporter: NewExporter(svc, svc, svc, zap.NewNop()),
+		now:      time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+	}
+	et.exporter.now = func() time.Time { return et.now }
+	return et
+}
+
+func (et *exporterTest) record(t *testing.T, n int) {
+	for i := 0; i < n; i++ {
+		if err := et.svc.RecordAudit(context.Background(), &influxdb.AuditRecord{
+			OrgID:        1,
+			Kind:         influxdb.AuditResource,
+			Action:       "create",
+			ResourceType: influxdb.BucketsResourceType,
+			ResourceID:   influxdb.ID(100 + i),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func (et *exporterTest) export(t *testing.T) *influxdb.AuditExport {
+	et.exporter.exportDue(context.Background())
+	x, err := et.svc.FindAuditExport(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func TestExporter_HTTP(t *testing.T) {
+	var (
+		batches []string
+		fail    bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := r.Header.Get(SignatureHeader), Sign([]byte("s3cr3t"), body); got != want {
+			t.Errorf("got signature %q, want %q", got, want)
+		}
+		if fail {
+			http.Error(w, "archive is down", http.StatusServiceUnavailable)
+			return
+		}
+		batches = append(batches, string(body))
+	}))
+	defer srv.Close()
+
+	et := newExporterTest(t, influxdb.AuditDestination{Type: influxdb.AuditDestinationHTTP, URL: srv.URL})
+	et.exporter.BatchSize = 2
+
+	et.record(t, 3)
+	x := et.export(t)
+	if len(batches) != 2 || strings.Count(batches[0], "\n") != 2 || strings.Count(batches[1], "\n") != 1 {
+		t.Fatalf("expected the records to be exported in batches of two, got %q", batches)
+	}
+	rs, err := et.svc.FindAuditRecords(context.Background(), influxdb.AuditRecordFilter{OrgID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.LastExportedID != rs[2].ID || x.LastExportedAt == nil || !x.LastExportedAt.Equal(et.now) || x.LastError != "" {
+		t.Errorf("expected the export to be saved up to the last record, got %+v", x)
+	}
+
+	// The export is not due again until its interval has passed.
+	et.record(t, 1)
+	et.now = et.now.Add(30 * time.Minute)
+	et.export(t)
+	if len(batches) != 2 {
+		t.Fatalf("expected no export before the interval, got %d batches", len(batches))
+	}
+
+	// A failed export is retried with the same records at the next interval.
+	fail = true
+	et.now = et.now.Add(30 * time.Minute)
+	if x := et.export(t); x.LastExportedID != rs[2].ID || !strings.Contains(x.LastError, "archive is down") {
+		t.Errorf("expected the failed export to be saved with its error, got %+v", x)
+	}
+	fail = false
+	et.now = et.now.Add(time.Hour)
+	if x := et.export(t); x.LastExportedID == rs[2].ID || x.LastError != "" {
+		t.Errorf("expected the retried export to be saved up to the new record, got %+v", x)
+	}
+	if len(batches) != 3 || strings.Count(batches[2], "\n") != 1 {
+		t.Errorf("expected only the new record to be exported, got %q", batches[2:])
+	}
+}
+
+func TestExporter_Syslog(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		b, _ := ioutil.ReadAll(bufio.NewReader(conn))
+		received <- string(b)
+	}()
+
+	et := newExporterTest(t, influxdb.AuditDestination{Type: influxdb.AuditDestinationSyslog, URL: "tcp://" + ln.Addr().String()})
+	et.exporter.Destinations[influxdb.AuditDestinationSyslog] = &SyslogDestination{Hostname: "influx1"}
+	et.record(t, 1)
+	if x := et.export(t); x.LastError != "" {
+		t.Fatalf("unexpected export error: %s", x.LastError)
+	}
+
+	msg := <-received
+	i := strings.Index(msg, "{")
+	if i < 0 {
+		t.Fatalf("expected a record in the message, got %q", msg)
+	}
+	record := msg[i:]
+	for _, want := range []string{
+		" <85>1 ",
+		" influx1 influxd - audit ",
+		`org="0000000000000001"`,
+		`signature="` + Sign([]byte("s3cr3t"), []byte(record)) + `"`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the message %q", want, msg)
+		}
+	}
+}
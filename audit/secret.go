@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.SecretService = (*SecretService)(nil)
+
+// SecretService wraps an influxdb.SecretService and records the access to and
+// the change of secrets. The values of secrets are never recorded.
+type SecretService struct {
+	influxdb.SecretService
+	recorder
+}
+
+// NewSecretService returns a SecretService recording the secret access of s
+// in audit.
+func NewSecretService(s influxdb.SecretService, audit influxdb.AuditService, logger *zap.Logger) *SecretService {
+	return &SecretService{
+		SecretService: s,
+		recorder:      recorder{audit: audit, logger: logger},
+	}
+}
+
+func (s *SecretService) recordSecrets(ctx context.Context, orgID influxdb.ID, action string, ks ...string) {
+	for _, k := range ks {
+		s.record(ctx, &influxdb.AuditRecord{
+			OrgID:        orgID,
+			Kind:         influxdb.AuditSecret,
+			Action:       action,
+			ResourceType: influxdb.SecretsResourceType,
+			Key:          k,
+		})
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	ks := influxdb.SecretKeys(m)
+	sort.Strings(ks)
+	return ks
+}
+
+// LoadSecret loads the value of a secret and records the access.
+func (s *SecretService) LoadSecret(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+	v, err := s.SecretService.LoadSecret(ctx, orgID, k)
+	if err != nil {
+		return "", err
+	}
+	s.recordSecrets(ctx, orgID, "read", k)
+	return v, nil
+}
+
+// PutSecret puts a secret and records the change.
+func (s *SecretService) PutSecret(ctx context.Context, orgID influxdb.ID, k string, v string) error {
+	if err := s.SecretService.PutSecret(ctx, orgID, k, v); err != nil {
+		return err
+	}
+	s.recordSecrets(ctx, orgID, "put", k)
+	return nil
+}
+
+// PutSecrets puts secrets and records the change of each of them.
+func (s *SecretService) PutSecrets(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
+	if err := s.SecretService.PutSecrets(ctx, orgID, m); err != nil {
+		return err
+	}
+	s.recordSecrets(ctx, orgID, "put", sortedKeys(m)...)
+	return nil
+}
+
+// PatchSecrets patches secrets and records the change of each of them.
+func (s *SecretService) PatchSecrets(ctx context.Context, orgID influxdb.ID, m map[string]string) error {
+	if err := s.SecretService.PatchSecrets(ctx, orgID, m); err != nil {
+		return err
+	}
+	s.recordSecrets(ctx, orgID, "put", sortedKeys(m)...)
+	return nil
+}
+
+// DeleteSecret deletes secrets and records the deletion of each of them.
+func (s *SecretService) DeleteSecret(ctx context.Context, orgID influxdb.ID, ks ...string) error {
+	if err := s.SecretService.DeleteSecret(ctx, orgID, ks...); err != nil {
+		return err
+	}
+	s.recordSecrets(ctx, orgID, "delete", ks...)
+	return nil
+}
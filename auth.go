@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"sort"
 )
 
 // AuthorizationKind is returned by (*Authorization).Kind().
@@ -110,6 +111,23 @@ type AuthorizationService interface {
 	DeleteAuthorization(ctx context.Context, id ID) error
 }
 
+// AuthorizationSortKeys are the keys authorizations can be sorted by.
+var AuthorizationSortKeys = []string{"ID", "Description", "Status"}
+
+// SortAuthorizations sorts authorizations by the sort key of the options, or by ID if they have none.
+func SortAuthorizations(opts FindOptions, as []*Authorization) {
+	var less func(i, j int) bool
+	switch opts.sortKey() {
+	case "description":
+		less = func(i, j int) bool { return as[i].Description < as[j].Description }
+	case "status":
+		less = func(i, j int) bool { return as[i].Status < as[j].Status }
+	default:
+		less = func(i, j int) bool { return as[i].ID < as[j].ID }
+	}
+	sort.SliceStable(as, opts.ordered(less))
+}
+
 // AuthorizationFilter represents a set of filter that restrict the returned results.
 type AuthorizationFilter struct {
 	Token *string
@@ -121,3 +139,30 @@ type AuthorizationFilter struct {
 	OrgID *ID
 	Org   *string
 }
+
+// QueryParams converts AuthorizationFilter fields to url query params.
+// The token is left out, so that it is not leaked in links.
+func (f AuthorizationFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.ID != nil {
+		qp["id"] = []string{f.ID.String()}
+	}
+
+	if f.UserID != nil {
+		qp["userID"] = []string{f.UserID.String()}
+	}
+
+	if f.User != nil {
+		qp["user"] = []string{*f.User}
+	}
+
+	if f.OrgID != nil {
+		qp["orgID"] = []string{f.OrgID.String()}
+	}
+
+	if f.Org != nil {
+		qp["org"] = []string{*f.Org}
+	}
+
+	return qp
+}
@@ -0,0 +1,156 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.AnnotationService = (*AnnotationService)(nil)
+
+// AnnotationService wraps a influxdb.AnnotationService and authorizes actions
+// against it appropriately.
+type AnnotationService struct {
+	s influxdb.AnnotationService
+}
+
+// NewAnnotationService constructs an instance of an authorizing annotation service.
+func NewAnnotationService(s influxdb.AnnotationService) *AnnotationService {
+	return &AnnotationService{
+		s: s,
+	}
+}
+
+func newAnnotationPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.AnnotationsResourceType, orgID)
+}
+
+func authorizeReadAnnotation(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newAnnotationPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteAnnotation(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newAnnotationPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAnnotationByID checks to see if the authorizer on context has read access to the id provided.
+func (s *AnnotationService) FindAnnotationByID(ctx context.Context, id influxdb.ID) (*influxdb.Annotation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	a, err := s.s.FindAnnotationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadAnnotation(ctx, a.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// FindAnnotations retrieves all annotations that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *AnnotationService) FindAnnotations(ctx context.Context, filter influxdb.AnnotationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Annotation, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	as, _, err := s.s.FindAnnotations(ctx, filter, unpaged(opt)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	annotations := as[:0]
+	for _, a := range as {
+		err := authorizeReadAnnotation(ctx, a.OrgID, a.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		annotations = append(annotations, a)
+	}
+
+	lo, hi := page(opt, len(annotations))
+	annotations = annotations[lo:hi]
+
+	return annotations, len(annotations), nil
+}
+
+// CreateAnnotations checks to see if the authorizer on context has write access to the annotations
+// of the organization of each of the annotations.
+func (s *AnnotationService) CreateAnnotations(ctx context.Context, as []*influxdb.Annotation) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	for _, a := range as {
+		p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.AnnotationsResourceType, a.OrgID)
+		if err != nil {
+			return err
+		}
+
+		if err := IsAllowed(ctx, *p); err != nil {
+			return err
+		}
+	}
+
+	return s.s.CreateAnnotations(ctx, as)
+}
+
+// UpdateAnnotation checks to see if the authorizer on context has write access to the annotation provided.
+func (s *AnnotationService) UpdateAnnotation(ctx context.Context, id influxdb.ID, upd influxdb.AnnotationUpdate) (*influxdb.Annotation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	a, err := s.s.FindAnnotationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteAnnotation(ctx, a.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateAnnotation(ctx, id, upd)
+}
+
+// DeleteAnnotation checks to see if the authorizer on context has write access to the annotation provided.
+func (s *AnnotationService) DeleteAnnotation(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	a, err := s.s.FindAnnotationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteAnnotation(ctx, a.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteAnnotation(ctx, id)
+}
@@ -0,0 +1,92 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestAnnotationService_CreateAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name: "authorized to write the annotations of the organizations",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "write",
+					Resource: influxdb.Resource{Type: influxdb.AnnotationsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+				},
+				{
+					Action:   "write",
+					Resource: influxdb.Resource{Type: influxdb.AnnotationsResourceType, OrgID: influxdbtesting.IDPtr(11)},
+				},
+			},
+		},
+		{
+			name: "authorized to write the annotations of one of the organizations",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "write",
+					Resource: influxdb.Resource{Type: influxdb.AnnotationsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			m := mock.NewAnnotationService()
+			m.CreateAnnotationsFn = func(ctx context.Context, as []*influxdb.Annotation) error {
+				created = true
+				return nil
+			}
+			s := authorizer.NewAnnotationService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			err := s.CreateAnnotations(ctx, []*influxdb.Annotation{{OrgID: 10}, {OrgID: 11}})
+			if tt.wantErr {
+				if influxdb.ErrorCode(err) != influxdb.EUnauthorized || created {
+					t.Fatalf("expected no annotation to be created, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestAnnotationService_FindAnnotations(t *testing.T) {
+	m := mock.NewAnnotationService()
+	m.FindAnnotationsFn = func(ctx context.Context, f influxdb.AnnotationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Annotation, int, error) {
+		return []*influxdb.Annotation{{ID: 1, OrgID: 10}, {ID: 2, OrgID: 11}}, 2, nil
+	}
+	s := authorizer.NewAnnotationService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action:   "read",
+			Resource: influxdb.Resource{Type: influxdb.AnnotationsResourceType, OrgID: influxdbtesting.IDPtr(10)},
+		},
+	}})
+
+	as, n, err := s.FindAnnotations(ctx, influxdb.AnnotationFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || as[0].ID != 1 {
+		t.Fatalf("expected only the annotation of the organization, got %+v", as)
+	}
+}
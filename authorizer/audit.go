@@ -0,0 +1,116 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.AuditService = (*AuditService)(nil)
+var _ influxdb.AuditExportService = (*AuditService)(nil)
+
+// AuditService wraps a influxdb.AuditService and influxdb.AuditExportService
+// and authorizes actions against them appropriately. The audit records and
+// the audit export of an organization require write access to it.
+type AuditService struct {
+	s influxdb.AuditService
+	e influxdb.AuditExportService
+}
+
+// NewAuditService constructs an instance of an authorizing audit service.
+func NewAuditService(s influxdb.AuditService, e influxdb.AuditExportService) *AuditService {
+	return &AuditService{
+		s: s,
+		e: e,
+	}
+}
+
+// RecordAudit checks to see if the authorizer on context has write access to the organization of the record.
+func (s *AuditService) RecordAudit(ctx context.Context, r *influxdb.AuditRecord) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteOrg(ctx, r.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.RecordAudit(ctx, r)
+}
+
+// FindAuditRecords checks to see if the authorizer on context has write access to the organization of the filter.
+func (s *AuditService) FindAuditRecords(ctx context.Context, filter influxdb.AuditRecordFilter) ([]*influxdb.AuditRecord, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteOrg(ctx, filter.OrgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindAuditRecords(ctx, filter)
+}
+
+// FindAuditExport checks to see if the authorizer on context has write access to the organization.
+func (s *AuditService) FindAuditExport(ctx context.Context, orgID influxdb.ID) (*influxdb.AuditExport, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteOrg(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.e.FindAuditExport(ctx, orgID)
+}
+
+// FindAuditExports retrieves all audit exports and then filters the list down to only the exports of organizations the authorizer on context has write access to.
+func (s *AuditService) FindAuditExports(ctx context.Context) ([]*influxdb.AuditExport, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	es, err := s.e.FindAuditExports(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	exports := es[:0]
+	for _, e := range es {
+		err := authorizeWriteOrg(ctx, e.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		exports = append(exports, e)
+	}
+
+	return exports, nil
+}
+
+// PutAuditExport checks to see if the authorizer on context has write access to the organization of the export.
+func (s *AuditService) PutAuditExport(ctx context.Context, e *influxdb.AuditExport) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteOrg(ctx, e.OrgID); err != nil {
+		return err
+	}
+
+	return s.e.PutAuditExport(ctx, e)
+}
+
+// DeleteAuditExport checks to see if the authorizer on context has write access to the organization.
+func (s *AuditService) DeleteAuditExport(ctx context.Context, orgID influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteOrg(ctx, orgID); err != nil {
+		return err
+	}
+
+	return s.e.DeleteAuditExport(ctx, orgID)
+}
@@ -0,0 +1,60 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestAuditService_FindAuditRecords(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the org",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "authorized to read the org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(10),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewAuditService()
+			s := authorizer.NewAuditService(m, m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.FindAuditRecords(ctx, influxdb.AuditRecordFilter{OrgID: 10})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+
+			err = s.PutAuditExport(ctx, &influxdb.AuditExport{OrgID: 10})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
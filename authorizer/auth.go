@@ -91,7 +91,7 @@ func (s *AuthorizationService) FindAuthorizationByToken(ctx context.Context, t s
 func (s *AuthorizationService) FindAuthorizations(ctx context.Context, filter influxdb.AuthorizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Authorization, int, error) {
 	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
 	// will likely be expensive.
-	as, _, err := s.s.FindAuthorizations(ctx, filter, opt...)
+	as, _, err := s.s.FindAuthorizations(ctx, filter, unpaged(opt)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -112,6 +112,9 @@ func (s *AuthorizationService) FindAuthorizations(ctx context.Context, filter in
 		authorizations = append(authorizations, a)
 	}
 
+	lo, hi := page(opt, len(authorizations))
+	authorizations = authorizations[lo:hi]
+
 	return authorizations, len(authorizations), nil
 }
 
@@ -96,7 +96,7 @@ func (s *BucketService) FindBuckets(ctx context.Context, filter influxdb.BucketF
 
 	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
 	// will likely be expensive.
-	bs, _, err := s.s.FindBuckets(ctx, filter, opt...)
+	bs, _, err := s.s.FindBuckets(ctx, filter, unpaged(opt)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -117,6 +117,9 @@ func (s *BucketService) FindBuckets(ctx context.Context, filter influxdb.BucketF
 		buckets = append(buckets, b)
 	}
 
+	lo, hi := page(opt, len(buckets))
+	buckets = buckets[lo:hi]
+
 	return buckets, len(buckets), nil
 }
 
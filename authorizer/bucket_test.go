@@ -626,3 +626,42 @@ func TestBucketService_CreateBucket(t *testing.T) {
 		})
 	}
 }
+
+func TestBucketService_FindBuckets_Paging(t *testing.T) {
+	m := &mock.BucketService{
+		FindBucketsFn: func(ctx context.Context, filter influxdb.BucketFilter, opts ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+			if len(opts) != 1 || opts[0].Offset != 0 || opts[0].Limit != 0 {
+				t.Fatalf("expected buckets to be found unpaged, got %+v", opts)
+			}
+			return []*influxdb.Bucket{
+				{ID: 1, OrgID: 10},
+				{ID: 2, OrgID: 11},
+				{ID: 3, OrgID: 10},
+				{ID: 4, OrgID: 10},
+			}, 4, nil
+		},
+	}
+	s := authorizer.NewBucketService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action: "read",
+			Resource: influxdb.Resource{
+				Type:  influxdb.BucketsResourceType,
+				OrgID: influxdbtesting.IDPtr(10),
+			},
+		},
+	}})
+
+	// The page is of the authorized buckets, so it is not short.
+	bs, n, err := s.FindBuckets(ctx, influxdb.BucketFilter{}, influxdb.FindOptions{Offset: 1, Limit: 2})
+	influxdbtesting.ErrorsEqual(t, err, nil)
+
+	want := []*influxdb.Bucket{{ID: 3, OrgID: 10}, {ID: 4, OrgID: 10}}
+	if diff := cmp.Diff(bs, want, bucketCmpOptions...); diff != "" {
+		t.Errorf("buckets are different -got/+want\ndiff %s", diff)
+	}
+	if n != 2 {
+		t.Errorf("got %d buckets, want 2", n)
+	}
+}
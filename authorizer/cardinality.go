@@ -0,0 +1,47 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.CardinalityService = (*CardinalityService)(nil)
+
+// CardinalityService wraps a influxdb.CardinalityService and authorizes actions
+// against it appropriately.
+type CardinalityService struct {
+	s influxdb.CardinalityService
+}
+
+// NewCardinalityService constructs an instance of an authorizing cardinality service.
+func NewCardinalityService(s influxdb.CardinalityService) *CardinalityService {
+	return &CardinalityService{
+		s: s,
+	}
+}
+
+// FindCardinalityTrend checks to see if the authorizer on context has read access to the bucket of the filter.
+func (s *CardinalityService) FindCardinalityTrend(ctx context.Context, filter influxdb.CardinalityTrendFilter) ([]*influxdb.CardinalitySnapshot, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, filter.OrgID, filter.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindCardinalityTrend(ctx, filter)
+}
+
+// FindCurrentCardinality checks to see if the authorizer on context has read access to the bucket.
+func (s *CardinalityService) FindCurrentCardinality(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalitySnapshot, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, orgID, bucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindCurrentCardinality(ctx, orgID, bucketID)
+}
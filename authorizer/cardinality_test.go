@@ -0,0 +1,111 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestCardinalityService_FindCardinalityTrend(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to read bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to read bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewCardinalityService()
+			m.FindCardinalityTrendFn = func(ctx context.Context, filter influxdb.CardinalityTrendFilter) ([]*influxdb.CardinalitySnapshot, error) {
+				return []*influxdb.CardinalitySnapshot{{Series: 1}}, nil
+			}
+			s := authorizer.NewCardinalityService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			now := time.Now()
+			_, err := s.FindCardinalityTrend(ctx, influxdb.CardinalityTrendFilter{
+				OrgID:    10,
+				BucketID: 1,
+				Start:    now.Add(-time.Hour),
+				Stop:     now,
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+func TestCardinalityService_FindCurrentCardinality(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to read bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to read bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewCardinalityService(mock.NewCardinalityService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.FindCurrentCardinality(ctx, 10, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
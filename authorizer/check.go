@@ -0,0 +1,170 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.CheckService = (*CheckService)(nil)
+
+// CheckService wraps a influxdb.CheckService and authorizes actions
+// against it appropriately.
+type CheckService struct {
+	s influxdb.CheckService
+}
+
+// NewCheckService constructs an instance of an authorizing check service.
+func NewCheckService(s influxdb.CheckService) *CheckService {
+	return &CheckService{
+		s: s,
+	}
+}
+
+func newCheckPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.ChecksResourceType, orgID)
+}
+
+func authorizeReadCheck(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newCheckPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteCheck(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newCheckPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindCheckByID checks to see if the authorizer on context has read access to the id provided.
+func (s *CheckService) FindCheckByID(ctx context.Context, id influxdb.ID) (*influxdb.Check, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	c, err := s.s.FindCheckByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadCheck(ctx, c.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// FindChecks retrieves all checks that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *CheckService) FindChecks(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]*influxdb.Check, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	cs, _, err := s.s.FindChecks(ctx, filter, unpaged(opt)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	checks := cs[:0]
+	for _, c := range cs {
+		err := authorizeReadCheck(ctx, c.OrgID, c.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		checks = append(checks, c)
+	}
+
+	lo, hi := page(opt, len(checks))
+	checks = checks[lo:hi]
+
+	return checks, len(checks), nil
+}
+
+// CreateCheck checks to see if the authorizer on context has write access to the checks of the organization.
+func (s *CheckService) CreateCheck(ctx context.Context, c *influxdb.Check) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.ChecksResourceType, c.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateCheck(ctx, c)
+}
+
+// UpdateCheck checks to see if the authorizer on context has write access to the check provided.
+func (s *CheckService) UpdateCheck(ctx context.Context, id influxdb.ID, upd influxdb.CheckUpdate) (*influxdb.Check, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	c, err := s.s.FindCheckByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteCheck(ctx, c.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateCheck(ctx, id, upd)
+}
+
+// ReplaceCheck checks to see if the authorizer on context has write access to the check provided.
+func (s *CheckService) ReplaceCheck(ctx context.Context, c *influxdb.Check) (*influxdb.Check, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	current, err := s.s.FindCheckByID(ctx, c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteCheck(ctx, current.OrgID, c.ID); err != nil {
+		return nil, err
+	}
+
+	return s.s.ReplaceCheck(ctx, c)
+}
+
+// DeleteCheck checks to see if the authorizer on context has write access to the check provided.
+func (s *CheckService) DeleteCheck(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	c, err := s.s.FindCheckByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteCheck(ctx, c.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteCheck(ctx, id)
+}
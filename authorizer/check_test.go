@@ -0,0 +1,98 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestCheckService_FindChecks(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		checks     []*influxdb.Check
+	}{
+		{
+			name: "authorized to read all checks of the org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.ChecksResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			checks: []*influxdb.Check{
+				{ID: 1, OrgID: 10, Name: "cpu"},
+				{ID: 2, OrgID: 10, Name: "memory"},
+			},
+		},
+		{
+			name: "authorized to read a single check",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.ChecksResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+			checks: []*influxdb.Check{
+				{ID: 2, OrgID: 10, Name: "memory"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewCheckService()
+			m.FindChecksFn = func(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]*influxdb.Check, int, error) {
+				return []*influxdb.Check{
+					{ID: 1, OrgID: 10, Name: "cpu"},
+					{ID: 2, OrgID: 10, Name: "memory"},
+				}, 2, nil
+			}
+			s := authorizer.NewCheckService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			cs, _, err := s.FindChecks(ctx, influxdb.CheckFilter{})
+			influxdbtesting.ErrorsEqual(t, err, nil)
+
+			if diff := cmp.Diff(cs, tt.checks); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckService_FindChecksPaged(t *testing.T) {
+	m := mock.NewCheckService()
+	m.FindChecksFn = func(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]*influxdb.Check, int, error) {
+		if len(opt) > 0 && (opt[0].Offset != 0 || opt[0].Limit != 0) {
+			t.Errorf("expected the checks to be found unpaged, got %+v", opt[0])
+		}
+		return []*influxdb.Check{
+			{ID: 1, OrgID: 10, Name: "cpu"},
+			{ID: 2, OrgID: 10, Name: "memory"},
+			{ID: 3, OrgID: 10, Name: "disk"},
+		}, 3, nil
+	}
+	s := authorizer.NewCheckService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{Action: "read", Resource: influxdb.Resource{Type: influxdb.ChecksResourceType, ID: influxdbtesting.IDPtr(2)}},
+		{Action: "read", Resource: influxdb.Resource{Type: influxdb.ChecksResourceType, ID: influxdbtesting.IDPtr(3)}},
+	}})
+
+	cs, _, err := s.FindChecks(ctx, influxdb.CheckFilter{}, influxdb.FindOptions{Offset: 1, Limit: 1})
+	influxdbtesting.ErrorsEqual(t, err, nil)
+
+	if diff := cmp.Diff(cs, []*influxdb.Check{{ID: 3, OrgID: 10, Name: "disk"}}); diff != "" {
+		t.Errorf("checks are different -got/+want\ndiff %s", diff)
+	}
+}
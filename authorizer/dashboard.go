@@ -69,7 +69,7 @@ func (s *DashboardService) FindDashboardByID(ctx context.Context, id influxdb.ID
 func (s *DashboardService) FindDashboards(ctx context.Context, filter influxdb.DashboardFilter, opt influxdb.FindOptions) ([]*influxdb.Dashboard, int, error) {
 	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
 	// will likely be expensive.
-	bs, _, err := s.s.FindDashboards(ctx, filter, opt)
+	bs, _, err := s.s.FindDashboards(ctx, filter, opt.Unpaged())
 	if err != nil {
 		return nil, 0, err
 	}
@@ -90,6 +90,9 @@ func (s *DashboardService) FindDashboards(ctx context.Context, filter influxdb.D
 		dashboards = append(dashboards, b)
 	}
 
+	lo, hi := opt.Page(len(dashboards))
+	dashboards = dashboards[lo:hi]
+
 	return dashboards, len(dashboards), nil
 }
 
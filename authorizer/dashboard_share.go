@@ -0,0 +1,105 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.DashboardShareService = (*DashboardShareService)(nil)
+
+// DashboardShareService wraps a influxdb.DashboardShareService and authorizes actions
+// against it appropriately. Creating and revoking the shares of a dashboard requires
+// write access to the dashboard, and finding them read access. The token of a share
+// is the credential that views it, so finding a share by its token is not authorized.
+type DashboardShareService struct {
+	s influxdb.DashboardShareService
+}
+
+// NewDashboardShareService constructs an instance of an authorizing dashboard share service.
+func NewDashboardShareService(s influxdb.DashboardShareService) *DashboardShareService {
+	return &DashboardShareService{
+		s: s,
+	}
+}
+
+// CreateDashboardShare checks to see if the authorizer on context has write access to the dashboard of the share.
+func (s *DashboardShareService) CreateDashboardShare(ctx context.Context, ds *influxdb.DashboardShare) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteDashboard(ctx, ds.OrgID, ds.DashboardID); err != nil {
+		return err
+	}
+
+	return s.s.CreateDashboardShare(ctx, ds)
+}
+
+// FindDashboardShareByID checks to see if the authorizer on context has read access to the dashboard of the share.
+func (s *DashboardShareService) FindDashboardShareByID(ctx context.Context, id influxdb.ID) (*influxdb.DashboardShare, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ds, err := s.s.FindDashboardShareByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadDashboard(ctx, ds.OrgID, ds.DashboardID); err != nil {
+		return nil, err
+	}
+
+	return ds, nil
+}
+
+// FindDashboardShareByToken returns the dashboard share with the token.
+func (s *DashboardShareService) FindDashboardShareByToken(ctx context.Context, token string) (*influxdb.DashboardShare, error) {
+	return s.s.FindDashboardShareByToken(ctx, token)
+}
+
+// FindDashboardShares retrieves all shares that match the provided filter and then filters the list down to only the shares of dashboards the authorizer on context has read access to.
+func (s *DashboardShareService) FindDashboardShares(ctx context.Context, filter influxdb.DashboardShareFilter) ([]*influxdb.DashboardShare, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ss, err := s.s.FindDashboardShares(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	shares := ss[:0]
+	for _, ds := range ss {
+		err := authorizeReadDashboard(ctx, ds.OrgID, ds.DashboardID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		shares = append(shares, ds)
+	}
+
+	return shares, nil
+}
+
+// RevokeDashboardShare checks to see if the authorizer on context has write access to the dashboard of the share.
+func (s *DashboardShareService) RevokeDashboardShare(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ds, err := s.s.FindDashboardShareByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteDashboard(ctx, ds.OrgID, ds.DashboardID); err != nil {
+		return err
+	}
+
+	return s.s.RevokeDashboardShare(ctx, id)
+}
@@ -0,0 +1,83 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestDashboardShareService_CreateDashboardShare(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the dashboard",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type: influxdb.DashboardsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "authorized to read the dashboard",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.DashboardsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/dashboards/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDashboardShareService(mock.NewDashboardShareService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.CreateDashboardShare(ctx, &influxdb.DashboardShare{DashboardID: 1, OrgID: 10, Mode: influxdb.DashboardShareLive, Range: "1h"})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+func TestDashboardShareService_FindDashboardShares(t *testing.T) {
+	m := mock.NewDashboardShareService()
+	m.FindDashboardSharesFn = func(ctx context.Context, filter influxdb.DashboardShareFilter) ([]*influxdb.DashboardShare, error) {
+		return []*influxdb.DashboardShare{
+			{ID: 1, DashboardID: 1, OrgID: 10},
+			{ID: 2, DashboardID: 2, OrgID: 10},
+		}, nil
+	}
+	s := authorizer.NewDashboardShareService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action: "read",
+			Resource: influxdb.Resource{
+				Type: influxdb.DashboardsResourceType,
+				ID:   influxdbtesting.IDPtr(2),
+			},
+		},
+	}})
+
+	ss, err := s.FindDashboardShares(ctx, influxdb.DashboardShareFilter{})
+	influxdbtesting.ErrorsEqual(t, err, nil)
+	if len(ss) != 1 || ss[0].ID != 2 {
+		t.Fatalf("expected only the share of the readable dashboard, got %+v", ss)
+	}
+}
@@ -0,0 +1,113 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestDBRPMappingService_FindMany(t *testing.T) {
+	m := mock.NewDBRPMappingService()
+	m.FindManyFn = func(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+		return []*influxdb.DBRPMapping{
+			{Cluster: "c", Database: "telegraf", RetentionPolicy: "autogen", OrganizationID: 10, BucketID: 1},
+			{Cluster: "c", Database: "telegraf", RetentionPolicy: "weekly", OrganizationID: 10, BucketID: 2},
+		}, 2, nil
+	}
+	s := authorizer.NewDBRPMappingService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{{
+		Action: "read",
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(10),
+			ID:    influxdbtesting.IDPtr(2),
+		},
+	}}})
+
+	ms, n, err := s.FindMany(ctx, influxdb.DBRPMappingFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || ms[0].RetentionPolicy != "weekly" {
+		t.Errorf("expected only the mapping of the readable bucket, got %+v", ms)
+	}
+}
+
+func TestDBRPMappingService_Create(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the bucket",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write the bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewDBRPMappingService(mock.NewDBRPMappingService())
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.Create(ctx, &influxdb.DBRPMapping{Cluster: "c", Database: "telegraf", RetentionPolicy: "autogen", OrganizationID: 10, BucketID: 1})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+func TestDBRPMappingService_Delete(t *testing.T) {
+	var deleted bool
+	m := mock.NewDBRPMappingService()
+	m.FindByFn = func(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+		return &influxdb.DBRPMapping{Cluster: cluster, Database: db, RetentionPolicy: rp, OrganizationID: 10, BucketID: 1}, nil
+	}
+	m.DeleteFn = func(ctx context.Context, cluster, db, rp string) error {
+		deleted = true
+		return nil
+	}
+	s := authorizer.NewDBRPMappingService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{{
+		Action: "read",
+		Resource: influxdb.Resource{
+			Type:  influxdb.BucketsResourceType,
+			OrgID: influxdbtesting.IDPtr(10),
+		},
+	}}})
+
+	err := s.Delete(ctx, "c", "telegraf", "autogen")
+	influxdbtesting.ErrorsEqual(t, err, &influxdb.Error{
+		Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+		Code: influxdb.EUnauthorized,
+	})
+	if deleted {
+		t.Error("expected mapping not to be deleted without write access to its bucket")
+	}
+}
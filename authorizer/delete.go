@@ -0,0 +1,35 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.DeleteService = (*DeleteService)(nil)
+
+// DeleteService wraps a influxdb.DeleteService and authorizes actions
+// against it appropriately.
+type DeleteService struct {
+	s influxdb.DeleteService
+}
+
+// NewDeleteService constructs an instance of an authorizing delete service.
+func NewDeleteService(s influxdb.DeleteService) *DeleteService {
+	return &DeleteService{
+		s: s,
+	}
+}
+
+// Delete checks to see if the authorizer on context has write access to the bucket of the request.
+func (s *DeleteService) Delete(ctx context.Context, req influxdb.DeleteRequest) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, req.OrgID, req.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.Delete(ctx, req)
+}
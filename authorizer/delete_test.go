@@ -0,0 +1,58 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestDeleteService_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write bucket",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewDeleteService()
+			s := authorizer.NewDeleteService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.Delete(ctx, influxdb.DeleteRequest{OrgID: 10, BucketID: 1, Start: time.Unix(0, 0), Stop: time.Unix(1, 0)})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.DropSeriesService = (*DropSeriesService)(nil)
+
+// DropSeriesService wraps a influxdb.DropSeriesService and authorizes actions
+// against it appropriately.
+type DropSeriesService struct {
+	s influxdb.DropSeriesService
+}
+
+// NewDropSeriesService constructs an instance of an authorizing drop series service.
+func NewDropSeriesService(s influxdb.DropSeriesService) *DropSeriesService {
+	return &DropSeriesService{
+		s: s,
+	}
+}
+
+// DropSeries checks to see if the authorizer on context has write access to the bucket of the request.
+func (s *DropSeriesService) DropSeries(ctx context.Context, req influxdb.DropSeriesRequest) (*influxdb.DropSeriesOperation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, req.OrgID, req.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.DropSeries(ctx, req)
+}
+
+// FindDropSeriesOperationByID checks to see if the authorizer on context has read access to the bucket of the operation.
+func (s *DropSeriesService) FindDropSeriesOperationByID(ctx context.Context, id influxdb.ID) (*influxdb.DropSeriesOperation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	op, err := s.s.FindDropSeriesOperationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, op.OrgID, op.BucketID); err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// FindDropSeriesOperations retrieves all operations that match the provided filter and then filters the list down to
+// the operations on buckets the authorizer on context may read.
+func (s *DropSeriesService) FindDropSeriesOperations(ctx context.Context, filter influxdb.DropSeriesOperationFilter) ([]*influxdb.DropSeriesOperation, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	all, err := s.s.FindDropSeriesOperations(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	ops := all[:0]
+	for _, op := range all {
+		err := authorizeReadBucket(ctx, op.OrgID, op.BucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
@@ -0,0 +1,87 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestDropSeriesService_DropSeries(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write bucket",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewDropSeriesService()
+			m.DropSeriesFn = func(ctx context.Context, req influxdb.DropSeriesRequest) (*influxdb.DropSeriesOperation, error) {
+				return &influxdb.DropSeriesOperation{ID: 2, DropSeriesRequest: req}, nil
+			}
+			s := authorizer.NewDropSeriesService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.DropSeries(ctx, influxdb.DropSeriesRequest{OrgID: 10, BucketID: 1, Measurement: "cpu"})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+func TestDropSeriesService_FindDropSeriesOperations(t *testing.T) {
+	m := mock.NewDropSeriesService()
+	m.FindDropSeriesOperationsFn = func(ctx context.Context, filter influxdb.DropSeriesOperationFilter) ([]*influxdb.DropSeriesOperation, error) {
+		return []*influxdb.DropSeriesOperation{
+			{ID: 1, DropSeriesRequest: influxdb.DropSeriesRequest{OrgID: 10, BucketID: 1}},
+			{ID: 2, DropSeriesRequest: influxdb.DropSeriesRequest{OrgID: 10, BucketID: 2}},
+		}, nil
+	}
+	s := authorizer.NewDropSeriesService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{{
+		Action: "read",
+		Resource: influxdb.Resource{
+			Type: influxdb.BucketsResourceType,
+			ID:   influxdbtesting.IDPtr(2),
+		},
+	}}})
+
+	ops, err := s.FindDropSeriesOperations(ctx, influxdb.DropSeriesOperationFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 1 || ops[0].ID != 2 {
+		t.Errorf("expected only the operation on the readable bucket, got %+v", ops)
+	}
+}
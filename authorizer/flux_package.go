@@ -0,0 +1,115 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.FluxPackageService = (*FluxPackageService)(nil)
+
+// FluxPackageService wraps a influxdb.FluxPackageService and authorizes actions
+// against it appropriately.
+//
+// Flux packages share the permissions of their organization, since every
+// query and task of the organization may import them.
+type FluxPackageService struct {
+	s influxdb.FluxPackageService
+}
+
+// NewFluxPackageService constructs an instance of an authorizing flux package service.
+func NewFluxPackageService(s influxdb.FluxPackageService) *FluxPackageService {
+	return &FluxPackageService{
+		s: s,
+	}
+}
+
+func authorizeFluxPackage(ctx context.Context, a influxdb.Action, orgID influxdb.ID) error {
+	p, err := influxdb.NewPermissionAtID(orgID, a, influxdb.OrgsResourceType, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindFluxPackageByID checks to see if the authorizer on context has read access to the package's organization.
+func (s *FluxPackageService) FindFluxPackageByID(ctx context.Context, id influxdb.ID) (*influxdb.FluxPackage, error) {
+	fp, err := s.s.FindFluxPackageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeFluxPackage(ctx, influxdb.ReadAction, fp.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	return fp, nil
+}
+
+// FindFluxPackages retrieves all flux packages that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *FluxPackageService) FindFluxPackages(ctx context.Context, filter influxdb.FluxPackageFilter) ([]*influxdb.FluxPackage, error) {
+	ps, err := s.s.FindFluxPackages(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	fps := ps[:0]
+	for _, fp := range ps {
+		err := authorizeFluxPackage(ctx, influxdb.ReadAction, fp.OrganizationID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		fps = append(fps, fp)
+	}
+
+	return fps, nil
+}
+
+// CreateFluxPackage checks to see if the authorizer on context has write access to the organization.
+func (s *FluxPackageService) CreateFluxPackage(ctx context.Context, fp *influxdb.FluxPackage) error {
+	if err := authorizeFluxPackage(ctx, influxdb.WriteAction, fp.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.CreateFluxPackage(ctx, fp)
+}
+
+// UpdateFluxPackage checks to see if the authorizer on context has write access to the package's organization.
+func (s *FluxPackageService) UpdateFluxPackage(ctx context.Context, id influxdb.ID, upd influxdb.FluxPackageUpdate) (*influxdb.FluxPackage, error) {
+	fp, err := s.s.FindFluxPackageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeFluxPackage(ctx, influxdb.WriteAction, fp.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateFluxPackage(ctx, id, upd)
+}
+
+// DeleteFluxPackage checks to see if the authorizer on context has write access to the package's organization.
+func (s *FluxPackageService) DeleteFluxPackage(ctx context.Context, id influxdb.ID) error {
+	fp, err := s.s.FindFluxPackageByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeFluxPackage(ctx, influxdb.WriteAction, fp.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteFluxPackage(ctx, id)
+}
@@ -0,0 +1,110 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.InviteService = (*InviteService)(nil)
+
+// InviteService wraps a influxdb.InviteService and authorizes actions
+// against it appropriately. Managing the invites of an organization requires
+// write access to it. The token of an invite is the credential that finds and
+// accepts it, so those are not authorized.
+type InviteService struct {
+	s influxdb.InviteService
+}
+
+// NewInviteService constructs an instance of an authorizing invite service.
+func NewInviteService(s influxdb.InviteService) *InviteService {
+	return &InviteService{
+		s: s,
+	}
+}
+
+// CreateInvite checks to see if the authorizer on context has write access to the organization of the invite.
+func (s *InviteService) CreateInvite(ctx context.Context, i *influxdb.Invite) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteOrg(ctx, i.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.CreateInvite(ctx, i)
+}
+
+// FindInviteByID checks to see if the authorizer on context has write access to the organization of the invite.
+func (s *InviteService) FindInviteByID(ctx context.Context, id influxdb.ID) (*influxdb.Invite, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	i, err := s.s.FindInviteByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteOrg(ctx, i.OrgID); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// FindInviteByToken returns the invite with the token.
+func (s *InviteService) FindInviteByToken(ctx context.Context, token string) (*influxdb.Invite, error) {
+	return s.s.FindInviteByToken(ctx, token)
+}
+
+// FindInvites retrieves all invites that match the provided filter and then filters the list down to only the invites of organizations the authorizer on context has write access to.
+func (s *InviteService) FindInvites(ctx context.Context, filter influxdb.InviteFilter) ([]*influxdb.Invite, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	is, err := s.s.FindInvites(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	invites := is[:0]
+	for _, i := range is {
+		err := authorizeWriteOrg(ctx, i.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		invites = append(invites, i)
+	}
+
+	return invites, nil
+}
+
+// RevokeInvite checks to see if the authorizer on context has write access to the organization of the invite.
+func (s *InviteService) RevokeInvite(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	i, err := s.s.FindInviteByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteOrg(ctx, i.OrgID); err != nil {
+		return err
+	}
+
+	return s.s.RevokeInvite(ctx, id)
+}
+
+// AcceptInvite accepts the invite with the token.
+func (s *InviteService) AcceptInvite(ctx context.Context, token string, a influxdb.InviteAcceptance) (*influxdb.User, error) {
+	return s.s.AcceptInvite(ctx, token, a)
+}
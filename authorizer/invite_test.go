@@ -0,0 +1,86 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestInviteService_CreateInvite(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the org",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "authorized to read the org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(10),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewInviteService(mock.NewInviteService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.CreateInvite(ctx, &influxdb.Invite{OrgID: 10, Email: "ada@example.com", Role: influxdb.Member})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+func TestInviteService_FindInvites(t *testing.T) {
+	m := mock.NewInviteService()
+	m.FindInvitesFn = func(ctx context.Context, filter influxdb.InviteFilter) ([]*influxdb.Invite, error) {
+		return []*influxdb.Invite{
+			{ID: 1, OrgID: 10, Email: "ada@example.com"},
+			{ID: 2, OrgID: 11, Email: "bob@example.com"},
+		}, nil
+	}
+	s := authorizer.NewInviteService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action: "write",
+			Resource: influxdb.Resource{
+				Type: influxdb.OrgsResourceType,
+				ID:   influxdbtesting.IDPtr(10),
+			},
+		},
+	}})
+
+	is, err := s.FindInvites(ctx, influxdb.InviteFilter{})
+	influxdbtesting.ErrorsEqual(t, err, nil)
+
+	want := []*influxdb.Invite{{ID: 1, OrgID: 10, Email: "ada@example.com"}}
+	if diff := cmp.Diff(is, want); diff != "" {
+		t.Errorf("invites are different -got/+want\ndiff %s", diff)
+	}
+}
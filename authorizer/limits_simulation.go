@@ -0,0 +1,36 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.LimitsSimulationService = (*LimitsSimulationService)(nil)
+
+// LimitsSimulationService wraps a influxdb.LimitsSimulationService and authorizes actions
+// against it appropriately.
+type LimitsSimulationService struct {
+	s influxdb.LimitsSimulationService
+}
+
+// NewLimitsSimulationService constructs an instance of an authorizing limits simulation service.
+func NewLimitsSimulationService(s influxdb.LimitsSimulationService) *LimitsSimulationService {
+	return &LimitsSimulationService{
+		s: s,
+	}
+}
+
+// SimulateLimits checks to see if the authorizer on context may read every organization,
+// since a simulation reports the usage of all of them.
+func (s *LimitsSimulationService) SimulateLimits(ctx context.Context, sim influxdb.LimitsSimulation) (*influxdb.LimitsSimulationResult, error) {
+	p, err := influxdb.NewGlobalPermission(influxdb.ReadAction, influxdb.OrgsResourceType)
+	if err != nil {
+		return nil, err
+	}
+	if err := IsAllowed(ctx, *p); err != nil {
+		return nil, err
+	}
+
+	return s.s.SimulateLimits(ctx, sim)
+}
@@ -0,0 +1,54 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestLimitsSimulationService_SimulateLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to read every org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+				},
+			},
+		},
+		{
+			name: "unauthorized to read every org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(10),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "read:orgs is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewLimitsSimulationService(mock.NewLimitsSimulationService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+			_, err := s.SimulateLimits(ctx, influxdb.LimitsSimulation{Limits: influxdb.OrgLimits{MaxSeries: 1000}})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	_ influxdb.MaintenanceService         = (*MaintenanceService)(nil)
+	_ influxdb.MaintenanceScheduleService = (*MaintenanceScheduleService)(nil)
+)
+
+// MaintenanceService wraps a influxdb.MaintenanceService and authorizes actions
+// against it appropriately.
+type MaintenanceService struct {
+	s influxdb.MaintenanceService
+}
+
+// NewMaintenanceService constructs an instance of an authorizing maintenance service.
+func NewMaintenanceService(s influxdb.MaintenanceService) *MaintenanceService {
+	return &MaintenanceService{
+		s: s,
+	}
+}
+
+// authorizeMaintenance checks that the authorizer on context is an instance operator,
+// i.e. may write every organization.
+func authorizeMaintenance(ctx context.Context) error {
+	p, err := influxdb.NewGlobalPermission(influxdb.WriteAction, influxdb.OrgsResourceType)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MaintenanceStatus returns the maintenance state. Any authorized user may read it.
+func (s *MaintenanceService) MaintenanceStatus(ctx context.Context) (*influxdb.MaintenanceStatus, error) {
+	return s.s.MaintenanceStatus(ctx)
+}
+
+// UpdateMaintenanceStatus checks to see if the authorizer on context is an instance operator.
+func (s *MaintenanceService) UpdateMaintenanceStatus(ctx context.Context, upd influxdb.MaintenanceStatusUpdate) (*influxdb.MaintenanceStatus, error) {
+	if err := authorizeMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateMaintenanceStatus(ctx, upd)
+}
+
+// MaintenanceScheduleService wraps a influxdb.MaintenanceScheduleService and authorizes actions
+// against it appropriately.
+type MaintenanceScheduleService struct {
+	s influxdb.MaintenanceScheduleService
+}
+
+// NewMaintenanceScheduleService constructs an instance of an authorizing maintenance schedule service.
+func NewMaintenanceScheduleService(s influxdb.MaintenanceScheduleService) *MaintenanceScheduleService {
+	return &MaintenanceScheduleService{
+		s: s,
+	}
+}
+
+// MaintenanceSchedule returns the maintenance schedule. Any authorized user may read it.
+func (s *MaintenanceScheduleService) MaintenanceSchedule(ctx context.Context) (*influxdb.MaintenanceSchedule, error) {
+	return s.s.MaintenanceSchedule(ctx)
+}
+
+// UpdateMaintenanceSchedule checks to see if the authorizer on context is an instance operator.
+func (s *MaintenanceScheduleService) UpdateMaintenanceSchedule(ctx context.Context, upd influxdb.MaintenanceScheduleUpdate) (*influxdb.MaintenanceSchedule, error) {
+	if err := authorizeMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateMaintenanceSchedule(ctx, upd)
+}
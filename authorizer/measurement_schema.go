@@ -0,0 +1,89 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MeasurementSchemaService = (*MeasurementSchemaService)(nil)
+
+// MeasurementSchemaService wraps a influxdb.MeasurementSchemaService and authorizes actions
+// against it appropriately.
+//
+// Measurement schemas share the permissions of their buckets: they are read with
+// read access to the bucket, and created and updated with write access to it.
+type MeasurementSchemaService struct {
+	s influxdb.MeasurementSchemaService
+}
+
+// NewMeasurementSchemaService constructs an instance of an authorizing measurement schema service.
+func NewMeasurementSchemaService(s influxdb.MeasurementSchemaService) *MeasurementSchemaService {
+	return &MeasurementSchemaService{
+		s: s,
+	}
+}
+
+// FindMeasurementSchemaByID checks to see if the authorizer on context has read access to the bucket of the schema.
+func (s *MeasurementSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	m, err := s.s.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, m.OrgID, m.BucketID); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// FindMeasurementSchemas retrieves all measurement schemas that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *MeasurementSchemaService) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+	ms, err := s.s.FindMeasurementSchemas(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	mss := ms[:0]
+	for _, m := range ms {
+		err := authorizeReadBucket(ctx, m.OrgID, m.BucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		mss = append(mss, m)
+	}
+
+	return mss, nil
+}
+
+// CreateMeasurementSchema checks to see if the authorizer on context has write access to the bucket of the schema.
+// The organization of the schema must be set to the one of its bucket.
+func (s *MeasurementSchemaService) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	if err := authorizeWriteBucket(ctx, m.OrgID, m.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.CreateMeasurementSchema(ctx, m)
+}
+
+// UpdateMeasurementSchema checks to see if the authorizer on context has write access to the bucket of the schema.
+func (s *MeasurementSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	m, err := s.s.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteBucket(ctx, m.OrgID, m.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateMeasurementSchema(ctx, bucketID, id, upd)
+}
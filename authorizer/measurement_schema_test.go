@@ -0,0 +1,122 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestMeasurementSchemaService_FindMeasurementSchemas(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		want       []influxdb.ID
+	}{
+		{
+			name: "authorized to read the bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(12),
+				},
+			},
+			want: []influxdb.ID{1, 2},
+		},
+		{
+			name: "unauthorized to read the bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(13),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewMeasurementSchemaService()
+			m.FindMeasurementSchemasFn = func(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+				return []*influxdb.MeasurementSchema{
+					{ID: 1, OrgID: 10, BucketID: 12},
+					{ID: 2, OrgID: 10, BucketID: 12},
+				}, nil
+			}
+			s := authorizer.NewMeasurementSchemaService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			ms, err := s.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: 12})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []influxdb.ID
+			for _, m := range ms {
+				got = append(got, m.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got schemas %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got schemas %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMeasurementSchemaService_CreateMeasurementSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the bucket",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(12),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write the bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+					ID:    influxdbtesting.IDPtr(12),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets/000000000000000c is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewMeasurementSchemaService(mock.NewMeasurementSchemaService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{OrgID: 10, BucketID: 12, Name: "cpu"})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
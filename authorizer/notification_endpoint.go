@@ -0,0 +1,194 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.NotificationEndpointService = (*NotificationEndpointService)(nil)
+
+// NotificationEndpointService wraps a influxdb.NotificationEndpointService and authorizes actions
+// against it appropriately.
+type NotificationEndpointService struct {
+	s influxdb.NotificationEndpointService
+}
+
+// NewNotificationEndpointService constructs an instance of an authorizing notification endpoint service.
+func NewNotificationEndpointService(s influxdb.NotificationEndpointService) *NotificationEndpointService {
+	return &NotificationEndpointService{
+		s: s,
+	}
+}
+
+func newNotificationEndpointPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.NotificationEndpointsResourceType, orgID)
+}
+
+func authorizeReadNotificationEndpoint(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newNotificationEndpointPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteNotificationEndpoint(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newNotificationEndpointPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindNotificationEndpointByID checks to see if the authorizer on context has read access to the id provided.
+func (s *NotificationEndpointService) FindNotificationEndpointByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationEndpoint, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e, err := s.s.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadNotificationEndpoint(ctx, e.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// FindNotificationEndpoints retrieves all notification endpoints that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *NotificationEndpointService) FindNotificationEndpoints(ctx context.Context, filter influxdb.NotificationEndpointFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationEndpoint, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	es, _, err := s.s.FindNotificationEndpoints(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	endpoints := es[:0]
+	for _, e := range es {
+		err := authorizeReadNotificationEndpoint(ctx, e.OrgID, e.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		endpoints = append(endpoints, e)
+	}
+
+	return endpoints, len(endpoints), nil
+}
+
+// CreateNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoints of the organization.
+func (s *NotificationEndpointService) CreateNotificationEndpoint(ctx context.Context, e *influxdb.NotificationEndpoint) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.NotificationEndpointsResourceType, e.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateNotificationEndpoint(ctx, e)
+}
+
+// UpdateNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
+func (s *NotificationEndpointService) UpdateNotificationEndpoint(ctx context.Context, id influxdb.ID, upd influxdb.NotificationEndpointUpdate) (*influxdb.NotificationEndpoint, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e, err := s.s.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteNotificationEndpoint(ctx, e.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateNotificationEndpoint(ctx, id, upd)
+}
+
+// ReplaceNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
+func (s *NotificationEndpointService) ReplaceNotificationEndpoint(ctx context.Context, e *influxdb.NotificationEndpoint) (*influxdb.NotificationEndpoint, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	current, err := s.s.FindNotificationEndpointByID(ctx, e.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteNotificationEndpoint(ctx, current.OrgID, e.ID); err != nil {
+		return nil, err
+	}
+
+	return s.s.ReplaceNotificationEndpoint(ctx, e)
+}
+
+// DeleteNotificationEndpoint checks to see if the authorizer on context has write access to the notification endpoint provided.
+func (s *NotificationEndpointService) DeleteNotificationEndpoint(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	e, err := s.s.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteNotificationEndpoint(ctx, e.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteNotificationEndpoint(ctx, id)
+}
+
+var _ influxdb.NotificationSender = (*NotificationSender)(nil)
+
+// NotificationSender wraps a influxdb.NotificationSender and authorizes
+// sending notifications to endpoints.
+type NotificationSender struct {
+	s influxdb.NotificationSender
+}
+
+// NewNotificationSender constructs an instance of an authorizing notification sender.
+func NewNotificationSender(s influxdb.NotificationSender) *NotificationSender {
+	return &NotificationSender{
+		s: s,
+	}
+}
+
+// SendNotification checks to see if the authorizer on context has write access to the notification endpoint provided.
+func (s *NotificationSender) SendNotification(ctx context.Context, e *influxdb.NotificationEndpoint, n influxdb.Notification) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteNotificationEndpoint(ctx, e.OrgID, e.ID); err != nil {
+		return err
+	}
+
+	return s.s.SendNotification(ctx, e, n)
+}
@@ -0,0 +1,115 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestNotificationEndpointService_FindNotificationEndpoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		endpoints  []*influxdb.NotificationEndpoint
+	}{
+		{
+			name: "authorized to read all notification endpoints of the org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.NotificationEndpointsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			endpoints: []*influxdb.NotificationEndpoint{
+				{ID: 1, OrgID: 10, Name: "slack"},
+				{ID: 2, OrgID: 10, Name: "pagerduty"},
+			},
+		},
+		{
+			name: "authorized to read a single notification endpoint",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.NotificationEndpointsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+			endpoints: []*influxdb.NotificationEndpoint{
+				{ID: 2, OrgID: 10, Name: "pagerduty"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewNotificationEndpointService()
+			m.FindNotificationEndpointsFn = func(ctx context.Context, filter influxdb.NotificationEndpointFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationEndpoint, int, error) {
+				return []*influxdb.NotificationEndpoint{
+					{ID: 1, OrgID: 10, Name: "slack"},
+					{ID: 2, OrgID: 10, Name: "pagerduty"},
+				}, 2, nil
+			}
+			s := authorizer.NewNotificationEndpointService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			es, _, err := s.FindNotificationEndpoints(ctx, influxdb.NotificationEndpointFilter{})
+			influxdbtesting.ErrorsEqual(t, err, nil)
+
+			if diff := cmp.Diff(es, tt.endpoints); diff != "" {
+				t.Errorf("notification endpoints are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestNotificationSender_SendNotification(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the notification endpoint",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type:  influxdb.NotificationEndpointsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "only authorized to read the notification endpoint",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.NotificationEndpointsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/notificationEndpoints/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewNotificationSender(mock.NewNotificationSender())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.SendNotification(ctx, &influxdb.NotificationEndpoint{ID: 1, OrgID: 10}, influxdb.Notification{})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
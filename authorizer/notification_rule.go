@@ -0,0 +1,242 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.NotificationRuleService = (*NotificationRuleService)(nil)
+
+// NotificationRuleService wraps a influxdb.NotificationRuleService and authorizes actions
+// against it appropriately.
+type NotificationRuleService struct {
+	s influxdb.NotificationRuleService
+}
+
+// NewNotificationRuleService constructs an instance of an authorizing notification rule service.
+func NewNotificationRuleService(s influxdb.NotificationRuleService) *NotificationRuleService {
+	return &NotificationRuleService{
+		s: s,
+	}
+}
+
+func newNotificationRulePermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.NotificationRulesResourceType, orgID)
+}
+
+func authorizeReadNotificationRule(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newNotificationRulePermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteNotificationRule(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newNotificationRulePermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindNotificationRuleByID checks to see if the authorizer on context has read access to the id provided.
+func (s *NotificationRuleService) FindNotificationRuleByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationRule, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadNotificationRule(ctx, r.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindNotificationRules retrieves all notification rules that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *NotificationRuleService) FindNotificationRules(ctx context.Context, filter influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRule, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	rs, _, err := s.s.FindNotificationRules(ctx, filter, unpaged(opt)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	rules := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadNotificationRule(ctx, r.OrgID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		rules = append(rules, r)
+	}
+
+	lo, hi := page(opt, len(rules))
+	rules = rules[lo:hi]
+
+	return rules, len(rules), nil
+}
+
+// CreateNotificationRule checks to see if the authorizer on context has write access to the notification rules of the organization
+// and read access to the notification endpoint of the rule.
+func (s *NotificationRuleService) CreateNotificationRule(ctx context.Context, r *influxdb.NotificationRule) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.NotificationRulesResourceType, r.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	if err := authorizeReadNotificationEndpoint(ctx, r.OrgID, r.EndpointID); err != nil {
+		return err
+	}
+
+	return s.s.CreateNotificationRule(ctx, r)
+}
+
+// UpdateNotificationRule checks to see if the authorizer on context has write access to the notification rule provided.
+func (s *NotificationRuleService) UpdateNotificationRule(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRuleUpdate) (*influxdb.NotificationRule, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteNotificationRule(ctx, r.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateNotificationRule(ctx, id, upd)
+}
+
+// ReplaceNotificationRule checks to see if the authorizer on context has write access to the notification rule provided
+// and read access to the notification endpoint of the rule.
+func (s *NotificationRuleService) ReplaceNotificationRule(ctx context.Context, r *influxdb.NotificationRule) (*influxdb.NotificationRule, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	current, err := s.s.FindNotificationRuleByID(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteNotificationRule(ctx, current.OrgID, r.ID); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadNotificationEndpoint(ctx, current.OrgID, r.EndpointID); err != nil {
+		return nil, err
+	}
+
+	return s.s.ReplaceNotificationRule(ctx, r)
+}
+
+// DeleteNotificationRule checks to see if the authorizer on context has write access to the notification rule provided.
+func (s *NotificationRuleService) DeleteNotificationRule(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r, err := s.s.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteNotificationRule(ctx, r.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteNotificationRule(ctx, id)
+}
+
+var _ influxdb.SentNotificationService = (*SentNotificationService)(nil)
+
+// SentNotificationService wraps a influxdb.SentNotificationService and
+// authorizes actions against it appropriately.
+//
+// A sent notification belongs to the rule that sent it: anyone who may read
+// the rule may see the notifications it sent.
+type SentNotificationService struct {
+	s influxdb.SentNotificationService
+}
+
+// NewSentNotificationService constructs an instance of an authorizing sent notification service.
+func NewSentNotificationService(s influxdb.SentNotificationService) *SentNotificationService {
+	return &SentNotificationService{
+		s: s,
+	}
+}
+
+// AddSentNotification checks to see if the authorizer on context has write access to the notification rule of the notification.
+func (s *SentNotificationService) AddSentNotification(ctx context.Context, n *influxdb.SentNotification) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteNotificationRule(ctx, n.OrgID, n.RuleID); err != nil {
+		return err
+	}
+
+	return s.s.AddSentNotification(ctx, n)
+}
+
+// FindSentNotifications retrieves all notifications that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *SentNotificationService) FindSentNotifications(ctx context.Context, filter influxdb.SentNotificationFilter, opt ...influxdb.FindOptions) ([]*influxdb.SentNotification, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ns, _, err := s.s.FindSentNotifications(ctx, filter, unpaged(opt)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	sent := ns[:0]
+	for _, n := range ns {
+		err := authorizeReadNotificationRule(ctx, n.OrgID, n.RuleID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		sent = append(sent, n)
+	}
+
+	lo, hi := page(opt, len(sent))
+	sent = sent[lo:hi]
+
+	return sent, len(sent), nil
+}
@@ -0,0 +1,96 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestNotificationRuleService_CreateNotificationRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name: "authorized to write rules and read the endpoint",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "write",
+					Resource: influxdb.Resource{Type: influxdb.NotificationRulesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+				},
+				{
+					Action:   "read",
+					Resource: influxdb.Resource{Type: influxdb.NotificationEndpointsResourceType, ID: influxdbtesting.IDPtr(3)},
+				},
+			},
+		},
+		{
+			name: "unauthorized to read the endpoint",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "write",
+					Resource: influxdb.Resource{Type: influxdb.NotificationRulesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewNotificationRuleService()
+			s := authorizer.NewNotificationRuleService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			err := s.CreateNotificationRule(ctx, &influxdb.NotificationRule{OrgID: 10, Name: "crit", EndpointID: 3})
+			if tt.wantErr && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Fatalf("expected the rule to be unauthorized, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSentNotificationService_FindSentNotifications(t *testing.T) {
+	m := mock.NewSentNotificationService()
+	m.FindSentNotificationsFn = func(ctx context.Context, filter influxdb.SentNotificationFilter, opt ...influxdb.FindOptions) ([]*influxdb.SentNotification, int, error) {
+		if len(opt) > 0 && (opt[0].Offset != 0 || opt[0].Limit != 0) {
+			t.Errorf("expected the notifications to be found unpaged, got %+v", opt[0])
+		}
+		return []*influxdb.SentNotification{
+			{ID: 1, OrgID: 10, RuleID: 2},
+			{ID: 2, OrgID: 10, RuleID: 3},
+			{ID: 3, OrgID: 10, RuleID: 2},
+			{ID: 4, OrgID: 10, RuleID: 2},
+		}, 4, nil
+	}
+	s := authorizer.NewSentNotificationService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action:   "read",
+			Resource: influxdb.Resource{Type: influxdb.NotificationRulesResourceType, ID: influxdbtesting.IDPtr(2)},
+		},
+	}})
+
+	ns, n, err := s.FindSentNotifications(ctx, influxdb.SentNotificationFilter{}, influxdb.FindOptions{Offset: 1, Limit: 1})
+	influxdbtesting.ErrorsEqual(t, err, nil)
+
+	want := []*influxdb.SentNotification{{ID: 3, OrgID: 10, RuleID: 2}}
+	if diff := cmp.Diff(ns, want); diff != "" {
+		t.Errorf("notifications are different -got/+want\ndiff %s", diff)
+	}
+	if n != 1 {
+		t.Errorf("expected a count of 1, got %d", n)
+	}
+}
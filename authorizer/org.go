@@ -86,7 +86,7 @@ func (s *OrgService) FindOrganization(ctx context.Context, filter influxdb.Organ
 func (s *OrgService) FindOrganizations(ctx context.Context, filter influxdb.OrganizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Organization, int, error) {
 	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
 	// will likely be expensive.
-	os, _, err := s.s.FindOrganizations(ctx, filter, opt...)
+	os, _, err := s.s.FindOrganizations(ctx, filter, unpaged(opt)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -107,6 +107,9 @@ func (s *OrgService) FindOrganizations(ctx context.Context, filter influxdb.Orga
 		orgs = append(orgs, o)
 	}
 
+	lo, hi := page(opt, len(orgs))
+	orgs = orgs[lo:hi]
+
 	return orgs, len(orgs), nil
 }
 
@@ -0,0 +1,101 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.OrgDeletionService = (*OrgDeletionService)(nil)
+
+// OrgDeletionService wraps a influxdb.OrgDeletionService and authorizes actions
+// against it appropriately.
+type OrgDeletionService struct {
+	s influxdb.OrgDeletionService
+}
+
+// NewOrgDeletionService constructs an instance of an authorizing organization deletion service.
+func NewOrgDeletionService(s influxdb.OrgDeletionService) *OrgDeletionService {
+	return &OrgDeletionService{
+		s: s,
+	}
+}
+
+// orgDeletionResourceTypes are the types of the resources an organization deletion deletes,
+// besides the organization itself.
+var orgDeletionResourceTypes = []influxdb.ResourceType{
+	influxdb.TasksResourceType,
+	influxdb.AuthorizationsResourceType,
+	influxdb.BucketsResourceType,
+}
+
+// DeleteOrganizationResources checks to see if the authorizer on context has write access to the organization
+// and to all of its tasks, tokens and buckets.
+func (s *OrgDeletionService) DeleteOrganizationResources(ctx context.Context, req influxdb.OrgDeletionRequest) (*influxdb.OrgDeletion, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteOrg(ctx, req.OrgID); err != nil {
+		return nil, err
+	}
+
+	for _, rt := range orgDeletionResourceTypes {
+		p, err := influxdb.NewPermission(influxdb.WriteAction, rt, req.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		if err := IsAllowed(ctx, *p); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.s.DeleteOrganizationResources(ctx, req)
+}
+
+// FindOrgDeletionByID checks to see if the authorizer on context has read access to the organization of the deletion.
+func (s *OrgDeletionService) FindOrgDeletionByID(ctx context.Context, id influxdb.ID) (*influxdb.OrgDeletion, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	d, err := s.s.FindOrgDeletionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadOrg(ctx, d.OrgID); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// FindOrgDeletions retrieves all deletions that match the provided filter and then filters the list down to
+// the deletions of organizations the authorizer on context may read.
+func (s *OrgDeletionService) FindOrgDeletions(ctx context.Context, filter influxdb.OrgDeletionFilter) ([]*influxdb.OrgDeletion, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	all, err := s.s.FindOrgDeletions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	ds := all[:0]
+	for _, d := range all {
+		err := authorizeReadOrg(ctx, d.OrgID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		ds = append(ds, d)
+	}
+
+	return ds, nil
+}
@@ -0,0 +1,112 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestOrgDeletionService_DeleteOrganizationResources(t *testing.T) {
+	orgWrite := influxdb.Permission{
+		Action: "write",
+		Resource: influxdb.Resource{
+			Type: influxdb.OrgsResourceType,
+			ID:   influxdbtesting.IDPtr(10),
+		},
+	}
+	orgWide := func(rt influxdb.ResourceType) influxdb.Permission {
+		return influxdb.Permission{
+			Action: "write",
+			Resource: influxdb.Resource{
+				Type:  rt,
+				OrgID: influxdbtesting.IDPtr(10),
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name: "authorized to write the org and its resources",
+			permissions: []influxdb.Permission{
+				orgWrite,
+				orgWide(influxdb.TasksResourceType),
+				orgWide(influxdb.AuthorizationsResourceType),
+				orgWide(influxdb.BucketsResourceType),
+			},
+		},
+		{
+			name: "unauthorized to write the resources of the org",
+			permissions: []influxdb.Permission{
+				orgWrite,
+				orgWide(influxdb.TasksResourceType),
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/authorizations is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name: "unauthorized to write the org",
+			permissions: []influxdb.Permission{
+				orgWide(influxdb.TasksResourceType),
+				orgWide(influxdb.AuthorizationsResourceType),
+				orgWide(influxdb.BucketsResourceType),
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewOrgDeletionService()
+			m.DeleteOrganizationResourcesFn = func(ctx context.Context, req influxdb.OrgDeletionRequest) (*influxdb.OrgDeletion, error) {
+				return &influxdb.OrgDeletion{ID: 2, OrgDeletionRequest: req}, nil
+			}
+			s := authorizer.NewOrgDeletionService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.DeleteOrganizationResources(ctx, influxdb.OrgDeletionRequest{OrgID: 10})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+func TestOrgDeletionService_FindOrgDeletions(t *testing.T) {
+	m := mock.NewOrgDeletionService()
+	m.FindOrgDeletionsFn = func(ctx context.Context, filter influxdb.OrgDeletionFilter) ([]*influxdb.OrgDeletion, error) {
+		return []*influxdb.OrgDeletion{
+			{ID: 1, OrgDeletionRequest: influxdb.OrgDeletionRequest{OrgID: 10}},
+			{ID: 2, OrgDeletionRequest: influxdb.OrgDeletionRequest{OrgID: 11}},
+		}, nil
+	}
+	s := authorizer.NewOrgDeletionService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{{
+		Action: "read",
+		Resource: influxdb.Resource{
+			Type: influxdb.OrgsResourceType,
+			ID:   influxdbtesting.IDPtr(11),
+		},
+	}}})
+
+	ds, err := s.FindOrgDeletions(ctx, influxdb.OrgDeletionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 || ds[0].ID != 2 {
+		t.Errorf("expected only the deletion of the readable org, got %+v", ds)
+	}
+}
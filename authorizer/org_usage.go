@@ -0,0 +1,35 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.OrgUsageService = (*OrgUsageService)(nil)
+
+// OrgUsageService wraps a influxdb.OrgUsageService and authorizes actions
+// against it appropriately.
+type OrgUsageService struct {
+	s influxdb.OrgUsageService
+}
+
+// NewOrgUsageService constructs an instance of an authorizing org usage service.
+func NewOrgUsageService(s influxdb.OrgUsageService) *OrgUsageService {
+	return &OrgUsageService{
+		s: s,
+	}
+}
+
+// FindOrgUsage checks to see if the authorizer on context has read access to the organization of the filter.
+func (s *OrgUsageService) FindOrgUsage(ctx context.Context, filter influxdb.OrgUsageFilter) (*influxdb.OrgUsageReport, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadOrg(ctx, filter.OrgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindOrgUsage(ctx, filter)
+}
@@ -0,0 +1,66 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestOrgUsageService_FindOrgUsage(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to read org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(1),
+				},
+			},
+		},
+		{
+			name: "unauthorized to read org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewOrgUsageService()
+			m.FindOrgUsageFn = func(ctx context.Context, filter influxdb.OrgUsageFilter) (*influxdb.OrgUsageReport, error) {
+				return &influxdb.OrgUsageReport{OrgID: filter.OrgID}, nil
+			}
+			s := authorizer.NewOrgUsageService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			now := time.Now()
+			_, err := s.FindOrgUsage(ctx, influxdb.OrgUsageFilter{
+				OrgID: 1,
+				Start: now.Add(-time.Hour),
+				Stop:  now,
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
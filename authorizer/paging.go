@@ -0,0 +1,23 @@
+package authorizer
+
+import (
+	"github.com/influxdata/influxdb"
+)
+
+// unpaged returns the find options without their offset and limit. Results
+// are found unpaged, so that they are paged after the unauthorized ones are
+// filtered out, and pages are neither short nor empty.
+func unpaged(opts []influxdb.FindOptions) []influxdb.FindOptions {
+	if len(opts) == 0 {
+		return nil
+	}
+	return []influxdb.FindOptions{opts[0].Unpaged()}
+}
+
+// page returns the bounds of the page of n authorized results the find options select.
+func page(opts []influxdb.FindOptions, n int) (lo, hi int) {
+	if len(opts) == 0 {
+		return 0, n
+	}
+	return opts[0].Page(n)
+}
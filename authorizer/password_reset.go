@@ -0,0 +1,42 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.PasswordResetService = (*PasswordResetService)(nil)
+
+// PasswordResetService wraps a influxdb.PasswordResetService and authorizes actions
+// against it appropriately. Creating a password reset of a user requires write
+// access to the user. The token of a password reset is the credential that
+// uses it, so that is not authorized.
+type PasswordResetService struct {
+	s influxdb.PasswordResetService
+}
+
+// NewPasswordResetService constructs an instance of an authorizing password reset service.
+func NewPasswordResetService(s influxdb.PasswordResetService) *PasswordResetService {
+	return &PasswordResetService{
+		s: s,
+	}
+}
+
+// CreatePasswordReset checks to see if the authorizer on context has write access to the user.
+func (s *PasswordResetService) CreatePasswordReset(ctx context.Context, userID influxdb.ID) (*influxdb.PasswordReset, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.s.CreatePasswordReset(ctx, userID)
+}
+
+// ResetPassword sets the password of the user of the password reset with the token.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token string, password string) error {
+	return s.s.ResetPassword(ctx, token, password)
+}
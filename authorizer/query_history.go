@@ -0,0 +1,60 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.QueryHistoryService = (*QueryHistoryService)(nil)
+
+// QueryHistoryService wraps a influxdb.QueryHistoryService and authorizes actions
+// against it appropriately.
+//
+// The query history belongs to its organization: anyone who may read the
+// organization may see the queries that ran in it.
+type QueryHistoryService struct {
+	s influxdb.QueryHistoryService
+}
+
+// NewQueryHistoryService constructs an instance of an authorizing query history service.
+func NewQueryHistoryService(s influxdb.QueryHistoryService) *QueryHistoryService {
+	return &QueryHistoryService{
+		s: s,
+	}
+}
+
+// AddQueryHistoryEntry checks to see if the authorizer on context has write access to the organization of the entry.
+func (s *QueryHistoryService) AddQueryHistoryEntry(ctx context.Context, e *influxdb.QueryHistoryEntry) error {
+	if err := authorizeWriteOrg(ctx, e.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.AddQueryHistoryEntry(ctx, e)
+}
+
+// FindQueryHistory retrieves all entries that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *QueryHistoryService) FindQueryHistory(ctx context.Context, filter influxdb.QueryHistoryFilter, opt ...influxdb.FindOptions) ([]*influxdb.QueryHistoryEntry, error) {
+	es, err := s.s.FindQueryHistory(ctx, filter, opt...)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	hes := es[:0]
+	for _, e := range es {
+		err := authorizeReadOrg(ctx, e.OrganizationID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		hes = append(hes, e)
+	}
+
+	return hes, nil
+}
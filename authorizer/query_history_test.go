@@ -0,0 +1,74 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestQueryHistoryService_FindQueryHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		want       []influxdb.ID
+	}{
+		{
+			name: "authorized to read all organizations",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+				},
+			},
+			want: []influxdb.ID{1, 2},
+		},
+		{
+			name: "authorized to read one organization",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(11),
+				},
+			},
+			want: []influxdb.ID{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewQueryHistoryService()
+			m.FindQueryHistoryFn = func(ctx context.Context, filter influxdb.QueryHistoryFilter, opt ...influxdb.FindOptions) ([]*influxdb.QueryHistoryEntry, error) {
+				return []*influxdb.QueryHistoryEntry{
+					{ID: 1, OrganizationID: 10},
+					{ID: 2, OrganizationID: 11},
+				}, nil
+			}
+			s := authorizer.NewQueryHistoryService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			es, err := s.FindQueryHistory(ctx, influxdb.QueryHistoryFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []influxdb.ID
+			for _, e := range es {
+				got = append(got, e.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got entries %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got entries %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
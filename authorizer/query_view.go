@@ -0,0 +1,94 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.QueryViewService = (*QueryViewService)(nil)
+
+// QueryViewService wraps a influxdb.QueryViewService and authorizes actions
+// against it appropriately.
+//
+// The result of a query view is stored in a bucket of its organization, so
+// query views share the permissions of the buckets of the organization.
+// The query of a view runs with the permissions of its creator.
+type QueryViewService struct {
+	s influxdb.QueryViewService
+}
+
+// NewQueryViewService constructs an instance of an authorizing query view service.
+func NewQueryViewService(s influxdb.QueryViewService) *QueryViewService {
+	return &QueryViewService{
+		s: s,
+	}
+}
+
+// FindQueryViewByID checks to see if the authorizer on context has read access to the bucket of the view.
+func (s *QueryViewService) FindQueryViewByID(ctx context.Context, id influxdb.ID) (*influxdb.QueryView, error) {
+	v, err := s.s.FindQueryViewByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, v.OrganizationID, v.BucketID); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// FindQueryViews retrieves all query views that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *QueryViewService) FindQueryViews(ctx context.Context, filter influxdb.QueryViewFilter) ([]*influxdb.QueryView, error) {
+	vs, err := s.s.FindQueryViews(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	qvs := vs[:0]
+	for _, v := range vs {
+		err := authorizeReadBucket(ctx, v.OrganizationID, v.BucketID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		qvs = append(qvs, v)
+	}
+
+	return qvs, nil
+}
+
+// CreateQueryView checks to see if the authorizer on context has write access to the buckets of the organization.
+func (s *QueryViewService) CreateQueryView(ctx context.Context, v *influxdb.QueryView) error {
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.BucketsResourceType, v.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateQueryView(ctx, v)
+}
+
+// DeleteQueryView checks to see if the authorizer on context has write access to the bucket of the view.
+func (s *QueryViewService) DeleteQueryView(ctx context.Context, id influxdb.ID) error {
+	v, err := s.s.FindQueryViewByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteBucket(ctx, v.OrganizationID, v.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteQueryView(ctx, id)
+}
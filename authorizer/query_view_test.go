@@ -0,0 +1,118 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestQueryViewService_FindQueryViews(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		want       []influxdb.ID
+	}{
+		{
+			name: "authorized to read the buckets of all organizations",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+				},
+			},
+			want: []influxdb.ID{1, 2},
+		},
+		{
+			name: "authorized to read the buckets of one organization",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			want: []influxdb.ID{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewQueryViewService()
+			m.FindQueryViewsFn = func(ctx context.Context, filter influxdb.QueryViewFilter) ([]*influxdb.QueryView, error) {
+				return []*influxdb.QueryView{
+					{ID: 1, OrganizationID: 10, BucketID: 12},
+					{ID: 2, OrganizationID: 11, BucketID: 12},
+				}, nil
+			}
+			s := authorizer.NewQueryViewService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			vs, err := s.FindQueryViews(ctx, influxdb.QueryViewFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []influxdb.ID
+			for _, v := range vs {
+				got = append(got, v.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got views %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got views %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryViewService_CreateQueryView(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the buckets of the organization",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write the buckets of the organization",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/buckets is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewQueryViewService(mock.NewQueryViewService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.CreateQueryView(ctx, &influxdb.QueryView{OrganizationID: 10, Name: "v", Query: "q"})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package authorizer
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.BucketQuotaService = (*BucketQuotaService)(nil)
+
+// BucketQuotaService wraps a influxdb.BucketQuotaService and authorizes actions
+// against it appropriately.
+type BucketQuotaService struct {
+	s influxdb.BucketQuotaService
+}
+
+// NewBucketQuotaService constructs an instance of an authorizing bucket quota service.
+func NewBucketQuotaService(s influxdb.BucketQuotaService) *BucketQuotaService {
+	return &BucketQuotaService{
+		s: s,
+	}
+}
+
+// ReserveWrite checks to see if the authorizer on context has write access to the bucket provided.
+func (s *BucketQuotaService) ReserveWrite(ctx context.Context, b *influxdb.Bucket, keys [][]byte, bytes int64) (time.Duration, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, b.OrgID, b.ID); err != nil {
+		return 0, err
+	}
+
+	return s.s.ReserveWrite(ctx, b, keys, bytes)
+}
+
+// FindBucketQuotaUsage checks to see if the authorizer on context has read access to the bucket provided.
+func (s *BucketQuotaService) FindBucketQuotaUsage(ctx context.Context, b *influxdb.Bucket) (*influxdb.BucketQuotaUsage, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, b.OrgID, b.ID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindBucketQuotaUsage(ctx, b)
+}
@@ -0,0 +1,67 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.RevisionService = (*RevisionService)(nil)
+
+// RevisionService wraps a influxdb.RevisionService and authorizes actions
+// against it appropriately.
+type RevisionService struct {
+	s influxdb.RevisionService
+}
+
+// NewRevisionService constructs an instance of an authorizing revision service.
+func NewRevisionService(s influxdb.RevisionService) *RevisionService {
+	return &RevisionService{
+		s: s,
+	}
+}
+
+// FindRevisions checks to see if the authorizer on context has read access to the resource of the revisions.
+func (s *RevisionService) FindRevisions(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, opt ...influxdb.FindOptions) ([]*influxdb.Revision, int, error) {
+	rs, n, err := s.s.FindRevisions(ctx, typ, id, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The revisions of a resource all belong to its organization.
+	for _, r := range rs {
+		if err := authorizeReadURM(ctx, r.ResourceType, r.OrgID, r.ResourceID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return rs, n, nil
+}
+
+// FindRevision checks to see if the authorizer on context has read access to the resource of the revision.
+func (s *RevisionService) FindRevision(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, version int) (*influxdb.Revision, error) {
+	r, err := s.s.FindRevision(ctx, typ, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadURM(ctx, r.ResourceType, r.OrgID, r.ResourceID); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// RestoreRevision checks to see if the authorizer on context has write access to the resource of the revision.
+func (s *RevisionService) RestoreRevision(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, version int) error {
+	r, err := s.s.FindRevision(ctx, typ, id, version)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteURM(ctx, r.ResourceType, r.OrgID, r.ResourceID); err != nil {
+		return err
+	}
+
+	return s.s.RestoreRevision(ctx, typ, id, version)
+}
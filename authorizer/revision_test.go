@@ -0,0 +1,68 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRevisionService(t *testing.T) {
+	var restored []int
+	m := mock.NewRevisionService()
+	m.FindRevisionFn = func(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, version int) (*influxdb.Revision, error) {
+		return &influxdb.Revision{ResourceType: typ, ResourceID: id, OrgID: 10, Version: version}, nil
+	}
+	m.FindRevisionsFn = func(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, opts ...influxdb.FindOptions) ([]*influxdb.Revision, int, error) {
+		return []*influxdb.Revision{
+			{ResourceType: typ, ResourceID: id, OrgID: 10, Version: 2},
+			{ResourceType: typ, ResourceID: id, OrgID: 10, Version: 1},
+		}, 2, nil
+	}
+	m.RestoreRevisionFn = func(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, version int) error {
+		restored = append(restored, version)
+		return nil
+	}
+	s := authorizer.NewRevisionService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action: "read",
+			Resource: influxdb.Resource{
+				Type:  influxdb.DashboardsResourceType,
+				OrgID: influxdbtesting.IDPtr(10),
+			},
+		},
+		{
+			Action: "write",
+			Resource: influxdb.Resource{
+				Type: influxdb.DashboardsResourceType,
+				ID:   influxdbtesting.IDPtr(1),
+			},
+		},
+	}})
+
+	rs, n, err := s.FindRevisions(ctx, influxdb.DashboardsResourceType, 2)
+	influxdbtesting.ErrorsEqual(t, err, nil)
+	if n != 2 || len(rs) != 2 {
+		t.Errorf("got %d revisions, want 2", n)
+	}
+	_, _, err = s.FindRevisions(ctx, influxdb.TasksResourceType, 2)
+	if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected finding revisions without read access to be unauthorized, got %v", err)
+	}
+
+	// Restoring requires write access to the resource.
+	influxdbtesting.ErrorsEqual(t, s.RestoreRevision(ctx, influxdb.DashboardsResourceType, 1, 1), nil)
+	err = s.RestoreRevision(ctx, influxdb.DashboardsResourceType, 2, 1)
+	if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected restoring without write access to be unauthorized, got %v", err)
+	}
+	if len(restored) != 1 || restored[0] != 1 {
+		t.Errorf("got restored versions %v, want [1]", restored)
+	}
+}
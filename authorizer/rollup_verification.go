@@ -0,0 +1,38 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.RollupVerificationService = (*RollupVerificationService)(nil)
+
+// RollupVerificationService wraps a influxdb.RollupVerificationService and authorizes actions
+// against it appropriately.
+type RollupVerificationService struct {
+	s influxdb.RollupVerificationService
+}
+
+// NewRollupVerificationService constructs an instance of an authorizing rollup verification service.
+func NewRollupVerificationService(s influxdb.RollupVerificationService) *RollupVerificationService {
+	return &RollupVerificationService{
+		s: s,
+	}
+}
+
+// VerifyRollup checks to see if the authorizer on context has read access to the rollup and its source bucket.
+func (s *RollupVerificationService) VerifyRollup(ctx context.Context, req influxdb.RollupVerificationRequest) (*influxdb.RollupVerification, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, req.OrgID, req.BucketID); err != nil {
+		return nil, err
+	}
+	if err := authorizeReadBucket(ctx, req.OrgID, req.SourceBucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.VerifyRollup(ctx, req)
+}
@@ -0,0 +1,74 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRollupVerificationService_VerifyRollup(t *testing.T) {
+	readBucket := func(id influxdb.ID) influxdb.Permission {
+		return influxdb.Permission{
+			Action: "read",
+			Resource: influxdb.Resource{
+				Type: influxdb.BucketsResourceType,
+				ID:   &id,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name:        "authorized to read both buckets",
+			permissions: []influxdb.Permission{readBucket(1), readBucket(2)},
+		},
+		{
+			name:        "unauthorized to read the rollup",
+			permissions: []influxdb.Permission{readBucket(2)},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/buckets/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:        "unauthorized to read the source",
+			permissions: []influxdb.Permission{readBucket(1)},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/buckets/0000000000000002 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewRollupVerificationService()
+			m.VerifyRollupFn = func(ctx context.Context, req influxdb.RollupVerificationRequest) (*influxdb.RollupVerification, error) {
+				return &influxdb.RollupVerification{Windows: 1}, nil
+			}
+			s := authorizer.NewRollupVerificationService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			now := time.Now()
+			_, err := s.VerifyRollup(ctx, influxdb.RollupVerificationRequest{
+				OrgID:          10,
+				BucketID:       1,
+				SourceBucketID: 2,
+				Start:          now.Add(-time.Hour),
+				Stop:           now,
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestRunningQueryService_FindRunningQueries(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		want       []influxdb.ID
+	}{
+		{
+			name: "authorized to read all organizations",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+				},
+			},
+			want: []influxdb.ID{1, 2},
+		},
+		{
+			name: "authorized to read one organization",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(10),
+				},
+			},
+			want: []influxdb.ID{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewRunningQueryService()
+			m.FindRunningQueriesFn = func(ctx context.Context, filter influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+				return []*influxdb.RunningQuery{
+					{ID: 1, OrganizationID: 10},
+					{ID: 2, OrganizationID: 11},
+				}, nil
+			}
+			s := authorizer.NewRunningQueryService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			qs, err := s.FindRunningQueries(ctx, influxdb.RunningQueryFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []influxdb.ID
+			for _, q := range qs {
+				got = append(got, q.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got queries %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got queries %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunningQueryService_CancelRunningQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write the organization",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write the organization",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.OrgsResourceType,
+					ID:   influxdbtesting.IDPtr(10),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewRunningQueryService()
+			m.FindRunningQueryByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.RunningQuery, error) {
+				return &influxdb.RunningQuery{ID: id, OrganizationID: 10}, nil
+			}
+			canceled := false
+			m.CancelRunningQueryFn = func(ctx context.Context, id influxdb.ID) error {
+				canceled = true
+				return nil
+			}
+			s := authorizer.NewRunningQueryService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.CancelRunningQuery(ctx, 1)
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+			if canceled != (tt.err == nil) {
+				t.Errorf("got canceled %v, want %v", canceled, tt.err == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.SchemaDocService = (*SchemaDocService)(nil)
+
+// SchemaDocService wraps a influxdb.SchemaDocService and authorizes actions
+// against it appropriately.
+type SchemaDocService struct {
+	s influxdb.SchemaDocService
+}
+
+// NewSchemaDocService constructs an instance of an authorizing schema doc service.
+func NewSchemaDocService(s influxdb.SchemaDocService) *SchemaDocService {
+	return &SchemaDocService{
+		s: s,
+	}
+}
+
+// FindSchemaDoc checks to see if the authorizer on context has read access to the bucket of the doc.
+func (s *SchemaDocService) FindSchemaDoc(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDoc, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	doc, err := s.s.FindSchemaDoc(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, doc.OrgID, doc.BucketID); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+var _ influxdb.SchemaDescriptionService = (*SchemaDescriptionService)(nil)
+
+// SchemaDescriptionService wraps a influxdb.SchemaDescriptionService and authorizes actions
+// against it appropriately.
+type SchemaDescriptionService struct {
+	s influxdb.SchemaDescriptionService
+}
+
+// NewSchemaDescriptionService constructs an instance of an authorizing schema description service.
+func NewSchemaDescriptionService(s influxdb.SchemaDescriptionService) *SchemaDescriptionService {
+	return &SchemaDescriptionService{
+		s: s,
+	}
+}
+
+// FindSchemaDescriptions checks to see if the authorizer on context has read access to the bucket of the descriptions.
+func (s *SchemaDescriptionService) FindSchemaDescriptions(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDescriptions, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	d, err := s.s.FindSchemaDescriptions(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadBucket(ctx, d.OrgID, d.BucketID); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// PutSchemaDescriptions checks to see if the authorizer on context has write access to the bucket of the descriptions.
+func (s *SchemaDescriptionService) PutSchemaDescriptions(ctx context.Context, d *influxdb.SchemaDescriptions) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeWriteBucket(ctx, d.OrgID, d.BucketID); err != nil {
+		return err
+	}
+
+	return s.s.PutSchemaDescriptions(ctx, d)
+}
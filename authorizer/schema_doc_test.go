@@ -0,0 +1,105 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestSchemaDocService_FindSchemaDoc(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to read bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+		},
+		{
+			name: "unauthorized to read bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(3),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/0000000000000001/buckets/0000000000000002 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewSchemaDocService()
+			m.FindSchemaDocFn = func(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDoc, error) {
+				return &influxdb.SchemaDoc{OrgID: 1, BucketID: bucketID}, nil
+			}
+			s := authorizer.NewSchemaDocService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			_, err := s.FindSchemaDoc(ctx, 2)
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+func TestSchemaDescriptionService_PutSchemaDescriptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to write bucket",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+		},
+		{
+			name: "unauthorized to write bucket",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.BucketsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/0000000000000001/buckets/0000000000000002 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewSchemaDescriptionService()
+			s := authorizer.NewSchemaDescriptionService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.PutSchemaDescriptions(ctx, &influxdb.SchemaDescriptions{OrgID: 1, BucketID: 2})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.ScraperTargetStatusService = (*ScraperTargetStatusService)(nil)
+
+// ScraperTargetStatusService wraps a influxdb.ScraperTargetStatusService and authorizes actions
+// against it appropriately.
+type ScraperTargetStatusService struct {
+	s  influxdb.ScraperTargetStatusService
+	ts influxdb.ScraperTargetStoreService
+}
+
+// NewScraperTargetStatusService constructs an instance of an authorizing scraper target status service.
+// The scraper target store finds the targets the statuses are of.
+func NewScraperTargetStatusService(s influxdb.ScraperTargetStatusService, ts influxdb.ScraperTargetStoreService) *ScraperTargetStatusService {
+	return &ScraperTargetStatusService{
+		s:  s,
+		ts: ts,
+	}
+}
+
+// PutTargetStatus checks to see if the authorizer on context has write access to the scraper target
+// of the status.
+func (s *ScraperTargetStatusService) PutTargetStatus(ctx context.Context, st *influxdb.ScraperTargetStatus) error {
+	target, err := s.ts.GetTargetByID(ctx, st.TargetID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteScraper(ctx, target.OrgID, target.ID); err != nil {
+		return err
+	}
+
+	return s.s.PutTargetStatus(ctx, st)
+}
+
+// FindTargetStatus checks to see if the authorizer on context has read access to the scraper target
+// of the status.
+func (s *ScraperTargetStatusService) FindTargetStatus(ctx context.Context, targetID influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+	target, err := s.ts.GetTargetByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadScraper(ctx, target.OrgID, target.ID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindTargetStatus(ctx, targetID)
+}
@@ -0,0 +1,92 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newScraperStatusTargetStore() *mock.ScraperTargetStoreService {
+	return &mock.ScraperTargetStoreService{
+		GetTargetByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTarget, error) {
+			return &influxdb.ScraperTarget{ID: id, OrgID: 10}, nil
+		},
+	}
+}
+
+func TestScraperTargetStatusService_FindTargetStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name: "authorized to read the scraper target",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "read",
+					Resource: influxdb.Resource{Type: influxdb.ScraperResourceType, ID: influxdbtesting.IDPtr(1), OrgID: influxdbtesting.IDPtr(10)},
+				},
+			},
+		},
+		{
+			name: "authorized to read another scraper target",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "read",
+					Resource: influxdb.Resource{Type: influxdb.ScraperResourceType, ID: influxdbtesting.IDPtr(2), OrgID: influxdbtesting.IDPtr(10)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewScraperTargetStatusService()
+			m.FindTargetStatusFn = func(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+				return &influxdb.ScraperTargetStatus{TargetID: id, OrgID: 10}, nil
+			}
+			s := authorizer.NewScraperTargetStatusService(m, newScraperStatusTargetStore())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.FindTargetStatus(ctx, 1)
+			if tt.wantErr && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Fatalf("expected finding the status to be unauthorized, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestScraperTargetStatusService_PutTargetStatus(t *testing.T) {
+	s := authorizer.NewScraperTargetStatusService(mock.NewScraperTargetStatusService(), newScraperStatusTargetStore())
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action:   "read",
+			Resource: influxdb.Resource{Type: influxdb.ScraperResourceType, ID: influxdbtesting.IDPtr(1), OrgID: influxdbtesting.IDPtr(10)},
+		},
+	}})
+	if err := s.PutTargetStatus(ctx, &influxdb.ScraperTargetStatus{TargetID: 1}); influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Fatalf("expected putting the status with read access to be unauthorized, got %v", err)
+	}
+
+	ctx = influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action:   "write",
+			Resource: influxdb.Resource{Type: influxdb.ScraperResourceType, ID: influxdbtesting.IDPtr(1), OrgID: influxdbtesting.IDPtr(10)},
+		},
+	}})
+	if err := s.PutTargetStatus(ctx, &influxdb.ScraperTargetStatus{TargetID: 1}); err != nil {
+		t.Fatal(err)
+	}
+}
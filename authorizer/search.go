@@ -0,0 +1,49 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SearchService = (*SearchService)(nil)
+
+// SearchService wraps a influxdb.SearchService and authorizes actions
+// against it appropriately.
+type SearchService struct {
+	s influxdb.SearchService
+}
+
+// NewSearchService constructs an instance of an authorizing search service.
+func NewSearchService(s influxdb.SearchService) *SearchService {
+	return &SearchService{
+		s: s,
+	}
+}
+
+// Search retrieves all resources that match the filter and then filters the list down to only the resources that are authorized.
+func (s *SearchService) Search(ctx context.Context, filter influxdb.SearchFilter, opt ...influxdb.FindOptions) ([]*influxdb.SearchResult, error) {
+	rs, err := s.s.Search(ctx, filter, unpaged(opt)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	results := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadURM(ctx, r.Type, r.OrgID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		results = append(results, r)
+	}
+
+	lo, hi := page(opt, len(results))
+	return results[lo:hi], nil
+}
@@ -0,0 +1,44 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	m := mock.NewSearchService()
+	m.SearchFn = func(ctx context.Context, filter influxdb.SearchFilter, opts ...influxdb.FindOptions) ([]*influxdb.SearchResult, error) {
+		return []*influxdb.SearchResult{
+			{Type: influxdb.BucketsResourceType, ID: 1, OrgID: 10, Name: "cpu"},
+			{Type: influxdb.DashboardsResourceType, ID: 2, OrgID: 10, Name: "cpu"},
+			{Type: influxdb.BucketsResourceType, ID: 3, OrgID: 11, Name: "cpu"},
+			{Type: influxdb.BucketsResourceType, ID: 4, OrgID: 10, Name: "cpu usage"},
+		}, nil
+	}
+	s := authorizer.NewSearchService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action: "read",
+			Resource: influxdb.Resource{
+				Type:  influxdb.BucketsResourceType,
+				OrgID: influxdbtesting.IDPtr(10),
+			},
+		},
+	}})
+
+	rs, err := s.Search(ctx, influxdb.SearchFilter{Query: "cpu"}, influxdb.FindOptions{Offset: 1, Limit: 1})
+	influxdbtesting.ErrorsEqual(t, err, nil)
+
+	want := []*influxdb.SearchResult{{Type: influxdb.BucketsResourceType, ID: 4, OrgID: 10, Name: "cpu usage"}}
+	if diff := cmp.Diff(rs, want); diff != "" {
+		t.Errorf("results are different -got/+want\ndiff %s", diff)
+	}
+}
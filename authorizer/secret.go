@@ -140,3 +140,45 @@ func (s *SecretService) DeleteSecret(ctx context.Context, orgID influxdb.ID, key
 
 	return nil
 }
+
+var _ influxdb.SecretRotationService = (*SecretRotationService)(nil)
+
+// SecretRotationService wraps a influxdb.SecretRotationService and authorizes actions
+// against it appropriately.
+type SecretRotationService struct {
+	s influxdb.SecretRotationService
+}
+
+// NewSecretRotationService constructs an instance of an authorizing secret rotation service.
+func NewSecretRotationService(s influxdb.SecretRotationService) *SecretRotationService {
+	return &SecretRotationService{
+		s: s,
+	}
+}
+
+// FindSecretVersions checks to see if the authorizer on context has read access to the secrets belonging to orgID.
+func (s *SecretRotationService) FindSecretVersions(ctx context.Context, orgID influxdb.ID, key string) ([]*influxdb.SecretVersion, error) {
+	if err := authorizeReadSecret(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindSecretVersions(ctx, orgID, key)
+}
+
+// LoadSecretVersion checks to see if the authorizer on context has read access to the secret key provided.
+func (s *SecretRotationService) LoadSecretVersion(ctx context.Context, orgID influxdb.ID, key string, version int) (string, error) {
+	if err := authorizeReadSecret(ctx, orgID); err != nil {
+		return "", err
+	}
+
+	return s.s.LoadSecretVersion(ctx, orgID, key, version)
+}
+
+// RotateSecret checks to see if the authorizer on context has write access to the secret key provided.
+func (s *SecretRotationService) RotateSecret(ctx context.Context, orgID influxdb.ID, key string, val string) (*influxdb.SecretVersion, error) {
+	if err := authorizeWriteSecret(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	return s.s.RotateSecret(ctx, orgID, key, val)
+}
@@ -696,3 +696,85 @@ func TestSecretService_PutSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretRotationService_RotateSecret(t *testing.T) {
+	type fields struct {
+		SecretRotationService influxdb.SecretRotationService
+	}
+	type args struct {
+		permission influxdb.Permission
+		org        influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "authorized to rotate secret",
+			fields: fields{
+				SecretRotationService: &mock.SecretRotationService{
+					RotateSecretFn: func(ctx context.Context, orgID influxdb.ID, k string, v string) (*influxdb.SecretVersion, error) {
+						return &influxdb.SecretVersion{OrgID: orgID, Key: k, Version: 2}, nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "write",
+					Resource: influxdb.Resource{
+						Type:  influxdb.SecretsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+				org: influxdb.ID(10),
+			},
+			wants: wants{
+				err: nil,
+			},
+		},
+		{
+			name: "unauthorized to rotate secret",
+			fields: fields{
+				SecretRotationService: &mock.SecretRotationService{
+					RotateSecretFn: func(ctx context.Context, orgID influxdb.ID, k string, v string) (*influxdb.SecretVersion, error) {
+						return &influxdb.SecretVersion{OrgID: orgID, Key: k, Version: 2}, nil
+					},
+				},
+			},
+			args: args{
+				permission: influxdb.Permission{
+					Action: "read",
+					Resource: influxdb.Resource{
+						Type:  influxdb.SecretsResourceType,
+						OrgID: influxdbtesting.IDPtr(10),
+					},
+				},
+				org: influxdb.ID(10),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "write:orgs/000000000000000a/secrets is unauthorized",
+					Code: influxdb.EUnauthorized,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewSecretRotationService(tt.fields.SecretRotationService)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{[]influxdb.Permission{tt.args.permission}})
+
+			_, err := s.RotateSecret(ctx, tt.args.org, "key", "val")
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
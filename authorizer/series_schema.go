@@ -0,0 +1,59 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.SeriesSchemaService = (*SeriesSchemaService)(nil)
+
+// SeriesSchemaService wraps a influxdb.SeriesSchemaService and authorizes actions
+// against it appropriately.
+type SeriesSchemaService struct {
+	s influxdb.SeriesSchemaService
+}
+
+// NewSeriesSchemaService constructs an instance of an authorizing series schema service.
+func NewSeriesSchemaService(s influxdb.SeriesSchemaService) *SeriesSchemaService {
+	return &SeriesSchemaService{
+		s: s,
+	}
+}
+
+// FindMeasurements checks to see if the authorizer on context has read access to the bucket of the filter.
+func (s *SeriesSchemaService) FindMeasurements(ctx context.Context, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, filter.OrgID, filter.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindMeasurements(ctx, filter)
+}
+
+// FindTagKeys checks to see if the authorizer on context has read access to the bucket of the filter.
+func (s *SeriesSchemaService) FindTagKeys(ctx context.Context, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, filter.OrgID, filter.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindTagKeys(ctx, filter)
+}
+
+// FindTagValues checks to see if the authorizer on context has read access to the bucket of the filter.
+func (s *SeriesSchemaService) FindTagValues(ctx context.Context, tagKey string, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := authorizeReadBucket(ctx, filter.OrgID, filter.BucketID); err != nil {
+		return nil, err
+	}
+
+	return s.s.FindTagValues(ctx, tagKey, filter)
+}
@@ -0,0 +1,136 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.SilenceService = (*SilenceService)(nil)
+
+// SilenceService wraps a influxdb.SilenceService and authorizes actions
+// against it appropriately.
+type SilenceService struct {
+	s influxdb.SilenceService
+}
+
+// NewSilenceService constructs an instance of an authorizing silence service.
+func NewSilenceService(s influxdb.SilenceService) *SilenceService {
+	return &SilenceService{
+		s: s,
+	}
+}
+
+func newSilencePermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.SilencesResourceType, orgID)
+}
+
+func authorizeReadSilence(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newSilencePermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteSilence(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newSilencePermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindSilenceByID checks to see if the authorizer on context has read access to the id provided.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadSilence(ctx, sl.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return sl, nil
+}
+
+// FindSilences retrieves all silences that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *SilenceService) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ss, _, err := s.s.FindSilences(ctx, filter, unpaged(opt)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	silences := ss[:0]
+	for _, sl := range ss {
+		err := authorizeReadSilence(ctx, sl.OrgID, sl.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		silences = append(silences, sl)
+	}
+
+	lo, hi := page(opt, len(silences))
+	silences = silences[lo:hi]
+
+	return silences, len(silences), nil
+}
+
+// CreateSilence checks to see if the authorizer on context has write access to the silences of the organization.
+func (s *SilenceService) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.SilencesResourceType, sl.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateSilence(ctx, sl)
+}
+
+// ExpireSilence checks to see if the authorizer on context has write access to the silence provided.
+func (s *SilenceService) ExpireSilence(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	sl, err := s.s.FindSilenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteSilence(ctx, sl.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.ExpireSilence(ctx, id)
+}
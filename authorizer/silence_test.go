@@ -0,0 +1,63 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestSilenceService_ExpireSilence(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name: "authorized to write the silences of the organization",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "write",
+					Resource: influxdb.Resource{Type: influxdb.SilencesResourceType, OrgID: influxdbtesting.IDPtr(10)},
+				},
+			},
+		},
+		{
+			name: "authorized to read the silence only",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "read",
+					Resource: influxdb.Resource{Type: influxdb.SilencesResourceType, ID: influxdbtesting.IDPtr(1)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewSilenceService()
+			m.FindSilenceByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+				return &influxdb.Silence{ID: id, OrgID: 10}, nil
+			}
+			m.ExpireSilenceFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+				return &influxdb.Silence{ID: id, OrgID: 10}, nil
+			}
+			s := authorizer.NewSilenceService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.ExpireSilence(ctx, 1)
+			if tt.wantErr && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Fatalf("expected the expiration to be unauthorized, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.StackService = (*StackService)(nil)
+
+// StackService wraps a influxdb.StackService and authorizes actions
+// against it appropriately.
+type StackService struct {
+	s influxdb.StackService
+}
+
+// NewStackService constructs an instance of an authorizing stack service.
+func NewStackService(s influxdb.StackService) *StackService {
+	return &StackService{
+		s: s,
+	}
+}
+
+func newStackPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.StacksResourceType, orgID)
+}
+
+func authorizeReadStack(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newStackPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteStack(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newStackPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindStackByID checks to see if the authorizer on context has read access to the id provided.
+func (s *StackService) FindStackByID(ctx context.Context, id influxdb.ID) (*influxdb.Stack, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	st, err := s.s.FindStackByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadStack(ctx, st.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+// FindStacks retrieves all stacks that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *StackService) FindStacks(ctx context.Context, filter influxdb.StackFilter, opt ...influxdb.FindOptions) ([]*influxdb.Stack, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ss, _, err := s.s.FindStacks(ctx, filter, unpaged(opt)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	stacks := ss[:0]
+	for _, st := range ss {
+		err := authorizeReadStack(ctx, st.OrgID, st.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		stacks = append(stacks, st)
+	}
+
+	lo, hi := page(opt, len(stacks))
+	stacks = stacks[lo:hi]
+
+	return stacks, len(stacks), nil
+}
+
+// CreateStack checks to see if the authorizer on context has write access to the stacks of the organization.
+func (s *StackService) CreateStack(ctx context.Context, st *influxdb.Stack) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.StacksResourceType, st.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateStack(ctx, st)
+}
+
+// UpdateStack checks to see if the authorizer on context has write access to the stack provided.
+func (s *StackService) UpdateStack(ctx context.Context, id influxdb.ID, upd influxdb.StackUpdate) (*influxdb.Stack, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	st, err := s.s.FindStackByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteStack(ctx, st.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateStack(ctx, id, upd)
+}
+
+// DeleteStack checks to see if the authorizer on context has write access to the stack provided.
+func (s *StackService) DeleteStack(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	st, err := s.s.FindStackByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteStack(ctx, st.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteStack(ctx, id)
+}
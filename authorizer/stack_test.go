@@ -0,0 +1,83 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestStackService_DeleteStack(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name: "authorized to write the stacks of the organization",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "write",
+					Resource: influxdb.Resource{Type: influxdb.StacksResourceType, OrgID: influxdbtesting.IDPtr(10)},
+				},
+			},
+		},
+		{
+			name: "authorized to read the stack only",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "read",
+					Resource: influxdb.Resource{Type: influxdb.StacksResourceType, ID: influxdbtesting.IDPtr(1)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewStackService()
+			m.FindStackByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Stack, error) {
+				return &influxdb.Stack{ID: id, OrgID: 10}, nil
+			}
+			s := authorizer.NewStackService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			err := s.DeleteStack(ctx, 1)
+			if tt.wantErr && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Fatalf("expected the deletion to be unauthorized, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestStackService_FindStacks(t *testing.T) {
+	m := mock.NewStackService()
+	m.FindStacksFn = func(ctx context.Context, filter influxdb.StackFilter, opt ...influxdb.FindOptions) ([]*influxdb.Stack, int, error) {
+		return []*influxdb.Stack{{ID: 1, OrgID: 10}, {ID: 2, OrgID: 11}}, 2, nil
+	}
+	s := authorizer.NewStackService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action:   "read",
+			Resource: influxdb.Resource{Type: influxdb.StacksResourceType, OrgID: influxdbtesting.IDPtr(10)},
+		},
+	}})
+
+	stacks, n, err := s.FindStacks(ctx, influxdb.StackFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || stacks[0].ID != 1 {
+		t.Errorf("expected only the stack of organization 10, got %+v", stacks)
+	}
+}
@@ -272,7 +272,7 @@ func (ts *taskServiceValidator) CancelRun(ctx context.Context, taskID, runID pla
 	return ts.TaskService.CancelRun(ctx, taskID, runID)
 }
 
-func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID platform.ID) (*platform.Run, error) {
+func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID platform.ID, annotation string) (*platform.Run, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -297,10 +297,10 @@ func (ts *taskServiceValidator) RetryRun(ctx context.Context, taskID, runID plat
 		return nil, err
 	}
 
-	return ts.TaskService.RetryRun(ctx, taskID, runID)
+	return ts.TaskService.RetryRun(ctx, taskID, runID, annotation)
 }
 
-func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*platform.Run, error) {
+func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64, annotation string) (*platform.Run, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -325,7 +325,7 @@ func (ts *taskServiceValidator) ForceRun(ctx context.Context, taskID platform.ID
 		return nil, err
 	}
 
-	return ts.TaskService.ForceRun(ctx, taskID, scheduledFor)
+	return ts.TaskService.ForceRun(ctx, taskID, scheduledFor, annotation)
 }
 
 func (ts *taskServiceValidator) validatePermission(ctx context.Context, perm platform.Permission, loggerFields ...zap.Field) error {
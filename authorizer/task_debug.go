@@ -0,0 +1,46 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskDebugService = (*TaskDebugService)(nil)
+
+// TaskDebugService wraps a influxdb.TaskDebugService and authorizes actions
+// against it appropriately.
+type TaskDebugService struct {
+	s  influxdb.TaskDebugService
+	ts influxdb.TaskService
+}
+
+// NewTaskDebugService constructs an instance of an authorizing task debug service.
+// The task service is used to find the organization of a task, and must not authorize the lookup.
+func NewTaskDebugService(s influxdb.TaskDebugService, ts influxdb.TaskService) *TaskDebugService {
+	return &TaskDebugService{
+		s:  s,
+		ts: ts,
+	}
+}
+
+// DebugRun checks to see if the authorizer on context may write the task,
+// since a debug run acts with the authorization of the task.
+func (s *TaskDebugService) DebugRun(ctx context.Context, taskID influxdb.ID, req influxdb.TaskDebugRequest) (*influxdb.TaskDebugRun, error) {
+	// Unauthenticated task lookup, to identify the task's organization.
+	t, err := s.ts.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := influxdb.NewPermissionAtID(taskID, influxdb.WriteAction, influxdb.TasksResourceType, t.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return nil, err
+	}
+
+	return s.s.DebugRun(ctx, taskID, req)
+}
@@ -0,0 +1,60 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestTaskDebugService_DebugRun(t *testing.T) {
+	taskID := influxdb.ID(1)
+	task := func(action influxdb.Action) influxdb.Permission {
+		return influxdb.Permission{
+			Action: action,
+			Resource: influxdb.Resource{
+				Type: influxdb.TasksResourceType,
+				ID:   &taskID,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name:        "authorized to write the task",
+			permissions: []influxdb.Permission{task(influxdb.WriteAction)},
+		},
+		{
+			name:        "unauthorized to write the task",
+			permissions: []influxdb.Permission{task(influxdb.ReadAction)},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/tasks/0000000000000001 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := &mock.TaskService{
+				FindTaskByIDFn: func(_ context.Context, id influxdb.ID) (*influxdb.Task, error) {
+					return &influxdb.Task{ID: id, OrganizationID: 10}, nil
+				},
+			}
+			s := authorizer.NewTaskDebugService(mock.NewTaskDebugService(), ts)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			_, err := s.DebugRun(ctx, taskID, influxdb.TaskDebugRequest{})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.SchedulerStateService = (*SchedulerStateService)(nil)
+
+// SchedulerStateService wraps a influxdb.SchedulerStateService and authorizes actions
+// against it appropriately.
+type SchedulerStateService struct {
+	s influxdb.SchedulerStateService
+}
+
+// NewSchedulerStateService constructs an instance of an authorizing scheduler state service.
+func NewSchedulerStateService(s influxdb.SchedulerStateService) *SchedulerStateService {
+	return &SchedulerStateService{
+		s: s,
+	}
+}
+
+// SchedulerState checks to see if the authorizer on context may read the tasks of every organization,
+// since the scheduler state covers all of them.
+func (s *SchedulerStateService) SchedulerState(ctx context.Context) (*influxdb.SchedulerState, error) {
+	p, err := influxdb.NewGlobalPermission(influxdb.ReadAction, influxdb.TasksResourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return nil, err
+	}
+
+	return s.s.SchedulerState(ctx)
+}
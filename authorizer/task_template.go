@@ -0,0 +1,115 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TaskTemplateService = (*TaskTemplateService)(nil)
+
+// TaskTemplateService wraps a influxdb.TaskTemplateService and authorizes actions
+// against it appropriately.
+//
+// Task templates share the permissions of the tasks of their organization,
+// since anyone allowed to create tasks can write the same Flux by hand.
+type TaskTemplateService struct {
+	s influxdb.TaskTemplateService
+}
+
+// NewTaskTemplateService constructs an instance of an authorizing task template service.
+func NewTaskTemplateService(s influxdb.TaskTemplateService) *TaskTemplateService {
+	return &TaskTemplateService{
+		s: s,
+	}
+}
+
+func authorizeTaskTemplate(ctx context.Context, a influxdb.Action, orgID influxdb.ID) error {
+	p, err := influxdb.NewPermission(a, influxdb.TasksResourceType, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindTaskTemplateByID checks to see if the authorizer on context has read access to the tasks of the template's organization.
+func (s *TaskTemplateService) FindTaskTemplateByID(ctx context.Context, id influxdb.ID) (*influxdb.TaskTemplate, error) {
+	tt, err := s.s.FindTaskTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeTaskTemplate(ctx, influxdb.ReadAction, tt.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	return tt, nil
+}
+
+// FindTaskTemplates retrieves all task templates that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *TaskTemplateService) FindTaskTemplates(ctx context.Context, filter influxdb.TaskTemplateFilter) ([]*influxdb.TaskTemplate, error) {
+	ts, err := s.s.FindTaskTemplates(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	tts := ts[:0]
+	for _, tt := range ts {
+		err := authorizeTaskTemplate(ctx, influxdb.ReadAction, tt.OrganizationID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		tts = append(tts, tt)
+	}
+
+	return tts, nil
+}
+
+// CreateTaskTemplate checks to see if the authorizer on context has write access to the tasks of the organization.
+func (s *TaskTemplateService) CreateTaskTemplate(ctx context.Context, tt *influxdb.TaskTemplate) error {
+	if err := authorizeTaskTemplate(ctx, influxdb.WriteAction, tt.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.CreateTaskTemplate(ctx, tt)
+}
+
+// UpdateTaskTemplate checks to see if the authorizer on context has write access to the tasks of the template's organization.
+func (s *TaskTemplateService) UpdateTaskTemplate(ctx context.Context, id influxdb.ID, upd influxdb.TaskTemplateUpdate) (*influxdb.TaskTemplate, error) {
+	tt, err := s.s.FindTaskTemplateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeTaskTemplate(ctx, influxdb.WriteAction, tt.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateTaskTemplate(ctx, id, upd)
+}
+
+// DeleteTaskTemplate checks to see if the authorizer on context has write access to the tasks of the template's organization.
+func (s *TaskTemplateService) DeleteTaskTemplate(ctx context.Context, id influxdb.ID) error {
+	tt, err := s.s.FindTaskTemplateByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeTaskTemplate(ctx, influxdb.WriteAction, tt.OrganizationID); err != nil {
+		return err
+	}
+
+	return s.s.DeleteTaskTemplate(ctx, id)
+}
@@ -102,10 +102,10 @@ from(bucket:"holder") |> range(start:-5m) |> to(bucket:"holder", org:"thing")`,
 		CancelRunFn: func(context.Context, influxdb.ID, influxdb.ID) error {
 			return nil
 		},
-		RetryRunFn: func(context.Context, influxdb.ID, influxdb.ID) (*influxdb.Run, error) {
+		RetryRunFn: func(context.Context, influxdb.ID, influxdb.ID, string) (*influxdb.Run, error) {
 			return &run, nil
 		},
-		ForceRunFn: func(context.Context, influxdb.ID, int64) (*influxdb.Run, error) {
+		ForceRunFn: func(context.Context, influxdb.ID, int64, string) (*influxdb.Run, error) {
 			return &run, nil
 		},
 	}
@@ -525,7 +525,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"cows", org:"thing")`
 			name: "RetryRun with bad auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: wrongOrgReadAllTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.RetryRun(ctx, taskID, 10)
+				_, err := svc.RetryRun(ctx, taskID, 10, "")
 				if err == nil {
 					return errors.New("returned no error with a invalid auth")
 				}
@@ -536,7 +536,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"cows", org:"thing")`
 			name: "RetryRun with org auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteAllTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.RetryRun(ctx, taskID, 10)
+				_, err := svc.RetryRun(ctx, taskID, 10, "")
 				return err
 			},
 		},
@@ -544,7 +544,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"cows", org:"thing")`
 			name: "RetryRun with task auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.RetryRun(ctx, taskID, 10)
+				_, err := svc.RetryRun(ctx, taskID, 10, "")
 				return err
 			},
 		},
@@ -552,7 +552,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"cows", org:"thing")`
 			name: "ForceRun with bad auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: wrongOrgReadAllTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.ForceRun(ctx, taskID, 10000)
+				_, err := svc.ForceRun(ctx, taskID, 10000, "")
 				if err == nil {
 					return errors.New("returned no error with a invalid auth")
 				}
@@ -563,7 +563,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"cows", org:"thing")`
 			name: "ForceRun with org auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteAllTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.ForceRun(ctx, taskID, 10000)
+				_, err := svc.ForceRun(ctx, taskID, 10000, "")
 				return err
 			},
 		},
@@ -571,7 +571,7 @@ from(bucket:"cows") |> range(start:-5m) |> to(bucket:"cows", org:"thing")`
 			name: "ForceRun with task auth",
 			auth: &influxdb.Authorization{Status: "active", Permissions: orgWriteTaskPermissions},
 			check: func(ctx context.Context, svc influxdb.TaskService) error {
-				_, err := svc.ForceRun(ctx, taskID, 10000)
+				_, err := svc.ForceRun(ctx, taskID, 10000, "")
 				return err
 			},
 		},
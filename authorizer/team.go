@@ -0,0 +1,150 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+var _ influxdb.TeamService = (*TeamService)(nil)
+
+// TeamService wraps a influxdb.TeamService and authorizes actions
+// against it appropriately.
+type TeamService struct {
+	s influxdb.TeamService
+}
+
+// NewTeamService constructs an instance of an authorizing team service.
+func NewTeamService(s influxdb.TeamService) *TeamService {
+	return &TeamService{
+		s: s,
+	}
+}
+
+func newTeamPermission(a influxdb.Action, orgID, id influxdb.ID) (*influxdb.Permission, error) {
+	return influxdb.NewPermissionAtID(id, a, influxdb.TeamsResourceType, orgID)
+}
+
+func authorizeReadTeam(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newTeamPermission(influxdb.ReadAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func authorizeWriteTeam(ctx context.Context, orgID, id influxdb.ID) error {
+	p, err := newTeamPermission(influxdb.WriteAction, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindTeamByID checks to see if the authorizer on context has read access to the id provided.
+func (s *TeamService) FindTeamByID(ctx context.Context, id influxdb.ID) (*influxdb.Team, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	t, err := s.s.FindTeamByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadTeam(ctx, t.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// FindTeams retrieves all teams that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *TeamService) FindTeams(ctx context.Context, filter influxdb.TeamFilter, opt ...influxdb.FindOptions) ([]*influxdb.Team, int, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	ts, _, err := s.s.FindTeams(ctx, filter, opt...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	teams := ts[:0]
+	for _, t := range ts {
+		err := authorizeReadTeam(ctx, t.OrgID, t.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		teams = append(teams, t)
+	}
+
+	return teams, len(teams), nil
+}
+
+// CreateTeam checks to see if the authorizer on context has write access to the teams of the organization.
+func (s *TeamService) CreateTeam(ctx context.Context, t *influxdb.Team) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	p, err := influxdb.NewPermission(influxdb.WriteAction, influxdb.TeamsResourceType, t.OrgID)
+	if err != nil {
+		return err
+	}
+
+	if err := IsAllowed(ctx, *p); err != nil {
+		return err
+	}
+
+	return s.s.CreateTeam(ctx, t)
+}
+
+// UpdateTeam checks to see if the authorizer on context has write access to the team provided.
+func (s *TeamService) UpdateTeam(ctx context.Context, id influxdb.ID, upd influxdb.TeamUpdate) (*influxdb.Team, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	t, err := s.s.FindTeamByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeWriteTeam(ctx, t.OrgID, id); err != nil {
+		return nil, err
+	}
+
+	return s.s.UpdateTeam(ctx, id, upd)
+}
+
+// DeleteTeam checks to see if the authorizer on context has write access to the team provided.
+func (s *TeamService) DeleteTeam(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	t, err := s.s.FindTeamByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteTeam(ctx, t.OrgID, id); err != nil {
+		return err
+	}
+
+	return s.s.DeleteTeam(ctx, id)
+}
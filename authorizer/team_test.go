@@ -0,0 +1,198 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestTeamService_FindTeams(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		teams      []*influxdb.Team
+	}{
+		{
+			name: "authorized to read all teams of the org",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type:  influxdb.TeamsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+			teams: []*influxdb.Team{
+				{ID: 1, OrgID: 10, Name: "ops"},
+				{ID: 2, OrgID: 10, Name: "dev"},
+			},
+		},
+		{
+			name: "authorized to read a single team",
+			permission: influxdb.Permission{
+				Action: "read",
+				Resource: influxdb.Resource{
+					Type: influxdb.TeamsResourceType,
+					ID:   influxdbtesting.IDPtr(2),
+				},
+			},
+			teams: []*influxdb.Team{
+				{ID: 2, OrgID: 10, Name: "dev"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock.NewTeamService()
+			m.FindTeamsFn = func(ctx context.Context, filter influxdb.TeamFilter, opt ...influxdb.FindOptions) ([]*influxdb.Team, int, error) {
+				return []*influxdb.Team{
+					{ID: 1, OrgID: 10, Name: "ops"},
+					{ID: 2, OrgID: 10, Name: "dev"},
+				}, 2, nil
+			}
+			s := authorizer.NewTeamService(m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			ts, _, err := s.FindTeams(ctx, influxdb.TeamFilter{})
+			influxdbtesting.ErrorsEqual(t, err, nil)
+
+			if diff := cmp.Diff(ts, tt.teams); diff != "" {
+				t.Errorf("teams are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestTeamService_CreateTeam(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission influxdb.Permission
+		err        error
+	}{
+		{
+			name: "authorized to create teams of the org",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type:  influxdb.TeamsResourceType,
+					OrgID: influxdbtesting.IDPtr(10),
+				},
+			},
+		},
+		{
+			name: "unauthorized to create teams of the org",
+			permission: influxdb.Permission{
+				Action: "write",
+				Resource: influxdb.Resource{
+					Type:  influxdb.TeamsResourceType,
+					OrgID: influxdbtesting.IDPtr(11),
+				},
+			},
+			err: &influxdb.Error{
+				Msg:  "write:orgs/000000000000000a/teams is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTeamService(mock.NewTeamService())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{tt.permission}})
+
+			err := s.CreateTeam(ctx, &influxdb.Team{OrgID: 10, Name: "ops"})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
+
+// teamOrgService finds the organization of teams and of other resources separately.
+type teamOrgService struct {
+	TeamOrgID     influxdb.ID
+	ResourceOrgID influxdb.ID
+}
+
+func (s *teamOrgService) FindResourceOrganizationID(ctx context.Context, rt influxdb.ResourceType, id influxdb.ID) (influxdb.ID, error) {
+	if rt == influxdb.TeamsResourceType {
+		return s.TeamOrgID, nil
+	}
+	return s.ResourceOrgID, nil
+}
+
+func TestURMService_CreateTeamUserResourceMapping(t *testing.T) {
+	writeDashboards := influxdb.Permission{
+		Action: "write",
+		Resource: influxdb.Resource{
+			Type:  influxdb.DashboardsResourceType,
+			OrgID: influxdbtesting.IDPtr(10),
+		},
+	}
+	readTeams := influxdb.Permission{
+		Action: "read",
+		Resource: influxdb.Resource{
+			Type:  influxdb.TeamsResourceType,
+			OrgID: influxdbtesting.IDPtr(10),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		teamOrgID   influxdb.ID
+		permissions []influxdb.Permission
+		err         error
+	}{
+		{
+			name:        "authorized to share a dashboard with a team of its org",
+			teamOrgID:   10,
+			permissions: []influxdb.Permission{writeDashboards, readTeams},
+		},
+		{
+			name:        "unauthorized to read the team",
+			teamOrgID:   10,
+			permissions: []influxdb.Permission{writeDashboards},
+			err: &influxdb.Error{
+				Msg:  "read:orgs/000000000000000a/teams/0000000000000005 is unauthorized",
+				Code: influxdb.EUnauthorized,
+			},
+		},
+		{
+			name:        "team of another org",
+			teamOrgID:   11,
+			permissions: []influxdb.Permission{writeDashboards, readTeams},
+			err: &influxdb.Error{
+				Msg:  "a team can only be mapped to resources of its organization",
+				Code: influxdb.EInvalid,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mock.UserResourceMappingService{
+				CreateMappingFn: func(ctx context.Context, m *influxdb.UserResourceMapping) error {
+					return nil
+				},
+			}
+			s := authorizer.NewURMService(&teamOrgService{TeamOrgID: tt.teamOrgID, ResourceOrgID: 10}, m)
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			err := s.CreateUserResourceMapping(ctx, &influxdb.UserResourceMapping{
+				UserID:       5,
+				UserType:     influxdb.Member,
+				MappingType:  influxdb.TeamMappingType,
+				ResourceType: influxdb.DashboardsResourceType,
+				ResourceID:   1,
+			})
+			influxdbtesting.ErrorsEqual(t, err, tt.err)
+		})
+	}
+}
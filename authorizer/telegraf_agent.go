@@ -0,0 +1,67 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TelegrafAgentService = (*TelegrafAgentService)(nil)
+
+// TelegrafAgentService wraps a influxdb.TelegrafAgentService and authorizes actions
+// against it appropriately.
+type TelegrafAgentService struct {
+	s  influxdb.TelegrafAgentService
+	ts influxdb.TelegrafConfigStore
+}
+
+// NewTelegrafAgentService constructs an instance of an authorizing telegraf agent service.
+// The telegraf config store finds the configs the agents check in with.
+func NewTelegrafAgentService(s influxdb.TelegrafAgentService, ts influxdb.TelegrafConfigStore) *TelegrafAgentService {
+	return &TelegrafAgentService{
+		s:  s,
+		ts: ts,
+	}
+}
+
+// CheckInTelegrafAgent checks to see if the authorizer on context has read access to the telegraf config
+// of the agent, which agents have as they download it.
+func (s *TelegrafAgentService) CheckInTelegrafAgent(ctx context.Context, a *influxdb.TelegrafAgent) error {
+	tc, err := s.ts.FindTelegrafConfigByID(ctx, a.ConfigID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeReadTelegraf(ctx, tc.OrgID, tc.ID); err != nil {
+		return err
+	}
+
+	return s.s.CheckInTelegrafAgent(ctx, a)
+}
+
+// FindTelegrafAgents retrieves all telegraf agents that match the provided filter and then filters the list
+// down to the agents of the telegraf configs that are authorized.
+func (s *TelegrafAgentService) FindTelegrafAgents(ctx context.Context, filter influxdb.TelegrafAgentFilter) ([]*influxdb.TelegrafAgent, error) {
+	as, err := s.s.FindTelegrafAgents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	agents := as[:0]
+	for _, a := range as {
+		err := authorizeReadTelegraf(ctx, a.OrgID, a.ConfigID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		agents = append(agents, a)
+	}
+
+	return agents, nil
+}
@@ -0,0 +1,90 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func newTelegrafAgentConfigStore() *mock.TelegrafConfigStore {
+	return &mock.TelegrafConfigStore{
+		FindTelegrafConfigByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.TelegrafConfig, error) {
+			return &influxdb.TelegrafConfig{ID: id, OrgID: 10}, nil
+		},
+	}
+}
+
+func TestTelegrafAgentService_CheckInTelegrafAgent(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []influxdb.Permission
+		wantErr     bool
+	}{
+		{
+			name: "authorized to read the telegraf config",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "read",
+					Resource: influxdb.Resource{Type: influxdb.TelegrafsResourceType, ID: influxdbtesting.IDPtr(1), OrgID: influxdbtesting.IDPtr(10)},
+				},
+			},
+		},
+		{
+			name: "authorized to read another telegraf config",
+			permissions: []influxdb.Permission{
+				{
+					Action:   "read",
+					Resource: influxdb.Resource{Type: influxdb.TelegrafsResourceType, ID: influxdbtesting.IDPtr(2), OrgID: influxdbtesting.IDPtr(10)},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := authorizer.NewTelegrafAgentService(mock.NewTelegrafAgentService(), newTelegrafAgentConfigStore())
+
+			ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{tt.permissions})
+
+			err := s.CheckInTelegrafAgent(ctx, &influxdb.TelegrafAgent{ConfigID: 1, Hostname: "web1"})
+			if tt.wantErr && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+				t.Fatalf("expected the check in to be unauthorized, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTelegrafAgentService_FindTelegrafAgents(t *testing.T) {
+	m := mock.NewTelegrafAgentService()
+	m.FindTelegrafAgentsFn = func(ctx context.Context, filter influxdb.TelegrafAgentFilter) ([]*influxdb.TelegrafAgent, error) {
+		return []*influxdb.TelegrafAgent{
+			{ConfigID: 1, OrgID: 10, Hostname: "web1"},
+			{ConfigID: 2, OrgID: 10, Hostname: "web2"},
+		}, nil
+	}
+	s := authorizer.NewTelegrafAgentService(m, newTelegrafAgentConfigStore())
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action:   "read",
+			Resource: influxdb.Resource{Type: influxdb.TelegrafsResourceType, ID: influxdbtesting.IDPtr(2), OrgID: influxdbtesting.IDPtr(10)},
+		},
+	}})
+
+	as, err := s.FindTelegrafAgents(ctx, influxdb.TelegrafAgentFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as) != 1 || as[0].Hostname != "web2" {
+		t.Fatalf("expected the agents of the readable config only, got %+v", as)
+	}
+}
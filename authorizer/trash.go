@@ -0,0 +1,92 @@
+package authorizer
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.TrashService = (*TrashService)(nil)
+
+// TrashService wraps a influxdb.TrashService and authorizes actions
+// against it appropriately.
+type TrashService struct {
+	s influxdb.TrashService
+}
+
+// NewTrashService constructs an instance of an authorizing trash service.
+func NewTrashService(s influxdb.TrashService) *TrashService {
+	return &TrashService{
+		s: s,
+	}
+}
+
+// FindTrashedResource checks to see if the authorizer on context has read access to the trashed resource.
+func (s *TrashService) FindTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) (*influxdb.TrashedResource, error) {
+	r, err := s.s.FindTrashedResource(ctx, typ, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeReadURM(ctx, r.Type, r.OrgID, r.ID); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FindTrashedResources retrieves all trashed resources that match the provided filter and then filters the list down to only the resources that are authorized.
+func (s *TrashService) FindTrashedResources(ctx context.Context, filter influxdb.TrashFilter, opt ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+	rs, _, err := s.s.FindTrashedResources(ctx, filter, unpaged(opt)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// This filters without allocating
+	// https://github.com/golang/go/wiki/SliceTricks#filtering-without-allocating
+	resources := rs[:0]
+	for _, r := range rs {
+		err := authorizeReadURM(ctx, r.Type, r.OrgID, r.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+			return nil, 0, err
+		}
+
+		if influxdb.ErrorCode(err) == influxdb.EUnauthorized {
+			continue
+		}
+
+		resources = append(resources, r)
+	}
+
+	n := len(resources)
+	lo, hi := page(opt, n)
+	return resources[lo:hi], n, nil
+}
+
+// RestoreTrashedResource checks to see if the authorizer on context has write access to the trashed resource.
+func (s *TrashService) RestoreTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) error {
+	r, err := s.s.FindTrashedResource(ctx, typ, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteURM(ctx, r.Type, r.OrgID, r.ID); err != nil {
+		return err
+	}
+
+	return s.s.RestoreTrashedResource(ctx, typ, id)
+}
+
+// PurgeTrashedResource checks to see if the authorizer on context has write access to the trashed resource.
+func (s *TrashService) PurgeTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) error {
+	r, err := s.s.FindTrashedResource(ctx, typ, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteURM(ctx, r.Type, r.OrgID, r.ID); err != nil {
+		return err
+	}
+
+	return s.s.PurgeTrashedResource(ctx, typ, id)
+}
@@ -0,0 +1,72 @@
+package authorizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	influxdbcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestTrashService(t *testing.T) {
+	var restored []influxdb.ID
+	m := mock.NewTrashService()
+	m.FindTrashedResourceFn = func(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) (*influxdb.TrashedResource, error) {
+		return &influxdb.TrashedResource{Type: typ, ID: id, OrgID: 10}, nil
+	}
+	m.FindTrashedResourcesFn = func(ctx context.Context, filter influxdb.TrashFilter, opts ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+		return []*influxdb.TrashedResource{
+			{Type: influxdb.BucketsResourceType, ID: 1, OrgID: 10},
+			{Type: influxdb.DashboardsResourceType, ID: 2, OrgID: 10},
+			{Type: influxdb.BucketsResourceType, ID: 3, OrgID: 11},
+			{Type: influxdb.BucketsResourceType, ID: 4, OrgID: 10},
+		}, 4, nil
+	}
+	m.RestoreTrashedResourceFn = func(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) error {
+		restored = append(restored, id)
+		return nil
+	}
+	s := authorizer.NewTrashService(m)
+
+	ctx := influxdbcontext.SetAuthorizer(context.Background(), &Authorizer{[]influxdb.Permission{
+		{
+			Action: "read",
+			Resource: influxdb.Resource{
+				Type:  influxdb.BucketsResourceType,
+				OrgID: influxdbtesting.IDPtr(10),
+			},
+		},
+		{
+			Action: "write",
+			Resource: influxdb.Resource{
+				Type: influxdb.BucketsResourceType,
+				ID:   influxdbtesting.IDPtr(1),
+			},
+		},
+	}})
+
+	rs, n, err := s.FindTrashedResources(ctx, influxdb.TrashFilter{}, influxdb.FindOptions{Offset: 1, Limit: 1})
+	influxdbtesting.ErrorsEqual(t, err, nil)
+
+	want := []*influxdb.TrashedResource{{Type: influxdb.BucketsResourceType, ID: 4, OrgID: 10}}
+	if diff := cmp.Diff(rs, want); diff != "" {
+		t.Errorf("trashed resources are different -got/+want\ndiff %s", diff)
+	}
+	if n != 2 {
+		t.Errorf("got %d trashed resources, want 2", n)
+	}
+
+	// Restoring requires write access to the resource.
+	influxdbtesting.ErrorsEqual(t, s.RestoreTrashedResource(ctx, influxdb.BucketsResourceType, 1), nil)
+	err = s.RestoreTrashedResource(ctx, influxdb.BucketsResourceType, 4)
+	if influxdb.ErrorCode(err) != influxdb.EUnauthorized {
+		t.Errorf("expected restoring without write access to be unauthorized, got %v", err)
+	}
+	if len(restored) != 1 || restored[0] != 1 {
+		t.Errorf("got restored resources %v, want [1]", restored)
+	}
+}
@@ -85,9 +85,35 @@ func (s *URMService) CreateUserResourceMapping(ctx context.Context, m *influxdb.
 		return err
 	}
 
+	if err := authorizeTeamURM(ctx, s.orgService, m, orgID); err != nil {
+		return err
+	}
+
 	return s.s.CreateUserResourceMapping(ctx, m)
 }
 
+// authorizeTeamURM checks that the team of a team mapping belongs to the
+// organization of the resource, and that the authorizer on context can read it.
+func authorizeTeamURM(ctx context.Context, orgService OrganizationService, m *influxdb.UserResourceMapping, orgID influxdb.ID) error {
+	if m.MappingType != influxdb.TeamMappingType {
+		return nil
+	}
+
+	teamOrgID, err := orgService.FindResourceOrganizationID(ctx, influxdb.TeamsResourceType, m.UserID)
+	if err != nil {
+		return err
+	}
+
+	if teamOrgID != orgID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "a team can only be mapped to resources of its organization",
+		}
+	}
+
+	return authorizeReadTeam(ctx, teamOrgID, m.UserID)
+}
+
 func (s *URMService) DeleteUserResourceMapping(ctx context.Context, resourceID influxdb.ID, userID influxdb.ID) error {
 	f := influxdb.UserResourceMappingFilter{ResourceID: resourceID, UserID: userID}
 	urms, _, err := s.s.FindUserResourceMappings(ctx, f)
@@ -112,3 +138,55 @@ func (s *URMService) DeleteUserResourceMapping(ctx context.Context, resourceID i
 
 	return nil
 }
+
+// URMBatchService wraps a influxdb.UserResourceMappingBatchService and authorizes actions
+// against it appropriately.
+type URMBatchService struct {
+	s          influxdb.UserResourceMappingBatchService
+	orgService OrganizationService
+}
+
+// NewURMBatchService constructs an instance of an authorizing user resource mapping batch service.
+func NewURMBatchService(orgSvc OrganizationService, s influxdb.UserResourceMappingBatchService) *URMBatchService {
+	return &URMBatchService{
+		s:          s,
+		orgService: orgSvc,
+	}
+}
+
+// ApplyUserResourceMappings checks that every op is allowed before applying any.
+func (s *URMBatchService) ApplyUserResourceMappings(ctx context.Context, ops []influxdb.UserResourceMappingOp) ([]influxdb.UserResourceMappingResult, error) {
+	rs := make([]influxdb.UserResourceMappingResult, len(ops))
+	for i, op := range ops {
+		rs[i] = influxdb.UserResourceMappingResult{
+			UserResourceMappingOp: op,
+			Err:                   s.authorizeOp(ctx, op),
+		}
+	}
+	if err := influxdb.ErrMappingOpsFailed(rs); err != nil {
+		return rs, err
+	}
+
+	return s.s.ApplyUserResourceMappings(ctx, ops)
+}
+
+func (s *URMBatchService) authorizeOp(ctx context.Context, op influxdb.UserResourceMappingOp) error {
+	if err := op.Validate(); err != nil {
+		return err
+	}
+
+	orgID, err := s.orgService.FindResourceOrganizationID(ctx, op.ResourceType, op.ResourceID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeWriteURM(ctx, op.ResourceType, orgID, op.ResourceID); err != nil {
+		return err
+	}
+
+	if op.Action == influxdb.AddMapping {
+		return authorizeTeamURM(ctx, s.orgService, &op.UserResourceMapping, orgID)
+	}
+
+	return nil
+}
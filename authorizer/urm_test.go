@@ -256,3 +256,112 @@ func TestURMService_WriteUserResourceMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestURMBatchService_ApplyUserResourceMappings(t *testing.T) {
+	ops := []influxdb.UserResourceMappingOp{
+		{
+			Action: influxdb.AddMapping,
+			UserResourceMapping: influxdb.UserResourceMapping{
+				ResourceID:   1,
+				ResourceType: influxdb.BucketsResourceType,
+				UserID:       100,
+				UserType:     influxdb.Member,
+			},
+		},
+		{
+			Action: influxdb.RemoveMapping,
+			UserResourceMapping: influxdb.UserResourceMapping{
+				ResourceID:   2,
+				ResourceType: influxdb.BucketsResourceType,
+				UserID:       100,
+			},
+		},
+	}
+
+	type args struct {
+		permissions []influxdb.Permission
+	}
+	type wants struct {
+		err     error
+		errs    []error
+		applied bool
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "authorized to write urms",
+			args: args{
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type:  influxdb.BucketsResourceType,
+							OrgID: influxdbtesting.IDPtr(10),
+						},
+					},
+				},
+			},
+			wants: wants{
+				errs:    []error{nil, nil},
+				applied: true,
+			},
+		},
+		{
+			name: "unauthorized to write one of the urms",
+			args: args{
+				permissions: []influxdb.Permission{
+					{
+						Action: "write",
+						Resource: influxdb.Resource{
+							Type:  influxdb.BucketsResourceType,
+							OrgID: influxdbtesting.IDPtr(10),
+							ID:    influxdbtesting.IDPtr(1),
+						},
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Msg:  "1 of 2 mapping operations failed; no mappings were changed",
+					Code: influxdb.EUnauthorized,
+				},
+				errs: []error{
+					nil,
+					&influxdb.Error{
+						Msg:  "write:orgs/000000000000000a/buckets/0000000000000002 is unauthorized",
+						Code: influxdb.EUnauthorized,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied bool
+			urms := mock.NewUserResourceMappingService()
+			next := urms.ApplyMappingsFn
+			urms.ApplyMappingsFn = func(ctx context.Context, ops []influxdb.UserResourceMappingOp) ([]influxdb.UserResourceMappingResult, error) {
+				applied = true
+				return next(ctx, ops)
+			}
+			s := authorizer.NewURMBatchService(&OrgService{OrgID: 10}, urms)
+
+			ctx := context.Background()
+			ctx = influxdbcontext.SetAuthorizer(ctx, &Authorizer{tt.args.permissions})
+
+			rs, err := s.ApplyUserResourceMappings(ctx, ops)
+			influxdbtesting.ErrorsEqual(t, err, tt.wants.err)
+			if applied != tt.wants.applied {
+				t.Errorf("expected applied to be %v", tt.wants.applied)
+			}
+			for i, r := range rs {
+				influxdbtesting.ErrorsEqual(t, r.Err, tt.wants.errs[i])
+			}
+		})
+	}
+}
@@ -86,7 +86,7 @@ func (s *UserService) FindUser(ctx context.Context, filter influxdb.UserFilter)
 func (s *UserService) FindUsers(ctx context.Context, filter influxdb.UserFilter, opt ...influxdb.FindOptions) ([]*influxdb.User, int, error) {
 	// TODO: we'll likely want to push this operation into the database eventually since fetching the whole list of data
 	// will likely be expensive.
-	os, _, err := s.s.FindUsers(ctx, filter, opt...)
+	os, _, err := s.s.FindUsers(ctx, filter, unpaged(opt)...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -107,6 +107,9 @@ func (s *UserService) FindUsers(ctx context.Context, filter influxdb.UserFilter,
 		users = append(users, o)
 	}
 
+	lo, hi := page(opt, len(users))
+	users = users[lo:hi]
+
 	return users, len(users), nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 )
 
 var (
@@ -122,38 +123,66 @@ const (
 	// ViewsResourceType gives permission to one or more views.
 	ViewsResourceType     = ResourceType("views")     // 12
 	DocumentsResourceType = ResourceType("documents") // 13
+	// TeamsResourceType gives permission to one or more teams.
+	TeamsResourceType = ResourceType("teams") // 14
+	// NotificationEndpointsResourceType gives permission to one or more notification endpoints.
+	NotificationEndpointsResourceType = ResourceType("notificationEndpoints") // 15
+	// ChecksResourceType gives permission to one or more checks.
+	ChecksResourceType = ResourceType("checks") // 16
+	// NotificationRulesResourceType gives permission to one or more notification rules.
+	NotificationRulesResourceType = ResourceType("notificationRules") // 17
+	// SilencesResourceType gives permission to one or more silences.
+	SilencesResourceType = ResourceType("silences") // 18
+	// AnnotationsResourceType gives permission to one or more annotations.
+	AnnotationsResourceType = ResourceType("annotations") // 19
+	// StacksResourceType gives permission to one or more stacks.
+	StacksResourceType = ResourceType("stacks") // 20
 )
 
 // AllResourceTypes is the list of all known resource types.
 var AllResourceTypes = []ResourceType{
-	AuthorizationsResourceType, // 0
-	BucketsResourceType,        // 1
-	DashboardsResourceType,     // 2
-	OrgsResourceType,           // 3
-	SourcesResourceType,        // 4
-	TasksResourceType,          // 5
-	TelegrafsResourceType,      // 6
-	UsersResourceType,          // 7
-	VariablesResourceType,      // 8
-	ScraperResourceType,        // 9
-	SecretsResourceType,        // 10
-	LabelsResourceType,         // 11
-	ViewsResourceType,          // 12
-	DocumentsResourceType,      // 13
+	AuthorizationsResourceType,        // 0
+	BucketsResourceType,               // 1
+	DashboardsResourceType,            // 2
+	OrgsResourceType,                  // 3
+	SourcesResourceType,               // 4
+	TasksResourceType,                 // 5
+	TelegrafsResourceType,             // 6
+	UsersResourceType,                 // 7
+	VariablesResourceType,             // 8
+	ScraperResourceType,               // 9
+	SecretsResourceType,               // 10
+	LabelsResourceType,                // 11
+	ViewsResourceType,                 // 12
+	DocumentsResourceType,             // 13
+	TeamsResourceType,                 // 14
+	NotificationEndpointsResourceType, // 15
+	ChecksResourceType,                // 16
+	NotificationRulesResourceType,     // 17
+	SilencesResourceType,              // 18
+	AnnotationsResourceType,           // 19
+	StacksResourceType,                // 20
 	// NOTE: when modifying this list, please update the swagger for components.schemas.Permission resource enum.
 }
 
 // OrgResourceTypes is the list of all known resource types that belong to an organization.
 var OrgResourceTypes = []ResourceType{
-	BucketsResourceType,    // 1
-	DashboardsResourceType, // 2
-	SourcesResourceType,    // 4
-	TasksResourceType,      // 5
-	TelegrafsResourceType,  // 6
-	UsersResourceType,      // 7
-	VariablesResourceType,  // 8
-	SecretsResourceType,    // 10
-	DocumentsResourceType,  //13
+	BucketsResourceType,               // 1
+	DashboardsResourceType,            // 2
+	SourcesResourceType,               // 4
+	TasksResourceType,                 // 5
+	TelegrafsResourceType,             // 6
+	UsersResourceType,                 // 7
+	VariablesResourceType,             // 8
+	SecretsResourceType,               // 10
+	DocumentsResourceType,             //13
+	TeamsResourceType,                 // 14
+	NotificationEndpointsResourceType, // 15
+	ChecksResourceType,                // 16
+	NotificationRulesResourceType,     // 17
+	SilencesResourceType,              // 18
+	AnnotationsResourceType,           // 19
+	StacksResourceType,                // 20
 }
 
 // Valid checks if the resource type is a member of the ResourceType enum.
@@ -178,6 +207,13 @@ func (t ResourceType) Valid() (err error) {
 	case LabelsResourceType: // 11
 	case ViewsResourceType: // 12
 	case DocumentsResourceType: // 13
+	case TeamsResourceType: // 14
+	case NotificationEndpointsResourceType: // 15
+	case ChecksResourceType: // 16
+	case NotificationRulesResourceType: // 17
+	case SilencesResourceType: // 18
+	case AnnotationsResourceType: // 19
+	case StacksResourceType: // 20
 	default:
 		err = ErrInvalidResourceType
 	}
@@ -189,6 +225,10 @@ func (t ResourceType) Valid() (err error) {
 type Permission struct {
 	Action   Action   `json:"action"`
 	Resource Resource `json:"resource"`
+	// Measurements restricts a permission to write to buckets to writing these
+	// measurements. A restricted permission only matches permissions to write
+	// some of its measurements, so it does not allow changing the buckets.
+	Measurements []string `json:"measurements,omitempty"`
 }
 
 // Matches returns whether or not one permission matches the other.
@@ -201,6 +241,10 @@ func (p Permission) Matches(perm Permission) bool {
 		return false
 	}
 
+	if len(p.Measurements) > 0 && !containsAll(p.Measurements, perm.Measurements) {
+		return false
+	}
+
 	if p.Resource.OrgID == nil && p.Resource.ID == nil {
 		return true
 	}
@@ -228,7 +272,30 @@ func (p Permission) Matches(perm Permission) bool {
 	return false
 }
 
+// containsAll returns whether ss is not empty and every string of it is in set.
+func containsAll(set, ss []string) bool {
+	if len(ss) == 0 {
+		return false
+	}
+	for _, s := range ss {
+		found := false
+		for _, t := range set {
+			if s == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (p Permission) String() string {
+	if len(p.Measurements) > 0 {
+		return fmt.Sprintf("%s:%s[%s]", p.Action, p.Resource, strings.Join(p.Measurements, ","))
+	}
 	return fmt.Sprintf("%s:%s", p.Action, p.Resource)
 }
 
@@ -266,6 +333,22 @@ func (p *Permission) Valid() error {
 		}
 	}
 
+	if len(p.Measurements) > 0 && (p.Action != WriteAction || p.Resource.Type != BucketsResourceType) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "measurements are only allowed for permissions to write to buckets",
+		}
+	}
+
+	for _, m := range p.Measurements {
+		if m == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "measurements of permission must not be empty",
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -307,6 +390,41 @@ func NewPermissionAtID(id ID, a Action, rt ResourceType, orgID ID) (*Permission,
 	return p, p.Valid()
 }
 
+// WriteMeasurements returns the measurements that the authorizer may write to
+// the bucket. It returns all as true if the authorizer may write any measurement
+// to the bucket, and no measurements if it may write none.
+func WriteMeasurements(a Authorizer, orgID, bucketID ID) (measurements []string, all bool) {
+	p := Permission{
+		Action: WriteAction,
+		Resource: Resource{
+			Type:  BucketsResourceType,
+			OrgID: &orgID,
+			ID:    &bucketID,
+		},
+	}
+	if a.Allowed(p) {
+		return nil, true
+	}
+
+	var ps []Permission
+	switch a := a.(type) {
+	case *Authorization:
+		ps = a.Permissions
+	case *Session:
+		ps = a.Permissions
+	}
+
+	for _, granted := range ps {
+		for _, m := range granted.Measurements {
+			p.Measurements = []string{m}
+			if a.Allowed(p) {
+				measurements = append(measurements, m)
+			}
+		}
+	}
+	return measurements, false
+}
+
 // OperPermissions are the default permissions for those who setup the application.
 func OperPermissions() []Permission {
 	ps := []Permission{}
@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"reflect"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
@@ -229,6 +230,100 @@ func TestAuthorizer_PermissionAllowed(t *testing.T) {
 			},
 			allowed: false,
 		},
+		{
+			name: "unrestricted permission allows writing measurements",
+			permission: platform.Permission{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+				Measurements: []string{"cpu"},
+			},
+			permissions: []platform.Permission{
+				{
+					Action: platform.WriteAction,
+					Resource: platform.Resource{
+						Type:  platform.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(1),
+						ID:    influxdbtesting.IDPtr(1),
+					},
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "restricted permission allows writing its measurements",
+			permission: platform.Permission{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+				Measurements: []string{"cpu", "mem"},
+			},
+			permissions: []platform.Permission{
+				{
+					Action: platform.WriteAction,
+					Resource: platform.Resource{
+						Type:  platform.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(1),
+						ID:    influxdbtesting.IDPtr(1),
+					},
+					Measurements: []string{"mem", "cpu"},
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "restricted permission disallows writing other measurements",
+			permission: platform.Permission{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+				Measurements: []string{"cpu", "disk"},
+			},
+			permissions: []platform.Permission{
+				{
+					Action: platform.WriteAction,
+					Resource: platform.Resource{
+						Type:  platform.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(1),
+						ID:    influxdbtesting.IDPtr(1),
+					},
+					Measurements: []string{"cpu", "mem"},
+				},
+			},
+			allowed: false,
+		},
+		{
+			name: "restricted permission disallows unrestricted permission",
+			permission: platform.Permission{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+					ID:    influxdbtesting.IDPtr(1),
+				},
+			},
+			permissions: []platform.Permission{
+				{
+					Action: platform.WriteAction,
+					Resource: platform.Resource{
+						Type:  platform.BucketsResourceType,
+						OrgID: influxdbtesting.IDPtr(1),
+						ID:    influxdbtesting.IDPtr(1),
+					},
+					Measurements: []string{"cpu"},
+				},
+			},
+			allowed: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -243,8 +338,9 @@ func TestAuthorizer_PermissionAllowed(t *testing.T) {
 
 func TestPermission_Valid(t *testing.T) {
 	type fields struct {
-		Action   platform.Action
-		Resource platform.Resource
+		Action       platform.Action
+		Resource     platform.Resource
+		Measurements []string
 	}
 	tests := []struct {
 		name    string
@@ -302,12 +398,60 @@ func TestPermission_Valid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid bucket write permission with measurements",
+			fields: fields{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Measurements: []string{"cpu"},
+			},
+		},
+		{
+			name: "invalid bucket read permission with measurements",
+			fields: fields{
+				Action: platform.ReadAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Measurements: []string{"cpu"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid task permission with measurements",
+			fields: fields{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.TasksResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Measurements: []string{"cpu"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid permission with an empty measurement",
+			fields: fields{
+				Action: platform.WriteAction,
+				Resource: platform.Resource{
+					Type:  platform.BucketsResourceType,
+					OrgID: influxdbtesting.IDPtr(1),
+				},
+				Measurements: []string{""},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &platform.Permission{
-				Action:   tt.fields.Action,
-				Resource: tt.fields.Resource,
+				Action:       tt.fields.Action,
+				Resource:     tt.fields.Resource,
+				Measurements: tt.fields.Measurements,
 			}
 			if err := p.Valid(); (err != nil) != tt.wantErr {
 				t.Errorf("Permission.Valid() error = %v, wantErr %v", err, tt.wantErr)
@@ -316,6 +460,57 @@ func TestPermission_Valid(t *testing.T) {
 	}
 }
 
+func TestWriteMeasurements(t *testing.T) {
+	bucketWrite := func(orgID, bucketID *platform.ID, measurements ...string) platform.Permission {
+		return platform.Permission{
+			Action:       platform.WriteAction,
+			Resource:     platform.Resource{Type: platform.BucketsResourceType, OrgID: orgID, ID: bucketID},
+			Measurements: measurements,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		authorizer       platform.Authorizer
+		wantMeasurements []string
+		wantAll          bool
+	}{
+		{
+			name:       "unrestricted",
+			authorizer: &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{bucketWrite(influxdbtesting.IDPtr(1), nil)}},
+			wantAll:    true,
+		},
+		{
+			name: "restricted",
+			authorizer: &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{
+				bucketWrite(influxdbtesting.IDPtr(1), influxdbtesting.IDPtr(2), "cpu", "mem"),
+				bucketWrite(influxdbtesting.IDPtr(1), influxdbtesting.IDPtr(3), "disk"),
+			}},
+			wantMeasurements: []string{"cpu", "mem"},
+		},
+		{
+			name: "inactive",
+			authorizer: &platform.Authorization{Status: platform.Inactive, Permissions: []platform.Permission{
+				bucketWrite(influxdbtesting.IDPtr(1), influxdbtesting.IDPtr(2), "cpu"),
+			}},
+		},
+		{
+			name: "read only",
+			authorizer: &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			measurements, all := platform.WriteMeasurements(tt.authorizer, 1, 2)
+			if all != tt.wantAll || !reflect.DeepEqual(measurements, tt.wantMeasurements) {
+				t.Errorf("got measurements %v and all %v, want %v and %v", measurements, all, tt.wantMeasurements, tt.wantAll)
+			}
+		})
+	}
+}
+
 func TestPermissionAllResources_Valid(t *testing.T) {
 	var resources = []platform.ResourceType{
 		platform.UsersResourceType,
@@ -0,0 +1,207 @@
+package awssecrets
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.SecretService = (*SecretService)(nil)
+
+// DefaultPrefix is the prefix of the names of the secrets that hold the
+// secrets of organizations.
+const DefaultPrefix = "influxdb/"
+
+// SecretService is service for storing user secrets in AWS Secrets Manager.
+type SecretService struct {
+	Client secretsmanageriface.SecretsManagerAPI
+	// Prefix is prepended to the ID of an organization to name the secret
+	// that holds the secrets of the organization.
+	Prefix string
+}
+
+// NewSecretService creates an instance of a SecretService.
+// The service is configured using the standard AWS environment variables and
+// shared configuration files.
+// https://docs.aws.amazon.com/sdk-for-go/v1/developer-guide/configuring-sdk.html
+func NewSecretService() (*SecretService, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretService{
+		Client: secretsmanager.New(sess),
+		Prefix: DefaultPrefix,
+	}, nil
+}
+
+func (s *SecretService) secretName(orgID platform.ID) string {
+	return s.Prefix + orgID.String()
+}
+
+// LoadSecret retrieves the secret value v found at key k for organization orgID.
+func (s *SecretService) LoadSecret(ctx context.Context, orgID platform.ID, k string) (string, error) {
+	data, _, err := s.loadSecrets(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+
+	if v, ok := data[k]; ok {
+		return v, nil
+	}
+
+	return "", &platform.Error{
+		Code: platform.ENotFound,
+		Msg:  platform.ErrSecretNotFound,
+	}
+}
+
+// loadSecrets retrieves a map of secrets for an organization, and whether the
+// secret that holds them exists.
+func (s *SecretService) loadSecrets(ctx context.Context, orgID platform.ID) (map[string]string, bool, error) {
+	out, err := s.Client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(s.secretName(orgID)),
+	})
+	if err != nil {
+		if hasErrCode(err, secretsmanager.ErrCodeResourceNotFoundException) {
+			return map[string]string{}, false, nil
+		}
+		return nil, false, &platform.Error{
+			Code: platform.EUnavailable,
+			Msg:  "unable to read secrets from AWS Secrets Manager",
+			Err:  err,
+		}
+	}
+
+	m := map[string]string{}
+	if out.SecretString == nil {
+		return m, true, nil
+	}
+	if err := json.Unmarshal([]byte(*out.SecretString), &m); err != nil {
+		return nil, false, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  "secret value is not a JSON object of strings",
+			Err:  err,
+		}
+	}
+	return m, true, nil
+}
+
+// GetSecretKeys retrieves all secret keys that are stored for the organization orgID.
+func (s *SecretService) GetSecretKeys(ctx context.Context, orgID platform.ID) ([]string, error) {
+	data, _, err := s.loadSecrets(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return platform.SecretKeys(data), nil
+}
+
+// PutSecret stores the secret pair (k,v) for the organization orgID.
+func (s *SecretService) PutSecret(ctx context.Context, orgID platform.ID, k string, v string) error {
+	data, exists, err := s.loadSecrets(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	data[k] = v
+
+	return s.putSecrets(ctx, orgID, data, exists)
+}
+
+// putSecrets stores data as the secrets of the organization orgID. The secret
+// that holds them is created if it does not exist yet, and is otherwise given
+// a new version.
+func (s *SecretService) putSecrets(ctx context.Context, orgID platform.ID, data map[string]string, exists bool) error {
+	octets, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	value := aws.String(string(octets))
+
+	if !exists {
+		_, err = s.Client.CreateSecretWithContext(ctx, &secretsmanager.CreateSecretInput{
+			Name:         aws.String(s.secretName(orgID)),
+			SecretString: value,
+		})
+		if err == nil {
+			return nil
+		}
+		if !hasErrCode(err, secretsmanager.ErrCodeResourceExistsException) {
+			return s.putError(err)
+		}
+		// The secret was created since it was read, so it is given a new version.
+	}
+
+	_, err = s.Client.PutSecretValueWithContext(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(s.secretName(orgID)),
+		SecretString: value,
+	})
+	return s.putError(err)
+}
+
+func hasErrCode(err error, code string) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == code
+}
+
+func (s *SecretService) putError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &platform.Error{
+		Code: platform.EUnavailable,
+		Msg:  "unable to write secrets to AWS Secrets Manager",
+		Err:  err,
+	}
+}
+
+// PutSecrets puts all provided secrets and overwrites any previous values.
+func (s *SecretService) PutSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
+	_, exists, err := s.loadSecrets(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	return s.putSecrets(ctx, orgID, m, exists)
+}
+
+// PatchSecrets patches all provided secrets and updates any previous values.
+func (s *SecretService) PatchSecrets(ctx context.Context, orgID platform.ID, m map[string]string) error {
+	data, exists, err := s.loadSecrets(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range m {
+		data[k] = v
+	}
+
+	return s.putSecrets(ctx, orgID, data, exists)
+}
+
+// DeleteSecret removes a single secret from the secret store.
+func (s *SecretService) DeleteSecret(ctx context.Context, orgID platform.ID, ks ...string) error {
+	data, exists, err := s.loadSecrets(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	for _, k := range ks {
+		delete(data, k)
+	}
+
+	return s.putSecrets(ctx, orgID, data, exists)
+}
@@ -0,0 +1,85 @@
+package awssecrets_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/awssecrets"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+// fakeClient keeps the latest value of each secret in memory.
+type fakeClient struct {
+	secretsmanageriface.SecretsManagerAPI
+	values map[string]string
+}
+
+func (c *fakeClient) GetSecretValueWithContext(ctx aws.Context, in *secretsmanager.GetSecretValueInput, opts ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+	v, ok := c.values[*in.SecretId]
+	if !ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "secret not found", nil)
+	}
+	return &secretsmanager.GetSecretValueOutput{Name: in.SecretId, SecretString: aws.String(v)}, nil
+}
+
+func (c *fakeClient) CreateSecretWithContext(ctx aws.Context, in *secretsmanager.CreateSecretInput, opts ...request.Option) (*secretsmanager.CreateSecretOutput, error) {
+	if _, ok := c.values[*in.Name]; ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceExistsException, "secret exists", nil)
+	}
+	c.values[*in.Name] = *in.SecretString
+	return &secretsmanager.CreateSecretOutput{Name: in.Name}, nil
+}
+
+func (c *fakeClient) PutSecretValueWithContext(ctx aws.Context, in *secretsmanager.PutSecretValueInput, opts ...request.Option) (*secretsmanager.PutSecretValueOutput, error) {
+	if _, ok := c.values[*in.SecretId]; !ok {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "secret not found", nil)
+	}
+	c.values[*in.SecretId] = *in.SecretString
+	return &secretsmanager.PutSecretValueOutput{Name: in.SecretId}, nil
+}
+
+func initSecretService(f influxdbtesting.SecretServiceFields, t *testing.T) (influxdb.SecretService, func()) {
+	s := &awssecrets.SecretService{
+		Client: &fakeClient{values: map[string]string{}},
+		Prefix: awssecrets.DefaultPrefix,
+	}
+
+	ctx := context.Background()
+	for _, sec := range f.Secrets {
+		for k, v := range sec.Env {
+			if err := s.PutSecret(ctx, sec.OrganizationID, k, v); err != nil {
+				t.Fatalf("failed to populate secrets: %v", err)
+			}
+		}
+	}
+	return s, func() {}
+}
+
+func TestSecretService(t *testing.T) {
+	influxdbtesting.SecretService(initSecretService, t)
+}
+
+func TestSecretService_Layout(t *testing.T) {
+	c := &fakeClient{values: map[string]string{}}
+	s := &awssecrets.SecretService{Client: c, Prefix: awssecrets.DefaultPrefix}
+
+	ctx := context.Background()
+	orgID := influxdb.ID(1)
+	if err := s.PutSecrets(ctx, orgID, map[string]string{"api_key": "abc123xyz"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := c.values["influxdb/0000000000000001"], `{"api_key":"abc123xyz"}`; got != want {
+		t.Errorf("got secret value %s, want %s", got, want)
+	}
+
+	if _, err := s.LoadSecret(ctx, orgID, "missing"); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a missing secret to not be found, got %v", err)
+	}
+}
@@ -0,0 +1,336 @@
+// Package bench generates synthetic workloads of writes and queries against an
+// instance of InfluxDB and measures their latencies, for capacity testing
+// before rollouts.
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"golang.org/x/time/rate"
+)
+
+// Defaults of a workload.
+const (
+	DefaultMeasurement = "bench"
+	DefaultSeries      = 1000
+	DefaultBatchSize   = 1000
+	DefaultWriters     = 4
+	DefaultDuration    = time.Minute
+)
+
+// Config is a synthetic workload.
+type Config struct {
+	OrgID    influxdb.ID
+	BucketID influxdb.ID
+
+	// Measurement is the measurement of the points written.
+	Measurement string
+	// Series is the number of series written to, which are told apart by their series tag.
+	Series int
+	// PointsPerSecond is the write rate; zero writes as fast as the writers can.
+	PointsPerSecond int
+	// BatchSize is the number of points of a write.
+	BatchSize int
+	// Writers is the number of writes that are sent at once.
+	Writers int
+
+	// Queries are the Flux queries that are run, in turn.
+	Queries []string
+	// QueriesPerSecond is the query rate; zero runs queries as fast as the queriers can.
+	QueriesPerSecond int
+	// Queriers is the number of queries that are run at once.
+	Queriers int
+
+	// Duration is how long the workload runs for.
+	Duration time.Duration
+	// SampleInterval is how often the resource usage of the target is sampled.
+	SampleInterval time.Duration
+}
+
+// withDefaults returns c with defaults for the settings that aren't set.
+func (c Config) withDefaults() Config {
+	if c.Measurement == "" {
+		c.Measurement = DefaultMeasurement
+	}
+	if c.Series <= 0 {
+		c.Series = DefaultSeries
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultBatchSize
+	}
+	if c.Writers <= 0 {
+		c.Writers = DefaultWriters
+	}
+	if c.Queriers <= 0 {
+		c.Queriers = 1
+	}
+	if c.Duration <= 0 {
+		c.Duration = DefaultDuration
+	}
+	if c.SampleInterval <= 0 {
+		c.SampleInterval = time.Second
+	}
+	return c
+}
+
+// A Querier runs a Flux query and reads its whole response.
+type Querier interface {
+	Query(ctx context.Context, orgID influxdb.ID, query string) error
+}
+
+// A Sampler samples the resource usage of the target of a workload.
+type Sampler interface {
+	Sample(ctx context.Context) (ResourceSample, error)
+}
+
+// Target is the instance a workload runs against.
+type Target struct {
+	WriteService influxdb.WriteService
+	// Querier runs the queries of the workload; if it is nil, no queries are run.
+	Querier Querier
+	// Sampler samples the resource usage of the target; if it is nil, it isn't reported.
+	Sampler Sampler
+}
+
+// Report is the outcome of a workload.
+type Report struct {
+	// Duration is how long the workload ran for.
+	Duration time.Duration
+
+	Writes Latencies
+	// Points is the number of points written successfully.
+	Points int64
+	// PointsPerSecond is the rate at which points were written successfully.
+	PointsPerSecond float64
+
+	Queries Latencies
+
+	// Resources is the resource usage of the target, if it was sampled.
+	Resources *ResourceUsage
+	// Errors are the first errors of writes and queries.
+	Errors []string
+}
+
+// reportMaxErrors is the most errors kept in a report.
+const reportMaxErrors = 10
+
+// Run runs the workload c against t until its duration passes or ctx is done.
+func Run(ctx context.Context, c Config, t Target) (*Report, error) {
+	c = c.withDefaults()
+	if !c.OrgID.Valid() || !c.BucketID.Valid() {
+		return nil, fmt.Errorf("workload requires an organization and a bucket")
+	}
+	if t.WriteService == nil {
+		return nil, fmt.Errorf("workload requires a write service")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.Duration)
+	defer cancel()
+
+	r := &run{config: c, target: t}
+	if t.Sampler != nil {
+		r.resources = &ResourceUsage{}
+		// The first sample fails the run, as a target that can't be sampled
+		// isn't going to be sampled later on.
+		if err := r.sample(ctx); err != nil {
+			return nil, fmt.Errorf("failed to sample resource usage of target: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	writes := limiter(c.PointsPerSecond, c.BatchSize)
+	for i := 0; i < c.Writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.write(ctx, writes, i)
+		}(i)
+	}
+
+	if t.Querier != nil && len(c.Queries) > 0 {
+		queries := limiter(c.QueriesPerSecond, 1)
+		for i := 0; i < c.Queriers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				r.query(ctx, queries, i)
+			}(i)
+		}
+	}
+
+	if t.Sampler != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(c.SampleInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := r.sample(ctx); err != nil && ctx.Err() == nil {
+						r.error(err)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return r.report(time.Since(start)), nil
+}
+
+// limiter returns a limiter of events to perSecond, which are taken n at a time,
+// or nil if they aren't limited.
+func limiter(perSecond, n int) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	burst := perSecond
+	if burst < n {
+		burst = n
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), burst)
+}
+
+// run is the state of a running workload.
+type run struct {
+	config Config
+	target Target
+
+	mu        sync.Mutex
+	writes    []time.Duration
+	writeErrs int
+	points    int64
+	queries   []time.Duration
+	queryErrs int
+	resources *ResourceUsage
+	errs      []string
+}
+
+// write sends batches of points until ctx is done. Writer i starts at its own
+// series, so that the writers spread over the series.
+func (r *run) write(ctx context.Context, l *rate.Limiter, i int) {
+	c := r.config
+	series := i * c.Series / c.Writers
+	rnd := rand.New(rand.NewSource(int64(i)))
+	var buf bytes.Buffer
+	for {
+		if l != nil {
+			if err := l.WaitN(ctx, c.BatchSize); err != nil {
+				return
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		buf.Reset()
+		now := time.Now().UnixNano()
+		for j := 0; j < c.BatchSize; j++ {
+			series = (series + 1) % c.Series
+			// Points of a series in the same batch get distinct timestamps.
+			fmt.Fprintf(&buf, "%s,series=s%d value=%s %d\n", c.Measurement, series,
+				strconv.FormatFloat(rnd.Float64()*100, 'f', -1, 64), now+int64(j))
+		}
+
+		start := time.Now()
+		err := r.target.WriteService.Write(ctx, c.OrgID, c.BucketID, &buf)
+		d := time.Since(start)
+		if ctx.Err() != nil {
+			// Writes that are cut short by the end of the workload aren't measured.
+			return
+		}
+
+		r.mu.Lock()
+		r.writes = append(r.writes, d)
+		if err != nil {
+			r.writeErrs++
+		} else {
+			r.points += int64(c.BatchSize)
+		}
+		r.mu.Unlock()
+		if err != nil {
+			r.error(fmt.Errorf("write: %v", err))
+		}
+	}
+}
+
+// query runs the queries of the workload in turn until ctx is done.
+func (r *run) query(ctx context.Context, l *rate.Limiter, i int) {
+	c := r.config
+	for n := i; ; n++ {
+		if l != nil {
+			if err := l.Wait(ctx); err != nil {
+				return
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		start := time.Now()
+		err := r.target.Querier.Query(ctx, c.OrgID, c.Queries[n%len(c.Queries)])
+		d := time.Since(start)
+		if ctx.Err() != nil {
+			return
+		}
+
+		r.mu.Lock()
+		r.queries = append(r.queries, d)
+		if err != nil {
+			r.queryErrs++
+		}
+		r.mu.Unlock()
+		if err != nil {
+			r.error(fmt.Errorf("query: %v", err))
+		}
+	}
+}
+
+// sample adds a sample of the resource usage of the target.
+func (r *run) sample(ctx context.Context) error {
+	s, err := r.target.Sampler.Sample(ctx)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.resources.add(s)
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *run) error(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.errs) < reportMaxErrors {
+		r.errs = append(r.errs, err.Error())
+	}
+}
+
+func (r *run) report(d time.Duration) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep := &Report{
+		Duration:  d,
+		Writes:    newLatencies(r.writes, r.writeErrs),
+		Points:    r.points,
+		Queries:   newLatencies(r.queries, r.queryErrs),
+		Resources: r.resources,
+		Errors:    r.errs,
+	}
+	if d > 0 {
+		rep.PointsPerSecond = float64(r.points) / d.Seconds()
+	}
+	return rep
+}
@@ -0,0 +1,146 @@
+package bench_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bench"
+)
+
+type writeService struct {
+	mu     sync.Mutex
+	series map[string]bool
+	lines  int
+}
+
+func (s *writeService) Write(ctx context.Context, orgID, bucketID influxdb.ID, r io.Reader) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		s.lines++
+		s.series[string(bytes.SplitN(line, []byte(" "), 2)[0])] = true
+	}
+	return nil
+}
+
+type querier struct {
+	mu      sync.Mutex
+	queries map[string]int
+}
+
+func (q *querier) Query(ctx context.Context, orgID influxdb.ID, query string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queries[query]++
+	if query == "fail" {
+		return errors.New("query failed")
+	}
+	return nil
+}
+
+type sampler struct {
+	n int
+}
+
+func (s *sampler) Sample(ctx context.Context) (bench.ResourceSample, error) {
+	s.n++
+	return bench.ResourceSample{CPUSeconds: float64(s.n), ResidentMemoryBytes: float64(100 * (s.n % 3)), Goroutines: 10}, nil
+}
+
+func TestRun(t *testing.T) {
+	ws := &writeService{series: make(map[string]bool)}
+	q := &querier{queries: make(map[string]int)}
+	c := bench.Config{
+		OrgID:            1,
+		BucketID:         2,
+		Series:           50,
+		PointsPerSecond:  2000,
+		BatchSize:        100,
+		Writers:          2,
+		Queries:          []string{"ok", "fail"},
+		QueriesPerSecond: 20,
+		Duration:         500 * time.Millisecond,
+		SampleInterval:   100 * time.Millisecond,
+	}
+
+	rep, err := bench.Run(context.Background(), c, bench.Target{WriteService: ws, Querier: q, Sampler: &sampler{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The limiter allows a burst of a second's worth of points, and then the rate.
+	if rep.Points < 1000 || rep.Points > 3200 {
+		t.Errorf("got %d points written, want about 2000", rep.Points)
+	}
+	if int64(ws.lines) != rep.Points || rep.Writes.Count != ws.lines/c.BatchSize || rep.Writes.Errors != 0 {
+		t.Errorf("got %d points in %d writes, but %d lines were written", rep.Points, rep.Writes.Count, ws.lines)
+	}
+	if len(ws.series) != c.Series {
+		t.Errorf("got %d series written, want %d", len(ws.series), c.Series)
+	}
+
+	if q.queries["ok"] == 0 || q.queries["fail"] == 0 {
+		t.Errorf("expected every query to run, got %v", q.queries)
+	}
+	if rep.Queries.Count != q.queries["ok"]+q.queries["fail"] || rep.Queries.Errors != q.queries["fail"] {
+		t.Errorf("got %d queries and %d errors, want %v", rep.Queries.Count, rep.Queries.Errors, q.queries)
+	}
+	if len(rep.Errors) == 0 || rep.Errors[0] != "query: query failed" {
+		t.Errorf("unexpected errors: %v", rep.Errors)
+	}
+
+	u := rep.Resources
+	if u == nil || u.Samples < 3 {
+		t.Fatalf("expected the resource usage to be sampled, got %+v", u)
+	}
+	if u.CPUSeconds != float64(u.Samples-1) || u.MaxResidentMemoryBytes != 200 || u.MaxGoroutines != 10 {
+		t.Errorf("unexpected resource usage: %+v", u)
+	}
+}
+
+func TestRun_invalid(t *testing.T) {
+	if _, err := bench.Run(context.Background(), bench.Config{}, bench.Target{WriteService: &writeService{}}); err == nil {
+		t.Error("expected a workload without a bucket to fail")
+	}
+}
+
+func TestMetricsSampler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `# TYPE process_cpu_seconds_total counter
+process_cpu_seconds_total 12.5
+# TYPE process_resident_memory_bytes gauge
+process_resident_memory_bytes 1.048576e+06
+# TYPE go_goroutines gauge
+go_goroutines 42
+`)
+	}))
+	defer srv.Close()
+
+	s := &bench.MetricsSampler{Addr: srv.URL}
+	got, err := s.Sample(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := bench.ResourceSample{CPUSeconds: 12.5, ResidentMemoryBytes: 1048576, Goroutines: 42}
+	if got != want {
+		t.Errorf("got sample %+v, want %+v", got, want)
+	}
+}
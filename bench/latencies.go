@@ -0,0 +1,53 @@
+package bench
+
+import (
+	"sort"
+	"time"
+)
+
+// Latencies are the latencies of the requests of a kind, such as writes.
+type Latencies struct {
+	// Count is the number of requests, including the failed ones.
+	Count  int
+	Errors int
+
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// newLatencies returns the latencies of the requests that took ds, errs of which failed.
+func newLatencies(ds []time.Duration, errs int) Latencies {
+	l := Latencies{Count: len(ds), Errors: errs}
+	if len(ds) == 0 {
+		return l
+	}
+
+	sorted := make([]time.Duration, len(ds))
+	copy(sorted, ds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	l.Min = sorted[0]
+	l.Mean = sum / time.Duration(len(sorted))
+	l.P50 = percentile(sorted, 50)
+	l.P90 = percentile(sorted, 90)
+	l.P99 = percentile(sorted, 99)
+	l.Max = sorted[len(sorted)-1]
+	return l
+}
+
+// percentile returns the p-th percentile of sorted by the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,38 @@
+package bench
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLatencies(t *testing.T) {
+	var ds []time.Duration
+	for i := 100; i >= 1; i-- {
+		ds = append(ds, time.Duration(i)*time.Millisecond)
+	}
+
+	got := newLatencies(ds, 3)
+	want := Latencies{
+		Count:  100,
+		Errors: 3,
+		Min:    time.Millisecond,
+		Mean:   50500 * time.Microsecond,
+		P50:    50 * time.Millisecond,
+		P90:    90 * time.Millisecond,
+		P99:    99 * time.Millisecond,
+		Max:    100 * time.Millisecond,
+	}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if ds[0] != 100*time.Millisecond {
+		t.Error("expected the durations not to be sorted in place")
+	}
+
+	if got := newLatencies(nil, 0); got != (Latencies{}) {
+		t.Errorf("got %+v for no requests", got)
+	}
+	if got := newLatencies([]time.Duration{time.Second}, 0); got.P50 != time.Second || got.P99 != time.Second {
+		t.Errorf("got %+v for a single request", got)
+	}
+}
@@ -0,0 +1,119 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// ResourceSample is the resource usage of the target of a workload at a time.
+type ResourceSample struct {
+	// CPUSeconds is the CPU time the target has used since it started.
+	CPUSeconds float64
+	// ResidentMemoryBytes is the memory of the target that is resident.
+	ResidentMemoryBytes float64
+	// HeapInuseBytes is the memory of the target that is in use by its heap.
+	HeapInuseBytes float64
+	// Goroutines is the number of goroutines of the target.
+	Goroutines float64
+}
+
+// ResourceUsage is the resource usage of the target of a workload while it ran.
+type ResourceUsage struct {
+	// Samples is the number of samples the usage is from.
+	Samples int
+	// CPUSeconds is the CPU time the target used while the workload ran.
+	CPUSeconds float64
+	// MaxResidentMemoryBytes, MaxHeapInuseBytes and MaxGoroutines are the peaks of the samples.
+	MaxResidentMemoryBytes float64
+	MaxHeapInuseBytes      float64
+	MaxGoroutines          float64
+
+	first ResourceSample
+}
+
+// add adds a sample to the usage.
+func (u *ResourceUsage) add(s ResourceSample) {
+	if u.Samples == 0 {
+		u.first = s
+	}
+	u.Samples++
+	u.CPUSeconds = s.CPUSeconds - u.first.CPUSeconds
+	if s.ResidentMemoryBytes > u.MaxResidentMemoryBytes {
+		u.MaxResidentMemoryBytes = s.ResidentMemoryBytes
+	}
+	if s.HeapInuseBytes > u.MaxHeapInuseBytes {
+		u.MaxHeapInuseBytes = s.HeapInuseBytes
+	}
+	if s.Goroutines > u.MaxGoroutines {
+		u.MaxGoroutines = s.Goroutines
+	}
+}
+
+const metricsPath = "/metrics"
+
+var _ Sampler = (*MetricsSampler)(nil)
+
+// MetricsSampler samples the resource usage of an instance from the Prometheus
+// metrics it serves. Metrics that the instance doesn't serve are sampled as zero.
+type MetricsSampler struct {
+	Addr string
+	// Client is the client of the requests; if it is nil, http.DefaultClient is.
+	Client *http.Client
+}
+
+// Sample returns the current resource usage of the instance.
+func (s *MetricsSampler) Sample(ctx context.Context) (ResourceSample, error) {
+	var sample ResourceSample
+	req, err := http.NewRequest("GET", strings.TrimSuffix(s.Addr, "/")+metricsPath, nil)
+	if err != nil {
+		return sample, err
+	}
+	req.Header.Set("Accept", string(expfmt.FmtText))
+
+	c := s.Client
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req.WithContext(ctx))
+	if err != nil {
+		return sample, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return sample, fmt.Errorf("got status %d from %s", resp.StatusCode, req.URL)
+	}
+
+	var p expfmt.TextParser
+	mfs, err := p.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return sample, err
+	}
+
+	sample.CPUSeconds = metricValue(mfs["process_cpu_seconds_total"])
+	sample.ResidentMemoryBytes = metricValue(mfs["process_resident_memory_bytes"])
+	sample.HeapInuseBytes = metricValue(mfs["go_memstats_heap_inuse_bytes"])
+	sample.Goroutines = metricValue(mfs["go_goroutines"])
+	return sample, nil
+}
+
+// metricValue returns the value of the first metric of a family of counters or gauges.
+func metricValue(mf *dto.MetricFamily) float64 {
+	if mf == nil || len(mf.Metric) == 0 {
+		return 0
+	}
+	m := mf.Metric[0]
+	switch {
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	case m.Untyped != nil:
+		return m.Untyped.GetValue()
+	}
+	return 0
+}
@@ -152,7 +152,8 @@ func (c *Client) FindBucket(ctx context.Context, filter platform.BucketFilter) (
 	var b *platform.Bucket
 	var err error
 
-	if filter.ID != nil {
+	// Filters on metadata need the linear scan.
+	if filter.ID != nil && len(filter.Metadata) == 0 {
 		b, err = c.FindBucketByID(ctx, *filter.ID)
 		if err != nil {
 			return nil, &platform.Error{
@@ -163,7 +164,7 @@ func (c *Client) FindBucket(ctx context.Context, filter platform.BucketFilter) (
 		return b, nil
 	}
 
-	if filter.Name != nil && filter.OrganizationID != nil {
+	if filter.Name != nil && filter.OrganizationID != nil && len(filter.Metadata) == 0 {
 		return c.FindBucketByName(ctx, *filter.OrganizationID, *filter.Name)
 	}
 
@@ -204,6 +205,16 @@ func (c *Client) FindBucket(ctx context.Context, filter platform.BucketFilter) (
 }
 
 func filterBucketsFn(filter platform.BucketFilter) func(b *platform.Bucket) bool {
+	fn := filterBucketsByIDOrNameFn(filter)
+	if len(filter.Metadata) == 0 {
+		return fn
+	}
+	return func(b *platform.Bucket) bool {
+		return fn(b) && b.Metadata.Matches(filter.Metadata)
+	}
+}
+
+func filterBucketsByIDOrNameFn(filter platform.BucketFilter) func(b *platform.Bucket) bool {
 	if filter.ID != nil {
 		return func(b *platform.Bucket) bool {
 			return b.ID == *filter.ID
@@ -243,6 +254,9 @@ func (c *Client) FindBuckets(ctx context.Context, filter platform.BucketFilter,
 		if err != nil {
 			return nil, 0, err
 		}
+		if !b.Metadata.Matches(filter.Metadata) {
+			return []*platform.Bucket{}, 0, nil
+		}
 
 		return []*platform.Bucket{b}, 1, nil
 	}
@@ -252,6 +266,9 @@ func (c *Client) FindBuckets(ctx context.Context, filter platform.BucketFilter,
 		if err != nil {
 			return nil, 0, err
 		}
+		if !b.Metadata.Matches(filter.Metadata) {
+			return []*platform.Bucket{}, 0, nil
+		}
 
 		return []*platform.Bucket{b}, 1, nil
 	}
@@ -350,6 +367,20 @@ func (c *Client) CreateBucket(ctx context.Context, b *platform.Bucket) error {
 			}
 		}
 
+		if err := b.Metadata.Valid(); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
+		if err := b.SchemaType.Valid(); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
 		b.ID = c.IDGenerator.ID()
 		b.CreatedAt = c.Now()
 		b.UpdatedAt = c.Now()
@@ -523,10 +554,34 @@ func (c *Client) updateBucket(ctx context.Context, tx *bolt.Tx, id platform.ID,
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ReorderWindow != nil {
+		b.ReorderWindow = *upd.ReorderWindow
+	}
+
+	if upd.Quota != nil {
+		b.Quota = *upd.Quota
+	}
+
+	if upd.QueryCacheDisabled != nil {
+		b.QueryCacheDisabled = *upd.QueryCacheDisabled
+	}
+
+	if upd.WriteHooks != nil {
+		b.WriteHooks = *upd.WriteHooks
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
 
+	if upd.Metadata != nil {
+		m, err := upd.Metadata.Apply(b.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		b.Metadata = m
+	}
+
 	if upd.Name != nil {
 		b0, err := c.findBucketByName(ctx, tx, b.OrgID, *upd.Name)
 		if err == nil && b0.ID != id {
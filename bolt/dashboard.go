@@ -97,7 +97,7 @@ func (c *Client) findDashboardByID(ctx context.Context, tx *bolt.Tx, id platform
 
 // FindDashboard retrieves a dashboard using an arbitrary dashboard filter.
 func (c *Client) FindDashboard(ctx context.Context, filter platform.DashboardFilter, opts ...platform.FindOptions) (*platform.Dashboard, error) {
-	if len(filter.IDs) == 1 {
+	if len(filter.IDs) == 1 && len(filter.Metadata) == 0 {
 		return c.FindDashboardByID(ctx, *filter.IDs[0])
 	}
 
@@ -135,11 +135,11 @@ func filterDashboardsFn(filter platform.DashboardFilter) func(d *platform.Dashbo
 		}
 		return func(d *platform.Dashboard) bool {
 			_, ok := m[d.ID.String()]
-			return ok
+			return ok && d.Metadata.Matches(filter.Metadata)
 		}
 	}
 
-	return func(d *platform.Dashboard) bool { return true }
+	return func(d *platform.Dashboard) bool { return d.Metadata.Matches(filter.Metadata) }
 }
 
 // FindDashboards retrives all dashboards that match an arbitrary dashboard filter.
@@ -153,7 +153,7 @@ func (c *Client) FindDashboards(ctx context.Context, filter platform.DashboardFi
 				Op:  getOp(platform.OpFindDashboardByID),
 			}
 		}
-		if d == nil {
+		if d == nil || !d.Metadata.Matches(filter.Metadata) {
 			return ds, 0, nil
 		}
 		return []*platform.Dashboard{d}, 1, nil
@@ -223,15 +223,23 @@ func decodeOrgDashboardIndexKey(indexKey []byte) (orgID platform.ID, dashID plat
 
 func (c *Client) findDashboards(ctx context.Context, tx *bolt.Tx, filter platform.DashboardFilter, opts ...platform.FindOptions) ([]*platform.Dashboard, error) {
 	if filter.OrganizationID != nil {
-		return c.findOrganizationDashboards(ctx, tx, *filter.OrganizationID)
+		ds, err := c.findOrganizationDashboards(ctx, tx, *filter.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		return filter.FilterByMetadata(ds), nil
 	}
 
 	if filter.Organization != nil {
-		o, err := c.findOrganizationByName(ctx, tx, *filter.Organization)
+		o, pe := c.findOrganizationByName(ctx, tx, *filter.Organization)
+		if pe != nil {
+			return nil, pe
+		}
+		ds, err := c.findOrganizationDashboards(ctx, tx, o.ID)
 		if err != nil {
 			return nil, err
 		}
-		return c.findOrganizationDashboards(ctx, tx, o.ID)
+		return filter.FilterByMetadata(ds), nil
 	}
 
 	var offset, limit, count int
@@ -267,6 +275,10 @@ func (c *Client) findDashboards(ctx context.Context, tx *bolt.Tx, filter platfor
 // CreateDashboard creates a platform dashboard and sets d.ID.
 func (c *Client) CreateDashboard(ctx context.Context, d *platform.Dashboard) error {
 	err := c.db.Update(func(tx *bolt.Tx) error {
+		if err := d.Metadata.Valid(); err != nil {
+			return err
+		}
+
 		d.ID = c.IDGenerator.ID()
 
 		for _, cell := range d.Cells {
@@ -45,16 +45,14 @@ func (c *Client) PutOnboardingStatus(ctx context.Context, v bool) error {
 
 // Generate OnboardingResults from onboarding request,
 // update db so this request will be disabled for the second run.
+// A second run with the same request gets the same results.
 func (c *Client) Generate(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
 	isOnboarding, err := c.IsOnboarding(ctx)
 	if err != nil {
 		return nil, err
 	}
 	if !isOnboarding {
-		return nil, &platform.Error{
-			Code: platform.EConflict,
-			Msg:  "onboarding has already been completed",
-		}
+		return c.findOnboardingResults(ctx, req)
 	}
 
 	if req.Password == "" {
@@ -85,19 +83,36 @@ func (c *Client) Generate(ctx context.Context, req *platform.OnboardingRequest)
 		}
 	}
 
+	return c.onboard(ctx, req, false)
+}
+
+// OnboardUser creates an additional user along with an organization it owns,
+// a bucket and a token with all access to the organization.
+func (c *Client) OnboardUser(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	return c.onboard(ctx, req, true)
+}
+
+// onboard creates the user, organization, bucket and token of the request.
+// The token of the first user has operator permissions, and creating it
+// completes onboarding.
+func (c *Client) onboard(ctx context.Context, req *platform.OnboardingRequest, additional bool) (*platform.OnboardingResults, error) {
 	u := &platform.User{Name: req.User}
 	if err := c.CreateUser(ctx, u); err != nil {
 		return nil, err
 	}
 
-	if err = c.SetPassword(ctx, u.Name, req.Password); err != nil {
+	if err := c.SetPassword(ctx, u.Name, req.Password); err != nil {
 		return nil, err
 	}
 
 	o := &platform.Organization{
 		Name: req.Org,
 	}
-	if err = c.CreateOrganization(ctx, o); err != nil {
+	if err := c.CreateOrganization(ctx, o); err != nil {
 		return nil, err
 	}
 	bucket := &platform.Bucket{
@@ -105,7 +120,7 @@ func (c *Client) Generate(ctx context.Context, req *platform.OnboardingRequest)
 		OrgID:           o.ID,
 		RetentionPeriod: time.Duration(req.RetentionPeriod) * time.Hour,
 	}
-	if err = c.CreateBucket(ctx, bucket); err != nil {
+	if err := c.CreateBucket(ctx, bucket); err != nil {
 		return nil, err
 	}
 
@@ -125,12 +140,17 @@ func (c *Client) Generate(ctx context.Context, req *platform.OnboardingRequest)
 		Permissions: platform.OperPermissions(),
 		Token:       req.Token,
 	}
-	if err = c.CreateAuthorization(ctx, auth); err != nil {
+	if additional {
+		auth.Permissions = platform.OnboardUserPermissions(o.ID, u.ID)
+	}
+	if err := c.CreateAuthorization(ctx, auth); err != nil {
 		return nil, err
 	}
 
-	if err = c.PutOnboardingStatus(ctx, true); err != nil {
-		return nil, err
+	if !additional {
+		if err := c.PutOnboardingStatus(ctx, true); err != nil {
+			return nil, err
+		}
 	}
 
 	return &platform.OnboardingResults{
@@ -140,3 +160,48 @@ func (c *Client) Generate(ctx context.Context, req *platform.OnboardingRequest)
 		Auth:   auth,
 	}, nil
 }
+
+// findOnboardingResults returns the results of the completed onboarding if
+// the request matches them. The token is the one of the request if set, else
+// the first active token of the user for the organization.
+func (c *Client) findOnboardingResults(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+	conflict := &platform.Error{
+		Code: platform.EConflict,
+		Msg:  platform.ErrOnboardingCompleted,
+	}
+	if req == nil || req.Valid() != nil {
+		return nil, conflict
+	}
+
+	if err := c.ComparePassword(ctx, req.User, req.Password); err != nil {
+		return nil, conflict
+	}
+	u, err := c.FindUser(ctx, platform.UserFilter{Name: &req.User})
+	if err != nil {
+		return nil, conflict
+	}
+	o, err := c.FindOrganization(ctx, platform.OrganizationFilter{Name: &req.Org})
+	if err != nil {
+		return nil, conflict
+	}
+	b, err := c.FindBucket(ctx, platform.BucketFilter{Name: &req.Bucket, OrganizationID: &o.ID})
+	if err != nil || b.RetentionPeriod != time.Duration(req.RetentionPeriod)*time.Hour {
+		return nil, conflict
+	}
+
+	as, _, err := c.FindAuthorizations(ctx, platform.AuthorizationFilter{UserID: &u.ID, OrgID: &o.ID})
+	if err != nil {
+		return nil, conflict
+	}
+	for _, a := range as {
+		if a.IsActive() && (req.Token == "" || a.Token == req.Token) {
+			return &platform.OnboardingResults{
+				User:   u,
+				Org:    o,
+				Bucket: b,
+				Auth:   a,
+			}, nil
+		}
+	}
+	return nil, conflict
+}
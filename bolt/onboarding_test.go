@@ -35,3 +35,11 @@ func initOnboardingService(f platformtesting.OnboardingFields, t *testing.T) (pl
 func TestOnboardingService_Generate(t *testing.T) {
 	platformtesting.Generate(initOnboardingService, t)
 }
+
+func TestOnboardingService_Regenerate(t *testing.T) {
+	platformtesting.Regenerate(initOnboardingService, t)
+}
+
+func TestOnboardingService_OnboardUser(t *testing.T) {
+	platformtesting.OnboardUser(initOnboardingService, t)
+}
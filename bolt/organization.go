@@ -228,6 +228,28 @@ func (c *Client) CreateOrganization(ctx context.Context, o *influxdb.Organizatio
 				Err: err,
 			}
 		}
+		if _, err := influxdb.LoadTimezone(o.Timezone); err != nil {
+			return &influxdb.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		if o.BucketDefaults != nil {
+			if err := o.BucketDefaults.Valid(); err != nil {
+				return &influxdb.Error{
+					Op:  op,
+					Err: err,
+				}
+			}
+		}
+		if o.NamePolicy != nil {
+			if err := o.NamePolicy.Valid(); err != nil {
+				return &influxdb.Error{
+					Op:  op,
+					Err: err,
+				}
+			}
+		}
 
 		o.ID = c.IDGenerator.ID()
 		o.CreatedAt = c.Now()
@@ -365,6 +387,39 @@ func (c *Client) updateOrganization(ctx context.Context, tx *bolt.Tx, id influxd
 		o.Description = *upd.Description
 	}
 
+	if upd.Timezone != nil {
+		if _, err := influxdb.LoadTimezone(*upd.Timezone); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		o.Timezone = *upd.Timezone
+	}
+
+	if upd.BucketDefaults != nil {
+		if err := upd.BucketDefaults.Valid(); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		o.BucketDefaults = nil
+		if d := *upd.BucketDefaults; !d.IsZero() {
+			o.BucketDefaults = &d
+		}
+	}
+
+	if upd.NamePolicy != nil {
+		o.NamePolicy = nil
+		if p := *upd.NamePolicy; !p.IsZero() {
+			if err := p.Valid(); err != nil {
+				return nil, &influxdb.Error{
+					Err: err,
+				}
+			}
+			o.NamePolicy = &p
+		}
+	}
+
 	o.UpdatedAt = c.Now()
 
 	if err := c.appendOrganizationEventToLog(ctx, tx, o.ID, organizationUpdatedEvent); err != nil {
@@ -185,11 +185,12 @@ func decodeSecretKey(key []byte) (influxdb.ID, string, error) {
 func decodeSecretValue(val []byte) (string, error) {
 	// store the secret value base64 encoded so that it's marginally better than plaintext
 	v := make([]byte, base64.StdEncoding.DecodedLen(len(val)))
-	if _, err := base64.StdEncoding.Decode(v, val); err != nil {
+	n, err := base64.StdEncoding.Decode(v, val)
+	if err != nil {
 		return "", err
 	}
 
-	return string(v), nil
+	return string(v[:n]), nil
 }
 
 func encodeSecretValue(v string) []byte {
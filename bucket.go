@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -18,6 +19,10 @@ const (
 // InfiniteRetention is default infinite retention period.
 const InfiniteRetention = 0
 
+// MaxReorderWindow is the longest reorder window of a bucket.
+// Points are held in memory for the window, so it is kept short.
+const MaxReorderWindow = time.Hour
+
 // Bucket is a bucket. 🎉
 type Bucket struct {
 	ID                  ID            `json:"id,omitempty"`
@@ -26,6 +31,23 @@ type Bucket struct {
 	Description         string        `json:"description"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+	// ReorderWindow is how long points written to the bucket are buffered, so that
+	// points arriving out of order within the window are stored in order.
+	// Buffered points are not queryable until the window has passed. Zero disables buffering.
+	ReorderWindow time.Duration `json:"reorderWindow,omitempty"`
+	// Quota limits the writes to the bucket.
+	Quota BucketQuota `json:"quota"`
+	// QueryCacheDisabled keeps HTTP caches from serving the responses to queries
+	// of the bucket, such as when historical data of the bucket is backfilled.
+	QueryCacheDisabled bool `json:"queryCacheDisabled,omitempty"`
+	// SchemaType is how the schemas of the measurements of the bucket are defined.
+	// It is set when the bucket is created and can not be changed.
+	SchemaType BucketSchemaType `json:"schemaType,omitempty"`
+	// WriteHooks are the names of the registered write hooks that the points
+	// written to the bucket are processed by, in order.
+	WriteHooks []string `json:"writeHooks,omitempty"`
+	// Metadata is free-form key/value metadata, such as the ID of the bucket in another system.
+	Metadata Metadata `json:"metadata,omitempty"`
 	CRUDLog
 }
 
@@ -68,6 +90,30 @@ type BucketUpdate struct {
 	Name            *string        `json:"name,omitempty"`
 	Description     *string        `json:"description,omitempty"`
 	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	ReorderWindow   *time.Duration `json:"reorderWindow,omitempty"`
+	Quota           *BucketQuota   `json:"quota,omitempty"`
+	// QueryCacheDisabled toggles HTTP caching of the responses to queries of the bucket.
+	QueryCacheDisabled *bool `json:"queryCacheDisabled,omitempty"`
+	// WriteHooks replaces the write hooks of the bucket; an empty list removes them.
+	WriteHooks *[]string      `json:"writeHooks,omitempty"`
+	Metadata   MetadataUpdate `json:"metadata,omitempty"`
+}
+
+// BucketSortKeys are the keys buckets can be sorted by.
+var BucketSortKeys = []string{"ID", "Name", "RetentionPeriod"}
+
+// SortBuckets sorts buckets by the sort key of the options, or by ID if they have none.
+func SortBuckets(opts FindOptions, bs []*Bucket) {
+	var less func(i, j int) bool
+	switch opts.sortKey() {
+	case "name":
+		less = func(i, j int) bool { return bs[i].Name < bs[j].Name }
+	case "retentionperiod":
+		less = func(i, j int) bool { return bs[i].RetentionPeriod < bs[j].RetentionPeriod }
+	default:
+		less = func(i, j int) bool { return bs[i].ID < bs[j].ID }
+	}
+	sort.SliceStable(bs, opts.ordered(less))
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
@@ -76,6 +122,8 @@ type BucketFilter struct {
 	Name           *string
 	OrganizationID *ID
 	Org            *string
+	// Metadata restricts the buckets to those with all its keys and values.
+	Metadata Metadata
 }
 
 // QueryParams Converts BucketFilter fields to url query params.
@@ -97,6 +145,10 @@ func (f BucketFilter) QueryParams() map[string][]string {
 		qp["org"] = []string{*f.Org}
 	}
 
+	if len(f.Metadata) > 0 {
+		qp["metadata"] = f.Metadata.QueryParams()
+	}
+
 	return qp
 }
 
@@ -124,3 +176,28 @@ func (f BucketFilter) String() string {
 func InternalBucketID(t BucketType) (*ID, error) {
 	return IDFromString(fmt.Sprintf("%d", t))
 }
+
+// BucketDefaults are the values the buckets of an organization are created
+// with when a bucket is created without them, so an organization can have a
+// retention policy without every client setting it.
+type BucketDefaults struct {
+	// RetentionPeriod is the retention period of buckets created without retention rules.
+	// Zero is infinite retention.
+	RetentionPeriod time.Duration `json:"retentionPeriod,omitempty"`
+}
+
+// Valid returns an error if the defaults are not valid values of a bucket.
+func (d BucketDefaults) Valid() error {
+	if d.RetentionPeriod < 0 || (d.RetentionPeriod > 0 && d.RetentionPeriod < time.Second) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "default retention period must be at least one second",
+		}
+	}
+	return nil
+}
+
+// IsZero returns true if the defaults set no value.
+func (d BucketDefaults) IsZero() bool {
+	return d == BucketDefaults{}
+}
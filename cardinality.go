@@ -0,0 +1,56 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// CardinalitySnapshot is the cardinality of a bucket at a point in time.
+type CardinalitySnapshot struct {
+	Time time.Time `json:"time"`
+	// Series is the number of series in the bucket.
+	Series int64 `json:"series"`
+	// TagValues is the number of distinct values of each tag key in the bucket.
+	TagValues map[string]int64 `json:"tagValues"`
+}
+
+// CardinalityService returns the cardinality snapshots recorded for buckets.
+type CardinalityService interface {
+	// FindCardinalityTrend returns the snapshots of a bucket in the time range of the filter, oldest first.
+	FindCardinalityTrend(ctx context.Context, filter CardinalityTrendFilter) ([]*CardinalitySnapshot, error)
+
+	// FindCurrentCardinality returns the cardinality of a bucket as it is now,
+	// rather than as of its last snapshot.
+	FindCurrentCardinality(ctx context.Context, orgID, bucketID ID) (*CardinalitySnapshot, error)
+}
+
+// CardinalityTrendFilter selects the cardinality snapshots of a bucket.
+type CardinalityTrendFilter struct {
+	OrgID    ID
+	BucketID ID
+	Start    time.Time
+	Stop     time.Time
+}
+
+// Valid returns an error if the filter does not select a bucket and a time range.
+func (f CardinalityTrendFilter) Valid() error {
+	if !f.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is invalid",
+		}
+	}
+	if !f.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucketID is invalid",
+		}
+	}
+	if !f.Start.Before(f.Stop) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "start must be before stop",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,596 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+)
+
+// ErrCheckNotFound is the error msg for a missing check.
+const ErrCheckNotFound = "check not found"
+
+// ops for check errors and op log.
+const (
+	OpFindCheckByID = "FindCheckByID"
+	OpFindChecks    = "FindChecks"
+	OpCreateCheck   = "CreateCheck"
+	OpUpdateCheck   = "UpdateCheck"
+	OpReplaceCheck  = "ReplaceCheck"
+	OpDeleteCheck   = "DeleteCheck"
+)
+
+// MonitoringBucketName is the name of the bucket of an organization that
+// the tasks of its checks write statuses to.
+const MonitoringBucketName = "_monitoring"
+
+// MonitoringBucketRetention is how long statuses are kept in the monitoring bucket.
+const MonitoringBucketRetention = 7 * 24 * time.Hour
+
+// CheckStatusMeasurement is the measurement statuses are written to.
+const CheckStatusMeasurement = "statuses"
+
+// DefaultCheckStatusMessageTemplate is the message of the statuses of checks without a template.
+const DefaultCheckStatusMessageTemplate = "Check: ${_check_name} is: ${_level}"
+
+// DefaultSchemaCheckStatusMessageTemplate is the message of the statuses of
+// schema checks without a template. The _value of their statuses describes
+// how the measurement drifted.
+const DefaultSchemaCheckStatusMessageTemplate = "Check: ${_check_name} is: ${_level}, ${_source_measurement}: ${_value}"
+
+var (
+	checkTagKeyRegexp        = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	checkMessageColumnRegexp = regexp.MustCompile(`\$\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}`)
+)
+
+// CheckType is the kind of criteria a check evaluates.
+type CheckType string
+
+// Check types.
+const (
+	ThresholdCheckType CheckType = "threshold"
+	DeadmanCheckType   CheckType = "deadman"
+	// SchemaCheckType checks watch the schema of a bucket for new
+	// measurements, tags and fields, and fields whose data type changes.
+	SchemaCheckType CheckType = "schema"
+)
+
+// CheckLevel is the level of a status written by a check.
+type CheckLevel string
+
+// Check levels, from least to most severe.
+const (
+	CheckLevelUnknown CheckLevel = "UNKNOWN"
+	CheckLevelOK      CheckLevel = "OK"
+	CheckLevelInfo    CheckLevel = "INFO"
+	CheckLevelWarn    CheckLevel = "WARN"
+	CheckLevelCrit    CheckLevel = "CRIT"
+)
+
+var checkLevelSeverity = map[CheckLevel]int{
+	CheckLevelUnknown: 0,
+	CheckLevelOK:      1,
+	CheckLevelInfo:    2,
+	CheckLevelWarn:    3,
+	CheckLevelCrit:    4,
+}
+
+// Valid returns an error if the level is unknown.
+func (l CheckLevel) Valid() error {
+	if _, ok := checkLevelSeverity[l]; !ok {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid check level %q: must be UNKNOWN, OK, INFO, WARN or CRIT", l),
+		}
+	}
+	return nil
+}
+
+// Severity orders levels; a more severe level has a higher severity.
+func (l CheckLevel) Severity() int {
+	return checkLevelSeverity[l]
+}
+
+// ThresholdType is the kind of comparison of a threshold.
+type ThresholdType string
+
+// Threshold types.
+const (
+	GreaterThreshold ThresholdType = "greater"
+	LesserThreshold  ThresholdType = "lesser"
+	RangeThreshold   ThresholdType = "range"
+)
+
+// Threshold sets the level of the values matching it. Greater and lesser
+// thresholds compare with Value, range thresholds match the values within,
+// or if Within is false outside, Min and Max.
+type Threshold struct {
+	Type   ThresholdType `json:"type"`
+	Level  CheckLevel    `json:"level"`
+	Value  float64       `json:"value,omitempty"`
+	Min    float64       `json:"min,omitempty"`
+	Max    float64       `json:"max,omitempty"`
+	Within bool          `json:"within,omitempty"`
+}
+
+// Valid returns an error if the threshold is invalid.
+func (t Threshold) Valid() error {
+	if err := t.Level.Valid(); err != nil {
+		return err
+	}
+	switch t.Type {
+	case GreaterThreshold, LesserThreshold:
+	case RangeThreshold:
+		if t.Min > t.Max {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "range threshold min must not be greater than max",
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid threshold type %q: must be greater, lesser or range", t.Type),
+		}
+	}
+	return nil
+}
+
+// CheckTag is a tag added to each status written by a check.
+type CheckTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// CheckStatus is a status written by a check.
+type CheckStatus struct {
+	CheckID   ID         `json:"checkID,omitempty"`
+	CheckName string     `json:"checkName,omitempty"`
+	Level     CheckLevel `json:"level"`
+	Message   string     `json:"message"`
+	Time      time.Time  `json:"time"`
+	// Tags are the tags of the series the status was written for, which
+	// include the tags of the check and of the data it queried.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// SeriesKey identifies the series of the status, its check and its tags.
+func (s *CheckStatus) SeriesKey() string {
+	keys := make([]string, 0, len(s.Tags))
+	for k := range s.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(s.CheckID.String())
+	for _, k := range keys {
+		b.WriteString(",")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(s.Tags[k])
+	}
+	return b.String()
+}
+
+// Check runs a query on a schedule and writes the level of the results to
+// the monitoring bucket of its organization. Checks run as tasks generated
+// from their definition, except schema checks, which compare the schema of
+// a bucket with the schema it had when they last ran.
+type Check struct {
+	ID          ID        `json:"id,omitempty"`
+	OrgID       ID        `json:"orgID,omitempty"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Type        CheckType `json:"type"`
+	Status      Status    `json:"status"`
+
+	// Query is the query whose results are checked.
+	Query DashboardQuery `json:"query"`
+	// BucketID is the bucket whose schema a schema check watches.
+	BucketID ID `json:"bucketID,omitempty"`
+	// Every and Offset schedule the task of the check. Schema checks run
+	// every Every.
+	Every  string `json:"every"`
+	Offset string `json:"offset,omitempty"`
+	// Tags are added to each status.
+	Tags []CheckTag `json:"tags,omitempty"`
+	// StatusMessageTemplate is the message of each status. Columns of the
+	// results are referenced as ${column}.
+	StatusMessageTemplate string `json:"statusMessageTemplate,omitempty"`
+
+	// Thresholds are the thresholds of a threshold check. The most severe
+	// threshold a value matches sets its level, or OK if none does.
+	Thresholds []Threshold `json:"thresholds,omitempty"`
+
+	// TimeSince is the number of seconds without data after which a deadman
+	// check writes statuses of Level. Schema checks write statuses of Level
+	// for the measurements whose schema drifted.
+	TimeSince int64      `json:"timeSince,omitempty"`
+	Level     CheckLevel `json:"level,omitempty"`
+
+	// TaskID is the task running the check.
+	TaskID ID `json:"taskID,omitempty"`
+	// LastStatus is the latest status written by the check. It is not stored.
+	LastStatus *CheckStatus `json:"lastStatus,omitempty"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the check is missing what its type needs.
+func (c *Check) Valid() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "check name is required",
+		}
+	}
+	if !c.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if err := c.Status.Valid(); err != nil {
+		return err
+	}
+	if c.RunsAsTask() && strings.TrimSpace(c.Query.Text) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "check query is required",
+		}
+	}
+	if c.Every == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "check every is required",
+		}
+	}
+	for _, t := range c.Tags {
+		if !checkTagKeyRegexp.MatchString(t.Key) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid check tag key %q: must start with a letter and contain only letters, digits and _", t.Key),
+			}
+		}
+	}
+
+	switch c.Type {
+	case ThresholdCheckType:
+		if len(c.Thresholds) == 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "threshold check needs at least one threshold",
+			}
+		}
+		for _, t := range c.Thresholds {
+			if err := t.Valid(); err != nil {
+				return err
+			}
+		}
+	case DeadmanCheckType:
+		if c.TimeSince <= 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "deadman check needs a positive timeSince",
+			}
+		}
+		if err := c.Level.Valid(); err != nil {
+			return err
+		}
+	case SchemaCheckType:
+		if !c.BucketID.Valid() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "schema check needs a bucketID",
+			}
+		}
+		if err := c.Level.Valid(); err != nil {
+			return err
+		}
+		if _, err := parser.ParseDuration(c.Every); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid check every %q: must be a duration such as 1m", c.Every),
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid check type %q: must be threshold, deadman or schema", c.Type),
+		}
+	}
+	return nil
+}
+
+// RunsAsTask returns whether the check runs as a task generated from it.
+// Schema checks compare schema docs, which Flux can not read, so they are run
+// by the check.SchemaDriftEngine of the server instead.
+func (c *Check) RunsAsTask() bool {
+	return c.Type != SchemaCheckType
+}
+
+// GenerateFlux returns the Flux of the task running the check. The task runs
+// the query of the check, sets the level of each result, and writes it with
+// the message, the tags of the check and the check ID and name as tags to the
+// statuses measurement of the monitoring bucket. The measurement and field of
+// the results are kept in the _source_measurement and _source_field tags.
+func (c *Check) GenerateFlux() (string, error) {
+	if err := c.Valid(); err != nil {
+		return "", err
+	}
+	if !c.RunsAsTask() {
+		return "", &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("%s checks do not run as tasks", c.Type),
+		}
+	}
+
+	imports, query, err := c.splitQuery()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, imp := range imports {
+		b.WriteString(imp + "\n")
+	}
+	if len(imports) > 0 {
+		b.WriteString("\n")
+	}
+
+	if _, err := parser.ParseDuration(c.Every); err != nil {
+		return "", &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid check every %q: must be a duration such as 1m", c.Every),
+		}
+	}
+	b.WriteString(fmt.Sprintf("option task = {name: %s, every: %s", fluxString(c.Name), c.Every))
+	if c.Offset != "" {
+		if _, err := parser.ParseDuration(c.Offset); err != nil {
+			return "", &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid check offset %q: must be a duration such as 1m", c.Offset),
+			}
+		}
+		b.WriteString(", offset: " + c.Offset)
+	}
+	b.WriteString("}\n\n")
+	b.WriteString("data = " + query + "\n\n")
+
+	b.WriteString("data\n")
+	if c.Type == DeadmanCheckType {
+		b.WriteString("\t|> last()\n")
+	}
+	b.WriteString("\t|> rename(columns: {_measurement: \"_source_measurement\", _field: \"_source_field\"})\n")
+	b.WriteString("\t|> map(fn: (r) => ({r with\n")
+	b.WriteString(fmt.Sprintf("\t\t_measurement: %s,\n", fluxString(CheckStatusMeasurement)))
+	b.WriteString(fmt.Sprintf("\t\t_check_id: %s,\n", fluxString(c.ID.String())))
+	b.WriteString(fmt.Sprintf("\t\t_check_name: %s,\n", fluxString(c.Name)))
+	b.WriteString(fmt.Sprintf("\t\t_type: %s,\n", fluxString(string(c.Type))))
+	for _, t := range c.Tags {
+		b.WriteString(fmt.Sprintf("\t\t%s: %s,\n", t.Key, fluxString(t.Value)))
+	}
+	b.WriteString("\t\t_level: " + c.levelExpr() + ",\n")
+	b.WriteString("\t\t_source_timestamp: int(v: r._time),\n")
+	b.WriteString("\t}))\n")
+	// The message is set separately, so that its template can reference the columns set above.
+	b.WriteString("\t|> map(fn: (r) => ({r with _message: " + c.messageExpr() + ", _time: now()}))\n")
+	b.WriteString(fmt.Sprintf("\t|> to(bucket: %s, orgID: %s, fieldFn: (r) => ({_message: r._message, _source_timestamp: r._source_timestamp, _value: r._value}))\n",
+		fluxString(MonitoringBucketName), fluxString(c.OrgID.String())))
+
+	flux := b.String()
+	if pkg := parser.ParseSource(flux); ast.Check(pkg) > 0 {
+		return "", &Error{
+			Code: EInvalid,
+			Msg:  "check does not generate valid flux",
+			Err:  ast.GetError(pkg),
+		}
+	}
+	return flux, nil
+}
+
+// splitQuery returns the imports of the query of the check and the
+// expression that follows them, which is all the query may contain.
+func (c *Check) splitQuery() ([]string, string, error) {
+	pkg := parser.ParseSource(c.Query.Text)
+	if ast.Check(pkg) > 0 {
+		return nil, "", &Error{
+			Code: EInvalid,
+			Msg:  "invalid check query",
+			Err:  ast.GetError(pkg),
+		}
+	}
+	if len(pkg.Files) != 1 || len(pkg.Files[0].Body) != 1 {
+		return nil, "", &Error{
+			Code: EInvalid,
+			Msg:  "check query must be a single expression",
+		}
+	}
+	f := pkg.Files[0]
+	stmt, ok := f.Body[0].(*ast.ExpressionStatement)
+	if !ok {
+		return nil, "", &Error{
+			Code: EInvalid,
+			Msg:  "check query must be a single expression",
+		}
+	}
+
+	imports := make([]string, 0, len(f.Imports))
+	for _, imp := range f.Imports {
+		imports = append(imports, ast.Format(imp))
+	}
+	return imports, ast.Format(stmt.Expression), nil
+}
+
+// levelExpr returns the Flux expression of the level of a result r.
+func (c *Check) levelExpr() string {
+	if c.Type == DeadmanCheckType {
+		return fmt.Sprintf("if int(v: now()) - int(v: r._time) > %d then %s else %s",
+			(time.Duration(c.TimeSince) * time.Second).Nanoseconds(), fluxString(string(c.Level)), fluxString(string(CheckLevelOK)))
+	}
+
+	ts := make([]Threshold, len(c.Thresholds))
+	copy(ts, c.Thresholds)
+	sort.SliceStable(ts, func(i, j int) bool {
+		return ts[i].Level.Severity() > ts[j].Level.Severity()
+	})
+
+	var b strings.Builder
+	for _, t := range ts {
+		b.WriteString(fmt.Sprintf("if %s then %s else ", t.condExpr("float(v: r._value)"), fluxString(string(t.Level))))
+	}
+	b.WriteString(fluxString(string(CheckLevelOK)))
+	return b.String()
+}
+
+// condExpr returns the Flux expression matching the value v to the threshold.
+func (t Threshold) condExpr(v string) string {
+	switch t.Type {
+	case GreaterThreshold:
+		return fmt.Sprintf("%s > %s", v, fluxFloat(t.Value))
+	case LesserThreshold:
+		return fmt.Sprintf("%s < %s", v, fluxFloat(t.Value))
+	default:
+		if t.Within {
+			return fmt.Sprintf("(%s > %s and %s < %s)", v, fluxFloat(t.Min), v, fluxFloat(t.Max))
+		}
+		return fmt.Sprintf("(%s < %s or %s > %s)", v, fluxFloat(t.Min), v, fluxFloat(t.Max))
+	}
+}
+
+// messageExpr returns the Flux expression of the message of a result r,
+// replacing the columns referenced in the template with their values.
+func (c *Check) messageExpr() string {
+	tmpl := c.StatusMessageTemplate
+	if tmpl == "" {
+		tmpl = DefaultCheckStatusMessageTemplate
+	}
+
+	var parts []string
+	last := 0
+	for _, m := range checkMessageColumnRegexp.FindAllStringSubmatchIndex(tmpl, -1) {
+		if m[0] > last {
+			parts = append(parts, fluxString(tmpl[last:m[0]]))
+		}
+		parts = append(parts, fmt.Sprintf("string(v: r.%s)", tmpl[m[2]:m[3]]))
+		last = m[1]
+	}
+	if last < len(tmpl) || len(parts) == 0 {
+		parts = append(parts, fluxString(tmpl[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// StatusMessage renders the message template of the check with the columns
+// of a status. Columns that are missing are replaced with nothing.
+func (c *Check) StatusMessage(columns map[string]string) string {
+	tmpl := c.StatusMessageTemplate
+	if tmpl == "" {
+		tmpl = DefaultCheckStatusMessageTemplate
+		if c.Type == SchemaCheckType {
+			tmpl = DefaultSchemaCheckStatusMessageTemplate
+		}
+	}
+
+	return checkMessageColumnRegexp.ReplaceAllStringFunc(tmpl, func(m string) string {
+		return columns[checkMessageColumnRegexp.FindStringSubmatch(m)[1]]
+	})
+}
+
+func fluxFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+// CheckUpdate is the changeset of a check.
+type CheckUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *Status `json:"status,omitempty"`
+}
+
+// Valid returns an error if the changeset is invalid.
+func (u CheckUpdate) Valid() error {
+	if u.Name != nil && strings.TrimSpace(*u.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "check name cannot be empty",
+		}
+	}
+	if u.Status != nil {
+		return u.Status.Valid()
+	}
+	return nil
+}
+
+// Apply applies the changeset to the check.
+func (u CheckUpdate) Apply(c *Check) {
+	if u.Name != nil {
+		c.Name = *u.Name
+	}
+	if u.Description != nil {
+		c.Description = *u.Description
+	}
+	if u.Status != nil {
+		c.Status = *u.Status
+	}
+}
+
+// CheckFilter selects checks.
+type CheckFilter struct {
+	ID    *ID
+	OrgID *ID
+	Name  *string
+	Type  *CheckType
+}
+
+// CheckService manages the checks of organizations.
+type CheckService interface {
+	// FindCheckByID returns a single check by ID.
+	FindCheckByID(ctx context.Context, id ID) (*Check, error)
+
+	// FindChecks returns the checks that match the filter, and the total
+	// count of matching checks.
+	FindChecks(ctx context.Context, filter CheckFilter, opt ...FindOptions) ([]*Check, int, error)
+
+	// CreateCheck creates a check and sets its ID.
+	CreateCheck(ctx context.Context, c *Check) error
+
+	// UpdateCheck updates a check with the changeset.
+	UpdateCheck(ctx context.Context, id ID, upd CheckUpdate) (*Check, error)
+
+	// ReplaceCheck replaces the definition of a check.
+	ReplaceCheck(ctx context.Context, c *Check) (*Check, error)
+
+	// DeleteCheck deletes a check.
+	DeleteCheck(ctx context.Context, id ID) error
+}
+
+// CheckStatusFilter selects the statuses written by the checks of an
+// organization from Start up to, but excluding, Stop.
+type CheckStatusFilter struct {
+	OrgID       ID
+	Start, Stop time.Time
+	// Last selects only the latest status of each series.
+	Last bool
+}
+
+// CheckStatusService reads the statuses written by checks.
+type CheckStatusService interface {
+	// FindLastCheckStatuses returns the latest status of each check of the
+	// organization that wrote one, by check ID.
+	FindLastCheckStatuses(ctx context.Context, orgID ID) (map[ID]*CheckStatus, error)
+
+	// FindCheckStatuses returns the statuses that match the filter, oldest first.
+	FindCheckStatuses(ctx context.Context, filter CheckStatusFilter) ([]*CheckStatus, error)
+}
@@ -0,0 +1,302 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/task/options"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+// SchemaDriftEngine runs the schema checks of all organizations. Every time
+// a check runs, it compares the schema doc of its bucket with the schema it
+// saw the last time it ran, and writes a status of its level for each
+// measurement that is new, or that has new tags or fields, or fields whose
+// declared data type changed. A measurement that drifted gets an OK status
+// the next time the check runs without it drifting again.
+//
+// The schemas are kept in memory, so a check starts over from the schema of
+// its bucket when the engine starts or the check is replaced. Measurements,
+// tags and fields past the limits of schema docs are not watched.
+//
+// Unlike threshold and deadman checks, schema checks do not run as generated
+// tasks. A task only runs Flux, and Flux can not read the schema docs of
+// buckets: the declared data types of fields come from the measurement
+// schemas and descriptions kept in the kv store, not from the data. A task
+// also has nowhere to keep the schema it saw the last time it ran. The engine
+// is scheduled by the interval of each check the way the task scheduler
+// would, so checks of either kind run every interval and write their statuses
+// to the monitoring bucket for the notification rules.
+type SchemaDriftEngine struct {
+	CheckService     influxdb.CheckService
+	BucketService    influxdb.BucketService
+	SchemaDocService influxdb.SchemaDocService
+	PointsWriter     storage.PointsWriter
+
+	now    func() time.Time
+	logger *zap.Logger
+	checks map[influxdb.ID]*schemaCheckState
+}
+
+// schemaCheckState is what the engine knows of the bucket of a schema check.
+type schemaCheckState struct {
+	// check is the definition of the check the state was kept for.
+	check *influxdb.Check
+	// next is when the check runs next.
+	next time.Time
+	// schemas are the schemas of the measurements of the bucket the last time the check ran.
+	schemas map[string]measurementSchema
+	// drifted are the measurements whose last status was not OK.
+	drifted map[string]bool
+}
+
+// measurementSchema is the tags and fields of a measurement, with the
+// declared data types of the fields.
+type measurementSchema struct {
+	tags   map[string]bool
+	fields map[string]influxdb.SchemaColumnDataType
+}
+
+// NewSchemaDriftEngine returns a SchemaDriftEngine that reads the schemas of
+// buckets with docs and writes statuses with pw.
+func NewSchemaDriftEngine(checks influxdb.CheckService, buckets influxdb.BucketService, docs influxdb.SchemaDocService, pw storage.PointsWriter, logger *zap.Logger) *SchemaDriftEngine {
+	return &SchemaDriftEngine{
+		CheckService:     checks,
+		BucketService:    buckets,
+		SchemaDocService: docs,
+		PointsWriter:     pw,
+		now:              time.Now,
+		logger:           logger.With(zap.String("service", "schema_checks")),
+		checks:           map[influxdb.ID]*schemaCheckState{},
+	}
+}
+
+// Run runs the schema checks that are due every interval until ctx is done.
+func (e *SchemaDriftEngine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.evaluate(ctx)
+		}
+	}
+}
+
+// evaluate runs the active schema checks that are due.
+func (e *SchemaDriftEngine) evaluate(ctx context.Context) {
+	typ := influxdb.SchemaCheckType
+	checks, _, err := e.CheckService.FindChecks(ctx, influxdb.CheckFilter{Type: &typ})
+	if err != nil {
+		e.logger.Error("Unable to find schema checks", zap.Error(err))
+		return
+	}
+
+	active := map[influxdb.ID]*influxdb.Check{}
+	for _, c := range checks {
+		if c.Status == influxdb.Active {
+			active[c.ID] = c
+		}
+	}
+	// Checks that are no longer active are forgotten, and start over once
+	// they are active again.
+	for id := range e.checks {
+		if _, ok := active[id]; !ok {
+			delete(e.checks, id)
+		}
+	}
+
+	now := e.now()
+	for id, c := range active {
+		st, ok := e.checks[id]
+		if !ok || st.check.BucketID != c.BucketID || st.check.Every != c.Every {
+			st = &schemaCheckState{}
+			e.checks[id] = st
+		}
+		st.check = c
+		if now.Before(st.next) {
+			continue
+		}
+
+		if err := e.evaluateCheck(ctx, st, now); err != nil {
+			e.logger.Error("Unable to run schema check", zap.String("check_id", id.String()), zap.Error(err))
+		}
+	}
+}
+
+// evaluateCheck runs the check of st at now, and schedules its next run.
+func (e *SchemaDriftEngine) evaluateCheck(ctx context.Context, st *schemaCheckState, now time.Time) error {
+	c := st.check
+
+	var every options.Duration
+	if err := every.Parse(c.Every); err != nil {
+		return err
+	}
+	next, err := every.Add(now)
+	if err != nil {
+		return err
+	}
+	st.next = next
+
+	doc, err := e.SchemaDocService.FindSchemaDoc(ctx, c.BucketID)
+	if err != nil {
+		return err
+	}
+
+	schemas := make(map[string]measurementSchema, len(doc.Measurements))
+	for _, m := range doc.Measurements {
+		schemas[m.Name] = newMeasurementSchema(m)
+	}
+	// The first time the check runs, the schema of the bucket is what its
+	// schema later drifts from.
+	if st.schemas == nil {
+		st.schemas = schemas
+		st.drifted = map[string]bool{}
+		return nil
+	}
+
+	var points models.Points
+	drifted := map[string]bool{}
+	for _, m := range doc.Measurements {
+		d := schemaDrift(st.schemas, m)
+		if d == "" {
+			continue
+		}
+		p, err := statusPoint(c, c.Level, m.Name, d, doc.GeneratedAt, now)
+		if err != nil {
+			return err
+		}
+		points = append(points, p)
+		drifted[m.Name] = true
+	}
+
+	recovered := make([]string, 0, len(st.drifted))
+	for name := range st.drifted {
+		if !drifted[name] {
+			recovered = append(recovered, name)
+		}
+	}
+	sort.Strings(recovered)
+	for _, name := range recovered {
+		p, err := statusPoint(c, influxdb.CheckLevelOK, name, "no drift", doc.GeneratedAt, now)
+		if err != nil {
+			return err
+		}
+		points = append(points, p)
+	}
+
+	if len(points) > 0 {
+		if err := e.writeStatuses(ctx, c.OrgID, points); err != nil {
+			return err
+		}
+	}
+
+	// Drift is reported once; the schema the measurements drifted to is
+	// what they are compared with the next time.
+	st.schemas = schemas
+	st.drifted = drifted
+	return nil
+}
+
+// writeStatuses writes status points to the monitoring bucket of the organization.
+func (e *SchemaDriftEngine) writeStatuses(ctx context.Context, orgID influxdb.ID, points models.Points) error {
+	name := influxdb.MonitoringBucketName
+	b, err := e.BucketService.FindBucket(ctx, influxdb.BucketFilter{OrganizationID: &orgID, Name: &name})
+	if err != nil {
+		return err
+	}
+
+	exploded, err := tsdb.ExplodePoints(orgID, b.ID, points)
+	if err != nil {
+		return err
+	}
+	return e.PointsWriter.WritePoints(ctx, exploded)
+}
+
+func newMeasurementSchema(m *influxdb.MeasurementDoc) measurementSchema {
+	s := measurementSchema{
+		tags:   make(map[string]bool, len(m.Tags)),
+		fields: make(map[string]influxdb.SchemaColumnDataType, len(m.Fields)),
+	}
+	for _, t := range m.Tags {
+		s.tags[t.Name] = true
+	}
+	for _, f := range m.Fields {
+		s.fields[f.Name] = f.DataType
+	}
+	return s
+}
+
+// schemaDrift describes how the measurement drifted from its schema in
+// schemas, or returns "" if it did not. Tags and fields that are gone are
+// not drift.
+func schemaDrift(schemas map[string]measurementSchema, m *influxdb.MeasurementDoc) string {
+	s, ok := schemas[m.Name]
+	if !ok {
+		return "new measurement"
+	}
+
+	var tags, fields, types []string
+	for _, t := range m.Tags {
+		if !s.tags[t.Name] {
+			tags = append(tags, t.Name)
+		}
+	}
+	for _, f := range m.Fields {
+		typ, ok := s.fields[f.Name]
+		switch {
+		case !ok:
+			fields = append(fields, f.Name)
+		case typ != "" && f.DataType != "" && typ != f.DataType:
+			types = append(types, fmt.Sprintf("%s from %s to %s", f.Name, typ, f.DataType))
+		}
+	}
+
+	var parts []string
+	if len(tags) > 0 {
+		parts = append(parts, "new tags: "+strings.Join(tags, ", "))
+	}
+	if len(fields) > 0 {
+		parts = append(parts, "new fields: "+strings.Join(fields, ", "))
+	}
+	if len(types) > 0 {
+		parts = append(parts, "changed field types: "+strings.Join(types, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// statusPoint returns the status of level the check writes for a
+// measurement, with the drift of the measurement as its value.
+func statusPoint(c *influxdb.Check, level influxdb.CheckLevel, measurement, drift string, source, now time.Time) (models.Point, error) {
+	tags := map[string]string{
+		"_check_id":           c.ID.String(),
+		"_check_name":         c.Name,
+		"_level":              string(level),
+		"_type":               string(c.Type),
+		"_source_measurement": measurement,
+	}
+	for _, t := range c.Tags {
+		tags[t.Key] = t.Value
+	}
+
+	columns := map[string]string{"_value": drift}
+	for k, v := range tags {
+		columns[k] = v
+	}
+	fields := map[string]interface{}{
+		"_message":          c.StatusMessage(columns),
+		"_source_timestamp": source.UnixNano(),
+		"_value":            drift,
+	}
+	return models.NewPoint(influxdb.CheckStatusMeasurement, models.NewTags(tags), fields, now)
+}
@@ -0,0 +1,144 @@
+package check
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestSchemaDriftEngine_evaluate(t *testing.T) {
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	checks := mock.NewCheckService()
+	checks.FindChecksFn = func(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]*influxdb.Check, int, error) {
+		if filter.Type == nil || *filter.Type != influxdb.SchemaCheckType {
+			t.Errorf("expected only schema checks to be found, got filter %+v", filter)
+		}
+		return []*influxdb.Check{
+			{
+				ID:       1,
+				OrgID:    10,
+				Name:     "telegraf schema",
+				Type:     influxdb.SchemaCheckType,
+				Status:   influxdb.Active,
+				BucketID: 3,
+				Every:    "5m",
+				Level:    influxdb.CheckLevelWarn,
+				Tags:     []influxdb.CheckTag{{Key: "team", Value: "ops"}},
+			},
+			{
+				ID:       2,
+				OrgID:    10,
+				Name:     "inactive",
+				Type:     influxdb.SchemaCheckType,
+				Status:   influxdb.Inactive,
+				BucketID: 4,
+				Every:    "5m",
+				Level:    influxdb.CheckLevelCrit,
+			},
+		}, 2, nil
+	}
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(ctx context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		if *filter.OrganizationID != 10 || *filter.Name != influxdb.MonitoringBucketName {
+			t.Errorf("expected the monitoring bucket of the org, got filter %+v", filter)
+		}
+		return &influxdb.Bucket{ID: 20, OrgID: 10, Name: influxdb.MonitoringBucketName}, nil
+	}
+
+	measurement := func(name string, tags []string, fields ...influxdb.SchemaDocColumn) *influxdb.MeasurementDoc {
+		m := &influxdb.MeasurementDoc{Name: name, Fields: fields}
+		for _, k := range tags {
+			m.Tags = append(m.Tags, influxdb.SchemaDocColumn{Name: k})
+		}
+		return m
+	}
+	field := func(name string, typ influxdb.SchemaColumnDataType) influxdb.SchemaDocColumn {
+		return influxdb.SchemaDocColumn{Name: name, DataType: typ}
+	}
+	var doc *influxdb.SchemaDoc
+	docs := mock.NewSchemaDocService()
+	docs.FindSchemaDocFn = func(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDoc, error) {
+		if bucketID != 3 {
+			t.Errorf("expected only the bucket of the active check to be read, got %s", bucketID)
+		}
+		return doc, nil
+	}
+
+	pw := &mock.PointsWriter{}
+	e := NewSchemaDriftEngine(checks, buckets, docs, pw, zap.NewNop())
+
+	type status struct {
+		Level, Measurement, Team, Message string
+	}
+	// run runs the engine at minutes past now with the measurements in the
+	// doc, and returns the statuses it wrote.
+	run := func(minutes int, ms ...*influxdb.MeasurementDoc) []status {
+		e.now = func() time.Time { return now.Add(time.Duration(minutes) * time.Minute) }
+		doc = &influxdb.SchemaDoc{OrgID: 10, BucketID: 3, Measurements: ms, GeneratedAt: e.now()}
+		pw.Points = nil
+		e.evaluate(context.Background())
+
+		var statuses []status
+		for _, p := range pw.Points {
+			fields, err := p.Fields()
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg, ok := fields["_message"]
+			if !ok {
+				continue
+			}
+			tags := p.Tags()
+			if got := string(tags.Get([]byte("_check_id"))); got != "0000000000000001" {
+				t.Errorf("got status of check %s", got)
+			}
+			statuses = append(statuses, status{
+				Level:       string(tags.Get([]byte("_level"))),
+				Measurement: string(tags.Get([]byte("_source_measurement"))),
+				Team:        string(tags.Get([]byte("team"))),
+				Message:     msg.(string),
+			})
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Measurement < statuses[j].Measurement })
+		return statuses
+	}
+
+	cpu := measurement("cpu", []string{"host"}, field("usage_user", ""))
+	disk := measurement("disk", []string{"host"}, field("free", influxdb.SchemaColumnDataTypeInteger))
+	if got := run(0, cpu, disk); len(got) != 0 {
+		t.Fatalf("expected the first run to only see the schema, got %+v", got)
+	}
+
+	drifted := []*influxdb.MeasurementDoc{
+		measurement("cpu", []string{"host", "pod"}, field("usage_user", ""), field("usage_system", "")),
+		measurement("disk", []string{"host"}, field("free", influxdb.SchemaColumnDataTypeString)),
+		measurement("mem", []string{"host"}, field("used", "")),
+	}
+	if got := run(1, drifted...); len(got) != 0 {
+		t.Fatalf("expected the check not to run before it is due, got %+v", got)
+	}
+	if diff := cmp.Diff(run(5, drifted...), []status{
+		{Level: "WARN", Measurement: "cpu", Team: "ops", Message: "Check: telegraf schema is: WARN, cpu: new tags: pod; new fields: usage_system"},
+		{Level: "WARN", Measurement: "disk", Team: "ops", Message: "Check: telegraf schema is: WARN, disk: changed field types: free from integer to string"},
+		{Level: "WARN", Measurement: "mem", Team: "ops", Message: "Check: telegraf schema is: WARN, mem: new measurement"},
+	}); diff != "" {
+		t.Errorf("unexpected drift statuses -got/+want\n%s", diff)
+	}
+
+	if diff := cmp.Diff(run(10, append(drifted, measurement("net", nil))...), []status{
+		{Level: "OK", Measurement: "cpu", Team: "ops", Message: "Check: telegraf schema is: OK, cpu: no drift"},
+		{Level: "OK", Measurement: "disk", Team: "ops", Message: "Check: telegraf schema is: OK, disk: no drift"},
+		{Level: "OK", Measurement: "mem", Team: "ops", Message: "Check: telegraf schema is: OK, mem: no drift"},
+		{Level: "WARN", Measurement: "net", Team: "ops", Message: "Check: telegraf schema is: WARN, net: new measurement"},
+	}); diff != "" {
+		t.Errorf("unexpected recovery statuses -got/+want\n%s", diff)
+	}
+}
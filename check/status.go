@@ -0,0 +1,196 @@
+// Package check reads the statuses of checks, and runs the checks that
+// don't run as tasks.
+package check
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	pctx "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+)
+
+var _ influxdb.CheckStatusService = (*StatusService)(nil)
+
+// StatusService reads the last statuses of checks from the monitoring bucket
+// of their organization.
+type StatusService struct {
+	BucketService influxdb.BucketService
+	QueryService  query.QueryService
+}
+
+// NewStatusService returns a StatusService.
+func NewStatusService(bs influxdb.BucketService, qs query.QueryService) *StatusService {
+	return &StatusService{
+		BucketService: bs,
+		QueryService:  qs,
+	}
+}
+
+// FindLastCheckStatuses returns the latest status of each check of the
+// organization written within the retention of the monitoring bucket. The
+// query runs on behalf of the authorizer on the context.
+func (s *StatusService) FindLastCheckStatuses(ctx context.Context, orgID influxdb.ID) (map[influxdb.ID]*influxdb.CheckStatus, error) {
+	statuses := map[influxdb.ID]*influxdb.CheckStatus{}
+	err := s.query(ctx, orgID, func(bucketID influxdb.ID) string {
+		return fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: -%dms)
+	|> filter(fn: (r) => r._measurement == %q and r._field == "_message")
+	|> group(columns: ["_check_id"])
+	|> sort(columns: ["_time"])
+	|> last()`, bucketID.String(), influxdb.MonitoringBucketRetention/time.Millisecond, influxdb.CheckStatusMeasurement)
+	}, func(st *influxdb.CheckStatus) {
+		if st.CheckID.Valid() {
+			statuses[st.CheckID] = &influxdb.CheckStatus{
+				Level:   st.Level,
+				Message: st.Message,
+				Time:    st.Time,
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// FindCheckStatuses returns the statuses of the checks of the organization
+// that match the filter, oldest first. The query runs on behalf of the
+// authorizer on the context.
+func (s *StatusService) FindCheckStatuses(ctx context.Context, filter influxdb.CheckStatusFilter) ([]*influxdb.CheckStatus, error) {
+	statuses := []*influxdb.CheckStatus{}
+	err := s.query(ctx, filter.OrgID, func(bucketID influxdb.ID) string {
+		script := fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: %s, stop: %s)
+	|> filter(fn: (r) => r._measurement == %q and r._field == "_message")`,
+			bucketID.String(), filter.Start.UTC().Format(time.RFC3339Nano), filter.Stop.UTC().Format(time.RFC3339Nano), influxdb.CheckStatusMeasurement)
+		if filter.Last {
+			script += "\n\t|> last()"
+		}
+		return script
+	}, func(st *influxdb.CheckStatus) {
+		if st.CheckID.Valid() {
+			statuses = append(statuses, st)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Time.Before(statuses[j].Time)
+	})
+	if filter.Last {
+		statuses = lastOfSeries(statuses)
+	}
+	return statuses, nil
+}
+
+// lastOfSeries returns the latest of the sorted statuses of each series. The
+// level of a status is a tag, so each level of a series is stored apart.
+func lastOfSeries(statuses []*influxdb.CheckStatus) []*influxdb.CheckStatus {
+	last := map[string]int{}
+	for i, st := range statuses {
+		last[st.SeriesKey()] = i
+	}
+
+	ss := make([]*influxdb.CheckStatus, 0, len(last))
+	for i, st := range statuses {
+		if last[st.SeriesKey()] == i {
+			ss = append(ss, st)
+		}
+	}
+	return ss
+}
+
+// query runs the script returned for the monitoring bucket of the
+// organization and calls fn with each status it returns. There are no
+// statuses if the organization has no monitoring bucket yet.
+func (s *StatusService) query(ctx context.Context, orgID influxdb.ID, script func(bucketID influxdb.ID) string, fn func(*influxdb.CheckStatus)) error {
+	name := influxdb.MonitoringBucketName
+	b, err := s.BucketService.FindBucket(ctx, influxdb.BucketFilter{OrganizationID: &orgID, Name: &name})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		// No check of the organization has been created yet.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	auth, err := pctx.GetAuthorization(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	request := &query.Request{Authorization: auth, OrganizationID: orgID, Compiler: lang.FluxCompiler{Query: script(b.ID)}}
+	ittr, err := s.QueryService.Query(ctx, request)
+	if err != nil {
+		return err
+	}
+	defer ittr.Release()
+
+	for ittr.More() {
+		if err := ittr.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(cr flux.ColReader) error {
+				readStatuses(cr, fn)
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+	}
+	return ittr.Err()
+}
+
+// statusColumns are the columns of statuses that are not tags of their series.
+var statusColumns = map[string]bool{
+	"_start":       true,
+	"_stop":        true,
+	"_time":        true,
+	"_measurement": true,
+	"_field":       true,
+	"_value":       true,
+	"_check_id":    true,
+	"_check_name":  true,
+	"_level":       true,
+	"_type":        true,
+}
+
+func readStatuses(cr flux.ColReader, fn func(*influxdb.CheckStatus)) {
+	for i := 0; i < cr.Len(); i++ {
+		st := &influxdb.CheckStatus{}
+		for j, col := range cr.Cols() {
+			switch col.Label {
+			case "_check_id":
+				id, err := influxdb.IDFromString(cr.Strings(j).ValueString(i))
+				if err != nil {
+					continue
+				}
+				st.CheckID = *id
+			case "_check_name":
+				st.CheckName = cr.Strings(j).ValueString(i)
+			case "_level":
+				st.Level = influxdb.CheckLevel(cr.Strings(j).ValueString(i))
+			case "_value":
+				if col.Type == flux.TString {
+					st.Message = cr.Strings(j).ValueString(i)
+				}
+			case "_time":
+				st.Time = time.Unix(0, cr.Times(j).Value(i)).UTC()
+			default:
+				if col.Type == flux.TString && !statusColumns[col.Label] && cr.Key().HasCol(col.Label) {
+					if st.Tags == nil {
+						st.Tags = map[string]string{}
+					}
+					st.Tags[col.Label] = cr.Strings(j).ValueString(i)
+				}
+			}
+		}
+		fn(st)
+	}
+}
@@ -0,0 +1,123 @@
+package check_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/check"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+)
+
+func TestStatusService_FindCheckStatuses(t *testing.T) {
+	t0 := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) execute.Time {
+		return execute.Time(t0.Add(time.Duration(minutes) * time.Minute).UnixNano())
+	}
+	// Each level of a series is a table of its own.
+	table := func(level, host string, minutes ...int) *executetest.Table {
+		tbl := &executetest.Table{
+			KeyCols: []string{"_start", "_stop", "_measurement", "_field", "_check_id", "_check_name", "_level", "_type", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_start", Type: flux.TTime},
+				{Label: "_stop", Type: flux.TTime},
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_check_id", Type: flux.TString},
+				{Label: "_check_name", Type: flux.TString},
+				{Label: "_level", Type: flux.TString},
+				{Label: "_type", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+			},
+		}
+		for _, m := range minutes {
+			tbl.Data = append(tbl.Data, []interface{}{at(0), at(10), at(m), "cpu is " + level, "_message", "statuses", "0000000000000002", "cpu", level, "threshold", host})
+		}
+		return tbl
+	}
+
+	var gotReq *query.Request
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			gotReq = req
+			return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{
+					table("CRIT", "a", 3),
+					table("OK", "a", 1, 5),
+					table("OK", "b", 2),
+				},
+			}}), nil
+		},
+	}
+
+	bs := mock.NewBucketService()
+	bs.FindBucketFn = func(ctx context.Context, filter influxdb.BucketFilter) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{ID: 5, OrgID: *filter.OrganizationID, Name: *filter.Name}, nil
+	}
+
+	s := check.NewStatusService(bs, qs)
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{OrgID: 1})
+	filter := influxdb.CheckStatusFilter{OrgID: 1, Start: t0, Stop: t0.Add(10 * time.Minute)}
+
+	statuses, err := s.FindCheckStatuses(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	script := gotReq.Compiler.(lang.FluxCompiler).Query
+	for _, want := range []string{`from(bucketID: "0000000000000005")`, `range(start: 2019-07-01T12:00:00Z, stop: 2019-07-01T12:10:00Z)`, `r._measurement == "statuses"`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected the query to contain %s, got %s", want, script)
+		}
+	}
+
+	status := func(level, host string, minutes int) *influxdb.CheckStatus {
+		return &influxdb.CheckStatus{
+			CheckID:   2,
+			CheckName: "cpu",
+			Level:     influxdb.CheckLevel(level),
+			Message:   "cpu is " + level,
+			Time:      t0.Add(time.Duration(minutes) * time.Minute),
+			Tags:      map[string]string{"host": host},
+		}
+	}
+	want := []*influxdb.CheckStatus{
+		status("OK", "a", 1),
+		status("OK", "b", 2),
+		status("CRIT", "a", 3),
+		status("OK", "a", 5),
+	}
+	if diff := cmp.Diff(statuses, want); diff != "" {
+		t.Errorf("statuses are different -got/+want\ndiff %s", diff)
+	}
+
+	filter.Last = true
+	statuses, err = s.FindCheckStatuses(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(gotReq.Compiler.(lang.FluxCompiler).Query, "last()") {
+		t.Errorf("expected the query to select the last statuses, got %s", gotReq.Compiler.(lang.FluxCompiler).Query)
+	}
+	// The last status of a series is the latest of any of its levels.
+	want = []*influxdb.CheckStatus{
+		status("OK", "b", 2),
+		status("OK", "a", 5),
+	}
+	if diff := cmp.Diff(statuses, want); diff != "" {
+		t.Errorf("last statuses are different -got/+want\ndiff %s", diff)
+	}
+}
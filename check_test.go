@@ -0,0 +1,217 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+func TestCheck_GenerateFlux(t *testing.T) {
+	tests := []struct {
+		name  string
+		check influxdb.Check
+		flux  string
+	}{
+		{
+			name: "threshold",
+			check: influxdb.Check{
+				ID:     1,
+				OrgID:  2,
+				Name:   `cpu "usage"`,
+				Type:   influxdb.ThresholdCheckType,
+				Status: influxdb.Active,
+				Query: influxdb.DashboardQuery{
+					Text: `import "strings"
+from(bucket: "telegraf") |> range(start: -1m) |> filter(fn: (r) => r._field == "usage_user")`,
+				},
+				Every:  "1m",
+				Offset: "10s",
+				Tags:   []influxdb.CheckTag{{Key: "team", Value: "ops"}},
+				Thresholds: []influxdb.Threshold{
+					{Type: influxdb.GreaterThreshold, Level: influxdb.CheckLevelWarn, Value: 80},
+					{Type: influxdb.RangeThreshold, Level: influxdb.CheckLevelCrit, Min: 90, Max: 100.5, Within: true},
+				},
+				StatusMessageTemplate: "${host} is ${ _level }",
+			},
+			flux: `import "strings"
+
+option task = {name: "cpu \"usage\"", every: 1m, offset: 10s}
+
+data = from(bucket: "telegraf")
+	|> range(start: -1m)
+	|> filter(fn: (r) =>
+		(r._field == "usage_user"))
+
+data
+	|> rename(columns: {_measurement: "_source_measurement", _field: "_source_field"})
+	|> map(fn: (r) => ({r with
+		_measurement: "statuses",
+		_check_id: "0000000000000001",
+		_check_name: "cpu \"usage\"",
+		_type: "threshold",
+		team: "ops",
+		_level: if (float(v: r._value) > 90.0 and float(v: r._value) < 100.5) then "CRIT" else if float(v: r._value) > 80.0 then "WARN" else "OK",
+		_source_timestamp: int(v: r._time),
+	}))
+	|> map(fn: (r) => ({r with _message: string(v: r.host) + " is " + string(v: r._level), _time: now()}))
+	|> to(bucket: "_monitoring", orgID: "0000000000000002", fieldFn: (r) => ({_message: r._message, _source_timestamp: r._source_timestamp, _value: r._value}))
+`,
+		},
+		{
+			name: "deadman",
+			check: influxdb.Check{
+				ID:        1,
+				OrgID:     2,
+				Name:      "heartbeat",
+				Type:      influxdb.DeadmanCheckType,
+				Status:    influxdb.Active,
+				Query:     influxdb.DashboardQuery{Text: `from(bucket: "telegraf") |> range(start: -1h)`},
+				Every:     "5m",
+				TimeSince: 90,
+				Level:     influxdb.CheckLevelCrit,
+			},
+			flux: `option task = {name: "heartbeat", every: 5m}
+
+data = from(bucket: "telegraf")
+	|> range(start: -1h)
+
+data
+	|> last()
+	|> rename(columns: {_measurement: "_source_measurement", _field: "_source_field"})
+	|> map(fn: (r) => ({r with
+		_measurement: "statuses",
+		_check_id: "0000000000000001",
+		_check_name: "heartbeat",
+		_type: "deadman",
+		_level: if int(v: now()) - int(v: r._time) > 90000000000 then "CRIT" else "OK",
+		_source_timestamp: int(v: r._time),
+	}))
+	|> map(fn: (r) => ({r with _message: "Check: " + string(v: r._check_name) + " is: " + string(v: r._level), _time: now()}))
+	|> to(bucket: "_monitoring", orgID: "0000000000000002", fieldFn: (r) => ({_message: r._message, _source_timestamp: r._source_timestamp, _value: r._value}))
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flux, err := tt.check.GenerateFlux()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if flux != tt.flux {
+				t.Errorf("got flux\n%s\nwant\n%s", flux, tt.flux)
+			}
+
+			opts, err := options.FromScript(flux)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts.Name != tt.check.Name {
+				t.Errorf("got task name %q, want %q", opts.Name, tt.check.Name)
+			}
+		})
+	}
+}
+
+func TestCheck_GenerateFluxErrors(t *testing.T) {
+	valid := func() influxdb.Check {
+		return influxdb.Check{
+			ID:         1,
+			OrgID:      2,
+			Name:       "cpu",
+			Type:       influxdb.ThresholdCheckType,
+			Status:     influxdb.Active,
+			Query:      influxdb.DashboardQuery{Text: `from(bucket: "telegraf") |> range(start: -1m)`},
+			Every:      "1m",
+			Thresholds: []influxdb.Threshold{{Type: influxdb.GreaterThreshold, Level: influxdb.CheckLevelCrit, Value: 90}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *influxdb.Check)
+	}{
+		{name: "no thresholds", modify: func(c *influxdb.Check) { c.Thresholds = nil }},
+		{name: "invalid level", modify: func(c *influxdb.Check) { c.Thresholds[0].Level = "bad" }},
+		{name: "invalid every", modify: func(c *influxdb.Check) { c.Every = "1m}, foo: {" }},
+		{name: "invalid tag key", modify: func(c *influxdb.Check) { c.Tags = []influxdb.CheckTag{{Key: "my-tag"}} }},
+		{name: "several statements", modify: func(c *influxdb.Check) { c.Query.Text = "a = 1\nfrom(bucket: \"b\")" }},
+		{name: "invalid query", modify: func(c *influxdb.Check) { c.Query.Text = "from(bucket: " }},
+		{name: "deadman without timeSince", modify: func(c *influxdb.Check) {
+			c.Type = influxdb.DeadmanCheckType
+			c.Level = influxdb.CheckLevelCrit
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(&c)
+			if _, err := c.GenerateFlux(); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected the check to be invalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheck_ValidSchema(t *testing.T) {
+	valid := func() influxdb.Check {
+		return influxdb.Check{
+			OrgID:    2,
+			Name:     "telegraf schema",
+			Type:     influxdb.SchemaCheckType,
+			Status:   influxdb.Active,
+			BucketID: 3,
+			Every:    "5m",
+			Level:    influxdb.CheckLevelWarn,
+		}
+	}
+
+	c := valid()
+	if err := c.Valid(); err != nil {
+		t.Fatalf("expected a schema check without a query to be valid, got %v", err)
+	}
+	if c.RunsAsTask() {
+		t.Error("expected a schema check not to run as a task")
+	}
+	if _, err := c.GenerateFlux(); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected a schema check to generate no flux, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *influxdb.Check)
+	}{
+		{name: "no bucket", modify: func(c *influxdb.Check) { c.BucketID = 0 }},
+		{name: "no level", modify: func(c *influxdb.Check) { c.Level = "" }},
+		{name: "invalid every", modify: func(c *influxdb.Check) { c.Every = "often" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.modify(&c)
+			if err := c.Valid(); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected the check to be invalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheck_StatusMessage(t *testing.T) {
+	c := influxdb.Check{Name: "telegraf schema", Type: influxdb.SchemaCheckType}
+	columns := map[string]string{
+		"_check_name":         "telegraf schema",
+		"_level":              "WARN",
+		"_source_measurement": "cpu",
+		"_value":              "new tags: host",
+	}
+	if got, want := c.StatusMessage(columns), "Check: telegraf schema is: WARN, cpu: new tags: host"; got != want {
+		t.Errorf("got message %q, want %q", got, want)
+	}
+
+	c.StatusMessageTemplate = "${ _source_measurement } drifted in ${region}"
+	if got, want := c.StatusMessage(columns), "cpu drifted in "; got != want {
+		t.Errorf("got message %q, want %q", got, want)
+	}
+}
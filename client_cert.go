@@ -0,0 +1,70 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Identities of a client certificate that a ClientCertRule matches.
+const (
+	// ClientCertCommonName is the common name of the subject of the certificate.
+	ClientCertCommonName = "cn"
+	// ClientCertDNSName is any DNS name of the subject alternative names of the certificate.
+	ClientCertDNSName = "dns"
+	// ClientCertEmailAddress is any email address of the subject alternative names of the certificate.
+	ClientCertEmailAddress = "email"
+	// ClientCertURI is any URI of the subject alternative names of the certificate.
+	ClientCertURI = "uri"
+)
+
+// ClientCertRule maps the verified client certificates with an identity that
+// matches the rule to a user, or to an authorization whose token the client
+// does not need to present.
+type ClientCertRule struct {
+	// Identity is the identity of the certificate the rule matches, such as cn or dns.
+	Identity string `json:"identity"`
+	// Match is a regular expression the identity must match in full.
+	Match string `json:"match"`
+	// User is the name of the user the certificate authenticates as, with the
+	// permissions of the user. It may refer to the submatches of Match, such as $1.
+	User string `json:"user,omitempty"`
+	// AuthorizationID is the authorization the certificate authenticates as.
+	AuthorizationID *ID `json:"authorizationID,omitempty"`
+}
+
+// Valid returns an error if the rule does not match a known identity with a
+// valid regular expression, or does not map to exactly one user or authorization.
+func (r ClientCertRule) Valid() error {
+	switch r.Identity {
+	case ClientCertCommonName, ClientCertDNSName, ClientCertEmailAddress, ClientCertURI:
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg: fmt.Sprintf("client certificate identity %q must be one of %s, %s, %s or %s",
+				r.Identity, ClientCertCommonName, ClientCertDNSName, ClientCertEmailAddress, ClientCertURI),
+		}
+	}
+
+	if _, err := regexp.Compile(r.Match); err != nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid client certificate match %q", r.Match),
+			Err:  err,
+		}
+	}
+
+	if (r.User == "") == (r.AuthorizationID == nil) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "client certificate rule must map to either a user or an authorizationID",
+		}
+	}
+	return nil
+}
+
+// UserPermissionService returns the maximal privileges of users, which are the
+// permissions of their resources and of their authorizations.
+type UserPermissionService interface {
+	FindUserPermissions(ctx context.Context, userID ID) ([]Permission, error)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	platform "github.com/influxdata/influxdb"
@@ -33,6 +34,8 @@ type AuthorizationCreateFlags struct {
 	writeBucketPermissions []string
 	readBucketPermissions  []string
 
+	writeMeasurements []string
+
 	writeTasksPermission bool
 	readTasksPermission  bool
 
@@ -69,6 +72,8 @@ func init() {
 	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.writeBucketPermissions, "write-bucket", "", []string{}, "The bucket id")
 	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.readBucketPermissions, "read-bucket", "", []string{}, "The bucket id")
 
+	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.writeMeasurements, "write-measurement", "", []string{}, "Restricts the write-buckets and write-bucket permissions to writing the measurement")
+
 	authorizationCreateCmd.Flags().BoolVarP(&authorizationCreateFlags.writeTasksPermission, "write-tasks", "", false, "Grants the permission to create tasks")
 	authorizationCreateCmd.Flags().BoolVarP(&authorizationCreateFlags.readTasksPermission, "read-tasks", "", false, "Grants the permission to read tasks")
 
@@ -91,6 +96,11 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	writeMeasurements := authorizationCreateFlags.writeMeasurements
+	if len(writeMeasurements) > 0 && !authorizationCreateFlags.writeBucketsPermission && len(authorizationCreateFlags.writeBucketPermissions) == 0 {
+		return fmt.Errorf("--write-measurement requires --write-buckets or --write-bucket")
+	}
+
 	ctx := context.Background()
 	orgFilter := platform.OrganizationFilter{Name: &authorizationCreateFlags.org}
 	o, err := orgSvc.FindOrganization(ctx, orgFilter)
@@ -119,6 +129,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		p.Measurements = writeMeasurements
 		permissions = append(permissions, *p)
 	}
 
@@ -140,6 +151,7 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return err
 		}
+		p.Measurements = writeMeasurements
 
 		permissions = append(permissions, *p)
 	}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/bench"
+	"github.com/influxdata/influxdb/cmd/influx/internal"
+	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/kit/signals"
+	"github.com/influxdata/influxdb/query"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Run a synthetic workload against InfluxDB",
+	Long: `Write synthetic points to a bucket and run a mix of queries against it for
+a duration, then report the latencies of the writes and queries and the
+resource usage of the instance, for capacity testing.
+
+The points are written to the bench measurement of the bucket, which should be
+a bucket for testing only.`,
+	RunE: wrapCheckSetup(benchF),
+}
+
+var benchFlags struct {
+	orgID    string
+	org      string
+	bucketID string
+	bucket   string
+
+	series          int
+	pointsPerSecond int
+	batchSize       int
+	writers         int
+
+	queries          []string
+	queriesPerSecond int
+	queriers         int
+
+	duration  time.Duration
+	resources bool
+}
+
+func init() {
+	benchCmd.Flags().StringVar(&benchFlags.orgID, "org-id", "", "The ID of the organization that owns the bucket")
+	benchCmd.Flags().StringVarP(&benchFlags.org, "org", "o", "", "The name of the organization that owns the bucket")
+	benchCmd.Flags().StringVar(&benchFlags.bucketID, "bucket-id", "", "The ID of the bucket to write to and query")
+	benchCmd.Flags().StringVarP(&benchFlags.bucket, "bucket", "b", "", "The name of the bucket to write to and query")
+
+	benchCmd.Flags().IntVar(&benchFlags.series, "series", bench.DefaultSeries, "Number of series written to")
+	benchCmd.Flags().IntVar(&benchFlags.pointsPerSecond, "points-per-second", 10000, "Points written per second; 0 writes as fast as possible")
+	benchCmd.Flags().IntVar(&benchFlags.batchSize, "batch-size", bench.DefaultBatchSize, "Points per write")
+	benchCmd.Flags().IntVar(&benchFlags.writers, "writers", bench.DefaultWriters, "Number of concurrent writes")
+
+	benchCmd.Flags().StringArrayVar(&benchFlags.queries, "query", nil, "Flux query to run in turn with the other queries; defaults to a mix of queries of the points written")
+	benchCmd.Flags().IntVar(&benchFlags.queriesPerSecond, "queries-per-second", 1, "Queries run per second; 0 runs no queries")
+	benchCmd.Flags().IntVar(&benchFlags.queriers, "queriers", 1, "Number of concurrent queries")
+
+	benchCmd.Flags().DurationVar(&benchFlags.duration, "duration", bench.DefaultDuration, "How long the workload runs for")
+	benchCmd.Flags().BoolVar(&benchFlags.resources, "resources", true, "Report the resource usage of the instance from its /metrics endpoint")
+}
+
+func benchF(cmd *cobra.Command, args []string) error {
+	if flags.local {
+		return fmt.Errorf("local flag not supported for bench command")
+	}
+	if benchFlags.org != "" && benchFlags.orgID != "" {
+		return fmt.Errorf("please specify one of org or org-id")
+	}
+	if (benchFlags.bucket != "") == (benchFlags.bucketID != "") {
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+
+	ctx := signals.WithStandardSignals(context.Background())
+
+	b, err := findBenchBucket(ctx)
+	if err != nil {
+		return err
+	}
+
+	c := bench.Config{
+		OrgID:           b.OrgID,
+		BucketID:        b.ID,
+		Series:          benchFlags.series,
+		PointsPerSecond: benchFlags.pointsPerSecond,
+		BatchSize:       benchFlags.batchSize,
+		Writers:         benchFlags.writers,
+		Queriers:        benchFlags.queriers,
+		Duration:        benchFlags.duration,
+	}
+	t := bench.Target{
+		WriteService: &http.WriteService{
+			Addr:  flags.host,
+			Token: flags.token,
+		},
+	}
+	if benchFlags.queriesPerSecond > 0 {
+		c.Queries = benchFlags.queries
+		if len(c.Queries) == 0 {
+			c.Queries = defaultBenchQueries(b.Name)
+		}
+		c.QueriesPerSecond = benchFlags.queriesPerSecond
+		t.Querier = &benchQuerier{
+			svc: &http.FluxService{
+				Addr:  flags.host,
+				Token: flags.token,
+			},
+		}
+	}
+	if benchFlags.resources {
+		t.Sampler = &bench.MetricsSampler{Addr: flags.host}
+	}
+
+	fmt.Fprintf(os.Stderr, "Running workload against bucket %q for %s\n", b.Name, c.Duration)
+	rep, err := bench.Run(ctx, c, t)
+	if err != nil {
+		return fmt.Errorf("failed to run workload: %v", err)
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders("Requests", "Count", "Errors", "Min", "Mean", "P50", "P90", "P99", "Max")
+	for _, r := range []struct {
+		name string
+		l    bench.Latencies
+	}{{"writes", rep.Writes}, {"queries", rep.Queries}} {
+		w.Write(map[string]interface{}{
+			"Requests": r.name,
+			"Count":    r.l.Count,
+			"Errors":   r.l.Errors,
+			"Min":      r.l.Min,
+			"Mean":     r.l.Mean,
+			"P50":      r.l.P50,
+			"P90":      r.l.P90,
+			"P99":      r.l.P99,
+			"Max":      r.l.Max,
+		})
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d points written in %s (%.0f points/s)\n", rep.Points, rep.Duration.Round(time.Millisecond), rep.PointsPerSecond)
+	if u := rep.Resources; u != nil {
+		fmt.Printf("cpu %.2fs, max resident memory %d bytes, max heap in use %d bytes, max goroutines %d\n",
+			u.CPUSeconds, int64(u.MaxResidentMemoryBytes), int64(u.MaxHeapInuseBytes), int64(u.MaxGoroutines))
+	}
+	for _, e := range rep.Errors {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", e)
+	}
+	return nil
+}
+
+// findBenchBucket returns the bucket of the flags of the bench command.
+func findBenchBucket(ctx context.Context) (*platform.Bucket, error) {
+	bs := &http.BucketService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var filter platform.BucketFilter
+	if benchFlags.bucketID != "" {
+		id, err := platform.IDFromString(benchFlags.bucketID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode bucket-id: %v", err)
+		}
+		filter.ID = id
+	}
+	if benchFlags.bucket != "" {
+		filter.Name = &benchFlags.bucket
+	}
+	if benchFlags.orgID != "" {
+		id, err := platform.IDFromString(benchFlags.orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode org-id: %v", err)
+		}
+		filter.OrganizationID = id
+	}
+	if benchFlags.org != "" {
+		filter.Org = &benchFlags.org
+	}
+
+	b, err := bs.FindBucket(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve bucket: %v", err)
+	}
+	return b, nil
+}
+
+// defaultBenchQueries returns a mix of queries of the points written to a bucket:
+// one of a single series, one of the last points of all series, and one that
+// aggregates all series.
+func defaultBenchQueries(bucket string) []string {
+	from := fmt.Sprintf(`from(bucket: %q) |> range(start: -1m) |> filter(fn: (r) => r._measurement == %q)`, bucket, bench.DefaultMeasurement)
+	return []string{
+		from + ` |> filter(fn: (r) => r.series == "s0")`,
+		from + ` |> last()`,
+		from + ` |> group() |> aggregateWindow(every: 10s, fn: mean)`,
+	}
+}
+
+// benchQuerier runs the queries of a workload and discards their results.
+type benchQuerier struct {
+	svc *http.FluxService
+}
+
+func (q *benchQuerier) Query(ctx context.Context, orgID platform.ID, script string) error {
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			OrganizationID: orgID,
+			Compiler:       lang.FluxCompiler{Query: script},
+		},
+		Dialect: csv.DefaultDialect(),
+	}
+	_, err := q.svc.Query(ctx, ioutil.Discard, req)
+	return err
+}
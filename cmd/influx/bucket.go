@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	platform "github.com/influxdata/influxdb"
@@ -25,9 +26,12 @@ func bucketF(cmd *cobra.Command, args []string) {
 
 // BucketCreateFlags define the Create Command
 type BucketCreateFlags struct {
-	name      string
-	orgID     string
-	retention time.Duration
+	name          string
+	orgID         string
+	retention     time.Duration
+	reorderWindow time.Duration
+	quota         platform.BucketQuota
+	schemaType    string
 }
 
 var bucketCreateFlags BucketCreateFlags
@@ -42,6 +46,9 @@ func init() {
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.name, "name", "n", "", "Name of bucket that will be created")
 	bucketCreateCmd.Flags().DurationVarP(&bucketCreateFlags.retention, "retention", "r", 0, "Duration in nanoseconds data will live in bucket")
 	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.orgID, "org-id", "", "", "The ID of the organization that owns the bucket")
+	bucketCreateCmd.Flags().DurationVarP(&bucketCreateFlags.reorderWindow, "reorder-window", "", 0, "Duration written points are buffered to store out-of-order points in order")
+	bucketQuotaFlags(bucketCreateCmd, &bucketCreateFlags.quota)
+	bucketCreateCmd.Flags().StringVarP(&bucketCreateFlags.schemaType, "schema-type", "", "", "How the schemas of measurements are defined: implicit by the points written, or explicit by measurement schemas")
 	bucketCreateCmd.MarkFlagRequired("name")
 
 	bucketCmd.AddCommand(bucketCreateCmd)
@@ -70,6 +77,9 @@ func bucketCreateF(cmd *cobra.Command, args []string) error {
 	b := &platform.Bucket{
 		Name:            bucketCreateFlags.name,
 		RetentionPeriod: bucketCreateFlags.retention,
+		ReorderWindow:   bucketCreateFlags.reorderWindow,
+		Quota:           bucketCreateFlags.quota,
+		SchemaType:      platform.BucketSchemaType(bucketCreateFlags.schemaType),
 	}
 
 	if bucketCreateFlags.orgID != "" {
@@ -103,6 +113,13 @@ func bucketCreateF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// bucketQuotaFlags adds the flags setting the limits of a bucket quota to cmd.
+func bucketQuotaFlags(cmd *cobra.Command, q *platform.BucketQuota) {
+	cmd.Flags().Int64VarP(&q.MaxPointsPerSecond, "max-points-per-second", "", 0, "Maximum points per second written to the bucket; 0 is no limit")
+	cmd.Flags().Int64VarP(&q.MaxSeries, "max-series", "", 0, "Maximum series in the bucket, past which writes of new series are rejected; 0 is no limit")
+	cmd.Flags().Int64VarP(&q.MaxBytesPerDay, "max-bytes-per-day", "", 0, "Maximum bytes of line protocol written to the bucket per UTC day; 0 is no limit")
+}
+
 // BucketFindFlags define the Find Command
 type BucketFindFlags struct {
 	name  string
@@ -191,9 +208,11 @@ func bucketFindF(cmd *cobra.Command, args []string) error {
 
 // BucketUpdateFlags define the Update Command
 type BucketUpdateFlags struct {
-	id        string
-	name      string
-	retention time.Duration
+	id            string
+	name          string
+	retention     time.Duration
+	reorderWindow time.Duration
+	quota         platform.BucketQuota
 }
 
 var bucketUpdateFlags BucketUpdateFlags
@@ -208,6 +227,8 @@ func init() {
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.id, "id", "i", "", "The bucket ID (required)")
 	bucketUpdateCmd.Flags().StringVarP(&bucketUpdateFlags.name, "name", "n", "", "New bucket name")
 	bucketUpdateCmd.Flags().DurationVarP(&bucketUpdateFlags.retention, "retention", "r", 0, "New duration data will live in bucket")
+	bucketUpdateCmd.Flags().DurationVarP(&bucketUpdateFlags.reorderWindow, "reorder-window", "", 0, "New duration written points are buffered; 0 disables buffering")
+	bucketQuotaFlags(bucketUpdateCmd, &bucketUpdateFlags.quota)
 	bucketUpdateCmd.MarkFlagRequired("id")
 
 	bucketCmd.AddCommand(bucketUpdateCmd)
@@ -231,6 +252,27 @@ func bucketUpdateF(cmd *cobra.Command, args []string) error {
 	if bucketUpdateFlags.retention != 0 {
 		update.RetentionPeriod = &bucketUpdateFlags.retention
 	}
+	if cmd.Flags().Changed("reorder-window") {
+		update.ReorderWindow = &bucketUpdateFlags.reorderWindow
+	}
+	if cmd.Flags().Changed("max-points-per-second") || cmd.Flags().Changed("max-series") || cmd.Flags().Changed("max-bytes-per-day") {
+		// The quota is replaced as a whole, so the limits not given are kept.
+		b, err := s.FindBucketByID(context.Background(), id)
+		if err != nil {
+			return fmt.Errorf("failed to find bucket: %v", err)
+		}
+		q := b.Quota
+		if cmd.Flags().Changed("max-points-per-second") {
+			q.MaxPointsPerSecond = bucketUpdateFlags.quota.MaxPointsPerSecond
+		}
+		if cmd.Flags().Changed("max-series") {
+			q.MaxSeries = bucketUpdateFlags.quota.MaxSeries
+		}
+		if cmd.Flags().Changed("max-bytes-per-day") {
+			q.MaxBytesPerDay = bucketUpdateFlags.quota.MaxBytesPerDay
+		}
+		update.Quota = &q
+	}
 
 	b, err := s.UpdateBucket(context.Background(), id, update)
 	if err != nil {
@@ -317,3 +359,111 @@ func init() {
 
 	bucketCmd.AddCommand(bucketDeleteCmd)
 }
+
+// BucketDropSeriesFlags define the Drop Series Command
+type BucketDropSeriesFlags struct {
+	id          string
+	measurement string
+	tags        []string
+	wait        bool
+}
+
+var bucketDropSeriesFlags BucketDropSeriesFlags
+
+func init() {
+	bucketDropSeriesCmd := &cobra.Command{
+		Use:   "drop-series",
+		Short: "Drop the series of a measurement from the index and data of a bucket",
+		RunE:  wrapCheckSetup(bucketDropSeriesF),
+	}
+
+	bucketDropSeriesCmd.Flags().StringVarP(&bucketDropSeriesFlags.id, "id", "i", "", "The bucket ID (required)")
+	bucketDropSeriesCmd.Flags().StringVarP(&bucketDropSeriesFlags.measurement, "measurement", "m", "", "The measurement of the series to drop (required)")
+	bucketDropSeriesCmd.Flags().StringArrayVarP(&bucketDropSeriesFlags.tags, "tag", "t", []string{}, "Tag predicate the series must match, such as host==a, host!=a, host=~^old- or host!~^web-")
+	bucketDropSeriesCmd.Flags().BoolVarP(&bucketDropSeriesFlags.wait, "wait", "", false, "Wait for the series to be dropped")
+	bucketDropSeriesCmd.MarkFlagRequired("id")
+	bucketDropSeriesCmd.MarkFlagRequired("measurement")
+
+	bucketCmd.AddCommand(bucketDropSeriesCmd)
+}
+
+func bucketDropSeriesF(cmd *cobra.Command, args []string) error {
+	if flags.local {
+		return fmt.Errorf("local flag not supported for drop-series command")
+	}
+
+	s := &http.DropSeriesService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	req := platform.DropSeriesRequest{
+		Measurement: bucketDropSeriesFlags.measurement,
+	}
+	if err := req.BucketID.DecodeFromString(bucketDropSeriesFlags.id); err != nil {
+		return fmt.Errorf("failed to decode bucket id %q: %v", bucketDropSeriesFlags.id, err)
+	}
+	for _, expr := range bucketDropSeriesFlags.tags {
+		p, err := parseSeriesTagPredicate(expr)
+		if err != nil {
+			return err
+		}
+		req.Tags = append(req.Tags, p)
+	}
+
+	ctx := context.Background()
+	op, err := s.DropSeries(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to drop series: %v", err)
+	}
+
+	for id := op.ID; bucketDropSeriesFlags.wait && !op.Done(); {
+		time.Sleep(time.Second)
+		if op, err = s.FindDropSeriesOperationByID(ctx, id); err != nil {
+			return fmt.Errorf("failed to find drop series operation with id %q: %v", id, err)
+		}
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"BucketID",
+		"Measurement",
+		"Status",
+		"Error",
+	)
+	w.Write(map[string]interface{}{
+		"ID":          op.ID.String(),
+		"BucketID":    op.BucketID.String(),
+		"Measurement": op.Measurement,
+		"Status":      op.Status,
+		"Error":       op.Error,
+	})
+	w.Flush()
+
+	if op.Status == platform.DropSeriesStatusFailed {
+		return fmt.Errorf("failed to drop series: %s", op.Error)
+	}
+	return nil
+}
+
+// parseSeriesTagPredicate parses a tag predicate expression such as host==a.
+// A single = is accepted for equality.
+func parseSeriesTagPredicate(expr string) (platform.SeriesTagPredicate, error) {
+	for i := 1; i < len(expr); i++ {
+		for _, op := range []string{
+			platform.TagPredicateEqual,
+			platform.TagPredicateNotEqual,
+			platform.TagPredicateRegex,
+			platform.TagPredicateNotRegex,
+		} {
+			if strings.HasPrefix(expr[i:], op) {
+				return platform.SeriesTagPredicate{Key: expr[:i], Op: op, Value: expr[i+len(op):]}, nil
+			}
+		}
+		if expr[i] == '=' {
+			return platform.SeriesTagPredicate{Key: expr[:i], Op: platform.TagPredicateEqual, Value: expr[i+1:]}, nil
+		}
+	}
+	return platform.SeriesTagPredicate{}, fmt.Errorf("invalid tag predicate %q", expr)
+}
@@ -29,6 +29,7 @@ var influxCmd = &cobra.Command{
 
 func init() {
 	influxCmd.AddCommand(authorizationCmd)
+	influxCmd.AddCommand(benchCmd)
 	influxCmd.AddCommand(bucketCmd)
 	influxCmd.AddCommand(organizationCmd)
 	influxCmd.AddCommand(queryCmd)
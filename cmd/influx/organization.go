@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/cmd/influx/internal"
@@ -201,22 +202,98 @@ func organizationUpdateF(cmd *cobra.Command, args []string) error {
 
 // OrganizationDeleteFlags contains the flag of the org delete command
 type OrganizationDeleteFlags struct {
-	id string
+	id     string
+	export bool
+	wait   bool
 }
 
 var organizationDeleteFlags OrganizationDeleteFlags
 
 func organizationDeleteF(cmd *cobra.Command, args []string) error {
-	orgSvc, err := newOrganizationService(flags)
-	if err != nil {
-		return fmt.Errorf("failed to initialize org service client: %v", err)
-	}
-
 	var id platform.ID
 	if err := id.DecodeFromString(organizationDeleteFlags.id); err != nil {
 		return fmt.Errorf("failed to decode org id %s: %v", organizationDeleteFlags.id, err)
 	}
 
+	if flags.local {
+		return organizationDeleteLocal(id)
+	}
+
+	s := &http.OrgDeletionService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	ctx := context.Background()
+	d, err := s.DeleteOrganizationResources(ctx, platform.OrgDeletionRequest{
+		OrgID:  id,
+		Export: organizationDeleteFlags.export,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete org with id %q: %v", id, err)
+	}
+
+	var progress string
+	for deletionID := d.ID; organizationDeleteFlags.wait && !d.Done(); {
+		time.Sleep(time.Second)
+		if d, err = s.FindOrgDeletionByID(ctx, deletionID); err != nil {
+			return fmt.Errorf("failed to find org deletion with id %q: %v", deletionID, err)
+		}
+		if p := orgDeletionProgress(d); p != progress {
+			progress = p
+			fmt.Fprintln(os.Stderr, progress)
+		}
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"OrgID",
+		"Name",
+		"Status",
+		"Stage",
+		"Error",
+		"ExportPath",
+	)
+	w.Write(map[string]interface{}{
+		"ID":         d.ID.String(),
+		"OrgID":      d.OrgID.String(),
+		"Name":       d.OrgName,
+		"Status":     d.Status,
+		"Stage":      orgDeletionProgress(d),
+		"Error":      d.Error,
+		"ExportPath": d.ExportPath,
+	})
+	w.Flush()
+
+	if d.Status == platform.OrgDeletionStatusFailed {
+		return fmt.Errorf("failed to delete org with id %q: %s", id, d.Error)
+	}
+	return nil
+}
+
+// orgDeletionProgress describes the stage a deletion is at, such as buckets 2/5.
+func orgDeletionProgress(d *platform.OrgDeletion) string {
+	for _, stage := range d.Stages {
+		if stage.Status == platform.OrgDeletionStatusRunning || stage.Status == platform.OrgDeletionStatusFailed {
+			return fmt.Sprintf("%s %d/%d", stage.Name, stage.Done, stage.Total)
+		}
+	}
+	return ""
+}
+
+// organizationDeleteLocal deletes the organization from the local store.
+// Its resources are left in place.
+func organizationDeleteLocal(id platform.ID) error {
+	if organizationDeleteFlags.export {
+		return fmt.Errorf("local flag not supported with the export flag")
+	}
+
+	orgSvc, err := newOrganizationService(flags)
+	if err != nil {
+		return fmt.Errorf("failed to initialize org service client: %v", err)
+	}
+
 	ctx := context.TODO()
 	o, err := orgSvc.FindOrganizationByID(ctx, id)
 	if err != nil {
@@ -246,11 +323,13 @@ func organizationDeleteF(cmd *cobra.Command, args []string) error {
 func init() {
 	organizationDeleteCmd := &cobra.Command{
 		Use:   "delete",
-		Short: "Delete organization",
+		Short: "Delete organization with its tasks, tokens, buckets and members",
 		RunE:  wrapCheckSetup(organizationDeleteF),
 	}
 
 	organizationDeleteCmd.Flags().StringVarP(&organizationDeleteFlags.id, "id", "i", "", "The organization ID (required)")
+	organizationDeleteCmd.Flags().BoolVarP(&organizationDeleteFlags.export, "export", "", false, "Export the resources and data of the organization on the server before deleting them")
+	organizationDeleteCmd.Flags().BoolVarP(&organizationDeleteFlags.wait, "wait", "", false, "Wait for the organization to be deleted")
 	organizationDeleteCmd.MarkFlagRequired("id")
 
 	organizationCmd.AddCommand(organizationDeleteCmd)
@@ -58,7 +58,9 @@ func setupF(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to determine if instance has been configured: %v", err)
 	}
-	if !allowed {
+	// Non-interactive setup can be re-run with the request that completed it,
+	// so that provisioning scripts can run it more than once.
+	if !allowed && isInteractive() {
 		return fmt.Errorf("instance at %q has already been setup", flags.host)
 	}
 
@@ -67,7 +69,8 @@ func setupF(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if _, err := os.Stat(dPath); err == nil {
+	_, statErr := os.Stat(dPath)
+	if statErr == nil && allowed {
 		return &platform.Error{
 			Code: platform.EConflict,
 			Msg:  fmt.Sprintf("token already exists at %s", dPath),
@@ -84,12 +87,22 @@ func setupF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to setup instance: %v", err)
 	}
 
-	err = writeTokenToPath(result.Auth.Token, dPath, dir)
-	if err != nil {
-		return fmt.Errorf("failed to write token to path %q: %v", dPath, err)
-	}
+	if statErr == nil {
+		// Setup was re-run: the stored token must be the one of the setup.
+		if tok, _ := getTokenFromDefaultPath(); tok != result.Auth.Token {
+			return &platform.Error{
+				Code: platform.EConflict,
+				Msg:  fmt.Sprintf("token already exists at %s", dPath),
+			}
+		}
+	} else {
+		err = writeTokenToPath(result.Auth.Token, dPath, dir)
+		if err != nil {
+			return fmt.Errorf("failed to write token to path %q: %v", dPath, err)
+		}
 
-	fmt.Println(promptWithColor("Your token has been stored in "+dPath+".", colorCyan))
+		fmt.Println(promptWithColor("Your token has been stored in "+dPath+".", colorCyan))
+	}
 
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/influxdata/flux/repl"
 	platform "github.com/influxdata/influxdb"
@@ -55,8 +58,9 @@ func init() {
 
 // TaskCreateFlags define the Create Command
 type TaskCreateFlags struct {
-	org   string
-	orgID string
+	org                string
+	orgID              string
+	deleteAfterSuccess bool
 }
 
 var taskCreateFlags TaskCreateFlags
@@ -71,6 +75,7 @@ func init() {
 
 	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.org, "org", "", "", "organization name")
 	taskCreateCmd.Flags().StringVarP(&taskCreateFlags.orgID, "org-id", "", "", "id of the organization that owns the task")
+	taskCreateCmd.Flags().BoolVarP(&taskCreateFlags.deleteAfterSuccess, "delete-after-success", "", false, "delete a task that runs once after its run succeeds")
 	taskCreateCmd.MarkFlagRequired("flux")
 
 	taskCmd.AddCommand(taskCreateCmd)
@@ -92,8 +97,9 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 	}
 
 	tc := platform.TaskCreate{
-		Flux:         flux,
-		Organization: taskCreateFlags.org,
+		Flux:               flux,
+		Organization:       taskCreateFlags.org,
+		DeleteAfterSuccess: taskCreateFlags.deleteAfterSuccess,
 	}
 	if taskCreateFlags.orgID != "" {
 		oid, err := platform.IDFromString(taskCreateFlags.orgID)
@@ -118,6 +124,7 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"At",
 	)
 	w.Write(map[string]interface{}{
 		"ID":              t.ID.String(),
@@ -128,6 +135,7 @@ func taskCreateF(cmd *cobra.Command, args []string) error {
 		"Status":          t.Status,
 		"Every":           t.Every,
 		"Cron":            t.Cron,
+		"At":              t.At,
 	})
 	w.Flush()
 
@@ -224,6 +232,7 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"At",
 	)
 	for _, t := range tasks {
 		w.Write(map[string]interface{}{
@@ -235,6 +244,7 @@ func taskFindF(cmd *cobra.Command, args []string) error {
 			"Status":          t.Status,
 			"Every":           t.Every,
 			"Cron":            t.Cron,
+			"At":              t.At,
 		})
 	}
 	w.Flush()
@@ -303,6 +313,7 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"At",
 	)
 	w.Write(map[string]interface{}{
 		"ID":              t.ID.String(),
@@ -313,6 +324,7 @@ func taskUpdateF(cmd *cobra.Command, args []string) error {
 		"Status":          t.Status,
 		"Every":           t.Every,
 		"Cron":            t.Cron,
+		"At":              t.At,
 	})
 	w.Flush()
 
@@ -371,6 +383,7 @@ func taskDeleteF(cmd *cobra.Command, args []string) error {
 		"Status",
 		"Every",
 		"Cron",
+		"At",
 	)
 	w.Write(map[string]interface{}{
 		"ID":              t.ID.String(),
@@ -381,12 +394,215 @@ func taskDeleteF(cmd *cobra.Command, args []string) error {
 		"Status":          t.Status,
 		"Every":           t.Every,
 		"Cron":            t.Cron,
+		"At":              t.At,
 	})
 	w.Flush()
 
 	return nil
 }
 
+// TaskExportFlags define the Export command
+type TaskExportFlags struct {
+	id   string
+	runs int
+	file string
+}
+
+var taskExportFlags TaskExportFlags
+
+func init() {
+	taskExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export task as JSON",
+		RunE:  wrapCheckSetup(taskExportF),
+	}
+
+	taskExportCmd.Flags().StringVarP(&taskExportFlags.id, "id", "i", "", "task id (required)")
+	taskExportCmd.Flags().IntVarP(&taskExportFlags.runs, "runs", "", 0, "number of recent runs to include")
+	taskExportCmd.Flags().StringVarP(&taskExportFlags.file, "file", "f", "", "file to write the export to instead of stdout")
+	taskExportCmd.MarkFlagRequired("id")
+
+	taskCmd.AddCommand(taskExportCmd)
+}
+
+func taskExportF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(taskExportFlags.id); err != nil {
+		return err
+	}
+
+	export, err := s.ExportTask(context.Background(), id, taskExportFlags.runs)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if taskExportFlags.file != "" {
+		f, err := os.Create(taskExportFlags.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(export)
+}
+
+// TaskImportFlags define the Import command
+type TaskImportFlags struct {
+	org      string
+	orgID    string
+	status   string
+	skipRuns bool
+}
+
+var taskImportFlags TaskImportFlags
+
+func init() {
+	taskImportCmd := &cobra.Command{
+		Use:   "import [/path/to/export.json ...]",
+		Short: "Import tasks from JSON exports",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  wrapCheckSetup(taskImportF),
+	}
+
+	taskImportCmd.Flags().StringVarP(&taskImportFlags.org, "org", "", "", "organization name")
+	taskImportCmd.Flags().StringVarP(&taskImportFlags.orgID, "org-id", "", "", "id of the organization that owns the tasks")
+	taskImportCmd.Flags().StringVarP(&taskImportFlags.status, "status", "", "", "status of the imported tasks, overriding the exported status")
+	taskImportCmd.Flags().BoolVarP(&taskImportFlags.skipRuns, "skip-runs", "", false, "do not import the run history of the tasks")
+
+	taskCmd.AddCommand(taskImportCmd)
+}
+
+func taskImportF(cmd *cobra.Command, args []string) error {
+	if (taskImportFlags.org == "") == (taskImportFlags.orgID == "") {
+		return fmt.Errorf("must specify exactly one of org or org-id")
+	}
+
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	ti := platform.TaskImport{
+		Organization: taskImportFlags.org,
+		Status:       taskImportFlags.status,
+		SkipRuns:     taskImportFlags.skipRuns,
+	}
+	if taskImportFlags.orgID != "" {
+		oid, err := platform.IDFromString(taskImportFlags.orgID)
+		if err != nil {
+			return fmt.Errorf("error parsing organization ID: %s", err)
+		}
+		ti.OrganizationID = *oid
+	}
+
+	for _, path := range args {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var export platform.TaskExport
+		if err := json.Unmarshal(b, &export); err != nil {
+			return fmt.Errorf("error parsing task export %s: %s", path, err)
+		}
+		ti.Tasks = append(ti.Tasks, export)
+	}
+
+	tasks, err := s.ImportTasks(context.Background(), ti)
+	if err != nil {
+		return err
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"OrganizationID",
+		"Organization",
+		"AuthorizationID",
+		"Status",
+		"Every",
+		"Cron",
+		"At",
+	)
+	for _, t := range tasks {
+		w.Write(map[string]interface{}{
+			"ID":              t.ID.String(),
+			"Name":            t.Name,
+			"OrganizationID":  t.OrganizationID.String(),
+			"Organization":    t.Organization,
+			"AuthorizationID": t.AuthorizationID.String(),
+			"Status":          t.Status,
+			"Every":           t.Every,
+			"Cron":            t.Cron,
+			"At":              t.At,
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
+func init() {
+	taskSchedulerCmd := &cobra.Command{
+		Use:   "scheduler",
+		Short: "Show the tasks claimed by the scheduler and why they are or aren't running",
+		RunE:  wrapCheckSetup(taskSchedulerF),
+	}
+
+	taskCmd.AddCommand(taskSchedulerCmd)
+}
+
+func taskSchedulerF(cmd *cobra.Command, args []string) error {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	state, err := s.SchedulerState(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Instance: %s, backlog: %d, last tick latency: %s\n", state.InstanceID, state.Backlog, state.TickLatency)
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"OrganizationID",
+		"Status",
+		"NextDue",
+		"Queued",
+		"Running",
+		"ClaimedAt",
+	)
+	for _, t := range state.Tasks {
+		w.Write(map[string]interface{}{
+			"ID":             t.TaskID.String(),
+			"Name":           t.Name,
+			"OrganizationID": t.OrganizationID.String(),
+			"Status":         t.Status,
+			"NextDue":        t.NextDue.Format(time.RFC3339),
+			"Queued":         t.HasQueue,
+			"Running":        fmt.Sprintf("%d/%d", t.RunsActive, t.MaxConcurrency),
+			"ClaimedAt":      t.ClaimedAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+
+	return nil
+}
+
 // taskLogFindFlags define the Delete command
 type TaskLogFindFlags struct {
 	taskID string
@@ -527,6 +743,7 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 		"StartedAt",
 		"FinishedAt",
 		"RequestedAt",
+		"Annotation",
 	)
 	for _, r := range runs {
 		w.Write(map[string]interface{}{
@@ -537,6 +754,7 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 			"StartedAt":    r.StartedAt,
 			"FinishedAt":   r.FinishedAt,
 			"RequestedAt":  r.RequestedAt,
+			"Annotation":   r.Annotation,
 		})
 	}
 	w.Flush()
@@ -546,6 +764,7 @@ func taskRunFindF(cmd *cobra.Command, args []string) error {
 
 type RunRetryFlags struct {
 	taskID, runID string
+	annotation    string
 }
 
 var runRetryFlags RunRetryFlags
@@ -559,6 +778,7 @@ func init() {
 
 	cmd.Flags().StringVarP(&runRetryFlags.taskID, "task-id", "i", "", "task id (required)")
 	cmd.Flags().StringVarP(&runRetryFlags.runID, "run-id", "r", "", "run id (required)")
+	cmd.Flags().StringVar(&runRetryFlags.annotation, "annotation", "", "reason for retrying the run")
 	cmd.MarkFlagRequired("task-id")
 	cmd.MarkFlagRequired("run-id")
 
@@ -580,7 +800,7 @@ func runRetryF(cmd *cobra.Command, args []string) error {
 	}
 
 	ctx := context.TODO()
-	newRun, err := s.RetryRun(ctx, taskID, runID)
+	newRun, err := s.RetryRun(ctx, taskID, runID, runRetryFlags.annotation)
 	if err != nil {
 		return err
 	}
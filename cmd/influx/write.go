@@ -26,11 +26,12 @@ or add an entire file specified with an @ prefix.`,
 }
 
 var writeFlags struct {
-	OrgID     string
-	Org       string
-	BucketID  string
-	Bucket    string
-	Precision string
+	OrgID       string
+	Org         string
+	BucketID    string
+	Bucket      string
+	Precision   string
+	DropInvalid bool
 }
 
 func init() {
@@ -63,6 +64,8 @@ func init() {
 	if p := viper.GetString("PRECISION"); p != "" {
 		writeFlags.Precision = p
 	}
+
+	writeCmd.PersistentFlags().BoolVar(&writeFlags.DropInvalid, "drop-invalid", false, "Write the valid lines even if some lines are invalid")
 }
 
 func fluxWriteF(cmd *cobra.Command, args []string) error {
@@ -144,9 +147,10 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 
 	s := write.Batcher{
 		Service: &http.WriteService{
-			Addr:      flags.host,
-			Token:     flags.token,
-			Precision: writeFlags.Precision,
+			Addr:             flags.host,
+			Token:            flags.token,
+			Precision:        writeFlags.Precision,
+			DropInvalidLines: writeFlags.DropInvalid,
 		},
 	}
 
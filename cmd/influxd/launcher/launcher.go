@@ -2,24 +2,33 @@ package launcher
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net"
 	nethttp "net/http"
 	_ "net/http/pprof" // needed to add pprof to our binary.
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/influxdata/flux/execute"
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/audit"
 	"github.com/influxdata/influxdb/authorizer"
+	"github.com/influxdata/influxdb/awssecrets"
 	"github.com/influxdata/influxdb/bolt"
+	"github.com/influxdata/influxdb/check"
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/gather"
 	"github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/http/metric"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/internal/fs"
 	"github.com/influxdata/influxdb/kit/cli"
@@ -27,11 +36,16 @@ import (
 	"github.com/influxdata/influxdb/kit/signals"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/limits"
 	influxlogger "github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/nats"
+	"github.com/influxdata/influxdb/notification"
+	"github.com/influxdata/influxdb/orgdelete"
 	infprom "github.com/influxdata/influxdb/prometheus"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
+	"github.com/influxdata/influxdb/redis"
+	"github.com/influxdata/influxdb/schemadoc"
 	"github.com/influxdata/influxdb/snowflake"
 	"github.com/influxdata/influxdb/source"
 	"github.com/influxdata/influxdb/storage"
@@ -39,6 +53,7 @@ import (
 	taskbackend "github.com/influxdata/influxdb/task/backend"
 	"github.com/influxdata/influxdb/task/backend/coordinator"
 	taskexecutor "github.com/influxdata/influxdb/task/backend/executor"
+	taskremote "github.com/influxdata/influxdb/task/backend/remote"
 	"github.com/influxdata/influxdb/telemetry"
 	_ "github.com/influxdata/influxdb/tsdb/tsi1" // needed for tsi1
 	_ "github.com/influxdata/influxdb/tsdb/tsm1" // needed for tsm1
@@ -57,6 +72,10 @@ const (
 	BoltStore = "bolt"
 	// MemoryStore stores all REST resources in memory (useful for testing).
 	MemoryStore = "memory"
+	// KVSessionStore stores sessions along with the REST resources.
+	KVSessionStore = "kv"
+	// RedisSessionStore stores sessions in redis, to share them between API nodes.
+	RedisSessionStore = "redis"
 
 	// LogTracing enables tracing via zap logs
 	LogTracing = "log"
@@ -133,6 +152,26 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: ":9999",
 			Desc:    "bind address for the REST HTTP API",
 		},
+		{
+			DestP: &l.tlsCert,
+			Flag:  "tls-cert",
+			Desc:  "path to the PEM encoded TLS certificate of the REST HTTP API; the API is served over HTTPS if set",
+		},
+		{
+			DestP: &l.tlsKey,
+			Flag:  "tls-key",
+			Desc:  "path to the PEM encoded private key of the TLS certificate",
+		},
+		{
+			DestP: &l.tlsClientCA,
+			Flag:  "tls-client-ca",
+			Desc:  "path to the PEM encoded certificate authorities that verify the client certificates requests present",
+		},
+		{
+			DestP: &l.tlsClientCertRules,
+			Flag:  "tls-client-cert-rules",
+			Desc:  "path to a JSON file of the rules that map verified client certificates to users or authorizations, in the order they are matched",
+		},
 		{
 			DestP:   &l.boltPath,
 			Flag:    "bolt-path",
@@ -162,11 +201,17 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: filepath.Join(dir, "engine"),
 			Desc:    "path to persistent engine files",
 		},
+		{
+			DestP:   &l.orgExportPath,
+			Flag:    "org-export-path",
+			Default: filepath.Join(dir, "exports"),
+			Desc:    "path to export organizations to before they are deleted",
+		},
 		{
 			DestP:   &l.secretStore,
 			Flag:    "secret-store",
 			Default: "bolt",
-			Desc:    "data store for secrets (bolt or vault)",
+			Desc:    "data store for secrets (bolt, vault or aws)",
 		},
 		{
 			DestP:   &l.reportingDisabled,
@@ -186,6 +231,189 @@ func buildLauncherCommand(l *Launcher, cmd *cobra.Command) {
 			Default: false,
 			Desc:    "disables automatically extending session ttl on request",
 		},
+		{
+			DestP:   &l.passwordPolicy.MinLength,
+			Flag:    "password-min-length",
+			Default: platform.DefaultPasswordPolicy.MinLength,
+			Desc:    "least number of characters of passwords",
+		},
+		{
+			DestP: &l.passwordClasses,
+			Flag:  "password-required-character-classes",
+			Desc:  "classes of characters passwords must have at least one of each of; any of lowercase, uppercase, digit and symbol",
+		},
+		{
+			DestP: &l.passwordDenyListPath,
+			Flag:  "password-deny-list-path",
+			Desc:  "path to a file of passwords that may not be used, one per line",
+		},
+		{
+			DestP: &l.passwordPolicy.HistorySize,
+			Flag:  "password-history",
+			Desc:  "number of most recent passwords of a user that the user may not set again; disabled if zero",
+		},
+		{
+			DestP: &l.passwordPolicy.MaxAge,
+			Flag:  "password-max-age",
+			Desc:  "how long passwords may be used before they have to be changed; passwords never expire if zero",
+		},
+		{
+			DestP:   &l.graphQLEnabled,
+			Flag:    "graphql-enabled",
+			Default: false,
+			Desc:    "serve GraphQL queries of organizations, buckets, dashboards and tasks at /api/v2/graphql",
+		},
+		{
+			DestP:   &l.sessionStore,
+			Flag:    "session-store",
+			Default: KVSessionStore,
+			Desc:    fmt.Sprintf("data store for sessions (%s or %s); use %s to share sessions between API nodes", KVSessionStore, RedisSessionStore, RedisSessionStore),
+		},
+		{
+			DestP:   &l.sessionRedisURL,
+			Flag:    "session-redis-url",
+			Default: "redis://localhost:6379/0",
+			Desc:    "url of the redis server of the redis session store, such as redis://:password@host:6379/0",
+		},
+		{
+			DestP:   &l.writesDisabled,
+			Flag:    "writes-disabled",
+			Default: false,
+			Desc:    "start with the write path disabled for maintenance; re-enable with PATCH /api/v2/maintenance",
+		},
+		{
+			DestP:   &l.queriesDisabled,
+			Flag:    "queries-disabled",
+			Default: false,
+			Desc:    "start with the query path disabled for maintenance; re-enable with PATCH /api/v2/maintenance",
+		},
+		{
+			DestP: &l.maintenanceMessage,
+			Flag:  "maintenance-message",
+			Desc:  "message returned to clients of disabled write or query paths",
+		},
+		{
+			DestP: &l.StorageConfig.MaintenanceWindows,
+			Flag:  "maintenance-windows",
+			Desc:  "daily windows in UTC, such as 01:00-05:00, to which full compactions, retention sweeps and series index rebuilds are confined; override with PATCH /api/v2/maintenance/schedule",
+		},
+		{
+			DestP: &l.queryCacheTTL,
+			Flag:  "query-cache-ttl",
+			Desc:  "how long the results of Flux queries are cached, so dashboards refreshing the same cells don't scan the same data again; disabled if zero",
+		},
+		{
+			DestP:   &l.queryCacheMaxBytes,
+			Flag:    "query-cache-max-bytes",
+			Default: 100 * 1024 * 1024,
+			Desc:    "approximate size of the query results the cache holds",
+		},
+		{
+			DestP:   &l.queryCacheNowPrecision,
+			Flag:    "query-cache-now-precision",
+			Default: 10 * time.Second,
+			Desc:    "interval now() is truncated to in cacheable queries, so queries within the same interval share results",
+		},
+		{
+			DestP: &l.queryHTTPCache.MaxAge,
+			Flag:  "query-http-cache-max-age",
+			Desc:  "how long HTTP caches may serve the responses to queries of historical time ranges, so repeated dashboard queries are served by caches in front of the server; disabled if zero",
+		},
+		{
+			DestP:   &l.queryHTTPCache.SettleTime,
+			Flag:    "query-http-cache-settle-time",
+			Default: time.Hour,
+			Desc:    "how far in the past, beyond the reorder window of its buckets, the time range of a query must end for HTTP caches to serve its responses",
+		},
+		{
+			DestP: &l.queryHTTPStream.ChunkSize,
+			Flag:  "query-http-chunk-size",
+			Desc:  "number of bytes of query results to buffer before flushing them to the client; results are flushed as they are encoded if zero",
+		},
+		{
+			DestP: &l.queryHTTPStream.KeepAliveInterval,
+			Flag:  "query-http-keep-alive-interval",
+			Desc:  "how often to write a keep-alive to the client while a query has not produced results yet; disabled if zero",
+		},
+		{
+			DestP: &l.queryOrgConcurrency,
+			Flag:  "query-org-concurrency",
+			Desc:  "number of queries of a single organization that may execute concurrently, so one organization can't take every query slot; unlimited if zero",
+		},
+		{
+			DestP: &l.queryOrgWeights,
+			Flag:  "query-org-weights",
+			Desc:  "weights of the shares of organizations in the admission of queued queries, as orgID=weight; organizations without a weight have a weight of 1",
+		},
+		{
+			DestP: &l.queryBatchConcurrency,
+			Flag:  "query-batch-concurrency",
+			Desc:  "number of batch queries, such as task runs, that may execute concurrently, so slots are left for interactive queries; unlimited if zero",
+		},
+		{
+			DestP:   &l.queryDefaultTimezone,
+			Flag:    "query-default-timezone",
+			Default: "UTC",
+			Desc:    "IANA timezone the calendar of a query is resolved in when its organization has no timezone",
+		},
+		{
+			DestP:   &l.queryHistoryLimit,
+			Flag:    "query-history-limit",
+			Default: platform.DefaultQueryHistoryLimit,
+			Desc:    "number of completed queries kept in the query history of each organization; disabled if zero",
+		},
+		{
+			DestP:   &l.uniqueNames,
+			Flag:    "unique-names",
+			Default: []string{string(platform.BucketsResourceType)},
+			Desc:    "types of resources whose names must be unique within their organization, unless the organization sets its own name policy; one or more of buckets, dashboards and tasks, and always buckets",
+		},
+		{
+			DestP: &l.trashRetention,
+			Flag:  "trash-retention",
+			Desc:  "duration deleted buckets, dashboards and tasks are kept in the trash, from which they can be restored; deleted at once if zero",
+		},
+		{
+			DestP:   &l.writeRejections.SampleEvery,
+			Flag:    "write-rejections-sample-every",
+			Default: 1,
+			Desc:    "record one in every n rejected writes in the monitoring bucket of their organization; disabled if zero",
+		},
+		{
+			DestP:   &l.writeRejections.MaxPerSecond,
+			Flag:    "write-rejections-max-per-second",
+			Default: 100,
+			Desc:    "maximum number of rejected writes recorded per second; unlimited if zero",
+		},
+		{
+			DestP:   &l.writeRejections.MaxLineBytes,
+			Flag:    "write-rejections-max-line-bytes",
+			Default: 256,
+			Desc:    "number of bytes of the offending line recorded with a rejected write",
+		},
+		{
+			DestP:   &l.usageInterval,
+			Flag:    "usage-interval",
+			Default: 10 * time.Minute,
+			Desc:    "interval at which the usage of organizations is recorded in their monitoring bucket; disabled if zero",
+		},
+		{
+			DestP:   &l.taskExecutor,
+			Flag:    "task-executor",
+			Default: "local",
+			Desc:    "executor of task runs (local, or remote to execute runs on external task workers)",
+		},
+		{
+			DestP:   &l.taskExecutorBindAddress,
+			Flag:    "task-executor-bind-address",
+			Default: ":9997",
+			Desc:    "bind address for the gRPC API of the remote task executor",
+		},
+		{
+			DestP: &l.taskExecutorSecret,
+			Flag:  "task-executor-secret",
+			Desc:  "shared secret external task workers authenticate with; required by the remote task executor",
+		},
 	}
 
 	cli.BindOptions(cmd, opts)
@@ -202,6 +430,36 @@ type Launcher struct {
 	testing              bool
 	sessionLength        int // in minutes
 	sessionRenewDisabled bool
+	passwordPolicy       platform.PasswordPolicy
+	passwordClasses      []string
+	passwordDenyListPath string
+	sessionStore         string
+	sessionRedisURL      string
+	graphQLEnabled       bool
+
+	writesDisabled     bool
+	queriesDisabled    bool
+	maintenanceMessage string
+
+	queryCacheTTL          time.Duration
+	queryCacheMaxBytes     int
+	queryCacheNowPrecision time.Duration
+
+	queryHTTPCache  http.QueryCacheHeadersConfig
+	queryHTTPStream http.QueryStreamConfig
+
+	queryOrgConcurrency   int
+	queryOrgWeights       []string
+	queryBatchConcurrency int
+	queryDefaultTimezone  string
+	queryHistoryLimit     int
+
+	uniqueNames []string
+
+	trashRetention time.Duration
+
+	writeRejections storage.WriteRejectionConfig
+	usageInterval   time.Duration
 
 	logLevel          string
 	tracingType       string
@@ -210,12 +468,23 @@ type Launcher struct {
 	httpBindAddress string
 	boltPath        string
 	enginePath      string
+	orgExportPath   string
 	secretStore     string
 
+	tlsCert            string
+	tlsKey             string
+	tlsClientCA        string
+	tlsClientCertRules string
+
+	taskExecutor            string
+	taskExecutorBindAddress string
+	taskExecutorSecret      string
+
 	boltClient    *bolt.Client
 	kvService     *kv.Service
 	engine        *storage.Engine
 	StorageConfig storage.Config
+	usageRecorder *storage.UsageRecorder
 
 	queryController *control.Controller
 
@@ -226,6 +495,7 @@ type Launcher struct {
 
 	scheduler          *taskbackend.TickScheduler
 	taskControlService taskbackend.TaskControlService
+	remoteExecutor     *taskremote.Executor
 
 	jaegerTracerCloser io.Closer
 	logger             *zap.Logger
@@ -284,6 +554,9 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 
 	m.logger.Info("Stopping", zap.String("service", "task"))
 	m.scheduler.Stop()
+	if m.remoteExecutor != nil {
+		m.remoteExecutor.Close()
+	}
 
 	m.logger.Info("Stopping", zap.String("service", "nats"))
 	m.natsServer.Close()
@@ -395,22 +668,58 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	var namePolicy *platform.NamePolicy
+	if len(m.uniqueNames) > 0 {
+		namePolicy = &platform.NamePolicy{}
+		for _, rt := range m.uniqueNames {
+			namePolicy.UniqueNames = append(namePolicy.UniqueNames, platform.ResourceType(rt))
+		}
+		if err := namePolicy.Valid(); err != nil {
+			m.logger.Error("invalid unique names", zap.Error(err))
+			return err
+		}
+	}
+
+	passwordPolicy := m.passwordPolicy
+	for _, c := range m.passwordClasses {
+		passwordPolicy.RequiredClasses = append(passwordPolicy.RequiredClasses, platform.PasswordCharacterClass(c))
+	}
+	if m.passwordDenyListPath != "" {
+		denyList, err := readPasswordDenyList(m.passwordDenyListPath)
+		if err != nil {
+			m.logger.Error("failed reading password deny list", zap.Error(err))
+			return err
+		}
+		passwordPolicy.DenyList = denyList
+	}
+	if err := passwordPolicy.Valid(); err != nil {
+		m.logger.Error("invalid password policy", zap.Error(err))
+		return err
+	}
+
 	serviceConfig := kv.ServiceConfig{
-		SessionLength: time.Duration(m.sessionLength) * time.Minute,
+		SessionLength:     time.Duration(m.sessionLength) * time.Minute,
+		QueryHistoryLimit: m.queryHistoryLimit,
+		NamePolicy:        namePolicy,
+		PasswordPolicy:    &passwordPolicy,
+		TrashRetention:    m.trashRetention,
 	}
 
 	var flusher http.Flusher
+	var kvStore kv.Store
 	switch m.storeType {
 	case BoltStore:
 		store := bolt.NewKVStore(m.boltPath)
 		store.WithDB(m.boltClient.DB())
 		m.kvService = kv.NewService(store, serviceConfig)
+		kvStore = store
 		if m.testing {
 			flusher = store
 		}
 	case MemoryStore:
 		store := inmem.NewKVStore()
 		m.kvService = kv.NewService(store, serviceConfig)
+		kvStore = store
 		if m.testing {
 			flusher = store
 		}
@@ -426,9 +735,35 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		return err
 	}
 
+	switch m.sessionStore {
+	case KVSessionStore:
+		sessionStore := kv.NewLocalSessionStore(kvStore)
+		sessionStore.Logger = m.logger.With(zap.String("service", "sessions"))
+		m.kvService.SessionStore = sessionStore
+
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			sessionStore.Cleanup(ctx, time.Minute)
+		}()
+	case RedisSessionStore:
+		// Redis removes expired sessions itself.
+		client, err := redis.NewClient(m.sessionRedisURL)
+		if err != nil {
+			m.logger.Error("failed initializing redis session store", zap.Error(err))
+			return err
+		}
+		m.kvService.SessionStore = redis.NewSessionStore(client)
+	default:
+		err := fmt.Errorf("unknown session store %q, expected %q or %q", m.sessionStore, KVSessionStore, RedisSessionStore)
+		m.logger.Error("failed setting session store", zap.Error(err))
+		return err
+	}
+
 	m.reg = prom.NewRegistry()
 	m.reg.MustRegister(
 		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
 		infprom.NewInfluxCollector(m.boltClient, info),
 	)
 	m.reg.WithLogger(m.logger)
@@ -454,12 +789,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		userResourceSvc  platform.UserResourceMappingService      = m.kvService
 		labelSvc         platform.LabelService                    = m.kvService
 		secretSvc        platform.SecretService                   = m.kvService
+		secretRotateSvc  platform.SecretRotationService           = m.kvService
 		lookupSvc        platform.LookupService                   = m.kvService
 	)
 
 	switch m.secretStore {
 	case "bolt":
 		// If it is bolt, then we already set it above.
+		m.kvService.SecretRotationHooks = append(m.kvService.SecretRotationHooks, func(ctx context.Context, v *platform.SecretVersion) {
+			m.logger.Info("Secret rotated", zap.String("org_id", v.OrgID.String()), zap.String("key", v.Key), zap.Int("version", v.Version))
+		})
 	case "vault":
 		// The vault secret service is configured using the standard vault environment variables.
 		// https://www.vaultproject.io/docs/commands/index.html#environment-variables
@@ -469,8 +808,23 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			return err
 		}
 		secretSvc = svc
+		m.kvService.SecretService = svc
+		// Vault keeps the versions of secrets itself, so they are not rotated through influxdb.
+		secretRotateSvc = platform.UnsupportedSecretRotationService{}
+	case "aws":
+		// The AWS Secrets Manager secret service is configured using the standard AWS environment variables.
+		// https://docs.aws.amazon.com/sdk-for-go/v1/developer-guide/configuring-sdk.html
+		svc, err := awssecrets.NewSecretService()
+		if err != nil {
+			m.logger.Error("failed initializing aws secret service", zap.Error(err))
+			return err
+		}
+		secretSvc = svc
+		m.kvService.SecretService = svc
+		// AWS Secrets Manager keeps the versions of secrets itself, so they are not rotated through influxdb.
+		secretRotateSvc = platform.UnsupportedSecretRotationService{}
 	default:
-		err := fmt.Errorf("unknown secret service %q, expected \"bolt\" or \"vault\"", m.secretStore)
+		err := fmt.Errorf("unknown secret service %q, expected \"bolt\", \"vault\" or \"aws\"", m.secretStore)
 		m.logger.Error("failed setting secret service", zap.Error(err))
 		return err
 	}
@@ -482,8 +836,9 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	}
 
 	var pointsWriter storage.PointsWriter
+	var queryLimits platform.SystemLimits
 	{
-		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc))
+		m.engine = storage.NewEngine(m.enginePath, m.StorageConfig, storage.WithRetentionEnforcer(bucketSvc), storage.WithReorderBuffer(bucketSvc), storage.WithCardinalityRecorder(bucketSvc))
 		m.engine.WithLogger(m.logger)
 
 		if err := m.engine.Open(ctx); err != nil {
@@ -495,6 +850,26 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		pointsWriter = m.engine
 
+		if m.usageInterval > 0 {
+			m.usageRecorder = storage.NewUsageRecorder(m.engine, bucketSvc, m.logger)
+			m.wg.Add(1)
+			go func() {
+				defer m.wg.Done()
+				m.usageRecorder.Run(ctx, m.usageInterval)
+			}()
+		}
+
+		if _, err := platform.LoadTimezone(m.queryDefaultTimezone); err != nil {
+			m.logger.Error("invalid default query timezone", zap.Error(err))
+			return err
+		}
+
+		orgQueueWeights, err := control.ParseOrgQueueWeights(m.queryOrgWeights)
+		if err != nil {
+			m.logger.Error("invalid query organization weights", zap.Error(err))
+			return err
+		}
+
 		// TODO(cwolff): Figure out a good default per-query memory limit:
 		//   https://github.com/influxdata/influxdb/issues/13642
 		const (
@@ -508,7 +883,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			ConcurrencyQuota:         concurrencyQuota,
 			MemoryBytesQuotaPerQuery: int64(memoryBytesQuotaPerQuery),
 			QueueSize:                QueueSize,
+			OrgConcurrencyQuota:      m.queryOrgConcurrency,
+			BatchConcurrencyQuota:    m.queryBatchConcurrency,
+			OrgQueueWeights:          orgQueueWeights,
+			FluxPackageService:       m.kvService,
 			Logger:                   m.logger.With(zap.String("service", "storage-reads")),
+			ResultCache: control.ResultCacheConfig{
+				TTL:          m.queryCacheTTL,
+				MaxBytes:     int64(m.queryCacheMaxBytes),
+				NowPrecision: m.queryCacheNowPrecision,
+			},
 		}
 
 		if err := readservice.AddControllerConfigDependencies(
@@ -524,11 +908,29 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			return err
 		}
 		m.queryController = c
+		queryLimits = platform.SystemLimits{
+			QueryConcurrency:      cc.ConcurrencyQuota,
+			QueryQueueSize:        cc.QueueSize,
+			QueryMemoryBytes:      cc.MemoryBytesQuotaPerQuery,
+			OrgQueryConcurrency:   cc.OrgConcurrencyQuota,
+			BatchQueryConcurrency: cc.BatchConcurrencyQuota,
+		}
 		m.reg.MustRegister(m.queryController.PrometheusCollectors()...)
 	}
 
 	var storageQueryService = readservice.NewProxyQueryService(m.queryController)
+	if m.queryHistoryLimit > 0 {
+		storageQueryService = &query.LoggingProxyQueryService{
+			ProxyQueryService: storageQueryService,
+			QueryLogger:       query.NewHistoryLogger(m.kvService, m.logger.With(zap.String("service", "query-history"))),
+			Logger:            m.logger,
+		}
+	}
 	var taskSvc platform.TaskService
+	var revisionSvc platform.RevisionService
+	var taskRunImporter platform.TaskRunImporter
+	var taskDebugSvc platform.TaskDebugService
+	var logBroadcaster *taskbackend.LogBroadcaster
 	{
 
 		// create the task stack:
@@ -536,16 +938,59 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 		// define the executor and build analytical storage middleware
 		combinedTaskService := taskbackend.NewAnalyticalStorage(m.logger.With(zap.String("service", "task-analytical-store")), m.kvService, m.kvService, pointsWriter, query.QueryServiceBridge{AsyncQueryService: m.queryController})
-		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, authSvc, combinedTaskService)
+		if m.usageRecorder != nil {
+			combinedTaskService.UsageRecorder = m.usageRecorder
+		}
+		var executor taskbackend.Executor
+		switch m.taskExecutor {
+		case "local":
+			executor = taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, authSvc, combinedTaskService)
+		case "remote":
+			if m.taskExecutorSecret == "" {
+				return fmt.Errorf("the remote task executor requires a task-executor-secret")
+			}
+			executorLogger := m.logger.With(zap.String("service", "task-executor"))
+			ln, err := net.Listen("tcp", m.taskExecutorBindAddress)
+			if err != nil {
+				executorLogger.Error("failed task executor listener", zap.Error(err))
+				return err
+			}
+			m.remoteExecutor = taskremote.NewExecutor(executorLogger, authSvc, combinedTaskService, m.taskExecutorSecret)
+			executor = m.remoteExecutor
+
+			m.wg.Add(1)
+			go func() {
+				defer m.wg.Done()
+				executorLogger.Info("Listening", zap.String("transport", "grpc"), zap.String("addr", m.taskExecutorBindAddress))
+				if err := m.remoteExecutor.Serve(ln); err != nil {
+					executorLogger.Error("failed task executor service", zap.Error(err))
+				}
+			}()
+		default:
+			return fmt.Errorf("unknown task executor %q", m.taskExecutor)
+		}
 
-		// create the scheduler
-		m.scheduler = taskbackend.NewScheduler(combinedTaskService, executor, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger))
+		// publish the logs of runs to the clients streaming them
+		logBroadcaster = taskbackend.NewLogBroadcaster(combinedTaskService)
+
+		// create the scheduler, reporting the host as the holder of its claims
+		hostname, err := os.Hostname()
+		if err != nil {
+			m.logger.Warn("Unable to determine hostname for the task scheduler", zap.Error(err))
+		}
+		m.scheduler = taskbackend.NewScheduler(logBroadcaster, executor, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger), taskbackend.WithInstanceID(hostname))
 		m.scheduler.Start(ctx)
 		m.reg.MustRegister(m.scheduler.PrometheusCollectors()...)
 
-		taskSvc = coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, combinedTaskService)
-		taskSvc = authorizer.NewTaskService(m.logger.With(zap.String("service", "task-authz-validator")), taskSvc, bucketSvc)
-		m.taskControlService = combinedTaskService
+		coord := coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, combinedTaskService)
+		taskSvc = authorizer.NewTaskService(m.logger.With(zap.String("service", "task-authz-validator")), coord, bucketSvc)
+		revisionSvc = coordinator.NewRevisionService(coord, m.kvService)
+		m.taskControlService = logBroadcaster
+		taskRunImporter = combinedTaskService
+
+		// debug runs are always executed locally, whatever the executor of the scheduled runs
+		taskDebugSvc = taskexecutor.NewDebugService(m.logger.With(zap.String("service", "task-debugger")), query.QueryServiceBridge{AsyncQueryService: m.queryController}, authSvc, combinedTaskService)
+		taskDebugSvc = authorizer.NewTaskDebugService(taskDebugSvc, combinedTaskService)
 	}
 
 	// NATS streaming server
@@ -574,11 +1019,12 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 			Writer: pointsWriter,
 		},
 	})
-	scraperScheduler, err := gather.NewScheduler(10, m.logger, scraperTargetSvc, publisher, subscriber, 10*time.Second, 30*time.Second)
+	scraperScheduler, err := gather.NewScheduler(10, m.logger, scraperTargetSvc, secretSvc, m.kvService, publisher, subscriber, 10*time.Second, 30*time.Second)
 	if err != nil {
 		m.logger.Error("failed to create scraper subscriber", zap.Error(err))
 		return err
 	}
+	m.reg.MustRegister(scraperScheduler.PrometheusCollectors()...)
 
 	m.wg.Add(1)
 	go func(logger *zap.Logger) {
@@ -594,24 +1040,176 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		Addr: m.httpBindAddress,
 	}
 
+	if err := m.configureTLS(); err != nil {
+		m.logger.Error("failed configuring tls", zap.Error(err))
+		return err
+	}
+
+	var clientCertAuthenticator *http.ClientCertAuthenticator
+	if m.tlsClientCertRules != "" {
+		rules, err := loadClientCertRules(m.tlsClientCertRules)
+		if err != nil {
+			m.logger.Error("failed loading client certificate rules", zap.Error(err))
+			return err
+		}
+		if clientCertAuthenticator, err = http.NewClientCertAuthenticator(rules); err != nil {
+			m.logger.Error("failed loading client certificate rules", zap.Error(err))
+			return err
+		}
+		clientCertAuthenticator.UserService = userSvc
+		clientCertAuthenticator.UserPermissionService = m.kvService
+		clientCertAuthenticator.AuthorizationService = authSvc
+	}
+
+	maintenanceSvc := inmem.NewMaintenanceService(platform.MaintenanceStatus{
+		Writes: platform.MaintenanceToggle{
+			Disabled: m.writesDisabled,
+			Message:  m.maintenanceMessage,
+		},
+		Queries: platform.MaintenanceToggle{
+			Disabled: m.queriesDisabled,
+			Message:  m.maintenanceMessage,
+		},
+	})
+	if m.writesDisabled || m.queriesDisabled {
+		m.logger.Warn("Starting in maintenance mode", zap.Bool("writes_disabled", m.writesDisabled), zap.Bool("queries_disabled", m.queriesDisabled))
+	}
+
+	buildInfo := platform.GetBuildInfo()
+	systemInfoSvc := inmem.NewSystemInfoService(platform.SystemInfo{
+		Version:   buildInfo.Version,
+		Commit:    buildInfo.Commit,
+		BuildDate: buildInfo.Date,
+		Storage: platform.SystemStorageInfo{
+			Engine:        "tsm1",
+			Index:         "tsi1",
+			LayoutVersion: storage.LayoutVersion,
+			WALEnabled:    m.StorageConfig.WAL.Enabled,
+		},
+		Limits: queryLimits,
+	})
+
+	// The usage of the quota of a bucket is dropped along with its data.
+	quotaSvc := storage.NewQuotaService(m.engine)
+	bucketDeleter := storage.BucketDeleters{m.engine, quotaSvc}
+
+	// Organizations are deleted through the storage backed BucketService, so that
+	// the data of their buckets is removed from the storage engine.
+	orgDeletionSvc := orgdelete.NewService(orgdelete.Services{
+		OrganizationService:        orgSvc,
+		TaskService:                taskSvc,
+		AuthorizationService:       authSvc,
+		UserResourceMappingService: userResourceSvc,
+		BucketService:              storage.NewBucketService(bucketSvc, bucketDeleter),
+		QueryService:               query.QueryServiceBridge{AsyncQueryService: m.queryController},
+	}, m.orgExportPath, m.logger.With(zap.String("service", "org-deletion")))
+
+	// The data of buckets moved to the trash is kept until they are purged
+	// from the trash, when it is removed from the storage engine.
+	apiBucketSvc := storage.NewBucketService(bucketSvc, bucketDeleter)
+	if m.trashRetention > 0 {
+		apiBucketSvc = storage.NewTrashBucketService(bucketSvc, bucketDeleter)
+	}
+	trashSvc := storage.NewTrashService(m.kvService, bucketDeleter, m.logger)
+	if m.trashRetention > 0 {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			trashSvc.Cleanup(ctx, time.Minute)
+		}()
+	}
+
+	// The quotas of buckets and the simulations of limits share the usage of
+	// the writes of this process.
+	limitsSimulationSvc := limits.NewSimulationService(limits.Services{
+		OrganizationService: orgSvc,
+		BucketService:       bucketSvc,
+		BucketQuotaService:  quotaSvc,
+		RunningQueryService: m.queryController,
+	})
+
+	schemaDocSvc := schemadoc.NewService(schemadoc.Services{
+		BucketService:            bucketSvc,
+		SeriesSchemaService:      storage.NewSeriesSchemaService(m.engine),
+		MeasurementSchemaService: m.kvService,
+		SchemaDescriptionService: m.kvService,
+	})
+
+	queryViewSvc := storage.NewQueryViewService(m.kvService, m.engine, query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.logger)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		queryViewSvc.Cleanup(ctx, time.Minute)
+	}()
+
+	// The auth events, the changes of buckets and the access to secrets
+	// through the API and by notifications are recorded for audit. The
+	// exporter loads the secrets that sign the records without recording it.
+	auditLogger := m.logger.With(zap.String("service", "audit"))
+	auditAuthSvc := audit.NewAuthorizationService(authSvc, m.kvService, auditLogger)
+	auditBucketSvc := audit.NewBucketService(apiBucketSvc, m.kvService, auditLogger)
+	auditSecretSvc := audit.NewSecretService(secretSvc, m.kvService, auditLogger)
+	auditExporter := audit.NewExporter(m.kvService, m.kvService, secretSvc, m.logger)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		auditExporter.Run(ctx, 10*time.Second)
+	}()
+
+	checkStatusSvc := check.NewStatusService(bucketSvc, query.QueryServiceBridge{AsyncQueryService: m.queryController})
+	notificationSender := notification.NewSender(auditSecretSvc)
+	ruleEngine := notification.NewRuleEngine(m.kvService, m.kvService, checkStatusSvc, m.kvService, m.kvService, notificationSender, m.logger)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ruleEngine.Run(ctx, time.Minute)
+	}()
+
+	schemaDriftEngine := check.NewSchemaDriftEngine(m.kvService, bucketSvc, schemaDocSvc, pointsWriter, m.logger)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		schemaDriftEngine.Run(ctx, 10*time.Second)
+	}()
+
+	var writeRejectionRecorder platform.WriteRejectionRecorder
+	if m.writeRejections.SampleEvery > 0 {
+		recorder := storage.NewWriteRejectionRecorder(m.engine, m.writeRejections, m.logger)
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			recorder.Run(ctx, time.Second)
+		}()
+		writeRejectionRecorder = recorder
+	}
+
+	var writeEventRecorder, queryEventRecorder metric.EventRecorder = infprom.NewEventRecorder("write"), infprom.NewEventRecorder("query")
+	if m.usageRecorder != nil {
+		writeEventRecorder = metric.NewUsageEventRecorder(writeEventRecorder, m.usageRecorder, platform.UsageWriteRequestCount, platform.UsageWriteRequestBytes)
+		queryEventRecorder = metric.NewUsageEventRecorder(queryEventRecorder, m.usageRecorder, platform.UsageQueryRequestCount, platform.UsageQueryRequestBytes)
+	}
+
 	m.apibackend = &http.APIBackend{
 		AssetsPath:           m.assetsPath,
 		HTTPErrorHandler:     http.ErrorHandler(0),
 		Logger:               m.logger,
 		SessionRenewDisabled: m.sessionRenewDisabled,
+		GraphQLEnabled:       m.graphQLEnabled,
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
-		AuthorizationService: authSvc,
+		AuthorizationService: auditAuthSvc,
 		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
-		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		BucketService:                   auditBucketSvc,
 		SessionService:                  sessionSvc,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
 		UserResourceMappingService:      userResourceSvc,
+		UserResourceMappingBatchService: m.kvService,
 		LabelService:                    labelSvc,
 		DashboardService:                dashboardSvc,
 		DashboardOperationLogService:    dashboardLogSvc,
+		DBRPMappingService:              m.kvService,
 		BucketOperationLogService:       bucketLogSvc,
 		UserOperationLogService:         userLogSvc,
 		OrganizationOperationLogService: orgLogSvc,
@@ -621,16 +1219,67 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		OnboardingService:               onboardingSvc,
 		InfluxQLService:                 nil, // No InfluxQL support
 		FluxService:                     storageQueryService,
+		QueryService:                    query.QueryServiceBridge{AsyncQueryService: m.queryController},
 		TaskService:                     taskSvc,
+		TeamService:                     m.kvService,
+		NotificationEndpointService:     m.kvService,
+		NotificationSender:              notificationSender,
+		CheckService:                    m.kvService,
+		CheckStatusService:              checkStatusSvc,
+		NotificationRuleService:         m.kvService,
+		SentNotificationService:         m.kvService,
+		SilenceService:                  m.kvService,
+		AnnotationService:               m.kvService,
+		StackService:                    m.kvService,
+		InviteService:                   m.kvService,
+		AuditService:                    m.kvService,
+		AuditExportService:              m.kvService,
+		DashboardShareService:           m.kvService,
+		PasswordResetService:            m.kvService,
+		TaskTemplateService:             m.kvService,
+		FluxPackageService:              m.kvService,
+		TaskRunImporter:                 taskRunImporter,
+		SchedulerStateService:           m.scheduler,
+		RunLogStreamer:                  logBroadcaster,
+		TaskDebugService:                taskDebugSvc,
 		TelegrafService:                 telegrafSvc,
+		TelegrafAgentService:            m.kvService,
 		ScraperTargetStoreService:       scraperTargetSvc,
+		ScraperTargetStatusService:      m.kvService,
 		ChronografService:               chronografSvc,
-		SecretService:                   secretSvc,
+		SecretService:                   auditSecretSvc,
+		SecretRotationService:           secretRotateSvc,
 		LookupService:                   lookupSvc,
+		MaintenanceService:              maintenanceSvc,
+		MaintenanceScheduleService:      m.engine.MaintenanceScheduler(),
+		SystemInfoService:               systemInfoSvc,
+		LimitsSimulationService:         limitsSimulationSvc,
 		DocumentService:                 m.kvService,
+		DropSeriesService:               storage.NewDropSeriesService(m.engine, m.logger),
+		DeleteService:                   storage.NewDeleteService(m.engine, m.logger),
+		CardinalityService:              storage.NewCardinalityService(m.engine, query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.logger),
+		OrgUsageService:                 storage.NewOrgUsageService(query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.logger),
+		RollupVerificationService:       storage.NewRollupVerificationService(query.QueryServiceBridge{AsyncQueryService: m.queryController}, m.logger),
+		MeasurementSchemaService:        m.kvService,
+		SeriesSchemaService:             storage.NewSeriesSchemaService(m.engine),
+		SchemaDocService:                schemaDocSvc,
+		SchemaDescriptionService:        m.kvService,
+		QueryViewService:                queryViewSvc,
+		RunningQueryService:             m.queryController,
+		QueryHistoryService:             m.kvService,
+		SearchService:                   m.kvService,
+		TrashService:                    trashSvc,
+		RevisionService:                 revisionSvc,
+		BucketQuotaService:              quotaSvc,
+		OrgDeletionService:              orgDeletionSvc,
 		OrgLookupService:                m.kvService,
-		WriteEventRecorder:              infprom.NewEventRecorder("write"),
-		QueryEventRecorder:              infprom.NewEventRecorder("query"),
+		ClientCertAuthenticator:         clientCertAuthenticator,
+		WriteEventRecorder:              writeEventRecorder,
+		WriteRejectionRecorder:          writeRejectionRecorder,
+		QueryDefaultTimezone:            m.queryDefaultTimezone,
+		QueryCacheHeaders:               m.queryHTTPCache,
+		QueryStream:                     m.queryHTTPStream,
+		QueryEventRecorder:              queryEventRecorder,
 	}
 
 	m.reg.MustRegister(m.apibackend.PrometheusCollectors()...)
@@ -664,9 +1313,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.wg.Add(1)
 	go func(logger *zap.Logger) {
 		defer m.wg.Done()
-		logger.Info("Listening", zap.String("transport", "http"), zap.String("addr", m.httpBindAddress), zap.Int("port", m.httpPort))
 
-		if err := m.httpServer.Serve(ln); err != nethttp.ErrServerClosed {
+		var err error
+		if m.tlsCert != "" {
+			logger.Info("Listening", zap.String("transport", "https"), zap.String("addr", m.httpBindAddress), zap.Int("port", m.httpPort))
+			err = m.httpServer.ServeTLS(ln, m.tlsCert, m.tlsKey)
+		} else {
+			logger.Info("Listening", zap.String("transport", "http"), zap.String("addr", m.httpBindAddress), zap.Int("port", m.httpPort))
+			err = m.httpServer.Serve(ln)
+		}
+		if err != nethttp.ErrServerClosed {
 			logger.Error("failed http service", zap.Error(err))
 		}
 		logger.Info("Stopping")
@@ -675,6 +1331,76 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	return nil
 }
 
+// configureTLS configures the HTTP server to serve HTTPS if a certificate is
+// set, and to verify the client certificates that requests present if client
+// certificate authorities are set.
+func (m *Launcher) configureTLS() error {
+	if m.tlsCert == "" {
+		if m.tlsKey != "" || m.tlsClientCA != "" || m.tlsClientCertRules != "" {
+			return fmt.Errorf("tls-cert is required to use tls-key, tls-client-ca or tls-client-cert-rules")
+		}
+		return nil
+	}
+	if m.tlsKey == "" {
+		return fmt.Errorf("tls-key is required with tls-cert")
+	}
+	if m.tlsClientCertRules != "" && m.tlsClientCA == "" {
+		return fmt.Errorf("tls-client-ca is required to verify the client certificates of tls-client-cert-rules")
+	}
+
+	m.httpServer.TLSConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if m.tlsClientCA == "" {
+		return nil
+	}
+
+	pem, err := ioutil.ReadFile(m.tlsClientCA)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("no certificates found in tls-client-ca %s", m.tlsClientCA)
+	}
+
+	// Clients without a certificate may still authenticate with tokens or sessions.
+	m.httpServer.TLSConfig.ClientCAs = pool
+	m.httpServer.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	return nil
+}
+
+// loadClientCertRules reads the JSON array of client certificate rules at path.
+func loadClientCertRules(path string) ([]platform.ClientCertRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []platform.ClientCertRule
+	if err := json.NewDecoder(f).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("invalid client certificate rules %s: %v", path, err)
+	}
+	return rules, nil
+}
+
+// readPasswordDenyList reads the passwords at path, one per line, skipping blank lines.
+func readPasswordDenyList(path string) ([]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var denyList []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			denyList = append(denyList, line)
+		}
+	}
+	return denyList, nil
+}
+
 // OrganizationService returns the internal organization service.
 func (m *Launcher) OrganizationService() platform.OrganizationService {
 	return m.apibackend.OrganizationService
@@ -31,6 +31,27 @@ func GetAuthorizer(ctx context.Context) (platform.Authorizer, error) {
 	return a, nil
 }
 
+// GetAuthorization retrieves the authorization that acts for the authorizer on
+// context in the organization, such as to run a query on its behalf. A session
+// acts with an ephemeral authorization for the organization.
+func GetAuthorization(ctx context.Context, orgID platform.ID) (*platform.Authorization, error) {
+	a, err := GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch a := a.(type) {
+	case *platform.Authorization:
+		return a, nil
+	case *platform.Session:
+		return a.EphemeralAuth(orgID), nil
+	}
+	return nil, &platform.Error{
+		Code: platform.EUnauthorized,
+		Err:  platform.ErrAuthorizerNotSupported,
+	}
+}
+
 // GetToken retrieves a token from the context; errors if no token.
 func GetToken(ctx context.Context) (string, error) {
 	a, ok := ctx.Value(authorizerCtxKey).(platform.Authorizer)
@@ -22,3 +22,27 @@ func TestGetToken(t *testing.T) {
 		t.Errorf("GetToken() want %s, got %s", want, got)
 	}
 }
+
+func TestGetAuthorization(t *testing.T) {
+	auth := &influxdb.Authorization{ID: 1, OrgID: 2, Token: "howdy"}
+	got, err := icontext.GetAuthorization(icontext.SetAuthorizer(context.Background(), auth), 2)
+	if err != nil {
+		t.Fatalf("unexpected error while retrieving authorization: %v", err)
+	}
+	if got != auth {
+		t.Errorf("GetAuthorization() want %v, got %v", auth, got)
+	}
+
+	session := &influxdb.Session{ID: 3, UserID: 4, Permissions: []influxdb.Permission{}}
+	got, err = icontext.GetAuthorization(icontext.SetAuthorizer(context.Background(), session), 2)
+	if err != nil {
+		t.Fatalf("unexpected error while retrieving authorization: %v", err)
+	}
+	if got.OrgID != 2 || got.UserID != 4 {
+		t.Errorf("GetAuthorization() want an ephemeral authorization of user 4 in org 2, got %+v", got)
+	}
+
+	if _, err := icontext.GetAuthorization(context.Background(), 2); err == nil {
+		t.Error("GetAuthorization() want an error without an authorizer")
+	}
+}
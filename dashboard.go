@@ -79,6 +79,8 @@ type Dashboard struct {
 	Description    string        `json:"description"`
 	Cells          []*Cell       `json:"cells"`
 	Meta           DashboardMeta `json:"meta"`
+	// Metadata is free-form key/value metadata, such as the ID of the dashboard in another system.
+	Metadata Metadata `json:"metadata,omitempty"`
 }
 
 // DashboardMeta contains meta information about dashboards
@@ -92,32 +94,33 @@ var DefaultDashboardFindOptions = FindOptions{
 	SortBy: "ID",
 }
 
-// SortDashboards sorts a slice of dashboards by a field.
+// DashboardSortKeys are the keys dashboards can be sorted by.
+var DashboardSortKeys = []string{"ID", "Name", "CreatedAt", "UpdatedAt"}
+
+// SortDashboards sorts a slice of dashboards by a field, or by ID if the
+// options have no sort key.
 func SortDashboards(opts FindOptions, ds []*Dashboard) {
 	var sorter func(i, j int) bool
-	switch opts.SortBy {
-	case "CreatedAt":
+	switch opts.sortKey() {
+	case "createdat":
 		sorter = func(i, j int) bool {
 			return ds[j].Meta.CreatedAt.After(ds[i].Meta.CreatedAt)
 		}
-	case "UpdatedAt":
+	case "updatedat":
 		sorter = func(i, j int) bool {
 			return ds[j].Meta.UpdatedAt.After(ds[i].Meta.UpdatedAt)
 		}
-	case "Name":
+	case "name":
 		sorter = func(i, j int) bool {
 			return ds[i].Name < ds[j].Name
 		}
 	default:
 		sorter = func(i, j int) bool {
-			if opts.Descending {
-				return ds[i].ID > ds[j].ID
-			}
 			return ds[i].ID < ds[j].ID
 		}
 	}
 
-	sort.Slice(ds, sorter)
+	sort.SliceStable(ds, opts.ordered(sorter))
 }
 
 // Cell holds positional information about a cell on dashboard and a reference to a cell.
@@ -139,6 +142,23 @@ type DashboardFilter struct {
 	IDs            []*ID
 	OrganizationID *ID
 	Organization   *string
+	// Metadata restricts the dashboards to those with all its keys and values.
+	Metadata Metadata
+}
+
+// FilterByMetadata filters the dashboards down to those with the metadata of the
+// filter. It reuses the backing array of ds rather than allocating.
+func (f DashboardFilter) FilterByMetadata(ds []*Dashboard) []*Dashboard {
+	if len(f.Metadata) == 0 {
+		return ds
+	}
+	fds := ds[:0]
+	for _, d := range ds {
+		if d.Metadata.Matches(f.Metadata) {
+			fds = append(fds, d)
+		}
+	}
+	return fds
 }
 
 // QueryParams turns a dashboard filter into query params
@@ -160,17 +180,30 @@ func (f DashboardFilter) QueryParams() map[string][]string {
 		qp.Add("org", *f.Organization)
 	}
 
+	for _, m := range f.Metadata.QueryParams() {
+		qp.Add("metadata", m)
+	}
+
 	return qp
 }
 
 // DashboardUpdate is the patch structure for a dashboard.
 type DashboardUpdate struct {
-	Name        *string `json:"name"`
-	Description *string `json:"description"`
+	Name        *string        `json:"name"`
+	Description *string        `json:"description"`
+	Metadata    MetadataUpdate `json:"metadata,omitempty"`
 }
 
 // Apply applies an update to a dashboard.
 func (u DashboardUpdate) Apply(d *Dashboard) error {
+	if u.Metadata != nil {
+		m, err := u.Metadata.Apply(d.Metadata)
+		if err != nil {
+			return err
+		}
+		d.Metadata = m
+	}
+
 	if u.Name != nil {
 		d.Name = *u.Name
 	}
@@ -184,7 +217,7 @@ func (u DashboardUpdate) Apply(d *Dashboard) error {
 
 // Valid returns an error if the dashboard update is invalid.
 func (u DashboardUpdate) Valid() *Error {
-	if u.Name == nil && u.Description == nil {
+	if u.Name == nil && u.Description == nil && u.Metadata == nil {
 		return &Error{
 			Code: EInvalid,
 			Msg:  "must update at least one attribute",
@@ -0,0 +1,313 @@
+package influxdb
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DashboardExportVersion is the version of the dashboard export format written by this instance.
+const DashboardExportVersion = 1
+
+// DashboardExport is the portable representation of a dashboard.
+// Its queries refer to buckets by name rather than by ID, and it carries the
+// variables and buckets its cells reference, so that it can be imported into
+// an organization on another instance.
+type DashboardExport struct {
+	Version    int    `json:"version"`
+	ExportedAt string `json:"exportedAt,omitempty"`
+
+	// SourceDashboardID and SourceOrgID identify the dashboard on the instance it was exported from.
+	SourceDashboardID ID `json:"sourceDashboardID,omitempty"`
+	SourceOrgID       ID `json:"sourceOrgID,omitempty"`
+
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Metadata    Metadata `json:"metadata,omitempty"`
+
+	Cells     []DashboardExportCell     `json:"cells"`
+	Variables []DashboardExportVariable `json:"variables"`
+	Buckets   []DashboardExportBucket   `json:"buckets"`
+}
+
+// DashboardExportCell is a cell of an exported dashboard with its view.
+type DashboardExportCell struct {
+	CellProperty
+	View View `json:"view"`
+}
+
+// DashboardExportVariable is a variable referenced by the cells of an exported dashboard.
+// Variables are matched by name on import, and created if missing.
+type DashboardExportVariable struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Selected    []string           `json:"selected"`
+	Arguments   *VariableArguments `json:"arguments"`
+}
+
+// DashboardExportBucket is a bucket referenced by the queries of an exported dashboard.
+// Buckets are matched by name on import, and created if missing.
+type DashboardExportBucket struct {
+	Name            string        `json:"name"`
+	Description     string        `json:"description,omitempty"`
+	RetentionPeriod time.Duration `json:"retentionPeriod"`
+}
+
+// NewDashboardExport returns the export of d without cells, variables or buckets.
+func NewDashboardExport(d *Dashboard, now time.Time) *DashboardExport {
+	return &DashboardExport{
+		Version:           DashboardExportVersion,
+		ExportedAt:        now.UTC().Format(time.RFC3339),
+		SourceDashboardID: d.ID,
+		SourceOrgID:       d.OrganizationID,
+		Name:              d.Name,
+		Description:       d.Description,
+		Metadata:          d.Metadata,
+		Cells:             []DashboardExportCell{},
+		Variables:         []DashboardExportVariable{},
+		Buckets:           []DashboardExportBucket{},
+	}
+}
+
+// NewDashboardExportVariable returns the export of v, with the references to the
+// buckets in names by ID in its query replaced by references to them by name.
+func NewDashboardExportVariable(v *Variable, names map[ID]string) DashboardExportVariable {
+	args := v.Arguments
+	if args != nil {
+		if q, ok := args.Values.(VariableQueryValues); ok {
+			q.Query = ReplaceBucketIDReferences(q.Query, names)
+			args = &VariableArguments{Type: args.Type, Values: q}
+		}
+	}
+	selected := v.Selected
+	if selected == nil {
+		selected = []string{}
+	}
+	return DashboardExportVariable{
+		Name:        v.Name,
+		Description: v.Description,
+		Selected:    selected,
+		Arguments:   args,
+	}
+}
+
+// Variable returns the values to create the exported variable in the organization.
+func (e DashboardExportVariable) Variable(orgID ID) *Variable {
+	return &Variable{
+		OrganizationID: orgID,
+		Name:           e.Name,
+		Description:    e.Description,
+		Selected:       e.Selected,
+		Arguments:      e.Arguments,
+	}
+}
+
+// VariableQuery returns the query of v if it is a query variable, or an empty string.
+func VariableQuery(v *Variable) string {
+	if v.Arguments == nil {
+		return ""
+	}
+	if q, ok := v.Arguments.Values.(VariableQueryValues); ok {
+		return q.Query
+	}
+	return ""
+}
+
+// Validate returns an error if the export cannot be imported by this instance.
+func (e *DashboardExport) Validate() error {
+	switch {
+	case e.Version < 1 || e.Version > DashboardExportVersion:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unsupported dashboard export version %d", e.Version),
+		}
+	case e.Name == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "dashboard export is missing a name",
+		}
+	}
+	if err := e.Metadata.Valid(); err != nil {
+		return err
+	}
+	for _, v := range e.Variables {
+		if v.Name == "" || v.Arguments == nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "dashboard export has a variable without a name or arguments",
+			}
+		}
+	}
+	for _, b := range e.Buckets {
+		if b.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "dashboard export has a bucket without a name",
+			}
+		}
+	}
+	return nil
+}
+
+// DashboardImport is the set of values to import an exported dashboard into an organization.
+type DashboardImport struct {
+	OrganizationID ID     `json:"orgID,omitempty"`
+	Organization   string `json:"org,omitempty"`
+	// Name overrides the name of the exported dashboard, i.e. when the organization
+	// already has a dashboard of that name.
+	Name string `json:"name,omitempty"`
+	// SkipBuckets leaves the buckets missing from the organization uncreated.
+	// The queries that reference them fail until they exist.
+	SkipBuckets bool            `json:"skipBuckets,omitempty"`
+	Dashboard   DashboardExport `json:"dashboard"`
+}
+
+// Validate returns an error if the dashboard cannot be imported.
+func (i DashboardImport) Validate() error {
+	if !i.OrganizationID.Valid() && i.Organization == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "missing orgID and org",
+		}
+	}
+	if err := i.Dashboard.Validate(); err != nil {
+		return &Error{
+			Msg: fmt.Sprintf("dashboard: %s", ErrorMessage(err)),
+			Err: err,
+		}
+	}
+	return nil
+}
+
+var (
+	// bucketIDReference matches the references to buckets by ID in a query, such as from(bucketID: "0000000000000001").
+	bucketIDReference = regexp.MustCompile(`\bbucketID\s*:\s*"([0-9a-fA-F]{16})"`)
+	// bucketNameReference matches the references to buckets by name in a query, such as from(bucket: "telegraf").
+	bucketNameReference = regexp.MustCompile(`\bbucket\s*:\s*"((?:[^"\\]|\\.)*)"`)
+
+	fluxStringUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`)
+)
+
+// BucketIDReferences returns the IDs of the buckets the query refers to by ID, in order.
+func BucketIDReferences(query string) []ID {
+	seen := map[ID]bool{}
+	var ids []ID
+	for _, m := range bucketIDReference.FindAllStringSubmatch(query, -1) {
+		var id ID
+		if err := id.DecodeFromString(m[1]); err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// BucketNameReferences returns the names of the buckets the query refers to by name, in order.
+func BucketNameReferences(query string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, m := range bucketNameReference.FindAllStringSubmatch(query, -1) {
+		name := fluxStringUnescaper.Replace(m[1])
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReplaceBucketIDReferences returns query with its references to the buckets in
+// names by ID replaced by references to them by name. Other references are kept.
+func ReplaceBucketIDReferences(query string, names map[ID]string) string {
+	return bucketIDReference.ReplaceAllStringFunc(query, func(ref string) string {
+		var id ID
+		if err := id.DecodeFromString(bucketIDReference.FindStringSubmatch(ref)[1]); err != nil {
+			return ref
+		}
+		name, ok := names[id]
+		if !ok {
+			return ref
+		}
+		return "bucket: " + fluxString(name)
+	})
+}
+
+// ViewBucketReferences returns the IDs and names of the buckets the queries of the view
+// refer to, including the buckets selected by the query builder, in order.
+func ViewBucketReferences(p ViewProperties) ([]ID, []string) {
+	var (
+		ids      []ID
+		names    []string
+		seenID   = map[ID]bool{}
+		seenName = map[string]bool{}
+	)
+	for _, q := range ViewQueries(p) {
+		for _, id := range BucketIDReferences(q.Text) {
+			if !seenID[id] {
+				seenID[id] = true
+				ids = append(ids, id)
+			}
+		}
+		for _, name := range append(BucketNameReferences(q.Text), q.BuilderConfig.Buckets...) {
+			if !seenName[name] {
+				seenName[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	sort.Strings(names)
+	return ids, names
+}
+
+// ReplaceViewBucketIDReferences returns a copy of p with the references to the buckets
+// in names by ID in its queries replaced by references to them by name.
+func ReplaceViewBucketIDReferences(p ViewProperties, names map[ID]string) ViewProperties {
+	qs := ViewQueries(p)
+	if qs == nil {
+		return p
+	}
+	replaced := make([]DashboardQuery, len(qs))
+	for i, q := range qs {
+		q.Text = ReplaceBucketIDReferences(q.Text, names)
+		replaced[i] = q
+	}
+	return withViewQueries(p, replaced)
+}
+
+// withViewQueries returns a copy of p with its queries set to qs.
+func withViewQueries(p ViewProperties, qs []DashboardQuery) ViewProperties {
+	switch p := p.(type) {
+	case XYViewProperties:
+		p.Queries = qs
+		return p
+	case LinePlusSingleStatProperties:
+		p.Queries = qs
+		return p
+	case SingleStatViewProperties:
+		p.Queries = qs
+		return p
+	case HistogramViewProperties:
+		p.Queries = qs
+		return p
+	case HeatmapViewProperties:
+		p.Queries = qs
+		return p
+	case ScatterViewProperties:
+		p.Queries = qs
+		return p
+	case GaugeViewProperties:
+		p.Queries = qs
+		return p
+	case TableViewProperties:
+		p.Queries = qs
+		return p
+	}
+	return p
+}
@@ -0,0 +1,118 @@
+package influxdb_test
+
+import (
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestBucketReferences(t *testing.T) {
+	query := `a = from(bucketID: "0000000000000002") |> range(start: -1h)
+b = from(bucket: "tele\"graf") |> range(start: -1h)
+c = from(bucketID:"0000000000000001") |> range(start: -1h)
+d = from(bucket: "system") |> filter(fn: (r) => r.mybucket == "x")
+e = from(bucketID: "0000000000000002") |> range(start: -1h)`
+
+	if got, want := platform.BucketIDReferences(query), []platform.ID{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got bucket IDs %v, want %v", got, want)
+	}
+	if got, want := platform.BucketNameReferences(query), []string{"system", `tele"graf`}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got bucket names %q, want %q", got, want)
+	}
+
+	replaced := platform.ReplaceBucketIDReferences(query, map[platform.ID]string{2: `my "bucket"`})
+	want := `a = from(bucket: "my \"bucket\"") |> range(start: -1h)
+b = from(bucket: "tele\"graf") |> range(start: -1h)
+c = from(bucketID:"0000000000000001") |> range(start: -1h)
+d = from(bucket: "system") |> filter(fn: (r) => r.mybucket == "x")
+e = from(bucket: "my \"bucket\"") |> range(start: -1h)`
+	if replaced != want {
+		t.Errorf("unexpected query:\n%s\nwant:\n%s", replaced, want)
+	}
+}
+
+func TestViewBucketReferences(t *testing.T) {
+	view := platform.XYViewProperties{
+		Type: "xy",
+		Queries: []platform.DashboardQuery{
+			{Text: `from(bucketID: "0000000000000003") |> range(start: v.timeRangeStart)`},
+			{
+				Text:          `from(bucket: "telegraf") |> range(start: v.timeRangeStart)`,
+				BuilderConfig: platform.BuilderConfig{Buckets: []string{"system", "telegraf"}},
+			},
+		},
+	}
+
+	ids, names := platform.ViewBucketReferences(view)
+	if want := []platform.ID{3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got bucket IDs %v, want %v", ids, want)
+	}
+	if want := []string{"system", "telegraf"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got bucket names %q, want %q", names, want)
+	}
+
+	replaced := platform.ReplaceViewBucketIDReferences(view, map[platform.ID]string{3: "metrics"}).(platform.XYViewProperties)
+	if got, want := replaced.Queries[0].Text, `from(bucket: "metrics") |> range(start: v.timeRangeStart)`; got != want {
+		t.Errorf("got query %q, want %q", got, want)
+	}
+	if got := view.Queries[0].Text; got != `from(bucketID: "0000000000000003") |> range(start: v.timeRangeStart)` {
+		t.Errorf("expected the view to be left unchanged, got query %q", got)
+	}
+}
+
+func TestDashboardImport_Validate(t *testing.T) {
+	valid := platform.DashboardExport{Version: 1, Name: "hosts"}
+	constant := &platform.VariableArguments{Type: "constant", Values: platform.VariableConstantValues{"a"}}
+
+	tests := []struct {
+		name    string
+		imp     platform.DashboardImport
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			imp:  platform.DashboardImport{Organization: "org", Dashboard: valid},
+		},
+		{
+			name:    "missing organization",
+			imp:     platform.DashboardImport{Dashboard: valid},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported version",
+			imp:     platform.DashboardImport{OrganizationID: 1, Dashboard: platform.DashboardExport{Version: 2, Name: "hosts"}},
+			wantErr: true,
+		},
+		{
+			name: "variable without a name",
+			imp: platform.DashboardImport{OrganizationID: 1, Dashboard: platform.DashboardExport{
+				Version:   1,
+				Name:      "hosts",
+				Variables: []platform.DashboardExportVariable{{Arguments: constant}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "bucket without a name",
+			imp: platform.DashboardImport{OrganizationID: 1, Dashboard: platform.DashboardExport{
+				Version: 1,
+				Name:    "hosts",
+				Buckets: []platform.DashboardExportBucket{{}},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.imp.Validate()
+			if tt.wantErr && platform.ErrorCode(err) != platform.EInvalid {
+				t.Errorf("expected an invalid error, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrDashboardShareNotFound is the error msg for a missing dashboard share.
+const ErrDashboardShareNotFound = "dashboard share not found"
+
+// DefaultDashboardShareTTL is how long a dashboard share can be viewed for when it has no expiration.
+const DefaultDashboardShareTTL = 7 * 24 * time.Hour
+
+// DashboardShareMaxResultBytes is the maximum size of the results a frozen dashboard share stores.
+const DashboardShareMaxResultBytes = 16 * 1024 * 1024
+
+// ops for dashboard share errors and op log.
+const (
+	OpCreateDashboardShare      = "CreateDashboardShare"
+	OpFindDashboardShareByID    = "FindDashboardShareByID"
+	OpFindDashboardShareByToken = "FindDashboardShareByToken"
+	OpFindDashboardShares       = "FindDashboardShares"
+	OpRevokeDashboardShare      = "RevokeDashboardShare"
+)
+
+// DashboardShareMode is how a dashboard share gets the data it shows.
+type DashboardShareMode string
+
+const (
+	// DashboardShareFrozen shows the results of its queries at the time it was created.
+	DashboardShareFrozen DashboardShareMode = "frozen"
+	// DashboardShareLive queries the data it shows whenever it is viewed, with a
+	// read-only authorization limited to the buckets its queries read.
+	DashboardShareLive DashboardShareMode = "live"
+)
+
+// Valid returns an error if the mode is not frozen or live.
+func (m DashboardShareMode) Valid() error {
+	switch m {
+	case DashboardShareFrozen, DashboardShareLive:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid dashboard share mode %q, must be frozen or live", m),
+	}
+}
+
+// DashboardShareStatus is the state of a dashboard share.
+type DashboardShareStatus string
+
+const (
+	// DashboardShareActive is a share that can be viewed.
+	DashboardShareActive DashboardShareStatus = "active"
+	// DashboardShareExpired is a share that can no longer be viewed.
+	DashboardShareExpired DashboardShareStatus = "expired"
+)
+
+// DashboardShare is a read-only copy of a dashboard that can be viewed by
+// anyone with its token, such as people who have no user. The cells of a
+// share are copied when it is created; later changes to the dashboard are
+// not shared.
+type DashboardShare struct {
+	ID          ID                   `json:"id,omitempty"`
+	DashboardID ID                   `json:"dashboardID"`
+	OrgID       ID                   `json:"orgID"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Mode        DashboardShareMode   `json:"mode"`
+	Token       string               `json:"token,omitempty"`
+	Status      DashboardShareStatus `json:"status"`
+
+	// Start and Stop are the time range of a frozen share.
+	Start time.Time `json:"start,omitempty"`
+	Stop  time.Time `json:"stop,omitempty"`
+	// Range is how far back from the time it is viewed a live share shows, such as 1h.
+	Range string `json:"range,omitempty"`
+
+	// AuthorizationID is the read-only authorization a live share queries with.
+	AuthorizationID ID `json:"authorizationID,omitempty"`
+
+	Cells []DashboardShareCell `json:"cells"`
+
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedBy ID        `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DashboardShareCell is a cell of a dashboard share. The references to variables in the
+// queries of its view are replaced by their values, except for the time range.
+type DashboardShareCell struct {
+	ID ID `json:"id"`
+	CellProperty
+	View View `json:"view"`
+	// Results are the annotated CSV results of the queries of the view of a frozen share, in order.
+	Results []string `json:"results,omitempty"`
+}
+
+// Valid returns an error if the share has no dashboard or organization, or has an invalid
+// mode or time range.
+func (s *DashboardShare) Valid() error {
+	if !s.DashboardID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "dashboardID is required",
+		}
+	}
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if err := s.Mode.Valid(); err != nil {
+		return err
+	}
+	if s.Mode == DashboardShareLive {
+		if _, err := s.RangeDuration(); err != nil {
+			return err
+		}
+		return nil
+	}
+	if !s.Start.Before(s.Stop) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "start must be before stop",
+		}
+	}
+	return nil
+}
+
+// RangeDuration returns the range of a live share as a duration.
+func (s *DashboardShare) RangeDuration() (time.Duration, error) {
+	d, err := time.ParseDuration(s.Range)
+	if err != nil || d <= 0 {
+		return 0, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("range of a live share must be a positive duration, got %q", s.Range),
+		}
+	}
+	return d, nil
+}
+
+// TimeRange returns the time range the share shows when it is viewed at now.
+func (s *DashboardShare) TimeRange(now time.Time) (start, stop time.Time, err error) {
+	if s.Mode != DashboardShareLive {
+		return s.Start, s.Stop, nil
+	}
+	d, err := s.RangeDuration()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return now.Add(-d), now, nil
+}
+
+// StatusAt returns the status of the share at now; a share expires at its expiration.
+func (s *DashboardShare) StatusAt(now time.Time) DashboardShareStatus {
+	if !now.Before(s.ExpiresAt) {
+		return DashboardShareExpired
+	}
+	return DashboardShareActive
+}
+
+// DashboardShareFilter selects dashboard shares.
+type DashboardShareFilter struct {
+	DashboardID *ID
+	OrgID       *ID
+}
+
+// DashboardShareService manages the read-only shares of dashboards.
+type DashboardShareService interface {
+	// CreateDashboardShare creates a share and sets its ID and token. The
+	// share expires after the DefaultDashboardShareTTL if it has no expiration.
+	CreateDashboardShare(ctx context.Context, s *DashboardShare) error
+
+	// FindDashboardShareByID returns a single share by ID.
+	FindDashboardShareByID(ctx context.Context, id ID) (*DashboardShare, error)
+
+	// FindDashboardShareByToken returns the share with the token.
+	FindDashboardShareByToken(ctx context.Context, token string) (*DashboardShare, error)
+
+	// FindDashboardShares returns the shares that match the filter.
+	FindDashboardShares(ctx context.Context, filter DashboardShareFilter) ([]*DashboardShare, error)
+
+	// RevokeDashboardShare deletes a share so it can no longer be viewed.
+	RevokeDashboardShare(ctx context.Context, id ID) error
+}
@@ -0,0 +1,278 @@
+package influxdb
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Names of the variables that describe the time range a dashboard is viewed in.
+// They are defined for every dashboard query, in addition to the variables of the organization.
+const (
+	TimeRangeStartVariable = "timeRangeStart"
+	TimeRangeStopVariable  = "timeRangeStop"
+	WindowPeriodVariable   = "windowPeriod"
+)
+
+// DashboardWindowCount is the number of windows the time range of a dashboard
+// is divided into to derive its window period.
+const DashboardWindowCount = 360
+
+// variableReference matches the references to dashboard variables, such as v.bucket, in a query.
+var variableReference = regexp.MustCompile(`(^|[^\w.])v\.([A-Za-z_]\w*)`)
+
+// A QueryResolver makes dashboard queries concrete by replacing their references
+// to dashboard variables with the values of the variables as Flux literals.
+type QueryResolver struct {
+	values     map[string]string
+	unresolved map[string]bool
+	// deferTimeRange leaves the variables of the time range to be resolved later.
+	deferTimeRange bool
+}
+
+// NewQueryResolver returns a QueryResolver for the time range from start to stop.
+// The value of a variable is its entry in selected if there is one, otherwise its
+// selected value, otherwise the first of its values. For map variables, the entry
+// selects a key of the map. Query variables without a selection are left unresolved.
+func NewQueryResolver(start, stop time.Time, variables []*Variable, selected map[string]string) (*QueryResolver, error) {
+	if !start.Before(stop) {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  "start must be before stop",
+		}
+	}
+
+	r := &QueryResolver{
+		values:     make(map[string]string, len(variables)+3),
+		unresolved: make(map[string]bool),
+	}
+
+	for _, v := range variables {
+		value, ok, err := variableValue(v, selected)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			r.values[v.Name] = fluxString(value)
+		}
+	}
+
+	windowPeriod := stop.Sub(start) / DashboardWindowCount
+	if windowPeriod < time.Millisecond {
+		windowPeriod = time.Millisecond
+	}
+	r.values[TimeRangeStartVariable] = start.UTC().Format(time.RFC3339Nano)
+	r.values[TimeRangeStopVariable] = stop.UTC().Format(time.RFC3339Nano)
+	r.values[WindowPeriodVariable] = fluxDuration(windowPeriod)
+
+	return r, nil
+}
+
+// variableValue returns the value v resolves to, and false if it can't be resolved.
+func variableValue(v *Variable, selected map[string]string) (string, bool, error) {
+	choice, ok := selected[v.Name]
+	if !ok && len(v.Selected) > 0 {
+		choice, ok = v.Selected[0], true
+	}
+	if v.Arguments == nil {
+		return choice, ok, nil
+	}
+
+	switch values := v.Arguments.Values.(type) {
+	case VariableConstantValues:
+		if !ok && len(values) > 0 {
+			choice, ok = values[0], true
+		}
+	case VariableMapValues:
+		if !ok {
+			keys := make([]string, 0, len(values))
+			for k := range values {
+				keys = append(keys, k)
+			}
+			if len(keys) == 0 {
+				return "", false, nil
+			}
+			sort.Strings(keys)
+			choice = keys[0]
+		}
+		value, found := values[choice]
+		if !found {
+			return "", false, &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("%q is not a key of variable %q", choice, v.Name),
+			}
+		}
+		return value, true, nil
+	}
+	return choice, ok, nil
+}
+
+// Resolve returns query with every reference to a known variable replaced by its value.
+// References to other variables are kept and reported by Unresolved.
+func (r *QueryResolver) Resolve(query string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range variableReference.FindAllStringSubmatchIndex(query, -1) {
+		// m[4]:m[5] is the variable name; the reference starts after the leading character at m[2]:m[3].
+		name := query[m[4]:m[5]]
+		value, ok := r.values[name]
+		if !ok {
+			if !r.deferTimeRange || !isTimeRangeVariable(name) {
+				r.unresolved[name] = true
+			}
+			continue
+		}
+		b.WriteString(query[last:m[3]])
+		b.WriteString(value)
+		last = m[5]
+	}
+	b.WriteString(query[last:])
+	return b.String()
+}
+
+// WithoutTimeRange returns a copy of r that leaves the references to the variables
+// of the time range unresolved, so they can be resolved when the query is run.
+// Those references are not reported by Unresolved.
+func (r *QueryResolver) WithoutTimeRange() *QueryResolver {
+	c := &QueryResolver{
+		values:         make(map[string]string, len(r.values)),
+		unresolved:     make(map[string]bool),
+		deferTimeRange: true,
+	}
+	for k, v := range r.values {
+		if !isTimeRangeVariable(k) {
+			c.values[k] = v
+		}
+	}
+	return c
+}
+
+func isTimeRangeVariable(name string) bool {
+	switch name {
+	case TimeRangeStartVariable, TimeRangeStopVariable, WindowPeriodVariable:
+		return true
+	}
+	return false
+}
+
+// ResolveView returns a copy of p with the text of each of its queries resolved.
+func (r *QueryResolver) ResolveView(p ViewProperties) ViewProperties {
+	switch p := p.(type) {
+	case XYViewProperties:
+		p.Queries = r.resolveQueries(p.Queries)
+		return p
+	case LinePlusSingleStatProperties:
+		p.Queries = r.resolveQueries(p.Queries)
+		return p
+	case SingleStatViewProperties:
+		p.Queries = r.resolveQueries(p.Queries)
+		return p
+	case HistogramViewProperties:
+		p.Queries = r.resolveQueries(p.Queries)
+		return p
+	case HeatmapViewProperties:
+		p.Queries = r.resolveQueries(p.Queries)
+		return p
+	case ScatterViewProperties:
+		p.Queries = r.resolveQueries(p.Queries)
+		return p
+	case GaugeViewProperties:
+		p.Queries = r.resolveQueries(p.Queries)
+		return p
+	case TableViewProperties:
+		p.Queries = r.resolveQueries(p.Queries)
+		return p
+	}
+	return p
+}
+
+// ViewQueries returns the queries of the view, or nil if the view has none.
+func ViewQueries(p ViewProperties) []DashboardQuery {
+	switch p := p.(type) {
+	case XYViewProperties:
+		return p.Queries
+	case LinePlusSingleStatProperties:
+		return p.Queries
+	case SingleStatViewProperties:
+		return p.Queries
+	case HistogramViewProperties:
+		return p.Queries
+	case HeatmapViewProperties:
+		return p.Queries
+	case ScatterViewProperties:
+		return p.Queries
+	case GaugeViewProperties:
+		return p.Queries
+	case TableViewProperties:
+		return p.Queries
+	}
+	return nil
+}
+
+// ReferencedVariables returns the names of the variables the queries of the
+// view reference, in order. The variables of the time range are left out, as
+// they are defined for every dashboard query.
+func ReferencedVariables(p ViewProperties) []string {
+	seen := map[string]bool{
+		TimeRangeStartVariable: true,
+		TimeRangeStopVariable:  true,
+		WindowPeriodVariable:   true,
+	}
+	var names []string
+	for _, q := range ViewQueries(p) {
+		for _, m := range variableReference.FindAllStringSubmatch(q.Text, -1) {
+			if name := m[2]; !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *QueryResolver) resolveQueries(qs []DashboardQuery) []DashboardQuery {
+	if qs == nil {
+		return nil
+	}
+	resolved := make([]DashboardQuery, len(qs))
+	for i, q := range qs {
+		q.Text = r.Resolve(q.Text)
+		resolved[i] = q
+	}
+	return resolved
+}
+
+// Values returns the Flux literal each known variable resolves to, by name.
+func (r *QueryResolver) Values() map[string]string {
+	values := make(map[string]string, len(r.values))
+	for k, v := range r.values {
+		values[k] = v
+	}
+	return values
+}
+
+// Unresolved returns the names of the variables referenced by resolved queries
+// that have no value, in order.
+func (r *QueryResolver) Unresolved() []string {
+	names := make([]string, 0, len(r.unresolved))
+	for name := range r.unresolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func fluxString(s string) string {
+	return `"` + escapeFluxString(s) + `"`
+}
+
+// fluxDuration formats d as a Flux duration literal with millisecond precision.
+func fluxDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+	return fmt.Sprintf("%dms", d/time.Millisecond)
+}
@@ -0,0 +1,141 @@
+package influxdb_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestQueryResolver(t *testing.T) {
+	start := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	stop := start.Add(time.Hour)
+
+	variables := []*platform.Variable{
+		{
+			Name:      "bucket",
+			Arguments: &platform.VariableArguments{Type: "constant", Values: platform.VariableConstantValues{"telegraf", "system"}},
+		},
+		{
+			Name:      "host",
+			Selected:  []string{"b"},
+			Arguments: &platform.VariableArguments{Type: "map", Values: platform.VariableMapValues{"a": "server-a", "b": `server "b"`}},
+		},
+		{
+			Name:      "region",
+			Arguments: &platform.VariableArguments{Type: "query", Values: platform.VariableQueryValues{Query: "buckets()", Language: "flux"}},
+		},
+	}
+
+	t.Run("resolves variables and the time range", func(t *testing.T) {
+		r, err := platform.NewQueryResolver(start, stop, variables, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		query := `from(bucket: v.bucket)
+  |> range(start: v.timeRangeStart, stop: v.timeRangeStop)
+  |> filter(fn: (r) => r.host == v.host and r.region == v.region)
+  |> aggregateWindow(every: v.windowPeriod, fn: mean)`
+		want := `from(bucket: "telegraf")
+  |> range(start: 2019-07-01T12:00:00Z, stop: 2019-07-01T13:00:00Z)
+  |> filter(fn: (r) => r.host == "server \"b\"" and r.region == v.region)
+  |> aggregateWindow(every: 10s, fn: mean)`
+		if got := r.Resolve(query); got != want {
+			t.Errorf("got query\n%s\nwant\n%s", got, want)
+		}
+		if got, want := r.Unresolved(), []string{"region"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got unresolved %v, want %v", got, want)
+		}
+	})
+
+	t.Run("selections override the variables", func(t *testing.T) {
+		r, err := platform.NewQueryResolver(start, stop, variables, map[string]string{
+			"bucket": "system",
+			"host":   "a",
+			"region": "us-west",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := r.Resolve(`v.bucket v.host v.region`)
+		if want := `"system" "server-a" "us-west"`; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if len(r.Unresolved()) != 0 {
+			t.Errorf("expected every variable to be resolved, got %v", r.Unresolved())
+		}
+	})
+
+	t.Run("resolves the queries of views", func(t *testing.T) {
+		r, err := platform.NewQueryResolver(start, stop, variables, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		props := platform.XYViewProperties{
+			Type:    "xy",
+			Queries: []platform.DashboardQuery{{Text: "from(bucket: v.bucket)", EditMode: "advanced"}},
+		}
+		got := r.ResolveView(props)
+		want := platform.XYViewProperties{
+			Type:    "xy",
+			Queries: []platform.DashboardQuery{{Text: `from(bucket: "telegraf")`, EditMode: "advanced"}},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+		if props.Queries[0].Text != "from(bucket: v.bucket)" {
+			t.Errorf("expected the original view to be unchanged, got %q", props.Queries[0].Text)
+		}
+	})
+
+	t.Run("rejects unknown map keys", func(t *testing.T) {
+		_, err := platform.NewQueryResolver(start, stop, variables, map[string]string{"host": "c"})
+		if platform.ErrorCode(err) != platform.EInvalid {
+			t.Fatalf("expected an invalid error, got %v", err)
+		}
+	})
+
+	t.Run("defers the time range", func(t *testing.T) {
+		r, err := platform.NewQueryResolver(start, stop, variables, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		live := r.WithoutTimeRange()
+		got := live.Resolve(`from(bucket: v.bucket) |> range(start: v.timeRangeStart, stop: v.timeRangeStop) |> filter(fn: (r) => r.host == v.missing)`)
+		if want := `from(bucket: "telegraf") |> range(start: v.timeRangeStart, stop: v.timeRangeStop) |> filter(fn: (r) => r.host == v.missing)`; got != want {
+			t.Errorf("got query %q, want %q", got, want)
+		}
+		if got := live.Unresolved(); len(got) != 1 || got[0] != "missing" {
+			t.Errorf("expected only the missing variable to be unresolved, got %v", got)
+		}
+		if _, ok := r.Values()[platform.TimeRangeStartVariable]; !ok {
+			t.Error("expected the original resolver to keep the time range")
+		}
+	})
+
+	t.Run("rejects empty time ranges", func(t *testing.T) {
+		_, err := platform.NewQueryResolver(stop, start, variables, nil)
+		if platform.ErrorCode(err) != platform.EInvalid {
+			t.Fatalf("expected an invalid error, got %v", err)
+		}
+	})
+}
+
+func TestReferencedVariables(t *testing.T) {
+	p := platform.XYViewProperties{
+		Queries: []platform.DashboardQuery{
+			{Text: `from(bucket: v.bucket) |> range(start: v.timeRangeStart) |> filter(fn: (r) => r.host == v.host)`},
+			{Text: `from(bucket: v.bucket) |> filter(fn: (r) => r.region == v.region and r.dc == env.v.dc)`},
+		},
+	}
+	if got, want := platform.ReferencedVariables(p), []string{"bucket", "host", "region"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := platform.ReferencedVariables(platform.MarkdownViewProperties{}); len(got) != 0 {
+		t.Errorf("expected a view without queries to reference no variables, got %v", got)
+	}
+}
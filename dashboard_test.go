@@ -88,3 +88,19 @@ func jsonEqual(s1, s2 string) (eq bool, err error) {
 
 	return cmp.Equal(o1, o2), nil
 }
+
+func TestDashboardFilter_FilterByMetadata(t *testing.T) {
+	prod := &platform.Dashboard{ID: 1, Metadata: platform.Metadata{"env": "prod", "team": "ops"}}
+	dev := &platform.Dashboard{ID: 2, Metadata: platform.Metadata{"env": "dev"}}
+	none := &platform.Dashboard{ID: 3}
+
+	ds := platform.DashboardFilter{Metadata: platform.Metadata{"env": "prod"}}.FilterByMetadata([]*platform.Dashboard{prod, dev, none})
+	if len(ds) != 1 || ds[0] != prod {
+		t.Errorf("got dashboards %v, want only the prod dashboard", ds)
+	}
+
+	ds = platform.DashboardFilter{}.FilterByMetadata([]*platform.Dashboard{prod, dev, none})
+	if len(ds) != 3 {
+		t.Errorf("got %d dashboards, want every dashboard without a metadata filter", len(ds))
+	}
+}
@@ -0,0 +1,58 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// OpDelete is the operation of a delete of data.
+const OpDelete = "Delete"
+
+// DeleteRequest selects the data of a bucket to delete: the points in the time
+// range of the series of the measurement, if there is one, that match all of the
+// tag predicates.
+type DeleteRequest struct {
+	OrgID    ID `json:"orgID"`
+	BucketID ID `json:"bucketID"`
+
+	// Start and Stop are the time range of the points deleted, which includes both.
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
+
+	Measurement string               `json:"measurement,omitempty"`
+	Tags        []SeriesTagPredicate `json:"tags,omitempty"`
+}
+
+// Valid returns an error if the request does not select a time range of a bucket.
+func (r DeleteRequest) Valid() error {
+	if !r.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucketID is required",
+		}
+	}
+	if r.Start.IsZero() || r.Stop.IsZero() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "start and stop are required",
+		}
+	}
+	if r.Stop.Before(r.Start) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "stop must not be before start",
+		}
+	}
+	for _, p := range r.Tags {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteService deletes data from buckets.
+type DeleteService interface {
+	// Delete deletes the data selected by the request. The data is gone once it returns.
+	Delete(ctx context.Context, req DeleteRequest) error
+}
@@ -0,0 +1,143 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Statuses of a DropSeriesOperation.
+const (
+	DropSeriesStatusQueued  = "queued"
+	DropSeriesStatusRunning = "running"
+	DropSeriesStatusSuccess = "success"
+	DropSeriesStatusFailed  = "failed"
+)
+
+// Operators of a SeriesTagPredicate.
+const (
+	TagPredicateEqual    = "=="
+	TagPredicateNotEqual = "!="
+	TagPredicateRegex    = "=~"
+	TagPredicateNotRegex = "!~"
+)
+
+// ErrDropSeriesOperationNotFound is returned when a drop series operation is not found.
+var ErrDropSeriesOperationNotFound = &Error{
+	Code: ENotFound,
+	Msg:  "drop series operation not found",
+}
+
+// SeriesTagPredicate matches series by the value of a single tag.
+// The value is a regular expression for the regex operators.
+type SeriesTagPredicate struct {
+	Key   string `json:"key"`
+	Op    string `json:"op,omitempty"`
+	Value string `json:"value"`
+}
+
+// Oper returns the operator of the predicate, defaulting to equality.
+func (p SeriesTagPredicate) Oper() string {
+	if p.Op == "" {
+		return TagPredicateEqual
+	}
+	return p.Op
+}
+
+// Valid returns an error if the predicate can't be evaluated.
+func (p SeriesTagPredicate) Valid() error {
+	if p.Key == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "tag predicate is missing a key",
+		}
+	}
+
+	switch p.Oper() {
+	case TagPredicateEqual, TagPredicateNotEqual:
+	case TagPredicateRegex, TagPredicateNotRegex:
+		if _, err := regexp.Compile(p.Value); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid regular expression for tag %q", p.Key),
+				Err:  err,
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid tag predicate operator %q", p.Op),
+		}
+	}
+	return nil
+}
+
+// DropSeriesRequest selects the series of a bucket to drop: every series of
+// the measurement that matches all of the tag predicates, regardless of time.
+type DropSeriesRequest struct {
+	OrgID       ID                   `json:"orgID,omitempty"`
+	BucketID    ID                   `json:"bucketID"`
+	Measurement string               `json:"measurement"`
+	Tags        []SeriesTagPredicate `json:"tags,omitempty"`
+}
+
+// Valid returns an error if the request does not select series of a single measurement.
+func (r DropSeriesRequest) Valid() error {
+	if !r.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucketID is required",
+		}
+	}
+	if r.Measurement == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "measurement is required",
+		}
+	}
+	for _, p := range r.Tags {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropSeriesOperation is the handle of an asynchronous drop of series.
+type DropSeriesOperation struct {
+	ID ID `json:"id"`
+	DropSeriesRequest
+
+	Status string `json:"status"`
+	// Error is the reason a failed operation failed.
+	Error string `json:"error,omitempty"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Done returns true if the operation finished, successfully or not.
+func (o *DropSeriesOperation) Done() bool {
+	return o.Status == DropSeriesStatusSuccess || o.Status == DropSeriesStatusFailed
+}
+
+// DropSeriesOperationFilter represents a set of filters that restrict the returned operations.
+type DropSeriesOperationFilter struct {
+	OrgID    *ID
+	BucketID *ID
+}
+
+// DropSeriesService drops series from the index and data of the storage engine.
+type DropSeriesService interface {
+	// DropSeries starts dropping the series selected by the request and
+	// returns the handle of the operation.
+	DropSeries(ctx context.Context, req DropSeriesRequest) (*DropSeriesOperation, error)
+
+	// FindDropSeriesOperationByID returns a single operation by ID.
+	FindDropSeriesOperationByID(ctx context.Context, id ID) (*DropSeriesOperation, error)
+
+	// FindDropSeriesOperations returns the operations that match filter, most recent first.
+	FindDropSeriesOperations(ctx context.Context, filter DropSeriesOperationFilter) ([]*DropSeriesOperation, error)
+}
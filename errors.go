@@ -24,6 +24,7 @@ const (
 	ETooManyRequests     = "too many requests"
 	EUnauthorized        = "unauthorized"
 	EMethodNotAllowed    = "method not allowed"
+	ELimitExceeded       = "limit exceeded" // a configured resource limit was exceeded
 )
 
 // Error is the error struct of platform.
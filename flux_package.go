@@ -0,0 +1,136 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+)
+
+// ErrFluxPackageNotFound is the error msg for a missing flux package.
+const ErrFluxPackageNotFound = "flux package not found"
+
+// ops for flux package error.
+const (
+	OpFindFluxPackageByID = "FindFluxPackageByID"
+	OpFindFluxPackages    = "FindFluxPackages"
+	OpCreateFluxPackage   = "CreateFluxPackage"
+	OpUpdateFluxPackage   = "UpdateFluxPackage"
+	OpDeleteFluxPackage   = "DeleteFluxPackage"
+)
+
+// FluxPackageService describes a service for managing the Flux packages of organizations.
+type FluxPackageService interface {
+	// FindFluxPackageByID finds a single flux package by its ID.
+	FindFluxPackageByID(ctx context.Context, id ID) (*FluxPackage, error)
+
+	// FindFluxPackages returns all flux packages that match the filter.
+	FindFluxPackages(ctx context.Context, filter FluxPackageFilter) ([]*FluxPackage, error)
+
+	// CreateFluxPackage creates a new flux package and assigns it an ID.
+	CreateFluxPackage(ctx context.Context, p *FluxPackage) error
+
+	// UpdateFluxPackage updates a single flux package with a changeset.
+	UpdateFluxPackage(ctx context.Context, id ID, upd FluxPackageUpdate) (*FluxPackage, error)
+
+	// DeleteFluxPackage removes a flux package by its ID.
+	DeleteFluxPackage(ctx context.Context, id ID) error
+}
+
+// FluxPackage is Flux source shared by the queries and tasks of an organization,
+// which import it by its name:
+//
+//	import "acme/alerts"
+//
+//	from(bucket: "telegraf") |> range(start: -1h) |> alerts.critical()
+//
+// The package clause of the source must match the last element of the name.
+// A package may import the Flux standard library, but not other packages of
+// the organization. The standard library takes precedence over packages with
+// the name of one of its packages.
+type FluxPackage struct {
+	ID             ID     `json:"id,omitempty"`
+	OrganizationID ID     `json:"orgID"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Source         string `json:"source"`
+	CRUDLog
+}
+
+var fluxPackageNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*(/[a-z][a-z0-9_]*)*$`)
+
+// Valid returns an error if the package can not be imported by its name.
+func (p *FluxPackage) Valid() error {
+	if !p.OrganizationID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "flux package orgID is required",
+		}
+	}
+	if !fluxPackageNameRegexp.MatchString(p.Name) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid flux package name %q; it must be a path of lower case identifiers such as acme/alerts", p.Name),
+		}
+	}
+
+	pkg := parser.ParseSource(p.Source)
+	if ast.Check(pkg) > 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "invalid flux package source",
+			Err:  ast.GetError(pkg),
+		}
+	}
+	if len(pkg.Files) == 0 || pkg.Files[0].Package == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "flux package source must start with a package clause",
+		}
+	}
+	if name := pkg.Files[0].Package.Name.Name; name != path.Base(p.Name) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("flux package clause %q does not match the last element of the package name %q", name, p.Name),
+		}
+	}
+	return nil
+}
+
+// FluxPackageFilter represents a set of filters that restrict the returned flux packages.
+type FluxPackageFilter struct {
+	OrganizationID *ID
+	Name           *string
+}
+
+// FluxPackageUpdate is the set of changes to a flux package.
+// The name of a package can not change, since queries import it by name.
+type FluxPackageUpdate struct {
+	Description *string `json:"description,omitempty"`
+	Source      *string `json:"source,omitempty"`
+}
+
+// Valid returns an error if the update is empty.
+func (u FluxPackageUpdate) Valid() error {
+	if u.Description == nil && u.Source == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "flux package update must change description or source",
+		}
+	}
+	return nil
+}
+
+// Apply applies the update to the package and validates the result.
+func (u FluxPackageUpdate) Apply(p *FluxPackage) error {
+	if u.Description != nil {
+		p.Description = *u.Description
+	}
+	if u.Source != nil {
+		p.Source = *u.Source
+	}
+	return p.Valid()
+}
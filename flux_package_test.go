@@ -0,0 +1,79 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestFluxPackage_Valid(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkg     influxdb.FluxPackage
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			pkg: influxdb.FluxPackage{
+				OrganizationID: 1,
+				Name:           "acme/alerts",
+				Source:         "package alerts\n\nlevel = \"crit\"",
+			},
+		},
+		{
+			name: "missing org",
+			pkg: influxdb.FluxPackage{
+				Name:   "alerts",
+				Source: "package alerts",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid name",
+			pkg: influxdb.FluxPackage{
+				OrganizationID: 1,
+				Name:           "Acme/alerts/",
+				Source:         "package alerts",
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing package clause",
+			pkg: influxdb.FluxPackage{
+				OrganizationID: 1,
+				Name:           "acme/alerts",
+				Source:         "level = \"crit\"",
+			},
+			wantErr: true,
+		},
+		{
+			name: "package clause does not match name",
+			pkg: influxdb.FluxPackage{
+				OrganizationID: 1,
+				Name:           "acme/alerts",
+				Source:         "package acme",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid source",
+			pkg: influxdb.FluxPackage{
+				OrganizationID: 1,
+				Name:           "acme/alerts",
+				Source:         "package alerts\n\nlevel = )",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pkg.Valid()
+			if tt.wantErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/nats"
 	"go.uber.org/zap"
 )
@@ -15,6 +17,10 @@ type handler struct {
 	Scraper   Scraper
 	Publisher nats.Publisher
 	Logger    *zap.Logger
+
+	// Status records the outcome of each scrape, if set.
+	Status  influxdb.ScraperTargetStatusService
+	metrics *scrapeMetrics
 }
 
 // Process consumes scraper target from scraper target queue,
@@ -29,7 +35,12 @@ func (h *handler) Process(s nats.Subscription, m nats.Message) {
 		return
 	}
 
-	ms, err := h.Scraper.Gather(context.TODO(), *req)
+	span, ctx := tracing.StartSpanFromContext(context.Background())
+	defer span.Finish()
+
+	start := time.Now()
+	ms, err := h.Scraper.Gather(ctx, *req)
+	h.recordStatus(ctx, *req, start, time.Since(start), len(ms.MetricsSlice), err)
 	if err != nil {
 		h.Logger.Error("unable to gather", zap.Error(err))
 		return
@@ -48,3 +59,26 @@ func (h *handler) Process(s nats.Subscription, m nats.Message) {
 	}
 
 }
+
+// recordStatus records the outcome of a scrape of the target in the metrics and its status.
+func (h *handler) recordStatus(ctx context.Context, target influxdb.ScraperTarget, start time.Time, d time.Duration, samples int, err error) {
+	if h.metrics != nil {
+		h.metrics.observe(d, samples, err)
+	}
+	if h.Status == nil {
+		return
+	}
+
+	st := &influxdb.ScraperTargetStatus{
+		TargetID:   target.ID,
+		LastScrape: start.UTC(),
+		Duration:   int64(d / time.Millisecond),
+		Samples:    samples,
+	}
+	if err != nil {
+		st.LastError = err.Error()
+	}
+	if err := h.Status.PutTargetStatus(ctx, st); err != nil {
+		h.Logger.Error("unable to record scraper target status", zap.Error(err))
+	}
+}
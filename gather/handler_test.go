@@ -0,0 +1,43 @@
+package gather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestHandler_recordStatus(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "scrape")
+
+	var got *influxdb.ScraperTargetStatus
+	status := mock.NewScraperTargetStatusService()
+	status.PutTargetStatusFn = func(ctx context.Context, st *influxdb.ScraperTargetStatus) error {
+		if ctx.Value(ctxKey{}) != "scrape" {
+			t.Error("expected the status to be recorded with the context of the scrape")
+		}
+		got = st
+		return nil
+	}
+	h := &handler{
+		Logger:  zap.NewNop(),
+		Status:  status,
+		metrics: newScrapeMetrics(),
+	}
+
+	start := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	target := influxdb.ScraperTarget{ID: 1, OrgID: *orgID}
+	h.recordStatus(ctx, target, start, 1500*time.Millisecond, 0, errors.New("connection refused"))
+
+	if got == nil {
+		t.Fatal("expected the status of the scrape to be recorded")
+	}
+	if got.TargetID != target.ID || !got.LastScrape.Equal(start) || got.Duration != 1500 || got.LastError != "connection refused" {
+		t.Fatalf("unexpected status %+v", got)
+	}
+}
@@ -2,6 +2,8 @@ package gather
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"math"
@@ -17,11 +19,24 @@ import (
 
 // prometheusScraper handles parsing prometheus metrics.
 // implements Scraper interfaces.
-type prometheusScraper struct{}
+type prometheusScraper struct {
+	// SecretService has the credentials of the targets.
+	SecretService influxdb.SecretService
+}
 
 // Gather parse metrics from a scraper target url.
 func (p *prometheusScraper) Gather(ctx context.Context, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
-	resp, err := http.Get(target.URL)
+	req, err := p.newRequest(ctx, target)
+	if err != nil {
+		return collected, err
+	}
+
+	client, err := newClient(target.TLS)
+	if err != nil {
+		return collected, err
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return collected, err
 	}
@@ -30,6 +45,81 @@ func (p *prometheusScraper) Gather(ctx context.Context, target influxdb.ScraperT
 	return p.parse(resp.Body, resp.Header, target)
 }
 
+// newRequest returns the scrape request of the target with its headers and
+// credentials. The credentials are loaded from the secrets of its organization.
+func (p *prometheusScraper) newRequest(ctx context.Context, target influxdb.ScraperTarget) (*http.Request, error) {
+	req, err := http.NewRequest("GET", target.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range target.Headers {
+		req.Header.Set(k, v)
+	}
+
+	switch target.AuthMethod {
+	case influxdb.HTTPAuthBasic:
+		username, err := p.loadSecret(ctx, target, target.Username)
+		if err != nil {
+			return nil, err
+		}
+		password, err := p.loadSecret(ctx, target, target.Password)
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(username, password)
+	case influxdb.HTTPAuthBearer:
+		token, err := p.loadSecret(ctx, target, target.Token)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// loadSecret returns the value of the credential f of the target, or "" when it has none.
+func (p *prometheusScraper) loadSecret(ctx context.Context, target influxdb.ScraperTarget, f *influxdb.SecretField) (string, error) {
+	if f.Empty() {
+		return "", nil
+	}
+	if f.Value != nil {
+		return *f.Value, nil
+	}
+	if p.SecretService == nil {
+		return "", fmt.Errorf("no secret service to load secret %s", f.Key)
+	}
+	return p.SecretService.LoadSecret(ctx, target.OrgID, f.Key)
+}
+
+// newClient returns the client scraping a target with the TLS config.
+// Targets without one share the default client.
+func newClient(c *influxdb.ScraperTLSConfig) (*http.Client, error) {
+	if c == nil {
+		return http.DefaultClient, nil
+	}
+
+	cfg := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.CACert)) {
+			return nil, fmt.Errorf("caCert of scraper target has no PEM encoded certificate")
+		}
+		cfg.RootCAs = pool
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			TLSClientConfig:   cfg,
+			DisableKeepAlives: true,
+		},
+	}, nil
+}
+
 func (p *prometheusScraper) parse(r io.Reader, header http.Header, target influxdb.ScraperTarget) (collected MetricsCollection, err error) {
 	var parser expfmt.TextParser
 	now := time.Now()
@@ -10,6 +10,7 @@ import (
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/nats"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -22,7 +23,9 @@ const (
 // Scheduler is struct to run scrape jobs.
 type Scheduler struct {
 	Targets influxdb.ScraperTargetStoreService
-	// Interval is between each metrics gathering event.
+	// Interval is between each metrics gathering event. Targets with an
+	// interval of their own are skipped by the events until it elapsed, so
+	// they are scraped at most once per Interval.
 	Interval time.Duration
 	// Timeout is the maxisium time duration allowed by each TCP request
 	Timeout time.Duration
@@ -32,7 +35,10 @@ type Scheduler struct {
 
 	Logger *zap.Logger
 
-	gather chan struct{}
+	gather  chan struct{}
+	metrics *scrapeMetrics
+	// scraped is when each target was last requested to be scraped.
+	scraped map[influxdb.ID]time.Time
 }
 
 // NewScheduler creates a new Scheduler and subscriptions for scraper jobs.
@@ -40,6 +46,8 @@ func NewScheduler(
 	numScrapers int,
 	l *zap.Logger,
 	targets influxdb.ScraperTargetStoreService,
+	secrets influxdb.SecretService,
+	status influxdb.ScraperTargetStatusService,
 	p nats.Publisher,
 	s nats.Subscriber,
 	interval time.Duration,
@@ -58,13 +66,17 @@ func NewScheduler(
 		Publisher: p,
 		Logger:    l,
 		gather:    make(chan struct{}, 100),
+		metrics:   newScrapeMetrics(),
+		scraped:   make(map[influxdb.ID]time.Time),
 	}
 
 	for i := 0; i < numScrapers; i++ {
 		err := s.Subscribe(promTargetSubject, "metrics", &handler{
-			Scraper:   new(prometheusScraper),
+			Scraper:   &prometheusScraper{SecretService: secrets},
 			Publisher: p,
 			Logger:    l,
+			Status:    status,
+			metrics:   scheduler.metrics,
 		})
 		if err != nil {
 			return nil, err
@@ -74,6 +86,11 @@ func NewScheduler(
 	return scheduler, nil
 }
 
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (s *Scheduler) PrometheusCollectors() []prometheus.Collector {
+	return s.metrics.PrometheusCollectors()
+}
+
 // Run will retrieve scraper targets from the target storage,
 // and publish them to nats job queue for gather.
 func (s *Scheduler) Run(ctx context.Context) error {
@@ -113,12 +130,30 @@ func (s *Scheduler) doGather(ctx context.Context) {
 		tracing.LogError(span, err)
 		return
 	}
+	now := time.Now()
+	scraped := make(map[influxdb.ID]time.Time, len(targets))
 	for _, target := range targets {
+		if last, ok := s.scraped[target.ID]; ok && !s.due(target, last, now) {
+			scraped[target.ID] = last
+			continue
+		}
 		if err := requestScrape(target, s.Publisher); err != nil {
 			s.Logger.Error("json encoding error", zap.Error(err))
 			tracing.LogError(span, err)
 		}
+		scraped[target.ID] = now
+	}
+	s.scraped = scraped
+}
+
+// due reports whether the interval of the target elapsed at now since it was
+// last scraped. Half an Interval is allowed for the drift of the events, so
+// targets are not left out for a whole event when it comes slightly early.
+func (s *Scheduler) due(target influxdb.ScraperTarget, last, now time.Time) bool {
+	if target.Interval == "" {
+		return true
 	}
+	return now.Add(s.Interval/2).Sub(last) >= target.ScrapeInterval(s.Interval)
 }
 
 func requestScrape(t influxdb.ScraperTarget, publisher nats.Publisher) error {
@@ -2,6 +2,7 @@ package gather
 
 import (
 	"context"
+	"io"
 	"net/http/httptest"
 	"os"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	influxlogger "github.com/influxdata/influxdb/logger"
 	"github.com/influxdata/influxdb/mock"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap"
 )
 
 func TestScheduler(t *testing.T) {
@@ -48,7 +50,7 @@ func TestScheduler(t *testing.T) {
 	})
 
 	scheduler, err := NewScheduler(10, logger,
-		storage, publisher, subscriber, time.Millisecond, time.Microsecond)
+		storage, nil, nil, publisher, subscriber, time.Millisecond, time.Microsecond)
 
 	go func() {
 		err = scheduler.run(ctx)
@@ -92,6 +94,59 @@ func TestScheduler(t *testing.T) {
 	ts.Close()
 }
 
+type countingPublisher struct {
+	published int
+}
+
+func (p *countingPublisher) Publish(subject string, r io.Reader) error {
+	p.published++
+	return nil
+}
+
+func TestScheduler_targetInterval(t *testing.T) {
+	storage := &mockStorage{
+		Targets: []influxdb.ScraperTarget{
+			{
+				ID:       influxdbtesting.MustIDBase16("3a0d0a6365646120"),
+				Type:     influxdb.PrometheusScraperType,
+				OrgID:    *orgID,
+				BucketID: *bucketID,
+			},
+			{
+				ID:       influxdbtesting.MustIDBase16("3a0d0a6365646121"),
+				Type:     influxdb.PrometheusScraperType,
+				OrgID:    *orgID,
+				BucketID: *bucketID,
+				Interval: "1h",
+			},
+		},
+	}
+	publisher := &countingPublisher{}
+	scheduler := &Scheduler{
+		Targets:   storage,
+		Interval:  time.Minute,
+		Timeout:   time.Second,
+		Publisher: publisher,
+		Logger:    zap.NewNop(),
+		scraped:   make(map[influxdb.ID]time.Time),
+	}
+
+	// Both targets are scraped the first time, then only the one without an interval.
+	scheduler.doGather(context.Background())
+	scheduler.doGather(context.Background())
+	if publisher.published != 3 {
+		t.Fatalf("expected 3 scrape requests, got %d", publisher.published)
+	}
+
+	// The target with an interval is scraped again once it elapsed.
+	id := storage.Targets[1].ID
+	scheduler.scraped[id] = scheduler.scraped[id].Add(-time.Hour + time.Minute/4)
+	scheduler.doGather(context.Background())
+	if publisher.published != 5 {
+		t.Fatalf("expected 5 scrape requests, got %d", publisher.published)
+	}
+}
+
 const sampleRespSmall = `
 # HELP go_goroutines Number of goroutines that currently exist.
 # TYPE go_goroutines gauge
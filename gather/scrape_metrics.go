@@ -0,0 +1,63 @@
+package gather
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// scrapeMetrics are the prometheus metrics of the scrapes of targets.
+type scrapeMetrics struct {
+	scrapes        *prometheus.CounterVec
+	scrapeDuration prometheus.Histogram
+	samples        prometheus.Counter
+}
+
+func newScrapeMetrics() *scrapeMetrics {
+	const namespace = "scraper"
+	const subsystem = "target"
+
+	return &scrapeMetrics{
+		scrapes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "scrapes_total",
+			Help:      "Total number of scrapes of scraper targets, split out by success or failure.",
+		}, []string{"status"}),
+
+		scrapeDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "scrape_duration_seconds",
+			Help:      "The duration in seconds of the scrapes of scraper targets.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+
+		samples: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "samples_total",
+			Help:      "Total number of metrics gathered from scraper targets.",
+		}),
+	}
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *scrapeMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.scrapes,
+		m.scrapeDuration,
+		m.samples,
+	}
+}
+
+// observe adjusts the metrics to account for a scrape that took d and gathered samples metrics.
+func (m *scrapeMetrics) observe(d time.Duration, samples int, err error) {
+	status := "success"
+	if err != nil {
+		status = "failed"
+	}
+	m.scrapes.WithLabelValues(status).Inc()
+	m.scrapeDuration.Observe(d.Seconds())
+	m.samples.Add(float64(samples))
+}
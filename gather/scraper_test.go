@@ -2,6 +2,7 @@ package gather
 
 import (
 	"context"
+	"encoding/pem"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
 )
 
 var (
@@ -155,6 +157,89 @@ func TestPrometheusScraper(t *testing.T) {
 	}
 }
 
+func TestPrometheusScraper_Auth(t *testing.T) {
+	var got *http.Request
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write([]byte(sampleRespSmall))
+	}))
+	defer ts.Close()
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}))
+
+	secrets := mock.NewSecretService()
+	secrets.LoadSecretFn = func(ctx context.Context, id influxdb.ID, k string) (string, error) {
+		if id != *orgID || k != "node-token" {
+			t.Fatalf("unexpected secret %s of org %s loaded", k, id)
+		}
+		return "abc123", nil
+	}
+	password := "secret"
+
+	cases := []struct {
+		name       string
+		target     influxdb.ScraperTarget
+		authHeader string
+		hasErr     bool
+	}{
+		{
+			name: "bearer token from secret",
+			target: influxdb.ScraperTarget{
+				AuthMethod: influxdb.HTTPAuthBearer,
+				Token:      &influxdb.SecretField{Key: "node-token"},
+				Headers:    map[string]string{"X-Scope": "metrics"},
+				TLS:        &influxdb.ScraperTLSConfig{CACert: caCert},
+			},
+			authHeader: "Bearer abc123",
+		},
+		{
+			name: "basic auth",
+			target: influxdb.ScraperTarget{
+				AuthMethod: influxdb.HTTPAuthBasic,
+				Username:   &influxdb.SecretField{Key: "node-token"},
+				Password:   &influxdb.SecretField{Value: &password},
+				Headers:    map[string]string{"X-Scope": "metrics"},
+				TLS:        &influxdb.ScraperTLSConfig{InsecureSkipVerify: true},
+			},
+			authHeader: "Basic YWJjMTIzOnNlY3JldA==",
+		},
+		{
+			name:   "unknown certificate authority",
+			target: influxdb.ScraperTarget{},
+			hasErr: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got = nil
+			scraper := &prometheusScraper{SecretService: secrets}
+			c.target.URL = ts.URL + "/metrics"
+			c.target.OrgID = *orgID
+			c.target.BucketID = *bucketID
+
+			results, err := scraper.Gather(context.Background(), c.target)
+			if c.hasErr {
+				if err == nil {
+					t.Fatal("expected the scrape to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results.MetricsSlice) != 1 {
+				t.Fatalf("expected one metric, got %d", len(results.MetricsSlice))
+			}
+			if v := got.Header.Get("Authorization"); v != c.authHeader {
+				t.Errorf("expected Authorization %q, got %q", c.authHeader, v)
+			}
+			if v := got.Header.Get("X-Scope"); v != "metrics" {
+				t.Errorf("expected X-Scope metrics, got %q", v)
+			}
+		})
+	}
+}
+
 const sampleResp = `
 # 	HELP go_gc_duration_seconds A summary of the GC invocation durations.
 # TYPE go_gc_duration_seconds summary
@@ -8,7 +8,7 @@ require (
 	github.com/RoaringBitmap/roaring v0.4.16
 	github.com/andreyvit/diff v0.0.0-20170406064948-c7f18ee00883
 	github.com/apache/arrow/go/arrow v0.0.0-20190426170622-338c62a2a205
-	github.com/aws/aws-sdk-go v1.16.15 // indirect
+	github.com/aws/aws-sdk-go v1.16.15
 	github.com/benbjohnson/tmpl v1.0.0
 	github.com/beorn7/perks v0.0.0-20180321164747-3a771d992973 // indirect
 	github.com/boltdb/bolt v1.3.1 // indirect
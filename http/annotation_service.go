@@ -0,0 +1,425 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/annotation/migrate"
+)
+
+// AnnotationBackend is all services and associated parameters
+// required to construct the AnnotationHandler.
+type AnnotationBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	AnnotationService   influxdb.AnnotationService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewAnnotationBackend returns a new instance of AnnotationBackend.
+func NewAnnotationBackend(b *APIBackend) *AnnotationBackend {
+	return &AnnotationBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "annotation")),
+
+		AnnotationService:   b.AnnotationService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// AnnotationHandler represents an HTTP API handler for annotations.
+type AnnotationHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	AnnotationService   influxdb.AnnotationService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	annotationsPath   = "/api/v2/annotations"
+	annotationsIDPath = "/api/v2/annotations/:id"
+
+	// annotationsImportPath is a custom method on the annotations collection.
+	annotationsImportPath = "/api/v2/annotations:import"
+)
+
+// NewAnnotationHandler returns a new instance of AnnotationHandler.
+func NewAnnotationHandler(b *AnnotationBackend) *AnnotationHandler {
+	h := &AnnotationHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		AnnotationService:   b.AnnotationService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", annotationsPath, h.handlePostAnnotations)
+	h.HandlerFunc("GET", annotationsPath, h.handleGetAnnotations)
+	h.HandlerFunc("GET", annotationsIDPath, h.handleGetAnnotation)
+	h.HandlerFunc("PATCH", annotationsIDPath, h.handlePatchAnnotation)
+	h.HandlerFunc("DELETE", annotationsIDPath, h.handleDeleteAnnotation)
+
+	return h
+}
+
+// ServeHTTP routes the custom methods on the annotations collection before delegating to the router.
+func (h *AnnotationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != annotationsImportPath {
+		h.Router.ServeHTTP(w, r)
+		return
+	}
+
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		baseHandler{HTTPErrorHandler: h.HTTPErrorHandler}.methodNotAllowed(w, r)
+		return
+	}
+	h.handlePostAnnotationsImport(w, r)
+}
+
+type annotationResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Annotation
+}
+
+func newAnnotationResponse(a *influxdb.Annotation) *annotationResponse {
+	return &annotationResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/annotations/%s", a.ID),
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", a.OrgID),
+		},
+		Annotation: *a,
+	}
+}
+
+type annotationsResponse struct {
+	Links       map[string]string     `json:"links"`
+	Annotations []*annotationResponse `json:"annotations"`
+}
+
+func newAnnotationsResponse(as []*influxdb.Annotation) *annotationsResponse {
+	res := &annotationsResponse{
+		Links: map[string]string{
+			"self": annotationsPath,
+		},
+		Annotations: make([]*annotationResponse, 0, len(as)),
+	}
+	for _, a := range as {
+		res.Annotations = append(res.Annotations, newAnnotationResponse(a))
+	}
+	return res
+}
+
+// decodePostAnnotationsRequest decodes a single annotation or a list of them. Annotations
+// without an organization are written to the organization of the orgID or org parameter.
+func (h *AnnotationHandler) decodePostAnnotationsRequest(ctx context.Context, r *http.Request) ([]*influxdb.Annotation, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to read annotations request",
+			Err:  err,
+		}
+	}
+
+	var as []*influxdb.Annotation
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(body, &as)
+	} else {
+		a := &influxdb.Annotation{}
+		err = json.Unmarshal(body, a)
+		as = []*influxdb.Annotation{a}
+	}
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode annotations request",
+			Err:  err,
+		}
+	}
+	if len(as) == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "at least one annotation is required",
+		}
+	}
+
+	orgID, err := h.decodeOrgParam(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range as {
+		if a == nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "annotations must not be null",
+			}
+		}
+		if !a.OrgID.Valid() && orgID != nil {
+			a.OrgID = *orgID
+		}
+	}
+	return as, nil
+}
+
+// decodeOrgParam returns the organization of the orgID or org parameter, or nil if there is neither.
+func (h *AnnotationHandler) decodeOrgParam(ctx context.Context, r *http.Request) (*influxdb.ID, error) {
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		return influxdb.IDFromString(orgID)
+	}
+	if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		return &o.ID, nil
+	}
+	return nil, nil
+}
+
+// handlePostAnnotations is the HTTP handler for the POST /api/v2/annotations route.
+func (h *AnnotationHandler) handlePostAnnotations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("annotations create request", zap.String("r", fmt.Sprint(r)))
+
+	as, err := h.decodePostAnnotationsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.AnnotationService.CreateAnnotations(ctx, as); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("annotations created", zap.Int("annotations", len(as)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newAnnotationsResponse(as)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostAnnotationsImport is the HTTP handler for the POST /api/v2/annotations:import route.
+// It imports the annotations of a line protocol export of a Chronograf database into the
+// organization of the orgID or org parameter, skipping the annotations imported before.
+func (h *AnnotationHandler) handlePostAnnotationsImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("annotations import request", zap.String("r", fmt.Sprint(r)))
+
+	orgID, err := h.decodeOrgParam(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if orgID == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID or org is required",
+		}, w)
+		return
+	}
+
+	i := &migrate.Importer{
+		AnnotationService: h.AnnotationService,
+		Options: migrate.Options{
+			OrgID:  *orgID,
+			Stream: r.URL.Query().Get("stream"),
+		},
+	}
+	res, err := i.ImportLineProtocol(ctx, r.Body)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("annotations imported", zap.Int("imported", res.Imported), zap.Int("skipped", res.Skipped))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetAnnotations is the HTTP handler for the GET /api/v2/annotations route.
+func (h *AnnotationHandler) handleGetAnnotations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("annotations retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	filter, opts, err := h.decodeGetAnnotationsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	as, _, err := h.AnnotationService.FindAnnotations(ctx, filter, opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("annotations retrieved", zap.Int("annotations", len(as)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAnnotationsResponse(as)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *AnnotationHandler) decodeGetAnnotationsRequest(ctx context.Context, r *http.Request) (influxdb.AnnotationFilter, influxdb.FindOptions, error) {
+	var filter influxdb.AnnotationFilter
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return filter, influxdb.FindOptions{}, err
+	}
+
+	filter.OrgID, err = h.decodeOrgParam(ctx, r)
+	if err != nil {
+		return filter, *opts, err
+	}
+
+	af, err := decodeAnnotationsQuery(r)
+	if err != nil {
+		return filter, *opts, err
+	}
+	filter.Streams, filter.Start, filter.Stop, filter.Tags = af.Streams, af.Start, af.Stop, af.Tags
+
+	return filter, *opts, nil
+}
+
+// decodeAnnotationsQuery decodes the stream, start, stop and tag parameters that select annotations.
+// Tags are given as key:value and streams may be given more than once.
+func decodeAnnotationsQuery(r *http.Request) (influxdb.AnnotationFilter, error) {
+	qp := r.URL.Query()
+	filter := influxdb.AnnotationFilter{
+		Streams: qp["stream"],
+	}
+
+	for _, name := range []string{"start", "stop"} {
+		v := qp.Get(name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("%s must be an RFC3339 time", name),
+				Err:  err,
+			}
+		}
+		if name == "start" {
+			filter.Start = &t
+		} else {
+			filter.Stop = &t
+		}
+	}
+
+	for _, tag := range qp["tag"] {
+		kv := strings.SplitN(tag, ":", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return filter, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("tag %q must be key:value", tag),
+			}
+		}
+		if filter.Tags == nil {
+			filter.Tags = map[string]string{}
+		}
+		filter.Tags[kv[0]] = kv[1]
+	}
+
+	return filter, nil
+}
+
+// handleGetAnnotation is the HTTP handler for the GET /api/v2/annotations/:id route.
+func (h *AnnotationHandler) handleGetAnnotation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("annotation retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AnnotationService.FindAnnotationByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("annotation retrieved", zap.String("annotationID", a.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAnnotationResponse(a)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchAnnotation is the HTTP handler for the PATCH /api/v2/annotations/:id route.
+func (h *AnnotationHandler) handlePatchAnnotation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("annotation update request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.AnnotationUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode annotation update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	a, err := h.AnnotationService.UpdateAnnotation(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("annotation updated", zap.String("annotationID", a.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAnnotationResponse(a)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteAnnotation is the HTTP handler for the DELETE /api/v2/annotations/:id route.
+func (h *AnnotationHandler) handleDeleteAnnotation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("annotation delete request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.AnnotationService.DeleteAnnotation(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("annotation deleted", zap.String("annotationID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newTestAnnotationHandler(as platform.AnnotationService) *AnnotationHandler {
+	return NewAnnotationHandler(&AnnotationBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		AnnotationService:   as,
+		OrganizationService: mock.NewOrganizationService(),
+	})
+}
+
+func TestAnnotationHandler_handlePostAnnotations(t *testing.T) {
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	as := mock.NewAnnotationService()
+	as.CreateAnnotationsFn = func(ctx context.Context, annotations []*platform.Annotation) error {
+		for i, a := range annotations {
+			if a.OrgID != 1 {
+				t.Errorf("expected the annotation to default to the organization of the request, got %s", a.OrgID)
+			}
+			a.ID = platform.ID(i + 2)
+			a.Stream = platform.DefaultAnnotationStream
+			a.StartTime, a.EndTime = now, now
+		}
+		return nil
+	}
+	h := newTestAnnotationHandler(as)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/annotations?orgID=0000000000000001", bytes.NewBufferString(`
+[{"summary": "deployed v1.2", "tags": {"service": "api"}}]`)))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {"self": "/api/v2/annotations"},
+  "annotations": [
+    {
+      "links": {
+        "self": "/api/v2/annotations/0000000000000002",
+        "org": "/api/v2/orgs/0000000000000001"
+      },
+      "id": "0000000000000002",
+      "orgID": "0000000000000001",
+      "stream": "default",
+      "summary": "deployed v1.2",
+      "tags": {"service": "api"},
+      "startTime": "2019-07-01T12:00:00Z",
+      "endTime": "2019-07-01T12:00:00Z",
+      "createdAt": "0001-01-01T00:00:00Z",
+      "updatedAt": "0001-01-01T00:00:00Z"
+    }
+  ]
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/annotations", bytes.NewBufferString(`[]`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAnnotationHandler_handleGetAnnotations(t *testing.T) {
+	as := mock.NewAnnotationService()
+	as.FindAnnotationsFn = func(ctx context.Context, filter platform.AnnotationFilter, opt ...platform.FindOptions) ([]*platform.Annotation, int, error) {
+		if filter.OrgID == nil || *filter.OrgID != 1 {
+			t.Errorf("expected the annotations of org 0000000000000001, got %+v", filter)
+		}
+		if len(filter.Streams) != 2 || filter.Streams[1] != "incidents" {
+			t.Errorf("expected the annotations of two streams, got %v", filter.Streams)
+		}
+		if filter.Start == nil || !filter.Start.Equal(time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)) || filter.Stop != nil {
+			t.Errorf("expected the annotations from noon on, got %v to %v", filter.Start, filter.Stop)
+		}
+		if filter.Tags["service"] != "api" {
+			t.Errorf("expected the annotations with the service tag, got %v", filter.Tags)
+		}
+		return nil, 0, nil
+	}
+	h := newTestAnnotationHandler(as)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/annotations?orgID=0000000000000001&stream=deploys&stream=incidents&start=2019-07-01T12:00:00Z&tag=service:api", nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `{"links": {"self": "/api/v2/annotations"}, "annotations": []}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	for _, query := range []string{"start=yesterday", "tag=service"} {
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/annotations?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestAnnotationHandler_handlePostAnnotationsImport(t *testing.T) {
+	as := mock.NewAnnotationService()
+	as.FindAnnotationsFn = func(ctx context.Context, filter platform.AnnotationFilter, opt ...platform.FindOptions) ([]*platform.Annotation, int, error) {
+		if filter.OrgID == nil || *filter.OrgID != 1 || len(filter.Streams) != 1 || filter.Streams[0] != "deploys" {
+			t.Errorf("expected the annotations of the deploys stream of org 0000000000000001, got %+v", filter)
+		}
+		return []*platform.Annotation{
+			{ID: 2, OrgID: 1, Stream: "deploys", Tags: map[string]string{"chronografID": "deploy-1"}},
+		}, 1, nil
+	}
+	var created []*platform.Annotation
+	as.CreateAnnotationsFn = func(ctx context.Context, annotations []*platform.Annotation) error {
+		created = annotations
+		return nil
+	}
+	h := newTestAnnotationHandler(as)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/annotations:import?orgID=0000000000000001&stream=deploys", bytes.NewBufferString(`
+# CONTEXT-DATABASE:chronograf
+annotations,id=deploy-1 deleted=false,start_time=1561982400000000000i,modified_time_ns=1i,text="deployed v1.1",type="deploys" 1561982400000000000
+annotations,id=deploy-2 deleted=false,start_time=1561986000000000000i,modified_time_ns=2i,text="deployed v1.2",type="deploys" 1561986000000000000
+`)))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `{"imported": 1, "skipped": 1}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+	if len(created) != 1 || created[0].Summary != "deployed v1.2" || created[0].Stream != "deploys" || created[0].OrgID != 1 {
+		t.Errorf("expected the second deploy to be imported, got %+v", created)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/annotations:import", bytes.NewBufferString(``)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/annotations:import", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/influxdata/influxdb/chronograf/server"
 	"github.com/influxdata/influxdb/http/metric"
 	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/influxdata/influxdb/pkger"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/storage"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,25 +19,56 @@ import (
 // APIHandler is a collection of all the service handlers.
 type APIHandler struct {
 	influxdb.HTTPErrorHandler
-	BucketHandler        *BucketHandler
-	UserHandler          *UserHandler
-	OrgHandler           *OrgHandler
-	AuthorizationHandler *AuthorizationHandler
-	DashboardHandler     *DashboardHandler
-	LabelHandler         *LabelHandler
-	AssetHandler         *AssetHandler
-	ChronografHandler    *ChronografHandler
-	ScraperHandler       *ScraperHandler
-	SourceHandler        *SourceHandler
-	VariableHandler      *VariableHandler
-	TaskHandler          *TaskHandler
-	TelegrafHandler      *TelegrafHandler
-	QueryHandler         *FluxHandler
-	WriteHandler         *WriteHandler
-	DocumentHandler      *DocumentHandler
-	SetupHandler         *SetupHandler
-	SessionHandler       *SessionHandler
-	SwaggerHandler       http.Handler
+	BucketHandler               *BucketHandler
+	UserHandler                 *UserHandler
+	OrgHandler                  *OrgHandler
+	OrgDeletionHandler          *OrgDeletionHandler
+	AuthorizationHandler        *AuthorizationHandler
+	DashboardHandler            *DashboardHandler
+	DBRPMappingHandler          *DBRPMappingHandler
+	DropSeriesHandler           *DropSeriesHandler
+	DeleteHandler               *DeleteHandler
+	LabelHandler                *LabelHandler
+	MappingBatchHandler         *MappingBatchHandler
+	AssetHandler                *AssetHandler
+	ChronografHandler           *ChronografHandler
+	ScraperHandler              *ScraperHandler
+	SourceHandler               *SourceHandler
+	VariableHandler             *VariableHandler
+	TaskHandler                 *TaskHandler
+	TeamHandler                 *TeamHandler
+	NotificationEndpointHandler *NotificationEndpointHandler
+	CheckHandler                *CheckHandler
+	NotificationRuleHandler     *NotificationRuleHandler
+	SilenceHandler              *SilenceHandler
+	PkgerHandler                *PkgerHandler
+	AnnotationHandler           *AnnotationHandler
+	InviteHandler               *InviteHandler
+	DashboardShareHandler       *DashboardShareHandler
+	PasswordResetHandler        *PasswordResetHandler
+	TaskTemplateHandler         *TaskTemplateHandler
+	FluxPackageHandler          *FluxPackageHandler
+	TaskOptionsHandler          *TaskOptionsHandler
+	QueryViewHandler            *QueryViewHandler
+	RunningQueryHandler         *RunningQueryHandler
+	QueryHistoryHandler         *QueryHistoryHandler
+	SearchHandler               *SearchHandler
+	TrashHandler                *TrashHandler
+	TelegrafHandler             *TelegrafHandler
+	QueryHandler                *FluxHandler
+	WriteHandler                *WriteHandler
+	CompatHandler               *CompatHandler
+	DocumentHandler             *DocumentHandler
+	SetupHandler                *SetupHandler
+	SessionHandler              *SessionHandler
+	MaintenanceHandler          *MaintenanceHandler
+	SystemInfoHandler           *SystemInfoHandler
+	LimitsHandler               *LimitsHandler
+	GraphQLHandler              *GraphQLHandler
+	SwaggerHandler              http.Handler
+
+	// MaintenanceService decides whether the write and query paths are disabled.
+	MaintenanceService influxdb.MaintenanceService
 }
 
 // APIBackend is all services and associated parameters required to construct
@@ -46,12 +78,26 @@ type APIBackend struct {
 	Logger     *zap.Logger
 	influxdb.HTTPErrorHandler
 	SessionRenewDisabled bool
+	// GraphQLEnabled serves GraphQL queries of the platform resources; the
+	// GraphQLHandler of the APIHandler is nil otherwise.
+	GraphQLEnabled bool
+	// ClientCertAuthenticator authenticates requests with client certificates, if not nil.
+	ClientCertAuthenticator *ClientCertAuthenticator
 
 	NewBucketService func(*influxdb.Source) (influxdb.BucketService, error)
 	NewQueryService  func(*influxdb.Source) (query.ProxyQueryService, error)
 
 	WriteEventRecorder metric.EventRecorder
 	QueryEventRecorder metric.EventRecorder
+	// WriteRejectionRecorder records rejected writes, if not nil.
+	WriteRejectionRecorder influxdb.WriteRejectionRecorder
+	// QueryDefaultTimezone is the timezone calendars of queries are resolved
+	// in when their organization has none.
+	QueryDefaultTimezone string
+	// QueryCacheHeaders configures the HTTP caching headers of the responses to queries.
+	QueryCacheHeaders QueryCacheHeadersConfig
+	// QueryStream configures how the results of queries are streamed to clients.
+	QueryStream QueryStreamConfig
 
 	PointsWriter                    storage.PointsWriter
 	AuthorizationService            influxdb.AuthorizationService
@@ -60,9 +106,11 @@ type APIBackend struct {
 	UserService                     influxdb.UserService
 	OrganizationService             influxdb.OrganizationService
 	UserResourceMappingService      influxdb.UserResourceMappingService
+	UserResourceMappingBatchService influxdb.UserResourceMappingBatchService
 	LabelService                    influxdb.LabelService
 	DashboardService                influxdb.DashboardService
 	DashboardOperationLogService    influxdb.DashboardOperationLogService
+	DBRPMappingService              influxdb.DBRPMappingService
 	BucketOperationLogService       influxdb.BucketOperationLogService
 	UserOperationLogService         influxdb.UserOperationLogService
 	OrganizationOperationLogService influxdb.OrganizationOperationLogService
@@ -72,14 +120,60 @@ type APIBackend struct {
 	OnboardingService               influxdb.OnboardingService
 	InfluxQLService                 query.ProxyQueryService
 	FluxService                     query.ProxyQueryService
+	QueryService                    query.QueryService
 	TaskService                     influxdb.TaskService
+	TeamService                     influxdb.TeamService
+	NotificationEndpointService     influxdb.NotificationEndpointService
+	NotificationSender              influxdb.NotificationSender
+	CheckService                    influxdb.CheckService
+	CheckStatusService              influxdb.CheckStatusService
+	NotificationRuleService         influxdb.NotificationRuleService
+	SentNotificationService         influxdb.SentNotificationService
+	SilenceService                  influxdb.SilenceService
+	StackService                    influxdb.StackService
+	AnnotationService               influxdb.AnnotationService
+	InviteService                   influxdb.InviteService
+	AuditService                    influxdb.AuditService
+	AuditExportService              influxdb.AuditExportService
+	DashboardShareService           influxdb.DashboardShareService
+	PasswordResetService            influxdb.PasswordResetService
+	TaskTemplateService             influxdb.TaskTemplateService
+	FluxPackageService              influxdb.FluxPackageService
+	TaskRunImporter                 influxdb.TaskRunImporter
+	SchedulerStateService           influxdb.SchedulerStateService
+	RunLogStreamer                  influxdb.RunLogStreamer
+	TaskDebugService                influxdb.TaskDebugService
 	TelegrafService                 influxdb.TelegrafConfigStore
+	TelegrafAgentService            influxdb.TelegrafAgentService
 	ScraperTargetStoreService       influxdb.ScraperTargetStoreService
+	ScraperTargetStatusService      influxdb.ScraperTargetStatusService
 	SecretService                   influxdb.SecretService
+	SecretRotationService           influxdb.SecretRotationService
 	LookupService                   influxdb.LookupService
 	ChronografService               *server.Service
 	OrgLookupService                authorizer.OrganizationService
 	DocumentService                 influxdb.DocumentService
+	DropSeriesService               influxdb.DropSeriesService
+	DeleteService                   influxdb.DeleteService
+	CardinalityService              influxdb.CardinalityService
+	RollupVerificationService       influxdb.RollupVerificationService
+	MeasurementSchemaService        influxdb.MeasurementSchemaService
+	SeriesSchemaService             influxdb.SeriesSchemaService
+	SchemaDocService                influxdb.SchemaDocService
+	SchemaDescriptionService        influxdb.SchemaDescriptionService
+	QueryViewService                influxdb.QueryViewService
+	RunningQueryService             influxdb.RunningQueryService
+	QueryHistoryService             influxdb.QueryHistoryService
+	SearchService                   influxdb.SearchService
+	TrashService                    influxdb.TrashService
+	RevisionService                 influxdb.RevisionService
+	BucketQuotaService              influxdb.BucketQuotaService
+	OrgDeletionService              influxdb.OrgDeletionService
+	OrgUsageService                 influxdb.OrgUsageService
+	MaintenanceService              influxdb.MaintenanceService
+	MaintenanceScheduleService      influxdb.MaintenanceScheduleService
+	SystemInfoService               influxdb.SystemInfoService
+	LimitsSimulationService         influxdb.LimitsSimulationService
 }
 
 // PrometheusCollectors exposes the prometheus collectors associated with an APIBackend.
@@ -114,20 +208,139 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 
 	bucketBackend := NewBucketBackend(b)
 	bucketBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	bucketBackend.CardinalityService = authorizer.NewCardinalityService(b.CardinalityService)
+	bucketBackend.BucketQuotaService = authorizer.NewBucketQuotaService(b.BucketQuotaService)
+	bucketBackend.RollupVerificationService = authorizer.NewRollupVerificationService(b.RollupVerificationService)
+	bucketBackend.MeasurementSchemaService = authorizer.NewMeasurementSchemaService(b.MeasurementSchemaService)
+	bucketBackend.SeriesSchemaService = authorizer.NewSeriesSchemaService(b.SeriesSchemaService)
+	if b.SchemaDocService != nil {
+		bucketBackend.SchemaDocService = authorizer.NewSchemaDocService(b.SchemaDocService)
+	}
+	if b.SchemaDescriptionService != nil {
+		bucketBackend.SchemaDescriptionService = authorizer.NewSchemaDescriptionService(b.SchemaDescriptionService)
+	}
 	h.BucketHandler = NewBucketHandler(bucketBackend)
 
 	orgBackend := NewOrgBackend(b)
 	orgBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	if b.OrgUsageService != nil {
+		orgBackend.OrgUsageService = authorizer.NewOrgUsageService(b.OrgUsageService)
+	}
+	orgBackend.InviteService = authorizer.NewInviteService(b.InviteService)
+	if b.AuditService != nil && b.AuditExportService != nil {
+		audit := authorizer.NewAuditService(b.AuditService, b.AuditExportService)
+		orgBackend.AuditService, orgBackend.AuditExportService = audit, audit
+	}
+	if b.SecretRotationService != nil {
+		orgBackend.SecretRotationService = authorizer.NewSecretRotationService(b.SecretRotationService)
+	}
 	h.OrgHandler = NewOrgHandler(orgBackend)
 
+	// Invites are found and accepted by their token, by those who have no user yet.
+	inviteBackend := NewInviteBackend(b)
+	inviteBackend.InviteService = authorizer.NewInviteService(b.InviteService)
+	h.InviteHandler = NewInviteHandler(inviteBackend)
+
+	teamBackend := NewTeamBackend(b)
+	teamBackend.TeamService = authorizer.NewTeamService(b.TeamService)
+	teamBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.TeamHandler = NewTeamHandler(teamBackend)
+
+	notificationEndpointBackend := NewNotificationEndpointBackend(b)
+	notificationEndpointBackend.NotificationEndpointService = authorizer.NewNotificationEndpointService(b.NotificationEndpointService)
+	notificationEndpointBackend.NotificationSender = authorizer.NewNotificationSender(b.NotificationSender)
+	notificationEndpointBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.NotificationEndpointHandler = NewNotificationEndpointHandler(notificationEndpointBackend)
+
+	checkBackend := NewCheckBackend(b)
+	checkBackend.CheckService = authorizer.NewCheckService(b.CheckService)
+	checkBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	checkBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.CheckHandler = NewCheckHandler(checkBackend)
+
+	notificationRuleBackend := NewNotificationRuleBackend(b)
+	notificationRuleBackend.NotificationRuleService = authorizer.NewNotificationRuleService(b.NotificationRuleService)
+	notificationRuleBackend.SentNotificationService = authorizer.NewSentNotificationService(b.SentNotificationService)
+	notificationRuleBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.NotificationRuleHandler = NewNotificationRuleHandler(notificationRuleBackend)
+
+	silenceBackend := NewSilenceBackend(b)
+	silenceBackend.SilenceService = authorizer.NewSilenceService(b.SilenceService)
+	silenceBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.SilenceHandler = NewSilenceHandler(silenceBackend)
+
+	pkgerBackend := NewPkgerBackend(b)
+	pkgerBackend.PkgerService = &pkger.Service{
+		Logger:                b.Logger.With(zap.String("service", "pkger")),
+		BucketService:         authorizer.NewBucketService(b.BucketService),
+		LabelService:          authorizer.NewLabelService(b.LabelService),
+		VariableService:       authorizer.NewVariableService(b.VariableService),
+		DashboardService:      authorizer.NewDashboardService(b.DashboardService),
+		TaskService:           b.TaskService,
+		TelegrafConfigService: authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService),
+		StackService:          authorizer.NewStackService(b.StackService),
+	}
+	pkgerBackend.StackService = authorizer.NewStackService(b.StackService)
+	pkgerBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.PkgerHandler = NewPkgerHandler(pkgerBackend)
+
+	annotationBackend := NewAnnotationBackend(b)
+	annotationBackend.AnnotationService = authorizer.NewAnnotationService(b.AnnotationService)
+	annotationBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	h.AnnotationHandler = NewAnnotationHandler(annotationBackend)
+
 	userBackend := NewUserBackend(b)
 	userBackend.UserService = authorizer.NewUserService(b.UserService)
+	userBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
+	userBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	userBackend.AuthorizationService = authorizer.NewAuthorizationService(b.AuthorizationService)
+	userBackend.PasswordResetService = authorizer.NewPasswordResetService(b.PasswordResetService)
 	h.UserHandler = NewUserHandler(userBackend)
 
+	// Password resets are used by their token, by users who can not sign in.
+	passwordResetBackend := NewPasswordResetBackend(b)
+	passwordResetBackend.PasswordResetService = authorizer.NewPasswordResetService(b.PasswordResetService)
+	h.PasswordResetHandler = NewPasswordResetHandler(passwordResetBackend)
+
 	dashboardBackend := NewDashboardBackend(b)
 	dashboardBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
+	dashboardBackend.VariableService = authorizer.NewVariableService(b.VariableService)
+	dashboardBackend.RevisionService = authorizer.NewRevisionService(b.RevisionService)
+	dashboardBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	dashboardBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+	dashboardBackend.DashboardShareService = authorizer.NewDashboardShareService(b.DashboardShareService)
+	dashboardBackend.AnnotationService = authorizer.NewAnnotationService(b.AnnotationService)
+	dashboardBackend.AuthorizationService = authorizer.NewAuthorizationService(b.AuthorizationService)
 	h.DashboardHandler = NewDashboardHandler(dashboardBackend)
 
+	// Dashboard shares are viewed by their token, by those who have no user.
+	dashboardShareBackend := NewDashboardShareBackend(b)
+	dashboardShareBackend.DashboardShareService = authorizer.NewDashboardShareService(b.DashboardShareService)
+	h.DashboardShareHandler = NewDashboardShareHandler(dashboardShareBackend)
+
+	dbrpMappingBackend := NewDBRPMappingBackend(b)
+	dbrpMappingBackend.DBRPMappingService = authorizer.NewDBRPMappingService(b.DBRPMappingService)
+	dbrpMappingBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	h.DBRPMappingHandler = NewDBRPMappingHandler(dbrpMappingBackend)
+
+	dropSeriesBackend := NewDropSeriesBackend(b)
+	dropSeriesBackend.DropSeriesService = authorizer.NewDropSeriesService(b.DropSeriesService)
+	dropSeriesBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	h.DropSeriesHandler = NewDropSeriesHandler(dropSeriesBackend)
+
+	deleteBackend := NewDeleteBackend(b)
+	deleteBackend.DeleteService = authorizer.NewDeleteService(b.DeleteService)
+	deleteBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+	h.DeleteHandler = NewDeleteHandler(deleteBackend)
+
+	orgDeletionBackend := NewOrgDeletionBackend(b)
+	orgDeletionBackend.OrgDeletionService = authorizer.NewOrgDeletionService(b.OrgDeletionService)
+	h.OrgDeletionHandler = NewOrgDeletionHandler(orgDeletionBackend)
+
+	mappingBatchBackend := NewMappingBatchBackend(b)
+	mappingBatchBackend.UserResourceMappingBatchService = authorizer.NewURMBatchService(b.OrgLookupService, b.UserResourceMappingBatchService)
+	h.MappingBatchHandler = NewMappingBatchHandler(mappingBatchBackend)
+
 	variableBackend := NewVariableBackend(b)
 	variableBackend.VariableService = authorizer.NewVariableService(b.VariableService)
 	h.VariableHandler = NewVariableHandler(variableBackend)
@@ -140,6 +353,7 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	scraperBackend.ScraperStorageService = authorizer.NewScraperTargetStoreService(b.ScraperTargetStoreService,
 		b.UserResourceMappingService,
 		b.OrganizationService)
+	scraperBackend.ScraperTargetStatusService = authorizer.NewScraperTargetStatusService(b.ScraperTargetStatusService, b.ScraperTargetStoreService)
 	h.ScraperHandler = NewScraperHandler(scraperBackend)
 
 	sourceBackend := NewSourceBackend(b)
@@ -151,19 +365,81 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.SetupHandler = NewSetupHandler(setupBackend)
 
 	taskBackend := NewTaskBackend(b)
+	taskBackend.TaskTemplateService = authorizer.NewTaskTemplateService(b.TaskTemplateService)
+	taskBackend.SchedulerStateService = authorizer.NewSchedulerStateService(b.SchedulerStateService)
+	taskBackend.RevisionService = authorizer.NewRevisionService(b.RevisionService)
 	h.TaskHandler = NewTaskHandler(taskBackend)
 	h.TaskHandler.UserResourceMappingService = internalURM
 
+	taskTemplateBackend := NewTaskTemplateBackend(b)
+	taskTemplateBackend.TaskTemplateService = authorizer.NewTaskTemplateService(b.TaskTemplateService)
+	h.TaskTemplateHandler = NewTaskTemplateHandler(taskTemplateBackend)
+
+	fluxPackageBackend := NewFluxPackageBackend(b)
+	fluxPackageBackend.FluxPackageService = authorizer.NewFluxPackageService(b.FluxPackageService)
+	h.FluxPackageHandler = NewFluxPackageHandler(fluxPackageBackend)
+
+	h.TaskOptionsHandler = NewTaskOptionsHandler(b)
+
+	queryViewBackend := NewQueryViewBackend(b)
+	queryViewBackend.QueryViewService = authorizer.NewQueryViewService(b.QueryViewService)
+	h.QueryViewHandler = NewQueryViewHandler(queryViewBackend)
+
+	runningQueryBackend := NewRunningQueryBackend(b)
+	runningQueryBackend.RunningQueryService = authorizer.NewRunningQueryService(b.RunningQueryService)
+	h.RunningQueryHandler = NewRunningQueryHandler(runningQueryBackend)
+
+	queryHistoryBackend := NewQueryHistoryBackend(b)
+	queryHistoryBackend.QueryHistoryService = authorizer.NewQueryHistoryService(b.QueryHistoryService)
+	h.QueryHistoryHandler = NewQueryHistoryHandler(queryHistoryBackend)
+
+	searchBackend := NewSearchBackend(b)
+	searchBackend.SearchService = authorizer.NewSearchService(b.SearchService)
+	h.SearchHandler = NewSearchHandler(searchBackend)
+
+	trashBackend := NewTrashBackend(b)
+	trashBackend.TrashService = authorizer.NewTrashService(b.TrashService)
+	h.TrashHandler = NewTrashHandler(trashBackend)
+
 	telegrafBackend := NewTelegrafBackend(b)
 	telegrafBackend.TelegrafService = authorizer.NewTelegrafConfigService(b.TelegrafService, b.UserResourceMappingService)
+	telegrafBackend.TelegrafAgentService = authorizer.NewTelegrafAgentService(b.TelegrafAgentService, b.TelegrafService)
+	telegrafBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
 	h.TelegrafHandler = NewTelegrafHandler(telegrafBackend)
 
 	writeBackend := NewWriteBackend(b)
 	h.WriteHandler = NewWriteHandler(writeBackend)
 
+	// The 1.x endpoints authorize through the write handler, and read only
+	// the mappings of the buckets a token may read.
+	compatBackend := NewCompatBackend(b)
+	h.CompatHandler = NewCompatHandler(compatBackend, h.WriteHandler)
+
 	fluxBackend := NewFluxBackend(b)
+	fluxBackend.BucketService = authorizer.NewBucketService(b.BucketService)
 	h.QueryHandler = NewFluxHandler(fluxBackend)
 
+	maintenanceBackend := NewMaintenanceBackend(b)
+	maintenanceBackend.MaintenanceService = authorizer.NewMaintenanceService(b.MaintenanceService)
+	maintenanceBackend.MaintenanceScheduleService = authorizer.NewMaintenanceScheduleService(b.MaintenanceScheduleService)
+	h.MaintenanceHandler = NewMaintenanceHandler(maintenanceBackend)
+	h.MaintenanceService = b.MaintenanceService
+
+	h.SystemInfoHandler = NewSystemInfoHandler(NewSystemInfoBackend(b))
+
+	limitsBackend := NewLimitsBackend(b)
+	limitsBackend.LimitsSimulationService = authorizer.NewLimitsSimulationService(b.LimitsSimulationService)
+	h.LimitsHandler = NewLimitsHandler(limitsBackend)
+
+	if b.GraphQLEnabled {
+		// The task service is authorized by the caller.
+		graphQLBackend := NewGraphQLBackend(b)
+		graphQLBackend.OrganizationService = authorizer.NewOrgService(b.OrganizationService)
+		graphQLBackend.BucketService = authorizer.NewBucketService(b.BucketService)
+		graphQLBackend.DashboardService = authorizer.NewDashboardService(b.DashboardService)
+		h.GraphQLHandler = NewGraphQLHandler(graphQLBackend)
+	}
+
 	h.ChronografHandler = NewChronografHandler(b.ChronografService, b.HTTPErrorHandler)
 	h.SwaggerHandler = newSwaggerLoader(b.Logger.With(zap.String("service", "swagger-loader")), b.HTTPErrorHandler)
 	h.LabelHandler = NewLabelHandler(authorizer.NewLabelService(b.LabelService), b.HTTPErrorHandler)
@@ -177,32 +453,63 @@ var apiLinks = map[string]interface{}{
 	"authorizations": "/api/v2/authorizations",
 	"buckets":        "/api/v2/buckets",
 	"dashboards":     "/api/v2/dashboards",
+	"dbrps":          "/api/v2/dbrps",
+	"delete":         "/api/v2/delete",
+	"dropseries":     "/api/v2/dropseries",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
-	"labels":    "/api/v2/labels",
-	"variables": "/api/v2/variables",
-	"me":        "/api/v2/me",
-	"orgs":      "/api/v2/orgs",
+	"labels": "/api/v2/labels",
+	"limits": map[string]string{
+		"simulate": "/api/v2/limits/simulate",
+	},
+	"variables":    "/api/v2/variables",
+	"maintenance":  "/api/v2/maintenance",
+	"me":           "/api/v2/me",
+	"orgdeletions": "/api/v2/orgdeletions",
+	"orgs":         "/api/v2/orgs",
+	"otlp":         "/api/v2/otlp/v1/metrics",
+	"packages": map[string]string{
+		"apply": "/api/v2/packages/apply",
+	},
+	"stacks": "/api/v2/stacks",
 	"query": map[string]string{
 		"self":        "/api/v2/query",
 		"ast":         "/api/v2/query/ast",
 		"analyze":     "/api/v2/query/analyze",
+		"pages":       "/api/v2/query/pages",
 		"suggestions": "/api/v2/query/suggestions",
 	},
-	"setup":    "/api/v2/setup",
-	"signin":   "/api/v2/signin",
-	"signout":  "/api/v2/signout",
-	"sources":  "/api/v2/sources",
-	"scrapers": "/api/v2/scrapers",
-	"swagger":  "/api/v2/swagger.json",
+	"notificationEndpoints": "/api/v2/notificationEndpoints",
+	"checks":                "/api/v2/checks",
+	"notificationRules":     "/api/v2/notificationRules",
+	"notifications":         "/api/v2/notifications",
+	"silences":              "/api/v2/silences",
+	"annotations":           "/api/v2/annotations",
+	"queries":               "/api/v2/queries",
+	"queryviews":            "/api/v2/queryviews",
+	"setup":                 "/api/v2/setup",
+	"signin":                "/api/v2/signin",
+	"signout":               "/api/v2/signout",
+	"sources":               "/api/v2/sources",
+	"scrapers":              "/api/v2/scrapers",
+	"search":                "/api/v2/search",
+	"swagger":               "/api/v2/swagger.json",
 	"system": map[string]string{
 		"metrics": "/metrics",
 		"debug":   "/debug/pprof",
 		"health":  "/health",
+		"info":    "/api/v2/system/info",
+	},
+	"tasks":         "/api/v2/tasks",
+	"tasktemplates": "/api/v2/tasktemplates",
+	"teams":         "/api/v2/teams",
+	"telegraf": map[string]string{
+		"plugins": "/api/v2/telegraf/plugins",
+		"render":  "/api/v2/telegraf/render",
 	},
-	"tasks":     "/api/v2/tasks",
 	"telegrafs": "/api/v2/telegrafs",
+	"trash":     "/api/v2/trash",
 	"users":     "/api/v2/users",
 	"write":     "/api/v2/write",
 }
@@ -214,6 +521,25 @@ func (h *APIHandler) serveLinks(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// rejectForMaintenance responds with the maintenance error and returns true
+// if pathErr reports the request's path as disabled.
+func (h *APIHandler) rejectForMaintenance(w http.ResponseWriter, r *http.Request, pathErr func(influxdb.MaintenanceStatus) error) bool {
+	if h.MaintenanceService == nil {
+		return false
+	}
+
+	ctx := r.Context()
+	status, err := h.MaintenanceService.MaintenanceStatus(ctx)
+	if err == nil {
+		err = pathErr(*status)
+	}
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return true
+	}
+	return false
+}
+
 // ServeHTTP delegates a request to the appropriate subhandler.
 func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	setCORSResponseHeaders(w, r)
@@ -237,17 +563,111 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if strings.HasPrefix(r.URL.Path, "/api/v2/write") {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/write") || strings.HasPrefix(r.URL.Path, "/api/v2/otlp") {
+		if h.rejectForMaintenance(w, r, influxdb.MaintenanceStatus.WritesErr) {
+			return
+		}
 		h.WriteHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if r.URL.Path == compatWritePath || r.URL.Path == compatQueryPath {
+		pathErr := influxdb.MaintenanceStatus.QueriesErr
+		if r.URL.Path == compatWritePath {
+			pathErr = influxdb.MaintenanceStatus.WritesErr
+		}
+		if h.rejectForMaintenance(w, r, pathErr) {
+			return
+		}
+		h.CompatHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Path == compatPingPath {
+		h.CompatHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Must be checked before the query prefix, which it shares. Creating a
+	// view runs its query, so it is disabled along with query execution.
+	if strings.HasPrefix(r.URL.Path, queryViewsPath) {
+		if r.Method == "POST" && r.URL.Path == queryViewsPath && h.rejectForMaintenance(w, r, influxdb.MaintenanceStatus.QueriesErr) {
+			return
+		}
+		h.QueryViewHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Must be checked before the running queries, which would take history
+	// for the ID of a query.
+	if r.URL.Path == queryHistoryPath {
+		h.QueryHistoryHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Listing and canceling running queries stays available during
+	// maintenance, when it may be needed most.
+	if strings.HasPrefix(r.URL.Path, runningQueriesPath) {
+		h.RunningQueryHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
+		// Only query execution is disabled, including running a query into
+		// pages; the ast, analyze and suggestions endpoints do not touch
+		// storage, and pages that were already read stay available.
+		runsQuery := r.URL.Path == "/api/v2/query" || (r.Method == "POST" && r.URL.Path == queryPagesPath)
+		if runsQuery && h.rejectForMaintenance(w, r, influxdb.MaintenanceStatus.QueriesErr) {
+			return
+		}
 		h.QueryHandler.ServeHTTP(w, r)
 		return
 	}
 
+	if r.URL.Path == graphQLPath && h.GraphQLHandler != nil {
+		h.GraphQLHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if r.URL.Path == searchPath {
+		h.SearchHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, trashPath) {
+		h.TrashHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/limits") {
+		h.LimitsHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/maintenance") {
+		h.MaintenanceHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/system") {
+		h.SystemInfoHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Downsampling a bucket creates a task, so it is served by the task handler.
+	if strings.HasPrefix(r.URL.Path, "/api/v2/buckets/") && strings.HasSuffix(r.URL.Path, "/downsample") {
+		h.TaskHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/buckets") {
+		// Sampling a bucket and verifying its rollups run queries, so they
+		// are disabled along with queries.
+		runsQuery := (r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/sample")) ||
+			(r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/rollup/verify"))
+		if runsQuery && h.rejectForMaintenance(w, r, influxdb.MaintenanceStatus.QueriesErr) {
+			return
+		}
 		h.BucketHandler.ServeHTTP(w, r)
 		return
 	}
@@ -272,6 +692,56 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/invites") {
+		h.InviteHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/shares") {
+		h.DashboardShareHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/password-resets") {
+		h.PasswordResetHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/teams") {
+		h.TeamHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/notificationEndpoints") {
+		h.NotificationEndpointHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/checks") {
+		h.CheckHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/notificationRules") || strings.HasPrefix(r.URL.Path, "/api/v2/notifications") {
+		h.NotificationRuleHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/silences") {
+		h.SilenceHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/packages") || strings.HasPrefix(r.URL.Path, "/api/v2/stacks") {
+		h.PkgerHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/annotations") {
+		h.AnnotationHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/authorizations") {
 		h.AuthorizationHandler.ServeHTTP(w, r)
 		return
@@ -282,6 +752,40 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/dbrps") {
+		h.DBRPMappingHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Dropping series deletes from storage, so it is disabled along with
+	// writes; its operations can still be listed.
+	if strings.HasPrefix(r.URL.Path, "/api/v2/dropseries") {
+		if r.Method == "POST" && h.rejectForMaintenance(w, r, influxdb.MaintenanceStatus.WritesErr) {
+			return
+		}
+		h.DropSeriesHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Deleting data writes to storage, so it is disabled along with writes.
+	if r.URL.Path == deletePath {
+		if h.rejectForMaintenance(w, r, influxdb.MaintenanceStatus.WritesErr) {
+			return
+		}
+		h.DeleteHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/orgdeletions") {
+		h.OrgDeletionHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/mappings") {
+		h.MappingBatchHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/sources") {
 		h.SourceHandler.ServeHTTP(w, r)
 		return
@@ -292,12 +796,30 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/flux/packages") {
+		h.FluxPackageHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Must be checked before the tasks prefix, which it shares.
+	if strings.HasPrefix(r.URL.Path, "/api/v2/tasktemplates") {
+		h.TaskTemplateHandler.ServeHTTP(w, r)
+		return
+	}
+
+	// Must be checked before the tasks prefix, since the task routes would take
+	// options for a task ID.
+	if strings.HasPrefix(r.URL.Path, "/api/v2/tasks/options") {
+		h.TaskOptionsHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/tasks") {
 		h.TaskHandler.ServeHTTP(w, r)
 		return
 	}
 
-	if strings.HasPrefix(r.URL.Path, "/api/v2/telegrafs") {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/telegrafs") || strings.HasPrefix(r.URL.Path, "/api/v2/telegraf/") {
 		h.TelegrafHandler.ServeHTTP(w, r)
 		return
 	}
@@ -26,6 +26,7 @@ type AuthorizationBackend struct {
 	OrganizationService  platform.OrganizationService
 	UserService          platform.UserService
 	LookupService        platform.LookupService
+	LabelService         platform.LabelService
 }
 
 // NewAuthorizationBackend returns a new instance of AuthorizationBackend.
@@ -38,6 +39,7 @@ func NewAuthorizationBackend(b *APIBackend) *AuthorizationBackend {
 		OrganizationService:  b.OrganizationService,
 		UserService:          b.UserService,
 		LookupService:        b.LookupService,
+		LabelService:         b.LabelService,
 	}
 }
 
@@ -51,6 +53,7 @@ type AuthorizationHandler struct {
 	UserService          platform.UserService
 	AuthorizationService platform.AuthorizationService
 	LookupService        platform.LookupService
+	LabelService         platform.LabelService
 }
 
 // NewAuthorizationHandler returns a new instance of AuthorizationHandler.
@@ -64,6 +67,7 @@ func NewAuthorizationHandler(b *AuthorizationBackend) *AuthorizationHandler {
 		OrganizationService:  b.OrganizationService,
 		UserService:          b.UserService,
 		LookupService:        b.LookupService,
+		LabelService:         b.LabelService,
 	}
 
 	h.HandlerFunc("POST", "/api/v2/authorizations", h.handlePostAuthorization)
@@ -71,6 +75,16 @@ func NewAuthorizationHandler(b *AuthorizationBackend) *AuthorizationHandler {
 	h.HandlerFunc("GET", "/api/v2/authorizations/:id", h.handleGetAuthorization)
 	h.HandlerFunc("PATCH", "/api/v2/authorizations/:id", h.handleUpdateAuthorization)
 	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id", h.handleDeleteAuthorization)
+
+	labelBackend := &LabelBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "label")),
+		LabelService:     b.LabelService,
+		ResourceType:     platform.AuthorizationsResourceType,
+	}
+	h.HandlerFunc("GET", "/api/v2/authorizations/:id/labels", newGetLabelsHandler(labelBackend))
+	h.HandlerFunc("POST", "/api/v2/authorizations/:id/labels", newPostLabelHandler(labelBackend))
+	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id/labels/:lid", newDeleteLabelHandler(labelBackend))
 	return h
 }
 
@@ -116,14 +130,15 @@ func (a *authResponse) toPlatform() *platform.Authorization {
 		UserID:      a.UserID,
 	}
 	for _, p := range a.Permissions {
-		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource})
+		res.Permissions = append(res.Permissions, platform.Permission{Action: p.Action, Resource: p.Resource.Resource, Measurements: p.Measurements})
 	}
 	return res
 }
 
 type permissionResponse struct {
-	Action   platform.Action  `json:"action"`
-	Resource resourceResponse `json:"resource"`
+	Action       platform.Action  `json:"action"`
+	Resource     resourceResponse `json:"resource"`
+	Measurements []string         `json:"measurements,omitempty"`
 }
 
 type resourceResponse struct {
@@ -140,6 +155,7 @@ func newPermissionsResponse(ctx context.Context, ps []platform.Permission, svc p
 			Resource: resourceResponse{
 				Resource: p.Resource,
 			},
+			Measurements: p.Measurements,
 		}
 
 		if p.Resource.ID != nil {
@@ -336,13 +352,25 @@ func (h *AuthorizationHandler) handleGetAuthorizations(w http.ResponseWriter, r
 		return
 	}
 
-	opts := platform.FindOptions{}
-	as, _, err := h.AuthorizationService.FindAuthorizations(ctx, req.filter, opts)
+	as, _, err := h.AuthorizationService.FindAuthorizations(ctx, req.filter, req.labels.findOptions(req.opts))
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
+	if len(req.labels) > 0 {
+		idx, err := req.labels.filter(ctx, h.LabelService, req.opts, len(as), func(i int) platform.ID { return as[i].ID })
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		labeled := make([]*platform.Authorization, 0, len(idx))
+		for _, i := range idx {
+			labeled = append(labeled, as[i])
+		}
+		as = labeled
+	}
+
 	auths := make([]*authResponse, 0, len(as))
 	for _, a := range as {
 		o, err := h.OrganizationService.FindOrganizationByID(ctx, a.OrgID)
@@ -368,7 +396,11 @@ func (h *AuthorizationHandler) handleGetAuthorizations(w http.ResponseWriter, r
 
 	h.Logger.Debug("auths retrieved ", zap.String("auths", fmt.Sprint(auths)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newAuthsResponse(auths)); err != nil {
+	// Paging links are of the page found, even if some of its authorizations are left out.
+	links := newPagingLinks(authorizationPath, req.opts, req.labels.pagingFilter(req.filter), len(as))
+	res := newAuthsResponse(auths)
+	addPagingLinks(res.Links, links)
+	if err := encodeListResponse(ctx, w, req.opts.Fields, links, "authorizations", res); err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
@@ -376,6 +408,8 @@ func (h *AuthorizationHandler) handleGetAuthorizations(w http.ResponseWriter, r
 
 type getAuthorizationsRequest struct {
 	filter platform.AuthorizationFilter
+	opts   platform.FindOptions
+	labels labelFilter
 }
 
 func decodeGetAuthorizationsRequest(ctx context.Context, r *http.Request) (*getAuthorizationsRequest, error) {
@@ -383,6 +417,15 @@ func decodeGetAuthorizationsRequest(ctx context.Context, r *http.Request) (*getA
 
 	req := &getAuthorizationsRequest{}
 
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.ValidSortBy(platform.AuthorizationSortKeys...); err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
 	userID := qp.Get("userID")
 	if userID != "" {
 		id, err := platform.IDFromString(userID)
@@ -420,6 +463,8 @@ func decodeGetAuthorizationsRequest(ctx context.Context, r *http.Request) (*getA
 		req.filter.ID = id
 	}
 
+	req.labels = decodeLabelFilter(r)
+
 	return req, nil
 }
 
@@ -712,6 +757,14 @@ func (s *AuthorizationService) FindAuthorizations(ctx context.Context, filter pl
 		query.Add("org", *filter.Org)
 	}
 
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			for _, v := range vs {
+				query.Add(k, v)
+			}
+		}
+	}
+
 	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
 
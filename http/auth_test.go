@@ -29,6 +29,7 @@ func NewMockAuthorizationBackend() *AuthorizationBackend {
 		OrganizationService:  mock.NewOrganizationService(),
 		UserService:          mock.NewUserService(),
 		LookupService:        mock.NewLookupService(),
+		LabelService:         mock.NewLabelService(),
 	}
 }
 
@@ -21,6 +21,11 @@ type AuthenticationHandler struct {
 	SessionService       platform.SessionService
 	SessionRenewDisabled bool
 
+	// ClientCertAuthenticator authenticates the requests that present a verified
+	// client certificate, but no token or session. Client certificates are not
+	// accepted if it is nil.
+	ClientCertAuthenticator *ClientCertAuthenticator
+
 	// This is only really used for it's lookup method the specific http
 	// handler used to register routes does not matter.
 	noAuthRouter *httprouter.Router
@@ -45,8 +50,9 @@ func (h *AuthenticationHandler) RegisterNoAuthRoute(method, path string) {
 }
 
 const (
-	tokenAuthScheme   = "token"
-	sessionAuthScheme = "session"
+	tokenAuthScheme      = "token"
+	sessionAuthScheme    = "session"
+	clientCertAuthScheme = "certificate"
 )
 
 // ProbeAuthScheme probes the http request for the requests for token or cookie session.
@@ -74,6 +80,9 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 
 	ctx := r.Context()
 	scheme, err := ProbeAuthScheme(r)
+	if err != nil && h.ClientCertAuthenticator != nil && clientCert(r) != nil {
+		scheme, err = clientCertAuthScheme, nil
+	}
 	if err != nil {
 		UnauthorizedError(ctx, h, w)
 		return
@@ -96,6 +105,15 @@ func (h *AuthenticationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		r = r.WithContext(ctx)
 		h.Handler.ServeHTTP(w, r)
 		return
+	case clientCertAuthScheme:
+		ctx, err = h.extractClientCert(ctx, r)
+		if err != nil {
+			h.Logger.Info("client certificate rejected", zap.Error(err))
+			break
+		}
+		r = r.WithContext(ctx)
+		h.Handler.ServeHTTP(w, r)
+		return
 	}
 
 	UnauthorizedError(ctx, h, w)
@@ -136,3 +154,12 @@ func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Requ
 
 	return platcontext.SetAuthorizer(ctx, s), nil
 }
+
+func (h *AuthenticationHandler) extractClientCert(ctx context.Context, r *http.Request) (context.Context, error) {
+	a, err := h.ClientCertAuthenticator.Authorizer(ctx, clientCert(r))
+	if err != nil {
+		return ctx, err
+	}
+
+	return platcontext.SetAuthorizer(ctx, a), nil
+}
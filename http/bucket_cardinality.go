@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// bucketCardinalityDefaultRange is the time range of the trend when no start is given.
+const bucketCardinalityDefaultRange = 30 * 24 * time.Hour
+
+type bucketCardinalityResponse struct {
+	Links     map[string]string               `json:"links"`
+	BucketID  influxdb.ID                     `json:"bucketID"`
+	Start     time.Time                       `json:"start"`
+	Stop      time.Time                       `json:"stop"`
+	Snapshots []*influxdb.CardinalitySnapshot `json:"snapshots"`
+	// Current is the cardinality of the bucket now, which the trend only
+	// catches up with at the next snapshot.
+	Current *influxdb.CardinalitySnapshot `json:"current,omitempty"`
+}
+
+func newBucketCardinalityResponse(filter influxdb.CardinalityTrendFilter, snapshots []*influxdb.CardinalitySnapshot, current *influxdb.CardinalitySnapshot) *bucketCardinalityResponse {
+	if snapshots == nil {
+		snapshots = []*influxdb.CardinalitySnapshot{}
+	}
+	return &bucketCardinalityResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/cardinality", filter.BucketID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", filter.BucketID),
+		},
+		BucketID:  filter.BucketID,
+		Start:     filter.Start,
+		Stop:      filter.Stop,
+		Snapshots: snapshots,
+		Current:   current,
+	}
+}
+
+// handleGetBucketCardinality is the HTTP handler for the GET /api/v2/buckets/:id/cardinality route.
+// It returns the cardinality snapshots recorded for the bucket, oldest first.
+func (h *BucketHandler) handleGetBucketCardinality(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetBucketCardinalityRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.CardinalityService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "bucket cardinality is not available",
+		}, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, filter.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	filter.OrgID = b.OrgID
+
+	snapshots, err := h.CardinalityService.FindCardinalityTrend(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	current, err := h.CardinalityService.FindCurrentCardinality(ctx, b.OrgID, b.ID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("bucket cardinality retrieved", zap.String("bucketID", b.ID.String()), zap.Int("snapshots", len(snapshots)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketCardinalityResponse(*filter, snapshots, current)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetBucketCardinalityRequest(ctx context.Context, r *http.Request) (*influxdb.CardinalityTrendFilter, error) {
+	gr, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &influxdb.CardinalityTrendFilter{
+		BucketID: gr.BucketID,
+		Stop:     time.Now().UTC(),
+	}
+
+	qp := r.URL.Query()
+	if stop := qp.Get("stop"); stop != "" {
+		t, err := time.Parse(time.RFC3339, stop)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "stop must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		filter.Stop = t
+	}
+
+	filter.Start = filter.Stop.Add(-bucketCardinalityDefaultRange)
+	if start := qp.Get("start"); start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "start must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		filter.Start = t
+	}
+
+	if !filter.Start.Before(filter.Stop) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "start must be before stop",
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func TestBucketHandler_handleGetBucketCardinality(t *testing.T) {
+	day := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: id, OrgID: 2, Name: "b"}, nil
+	}
+
+	var gotFilter *platform.CardinalityTrendFilter
+	cs := mock.NewCardinalityService()
+	cs.FindCardinalityTrendFn = func(ctx context.Context, filter platform.CardinalityTrendFilter) ([]*platform.CardinalitySnapshot, error) {
+		gotFilter = &filter
+		return []*platform.CardinalitySnapshot{
+			{Time: day, Series: 10, TagValues: map[string]int64{"host": 5}},
+			{Time: day.Add(24 * time.Hour), Series: 1000, TagValues: map[string]int64{"host": 5, "request_id": 995}},
+		}, nil
+	}
+	cs.FindCurrentCardinalityFn = func(ctx context.Context, orgID, bucketID platform.ID) (*platform.CardinalitySnapshot, error) {
+		return &platform.CardinalitySnapshot{Time: day.Add(36 * time.Hour), Series: 1500, TagValues: map[string]int64{"host": 5, "request_id": 1495}}, nil
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantStart  time.Time
+		wantBody   string
+	}{
+		{
+			name:       "time range",
+			query:      "?start=2019-07-01T00:00:00Z&stop=2019-07-03T00:00:00Z",
+			wantStatus: http.StatusOK,
+			wantStart:  day,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000001/cardinality",
+    "bucket": "/api/v2/buckets/0000000000000001"
+  },
+  "bucketID": "0000000000000001",
+  "start": "2019-07-01T00:00:00Z",
+  "stop": "2019-07-03T00:00:00Z",
+  "snapshots": [
+    {"time": "2019-07-01T00:00:00Z", "series": 10, "tagValues": {"host": 5}},
+    {"time": "2019-07-02T00:00:00Z", "series": 1000, "tagValues": {"host": 5, "request_id": 995}}
+  ],
+  "current": {"time": "2019-07-02T12:00:00Z", "series": 1500, "tagValues": {"host": 5, "request_id": 1495}}
+}`,
+		},
+		{
+			name:       "default start",
+			query:      "?stop=2019-07-31T00:00:00Z",
+			wantStatus: http.StatusOK,
+			wantStart:  day,
+		},
+		{
+			name:       "invalid start",
+			query:      "?start=yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "start after stop",
+			query:      "?start=2019-07-03T00:00:00Z&stop=2019-07-01T00:00:00Z",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFilter = nil
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler:   ErrorHandler(0),
+				Logger:             zap.NewNop(),
+				BucketService:      bs,
+				CardinalityService: cs,
+			})
+
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/buckets/0000000000000001/cardinality"+tt.query, nil)
+			r = r.WithContext(context.WithValue(
+				pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+			w := httptest.NewRecorder()
+			h.handleGetBucketCardinality(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if gotFilter != nil {
+					t.Fatal("the trend should not be read for an invalid request")
+				}
+				return
+			}
+
+			if gotFilter.OrgID != 2 || gotFilter.BucketID != 1 || !gotFilter.Start.Equal(tt.wantStart) {
+				t.Errorf("unexpected filter %+v", gotFilter)
+			}
+
+			if tt.wantBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+					t.Errorf("error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("***%s***", diff)
+				}
+			}
+		})
+	}
+}
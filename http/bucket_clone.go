@@ -0,0 +1,228 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+// bucketCloneMaxCopyLookback is the most recent data of a bucket that can be
+// copied to its clone, which keeps a clone from running as long as a backup.
+const bucketCloneMaxCopyLookback = 7 * 24 * time.Hour
+
+type postBucketCloneRequestBody struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	// CopyLookback is how far back the data of the bucket is copied to the clone, such as 1h.
+	CopyLookback string `json:"copyLookback,omitempty"`
+}
+
+type postBucketCloneRequest struct {
+	BucketID     influxdb.ID
+	Name         string
+	Description  *string
+	CopyLookback time.Duration
+}
+
+// handlePostBucketClone is the HTTP handler for the POST /api/v2/buckets/:id/clone route.
+// It creates a bucket with the settings, labels and measurement schemas of the
+// bucket, and copies its recent data if it is asked to.
+func (h *BucketHandler) handlePostBucketClone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostBucketCloneRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	src, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if req.CopyLookback > 0 && h.QueryService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "copying data to a bucket clone is not available",
+		}, w)
+		return
+	}
+
+	b, labels, err := h.cloneBucket(ctx, src, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("bucket cloned", zap.String("bucketID", src.ID.String()), zap.String("cloneID", b.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newBucketResponse(b, labels)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// cloneBucket creates the clone of a bucket. If any part of the clone fails,
+// the clone is deleted, so that a failed clone doesn't leave a partial copy.
+func (h *BucketHandler) cloneBucket(ctx context.Context, src *influxdb.Bucket, req *postBucketCloneRequest) (*influxdb.Bucket, []*influxdb.Label, error) {
+	b := &influxdb.Bucket{
+		OrgID:              src.OrgID,
+		Name:               req.Name,
+		Description:        src.Description,
+		RetentionPeriod:    src.RetentionPeriod,
+		ReorderWindow:      src.ReorderWindow,
+		Quota:              src.Quota,
+		QueryCacheDisabled: src.QueryCacheDisabled,
+		SchemaType:         src.SchemaType,
+		WriteHooks:         src.WriteHooks,
+	}
+	if req.Description != nil {
+		b.Description = *req.Description
+	}
+	if err := h.BucketService.CreateBucket(ctx, b); err != nil {
+		return nil, nil, err
+	}
+
+	labels, err := h.copyBucket(ctx, src, b, req.CopyLookback)
+	if err != nil {
+		if derr := h.BucketService.DeleteBucket(ctx, b.ID); derr != nil {
+			h.Logger.Error("failed to delete bucket after failed clone", zap.String("bucketID", b.ID.String()), zap.Error(derr))
+		}
+		return nil, nil, err
+	}
+	return b, labels, nil
+}
+
+// copyBucket copies the labels, measurement schemas and the data of the last
+// lookback of a bucket to its clone, and returns the labels of the clone.
+func (h *BucketHandler) copyBucket(ctx context.Context, src, dst *influxdb.Bucket, lookback time.Duration) ([]*influxdb.Label, error) {
+	labels, err := h.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: src.ID, ResourceType: influxdb.BucketsResourceType})
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		if err := h.LabelService.CreateLabelMapping(ctx, &influxdb.LabelMapping{
+			LabelID:      l.ID,
+			ResourceID:   dst.ID,
+			ResourceType: influxdb.BucketsResourceType,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if src.SchemaType == influxdb.BucketSchemaTypeExplicit && h.MeasurementSchemaService != nil {
+		schemas, err := h.MeasurementSchemaService.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: src.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range schemas {
+			if err := h.MeasurementSchemaService.CreateMeasurementSchema(ctx, &influxdb.MeasurementSchema{
+				OrgID:    dst.OrgID,
+				BucketID: dst.ID,
+				Name:     m.Name,
+				Columns:  m.Columns,
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if lookback > 0 {
+		if err := h.copyBucketData(ctx, src, dst, lookback); err != nil {
+			return nil, err
+		}
+	}
+	return labels, nil
+}
+
+// copyBucketData copies the data of the last lookback of a bucket to another
+// bucket with a query, so that it is written like any other points.
+func (h *BucketHandler) copyBucketData(ctx context.Context, src, dst *influxdb.Bucket, lookback time.Duration) error {
+	auth, err := pcontext.GetAuthorization(ctx, src.OrgID)
+	if err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: -%dms)
+	|> to(bucketID: %q, orgID: %q)`, src.ID.String(), lookback/time.Millisecond, dst.ID.String(), dst.OrgID.String())
+
+	itr, err := h.QueryService.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: src.OrgID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	})
+	if err != nil {
+		return handleFluxError(err)
+	}
+	defer itr.Release()
+
+	for itr.More() {
+		// The tables are written as they are read, so they must be consumed.
+		if err := itr.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(flux.ColReader) error { return nil })
+		}); err != nil {
+			return handleFluxError(err)
+		}
+	}
+	if err := itr.Err(); err != nil {
+		return handleFluxError(err)
+	}
+	return nil
+}
+
+func decodePostBucketCloneRequest(ctx context.Context, r *http.Request) (*postBucketCloneRequest, error) {
+	gr, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var body postBucketCloneRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+	if body.Name == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "bucket clone requires a name",
+		}
+	}
+
+	req := &postBucketCloneRequest{
+		BucketID:    gr.BucketID,
+		Name:        body.Name,
+		Description: body.Description,
+	}
+	if body.CopyLookback != "" {
+		lookback, err := time.ParseDuration(body.CopyLookback)
+		if err != nil || lookback <= 0 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "copyLookback must be a positive duration",
+			}
+		}
+		if lookback > bucketCloneMaxCopyLookback {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("copyLookback must be at most %s", bucketCloneMaxCopyLookback),
+			}
+		}
+		req.CopyLookback = lookback
+	}
+	return req, nil
+}
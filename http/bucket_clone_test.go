@@ -0,0 +1,206 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func TestBucketHandler_handlePostBucketClone(t *testing.T) {
+	src := &platform.Bucket{
+		ID:              1,
+		OrgID:           2,
+		Name:            "b",
+		Description:     "the bucket",
+		RetentionPeriod: 24 * time.Hour,
+		ReorderWindow:   time.Minute,
+		SchemaType:      platform.BucketSchemaTypeExplicit,
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		queryErr   error
+		wantStatus int
+		wantBucket *platform.Bucket
+		wantFlux   []string
+		wantDelete bool
+	}{
+		{
+			name:       "settings and labels",
+			body:       `{"name": "c"}`,
+			wantStatus: http.StatusCreated,
+			wantBucket: &platform.Bucket{
+				ID:              3,
+				OrgID:           2,
+				Name:            "c",
+				Description:     "the bucket",
+				RetentionPeriod: 24 * time.Hour,
+				ReorderWindow:   time.Minute,
+				SchemaType:      platform.BucketSchemaTypeExplicit,
+			},
+		},
+		{
+			name:       "recent data",
+			body:       `{"name": "c", "description": "a copy", "copyLookback": "2h"}`,
+			wantStatus: http.StatusCreated,
+			wantBucket: &platform.Bucket{
+				ID:              3,
+				OrgID:           2,
+				Name:            "c",
+				Description:     "a copy",
+				RetentionPeriod: 24 * time.Hour,
+				ReorderWindow:   time.Minute,
+				SchemaType:      platform.BucketSchemaTypeExplicit,
+			},
+			wantFlux: []string{`from(bucketID: "0000000000000001")`, `range(start: -7200000ms)`, `to(bucketID: "0000000000000003", orgID: "0000000000000002")`},
+		},
+		{
+			name:       "failed copy",
+			body:       `{"name": "c", "copyLookback": "2h"}`,
+			queryErr:   fmt.Errorf("bucket not found"),
+			wantStatus: http.StatusInternalServerError,
+			wantDelete: true,
+		},
+		{
+			name:       "missing name",
+			body:       `{"copyLookback": "2h"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid lookback",
+			body:       `{"name": "c", "copyLookback": "yesterday"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "lookback too long",
+			body:       `{"name": "c", "copyLookback": "720h"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *platform.Bucket
+			var deleted bool
+			bs := mock.NewBucketService()
+			bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+				return src, nil
+			}
+			bs.CreateBucketFn = func(ctx context.Context, b *platform.Bucket) error {
+				b.ID = 3
+				created = b
+				return nil
+			}
+			bs.DeleteBucketFn = func(ctx context.Context, id platform.ID) error {
+				deleted = id == 3
+				return nil
+			}
+
+			var mappings []*platform.LabelMapping
+			ls := mock.NewLabelService()
+			ls.FindResourceLabelsFn = func(ctx context.Context, filter platform.LabelMappingFilter) ([]*platform.Label, error) {
+				return []*platform.Label{{ID: 4, Name: "l"}}, nil
+			}
+			ls.CreateLabelMappingFn = func(ctx context.Context, m *platform.LabelMapping) error {
+				mappings = append(mappings, m)
+				return nil
+			}
+
+			var schemas []*platform.MeasurementSchema
+			ms := mock.NewMeasurementSchemaService()
+			ms.FindMeasurementSchemasFn = func(ctx context.Context, filter platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error) {
+				return []*platform.MeasurementSchema{{ID: 5, BucketID: filter.BucketID, Name: "cpu"}}, nil
+			}
+			ms.CreateMeasurementSchemaFn = func(ctx context.Context, m *platform.MeasurementSchema) error {
+				schemas = append(schemas, m)
+				return nil
+			}
+
+			var gotReq *query.Request
+			qs := &querymock.QueryService{
+				QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+					gotReq = req
+					if tt.queryErr != nil {
+						return nil, tt.queryErr
+					}
+					return flux.NewSliceResultIterator(nil), nil
+				},
+			}
+
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler:         ErrorHandler(0),
+				Logger:                   zap.NewNop(),
+				BucketService:            bs,
+				LabelService:             ls,
+				MeasurementSchemaService: ms,
+				QueryService:             qs,
+			})
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/buckets/0000000000000001/clone", strings.NewReader(tt.body))
+			r = r.WithContext(context.WithValue(
+				pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+			w := httptest.NewRecorder()
+			h.handlePostBucketClone(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if deleted != tt.wantDelete {
+				t.Errorf("got clone deleted %v, want %v", deleted, tt.wantDelete)
+			}
+			if tt.wantBucket == nil {
+				return
+			}
+
+			if !reflect.DeepEqual(created, tt.wantBucket) {
+				t.Errorf("got bucket %+v, want %+v", created, tt.wantBucket)
+			}
+			if len(mappings) != 1 || mappings[0].LabelID != 4 || mappings[0].ResourceID != 3 || mappings[0].ResourceType != platform.BucketsResourceType {
+				t.Errorf("expected the label of the bucket to be added to its clone, got %+v", mappings)
+			}
+			if len(schemas) != 1 || schemas[0].BucketID != 3 || schemas[0].Name != "cpu" {
+				t.Errorf("expected the schema of the bucket to be added to its clone, got %+v", schemas)
+			}
+			if !strings.Contains(string(body), `"labels":[{"id":"0000000000000004","name":"l"}]`) {
+				t.Errorf("expected the labels of the clone in the response, got %s", body)
+			}
+
+			if len(tt.wantFlux) == 0 {
+				if gotReq != nil {
+					t.Error("no data should be copied without a lookback")
+				}
+				return
+			}
+			if gotReq == nil || gotReq.OrganizationID != 2 {
+				t.Fatalf("expected a query in the bucket organization, got %+v", gotReq)
+			}
+			script := gotReq.Compiler.(lang.FluxCompiler).Query
+			for _, want := range tt.wantFlux {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected query to contain %q, got:\n%s", want, script)
+				}
+			}
+		})
+	}
+}
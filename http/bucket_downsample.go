@@ -0,0 +1,304 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// bucketsIDDownsamplePath is served by the TaskHandler rather than the
+// BucketHandler, as a downsample creates a task.
+const bucketsIDDownsamplePath = "/api/v2/buckets/:id/downsample"
+
+// downsampleAggregates are the aggregate functions a bucket may be
+// downsampled with, which all take a table and return a single row.
+var downsampleAggregates = map[string]bool{
+	"mean":   true,
+	"median": true,
+	"sum":    true,
+	"count":  true,
+	"min":    true,
+	"max":    true,
+	"first":  true,
+	"last":   true,
+	"spread": true,
+	"stddev": true,
+}
+
+type postBucketDownsampleRequestBody struct {
+	// TargetBucketID or TargetBucket, the name of a bucket of the same
+	// organization, is the bucket the aggregates are written to.
+	TargetBucketID platform.ID `json:"targetBucketID,omitempty"`
+	TargetBucket   string      `json:"targetBucket,omitempty"`
+	// Every is both how often the task runs and the window of the aggregates, such as 1h.
+	Every string `json:"every"`
+	// Offset delays the runs of the task, so that late data is included.
+	Offset string `json:"offset,omitempty"`
+	// Fn is the aggregate function, which defaults to mean.
+	Fn          string                        `json:"fn,omitempty"`
+	Measurement string                        `json:"measurement,omitempty"`
+	Tags        []platform.SeriesTagPredicate `json:"tags,omitempty"`
+	Name        string                        `json:"name,omitempty"`
+	Description string                        `json:"description,omitempty"`
+	Status      string                        `json:"status,omitempty"`
+}
+
+type postBucketDownsampleRequest struct {
+	BucketID platform.ID
+	Body     postBucketDownsampleRequestBody
+	Every    *ast.DurationLiteral
+	Offset   *ast.DurationLiteral
+}
+
+// handlePostBucketDownsample is the HTTP handler for the POST /api/v2/buckets/:id/downsample route.
+// It creates a task that writes the aggregates of each window of the bucket to
+// another bucket, so that the Flux of a downsampling task isn't written by hand.
+func (h *TaskHandler) handlePostBucketDownsample(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EUnauthorized,
+			Msg:  "failed to get authorizer",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodePostBucketDownsampleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	src, dst, err := h.findDownsampleBuckets(ctx, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tc := platform.TaskCreate{
+		Flux:           downsampleFlux(src, dst, req),
+		Description:    req.Body.Description,
+		Status:         req.Body.Status,
+		OrganizationID: src.OrgID,
+	}
+	if err := tc.Validate(); err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "downsample does not describe a valid task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// The task service checks that the authorizer may read from the source
+	// bucket and write to the target bucket of the script.
+	task, err := h.createTask(ctx, auth, tc)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.logger.Debug("downsample task created", zap.String("bucketID", src.ID.String()), zap.String("taskID", task.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newTaskResponse(*task, []*platform.Label{})); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+// findDownsampleBuckets returns the bucket that is downsampled and the bucket
+// its aggregates are written to, which must be another bucket of its organization.
+func (h *TaskHandler) findDownsampleBuckets(ctx context.Context, req *postBucketDownsampleRequest) (*platform.Bucket, *platform.Bucket, error) {
+	src, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var dst *platform.Bucket
+	if req.Body.TargetBucketID.Valid() {
+		dst, err = h.BucketService.FindBucketByID(ctx, req.Body.TargetBucketID)
+	} else {
+		dst, err = h.BucketService.FindBucket(ctx, platform.BucketFilter{
+			OrganizationID: &src.OrgID,
+			Name:           &req.Body.TargetBucket,
+		})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if dst.OrgID != src.OrgID {
+		return nil, nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "target bucket must be in the organization of the bucket",
+		}
+	}
+	if dst.ID == src.ID {
+		return nil, nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "target bucket must not be the bucket that is downsampled",
+		}
+	}
+	return src, dst, nil
+}
+
+// downsampleFlux returns the script of a task that downsamples a bucket. The
+// buckets are referenced by name, so that the script reads like one written
+// by hand and is kept up to date when either is renamed.
+func downsampleFlux(src, dst *platform.Bucket, req *postBucketDownsampleRequest) string {
+	name := req.Body.Name
+	if name == "" {
+		name = fmt.Sprintf("Downsample %s to %s", src.Name, dst.Name)
+	}
+	fn := req.Body.Fn
+	if fn == "" {
+		fn = "mean"
+	}
+	every := ast.Format(req.Every)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "option task = {name: %s, every: %s", ast.Format(&ast.StringLiteral{Value: name}), every)
+	if req.Offset != nil {
+		fmt.Fprintf(&b, ", offset: %s", ast.Format(req.Offset))
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "from(bucket: %s)\n", ast.Format(&ast.StringLiteral{Value: src.Name}))
+	b.WriteString("\t|> range(start: -task.every)\n")
+	if pred := downsamplePredicate(req.Body.Measurement, req.Body.Tags); pred != nil {
+		fmt.Fprintf(&b, "\t|> filter(fn: (r) => %s)\n", ast.Format(pred))
+	}
+	fmt.Fprintf(&b, "\t|> aggregateWindow(every: %s, fn: %s)\n", every, fn)
+	fmt.Fprintf(&b, "\t|> to(bucket: %s, orgID: %s)\n",
+		ast.Format(&ast.StringLiteral{Value: dst.Name}),
+		ast.Format(&ast.StringLiteral{Value: dst.OrgID.String()}))
+	return b.String()
+}
+
+// downsamplePredicate returns the expression that matches the records of the
+// measurement, if there is one, that match all of the tag predicates, or nil
+// if every record is downsampled.
+func downsamplePredicate(measurement string, tags []platform.SeriesTagPredicate) ast.Expression {
+	var exprs []ast.Expression
+	if measurement != "" {
+		exprs = append(exprs, &ast.BinaryExpression{
+			Operator: ast.EqualOperator,
+			Left:     &ast.MemberExpression{Object: &ast.Identifier{Name: "r"}, Property: &ast.Identifier{Name: "_measurement"}},
+			Right:    &ast.StringLiteral{Value: measurement},
+		})
+	}
+	for _, p := range tags {
+		var value ast.Expression = &ast.StringLiteral{Value: p.Value}
+		switch p.Oper() {
+		case platform.TagPredicateRegex, platform.TagPredicateNotRegex:
+			// The predicate is valid, so the expression compiles.
+			value = &ast.RegexpLiteral{Value: regexp.MustCompile(p.Value)}
+		}
+		exprs = append(exprs, &ast.BinaryExpression{
+			Operator: ast.OperatorLookup(p.Oper()),
+			Left:     &ast.MemberExpression{Object: &ast.Identifier{Name: "r"}, Property: &ast.StringLiteral{Value: p.Key}},
+			Right:    value,
+		})
+	}
+	if len(exprs) == 0 {
+		return nil
+	}
+
+	pred := exprs[0]
+	for _, e := range exprs[1:] {
+		pred = &ast.LogicalExpression{Operator: ast.AndOperator, Left: pred, Right: e}
+	}
+	return pred
+}
+
+func decodePostBucketDownsampleRequest(ctx context.Context, r *http.Request) (*postBucketDownsampleRequest, error) {
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		return nil, err
+	}
+	req := &postBucketDownsampleRequest{BucketID: id}
+
+	if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	if !req.Body.TargetBucketID.Valid() && req.Body.TargetBucket == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "targetBucket or targetBucketID is required",
+		}
+	}
+
+	var ok bool
+	if req.Every, ok = parseFluxDuration(req.Body.Every); !ok || !positiveFluxDuration(req.Every) {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "every must be a positive duration, such as 1h",
+		}
+	}
+	if req.Body.Offset != "" {
+		if req.Offset, ok = parseFluxDuration(req.Body.Offset); !ok {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "offset must be a duration, such as 5m",
+			}
+		}
+	}
+
+	if req.Body.Fn != "" && !downsampleAggregates[req.Body.Fn] {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("unsupported aggregate function %q", req.Body.Fn),
+		}
+	}
+	for _, p := range req.Body.Tags {
+		if err := p.Valid(); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// parseFluxDuration parses a Flux duration literal, such as 1h30m or 1mo,
+// which may use units that a time.Duration can't.
+func parseFluxDuration(s string) (*ast.DurationLiteral, bool) {
+	if s == "" {
+		return nil, false
+	}
+	pkg := parser.ParseSource("d = " + s)
+	if ast.Check(pkg) > 0 || len(pkg.Files) != 1 || len(pkg.Files[0].Body) != 1 {
+		return nil, false
+	}
+	a, ok := pkg.Files[0].Body[0].(*ast.VariableAssignment)
+	if !ok {
+		return nil, false
+	}
+	d, ok := a.Init.(*ast.DurationLiteral)
+	return d, ok
+}
+
+func positiveFluxDuration(d *ast.DurationLiteral) bool {
+	for _, v := range d.Values {
+		if v.Magnitude > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestDownsampleFlux(t *testing.T) {
+	src := &platform.Bucket{ID: 1, OrgID: 2, Name: `raw "data"`}
+	dst := &platform.Bucket{ID: 3, OrgID: 2, Name: "hourly"}
+
+	tests := []struct {
+		name string
+		body postBucketDownsampleRequestBody
+		want string
+	}{
+		{
+			name: "defaults",
+			body: postBucketDownsampleRequestBody{Every: "1h"},
+			want: `option task = {name: "Downsample raw \"data\" to hourly", every: 1h}
+
+from(bucket: "raw \"data\"")
+	|> range(start: -task.every)
+	|> aggregateWindow(every: 1h, fn: mean)
+	|> to(bucket: "hourly", orgID: "0000000000000002")
+`,
+		},
+		{
+			name: "filters",
+			body: postBucketDownsampleRequestBody{
+				Name:        "cpu",
+				Every:       "1h30m",
+				Offset:      "5m",
+				Fn:          "max",
+				Measurement: "cpu",
+				Tags: []platform.SeriesTagPredicate{
+					{Key: "host", Value: "a"},
+					{Key: "region", Op: platform.TagPredicateNotRegex, Value: "us/.*"},
+				},
+			},
+			want: `option task = {name: "cpu", every: 1h30m, offset: 5m}
+
+from(bucket: "raw \"data\"")
+	|> range(start: -task.every)
+	|> filter(fn: (r) => r._measurement == "cpu" and r["host"] == "a" and r["region"] !~ /us\/.*/)
+	|> aggregateWindow(every: 1h30m, fn: max)
+	|> to(bucket: "hourly", orgID: "0000000000000002")
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &postBucketDownsampleRequest{Body: tt.body}
+			req.Every, _ = parseFluxDuration(tt.body.Every)
+			if tt.body.Offset != "" {
+				req.Offset, _ = parseFluxDuration(tt.body.Offset)
+			}
+			if got := downsampleFlux(src, dst, req); got != tt.want {
+				t.Errorf("downsampleFlux() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_handlePostBucketDownsample(t *testing.T) {
+	buckets := map[platform.ID]*platform.Bucket{
+		1: {ID: 1, OrgID: 10, Name: "raw"},
+		2: {ID: 2, OrgID: 10, Name: "hourly"},
+		3: {ID: 3, OrgID: 20, Name: "other"},
+	}
+	bucketService := &mock.BucketService{
+		FindBucketByIDFn: func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+			if b, ok := buckets[id]; ok {
+				return b, nil
+			}
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		},
+		FindBucketFn: func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+			for _, b := range buckets {
+				if b.OrgID == *filter.OrganizationID && b.Name == *filter.Name {
+					return b, nil
+				}
+			}
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		},
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantFlux   string
+	}{
+		{
+			name:       "by name",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"targetBucket": "hourly", "every": "1h", "fn": "sum", "measurement": "cpu"}`,
+			wantStatus: http.StatusCreated,
+			wantFlux:   `|> filter(fn: (r) => r._measurement == "cpu")`,
+		},
+		{
+			name:       "by id",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"targetBucketID": "0000000000000002", "every": "1d"}`,
+			wantStatus: http.StatusCreated,
+			wantFlux:   `|> aggregateWindow(every: 1d, fn: mean)`,
+		},
+		{
+			name:       "missing target",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"every": "1h"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid every",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"targetBucket": "hourly", "every": "0s"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsupported fn",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"targetBucket": "hourly", "every": "1h", "fn": "yield"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid tag predicate",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"targetBucket": "hourly", "every": "1h", "tags": [{"key": "host", "op": "=~", "value": "("}]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "target in another org",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"targetBucketID": "0000000000000003", "every": "1h"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "target is the bucket",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"targetBucketID": "0000000000000001", "every": "1h"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid status",
+			path:       "/api/v2/buckets/0000000000000001/downsample",
+			body:       `{"targetBucket": "hourly", "every": "1h", "status": "paused"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "bucket not found",
+			path:       "/api/v2/buckets/0000000000000009/downsample",
+			body:       `{"targetBucket": "hourly", "every": "1h"}`,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *platform.TaskCreate
+			taskBackend := NewMockTaskBackend(t)
+			taskBackend.HTTPErrorHandler = ErrorHandler(0)
+			taskBackend.BucketService = bucketService
+			taskBackend.TaskService = &mock.TaskService{
+				CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+					created = &tc
+					return &platform.Task{ID: 100, OrganizationID: tc.OrganizationID, Flux: tc.Flux}, nil
+				},
+			}
+			h := NewTaskHandler(taskBackend)
+
+			r := httptest.NewRequest("POST", tt.path, strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), new(platform.Authorization)))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				if created != nil {
+					t.Fatal("task was created for an invalid request")
+				}
+				return
+			}
+			if created == nil {
+				t.Fatal("task was not created")
+			}
+			if created.OrganizationID != 10 {
+				t.Errorf("task created in org %s, want %s", created.OrganizationID, platform.ID(10))
+			}
+			if !strings.Contains(created.Flux, tt.wantFlux) {
+				t.Errorf("task flux = %q, want it to contain %q", created.Flux, tt.wantFlux)
+			}
+		})
+	}
+}
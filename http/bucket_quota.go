@@ -0,0 +1,63 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+type bucketQuotaResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.BucketQuotaUsage
+}
+
+func newBucketQuotaResponse(u *influxdb.BucketQuotaUsage) *bucketQuotaResponse {
+	return &bucketQuotaResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/quota", u.BucketID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", u.BucketID),
+		},
+		BucketQuotaUsage: *u,
+	}
+}
+
+// handleGetBucketQuota is the HTTP handler for the GET /api/v2/buckets/:id/quota route.
+// It returns the current usage of the bucket against its quota.
+func (h *BucketHandler) handleGetBucketQuota(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.BucketQuotaService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "bucket quotas are not available",
+		}, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	u, err := h.BucketQuotaService.FindBucketQuotaUsage(ctx, b)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("bucket quota usage retrieved", zap.String("bucketID", b.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketQuotaResponse(u)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func TestBucketHandler_handleGetBucketQuota(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: id, OrgID: 2, Name: "b", Quota: platform.BucketQuota{MaxPointsPerSecond: 1000, MaxBytesPerDay: 1 << 30}}, nil
+	}
+
+	qs := mock.NewBucketQuotaService()
+	qs.FindBucketQuotaUsageFn = func(ctx context.Context, b *platform.Bucket) (*platform.BucketQuotaUsage, error) {
+		return &platform.BucketQuotaUsage{
+			BucketID:        b.ID,
+			Quota:           b.Quota,
+			PointsPerSecond: 250,
+			Series:          42,
+			BytesToday:      4096,
+		}, nil
+	}
+
+	tests := []struct {
+		name       string
+		quotas     platform.BucketQuotaService
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "usage against quota",
+			quotas:     qs,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000001/quota",
+    "bucket": "/api/v2/buckets/0000000000000001"
+  },
+  "bucketID": "0000000000000001",
+  "quota": {"maxPointsPerSecond": 1000, "maxBytesPerDay": 1073741824},
+  "pointsPerSecond": 250,
+  "series": 42,
+  "bytesToday": 4096
+}`,
+		},
+		{
+			name:       "quotas unavailable",
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler:   ErrorHandler(0),
+				Logger:             zap.NewNop(),
+				BucketService:      bs,
+				BucketQuotaService: tt.quotas,
+			})
+
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/buckets/0000000000000001/quota", nil)
+			r = r.WithContext(context.WithValue(
+				pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+			w := httptest.NewRecorder()
+			h.handleGetBucketQuota(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+
+			if tt.wantBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+					t.Errorf("error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("***%s***", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// taskBucketRename is the script of a task before and after the bucket it
+// references is renamed.
+type taskBucketRename struct {
+	TaskID  influxdb.ID
+	OldFlux string
+	NewFlux string
+}
+
+// renameBucket renames a bucket along with the references to it in the tasks
+// of its organization. The scripts of the tasks are rewritten once the bucket
+// is renamed, and if a task can't be updated the bucket and the tasks already
+// updated are changed back, so that no task is left reading from or writing to
+// a bucket that doesn't exist.
+func (h *BucketHandler) renameBucket(ctx context.Context, b *influxdb.Bucket, upd influxdb.BucketUpdate) (*influxdb.Bucket, error) {
+	oldName, newName := b.Name, *upd.Name
+
+	renames, err := h.findTaskBucketRenames(ctx, b, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	nb, err := h.BucketService.UpdateBucket(ctx, b.ID, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, rn := range renames {
+		flux := rn.NewFlux
+		if _, err := h.TaskService.UpdateTask(ctx, rn.TaskID, influxdb.TaskUpdate{Flux: &flux}); err != nil {
+			h.rollbackBucketRename(ctx, b.ID, oldName, renames[:i])
+			return nil, &influxdb.Error{
+				Code: influxdb.ErrorCode(err),
+				Msg:  fmt.Sprintf("bucket was not renamed, as task %s that references it could not be updated", rn.TaskID),
+				Err:  err,
+			}
+		}
+	}
+
+	if len(renames) > 0 {
+		h.Logger.Info("bucket renamed in tasks",
+			zap.String("bucketID", b.ID.String()),
+			zap.String("oldName", oldName),
+			zap.String("newName", newName),
+			zap.Int("tasks", len(renames)))
+	}
+	return nb, nil
+}
+
+// rollbackBucketRename changes the name of a bucket and the scripts of the
+// tasks that were updated back. Failures are logged, as the rename has
+// already failed.
+func (h *BucketHandler) rollbackBucketRename(ctx context.Context, id influxdb.ID, name string, renames []taskBucketRename) {
+	for _, rn := range renames {
+		flux := rn.OldFlux
+		if _, err := h.TaskService.UpdateTask(ctx, rn.TaskID, influxdb.TaskUpdate{Flux: &flux}); err != nil {
+			h.Logger.Error("failed to restore task after failed bucket rename", zap.String("taskID", rn.TaskID.String()), zap.Error(err))
+		}
+	}
+	if _, err := h.BucketService.UpdateBucket(ctx, id, influxdb.BucketUpdate{Name: &name}); err != nil {
+		h.Logger.Error("failed to restore bucket name after failed rename", zap.String("bucketID", id.String()), zap.Error(err))
+	}
+}
+
+// findTaskBucketRenames returns the scripts of the tasks of the organization of
+// a bucket that reference it by name, rewritten to reference it by a new name.
+func (h *BucketHandler) findTaskBucketRenames(ctx context.Context, b *influxdb.Bucket, newName string) ([]taskBucketRename, error) {
+	var org string
+	if h.OrganizationService != nil {
+		o, err := h.OrganizationService.FindOrganizationByID(ctx, b.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		org = o.Name
+	}
+
+	var renames []taskBucketRename
+	filter := influxdb.TaskFilter{OrganizationID: &b.OrgID, Limit: influxdb.TaskMaxPageSize}
+	for {
+		tasks, _, err := h.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			if flux, ok := renameFluxBucket(t.Flux, b, org, newName); ok {
+				renames = append(renames, taskBucketRename{TaskID: t.ID, OldFlux: t.Flux, NewFlux: flux})
+			}
+		}
+		if len(tasks) < filter.Limit {
+			return renames, nil
+		}
+		filter.After = &tasks[len(tasks)-1].ID
+	}
+}
+
+// renameFluxBucket returns the script with the bucket arguments of its calls
+// of from() and to() that name the bucket replaced by a new name, and whether
+// there were any. Calls that name the bucket of another organization or
+// instance are left alone. Only the literals are replaced, so the rest of the
+// script is kept as it was written.
+func renameFluxBucket(script string, b *influxdb.Bucket, org, newName string) (string, bool) {
+	pkg := parser.ParseSource(script)
+	if ast.Check(pkg) > 0 {
+		return script, false
+	}
+
+	var refs []*ast.StringLiteral
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		call, ok := n.(*ast.CallExpression)
+		if !ok || !isBucketCall(call) || !isOrgCall(call, b.OrgID, org) {
+			return
+		}
+		if lit, ok := callArgument(call, "bucket").(*ast.StringLiteral); ok && lit.Value == b.Name {
+			refs = append(refs, lit)
+		}
+	}), pkg)
+	if len(refs) == 0 {
+		return script, false
+	}
+
+	lines := strings.SplitAfter(script, "\n")
+	offset := func(p ast.Position) int {
+		n := 0
+		for _, l := range lines[:p.Line-1] {
+			n += len(l)
+		}
+		return n + p.Column - 1
+	}
+
+	lit := ast.Format(&ast.StringLiteral{Value: newName})
+	// The literals are found in the order of the script, and are replaced
+	// from its end so that the offsets of the others stay the same.
+	for i := len(refs) - 1; i >= 0; i-- {
+		loc := refs[i].Loc
+		if loc == nil {
+			return script, false
+		}
+		script = script[:offset(loc.Start)] + lit + script[offset(loc.End):]
+	}
+	return script, true
+}
+
+// isBucketCall returns true if the call is a call of from() or to(), or of
+// one of a package such as influxdb.to().
+func isBucketCall(call *ast.CallExpression) bool {
+	switch callee := call.Callee.(type) {
+	case *ast.Identifier:
+		return callee.Name == "from" || callee.Name == "to"
+	case *ast.MemberExpression:
+		name := callee.Property.Key()
+		return name == "from" || name == "to"
+	}
+	return false
+}
+
+// isOrgCall returns true if the call reads from or writes to the organization
+// of the task, rather than another organization or another instance.
+func isOrgCall(call *ast.CallExpression, orgID influxdb.ID, org string) bool {
+	if callArgument(call, "host") != nil {
+		return false
+	}
+	if e := callArgument(call, "orgID"); e != nil {
+		lit, ok := e.(*ast.StringLiteral)
+		return ok && lit.Value == orgID.String()
+	}
+	if e := callArgument(call, "org"); e != nil {
+		lit, ok := e.(*ast.StringLiteral)
+		return ok && org != "" && lit.Value == org
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func TestRenameFluxBucket(t *testing.T) {
+	b := &platform.Bucket{ID: 1, OrgID: 2, Name: "old"}
+
+	tests := []struct {
+		name    string
+		script  string
+		newName string
+		want    string
+		wantOK  bool
+	}{
+		{
+			name: "reads and writes",
+			script: `option task = {name: "t", every: 1h}
+
+from(bucket: "old")
+	|> range(start: -1h)
+	|> to(bucket:"old", org: "o")`,
+			newName: "new",
+			want: `option task = {name: "t", every: 1h}
+
+from(bucket: "new")
+	|> range(start: -1h)
+	|> to(bucket:"new", org: "o")`,
+			wantOK: true,
+		},
+		{
+			name:    "package function",
+			script:  `import "influxdata/influxdb/v1"` + "\n" + `from(bucket: "other") |> v1.fieldsAsCols() |> influxdb.to(bucket: "old", orgID: "0000000000000002")`,
+			newName: "new",
+			want:    `import "influxdata/influxdb/v1"` + "\n" + `from(bucket: "other") |> v1.fieldsAsCols() |> influxdb.to(bucket: "new", orgID: "0000000000000002")`,
+			wantOK:  true,
+		},
+		{
+			name:    "other organization",
+			script:  `from(bucket: "old") |> range(start: -1h) |> to(bucket: "old", org: "other")`,
+			newName: "new",
+			want:    `from(bucket: "new") |> range(start: -1h) |> to(bucket: "old", org: "other")`,
+			wantOK:  true,
+		},
+		{
+			name:    "other instance",
+			script:  `from(bucket: "old", host: "https://example.com", token: "t") |> range(start: -1h)`,
+			newName: "new",
+			want:    `from(bucket: "old", host: "https://example.com", token: "t") |> range(start: -1h)`,
+		},
+		{
+			name:    "by ID",
+			script:  `from(bucketID: "0000000000000001") |> range(start: -1h)`,
+			newName: "new",
+			want:    `from(bucketID: "0000000000000001") |> range(start: -1h)`,
+		},
+		{
+			name:    "other bucket",
+			script:  `from(bucket: "older") |> range(start: -1h)`,
+			newName: "new",
+			want:    `from(bucket: "older") |> range(start: -1h)`,
+		},
+		{
+			name:    "invalid script",
+			script:  `from(bucket: "old") |> range(start: `,
+			newName: "new",
+			want:    `from(bucket: "old") |> range(start: `,
+		},
+		{
+			name:    "escaped name",
+			script:  `x = "ü" from(bucket: "old")`,
+			newName: `a"b`,
+			want:    `x = "ü" from(bucket: "a\"b")`,
+			wantOK:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := renameFluxBucket(tt.script, b, "o", tt.newName)
+			if ok != tt.wantOK {
+				t.Errorf("got ok %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("got script:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBucketHandler_handlePatchBucket_rename(t *testing.T) {
+	tests := []struct {
+		name          string
+		updateTaskErr error
+		wantStatus    int
+		wantName      string
+		wantFlux      map[platform.ID]string
+	}{
+		{
+			name:       "tasks are updated",
+			wantStatus: http.StatusOK,
+			wantName:   "new",
+			wantFlux: map[platform.ID]string{
+				10: `from(bucket: "new") |> range(start: -1h) |> to(bucket: "b")`,
+				11: `from(bucket: "a") |> range(start: -1h) |> to(bucket: "new")`,
+				12: `from(bucket: "a") |> range(start: -1h)`,
+			},
+		},
+		{
+			name:          "failed task update",
+			updateTaskErr: &platform.Error{Code: platform.EInvalid, Msg: "invalid task"},
+			wantStatus:    http.StatusBadRequest,
+			wantName:      "old",
+			wantFlux: map[platform.ID]string{
+				10: `from(bucket: "old") |> range(start: -1h) |> to(bucket: "b")`,
+				11: `from(bucket: "a") |> range(start: -1h) |> to(bucket: "old")`,
+				12: `from(bucket: "a") |> range(start: -1h)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bucket := &platform.Bucket{ID: 1, OrgID: 2, Name: "old"}
+			bs := mock.NewBucketService()
+			bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+				b := *bucket
+				return &b, nil
+			}
+			bs.UpdateBucketFn = func(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+				if upd.Name != nil {
+					bucket.Name = *upd.Name
+				}
+				b := *bucket
+				return &b, nil
+			}
+
+			orgs := mock.NewOrganizationService()
+			orgs.FindOrganizationByIDF = func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+				return &platform.Organization{ID: id, Name: "o"}, nil
+			}
+
+			flux := map[platform.ID]string{
+				10: `from(bucket: "old") |> range(start: -1h) |> to(bucket: "b")`,
+				11: `from(bucket: "a") |> range(start: -1h) |> to(bucket: "old")`,
+				12: `from(bucket: "a") |> range(start: -1h)`,
+			}
+			ts := &mock.TaskService{
+				FindTasksFn: func(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
+					if *filter.OrganizationID != 2 {
+						return nil, 0, fmt.Errorf("unexpected organization %s", filter.OrganizationID)
+					}
+					if filter.After != nil {
+						return nil, 0, nil
+					}
+					var tasks []*platform.Task
+					for _, id := range []platform.ID{10, 11, 12} {
+						tasks = append(tasks, &platform.Task{ID: id, OrganizationID: 2, Flux: flux[id]})
+					}
+					return tasks, len(tasks), nil
+				},
+				UpdateTaskFn: func(ctx context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
+					// The second task fails to update, after the first one has.
+					if tt.updateTaskErr != nil && id == 11 && strings.Contains(*upd.Flux, `"new"`) {
+						return nil, tt.updateTaskErr
+					}
+					flux[id] = *upd.Flux
+					return &platform.Task{ID: id, Flux: *upd.Flux}, nil
+				},
+			}
+
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler:    ErrorHandler(0),
+				Logger:              zap.NewNop(),
+				BucketService:       bs,
+				LabelService:        mock.NewLabelService(),
+				OrganizationService: orgs,
+				TaskService:         ts,
+			})
+
+			r := httptest.NewRequest("PATCH", "http://any.url/api/v2/buckets/0000000000000001", strings.NewReader(`{"name": "new"}`))
+			r = r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+			w := httptest.NewRecorder()
+			h.handlePatchBucket(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if bucket.Name != tt.wantName {
+				t.Errorf("got bucket name %q, want %q", bucket.Name, tt.wantName)
+			}
+			for id, want := range tt.wantFlux {
+				if flux[id] != want {
+					t.Errorf("got flux of task %s:\n%s\nwant:\n%s", id, flux[id], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	// rollupVerificationDefaultRange is the time range that is sampled when no start is given.
+	rollupVerificationDefaultRange = 7 * 24 * time.Hour
+	// rollupVerificationDefaultTolerance allows for the rounding of floats
+	// that were summed up in a different order.
+	rollupVerificationDefaultTolerance = 1e-9
+)
+
+type postRollupVerificationRequest struct {
+	SourceBucketID influxdb.ID `json:"sourceBucketID"`
+	Measurement    string      `json:"measurement"`
+	Field          string      `json:"field"`
+	Aggregate      string      `json:"aggregate"`
+	Every          string      `json:"every"`
+	TimeSrc        string      `json:"timeSrc"`
+	Start          *time.Time  `json:"start"`
+	Stop           *time.Time  `json:"stop"`
+	Samples        int         `json:"samples"`
+	Tolerance      *float64    `json:"tolerance"`
+}
+
+type rollupVerificationResponse struct {
+	Links          map[string]string `json:"links"`
+	BucketID       influxdb.ID       `json:"bucketID"`
+	SourceBucketID influxdb.ID       `json:"sourceBucketID"`
+	Start          time.Time         `json:"start"`
+	Stop           time.Time         `json:"stop"`
+	Every          string            `json:"every"`
+	*influxdb.RollupVerification
+}
+
+func newRollupVerificationResponse(req influxdb.RollupVerificationRequest, v *influxdb.RollupVerification) *rollupVerificationResponse {
+	return &rollupVerificationResponse{
+		Links: map[string]string{
+			"self":         fmt.Sprintf("/api/v2/buckets/%s/rollup/verify", req.BucketID),
+			"bucket":       fmt.Sprintf("/api/v2/buckets/%s", req.BucketID),
+			"sourceBucket": fmt.Sprintf("/api/v2/buckets/%s", req.SourceBucketID),
+		},
+		BucketID:           req.BucketID,
+		SourceBucketID:     req.SourceBucketID,
+		Start:              req.Start,
+		Stop:               req.Stop,
+		Every:              req.Every.String(),
+		RollupVerification: v,
+	}
+}
+
+// handlePostBucketRollupVerification is the HTTP handler for the POST /api/v2/buckets/:id/rollup/verify route.
+// It compares the rollups in the bucket with the aggregates of the raw data of its source bucket
+// in sampled windows and responds with the discrepancies.
+func (h *BucketHandler) handlePostBucketRollupVerification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostBucketRollupVerificationRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.RollupVerificationService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "rollup verification is not available",
+		}, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	src, err := h.BucketService.FindBucketByID(ctx, req.SourceBucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if src.OrgID != b.OrgID {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "the source bucket must belong to the organization of the rollup",
+		}, w)
+		return
+	}
+	req.OrgID = b.OrgID
+
+	v, err := h.RollupVerificationService.VerifyRollup(ctx, *req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("rollup verified", zap.String("bucketID", b.ID.String()), zap.Int("discrepancies", len(v.Discrepancies)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRollupVerificationResponse(*req, v)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostBucketRollupVerificationRequest(ctx context.Context, r *http.Request) (*influxdb.RollupVerificationRequest, error) {
+	gr, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var body postRollupVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid rollup verification request",
+			Err:  err,
+		}
+	}
+
+	req := &influxdb.RollupVerificationRequest{
+		BucketID:       gr.BucketID,
+		SourceBucketID: body.SourceBucketID,
+		Measurement:    body.Measurement,
+		Field:          body.Field,
+		Aggregate:      body.Aggregate,
+		TimeSrc:        body.TimeSrc,
+		Samples:        body.Samples,
+		Tolerance:      rollupVerificationDefaultTolerance,
+	}
+	if req.Aggregate == "" {
+		req.Aggregate = influxdb.RollupAggregateMean
+	}
+	if req.TimeSrc == "" {
+		req.TimeSrc = "_stop"
+	}
+	if req.Samples == 0 {
+		req.Samples = influxdb.DefaultRollupSamples
+	}
+	if body.Tolerance != nil {
+		req.Tolerance = *body.Tolerance
+	}
+
+	if body.Every == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "every is required",
+		}
+	}
+	if req.Every, err = time.ParseDuration(body.Every); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "every must be a duration such as 1h",
+			Err:  err,
+		}
+	}
+
+	req.Stop = time.Now().UTC()
+	if body.Stop != nil {
+		req.Stop = *body.Stop
+	}
+	req.Start = req.Stop.Add(-rollupVerificationDefaultRange)
+	if body.Start != nil {
+		req.Start = *body.Start
+	}
+
+	return req, nil
+}
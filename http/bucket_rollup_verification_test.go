@@ -0,0 +1,128 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func TestBucketHandler_handlePostBucketRollupVerification(t *testing.T) {
+	day := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		orgID := platform.ID(2)
+		if id == 9 {
+			orgID = 3
+		}
+		return &platform.Bucket{ID: id, OrgID: orgID, Name: "b"}, nil
+	}
+
+	var gotReq *platform.RollupVerificationRequest
+	vs := mock.NewRollupVerificationService()
+	vs.VerifyRollupFn = func(ctx context.Context, req platform.RollupVerificationRequest) (*platform.RollupVerification, error) {
+		gotReq = &req
+		expected := 5.0
+		return &platform.RollupVerification{
+			Windows: 1,
+			Series:  2,
+			Discrepancies: []*platform.RollupDiscrepancy{
+				{Start: day, Stop: day.Add(time.Hour), Series: "host=b", Reason: platform.RollupMissing, Expected: &expected},
+			},
+		}, nil
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "verifies the rollup",
+			body:       `{"sourceBucketID": "0000000000000005", "measurement": "cpu", "field": "usage", "every": "1h", "start": "2019-07-01T00:00:00Z", "stop": "2019-07-02T00:00:00Z"}`,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000001/rollup/verify",
+    "bucket": "/api/v2/buckets/0000000000000001",
+    "sourceBucket": "/api/v2/buckets/0000000000000005"
+  },
+  "bucketID": "0000000000000001",
+  "sourceBucketID": "0000000000000005",
+  "start": "2019-07-01T00:00:00Z",
+  "stop": "2019-07-02T00:00:00Z",
+  "every": "1h0m0s",
+  "windows": 1,
+  "skippedWindows": 0,
+  "series": 2,
+  "discrepancies": [
+    {"start": "2019-07-01T00:00:00Z", "stop": "2019-07-01T01:00:00Z", "series": "host=b", "reason": "missing", "expected": 5}
+  ]
+}`,
+		},
+		{
+			name:       "every is required",
+			body:       `{"sourceBucketID": "0000000000000005", "measurement": "cpu", "field": "usage"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "source of another organization",
+			body:       `{"sourceBucketID": "0000000000000009", "measurement": "cpu", "field": "usage", "every": "1h"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotReq = nil
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler:          ErrorHandler(0),
+				Logger:                    zap.NewNop(),
+				BucketService:             bs,
+				RollupVerificationService: vs,
+			})
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/buckets/0000000000000001/rollup/verify", bytes.NewBufferString(tt.body))
+			r = r.WithContext(context.WithValue(
+				pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+			w := httptest.NewRecorder()
+			h.handlePostBucketRollupVerification(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if gotReq != nil {
+					t.Fatal("the rollup should not be verified for an invalid request")
+				}
+				return
+			}
+
+			if gotReq.OrgID != 2 || gotReq.Aggregate != platform.RollupAggregateMean || gotReq.TimeSrc != "_stop" || gotReq.Samples != platform.DefaultRollupSamples {
+				t.Errorf("unexpected request %+v", gotReq)
+			}
+
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+				t.Errorf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("***%s***", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+// Server-enforced limits of the bucket sample endpoint.
+// They keep a preview cheap regardless of what the client asks for.
+const (
+	bucketSampleDefaultLimit    = 10
+	bucketSampleMaxLimit        = 100
+	bucketSampleDefaultLookback = time.Hour
+	bucketSampleMaxLookback     = 24 * time.Hour
+	bucketSampleMaxMeasurements = 50
+	bucketSampleMemoryBytes     = 64 * 1024 * 1024
+	bucketSampleMaxDuration     = 10 * time.Second
+)
+
+// Columns of a sampled row that are not reported as tags.
+var bucketSampleNonTagColumns = map[string]bool{
+	"_start":       true,
+	"_stop":        true,
+	"_time":        true,
+	"_value":       true,
+	"_field":       true,
+	"_measurement": true,
+	"result":       true,
+	"table":        true,
+}
+
+type bucketSamplePoint struct {
+	Time  time.Time         `json:"time"`
+	Field string            `json:"field"`
+	Value interface{}       `json:"value"`
+	Tags  map[string]string `json:"tags"`
+}
+
+type bucketSampleMeasurement struct {
+	Name   string              `json:"name"`
+	Points []bucketSamplePoint `json:"points"`
+}
+
+type bucketSampleResponse struct {
+	BucketID     influxdb.ID               `json:"bucketID"`
+	Lookback     string                    `json:"lookback"`
+	Limit        int                       `json:"limit"`
+	Measurements []bucketSampleMeasurement `json:"measurements"`
+	// Truncated is set when the bucket had more measurements than are sampled.
+	Truncated bool `json:"truncated"`
+}
+
+// handleGetBucketSample is the HTTP handler for the GET /api/v2/buckets/:id/sample route.
+// It returns the most recent points of each measurement in the bucket.
+func (h *BucketHandler) handleGetBucketSample(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketSampleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// Finding the bucket checks that the caller may read it.
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.QueryService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "bucket sampling is not available",
+		}, w)
+		return
+	}
+
+	auth, err := pcontext.GetAuthorization(ctx, b.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res, err := h.sampleBucket(ctx, auth, b, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, handleFluxError(err), w)
+		return
+	}
+
+	h.Logger.Debug("bucket sampled", zap.String("bucketID", b.ID.String()), zap.Int("measurements", len(res.Measurements)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *BucketHandler) sampleBucket(ctx context.Context, auth *influxdb.Authorization, b *influxdb.Bucket, req *getBucketSampleRequest) (*bucketSampleResponse, error) {
+	script := fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: -%dms)
+	|> group(columns: ["_measurement"])
+	|> sort(columns: ["_time"])
+	|> tail(n: %d)`, b.ID.String(), req.Lookback/time.Millisecond, req.Limit)
+
+	qreq := &query.Request{
+		Authorization:    auth,
+		OrganizationID:   b.OrgID,
+		Compiler:         lang.FluxCompiler{Query: script},
+		MemoryBytesQuota: bucketSampleMemoryBytes,
+		MaxDuration:      bucketSampleMaxDuration,
+	}
+
+	itr, err := h.QueryService.Query(ctx, qreq)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+
+	res := &bucketSampleResponse{
+		BucketID:     b.ID,
+		Lookback:     req.Lookback.String(),
+		Limit:        req.Limit,
+		Measurements: []bucketSampleMeasurement{},
+	}
+	for itr.More() {
+		err := itr.Next().Tables().Do(func(tbl flux.Table) error {
+			if len(res.Measurements) == bucketSampleMaxMeasurements {
+				res.Truncated = true
+				// Tables must be consumed even when they are not reported.
+				return tbl.Do(func(flux.ColReader) error { return nil })
+			}
+
+			m := bucketSampleMeasurement{Points: []bucketSamplePoint{}}
+			if err := tbl.Do(func(cr flux.ColReader) error {
+				for i := 0; i < cr.Len(); i++ {
+					p := bucketSamplePoint{Tags: map[string]string{}}
+					for j, col := range cr.Cols() {
+						switch col.Label {
+						case "_measurement":
+							m.Name = cr.Strings(j).ValueString(i)
+						case "_field":
+							p.Field = cr.Strings(j).ValueString(i)
+						case "_time":
+							p.Time = time.Unix(0, cr.Times(j).Value(i)).UTC()
+						case "_value":
+							p.Value = sampleValue(cr, i, j)
+						default:
+							if !bucketSampleNonTagColumns[col.Label] && col.Type == flux.TString {
+								p.Tags[col.Label] = cr.Strings(j).ValueString(i)
+							}
+						}
+					}
+					m.Points = append(m.Points, p)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			if len(m.Points) > 0 {
+				res.Measurements = append(res.Measurements, m)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := itr.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// sampleValue returns the value at row i of column j, or nil if it is null.
+func sampleValue(cr flux.ColReader, i, j int) interface{} {
+	switch cr.Cols()[j].Type {
+	case flux.TFloat:
+		if vs := cr.Floats(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TInt:
+		if vs := cr.Ints(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TUInt:
+		if vs := cr.UInts(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TString:
+		if vs := cr.Strings(j); vs.IsValid(i) {
+			return vs.ValueString(i)
+		}
+	case flux.TBool:
+		if vs := cr.Bools(j); vs.IsValid(i) {
+			return vs.Value(i)
+		}
+	case flux.TTime:
+		if vs := cr.Times(j); vs.IsValid(i) {
+			return time.Unix(0, vs.Value(i)).UTC()
+		}
+	}
+	return nil
+}
+
+type getBucketSampleRequest struct {
+	BucketID influxdb.ID
+	Limit    int
+	Lookback time.Duration
+}
+
+func decodeGetBucketSampleRequest(ctx context.Context, r *http.Request) (*getBucketSampleRequest, error) {
+	gr, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &getBucketSampleRequest{
+		BucketID: gr.BucketID,
+		Limit:    bucketSampleDefaultLimit,
+		Lookback: bucketSampleDefaultLookback,
+	}
+
+	qp := r.URL.Query()
+	if l := qp.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit < 1 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "limit must be a positive integer",
+			}
+		}
+		if limit > bucketSampleMaxLimit {
+			limit = bucketSampleMaxLimit
+		}
+		req.Limit = limit
+	}
+
+	if lb := qp.Get("lookback"); lb != "" {
+		lookback, err := time.ParseDuration(lb)
+		if err != nil || lookback <= 0 {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "lookback must be a positive duration",
+			}
+		}
+		if lookback > bucketSampleMaxLookback {
+			lookback = bucketSampleMaxLookback
+		}
+		req.Lookback = lookback
+	}
+
+	return req, nil
+}
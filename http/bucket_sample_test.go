@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func TestBucketHandler_handleGetBucketSample(t *testing.T) {
+	t0 := execute.Time(time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC).UnixNano())
+
+	var gotReq *query.Request
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			gotReq = req
+			return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					KeyCols: []string{"_measurement"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{t0, t0, t0, 1.5, "usage", "cpu", "a"},
+					},
+				}},
+			}}), nil
+		},
+	}
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: id, OrgID: 2, Name: "b"}, nil
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantFlux   []string
+		wantBody   string
+	}{
+		{
+			name:       "defaults",
+			wantStatus: http.StatusOK,
+			wantFlux:   []string{`from(bucketID: "0000000000000001")`, `range(start: -3600000ms)`, `tail(n: 10)`},
+			wantBody: `
+{
+  "bucketID": "0000000000000001",
+  "lookback": "1h0m0s",
+  "limit": 10,
+  "truncated": false,
+  "measurements": [
+    {
+      "name": "cpu",
+      "points": [
+        {"time": "2019-07-01T12:00:00Z", "field": "usage", "value": 1.5, "tags": {"host": "a"}}
+      ]
+    }
+  ]
+}`,
+		},
+		{
+			name:       "limits are capped",
+			query:      "?limit=100000&lookback=720h",
+			wantStatus: http.StatusOK,
+			wantFlux:   []string{`range(start: -86400000ms)`, `tail(n: 100)`},
+		},
+		{
+			name:       "fractional lookback",
+			query:      "?lookback=1.5s",
+			wantStatus: http.StatusOK,
+			wantFlux:   []string{`range(start: -1500ms)`},
+		},
+		{
+			name:       "invalid limit",
+			query:      "?limit=-1",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid lookback",
+			query:      "?lookback=yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotReq = nil
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler: ErrorHandler(0),
+				Logger:           zap.NewNop(),
+				BucketService:    bs,
+				QueryService:     qs,
+			})
+
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/buckets/0000000000000001/sample"+tt.query, nil)
+			r = r.WithContext(context.WithValue(
+				pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+			w := httptest.NewRecorder()
+			h.handleGetBucketSample(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if gotReq != nil {
+					t.Fatal("no query should run for an invalid request")
+				}
+				return
+			}
+
+			if gotReq.OrganizationID != 2 || gotReq.MemoryBytesQuota <= 0 || gotReq.MaxDuration <= 0 {
+				t.Errorf("expected a bounded query in the bucket organization, got %+v", gotReq)
+			}
+			script := gotReq.Compiler.(lang.FluxCompiler).Query
+			if pkg := parser.ParseSource(script); ast.Check(pkg) > 0 {
+				t.Errorf("invalid query %s: %v", script, ast.GetError(pkg))
+			}
+			for _, want := range tt.wantFlux {
+				if !strings.Contains(script, want) {
+					t.Errorf("expected query to contain %q, got:\n%s", want, script)
+				}
+			}
+
+			if tt.wantBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+					t.Errorf("error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("***%s***", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/schemadoc"
+	"go.uber.org/zap"
+)
+
+const (
+	bucketsIDSchemaDocPath          = "/api/v2/buckets/:id/schema/doc"
+	bucketsIDSchemaDescriptionsPath = "/api/v2/buckets/:id/schema/descriptions"
+)
+
+type schemaDocResponse struct {
+	Links map[string]string `json:"links"`
+	*influxdb.SchemaDoc
+}
+
+func newSchemaDocResponse(d *influxdb.SchemaDoc) *schemaDocResponse {
+	return &schemaDocResponse{
+		Links: map[string]string{
+			"self":         fmt.Sprintf("/api/v2/buckets/%s/schema/doc", d.BucketID),
+			"descriptions": fmt.Sprintf("/api/v2/buckets/%s/schema/descriptions", d.BucketID),
+			"bucket":       fmt.Sprintf("/api/v2/buckets/%s", d.BucketID),
+		},
+		SchemaDoc: d,
+	}
+}
+
+type schemaDescriptionsResponse struct {
+	Links map[string]string `json:"links"`
+	*influxdb.SchemaDescriptions
+}
+
+func newSchemaDescriptionsResponse(d *influxdb.SchemaDescriptions) *schemaDescriptionsResponse {
+	if d.Measurements == nil {
+		d.Measurements = map[string]influxdb.MeasurementDescriptions{}
+	}
+	return &schemaDescriptionsResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/buckets/%s/schema/descriptions", d.BucketID),
+			"doc":    fmt.Sprintf("/api/v2/buckets/%s/schema/doc", d.BucketID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", d.BucketID),
+		},
+		SchemaDescriptions: d,
+	}
+}
+
+// handleGetBucketSchemaDoc is the HTTP handler for the GET /api/v2/buckets/:id/schema/doc route.
+// The doc is returned as JSON, or as markdown if the format query parameter is markdown.
+func (h *BucketHandler) handleGetBucketSchemaDoc(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "markdown" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "format must be json or markdown",
+		}, w)
+		return
+	}
+
+	if h.SchemaDocService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "schema documentation is not available",
+		}, w)
+		return
+	}
+
+	doc, err := h.SchemaDocService.FindSchemaDoc(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("bucket schema doc generated", zap.String("bucketID", doc.BucketID.String()), zap.Int("measurements", len(doc.Measurements)))
+
+	if format == "markdown" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if err := schemadoc.WriteMarkdown(w, doc); err != nil {
+			logEncodingError(h.Logger, r, err)
+		}
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSchemaDocResponse(doc)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetBucketSchemaDescriptions is the HTTP handler for the GET /api/v2/buckets/:id/schema/descriptions route.
+// A bucket that has not been described has empty descriptions.
+func (h *BucketHandler) handleGetBucketSchemaDescriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.SchemaDescriptionService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "schema descriptions are not available",
+		}, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	d, err := h.SchemaDescriptionService.FindSchemaDescriptions(ctx, b.ID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		d, err = &influxdb.SchemaDescriptions{OrgID: b.OrgID, BucketID: b.ID}, nil
+	}
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("bucket schema descriptions retrieved", zap.String("bucketID", b.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSchemaDescriptionsResponse(d)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePutBucketSchemaDescriptions is the HTTP handler for the PUT /api/v2/buckets/:id/schema/descriptions route.
+// It replaces all the descriptions of the schema of the bucket.
+func (h *BucketHandler) handlePutBucketSchemaDescriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	d, err := decodePutBucketSchemaDescriptionsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.SchemaDescriptionService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "schema descriptions are not available",
+		}, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, d.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	d.OrgID = b.OrgID
+
+	if err := h.SchemaDescriptionService.PutSchemaDescriptions(ctx, d); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("bucket schema descriptions updated", zap.String("bucketID", b.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSchemaDescriptionsResponse(d)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePutBucketSchemaDescriptionsRequest(ctx context.Context, r *http.Request) (*influxdb.SchemaDescriptions, error) {
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &influxdb.SchemaDescriptions{}
+	if err := json.NewDecoder(r.Body).Decode(d); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+	d.BucketID = req.BucketID
+
+	if err := d.Valid(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,220 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func newSchemaDocTestRequest(method, path string, body []byte) *http.Request {
+	r := httptest.NewRequest(method, "http://any.url"+path, bytes.NewReader(body))
+	return r.WithContext(context.WithValue(
+		r.Context(),
+		httprouter.ParamsKey,
+		httprouter.Params{{Key: "id", Value: "0000000000000002"}}))
+}
+
+func TestBucketHandler_handleGetBucketSchemaDoc(t *testing.T) {
+	ds := mock.NewSchemaDocService()
+	ds.FindSchemaDocFn = func(ctx context.Context, bucketID platform.ID) (*platform.SchemaDoc, error) {
+		return &platform.SchemaDoc{
+			OrgID:      1,
+			BucketID:   bucketID,
+			BucketName: "telegraf",
+			Measurements: []*platform.MeasurementDoc{
+				{
+					Name:        "cpu",
+					Description: "CPU usage",
+					Tags:        []platform.SchemaDocColumn{{Name: "host"}},
+					Fields:      []platform.SchemaDocColumn{{Name: "usage_user", Unit: "percent"}},
+				},
+			},
+			GeneratedAt: time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC),
+		}, nil
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "json",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000002/schema/doc",
+    "descriptions": "/api/v2/buckets/0000000000000002/schema/descriptions",
+    "bucket": "/api/v2/buckets/0000000000000002"
+  },
+  "orgID": "0000000000000001",
+  "bucketID": "0000000000000002",
+  "bucketName": "telegraf",
+  "measurements": [
+    {
+      "name": "cpu",
+      "description": "CPU usage",
+      "tags": [{"name": "host"}],
+      "fields": [{"name": "usage_user", "unit": "percent"}]
+    }
+  ],
+  "generatedAt": "2019-07-01T12:00:00Z"
+}`,
+		},
+		{
+			name:            "markdown",
+			query:           "?format=markdown",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/markdown; charset=utf-8",
+		},
+		{
+			name:       "unknown format",
+			query:      "?format=html",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler: ErrorHandler(0),
+				Logger:           zap.NewNop(),
+				SchemaDocService: ds,
+			})
+
+			w := httptest.NewRecorder()
+			h.handleGetBucketSchemaDoc(w, newSchemaDocTestRequest("GET", "/api/v2/buckets/0000000000000002/schema/doc"+tt.query, nil))
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if ct := res.Header.Get("Content-Type"); ct != tt.wantContentType {
+				t.Errorf("got content type %q, want %q", ct, tt.wantContentType)
+			}
+
+			if tt.wantBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+					t.Errorf("error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("***%s***", diff)
+				}
+			} else if !strings.Contains(string(body), "| usage_user |  | percent |  |") {
+				t.Errorf("expected the fields to be documented, got:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestBucketHandler_handleGetBucketSchemaDoc_Unavailable(t *testing.T) {
+	h := NewBucketHandler(&BucketBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zap.NewNop(),
+		BucketService:    mock.NewBucketService(),
+	})
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/buckets/0000000000000002/schema/doc", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if res := w.Result(); res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, want %d", res.StatusCode, http.StatusServiceUnavailable)
+	}
+}
+
+func TestBucketHandler_handleSchemaDescriptions(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: id, OrgID: 1, Name: "telegraf"}, nil
+	}
+
+	var stored *platform.SchemaDescriptions
+	ss := mock.NewSchemaDescriptionService()
+	ss.FindSchemaDescriptionsFn = func(ctx context.Context, bucketID platform.ID) (*platform.SchemaDescriptions, error) {
+		if stored == nil {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrSchemaDescriptionsNotFound}
+		}
+		return stored, nil
+	}
+	ss.PutSchemaDescriptionsFn = func(ctx context.Context, d *platform.SchemaDescriptions) error {
+		stored = d
+		return nil
+	}
+
+	h := NewBucketHandler(&BucketBackend{
+		HTTPErrorHandler:         ErrorHandler(0),
+		Logger:                   zap.NewNop(),
+		BucketService:            bs,
+		SchemaDescriptionService: ss,
+	})
+	path := "/api/v2/buckets/0000000000000002/schema/descriptions"
+
+	// A bucket that has not been described has empty descriptions.
+	w := httptest.NewRecorder()
+	h.handleGetBucketSchemaDescriptions(w, newSchemaDocTestRequest("GET", path, nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000002/schema/descriptions",
+    "doc": "/api/v2/buckets/0000000000000002/schema/doc",
+    "bucket": "/api/v2/buckets/0000000000000002"
+  },
+  "orgID": "0000000000000001",
+  "bucketID": "0000000000000002",
+  "measurements": {},
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	// The descriptions are stored for the bucket of the path, in its organization.
+	w = httptest.NewRecorder()
+	h.handlePutBucketSchemaDescriptions(w, newSchemaDocTestRequest("PUT", path, []byte(`{
+  "bucketID": "0000000000000009",
+  "description": "Metrics of the hosts.",
+  "measurements": {"cpu": {"description": "CPU usage", "fields": {"usage_user": "Time spent in user space"}}}
+}`)))
+	if w.Code != http.StatusOK {
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if stored == nil || stored.OrgID != 1 || stored.BucketID != 2 || stored.Measurements["cpu"].Fields["usage_user"] != "Time spent in user space" {
+		t.Errorf("unexpected stored descriptions %+v", stored)
+	}
+
+	// Descriptions that are too long are rejected.
+	stored = nil
+	w = httptest.NewRecorder()
+	h.handlePutBucketSchemaDescriptions(w, newSchemaDocTestRequest("PUT", path, []byte(`{"description": "`+strings.Repeat("a", platform.MaxSchemaDescriptionLength+1)+`"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if stored != nil {
+		t.Error("invalid descriptions should not be stored")
+	}
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	bucketsIDMeasurementsPath = "/api/v2/buckets/:id/measurements"
+	bucketsIDTagKeysPath      = "/api/v2/buckets/:id/tag-keys"
+	bucketsIDTagValuesPath    = "/api/v2/buckets/:id/tag-values"
+)
+
+// seriesSchemaResponse is a page of the measurements, tag keys or tag values
+// of a bucket. The next link is set while the page is full, as there may be
+// more values after it.
+type seriesSchemaResponse struct {
+	Links  map[string]string `json:"links"`
+	Values []string          `json:"values"`
+}
+
+func newSeriesSchemaResponse(u *url.URL, filter influxdb.SeriesSchemaFilter, values []string) *seriesSchemaResponse {
+	res := &seriesSchemaResponse{
+		Links: map[string]string{
+			"self":   u.RequestURI(),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", filter.BucketID),
+		},
+		Values: values,
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = influxdb.SeriesSchemaDefaultLimit
+	}
+	if len(values) == limit {
+		qp := u.Query()
+		qp.Set("after", values[len(values)-1])
+		next := *u
+		next.RawQuery = qp.Encode()
+		res.Links["next"] = next.RequestURI()
+	}
+	return res
+}
+
+// handleGetBucketMeasurements is the HTTP handler for the GET /api/v2/buckets/:id/measurements route.
+func (h *BucketHandler) handleGetBucketMeasurements(w http.ResponseWriter, r *http.Request) {
+	h.handleGetBucketSeriesSchema(w, r, func(ctx context.Context, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+		return h.SeriesSchemaService.FindMeasurements(ctx, filter)
+	})
+}
+
+// handleGetBucketTagKeys is the HTTP handler for the GET /api/v2/buckets/:id/tag-keys route.
+func (h *BucketHandler) handleGetBucketTagKeys(w http.ResponseWriter, r *http.Request) {
+	h.handleGetBucketSeriesSchema(w, r, func(ctx context.Context, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+		return h.SeriesSchemaService.FindTagKeys(ctx, filter)
+	})
+}
+
+// handleGetBucketTagValues is the HTTP handler for the GET /api/v2/buckets/:id/tag-values route.
+// The tag key is given by the key query parameter.
+func (h *BucketHandler) handleGetBucketTagValues(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		h.HandleHTTPError(r.Context(), &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "key is required",
+		}, w)
+		return
+	}
+	h.handleGetBucketSeriesSchema(w, r, func(ctx context.Context, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+		return h.SeriesSchemaService.FindTagValues(ctx, key, filter)
+	})
+}
+
+// handleGetBucketSeriesSchema responds with the page of the values found for
+// the series of the bucket selected by the query parameters.
+func (h *BucketHandler) handleGetBucketSeriesSchema(w http.ResponseWriter, r *http.Request, find func(context.Context, influxdb.SeriesSchemaFilter) ([]string, error)) {
+	ctx := r.Context()
+
+	filter, err := decodeGetBucketSeriesSchemaRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.SeriesSchemaService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "browsing the series of a bucket is not available",
+		}, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, filter.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	filter.OrgID = b.OrgID
+
+	values, err := find(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if values == nil {
+		values = []string{}
+	}
+
+	h.Logger.Debug("bucket series schema retrieved", zap.String("bucketID", b.ID.String()), zap.Int("values", len(values)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSeriesSchemaResponse(r.URL, *filter, values)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetBucketSeriesSchemaRequest(ctx context.Context, r *http.Request) (*influxdb.SeriesSchemaFilter, error) {
+	gr, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	qp := r.URL.Query()
+	filter := &influxdb.SeriesSchemaFilter{
+		BucketID:    gr.BucketID,
+		Measurement: qp.Get("measurement"),
+		Prefix:      qp.Get("prefix"),
+		After:       qp.Get("after"),
+	}
+
+	for _, p := range []struct {
+		name string
+		t    *time.Time
+	}{{"start", &filter.Start}, {"stop", &filter.Stop}} {
+		if s := qp.Get(p.name); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("%s must be an RFC3339 time", p.name),
+					Err:  err,
+				}
+			}
+			*p.t = t
+		}
+	}
+
+	if s := qp.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > influxdb.SeriesSchemaMaxLimit {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("limit must be between 1 and %d", influxdb.SeriesSchemaMaxLimit),
+			}
+		}
+		filter.Limit = limit
+	}
+
+	for _, s := range qp["tag"] {
+		p, err := parseSeriesTagPredicate(s)
+		if err != nil {
+			return nil, err
+		}
+		filter.Tags = append(filter.Tags, p)
+	}
+
+	if !filter.Start.IsZero() && !filter.Stop.IsZero() && filter.Stop.Before(filter.Start) {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "stop must not be before start",
+		}
+	}
+	return filter, nil
+}
+
+// seriesTagPredicateOps are the operators of a tag predicate of a query parameter.
+var seriesTagPredicateOps = []string{
+	influxdb.TagPredicateEqual,
+	influxdb.TagPredicateNotEqual,
+	influxdb.TagPredicateRegex,
+	influxdb.TagPredicateNotRegex,
+}
+
+// parseSeriesTagPredicate parses a tag predicate written as the key, the
+// operator and the value, such as host==a or region=~^us-.
+func parseSeriesTagPredicate(s string) (influxdb.SeriesTagPredicate, error) {
+	i, op := -1, ""
+	for _, o := range seriesTagPredicateOps {
+		if j := strings.Index(s, o); j > 0 && (i < 0 || j < i) {
+			i, op = j, o
+		}
+	}
+	if i < 0 {
+		return influxdb.SeriesTagPredicate{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid tag predicate %q: expected a key, an operator and a value, such as host==a", s),
+		}
+	}
+
+	p := influxdb.SeriesTagPredicate{Key: s[:i], Op: op, Value: s[i+len(op):]}
+	if err := p.Valid(); err != nil {
+		return influxdb.SeriesTagPredicate{}, err
+	}
+	return p, nil
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestBucketHandler_handleGetBucketSeriesSchema(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: id, OrgID: 2, Name: "b"}, nil
+	}
+
+	var (
+		gotFilter *platform.SeriesSchemaFilter
+		gotKey    string
+	)
+	ss := mock.NewSeriesSchemaService()
+	ss.FindMeasurementsFn = func(ctx context.Context, filter platform.SeriesSchemaFilter) ([]string, error) {
+		gotFilter = &filter
+		return []string{"cpu", "mem"}, nil
+	}
+	ss.FindTagKeysFn = func(ctx context.Context, filter platform.SeriesSchemaFilter) ([]string, error) {
+		gotFilter = &filter
+		return nil, nil
+	}
+	ss.FindTagValuesFn = func(ctx context.Context, key string, filter platform.SeriesSchemaFilter) ([]string, error) {
+		gotFilter, gotKey = &filter, key
+		return []string{"a", "b"}, nil
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantFilter platform.SeriesSchemaFilter
+		wantKey    string
+		wantBody   string
+	}{
+		{
+			name:       "measurements",
+			path:       "/api/v2/buckets/0000000000000001/measurements?prefix=c",
+			wantStatus: http.StatusOK,
+			wantFilter: platform.SeriesSchemaFilter{OrgID: 2, BucketID: 1, Prefix: "c"},
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000001/measurements?prefix=c",
+    "bucket": "/api/v2/buckets/0000000000000001"
+  },
+  "values": ["cpu", "mem"]
+}`,
+		},
+		{
+			name:       "empty tag keys",
+			path:       "/api/v2/buckets/0000000000000001/tag-keys?measurement=cpu",
+			wantStatus: http.StatusOK,
+			wantFilter: platform.SeriesSchemaFilter{OrgID: 2, BucketID: 1, Measurement: "cpu"},
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000001/tag-keys?measurement=cpu",
+    "bucket": "/api/v2/buckets/0000000000000001"
+  },
+  "values": []
+}`,
+		},
+		{
+			name:       "full page of tag values",
+			path:       "/api/v2/buckets/0000000000000001/tag-values?key=host&limit=2&tag=region%3D~%5Eus&tag=env!%3Dtest",
+			wantStatus: http.StatusOK,
+			wantFilter: platform.SeriesSchemaFilter{
+				OrgID:    2,
+				BucketID: 1,
+				Limit:    2,
+				Tags: []platform.SeriesTagPredicate{
+					{Key: "region", Op: platform.TagPredicateRegex, Value: "^us"},
+					{Key: "env", Op: platform.TagPredicateNotEqual, Value: "test"},
+				},
+			},
+			wantKey: "host",
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000001/tag-values?key=host&limit=2&tag=region%3D~%5Eus&tag=env!%3Dtest",
+    "bucket": "/api/v2/buckets/0000000000000001",
+    "next": "/api/v2/buckets/0000000000000001/tag-values?after=b&key=host&limit=2&tag=region%3D~%5Eus&tag=env%21%3Dtest"
+  },
+  "values": ["a", "b"]
+}`,
+		},
+		{
+			name:       "tag values without key",
+			path:       "/api/v2/buckets/0000000000000001/tag-values",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			path:       "/api/v2/buckets/0000000000000001/measurements?limit=5000",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid tag predicate",
+			path:       "/api/v2/buckets/0000000000000001/tag-keys?tag=host",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "stop before start",
+			path:       "/api/v2/buckets/0000000000000001/measurements?start=2019-07-03T00:00:00Z&stop=2019-07-01T00:00:00Z",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFilter, gotKey = nil, ""
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler:    ErrorHandler(0),
+				Logger:              zap.NewNop(),
+				BucketService:       bs,
+				SeriesSchemaService: ss,
+			})
+
+			r := httptest.NewRequest("GET", tt.path, nil)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if gotFilter != nil {
+					t.Fatal("the series should not be read for an invalid request")
+				}
+				return
+			}
+
+			if gotFilter == nil || !reflect.DeepEqual(*gotFilter, tt.wantFilter) {
+				t.Errorf("got filter %+v, want %+v", gotFilter, tt.wantFilter)
+			}
+			if gotKey != tt.wantKey {
+				t.Errorf("got tag key %q, want %q", gotKey, tt.wantKey)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+				t.Errorf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("***%s***", diff)
+			}
+		})
+	}
+}
+
+func TestParseSeriesTagPredicate(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    platform.SeriesTagPredicate
+		wantErr bool
+	}{
+		{s: "host==a", want: platform.SeriesTagPredicate{Key: "host", Op: "==", Value: "a"}},
+		{s: "host!=a==b", want: platform.SeriesTagPredicate{Key: "host", Op: "!=", Value: "a==b"}},
+		{s: "region=~^us-", want: platform.SeriesTagPredicate{Key: "region", Op: "=~", Value: "^us-"}},
+		{s: "region!~", want: platform.SeriesTagPredicate{Key: "region", Op: "!~", Value: ""}},
+		{s: "host", wantErr: true},
+		{s: "==a", wantErr: true},
+		{s: "host=~(", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSeriesTagPredicate(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSeriesTagPredicate(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseSeriesTagPredicate(%q) = %+v, want %+v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -14,6 +14,8 @@ import (
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/writehook"
 )
 
 // BucketBackend is all services and associated parameters required to construct
@@ -28,6 +30,16 @@ type BucketBackend struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	QueryService               query.QueryService
+	CardinalityService         influxdb.CardinalityService
+	BucketQuotaService         influxdb.BucketQuotaService
+	RollupVerificationService  influxdb.RollupVerificationService
+	MeasurementSchemaService   influxdb.MeasurementSchemaService
+	SeriesSchemaService        influxdb.SeriesSchemaService
+	SchemaDocService           influxdb.SchemaDocService
+	SchemaDescriptionService   influxdb.SchemaDescriptionService
+	// TaskService updates the tasks that reference a bucket when it is renamed.
+	TaskService influxdb.TaskService
 }
 
 // NewBucketBackend returns a new instance of BucketBackend.
@@ -42,6 +54,15 @@ func NewBucketBackend(b *APIBackend) *BucketBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		QueryService:               b.QueryService,
+		CardinalityService:         b.CardinalityService,
+		BucketQuotaService:         b.BucketQuotaService,
+		RollupVerificationService:  b.RollupVerificationService,
+		MeasurementSchemaService:   b.MeasurementSchemaService,
+		SeriesSchemaService:        b.SeriesSchemaService,
+		SchemaDocService:           b.SchemaDocService,
+		SchemaDescriptionService:   b.SchemaDescriptionService,
+		TaskService:                b.TaskService,
 	}
 }
 
@@ -57,18 +78,36 @@ type BucketHandler struct {
 	LabelService               influxdb.LabelService
 	UserService                influxdb.UserService
 	OrganizationService        influxdb.OrganizationService
+	QueryService               query.QueryService
+	CardinalityService         influxdb.CardinalityService
+	BucketQuotaService         influxdb.BucketQuotaService
+	RollupVerificationService  influxdb.RollupVerificationService
+	MeasurementSchemaService   influxdb.MeasurementSchemaService
+	SeriesSchemaService        influxdb.SeriesSchemaService
+	SchemaDocService           influxdb.SchemaDocService
+	SchemaDescriptionService   influxdb.SchemaDescriptionService
+	// TaskService updates the tasks that reference a bucket when it is renamed.
+	TaskService influxdb.TaskService
 }
 
 const (
-	bucketsPath            = "/api/v2/buckets"
-	bucketsIDPath          = "/api/v2/buckets/:id"
-	bucketsIDLogPath       = "/api/v2/buckets/:id/logs"
-	bucketsIDMembersPath   = "/api/v2/buckets/:id/members"
-	bucketsIDMembersIDPath = "/api/v2/buckets/:id/members/:userID"
-	bucketsIDOwnersPath    = "/api/v2/buckets/:id/owners"
-	bucketsIDOwnersIDPath  = "/api/v2/buckets/:id/owners/:userID"
-	bucketsIDLabelsPath    = "/api/v2/buckets/:id/labels"
-	bucketsIDLabelsIDPath  = "/api/v2/buckets/:id/labels/:lid"
+	bucketsPath              = "/api/v2/buckets"
+	bucketsIDPath            = "/api/v2/buckets/:id"
+	bucketsIDLogPath         = "/api/v2/buckets/:id/logs"
+	bucketsIDMembersPath     = "/api/v2/buckets/:id/members"
+	bucketsIDMembersIDPath   = "/api/v2/buckets/:id/members/:userID"
+	bucketsIDOwnersPath      = "/api/v2/buckets/:id/owners"
+	bucketsIDOwnersIDPath    = "/api/v2/buckets/:id/owners/:userID"
+	bucketsIDLabelsPath      = "/api/v2/buckets/:id/labels"
+	bucketsIDLabelsIDPath    = "/api/v2/buckets/:id/labels/:lid"
+	bucketsIDSamplePath      = "/api/v2/buckets/:id/sample"
+	bucketsIDCardinalityPath = "/api/v2/buckets/:id/cardinality"
+	bucketsIDQuotaPath       = "/api/v2/buckets/:id/quota"
+	bucketsIDRollupPath      = "/api/v2/buckets/:id/rollup/verify"
+	bucketsIDClonePath       = "/api/v2/buckets/:id/clone"
+
+	bucketsIDSchemaMeasurementsPath   = "/api/v2/buckets/:id/schema/measurements"
+	bucketsIDSchemaMeasurementsIDPath = "/api/v2/buckets/:id/schema/measurements/:measurementID"
 )
 
 // NewBucketHandler returns a new instance of BucketHandler.
@@ -84,12 +123,36 @@ func NewBucketHandler(b *BucketBackend) *BucketHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		OrganizationService:        b.OrganizationService,
+		QueryService:               b.QueryService,
+		CardinalityService:         b.CardinalityService,
+		BucketQuotaService:         b.BucketQuotaService,
+		RollupVerificationService:  b.RollupVerificationService,
+		MeasurementSchemaService:   b.MeasurementSchemaService,
+		SeriesSchemaService:        b.SeriesSchemaService,
+		SchemaDocService:           b.SchemaDocService,
+		SchemaDescriptionService:   b.SchemaDescriptionService,
+		TaskService:                b.TaskService,
 	}
 
 	h.HandlerFunc("POST", bucketsPath, h.handlePostBucket)
 	h.HandlerFunc("GET", bucketsPath, h.handleGetBuckets)
 	h.HandlerFunc("GET", bucketsIDPath, h.handleGetBucket)
 	h.HandlerFunc("GET", bucketsIDLogPath, h.handleGetBucketLog)
+	h.HandlerFunc("GET", bucketsIDSamplePath, h.handleGetBucketSample)
+	h.HandlerFunc("GET", bucketsIDCardinalityPath, h.handleGetBucketCardinality)
+	h.HandlerFunc("GET", bucketsIDQuotaPath, h.handleGetBucketQuota)
+	h.HandlerFunc("POST", bucketsIDRollupPath, h.handlePostBucketRollupVerification)
+	h.HandlerFunc("POST", bucketsIDClonePath, h.handlePostBucketClone)
+	h.HandlerFunc("GET", bucketsIDMeasurementsPath, h.handleGetBucketMeasurements)
+	h.HandlerFunc("GET", bucketsIDTagKeysPath, h.handleGetBucketTagKeys)
+	h.HandlerFunc("GET", bucketsIDTagValuesPath, h.handleGetBucketTagValues)
+	h.HandlerFunc("GET", bucketsIDSchemaMeasurementsPath, h.handleGetMeasurementSchemas)
+	h.HandlerFunc("POST", bucketsIDSchemaMeasurementsPath, h.handlePostMeasurementSchema)
+	h.HandlerFunc("GET", bucketsIDSchemaMeasurementsIDPath, h.handleGetMeasurementSchema)
+	h.HandlerFunc("PATCH", bucketsIDSchemaMeasurementsIDPath, h.handlePatchMeasurementSchema)
+	h.HandlerFunc("GET", bucketsIDSchemaDocPath, h.handleGetBucketSchemaDoc)
+	h.HandlerFunc("GET", bucketsIDSchemaDescriptionsPath, h.handleGetBucketSchemaDescriptions)
+	h.HandlerFunc("PUT", bucketsIDSchemaDescriptionsPath, h.handlePutBucketSchemaDescriptions)
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
 
@@ -138,6 +201,17 @@ type bucket struct {
 	Name                string          `json:"name"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+	// ReorderWindowSeconds is how long written points are buffered to be stored in order.
+	ReorderWindowSeconds int64 `json:"reorderWindowSeconds,omitempty"`
+	// Quota limits the writes to the bucket; it is left out when it sets no limit.
+	Quota *influxdb.BucketQuota `json:"quota,omitempty"`
+	// QueryCacheDisabled keeps HTTP caches from serving the responses to queries of the bucket.
+	QueryCacheDisabled bool `json:"queryCacheDisabled,omitempty"`
+	// SchemaType is how the schemas of the measurements of the bucket are defined.
+	SchemaType influxdb.BucketSchemaType `json:"schemaType,omitempty"`
+	// WriteHooks are the names of the write hooks the points written to the bucket are processed by.
+	WriteHooks []string          `json:"writeHooks,omitempty"`
+	Metadata   influxdb.Metadata `json:"metadata,omitempty"`
 	influxdb.CRUDLog
 }
 
@@ -165,6 +239,27 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		}
 	}
 
+	rw, err := reorderWindow(b.ReorderWindowSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	var q influxdb.BucketQuota
+	if b.Quota != nil {
+		if err := b.Quota.Valid(); err != nil {
+			return nil, err
+		}
+		q = *b.Quota
+	}
+
+	if err := b.Metadata.Valid(); err != nil {
+		return nil, err
+	}
+
+	if err := b.SchemaType.Valid(); err != nil {
+		return nil, err
+	}
+
 	return &influxdb.Bucket{
 		ID:                  b.ID,
 		OrgID:               b.OrgID,
@@ -172,10 +267,28 @@ func (b *bucket) toInfluxDB() (*influxdb.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		ReorderWindow:       rw,
+		Quota:               q,
+		QueryCacheDisabled:  b.QueryCacheDisabled,
+		SchemaType:          b.SchemaType,
+		WriteHooks:          b.WriteHooks,
+		Metadata:            b.Metadata,
 		CRUDLog:             b.CRUDLog,
 	}, nil
 }
 
+// reorderWindow returns the reorder window of a bucket request given in seconds.
+func reorderWindow(seconds int64) (time.Duration, error) {
+	d := time.Duration(seconds) * time.Second
+	if d < 0 || d > influxdb.MaxReorderWindow {
+		return 0, &influxdb.Error{
+			Code: influxdb.EUnprocessableEntity,
+			Msg:  fmt.Sprintf("reorder window seconds must be between 0 and %d", int64(influxdb.MaxReorderWindow/time.Second)),
+		}
+	}
+	return d, nil
+}
+
 func newBucket(pb *influxdb.Bucket) *bucket {
 	if pb == nil {
 		return nil
@@ -191,21 +304,44 @@ func newBucket(pb *influxdb.Bucket) *bucket {
 	}
 
 	return &bucket{
-		ID:                  pb.ID,
-		OrgID:               pb.OrgID,
-		Name:                pb.Name,
-		Description:         pb.Description,
-		RetentionPolicyName: pb.RetentionPolicyName,
-		RetentionRules:      rules,
-		CRUDLog:             pb.CRUDLog,
+		ID:                   pb.ID,
+		OrgID:                pb.OrgID,
+		Name:                 pb.Name,
+		Description:          pb.Description,
+		RetentionPolicyName:  pb.RetentionPolicyName,
+		RetentionRules:       rules,
+		ReorderWindowSeconds: int64(pb.ReorderWindow.Round(time.Second) / time.Second),
+		Quota:                newBucketQuota(pb.Quota),
+		QueryCacheDisabled:   pb.QueryCacheDisabled,
+		SchemaType:           pb.SchemaType,
+		WriteHooks:           pb.WriteHooks,
+		Metadata:             pb.Metadata,
+		CRUDLog:              pb.CRUDLog,
 	}
 }
 
+func newBucketQuota(q influxdb.BucketQuota) *influxdb.BucketQuota {
+	if q.Unlimited() {
+		return nil
+	}
+	return &q
+}
+
 // bucketUpdate is used for serialization/deserialization with retention rules.
 type bucketUpdate struct {
 	Name           *string         `json:"name,omitempty"`
 	Description    *string         `json:"description,omitempty"`
 	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+	// ReorderWindowSeconds of zero disables the reorder window.
+	ReorderWindowSeconds *int64 `json:"reorderWindowSeconds,omitempty"`
+	// Quota replaces the quota of the bucket; a zero quota removes its limits.
+	Quota *influxdb.BucketQuota `json:"quota,omitempty"`
+	// QueryCacheDisabled toggles HTTP caching of the responses to queries of the bucket.
+	QueryCacheDisabled *bool `json:"queryCacheDisabled,omitempty"`
+	// WriteHooks replaces the write hooks of the bucket; an empty list removes them.
+	WriteHooks *[]string `json:"writeHooks,omitempty"`
+	// Metadata sets the keys to the values and removes the keys that are null.
+	Metadata influxdb.MetadataUpdate `json:"metadata,omitempty"`
 }
 
 func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
@@ -225,11 +361,31 @@ func (b *bucketUpdate) toInfluxDB() (*influxdb.BucketUpdate, error) {
 		}
 	}
 
-	return &influxdb.BucketUpdate{
-		Name:            b.Name,
-		Description:     b.Description,
-		RetentionPeriod: &d,
-	}, nil
+	upd := &influxdb.BucketUpdate{
+		Name:               b.Name,
+		Description:        b.Description,
+		RetentionPeriod:    &d,
+		QueryCacheDisabled: b.QueryCacheDisabled,
+		WriteHooks:         b.WriteHooks,
+		Metadata:           b.Metadata,
+	}
+
+	if b.ReorderWindowSeconds != nil {
+		rw, err := reorderWindow(*b.ReorderWindowSeconds)
+		if err != nil {
+			return nil, err
+		}
+		upd.ReorderWindow = &rw
+	}
+
+	if b.Quota != nil {
+		if err := b.Quota.Valid(); err != nil {
+			return nil, err
+		}
+		upd.Quota = b.Quota
+	}
+
+	return upd, nil
 }
 
 func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
@@ -250,6 +406,16 @@ func newBucketUpdate(pb *influxdb.BucketUpdate) *bucketUpdate {
 			EverySeconds: d,
 		})
 	}
+
+	if pb.ReorderWindow != nil {
+		rw := int64((*pb.ReorderWindow).Round(time.Second) / time.Second)
+		up.ReorderWindowSeconds = &rw
+	}
+
+	up.Quota = pb.Quota
+	up.QueryCacheDisabled = pb.QueryCacheDisabled
+	up.WriteHooks = pb.WriteHooks
+	up.Metadata = pb.Metadata
 	return up
 }
 
@@ -286,7 +452,7 @@ type bucketsResponse struct {
 	Buckets []*bucketResponse     `json:"buckets"`
 }
 
-func newBucketsResponse(ctx context.Context, opts influxdb.FindOptions, f influxdb.BucketFilter, bs []*influxdb.Bucket, labelService influxdb.LabelService) *bucketsResponse {
+func newBucketsResponse(ctx context.Context, opts influxdb.FindOptions, f influxdb.PagingFilter, bs []*influxdb.Bucket, labelService influxdb.LabelService) *bucketsResponse {
 	rs := make([]*bucketResponse, 0, len(bs))
 	for _, b := range bs {
 		labels, _ := labelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: b.ID})
@@ -309,6 +475,13 @@ func (h *BucketHandler) handlePostBucket(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if req.DefaultRetention {
+		if err := h.applyBucketDefaults(ctx, req.Bucket); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
 	if err := h.BucketService.CreateBucket(ctx, req.Bucket); err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
@@ -321,15 +494,30 @@ func (h *BucketHandler) handlePostBucket(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// applyBucketDefaults sets the retention period of a bucket to the default of its organization.
+func (h *BucketHandler) applyBucketDefaults(ctx context.Context, b *influxdb.Bucket) error {
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, b.OrgID)
+	if err != nil {
+		return err
+	}
+	if o.BucketDefaults != nil {
+		b.RetentionPeriod = o.BucketDefaults.RetentionPeriod
+	}
+	return nil
+}
+
 type postBucketRequest struct {
 	Bucket *influxdb.Bucket
+	// DefaultRetention is true if the request has no retention rules,
+	// so the bucket is created with the default retention of its organization.
+	DefaultRetention bool
 }
 
 func (b postBucketRequest) Validate() error {
 	if !b.Bucket.OrgID.Valid() {
 		return fmt.Errorf("bucket requires an organization")
 	}
-	return nil
+	return writehook.Valid(b.Bucket.WriteHooks)
 }
 
 func decodePostBucketRequest(ctx context.Context, r *http.Request) (*postBucketRequest, error) {
@@ -344,7 +532,8 @@ func decodePostBucketRequest(ctx context.Context, r *http.Request) (*postBucketR
 	}
 
 	req := &postBucketRequest{
-		Bucket: pb,
+		Bucket:           pb,
+		DefaultRetention: b.RetentionRules == nil,
 	}
 
 	return req, req.Validate()
@@ -467,14 +656,28 @@ func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	bs, _, err := h.BucketService.FindBuckets(ctx, req.filter, req.opts)
+	bs, _, err := h.BucketService.FindBuckets(ctx, req.filter, req.labels.findOptions(req.opts))
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+
+	if len(req.labels) > 0 {
+		idx, err := req.labels.filter(ctx, h.LabelService, req.opts, len(bs), func(i int) influxdb.ID { return bs[i].ID })
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		labeled := make([]*influxdb.Bucket, 0, len(idx))
+		for _, i := range idx {
+			labeled = append(labeled, bs[i])
+		}
+		bs = labeled
+	}
 	h.Logger.Debug("buckets retrieved", zap.String("buckets", fmt.Sprint(bs)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newBucketsResponse(ctx, req.opts, req.filter, bs, h.LabelService)); err != nil {
+	res := newBucketsResponse(ctx, req.opts, req.labels.pagingFilter(req.filter), bs, h.LabelService)
+	if err := encodeListResponse(ctx, w, req.opts.Fields, res.Links, "buckets", res); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
@@ -483,6 +686,7 @@ func (h *BucketHandler) handleGetBuckets(w http.ResponseWriter, r *http.Request)
 type getBucketsRequest struct {
 	filter influxdb.BucketFilter
 	opts   influxdb.FindOptions
+	labels labelFilter
 }
 
 func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsRequest, error) {
@@ -493,6 +697,9 @@ func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsR
 	if err != nil {
 		return nil, err
 	}
+	if err := opts.ValidSortBy(influxdb.BucketSortKeys...); err != nil {
+		return nil, err
+	}
 
 	req.opts = *opts
 
@@ -520,6 +727,12 @@ func decodeGetBucketsRequest(ctx context.Context, r *http.Request) (*getBucketsR
 		req.filter.ID = id
 	}
 
+	if req.filter.Metadata, err = influxdb.ParseMetadataQueryParams(qp["metadata"]); err != nil {
+		return nil, err
+	}
+
+	req.labels = decodeLabelFilter(r)
+
 	return req, nil
 }
 
@@ -534,7 +747,7 @@ func (h *BucketHandler) handlePatchBucket(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	b, err := h.BucketService.UpdateBucket(ctx, req.BucketID, req.Update)
+	b, err := h.updateBucket(ctx, req)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
@@ -553,6 +766,23 @@ func (h *BucketHandler) handlePatchBucket(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// updateBucket updates a bucket, renaming it in the tasks that reference it
+// if its name changes.
+func (h *BucketHandler) updateBucket(ctx context.Context, req *patchBucketRequest) (*influxdb.Bucket, error) {
+	if req.Update.Name == nil || h.TaskService == nil {
+		return h.BucketService.UpdateBucket(ctx, req.BucketID, req.Update)
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		return nil, err
+	}
+	if b.Name == *req.Update.Name {
+		return h.BucketService.UpdateBucket(ctx, req.BucketID, req.Update)
+	}
+	return h.renameBucket(ctx, b, req.Update)
+}
+
 type patchBucketRequest struct {
 	Update   influxdb.BucketUpdate
 	BucketID influxdb.ID
@@ -589,6 +819,12 @@ func decodePatchBucketRequest(ctx context.Context, r *http.Request) (*patchBucke
 		return nil, err
 	}
 
+	if upd.WriteHooks != nil {
+		if err := writehook.Valid(*upd.WriteHooks); err != nil {
+			return nil, err
+		}
+	}
+
 	return &patchBucketRequest{
 		Update:   *upd,
 		BucketID: i,
@@ -694,6 +930,9 @@ func (s *BucketService) FindBuckets(ctx context.Context, filter influxdb.BucketF
 	if filter.Name != nil {
 		query.Add("name", *filter.Name)
 	}
+	for _, m := range filter.Metadata.QueryParams() {
+		query.Add("metadata", m)
+	}
 
 	if len(opt) > 0 {
 		for k, vs := range opt[0].QueryParams() {
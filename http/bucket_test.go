@@ -574,9 +574,11 @@ func TestService_handlePatchBucket(t *testing.T) {
 		BucketService platform.BucketService
 	}
 	type args struct {
-		id        string
-		name      string
-		retention time.Duration
+		id            string
+		name          string
+		retention     time.Duration
+		reorderWindow *time.Duration
+		quota         *platform.BucketQuota
 	}
 	type wants struct {
 		statusCode  int
@@ -823,6 +825,138 @@ func TestService_handlePatchBucket(t *testing.T) {
 				statusCode: http.StatusUnprocessableEntity,
 			},
 		},
+		{
+			name: "update a bucket reorder window",
+			fields: fields{
+				&mock.BucketService{
+					UpdateBucketFn: func(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+						d := &platform.Bucket{
+							ID:    platformtesting.MustIDBase16("020f755c3c082000"),
+							Name:  "hello",
+							OrgID: platformtesting.MustIDBase16("020f755c3c082000"),
+						}
+
+						if upd.ReorderWindow != nil {
+							d.ReorderWindow = *upd.ReorderWindow
+						}
+
+						return d, nil
+					},
+				},
+			},
+			args: args{
+				id:            "020f755c3c082000",
+				reorderWindow: durationPtr(5 * time.Minute),
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/020f755c3c082000",
+    "self": "/api/v2/buckets/020f755c3c082000",
+    "logs": "/api/v2/buckets/020f755c3c082000/logs",
+    "labels": "/api/v2/buckets/020f755c3c082000/labels",
+    "members": "/api/v2/buckets/020f755c3c082000/members",
+    "owners": "/api/v2/buckets/020f755c3c082000/owners",
+    "write": "/api/v2/write?org=020f755c3c082000&bucket=020f755c3c082000"
+  },
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z",
+  "id": "020f755c3c082000",
+  "orgID": "020f755c3c082000",
+  "name": "hello",
+  "retentionRules": [],
+  "reorderWindowSeconds": 300,
+  "labels": []
+}
+`,
+			},
+		},
+		{
+			name: "update a bucket with a too long reorder window is an error",
+			fields: fields{
+				&mock.BucketService{
+					UpdateBucketFn: func(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+						return nil, fmt.Errorf("should not be called")
+					},
+				},
+			},
+			args: args{
+				id:            "020f755c3c082000",
+				reorderWindow: durationPtr(platform.MaxReorderWindow + time.Second),
+			},
+			wants: wants{
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
+		{
+			name: "update a bucket quota",
+			fields: fields{
+				&mock.BucketService{
+					UpdateBucketFn: func(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+						d := &platform.Bucket{
+							ID:    platformtesting.MustIDBase16("020f755c3c082000"),
+							Name:  "hello",
+							OrgID: platformtesting.MustIDBase16("020f755c3c082000"),
+						}
+
+						if upd.Quota != nil {
+							d.Quota = *upd.Quota
+						}
+
+						return d, nil
+					},
+				},
+			},
+			args: args{
+				id:    "020f755c3c082000",
+				quota: &platform.BucketQuota{MaxPointsPerSecond: 1000, MaxSeries: 100000},
+			},
+			wants: wants{
+				statusCode:  http.StatusOK,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "org": "/api/v2/orgs/020f755c3c082000",
+    "self": "/api/v2/buckets/020f755c3c082000",
+    "logs": "/api/v2/buckets/020f755c3c082000/logs",
+    "labels": "/api/v2/buckets/020f755c3c082000/labels",
+    "members": "/api/v2/buckets/020f755c3c082000/members",
+    "owners": "/api/v2/buckets/020f755c3c082000/owners",
+    "write": "/api/v2/write?org=020f755c3c082000&bucket=020f755c3c082000"
+  },
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z",
+  "id": "020f755c3c082000",
+  "orgID": "020f755c3c082000",
+  "name": "hello",
+  "retentionRules": [],
+  "quota": {"maxPointsPerSecond": 1000, "maxSeries": 100000},
+  "labels": []
+}
+`,
+			},
+		},
+		{
+			name: "update a bucket with a negative quota is an error",
+			fields: fields{
+				&mock.BucketService{
+					UpdateBucketFn: func(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+						return nil, fmt.Errorf("should not be called")
+					},
+				},
+			},
+			args: args{
+				id:    "020f755c3c082000",
+				quota: &platform.BucketQuota{MaxBytesPerDay: -1},
+			},
+			wants: wants{
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -841,6 +975,9 @@ func TestService_handlePatchBucket(t *testing.T) {
 				upd.RetentionPeriod = &tt.args.retention
 			}
 
+			upd.ReorderWindow = tt.args.reorderWindow
+			upd.Quota = tt.args.quota
+
 			b, err := json.Marshal(newBucketUpdate(&upd))
 			if err != nil {
 				t.Fatalf("failed to unmarshal bucket update: %v", err)
@@ -1105,3 +1242,7 @@ func initBucketService(f platformtesting.BucketFields, t *testing.T) (platform.B
 func TestBucketService(t *testing.T) {
 	platformtesting.BucketService(initBucketService, t)
 }
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,555 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+)
+
+// CheckBackend is all services and associated parameters required to
+// construct the CheckHandler.
+type CheckBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	CheckService               influxdb.CheckService
+	CheckStatusService         influxdb.CheckStatusService
+	TaskService                influxdb.TaskService
+	AuthorizationService       influxdb.AuthorizationService
+	BucketService              influxdb.BucketService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+// NewCheckBackend returns a new instance of CheckBackend.
+func NewCheckBackend(b *APIBackend) *CheckBackend {
+	return &CheckBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "check")),
+
+		CheckService:               b.CheckService,
+		CheckStatusService:         b.CheckStatusService,
+		TaskService:                b.TaskService,
+		AuthorizationService:       b.AuthorizationService,
+		BucketService:              b.BucketService,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+}
+
+// CheckHandler represents an HTTP API handler for checks. Each check runs as
+// a task generated from its definition, which the handler keeps in sync with
+// the check.
+type CheckHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	CheckService               influxdb.CheckService
+	CheckStatusService         influxdb.CheckStatusService
+	TaskService                influxdb.TaskService
+	BucketService              influxdb.BucketService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+
+	// tasks creates the tasks of checks like tasks created through the API.
+	tasks *TaskHandler
+}
+
+const (
+	checksPath            = "/api/v2/checks"
+	checksIDPath          = "/api/v2/checks/:id"
+	checksIDMembersPath   = "/api/v2/checks/:id/members"
+	checksIDMembersIDPath = "/api/v2/checks/:id/members/:userID"
+	checksIDOwnersPath    = "/api/v2/checks/:id/owners"
+	checksIDOwnersIDPath  = "/api/v2/checks/:id/owners/:userID"
+)
+
+// NewCheckHandler returns a new instance of CheckHandler.
+func NewCheckHandler(b *CheckBackend) *CheckHandler {
+	h := &CheckHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		CheckService:               b.CheckService,
+		CheckStatusService:         b.CheckStatusService,
+		TaskService:                b.TaskService,
+		BucketService:              b.BucketService,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+
+		tasks: &TaskHandler{
+			logger:               b.Logger,
+			TaskService:          b.TaskService,
+			AuthorizationService: b.AuthorizationService,
+			BucketService:        b.BucketService,
+		},
+	}
+
+	h.HandlerFunc("POST", checksPath, h.handlePostCheck)
+	h.HandlerFunc("GET", checksPath, h.handleGetChecks)
+	h.HandlerFunc("GET", checksIDPath, h.handleGetCheck)
+	h.HandlerFunc("PATCH", checksIDPath, h.handlePatchCheck)
+	h.HandlerFunc("PUT", checksIDPath, h.handlePutCheck)
+	h.HandlerFunc("DELETE", checksIDPath, h.handleDeleteCheck)
+
+	memberBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.ChecksResourceType,
+		UserType:                   influxdb.Member,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", checksIDMembersPath, newPostMemberHandler(memberBackend))
+	h.HandlerFunc("GET", checksIDMembersPath, newGetMembersHandler(memberBackend))
+	h.HandlerFunc("DELETE", checksIDMembersIDPath, newDeleteMemberHandler(memberBackend))
+
+	ownerBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.ChecksResourceType,
+		UserType:                   influxdb.Owner,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", checksIDOwnersPath, newPostMemberHandler(ownerBackend))
+	h.HandlerFunc("GET", checksIDOwnersPath, newGetMembersHandler(ownerBackend))
+	h.HandlerFunc("DELETE", checksIDOwnersIDPath, newDeleteMemberHandler(ownerBackend))
+
+	return h
+}
+
+type checkResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Check
+}
+
+func newCheckResponse(c *influxdb.Check) *checkResponse {
+	res := &checkResponse{
+		Links: map[string]string{
+			"self":    fmt.Sprintf("/api/v2/checks/%s", c.ID),
+			"members": fmt.Sprintf("/api/v2/checks/%s/members", c.ID),
+			"owners":  fmt.Sprintf("/api/v2/checks/%s/owners", c.ID),
+			"org":     fmt.Sprintf("/api/v2/orgs/%s", c.OrgID),
+		},
+		Check: *c,
+	}
+	if c.RunsAsTask() {
+		res.Links["task"] = fmt.Sprintf("/api/v2/tasks/%s", c.TaskID)
+	}
+	return res
+}
+
+type checksResponse struct {
+	Links  map[string]string `json:"links"`
+	Checks []*checkResponse  `json:"checks"`
+}
+
+func newChecksResponse(cs []*influxdb.Check) *checksResponse {
+	res := &checksResponse{
+		Links: map[string]string{
+			"self": checksPath,
+		},
+		Checks: make([]*checkResponse, 0, len(cs)),
+	}
+	for _, c := range cs {
+		res.Checks = append(res.Checks, newCheckResponse(c))
+	}
+	return res
+}
+
+func decodeCheck(r *http.Request) (*influxdb.Check, error) {
+	c := &influxdb.Check{}
+	if err := json.NewDecoder(r.Body).Decode(c); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode check request",
+			Err:  err,
+		}
+	}
+	// The task and the last status of a check are not set by users.
+	c.TaskID = 0
+	c.LastStatus = nil
+	return c, nil
+}
+
+// handlePostCheck is the HTTP handler for the POST /api/v2/checks route.
+// It creates the check, the monitoring bucket of its organization if it has
+// none yet, and the task running the check.
+func (h *CheckHandler) handlePostCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("check create request", zap.String("r", fmt.Sprint(r)))
+
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Err:  err,
+			Code: influxdb.EUnauthorized,
+			Msg:  "failed to get authorizer",
+		}, w)
+		return
+	}
+
+	c, err := decodeCheck(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if c.Status == "" {
+		c.Status = influxdb.Active
+	}
+	// The check is validated before anything is created.
+	if err := validateCheck(c); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.createMonitoringBucketIfNotExists(ctx, c.OrgID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.CheckService.CreateCheck(ctx, c); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if c.RunsAsTask() {
+		c, err = h.createCheckTask(ctx, auth, c)
+		if err != nil {
+			if derr := h.CheckService.DeleteCheck(ctx, c.ID); derr != nil {
+				h.Logger.Warn("Failed to delete check without task", zap.String("checkID", c.ID.String()), zap.Error(derr))
+			}
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+	h.Logger.Debug("check created", zap.String("checkID", c.ID.String()), zap.String("taskID", c.TaskID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newCheckResponse(c)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// validateCheck returns an error if the check is invalid, or if it runs as a
+// task and does not generate valid flux.
+func validateCheck(c *influxdb.Check) error {
+	if !c.RunsAsTask() {
+		return c.Valid()
+	}
+	_, err := c.GenerateFlux()
+	return err
+}
+
+// createMonitoringBucketIfNotExists creates the bucket the checks of the
+// organization write their statuses to.
+func (h *CheckHandler) createMonitoringBucketIfNotExists(ctx context.Context, orgID influxdb.ID) error {
+	name := influxdb.MonitoringBucketName
+	_, err := h.BucketService.FindBucket(ctx, influxdb.BucketFilter{OrganizationID: &orgID, Name: &name})
+	if influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+
+	return h.BucketService.CreateBucket(ctx, &influxdb.Bucket{
+		OrgID:           orgID,
+		Name:            influxdb.MonitoringBucketName,
+		Description:     "statuses written by checks",
+		RetentionPeriod: influxdb.MonitoringBucketRetention,
+	})
+}
+
+// createCheckTask creates the task running the check on behalf of auth and
+// sets the task of the check.
+func (h *CheckHandler) createCheckTask(ctx context.Context, auth influxdb.Authorizer, c *influxdb.Check) (*influxdb.Check, error) {
+	flux, err := c.GenerateFlux()
+	if err != nil {
+		return c, err
+	}
+
+	task, err := h.tasks.createTask(ctx, auth, influxdb.TaskCreate{
+		OrganizationID: c.OrgID,
+		Flux:           flux,
+		Status:         string(c.Status),
+		Description:    fmt.Sprintf("runs check %q", c.Name),
+	})
+	if err != nil {
+		return c, err
+	}
+
+	c.TaskID = task.ID
+	updated, err := h.CheckService.ReplaceCheck(ctx, c)
+	if err != nil {
+		if derr := h.TaskService.DeleteTask(ctx, task.ID); derr != nil {
+			h.Logger.Warn("Failed to delete task of check", zap.String("taskID", task.ID.String()), zap.Error(derr))
+		}
+		return c, err
+	}
+	return updated, nil
+}
+
+// syncCheckTask updates the task of the check to run its definition. Checks
+// that don't run as tasks have nothing to update.
+func (h *CheckHandler) syncCheckTask(ctx context.Context, c *influxdb.Check) error {
+	if !c.RunsAsTask() {
+		return nil
+	}
+
+	flux, err := c.GenerateFlux()
+	if err != nil {
+		return err
+	}
+
+	status := string(c.Status)
+	if _, err := h.TaskService.UpdateTask(ctx, c.TaskID, influxdb.TaskUpdate{Flux: &flux, Status: &status}); err != nil {
+		return &influxdb.Error{
+			Err: err,
+			Msg: fmt.Sprintf("failed to update task %s of check", c.TaskID),
+		}
+	}
+	return nil
+}
+
+// withLastStatuses sets the last status of the checks that wrote one.
+// Checks keep no last status if the statuses can not be read.
+func (h *CheckHandler) withLastStatuses(ctx context.Context, cs ...*influxdb.Check) {
+	byOrg := map[influxdb.ID]map[influxdb.ID]*influxdb.CheckStatus{}
+	for _, c := range cs {
+		statuses, ok := byOrg[c.OrgID]
+		if !ok {
+			var err error
+			statuses, err = h.CheckStatusService.FindLastCheckStatuses(ctx, c.OrgID)
+			if err != nil {
+				h.Logger.Info("Failed to find last statuses of checks", zap.String("orgID", c.OrgID.String()), zap.Error(err))
+			}
+			byOrg[c.OrgID] = statuses
+		}
+		c.LastStatus = statuses[c.ID]
+	}
+}
+
+// handleGetChecks is the HTTP handler for the GET /api/v2/checks route.
+func (h *CheckHandler) handleGetChecks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("checks retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	filter, opts, err := h.decodeGetChecksRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	cs, _, err := h.CheckService.FindChecks(ctx, filter, opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.withLastStatuses(ctx, cs...)
+	h.Logger.Debug("checks retrieved", zap.Int("checks", len(cs)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newChecksResponse(cs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *CheckHandler) decodeGetChecksRequest(ctx context.Context, r *http.Request) (influxdb.CheckFilter, influxdb.FindOptions, error) {
+	qp := r.URL.Query()
+	var filter influxdb.CheckFilter
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return filter, influxdb.FindOptions{}, err
+	}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	if typ := qp.Get("type"); typ != "" {
+		t := influxdb.CheckType(typ)
+		filter.Type = &t
+	}
+
+	return filter, *opts, nil
+}
+
+// handleGetCheck is the HTTP handler for the GET /api/v2/checks/:id route.
+func (h *CheckHandler) handleGetCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("check retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	c, err := h.CheckService.FindCheckByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.withLastStatuses(ctx, c)
+	h.Logger.Debug("check retrieved", zap.String("checkID", c.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newCheckResponse(c)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchCheck is the HTTP handler for the PATCH /api/v2/checks/:id route.
+func (h *CheckHandler) handlePatchCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("check update request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.CheckUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode check update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	c, err := h.CheckService.UpdateCheck(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := h.syncCheckTask(ctx, c); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("check updated", zap.String("checkID", c.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newCheckResponse(c)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePutCheck is the HTTP handler for the PUT /api/v2/checks/:id route.
+func (h *CheckHandler) handlePutCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("check replace request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	c, err := decodeCheck(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	current, err := h.CheckService.FindCheckByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	c.ID = id
+	c.OrgID = current.OrgID
+	if c.Status == "" {
+		c.Status = current.Status
+	}
+	if c.RunsAsTask() != current.RunsAsTask() {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("a %s check can not be replaced with a %s check", current.Type, c.Type),
+		}, w)
+		return
+	}
+	if err := validateCheck(c); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	c, err = h.CheckService.ReplaceCheck(ctx, c)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err := h.syncCheckTask(ctx, c); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("check replaced", zap.String("checkID", c.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newCheckResponse(c)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteCheck is the HTTP handler for the DELETE /api/v2/checks/:id route.
+// It deletes the check and its task.
+func (h *CheckHandler) handleDeleteCheck(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("check delete request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	c, err := h.CheckService.FindCheckByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if c.TaskID.Valid() {
+		if err := h.TaskService.DeleteTask(ctx, c.TaskID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	if err := h.CheckService.DeleteCheck(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("check deleted", zap.String("checkID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,270 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newTestCheckHandler(cs platform.CheckService, ss platform.CheckStatusService, ts platform.TaskService, bs platform.BucketService) *CheckHandler {
+	return NewCheckHandler(&CheckBackend{
+		HTTPErrorHandler:           ErrorHandler(0),
+		Logger:                     zap.NewNop(),
+		CheckService:               cs,
+		CheckStatusService:         ss,
+		TaskService:                ts,
+		AuthorizationService:       mock.NewAuthorizationService(),
+		BucketService:              bs,
+		OrganizationService:        mock.NewOrganizationService(),
+		UserResourceMappingService: mock.NewUserResourceMappingService(),
+		UserService:                mock.NewUserService(),
+	})
+}
+
+func TestCheckHandler_handlePostCheck(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+		return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+	}
+	var created *platform.Bucket
+	bs.CreateBucketFn = func(ctx context.Context, b *platform.Bucket) error {
+		created = b
+		return nil
+	}
+
+	cs := mock.NewCheckService()
+	cs.CreateCheckFn = func(ctx context.Context, c *platform.Check) error {
+		c.ID = 2
+		return nil
+	}
+	cs.ReplaceCheckFn = func(ctx context.Context, c *platform.Check) (*platform.Check, error) {
+		return c, nil
+	}
+
+	ts := &mock.TaskService{
+		CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+			if tc.OrganizationID != 1 || tc.Status != string(platform.Active) {
+				t.Errorf("got task create %+v", tc)
+			}
+			c := &platform.Check{ID: 2, OrgID: 1, Name: "cpu", Type: platform.ThresholdCheckType, Status: platform.Active,
+				Query: platform.DashboardQuery{Text: `from(bucket: "telegraf") |> range(start: -1m)`}, Every: "1m",
+				Thresholds: []platform.Threshold{{Type: platform.GreaterThreshold, Level: platform.CheckLevelCrit, Value: 90}}}
+			if flux, _ := c.GenerateFlux(); tc.Flux != flux {
+				t.Errorf("got task flux\n%s\nwant\n%s", tc.Flux, flux)
+			}
+			return &platform.Task{ID: 5, OrganizationID: 1}, nil
+		},
+	}
+	h := newTestCheckHandler(cs, mock.NewCheckStatusService(), ts, bs)
+
+	r := httptest.NewRequest("POST", "http://any.url/api/v2/checks", bytes.NewBufferString(`
+{
+  "orgID": "0000000000000001",
+  "name": "cpu",
+  "type": "threshold",
+  "query": {"text": "from(bucket: \"telegraf\") |> range(start: -1m)"},
+  "every": "1m",
+  "thresholds": [{"type": "greater", "level": "CRIT", "value": 90}],
+  "taskID": "0000000000000009"
+}`))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{ID: 3, OrgID: 1}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if created == nil || created.Name != platform.MonitoringBucketName || created.OrgID != 1 {
+		t.Errorf("expected the monitoring bucket to be created, got %+v", created)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/checks/0000000000000002",
+    "task": "/api/v2/tasks/0000000000000005",
+    "members": "/api/v2/checks/0000000000000002/members",
+    "owners": "/api/v2/checks/0000000000000002/owners",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "name": "cpu",
+  "type": "threshold",
+  "status": "active",
+  "query": {"text": "from(bucket: \"telegraf\") |> range(start: -1m)", "editMode": "", "name": "", "builderConfig": {"buckets": null, "tags": null, "functions": null, "aggregateWindow": {"period": ""}}},
+  "every": "1m",
+  "thresholds": [{"type": "greater", "level": "CRIT", "value": 90}],
+  "taskID": "0000000000000005",
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+}
+
+func TestCheckHandler_handlePostCheckInvalid(t *testing.T) {
+	cs := mock.NewCheckService()
+	cs.CreateCheckFn = func(ctx context.Context, c *platform.Check) error {
+		t.Error("expected an invalid check not to be created")
+		return nil
+	}
+	h := newTestCheckHandler(cs, mock.NewCheckStatusService(), &mock.TaskService{}, mock.NewBucketService())
+
+	r := httptest.NewRequest("POST", "http://any.url/api/v2/checks", bytes.NewBufferString(`
+{
+  "orgID": "0000000000000001",
+  "name": "cpu",
+  "type": "threshold",
+  "query": {"text": "a = 1\nfrom(bucket: \"telegraf\")"},
+  "every": "1m",
+  "thresholds": [{"type": "greater", "level": "CRIT", "value": 90}]
+}`))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{ID: 3, OrgID: 1}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestCheckHandler_handlePostSchemaCheck(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: 4, OrgID: 1, Name: platform.MonitoringBucketName}, nil
+	}
+	cs := mock.NewCheckService()
+	cs.CreateCheckFn = func(ctx context.Context, c *platform.Check) error {
+		c.ID = 2
+		return nil
+	}
+	ts := &mock.TaskService{
+		CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+			t.Error("expected a schema check not to run as a task")
+			return nil, nil
+		},
+	}
+	h := newTestCheckHandler(cs, mock.NewCheckStatusService(), ts, bs)
+
+	r := httptest.NewRequest("POST", "http://any.url/api/v2/checks", bytes.NewBufferString(`
+{
+  "orgID": "0000000000000001",
+  "name": "telegraf schema",
+  "type": "schema",
+  "bucketID": "0000000000000003",
+  "every": "5m",
+  "level": "WARN"
+}`))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{ID: 3, OrgID: 1}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/checks/0000000000000002",
+    "members": "/api/v2/checks/0000000000000002/members",
+    "owners": "/api/v2/checks/0000000000000002/owners",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "name": "telegraf schema",
+  "type": "schema",
+  "status": "active",
+  "query": {"text": "", "editMode": "", "name": "", "builderConfig": {"buckets": null, "tags": null, "functions": null, "aggregateWindow": {"period": ""}}},
+  "bucketID": "0000000000000003",
+  "every": "5m",
+  "level": "WARN",
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+}
+
+func TestCheckHandler_handleGetChecks(t *testing.T) {
+	cs := mock.NewCheckService()
+	cs.FindChecksFn = func(ctx context.Context, filter platform.CheckFilter, opt ...platform.FindOptions) ([]*platform.Check, int, error) {
+		return []*platform.Check{
+			{ID: 2, OrgID: 1, Name: "cpu", Type: platform.DeadmanCheckType, TaskID: 5},
+			{ID: 3, OrgID: 1, Name: "memory", Type: platform.DeadmanCheckType, TaskID: 6},
+		}, 2, nil
+	}
+	ss := mock.NewCheckStatusService()
+	ss.FindLastCheckStatusesFn = func(ctx context.Context, orgID platform.ID) (map[platform.ID]*platform.CheckStatus, error) {
+		return map[platform.ID]*platform.CheckStatus{
+			2: {Level: platform.CheckLevelCrit, Message: "cpu is high", Time: time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC)},
+		}, nil
+	}
+	h := newTestCheckHandler(cs, ss, &mock.TaskService{}, mock.NewBucketService())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/checks?orgID=0000000000000001", nil))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	resp := checksResponse{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Checks) != 2 {
+		t.Fatalf("got %d checks, want 2", len(resp.Checks))
+	}
+	if st := resp.Checks[0].LastStatus; st == nil || st.Level != platform.CheckLevelCrit || st.Message != "cpu is high" {
+		t.Errorf("got last status %+v of the first check", st)
+	}
+	if st := resp.Checks[1].LastStatus; st != nil {
+		t.Errorf("expected the second check to have no last status, got %+v", st)
+	}
+}
+
+func TestCheckHandler_handleDeleteCheck(t *testing.T) {
+	cs := mock.NewCheckService()
+	cs.FindCheckByIDFn = func(ctx context.Context, id platform.ID) (*platform.Check, error) {
+		return &platform.Check{ID: id, OrgID: 1, TaskID: 5}, nil
+	}
+	var deletedCheck platform.ID
+	cs.DeleteCheckFn = func(ctx context.Context, id platform.ID) error {
+		deletedCheck = id
+		return nil
+	}
+	var deletedTask platform.ID
+	ts := &mock.TaskService{
+		DeleteTaskFn: func(ctx context.Context, id platform.ID) error {
+			deletedTask = id
+			return nil
+		},
+	}
+	h := newTestCheckHandler(cs, mock.NewCheckStatusService(), ts, mock.NewBucketService())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("DELETE", "http://any.url/api/v2/checks/0000000000000002", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if deletedCheck != 2 || deletedTask != 5 {
+		t.Errorf("expected check 2 and task 5 to be deleted, got %s and %s", deletedCheck, deletedTask)
+	}
+}
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 
@@ -90,3 +93,56 @@ func (c *traceClient) Do(r *http.Request) (*http.Response, error) {
 	tracing.InjectToHTTPRequest(span, r)
 	return c.Client.Do(r)
 }
+
+// apiClient sends requests with JSON bodies and responses to the API of an InfluxDB.
+type apiClient struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// do sends a request to the path p with the query, and body as its JSON body unless
+// body is nil. The JSON response is decoded into res unless res is nil.
+func (c apiClient) do(ctx context.Context, method, p string, query url.Values, body, res interface{}) error {
+	u, err := NewURL(c.Addr, p)
+	if err != nil {
+		return err
+	}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	var octets []byte
+	if body != nil {
+		octets, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	SetToken(c.Token, req)
+
+	hc := NewClient(u.Scheme, c.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return err
+	}
+
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
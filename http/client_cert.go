@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+// clientCertRule is a ClientCertRule with its compiled regular expression.
+type clientCertRule struct {
+	platform.ClientCertRule
+	re *regexp.Regexp
+}
+
+// ClientCertAuthenticator authenticates requests with the verified client
+// certificates they present. The certificate is mapped to an authorizer by the
+// first rule that matches one of the identities of the certificate.
+type ClientCertAuthenticator struct {
+	UserService           platform.UserService
+	UserPermissionService platform.UserPermissionService
+	AuthorizationService  platform.AuthorizationService
+
+	rules []clientCertRule
+}
+
+// NewClientCertAuthenticator returns a ClientCertAuthenticator with the rules,
+// in the order they are matched.
+func NewClientCertAuthenticator(rules []platform.ClientCertRule) (*ClientCertAuthenticator, error) {
+	a := &ClientCertAuthenticator{}
+	for _, r := range rules {
+		if err := r.Valid(); err != nil {
+			return nil, err
+		}
+		// Identities must match in full, so that a rule for a.example.com
+		// does not match a.example.com.evil.org.
+		re, err := regexp.Compile("^(?:" + r.Match + ")$")
+		if err != nil {
+			return nil, err
+		}
+		a.rules = append(a.rules, clientCertRule{ClientCertRule: r, re: re})
+	}
+	return a, nil
+}
+
+// clientCert returns the verified client certificate of the request, or nil.
+func clientCert(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+// certIdentities returns the identities of the certificate of the kind.
+func certIdentities(cert *x509.Certificate, identity string) []string {
+	switch identity {
+	case platform.ClientCertCommonName:
+		return []string{cert.Subject.CommonName}
+	case platform.ClientCertDNSName:
+		return cert.DNSNames
+	case platform.ClientCertEmailAddress:
+		return cert.EmailAddresses
+	case platform.ClientCertURI:
+		uris := make([]string, 0, len(cert.URIs))
+		for _, u := range cert.URIs {
+			uris = append(uris, u.String())
+		}
+		return uris
+	}
+	return nil
+}
+
+// Authorizer returns the authorizer of the certificate. Certificates mapped to
+// a user are authorized with the permissions of the user until the certificate
+// expires.
+func (a *ClientCertAuthenticator) Authorizer(ctx context.Context, cert *x509.Certificate) (platform.Authorizer, error) {
+	for _, r := range a.rules {
+		for _, id := range certIdentities(cert, r.Identity) {
+			m := r.re.FindStringSubmatchIndex(id)
+			if m == nil {
+				continue
+			}
+
+			if r.AuthorizationID != nil {
+				return a.authorization(ctx, *r.AuthorizationID)
+			}
+			name := string(r.re.ExpandString(nil, r.User, id, m))
+			return a.user(ctx, name, cert)
+		}
+	}
+
+	return nil, &platform.Error{
+		Code: platform.EUnauthorized,
+		Msg:  fmt.Sprintf("client certificate %q matches no rule", cert.Subject.CommonName),
+	}
+}
+
+func (a *ClientCertAuthenticator) authorization(ctx context.Context, id platform.ID) (platform.Authorizer, error) {
+	auth, err := a.AuthorizationService.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.IsActive() {
+		return nil, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "authorization of client certificate is inactive",
+		}
+	}
+	return auth, nil
+}
+
+func (a *ClientCertAuthenticator) user(ctx context.Context, name string, cert *x509.Certificate) (platform.Authorizer, error) {
+	u, err := a.UserService.FindUser(ctx, platform.UserFilter{Name: &name})
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := a.UserPermissionService.FindUserPermissions(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The session is not stored; it only lasts for the request.
+	return &platform.Session{
+		ID:          u.ID,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   cert.NotAfter,
+		UserID:      u.ID,
+		Permissions: ps,
+	}, nil
+}
@@ -0,0 +1,165 @@
+package http_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	platcontext "github.com/influxdata/influxdb/context"
+	platformhttp "github.com/influxdata/influxdb/http"
+	"github.com/influxdata/influxdb/mock"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestAuthenticationHandler_ClientCert(t *testing.T) {
+	notAfter := time.Now().Add(time.Hour)
+	readBuckets := platform.Permission{
+		Action:   platform.ReadAction,
+		Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: platformtesting.IDPtr(1)},
+	}
+
+	us := mock.NewUserService()
+	us.FindUserFn = func(ctx context.Context, f platform.UserFilter) (*platform.User, error) {
+		if *f.Name != "sensor-7" {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "user not found"}
+		}
+		return &platform.User{ID: 10, Name: *f.Name}, nil
+	}
+	ps := mock.NewUserPermissionService()
+	ps.FindUserPermissionsFn = func(ctx context.Context, userID platform.ID) ([]platform.Permission, error) {
+		return []platform.Permission{readBuckets}, nil
+	}
+	as := &mock.AuthorizationService{
+		FindAuthorizationByIDFn: func(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
+			return &platform.Authorization{ID: id, Status: platform.Active, Permissions: []platform.Permission{readBuckets}}, nil
+		},
+		FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+			return &platform.Authorization{ID: 30, Status: platform.Active}, nil
+		},
+	}
+
+	cc, err := platformhttp.NewClientCertAuthenticator([]platform.ClientCertRule{
+		{Identity: platform.ClientCertCommonName, Match: `(.+)\.plant\.example\.com`, User: "$1"},
+		{Identity: platform.ClientCertDNSName, Match: `gateway\.example\.com`, AuthorizationID: platformtesting.IDPtr(20)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc.UserService = us
+	cc.UserPermissionService = ps
+	cc.AuthorizationService = as
+
+	tests := []struct {
+		name     string
+		cert     *x509.Certificate
+		verified bool
+		token    string
+		wantCode int
+		wantAuth func(*testing.T, platform.Authorizer)
+	}{
+		{
+			name:     "common name mapped to user",
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "sensor-7.plant.example.com"}, NotAfter: notAfter},
+			verified: true,
+			wantCode: http.StatusOK,
+			wantAuth: func(t *testing.T, a platform.Authorizer) {
+				s, ok := a.(*platform.Session)
+				if !ok || s.UserID != 10 || !s.ExpiresAt.Equal(notAfter) || !s.Allowed(readBuckets) {
+					t.Errorf("unexpected authorizer %+v", a)
+				}
+			},
+		},
+		{
+			name:     "DNS name mapped to authorization",
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "gateway"}, DNSNames: []string{"gw.local", "gateway.example.com"}, NotAfter: notAfter},
+			verified: true,
+			wantCode: http.StatusOK,
+			wantAuth: func(t *testing.T, a platform.Authorizer) {
+				if auth, ok := a.(*platform.Authorization); !ok || auth.ID != 20 {
+					t.Errorf("unexpected authorizer %+v", a)
+				}
+			},
+		},
+		{
+			name:     "identities match in full",
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "sensor-7.plant.example.com.evil.org"}, NotAfter: notAfter},
+			verified: true,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "unknown user",
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "sensor-8.plant.example.com"}, NotAfter: notAfter},
+			verified: true,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "unverified certificate",
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "sensor-7.plant.example.com"}, NotAfter: notAfter},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "token takes precedence",
+			cert:     &x509.Certificate{Subject: pkix.Name{CommonName: "sensor-7.plant.example.com"}, NotAfter: notAfter},
+			verified: true,
+			token:    "abc123",
+			wantCode: http.StatusOK,
+			wantAuth: func(t *testing.T, a platform.Authorizer) {
+				if auth, ok := a.(*platform.Authorization); !ok || auth.ID != 30 {
+					t.Errorf("unexpected authorizer %+v", a)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got platform.Authorizer
+			h := platformhttp.NewAuthenticationHandler(platformhttp.ErrorHandler(0))
+			h.AuthorizationService = as
+			h.SessionService = mock.NewSessionService()
+			h.ClientCertAuthenticator = cc
+			h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = platcontext.GetAuthorizer(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "https://any.url", nil)
+			r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.cert}}
+			if tt.verified {
+				r.TLS.VerifiedChains = [][]*x509.Certificate{{tt.cert}}
+			}
+			if tt.token != "" {
+				platformhttp.SetToken(tt.token, r)
+			}
+
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status code to be %d got %d", tt.wantCode, w.Code)
+			}
+			if tt.wantAuth != nil {
+				tt.wantAuth(t, got)
+			}
+		})
+	}
+}
+
+func TestNewClientCertAuthenticator_invalidRules(t *testing.T) {
+	for _, r := range []platform.ClientCertRule{
+		{Identity: "serial", Match: "1", User: "a"},
+		{Identity: platform.ClientCertCommonName, Match: "(", User: "a"},
+		{Identity: platform.ClientCertCommonName, Match: "a"},
+		{Identity: platform.ClientCertCommonName, Match: "a", User: "a", AuthorizationID: platformtesting.IDPtr(1)},
+	} {
+		if _, err := platformhttp.NewClientCertAuthenticator([]platform.ClientCertRule{r}); platform.ErrorCode(err) != platform.EInvalid {
+			t.Errorf("expected rule %+v to be invalid, got %v", r, err)
+		}
+	}
+}
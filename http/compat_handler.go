@@ -0,0 +1,405 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/influxdata/flux/iocounter"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+)
+
+const (
+	compatWritePath = "/write"
+	compatQueryPath = "/query"
+	compatPingPath  = "/ping"
+)
+
+// isCompatPath reports whether p is the path of an InfluxDB 1.x endpoint.
+func isCompatPath(p string) bool {
+	return p == compatWritePath || p == compatQueryPath || p == compatPingPath
+}
+
+// setCompatToken makes the credentials 1.x clients send, a password in the p
+// parameter or in basic authentication, the token of the request, since the
+// token is what those clients are configured with in place of a password.
+func setCompatToken(r *http.Request) {
+	if _, password, ok := r.BasicAuth(); ok {
+		SetToken(password, r)
+		return
+	}
+	if r.Header.Get("Authorization") != "" {
+		return
+	}
+	if p := r.URL.Query().Get("p"); p != "" {
+		SetToken(p, r)
+	}
+}
+
+// CompatBackend is all services and associated parameters required to construct
+// the CompatHandler.
+type CompatBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DBRPMappingService platform.DBRPMappingService
+	ProxyQueryService  query.ProxyQueryService
+}
+
+// NewCompatBackend returns a new instance of CompatBackend.
+func NewCompatBackend(b *APIBackend) *CompatBackend {
+	return &CompatBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "compat")),
+
+		DBRPMappingService: b.DBRPMappingService,
+		ProxyQueryService:  b.FluxService,
+	}
+}
+
+// CompatHandler serves the /write, /query and /ping endpoints of InfluxDB 1.x,
+// so that 1.x clients work unchanged. The database and retention policy of a
+// request are mapped to a bucket of the organization of its token by the dbrp
+// mappings, and queries are InfluxQL.
+type CompatHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DBRPMappingService platform.DBRPMappingService
+	ProxyQueryService  query.ProxyQueryService
+
+	// WriteHandler writes the line protocol of the /write endpoint to the mapped bucket.
+	WriteHandler http.Handler
+}
+
+// NewCompatHandler returns a new instance of CompatHandler, which writes through writeHandler.
+func NewCompatHandler(b *CompatBackend, writeHandler http.Handler) *CompatHandler {
+	h := &CompatHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		DBRPMappingService: b.DBRPMappingService,
+		ProxyQueryService:  b.ProxyQueryService,
+		WriteHandler:       writeHandler,
+	}
+
+	h.HandlerFunc("POST", compatWritePath, h.handleWrite)
+	h.HandlerFunc("GET", compatQueryPath, h.handleQuery)
+	h.HandlerFunc("POST", compatQueryPath, h.handleQuery)
+	h.HandlerFunc("GET", compatPingPath, h.handlePing)
+	h.HandlerFunc("HEAD", compatPingPath, h.handlePing)
+	return h
+}
+
+// handlePing is the HTTP handler for the GET /ping route, which 1.x clients
+// use to check that the server is up.
+func (h *CompatHandler) handlePing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Influxdb-Build", "OSS")
+	w.Header().Set("X-Influxdb-Version", compatVersion())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// compatDevVersion is the version reported to 1.x clients by builds
+// without a version, like those of influxd.
+const compatDevVersion = "dev"
+
+// compatVersion is the version reported to 1.x clients, which expect one.
+func compatVersion() string {
+	if v := platform.GetBuildInfo().Version; v != "" {
+		return v
+	}
+	return compatDevVersion
+}
+
+// compatPrecisions maps the precisions of 1.x writes to those of writes.
+var compatPrecisions = map[string]string{
+	"":   "ns",
+	"n":  "ns",
+	"ns": "ns",
+	"u":  "us",
+	"µ":  "us",
+	"us": "us",
+	"ms": "ms",
+	"s":  "s",
+}
+
+// handleWrite is the HTTP handler for the POST /write route. It writes to the
+// bucket the database and retention policy of the request are mapped to, as a
+// write to /api/v2/write would.
+func (h *CompatHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "CompatHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	auth, err := compatAuthorization(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	qp := r.URL.Query()
+	precision, ok := compatPrecisions[qp.Get("precision")]
+	if !ok {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handleCompatWrite",
+			Msg:  "invalid precision; valid precision units are n, u, ms and s",
+		}, w)
+		return
+	}
+
+	db := qp.Get("db")
+	if db == "" {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handleCompatWrite",
+			Msg:  "database is required",
+		}, w)
+		return
+	}
+
+	m, err := findCompatDBRPMapping(ctx, h.DBRPMappingService, auth.OrgID, db, qp.Get("rp"))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	wr := r.WithContext(ctx)
+	u := *r.URL
+	u.Path = writePath
+	u.RawQuery = url.Values{
+		"org":       []string{m.OrganizationID.String()},
+		"bucket":    []string{m.BucketID.String()},
+		"precision": []string{precision},
+	}.Encode()
+	wr.URL = &u
+	h.WriteHandler.ServeHTTP(w, wr)
+}
+
+// handleQuery is the HTTP handler for the GET and POST /query routes. It runs
+// the InfluxQL of the q parameter, in the database and retention policy of the
+// db and rp parameters if statements don't name their own.
+func (h *CompatHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "CompatHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	auth, err := compatAuthorization(ctx)
+	if err != nil {
+		h.handleQueryError(ctx, w, err)
+		return
+	}
+
+	// The parameters of POST requests may also be form encoded in the body.
+	if err := r.ParseForm(); err != nil {
+		h.handleQueryError(ctx, w, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to parse query parameters",
+			Err:  err,
+		})
+		return
+	}
+
+	q := r.FormValue("q")
+	if q == "" {
+		h.handleQueryError(ctx, w, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  `missing required parameter "q"`,
+		})
+		return
+	}
+
+	timeFormat, err := compatTimeFormat(r.FormValue("epoch"))
+	if err != nil {
+		h.handleQueryError(ctx, w, err)
+		return
+	}
+
+	compiler := influxql.NewCompiler(&compatDBRPMappingService{
+		DBRPMappingService: h.DBRPMappingService,
+		auth:               auth,
+	})
+	compiler.DB = r.FormValue("db")
+	compiler.RP = r.FormValue("rp")
+	compiler.Query = q
+
+	dialect := &influxql.Dialect{
+		TimeFormat: timeFormat,
+		Encoding:   influxql.JSON,
+	}
+
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  auth,
+			OrganizationID: auth.OrgID,
+			Compiler:       compiler,
+		},
+		Dialect: dialect,
+	}
+
+	dialect.SetHeaders(w)
+	cw := iocounter.Writer{Writer: w}
+	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+		if cw.Count() == 0 {
+			// Only record the error headers IFF nothing has been written to w.
+			h.handleQueryError(ctx, w, handleFluxError(err))
+			return
+		}
+		h.Logger.Info("Error writing response to client",
+			zap.String("handler", "compat"),
+			zap.Error(err),
+		)
+	}
+}
+
+// handleQueryError responds with err in the format of 1.x query responses,
+// which 1.x clients look for the errors of queries in. Errors that aren't
+// platform errors are those of the InfluxQL of the query, such as statements
+// that cannot be transpiled.
+func (h *CompatHandler) handleQueryError(ctx context.Context, w http.ResponseWriter, err error) {
+	code, msg := platform.EInvalid, err.Error()
+	if pe, ok := err.(*platform.Error); ok {
+		code, msg = platform.ErrorCode(pe), platform.ErrorMessage(pe)
+	}
+	httpCode, ok := statusCodePlatformError[code]
+	if !ok {
+		httpCode = http.StatusBadRequest
+	}
+
+	w.Header().Set(PlatformErrorCodeHeader, code)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpCode)
+	if err := json.NewEncoder(w).Encode(influxql.Response{Err: msg}); err != nil {
+		h.Logger.Info("Error writing response to client", zap.String("handler", "compat"), zap.Error(err))
+	}
+}
+
+// compatTimeFormat returns the time format of the epoch parameter of 1.x queries.
+func compatTimeFormat(epoch string) (influxql.TimeFormat, error) {
+	switch epoch {
+	case "":
+		return influxql.RFC3339Nano, nil
+	case "h":
+		return influxql.Hour, nil
+	case "m":
+		return influxql.Minute, nil
+	case "s":
+		return influxql.Second, nil
+	case "ms":
+		return influxql.Millisecond, nil
+	case "u", "µ":
+		return influxql.Microsecond, nil
+	case "n", "ns":
+		return influxql.Nanosecond, nil
+	default:
+		return 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("invalid epoch %q; valid epochs are h, m, s, ms, u and n", epoch),
+		}
+	}
+}
+
+// compatAuthorization returns the authorization of a 1.x request, whose
+// database is mapped in the organization of its token. Sessions have no
+// organization, and 1.x clients don't sign in anyway.
+func compatAuthorization(ctx context.Context) (*platform.Authorization, error) {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, ok := a.(*platform.Authorization)
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "1.x endpoints require a token",
+		}
+	}
+	return auth, nil
+}
+
+// findCompatDBRPMapping returns the mapping of db and rp in the organization,
+// or of the default retention policy of db if rp is empty. The cluster of the
+// mapping does not matter, since 1.x clients know nothing of it.
+func findCompatDBRPMapping(ctx context.Context, svc platform.DBRPMappingService, orgID platform.ID, db, rp string) (*platform.DBRPMapping, error) {
+	filter := platform.DBRPMappingFilter{Database: &db}
+	if rp != "" {
+		filter.RetentionPolicy = &rp
+	} else {
+		def := true
+		filter.Default = &def
+	}
+
+	ms, _, err := svc.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range ms {
+		if m.OrganizationID == orgID {
+			return m, nil
+		}
+	}
+
+	msg := fmt.Sprintf("no dbrp mapping found for database %q and retention policy %q", db, rp)
+	if rp == "" {
+		msg = fmt.Sprintf("no dbrp mapping found for the default retention policy of database %q", db)
+	}
+	return nil, &platform.Error{
+		Code: platform.ENotFound,
+		Msg:  msg,
+	}
+}
+
+// compatDBRPMappingService finds the mappings of the databases and retention
+// policies of the statements of 1.x queries, which are those of the organization
+// of the token. Only the mappings of buckets the token may read are found.
+type compatDBRPMappingService struct {
+	platform.DBRPMappingService
+	auth *platform.Authorization
+}
+
+// Find returns the mapping of the database and retention policy of filter.
+// The transpiler filters by cluster and by whether the mapping is the default,
+// neither of which matters for 1.x queries once the retention policy is named.
+func (s *compatDBRPMappingService) Find(ctx context.Context, filter platform.DBRPMappingFilter) (*platform.DBRPMapping, error) {
+	if filter.Database == nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "database is required",
+		}
+	}
+
+	var rp string
+	if filter.RetentionPolicy != nil {
+		rp = *filter.RetentionPolicy
+	}
+	m, err := findCompatDBRPMapping(ctx, s.DBRPMappingService, s.auth.OrgID, *filter.Database, rp)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := platform.NewPermissionAtID(m.BucketID, platform.ReadAction, platform.BucketsResourceType, m.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.auth.Allowed(*p) {
+		return nil, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  fmt.Sprintf("insufficient permissions to read database %q", m.Database),
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,296 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/influxql"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	platformtesting "github.com/influxdata/influxdb/testing"
+	"go.uber.org/zap"
+)
+
+func newTestCompatDBRPMappingService() *mock.DBRPMappingService {
+	mappings := []*platform.DBRPMapping{
+		{Cluster: "c", Database: "telegraf", RetentionPolicy: "autogen", Default: true, OrganizationID: 1, BucketID: 2},
+		{Cluster: "c", Database: "telegraf", RetentionPolicy: "weekly", OrganizationID: 1, BucketID: 3},
+		{Cluster: "c", Database: "other", RetentionPolicy: "autogen", Default: true, OrganizationID: 4, BucketID: 5},
+	}
+
+	svc := mock.NewDBRPMappingService()
+	svc.FindManyFn = func(ctx context.Context, filter platform.DBRPMappingFilter, opt ...platform.FindOptions) ([]*platform.DBRPMapping, int, error) {
+		var ms []*platform.DBRPMapping
+		for _, m := range mappings {
+			if filter.Database != nil && *filter.Database != m.Database {
+				continue
+			}
+			if filter.RetentionPolicy != nil && *filter.RetentionPolicy != m.RetentionPolicy {
+				continue
+			}
+			if filter.Default != nil && *filter.Default != m.Default {
+				continue
+			}
+			ms = append(ms, m)
+		}
+		return ms, len(ms), nil
+	}
+	return svc
+}
+
+func TestCompatHandler_handleWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+		wantTime int64
+	}{
+		{
+			name:     "default retention policy",
+			query:    "db=telegraf",
+			wantCode: http.StatusNoContent,
+			wantTime: 1,
+		},
+		{
+			name:     "retention policy and precision",
+			query:    "db=telegraf&rp=weekly&precision=s",
+			wantCode: http.StatusNoContent,
+			wantTime: 1000000000,
+		},
+		{
+			name:     "missing database",
+			query:    "rp=weekly",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid precision",
+			query:    "db=telegraf&precision=d",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown retention policy",
+			query:    "db=telegraf&rp=monthly",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "database of another organization",
+			query:    "db=other",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := NewCompatHandler(&CompatBackend{
+				HTTPErrorHandler:   ErrorHandler(0),
+				Logger:             zap.NewNop(),
+				DBRPMappingService: newTestCompatDBRPMappingService(),
+			}, newTestWriteHandler(pw))
+
+			r := httptest.NewRequest("POST", "/write?"+tt.query, strings.NewReader("m f=1 1"))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, OrgID: 1, Permissions: platform.OperPermissions()}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusNoContent {
+				if len(pw.Points) != 0 {
+					t.Errorf("expected no points to be written, got %d", len(pw.Points))
+				}
+				return
+			}
+			if len(pw.Points) != 1 {
+				t.Fatalf("got %d points, want 1", len(pw.Points))
+			}
+			if got := pw.Points[0].Time().UnixNano(); got != tt.wantTime {
+				t.Errorf("got time %d, want %d", got, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestCompatHandler_handleQuery(t *testing.T) {
+	var got *query.ProxyRequest
+	h := NewCompatHandler(&CompatBackend{
+		HTTPErrorHandler:   ErrorHandler(0),
+		Logger:             zap.NewNop(),
+		DBRPMappingService: newTestCompatDBRPMappingService(),
+		ProxyQueryService: &querymock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				got = req
+				if _, err := io.WriteString(w, `{"results":[]}`); err != nil {
+					return flux.Statistics{}, err
+				}
+				return flux.Statistics{}, nil
+			},
+		},
+	}, nil)
+
+	r := httptest.NewRequest("POST", "/query?db=telegraf&epoch=ms", strings.NewReader("q=SELECT+f+FROM+m&rp=weekly"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, OrgID: 1, Permissions: platform.OperPermissions()}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got.Request.OrganizationID != 1 {
+		t.Errorf("got organization %s, want %s", got.Request.OrganizationID, platform.ID(1))
+	}
+	c, ok := got.Request.Compiler.(*influxql.Compiler)
+	if !ok {
+		t.Fatalf("got compiler %T, want *influxql.Compiler", got.Request.Compiler)
+	}
+	if c.DB != "telegraf" || c.RP != "weekly" || c.Query != "SELECT f FROM m" {
+		t.Errorf("got db %q, rp %q and query %q", c.DB, c.RP, c.Query)
+	}
+	if d := got.Dialect.(*influxql.Dialect); d.TimeFormat != influxql.Millisecond {
+		t.Errorf("got time format %v, want %v", d.TimeFormat, influxql.Millisecond)
+	}
+}
+
+func TestCompatHandler_handleQuery_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "missing query",
+			query:    "db=telegraf",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":"missing required parameter \"q\""}`,
+		},
+		{
+			name:     "invalid epoch",
+			query:    "db=telegraf&q=SELECT+f+FROM+m&epoch=d",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":"invalid epoch \"d\"; valid epochs are h, m, s, ms, u and n"}`,
+		},
+		{
+			name:     "query error",
+			query:    "db=telegraf&q=SELECT+f+FROM+m",
+			err:      errors.New("unsupported statement"),
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"error":"unsupported statement"}`,
+		},
+		{
+			name:  "platform error",
+			query: "db=telegraf&q=SELECT+f+FROM+m",
+			err: &platform.Error{
+				Code: platform.ENotFound,
+				Msg:  "no dbrp mapping found",
+			},
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"no dbrp mapping found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCompatHandler(&CompatBackend{
+				HTTPErrorHandler:   ErrorHandler(0),
+				Logger:             zap.NewNop(),
+				DBRPMappingService: newTestCompatDBRPMappingService(),
+				ProxyQueryService: &querymock.ProxyQueryService{
+					QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+						return flux.Statistics{}, tt.err
+					},
+				},
+			}, nil)
+
+			r := httptest.NewRequest("GET", "/query?"+tt.query, nil)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, OrgID: 1, Permissions: platform.OperPermissions()}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if eq, diff, _ := jsonEqual(w.Body.String(), tt.wantBody); !eq {
+				t.Errorf("unexpected body -got/+want\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCompatHandler_handlePing(t *testing.T) {
+	defer func(bi platform.BuildInfo) {
+		platform.SetBuildInfo(bi.Version, bi.Commit, bi.Date)
+	}(platform.GetBuildInfo())
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+	}{
+		{
+			name:    "build version",
+			version: "2.0.0",
+			want:    "2.0.0",
+		},
+		{
+			name: "build without version",
+			want: compatDevVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platform.SetBuildInfo(tt.version, "", "")
+			h := NewCompatHandler(&CompatBackend{
+				HTTPErrorHandler: ErrorHandler(0),
+				Logger:           zap.NewNop(),
+			}, nil)
+
+			r := httptest.NewRequest("GET", "/ping", nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("got status %d, want %d", w.Code, http.StatusNoContent)
+			}
+			if got := w.Header().Get("X-Influxdb-Version"); got != tt.want {
+				t.Errorf("got X-Influxdb-Version %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatDBRPMappingService_Find(t *testing.T) {
+	db, rp := "telegraf", "weekly"
+	s := &compatDBRPMappingService{
+		DBRPMappingService: newTestCompatDBRPMappingService(),
+		auth: &platform.Authorization{Status: platform.Active, OrgID: 1, Permissions: []platform.Permission{{
+			Action:   platform.ReadAction,
+			Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: platformtesting.IDPtr(1), ID: platformtesting.IDPtr(2)},
+		}}},
+	}
+
+	m, err := s.Find(context.Background(), platform.DBRPMappingFilter{Database: &db})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.BucketID != 2 {
+		t.Errorf("got bucket %s, want %s", m.BucketID, platform.ID(2))
+	}
+
+	_, err = s.Find(context.Background(), platform.DBRPMappingFilter{Database: &db, RetentionPolicy: &rp})
+	if platform.ErrorCode(err) != platform.EForbidden {
+		t.Errorf("expected a forbidden error reading a bucket without permission, got %v", err)
+	}
+}
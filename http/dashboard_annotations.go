@@ -0,0 +1,124 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+type dashboardCellAnnotationsResponse struct {
+	Links       map[string]string          `json:"links"`
+	Start       time.Time                  `json:"start"`
+	Stop        time.Time                  `json:"stop"`
+	View        *dashboardCellViewResponse `json:"view,omitempty"`
+	Annotations []*annotationResponse      `json:"annotations"`
+}
+
+// handleGetDashboardCellAnnotations is the HTTP handler for the GET /api/v2/dashboards/:id/cells/:cellID/annotations route.
+// It returns the view of the cell with its queries resolved for a time range, along with the annotations
+// of the organization of the dashboard that overlap the range, so the graph of the cell can overlay them.
+// The range defaults to the hour until now, and the annotations may be selected by stream and tag.
+func (h *DashboardHandler) handleGetDashboardCellAnnotations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("get dashboard cell annotations request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodeGetDashboardCellViewRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	filter, err := decodeAnnotationsQuery(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	stop := time.Now().UTC()
+	if filter.Stop != nil {
+		stop = *filter.Stop
+	}
+	start := stop.Add(-dashboardSnapshotDefaultRange)
+	if filter.Start != nil {
+		start = *filter.Start
+	}
+	filter.Start, filter.Stop = &start, &stop
+
+	dashboard, err := h.DashboardService.FindDashboardByID(ctx, req.dashboardID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	found := false
+	for _, cell := range dashboard.Cells {
+		if cell.ID == req.cellID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrCellNotFound,
+		}, w)
+		return
+	}
+	filter.OrgID = &dashboard.OrganizationID
+
+	var variables []*platform.Variable
+	if h.VariableService != nil {
+		variables, err = h.VariableService.FindVariables(ctx, platform.VariableFilter{OrganizationID: &dashboard.OrganizationID})
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	resolver, err := platform.NewQueryResolver(start, stop, variables, nil)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := &dashboardCellAnnotationsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/dashboards/%s/cells/%s/annotations", dashboard.ID, req.cellID),
+			"cell": fmt.Sprintf("/api/v2/dashboards/%s/cells/%s", dashboard.ID, req.cellID),
+		},
+		Start:       start,
+		Stop:        stop,
+		Annotations: []*annotationResponse{},
+	}
+
+	view, err := h.DashboardService.GetDashboardCellView(ctx, dashboard.ID, req.cellID)
+	if err != nil && platform.ErrorCode(err) != platform.ENotFound {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if view != nil {
+		resolved := *view
+		resolved.Properties = resolver.ResolveView(view.Properties)
+		vr := newDashboardCellViewResponse(dashboard.ID, req.cellID, &resolved)
+		res.View = &vr
+	}
+
+	if h.AnnotationService != nil {
+		as, _, err := h.AnnotationService.FindAnnotations(ctx, filter)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		for _, a := range as {
+			res.Annotations = append(res.Annotations, newAnnotationResponse(a))
+		}
+	}
+
+	h.Logger.Debug("dashboard cell annotations retrieved", zap.String("cellID", req.cellID.String()), zap.Int("annotations", len(res.Annotations)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
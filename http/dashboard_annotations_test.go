@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestService_handleGetDashboardCellAnnotations(t *testing.T) {
+	stop := time.Date(2019, 7, 1, 13, 0, 0, 0, time.UTC)
+	ds := mock.NewDashboardService()
+	ds.FindDashboardByIDF = func(_ context.Context, id platform.ID) (*platform.Dashboard, error) {
+		return &platform.Dashboard{ID: id, OrganizationID: 2, Cells: []*platform.Cell{{ID: 3}}}, nil
+	}
+	ds.GetDashboardCellViewF = func(_ context.Context, dashboardID, cellID platform.ID) (*platform.View, error) {
+		return &platform.View{
+			ViewContents: platform.ViewContents{ID: cellID, Name: "cpu"},
+			Properties: platform.XYViewProperties{
+				Type:    "xy",
+				Queries: []platform.DashboardQuery{{Text: "from(bucket: \"b\") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)"}},
+			},
+		}, nil
+	}
+
+	as := mock.NewAnnotationService()
+	as.FindAnnotationsFn = func(_ context.Context, filter platform.AnnotationFilter, _ ...platform.FindOptions) ([]*platform.Annotation, int, error) {
+		if filter.OrgID == nil || *filter.OrgID != 2 {
+			t.Errorf("expected the annotations of the dashboard's organization, got %+v", filter)
+		}
+		if filter.Start == nil || !filter.Start.Equal(stop.Add(-time.Hour)) || filter.Stop == nil || !filter.Stop.Equal(stop) {
+			t.Errorf("expected the annotations of the hour until the stop, got %v to %v", filter.Start, filter.Stop)
+		}
+		if len(filter.Streams) != 1 || filter.Streams[0] != "deploys" {
+			t.Errorf("expected the annotations of the deploys stream, got %v", filter.Streams)
+		}
+		return []*platform.Annotation{{ID: 4, OrgID: 2, Stream: "deploys", Summary: "deployed v1.2", StartTime: stop.Add(-time.Minute), EndTime: stop.Add(-time.Minute)}}, 1, nil
+	}
+
+	b := NewMockDashboardBackend()
+	b.HTTPErrorHandler = ErrorHandler(0)
+	b.DashboardService = ds
+	b.AnnotationService = as
+	h := NewDashboardHandler(b)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/dashboards/0000000000000001/cells/0000000000000003/annotations?stop=2019-07-01T13:00:00Z&stream=deploys", nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+
+	var res struct {
+		Start time.Time `json:"start"`
+		View  struct {
+			Properties struct {
+				Queries []struct {
+					Text string `json:"text"`
+				} `json:"queries"`
+			} `json:"properties"`
+		} `json:"view"`
+		Annotations []struct {
+			ID      platform.ID `json:"id"`
+			Summary string      `json:"summary"`
+		} `json:"annotations"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatal(err)
+	}
+	if !res.Start.Equal(stop.Add(-time.Hour)) {
+		t.Errorf("expected the range to start an hour before its stop, got %v", res.Start)
+	}
+	if qs := res.View.Properties.Queries; len(qs) != 1 || qs[0].Text != "from(bucket: \"b\") |> range(start: 2019-07-01T12:00:00Z, stop: 2019-07-01T13:00:00Z)" {
+		t.Errorf("expected the query of the cell resolved for the range, got %+v", qs)
+	}
+	if len(res.Annotations) != 1 || res.Annotations[0].ID != 4 {
+		t.Errorf("expected the deploy annotation, got %+v", res.Annotations)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/dashboards/0000000000000001/cells/0000000000000005/annotations", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a missing cell, want %d", w.Code, http.StatusNotFound)
+	}
+}
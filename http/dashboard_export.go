@@ -0,0 +1,447 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// handleGetDashboardExport is the HTTP handler for the GET /api/v2/dashboards/:id/export route.
+// The export refers to buckets by name and carries the variables and buckets its cells reference.
+func (h *DashboardHandler) handleGetDashboardExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	dashboard, err := h.DashboardService.FindDashboardByID(ctx, req.DashboardID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	export, err := h.exportDashboard(ctx, dashboard)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("dashboard exported", zap.String("dashboardID", dashboard.ID.String()), zap.Int("cells", len(export.Cells)))
+	if err := encodeResponse(ctx, w, http.StatusOK, export); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// exportDashboard returns the export of the dashboard with its cells, the variables of its
+// organization the cells reference, and the buckets the cells and variables query.
+func (h *DashboardHandler) exportDashboard(ctx context.Context, dashboard *platform.Dashboard) (*platform.DashboardExport, error) {
+	export := platform.NewDashboardExport(dashboard, time.Now())
+
+	var (
+		views       = make([]*platform.View, len(dashboard.Cells))
+		bucketIDs   = map[platform.ID]bool{}
+		bucketNames = map[string]bool{}
+		referenced  = map[string]bool{}
+	)
+	for i, cell := range dashboard.Cells {
+		view, err := h.DashboardService.GetDashboardCellView(ctx, dashboard.ID, cell.ID)
+		if platform.ErrorCode(err) == platform.ENotFound {
+			view = &platform.View{Properties: platform.EmptyViewProperties{}}
+		} else if err != nil {
+			return nil, err
+		}
+		views[i] = view
+
+		ids, names := platform.ViewBucketReferences(view.Properties)
+		for _, id := range ids {
+			bucketIDs[id] = true
+		}
+		for _, name := range names {
+			bucketNames[name] = true
+		}
+		for _, name := range platform.ReferencedVariables(view.Properties) {
+			referenced[name] = true
+		}
+	}
+
+	var variables []*platform.Variable
+	if len(referenced) > 0 && h.VariableService != nil {
+		vs, err := h.VariableService.FindVariables(ctx, platform.VariableFilter{OrganizationID: &dashboard.OrganizationID})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vs {
+			if !referenced[v.Name] {
+				continue
+			}
+			variables = append(variables, v)
+			query := platform.VariableQuery(v)
+			for _, id := range platform.BucketIDReferences(query) {
+				bucketIDs[id] = true
+			}
+			for _, name := range platform.BucketNameReferences(query) {
+				bucketNames[name] = true
+			}
+		}
+	}
+
+	// The buckets referenced by ID are exported under their names, so that the
+	// references can be resolved in another organization.
+	names := map[platform.ID]string{}
+	buckets := map[string]*platform.Bucket{}
+	if h.BucketService != nil {
+		for id := range bucketIDs {
+			b, err := h.BucketService.FindBucketByID(ctx, id)
+			if platform.ErrorCode(err) == platform.ENotFound {
+				// The reference is kept as is; it can't be resolved on import either.
+				h.Logger.Info("Skipping reference to missing bucket", zap.String("dashboardID", dashboard.ID.String()), zap.String("bucketID", id.String()))
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			names[id] = b.Name
+			buckets[b.Name] = b
+		}
+		for name := range bucketNames {
+			if _, ok := buckets[name]; ok {
+				continue
+			}
+			name := name
+			b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
+				Name:           &name,
+				OrganizationID: &dashboard.OrganizationID,
+			})
+			if platform.ErrorCode(err) == platform.ENotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			buckets[b.Name] = b
+		}
+	}
+
+	for i, cell := range dashboard.Cells {
+		export.Cells = append(export.Cells, platform.DashboardExportCell{
+			CellProperty: cell.CellProperty,
+			View: platform.View{
+				ViewContents: platform.ViewContents{Name: views[i].Name},
+				Properties:   platform.ReplaceViewBucketIDReferences(views[i].Properties, names),
+			},
+		})
+	}
+	for _, v := range variables {
+		export.Variables = append(export.Variables, platform.NewDashboardExportVariable(v, names))
+	}
+	sort.Slice(export.Variables, func(i, j int) bool {
+		return export.Variables[i].Name < export.Variables[j].Name
+	})
+	for _, b := range buckets {
+		export.Buckets = append(export.Buckets, platform.DashboardExportBucket{
+			Name:            b.Name,
+			Description:     b.Description,
+			RetentionPeriod: b.RetentionPeriod,
+		})
+	}
+	sort.Slice(export.Buckets, func(i, j int) bool {
+		return export.Buckets[i].Name < export.Buckets[j].Name
+	})
+
+	return export, nil
+}
+
+type dashboardImportResponse struct {
+	SourceDashboardID platform.ID       `json:"sourceDashboardID,omitempty"`
+	Dashboard         dashboardResponse `json:"dashboard"`
+	// CreatedVariables and CreatedBuckets list the exported variables and buckets
+	// that were missing from the organization, and were created.
+	CreatedVariables []string `json:"createdVariables"`
+	CreatedBuckets   []string `json:"createdBuckets"`
+	// MissingBuckets lists the exported buckets that are missing from the organization
+	// and were not created, because the import skipped buckets.
+	MissingBuckets []string `json:"missingBuckets"`
+}
+
+// dashboardImport holds the resources created by an import, so that they can be rolled back.
+type dashboardImport struct {
+	dashboard *platform.Dashboard
+	variables []*platform.Variable
+	buckets   []*platform.Bucket
+}
+
+// handlePostDashboardsImport is the HTTP handler for the POST /api/v2/dashboards:import route.
+// It creates the dashboard of the export with its cells, and the variables and buckets of the
+// export missing from the organization. Nothing is left behind if any of them fails to import.
+func (h *DashboardHandler) handlePostDashboardsImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostDashboardsImportRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	orgID, err := h.importOrganizationID(ctx, req)
+	if err != nil {
+		err = &platform.Error{
+			Err: err,
+			Msg: "could not identify organization",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	imported := &dashboardImport{}
+	res, err := h.importDashboard(ctx, orgID, req, imported)
+	if err != nil {
+		// Don't leave a partial import behind.
+		h.deleteImported(ctx, imported)
+		h.HandleHTTPError(ctx, &platform.Error{
+			Err: err,
+			Msg: fmt.Sprintf("failed to import dashboard: %s", platform.ErrorMessage(err)),
+		}, w)
+		return
+	}
+
+	h.Logger.Debug("dashboard imported", zap.String("dashboardID", imported.dashboard.ID.String()), zap.Int("variables", len(imported.variables)), zap.Int("buckets", len(imported.buckets)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *DashboardHandler) importOrganizationID(ctx context.Context, req *platform.DashboardImport) (platform.ID, error) {
+	if req.OrganizationID.Valid() {
+		o, err := h.OrganizationService.FindOrganizationByID(ctx, req.OrganizationID)
+		if err != nil {
+			return 0, err
+		}
+		return o.ID, nil
+	}
+	o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &req.Organization})
+	if err != nil {
+		return 0, err
+	}
+	return o.ID, nil
+}
+
+// importDashboard resolves the buckets and variables of the export in the organization,
+// creating the missing ones, then creates the dashboard and its cells.
+// Everything it creates is recorded in imported as soon as it exists.
+func (h *DashboardHandler) importDashboard(ctx context.Context, orgID platform.ID, req *platform.DashboardImport, imported *dashboardImport) (*dashboardImportResponse, error) {
+	export := &req.Dashboard
+	res := &dashboardImportResponse{
+		SourceDashboardID: export.SourceDashboardID,
+		CreatedVariables:  []string{},
+		CreatedBuckets:    []string{},
+		MissingBuckets:    []string{},
+	}
+
+	for _, eb := range export.Buckets {
+		name := eb.Name
+		_, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
+			Name:           &name,
+			OrganizationID: &orgID,
+		})
+		if err == nil {
+			continue
+		}
+		if platform.ErrorCode(err) != platform.ENotFound {
+			return nil, err
+		}
+		if req.SkipBuckets {
+			res.MissingBuckets = append(res.MissingBuckets, eb.Name)
+			continue
+		}
+
+		b := &platform.Bucket{
+			OrgID:           orgID,
+			Name:            eb.Name,
+			Description:     eb.Description,
+			RetentionPeriod: eb.RetentionPeriod,
+		}
+		if err := h.BucketService.CreateBucket(ctx, b); err != nil {
+			return nil, err
+		}
+		imported.buckets = append(imported.buckets, b)
+		res.CreatedBuckets = append(res.CreatedBuckets, b.Name)
+	}
+
+	if len(export.Variables) > 0 {
+		existing, err := h.VariableService.FindVariables(ctx, platform.VariableFilter{OrganizationID: &orgID})
+		if err != nil {
+			return nil, err
+		}
+		defined := make(map[string]bool, len(existing))
+		for _, v := range existing {
+			defined[v.Name] = true
+		}
+
+		for _, ev := range export.Variables {
+			if defined[ev.Name] {
+				continue
+			}
+			v := ev.Variable(orgID)
+			if err := h.VariableService.CreateVariable(ctx, v); err != nil {
+				return nil, err
+			}
+			imported.variables = append(imported.variables, v)
+			res.CreatedVariables = append(res.CreatedVariables, v.Name)
+			defined[v.Name] = true
+		}
+	}
+
+	name := export.Name
+	if req.Name != "" {
+		name = req.Name
+	}
+	d := &platform.Dashboard{
+		OrganizationID: orgID,
+		Name:           name,
+		Description:    export.Description,
+		Metadata:       export.Metadata,
+	}
+	if err := h.DashboardService.CreateDashboard(ctx, d); err != nil {
+		return nil, err
+	}
+	imported.dashboard = d
+
+	for i := range export.Cells {
+		ec := export.Cells[i]
+		view := ec.View
+		if err := h.DashboardService.AddDashboardCell(ctx, d.ID, &platform.Cell{CellProperty: ec.CellProperty}, platform.AddDashboardCellOptions{View: &view}); err != nil {
+			return nil, err
+		}
+	}
+
+	d, err := h.DashboardService.FindDashboardByID(ctx, d.ID)
+	if err != nil {
+		return nil, err
+	}
+	res.Dashboard = newDashboardResponse(d, []*platform.Label{})
+	return res, nil
+}
+
+// deleteImported removes the resources created by an import that failed part way.
+func (h *DashboardHandler) deleteImported(ctx context.Context, imported *dashboardImport) {
+	if d := imported.dashboard; d != nil {
+		if err := h.DashboardService.DeleteDashboard(ctx, d.ID); err != nil {
+			h.Logger.Warn("Failed to delete dashboard after failed import", zap.String("dashboardID", d.ID.String()), zap.Error(err))
+		}
+	}
+	for _, v := range imported.variables {
+		if err := h.VariableService.DeleteVariable(ctx, v.ID); err != nil {
+			h.Logger.Warn("Failed to delete variable after failed import", zap.String("variableID", v.ID.String()), zap.Error(err))
+		}
+	}
+	for _, b := range imported.buckets {
+		if err := h.BucketService.DeleteBucket(ctx, b.ID); err != nil {
+			h.Logger.Warn("Failed to delete bucket after failed import", zap.String("bucketID", b.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+func decodePostDashboardsImportRequest(ctx context.Context, r *http.Request) (*platform.DashboardImport, error) {
+	var req platform.DashboardImport
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// ExportDashboard returns the portable export of a dashboard.
+func (s *DashboardService) ExportDashboard(ctx context.Context, id platform.ID) (*platform.DashboardExport, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, path.Join(dashboardIDPath(id), "export"))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var export platform.DashboardExport
+	if err := json.NewDecoder(resp.Body).Decode(&export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ImportDashboard creates an exported dashboard in an organization and returns the created dashboard.
+func (s *DashboardService) ImportDashboard(ctx context.Context, di platform.DashboardImport) (*platform.Dashboard, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, dashboardsImportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBytes, err := json.Marshal(di)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var dr dashboardImportResponse
+	if err := json.NewDecoder(resp.Body).Decode(&dr); err != nil {
+		return nil, err
+	}
+	return dr.Dashboard.toPlatform(), nil
+}
@@ -0,0 +1,274 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestService_handleGetDashboardExport(t *testing.T) {
+	ds := mock.NewDashboardService()
+	ds.FindDashboardByIDF = func(_ context.Context, id platform.ID) (*platform.Dashboard, error) {
+		return &platform.Dashboard{
+			ID:             1,
+			OrganizationID: 2,
+			Name:           "hosts",
+			Cells: []*platform.Cell{
+				{ID: 3, CellProperty: platform.CellProperty{X: 0, Y: 0, W: 4, H: 4}},
+			},
+		}, nil
+	}
+	ds.GetDashboardCellViewF = func(_ context.Context, dashboardID, cellID platform.ID) (*platform.View, error) {
+		return &platform.View{
+			ViewContents: platform.ViewContents{ID: cellID, Name: "cpu"},
+			Properties: platform.XYViewProperties{
+				Type:    "xy",
+				Queries: []platform.DashboardQuery{{Text: `from(bucketID: "0000000000000005") |> filter(fn: (r) => r.host == v.host)`}},
+			},
+		}, nil
+	}
+
+	vs := mock.NewVariableService()
+	vs.FindVariablesF = func(_ context.Context, f platform.VariableFilter, _ ...platform.FindOptions) ([]*platform.Variable, error) {
+		return []*platform.Variable{
+			{
+				ID:             7,
+				OrganizationID: 2,
+				Name:           "host",
+				Selected:       []string{},
+				Arguments: &platform.VariableArguments{Type: "query", Values: platform.VariableQueryValues{
+					Query:    `import "influxdata/influxdb/v1" v1.tagValues(bucketID: "0000000000000005", tag: "host")`,
+					Language: "flux",
+				}},
+			},
+		}, nil
+	}
+
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(_ context.Context, id platform.ID) (*platform.Bucket, error) {
+		if id != 5 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		return &platform.Bucket{ID: 5, OrgID: 2, Name: "telegraf", RetentionPeriod: 72 * time.Hour}, nil
+	}
+
+	b := NewMockDashboardBackend()
+	b.HTTPErrorHandler = ErrorHandler(0)
+	b.DashboardService = ds
+	b.VariableService = vs
+	b.BucketService = bs
+	h := NewDashboardHandler(b)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/dashboards/0000000000000001/export", nil))
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", res.StatusCode, http.StatusOK, body)
+	}
+
+	var export platform.DashboardExport
+	if err := json.Unmarshal(body, &export); err != nil {
+		t.Fatalf("failed to decode export: %v", err)
+	}
+	if export.Version != platform.DashboardExportVersion || export.SourceDashboardID != 1 || export.Name != "hosts" {
+		t.Errorf("unexpected export %+v", export)
+	}
+	if len(export.Cells) != 1 || export.Cells[0].W != 4 || export.Cells[0].View.Name != "cpu" || export.Cells[0].View.ID.Valid() {
+		t.Fatalf("unexpected cells %+v", export.Cells)
+	}
+	xy, ok := export.Cells[0].View.Properties.(platform.XYViewProperties)
+	if !ok {
+		t.Fatalf("unexpected view properties %T", export.Cells[0].View.Properties)
+	}
+	if got, want := xy.Queries[0].Text, `from(bucket: "telegraf") |> filter(fn: (r) => r.host == v.host)`; got != want {
+		t.Errorf("got query %q, want %q", got, want)
+	}
+	if len(export.Variables) != 1 || export.Variables[0].Name != "host" {
+		t.Fatalf("unexpected variables %+v", export.Variables)
+	}
+	if got, want := export.Variables[0].Arguments.Values.(platform.VariableQueryValues).Query, `import "influxdata/influxdb/v1" v1.tagValues(bucket: "telegraf", tag: "host")`; got != want {
+		t.Errorf("got variable query %q, want %q", got, want)
+	}
+	if want := []platform.DashboardExportBucket{{Name: "telegraf", RetentionPeriod: 72 * time.Hour}}; len(export.Buckets) != 1 || export.Buckets[0] != want[0] {
+		t.Errorf("got buckets %+v, want %+v", export.Buckets, want)
+	}
+}
+
+func TestService_handlePostDashboardsImport(t *testing.T) {
+	export := `
+{
+  "version": 1,
+  "sourceDashboardID": "0000000000000001",
+  "name": "hosts",
+  "cells": [
+    {
+      "x": 0, "y": 0, "w": 4, "h": 4,
+      "view": {
+        "name": "cpu",
+        "properties": {
+          "shape": "chronograf-v2",
+          "type": "xy",
+          "queries": [{"text": "from(bucket: \"telegraf\") |> filter(fn: (r) => r.host == v.host)"}]
+        }
+      }
+    }
+  ],
+  "variables": [
+    {"name": "host", "selected": [], "arguments": {"type": "constant", "values": ["a", "b"]}},
+    {"name": "region", "selected": [], "arguments": {"type": "constant", "values": ["east"]}}
+  ],
+  "buckets": [
+    {"name": "telegraf", "retentionPeriod": 0},
+    {"name": "system", "retentionPeriod": 3600000000000}
+  ]
+}`
+
+	newHandler := func(t *testing.T, failCells bool) (*DashboardHandler, *[]string) {
+		var deleted []string
+
+		orgs := mock.NewOrganizationService()
+		orgs.FindOrganizationF = func(_ context.Context, f platform.OrganizationFilter) (*platform.Organization, error) {
+			return &platform.Organization{ID: 2, Name: *f.Name}, nil
+		}
+
+		bs := mock.NewBucketService()
+		bs.FindBucketFn = func(_ context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+			if *f.Name == "telegraf" {
+				return &platform.Bucket{ID: 5, OrgID: 2, Name: "telegraf"}, nil
+			}
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		bs.CreateBucketFn = func(_ context.Context, b *platform.Bucket) error {
+			if b.Name != "system" || b.OrgID != 2 || b.RetentionPeriod != time.Hour {
+				t.Errorf("unexpected bucket created %+v", b)
+			}
+			b.ID = 6
+			return nil
+		}
+		bs.DeleteBucketFn = func(_ context.Context, id platform.ID) error {
+			deleted = append(deleted, "bucket "+id.String())
+			return nil
+		}
+
+		vs := mock.NewVariableService()
+		vs.FindVariablesF = func(_ context.Context, f platform.VariableFilter, _ ...platform.FindOptions) ([]*platform.Variable, error) {
+			return []*platform.Variable{{ID: 7, OrganizationID: 2, Name: "host"}}, nil
+		}
+		vs.CreateVariableF = func(_ context.Context, v *platform.Variable) error {
+			if v.Name != "region" || v.OrganizationID != 2 {
+				t.Errorf("unexpected variable created %+v", v)
+			}
+			v.ID = 8
+			return nil
+		}
+		vs.DeleteVariableF = func(_ context.Context, id platform.ID) error {
+			deleted = append(deleted, "variable "+id.String())
+			return nil
+		}
+
+		var cells []*platform.Cell
+		ds := mock.NewDashboardService()
+		ds.CreateDashboardF = func(_ context.Context, d *platform.Dashboard) error {
+			if d.Name != "hosts copy" || d.OrganizationID != 2 {
+				t.Errorf("unexpected dashboard created %+v", d)
+			}
+			d.ID = 9
+			return nil
+		}
+		ds.AddDashboardCellF = func(_ context.Context, id platform.ID, c *platform.Cell, opts platform.AddDashboardCellOptions) error {
+			if failCells {
+				return &platform.Error{Code: platform.EInternal, Msg: "no space left"}
+			}
+			if opts.View == nil || opts.View.Name != "cpu" {
+				t.Errorf("unexpected view %+v", opts.View)
+			}
+			c.ID = 10
+			cells = append(cells, c)
+			return nil
+		}
+		ds.FindDashboardByIDF = func(_ context.Context, id platform.ID) (*platform.Dashboard, error) {
+			return &platform.Dashboard{ID: id, OrganizationID: 2, Name: "hosts copy", Cells: cells}, nil
+		}
+		ds.DeleteDashboardF = func(_ context.Context, id platform.ID) error {
+			deleted = append(deleted, "dashboard "+id.String())
+			return nil
+		}
+
+		b := NewMockDashboardBackend()
+		b.HTTPErrorHandler = ErrorHandler(0)
+		b.DashboardService = ds
+		b.VariableService = vs
+		b.BucketService = bs
+		b.OrganizationService = orgs
+		return NewDashboardHandler(b), &deleted
+	}
+
+	body := `{"org": "acme", "name": "hosts copy", "dashboard": ` + export + `}`
+
+	t.Run("imports the dashboard", func(t *testing.T) {
+		h, deleted := newHandler(t, false)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/api/v2/dashboards:import", bytes.NewBufferString(body)))
+
+		res := w.Result()
+		got, _ := ioutil.ReadAll(res.Body)
+		if res.StatusCode != http.StatusCreated {
+			t.Fatalf("got status %d, want %d: %s", res.StatusCode, http.StatusCreated, got)
+		}
+
+		var ir dashboardImportResponse
+		if err := json.Unmarshal(got, &ir); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if ir.SourceDashboardID != 1 || ir.Dashboard.ID != 9 || len(ir.Dashboard.Cells) != 1 {
+			t.Errorf("unexpected response %s", got)
+		}
+		if len(ir.CreatedVariables) != 1 || ir.CreatedVariables[0] != "region" {
+			t.Errorf("got created variables %v, want [region]", ir.CreatedVariables)
+		}
+		if len(ir.CreatedBuckets) != 1 || ir.CreatedBuckets[0] != "system" {
+			t.Errorf("got created buckets %v, want [system]", ir.CreatedBuckets)
+		}
+		if len(*deleted) != 0 {
+			t.Errorf("expected nothing to be deleted, got %v", *deleted)
+		}
+	})
+
+	t.Run("rolls back a failed import", func(t *testing.T) {
+		h, deleted := newHandler(t, true)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/api/v2/dashboards:import", bytes.NewBufferString(body)))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusInternalServerError)
+		}
+		want := []string{"dashboard 0000000000000009", "variable 0000000000000008", "bucket 0000000000000006"}
+		if len(*deleted) != len(want) {
+			t.Fatalf("got deleted %v, want %v", *deleted, want)
+		}
+		for i := range want {
+			if (*deleted)[i] != want[i] {
+				t.Errorf("got deleted %v, want %v", *deleted, want)
+			}
+		}
+	})
+
+	t.Run("only the import is a custom method", func(t *testing.T) {
+		h, _ := newHandler(t, false)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/dashboards:import", nil))
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("got status %d, want %d", w.Code, http.StatusMethodNotAllowed)
+		}
+	})
+}
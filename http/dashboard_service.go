@@ -9,6 +9,7 @@ import (
 	"path"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -24,6 +25,14 @@ type DashboardBackend struct {
 	UserResourceMappingService   platform.UserResourceMappingService
 	LabelService                 platform.LabelService
 	UserService                  platform.UserService
+	VariableService              platform.VariableService
+	RevisionService              platform.RevisionService
+	BucketService                platform.BucketService
+	OrganizationService          platform.OrganizationService
+	DashboardShareService        platform.DashboardShareService
+	AnnotationService            platform.AnnotationService
+	AuthorizationService         platform.AuthorizationService
+	QueryService                 query.QueryService
 }
 
 // NewDashboardBackend creates a backend used by the dashboard handler.
@@ -37,6 +46,14 @@ func NewDashboardBackend(b *APIBackend) *DashboardBackend {
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
+		VariableService:              b.VariableService,
+		RevisionService:              b.RevisionService,
+		BucketService:                b.BucketService,
+		OrganizationService:          b.OrganizationService,
+		DashboardShareService:        b.DashboardShareService,
+		AnnotationService:            b.AnnotationService,
+		AuthorizationService:         b.AuthorizationService,
+		QueryService:                 b.QueryService,
 	}
 }
 
@@ -52,6 +69,14 @@ type DashboardHandler struct {
 	UserResourceMappingService   platform.UserResourceMappingService
 	LabelService                 platform.LabelService
 	UserService                  platform.UserService
+	VariableService              platform.VariableService
+	RevisionService              platform.RevisionService
+	BucketService                platform.BucketService
+	OrganizationService          platform.OrganizationService
+	DashboardShareService        platform.DashboardShareService
+	AnnotationService            platform.AnnotationService
+	AuthorizationService         platform.AuthorizationService
+	QueryService                 query.QueryService
 }
 
 const (
@@ -67,6 +92,20 @@ const (
 	dashboardsIDOwnersIDPath    = "/api/v2/dashboards/:id/owners/:userID"
 	dashboardsIDLabelsPath      = "/api/v2/dashboards/:id/labels"
 	dashboardsIDLabelsIDPath    = "/api/v2/dashboards/:id/labels/:lid"
+	dashboardsIDSnapshotPath    = "/api/v2/dashboards/:id/snapshot"
+	dashboardsIDVariablesPath   = "/api/v2/dashboards/:id/variables"
+	dashboardsIDExportPath      = "/api/v2/dashboards/:id/export"
+	dashboardsIDSharesPath      = "/api/v2/dashboards/:id/shares"
+	dashboardsIDSharesIDPath    = "/api/v2/dashboards/:id/shares/:shareID"
+
+	dashboardsIDCellsIDAnnotationsPath = "/api/v2/dashboards/:id/cells/:cellID/annotations"
+
+	dashboardsIDRevisionsPath        = "/api/v2/dashboards/:id/revisions"
+	dashboardsIDRevisionsVersionPath = "/api/v2/dashboards/:id/revisions/:version"
+	dashboardsIDRevisionsRestorePath = "/api/v2/dashboards/:id/revisions/:version/restore"
+
+	// dashboardsImportPath is a custom method on the dashboards collection.
+	dashboardsImportPath = "/api/v2/dashboards:import"
 )
 
 // NewDashboardHandler returns a new instance of DashboardHandler.
@@ -81,6 +120,14 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 		UserResourceMappingService:   b.UserResourceMappingService,
 		LabelService:                 b.LabelService,
 		UserService:                  b.UserService,
+		VariableService:              b.VariableService,
+		RevisionService:              b.RevisionService,
+		BucketService:                b.BucketService,
+		OrganizationService:          b.OrganizationService,
+		DashboardShareService:        b.DashboardShareService,
+		AnnotationService:            b.AnnotationService,
+		AuthorizationService:         b.AuthorizationService,
+		QueryService:                 b.QueryService,
 	}
 
 	h.HandlerFunc("POST", dashboardsPath, h.handlePostDashboard)
@@ -97,6 +144,15 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 
 	h.HandlerFunc("GET", dashboardsIDCellsIDViewPath, h.handleGetDashboardCellView)
 	h.HandlerFunc("PATCH", dashboardsIDCellsIDViewPath, h.handlePatchDashboardCellView)
+	h.HandlerFunc("GET", dashboardsIDCellsIDAnnotationsPath, h.handleGetDashboardCellAnnotations)
+
+	h.HandlerFunc("POST", dashboardsIDSnapshotPath, h.handlePostDashboardSnapshot)
+	h.HandlerFunc("GET", dashboardsIDVariablesPath, h.handleGetDashboardVariables)
+	h.HandlerFunc("GET", dashboardsIDExportPath, h.handleGetDashboardExport)
+
+	h.HandlerFunc("POST", dashboardsIDSharesPath, h.handlePostDashboardShare)
+	h.HandlerFunc("GET", dashboardsIDSharesPath, h.handleGetDashboardShares)
+	h.HandlerFunc("DELETE", dashboardsIDSharesIDPath, h.handleDeleteDashboardShare)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -132,9 +188,34 @@ func NewDashboardHandler(b *DashboardBackend) *DashboardHandler {
 	h.HandlerFunc("POST", dashboardsIDLabelsPath, newPostLabelHandler(labelBackend))
 	h.HandlerFunc("DELETE", dashboardsIDLabelsIDPath, newDeleteLabelHandler(labelBackend))
 
+	revisionBackend := &RevisionBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "revision")),
+		RevisionService:  b.RevisionService,
+		ResourceType:     platform.DashboardsResourceType,
+	}
+	h.HandlerFunc("GET", dashboardsIDRevisionsPath, newGetRevisionsHandler(revisionBackend))
+	h.HandlerFunc("GET", dashboardsIDRevisionsVersionPath, newGetRevisionHandler(revisionBackend))
+	h.HandlerFunc("POST", dashboardsIDRevisionsRestorePath, newPostRevisionRestoreHandler(revisionBackend))
+
 	return h
 }
 
+// ServeHTTP routes the custom methods on the dashboards collection before delegating to the router.
+func (h *DashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != dashboardsImportPath {
+		h.Router.ServeHTTP(w, r)
+		return
+	}
+
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		baseHandler{HTTPErrorHandler: h.HTTPErrorHandler}.methodNotAllowed(w, r)
+		return
+	}
+	h.handlePostDashboardsImport(w, r)
+}
+
 type dashboardLinks struct {
 	Self         string `json:"self"`
 	Members      string `json:"members"`
@@ -151,6 +232,7 @@ type dashboardResponse struct {
 	Name           string                  `json:"name"`
 	Description    string                  `json:"description"`
 	Meta           platform.DashboardMeta  `json:"meta"`
+	Metadata       platform.Metadata       `json:"metadata,omitempty"`
 	Cells          []dashboardCellResponse `json:"cells"`
 	Labels         []platform.Label        `json:"labels"`
 	Links          dashboardLinks          `json:"links"`
@@ -169,6 +251,7 @@ func (d dashboardResponse) toPlatform() *platform.Dashboard {
 		OrganizationID: d.OrganizationID,
 		Name:           d.Name,
 		Meta:           d.Meta,
+		Metadata:       d.Metadata,
 		Cells:          cells,
 	}
 }
@@ -189,6 +272,7 @@ func newDashboardResponse(d *platform.Dashboard, labels []*platform.Label) dashb
 		Name:           d.Name,
 		Description:    d.Description,
 		Meta:           d.Meta,
+		Metadata:       d.Metadata,
 		Labels:         []platform.Label{},
 		Cells:          []dashboardCellResponse{},
 	}
@@ -345,15 +429,29 @@ func (h *DashboardHandler) handleGetDashboards(w http.ResponseWriter, r *http.Re
 		}
 	}
 
-	dashboards, _, err := h.DashboardService.FindDashboards(ctx, req.filter, req.opts)
+	dashboards, _, err := h.DashboardService.FindDashboards(ctx, req.filter, req.labels.findOptions(req.opts))
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
 
+	if len(req.labels) > 0 {
+		idx, err := req.labels.filter(ctx, h.LabelService, req.opts, len(dashboards), func(i int) platform.ID { return dashboards[i].ID })
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		labeled := make([]*platform.Dashboard, 0, len(idx))
+		for _, i := range idx {
+			labeled = append(labeled, dashboards[i])
+		}
+		dashboards = labeled
+	}
+
 	h.Logger.Debug("dashboards retrieved", zap.String("dashboards", fmt.Sprint(dashboards)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newGetDashboardsResponse(ctx, dashboards, req.filter, req.opts, h.LabelService)); err != nil {
+	res := newGetDashboardsResponse(ctx, dashboards, req.labels.pagingFilter(req.filter), req.opts, h.LabelService)
+	if err := encodeListResponse(ctx, w, req.opts.Fields, res.Links, "dashboards", res); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
@@ -363,6 +461,7 @@ type getDashboardsRequest struct {
 	filter  platform.DashboardFilter
 	opts    platform.FindOptions
 	ownerID *platform.ID
+	labels  labelFilter
 }
 
 func decodeGetDashboardsRequest(ctx context.Context, r *http.Request) (*getDashboardsRequest, error) {
@@ -373,6 +472,9 @@ func decodeGetDashboardsRequest(ctx context.Context, r *http.Request) (*getDashb
 	if err != nil {
 		return nil, err
 	}
+	if err := opts.ValidSortBy(platform.DashboardSortKeys...); err != nil {
+		return nil, err
+	}
 	req.opts = *opts
 
 	initialID := platform.InvalidID()
@@ -399,6 +501,12 @@ func decodeGetDashboardsRequest(ctx context.Context, r *http.Request) (*getDashb
 		req.filter.Organization = &org
 	}
 
+	if req.filter.Metadata, err = platform.ParseMetadataQueryParams(qp["metadata"]); err != nil {
+		return nil, err
+	}
+
+	req.labels = decodeLabelFilter(r)
+
 	return req, nil
 }
 
@@ -415,7 +523,7 @@ func (d getDashboardsResponse) toPlatform() []*platform.Dashboard {
 	return res
 }
 
-func newGetDashboardsResponse(ctx context.Context, dashboards []*platform.Dashboard, filter platform.DashboardFilter, opts platform.FindOptions, labelService platform.LabelService) getDashboardsResponse {
+func newGetDashboardsResponse(ctx context.Context, dashboards []*platform.Dashboard, filter platform.PagingFilter, opts platform.FindOptions, labelService platform.LabelService) getDashboardsResponse {
 	res := getDashboardsResponse{
 		Links:      newPagingLinks(dashboardsPath, opts, filter, len(dashboards)),
 		Dashboards: make([]dashboardResponse, 0, len(dashboards)),
@@ -1139,6 +1247,9 @@ func (s *DashboardService) FindDashboards(ctx context.Context, filter platform.D
 	if filter.Organization != nil {
 		qp.Add("org", *filter.Organization)
 	}
+	for _, m := range filter.Metadata.QueryParams() {
+		qp.Add("metadata", m)
+	}
 	for k, vs := range opts.QueryParams() {
 		for _, v := range vs {
 			qp.Add(k, v)
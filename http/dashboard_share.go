@@ -0,0 +1,731 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	sharesTokenPath            = "/api/v2/shares/:token"
+	sharesTokenCellResultsPath = "/api/v2/shares/:token/cells/:cellID/results"
+	sharesTokenPattern         = "/api/v2/shares/%s"
+
+	// dashboardShareDefaultRange is the time range of a share when none is given.
+	dashboardShareDefaultRange = time.Hour
+	// dashboardShareQueryMaxDuration is how long a query of a share may run for.
+	dashboardShareQueryMaxDuration = 30 * time.Second
+)
+
+type dashboardShareResponse struct {
+	Links           map[string]string             `json:"links"`
+	ID              influxdb.ID                   `json:"id"`
+	DashboardID     influxdb.ID                   `json:"dashboardID"`
+	OrgID           influxdb.ID                   `json:"orgID"`
+	Name            string                        `json:"name"`
+	Description     string                        `json:"description,omitempty"`
+	Mode            influxdb.DashboardShareMode   `json:"mode"`
+	Status          influxdb.DashboardShareStatus `json:"status"`
+	Start           *time.Time                    `json:"start,omitempty"`
+	Stop            *time.Time                    `json:"stop,omitempty"`
+	Range           string                        `json:"range,omitempty"`
+	AuthorizationID influxdb.ID                   `json:"authorizationID,omitempty"`
+	Cells           int                           `json:"cells"`
+	// URL is the absolute URL of the share, to be sent to those it is shared with.
+	URL       string      `json:"url,omitempty"`
+	ExpiresAt time.Time   `json:"expiresAt"`
+	CreatedBy influxdb.ID `json:"createdBy,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// newDashboardShareResponse leaves out the views and results of the share, which
+// are only served to those it is shared with.
+func newDashboardShareResponse(r *http.Request, s *influxdb.DashboardShare) *dashboardShareResponse {
+	res := &dashboardShareResponse{
+		Links: map[string]string{
+			"self":      fmt.Sprintf("/api/v2/dashboards/%s/shares/%s", s.DashboardID, s.ID),
+			"dashboard": fmt.Sprintf("/api/v2/dashboards/%s", s.DashboardID),
+		},
+		ID:              s.ID,
+		DashboardID:     s.DashboardID,
+		OrgID:           s.OrgID,
+		Name:            s.Name,
+		Description:     s.Description,
+		Mode:            s.Mode,
+		Status:          s.Status,
+		Range:           s.Range,
+		AuthorizationID: s.AuthorizationID,
+		Cells:           len(s.Cells),
+		ExpiresAt:       s.ExpiresAt,
+		CreatedBy:       s.CreatedBy,
+		CreatedAt:       s.CreatedAt,
+	}
+	if s.Mode == influxdb.DashboardShareFrozen {
+		start, stop := s.Start, s.Stop
+		res.Start, res.Stop = &start, &stop
+	}
+	if s.Status == influxdb.DashboardShareActive {
+		res.Links["share"] = fmt.Sprintf(sharesTokenPattern, s.Token)
+		res.URL = dashboardShareURL(r, s.Token)
+	}
+	return res
+}
+
+// dashboardShareURL is the URL of the share with the token on the host the request was sent to.
+func dashboardShareURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s"+sharesTokenPattern, scheme, r.Host, token)
+}
+
+type dashboardSharesResponse struct {
+	Links  map[string]string         `json:"links"`
+	Shares []*dashboardShareResponse `json:"shares"`
+}
+
+// postDashboardShareRequest shares the dashboard frozen over the last hour, unless
+// another time range is given. A live share shows the last hour unless another range is given.
+type postDashboardShareRequest struct {
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	Mode        influxdb.DashboardShareMode `json:"mode"`
+	Start       *time.Time                  `json:"start"`
+	Stop        *time.Time                  `json:"stop"`
+	Range       string                      `json:"range"`
+	Variables   map[string]string           `json:"variables"`
+	ExpiresAt   time.Time                   `json:"expiresAt"`
+}
+
+func decodePostDashboardShareRequest(ctx context.Context, r *http.Request) (*postDashboardShareRequest, error) {
+	req := &postDashboardShareRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil && err != io.EOF {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode dashboard share request",
+			Err:  err,
+		}
+	}
+	if req.Mode == "" {
+		req.Mode = influxdb.DashboardShareFrozen
+	}
+	return req, nil
+}
+
+// handlePostDashboardShare is the HTTP handler for the POST /api/v2/dashboards/:id/shares route.
+// A frozen share stores the results of the queries of the dashboard when it is created. A live
+// share is given an authorization that can only read the buckets its queries read, which it
+// queries with whenever it is viewed.
+func (h *DashboardHandler) handlePostDashboardShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("dashboard share create request", zap.String("r", fmt.Sprint(r)))
+
+	gr, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodePostDashboardShareRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	dashboard, err := h.DashboardService.FindDashboardByID(ctx, gr.DashboardID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	share, err := h.newDashboardShare(ctx, dashboard, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, handleFluxError(err), w)
+		return
+	}
+
+	if err := h.DashboardShareService.CreateDashboardShare(ctx, share); err != nil {
+		h.deleteDashboardShareAuthorization(ctx, share)
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dashboard share created", zap.String("shareID", share.ID.String()), zap.String("dashboardID", dashboard.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newDashboardShareResponse(r, share)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// newDashboardShare copies the cells of the dashboard into a share with their queries
+// resolved, and runs the queries of a frozen share or authorizes the queries of a live one.
+func (h *DashboardHandler) newDashboardShare(ctx context.Context, d *influxdb.Dashboard, req *postDashboardShareRequest) (*influxdb.DashboardShare, error) {
+	share := &influxdb.DashboardShare{
+		DashboardID: d.ID,
+		OrgID:       d.OrganizationID,
+		Name:        req.Name,
+		Description: req.Description,
+		Mode:        req.Mode,
+		Range:       req.Range,
+		ExpiresAt:   req.ExpiresAt,
+		Cells:       []influxdb.DashboardShareCell{},
+	}
+	if share.Name == "" {
+		share.Name = d.Name
+	}
+
+	now := time.Now().UTC()
+	switch share.Mode {
+	case influxdb.DashboardShareFrozen:
+		share.Stop = now
+		if req.Stop != nil {
+			share.Stop = *req.Stop
+		}
+		share.Start = share.Stop.Add(-dashboardShareDefaultRange)
+		if req.Start != nil {
+			share.Start = *req.Start
+		}
+	case influxdb.DashboardShareLive:
+		if share.Range == "" {
+			share.Range = dashboardShareDefaultRange.String()
+		}
+	}
+	if err := share.Valid(); err != nil {
+		return nil, err
+	}
+
+	var variables []*influxdb.Variable
+	if h.VariableService != nil {
+		vs, err := h.VariableService.FindVariables(ctx, influxdb.VariableFilter{OrganizationID: &d.OrganizationID})
+		if err != nil {
+			return nil, err
+		}
+		variables = vs
+	}
+
+	start, stop, err := share.TimeRange(now)
+	if err != nil {
+		return nil, err
+	}
+	resolver, err := influxdb.NewQueryResolver(start, stop, variables, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+	if share.Mode == influxdb.DashboardShareLive {
+		resolver = resolver.WithoutTimeRange()
+	}
+
+	for _, cell := range d.Cells {
+		view, err := h.DashboardService.GetDashboardCellView(ctx, d.ID, cell.ID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		resolved := *view
+		resolved.Properties = resolver.ResolveView(view.Properties)
+		share.Cells = append(share.Cells, influxdb.DashboardShareCell{
+			ID:           cell.ID,
+			CellProperty: cell.CellProperty,
+			View:         resolved,
+		})
+	}
+	if unresolved := resolver.Unresolved(); len(unresolved) > 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("variables %s have no value to share; select a value for them", strings.Join(unresolved, ", ")),
+		}
+	}
+
+	if h.QueryService == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "dashboard shares are not available",
+		}
+	}
+
+	if share.Mode == influxdb.DashboardShareLive {
+		if err := h.authorizeDashboardShare(ctx, share); err != nil {
+			return nil, err
+		}
+		return share, nil
+	}
+
+	auth, err := pcontext.GetAuthorization(ctx, share.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	budget := influxdb.DashboardShareMaxResultBytes
+	for i := range share.Cells {
+		results, err := runDashboardShareQueries(ctx, h.QueryService, auth, share.OrgID, share.Cells[i].View.Properties, &budget)
+		if err != nil {
+			return nil, err
+		}
+		share.Cells[i].Results = results
+	}
+	return share, nil
+}
+
+// authorizeDashboardShare creates the authorization of a live share for the user sharing it.
+// It can read the buckets the queries of the share read and nothing else, which the user must
+// be able to read as well.
+func (h *DashboardHandler) authorizeDashboardShare(ctx context.Context, share *influxdb.DashboardShare) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := map[influxdb.ID]bool{}
+	for _, cell := range share.Cells {
+		bucketIDs, names := influxdb.ViewBucketReferences(cell.View.Properties)
+		for _, id := range bucketIDs {
+			ids[id] = true
+		}
+		for _, name := range names {
+			name := name
+			b, err := h.BucketService.FindBucket(ctx, influxdb.BucketFilter{Name: &name, OrganizationID: &share.OrgID})
+			if err != nil {
+				return err
+			}
+			ids[b.ID] = true
+		}
+	}
+
+	bucketIDs := make([]influxdb.ID, 0, len(ids))
+	for id := range ids {
+		bucketIDs = append(bucketIDs, id)
+	}
+	sort.Slice(bucketIDs, func(i, j int) bool { return bucketIDs[i] < bucketIDs[j] })
+
+	auth := &influxdb.Authorization{
+		OrgID:       share.OrgID,
+		UserID:      a.GetUserID(),
+		Description: fmt.Sprintf("read-only access of the live share %q of a dashboard", share.Name),
+		Permissions: make([]influxdb.Permission, 0, len(bucketIDs)),
+	}
+	for _, id := range bucketIDs {
+		p, err := influxdb.NewPermissionAtID(id, influxdb.ReadAction, influxdb.BucketsResourceType, share.OrgID)
+		if err != nil {
+			return err
+		}
+		auth.Permissions = append(auth.Permissions, *p)
+	}
+
+	if err := h.AuthorizationService.CreateAuthorization(ctx, auth); err != nil {
+		return err
+	}
+	share.AuthorizationID = auth.ID
+	return nil
+}
+
+// deleteDashboardShareAuthorization deletes the authorization of a live share that was not
+// created or was revoked. A failure is only logged, as the authorization is of no use without the share.
+func (h *DashboardHandler) deleteDashboardShareAuthorization(ctx context.Context, share *influxdb.DashboardShare) {
+	if !share.AuthorizationID.Valid() {
+		return
+	}
+	if err := h.AuthorizationService.DeleteAuthorization(ctx, share.AuthorizationID); err != nil {
+		h.Logger.Warn("failed to delete the authorization of a dashboard share",
+			zap.String("authorizationID", share.AuthorizationID.String()), zap.Error(err))
+	}
+}
+
+// handleGetDashboardShares is the HTTP handler for the GET /api/v2/dashboards/:id/shares route.
+func (h *DashboardHandler) handleGetDashboardShares(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("dashboard shares retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ss, err := h.DashboardShareService.FindDashboardShares(ctx, influxdb.DashboardShareFilter{DashboardID: &req.DashboardID})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("dashboard shares retrieved", zap.Int("shares", len(ss)))
+
+	res := &dashboardSharesResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/dashboards/%s/shares", req.DashboardID),
+		},
+		Shares: make([]*dashboardShareResponse, 0, len(ss)),
+	}
+	for _, s := range ss {
+		res.Shares = append(res.Shares, newDashboardShareResponse(r, s))
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteDashboardShare is the HTTP handler for the DELETE /api/v2/dashboards/:id/shares/:shareID route.
+// Revoking a live share deletes its authorization as well.
+func (h *DashboardHandler) handleDeleteDashboardShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("dashboard share revoke request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	id, err := decodeIDParam(ctx, "shareID")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	s, err := h.DashboardShareService.FindDashboardShareByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if s.DashboardID != req.DashboardID {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrDashboardShareNotFound,
+		}, w)
+		return
+	}
+
+	if err := h.DashboardShareService.RevokeDashboardShare(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.deleteDashboardShareAuthorization(ctx, s)
+	h.Logger.Debug("dashboard share revoked", zap.String("shareID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// errDashboardShareTooLarge is returned when the results of a share exceed DashboardShareMaxResultBytes.
+var errDashboardShareTooLarge = &influxdb.Error{
+	Code: influxdb.EInvalid,
+	Msg:  fmt.Sprintf("the results of the dashboard exceed the %d bytes a share may have; share a shorter time range", influxdb.DashboardShareMaxResultBytes),
+}
+
+// shareResultsWriter buffers the results of a share until they exceed its budget.
+type shareResultsWriter struct {
+	bytes.Buffer
+	budget   *int
+	exceeded bool
+}
+
+func (w *shareResultsWriter) Write(p []byte) (int, error) {
+	if len(p) > *w.budget {
+		w.exceeded = true
+		return 0, errDashboardShareTooLarge
+	}
+	*w.budget -= len(p)
+	return w.Buffer.Write(p)
+}
+
+// runDashboardShareQueries returns the annotated CSV results of each of the queries of
+// the view, in order, with the authorization. The results are taken out of the budget.
+func runDashboardShareQueries(ctx context.Context, qs query.QueryService, auth *influxdb.Authorization, orgID influxdb.ID, p influxdb.ViewProperties, budget *int) ([]string, error) {
+	queries := influxdb.ViewQueries(p)
+	results := make([]string, 0, len(queries))
+	for _, q := range queries {
+		if strings.TrimSpace(q.Text) == "" {
+			results = append(results, "")
+			continue
+		}
+
+		itr, err := qs.Query(ctx, &query.Request{
+			Authorization:  auth,
+			OrganizationID: orgID,
+			Compiler:       lang.FluxCompiler{Query: q.Text},
+			MaxDuration:    dashboardShareQueryMaxDuration,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		w := &shareResultsWriter{budget: budget}
+		_, err = csv.NewMultiResultEncoder(csv.DefaultEncoderConfig()).Encode(w, itr)
+		itr.Release()
+		if w.exceeded {
+			return nil, errDashboardShareTooLarge
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, w.String())
+	}
+	return results, nil
+}
+
+// DashboardShareBackend is all services and associated parameters required to construct
+// the DashboardShareHandler.
+type DashboardShareBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DashboardShareService influxdb.DashboardShareService
+	AuthorizationService  influxdb.AuthorizationService
+	QueryService          query.QueryService
+}
+
+// NewDashboardShareBackend returns a new instance of DashboardShareBackend.
+func NewDashboardShareBackend(b *APIBackend) *DashboardShareBackend {
+	return &DashboardShareBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "dashboard_share")),
+
+		DashboardShareService: b.DashboardShareService,
+		AuthorizationService:  b.AuthorizationService,
+		QueryService:          b.QueryService,
+	}
+}
+
+// DashboardShareHandler serves the shares of dashboards to those they are shared with.
+// The token in the path is the credential of those requests, so they are not authenticated.
+type DashboardShareHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DashboardShareService influxdb.DashboardShareService
+	// AuthorizationService finds the authorizations live shares query with. It is
+	// not authorized, as the requests have no authorizer.
+	AuthorizationService influxdb.AuthorizationService
+	QueryService         query.QueryService
+}
+
+// NewDashboardShareHandler returns a new instance of DashboardShareHandler.
+func NewDashboardShareHandler(b *DashboardShareBackend) *DashboardShareHandler {
+	h := &DashboardShareHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		DashboardShareService: b.DashboardShareService,
+		AuthorizationService:  b.AuthorizationService,
+		QueryService:          b.QueryService,
+	}
+
+	h.HandlerFunc("GET", sharesTokenPath, h.handleGetDashboardShare)
+	h.HandlerFunc("GET", sharesTokenCellResultsPath, h.handleGetDashboardShareCellResults)
+	return h
+}
+
+type sharedCellResponse struct {
+	Links map[string]string `json:"links"`
+	ID    influxdb.ID       `json:"id"`
+	influxdb.CellProperty
+	View influxdb.View `json:"view"`
+}
+
+// sharedDashboardResponse is what those a dashboard is shared with are shown of the share.
+type sharedDashboardResponse struct {
+	Links       map[string]string           `json:"links"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description,omitempty"`
+	Mode        influxdb.DashboardShareMode `json:"mode"`
+	Start       time.Time                   `json:"start"`
+	Stop        time.Time                   `json:"stop"`
+	ExpiresAt   time.Time                   `json:"expiresAt"`
+	Cells       []sharedCellResponse        `json:"cells"`
+}
+
+type sharedCellResultsResponse struct {
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
+	// Results are the annotated CSV results of the queries of the view of the cell, in order.
+	Results []string `json:"results"`
+}
+
+// findActiveDashboardShare returns the share with the token in the path, which must not have expired.
+func (h *DashboardShareHandler) findActiveDashboardShare(ctx context.Context) (*influxdb.DashboardShare, error) {
+	token := httprouter.ParamsFromContext(ctx).ByName("token")
+	s, err := h.DashboardShareService.FindDashboardShareByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if s.Status != influxdb.DashboardShareActive {
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "dashboard share has expired",
+		}
+	}
+	return s, nil
+}
+
+// resolveSharedView returns the view of the cell of the share with its time range resolved for now.
+func resolveSharedView(s *influxdb.DashboardShare, cell influxdb.DashboardShareCell, now time.Time) (influxdb.View, time.Time, time.Time, error) {
+	start, stop, err := s.TimeRange(now)
+	if err != nil {
+		return influxdb.View{}, start, stop, err
+	}
+	view := cell.View
+	if s.Mode == influxdb.DashboardShareLive {
+		resolver, err := influxdb.NewQueryResolver(start, stop, nil, nil)
+		if err != nil {
+			return influxdb.View{}, start, stop, err
+		}
+		view.Properties = resolver.ResolveView(view.Properties)
+	}
+	return view, start, stop, nil
+}
+
+// handleGetDashboardShare is the HTTP handler for the GET /api/v2/shares/:token route.
+func (h *DashboardShareHandler) handleGetDashboardShare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s, err := h.findActiveDashboardShare(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	self := fmt.Sprintf(sharesTokenPattern, s.Token)
+	res := &sharedDashboardResponse{
+		Links: map[string]string{
+			"self": self,
+		},
+		Name:        s.Name,
+		Description: s.Description,
+		Mode:        s.Mode,
+		ExpiresAt:   s.ExpiresAt,
+		Cells:       make([]sharedCellResponse, 0, len(s.Cells)),
+	}
+
+	now := time.Now().UTC()
+	for _, cell := range s.Cells {
+		view, start, stop, err := resolveSharedView(s, cell, now)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		res.Start, res.Stop = start, stop
+		res.Cells = append(res.Cells, sharedCellResponse{
+			Links: map[string]string{
+				"results": fmt.Sprintf("%s/cells/%s/results", self, cell.ID),
+			},
+			ID:           cell.ID,
+			CellProperty: cell.CellProperty,
+			View:         view,
+		})
+	}
+	if len(s.Cells) == 0 {
+		res.Start, res.Stop, _ = s.TimeRange(now)
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetDashboardShareCellResults is the HTTP handler for the GET /api/v2/shares/:token/cells/:cellID/results route.
+// The results of a frozen share are the ones stored with it; the queries of a live share are run
+// with the authorization of the share.
+func (h *DashboardShareHandler) handleGetDashboardShareCellResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s, err := h.findActiveDashboardShare(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	cellID, err := decodeIDParam(ctx, "cellID")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	var cell *influxdb.DashboardShareCell
+	for i := range s.Cells {
+		if s.Cells[i].ID == cellID {
+			cell = &s.Cells[i]
+			break
+		}
+	}
+	if cell == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrCellNotFound,
+		}, w)
+		return
+	}
+
+	view, start, stop, err := resolveSharedView(s, *cell, time.Now().UTC())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	res := &sharedCellResultsResponse{
+		Start:   start,
+		Stop:    stop,
+		Results: cell.Results,
+	}
+
+	if s.Mode == influxdb.DashboardShareLive {
+		results, err := h.queryLiveShare(ctx, s, view)
+		if err != nil {
+			h.HandleHTTPError(ctx, handleFluxError(err), w)
+			return
+		}
+		res.Results = results
+	}
+	if res.Results == nil {
+		res.Results = []string{}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *DashboardShareHandler) queryLiveShare(ctx context.Context, s *influxdb.DashboardShare, view influxdb.View) ([]string, error) {
+	if h.QueryService == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "dashboard shares are not available",
+		}
+	}
+
+	auth, err := h.AuthorizationService.FindAuthorizationByID(ctx, s.AuthorizationID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "dashboard share is no longer authorized",
+			Err:  err,
+		}
+	}
+	if !auth.IsActive() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "dashboard share is no longer authorized",
+		}
+	}
+
+	budget := influxdb.DashboardShareMaxResultBytes
+	ctx = pcontext.SetAuthorizer(ctx, auth)
+	return runDashboardShareQueries(ctx, h.QueryService, auth, s.OrgID, view.Properties, &budget)
+}
@@ -0,0 +1,355 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap"
+)
+
+// newShareQueryService returns a query service that records the queries it runs and
+// returns a single table for each of them.
+func newShareQueryService(queries *[]*query.Request) *querymock.QueryService {
+	return &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			*queries = append(*queries, req)
+			return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TFloat}},
+					Data:    [][]interface{}{{1.5}},
+				}},
+			}}), nil
+		},
+	}
+}
+
+func TestDashboardHandler_handlePostDashboardShare(t *testing.T) {
+	newHandler := func(queries *[]*query.Request, created **platform.DashboardShare) (*DashboardHandler, *mock.AuthorizationService) {
+		ds := mock.NewDashboardService()
+		ds.FindDashboardByIDF = func(_ context.Context, id platform.ID) (*platform.Dashboard, error) {
+			return &platform.Dashboard{
+				ID:             1,
+				OrganizationID: 2,
+				Name:           "hosts",
+				Cells: []*platform.Cell{
+					{ID: 3, CellProperty: platform.CellProperty{W: 4, H: 4}},
+				},
+			}, nil
+		}
+		ds.GetDashboardCellViewF = func(_ context.Context, dashboardID, cellID platform.ID) (*platform.View, error) {
+			return &platform.View{
+				ViewContents: platform.ViewContents{ID: cellID, Name: "cpu"},
+				Properties: platform.XYViewProperties{
+					Type:    "xy",
+					Queries: []platform.DashboardQuery{{Text: `from(bucket: v.bucket) |> range(start: v.timeRangeStart, stop: v.timeRangeStop)`}},
+				},
+			}, nil
+		}
+
+		vs := mock.NewVariableService()
+		vs.FindVariablesF = func(_ context.Context, f platform.VariableFilter, _ ...platform.FindOptions) ([]*platform.Variable, error) {
+			return []*platform.Variable{{
+				ID:             7,
+				OrganizationID: 2,
+				Name:           "bucket",
+				Arguments:      &platform.VariableArguments{Type: "constant", Values: platform.VariableConstantValues{"telegraf"}},
+			}}, nil
+		}
+
+		bs := mock.NewBucketService()
+		bs.FindBucketFn = func(_ context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+			if *f.Name != "telegraf" || *f.OrganizationID != 2 {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+			}
+			return &platform.Bucket{ID: 5, OrgID: 2, Name: "telegraf"}, nil
+		}
+
+		as := mock.NewAuthorizationService()
+		as.CreateAuthorizationFn = func(_ context.Context, a *platform.Authorization) error {
+			a.ID = 9
+			return nil
+		}
+
+		ss := mock.NewDashboardShareService()
+		ss.CreateDashboardShareFn = func(_ context.Context, s *platform.DashboardShare) error {
+			s.ID = 8
+			s.Token = "secret"
+			s.Status = platform.DashboardShareActive
+			*created = s
+			return nil
+		}
+
+		b := NewMockDashboardBackend()
+		b.HTTPErrorHandler = ErrorHandler(0)
+		b.DashboardService = ds
+		b.VariableService = vs
+		b.BucketService = bs
+		b.AuthorizationService = as
+		b.DashboardShareService = ss
+		b.QueryService = newShareQueryService(queries)
+		return NewDashboardHandler(b), as
+	}
+
+	userCtx := pcontext.SetAuthorizer(context.Background(), &platform.Authorization{ID: 10, OrgID: 2, UserID: 11})
+
+	t.Run("frozen shares store the results of their queries", func(t *testing.T) {
+		var queries []*query.Request
+		var created *platform.DashboardShare
+		h, _ := newHandler(&queries, &created)
+
+		body := `{"start": "2019-07-01T12:00:00Z", "stop": "2019-07-01T13:00:00Z"}`
+		r := httptest.NewRequest("POST", "http://influx.example.com/api/v2/dashboards/0000000000000001/shares", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r.WithContext(userCtx))
+
+		got, _ := ioutil.ReadAll(w.Result().Body)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, got)
+		}
+		if len(queries) != 1 {
+			t.Fatalf("expected the query of the cell to run, got %d queries", len(queries))
+		}
+		if got, want := queries[0].Compiler.(lang.FluxCompiler).Query, `from(bucket: "telegraf") |> range(start: 2019-07-01T12:00:00Z, stop: 2019-07-01T13:00:00Z)`; got != want {
+			t.Errorf("got query %q, want %q", got, want)
+		}
+		if queries[0].Authorization.ID != 10 {
+			t.Errorf("expected the query to run with the authorization of the request, got %+v", queries[0].Authorization)
+		}
+		if created.Name != "hosts" || created.OrgID != 2 || created.Mode != platform.DashboardShareFrozen || created.AuthorizationID.Valid() {
+			t.Errorf("unexpected share %+v", created)
+		}
+		if len(created.Cells) != 1 || len(created.Cells[0].Results) != 1 || !strings.Contains(created.Cells[0].Results[0], "1.5") {
+			t.Fatalf("expected the share to store the results of the cell, got %+v", created.Cells)
+		}
+
+		var res dashboardShareResponse
+		if err := json.Unmarshal(got, &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.URL != "http://influx.example.com/api/v2/shares/secret" || res.Cells != 1 {
+			t.Errorf("unexpected response %s", got)
+		}
+	})
+
+	t.Run("live shares are authorized to read the buckets of their queries", func(t *testing.T) {
+		var queries []*query.Request
+		var created *platform.DashboardShare
+		h, as := newHandler(&queries, &created)
+
+		var auth *platform.Authorization
+		as.CreateAuthorizationFn = func(_ context.Context, a *platform.Authorization) error {
+			a.ID = 9
+			auth = a
+			return nil
+		}
+
+		r := httptest.NewRequest("POST", "/api/v2/dashboards/0000000000000001/shares", bytes.NewBufferString(`{"mode": "live", "range": "24h"}`))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r.WithContext(userCtx))
+
+		got, _ := ioutil.ReadAll(w.Result().Body)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, got)
+		}
+		if len(queries) != 0 {
+			t.Errorf("expected a live share to run no queries, got %d", len(queries))
+		}
+		if auth == nil || auth.UserID != 11 || auth.OrgID != 2 || len(auth.Permissions) != 1 {
+			t.Fatalf("unexpected authorization %+v", auth)
+		}
+		if p := auth.Permissions[0]; p.Action != platform.ReadAction || p.Resource.Type != platform.BucketsResourceType || *p.Resource.ID != 5 {
+			t.Errorf("expected the authorization to read the bucket of the query, got %v", p)
+		}
+		if created.AuthorizationID != 9 || created.Range != "24h" {
+			t.Errorf("unexpected share %+v", created)
+		}
+		xy := created.Cells[0].View.Properties.(platform.XYViewProperties)
+		if got, want := xy.Queries[0].Text, `from(bucket: "telegraf") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)`; got != want {
+			t.Errorf("got query %q, want %q", got, want)
+		}
+	})
+
+	t.Run("the authorization of a share that fails to be created is deleted", func(t *testing.T) {
+		var queries []*query.Request
+		var created *platform.DashboardShare
+		h, as := newHandler(&queries, &created)
+		h.DashboardShareService.(*mock.DashboardShareService).CreateDashboardShareFn = func(context.Context, *platform.DashboardShare) error {
+			return &platform.Error{Code: platform.EInternal, Msg: "no space left"}
+		}
+		var deleted platform.ID
+		as.DeleteAuthorizationFn = func(_ context.Context, id platform.ID) error {
+			deleted = id
+			return nil
+		}
+
+		r := httptest.NewRequest("POST", "/api/v2/dashboards/0000000000000001/shares", bytes.NewBufferString(`{"mode": "live"}`))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r.WithContext(userCtx))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusInternalServerError)
+		}
+		if deleted != 9 {
+			t.Errorf("expected the authorization of the share to be deleted, got %v", deleted)
+		}
+	})
+}
+
+func TestDashboardShareHandler(t *testing.T) {
+	now := time.Now().UTC()
+	shares := map[string]*platform.DashboardShare{
+		"frozen": {
+			ID:     1,
+			Token:  "frozen",
+			OrgID:  2,
+			Name:   "hosts",
+			Mode:   platform.DashboardShareFrozen,
+			Status: platform.DashboardShareActive,
+			Start:  now.Add(-time.Hour),
+			Stop:   now,
+			Cells: []platform.DashboardShareCell{{
+				ID:      3,
+				View:    platform.View{Properties: platform.XYViewProperties{Type: "xy", Queries: []platform.DashboardQuery{{Text: `from(bucket: "telegraf")`}}}},
+				Results: []string{"stored"},
+			}},
+			ExpiresAt: now.Add(time.Hour),
+		},
+		"live": {
+			ID:              4,
+			Token:           "live",
+			OrgID:           2,
+			Name:            "hosts",
+			Mode:            platform.DashboardShareLive,
+			Status:          platform.DashboardShareActive,
+			Range:           "1h",
+			AuthorizationID: 9,
+			Cells: []platform.DashboardShareCell{{
+				ID:   3,
+				View: platform.View{Properties: platform.XYViewProperties{Type: "xy", Queries: []platform.DashboardQuery{{Text: `from(bucket: "telegraf") |> range(start: v.timeRangeStart)`}}}},
+			}},
+			ExpiresAt: now.Add(time.Hour),
+		},
+		"expired": {
+			ID:        5,
+			Token:     "expired",
+			OrgID:     2,
+			Mode:      platform.DashboardShareLive,
+			Status:    platform.DashboardShareExpired,
+			Range:     "1h",
+			ExpiresAt: now.Add(-time.Hour),
+		},
+	}
+
+	ss := mock.NewDashboardShareService()
+	ss.FindDashboardShareByTokenFn = func(_ context.Context, token string) (*platform.DashboardShare, error) {
+		if s, ok := shares[token]; ok {
+			return s, nil
+		}
+		return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrDashboardShareNotFound}
+	}
+
+	authStatus := platform.Active
+	as := mock.NewAuthorizationService()
+	as.FindAuthorizationByIDFn = func(_ context.Context, id platform.ID) (*platform.Authorization, error) {
+		return &platform.Authorization{ID: id, OrgID: 2, Status: authStatus}, nil
+	}
+
+	var queries []*query.Request
+	h := NewDashboardShareHandler(&DashboardShareBackend{
+		HTTPErrorHandler:      ErrorHandler(0),
+		Logger:                zap.NewNop(),
+		DashboardShareService: ss,
+		AuthorizationService:  as,
+		QueryService:          newShareQueryService(&queries),
+	})
+
+	get := func(path string) (int, []byte) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		return w.Code, body
+	}
+
+	t.Run("live shares are resolved when they are viewed", func(t *testing.T) {
+		code, body := get("/api/v2/shares/live")
+		if code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", code, http.StatusOK, body)
+		}
+		if strings.Contains(string(body), "authorizationID") || strings.Contains(string(body), "v.timeRangeStart") {
+			t.Errorf("unexpected shared dashboard %s", body)
+		}
+		var res sharedDashboardResponse
+		if err := json.Unmarshal(body, &res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Cells) != 1 || res.Cells[0].Links["results"] != "/api/v2/shares/live/cells/0000000000000003/results" {
+			t.Errorf("unexpected cells %s", body)
+		}
+		if d := res.Stop.Sub(res.Start); d != time.Hour {
+			t.Errorf("expected the last hour to be shared, got %v", d)
+		}
+	})
+
+	t.Run("frozen shares return their stored results", func(t *testing.T) {
+		code, body := get("/api/v2/shares/frozen/cells/0000000000000003/results")
+		if code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", code, http.StatusOK, body)
+		}
+		var res sharedCellResultsResponse
+		if err := json.Unmarshal(body, &res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Results) != 1 || res.Results[0] != "stored" || len(queries) != 0 {
+			t.Errorf("expected the stored results without querying, got %s", body)
+		}
+	})
+
+	t.Run("live shares query with their authorization", func(t *testing.T) {
+		code, body := get("/api/v2/shares/live/cells/0000000000000003/results")
+		if code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", code, http.StatusOK, body)
+		}
+		if len(queries) != 1 || queries[0].Authorization.ID != 9 || queries[0].OrganizationID != 2 {
+			t.Fatalf("expected a query with the authorization of the share, got %+v", queries)
+		}
+		if q := queries[0].Compiler.(lang.FluxCompiler).Query; strings.Contains(q, "v.timeRangeStart") {
+			t.Errorf("expected the time range of the query to be resolved, got %q", q)
+		}
+	})
+
+	t.Run("live shares of inactive authorizations are forbidden", func(t *testing.T) {
+		authStatus = platform.Inactive
+		defer func() { authStatus = platform.Active }()
+		if code, body := get("/api/v2/shares/live/cells/0000000000000003/results"); code != http.StatusForbidden {
+			t.Errorf("got status %d, want %d: %s", code, http.StatusForbidden, body)
+		}
+	})
+
+	t.Run("expired and unknown shares can not be viewed", func(t *testing.T) {
+		if code, _ := get("/api/v2/shares/expired"); code != http.StatusForbidden {
+			t.Errorf("got status %d for an expired share, want %d", code, http.StatusForbidden)
+		}
+		if code, _ := get("/api/v2/shares/unknown"); code != http.StatusNotFound {
+			t.Errorf("got status %d for an unknown share, want %d", code, http.StatusNotFound)
+		}
+		if code, _ := get("/api/v2/shares/frozen/cells/0000000000000004/results"); code != http.StatusNotFound {
+			t.Errorf("got status %d for an unknown cell, want %d", code, http.StatusNotFound)
+		}
+	})
+}
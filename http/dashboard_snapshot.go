@@ -0,0 +1,148 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// dashboardSnapshotDefaultRange is the time range of a snapshot when no start is given.
+const dashboardSnapshotDefaultRange = time.Hour
+
+type postDashboardSnapshotRequest struct {
+	DashboardID platform.ID
+	Start       time.Time
+	Stop        time.Time
+	Variables   map[string]string
+}
+
+func decodePostDashboardSnapshotRequest(ctx context.Context, r *http.Request) (*postDashboardSnapshotRequest, error) {
+	gr, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		Start     *time.Time        `json:"start"`
+		Stop      *time.Time        `json:"stop"`
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request body",
+			Err:  err,
+		}
+	}
+
+	req := &postDashboardSnapshotRequest{
+		DashboardID: gr.DashboardID,
+		Stop:        time.Now().UTC(),
+		Variables:   body.Variables,
+	}
+	if body.Stop != nil {
+		req.Stop = *body.Stop
+	}
+	req.Start = req.Stop.Add(-dashboardSnapshotDefaultRange)
+	if body.Start != nil {
+		req.Start = *body.Start
+	}
+
+	return req, nil
+}
+
+type dashboardSnapshotCellResponse struct {
+	platform.Cell
+	View *dashboardCellViewResponse `json:"view,omitempty"`
+}
+
+type dashboardSnapshotResponse struct {
+	Links       map[string]string               `json:"links"`
+	DashboardID platform.ID                     `json:"dashboardID"`
+	Name        string                          `json:"name"`
+	Description string                          `json:"description"`
+	Start       time.Time                       `json:"start"`
+	Stop        time.Time                       `json:"stop"`
+	Variables   map[string]string               `json:"variables"`
+	Unresolved  []string                        `json:"unresolved"`
+	Cells       []dashboardSnapshotCellResponse `json:"cells"`
+}
+
+// handlePostDashboardSnapshot is the HTTP handler for the POST /api/v2/dashboards/:id/snapshot route.
+// It returns the dashboard with the queries of its cells resolved for a time range and a selection of
+// variables, so the snapshot renders the same data regardless of when or where it is viewed.
+func (h *DashboardHandler) handlePostDashboardSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostDashboardSnapshotRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	dashboard, err := h.DashboardService.FindDashboardByID(ctx, req.DashboardID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var variables []*platform.Variable
+	if h.VariableService != nil {
+		variables, err = h.VariableService.FindVariables(ctx, platform.VariableFilter{OrganizationID: &dashboard.OrganizationID})
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	resolver, err := platform.NewQueryResolver(req.Start, req.Stop, variables, req.Variables)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := &dashboardSnapshotResponse{
+		Links: map[string]string{
+			"self":      fmt.Sprintf("/api/v2/dashboards/%s/snapshot", dashboard.ID),
+			"dashboard": fmt.Sprintf("/api/v2/dashboards/%s", dashboard.ID),
+		},
+		DashboardID: dashboard.ID,
+		Name:        dashboard.Name,
+		Description: dashboard.Description,
+		Start:       req.Start,
+		Stop:        req.Stop,
+		Cells:       []dashboardSnapshotCellResponse{},
+	}
+
+	for _, cell := range dashboard.Cells {
+		view, err := h.DashboardService.GetDashboardCellView(ctx, dashboard.ID, cell.ID)
+		if err != nil && platform.ErrorCode(err) != platform.ENotFound {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		c := dashboardSnapshotCellResponse{Cell: *cell}
+		if view != nil {
+			resolved := *view
+			resolved.Properties = resolver.ResolveView(view.Properties)
+			vr := newDashboardCellViewResponse(dashboard.ID, cell.ID, &resolved)
+			c.View = &vr
+		}
+		res.Cells = append(res.Cells, c)
+	}
+	res.Variables = resolver.Values()
+	res.Unresolved = resolver.Unresolved()
+
+	h.Logger.Debug("dashboard snapshot created", zap.String("dashboardID", dashboard.ID.String()), zap.Strings("unresolved", res.Unresolved))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
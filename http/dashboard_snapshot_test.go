@@ -0,0 +1,170 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestService_handlePostDashboardSnapshot(t *testing.T) {
+	dashboard := &platform.Dashboard{
+		ID:             1,
+		OrganizationID: 2,
+		Name:           "hosts",
+		Cells: []*platform.Cell{
+			{ID: 3, CellProperty: platform.CellProperty{W: 4, H: 4}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "resolves the queries of the cells",
+			body:       `{"start": "2019-07-01T12:00:00Z", "stop": "2019-07-01T13:00:00Z", "variables": {"host": "b"}}`,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/dashboards/0000000000000001/snapshot",
+    "dashboard": "/api/v2/dashboards/0000000000000001"
+  },
+  "dashboardID": "0000000000000001",
+  "name": "hosts",
+  "description": "",
+  "start": "2019-07-01T12:00:00Z",
+  "stop": "2019-07-01T13:00:00Z",
+  "variables": {
+    "host": "\"server-b\"",
+    "timeRangeStart": "2019-07-01T12:00:00Z",
+    "timeRangeStop": "2019-07-01T13:00:00Z",
+    "windowPeriod": "10s"
+  },
+  "unresolved": ["bucket"],
+  "cells": [
+    {
+      "id": "0000000000000003",
+      "x": 0,
+      "y": 0,
+      "w": 4,
+      "h": 4,
+      "view": {
+        "id": "0000000000000003",
+        "name": "cpu",
+        "links": {
+          "self": "/api/v2/dashboards/0000000000000001/cells/0000000000000003"
+        },
+        "properties": {
+          "shape": "chronograf-v2",
+          "type": "single-stat",
+          "queries": [
+            {
+              "text": "from(bucket: v.bucket) |> range(start: 2019-07-01T12:00:00Z, stop: 2019-07-01T13:00:00Z) |> filter(fn: (r) => r.host == \"server-b\")",
+              "editMode": "advanced",
+              "name": "",
+              "builderConfig": {
+                "buckets": null,
+                "tags": null,
+                "functions": null,
+                "aggregateWindow": {
+                  "period": ""
+                }
+              }
+            }
+          ],
+          "prefix": "",
+          "suffix": "",
+          "colors": null,
+          "decimalPlaces": {
+            "isEnforced": false,
+            "digits": 0
+          },
+          "note": "",
+          "showNoteWhenEmpty": false
+        }
+      }
+    }
+  ]
+}
+`,
+		},
+		{
+			name:       "rejects unknown map keys",
+			body:       `{"variables": {"host": "c"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "rejects empty time ranges",
+			body:       `{"start": "2019-07-01T13:00:00Z", "stop": "2019-07-01T12:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := mock.NewDashboardService()
+			ds.FindDashboardByIDF = func(_ context.Context, id platform.ID) (*platform.Dashboard, error) {
+				if id != dashboard.ID {
+					return nil, &platform.Error{Code: platform.ENotFound, Msg: "dashboard not found"}
+				}
+				return dashboard, nil
+			}
+			ds.GetDashboardCellViewF = func(_ context.Context, dashboardID, cellID platform.ID) (*platform.View, error) {
+				return &platform.View{
+					ViewContents: platform.ViewContents{ID: cellID, Name: "cpu"},
+					Properties: platform.SingleStatViewProperties{
+						Type: "single-stat",
+						Queries: []platform.DashboardQuery{{
+							Text:     "from(bucket: v.bucket) |> range(start: v.timeRangeStart, stop: v.timeRangeStop) |> filter(fn: (r) => r.host == v.host)",
+							EditMode: "advanced",
+						}},
+					},
+				}, nil
+			}
+
+			vs := mock.NewVariableService()
+			vs.FindVariablesF = func(_ context.Context, f platform.VariableFilter, _ ...platform.FindOptions) ([]*platform.Variable, error) {
+				if f.OrganizationID == nil || *f.OrganizationID != dashboard.OrganizationID {
+					t.Errorf("expected the variables of the dashboard's organization, got filter %+v", f)
+				}
+				return []*platform.Variable{{
+					Name:      "host",
+					Arguments: &platform.VariableArguments{Type: "map", Values: platform.VariableMapValues{"a": "server-a", "b": "server-b"}},
+				}}, nil
+			}
+
+			b := NewMockDashboardBackend()
+			b.HTTPErrorHandler = ErrorHandler(0)
+			b.DashboardService = ds
+			b.VariableService = vs
+			h := NewDashboardHandler(b)
+
+			r := httptest.NewRequest("POST", "/api/v2/dashboards/0000000000000001/snapshot", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+				t.Errorf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("unexpected body: %s", diff)
+			}
+		})
+	}
+}
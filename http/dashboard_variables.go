@@ -0,0 +1,106 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	platform "github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+type dashboardVariableResponse struct {
+	variableResponse
+	Cells []platform.ID `json:"cells"`
+}
+
+type dashboardUndefinedVariableResponse struct {
+	Name  string        `json:"name"`
+	Cells []platform.ID `json:"cells"`
+}
+
+type dashboardVariablesResponse struct {
+	Links     map[string]string                    `json:"links"`
+	Variables []dashboardVariableResponse          `json:"variables"`
+	Undefined []dashboardUndefinedVariableResponse `json:"undefined"`
+}
+
+// handleGetDashboardVariables is the HTTP handler for the GET /api/v2/dashboards/:id/variables route.
+// It returns the variables of the organization of the dashboard that the queries of its cells
+// reference, with the cells that reference them, and the names referenced that are not defined.
+func (h *DashboardHandler) handleGetDashboardVariables(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetDashboardRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	dashboard, err := h.DashboardService.FindDashboardByID(ctx, req.DashboardID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var variables []*platform.Variable
+	if h.VariableService != nil {
+		variables, err = h.VariableService.FindVariables(ctx, platform.VariableFilter{OrganizationID: &dashboard.OrganizationID})
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+	}
+
+	// The cells that reference each variable, by name.
+	cells := map[string][]platform.ID{}
+	for _, cell := range dashboard.Cells {
+		view, err := h.DashboardService.GetDashboardCellView(ctx, dashboard.ID, cell.ID)
+		if platform.ErrorCode(err) == platform.ENotFound {
+			continue
+		}
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		for _, name := range platform.ReferencedVariables(view.Properties) {
+			cells[name] = append(cells[name], cell.ID)
+		}
+	}
+
+	res := &dashboardVariablesResponse{
+		Links: map[string]string{
+			"self":      fmt.Sprintf("/api/v2/dashboards/%s/variables", dashboard.ID),
+			"dashboard": fmt.Sprintf("/api/v2/dashboards/%s", dashboard.ID),
+		},
+		Variables: []dashboardVariableResponse{},
+		Undefined: []dashboardUndefinedVariableResponse{},
+	}
+	for _, v := range variables {
+		ids, ok := cells[v.Name]
+		if !ok {
+			continue
+		}
+		delete(cells, v.Name)
+		res.Variables = append(res.Variables, dashboardVariableResponse{
+			variableResponse: newVariableResponse(v, nil),
+			Cells:            ids,
+		})
+	}
+	sort.Slice(res.Variables, func(i, j int) bool {
+		return res.Variables[i].Name < res.Variables[j].Name
+	})
+	for name, ids := range cells {
+		res.Undefined = append(res.Undefined, dashboardUndefinedVariableResponse{Name: name, Cells: ids})
+	}
+	sort.Slice(res.Undefined, func(i, j int) bool {
+		return res.Undefined[i].Name < res.Undefined[j].Name
+	})
+
+	h.Logger.Debug("dashboard variables retrieved", zap.String("dashboardID", dashboard.ID.String()), zap.Int("variables", len(res.Variables)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
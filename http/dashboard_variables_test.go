@@ -0,0 +1,142 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestService_handleGetDashboardVariables(t *testing.T) {
+	dashboard := &platform.Dashboard{
+		ID:             1,
+		OrganizationID: 2,
+		Name:           "hosts",
+		Cells: []*platform.Cell{
+			{ID: 3},
+			{ID: 4},
+			{ID: 5},
+		},
+	}
+
+	ds := mock.NewDashboardService()
+	ds.FindDashboardByIDF = func(_ context.Context, id platform.ID) (*platform.Dashboard, error) {
+		return dashboard, nil
+	}
+	ds.GetDashboardCellViewF = func(_ context.Context, dashboardID, cellID platform.ID) (*platform.View, error) {
+		queries := map[platform.ID]string{
+			3: "from(bucket: v.bucket) |> range(start: v.timeRangeStart) |> filter(fn: (r) => r.host == v.host)",
+			4: "from(bucket: v.bucket) |> range(start: v.timeRangeStart) |> filter(fn: (r) => r.region == v.region)",
+		}
+		if _, ok := queries[cellID]; !ok {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "view not found"}
+		}
+		return &platform.View{
+			ViewContents: platform.ViewContents{ID: cellID},
+			Properties: platform.XYViewProperties{
+				Type:    "xy",
+				Queries: []platform.DashboardQuery{{Text: queries[cellID]}},
+			},
+		}, nil
+	}
+
+	vs := mock.NewVariableService()
+	vs.FindVariablesF = func(_ context.Context, f platform.VariableFilter, _ ...platform.FindOptions) ([]*platform.Variable, error) {
+		if f.OrganizationID == nil || *f.OrganizationID != dashboard.OrganizationID {
+			t.Errorf("expected the variables of the dashboard's organization, got filter %+v", f)
+		}
+		return []*platform.Variable{
+			{
+				ID:             7,
+				OrganizationID: 2,
+				Name:           "host",
+				Selected:       []string{"a"},
+				Arguments:      &platform.VariableArguments{Type: "map", Values: platform.VariableMapValues{"a": "server-a"}},
+			},
+			{
+				ID:             6,
+				OrganizationID: 2,
+				Name:           "bucket",
+				Selected:       []string{},
+				Arguments:      &platform.VariableArguments{Type: "constant", Values: platform.VariableConstantValues{"telegraf"}},
+			},
+			// Not referenced by the dashboard.
+			{
+				ID:             8,
+				OrganizationID: 2,
+				Name:           "dc",
+				Selected:       []string{},
+				Arguments:      &platform.VariableArguments{Type: "constant", Values: platform.VariableConstantValues{"east"}},
+			},
+		}, nil
+	}
+
+	b := NewMockDashboardBackend()
+	b.HTTPErrorHandler = ErrorHandler(0)
+	b.DashboardService = ds
+	b.VariableService = vs
+	h := NewDashboardHandler(b)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v2/dashboards/0000000000000001/variables", nil))
+
+	res := w.Result()
+	body, _ := ioutil.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", res.StatusCode, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/dashboards/0000000000000001/variables",
+    "dashboard": "/api/v2/dashboards/0000000000000001"
+  },
+  "variables": [
+    {
+      "id": "0000000000000006",
+      "orgID": "0000000000000002",
+      "name": "bucket",
+      "description": "",
+      "selected": [],
+      "arguments": {"type": "constant", "values": ["telegraf"]},
+      "createdAt": "0001-01-01T00:00:00Z",
+      "updatedAt": "0001-01-01T00:00:00Z",
+      "labels": [],
+      "links": {
+        "self": "/api/v2/variables/0000000000000006",
+        "labels": "/api/v2/variables/0000000000000006/labels",
+        "org": "/api/v2/orgs/0000000000000002"
+      },
+      "cells": ["0000000000000003", "0000000000000004"]
+    },
+    {
+      "id": "0000000000000007",
+      "orgID": "0000000000000002",
+      "name": "host",
+      "description": "",
+      "selected": ["a"],
+      "arguments": {"type": "map", "values": {"a": "server-a"}},
+      "createdAt": "0001-01-01T00:00:00Z",
+      "updatedAt": "0001-01-01T00:00:00Z",
+      "labels": [],
+      "links": {
+        "self": "/api/v2/variables/0000000000000007",
+        "labels": "/api/v2/variables/0000000000000007/labels",
+        "org": "/api/v2/orgs/0000000000000002"
+      },
+      "cells": ["0000000000000003"]
+    }
+  ],
+  "undefined": [
+    {"name": "region", "cells": ["0000000000000004"]}
+  ]
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("unexpected body: %s", diff)
+	}
+}
@@ -0,0 +1,317 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	dbrpMappingsPath   = "/api/v2/dbrps"
+	dbrpMappingKeyPath = "/api/v2/dbrps/:cluster/:db/:rp"
+)
+
+// DBRPMappingBackend is all services and associated parameters required to construct
+// the DBRPMappingHandler.
+type DBRPMappingBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DBRPMappingService platform.DBRPMappingService
+	BucketService      platform.BucketService
+}
+
+// NewDBRPMappingBackend returns a new instance of DBRPMappingBackend.
+func NewDBRPMappingBackend(b *APIBackend) *DBRPMappingBackend {
+	return &DBRPMappingBackend{
+		HTTPErrorHandler:   b.HTTPErrorHandler,
+		Logger:             b.Logger.With(zap.String("handler", "dbrp_mapping")),
+		DBRPMappingService: b.DBRPMappingService,
+		BucketService:      b.BucketService,
+	}
+}
+
+// DBRPMappingHandler represents an HTTP API handler for the mappings of InfluxDB 1.x
+// databases and retention policies to buckets.
+type DBRPMappingHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DBRPMappingService platform.DBRPMappingService
+	BucketService      platform.BucketService
+}
+
+// NewDBRPMappingHandler returns a new instance of DBRPMappingHandler.
+func NewDBRPMappingHandler(b *DBRPMappingBackend) *DBRPMappingHandler {
+	h := &DBRPMappingHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		DBRPMappingService: b.DBRPMappingService,
+		BucketService:      b.BucketService,
+	}
+
+	h.HandlerFunc("GET", dbrpMappingsPath, h.handleGetDBRPMappings)
+	h.HandlerFunc("POST", dbrpMappingsPath, h.handlePostDBRPMapping)
+
+	h.HandlerFunc("GET", dbrpMappingKeyPath, h.handleGetDBRPMapping)
+	h.HandlerFunc("DELETE", dbrpMappingKeyPath, h.handleDeleteDBRPMapping)
+
+	return h
+}
+
+type dbrpMappingResponse struct {
+	*platform.DBRPMapping
+	Links map[string]string `json:"links"`
+}
+
+func newDBRPMappingResponse(m *platform.DBRPMapping) dbrpMappingResponse {
+	return dbrpMappingResponse{
+		DBRPMapping: m,
+		Links: map[string]string{
+			"self":   dbrpMappingPath(m.Cluster, m.Database, m.RetentionPolicy),
+			"bucket": path.Join(bucketsPath, m.BucketID.String()),
+			"org":    path.Join(organizationsPath, m.OrganizationID.String()),
+		},
+	}
+}
+
+type dbrpMappingsResponse struct {
+	Links        map[string]string     `json:"links"`
+	DBRPMappings []dbrpMappingResponse `json:"dbrps"`
+}
+
+func newDBRPMappingsResponse(ms []*platform.DBRPMapping) dbrpMappingsResponse {
+	res := dbrpMappingsResponse{
+		Links: map[string]string{
+			"self": dbrpMappingsPath,
+		},
+		DBRPMappings: make([]dbrpMappingResponse, 0, len(ms)),
+	}
+	for _, m := range ms {
+		res.DBRPMappings = append(res.DBRPMappings, newDBRPMappingResponse(m))
+	}
+	return res
+}
+
+func dbrpMappingPath(cluster, db, rp string) string {
+	return path.Join(dbrpMappingsPath, cluster, db, rp)
+}
+
+// handleGetDBRPMappings is the HTTP handler for the GET /api/v2/dbrps route.
+func (h *DBRPMappingHandler) handleGetDBRPMappings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetDBRPMappingsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.DBRPMappingService.FindMany(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDBRPMappingsResponse(ms)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetDBRPMappingsRequest(ctx context.Context, r *http.Request) (*platform.DBRPMappingFilter, error) {
+	qp := r.URL.Query()
+	filter := &platform.DBRPMappingFilter{}
+
+	if cluster := qp.Get("cluster"); cluster != "" {
+		filter.Cluster = &cluster
+	}
+	if db := qp.Get("db"); db != "" {
+		filter.Database = &db
+	}
+	if rp := qp.Get("rp"); rp != "" {
+		filter.RetentionPolicy = &rp
+	}
+	if s := qp.Get("default"); s != "" {
+		def, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "default must be a boolean",
+				Err:  err,
+			}
+		}
+		filter.Default = &def
+	}
+
+	return filter, nil
+}
+
+// handlePostDBRPMapping is the HTTP handler for the POST /api/v2/dbrps route.
+func (h *DBRPMappingHandler) handlePostDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	m, err := decodePostDBRPMappingRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// The bucket must belong to the organization of the mapping, since the
+	// data of a bucket is stored by both of them.
+	b, err := h.BucketService.FindBucketByID(ctx, m.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if b.OrgID != m.OrganizationID {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "bucket does not belong to the organization of the mapping",
+		}, w)
+		return
+	}
+
+	if err := h.DBRPMappingService.Create(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newDBRPMappingResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostDBRPMappingRequest(ctx context.Context, r *http.Request) (*platform.DBRPMapping, error) {
+	m := &platform.DBRPMapping{}
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// handleGetDBRPMapping is the HTTP handler for the GET /api/v2/dbrps/:cluster/:db/:rp route.
+func (h *DBRPMappingHandler) handleGetDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params := httprouter.ParamsFromContext(ctx)
+	m, err := h.DBRPMappingService.FindBy(ctx, params.ByName("cluster"), params.ByName("db"), params.ByName("rp"))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDBRPMappingResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteDBRPMapping is the HTTP handler for the DELETE /api/v2/dbrps/:cluster/:db/:rp route.
+func (h *DBRPMappingHandler) handleDeleteDBRPMapping(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params := httprouter.ParamsFromContext(ctx)
+	if err := h.DBRPMappingService.Delete(ctx, params.ByName("cluster"), params.ByName("db"), params.ByName("rp")); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DBRPMappingService connects to Influx via HTTP using tokens to manage dbrp mappings.
+type DBRPMappingService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.DBRPMappingService = (*DBRPMappingService)(nil)
+
+// FindBy returns the dbrp mapping for the cluster, db and rp.
+func (s *DBRPMappingService) FindBy(ctx context.Context, cluster, db, rp string) (*platform.DBRPMapping, error) {
+	var res dbrpMappingResponse
+	if err := s.client().do(ctx, "GET", dbrpMappingPath(cluster, db, rp), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.DBRPMapping, nil
+}
+
+// Find returns the first dbrp mapping that matches filter.
+func (s *DBRPMappingService) Find(ctx context.Context, filter platform.DBRPMappingFilter) (*platform.DBRPMapping, error) {
+	ms, n, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "dbrp mapping not found",
+		}
+	}
+
+	return ms[0], nil
+}
+
+// FindMany returns the dbrp mappings that match filter and the total count of matching dbrp mappings.
+func (s *DBRPMappingService) FindMany(ctx context.Context, filter platform.DBRPMappingFilter, opt ...platform.FindOptions) ([]*platform.DBRPMapping, int, error) {
+	query := url.Values{}
+	if filter.Cluster != nil {
+		query.Set("cluster", *filter.Cluster)
+	}
+	if filter.Database != nil {
+		query.Set("db", *filter.Database)
+	}
+	if filter.RetentionPolicy != nil {
+		query.Set("rp", *filter.RetentionPolicy)
+	}
+	if filter.Default != nil {
+		query.Set("default", strconv.FormatBool(*filter.Default))
+	}
+
+	var res dbrpMappingsResponse
+	if err := s.client().do(ctx, "GET", dbrpMappingsPath, query, nil, &res); err != nil {
+		return nil, 0, err
+	}
+
+	ms := make([]*platform.DBRPMapping, 0, len(res.DBRPMappings))
+	for _, m := range res.DBRPMappings {
+		ms = append(ms, m.DBRPMapping)
+	}
+	return ms, len(ms), nil
+}
+
+// Create creates a new dbrp mapping.
+func (s *DBRPMappingService) Create(ctx context.Context, m *platform.DBRPMapping) error {
+	return s.client().do(ctx, "POST", dbrpMappingsPath, nil, m, nil)
+}
+
+// Delete removes a dbrp mapping.
+func (s *DBRPMappingService) Delete(ctx context.Context, cluster, db, rp string) error {
+	return s.client().do(ctx, "DELETE", dbrpMappingPath(cluster, db, rp), nil, nil, nil)
+}
+
+func (s *DBRPMappingService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
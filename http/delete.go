@@ -0,0 +1,217 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const deletePath = "/api/v2/delete"
+
+// DeleteBackend is all services and associated parameters required to construct
+// the DeleteHandler.
+type DeleteBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DeleteService platform.DeleteService
+	BucketService platform.BucketService
+}
+
+// NewDeleteBackend returns a new instance of DeleteBackend.
+func NewDeleteBackend(b *APIBackend) *DeleteBackend {
+	return &DeleteBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "delete")),
+
+		DeleteService: b.DeleteService,
+		BucketService: b.BucketService,
+	}
+}
+
+// DeleteHandler is the handler for deleting data from buckets.
+type DeleteHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DeleteService platform.DeleteService
+	BucketService platform.BucketService
+}
+
+// NewDeleteHandler returns a new instance of DeleteHandler.
+func NewDeleteHandler(b *DeleteBackend) *DeleteHandler {
+	h := &DeleteHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		DeleteService: b.DeleteService,
+		BucketService: b.BucketService,
+	}
+
+	h.HandlerFunc("POST", deletePath, h.handleDelete)
+	return h
+}
+
+// deleteRequestBody is the body of a delete request, which selects the data of
+// the bucket of the query parameters.
+type deleteRequestBody struct {
+	Start       time.Time                     `json:"start"`
+	Stop        time.Time                     `json:"stop"`
+	Measurement string                        `json:"measurement,omitempty"`
+	Tags        []platform.SeriesTagPredicate `json:"tags,omitempty"`
+}
+
+// handleDelete is the HTTP handler for the POST /api/v2/delete route.
+// The data is deleted once the response is sent.
+func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeDeleteRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.findBucket(ctx, r.URL.Query())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	req.OrgID = b.OrgID
+	req.BucketID = b.ID
+	if err := req.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.DeleteService.Delete(ctx, *req); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("data deleted", zap.String("bucketID", b.ID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeDeleteRequest(ctx context.Context, r *http.Request) (*platform.DeleteRequest, error) {
+	var body deleteRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	req := &platform.DeleteRequest{
+		Start:       body.Start,
+		Stop:        body.Stop,
+		Measurement: body.Measurement,
+		Tags:        body.Tags,
+	}
+	return req, nil
+}
+
+// findBucket returns the bucket of a delete, which is given by its ID, or by its
+// name and the ID or name of its organization.
+func (h *DeleteHandler) findBucket(ctx context.Context, qp url.Values) (*platform.Bucket, error) {
+	if bucketID := qp.Get("bucketID"); bucketID != "" {
+		id, err := platform.IDFromString(bucketID)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid bucketID",
+				Err:  err,
+			}
+		}
+		return h.BucketService.FindBucketByID(ctx, *id)
+	}
+
+	name := qp.Get("bucket")
+	if name == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "bucket or bucketID is required",
+		}
+	}
+	filter := platform.BucketFilter{Name: &name}
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		filter.Org = &org
+	} else {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "org or orgID is required with a bucket name",
+		}
+	}
+	return h.BucketService.FindBucket(ctx, filter)
+}
+
+// DeleteService connects to Influx via HTTP using tokens to delete data.
+type DeleteService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.DeleteService = (*DeleteService)(nil)
+
+// Delete deletes the data selected by the request.
+func (s *DeleteService) Delete(ctx context.Context, req platform.DeleteRequest) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, deletePath)
+	if err != nil {
+		return err
+	}
+	qp := url.Values{}
+	qp.Set("bucketID", req.BucketID.String())
+	u.RawQuery = qp.Encode()
+
+	octets, err := json.Marshal(deleteRequestBody{
+		Start:       req.Start,
+		Stop:        req.Stop,
+		Measurement: req.Measurement,
+		Tags:        req.Tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, r)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(r.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp)
+}
@@ -0,0 +1,173 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newDeleteTestHandler(ds platform.DeleteService) *DeleteHandler {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		if id != 1 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		return &platform.Bucket{ID: id, OrgID: 2, Name: "b"}, nil
+	}
+	bs.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+		if *filter.Name != "b" || (filter.Org == nil || *filter.Org != "o") && (filter.OrganizationID == nil || *filter.OrganizationID != 2) {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		return &platform.Bucket{ID: 1, OrgID: 2, Name: "b"}, nil
+	}
+
+	return NewDeleteHandler(&DeleteBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zap.NewNop(),
+		DeleteService:    ds,
+		BucketService:    bs,
+	})
+}
+
+func TestDeleteHandler_handleDelete(t *testing.T) {
+	var gotReq *platform.DeleteRequest
+	ds := mock.NewDeleteService()
+	ds.DeleteFn = func(ctx context.Context, req platform.DeleteRequest) error {
+		gotReq = &req
+		return nil
+	}
+
+	const body = `{"start": "2019-07-01T00:00:00Z", "stop": "2019-07-01T12:00:00Z", "measurement": "cpu", "tags": [{"key": "host", "value": "bad"}]}`
+	want := &platform.DeleteRequest{
+		OrgID:       2,
+		BucketID:    1,
+		Start:       time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+		Stop:        time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC),
+		Measurement: "cpu",
+		Tags:        []platform.SeriesTagPredicate{{Key: "host", Value: "bad"}},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "delete by bucket ID",
+			query:      "bucketID=0000000000000001",
+			body:       body,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "delete by bucket and organization name",
+			query:      "bucket=b&org=o",
+			body:       body,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "delete by bucket name and organization ID",
+			query:      "bucket=b&orgID=0000000000000002",
+			body:       body,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "bucket name without organization",
+			query:      "bucket=b",
+			body:       body,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "bucket not found",
+			query:      "bucketID=0000000000000009",
+			body:       body,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "missing stop",
+			query:      "bucketID=0000000000000001",
+			body:       `{"start": "2019-07-01T00:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "stop before start",
+			query:      "bucketID=0000000000000001",
+			body:       `{"start": "2019-07-01T12:00:00Z", "stop": "2019-07-01T00:00:00Z"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid tag predicate operator",
+			query:      "bucketID=0000000000000001",
+			body:       `{"start": "2019-07-01T00:00:00Z", "stop": "2019-07-01T12:00:00Z", "tags": [{"key": "host", "op": "<", "value": "a"}]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotReq = nil
+			h := newDeleteTestHandler(ds)
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/delete?"+tt.query, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusNoContent {
+				if gotReq != nil {
+					t.Fatal("no data should be deleted for an invalid request")
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(gotReq, want) {
+				t.Errorf("got request %+v, want %+v", gotReq, want)
+			}
+		})
+	}
+}
+
+func TestDeleteService_Client(t *testing.T) {
+	var gotReq platform.DeleteRequest
+	ds := mock.NewDeleteService()
+	ds.DeleteFn = func(ctx context.Context, req platform.DeleteRequest) error {
+		gotReq = req
+		return nil
+	}
+
+	server := httptest.NewServer(newDeleteTestHandler(ds))
+	defer server.Close()
+	client := DeleteService{Addr: server.URL}
+
+	req := platform.DeleteRequest{
+		OrgID:    2,
+		BucketID: 1,
+		Start:    time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+		Stop:     time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC),
+		Tags:     []platform.SeriesTagPredicate{{Key: "host", Op: platform.TagPredicateRegex, Value: "^bad-"}},
+	}
+	if err := client.Delete(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotReq, req) {
+		t.Errorf("got request %+v, want %+v", gotReq, req)
+	}
+
+	req.BucketID = 9
+	if err := client.Delete(context.Background(), req); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	dropSeriesPath   = "/api/v2/dropseries"
+	dropSeriesIDPath = "/api/v2/dropseries/:id"
+)
+
+// DropSeriesBackend is all services and associated parameters required to construct
+// the DropSeriesHandler.
+type DropSeriesBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DropSeriesService platform.DropSeriesService
+	BucketService     platform.BucketService
+}
+
+// NewDropSeriesBackend returns a new instance of DropSeriesBackend.
+func NewDropSeriesBackend(b *APIBackend) *DropSeriesBackend {
+	return &DropSeriesBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "drop_series")),
+
+		DropSeriesService: b.DropSeriesService,
+		BucketService:     b.BucketService,
+	}
+}
+
+// DropSeriesHandler is the handler for dropping series from buckets.
+type DropSeriesHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	DropSeriesService platform.DropSeriesService
+	BucketService     platform.BucketService
+}
+
+// NewDropSeriesHandler returns a new instance of DropSeriesHandler.
+func NewDropSeriesHandler(b *DropSeriesBackend) *DropSeriesHandler {
+	h := &DropSeriesHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		DropSeriesService: b.DropSeriesService,
+		BucketService:     b.BucketService,
+	}
+
+	h.HandlerFunc("POST", dropSeriesPath, h.handlePostDropSeries)
+	h.HandlerFunc("GET", dropSeriesPath, h.handleGetDropSeriesOperations)
+	h.HandlerFunc("GET", dropSeriesIDPath, h.handleGetDropSeriesOperation)
+	return h
+}
+
+type dropSeriesOperationResponse struct {
+	Links map[string]string `json:"links"`
+	*platform.DropSeriesOperation
+}
+
+func newDropSeriesOperationResponse(op *platform.DropSeriesOperation) *dropSeriesOperationResponse {
+	return &dropSeriesOperationResponse{
+		Links: map[string]string{
+			"self":   path.Join(dropSeriesPath, op.ID.String()),
+			"bucket": path.Join(bucketsPath, op.BucketID.String()),
+		},
+		DropSeriesOperation: op,
+	}
+}
+
+type dropSeriesOperationsResponse struct {
+	Links      map[string]string              `json:"links"`
+	Operations []*dropSeriesOperationResponse `json:"operations"`
+}
+
+func newDropSeriesOperationsResponse(ops []*platform.DropSeriesOperation) *dropSeriesOperationsResponse {
+	res := &dropSeriesOperationsResponse{
+		Links: map[string]string{
+			"self": dropSeriesPath,
+		},
+		Operations: make([]*dropSeriesOperationResponse, 0, len(ops)),
+	}
+	for _, op := range ops {
+		res.Operations = append(res.Operations, newDropSeriesOperationResponse(op))
+	}
+	return res
+}
+
+// handlePostDropSeries is the HTTP handler for the POST /api/v2/dropseries route.
+// The series are dropped asynchronously; the response is the handle of the operation.
+func (h *DropSeriesHandler) handlePostDropSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostDropSeriesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// Finding the bucket checks that it exists and resolves its organization.
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	req.OrgID = b.OrgID
+
+	op, err := h.DropSeriesService.DropSeries(ctx, *req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("drop series queued", zap.String("operationID", op.ID.String()), zap.String("bucketID", b.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusAccepted, newDropSeriesOperationResponse(op)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostDropSeriesRequest(ctx context.Context, r *http.Request) (*platform.DropSeriesRequest, error) {
+	req := &platform.DropSeriesRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// handleGetDropSeriesOperations is the HTTP handler for the GET /api/v2/dropseries route.
+func (h *DropSeriesHandler) handleGetDropSeriesOperations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetDropSeriesOperationsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ops, err := h.DropSeriesService.FindDropSeriesOperations(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDropSeriesOperationsResponse(ops)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetDropSeriesOperationsRequest(ctx context.Context, r *http.Request) (*platform.DropSeriesOperationFilter, error) {
+	qp := r.URL.Query()
+	filter := &platform.DropSeriesOperationFilter{}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		filter.OrgID = id
+	}
+
+	if bucketID := qp.Get("bucketID"); bucketID != "" {
+		id, err := platform.IDFromString(bucketID)
+		if err != nil {
+			return nil, err
+		}
+		filter.BucketID = id
+	}
+
+	return filter, nil
+}
+
+// handleGetDropSeriesOperation is the HTTP handler for the GET /api/v2/dropseries/:id route.
+func (h *DropSeriesHandler) handleGetDropSeriesOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	op, err := h.DropSeriesService.FindDropSeriesOperationByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDropSeriesOperationResponse(op)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// DropSeriesService connects to Influx via HTTP using tokens to drop series.
+type DropSeriesService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.DropSeriesService = (*DropSeriesService)(nil)
+
+// DropSeries starts dropping the series selected by the request and returns the handle of the operation.
+func (s *DropSeriesService) DropSeries(ctx context.Context, req platform.DropSeriesRequest) (*platform.DropSeriesOperation, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var op platform.DropSeriesOperation
+	if err := s.client().do(ctx, "POST", dropSeriesPath, nil, req, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// FindDropSeriesOperationByID returns a single operation by ID.
+func (s *DropSeriesService) FindDropSeriesOperationByID(ctx context.Context, id platform.ID) (*platform.DropSeriesOperation, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var op platform.DropSeriesOperation
+	if err := s.client().do(ctx, "GET", path.Join(dropSeriesPath, id.String()), nil, nil, &op); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// FindDropSeriesOperations returns the operations that match filter, most recent first.
+func (s *DropSeriesService) FindDropSeriesOperations(ctx context.Context, filter platform.DropSeriesOperationFilter) ([]*platform.DropSeriesOperation, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	qp := url.Values{}
+	if filter.OrgID != nil {
+		qp.Set("orgID", filter.OrgID.String())
+	}
+	if filter.BucketID != nil {
+		qp.Set("bucketID", filter.BucketID.String())
+	}
+
+	var res struct {
+		Operations []*platform.DropSeriesOperation `json:"operations"`
+	}
+	if err := s.client().do(ctx, "GET", dropSeriesPath, qp, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Operations, nil
+}
+
+func (s *DropSeriesService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
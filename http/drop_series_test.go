@@ -0,0 +1,192 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newDropSeriesTestHandler(ds platform.DropSeriesService) *DropSeriesHandler {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		if id != 1 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		}
+		return &platform.Bucket{ID: id, OrgID: 2, Name: "b"}, nil
+	}
+
+	return NewDropSeriesHandler(&DropSeriesBackend{
+		HTTPErrorHandler:  ErrorHandler(0),
+		Logger:            zap.NewNop(),
+		DropSeriesService: ds,
+		BucketService:     bs,
+	})
+}
+
+func TestDropSeriesHandler_handlePostDropSeries(t *testing.T) {
+	createdAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	var gotReq *platform.DropSeriesRequest
+	ds := mock.NewDropSeriesService()
+	ds.DropSeriesFn = func(ctx context.Context, req platform.DropSeriesRequest) (*platform.DropSeriesOperation, error) {
+		gotReq = &req
+		return &platform.DropSeriesOperation{
+			ID:                3,
+			DropSeriesRequest: req,
+			Status:            platform.DropSeriesStatusQueued,
+			CreatedAt:         createdAt,
+		}, nil
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "drop series of decommissioned hosts",
+			body:       `{"bucketID": "0000000000000001", "measurement": "cpu", "tags": [{"key": "host", "op": "=~", "value": "^old-"}]}`,
+			wantStatus: http.StatusAccepted,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/dropseries/0000000000000003",
+    "bucket": "/api/v2/buckets/0000000000000001"
+  },
+  "id": "0000000000000003",
+  "orgID": "0000000000000002",
+  "bucketID": "0000000000000001",
+  "measurement": "cpu",
+  "tags": [{"key": "host", "op": "=~", "value": "^old-"}],
+  "status": "queued",
+  "createdAt": "2019-07-01T12:00:00Z"
+}`,
+		},
+		{
+			name:       "missing measurement",
+			body:       `{"bucketID": "0000000000000001"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid regular expression",
+			body:       `{"bucketID": "0000000000000001", "measurement": "cpu", "tags": [{"key": "host", "op": "=~", "value": "("}]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "bucket not found",
+			body:       `{"bucketID": "0000000000000009", "measurement": "cpu"}`,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotReq = nil
+			h := newDropSeriesTestHandler(ds)
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/dropseries", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusAccepted {
+				if gotReq != nil {
+					t.Fatal("no series should be dropped for an invalid request")
+				}
+				return
+			}
+
+			if gotReq.OrgID != 2 {
+				t.Errorf("expected the organization of the bucket, got %v", gotReq.OrgID)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+				t.Errorf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("***%s***", diff)
+			}
+		})
+	}
+}
+
+func TestDropSeriesService_Client(t *testing.T) {
+	createdAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	op := &platform.DropSeriesOperation{
+		ID: 3,
+		DropSeriesRequest: platform.DropSeriesRequest{
+			OrgID:       2,
+			BucketID:    1,
+			Measurement: "cpu",
+			Tags:        []platform.SeriesTagPredicate{{Key: "host", Value: "old-1"}},
+		},
+		Status:     platform.DropSeriesStatusSuccess,
+		CreatedAt:  createdAt,
+		StartedAt:  &createdAt,
+		FinishedAt: &createdAt,
+	}
+
+	var gotFilter platform.DropSeriesOperationFilter
+	ds := mock.NewDropSeriesService()
+	ds.DropSeriesFn = func(ctx context.Context, req platform.DropSeriesRequest) (*platform.DropSeriesOperation, error) {
+		return &platform.DropSeriesOperation{ID: 3, DropSeriesRequest: req, Status: platform.DropSeriesStatusQueued, CreatedAt: createdAt}, nil
+	}
+	ds.FindDropSeriesOperationByIDFn = func(ctx context.Context, id platform.ID) (*platform.DropSeriesOperation, error) {
+		if id != op.ID {
+			return nil, platform.ErrDropSeriesOperationNotFound
+		}
+		return op, nil
+	}
+	ds.FindDropSeriesOperationsFn = func(ctx context.Context, filter platform.DropSeriesOperationFilter) ([]*platform.DropSeriesOperation, error) {
+		gotFilter = filter
+		return []*platform.DropSeriesOperation{op}, nil
+	}
+
+	server := httptest.NewServer(newDropSeriesTestHandler(ds))
+	defer server.Close()
+	client := DropSeriesService{Addr: server.URL}
+	ctx := context.Background()
+
+	queued, err := client.DropSeries(ctx, platform.DropSeriesRequest{BucketID: 1, Measurement: "cpu"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued.ID != 3 || queued.OrgID != 2 || queued.Status != platform.DropSeriesStatusQueued {
+		t.Errorf("unexpected queued operation %+v", queued)
+	}
+
+	got, err := client.FindDropSeriesOperationByID(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != platform.DropSeriesStatusSuccess || got.FinishedAt == nil || !got.FinishedAt.Equal(createdAt) || len(got.Tags) != 1 {
+		t.Errorf("unexpected operation %+v", got)
+	}
+
+	if _, err := client.FindDropSeriesOperationByID(ctx, 4); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	bucketID := platform.ID(1)
+	ops, err := client.FindDropSeriesOperations(ctx, platform.DropSeriesOperationFilter{BucketID: &bucketID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 1 || ops[0].ID != 3 {
+		t.Errorf("unexpected operations %+v", ops)
+	}
+	if gotFilter.BucketID == nil || *gotFilter.BucketID != 1 || gotFilter.OrgID != nil {
+		t.Errorf("unexpected filter %+v", gotFilter)
+	}
+}
@@ -0,0 +1,340 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	fluxPackagesPath   = "/api/v2/flux/packages"
+	fluxPackagesIDPath = "/api/v2/flux/packages/:id"
+)
+
+// FluxPackageBackend is all services and associated parameters required to construct
+// the FluxPackageHandler.
+type FluxPackageBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	FluxPackageService  platform.FluxPackageService
+	OrganizationService platform.OrganizationService
+}
+
+// NewFluxPackageBackend returns a new instance of FluxPackageBackend.
+func NewFluxPackageBackend(b *APIBackend) *FluxPackageBackend {
+	return &FluxPackageBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Logger:              b.Logger.With(zap.String("handler", "flux_package")),
+		FluxPackageService:  b.FluxPackageService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// FluxPackageHandler represents an HTTP API handler for flux packages.
+type FluxPackageHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	FluxPackageService  platform.FluxPackageService
+	OrganizationService platform.OrganizationService
+}
+
+// NewFluxPackageHandler returns a new instance of FluxPackageHandler.
+func NewFluxPackageHandler(b *FluxPackageBackend) *FluxPackageHandler {
+	h := &FluxPackageHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		FluxPackageService:  b.FluxPackageService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", fluxPackagesPath, h.handleGetFluxPackages)
+	h.HandlerFunc("POST", fluxPackagesPath, h.handlePostFluxPackage)
+
+	h.HandlerFunc("GET", fluxPackagesIDPath, h.handleGetFluxPackage)
+	h.HandlerFunc("PATCH", fluxPackagesIDPath, h.handlePatchFluxPackage)
+	h.HandlerFunc("DELETE", fluxPackagesIDPath, h.handleDeleteFluxPackage)
+
+	return h
+}
+
+type fluxPackageLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+}
+
+type fluxPackageResponse struct {
+	*platform.FluxPackage
+	Links fluxPackageLinks `json:"links"`
+}
+
+func newFluxPackageResponse(fp *platform.FluxPackage) fluxPackageResponse {
+	return fluxPackageResponse{
+		FluxPackage: fp,
+		Links: fluxPackageLinks{
+			Self: fluxPackageIDPath(fp.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", fp.OrganizationID),
+		},
+	}
+}
+
+type fluxPackagesResponse struct {
+	Links        map[string]string     `json:"links"`
+	FluxPackages []fluxPackageResponse `json:"fluxPackages"`
+}
+
+func newFluxPackagesResponse(fps []*platform.FluxPackage) fluxPackagesResponse {
+	res := fluxPackagesResponse{
+		Links: map[string]string{
+			"self": fluxPackagesPath,
+		},
+		FluxPackages: make([]fluxPackageResponse, 0, len(fps)),
+	}
+	for _, fp := range fps {
+		res.FluxPackages = append(res.FluxPackages, newFluxPackageResponse(fp))
+	}
+	return res
+}
+
+func fluxPackageIDPath(id platform.ID) string {
+	return path.Join(fluxPackagesPath, id.String())
+}
+
+// handleGetFluxPackages is the HTTP handler for the GET /api/v2/flux/packages route.
+func (h *FluxPackageHandler) handleGetFluxPackages(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := h.decodeGetFluxPackagesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	fps, err := h.FluxPackageService.FindFluxPackages(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newFluxPackagesResponse(fps)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *FluxPackageHandler) decodeGetFluxPackagesRequest(ctx context.Context, r *http.Request) (*platform.FluxPackageFilter, error) {
+	qp := r.URL.Query()
+	filter := &platform.FluxPackageFilter{}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	return filter, nil
+}
+
+// handlePostFluxPackage is the HTTP handler for the POST /api/v2/flux/packages route.
+func (h *FluxPackageHandler) handlePostFluxPackage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	fp, err := decodePostFluxPackageRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.FluxPackageService.CreateFluxPackage(ctx, fp); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newFluxPackageResponse(fp)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostFluxPackageRequest(ctx context.Context, r *http.Request) (*platform.FluxPackage, error) {
+	fp := &platform.FluxPackage{}
+	if err := json.NewDecoder(r.Body).Decode(fp); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := fp.Valid(); err != nil {
+		return nil, err
+	}
+
+	return fp, nil
+}
+
+// handleGetFluxPackage is the HTTP handler for the GET /api/v2/flux/packages/:id route.
+func (h *FluxPackageHandler) handleGetFluxPackage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	fp, err := h.FluxPackageService.FindFluxPackageByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newFluxPackageResponse(fp)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchFluxPackage is the HTTP handler for the PATCH /api/v2/flux/packages/:id route.
+func (h *FluxPackageHandler) handlePatchFluxPackage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	upd := platform.FluxPackageUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := upd.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	fp, err := h.FluxPackageService.UpdateFluxPackage(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newFluxPackageResponse(fp)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteFluxPackage is the HTTP handler for the DELETE /api/v2/flux/packages/:id route.
+func (h *FluxPackageHandler) handleDeleteFluxPackage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.FluxPackageService.DeleteFluxPackage(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// FluxPackageService connects to Influx via HTTP using tokens to manage flux packages.
+type FluxPackageService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.FluxPackageService = (*FluxPackageService)(nil)
+
+// FindFluxPackageByID returns a single flux package by ID.
+func (s *FluxPackageService) FindFluxPackageByID(ctx context.Context, id platform.ID) (*platform.FluxPackage, error) {
+	var res fluxPackageResponse
+	if err := s.client().do(ctx, "GET", fluxPackageIDPath(id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.FluxPackage, nil
+}
+
+// FindFluxPackages returns all flux packages that match the filter.
+func (s *FluxPackageService) FindFluxPackages(ctx context.Context, filter platform.FluxPackageFilter) ([]*platform.FluxPackage, error) {
+	query := url.Values{}
+	if filter.OrganizationID != nil {
+		query.Set("orgID", filter.OrganizationID.String())
+	}
+	if filter.Name != nil {
+		query.Set("name", *filter.Name)
+	}
+
+	var res fluxPackagesResponse
+	if err := s.client().do(ctx, "GET", fluxPackagesPath, query, nil, &res); err != nil {
+		return nil, err
+	}
+
+	fps := make([]*platform.FluxPackage, 0, len(res.FluxPackages))
+	for _, fp := range res.FluxPackages {
+		fps = append(fps, fp.FluxPackage)
+	}
+	return fps, nil
+}
+
+// CreateFluxPackage creates a new flux package and sets fp.ID with the new identifier.
+func (s *FluxPackageService) CreateFluxPackage(ctx context.Context, fp *platform.FluxPackage) error {
+	var res fluxPackageResponse
+	if err := s.client().do(ctx, "POST", fluxPackagesPath, nil, fp, &res); err != nil {
+		return err
+	}
+	*fp = *res.FluxPackage
+	return nil
+}
+
+// UpdateFluxPackage updates a single flux package with a changeset.
+func (s *FluxPackageService) UpdateFluxPackage(ctx context.Context, id platform.ID, upd platform.FluxPackageUpdate) (*platform.FluxPackage, error) {
+	var res fluxPackageResponse
+	if err := s.client().do(ctx, "PATCH", fluxPackageIDPath(id), nil, upd, &res); err != nil {
+		return nil, err
+	}
+	return res.FluxPackage, nil
+}
+
+// DeleteFluxPackage removes a flux package by ID.
+func (s *FluxPackageService) DeleteFluxPackage(ctx context.Context, id platform.ID) error {
+	return s.client().do(ctx, "DELETE", fluxPackageIDPath(id), nil, nil, nil)
+}
+
+func (s *FluxPackageService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
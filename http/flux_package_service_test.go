@@ -0,0 +1,64 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestFluxPackageHandler_handlePostFluxPackage(t *testing.T) {
+	newHandler := func(s platform.FluxPackageService) *FluxPackageHandler {
+		return NewFluxPackageHandler(&FluxPackageBackend{
+			HTTPErrorHandler:   ErrorHandler(0),
+			Logger:             zap.NewNop(),
+			FluxPackageService: s,
+		})
+	}
+
+	t.Run("creates the package", func(t *testing.T) {
+		s := mock.NewFluxPackageService()
+		s.CreateFluxPackageFn = func(ctx context.Context, p *platform.FluxPackage) error {
+			p.ID = 1
+			return nil
+		}
+
+		body := `{"orgID":"0000000000000002","name":"acme/alerts","source":"package alerts\n\nlevel = \"crit\""}`
+		r := httptest.NewRequest("POST", "/api/v2/flux/packages", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		newHandler(s).ServeHTTP(w, r)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+		}
+
+		var res fluxPackageResponse
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Name != "acme/alerts" || res.Links.Self != "/api/v2/flux/packages/0000000000000001" {
+			t.Fatalf("unexpected response: %+v", res)
+		}
+	})
+
+	t.Run("rejects a package clause that does not match the name", func(t *testing.T) {
+		s := mock.NewFluxPackageService()
+		s.CreateFluxPackageFn = func(ctx context.Context, p *platform.FluxPackage) error {
+			t.Fatal("the package must not be created")
+			return nil
+		}
+
+		body := `{"orgID":"0000000000000002","name":"acme/alerts","source":"package other"}`
+		r := httptest.NewRequest("POST", "/api/v2/flux/packages", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		newHandler(s).ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,551 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/graphql"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	graphQLPath = "/api/v2/graphql"
+
+	// graphQLMaxDepth is how deeply the fields of a query may be nested,
+	// which bounds the number of fetches a single query may make.
+	graphQLMaxDepth = 6
+	// graphQLDefaultRunsLimit is the number of runs of a task listed by default.
+	graphQLDefaultRunsLimit = 10
+)
+
+// GraphQLBackend is all services and associated parameters required to construct
+// the GraphQLHandler.
+type GraphQLBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	OrganizationService influxdb.OrganizationService
+	BucketService       influxdb.BucketService
+	DashboardService    influxdb.DashboardService
+	TaskService         influxdb.TaskService
+}
+
+// NewGraphQLBackend returns a new instance of GraphQLBackend.
+func NewGraphQLBackend(b *APIBackend) *GraphQLBackend {
+	return &GraphQLBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "graphql")),
+
+		OrganizationService: b.OrganizationService,
+		BucketService:       b.BucketService,
+		DashboardService:    b.DashboardService,
+		TaskService:         b.TaskService,
+	}
+}
+
+// GraphQLHandler serves GraphQL queries over the organizations, buckets,
+// dashboards and tasks of the platform. Each field is resolved with the
+// services of the backend, which authorize every resource it reads, so a
+// field the request may not read is null with an unauthorized error while
+// the rest of the query is answered.
+type GraphQLHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	OrganizationService influxdb.OrganizationService
+	BucketService       influxdb.BucketService
+	DashboardService    influxdb.DashboardService
+	TaskService         influxdb.TaskService
+
+	schema *graphql.Schema
+}
+
+// NewGraphQLHandler returns a new instance of GraphQLHandler.
+func NewGraphQLHandler(b *GraphQLBackend) *GraphQLHandler {
+	h := &GraphQLHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		OrganizationService: b.OrganizationService,
+		BucketService:       b.BucketService,
+		DashboardService:    b.DashboardService,
+		TaskService:         b.TaskService,
+	}
+
+	schema, err := graphql.NewSchema(h.queryType())
+	if err != nil {
+		// The schema is static, so this is a programming error.
+		panic(fmt.Sprintf("invalid graphql schema: %v", err))
+	}
+	h.schema = schema
+
+	h.HandlerFunc("GET", graphQLPath, h.handleGraphQL)
+	h.HandlerFunc("POST", graphQLPath, h.handleGraphQL)
+	return h
+}
+
+// graphQLRequest is a GraphQL query, which is the body of a POST request or
+// the query parameters of a GET request, where the variables are JSON.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// handleGraphQL is the HTTP handler for the GET and POST /api/v2/graphql routes.
+// The response of a query that could be decoded is always OK, and lists the
+// errors of the query and of its fields.
+func (h *GraphQLHandler) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGraphQLRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := graphql.Execute(ctx, h.schema, graphql.Params{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+		MaxDepth:      graphQLMaxDepth,
+	})
+	for _, e := range res.Errors {
+		if e.Err == nil {
+			continue
+		}
+		if _, ok := e.Err.(*influxdb.Error); ok {
+			e.Message = influxdb.ErrorMessage(e.Err)
+		}
+		e.Extensions = map[string]interface{}{"code": influxdb.ErrorCode(e.Err)}
+	}
+
+	h.Logger.Debug("graphql query executed", zap.String("operation", req.OperationName), zap.Int("errors", len(res.Errors)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGraphQLRequest(ctx context.Context, r *http.Request) (*graphQLRequest, error) {
+	req := &graphQLRequest{}
+	if r.Method == "GET" {
+		qp := r.URL.Query()
+		req.Query = qp.Get("query")
+		req.OperationName = qp.Get("operationName")
+		if s := qp.Get("variables"); s != "" {
+			if err := json.Unmarshal([]byte(s), &req.Variables); err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "variables must be a JSON object",
+					Err:  err,
+				}
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	if req.Query == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "query is required",
+		}
+	}
+	return req, nil
+}
+
+// queryType returns the root type of the schema of the handler.
+func (h *GraphQLHandler) queryType() *graphql.Object {
+	org := &graphql.Object{
+		Name: "Organization",
+		Fields: map[string]*graphql.FieldDef{
+			"id":          {Type: graphql.NewNonNull(graphql.ID)},
+			"name":        {Type: graphql.NewNonNull(graphql.String)},
+			"description": {Type: graphql.String},
+			"timezone":    {Type: graphql.String},
+		},
+	}
+	bucket := &graphql.Object{
+		Name: "Bucket",
+		Fields: map[string]*graphql.FieldDef{
+			"id":          {Type: graphql.NewNonNull(graphql.ID)},
+			"orgID":       {Type: graphql.NewNonNull(graphql.ID)},
+			"name":        {Type: graphql.NewNonNull(graphql.String)},
+			"description": {Type: graphql.String},
+			"retentionSeconds": {
+				Type:        graphql.NewNonNull(graphql.Float),
+				Description: "How long the data of the bucket is kept, or 0 to keep it forever.",
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*influxdb.Bucket).RetentionPeriod.Seconds(), nil
+				},
+			},
+			"organization": h.organizationOfField(org, func(src interface{}) influxdb.ID {
+				return src.(*influxdb.Bucket).OrgID
+			}),
+		},
+	}
+	dashboard := &graphql.Object{
+		Name: "Dashboard",
+		Fields: map[string]*graphql.FieldDef{
+			"id":          {Type: graphql.NewNonNull(graphql.ID)},
+			"orgID":       {Type: graphql.NewNonNull(graphql.ID)},
+			"name":        {Type: graphql.NewNonNull(graphql.String)},
+			"description": {Type: graphql.String},
+			"createdAt": {
+				Type: graphql.String,
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					return graphQLTime(p.Source.(*influxdb.Dashboard).Meta.CreatedAt), nil
+				},
+			},
+			"updatedAt": {
+				Type: graphql.String,
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					return graphQLTime(p.Source.(*influxdb.Dashboard).Meta.UpdatedAt), nil
+				},
+			},
+			"organization": h.organizationOfField(org, func(src interface{}) influxdb.ID {
+				return src.(*influxdb.Dashboard).OrganizationID
+			}),
+		},
+	}
+	log := &graphql.Object{
+		Name: "Log",
+		Fields: map[string]*graphql.FieldDef{
+			"runID":   {Type: graphql.ID},
+			"time":    {Type: graphql.NewNonNull(graphql.String)},
+			"message": {Type: graphql.NewNonNull(graphql.String)},
+		},
+	}
+	run := &graphql.Object{
+		Name: "Run",
+		Fields: map[string]*graphql.FieldDef{
+			"id":           {Type: graphql.NewNonNull(graphql.ID)},
+			"taskID":       {Type: graphql.NewNonNull(graphql.ID)},
+			"status":       {Type: graphql.NewNonNull(graphql.String)},
+			"scheduledFor": {Type: graphql.String},
+			"startedAt":    {Type: graphql.String},
+			"finishedAt":   {Type: graphql.String},
+			"requestedAt":  {Type: graphql.String},
+			"annotation":   {Type: graphql.String},
+			"logs": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(log))),
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					run := p.Source.(*influxdb.Run)
+					logs, _, err := h.TaskService.FindLogs(ctx, influxdb.LogFilter{Task: run.TaskID, Run: &run.ID})
+					return logs, err
+				},
+			},
+		},
+	}
+	task := &graphql.Object{
+		Name: "Task",
+		Fields: map[string]*graphql.FieldDef{
+			"id":              {Type: graphql.NewNonNull(graphql.ID)},
+			"orgID":           {Type: graphql.NewNonNull(graphql.ID)},
+			"org":             {Type: graphql.String},
+			"name":            {Type: graphql.NewNonNull(graphql.String)},
+			"description":     {Type: graphql.String},
+			"status":          {Type: graphql.NewNonNull(graphql.String)},
+			"flux":            {Type: graphql.NewNonNull(graphql.String)},
+			"every":           {Type: graphql.String},
+			"cron":            {Type: graphql.String},
+			"offset":          {Type: graphql.String},
+			"at":              {Type: graphql.String},
+			"latestCompleted": {Type: graphql.String},
+			"createdAt":       {Type: graphql.String},
+			"updatedAt":       {Type: graphql.String},
+			"runs": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(run))),
+				Description: "The latest runs of the task.",
+				Args: map[string]*graphql.ArgDef{
+					"limit":      {Type: graphql.Int, Default: graphQLDefaultRunsLimit},
+					"afterTime":  {Type: graphql.String},
+					"beforeTime": {Type: graphql.String},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					limit, err := graphQLLimit(p.Args, influxdb.TaskMaxPageSize)
+					if err != nil {
+						return nil, err
+					}
+					filter := influxdb.RunFilter{Task: p.Source.(*influxdb.Task).ID, Limit: limit}
+					filter.AfterTime, _ = p.Args["afterTime"].(string)
+					filter.BeforeTime, _ = p.Args["beforeTime"].(string)
+					runs, _, err := h.TaskService.FindRuns(ctx, filter)
+					return runs, err
+				},
+			},
+			"organization": h.organizationOfField(org, func(src interface{}) influxdb.ID {
+				return src.(*influxdb.Task).OrganizationID
+			}),
+		},
+	}
+
+	buckets := h.bucketsField(bucket)
+	dashboards := h.dashboardsField(dashboard)
+	tasks := h.tasksField(task)
+	// The resources of an organization are those of the root fields, for the
+	// organization.
+	org.Fields["buckets"] = graphQLFieldOfOrg(buckets)
+	org.Fields["dashboards"] = graphQLFieldOfOrg(dashboards)
+	org.Fields["tasks"] = graphQLFieldOfOrg(tasks)
+
+	return &graphql.Object{
+		Name: "Query",
+		Fields: map[string]*graphql.FieldDef{
+			"organizations": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(org))),
+				Args: graphQLPagingArgs(),
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					opts, err := graphQLFindOptions(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					orgs, _, err := h.OrganizationService.FindOrganizations(ctx, influxdb.OrganizationFilter{}, opts)
+					return orgs, err
+				},
+			},
+			"organization": {
+				Type: org,
+				Args: map[string]*graphql.ArgDef{
+					"id":   {Type: graphql.ID},
+					"name": {Type: graphql.String},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					filter := influxdb.OrganizationFilter{}
+					id, err := graphQLIDArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					filter.ID = id
+					if name, ok := p.Args["name"].(string); ok {
+						filter.Name = &name
+					}
+					if filter.ID == nil && filter.Name == nil {
+						return nil, &influxdb.Error{
+							Code: influxdb.EInvalid,
+							Msg:  "organization requires an id or a name",
+						}
+					}
+					return h.OrganizationService.FindOrganization(ctx, filter)
+				},
+			},
+			"buckets": buckets,
+			"bucket": {
+				Type: bucket,
+				Args: map[string]*graphql.ArgDef{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					id, err := graphQLIDArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					return h.BucketService.FindBucketByID(ctx, *id)
+				},
+			},
+			"dashboards": dashboards,
+			"dashboard": {
+				Type: dashboard,
+				Args: map[string]*graphql.ArgDef{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					id, err := graphQLIDArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					return h.DashboardService.FindDashboardByID(ctx, *id)
+				},
+			},
+			"tasks": tasks,
+			"task": {
+				Type: task,
+				Args: map[string]*graphql.ArgDef{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					id, err := graphQLIDArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+					return h.TaskService.FindTaskByID(ctx, *id)
+				},
+			},
+		},
+	}
+}
+
+// organizationOfField returns the field of the organization of a resource,
+// whose ID is returned by orgID.
+func (h *GraphQLHandler) organizationOfField(org *graphql.Object, orgID func(src interface{}) influxdb.ID) *graphql.FieldDef {
+	return &graphql.FieldDef{
+		Type: org,
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.OrganizationService.FindOrganizationByID(ctx, orgID(p.Source))
+		},
+	}
+}
+
+// bucketsField returns the field listing buckets, by organization if it has an orgID.
+func (h *GraphQLHandler) bucketsField(bucket *graphql.Object) *graphql.FieldDef {
+	args := graphQLPagingArgs()
+	args["orgID"] = &graphql.ArgDef{Type: graphql.ID}
+	return &graphql.FieldDef{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bucket))),
+		Args: args,
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			opts, err := graphQLFindOptions(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			orgID, err := graphQLIDArg(p.Args, "orgID")
+			if err != nil {
+				return nil, err
+			}
+			buckets, _, err := h.BucketService.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: orgID}, opts)
+			return buckets, err
+		},
+	}
+}
+
+// dashboardsField returns the field listing dashboards, by organization if it has an orgID.
+func (h *GraphQLHandler) dashboardsField(dashboard *graphql.Object) *graphql.FieldDef {
+	args := graphQLPagingArgs()
+	args["orgID"] = &graphql.ArgDef{Type: graphql.ID}
+	return &graphql.FieldDef{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(dashboard))),
+		Args: args,
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			opts, err := graphQLFindOptions(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			orgID, err := graphQLIDArg(p.Args, "orgID")
+			if err != nil {
+				return nil, err
+			}
+			dashboards, _, err := h.DashboardService.FindDashboards(ctx, influxdb.DashboardFilter{OrganizationID: orgID}, opts)
+			return dashboards, err
+		},
+	}
+}
+
+// tasksField returns the field listing tasks, by organization if it has an
+// orgID. Tasks are paged after the ID of a task rather than by an offset.
+func (h *GraphQLHandler) tasksField(task *graphql.Object) *graphql.FieldDef {
+	return &graphql.FieldDef{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(task))),
+		Args: map[string]*graphql.ArgDef{
+			"orgID": {Type: graphql.ID},
+			"after": {Type: graphql.ID},
+			"limit": {Type: graphql.Int, Default: influxdb.TaskDefaultPageSize},
+		},
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			limit, err := graphQLLimit(p.Args, influxdb.TaskMaxPageSize)
+			if err != nil {
+				return nil, err
+			}
+			filter := influxdb.TaskFilter{Limit: limit}
+			if filter.OrganizationID, err = graphQLIDArg(p.Args, "orgID"); err != nil {
+				return nil, err
+			}
+			if filter.After, err = graphQLIDArg(p.Args, "after"); err != nil {
+				return nil, err
+			}
+			tasks, _, err := h.TaskService.FindTasks(ctx, filter)
+			return tasks, err
+		},
+	}
+}
+
+// graphQLFieldOfOrg returns the field of an organization that lists the
+// resources of a root field, which takes the organization from its orgID.
+func graphQLFieldOfOrg(f *graphql.FieldDef) *graphql.FieldDef {
+	args := make(map[string]*graphql.ArgDef, len(f.Args))
+	for name, a := range f.Args {
+		if name != "orgID" {
+			args[name] = a
+		}
+	}
+	return &graphql.FieldDef{
+		Type:        f.Type,
+		Description: f.Description,
+		Args:        args,
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			p.Args["orgID"] = p.Source.(*influxdb.Organization).ID.String()
+			p.Source = nil
+			return f.Resolve(ctx, p)
+		},
+	}
+}
+
+func graphQLPagingArgs() map[string]*graphql.ArgDef {
+	return map[string]*graphql.ArgDef{
+		"limit":  {Type: graphql.Int, Default: influxdb.DefaultPageSize},
+		"offset": {Type: graphql.Int, Default: 0},
+	}
+}
+
+func graphQLFindOptions(args map[string]interface{}) (influxdb.FindOptions, error) {
+	limit, err := graphQLLimit(args, influxdb.MaxPageSize)
+	if err != nil {
+		return influxdb.FindOptions{}, err
+	}
+	offset, _ := args["offset"].(int)
+	if offset < 0 {
+		return influxdb.FindOptions{}, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "offset must not be negative",
+		}
+	}
+	return influxdb.FindOptions{Limit: limit, Offset: offset}, nil
+}
+
+func graphQLLimit(args map[string]interface{}, max int) (int, error) {
+	limit, _ := args["limit"].(int)
+	if limit < 1 || limit > max {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("limit must be between 1 and %d", max),
+		}
+	}
+	return limit, nil
+}
+
+// graphQLIDArg returns the ID of the argument, or nil if it has none.
+func graphQLIDArg(args map[string]interface{}, name string) (*influxdb.ID, error) {
+	s, ok := args[name].(string)
+	if !ok {
+		return nil, nil
+	}
+	var id influxdb.ID
+	if err := id.DecodeFromString(s); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("%s is not a valid ID", name),
+			Err:  err,
+		}
+	}
+	return &id, nil
+}
+
+// graphQLTime formats a time of a resource, which is null if it is not set.
+func graphQLTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newGraphQLTestHandler(t *testing.T) (*GraphQLHandler, *[]platform.RunFilter) {
+	t.Helper()
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationsF = func(ctx context.Context, filter platform.OrganizationFilter, opt ...platform.FindOptions) ([]*platform.Organization, int, error) {
+		return []*platform.Organization{{ID: 2, Name: "o"}}, 1, nil
+	}
+	orgs.FindOrganizationF = func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+		return &platform.Organization{ID: *filter.ID, Name: "o"}, nil
+	}
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: id, OrgID: 3, Name: "other"}, nil
+	}
+	buckets.FindBucketsFn = func(ctx context.Context, filter platform.BucketFilter, opt ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+		return []*platform.Bucket{{ID: 1, OrgID: *filter.OrganizationID, Name: "b"}}, 1, nil
+	}
+
+	var runFilters []platform.RunFilter
+	tasks := &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, filter platform.TaskFilter) ([]*platform.Task, int, error) {
+			return []*platform.Task{{ID: 4, OrganizationID: *filter.OrganizationID, Name: "t", Status: "active"}}, 1, nil
+		},
+		FindRunsFn: func(ctx context.Context, filter platform.RunFilter) ([]*platform.Run, int, error) {
+			runFilters = append(runFilters, filter)
+			return []*platform.Run{{ID: 5, TaskID: filter.Task, Status: "failed"}}, 1, nil
+		},
+		FindLogsFn: func(ctx context.Context, filter platform.LogFilter) ([]*platform.Log, int, error) {
+			return []*platform.Log{{RunID: *filter.Run, Time: "2019-01-01T00:00:00Z", Message: "error"}}, 1, nil
+		},
+	}
+
+	h := NewGraphQLHandler(&GraphQLBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		OrganizationService: orgs,
+		BucketService:       authorizer.NewBucketService(buckets),
+		DashboardService:    mock.NewDashboardService(),
+		TaskService:         tasks,
+	})
+	return h, &runFilters
+}
+
+func TestGraphQLHandler_handleGraphQL(t *testing.T) {
+	perm, err := platform.NewPermission(platform.ReadAction, platform.BucketsResourceType, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{*perm}}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		wantStatus     int
+		wantBody       string
+		wantRunFilters []platform.RunFilter
+	}{
+		{
+			name:   "nested resources",
+			method: "POST",
+			path:   "/api/v2/graphql",
+			body: `{
+				"query": "query ($org: ID!) { organization(id: $org) { name buckets { name } tasks { name runs(limit: 2) { id status logs { message } } } } }",
+				"variables": {"org": "0000000000000002"}
+			}`,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "data": {
+    "organization": {
+      "name": "o",
+      "buckets": [{"name": "b"}],
+      "tasks": [
+        {
+          "name": "t",
+          "runs": [{"id": "0000000000000005", "status": "failed", "logs": [{"message": "error"}]}]
+        }
+      ]
+    }
+  }
+}`,
+			wantRunFilters: []platform.RunFilter{{Task: 4, Limit: 2}},
+		},
+		{
+			name:       "unauthorized field",
+			method:     "GET",
+			path:       "/api/v2/graphql?query=" + url.QueryEscape(`{ bucket(id: "0000000000000001") { name } organizations { id } }`),
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "errors": [
+    {
+      "message": "read:orgs/0000000000000003/buckets/0000000000000001 is unauthorized",
+      "locations": [{"line": 1, "column": 3}],
+      "path": ["bucket"],
+      "extensions": {"code": "unauthorized"}
+    }
+  ],
+  "data": {
+    "bucket": null,
+    "organizations": [{"id": "0000000000000002"}]
+  }
+}`,
+		},
+		{
+			name:       "invalid argument",
+			method:     "POST",
+			path:       "/api/v2/graphql",
+			body:       `{"query": "{ task(id: \"x\") { id } }"}`,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "errors": [
+    {
+      "message": "id is not a valid ID",
+      "locations": [{"line": 1, "column": 3}],
+      "path": ["task"],
+      "extensions": {"code": "invalid"}
+    }
+  ],
+  "data": {"task": null}
+}`,
+		},
+		{
+			name:       "invalid query",
+			method:     "POST",
+			path:       "/api/v2/graphql",
+			body:       `{"query": "{ buckets { owner } }"}`,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "errors": [
+    {
+      "message": "unknown field owner of type Bucket",
+      "locations": [{"line": 1, "column": 13}]
+    }
+  ]
+}`,
+		},
+		{
+			name:       "missing query",
+			method:     "POST",
+			path:       "/api/v2/graphql",
+			body:       `{}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code": "invalid", "message": "query is required"}`,
+		},
+		{
+			name:       "invalid variables",
+			method:     "GET",
+			path:       "/api/v2/graphql?query=%7B%20organizations%20%7B%20id%20%7D%20%7D&variables=x",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code": "invalid", "message": "variables must be a JSON object", "error": "invalid character 'x' looking for beginning of value"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, runFilters := newGraphQLTestHandler(t)
+
+			r := httptest.NewRequest(tt.method, "http://any.url"+tt.path, strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), auth))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+				t.Errorf("failed to compare body: %v: %s", err, body)
+			} else if !eq {
+				t.Errorf("unexpected body -got/+want\n%s", diff)
+			}
+			if !reflect.DeepEqual(*runFilters, tt.wantRunFilters) {
+				t.Errorf("got run filters %+v, want %+v", *runFilters, tt.wantRunFilters)
+			}
+		})
+	}
+}
+
+func TestAPIHandler_GraphQLDisabled(t *testing.T) {
+	h := &APIHandler{HTTPErrorHandler: ErrorHandler(0)}
+
+	r := httptest.NewRequest("POST", "http://any.url/api/v2/graphql", strings.NewReader(`{"query": "{ organizations { id } }"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if res := w.Result(); res.StatusCode != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", res.StatusCode, http.StatusNotFound)
+	}
+}
@@ -44,7 +44,7 @@ type Handler struct {
 	Handler http.Handler
 
 	requests   *prometheus.CounterVec
-	requestDur *prometheus.HistogramVec
+	requestDur *prom.HistogramVec
 
 	// Logger if set will log all HTTP requests as they are served
 	Logger *zap.Logger
@@ -109,13 +109,13 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"status":     statusClass,
 			"user_agent": userAgent,
 		}).Inc()
-		h.requestDur.With(prometheus.Labels{
+		prom.ObserveWithTraceID(h.requestDur.With(prometheus.Labels{
 			"handler":    h.name,
 			"method":     r.Method,
 			"path":       r.URL.Path,
 			"status":     statusClass,
 			"user_agent": userAgent,
-		}).Observe(duration.Seconds())
+		}), duration.Seconds(), tracing.TraceID(span))
 		if h.Logger != nil {
 			errField := zap.Skip()
 			if errStr := w.Header().Get(PlatformErrorCodeHeader); errStr != "" {
@@ -178,7 +178,7 @@ func (h *Handler) initMetrics() {
 		Help:      "Number of http requests received",
 	}, []string{"handler", "method", "path", "status", "user_agent"})
 
-	h.requestDur = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	h.requestDur = prom.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: namespace,
 		Subsystem: handlerSubsystem,
 		Name:      "request_duration_seconds",
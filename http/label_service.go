@@ -532,6 +532,109 @@ func decodeDeleteLabelMappingRequest(ctx context.Context, r *http.Request) (*del
 	}, nil
 }
 
+// labelFilter is the names of the labels that every resource listed by a GET
+// to a collection endpoint must carry, as in ?label=prod&label=team-a.
+type labelFilter []string
+
+func decodeLabelFilter(r *http.Request) labelFilter {
+	return labelFilter(r.URL.Query()["label"])
+}
+
+// findOptions returns the options to find resources with. Resources filtered
+// by label are found unpaged, and are paged once filtered, so that pages are
+// neither short nor empty.
+func (f labelFilter) findOptions(opts influxdb.FindOptions) influxdb.FindOptions {
+	if len(f) == 0 {
+		return opts
+	}
+	return opts.Unpaged()
+}
+
+// filter returns the indexes of the n found resources that carry every label
+// of the filter, in the page opts selects. id returns the ID of the found
+// resource at index i.
+func (f labelFilter) filter(ctx context.Context, s influxdb.LabelService, opts influxdb.FindOptions, n int, id func(i int) influxdb.ID) ([]int, error) {
+	labels, err := findResourcesLabels(ctx, s, n, id)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := make([]int, 0, n)
+	for i, ls := range labels {
+		names := make(map[string]bool, len(ls))
+		for _, l := range ls {
+			names[l.Name] = true
+		}
+
+		matched := true
+		for _, name := range f {
+			if !names[name] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			idx = append(idx, i)
+		}
+	}
+
+	lo, hi := opts.Page(len(idx))
+	return idx[lo:hi], nil
+}
+
+// findResourcesLabels returns the labels of each of the n found resources.
+// They are found at once when the label service finds the labels of many
+// resources at once, and by resource otherwise.
+func findResourcesLabels(ctx context.Context, s influxdb.LabelService, n int, id func(i int) influxdb.ID) ([][]*influxdb.Label, error) {
+	labels := make([][]*influxdb.Label, n)
+	if bs, ok := s.(influxdb.LabelMappingBatchService); ok {
+		ids := make([]influxdb.ID, n)
+		for i := range ids {
+			ids[i] = id(i)
+		}
+		m, err := bs.FindResourcesLabels(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for i, id := range ids {
+			labels[i] = m[id]
+		}
+		return labels, nil
+	}
+
+	for i := range labels {
+		ls, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: id(i)})
+		if err != nil {
+			return nil, err
+		}
+		labels[i] = ls
+	}
+	return labels, nil
+}
+
+// pagingFilter returns pf with the label query params of the filter, so that
+// the paging links of filtered resources keep filtering them.
+func (f labelFilter) pagingFilter(pf influxdb.PagingFilter) influxdb.PagingFilter {
+	if len(f) == 0 {
+		return pf
+	}
+	return labeledPagingFilter{PagingFilter: pf, labels: f}
+}
+
+type labeledPagingFilter struct {
+	influxdb.PagingFilter
+	labels labelFilter
+}
+
+func (f labeledPagingFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	for k, vs := range f.PagingFilter.QueryParams() {
+		qp[k] = vs
+	}
+	qp["label"] = f.labels
+	return qp
+}
+
 func labelIDPath(id influxdb.ID) string {
 	return path.Join(labelsPath, id.String())
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/influxdata/influxdb/mock"
 	platformtesting "github.com/influxdata/influxdb/testing"
 	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
 )
 
 func TestService_handleGetLabels(t *testing.T) {
@@ -589,3 +590,66 @@ func TestService_handlePatchLabel(t *testing.T) {
 		})
 	}
 }
+
+// batchLabelService finds the labels of many resources at once.
+type batchLabelService struct {
+	*mock.LabelService
+	labels map[platform.ID][]*platform.Label
+	calls  int
+}
+
+func (s *batchLabelService) FindResourcesLabels(ctx context.Context, ids []platform.ID) (map[platform.ID][]*platform.Label, error) {
+	s.calls++
+	m := make(map[platform.ID][]*platform.Label, len(ids))
+	for _, id := range ids {
+		m[id] = s.labels[id]
+	}
+	return m, nil
+}
+
+func TestOrgHandler_handleGetOrgsByLabel(t *testing.T) {
+	orgs := []*platform.Organization{
+		{ID: platformtesting.MustIDBase16("0000000000000001"), Name: "alpha"},
+		{ID: platformtesting.MustIDBase16("0000000000000002"), Name: "beta"},
+		{ID: platformtesting.MustIDBase16("0000000000000003"), Name: "gamma"},
+	}
+	labels := &batchLabelService{
+		LabelService: &mock.LabelService{
+			FindResourceLabelsFn: func(ctx context.Context, f platform.LabelMappingFilter) ([]*platform.Label, error) {
+				t.Errorf("expected the labels of the orgs to be found at once, got a lookup of %s", f.ResourceID)
+				return nil, nil
+			},
+		},
+		labels: map[platform.ID][]*platform.Label{
+			orgs[0].ID: {{Name: "prod"}},
+			orgs[2].ID: {{Name: "prod"}, {Name: "team-a"}},
+		},
+	}
+
+	os := mock.NewOrganizationService()
+	os.FindOrganizationsF = func(ctx context.Context, f platform.OrganizationFilter, opts ...platform.FindOptions) ([]*platform.Organization, int, error) {
+		return orgs, len(orgs), nil
+	}
+	h := NewOrgHandler(&OrgBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		OrganizationService: os,
+		LabelService:        labels,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/orgs?label=prod", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var res orgsResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Organizations) != 2 || res.Organizations[0].Name != "alpha" || res.Organizations[1].Name != "gamma" {
+		t.Errorf("expected the orgs labeled prod, got %+v", res.Organizations)
+	}
+	if labels.calls != 1 {
+		t.Errorf("expected the labels to be found in one lookup, got %d", labels.calls)
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const limitsSimulatePath = "/api/v2/limits/simulate"
+
+// LimitsBackend is all services and associated parameters required to construct
+// the LimitsHandler.
+type LimitsBackend struct {
+	platform.HTTPErrorHandler
+	Logger                  *zap.Logger
+	LimitsSimulationService platform.LimitsSimulationService
+}
+
+// NewLimitsBackend returns a new instance of LimitsBackend.
+func NewLimitsBackend(b *APIBackend) *LimitsBackend {
+	return &LimitsBackend{
+		HTTPErrorHandler:        b.HTTPErrorHandler,
+		Logger:                  b.Logger.With(zap.String("handler", "limits")),
+		LimitsSimulationService: b.LimitsSimulationService,
+	}
+}
+
+// LimitsHandler is the handler for simulations of the limits of organizations.
+type LimitsHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	LimitsSimulationService platform.LimitsSimulationService
+}
+
+// NewLimitsHandler returns a new instance of LimitsHandler.
+func NewLimitsHandler(b *LimitsBackend) *LimitsHandler {
+	h := &LimitsHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		LimitsSimulationService: b.LimitsSimulationService,
+	}
+
+	h.HandlerFunc("POST", limitsSimulatePath, h.handlePostLimitsSimulation)
+	return h
+}
+
+// handlePostLimitsSimulation is the HTTP handler for the POST /api/v2/limits/simulate route.
+func (h *LimitsHandler) handlePostLimitsSimulation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var sim platform.LimitsSimulation
+	if err := json.NewDecoder(r.Body).Decode(&sim); err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := sim.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res, err := h.LimitsSimulationService.SimulateLimits(ctx, sim)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("limits simulated", zap.Int("orgs", len(res.Orgs)), zap.Int("violating", res.Violating))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// LimitsSimulationService connects to Influx via HTTP using tokens to simulate
+// the limits of organizations.
+type LimitsSimulationService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.LimitsSimulationService = (*LimitsSimulationService)(nil)
+
+// SimulateLimits returns the usage of every organization of the remote server against the limits of sim.
+func (s *LimitsSimulationService) SimulateLimits(ctx context.Context, sim platform.LimitsSimulation) (*platform.LimitsSimulationResult, error) {
+	var res platform.LimitsSimulationResult
+	if err := s.client().do(ctx, "POST", limitsSimulatePath, nil, sim, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (s *LimitsSimulationService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
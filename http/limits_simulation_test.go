@@ -0,0 +1,99 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestLimitsHandler_handlePostLimitsSimulation(t *testing.T) {
+	ls := mock.NewLimitsSimulationService()
+	ls.SimulateLimitsFn = func(ctx context.Context, sim platform.LimitsSimulation) (*platform.LimitsSimulationResult, error) {
+		limits := sim.OrgLimits(1)
+		usage := platform.OrgUsage{Series: 1100, PointsPerSecond: 150, ConcurrentQueries: 2}
+		return &platform.LimitsSimulationResult{
+			Orgs: []*platform.OrgLimitsResult{{
+				OrgID:      1,
+				OrgName:    "acme",
+				Limits:     limits,
+				Usage:      usage,
+				Violations: usage.Violations(limits),
+			}},
+			Violating: 1,
+		}, nil
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "limits of every org",
+			body:       `{"limits": {"maxSeries": 1000, "maxConcurrentQueries": 1}}`,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "orgs": [
+    {
+      "orgID": "0000000000000001",
+      "orgName": "acme",
+      "limits": {"maxSeries": 1000, "maxConcurrentQueries": 1},
+      "usage": {"series": 1100, "pointsPerSecond": 150, "concurrentQueries": 2},
+      "violations": [
+        {"limit": "maxSeries", "max": 1000, "usage": 1100},
+        {"limit": "maxConcurrentQueries", "max": 1, "usage": 2}
+      ]
+    }
+  ],
+  "violating": 1
+}`,
+		},
+		{
+			name:       "negative limit",
+			body:       `{"limits": {"maxSeries": -1}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid json",
+			body:       `{"limits": `,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewLimitsHandler(&LimitsBackend{
+				HTTPErrorHandler:        ErrorHandler(0),
+				Logger:                  zap.NewNop(),
+				LimitsSimulationService: ls,
+			})
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/limits/simulate", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+
+			if tt.wantBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+					t.Errorf("error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("***%s***", diff)
+				}
+			}
+		})
+	}
+}
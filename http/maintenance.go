@@ -0,0 +1,225 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	maintenancePath         = "/api/v2/maintenance"
+	maintenanceSchedulePath = "/api/v2/maintenance/schedule"
+)
+
+// MaintenanceBackend is all services and associated parameters required to construct
+// the MaintenanceHandler.
+type MaintenanceBackend struct {
+	platform.HTTPErrorHandler
+	Logger                     *zap.Logger
+	MaintenanceService         platform.MaintenanceService
+	MaintenanceScheduleService platform.MaintenanceScheduleService
+}
+
+// NewMaintenanceBackend returns a new instance of MaintenanceBackend.
+func NewMaintenanceBackend(b *APIBackend) *MaintenanceBackend {
+	return &MaintenanceBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "maintenance")),
+		MaintenanceService:         b.MaintenanceService,
+		MaintenanceScheduleService: b.MaintenanceScheduleService,
+	}
+}
+
+// MaintenanceHandler is the handler for toggling the write and query paths, and
+// for overriding the maintenance windows of heavy background work.
+type MaintenanceHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	MaintenanceService         platform.MaintenanceService
+	MaintenanceScheduleService platform.MaintenanceScheduleService
+}
+
+// NewMaintenanceHandler returns a new instance of MaintenanceHandler.
+func NewMaintenanceHandler(b *MaintenanceBackend) *MaintenanceHandler {
+	h := &MaintenanceHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		MaintenanceService:         b.MaintenanceService,
+		MaintenanceScheduleService: b.MaintenanceScheduleService,
+	}
+
+	h.HandlerFunc("GET", maintenancePath, h.handleGetMaintenance)
+	h.HandlerFunc("PATCH", maintenancePath, h.handlePatchMaintenance)
+
+	h.HandlerFunc("GET", maintenanceSchedulePath, h.handleGetMaintenanceSchedule)
+	h.HandlerFunc("PATCH", maintenanceSchedulePath, h.handlePatchMaintenanceSchedule)
+	return h
+}
+
+// handleGetMaintenance is the HTTP handler for the GET /api/v2/maintenance route.
+func (h *MaintenanceHandler) handleGetMaintenance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	status, err := h.MaintenanceService.MaintenanceStatus(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, status); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchMaintenance is the HTTP handler for the PATCH /api/v2/maintenance route.
+func (h *MaintenanceHandler) handlePatchMaintenance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	upd, err := decodePatchMaintenanceRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	status, err := h.MaintenanceService.UpdateMaintenanceStatus(ctx, *upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, status); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePatchMaintenanceRequest(ctx context.Context, r *http.Request) (*platform.MaintenanceStatusUpdate, error) {
+	upd := &platform.MaintenanceStatusUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(upd); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := upd.Valid(); err != nil {
+		return nil, err
+	}
+
+	return upd, nil
+}
+
+// handleGetMaintenanceSchedule is the HTTP handler for the GET /api/v2/maintenance/schedule route.
+func (h *MaintenanceHandler) handleGetMaintenanceSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schedule, err := h.MaintenanceScheduleService.MaintenanceSchedule(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, schedule); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchMaintenanceSchedule is the HTTP handler for the PATCH /api/v2/maintenance/schedule route.
+func (h *MaintenanceHandler) handlePatchMaintenanceSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	upd, err := decodePatchMaintenanceScheduleRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	schedule, err := h.MaintenanceScheduleService.UpdateMaintenanceSchedule(ctx, *upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, schedule); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePatchMaintenanceScheduleRequest(ctx context.Context, r *http.Request) (*platform.MaintenanceScheduleUpdate, error) {
+	upd := &platform.MaintenanceScheduleUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(upd); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := upd.Valid(); err != nil {
+		return nil, err
+	}
+
+	return upd, nil
+}
+
+// MaintenanceService connects to Influx via HTTP using tokens to manage the maintenance state.
+type MaintenanceService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var (
+	_ platform.MaintenanceService         = (*MaintenanceService)(nil)
+	_ platform.MaintenanceScheduleService = (*MaintenanceService)(nil)
+)
+
+// MaintenanceStatus returns the maintenance state of the remote node.
+func (s *MaintenanceService) MaintenanceStatus(ctx context.Context) (*platform.MaintenanceStatus, error) {
+	var status platform.MaintenanceStatus
+	if err := s.client().do(ctx, "GET", maintenancePath, nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// UpdateMaintenanceStatus updates the maintenance state of the remote node.
+func (s *MaintenanceService) UpdateMaintenanceStatus(ctx context.Context, upd platform.MaintenanceStatusUpdate) (*platform.MaintenanceStatus, error) {
+	var status platform.MaintenanceStatus
+	if err := s.client().do(ctx, "PATCH", maintenancePath, nil, upd, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// MaintenanceSchedule returns the maintenance schedule of the remote node.
+func (s *MaintenanceService) MaintenanceSchedule(ctx context.Context) (*platform.MaintenanceSchedule, error) {
+	var schedule platform.MaintenanceSchedule
+	if err := s.client().do(ctx, "GET", maintenanceSchedulePath, nil, nil, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// UpdateMaintenanceSchedule overrides the maintenance windows of the remote node.
+func (s *MaintenanceService) UpdateMaintenanceSchedule(ctx context.Context, upd platform.MaintenanceScheduleUpdate) (*platform.MaintenanceSchedule, error) {
+	var schedule platform.MaintenanceSchedule
+	if err := s.client().do(ctx, "PATCH", maintenanceSchedulePath, nil, upd, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (s *MaintenanceService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
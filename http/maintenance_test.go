@@ -0,0 +1,250 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestAPIHandler_Maintenance(t *testing.T) {
+	type wants struct {
+		statusCode int
+		body       string
+	}
+
+	tests := []struct {
+		name   string
+		status platform.MaintenanceStatus
+		method string
+		path   string
+		wants  wants
+	}{
+		{
+			name: "writes disabled with default message",
+			status: platform.MaintenanceStatus{
+				Writes: platform.MaintenanceToggle{Disabled: true},
+			},
+			method: "POST",
+			path:   "/api/v2/write?org=o&bucket=b",
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "code": "unavailable",
+  "op": "http/write",
+  "message": "writes are disabled for maintenance"
+}`,
+			},
+		},
+		{
+			name: "deletes disabled with writes",
+			status: platform.MaintenanceStatus{
+				Writes: platform.MaintenanceToggle{Disabled: true},
+			},
+			method: "POST",
+			path:   "/api/v2/delete?org=o&bucket=b",
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "code": "unavailable",
+  "op": "http/write",
+  "message": "writes are disabled for maintenance"
+}`,
+			},
+		},
+		{
+			name: "dropping series disabled with writes",
+			status: platform.MaintenanceStatus{
+				Writes: platform.MaintenanceToggle{Disabled: true},
+			},
+			method: "POST",
+			path:   "/api/v2/dropseries",
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "code": "unavailable",
+  "op": "http/write",
+  "message": "writes are disabled for maintenance"
+}`,
+			},
+		},
+		{
+			name: "queries disabled with custom message",
+			status: platform.MaintenanceStatus{
+				Queries: platform.MaintenanceToggle{Disabled: true, Message: "compacting until 10:00 UTC"},
+			},
+			method: "POST",
+			path:   "/api/v2/query",
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "code": "unavailable",
+  "op": "http/query",
+  "message": "compacting until 10:00 UTC"
+}`,
+			},
+		},
+		{
+			name: "query pages disabled with queries",
+			status: platform.MaintenanceStatus{
+				Queries: platform.MaintenanceToggle{Disabled: true},
+			},
+			method: "POST",
+			path:   "/api/v2/query/pages",
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "code": "unavailable",
+  "op": "http/query",
+  "message": "queries are disabled for maintenance"
+}`,
+			},
+		},
+		{
+			name: "bucket samples disabled with queries",
+			status: platform.MaintenanceStatus{
+				Queries: platform.MaintenanceToggle{Disabled: true},
+			},
+			method: "GET",
+			path:   "/api/v2/buckets/020f755c3c082000/sample",
+			wants: wants{
+				statusCode: http.StatusServiceUnavailable,
+				body: `
+{
+  "code": "unavailable",
+  "op": "http/query",
+  "message": "queries are disabled for maintenance"
+}`,
+			},
+		},
+		{
+			name: "maintenance state stays available",
+			status: platform.MaintenanceStatus{
+				Writes:  platform.MaintenanceToggle{Disabled: true},
+				Queries: platform.MaintenanceToggle{Disabled: true},
+			},
+			method: "GET",
+			path:   "/api/v2/maintenance",
+			wants: wants{
+				statusCode: http.StatusOK,
+				body: `
+{
+  "writes": {
+    "disabled": true
+  },
+  "queries": {
+    "disabled": true
+  }
+}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &APIBackend{
+				HTTPErrorHandler:   ErrorHandler(0),
+				Logger:             zap.NewNop(),
+				MaintenanceService: inmem.NewMaintenanceService(tt.status),
+			}
+			h := NewAPIHandler(b)
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+
+			if res.StatusCode != tt.wants.statusCode {
+				t.Errorf("%q. got %v, want %v", tt.name, res.StatusCode, tt.wants.statusCode)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wants.body); err != nil {
+				t.Errorf("%q, error unmarshaling json %v", tt.name, err)
+			} else if !eq {
+				t.Errorf("%q. ***%s***", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestMaintenanceHandler_Patch(t *testing.T) {
+	svc := inmem.NewMaintenanceService(platform.MaintenanceStatus{})
+	h := NewMaintenanceHandler(&MaintenanceBackend{
+		HTTPErrorHandler:   ErrorHandler(0),
+		Logger:             zap.NewNop(),
+		MaintenanceService: svc,
+	})
+
+	r := httptest.NewRequest("PATCH", "/api/v2/maintenance", bytes.NewBufferString(`{"writes":{"disabled":true,"message":"upgrading storage"}}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	status, err := svc.MaintenanceStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Writes.Disabled || status.Writes.Message != "upgrading storage" {
+		t.Errorf("expected writes to be disabled with message, got %+v", status.Writes)
+	}
+	if status.Queries.Disabled {
+		t.Errorf("expected queries to stay enabled")
+	}
+}
+
+func TestMaintenanceHandler_PatchSchedule(t *testing.T) {
+	var got platform.MaintenanceScheduleUpdate
+	svc := mock.NewMaintenanceScheduleService()
+	svc.UpdateMaintenanceScheduleFn = func(ctx context.Context, upd platform.MaintenanceScheduleUpdate) (*platform.MaintenanceSchedule, error) {
+		got = upd
+		return &platform.MaintenanceSchedule{
+			Windows:  []platform.MaintenanceWindow{{Start: "01:00", End: "05:00"}},
+			Override: upd.Override,
+			Open:     true,
+		}, nil
+	}
+	h := NewMaintenanceHandler(&MaintenanceBackend{
+		HTTPErrorHandler:           ErrorHandler(0),
+		Logger:                     zap.NewNop(),
+		MaintenanceService:         mock.NewMaintenanceService(),
+		MaintenanceScheduleService: svc,
+	})
+
+	r := httptest.NewRequest("PATCH", "/api/v2/maintenance/schedule", bytes.NewBufferString(`{"override":"allow"}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got.Override != platform.MaintenanceOverrideAllow {
+		t.Errorf("got override %q, want %q", got.Override, platform.MaintenanceOverrideAllow)
+	}
+	want := `{"windows":[{"start":"01:00","end":"05:00"}],"override":"allow","open":true}`
+	if eq, diff, _ := jsonEqual(w.Body.String(), want); !eq {
+		t.Errorf("unexpected body -got/+want\n%s", diff)
+	}
+
+	r = httptest.NewRequest("PATCH", "/api/v2/maintenance/schedule", bytes.NewBufferString(`{"override":"sometimes"}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d for an invalid override", w.Code, http.StatusBadRequest)
+	}
+}
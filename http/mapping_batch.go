@@ -0,0 +1,247 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	mappingsBatchPath = "/api/v2/mappings/batch"
+
+	// mappingsBatchMaxOps is the most operations a batch may hold.
+	mappingsBatchMaxOps = 10000
+)
+
+// MappingBatchBackend is all services and associated parameters required to construct
+// the MappingBatchHandler.
+type MappingBatchBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	UserResourceMappingBatchService platform.UserResourceMappingBatchService
+	UserService                     platform.UserService
+}
+
+// NewMappingBatchBackend returns a new instance of MappingBatchBackend.
+func NewMappingBatchBackend(b *APIBackend) *MappingBatchBackend {
+	return &MappingBatchBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "mapping_batch")),
+
+		UserResourceMappingBatchService: b.UserResourceMappingBatchService,
+		UserService:                     b.UserService,
+	}
+}
+
+// MappingBatchHandler is the handler for changing many user resource mappings at once.
+type MappingBatchHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	UserResourceMappingBatchService platform.UserResourceMappingBatchService
+	UserService                     platform.UserService
+}
+
+// NewMappingBatchHandler returns a new instance of MappingBatchHandler.
+func NewMappingBatchHandler(b *MappingBatchBackend) *MappingBatchHandler {
+	h := &MappingBatchHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		UserResourceMappingBatchService: b.UserResourceMappingBatchService,
+		UserService:                     b.UserService,
+	}
+
+	h.HandlerFunc("POST", mappingsBatchPath, h.handlePostMappingsBatch)
+	return h
+}
+
+type mappingBatchRequest struct {
+	Operations []platform.UserResourceMappingOp `json:"operations"`
+}
+
+type mappingResultResponse struct {
+	platform.UserResourceMappingOp
+	Error *platform.Error `json:"error,omitempty"`
+}
+
+// mappingBatchResponse is the response to a batch, whether or not it was applied.
+type mappingBatchResponse struct {
+	Code    string                  `json:"code,omitempty"`
+	Message string                  `json:"message,omitempty"`
+	Applied bool                    `json:"applied"`
+	Results []mappingResultResponse `json:"results"`
+}
+
+func newMappingBatchResponse(rs []platform.UserResourceMappingResult, err error) *mappingBatchResponse {
+	res := &mappingBatchResponse{
+		Applied: err == nil,
+		Results: make([]mappingResultResponse, 0, len(rs)),
+	}
+	if err != nil {
+		res.Code = platform.ErrorCode(err)
+		res.Message = platform.ErrorMessage(err)
+	}
+
+	for _, r := range rs {
+		rr := mappingResultResponse{UserResourceMappingOp: r.UserResourceMappingOp}
+		if r.Err != nil {
+			rr.Error = &platform.Error{
+				Code: platform.ErrorCode(r.Err),
+				Msg:  platform.ErrorMessage(r.Err),
+			}
+		}
+		res.Results = append(res.Results, rr)
+	}
+	return res
+}
+
+// handlePostMappingsBatch is the HTTP handler for the POST /api/v2/mappings/batch route.
+// Either all the operations are applied or none; the response reports the result of each.
+func (h *MappingBatchHandler) handlePostMappingsBatch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("mappings batch request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodePostMappingsBatchRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// Like adding a single member or owner, adding a mapping requires its user to exist.
+	// The teams of team mappings are checked by the mapping service.
+	rs := make([]platform.UserResourceMappingResult, len(req.Operations))
+	for i, op := range req.Operations {
+		rs[i].UserResourceMappingOp = op
+		if op.Action == platform.AddMapping && op.MappingType == platform.UserMappingType && op.UserID.Valid() {
+			_, rs[i].Err = h.UserService.FindUserByID(ctx, op.UserID)
+		}
+	}
+	err = platform.ErrMappingOpsFailed(rs)
+	if err == nil {
+		rs, err = h.UserResourceMappingBatchService.ApplyUserResourceMappings(ctx, req.Operations)
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		var ok bool
+		if status, ok = statusCodePlatformError[platform.ErrorCode(err)]; !ok {
+			status = http.StatusBadRequest
+		}
+		w.Header().Set(PlatformErrorCodeHeader, platform.ErrorCode(err))
+	}
+	h.Logger.Debug("mappings batch processed", zap.Int("operations", len(rs)), zap.Error(err))
+
+	if err := encodeResponse(ctx, w, status, newMappingBatchResponse(rs, err)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostMappingsBatchRequest(ctx context.Context, r *http.Request) (*mappingBatchRequest, error) {
+	var req mappingBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}
+	}
+
+	if len(req.Operations) == 0 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "operations are required",
+		}
+	}
+
+	if len(req.Operations) > mappingsBatchMaxOps {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("a batch may hold at most %d operations", mappingsBatchMaxOps),
+		}
+	}
+
+	return &req, nil
+}
+
+// UserResourceMappingBatchService connects to Influx via HTTP using tokens to manage user resource mappings.
+type UserResourceMappingBatchService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.UserResourceMappingBatchService = (*UserResourceMappingBatchService)(nil)
+
+// ApplyUserResourceMappings applies ops in a single transaction.
+func (s *UserResourceMappingBatchService) ApplyUserResourceMappings(ctx context.Context, ops []platform.UserResourceMappingOp) ([]platform.UserResourceMappingResult, error) {
+	u, err := NewURL(s.Addr, mappingsBatchPath)
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(mappingBatchRequest{Operations: ops})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var res mappingBatchResponse
+	if err := json.Unmarshal(b, &res); err != nil || res.Results == nil {
+		// The errors about the request rather than its operations have no results.
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		if err := CheckError(resp); err != nil {
+			return nil, err
+		}
+		return nil, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  "failed to decode mappings batch response",
+			Err:  err,
+		}
+	}
+
+	rs := make([]platform.UserResourceMappingResult, 0, len(res.Results))
+	for _, r := range res.Results {
+		rr := platform.UserResourceMappingResult{UserResourceMappingOp: r.UserResourceMappingOp}
+		if r.Error != nil {
+			rr.Err = r.Error
+		}
+		rs = append(rs, rr)
+	}
+	if !res.Applied {
+		return rs, &platform.Error{
+			Code: res.Code,
+			Msg:  res.Message,
+		}
+	}
+	return rs, nil
+}
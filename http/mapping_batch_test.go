@@ -0,0 +1,142 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestMappingBatchHandler_handlePostMappingsBatch(t *testing.T) {
+	const body = `
+{
+  "operations": [
+    {"action": "add", "userID": "0000000000000001", "userType": "owner", "resourceType": "buckets", "resourceID": "0000000000000010"},
+    {"action": "remove", "userID": "0000000000000002", "resourceType": "buckets", "resourceID": "0000000000000010"}
+  ]
+}`
+
+	tests := []struct {
+		name       string
+		body       string
+		userExists bool
+		applyErr   error
+		wantCalled bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "applies the operations",
+			body:       body,
+			userExists: true,
+			wantCalled: true,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "applied": true,
+  "results": [
+    {"action": "add", "userID": "0000000000000001", "userType": "owner", "mappingType": "user", "resourceType": "buckets", "resourceID": "0000000000000010"},
+    {"action": "remove", "userID": "0000000000000002", "userType": "", "mappingType": "user", "resourceType": "buckets", "resourceID": "0000000000000010"}
+  ]
+}`,
+		},
+		{
+			name:       "reports the operations that fail",
+			body:       body,
+			userExists: true,
+			applyErr:   &platform.Error{Code: platform.ENotFound, Msg: "user to resource mapping not found"},
+			wantCalled: true,
+			wantStatus: http.StatusNotFound,
+			wantBody: `
+{
+  "code": "not found",
+  "message": "1 of 2 mapping operations failed; no mappings were changed",
+  "applied": false,
+  "results": [
+    {"action": "add", "userID": "0000000000000001", "userType": "owner", "mappingType": "user", "resourceType": "buckets", "resourceID": "0000000000000010"},
+    {"action": "remove", "userID": "0000000000000002", "userType": "", "mappingType": "user", "resourceType": "buckets", "resourceID": "0000000000000010", "error": {"code": "not found", "message": "user to resource mapping not found"}}
+  ]
+}`,
+		},
+		{
+			name:       "requires the users of added mappings to exist",
+			body:       body,
+			wantStatus: http.StatusNotFound,
+			wantBody: `
+{
+  "code": "not found",
+  "message": "1 of 2 mapping operations failed; no mappings were changed",
+  "applied": false,
+  "results": [
+    {"action": "add", "userID": "0000000000000001", "userType": "owner", "mappingType": "user", "resourceType": "buckets", "resourceID": "0000000000000010", "error": {"code": "not found", "message": "user not found"}},
+    {"action": "remove", "userID": "0000000000000002", "userType": "", "mappingType": "user", "resourceType": "buckets", "resourceID": "0000000000000010"}
+  ]
+}`,
+		},
+		{
+			name:       "requires operations",
+			body:       `{"operations": []}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			urms := mock.NewUserResourceMappingService()
+			urms.ApplyMappingsFn = func(ctx context.Context, ops []platform.UserResourceMappingOp) ([]platform.UserResourceMappingResult, error) {
+				called = true
+				rs := make([]platform.UserResourceMappingResult, len(ops))
+				for i, op := range ops {
+					rs[i].UserResourceMappingOp = op
+				}
+				if tt.applyErr != nil {
+					rs[1].Err = tt.applyErr
+					return rs, platform.ErrMappingOpsFailed(rs)
+				}
+				return rs, nil
+			}
+			users := mock.NewUserService()
+			users.FindUserByIDFn = func(ctx context.Context, id platform.ID) (*platform.User, error) {
+				if !tt.userExists {
+					return nil, &platform.Error{Code: platform.ENotFound, Msg: "user not found"}
+				}
+				return &platform.User{ID: id}, nil
+			}
+
+			h := NewMappingBatchHandler(&MappingBatchBackend{
+				HTTPErrorHandler:                ErrorHandler(0),
+				Logger:                          zap.NewNop(),
+				UserResourceMappingBatchService: urms,
+				UserService:                     users,
+			})
+
+			r := httptest.NewRequest("POST", "/api/v2/mappings/batch", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			b, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, b)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("expected the batch service to be called: %v", tt.wantCalled)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(b), tt.wantBody); err != nil {
+				t.Fatal(err)
+			} else if !eq {
+				t.Errorf("unexpected body -got/+want\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+type measurementSchemaResponse struct {
+	*influxdb.MeasurementSchema
+	Links map[string]string `json:"links"`
+}
+
+func newMeasurementSchemaResponse(m *influxdb.MeasurementSchema) measurementSchemaResponse {
+	return measurementSchemaResponse{
+		MeasurementSchema: m,
+		Links: map[string]string{
+			"self":   measurementSchemaIDPath(m.BucketID, m.ID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", m.BucketID),
+		},
+	}
+}
+
+type measurementSchemasResponse struct {
+	Links              map[string]string           `json:"links"`
+	MeasurementSchemas []measurementSchemaResponse `json:"measurementSchemas"`
+}
+
+func newMeasurementSchemasResponse(bucketID influxdb.ID, ms []*influxdb.MeasurementSchema) measurementSchemasResponse {
+	res := measurementSchemasResponse{
+		Links: map[string]string{
+			"self":   measurementSchemasPath(bucketID),
+			"bucket": fmt.Sprintf("/api/v2/buckets/%s", bucketID),
+		},
+		MeasurementSchemas: make([]measurementSchemaResponse, 0, len(ms)),
+	}
+	for _, m := range ms {
+		res.MeasurementSchemas = append(res.MeasurementSchemas, newMeasurementSchemaResponse(m))
+	}
+	return res
+}
+
+func measurementSchemasPath(bucketID influxdb.ID) string {
+	return fmt.Sprintf("/api/v2/buckets/%s/schema/measurements", bucketID)
+}
+
+func measurementSchemaIDPath(bucketID, id influxdb.ID) string {
+	return fmt.Sprintf("/api/v2/buckets/%s/schema/measurements/%s", bucketID, id)
+}
+
+// handleGetMeasurementSchemas is the HTTP handler for the GET /api/v2/buckets/:id/schema/measurements route.
+func (h *BucketHandler) handleGetMeasurementSchemas(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	filter := influxdb.MeasurementSchemaFilter{BucketID: req.BucketID}
+	if name := r.URL.Query().Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	ms, err := h.MeasurementSchemaService.FindMeasurementSchemas(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMeasurementSchemasResponse(req.BucketID, ms)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostMeasurementSchema is the HTTP handler for the POST /api/v2/buckets/:id/schema/measurements route.
+func (h *BucketHandler) handlePostMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	m := &influxdb.MeasurementSchema{}
+	if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := m.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, req.BucketID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	m.OrgID = b.OrgID
+	m.BucketID = b.ID
+
+	if err := h.MeasurementSchemaService.CreateMeasurementSchema(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("measurement schema created", zap.String("bucketID", b.ID.String()), zap.String("name", m.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newMeasurementSchemaResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetMeasurementSchema is the HTTP handler for the GET /api/v2/buckets/:id/schema/measurements/:measurementID route.
+func (h *BucketHandler) handleGetMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bucketID, id, err := decodeMeasurementSchemaIDs(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	m, err := h.MeasurementSchemaService.FindMeasurementSchemaByID(ctx, bucketID, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMeasurementSchemaResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchMeasurementSchema is the HTTP handler for the PATCH /api/v2/buckets/:id/schema/measurements/:measurementID route.
+// The columns of the request replace the columns of the schema, which they must all include unchanged.
+func (h *BucketHandler) handlePatchMeasurementSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	bucketID, id, err := decodeMeasurementSchemaIDs(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.MeasurementSchemaUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	m, err := h.MeasurementSchemaService.UpdateMeasurementSchema(ctx, bucketID, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("measurement schema updated", zap.String("bucketID", bucketID.String()), zap.String("name", m.Name))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newMeasurementSchemaResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeMeasurementSchemaIDs(ctx context.Context, r *http.Request) (influxdb.ID, influxdb.ID, error) {
+	req, err := decodeGetBucketRequest(ctx, r)
+	if err != nil {
+		return influxdb.InvalidID(), influxdb.InvalidID(), err
+	}
+
+	id, err := decodeIDParam(ctx, "measurementID")
+	if err != nil {
+		return influxdb.InvalidID(), influxdb.InvalidID(), err
+	}
+	return req.BucketID, id, nil
+}
+
+// MeasurementSchemaService connects to Influx via HTTP using tokens to manage
+// the measurement schemas of buckets.
+type MeasurementSchemaService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ influxdb.MeasurementSchemaService = (*MeasurementSchemaService)(nil)
+
+// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+func (s *MeasurementSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	var res measurementSchemaResponse
+	if err := s.client().do(ctx, "GET", measurementSchemaIDPath(bucketID, id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.MeasurementSchema, nil
+}
+
+// FindMeasurementSchemas returns the measurement schemas of a bucket that match the filter.
+func (s *MeasurementSchemaService) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+	query := url.Values{}
+	if filter.Name != nil {
+		query.Set("name", *filter.Name)
+	}
+
+	var res measurementSchemasResponse
+	if err := s.client().do(ctx, "GET", measurementSchemasPath(filter.BucketID), query, nil, &res); err != nil {
+		return nil, err
+	}
+
+	ms := make([]*influxdb.MeasurementSchema, 0, len(res.MeasurementSchemas))
+	for _, m := range res.MeasurementSchemas {
+		ms = append(ms, m.MeasurementSchema)
+	}
+	return ms, nil
+}
+
+// CreateMeasurementSchema creates a new measurement schema and sets m.ID with the new identifier.
+func (s *MeasurementSchemaService) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	var res measurementSchemaResponse
+	if err := s.client().do(ctx, "POST", measurementSchemasPath(m.BucketID), nil, m, &res); err != nil {
+		return err
+	}
+	*m = *res.MeasurementSchema
+	return nil
+}
+
+// UpdateMeasurementSchema updates the columns of a measurement schema.
+func (s *MeasurementSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	var res measurementSchemaResponse
+	if err := s.client().do(ctx, "PATCH", measurementSchemaIDPath(bucketID, id), nil, upd, &res); err != nil {
+		return nil, err
+	}
+	return res.MeasurementSchema, nil
+}
+
+func (s *MeasurementSchemaService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
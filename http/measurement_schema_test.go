@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func TestBucketHandler_handlePostMeasurementSchema(t *testing.T) {
+	bs := mock.NewBucketService()
+	bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: id, OrgID: 2, Name: "b", SchemaType: platform.BucketSchemaTypeExplicit}, nil
+	}
+
+	ms := mock.NewMeasurementSchemaService()
+	ms.CreateMeasurementSchemaFn = func(ctx context.Context, m *platform.MeasurementSchema) error {
+		m.ID = 3
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "schema of a measurement",
+			body: `{"name": "cpu", "columns": [
+  {"name": "time", "type": "timestamp"},
+  {"name": "host", "type": "tag"},
+  {"name": "usage_user", "type": "field", "dataType": "float"}
+]}`,
+			wantStatus: http.StatusCreated,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/buckets/0000000000000001/schema/measurements/0000000000000003",
+    "bucket": "/api/v2/buckets/0000000000000001"
+  },
+  "id": "0000000000000003",
+  "orgID": "0000000000000002",
+  "bucketID": "0000000000000001",
+  "name": "cpu",
+  "columns": [
+    {"name": "time", "type": "timestamp"},
+    {"name": "host", "type": "tag"},
+    {"name": "usage_user", "type": "field", "dataType": "float"}
+  ],
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`,
+		},
+		{
+			name: "field without a data type",
+			body: `{"name": "cpu", "columns": [
+  {"name": "time", "type": "timestamp"},
+  {"name": "usage_user", "type": "field"}
+]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "schema without a timestamp",
+			body: `{"name": "cpu", "columns": [
+  {"name": "usage_user", "type": "field", "dataType": "float"}
+]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBucketHandler(&BucketBackend{
+				HTTPErrorHandler:         ErrorHandler(0),
+				Logger:                   zap.NewNop(),
+				BucketService:            bs,
+				MeasurementSchemaService: ms,
+			})
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/buckets/0000000000000001/schema/measurements", strings.NewReader(tt.body))
+			r = r.WithContext(context.WithValue(
+				pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+			w := httptest.NewRecorder()
+			h.handlePostMeasurementSchema(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+
+			if tt.wantBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+					t.Errorf("error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("***%s***", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestBucketHandler_handlePatchMeasurementSchema(t *testing.T) {
+	ms := mock.NewMeasurementSchemaService()
+	ms.UpdateMeasurementSchemaFn = func(ctx context.Context, bucketID, id platform.ID, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+		m := &platform.MeasurementSchema{
+			ID:       id,
+			OrgID:    2,
+			BucketID: bucketID,
+			Name:     "cpu",
+			Columns: []platform.MeasurementSchemaColumn{
+				{Name: "time", Type: platform.SemanticColumnTypeTimestamp},
+				{Name: "usage_user", Type: platform.SemanticColumnTypeField, DataType: platform.SchemaColumnDataTypeFloat},
+			},
+		}
+		if err := upd.Apply(m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	h := NewBucketHandler(&BucketBackend{
+		HTTPErrorHandler:         ErrorHandler(0),
+		Logger:                   zap.NewNop(),
+		MeasurementSchemaService: ms,
+	})
+
+	patch := func(body string) *http.Response {
+		r := httptest.NewRequest("PATCH", "http://any.url/api/v2/buckets/0000000000000001/schema/measurements/0000000000000003", strings.NewReader(body))
+		r = r.WithContext(context.WithValue(
+			pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}),
+			httprouter.ParamsKey,
+			httprouter.Params{{Key: "id", Value: "0000000000000001"}, {Key: "measurementID", Value: "0000000000000003"}}))
+		w := httptest.NewRecorder()
+		h.handlePatchMeasurementSchema(w, r)
+		return w.Result()
+	}
+
+	added := patch(`{"columns": [
+  {"name": "time", "type": "timestamp"},
+  {"name": "usage_user", "type": "field", "dataType": "float"},
+  {"name": "host", "type": "tag"}
+]}`)
+	if added.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(added.Body)
+		t.Fatalf("expected a column to be added, got status %d: %s", added.StatusCode, body)
+	}
+
+	changed := patch(`{"columns": [
+  {"name": "time", "type": "timestamp"},
+  {"name": "usage_user", "type": "field", "dataType": "integer"}
+]}`)
+	if changed.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected changing the data type of a column to be rejected, got status %d", changed.StatusCode)
+	}
+}
@@ -0,0 +1,47 @@
+package metric
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UsageEventRecorder records the usage of the organizations of successful
+// requests, and passes every event on to another recorder.
+type UsageEventRecorder struct {
+	next  EventRecorder
+	usage influxdb.UsageRecorder
+	count influxdb.UsageMetric
+	bytes influxdb.UsageMetric
+}
+
+// NewUsageEventRecorder returns a recorder that records a request to the usage
+// metric count, and its request bytes to the usage metric bytes, before passing
+// the event on to next.
+func NewUsageEventRecorder(next EventRecorder, usage influxdb.UsageRecorder, count, bytes influxdb.UsageMetric) *UsageEventRecorder {
+	return &UsageEventRecorder{
+		next:  next,
+		usage: usage,
+		count: count,
+		bytes: bytes,
+	}
+}
+
+// Record records the usage of the request, if it succeeded, and passes the event on.
+func (r *UsageEventRecorder) Record(ctx context.Context, e Event) {
+	if e.OrgID.Valid() && e.Status >= 200 && e.Status < 300 {
+		r.usage.RecordUsage(ctx, e.OrgID, r.count, 1)
+		r.usage.RecordUsage(ctx, e.OrgID, r.bytes, int64(e.RequestBytes))
+	}
+	r.next.Record(ctx, e)
+}
+
+// PrometheusCollectors exposes the prometheus collectors of the recorder the events are passed on to.
+func (r *UsageEventRecorder) PrometheusCollectors() []prometheus.Collector {
+	if pc, ok := r.next.(prom.PrometheusCollector); ok {
+		return pc.PrometheusCollectors()
+	}
+	return nil
+}
@@ -0,0 +1,402 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+)
+
+// NotificationEndpointBackend is all services and associated parameters
+// required to construct the NotificationEndpointHandler.
+type NotificationEndpointBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	NotificationEndpointService influxdb.NotificationEndpointService
+	NotificationSender          influxdb.NotificationSender
+	OrganizationService         influxdb.OrganizationService
+	UserResourceMappingService  influxdb.UserResourceMappingService
+	UserService                 influxdb.UserService
+}
+
+// NewNotificationEndpointBackend returns a new instance of NotificationEndpointBackend.
+func NewNotificationEndpointBackend(b *APIBackend) *NotificationEndpointBackend {
+	return &NotificationEndpointBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "notificationEndpoint")),
+
+		NotificationEndpointService: b.NotificationEndpointService,
+		NotificationSender:          b.NotificationSender,
+		OrganizationService:         b.OrganizationService,
+		UserResourceMappingService:  b.UserResourceMappingService,
+		UserService:                 b.UserService,
+	}
+}
+
+// NotificationEndpointHandler represents an HTTP API handler for notification endpoints.
+type NotificationEndpointHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	NotificationEndpointService influxdb.NotificationEndpointService
+	NotificationSender          influxdb.NotificationSender
+	OrganizationService         influxdb.OrganizationService
+	UserResourceMappingService  influxdb.UserResourceMappingService
+	UserService                 influxdb.UserService
+}
+
+const (
+	notificationEndpointsPath            = "/api/v2/notificationEndpoints"
+	notificationEndpointsIDPath          = "/api/v2/notificationEndpoints/:id"
+	notificationEndpointsIDTestPath      = "/api/v2/notificationEndpoints/:id/test"
+	notificationEndpointsIDMembersPath   = "/api/v2/notificationEndpoints/:id/members"
+	notificationEndpointsIDMembersIDPath = "/api/v2/notificationEndpoints/:id/members/:userID"
+	notificationEndpointsIDOwnersPath    = "/api/v2/notificationEndpoints/:id/owners"
+	notificationEndpointsIDOwnersIDPath  = "/api/v2/notificationEndpoints/:id/owners/:userID"
+)
+
+// NewNotificationEndpointHandler returns a new instance of NotificationEndpointHandler.
+func NewNotificationEndpointHandler(b *NotificationEndpointBackend) *NotificationEndpointHandler {
+	h := &NotificationEndpointHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		NotificationEndpointService: b.NotificationEndpointService,
+		NotificationSender:          b.NotificationSender,
+		OrganizationService:         b.OrganizationService,
+		UserResourceMappingService:  b.UserResourceMappingService,
+		UserService:                 b.UserService,
+	}
+
+	h.HandlerFunc("POST", notificationEndpointsPath, h.handlePostNotificationEndpoint)
+	h.HandlerFunc("GET", notificationEndpointsPath, h.handleGetNotificationEndpoints)
+	h.HandlerFunc("GET", notificationEndpointsIDPath, h.handleGetNotificationEndpoint)
+	h.HandlerFunc("PATCH", notificationEndpointsIDPath, h.handlePatchNotificationEndpoint)
+	h.HandlerFunc("PUT", notificationEndpointsIDPath, h.handlePutNotificationEndpoint)
+	h.HandlerFunc("DELETE", notificationEndpointsIDPath, h.handleDeleteNotificationEndpoint)
+	h.HandlerFunc("POST", notificationEndpointsIDTestPath, h.handlePostNotificationEndpointTest)
+
+	memberBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.NotificationEndpointsResourceType,
+		UserType:                   influxdb.Member,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", notificationEndpointsIDMembersPath, newPostMemberHandler(memberBackend))
+	h.HandlerFunc("GET", notificationEndpointsIDMembersPath, newGetMembersHandler(memberBackend))
+	h.HandlerFunc("DELETE", notificationEndpointsIDMembersIDPath, newDeleteMemberHandler(memberBackend))
+
+	ownerBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.NotificationEndpointsResourceType,
+		UserType:                   influxdb.Owner,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", notificationEndpointsIDOwnersPath, newPostMemberHandler(ownerBackend))
+	h.HandlerFunc("GET", notificationEndpointsIDOwnersPath, newGetMembersHandler(ownerBackend))
+	h.HandlerFunc("DELETE", notificationEndpointsIDOwnersIDPath, newDeleteMemberHandler(ownerBackend))
+
+	return h
+}
+
+type notificationEndpointResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.NotificationEndpoint
+}
+
+func newNotificationEndpointResponse(e *influxdb.NotificationEndpoint) *notificationEndpointResponse {
+	return &notificationEndpointResponse{
+		Links: map[string]string{
+			"self":    fmt.Sprintf("/api/v2/notificationEndpoints/%s", e.ID),
+			"test":    fmt.Sprintf("/api/v2/notificationEndpoints/%s/test", e.ID),
+			"members": fmt.Sprintf("/api/v2/notificationEndpoints/%s/members", e.ID),
+			"owners":  fmt.Sprintf("/api/v2/notificationEndpoints/%s/owners", e.ID),
+			"org":     fmt.Sprintf("/api/v2/orgs/%s", e.OrgID),
+		},
+		NotificationEndpoint: *e,
+	}
+}
+
+type notificationEndpointsResponse struct {
+	Links                 map[string]string               `json:"links"`
+	NotificationEndpoints []*notificationEndpointResponse `json:"notificationEndpoints"`
+}
+
+func newNotificationEndpointsResponse(es []*influxdb.NotificationEndpoint) *notificationEndpointsResponse {
+	res := &notificationEndpointsResponse{
+		Links: map[string]string{
+			"self": notificationEndpointsPath,
+		},
+		NotificationEndpoints: make([]*notificationEndpointResponse, 0, len(es)),
+	}
+	for _, e := range es {
+		res.NotificationEndpoints = append(res.NotificationEndpoints, newNotificationEndpointResponse(e))
+	}
+	return res
+}
+
+func decodeNotificationEndpoint(r *http.Request) (*influxdb.NotificationEndpoint, error) {
+	e := &influxdb.NotificationEndpoint{}
+	if err := json.NewDecoder(r.Body).Decode(e); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode notification endpoint request",
+			Err:  err,
+		}
+	}
+	return e, nil
+}
+
+// handlePostNotificationEndpoint is the HTTP handler for the POST /api/v2/notificationEndpoints route.
+func (h *NotificationEndpointHandler) handlePostNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification endpoint create request", zap.String("r", fmt.Sprint(r)))
+
+	e, err := decodeNotificationEndpoint(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotificationEndpointService.CreateNotificationEndpoint(ctx, e); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification endpoint created", zap.String("notificationEndpointID", e.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newNotificationEndpointResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetNotificationEndpoints is the HTTP handler for the GET /api/v2/notificationEndpoints route.
+func (h *NotificationEndpointHandler) handleGetNotificationEndpoints(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification endpoints retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	filter, err := h.decodeGetNotificationEndpointsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	es, _, err := h.NotificationEndpointService.FindNotificationEndpoints(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification endpoints retrieved", zap.Int("notificationEndpoints", len(es)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationEndpointsResponse(es)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationEndpointHandler) decodeGetNotificationEndpointsRequest(ctx context.Context, r *http.Request) (influxdb.NotificationEndpointFilter, error) {
+	qp := r.URL.Query()
+	var filter influxdb.NotificationEndpointFilter
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	if typ := qp.Get("type"); typ != "" {
+		t := influxdb.NotificationEndpointType(typ)
+		filter.Type = &t
+	}
+
+	return filter, nil
+}
+
+// handleGetNotificationEndpoint is the HTTP handler for the GET /api/v2/notificationEndpoints/:id route.
+func (h *NotificationEndpointHandler) handleGetNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification endpoint retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	e, err := h.NotificationEndpointService.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification endpoint retrieved", zap.String("notificationEndpointID", e.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationEndpointResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchNotificationEndpoint is the HTTP handler for the PATCH /api/v2/notificationEndpoints/:id route.
+func (h *NotificationEndpointHandler) handlePatchNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification endpoint update request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.NotificationEndpointUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode notification endpoint update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	e, err := h.NotificationEndpointService.UpdateNotificationEndpoint(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification endpoint updated", zap.String("notificationEndpointID", e.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationEndpointResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePutNotificationEndpoint is the HTTP handler for the PUT /api/v2/notificationEndpoints/:id route.
+func (h *NotificationEndpointHandler) handlePutNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification endpoint replace request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	e, err := decodeNotificationEndpoint(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	e.ID = id
+
+	e, err = h.NotificationEndpointService.ReplaceNotificationEndpoint(ctx, e)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification endpoint replaced", zap.String("notificationEndpointID", e.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationEndpointResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteNotificationEndpoint is the HTTP handler for the DELETE /api/v2/notificationEndpoints/:id route.
+func (h *NotificationEndpointHandler) handleDeleteNotificationEndpoint(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification endpoint delete request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotificationEndpointService.DeleteNotificationEndpoint(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification endpoint deleted", zap.String("notificationEndpointID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// defaultTestNotification is sent by the send-test endpoint when no message is given.
+const defaultTestNotification = "This is a test notification from InfluxDB."
+
+// postNotificationEndpointTestRequest is the optional body of a POST to
+// /api/v2/notificationEndpoints/:id/test.
+type postNotificationEndpointTestRequest struct {
+	Message string `json:"message"`
+}
+
+// handlePostNotificationEndpointTest is the HTTP handler for the POST /api/v2/notificationEndpoints/:id/test route.
+// It sends a test notification to the notification endpoint.
+func (h *NotificationEndpointHandler) handlePostNotificationEndpointTest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification endpoint test request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req postNotificationEndpointTestRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "unable to decode notification endpoint test request",
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+	if req.Message == "" {
+		req.Message = defaultTestNotification
+	}
+
+	e, err := h.NotificationEndpointService.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	n := influxdb.Notification{
+		Level:   influxdb.NotificationLevelInfo,
+		Message: req.Message,
+		Source:  "influxdb",
+	}
+	if err := h.NotificationSender.SendNotification(ctx, e, n); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification endpoint tested", zap.String("notificationEndpointID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newTestNotificationEndpointHandler(es platform.NotificationEndpointService, sender platform.NotificationSender) *NotificationEndpointHandler {
+	return NewNotificationEndpointHandler(&NotificationEndpointBackend{
+		HTTPErrorHandler:            ErrorHandler(0),
+		Logger:                      zap.NewNop(),
+		NotificationEndpointService: es,
+		NotificationSender:          sender,
+		OrganizationService:         mock.NewOrganizationService(),
+		UserResourceMappingService:  mock.NewUserResourceMappingService(),
+		UserService:                 mock.NewUserService(),
+	})
+}
+
+func TestNotificationEndpointHandler_handlePostNotificationEndpoint(t *testing.T) {
+	es := mock.NewNotificationEndpointService()
+	es.CreateNotificationEndpointFn = func(ctx context.Context, e *platform.NotificationEndpoint) error {
+		if e.Token == nil || e.Token.Value == nil || *e.Token.Value != "xoxb" {
+			t.Errorf("expected the token to be given by value, got %+v", e.Token)
+		}
+		e.ID = 2
+		e.Status = platform.Active
+		e.Token = &platform.SecretField{Key: "0000000000000002-token"}
+		return nil
+	}
+	h := newTestNotificationEndpointHandler(es, mock.NewNotificationSender())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/notificationEndpoints", bytes.NewBufferString(`{"orgID": "0000000000000001", "name": "ops", "type": "slack", "token": {"value": "xoxb"}}`)))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/notificationEndpoints/0000000000000002",
+    "test": "/api/v2/notificationEndpoints/0000000000000002/test",
+    "members": "/api/v2/notificationEndpoints/0000000000000002/members",
+    "owners": "/api/v2/notificationEndpoints/0000000000000002/owners",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "name": "ops",
+  "status": "active",
+  "type": "slack",
+  "token": {"key": "0000000000000002-token"},
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+}
+
+func TestNotificationEndpointHandler_handlePostNotificationEndpointTest(t *testing.T) {
+	es := mock.NewNotificationEndpointService()
+	es.FindNotificationEndpointByIDFn = func(ctx context.Context, id platform.ID) (*platform.NotificationEndpoint, error) {
+		if id != 2 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrNotificationEndpointNotFound}
+		}
+		return &platform.NotificationEndpoint{ID: 2, OrgID: 1, Name: "ops", Type: platform.SlackNotificationEndpoint}, nil
+	}
+
+	var sent []platform.Notification
+	sender := mock.NewNotificationSender()
+	sender.SendNotificationFn = func(ctx context.Context, e *platform.NotificationEndpoint, n platform.Notification) error {
+		if e.ID != 2 {
+			t.Errorf("sent to endpoint %s, want 0000000000000002", e.ID)
+		}
+		sent = append(sent, n)
+		return nil
+	}
+	h := newTestNotificationEndpointHandler(es, sender)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/notificationEndpoints/0000000000000002/test", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/notificationEndpoints/0000000000000002/test", bytes.NewBufferString(`{"message": "hello"}`)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+
+	if len(sent) != 2 || sent[0].Message != defaultTestNotification || sent[1].Message != "hello" {
+		t.Errorf("got notifications %+v", sent)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/notificationEndpoints/0000000000000003/test", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,458 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+)
+
+// NotificationRuleBackend is all services and associated parameters
+// required to construct the NotificationRuleHandler.
+type NotificationRuleBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	NotificationRuleService    influxdb.NotificationRuleService
+	SentNotificationService    influxdb.SentNotificationService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+// NewNotificationRuleBackend returns a new instance of NotificationRuleBackend.
+func NewNotificationRuleBackend(b *APIBackend) *NotificationRuleBackend {
+	return &NotificationRuleBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "notificationRule")),
+
+		NotificationRuleService:    b.NotificationRuleService,
+		SentNotificationService:    b.SentNotificationService,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+}
+
+// NotificationRuleHandler represents an HTTP API handler for notification
+// rules and the history of the notifications they sent.
+type NotificationRuleHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	NotificationRuleService    influxdb.NotificationRuleService
+	SentNotificationService    influxdb.SentNotificationService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+const (
+	notificationRulesPath            = "/api/v2/notificationRules"
+	notificationRulesIDPath          = "/api/v2/notificationRules/:id"
+	notificationRulesIDMembersPath   = "/api/v2/notificationRules/:id/members"
+	notificationRulesIDMembersIDPath = "/api/v2/notificationRules/:id/members/:userID"
+	notificationRulesIDOwnersPath    = "/api/v2/notificationRules/:id/owners"
+	notificationRulesIDOwnersIDPath  = "/api/v2/notificationRules/:id/owners/:userID"
+	sentNotificationsPath            = "/api/v2/notifications"
+)
+
+// NewNotificationRuleHandler returns a new instance of NotificationRuleHandler.
+func NewNotificationRuleHandler(b *NotificationRuleBackend) *NotificationRuleHandler {
+	h := &NotificationRuleHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		NotificationRuleService:    b.NotificationRuleService,
+		SentNotificationService:    b.SentNotificationService,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+
+	h.HandlerFunc("POST", notificationRulesPath, h.handlePostNotificationRule)
+	h.HandlerFunc("GET", notificationRulesPath, h.handleGetNotificationRules)
+	h.HandlerFunc("GET", notificationRulesIDPath, h.handleGetNotificationRule)
+	h.HandlerFunc("PATCH", notificationRulesIDPath, h.handlePatchNotificationRule)
+	h.HandlerFunc("PUT", notificationRulesIDPath, h.handlePutNotificationRule)
+	h.HandlerFunc("DELETE", notificationRulesIDPath, h.handleDeleteNotificationRule)
+
+	h.HandlerFunc("GET", sentNotificationsPath, h.handleGetSentNotifications)
+
+	memberBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.NotificationRulesResourceType,
+		UserType:                   influxdb.Member,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", notificationRulesIDMembersPath, newPostMemberHandler(memberBackend))
+	h.HandlerFunc("GET", notificationRulesIDMembersPath, newGetMembersHandler(memberBackend))
+	h.HandlerFunc("DELETE", notificationRulesIDMembersIDPath, newDeleteMemberHandler(memberBackend))
+
+	ownerBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.NotificationRulesResourceType,
+		UserType:                   influxdb.Owner,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", notificationRulesIDOwnersPath, newPostMemberHandler(ownerBackend))
+	h.HandlerFunc("GET", notificationRulesIDOwnersPath, newGetMembersHandler(ownerBackend))
+	h.HandlerFunc("DELETE", notificationRulesIDOwnersIDPath, newDeleteMemberHandler(ownerBackend))
+
+	return h
+}
+
+type notificationRuleResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.NotificationRule
+}
+
+func newNotificationRuleResponse(rule *influxdb.NotificationRule) *notificationRuleResponse {
+	res := &notificationRuleResponse{
+		Links: map[string]string{
+			"self":          fmt.Sprintf("/api/v2/notificationRules/%s", rule.ID),
+			"endpoint":      fmt.Sprintf("/api/v2/notificationEndpoints/%s", rule.EndpointID),
+			"notifications": fmt.Sprintf("/api/v2/notifications?ruleID=%s", rule.ID),
+			"members":       fmt.Sprintf("/api/v2/notificationRules/%s/members", rule.ID),
+			"owners":        fmt.Sprintf("/api/v2/notificationRules/%s/owners", rule.ID),
+			"org":           fmt.Sprintf("/api/v2/orgs/%s", rule.OrgID),
+		},
+		NotificationRule: *rule,
+	}
+	if rule.CheckID.Valid() {
+		res.Links["check"] = fmt.Sprintf("/api/v2/checks/%s", rule.CheckID)
+	}
+	return res
+}
+
+type notificationRulesResponse struct {
+	Links             map[string]string           `json:"links"`
+	NotificationRules []*notificationRuleResponse `json:"notificationRules"`
+}
+
+func newNotificationRulesResponse(rules []*influxdb.NotificationRule) *notificationRulesResponse {
+	res := &notificationRulesResponse{
+		Links: map[string]string{
+			"self": notificationRulesPath,
+		},
+		NotificationRules: make([]*notificationRuleResponse, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		res.NotificationRules = append(res.NotificationRules, newNotificationRuleResponse(rule))
+	}
+	return res
+}
+
+func decodeNotificationRule(r *http.Request) (*influxdb.NotificationRule, error) {
+	rule := &influxdb.NotificationRule{}
+	if err := json.NewDecoder(r.Body).Decode(rule); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode notification rule request",
+			Err:  err,
+		}
+	}
+	return rule, nil
+}
+
+// handlePostNotificationRule is the HTTP handler for the POST /api/v2/notificationRules route.
+func (h *NotificationRuleHandler) handlePostNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification rule create request", zap.String("r", fmt.Sprint(r)))
+
+	rule, err := decodeNotificationRule(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotificationRuleService.CreateNotificationRule(ctx, rule); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification rule created", zap.String("notificationRuleID", rule.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newNotificationRuleResponse(rule)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetNotificationRules is the HTTP handler for the GET /api/v2/notificationRules route.
+func (h *NotificationRuleHandler) handleGetNotificationRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification rules retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	filter, opts, err := h.decodeGetNotificationRulesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rules, _, err := h.NotificationRuleService.FindNotificationRules(ctx, filter, opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification rules retrieved", zap.Int("notificationRules", len(rules)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationRulesResponse(rules)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationRuleHandler) decodeGetNotificationRulesRequest(ctx context.Context, r *http.Request) (influxdb.NotificationRuleFilter, influxdb.FindOptions, error) {
+	qp := r.URL.Query()
+	var filter influxdb.NotificationRuleFilter
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return filter, influxdb.FindOptions{}, err
+	}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	if endpointID := qp.Get("endpointID"); endpointID != "" {
+		id, err := influxdb.IDFromString(endpointID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.EndpointID = id
+	}
+
+	if checkID := qp.Get("checkID"); checkID != "" {
+		id, err := influxdb.IDFromString(checkID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.CheckID = id
+	}
+
+	return filter, *opts, nil
+}
+
+// handleGetNotificationRule is the HTTP handler for the GET /api/v2/notificationRules/:id route.
+func (h *NotificationRuleHandler) handleGetNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification rule retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rule, err := h.NotificationRuleService.FindNotificationRuleByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification rule retrieved", zap.String("notificationRuleID", rule.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationRuleResponse(rule)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchNotificationRule is the HTTP handler for the PATCH /api/v2/notificationRules/:id route.
+func (h *NotificationRuleHandler) handlePatchNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification rule update request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.NotificationRuleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode notification rule update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	rule, err := h.NotificationRuleService.UpdateNotificationRule(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification rule updated", zap.String("notificationRuleID", rule.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationRuleResponse(rule)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePutNotificationRule is the HTTP handler for the PUT /api/v2/notificationRules/:id route.
+func (h *NotificationRuleHandler) handlePutNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification rule replace request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rule, err := decodeNotificationRule(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	rule.ID = id
+
+	rule, err = h.NotificationRuleService.ReplaceNotificationRule(ctx, rule)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification rule replaced", zap.String("notificationRuleID", rule.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newNotificationRuleResponse(rule)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteNotificationRule is the HTTP handler for the DELETE /api/v2/notificationRules/:id route.
+func (h *NotificationRuleHandler) handleDeleteNotificationRule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("notification rule delete request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.NotificationRuleService.DeleteNotificationRule(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("notification rule deleted", zap.String("notificationRuleID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type sentNotificationsResponse struct {
+	Links         *influxdb.PagingLinks        `json:"links"`
+	Notifications []*influxdb.SentNotification `json:"notifications"`
+}
+
+// handleGetSentNotifications is the HTTP handler for the GET /api/v2/notifications route.
+func (h *NotificationRuleHandler) handleGetSentNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("sent notifications retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	filter, opts, err := h.decodeGetSentNotificationsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ns, _, err := h.SentNotificationService.FindSentNotifications(ctx, filter, opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("sent notifications retrieved", zap.Int("notifications", len(ns)))
+
+	res := sentNotificationsResponse{
+		Links:         newPagingLinks(sentNotificationsPath, opts, filter, len(ns)),
+		Notifications: ns,
+	}
+	if res.Notifications == nil {
+		res.Notifications = []*influxdb.SentNotification{}
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *NotificationRuleHandler) decodeGetSentNotificationsRequest(ctx context.Context, r *http.Request) (influxdb.SentNotificationFilter, influxdb.FindOptions, error) {
+	qp := r.URL.Query()
+	var filter influxdb.SentNotificationFilter
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return filter, influxdb.FindOptions{}, err
+	}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	for param, id := range map[string]**influxdb.ID{
+		"ruleID":     &filter.RuleID,
+		"checkID":    &filter.CheckID,
+		"endpointID": &filter.EndpointID,
+	} {
+		if v := qp.Get(param); v != "" {
+			i, err := influxdb.IDFromString(v)
+			if err != nil {
+				return filter, *opts, err
+			}
+			*id = i
+		}
+	}
+
+	if since := qp.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return filter, *opts, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "since must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		filter.Since = t
+	}
+
+	return filter, *opts, nil
+}
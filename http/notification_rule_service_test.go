@@ -0,0 +1,157 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newTestNotificationRuleHandler(rs platform.NotificationRuleService, ss platform.SentNotificationService) *NotificationRuleHandler {
+	return NewNotificationRuleHandler(&NotificationRuleBackend{
+		HTTPErrorHandler:           ErrorHandler(0),
+		Logger:                     zap.NewNop(),
+		NotificationRuleService:    rs,
+		SentNotificationService:    ss,
+		OrganizationService:        mock.NewOrganizationService(),
+		UserResourceMappingService: mock.NewUserResourceMappingService(),
+		UserService:                mock.NewUserService(),
+	})
+}
+
+func TestNotificationRuleHandler_handlePostNotificationRule(t *testing.T) {
+	rs := mock.NewNotificationRuleService()
+	rs.CreateNotificationRuleFn = func(ctx context.Context, r *platform.NotificationRule) error {
+		if len(r.StatusRules) != 1 || r.StatusRules[0].PreviousLevel == nil || r.StatusRules[0].PreviousLevel.Level != platform.CheckLevelOK {
+			t.Errorf("unexpected status rules %+v", r.StatusRules)
+		}
+		r.ID = 2
+		r.SetDefaults()
+		return nil
+	}
+	h := newTestNotificationRuleHandler(rs, mock.NewSentNotificationService())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/notificationRules", bytes.NewBufferString(`
+{
+  "orgID": "0000000000000001",
+  "name": "crit",
+  "endpointID": "0000000000000003",
+  "checkID": "0000000000000004",
+  "statusRules": [{"currentLevel": {"level": "CRIT", "operation": "equal"}, "previousLevel": {"level": "OK", "operation": "equal"}}]
+}`)))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/notificationRules/0000000000000002",
+    "endpoint": "/api/v2/notificationEndpoints/0000000000000003",
+    "check": "/api/v2/checks/0000000000000004",
+    "notifications": "/api/v2/notifications?ruleID=0000000000000002",
+    "members": "/api/v2/notificationRules/0000000000000002/members",
+    "owners": "/api/v2/notificationRules/0000000000000002/owners",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "name": "crit",
+  "status": "active",
+  "endpointID": "0000000000000003",
+  "checkID": "0000000000000004",
+  "statusRules": [{"currentLevel": {"level": "CRIT", "operation": "equal"}, "previousLevel": {"level": "OK", "operation": "equal"}}],
+  "limit": 1,
+  "limitEvery": 900,
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+}
+
+func TestNotificationRuleHandler_handleGetSentNotifications(t *testing.T) {
+	sentAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	ss := mock.NewSentNotificationService()
+	ss.FindSentNotificationsFn = func(ctx context.Context, filter platform.SentNotificationFilter, opt ...platform.FindOptions) ([]*platform.SentNotification, int, error) {
+		if filter.RuleID == nil || *filter.RuleID != 2 {
+			t.Errorf("expected the notifications of rule 0000000000000002, got %v", filter.RuleID)
+		}
+		if !filter.Since.Equal(sentAt.Add(-time.Hour)) {
+			t.Errorf("expected the notifications since %s, got %s", sentAt.Add(-time.Hour), filter.Since)
+		}
+		if len(opt) == 0 || opt[0].Limit != 1 {
+			t.Errorf("expected a limit of 1, got %+v", opt)
+		}
+		return []*platform.SentNotification{
+			{
+				ID:            5,
+				OrgID:         1,
+				RuleID:        2,
+				EndpointID:    3,
+				CheckID:       4,
+				Series:        "0000000000000004,host=a",
+				Level:         platform.CheckLevelCrit,
+				PreviousLevel: platform.CheckLevelOK,
+				Message:       "cpu is CRIT",
+				StatusTime:    sentAt.Add(-time.Minute),
+				SentAt:        sentAt,
+				Error:         "slack is down",
+			},
+		}, 1, nil
+	}
+	h := newTestNotificationRuleHandler(mock.NewNotificationRuleService(), ss)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/notifications?ruleID=0000000000000002&since=2019-07-01T11:00:00Z&limit=1", nil))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/notifications?descending=false&limit=1&offset=0&ruleID=0000000000000002&since=2019-07-01T11%3A00%3A00Z",
+    "next": "/api/v2/notifications?descending=false&limit=1&offset=1&ruleID=0000000000000002&since=2019-07-01T11%3A00%3A00Z"
+  },
+  "notifications": [
+    {
+      "id": "0000000000000005",
+      "orgID": "0000000000000001",
+      "ruleID": "0000000000000002",
+      "endpointID": "0000000000000003",
+      "checkID": "0000000000000004",
+      "series": "0000000000000004,host=a",
+      "level": "CRIT",
+      "previousLevel": "OK",
+      "message": "cpu is CRIT",
+      "statusTime": "2019-07-01T11:59:00Z",
+      "sentAt": "2019-07-01T12:00:00Z",
+      "error": "slack is down"
+    }
+  ]
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/notifications?since=yesterday", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
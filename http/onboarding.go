@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -39,7 +40,8 @@ type SetupHandler struct {
 }
 
 const (
-	setupPath = "/api/v2/setup"
+	setupPath     = "/api/v2/setup"
+	setupUserPath = "/api/v2/setup/user"
 )
 
 // NewSetupHandler returns a new instance of SetupHandler.
@@ -52,6 +54,7 @@ func NewSetupHandler(b *SetupBackend) *SetupHandler {
 	}
 	h.HandlerFunc("POST", setupPath, h.handlePostSetup)
 	h.HandlerFunc("GET", setupPath, h.isOnboarding)
+	h.HandlerFunc("POST", setupUserPath, h.handlePostSetupUser)
 	return h
 }
 
@@ -77,7 +80,9 @@ func (h *SetupHandler) isOnboarding(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// isOnboarding is the HTTP handler for the POST /api/v2/setup route.
+// handlePostSetup is the HTTP handler for the POST /api/v2/setup route.
+// Re-running setup with the request that completed it responds with the
+// same results, so that provisioning scripts can run it more than once.
 func (h *SetupHandler) handlePostSetup(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	h.Logger.Debug("onboarding setup request", zap.String("r", fmt.Sprint(r)))
@@ -86,6 +91,11 @@ func (h *SetupHandler) handlePostSetup(w http.ResponseWriter, r *http.Request) {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+	isOnboarding, err := h.OnboardingService.IsOnboarding(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
 	results, err := h.OnboardingService.Generate(ctx, req)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
@@ -93,6 +103,43 @@ func (h *SetupHandler) handlePostSetup(w http.ResponseWriter, r *http.Request) {
 	}
 	h.Logger.Debug("onboarding setup completed", zap.String("results", fmt.Sprint(results)))
 
+	status := http.StatusCreated
+	if !isOnboarding {
+		status = http.StatusOK
+	}
+	if err := encodeResponse(ctx, w, status, newOnboardingResponse(results)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// onboardUserPermissions are required to onboard additional users, as
+// creating users and organizations is reserved to operators.
+var onboardUserPermissions = []platform.Permission{
+	{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.UsersResourceType}},
+	{Action: platform.WriteAction, Resource: platform.Resource{Type: platform.OrgsResourceType}},
+}
+
+// handlePostSetupUser is the HTTP handler for the POST /api/v2/setup/user route.
+func (h *SetupHandler) handlePostSetupUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("onboarding user request", zap.String("r", fmt.Sprint(r)))
+	if err := authorizer.VerifyPermissions(ctx, onboardUserPermissions); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	req, err := decodePostSetupRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	results, err := h.OnboardingService.OnboardUser(ctx, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("onboarding user completed", zap.String("results", fmt.Sprint(results)))
+
 	if err := encodeResponse(ctx, w, http.StatusCreated, newOnboardingResponse(results)); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
@@ -107,9 +154,9 @@ type onboardingResponse struct {
 }
 
 func newOnboardingResponse(results *platform.OnboardingResults) *onboardingResponse {
-	// when onboarding the permissions are for all resources and no
-	// specifically named resources.  Therefore, there is no need to
-	// lookup the name.
+	// when onboarding the permissions are for all resources, or all
+	// resources of the new org, and no specifically named resources.
+	// Therefore, there is no need to lookup the name.
 	ps := make([]permissionResponse, len(results.Auth.Permissions))
 	for i, p := range results.Auth.Permissions {
 		ps[i] = permissionResponse{
@@ -140,6 +187,8 @@ func decodePostSetupRequest(ctx context.Context, r *http.Request) (*platform.Onb
 type SetupService struct {
 	Addr               string
 	InsecureSkipVerify bool
+	// Token authenticates the onboarding of additional users.
+	Token string
 }
 
 // IsOnboarding determine if onboarding request is allowed.
@@ -171,7 +220,16 @@ func (s *SetupService) IsOnboarding(ctx context.Context) (bool, error) {
 
 // Generate OnboardingResults.
 func (s *SetupService) Generate(ctx context.Context, or *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
-	u, err := NewURL(s.Addr, setupPath)
+	return s.postSetup(ctx, setupPath, or)
+}
+
+// OnboardUser creates an additional user, org, bucket and token.
+func (s *SetupService) OnboardUser(ctx context.Context, or *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+	return s.postSetup(ctx, setupUserPath, or)
+}
+
+func (s *SetupService) postSetup(ctx context.Context, path string, or *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+	u, err := NewURL(s.Addr, path)
 	if err != nil {
 		return nil, err
 	}
@@ -185,6 +243,9 @@ func (s *SetupService) Generate(ctx context.Context, or *platform.OnboardingRequ
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if s.Token != "" {
+		SetToken(s.Token, req)
+	}
 	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
 
 	resp, err := hc.Do(req)
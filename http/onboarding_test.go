@@ -1,7 +1,9 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
@@ -9,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/inmem"
 	platformtesting "github.com/influxdata/influxdb/testing"
 )
@@ -62,3 +65,116 @@ func initOnboardingService(f platformtesting.OnboardingFields, t *testing.T) (pl
 func TestOnboardingService(t *testing.T) {
 	platformtesting.Generate(initOnboardingService, t)
 }
+
+func TestOnboardingService_Regenerate(t *testing.T) {
+	platformtesting.Regenerate(initOnboardingService, t)
+}
+
+func testOnboardingResults(req *platform.OnboardingRequest) *platform.OnboardingResults {
+	orgID, userID := platformtesting.MustIDBase16("020f755c3c082001"), platformtesting.MustIDBase16("020f755c3c082000")
+	return &platform.OnboardingResults{
+		User:   &platform.User{ID: userID, Name: req.User},
+		Org:    &platform.Organization{ID: orgID, Name: req.Org},
+		Bucket: &platform.Bucket{ID: platformtesting.MustIDBase16("020f755c3c082002"), OrgID: orgID, Name: req.Bucket},
+		Auth: &platform.Authorization{
+			ID:          platformtesting.MustIDBase16("020f755c3c082003"),
+			OrgID:       orgID,
+			UserID:      userID,
+			Status:      platform.Active,
+			Permissions: platform.OnboardUserPermissions(orgID, userID),
+		},
+	}
+}
+
+func TestSetupHandler_handlePostSetup(t *testing.T) {
+	tests := []struct {
+		name         string
+		isOnboarding bool
+		status       int
+	}{
+		{
+			name:         "setup creates the user, org, bucket and token",
+			isOnboarding: true,
+			status:       http.StatusCreated,
+		},
+		{
+			name:   "setup re-run with the same request",
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := mock.NewOnboardingService()
+			svc.IsOnboardingFn = func(context.Context) (bool, error) { return tt.isOnboarding, nil }
+			svc.GenerateFn = func(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+				return testOnboardingResults(req), nil
+			}
+			setupBackend := NewMockSetupBackend()
+			setupBackend.HTTPErrorHandler = ErrorHandler(0)
+			setupBackend.OnboardingService = svc
+			h := NewSetupHandler(setupBackend)
+
+			body := `{"username": "admin", "password": "password1", "org": "org1", "bucket": "bucket1"}`
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/setup", bytes.NewBufferString(body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("got status %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSetupHandler_handlePostSetupUser(t *testing.T) {
+	orgID := platformtesting.MustIDBase16("020f755c3c082001")
+	tests := []struct {
+		name        string
+		permissions []platform.Permission
+		status      int
+	}{
+		{
+			name:        "operators onboard additional users",
+			permissions: platform.OperPermissions(),
+			status:      http.StatusCreated,
+		},
+		{
+			name:        "owners of an organization cannot onboard users",
+			permissions: platform.OwnerPermissions(orgID),
+			status:      http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var onboarded *platform.OnboardingRequest
+			svc := mock.NewOnboardingService()
+			svc.OnboardUserFn = func(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+				onboarded = req
+				return testOnboardingResults(req), nil
+			}
+			setupBackend := NewMockSetupBackend()
+			setupBackend.HTTPErrorHandler = ErrorHandler(0)
+			setupBackend.OnboardingService = svc
+			h := NewSetupHandler(setupBackend)
+
+			body := `{"username": "user2", "password": "password2", "org": "org2", "bucket": "bucket2"}`
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/setup/user", bytes.NewBufferString(body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.status == http.StatusCreated && (onboarded == nil || onboarded.User != "user2") {
+				t.Errorf("expected user2 to be onboarded, got %+v", onboarded)
+			}
+			if tt.status != http.StatusCreated && onboarded != nil {
+				t.Errorf("expected no user to be onboarded, got %+v", onboarded)
+			}
+		})
+	}
+}
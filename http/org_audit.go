@@ -0,0 +1,230 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	organizationsIDAuditPath       = "/api/v2/orgs/:id/audit"
+	organizationsIDAuditExportPath = "/api/v2/orgs/:id/audit/export"
+)
+
+type auditExportResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.AuditExport
+}
+
+func newAuditExportResponse(e *influxdb.AuditExport) *auditExportResponse {
+	return &auditExportResponse{
+		Links: map[string]string{
+			"self":  fmt.Sprintf("/api/v2/orgs/%s/audit/export", e.OrgID),
+			"audit": fmt.Sprintf("/api/v2/orgs/%s/audit", e.OrgID),
+			"org":   fmt.Sprintf("/api/v2/orgs/%s", e.OrgID),
+		},
+		AuditExport: *e,
+	}
+}
+
+// putAuditExportRequest is the audit export of an organization; the export is
+// active unless another status is given.
+type putAuditExportRequest struct {
+	Destination      influxdb.AuditDestination `json:"destination"`
+	SigningSecretKey string                    `json:"signingSecretKey"`
+	Every            string                    `json:"every"`
+	Status           influxdb.Status           `json:"status"`
+}
+
+func (h *OrgHandler) auditUnavailable() *influxdb.Error {
+	if h.AuditService != nil && h.AuditExportService != nil {
+		return nil
+	}
+	return &influxdb.Error{
+		Code: influxdb.EUnavailable,
+		Msg:  "audit records are not available",
+	}
+}
+
+// handleGetOrgAudit is the HTTP handler for the GET /api/v2/orgs/:id/audit
+// route. It exports the audit records of the organization as
+// newline-delimited JSON, oldest first.
+func (h *OrgHandler) handleGetOrgAudit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("audit records retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	if err := h.auditUnavailable(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	filter := influxdb.AuditRecordFilter{OrgID: req.OrgID}
+	qp := r.URL.Query()
+	if after := qp.Get("after"); after != "" {
+		id, err := influxdb.IDFromString(after)
+		if err != nil {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "after must be the ID of an audit record",
+				Err:  err,
+			}, w)
+			return
+		}
+		filter.After = id
+	}
+	if limit := qp.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			h.HandleHTTPError(ctx, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "limit must be a number of records",
+			}, w)
+			return
+		}
+		filter.Limit = n
+	}
+
+	rs, err := h.AuditService.FindAuditRecords(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("audit records retrieved", zap.Int("records", len(rs)))
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(w)
+	for _, rec := range rs {
+		if err := enc.Encode(rec); err != nil {
+			logEncodingError(h.Logger, r, err)
+			return
+		}
+	}
+}
+
+// handleGetOrgAuditExport is the HTTP handler for the GET /api/v2/orgs/:id/audit/export route.
+func (h *OrgHandler) handleGetOrgAuditExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("audit export retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	if err := h.auditUnavailable(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	e, err := h.AuditExportService.FindAuditExport(ctx, req.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAuditExportResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePutOrgAuditExport is the HTTP handler for the PUT /api/v2/orgs/:id/audit/export
+// route. Replacing the export keeps how far it got, so records are not exported twice.
+func (h *OrgHandler) handlePutOrgAuditExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("audit export put request", zap.String("r", fmt.Sprint(r)))
+
+	if err := h.auditUnavailable(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var body putAuditExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode audit export request",
+			Err:  err,
+		}, w)
+		return
+	}
+	if body.Status == "" {
+		body.Status = influxdb.Active
+	}
+
+	e := &influxdb.AuditExport{
+		OrgID:            req.OrgID,
+		Destination:      body.Destination,
+		SigningSecretKey: body.SigningSecretKey,
+		Every:            body.Every,
+		Status:           body.Status,
+	}
+	if err := e.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	prev, err := h.AuditExportService.FindAuditExport(ctx, req.OrgID)
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if prev != nil {
+		e.LastExportedID = prev.LastExportedID
+		e.LastExportedAt = prev.LastExportedAt
+	}
+
+	if err := h.AuditExportService.PutAuditExport(ctx, e); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("audit export put", zap.String("orgID", e.OrgID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAuditExportResponse(e)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteOrgAuditExport is the HTTP handler for the DELETE /api/v2/orgs/:id/audit/export route.
+func (h *OrgHandler) handleDeleteOrgAuditExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("audit export delete request", zap.String("r", fmt.Sprint(r)))
+
+	if err := h.auditUnavailable(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.AuditExportService.DeleteAuditExport(ctx, req.OrgID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("audit export deleted", zap.String("orgID", req.OrgID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestOrgHandler_handleGetOrgAudit(t *testing.T) {
+	var filter platform.AuditRecordFilter
+	as := mock.NewAuditService()
+	as.FindAuditRecordsFn = func(ctx context.Context, f platform.AuditRecordFilter) ([]*platform.AuditRecord, error) {
+		filter = f
+		return []*platform.AuditRecord{
+			{ID: 3, OrgID: 1, Time: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC), Kind: platform.AuditSecret, Action: "put", ResourceType: platform.SecretsResourceType, Key: "token", UserID: 7},
+			{ID: 4, OrgID: 1, Time: time.Date(2019, 7, 1, 0, 1, 0, 0, time.UTC), Kind: platform.AuditResource, Action: "delete", ResourceType: platform.BucketsResourceType, ResourceID: 5},
+		}, nil
+	}
+
+	h := NewOrgHandler(&OrgBackend{
+		HTTPErrorHandler:   ErrorHandler(0),
+		Logger:             zap.NewNop(),
+		AuditService:       as,
+		AuditExportService: as,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/orgs/0000000000000001/audit?after=0000000000000002&limit=10", nil))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if filter.OrgID != 1 || filter.After == nil || *filter.After != 2 || filter.Limit != 10 {
+		t.Errorf("unexpected filter %+v", filter)
+	}
+	if got, want := w.Header().Get("Content-Type"), "application/x-ndjson"; got != want {
+		t.Errorf("got content type %q, want %q", got, want)
+	}
+	want := `{"id":"0000000000000003","orgID":"0000000000000001","time":"2019-07-01T00:00:00Z","kind":"secret","action":"put","resourceType":"secrets","key":"token","userID":"0000000000000007"}
+{"id":"0000000000000004","orgID":"0000000000000001","time":"2019-07-01T00:01:00Z","kind":"resource","action":"delete","resourceType":"buckets","resourceID":"0000000000000005"}
+`
+	if string(body) != want {
+		t.Errorf("got body\n%s\nwant\n%s", body, want)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/orgs/0000000000000001/audit?limit=-1", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestOrgHandler_handlePutOrgAuditExport(t *testing.T) {
+	exportedAt := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	var put *platform.AuditExport
+	as := mock.NewAuditService()
+	as.FindAuditExportFn = func(ctx context.Context, orgID platform.ID) (*platform.AuditExport, error) {
+		return &platform.AuditExport{
+			OrgID:          orgID,
+			Destination:    platform.AuditDestination{Type: platform.AuditDestinationHTTP, URL: "https://old.example.com"},
+			Every:          "1h",
+			Status:         platform.Active,
+			LastExportedID: 9,
+			LastExportedAt: &exportedAt,
+		}, nil
+	}
+	as.PutAuditExportFn = func(ctx context.Context, e *platform.AuditExport) error {
+		put = e
+		return nil
+	}
+
+	h := NewOrgHandler(&OrgBackend{
+		HTTPErrorHandler:   ErrorHandler(0),
+		Logger:             zap.NewNop(),
+		AuditService:       as,
+		AuditExportService: as,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("PUT", "http://any.url/api/v2/orgs/0000000000000001/audit/export", bytes.NewBufferString(`
+{
+  "destination": {"type": "s3", "url": "s3://archive/influxdb"},
+  "signingSecretKey": "audit-signing",
+  "every": "24h"
+}`)))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/orgs/0000000000000001/audit/export",
+    "audit": "/api/v2/orgs/0000000000000001/audit",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "orgID": "0000000000000001",
+  "destination": {"type": "s3", "url": "s3://archive/influxdb"},
+  "signingSecretKey": "audit-signing",
+  "every": "24h",
+  "status": "active",
+  "lastExportedID": "0000000000000009",
+  "lastExportedAt": "2019-07-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+	if put == nil || put.LastExportedID != 9 {
+		t.Errorf("expected the export to be put with how far it got, got %+v", put)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("PUT", "http://any.url/api/v2/orgs/0000000000000001/audit/export", bytes.NewBufferString(`
+{
+  "destination": {"type": "syslog", "url": "https://logs.example.com"},
+  "signingSecretKey": "audit-signing",
+  "every": "24h"
+}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
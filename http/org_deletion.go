@@ -0,0 +1,252 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	orgDeletionsPath   = "/api/v2/orgdeletions"
+	orgDeletionsIDPath = "/api/v2/orgdeletions/:id"
+)
+
+// OrgDeletionBackend is all services and associated parameters required to construct
+// the OrgDeletionHandler.
+type OrgDeletionBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	OrgDeletionService platform.OrgDeletionService
+}
+
+// NewOrgDeletionBackend returns a new instance of OrgDeletionBackend.
+func NewOrgDeletionBackend(b *APIBackend) *OrgDeletionBackend {
+	return &OrgDeletionBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "org_deletion")),
+
+		OrgDeletionService: b.OrgDeletionService,
+	}
+}
+
+// OrgDeletionHandler is the handler for deleting organizations with all of their resources.
+type OrgDeletionHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	OrgDeletionService platform.OrgDeletionService
+}
+
+// NewOrgDeletionHandler returns a new instance of OrgDeletionHandler.
+func NewOrgDeletionHandler(b *OrgDeletionBackend) *OrgDeletionHandler {
+	h := &OrgDeletionHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		OrgDeletionService: b.OrgDeletionService,
+	}
+
+	h.HandlerFunc("POST", orgDeletionsPath, h.handlePostOrgDeletion)
+	h.HandlerFunc("GET", orgDeletionsPath, h.handleGetOrgDeletions)
+	h.HandlerFunc("GET", orgDeletionsIDPath, h.handleGetOrgDeletion)
+	return h
+}
+
+type orgDeletionResponse struct {
+	Links map[string]string `json:"links"`
+	*platform.OrgDeletion
+}
+
+func newOrgDeletionResponse(d *platform.OrgDeletion) *orgDeletionResponse {
+	return &orgDeletionResponse{
+		Links: map[string]string{
+			"self": path.Join(orgDeletionsPath, d.ID.String()),
+			"org":  path.Join(organizationsPath, d.OrgID.String()),
+		},
+		OrgDeletion: d,
+	}
+}
+
+type orgDeletionsResponse struct {
+	Links     map[string]string      `json:"links"`
+	Deletions []*orgDeletionResponse `json:"deletions"`
+}
+
+func newOrgDeletionsResponse(ds []*platform.OrgDeletion) *orgDeletionsResponse {
+	res := &orgDeletionsResponse{
+		Links: map[string]string{
+			"self": orgDeletionsPath,
+		},
+		Deletions: make([]*orgDeletionResponse, 0, len(ds)),
+	}
+	for _, d := range ds {
+		res.Deletions = append(res.Deletions, newOrgDeletionResponse(d))
+	}
+	return res
+}
+
+// handlePostOrgDeletion is the HTTP handler for the POST /api/v2/orgdeletions route.
+// The organization is deleted asynchronously; the response is the handle of the deletion.
+func (h *OrgDeletionHandler) handlePostOrgDeletion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostOrgDeletionRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	d, err := h.OrgDeletionService.DeleteOrganizationResources(ctx, *req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("organization deletion queued", zap.String("deletionID", d.ID.String()), zap.String("orgID", d.OrgID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusAccepted, newOrgDeletionResponse(d)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostOrgDeletionRequest(ctx context.Context, r *http.Request) (*platform.OrgDeletionRequest, error) {
+	req := &platform.OrgDeletionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// handleGetOrgDeletions is the HTTP handler for the GET /api/v2/orgdeletions route.
+func (h *OrgDeletionHandler) handleGetOrgDeletions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetOrgDeletionsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ds, err := h.OrgDeletionService.FindOrgDeletions(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgDeletionsResponse(ds)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetOrgDeletionsRequest(ctx context.Context, r *http.Request) (*platform.OrgDeletionFilter, error) {
+	qp := r.URL.Query()
+	filter := &platform.OrgDeletionFilter{}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		filter.OrgID = id
+	}
+
+	return filter, nil
+}
+
+// handleGetOrgDeletion is the HTTP handler for the GET /api/v2/orgdeletions/:id route.
+func (h *OrgDeletionHandler) handleGetOrgDeletion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	d, err := h.OrgDeletionService.FindOrgDeletionByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgDeletionResponse(d)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// OrgDeletionService connects to Influx via HTTP using tokens to delete organizations.
+type OrgDeletionService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.OrgDeletionService = (*OrgDeletionService)(nil)
+
+// DeleteOrganizationResources starts deleting the organization of the request and returns the handle of the deletion.
+func (s *OrgDeletionService) DeleteOrganizationResources(ctx context.Context, req platform.OrgDeletionRequest) (*platform.OrgDeletion, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var d platform.OrgDeletion
+	if err := s.client().do(ctx, "POST", orgDeletionsPath, nil, req, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// FindOrgDeletionByID returns a single deletion by ID.
+func (s *OrgDeletionService) FindOrgDeletionByID(ctx context.Context, id platform.ID) (*platform.OrgDeletion, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var d platform.OrgDeletion
+	if err := s.client().do(ctx, "GET", path.Join(orgDeletionsPath, id.String()), nil, nil, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// FindOrgDeletions returns the deletions that match filter, most recent first.
+func (s *OrgDeletionService) FindOrgDeletions(ctx context.Context, filter platform.OrgDeletionFilter) ([]*platform.OrgDeletion, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	qp := url.Values{}
+	if filter.OrgID != nil {
+		qp.Set("orgID", filter.OrgID.String())
+	}
+
+	var res struct {
+		Deletions []*platform.OrgDeletion `json:"deletions"`
+	}
+	if err := s.client().do(ctx, "GET", orgDeletionsPath, qp, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Deletions, nil
+}
+
+func (s *OrgDeletionService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
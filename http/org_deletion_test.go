@@ -0,0 +1,188 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newOrgDeletionTestHandler(ds platform.OrgDeletionService) *OrgDeletionHandler {
+	return NewOrgDeletionHandler(&OrgDeletionBackend{
+		HTTPErrorHandler:   ErrorHandler(0),
+		Logger:             zap.NewNop(),
+		OrgDeletionService: ds,
+	})
+}
+
+func TestOrgDeletionHandler_handlePostOrgDeletion(t *testing.T) {
+	createdAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	var gotReq *platform.OrgDeletionRequest
+	ds := mock.NewOrgDeletionService()
+	ds.DeleteOrganizationResourcesFn = func(ctx context.Context, req platform.OrgDeletionRequest) (*platform.OrgDeletion, error) {
+		if req.OrgID != 2 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "organization not found"}
+		}
+		gotReq = &req
+		return &platform.OrgDeletion{
+			ID:                 3,
+			OrgDeletionRequest: req,
+			OrgName:            "acme",
+			Status:             platform.OrgDeletionStatusQueued,
+			Stages:             platform.NewOrgDeletionStages(req),
+			CreatedAt:          createdAt,
+		}, nil
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "delete org with export",
+			body:       `{"orgID": "0000000000000002", "export": true}`,
+			wantStatus: http.StatusAccepted,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/orgdeletions/0000000000000003",
+    "org": "/api/v2/orgs/0000000000000002"
+  },
+  "id": "0000000000000003",
+  "orgID": "0000000000000002",
+  "orgName": "acme",
+  "export": true,
+  "status": "queued",
+  "stages": [
+    {"name": "export", "status": "queued", "total": 0, "done": 0},
+    {"name": "tasks", "status": "queued", "total": 0, "done": 0},
+    {"name": "tokens", "status": "queued", "total": 0, "done": 0},
+    {"name": "buckets", "status": "queued", "total": 0, "done": 0},
+    {"name": "mappings", "status": "queued", "total": 0, "done": 0},
+    {"name": "organization", "status": "queued", "total": 0, "done": 0}
+  ],
+  "createdAt": "2019-07-01T12:00:00Z"
+}`,
+		},
+		{
+			name:       "missing orgID",
+			body:       `{"export": true}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "org not found",
+			body:       `{"orgID": "0000000000000009"}`,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotReq = nil
+			h := newOrgDeletionTestHandler(ds)
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/orgdeletions", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusAccepted {
+				if gotReq != nil {
+					t.Fatal("no organization should be deleted for an invalid request")
+				}
+				return
+			}
+
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+				t.Errorf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("***%s***", diff)
+			}
+		})
+	}
+}
+
+func TestOrgDeletionService_Client(t *testing.T) {
+	createdAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	d := &platform.OrgDeletion{
+		ID:                 3,
+		OrgDeletionRequest: platform.OrgDeletionRequest{OrgID: 2},
+		OrgName:            "acme",
+		Status:             platform.OrgDeletionStatusFailed,
+		Stages: []platform.OrgDeletionStage{
+			{Name: platform.OrgDeletionStageTasks, Status: platform.OrgDeletionStatusSuccess, Total: 2, Done: 2},
+			{Name: platform.OrgDeletionStageTokens, Status: platform.OrgDeletionStatusFailed, Total: 3, Done: 1},
+		},
+		Error:      "disk is read-only",
+		CreatedAt:  createdAt,
+		StartedAt:  &createdAt,
+		FinishedAt: &createdAt,
+	}
+
+	var gotFilter platform.OrgDeletionFilter
+	ds := mock.NewOrgDeletionService()
+	ds.DeleteOrganizationResourcesFn = func(ctx context.Context, req platform.OrgDeletionRequest) (*platform.OrgDeletion, error) {
+		return &platform.OrgDeletion{ID: 3, OrgDeletionRequest: req, Status: platform.OrgDeletionStatusQueued, CreatedAt: createdAt}, nil
+	}
+	ds.FindOrgDeletionByIDFn = func(ctx context.Context, id platform.ID) (*platform.OrgDeletion, error) {
+		if id != d.ID {
+			return nil, platform.ErrOrgDeletionNotFound
+		}
+		return d, nil
+	}
+	ds.FindOrgDeletionsFn = func(ctx context.Context, filter platform.OrgDeletionFilter) ([]*platform.OrgDeletion, error) {
+		gotFilter = filter
+		return []*platform.OrgDeletion{d}, nil
+	}
+
+	server := httptest.NewServer(newOrgDeletionTestHandler(ds))
+	defer server.Close()
+	client := OrgDeletionService{Addr: server.URL}
+	ctx := context.Background()
+
+	queued, err := client.DeleteOrganizationResources(ctx, platform.OrgDeletionRequest{OrgID: 2, Export: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued.ID != 3 || queued.OrgID != 2 || !queued.Export || queued.Status != platform.OrgDeletionStatusQueued {
+		t.Errorf("unexpected queued deletion %+v", queued)
+	}
+
+	got, err := client.FindOrgDeletionByID(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != platform.OrgDeletionStatusFailed || got.Error != d.Error || len(got.Stages) != 2 || got.Stages[1].Done != 1 {
+		t.Errorf("unexpected deletion %+v", got)
+	}
+
+	if _, err := client.FindOrgDeletionByID(ctx, 4); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	orgID := platform.ID(2)
+	deletions, err := client.FindOrgDeletions(ctx, platform.OrgDeletionFilter{OrgID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deletions) != 1 || deletions[0].ID != 3 {
+		t.Errorf("unexpected deletions %+v", deletions)
+	}
+	if gotFilter.OrgID == nil || *gotFilter.OrgID != 2 {
+		t.Errorf("unexpected filter %+v", gotFilter)
+	}
+}
@@ -0,0 +1,324 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	organizationsIDInvitesPath   = "/api/v2/orgs/:id/invites"
+	organizationsIDInvitesIDPath = "/api/v2/orgs/:id/invites/:inviteID"
+
+	invitesTokenPath    = "/api/v2/invites/:token"
+	invitesAcceptPath   = "/api/v2/invites/:token/accept"
+	invitesTokenPattern = "/api/v2/invites/%s"
+)
+
+type inviteResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Invite
+	// AcceptURL is the absolute URL of the invite, to be sent to the invitee.
+	AcceptURL string `json:"acceptURL,omitempty"`
+}
+
+func newInviteResponse(r *http.Request, i *influxdb.Invite) *inviteResponse {
+	res := &inviteResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/orgs/%s/invites/%s", i.OrgID, i.ID),
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", i.OrgID),
+		},
+		Invite: *i,
+	}
+	if i.Status == influxdb.InvitePending {
+		res.Links["accept"] = fmt.Sprintf(invitesTokenPattern+"/accept", i.Token)
+		res.AcceptURL = inviteAcceptURL(r, i.Token)
+	}
+	return res
+}
+
+// inviteAcceptURL is the URL of the invite with the token on the host the request was sent to.
+func inviteAcceptURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s"+invitesTokenPattern, scheme, r.Host, token)
+}
+
+type invitesResponse struct {
+	Links   map[string]string `json:"links"`
+	Invites []*inviteResponse `json:"invites"`
+}
+
+// postInviteRequest invites the email as a member of the organization, unless another role is given.
+type postInviteRequest struct {
+	Email     string            `json:"email"`
+	Role      influxdb.UserType `json:"role"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+// handlePostOrgInvite is the HTTP handler for the POST /api/v2/orgs/:id/invites route.
+func (h *OrgHandler) handlePostOrgInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("invite create request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var body postInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode invite request",
+			Err:  err,
+		}, w)
+		return
+	}
+	if body.Role == "" {
+		body.Role = influxdb.Member
+	}
+
+	i := &influxdb.Invite{
+		OrgID:     req.OrgID,
+		Email:     body.Email,
+		Role:      body.Role,
+		ExpiresAt: body.ExpiresAt,
+	}
+	if err := i.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.InviteService.CreateInvite(ctx, i); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("invite created", zap.String("inviteID", i.ID.String()), zap.String("orgID", i.OrgID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newInviteResponse(r, i)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetOrgInvites is the HTTP handler for the GET /api/v2/orgs/:id/invites route.
+func (h *OrgHandler) handleGetOrgInvites(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("invites retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	is, err := h.InviteService.FindInvites(ctx, influxdb.InviteFilter{OrgID: &req.OrgID})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("invites retrieved", zap.Int("invites", len(is)))
+
+	res := &invitesResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/orgs/%s/invites", req.OrgID),
+		},
+		Invites: make([]*inviteResponse, 0, len(is)),
+	}
+	for _, i := range is {
+		res.Invites = append(res.Invites, newInviteResponse(r, i))
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteOrgInvite is the HTTP handler for the DELETE /api/v2/orgs/:id/invites/:inviteID route.
+func (h *OrgHandler) handleDeleteOrgInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("invite revoke request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	id, err := decodeIDParam(ctx, "inviteID")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	i, err := h.InviteService.FindInviteByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if i.OrgID != req.OrgID {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrInviteNotFound,
+		}, w)
+		return
+	}
+
+	if err := h.InviteService.RevokeInvite(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("invite revoked", zap.String("inviteID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// InviteBackend is all services and associated parameters required to construct
+// the InviteHandler.
+type InviteBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	InviteService       influxdb.InviteService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewInviteBackend returns a new instance of InviteBackend.
+func NewInviteBackend(b *APIBackend) *InviteBackend {
+	return &InviteBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "invite")),
+
+		InviteService:       b.InviteService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// InviteHandler serves the invites to those who were sent them. The token in
+// the path is the credential of those requests, so they are not authenticated.
+type InviteHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	InviteService       influxdb.InviteService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewInviteHandler returns a new instance of InviteHandler.
+func NewInviteHandler(b *InviteBackend) *InviteHandler {
+	h := &InviteHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		InviteService:       b.InviteService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", invitesTokenPath, h.handleGetInvite)
+	h.HandlerFunc("POST", invitesAcceptPath, h.handlePostInviteAccept)
+	return h
+}
+
+// inviteDetailsResponse is what the invitee is shown of an invite.
+type inviteDetailsResponse struct {
+	Links     map[string]string     `json:"links"`
+	OrgID     influxdb.ID           `json:"orgID"`
+	OrgName   string                `json:"orgName"`
+	Email     string                `json:"email"`
+	Role      influxdb.UserType     `json:"role"`
+	Status    influxdb.InviteStatus `json:"status"`
+	ExpiresAt time.Time             `json:"expiresAt"`
+}
+
+// handleGetInvite is the HTTP handler for the GET /api/v2/invites/:token route.
+func (h *InviteHandler) handleGetInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token := httprouter.ParamsFromContext(ctx).ByName("token")
+	i, err := h.InviteService.FindInviteByToken(ctx, token)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, i.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := &inviteDetailsResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf(invitesTokenPattern, token),
+			"accept": fmt.Sprintf(invitesTokenPattern+"/accept", token),
+		},
+		OrgID:     i.OrgID,
+		OrgName:   o.Name,
+		Email:     i.Email,
+		Role:      i.Role,
+		Status:    i.Status,
+		ExpiresAt: i.ExpiresAt,
+	}
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostInviteAccept is the HTTP handler for the POST /api/v2/invites/:token/accept route.
+// It creates the user of the invite, who can then sign in.
+func (h *InviteHandler) handlePostInviteAccept(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := decodePostInviteAcceptRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	token := httprouter.ParamsFromContext(ctx).ByName("token")
+	u, err := h.InviteService.AcceptInvite(ctx, token, *a)
+	if err != nil {
+		if err := encodePasswordError(ctx, h, w, err); err != nil {
+			logEncodingError(h.Logger, r, err)
+		}
+		return
+	}
+	h.Logger.Debug("invite accepted", zap.String("userID", u.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newUserResponse(u)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostInviteAcceptRequest(ctx context.Context, r *http.Request) (*influxdb.InviteAcceptance, error) {
+	a := &influxdb.InviteAcceptance{}
+	if err := json.NewDecoder(r.Body).Decode(a); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode invite acceptance",
+			Err:  err,
+		}
+	}
+	if a.Password == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "a password is required",
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestOrgHandler_handlePostOrgInvite(t *testing.T) {
+	expiresAt := time.Date(2019, 7, 8, 12, 0, 0, 0, time.UTC)
+	is := mock.NewInviteService()
+	is.CreateInviteFn = func(ctx context.Context, i *platform.Invite) error {
+		i.ID = 2
+		i.Token = "secret"
+		i.Status = platform.InvitePending
+		i.ExpiresAt = expiresAt
+		i.CreatedAt = expiresAt.Add(-platform.DefaultInviteTTL)
+		return nil
+	}
+
+	h := NewOrgHandler(&OrgBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zap.NewNop(),
+		InviteService:    is,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://influx.example.com/api/v2/orgs/0000000000000001/invites", bytes.NewBufferString(`{"email": "ada@example.com"}`)))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/orgs/0000000000000001/invites/0000000000000002",
+    "org": "/api/v2/orgs/0000000000000001",
+    "accept": "/api/v2/invites/secret/accept"
+  },
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "email": "ada@example.com",
+  "role": "member",
+  "token": "secret",
+  "status": "pending",
+  "expiresAt": "2019-07-08T12:00:00Z",
+  "createdAt": "2019-07-01T12:00:00Z",
+  "acceptURL": "http://influx.example.com/api/v2/invites/secret"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://influx.example.com/api/v2/orgs/0000000000000001/invites", bytes.NewBufferString(`{"email": "ada@example.com", "role": "admin"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestInviteHandler_handlePostInviteAccept(t *testing.T) {
+	var accepted platform.InviteAcceptance
+	is := mock.NewInviteService()
+	is.AcceptInviteFn = func(ctx context.Context, token string, a platform.InviteAcceptance) (*platform.User, error) {
+		if token != "secret" {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrInviteNotFound}
+		}
+		accepted = a
+		return &platform.User{ID: 3, Name: "ada"}, nil
+	}
+
+	h := NewInviteHandler(&InviteBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zap.NewNop(),
+		InviteService:    is,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/invites/secret/accept", bytes.NewBufferString(`{"name": "ada", "password": "password1"}`)))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if accepted.Name != "ada" || accepted.Password != "password1" {
+		t.Errorf("unexpected acceptance %+v", accepted)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/invites/other/accept", bytes.NewBufferString(`{"password": "password1"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
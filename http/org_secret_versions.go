@@ -0,0 +1,129 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	organizationsIDSecretsVersionsPath = "/api/v2/orgs/:id/secrets/versions"
+	organizationsIDSecretsRotatePath   = "/api/v2/orgs/:id/secrets/rotate"
+)
+
+type secretVersionsResponse struct {
+	Links    map[string]string         `json:"links"`
+	Versions []*influxdb.SecretVersion `json:"versions"`
+}
+
+func newSecretVersionsResponse(orgID influxdb.ID, k string, vs []*influxdb.SecretVersion) *secretVersionsResponse {
+	return &secretVersionsResponse{
+		Links: map[string]string{
+			"self":    fmt.Sprintf("/api/v2/orgs/%s/secrets/versions?key=%s", orgID, url.QueryEscape(k)),
+			"org":     fmt.Sprintf("/api/v2/orgs/%s", orgID),
+			"secrets": fmt.Sprintf("/api/v2/orgs/%s/secrets", orgID),
+		},
+		Versions: vs,
+	}
+}
+
+func (h *OrgHandler) secretRotationUnavailable(ctx context.Context, w http.ResponseWriter) bool {
+	if h.SecretRotationService != nil {
+		return false
+	}
+	h.HandleHTTPError(ctx, &influxdb.Error{
+		Code: influxdb.EUnavailable,
+		Msg:  influxdb.ErrSecretRotationNotSupported,
+	}, w)
+	return true
+}
+
+// handleGetSecretVersions is the HTTP handler for the GET /api/v2/orgs/:id/secrets/versions route.
+func (h *OrgHandler) handleGetSecretVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if h.secretRotationUnavailable(ctx, w) {
+		return
+	}
+
+	req, err := decodeGetSecretsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	k := r.URL.Query().Get("key")
+	if k == "" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "key is required",
+		}, w)
+		return
+	}
+
+	vs, err := h.SecretRotationService.FindSecretVersions(ctx, req.orgID, k)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSecretVersionsResponse(req.orgID, k, vs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// rotateSecretRequest is the body of a POST to /api/v2/orgs/:id/secrets/rotate.
+type rotateSecretRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// handlePostSecretRotate is the HTTP handler for the POST /api/v2/orgs/:id/secrets/rotate route.
+func (h *OrgHandler) handlePostSecretRotate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if h.secretRotationUnavailable(ctx, w) {
+		return
+	}
+
+	req, err := decodeGetSecretsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var body rotateSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}, w)
+		return
+	}
+	if body.Key == "" {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "key is required",
+		}, w)
+		return
+	}
+
+	v, err := h.SecretRotationService.RotateSecret(ctx, req.orgID, body.Key, body.Value)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("secret rotated", zap.String("orgID", req.orgID.String()), zap.String("key", body.Key), zap.Int("version", v.Version))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, v); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
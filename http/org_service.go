@@ -25,8 +25,13 @@ type OrgBackend struct {
 	OrganizationOperationLogService influxdb.OrganizationOperationLogService
 	UserResourceMappingService      influxdb.UserResourceMappingService
 	SecretService                   influxdb.SecretService
+	SecretRotationService           influxdb.SecretRotationService
 	LabelService                    influxdb.LabelService
 	UserService                     influxdb.UserService
+	OrgUsageService                 influxdb.OrgUsageService
+	InviteService                   influxdb.InviteService
+	AuditService                    influxdb.AuditService
+	AuditExportService              influxdb.AuditExportService
 }
 
 // NewOrgBackend is a datasource used by the org handler.
@@ -39,8 +44,13 @@ func NewOrgBackend(b *APIBackend) *OrgBackend {
 		OrganizationOperationLogService: b.OrganizationOperationLogService,
 		UserResourceMappingService:      b.UserResourceMappingService,
 		SecretService:                   b.SecretService,
+		SecretRotationService:           b.SecretRotationService,
 		LabelService:                    b.LabelService,
 		UserService:                     b.UserService,
+		OrgUsageService:                 b.OrgUsageService,
+		InviteService:                   b.InviteService,
+		AuditService:                    b.AuditService,
+		AuditExportService:              b.AuditExportService,
 	}
 }
 
@@ -54,8 +64,13 @@ type OrgHandler struct {
 	OrganizationOperationLogService influxdb.OrganizationOperationLogService
 	UserResourceMappingService      influxdb.UserResourceMappingService
 	SecretService                   influxdb.SecretService
+	SecretRotationService           influxdb.SecretRotationService
 	LabelService                    influxdb.LabelService
 	UserService                     influxdb.UserService
+	OrgUsageService                 influxdb.OrgUsageService
+	InviteService                   influxdb.InviteService
+	AuditService                    influxdb.AuditService
+	AuditExportService              influxdb.AuditExportService
 }
 
 const (
@@ -84,8 +99,13 @@ func NewOrgHandler(b *OrgBackend) *OrgHandler {
 		OrganizationOperationLogService: b.OrganizationOperationLogService,
 		UserResourceMappingService:      b.UserResourceMappingService,
 		SecretService:                   b.SecretService,
+		SecretRotationService:           b.SecretRotationService,
 		LabelService:                    b.LabelService,
 		UserService:                     b.UserService,
+		OrgUsageService:                 b.OrgUsageService,
+		InviteService:                   b.InviteService,
+		AuditService:                    b.AuditService,
+		AuditExportService:              b.AuditExportService,
 	}
 
 	h.HandlerFunc("POST", organizationsPath, h.handlePostOrg)
@@ -94,6 +114,16 @@ func NewOrgHandler(b *OrgBackend) *OrgHandler {
 	h.HandlerFunc("GET", organizationsIDLogPath, h.handleGetOrgLog)
 	h.HandlerFunc("PATCH", organizationsIDPath, h.handlePatchOrg)
 	h.HandlerFunc("DELETE", organizationsIDPath, h.handleDeleteOrg)
+	h.HandlerFunc("GET", organizationsIDSettingsPath, h.handleGetOrgSettings)
+	h.HandlerFunc("PATCH", organizationsIDSettingsPath, h.handlePatchOrgSettings)
+	h.HandlerFunc("GET", organizationsIDUsagePath, h.handleGetOrgUsage)
+	h.HandlerFunc("POST", organizationsIDInvitesPath, h.handlePostOrgInvite)
+	h.HandlerFunc("GET", organizationsIDInvitesPath, h.handleGetOrgInvites)
+	h.HandlerFunc("DELETE", organizationsIDInvitesIDPath, h.handleDeleteOrgInvite)
+	h.HandlerFunc("GET", organizationsIDAuditPath, h.handleGetOrgAudit)
+	h.HandlerFunc("GET", organizationsIDAuditExportPath, h.handleGetOrgAuditExport)
+	h.HandlerFunc("PUT", organizationsIDAuditExportPath, h.handlePutOrgAuditExport)
+	h.HandlerFunc("DELETE", organizationsIDAuditExportPath, h.handleDeleteOrgAuditExport)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -123,6 +153,8 @@ func NewOrgHandler(b *OrgBackend) *OrgHandler {
 	h.HandlerFunc("PATCH", organizationsIDSecretsPath, h.handlePatchSecrets)
 	// TODO(desa): need a way to specify which secrets to delete. this should work for now
 	h.HandlerFunc("POST", organizationsIDSecretsDeletePath, h.handleDeleteSecrets)
+	h.HandlerFunc("GET", organizationsIDSecretsVersionsPath, h.handleGetSecretVersions)
+	h.HandlerFunc("POST", organizationsIDSecretsRotatePath, h.handlePostSecretRotate)
 
 	labelBackend := &LabelBackend{
 		HTTPErrorHandler: b.HTTPErrorHandler,
@@ -176,6 +208,7 @@ func newOrgResponse(o *influxdb.Organization) *orgResponse {
 			"members":    fmt.Sprintf("/api/v2/orgs/%s/members", o.ID),
 			"owners":     fmt.Sprintf("/api/v2/orgs/%s/owners", o.ID),
 			"secrets":    fmt.Sprintf("/api/v2/orgs/%s/secrets", o.ID),
+			"settings":   fmt.Sprintf("/api/v2/orgs/%s/settings", o.ID),
 			"labels":     fmt.Sprintf("/api/v2/orgs/%s/labels", o.ID),
 			"buckets":    fmt.Sprintf("/api/v2/buckets?org=%s", o.Name),
 			"tasks":      fmt.Sprintf("/api/v2/tasks?org=%s", o.Name),
@@ -298,14 +331,30 @@ func (h *OrgHandler) handleGetOrgs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orgs, _, err := h.OrganizationService.FindOrganizations(ctx, req.filter)
+	orgs, _, err := h.OrganizationService.FindOrganizations(ctx, req.filter, req.labels.findOptions(req.opts))
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+
+	if len(req.labels) > 0 {
+		idx, err := req.labels.filter(ctx, h.LabelService, req.opts, len(orgs), func(i int) influxdb.ID { return orgs[i].ID })
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		labeled := make([]*influxdb.Organization, 0, len(idx))
+		for _, i := range idx {
+			labeled = append(labeled, orgs[i])
+		}
+		orgs = labeled
+	}
 	h.Logger.Debug("orgs retrieved", zap.String("org", fmt.Sprint(orgs)))
 
-	if err := encodeResponse(ctx, w, http.StatusOK, newOrgsResponse(orgs)); err != nil {
+	links := newPagingLinks(organizationsPath, req.opts, req.labels.pagingFilter(req.filter), len(orgs))
+	res := newOrgsResponse(orgs)
+	addPagingLinks(res.Links, links)
+	if err := encodeListResponse(ctx, w, req.opts.Fields, links, "orgs", res); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
 	}
@@ -313,12 +362,23 @@ func (h *OrgHandler) handleGetOrgs(w http.ResponseWriter, r *http.Request) {
 
 type getOrgsRequest struct {
 	filter influxdb.OrganizationFilter
+	opts   influxdb.FindOptions
+	labels labelFilter
 }
 
 func decodeGetOrgsRequest(ctx context.Context, r *http.Request) (*getOrgsRequest, error) {
 	qp := r.URL.Query()
 	req := &getOrgsRequest{}
 
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.ValidSortBy(influxdb.OrganizationSortKeys...); err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
 	if orgID := qp.Get(OrgID); orgID != "" {
 		id, err := influxdb.IDFromString(orgID)
 		if err != nil {
@@ -331,6 +391,8 @@ func decodeGetOrgsRequest(ctx context.Context, r *http.Request) (*getOrgsRequest
 		req.filter.Name = &name
 	}
 
+	req.labels = decodeLabelFilter(r)
+
 	return req, nil
 }
 
@@ -665,6 +727,14 @@ func (s *OrganizationService) FindOrganizations(ctx context.Context, filter infl
 	if filter.ID != nil {
 		qp.Add(OrgID, filter.ID.String())
 	}
+
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			for _, v := range vs {
+				qp.Add(k, v)
+			}
+		}
+	}
 	url.RawQuery = qp.Encode()
 
 	req, err := http.NewRequest("GET", url.String(), nil)
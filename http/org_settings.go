@@ -0,0 +1,164 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const organizationsIDSettingsPath = "/api/v2/orgs/:id/settings"
+
+// orgSettings are the settings of an organization that apply to its resources.
+type orgSettings struct {
+	Timezone       string            `json:"timezone"`
+	BucketDefaults orgBucketDefaults `json:"bucketDefaults"`
+	// NamePolicy is null if the organization has the name policy of the server.
+	NamePolicy *influxdb.NamePolicy `json:"namePolicy"`
+}
+
+// orgBucketDefaults are the values buckets of an organization are created with,
+// with the retention period as retention rules like in bucket requests.
+type orgBucketDefaults struct {
+	RetentionRules []retentionRule `json:"retentionRules"`
+}
+
+func (d *orgBucketDefaults) toInfluxDB() (*influxdb.BucketDefaults, error) {
+	bd := &influxdb.BucketDefaults{}
+	// Only support a single retention period for the moment
+	if len(d.RetentionRules) > 0 {
+		bd.RetentionPeriod = time.Duration(d.RetentionRules[0].EverySeconds) * time.Second
+		if bd.RetentionPeriod < time.Second {
+			return nil, &influxdb.Error{
+				Code: influxdb.EUnprocessableEntity,
+				Msg:  "expiration seconds must be greater than or equal to one second",
+			}
+		}
+	}
+	return bd, nil
+}
+
+func newOrgBucketDefaults(d *influxdb.BucketDefaults) orgBucketDefaults {
+	rules := []retentionRule{}
+	if d != nil && d.RetentionPeriod > 0 {
+		rules = append(rules, retentionRule{
+			Type:         "expire",
+			EverySeconds: int64(d.RetentionPeriod.Round(time.Second) / time.Second),
+		})
+	}
+	return orgBucketDefaults{RetentionRules: rules}
+}
+
+type orgSettingsResponse struct {
+	Links map[string]string `json:"links"`
+	orgSettings
+}
+
+func newOrgSettingsResponse(o *influxdb.Organization) *orgSettingsResponse {
+	return &orgSettingsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/orgs/%s/settings", o.ID),
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", o.ID),
+		},
+		orgSettings: orgSettings{
+			Timezone:       o.Timezone,
+			BucketDefaults: newOrgBucketDefaults(o.BucketDefaults),
+			NamePolicy:     o.NamePolicy,
+		},
+	}
+}
+
+// handleGetOrgSettings is the HTTP handler for the GET /api/v2/orgs/:id/settings route.
+func (h *OrgHandler) handleGetOrgSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, req.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgSettingsResponse(o)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchOrgSettings is the HTTP handler for the PATCH /api/v2/orgs/:id/settings route.
+func (h *OrgHandler) handlePatchOrgSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePatchOrgSettingsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.UpdateOrganization(ctx, req.OrgID, req.Update)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("org settings updated", zap.String("org", fmt.Sprint(o)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgSettingsResponse(o)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// orgSettingsUpdate updates the settings that are set.
+type orgSettingsUpdate struct {
+	Timezone *string `json:"timezone,omitempty"`
+	// BucketDefaults replaces the bucket defaults; empty retention rules remove the default retention.
+	BucketDefaults *orgBucketDefaults `json:"bucketDefaults,omitempty"`
+	// NamePolicy replaces the name policy; a policy with no unique names restores the policy of the server.
+	NamePolicy *influxdb.NamePolicy `json:"namePolicy,omitempty"`
+}
+
+func decodePatchOrgSettingsRequest(ctx context.Context, r *http.Request) (*patchOrgRequest, error) {
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var s orgSettingsUpdate
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid org settings",
+			Err:  err,
+		}
+	}
+
+	upd := influxdb.OrganizationUpdate{
+		Timezone:   s.Timezone,
+		NamePolicy: s.NamePolicy,
+	}
+	if s.BucketDefaults != nil {
+		if upd.BucketDefaults, err = s.BucketDefaults.toInfluxDB(); err != nil {
+			return nil, err
+		}
+	}
+	if upd.Timezone == nil && upd.BucketDefaults == nil && upd.NamePolicy == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "org settings update has no settings",
+		}
+	}
+
+	return &patchOrgRequest{
+		Update: upd,
+		OrgID:  req.OrgID,
+	}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -342,3 +343,112 @@ func TestSecretService_handleDeleteSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestService_handlePatchOrgSettings(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	o := &platform.Organization{Name: "theorg"}
+	if err := svc.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+
+	orgBackend := NewMockOrgBackend()
+	orgBackend.HTTPErrorHandler = ErrorHandler(0)
+	orgBackend.OrganizationService = svc
+	h := NewOrgHandler(orgBackend)
+
+	u := fmt.Sprintf("http://any.url/api/v2/orgs/%s/settings", o.ID)
+	body := `{"timezone": "Europe/Berlin", "bucketDefaults": {"retentionRules": [{"type": "expire", "everySeconds": 3600}]}}`
+	r := httptest.NewRequest("PATCH", u, bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("handlePatchOrgSettings() = %v, want %v: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	want := fmt.Sprintf(`
+{
+  "links": {
+    "self": "/api/v2/orgs/%[1]s/settings",
+    "org": "/api/v2/orgs/%[1]s"
+  },
+  "timezone": "Europe/Berlin",
+  "bucketDefaults": {
+    "retentionRules": [{"type": "expire", "everySeconds": 3600}]
+  },
+  "namePolicy": null
+}
+`, o.ID)
+	r = httptest.NewRequest("GET", u, nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if eq, diff, err := jsonEqual(w.Body.String(), want); err != nil || !eq {
+		t.Fatalf("handleGetOrgSettings() = ***%s*** (%v)", diff, err)
+	}
+
+	// Buckets created without retention rules get the default retention.
+	bucketBackend := NewMockBucketBackend()
+	bucketBackend.HTTPErrorHandler = ErrorHandler(0)
+	bucketBackend.BucketService = svc
+	bucketBackend.OrganizationService = svc
+	bh := NewBucketHandler(bucketBackend)
+	for _, tt := range []struct {
+		body string
+		want time.Duration
+	}{
+		{body: `{"name": "defaulted", "orgID": "%s"}`, want: time.Hour},
+		{body: `{"name": "infinite", "orgID": "%s", "retentionRules": []}`, want: platform.InfiniteRetention},
+	} {
+		r := httptest.NewRequest("POST", "http://any.url/api/v2/buckets", bytes.NewBufferString(fmt.Sprintf(tt.body, o.ID)))
+		w := httptest.NewRecorder()
+		bh.handlePostBucket(w, r)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("handlePostBucket() = %v, want %v: %s", w.Code, http.StatusCreated, w.Body.String())
+		}
+		var b bucket
+		if err := json.NewDecoder(w.Body).Decode(&b); err != nil {
+			t.Fatal(err)
+		}
+		pb, err := b.toInfluxDB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pb.RetentionPeriod != tt.want {
+			t.Errorf("bucket %q has retention %v, want %v", b.Name, pb.RetentionPeriod, tt.want)
+		}
+	}
+
+	// Empty retention rules remove the default retention.
+	r = httptest.NewRequest("PATCH", u, bytes.NewBufferString(`{"bucketDefaults": {"retentionRules": []}}`))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("handlePatchOrgSettings() = %v, want %v: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got, err := svc.FindOrganizationByID(ctx, o.ID); err != nil || got.BucketDefaults != nil {
+		t.Fatalf("expected the bucket defaults to be removed, got %+v (%v)", got, err)
+	}
+
+	// The name policy must keep bucket names unique.
+	for _, tt := range []struct {
+		body string
+		code int
+	}{
+		{body: `{"namePolicy": {"uniqueNames": ["tasks"]}}`, code: http.StatusBadRequest},
+		{body: `{"namePolicy": {"uniqueNames": ["buckets", "dashboards"]}}`, code: http.StatusOK},
+	} {
+		r = httptest.NewRequest("PATCH", u, bytes.NewBufferString(tt.body))
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.code {
+			t.Fatalf("handlePatchOrgSettings(%s) = %v, want %v: %s", tt.body, w.Code, tt.code, w.Body.String())
+		}
+	}
+	got, err := svc.FindOrganizationByID(ctx, o.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NamePolicy == nil || !got.NamePolicy.Unique(platform.DashboardsResourceType) {
+		t.Fatalf("expected dashboard names to be unique, got %+v", got.NamePolicy)
+	}
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const organizationsIDUsagePath = "/api/v2/orgs/:id/usage"
+
+// orgUsageDefaultRange is the time range of the usage when no start is given.
+const orgUsageDefaultRange = 30 * 24 * time.Hour
+
+type orgUsageResponse struct {
+	Links map[string]string `json:"links"`
+	*influxdb.OrgUsageReport
+}
+
+func newOrgUsageResponse(u *influxdb.OrgUsageReport) *orgUsageResponse {
+	return &orgUsageResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/orgs/%s/usage", u.OrgID),
+			"org":  fmt.Sprintf("/api/v2/orgs/%s", u.OrgID),
+		},
+		OrgUsageReport: u,
+	}
+}
+
+// handleGetOrgUsage is the HTTP handler for the GET /api/v2/orgs/:id/usage route.
+// It returns the usage recorded for the organization in the requested time range.
+func (h *OrgHandler) handleGetOrgUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := decodeGetOrgUsageRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.OrgUsageService == nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "organization usage is not available",
+		}, w)
+		return
+	}
+
+	if _, err := h.OrganizationService.FindOrganizationByID(ctx, filter.OrgID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	usage, err := h.OrgUsageService.FindOrgUsage(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.Logger.Debug("org usage retrieved", zap.String("orgID", filter.OrgID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newOrgUsageResponse(usage)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeGetOrgUsageRequest(ctx context.Context, r *http.Request) (*influxdb.OrgUsageFilter, error) {
+	req, err := decodeGetOrgRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &influxdb.OrgUsageFilter{
+		OrgID: req.OrgID,
+		Stop:  time.Now().UTC(),
+	}
+
+	qp := r.URL.Query()
+	if stop := qp.Get("stop"); stop != "" {
+		t, err := time.Parse(time.RFC3339, stop)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "stop must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		filter.Stop = t
+	}
+
+	filter.Start = filter.Stop.Add(-orgUsageDefaultRange)
+	if start := qp.Get("start"); start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "start must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		filter.Start = t
+	}
+
+	if err := filter.Valid(); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
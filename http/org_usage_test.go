@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func TestOrgHandler_handleGetOrgUsage(t *testing.T) {
+	day := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	os := mock.NewOrganizationService()
+	os.FindOrganizationByIDF = func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+		return &platform.Organization{ID: id, Name: "o"}, nil
+	}
+
+	var gotFilter *platform.OrgUsageFilter
+	us := mock.NewOrgUsageService()
+	us.FindOrgUsageFn = func(ctx context.Context, filter platform.OrgUsageFilter) (*platform.OrgUsageReport, error) {
+		gotFilter = &filter
+		return &platform.OrgUsageReport{
+			OrgID:             filter.OrgID,
+			Start:             filter.Start,
+			Stop:              filter.Stop,
+			WriteRequestCount: 2,
+			WriteRequestBytes: 30,
+			QueryRequestCount: 4,
+			QueryRequestBytes: 40,
+			TaskRunCount:      5,
+			StorageBytes:      150,
+		}, nil
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantStart  time.Time
+		wantBody   string
+	}{
+		{
+			name:       "time range",
+			query:      "?start=2019-07-01T00:00:00Z&stop=2019-07-03T00:00:00Z",
+			wantStatus: http.StatusOK,
+			wantStart:  day,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/orgs/0000000000000001/usage",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "orgID": "0000000000000001",
+  "start": "2019-07-01T00:00:00Z",
+  "stop": "2019-07-03T00:00:00Z",
+  "writeRequestCount": 2,
+  "writeRequestBytes": 30,
+  "queryRequestCount": 4,
+  "queryRequestBytes": 40,
+  "taskRunCount": 5,
+  "storageBytes": 150
+}`,
+		},
+		{
+			name:       "default start",
+			query:      "?stop=2019-07-31T00:00:00Z",
+			wantStatus: http.StatusOK,
+			wantStart:  day,
+		},
+		{
+			name:       "invalid stop",
+			query:      "?stop=now",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "start after stop",
+			query:      "?start=2019-07-03T00:00:00Z&stop=2019-07-01T00:00:00Z",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFilter = nil
+			h := NewOrgHandler(&OrgBackend{
+				HTTPErrorHandler:    ErrorHandler(0),
+				Logger:              zap.NewNop(),
+				OrganizationService: os,
+				OrgUsageService:     us,
+			})
+
+			r := httptest.NewRequest("GET", "http://any.url/api/v2/orgs/0000000000000001/usage"+tt.query, nil)
+			r = r.WithContext(context.WithValue(
+				pcontext.SetAuthorizer(r.Context(), &platform.Authorization{}),
+				httprouter.ParamsKey,
+				httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+			w := httptest.NewRecorder()
+			h.handleGetOrgUsage(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if gotFilter != nil {
+					t.Fatal("the usage should not be read for an invalid request")
+				}
+				return
+			}
+
+			if gotFilter.OrgID != 1 || !gotFilter.Start.Equal(tt.wantStart) {
+				t.Errorf("unexpected filter %+v", gotFilter)
+			}
+
+			if tt.wantBody != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+					t.Errorf("error unmarshaling json %v", err)
+				} else if !eq {
+					t.Errorf("***%s***", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestOrgHandler_handleGetOrgUsage_Unavailable(t *testing.T) {
+	h := NewOrgHandler(&OrgBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		OrganizationService: mock.NewOrganizationService(),
+	})
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/orgs/0000000000000001/usage", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if res := w.Result(); res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got status %d, want %d", res.StatusCode, http.StatusServiceUnavailable)
+	}
+}
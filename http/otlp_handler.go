@@ -0,0 +1,239 @@
+package http
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/otlp"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/writehook"
+)
+
+const (
+	otlpMetricsPath = "/api/v2/otlp/v1/metrics"
+
+	otlpProtoContentType = "application/x-protobuf"
+	otlpJSONContentType  = "application/json"
+
+	// otlpMaxRequestSize is the size of the largest uncompressed export request accepted.
+	otlpMaxRequestSize = 64 << 20
+)
+
+// handleOTLPMetrics receives the metrics of an OTLP/HTTP export request, encoded
+// as a protocol buffer or as JSON, and writes them to a bucket like a write.
+func (h *WriteHandler) handleOTLPMetrics(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "WriteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var orgID platform.ID
+	var requestBytes int
+	sw := newStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.EventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  requestBytes,
+			ResponseBytes: sw.responseBytes,
+			Status:        sw.code(),
+		})
+	}()
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (contentType != otlpProtoContentType && contentType != otlpJSONContentType) {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handleOTLPMetrics",
+			Msg:  fmt.Sprintf("content type must be %s or %s", otlpProtoContentType, otlpJSONContentType),
+		}, w)
+		return
+	}
+
+	in := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		in, err = gzip.NewReader(r.Body)
+		if err != nil {
+			h.HandleHTTPError(ctx, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/handleOTLPMetrics",
+				Msg:  errInvalidGzipHeader,
+				Err:  err,
+			}, w)
+			return
+		}
+		defer in.Close()
+	}
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	qp := r.URL.Query()
+	logger := h.Logger.With(zap.String("org", qp.Get("org")), zap.String("bucket", qp.Get("bucket")))
+
+	org, bucket, err := h.findBucket(ctx, qp.Get("org"), qp.Get("bucket"), logger)
+	if org != nil {
+		orgID = org.ID
+	}
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	measurements, err := authorizeWrite(a, org, bucket)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	schemas, err := h.measurementSchemas(ctx, bucket)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	hook, err := writehook.Chain(bucket.WriteHooks)
+	if err != nil {
+		logger.Error("Failed to find write hooks of bucket", zap.Error(err))
+		h.HandleHTTPError(ctx, &platform.Error{
+			Op:  "http/handleOTLPMetrics",
+			Err: err,
+		}, w)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(in, otlpMaxRequestSize+1))
+	requestBytes = len(body)
+	if err != nil {
+		h.handleReadError(ctx, w, err, logger)
+		return
+	}
+	if len(body) > otlpMaxRequestSize {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handleOTLPMetrics",
+			Msg:  fmt.Sprintf("request exceeds the maximum size of %d bytes", otlpMaxRequestSize),
+		}, w)
+		return
+	}
+
+	var metrics []otlp.Metric
+	if contentType == otlpProtoContentType {
+		metrics, err = otlp.DecodeProto(body)
+	} else {
+		metrics, err = otlp.DecodeJSON(body)
+	}
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handleOTLPMetrics",
+			Msg:  "unable to decode export request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	points, rejected := otlp.Points(metrics, time.Now())
+	if hook != nil && len(points) > 0 {
+		// The hooks process the data points of an export at once,
+		// so an error rejects all of them.
+		processed, err := hook.ProcessPoints(ctx, bucket, points)
+		if err != nil {
+			logger.Info("Write hooks rejected OTLP export", zap.Error(err))
+			rejected += len(points)
+			processed = nil
+		}
+		points = processed
+	}
+	if measurements != nil {
+		allowed := points[:0]
+		for _, p := range points {
+			if measurements[string(p.Name())] {
+				allowed = append(allowed, p)
+			} else {
+				rejected++
+			}
+		}
+		points = allowed
+	}
+	if schemas != nil {
+		valid := points[:0]
+		for _, p := range points {
+			if validatePointSchema(schemas, p.Name(), p) == nil {
+				valid = append(valid, p)
+			} else {
+				rejected++
+			}
+		}
+		points = valid
+	}
+
+	points, err = tsdb.ExplodePoints(org.ID, bucket.ID, points)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleOTLPMetrics",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if len(points) > 0 {
+		if h.BucketQuotaService != nil {
+			if wait, err := h.BucketQuotaService.ReserveWrite(ctx, bucket, seriesKeys(points), int64(len(body))); err != nil {
+				h.handleQuotaError(ctx, w, wait, err, logger)
+				return
+			}
+		}
+
+		if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+			logger.Error("Error writing points", zap.Error(err))
+			h.HandleHTTPError(ctx, &platform.Error{
+				Code: platform.EInternal,
+				Op:   "http/handleOTLPMetrics",
+				Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+				Err:  err,
+			}, w)
+			return
+		}
+	}
+
+	// Data points that cannot be converted, such as those of exponential
+	// histograms, and those of metrics the token may not write are reported as
+	// a partial success, which clients don't retry.
+	var msg string
+	if rejected > 0 {
+		logger.Info("Rejected data points of OTLP export", zap.Int("rejected", rejected), zap.Int("accepted", len(points)))
+		msg = fmt.Sprintf("%d data points were rejected, since they are exponential histograms, have no valid value, are not allowed by the permissions of the token or do not match the schema of the bucket", rejected)
+	}
+
+	var res []byte
+	if contentType == otlpProtoContentType {
+		res = otlp.EncodeProtoResponse(int64(rejected), msg)
+	} else if res, err = otlp.EncodeJSONResponse(int64(rejected), msg); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(res); err != nil {
+		logEncodingError(logger, r, err)
+	}
+}
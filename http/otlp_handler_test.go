@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestWriteHandler_handleOTLPMetrics(t *testing.T) {
+	const body = `{"resourceMetrics": [{
+	  "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "checkout"}}]},
+	  "scopeMetrics": [{"metrics": [
+	    {"name": "requests", "sum": {"isMonotonic": true, "dataPoints": [{"timeUnixNano": "1565000000000000000", "asInt": "12"}]}},
+	    {"name": "latency", "histogram": {"dataPoints": [{"timeUnixNano": "1565000000000000000", "count": "3", "sum": 0.9, "bucketCounts": ["1", "2"], "explicitBounds": [0.5]}]}},
+	    {"name": "sizes", "exponentialHistogram": {"dataPoints": [{"timeUnixNano": "1565000000000000000", "count": "3"}]}}
+	  ]}]
+	}]}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		encoding    string
+		body        string
+		permissions []platform.Permission
+		wantCode    int
+		wantBody    string
+		// wantPoints is the number of points written, which are exploded
+		// into a point per field before the points writer gets them.
+		wantPoints int
+	}{
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        body,
+			permissions: platform.OperPermissions(),
+			wantCode:    http.StatusOK,
+			wantBody:    `{"partialSuccess":{"rejectedDataPoints":"1","errorMessage":"1 data points were rejected, since they are exponential histograms, have no valid value, are not allowed by the permissions of the token or do not match the schema of the bucket"}}`,
+			wantPoints:  5, // the requests counter, and the count, sum and 2 buckets of the latency histogram
+		},
+		{
+			name:        "restricted measurements",
+			contentType: "application/json",
+			body:        body,
+			permissions: []platform.Permission{{
+				Action:       platform.WriteAction,
+				Resource:     platform.Resource{Type: platform.BucketsResourceType},
+				Measurements: []string{"requests"},
+			}},
+			wantCode:   http.StatusOK,
+			wantBody:   `{"partialSuccess":{"rejectedDataPoints":"2","errorMessage":"2 data points were rejected, since they are exponential histograms, have no valid value, are not allowed by the permissions of the token or do not match the schema of the bucket"}}`,
+			wantPoints: 1, // the requests counter
+		},
+		{
+			name:        "gzipped json",
+			contentType: "application/json; charset=utf-8",
+			encoding:    "gzip",
+			body:        `{"resourceMetrics": []}`,
+			permissions: platform.OperPermissions(),
+			wantCode:    http.StatusOK,
+			wantBody:    `{}`,
+		},
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+			body:        "",
+			permissions: platform.OperPermissions(),
+			wantCode:    http.StatusOK,
+			wantBody:    "",
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "text/plain",
+			body:        body,
+			permissions: platform.OperPermissions(),
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "invalid body",
+			contentType: "application/x-protobuf",
+			body:        "\x0a\x05",
+			permissions: platform.OperPermissions(),
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "insufficient permissions",
+			contentType: "application/json",
+			body:        body,
+			permissions: []platform.Permission{{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType}}},
+			wantCode:    http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := newTestWriteHandler(pw)
+
+			b := []byte(tt.body)
+			if tt.encoding == "gzip" {
+				var buf bytes.Buffer
+				gw := gzip.NewWriter(&buf)
+				if _, err := gw.Write(b); err != nil {
+					t.Fatal(err)
+				}
+				if err := gw.Close(); err != nil {
+					t.Fatal(err)
+				}
+				b = buf.Bytes()
+			}
+
+			r := httptest.NewRequest("POST", "/api/v2/otlp/v1/metrics?org=0000000000000001&bucket=0000000000000002", bytes.NewReader(b))
+			r.Header.Set("Content-Type", tt.contentType)
+			if tt.encoding != "" {
+				r.Header.Set("Content-Encoding", tt.encoding)
+			}
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: tt.permissions}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+				t.Errorf("got body %q, want %q", got, tt.wantBody)
+			}
+			if len(pw.Points) != tt.wantPoints {
+				t.Fatalf("got %d points, want %d", len(pw.Points), tt.wantPoints)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	platform "github.com/influxdata/influxdb"
 )
@@ -21,6 +23,13 @@ func decodeFindOptions(ctx context.Context, r *http.Request) (*platform.FindOpti
 			return nil, err
 		}
 
+		if o < 0 {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "offset must not be negative",
+			}
+		}
+
 		opts.Offset = o
 	}
 
@@ -55,9 +64,22 @@ func decodeFindOptions(ctx context.Context, r *http.Request) (*platform.FindOpti
 		opts.Descending = desc
 	}
 
+	opts.Fields = decodeFields(qp)
+
 	return opts, nil
 }
 
+// decodeFields returns the comma separated fields of the fields query param.
+func decodeFields(qp url.Values) []string {
+	var fields []string
+	for _, f := range strings.Split(qp.Get("fields"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
 // newPagingLinks returns a PagingLinks.
 // num is the number of returned results.
 func newPagingLinks(basePath string, opts platform.FindOptions, f platform.PagingFilter, num int) *platform.PagingLinks {
@@ -111,3 +133,88 @@ func newPagingLinks(basePath string, opts platform.FindOptions, f platform.Pagin
 
 	return links
 }
+
+// setLinkHeader sets the Link header of the response to the paging links.
+func setLinkHeader(w http.ResponseWriter, links *platform.PagingLinks) {
+	var ls []string
+	for _, l := range []struct{ rel, url string }{
+		{rel: "prev", url: links.Prev},
+		{rel: "self", url: links.Self},
+		{rel: "next", url: links.Next},
+	} {
+		if l.url != "" {
+			ls = append(ls, fmt.Sprintf("<%s>; rel=%q", l.url, l.rel))
+		}
+	}
+	if len(ls) > 0 {
+		w.Header().Set("Link", strings.Join(ls, ", "))
+	}
+}
+
+// encodeListResponse encodes a response that lists results under key, with
+// the paging links as Link header. If there are fields, the listed results
+// only have those fields, and their id and links.
+func encodeListResponse(ctx context.Context, w http.ResponseWriter, fields []string, links *platform.PagingLinks, key string, res interface{}) error {
+	setLinkHeader(w, links)
+	if len(fields) == 0 {
+		return encodeResponse(ctx, w, http.StatusOK, res)
+	}
+
+	sparse, err := selectFields(res, key, fields)
+	if err != nil {
+		return err
+	}
+	return encodeResponse(ctx, w, http.StatusOK, sparse)
+}
+
+// selectFields returns the response with only the fields of the results listed under key.
+func selectFields(res interface{}, key string, fields []string) (map[string]interface{}, error) {
+	octets, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(octets, &m); err != nil {
+		return nil, err
+	}
+
+	var results []map[string]json.RawMessage
+	if err := json.Unmarshal(m[key], &results); err != nil {
+		return nil, err
+	}
+
+	keep := map[string]bool{"id": true, "links": true}
+	for _, f := range fields {
+		keep[f] = true
+	}
+
+	sparse := make([]map[string]json.RawMessage, 0, len(results))
+	for _, r := range results {
+		s := map[string]json.RawMessage{}
+		for k, v := range r {
+			if keep[k] {
+				s[k] = v
+			}
+		}
+		sparse = append(sparse, s)
+	}
+
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	out[key] = sparse
+	return out, nil
+}
+
+// addPagingLinks adds the previous and next paging links to the links of a
+// response whose self link is its path.
+func addPagingLinks(links map[string]string, pl *platform.PagingLinks) {
+	if pl.Prev != "" {
+		links["prev"] = pl.Prev
+	}
+	if pl.Next != "" {
+		links["next"] = pl.Next
+	}
+}
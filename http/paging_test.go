@@ -2,7 +2,9 @@ package http
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
@@ -41,6 +43,20 @@ func TestPaging_decodeFindOptions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "decode FindOptions with fields",
+			args: args{
+				map[string]string{
+					"fields": "name, orgID,,",
+				},
+			},
+			wants: wants{
+				opts: platform.FindOptions{
+					Limit:  platform.DefaultPageSize,
+					Fields: []string{"name", "orgID"},
+				},
+			},
+		},
 		{
 			name: "decode FindOptions with default values",
 			args: args{
@@ -85,10 +101,20 @@ func TestPaging_decodeFindOptions(t *testing.T) {
 			if opts.Descending != tt.wants.opts.Descending {
 				t.Errorf("%q. decodeFindOptions() = %v, want %v", tt.name, opts.Descending, tt.wants.opts.Descending)
 			}
+			if !reflect.DeepEqual(opts.Fields, tt.wants.opts.Fields) {
+				t.Errorf("%q. decodeFindOptions() = %v, want %v", tt.name, opts.Fields, tt.wants.opts.Fields)
+			}
 		})
 	}
 }
 
+func TestPaging_decodeFindOptionsNegativeOffset(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://any.url?offset=-1", nil)
+	if _, err := decodeFindOptions(context.Background(), r); platform.ErrorCode(err) != platform.EInvalid {
+		t.Errorf("expected a negative offset to be invalid, got %v", err)
+	}
+}
+
 func TestPaging_newPagingLinks(t *testing.T) {
 	type args struct {
 		basePath string
@@ -194,3 +220,42 @@ func TestPaging_newPagingLinks(t *testing.T) {
 		})
 	}
 }
+
+func TestPaging_encodeListResponse(t *testing.T) {
+	links := &platform.PagingLinks{
+		Self: "/api/v2/users?limit=1&offset=0",
+		Next: "/api/v2/users?limit=1&offset=1",
+	}
+	res := newUsersResponse([]*platform.User{{ID: 1, Name: "ada", OAuthID: "ada@example.com"}})
+
+	w := httptest.NewRecorder()
+	if err := encodeListResponse(context.Background(), w, []string{"name"}, links, "users", res); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := w.Header().Get("Link"), `</api/v2/users?limit=1&offset=0>; rel="self", </api/v2/users?limit=1&offset=1>; rel="next"`; got != want {
+		t.Errorf("got Link header %q, want %q", got, want)
+	}
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/users"
+  },
+  "users": [
+    {
+      "links": {
+        "self": "/api/v2/users/0000000000000001",
+        "logs": "/api/v2/users/0000000000000001/logs"
+      },
+      "id": "0000000000000001",
+      "name": "ada"
+    }
+  ]
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	usersPasswordResetPath = "/api/v2/users/:id/password/reset"
+
+	passwordResetsTokenPath    = "/api/v2/password-resets/:token"
+	passwordResetsTokenPattern = "/api/v2/password-resets/%s"
+)
+
+type passwordResetResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.PasswordReset
+	// ResetURL is the absolute URL of the password reset, to be sent to the user.
+	ResetURL string `json:"resetURL"`
+}
+
+func newPasswordResetResponse(r *http.Request, pr *influxdb.PasswordReset) *passwordResetResponse {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return &passwordResetResponse{
+		Links: map[string]string{
+			"user":  fmt.Sprintf("/api/v2/users/%s", pr.UserID),
+			"reset": fmt.Sprintf(passwordResetsTokenPattern, pr.Token),
+		},
+		PasswordReset: *pr,
+		ResetURL:      fmt.Sprintf("%s://%s"+passwordResetsTokenPattern, scheme, r.Host, pr.Token),
+	}
+}
+
+// handlePostUserPasswordReset is the HTTP handler for the POST /api/v2/users/:id/password/reset route.
+// The token of the password reset is returned, for the caller to deliver to the user.
+func (h *UserHandler) handlePostUserPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("password reset create request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	pr, err := h.PasswordResetService.CreatePasswordReset(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("password reset created", zap.String("userID", id.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newPasswordResetResponse(r, pr)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// PasswordResetBackend is all services and associated parameters required to construct
+// the PasswordResetHandler.
+type PasswordResetBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	PasswordResetService influxdb.PasswordResetService
+}
+
+// NewPasswordResetBackend returns a new instance of PasswordResetBackend.
+func NewPasswordResetBackend(b *APIBackend) *PasswordResetBackend {
+	return &PasswordResetBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "password_reset")),
+
+		PasswordResetService: b.PasswordResetService,
+	}
+}
+
+// PasswordResetHandler uses password resets to set the password of their users.
+// The token in the path is the credential of those requests, so they are not
+// authenticated.
+type PasswordResetHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	PasswordResetService influxdb.PasswordResetService
+}
+
+// NewPasswordResetHandler returns a new instance of PasswordResetHandler.
+func NewPasswordResetHandler(b *PasswordResetBackend) *PasswordResetHandler {
+	h := &PasswordResetHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		PasswordResetService: b.PasswordResetService,
+	}
+
+	h.HandlerFunc("POST", passwordResetsTokenPath, h.handlePostPasswordReset)
+	return h
+}
+
+// handlePostPasswordReset is the HTTP handler for the POST /api/v2/password-resets/:token route.
+func (h *PasswordResetHandler) handlePostPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	password, err := decodePostPasswordResetRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	token := httprouter.ParamsFromContext(ctx).ByName("token")
+	if err := h.PasswordResetService.ResetPassword(ctx, token, password); err != nil {
+		if err := encodePasswordError(ctx, h, w, err); err != nil {
+			logEncodingError(h.Logger, r, err)
+		}
+		return
+	}
+	h.Logger.Debug("password reset")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodePostPasswordResetRequest(ctx context.Context, r *http.Request) (string, error) {
+	body := &passwordResetRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode password reset",
+			Err:  err,
+		}
+	}
+	if body.Password == "" {
+		return "", &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "a password is required",
+		}
+	}
+	return body.Password, nil
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestUserHandler_handlePostUserPasswordReset(t *testing.T) {
+	createdAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	prs := mock.NewPasswordResetService()
+	prs.CreatePasswordResetFn = func(ctx context.Context, userID platform.ID) (*platform.PasswordReset, error) {
+		return &platform.PasswordReset{
+			UserID:    userID,
+			Token:     "secret",
+			ExpiresAt: createdAt.Add(platform.DefaultPasswordResetTTL),
+			CreatedAt: createdAt,
+		}, nil
+	}
+
+	h := NewUserHandler(&UserBackend{
+		HTTPErrorHandler:     ErrorHandler(0),
+		Logger:               zap.NewNop(),
+		PasswordResetService: prs,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://influx.example.com/api/v2/users/0000000000000001/password/reset", nil))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "user": "/api/v2/users/0000000000000001",
+    "reset": "/api/v2/password-resets/secret"
+  },
+  "userID": "0000000000000001",
+  "token": "secret",
+  "expiresAt": "2019-07-01T13:00:00Z",
+  "createdAt": "2019-07-01T12:00:00Z",
+  "resetURL": "http://influx.example.com/api/v2/password-resets/secret"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+}
+
+func TestPasswordResetHandler_handlePostPasswordReset(t *testing.T) {
+	var password string
+	prs := mock.NewPasswordResetService()
+	prs.ResetPasswordFn = func(ctx context.Context, token string, pw string) error {
+		if token != "secret" {
+			return &platform.Error{Code: platform.ENotFound, Msg: platform.ErrPasswordResetNotFound}
+		}
+		if err := platform.DefaultPasswordPolicy.Check(pw); err != nil {
+			return err
+		}
+		password = pw
+		return nil
+	}
+
+	h := NewPasswordResetHandler(&PasswordResetBackend{
+		HTTPErrorHandler:     ErrorHandler(0),
+		Logger:               zap.NewNop(),
+		PasswordResetService: prs,
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/password-resets/secret", bytes.NewBufferString(`{"password": "remembered1"}`)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if password != "remembered1" {
+		t.Errorf("expected the password to be reset, got %q", password)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/password-resets/secret", bytes.NewBufferString(`{"password": "short"}`)))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "code": "invalid",
+  "message": "passwords must be at least 8 characters long",
+  "violations": [
+    {"rule": "min_length", "message": "passwords must be at least 8 characters long"}
+  ]
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/password-resets/other", bytes.NewBufferString(`{"password": "remembered1"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
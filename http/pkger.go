@@ -0,0 +1,411 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	"github.com/ghodss/yaml"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkger"
+)
+
+// PkgerBackend is all services and associated parameters
+// required to construct the PkgerHandler.
+type PkgerBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	PkgerService        pkger.SVC
+	StackService        influxdb.StackService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewPkgerBackend returns a new instance of PkgerBackend.
+func NewPkgerBackend(b *APIBackend) *PkgerBackend {
+	return &PkgerBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "pkger")),
+
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// PkgerHandler represents an HTTP API handler for packages.
+type PkgerHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	PkgerService        pkger.SVC
+	StackService        influxdb.StackService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	packagesApplyPath = "/api/v2/packages/apply"
+	stacksPath        = "/api/v2/stacks"
+	stacksIDPath      = "/api/v2/stacks/:id"
+	stacksIDDriftPath = "/api/v2/stacks/:id/drift"
+)
+
+// NewPkgerHandler returns a new instance of PkgerHandler.
+func NewPkgerHandler(b *PkgerBackend) *PkgerHandler {
+	h := &PkgerHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		PkgerService:        b.PkgerService,
+		StackService:        b.StackService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", packagesApplyPath, h.handlePostPackagesApply)
+	h.HandlerFunc("POST", stacksPath, h.handlePostStack)
+	h.HandlerFunc("GET", stacksPath, h.handleGetStacks)
+	h.HandlerFunc("GET", stacksIDPath, h.handleGetStack)
+	h.HandlerFunc("GET", stacksIDDriftPath, h.handleGetStackDrift)
+	h.HandlerFunc("DELETE", stacksIDPath, h.handleDeleteStack)
+
+	return h
+}
+
+type postPackagesApplyRequest struct {
+	OrgID  influxdb.ID `json:"orgID,omitempty"`
+	Org    string      `json:"org,omitempty"`
+	DryRun bool        `json:"dryRun"`
+	// StackID is the stack the package is applied with, if any.
+	StackID influxdb.ID `json:"stackID,omitempty"`
+	// Package is decoded by the pkger package, so that it is validated there.
+	Package json.RawMessage `json:"package"`
+
+	pkg *pkger.Pkg
+}
+
+type packagesApplyResponse struct {
+	DryRun  bool           `json:"dryRun"`
+	Changes []pkger.Change `json:"changes"`
+}
+
+// handlePostPackagesApply is the HTTP handler for the POST /api/v2/packages/apply route.
+// It applies a package to an organization, or only returns the changes applying
+// it would make when dryRun is set.
+func (h *PkgerHandler) handlePostPackagesApply(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostPackagesApplyRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	orgID, err := h.packageOrganizationID(ctx, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	opts := []pkger.ApplyOptFn{}
+	if req.StackID.Valid() {
+		opts = append(opts, pkger.ApplyWithStackID(req.StackID))
+	}
+
+	applyFn, status := h.PkgerService.Apply, http.StatusCreated
+	if req.DryRun {
+		applyFn, status = h.PkgerService.DryRun, http.StatusOK
+	}
+	changes, err := applyFn(ctx, orgID, req.pkg, opts...)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("package applied", zap.String("pkgName", req.pkg.Metadata.Name), zap.Bool("dryRun", req.DryRun), zap.Int("changes", len(changes)))
+
+	if err := encodeResponse(ctx, w, status, &packagesApplyResponse{
+		DryRun:  req.DryRun,
+		Changes: changes,
+	}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// decodePostPackagesApplyRequest decodes a JSON request, or a YAML one if its
+// content type is application/x-yaml.
+func decodePostPackagesApplyRequest(r *http.Request) (*postPackagesApplyRequest, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to read request",
+			Err:  err,
+		}
+	}
+
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "application/x-yaml" {
+		if body, err = yaml.YAMLToJSON(body); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "request is not valid YAML",
+				Err:  err,
+			}
+		}
+	}
+
+	req := &postPackagesApplyRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}
+	}
+	if !req.OrgID.Valid() && req.Org == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "orgID or org is required",
+		}
+	}
+	if len(req.Package) == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "package is required",
+		}
+	}
+
+	if req.pkg, err = pkger.Parse(pkger.EncodingJSON, bytes.NewReader(req.Package)); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (h *PkgerHandler) packageOrganizationID(ctx context.Context, req *postPackagesApplyRequest) (influxdb.ID, error) {
+	if req.OrgID.Valid() {
+		o, err := h.OrganizationService.FindOrganizationByID(ctx, req.OrgID)
+		if err != nil {
+			return 0, err
+		}
+		return o.ID, nil
+	}
+	o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &req.Org})
+	if err != nil {
+		return 0, err
+	}
+	return o.ID, nil
+}
+
+type stackResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Stack
+}
+
+func newStackResponse(st *influxdb.Stack) *stackResponse {
+	return &stackResponse{
+		Links: map[string]string{
+			"self":  fmt.Sprintf("/api/v2/stacks/%s", st.ID),
+			"drift": fmt.Sprintf("/api/v2/stacks/%s/drift", st.ID),
+			"org":   fmt.Sprintf("/api/v2/orgs/%s", st.OrgID),
+		},
+		Stack: *st,
+	}
+}
+
+type stacksResponse struct {
+	Links  map[string]string `json:"links"`
+	Stacks []*stackResponse  `json:"stacks"`
+}
+
+func newStacksResponse(ss []*influxdb.Stack) *stacksResponse {
+	res := &stacksResponse{
+		Links: map[string]string{
+			"self": stacksPath,
+		},
+		Stacks: make([]*stackResponse, 0, len(ss)),
+	}
+	for _, st := range ss {
+		res.Stacks = append(res.Stacks, newStackResponse(st))
+	}
+	return res
+}
+
+type postStackRequest struct {
+	OrgID       influxdb.ID `json:"orgID"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+}
+
+// handlePostStack is the HTTP handler for the POST /api/v2/stacks route.
+// Stacks are created without resources; applying packages with them adds some.
+func (h *PkgerHandler) handlePostStack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("stack create request", zap.String("r", fmt.Sprint(r)))
+
+	var req postStackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	st := &influxdb.Stack{
+		OrgID:       req.OrgID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := h.StackService.CreateStack(ctx, st); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("stack created", zap.String("stackID", st.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newStackResponse(st)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetStacks is the HTTP handler for the GET /api/v2/stacks route.
+func (h *PkgerHandler) handleGetStacks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("stacks retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	filter, opts, err := h.decodeGetStacksRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ss, _, err := h.StackService.FindStacks(ctx, filter, opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("stacks retrieved", zap.Int("stacks", len(ss)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newStacksResponse(ss)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *PkgerHandler) decodeGetStacksRequest(ctx context.Context, r *http.Request) (influxdb.StackFilter, influxdb.FindOptions, error) {
+	qp := r.URL.Query()
+	var filter influxdb.StackFilter
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return filter, influxdb.FindOptions{}, err
+	}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	return filter, *opts, nil
+}
+
+// handleGetStack is the HTTP handler for the GET /api/v2/stacks/:id route.
+func (h *PkgerHandler) handleGetStack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("stack retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	st, err := h.StackService.FindStackByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("stack retrieved", zap.String("stackID", st.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newStackResponse(st)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type stackDriftResponse struct {
+	Links     map[string]string `json:"links"`
+	Resources []pkger.Drift     `json:"resources"`
+}
+
+// handleGetStackDrift is the HTTP handler for the GET /api/v2/stacks/:id/drift route.
+// It compares the resources owned by the stack with the package last applied with it.
+func (h *PkgerHandler) handleGetStackDrift(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("stack drift request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	drifts, err := h.PkgerService.Drift(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, &stackDriftResponse{
+		Links: map[string]string{
+			"self":  fmt.Sprintf("/api/v2/stacks/%s/drift", id),
+			"stack": fmt.Sprintf("/api/v2/stacks/%s", id),
+		},
+		Resources: drifts,
+	}); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteStack is the HTTP handler for the DELETE /api/v2/stacks/:id route.
+// It uninstalls the stack: the resources it owns are deleted along with it.
+func (h *PkgerHandler) handleDeleteStack(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("stack delete request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	changes, err := h.PkgerService.Uninstall(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("stack uninstalled", zap.String("stackID", id.String()), zap.Int("changes", len(changes)))
+
+	w.WriteHeader(http.StatusNoContent)
+}
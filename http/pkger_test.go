@@ -0,0 +1,315 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/pkger"
+	"go.uber.org/zap"
+)
+
+type fakePkgerService struct {
+	dryRun  bool
+	orgID   platform.ID
+	stackID platform.ID
+	pkg     *pkger.Pkg
+}
+
+func (s *fakePkgerService) changes(orgID platform.ID, pkg *pkger.Pkg, opts []pkger.ApplyOptFn) []pkger.Change {
+	var opt pkger.ApplyOpt
+	for _, o := range opts {
+		o(&opt)
+	}
+	s.orgID, s.stackID, s.pkg = orgID, opt.StackID, pkg
+	changes := []pkger.Change{}
+	for _, r := range pkg.Spec.Resources {
+		c := pkger.Change{Kind: r.Kind, Name: r.Name, Action: pkger.ActionCreate}
+		if !s.dryRun {
+			c.ID = 3
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func (s *fakePkgerService) DryRun(ctx context.Context, orgID platform.ID, pkg *pkger.Pkg, opts ...pkger.ApplyOptFn) ([]pkger.Change, error) {
+	s.dryRun = true
+	return s.changes(orgID, pkg, opts), nil
+}
+
+func (s *fakePkgerService) Apply(ctx context.Context, orgID platform.ID, pkg *pkger.Pkg, opts ...pkger.ApplyOptFn) ([]pkger.Change, error) {
+	return s.changes(orgID, pkg, opts), nil
+}
+
+func (s *fakePkgerService) Drift(ctx context.Context, stackID platform.ID) ([]pkger.Drift, error) {
+	s.stackID = stackID
+	return []pkger.Drift{
+		{Kind: pkger.KindBucket, Name: "b1", ID: 3, Status: pkger.DriftModified, Fields: []string{"retentionPeriod"}},
+		{Kind: pkger.KindLabel, Name: "l1", ID: 4, Status: pkger.DriftMissing},
+	}, nil
+}
+
+func (s *fakePkgerService) Uninstall(ctx context.Context, stackID platform.ID) ([]pkger.Change, error) {
+	s.stackID = stackID
+	return []pkger.Change{{Kind: pkger.KindBucket, Name: "b1", Action: pkger.ActionDelete, ID: 3}}, nil
+}
+
+func newTestPkgerHandler(ps pkger.SVC) *PkgerHandler {
+	orgSvc := mock.NewOrganizationService()
+	orgSvc.FindOrganizationByIDF = func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+		return &platform.Organization{ID: id, Name: "org"}, nil
+	}
+	orgSvc.FindOrganizationF = func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+		if filter.Name == nil || *filter.Name != "org" {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "organization not found"}
+		}
+		return &platform.Organization{ID: 1, Name: "org"}, nil
+	}
+	return NewPkgerHandler(&PkgerBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		PkgerService:        ps,
+		OrganizationService: orgSvc,
+	})
+}
+
+func TestPkgerHandler_handlePostPackagesApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		want        string
+	}{
+		{
+			name: "apply a JSON package",
+			body: `
+{
+  "orgID": "0000000000000001",
+  "stackID": "0000000000000002",
+  "package": {
+    "apiVersion": "0.1.0",
+    "kind": "Package",
+    "meta": {"pkgName": "buckets"},
+    "spec": {"resources": [{"kind": "Bucket", "name": "b1", "retentionPeriod": "24h"}]}
+  }
+}`,
+			status: http.StatusCreated,
+			want: `
+{
+  "dryRun": false,
+  "changes": [{"kind": "Bucket", "name": "b1", "action": "create", "id": "0000000000000003"}]
+}`,
+		},
+		{
+			name:        "dry run a YAML package",
+			contentType: "application/x-yaml",
+			body: `
+org: org
+dryRun: true
+package:
+  apiVersion: 0.1.0
+  kind: Package
+  meta:
+    pkgName: labels
+  spec:
+    resources:
+      - kind: Label
+        name: l1
+        color: "#FFFFFF"
+`,
+			status: http.StatusOK,
+			want: `
+{
+  "dryRun": true,
+  "changes": [{"kind": "Label", "name": "l1", "action": "create"}]
+}`,
+		},
+		{
+			name:   "invalid package",
+			body:   `{"orgID": "0000000000000001", "package": {"apiVersion": "0.1.0", "kind": "Package", "meta": {"pkgName": "p"}, "spec": {"resources": [{"kind": "Widget", "name": "w"}]}}}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "missing organization",
+			body:   `{"package": {"apiVersion": "0.1.0", "kind": "Package", "meta": {"pkgName": "p"}, "spec": {"resources": []}}}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown organization",
+			body:   `{"org": "other", "package": {"apiVersion": "0.1.0", "kind": "Package", "meta": {"pkgName": "p"}, "spec": {"resources": []}}}`,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := &fakePkgerService{}
+			h := newTestPkgerHandler(ps)
+
+			r := httptest.NewRequest("POST", "http://any.url/api/v2/packages/apply", bytes.NewBufferString(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			body, _ := ioutil.ReadAll(w.Result().Body)
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.status, body)
+			}
+			if tt.want == "" {
+				return
+			}
+			if ps.orgID != 1 {
+				t.Errorf("expected the package to be applied to org 1, got %s", ps.orgID)
+			}
+			if !ps.dryRun && ps.stackID != 2 {
+				t.Errorf("expected the package to be applied with stack 2, got %s", ps.stackID)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected response -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestPkgerHandler_stacks(t *testing.T) {
+	stack := &platform.Stack{
+		ID:        2,
+		OrgID:     1,
+		Name:      "system",
+		Resources: []platform.StackResource{{Kind: "Bucket", Name: "b1", ID: 3}},
+	}
+	ss := mock.NewStackService()
+	ss.FindStackByIDFn = func(ctx context.Context, id platform.ID) (*platform.Stack, error) {
+		if id != stack.ID {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrStackNotFound}
+		}
+		return stack, nil
+	}
+	ss.FindStacksFn = func(ctx context.Context, filter platform.StackFilter, opt ...platform.FindOptions) ([]*platform.Stack, int, error) {
+		if filter.OrgID == nil || *filter.OrgID != stack.OrgID {
+			return []*platform.Stack{}, 0, nil
+		}
+		return []*platform.Stack{stack}, 1, nil
+	}
+	ss.CreateStackFn = func(ctx context.Context, st *platform.Stack) error {
+		st.ID = 5
+		st.Resources = []platform.StackResource{}
+		return nil
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name:   "create a stack",
+			method: "POST",
+			path:   "/api/v2/stacks",
+			body:   `{"orgID": "0000000000000001", "name": "monitoring"}`,
+			status: http.StatusCreated,
+			want: `
+{
+  "links": {
+    "self": "/api/v2/stacks/0000000000000005",
+    "drift": "/api/v2/stacks/0000000000000005/drift",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "id": "0000000000000005",
+  "orgID": "0000000000000001",
+  "name": "monitoring",
+  "resources": [],
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`,
+		},
+		{
+			name:   "list the stacks of an organization by name",
+			method: "GET",
+			path:   "/api/v2/stacks?org=org",
+			status: http.StatusOK,
+			want: `
+{
+  "links": {"self": "/api/v2/stacks"},
+  "stacks": [
+    {
+      "links": {
+        "self": "/api/v2/stacks/0000000000000002",
+        "drift": "/api/v2/stacks/0000000000000002/drift",
+        "org": "/api/v2/orgs/0000000000000001"
+      },
+      "id": "0000000000000002",
+      "orgID": "0000000000000001",
+      "name": "system",
+      "resources": [{"kind": "Bucket", "name": "b1", "id": "0000000000000003"}],
+      "createdAt": "0001-01-01T00:00:00Z",
+      "updatedAt": "0001-01-01T00:00:00Z"
+    }
+  ]
+}`,
+		},
+		{
+			name:   "get an unknown stack",
+			method: "GET",
+			path:   "/api/v2/stacks/0000000000000009",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "get the drift of a stack",
+			method: "GET",
+			path:   "/api/v2/stacks/0000000000000002/drift",
+			status: http.StatusOK,
+			want: `
+{
+  "links": {
+    "self": "/api/v2/stacks/0000000000000002/drift",
+    "stack": "/api/v2/stacks/0000000000000002"
+  },
+  "resources": [
+    {"kind": "Bucket", "name": "b1", "id": "0000000000000003", "status": "modified", "fields": ["retentionPeriod"]},
+    {"kind": "Label", "name": "l1", "id": "0000000000000004", "status": "missing"}
+  ]
+}`,
+		},
+		{
+			name:   "uninstall a stack",
+			method: "DELETE",
+			path:   "/api/v2/stacks/0000000000000002",
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestPkgerHandler(&fakePkgerService{})
+			h.StackService = ss
+
+			r := httptest.NewRequest(tt.method, "http://any.url"+tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			body, _ := ioutil.ReadAll(w.Result().Body)
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.status, body)
+			}
+			if tt.want == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.want); err != nil || !eq {
+				t.Errorf("unexpected response -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
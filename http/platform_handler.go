@@ -29,6 +29,7 @@ func NewPlatformHandler(b *APIBackend) *PlatformHandler {
 	h.AuthorizationService = b.AuthorizationService
 	h.SessionService = b.SessionService
 	h.SessionRenewDisabled = b.SessionRenewDisabled
+	h.ClientCertAuthenticator = b.ClientCertAuthenticator
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
@@ -36,6 +37,16 @@ func NewPlatformHandler(b *APIBackend) *PlatformHandler {
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/swagger.json")
+	// Users change their password with their old password, so that users whose
+	// password has expired, who can not sign in, can change it.
+	h.RegisterNoAuthRoute("PUT", usersPasswordPath)
+	h.RegisterNoAuthRoute("GET", invitesTokenPath)
+	h.RegisterNoAuthRoute("POST", invitesAcceptPath)
+	h.RegisterNoAuthRoute("GET", sharesTokenPath)
+	h.RegisterNoAuthRoute("GET", sharesTokenCellResultsPath)
+	h.RegisterNoAuthRoute("POST", passwordResetsTokenPath)
+	h.RegisterNoAuthRoute("GET", compatPingPath)
+	h.RegisterNoAuthRoute("HEAD", compatPingPath)
 
 	assetHandler := NewAssetHandler()
 	assetHandler.Path = b.AssetsPath
@@ -59,6 +70,12 @@ func (h *PlatformHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isCompatPath(r.URL.Path) {
+		setCompatToken(r)
+		h.APIHandler.ServeHTTP(w, r)
+		return
+	}
+
 	// Serve the chronograf assets for any basepath that does not start with addressable parts
 	// of the platform API.
 	if !strings.HasPrefix(r.URL.Path, "/v1") &&
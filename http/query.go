@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
@@ -22,6 +23,8 @@ import (
 	"github.com/influxdata/flux/repl"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/arrow"
+	"github.com/influxdata/influxdb/query/ndjson"
 	"github.com/influxdata/influxql"
 )
 
@@ -33,6 +36,17 @@ type QueryRequest struct {
 	Query   string       `json:"query"`
 	Type    string       `json:"type"`
 	Dialect QueryDialect `json:"dialect"`
+	// Priority is the priority of the query, interactive by default.
+	Priority query.Priority `json:"priority,omitempty"`
+	// Calendar declares the calendar of query.Calendar for the query, resolved
+	// in Timezone, or else in the timezone of the organization.
+	Calendar bool   `json:"calendar,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	// Params are declared as the params option of the query, see query.ParamsStatement.
+	Params map[string]interface{} `json:"params,omitempty"`
+	// Profile, when set, returns the execution profile of the query as an extra
+	// result or in the trailers of the response.
+	Profile query.ProfileMode `json:"profile,omitempty"`
 
 	Org *influxdb.Organization `json:"-"`
 }
@@ -110,12 +124,48 @@ func (r QueryRequest) Validate() error {
 		return fmt.Errorf(`unknown dialect date time format: %s`, r.Dialect.DateTimeFormat)
 	}
 
+	if !r.Priority.Valid() {
+		return fmt.Errorf(`unknown query priority: %s`, r.Priority)
+	}
+
+	if !r.Profile.Valid() {
+		return fmt.Errorf(`unknown query profile: %s`, r.Profile)
+	}
+
+	if r.Calendar && r.Query == "" && r.AST == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "calendar cannot be declared for a spec",
+		}
+	}
+
+	if r.Timezone != "" {
+		if _, err := influxdb.LoadTimezone(r.Timezone); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Params) > 0 {
+		if r.Query == "" && r.AST == nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "params cannot be declared for a spec",
+			}
+		}
+		if _, err := query.ParamsStatement(r.Params); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // QueryAnalysis is a structured response of errors.
 type QueryAnalysis struct {
 	Errors []queryParseError `json:"errors"`
+
+	// Warnings are likely mistakes of a Flux query that parses.
+	Warnings []queryWarning `json:"warnings"`
 }
 
 type queryParseError struct {
@@ -139,11 +189,12 @@ func (r QueryRequest) Analyze() (*QueryAnalysis, error) {
 }
 
 func (r QueryRequest) analyzeFluxQuery() (*QueryAnalysis, error) {
-	a := &QueryAnalysis{}
+	a := &QueryAnalysis{Warnings: []queryWarning{}}
 	pkg := parser.ParseSource(r.Query)
 	errCount := ast.Check(pkg)
 	if errCount == 0 {
 		a.Errors = []queryParseError{}
+		a.Warnings = lintFluxQuery(pkg)
 		return a, nil
 	}
 	a.Errors = make([]queryParseError, 0, errCount)
@@ -161,7 +212,7 @@ func (r QueryRequest) analyzeFluxQuery() (*QueryAnalysis, error) {
 }
 
 func (r QueryRequest) analyzeInfluxQLQuery() (*QueryAnalysis, error) {
-	a := &QueryAnalysis{}
+	a := &QueryAnalysis{Warnings: []queryWarning{}}
 	_, err := influxql.ParseQuery(r.Query)
 	if err == nil {
 		a.Errors = []queryParseError{}
@@ -224,21 +275,43 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 	if err := r.Validate(); err != nil {
 		return nil, err
 	}
+
+	var (
+		n      time.Time
+		extern = r.Extern
+	)
+	if r.Query != "" || r.AST != nil {
+		n = now()
+		if r.Calendar {
+			c, err := r.calendar(n)
+			if err != nil {
+				return nil, err
+			}
+			extern = query.WithCalendar(extern, c)
+		}
+		if len(r.Params) > 0 {
+			var err error
+			if extern, err = query.WithParams(extern, r.Params); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// Query is preferred over AST
 	var compiler flux.Compiler
 	if r.Query != "" {
 		compiler = lang.FluxCompiler{
-			Now:    now(),
-			Extern: r.Extern,
+			Now:    n,
+			Extern: extern,
 			Query:  r.Query,
 		}
 	} else if r.AST != nil {
 		c := lang.ASTCompiler{
 			AST: r.AST,
-			Now: now(),
+			Now: n,
 		}
-		if r.Extern != nil {
-			c.PrependFile(r.Extern)
+		if extern != nil {
+			c.PrependFile(extern)
 		}
 		compiler = c
 	} else if r.Spec != nil {
@@ -260,7 +333,9 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 		Request: query.Request{
 			OrganizationID: r.Org.ID,
 			Compiler:       compiler,
+			Priority:       r.Priority,
 		},
+		Profile: r.Profile,
 		Dialect: &csv.Dialect{
 			ResultEncoderConfig: csv.ResultEncoderConfig{
 				NoHeader:    noHeader,
@@ -271,6 +346,19 @@ func (r QueryRequest) proxyRequest(now func() time.Time) (*query.ProxyRequest, e
 	}, nil
 }
 
+// calendar returns the calendar of the timezone of the request at now.
+func (r QueryRequest) calendar(now time.Time) (query.Calendar, error) {
+	tz := r.Timezone
+	if tz == "" && r.Org != nil {
+		tz = r.Org.Timezone
+	}
+	loc, err := influxdb.LoadTimezone(tz)
+	if err != nil {
+		return query.Calendar{}, err
+	}
+	return query.NewCalendar(now, loc), nil
+}
+
 // QueryRequestFromProxyRequest converts a query.ProxyRequest into a QueryRequest.
 // The ProxyRequest must contain supported compilers and dialects otherwise an error occurs.
 func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error) {
@@ -289,6 +377,8 @@ func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error
 	default:
 		return nil, fmt.Errorf("unsupported compiler %T", c)
 	}
+	qr.Priority = req.Request.Priority
+	qr.Profile = req.Profile
 	switch d := req.Dialect.(type) {
 	case *csv.Dialect:
 		var header = !d.ResultEncoderConfig.NoHeader
@@ -297,6 +387,8 @@ func QueryRequestFromProxyRequest(req *query.ProxyRequest) (*QueryRequest, error
 		qr.Dialect.CommentPrefix = "#"
 		qr.Dialect.DateTimeFormat = "RFC3339"
 		qr.Dialect.Annotations = d.ResultEncoderConfig.Annotations
+	case *arrow.Dialect, *ndjson.Dialect:
+		// The format is negotiated with the Accept header rather than the dialect.
 	default:
 		return nil, fmt.Errorf("unsupported dialect %T", d)
 	}
@@ -331,6 +423,22 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc influxdb.Organ
 		}
 	}
 
+	// The priority and calendar may be given as parameters, so they can be
+	// set for queries that are sent as Flux rather than JSON.
+	qp := r.URL.Query()
+	if p := qp.Get("priority"); p != "" && req.Priority == "" {
+		req.Priority = query.Priority(p)
+	}
+	if qp.Get("calendar") == "true" {
+		req.Calendar = true
+	}
+	if tz := qp.Get("timezone"); tz != "" && req.Timezone == "" {
+		req.Timezone = tz
+	}
+	if p := qp.Get("profile"); p != "" && req.Profile == "" {
+		req.Profile = query.ProfileMode(p)
+	}
+
 	req = req.WithDefaults()
 	if err := req.Validate(); err != nil {
 		return nil, body.bytesRead, err
@@ -340,6 +448,40 @@ func decodeQueryRequest(ctx context.Context, r *http.Request, svc influxdb.Organ
 	return &req, body.bytesRead, err
 }
 
+// acceptedDialect returns the dialect of the results of a query an Accept
+// header prefers over annotated CSV, an Arrow IPC stream or newline delimited
+// JSON, or nil if it does not prefer either of them.
+func acceptedDialect(accept string) flux.Dialect {
+	qs := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > qs[mt] {
+			qs[mt] = q
+		}
+	}
+
+	var (
+		d     flux.Dialect
+		bestQ = qs["text/csv"]
+	)
+	if q := qs[ndjson.MediaType]; q > 0 && q >= bestQ {
+		d, bestQ = new(ndjson.Dialect), q
+	}
+	if q := qs[arrow.MediaType]; q > 0 && q >= bestQ {
+		d = new(arrow.Dialect)
+	}
+	return d
+}
+
 type countReader struct {
 	bytesRead int
 	io.Reader
@@ -351,16 +493,25 @@ func (r *countReader) Read(p []byte) (n int, err error) {
 	return n, err
 }
 
-func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth influxdb.Authorizer, svc influxdb.OrganizationService) (*query.ProxyRequest, int, error) {
+// decodeProxyQueryRequest decodes the query request. The calendar of a query is
+// resolved in defaultTimezone if neither the request nor its organization has a timezone.
+func decodeProxyQueryRequest(ctx context.Context, r *http.Request, auth influxdb.Authorizer, svc influxdb.OrganizationService, defaultTimezone string) (*query.ProxyRequest, int, error) {
 	req, n, err := decodeQueryRequest(ctx, r, svc)
 	if err != nil {
 		return nil, n, err
 	}
 
+	if req.Timezone == "" && req.Org.Timezone == "" {
+		req.Timezone = defaultTimezone
+	}
+
 	pr, err := req.ProxyRequest()
 	if err != nil {
 		return nil, n, err
 	}
+	if d := acceptedDialect(r.Header.Get("Accept")); d != nil {
+		pr.Dialect = d
+	}
 
 	var token *influxdb.Authorization
 	switch a := auth.(type) {
@@ -0,0 +1,320 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+// QueryCacheHeadersConfig configures the HTTP caching headers of the responses to queries.
+//
+// Dashboards repeat the same queries of past time ranges, whose data no longer
+// changes once the reorder windows of its buckets and a settle time have passed.
+// The responses to such queries are marked as cacheable, so that caches in front
+// of the server can serve the repeated queries.
+type QueryCacheHeadersConfig struct {
+	// MaxAge is how long caches may serve a response. No caching headers are
+	// emitted if it is less than a second.
+	MaxAge time.Duration
+	// SettleTime is how long before now, in addition to the reorder windows of
+	// its buckets, the time range of a query must end for its response to be cacheable.
+	SettleTime time.Duration
+}
+
+// cacheableQueryImports are the packages that cacheable queries may import,
+// whose functions neither have side effects nor read data of their own.
+var cacheableQueryImports = map[string]bool{
+	"date":    true,
+	"math":    true,
+	"regexp":  true,
+	"strings": true,
+}
+
+// uncacheableQueryFunctions depend on the time a query runs, read data that is
+// not bound by the time range of the query, or write data.
+var uncacheableQueryFunctions = map[string]bool{
+	"buckets": true,
+	"now":     true,
+	"to":      true,
+}
+
+// setQueryCacheHeaders marks the response to a query as cacheable if the query
+// reads a time range of its buckets that no longer changes, and returns true if it does.
+func (h *FluxHandler) setQueryCacheHeaders(ctx context.Context, w http.ResponseWriter, req *query.ProxyRequest) bool {
+	etag, ok := h.queryCacheETag(ctx, req)
+	if !ok {
+		return false
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int64(h.CacheHeaders.MaxAge/time.Second)))
+	w.Header().Set("ETag", etag)
+	// The response depends on the permissions of the token or session of the request.
+	w.Header().Set("Vary", "Authorization, Cookie")
+	return true
+}
+
+// deleteQueryCacheHeaders removes the caching headers of a response that was not written yet.
+func deleteQueryCacheHeaders(w http.ResponseWriter) {
+	w.Header().Del("Cache-Control")
+	w.Header().Del("ETag")
+	w.Header().Del("Vary")
+}
+
+// queryCacheETag returns the entity tag of the response to a query, and false
+// if the response is not cacheable. The tag identifies the request rather than
+// the bytes of the response, so it is a weak tag.
+func (h *FluxHandler) queryCacheETag(ctx context.Context, req *query.ProxyRequest) (string, bool) {
+	c := h.CacheHeaders
+	if c.MaxAge < time.Second || h.BucketService == nil || req.Profile != "" {
+		return "", false
+	}
+	compiler, ok := req.Request.Compiler.(lang.FluxCompiler)
+	if !ok {
+		return "", false
+	}
+	pkg := parser.ParseSource(compiler.Query)
+	if ast.Check(pkg) > 0 {
+		return "", false
+	}
+	q, ok := analyzeCacheableQuery(pkg, compiler.Extern)
+	if !ok {
+		return "", false
+	}
+
+	now := h.Now()
+	for _, ref := range q.buckets {
+		b, err := h.findQueryBucket(ctx, req.Request.OrganizationID, ref)
+		if err != nil || !h.bucketCacheable(ctx, b, q, now) {
+			return "", false
+		}
+	}
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n", req.Request.OrganizationID)
+	if req.Request.Authorization != nil {
+		fmt.Fprintf(hash, "%s\n", req.Request.Authorization.Identifier())
+	}
+	fmt.Fprintf(hash, "%T %+v\n", req.Dialect, req.Dialect)
+	if compiler.Extern != nil {
+		fmt.Fprintf(hash, "%s\n", ast.Format(compiler.Extern))
+	}
+	fmt.Fprintf(hash, "%s", compiler.Query)
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, true
+}
+
+// findQueryBucket finds a bucket a query reads from.
+func (h *FluxHandler) findQueryBucket(ctx context.Context, orgID platform.ID, ref queryBucketRef) (*platform.Bucket, error) {
+	if ref.name == "" {
+		id, err := platform.IDFromString(ref.id)
+		if err != nil {
+			return nil, err
+		}
+		return h.BucketService.FindBucketByID(ctx, *id)
+	}
+	return h.BucketService.FindBucket(ctx, platform.BucketFilter{
+		OrganizationID: &orgID,
+		Name:           &ref.name,
+	})
+}
+
+// bucketCacheable returns true if the time range of a query of the bucket no
+// longer changes: it has passed the reorder window of the bucket and the settle
+// time, it is not deleted by the retention of the bucket before the response
+// expires, and no series of the bucket were dropped while an earlier response
+// may still be cached.
+func (h *FluxHandler) bucketCacheable(ctx context.Context, b *platform.Bucket, q *cacheableQuery, now time.Time) bool {
+	c := h.CacheHeaders
+	if b.QueryCacheDisabled {
+		return false
+	}
+	if q.stop.After(now.Add(-c.SettleTime - b.ReorderWindow)) {
+		return false
+	}
+	if b.RetentionPeriod > 0 && q.start.Before(now.Add(-b.RetentionPeriod+c.MaxAge)) {
+		return false
+	}
+
+	if h.DropSeriesService == nil {
+		return true
+	}
+	ops, err := h.DropSeriesService.FindDropSeriesOperations(ctx, platform.DropSeriesOperationFilter{BucketID: &b.ID})
+	if err != nil {
+		return false
+	}
+	for _, op := range ops {
+		if !op.Done() || op.FinishedAt == nil || now.Sub(*op.FinishedAt) < c.MaxAge {
+			return false
+		}
+	}
+	return true
+}
+
+// queryBucketRef is a bucket a query reads from, by name or by ID.
+type queryBucketRef struct {
+	name string
+	id   string
+}
+
+// cacheableQuery is what decides whether the response to a query is cacheable:
+// the buckets it reads from and the earliest start and latest stop of its time ranges.
+type cacheableQuery struct {
+	buckets     []queryBucketRef
+	start, stop time.Time
+}
+
+// analyzeCacheableQuery returns the buckets and time range of a query, and false
+// if the query may read other data, or its time ranges are not given as times,
+// either in the query or in the options of its extern, such as the time range
+// variables of dashboards and the params of the request.
+func analyzeCacheableQuery(pkg *ast.Package, extern *ast.File) (*cacheableQuery, bool) {
+	for _, f := range pkg.Files {
+		for _, imp := range f.Imports {
+			if imp.Path == nil || !cacheableQueryImports[imp.Path.Value] {
+				return nil, false
+			}
+		}
+	}
+
+	values := externObjectValues(extern)
+	// Assignments of the query shadow the options of its extern.
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		if a, ok := n.(*ast.VariableAssignment); ok {
+			for k := range values {
+				if k.object == a.ID.Name {
+					delete(values, k)
+				}
+			}
+		}
+	}), pkg)
+
+	q := &cacheableQuery{}
+	ok := true
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		call, isCall := n.(*ast.CallExpression)
+		if !ok || !isCall {
+			return
+		}
+		callee, isIdent := call.Callee.(*ast.Identifier)
+		if !isIdent {
+			return
+		}
+		switch {
+		case uncacheableQueryFunctions[callee.Name]:
+			ok = false
+		case callee.Name == "from":
+			ok = q.addBucket(call)
+		case callee.Name == "range":
+			ok = q.addRange(call, values)
+		}
+	}), pkg)
+
+	if !ok || len(q.buckets) == 0 || q.stop.IsZero() {
+		return nil, false
+	}
+	return q, true
+}
+
+// addBucket adds the bucket of a call of from(), and returns false if it is not a literal.
+func (q *cacheableQuery) addBucket(call *ast.CallExpression) bool {
+	if name, ok := callArgument(call, "bucket").(*ast.StringLiteral); ok {
+		q.buckets = append(q.buckets, queryBucketRef{name: name.Value})
+		return true
+	}
+	if id, ok := callArgument(call, "bucketID").(*ast.StringLiteral); ok {
+		q.buckets = append(q.buckets, queryBucketRef{id: id.Value})
+		return true
+	}
+	return false
+}
+
+// addRange adds the time range of a call of range(), and returns false if its
+// start or stop is not a time. A range without a stop ends now.
+func (q *cacheableQuery) addRange(call *ast.CallExpression, values map[externValueKey]ast.Expression) bool {
+	start, ok := cacheableQueryTime(callArgument(call, "start"), values)
+	if !ok {
+		return false
+	}
+	stop, ok := cacheableQueryTime(callArgument(call, "stop"), values)
+	if !ok {
+		return false
+	}
+	if q.start.IsZero() || start.Before(q.start) {
+		q.start = start
+	}
+	if stop.After(q.stop) {
+		q.stop = stop
+	}
+	return true
+}
+
+// cacheableQueryTime returns the time of a literal, or of a property of an
+// object of the extern such as v.timeRangeStop.
+func cacheableQueryTime(e ast.Expression, values map[externValueKey]ast.Expression) (time.Time, bool) {
+	if m, ok := e.(*ast.MemberExpression); ok {
+		obj, ok := m.Object.(*ast.Identifier)
+		if !ok {
+			return time.Time{}, false
+		}
+		e = values[externValueKey{object: obj.Name, property: propertyName(m.Property)}]
+	}
+	if dt, ok := e.(*ast.DateTimeLiteral); ok {
+		return dt.Value, true
+	}
+	return time.Time{}, false
+}
+
+// externValueKey is a property of an object assigned in an extern.
+type externValueKey struct {
+	object, property string
+}
+
+// externObjectValues returns the properties of the objects an extern assigns,
+// such as option v = {timeRangeStart: 2019-01-01T00:00:00Z}.
+func externObjectValues(extern *ast.File) map[externValueKey]ast.Expression {
+	values := make(map[externValueKey]ast.Expression)
+	if extern == nil {
+		return values
+	}
+	for _, s := range extern.Body {
+		var a *ast.VariableAssignment
+		switch s := s.(type) {
+		case *ast.OptionStatement:
+			a, _ = s.Assignment.(*ast.VariableAssignment)
+		case *ast.VariableAssignment:
+			a = s
+		}
+		if a == nil {
+			continue
+		}
+		obj, ok := a.Init.(*ast.ObjectExpression)
+		if !ok {
+			continue
+		}
+		for _, p := range obj.Properties {
+			if p.Value != nil {
+				values[externValueKey{object: a.ID.Name, property: propertyName(p.Key)}] = p.Value
+			}
+		}
+	}
+	return values
+}
+
+// propertyName returns the name of the key of a property or member, which is
+// an identifier or a string literal.
+func propertyName(n ast.Node) string {
+	switch n := n.(type) {
+	case *ast.Identifier:
+		return n.Name
+	case *ast.StringLiteral:
+		return n.Value
+	}
+	return ""
+}
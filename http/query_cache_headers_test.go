@@ -0,0 +1,214 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestAnalyzeCacheableQuery(t *testing.T) {
+	extern := parser.ParseSource(`option v = {timeRangeStart: 2019-01-01T00:00:00Z, timeRangeStop: 2019-01-02T00:00:00Z}`).Files[0]
+	tests := []struct {
+		name      string
+		query     string
+		cacheable bool
+		buckets   []queryBucketRef
+	}{
+		{
+			name:      "absolute range",
+			query:     `from(bucket: "a") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)`,
+			cacheable: true,
+			buckets:   []queryBucketRef{{name: "a"}},
+		},
+		{
+			name:      "range of the extern",
+			query:     `from(bucketID: "020f755c3c082000") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)`,
+			cacheable: true,
+			buckets:   []queryBucketRef{{id: "020f755c3c082000"}},
+		},
+		{
+			name:  "relative range",
+			query: `from(bucket: "a") |> range(start: -1h, stop: -30m)`,
+		},
+		{
+			name:  "range that ends now",
+			query: `from(bucket: "a") |> range(start: 2019-01-01T00:00:00Z)`,
+		},
+		{
+			name:  "extern shadowed by the query",
+			query: "v = {timeRangeStart: -1h, timeRangeStop: now()}\nfrom(bucket: \"a\") |> range(start: v.timeRangeStart, stop: v.timeRangeStop)",
+		},
+		{
+			name:  "bucket that is not a literal",
+			query: "b = \"a\"\nfrom(bucket: b) |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)",
+		},
+		{
+			name:  "import with side effects",
+			query: "import \"http\"\nfrom(bucket: \"a\") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)",
+		},
+		{
+			name:  "write",
+			query: `from(bucket: "a") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z) |> to(bucket: "b")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg := parser.ParseSource(tt.query)
+			if ast.Check(pkg) > 0 {
+				t.Fatal(ast.GetError(pkg))
+			}
+			q, ok := analyzeCacheableQuery(pkg, extern)
+			if ok != tt.cacheable {
+				t.Fatalf("got cacheable %v, want %v", ok, tt.cacheable)
+			}
+			if !ok {
+				return
+			}
+			if len(q.buckets) != len(tt.buckets) || q.buckets[0] != tt.buckets[0] {
+				t.Errorf("got buckets %+v, want %+v", q.buckets, tt.buckets)
+			}
+			if want := time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC); !q.stop.Equal(want) {
+				t.Errorf("got stop %v, want %v", q.stop, want)
+			}
+		})
+	}
+}
+
+func TestFluxHandler_PostQuery_CacheHeaders(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	i := inmem.NewService()
+	org := platform.Organization{Name: t.Name()}
+	if err := i.CreateOrganization(ctx, &org); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []*platform.Bucket{
+		{OrgID: org.ID, Name: "historical"},
+		{OrgID: org.ID, Name: "backfilled", QueryCacheDisabled: true},
+		{OrgID: org.ID, Name: "short", RetentionPeriod: 7 * 24 * time.Hour},
+		{OrgID: org.ID, Name: "dropped"},
+	} {
+		if err := i.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	droppedName := "dropped"
+	dropped, err := i.FindBucket(ctx, platform.BucketFilter{Name: &droppedName})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dropSeries := mock.NewDropSeriesService()
+	dropSeries.FindDropSeriesOperationsFn = func(ctx context.Context, f platform.DropSeriesOperationFilter) ([]*platform.DropSeriesOperation, error) {
+		if *f.BucketID != dropped.ID {
+			return nil, nil
+		}
+		finished := now.Add(-time.Minute)
+		return []*platform.DropSeriesOperation{{Status: platform.DropSeriesStatusSuccess, FinishedAt: &finished}}, nil
+	}
+
+	queryErr := false
+	h := NewFluxHandler(&FluxBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		QueryEventRecorder:  noopEventRecorder{},
+		OrganizationService: i,
+		BucketService:       i,
+		DropSeriesService:   dropSeries,
+		CacheHeaders: QueryCacheHeadersConfig{
+			MaxAge:     5 * time.Minute,
+			SettleTime: time.Hour,
+		},
+		ProxyQueryService: &querymock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				if queryErr {
+					return flux.Statistics{}, &platform.Error{Code: platform.EInternal, Msg: "failed"}
+				}
+				_, err := io.WriteString(w, "#datatype,string\r\n")
+				return flux.Statistics{}, err
+			},
+		},
+	})
+	h.Now = func() time.Time { return now }
+
+	post := func(q string) *http.Response {
+		req := httptest.NewRequest("POST", "/api/v2/query?orgID="+org.ID.String(), strings.NewReader(q))
+		req = req.WithContext(icontext.SetAuthorizer(req.Context(), &platform.Authorization{}))
+		req.Header.Set("Content-Type", "application/vnd.flux")
+		w := httptest.NewRecorder()
+		h.handleQuery(w, req)
+		return w.Result()
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		queryErr  bool
+		cacheable bool
+	}{
+		{
+			name:      "historical range",
+			query:     `from(bucket: "historical") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)`,
+			cacheable: true,
+		},
+		{
+			name:  "range within the settle time",
+			query: `from(bucket: "historical") |> range(start: 2019-01-31T00:00:00Z, stop: 2019-01-31T23:30:00Z)`,
+		},
+		{
+			name:  "bucket that opted out",
+			query: `from(bucket: "backfilled") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)`,
+		},
+		{
+			name:  "range deleted by retention",
+			query: `from(bucket: "short") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)`,
+		},
+		{
+			name:  "recently dropped series",
+			query: `from(bucket: "dropped") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)`,
+		},
+		{
+			name:     "error",
+			query:    `from(bucket: "historical") |> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)`,
+			queryErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryErr = tt.queryErr
+			res := post(tt.query)
+			cc, etag := res.Header.Get("Cache-Control"), res.Header.Get("ETag")
+			if !tt.cacheable {
+				if cc != "" || etag != "" {
+					t.Fatalf("expected no caching headers, got Cache-Control %q and ETag %q", cc, etag)
+				}
+				return
+			}
+			if cc != "public, max-age=300" {
+				t.Errorf("unexpected Cache-Control %q", cc)
+			}
+			if !strings.HasPrefix(etag, `W/"`) {
+				t.Errorf("unexpected ETag %q", etag)
+			}
+			if again := post(tt.query).Header.Get("ETag"); again != etag {
+				t.Errorf("got ETag %q for the same query, want %q", again, etag)
+			}
+		})
+	}
+}
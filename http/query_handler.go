@@ -25,6 +25,8 @@ import (
 	"github.com/influxdata/influxdb/kit/check"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/arrow"
+	"github.com/influxdata/influxdb/query/ndjson"
 	"github.com/julienschmidt/httprouter"
 	"github.com/pkg/errors"
 	prom "github.com/prometheus/client_golang/prometheus"
@@ -33,6 +35,10 @@ import (
 
 const (
 	fluxPath = "/api/v2/query"
+
+	// QueryProfileTrailer is the trailer of the JSON profile of a query,
+	// when the query asks for its profile in the trailers.
+	QueryProfileTrailer = "Influx-Query-Profile"
 )
 
 // FluxBackend is all services and associated parameters required to construct
@@ -44,6 +50,21 @@ type FluxBackend struct {
 
 	OrganizationService platform.OrganizationService
 	ProxyQueryService   query.ProxyQueryService
+	QueryService        query.QueryService
+
+	// DefaultTimezone is the timezone calendars of queries are resolved in
+	// when their organization has none, UTC if empty.
+	DefaultTimezone string
+
+	// CacheHeaders configures the HTTP caching headers of the responses to
+	// queries. The BucketService and DropSeriesService decide which queries
+	// read data that no longer changes.
+	CacheHeaders      QueryCacheHeadersConfig
+	BucketService     platform.BucketService
+	DropSeriesService platform.DropSeriesService
+
+	// Stream configures how the results of queries are streamed to clients.
+	Stream QueryStreamConfig
 }
 
 // NewFluxBackend returns a new instance of FluxBackend.
@@ -54,7 +75,13 @@ func NewFluxBackend(b *APIBackend) *FluxBackend {
 		QueryEventRecorder: b.QueryEventRecorder,
 
 		ProxyQueryService:   b.FluxService,
+		QueryService:        b.QueryService,
 		OrganizationService: b.OrganizationService,
+		DefaultTimezone:     b.QueryDefaultTimezone,
+		CacheHeaders:        b.QueryCacheHeaders,
+		BucketService:       b.BucketService,
+		DropSeriesService:   b.DropSeriesService,
+		Stream:              b.QueryStream,
 	}
 }
 
@@ -72,8 +99,17 @@ type FluxHandler struct {
 	Now                 func() time.Time
 	OrganizationService platform.OrganizationService
 	ProxyQueryService   query.ProxyQueryService
+	QueryService        query.QueryService
+	DefaultTimezone     string
+
+	CacheHeaders      QueryCacheHeadersConfig
+	BucketService     platform.BucketService
+	DropSeriesService platform.DropSeriesService
+	Stream            QueryStreamConfig
 
 	EventRecorder metric.EventRecorder
+
+	pages *queryPageStore
 }
 
 // NewFluxHandler returns a new handler at /api/v2/query for flux queries.
@@ -85,13 +121,24 @@ func NewFluxHandler(b *FluxBackend) *FluxHandler {
 		Logger:           b.Logger,
 
 		ProxyQueryService:   b.ProxyQueryService,
+		QueryService:        b.QueryService,
 		OrganizationService: b.OrganizationService,
+		DefaultTimezone:     b.DefaultTimezone,
+		CacheHeaders:        b.CacheHeaders,
+		BucketService:       b.BucketService,
+		DropSeriesService:   b.DropSeriesService,
+		Stream:              b.Stream,
 		EventRecorder:       b.QueryEventRecorder,
 	}
+	h.pages = newQueryPageStore(func() time.Time { return h.Now() })
 
 	h.HandlerFunc("POST", fluxPath, h.handleQuery)
 	h.HandlerFunc("POST", "/api/v2/query/ast", h.postFluxAST)
+	h.HandlerFunc("POST", "/api/v2/query/format", h.postFluxFormat)
 	h.HandlerFunc("POST", "/api/v2/query/analyze", h.postQueryAnalyze)
+	h.HandlerFunc("POST", queryPlanPath, h.handlePostQueryPlan)
+	h.HandlerFunc("POST", queryPagesPath, h.handlePostQueryPages)
+	h.HandlerFunc("GET", queryPagesTokenPath, h.handleGetQueryPage)
 	h.HandlerFunc("GET", "/api/v2/query/suggestions", h.getFluxSuggestions)
 	h.HandlerFunc("GET", "/api/v2/query/suggestions/:name", h.getFluxSuggestion)
 	return h
@@ -132,7 +179,7 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req, n, err := decodeProxyQueryRequest(ctx, r, a, h.OrganizationService)
+	req, n, err := decodeProxyQueryRequest(ctx, r, a, h.OrganizationService, h.DefaultTimezone)
 	if err != nil && err != platform.ErrAuthorizerNotSupported {
 		err := &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -160,11 +207,33 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	hd.SetHeaders(w)
-
-	cw := iocounter.Writer{Writer: w}
-	if _, err := h.ProxyQueryService.Query(ctx, &cw, req); err != nil {
+	if req.Profile == query.ProfileTrailers {
+		w.Header().Set("Trailer", QueryProfileTrailer)
+	}
+	cacheable := h.setQueryCacheHeaders(ctx, w, req)
+
+	// Read the rest of the body, so that the server notices when the client
+	// disconnects and cancels the context, and with it the query.
+	_, _ = io.Copy(ioutil.Discard, r.Body)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Keep-alives would send the caching headers before the query may fail.
+	stream := newQueryStreamWriter(w, h.Stream, req.Dialect, !cacheable, cancel)
+	cw := iocounter.Writer{Writer: stream}
+	stats, err := h.ProxyQueryService.Query(ctx, &cw, req)
+	if stream.Close() && err != nil && cw.Count() == 0 {
+		// Keep-alives started the response, so the error is reported in its body.
+		if err := stream.EncodeError(err); err != nil {
+			h.Logger.Info("Error writing response to client", zap.String("handler", "flux"), zap.Error(err))
+		}
+		return
+	}
+	if err != nil {
 		if cw.Count() == 0 {
 			// Only record the error headers IFF nothing has been written to w.
+			// Errors must not be cached.
+			deleteQueryCacheHeaders(w)
 			h.HandleHTTPError(ctx, handleFluxError(err), w)
 			return
 		}
@@ -172,6 +241,15 @@ func (h *FluxHandler) handleQuery(w http.ResponseWriter, r *http.Request) {
 			zap.String("handler", "flux"),
 			zap.Error(err),
 		)
+		return
+	}
+	if req.Profile == query.ProfileTrailers {
+		b, err := json.Marshal(query.NewProfile(stats))
+		if err != nil {
+			h.Logger.Info("Error encoding query profile", zap.Error(err))
+			return
+		}
+		w.Header().Set(QueryProfileTrailer, string(b))
 	}
 }
 
@@ -267,6 +345,59 @@ func (h *FluxHandler) postFluxAST(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+type postFluxFormatResponse struct {
+	Query string `json:"query"`
+}
+
+// postFluxFormat returns the canonical formatting of a flux string.
+func (h *FluxHandler) postFluxFormat(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	var request langRequest
+	ctx := r.Context()
+
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	pkg := parser.ParseSource(request.Query)
+	if ast.Check(pkg) > 0 {
+		err := ast.GetError(pkg)
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid AST",
+			Err:  err,
+		}, w)
+		return
+	}
+	// The AST has no comments, so formatting would drop them.
+	if hasFluxComments(request.Query, pkg) {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "queries with comments can not be formatted, since formatting does not preserve comments",
+		}, w)
+		return
+	}
+
+	// The package is formatted as its file, which has a package clause only if
+	// the query does.
+	res := postFluxFormatResponse{
+		Query: ast.Format(pkg.Files[0]),
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
 // postQueryAnalyze parses a query and returns any query errors.
 func (h *FluxHandler) postQueryAnalyze(w http.ResponseWriter, r *http.Request) {
 	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
@@ -415,6 +546,12 @@ func (s *FluxService) Query(ctx context.Context, w io.Writer, r *query.ProxyRequ
 
 	hreq.Header.Set("Content-Type", "application/json")
 	hreq.Header.Set("Accept", "text/csv")
+	switch r.Dialect.(type) {
+	case *arrow.Dialect:
+		hreq.Header.Set("Accept", arrow.MediaType)
+	case *ndjson.Dialect:
+		hreq.Header.Set("Accept", ndjson.MediaType)
+	}
 	hreq = hreq.WithContext(ctx)
 
 	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
@@ -270,6 +270,52 @@ func TestFluxHandler_postFluxAST(t *testing.T) {
 	}
 }
 
+func TestFluxHandler_postFluxFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		want   string
+		status int
+	}{
+		{
+			name:   "formats the query",
+			query:  `from(bucket:"a")|>range(start:-1h)`,
+			want:   `{"query":"from(bucket: \"a\")\n\t|\u003e range(start: -1h)"}` + "\n",
+			status: http.StatusOK,
+		},
+		{
+			name:   "refuses to drop comments",
+			query:  "// cpu\nfrom(bucket: \"a\")",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "refuses invalid queries",
+			query:  `from(`,
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(langRequest{Query: tt.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v2/query/format", bytes.NewReader(body))
+			h := &FluxHandler{
+				HTTPErrorHandler: ErrorHandler(0),
+			}
+			h.postFluxFormat(w, r)
+			if got := w.Code; got != tt.status {
+				t.Fatalf("http.postFluxFormat = got %d\nwant %d: %s", got, tt.status, w.Body.String())
+			}
+			if got := w.Body.String(); tt.want != "" && got != tt.want {
+				t.Errorf("http.postFluxFormat = got\n%vwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestFluxService_Check(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(HealthHandler))
 	defer ts.Close()
@@ -432,3 +478,56 @@ func TestFluxHandler_PostQuery_Errors(t *testing.T) {
 		}
 	})
 }
+
+func TestFluxHandler_PostQuery_ProfileTrailers(t *testing.T) {
+	i := inmem.NewService()
+	org := influxdb.Organization{Name: t.Name()}
+	if err := i.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &FluxBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		QueryEventRecorder:  noopEventRecorder{},
+		OrganizationService: i,
+		ProxyQueryService: &mock.ProxyQueryService{
+			QueryF: func(ctx context.Context, w io.Writer, req *query.ProxyRequest) (flux.Statistics, error) {
+				if req.Profile != query.ProfileTrailers {
+					t.Errorf("unexpected profile mode: %q", req.Profile)
+				}
+				if _, err := io.WriteString(w, "#datatype,string\r\n"); err != nil {
+					return flux.Statistics{}, err
+				}
+				return flux.Statistics{TotalDuration: 5, MaxAllocated: 64}, nil
+			},
+		},
+	}
+	h := NewFluxHandler(b)
+
+	req := httptest.NewRequest("POST", "/api/v2/query?profile=trailers&orgID="+org.ID.String(), strings.NewReader("buckets()"))
+	req = req.WithContext(icontext.SetAuthorizer(req.Context(), &influxdb.Authorization{}))
+	req.Header.Set("Content-Type", "application/vnd.flux")
+
+	w := httptest.NewRecorder()
+	h.handleQuery(w, req)
+
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", res.StatusCode)
+	}
+	if got := res.Header.Get("Trailer"); got != QueryProfileTrailer {
+		t.Errorf("unexpected trailer declaration: %q", got)
+	}
+
+	var p query.Profile
+	if err := json.Unmarshal([]byte(res.Trailer.Get(QueryProfileTrailer)), &p); err != nil {
+		t.Fatalf("failed to decode profile trailer %q: %v", res.Trailer.Get(QueryProfileTrailer), err)
+	}
+	if p.MaxAllocated != 64 {
+		t.Errorf("unexpected max allocated: %d", p.MaxAllocated)
+	}
+	if last := p.Operations[len(p.Operations)-1]; last.Name != "total" || last.Duration != 5 {
+		t.Errorf("unexpected total operation: %+v", last)
+	}
+}
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	queryHistoryPath = "/api/v2/queries/history"
+)
+
+// QueryHistoryBackend is all services and associated parameters required to construct
+// the QueryHistoryHandler.
+type QueryHistoryBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	QueryHistoryService platform.QueryHistoryService
+	OrganizationService platform.OrganizationService
+}
+
+// NewQueryHistoryBackend returns a new instance of QueryHistoryBackend.
+func NewQueryHistoryBackend(b *APIBackend) *QueryHistoryBackend {
+	return &QueryHistoryBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Logger:              b.Logger.With(zap.String("handler", "query_history")),
+		QueryHistoryService: b.QueryHistoryService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// QueryHistoryHandler represents an HTTP API handler for the history of completed queries.
+type QueryHistoryHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	QueryHistoryService platform.QueryHistoryService
+	OrganizationService platform.OrganizationService
+}
+
+// NewQueryHistoryHandler returns a new instance of QueryHistoryHandler.
+func NewQueryHistoryHandler(b *QueryHistoryBackend) *QueryHistoryHandler {
+	h := &QueryHistoryHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		QueryHistoryService: b.QueryHistoryService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", queryHistoryPath, h.handleGetQueryHistory)
+
+	return h
+}
+
+type queryHistoryEntryResponse struct {
+	*platform.QueryHistoryEntry
+	Duration string                 `json:"duration"`
+	Links    queryHistoryEntryLinks `json:"links"`
+}
+
+type queryHistoryEntryLinks struct {
+	Org string `json:"org"`
+}
+
+func newQueryHistoryEntryResponse(e *platform.QueryHistoryEntry) queryHistoryEntryResponse {
+	return queryHistoryEntryResponse{
+		QueryHistoryEntry: e,
+		Duration:          e.Duration.String(),
+		Links: queryHistoryEntryLinks{
+			Org: fmt.Sprintf("/api/v2/orgs/%s", e.OrganizationID),
+		},
+	}
+}
+
+// queryHistoryEntry returns the entry of the response.
+func (res queryHistoryEntryResponse) queryHistoryEntry() (*platform.QueryHistoryEntry, error) {
+	d, err := time.ParseDuration(res.Duration)
+	if err != nil {
+		return nil, err
+	}
+	res.QueryHistoryEntry.Duration = d
+	return res.QueryHistoryEntry, nil
+}
+
+type queryHistoryResponse struct {
+	Links   *platform.PagingLinks       `json:"links"`
+	History []queryHistoryEntryResponse `json:"history"`
+}
+
+func newQueryHistoryResponse(opts platform.FindOptions, f platform.QueryHistoryFilter, es []*platform.QueryHistoryEntry) queryHistoryResponse {
+	res := queryHistoryResponse{
+		Links:   newPagingLinks(queryHistoryPath, opts, f, len(es)),
+		History: make([]queryHistoryEntryResponse, 0, len(es)),
+	}
+	for _, e := range es {
+		res.History = append(res.History, newQueryHistoryEntryResponse(e))
+	}
+	return res
+}
+
+// handleGetQueryHistory is the HTTP handler for the GET /api/v2/queries/history route.
+func (h *QueryHistoryHandler) handleGetQueryHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := h.decodeGetQueryHistoryRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	es, err := h.QueryHistoryService.FindQueryHistory(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newQueryHistoryResponse(req.opts, req.filter, es)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type getQueryHistoryRequest struct {
+	filter platform.QueryHistoryFilter
+	opts   platform.FindOptions
+}
+
+func (h *QueryHistoryHandler) decodeGetQueryHistoryRequest(ctx context.Context, r *http.Request) (*getQueryHistoryRequest, error) {
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	switch opts.SortBy {
+	case "", "completedAt", "duration":
+	default:
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "sortBy must be completedAt or duration",
+		}
+	}
+
+	req := &getQueryHistoryRequest{
+		opts: *opts,
+	}
+
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrganizationID = &o.ID
+	}
+
+	if userID := qp.Get("userID"); userID != "" {
+		id, err := platform.IDFromString(userID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.UserID = id
+	}
+
+	if outcome := qp.Get("outcome"); outcome != "" {
+		switch outcome {
+		case platform.QuerySucceeded, platform.QueryFailed, platform.QueryCanceled:
+		default:
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("outcome must be %s, %s or %s", platform.QuerySucceeded, platform.QueryFailed, platform.QueryCanceled),
+			}
+		}
+		req.filter.Outcome = &outcome
+	}
+
+	if minDuration := qp.Get("minDuration"); minDuration != "" {
+		d, err := time.ParseDuration(minDuration)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "minDuration must be a duration such as 1s",
+				Err:  err,
+			}
+		}
+		req.filter.MinDuration = d
+	}
+
+	return req, nil
+}
+
+// QueryHistoryService connects to Influx via HTTP using tokens to find completed queries.
+type QueryHistoryService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.QueryHistoryService = (*QueryHistoryService)(nil)
+
+// AddQueryHistoryEntry is not supported over HTTP; queries are added to the
+// history by the server that ran them.
+func (s *QueryHistoryService) AddQueryHistoryEntry(ctx context.Context, e *platform.QueryHistoryEntry) error {
+	return &platform.Error{
+		Code: platform.EMethodNotAllowed,
+		Msg:  "queries can only be added to the history by the server that ran them",
+	}
+}
+
+// FindQueryHistory returns the entries of the history that match the filter.
+func (s *QueryHistoryService) FindQueryHistory(ctx context.Context, filter platform.QueryHistoryFilter, opt ...platform.FindOptions) ([]*platform.QueryHistoryEntry, error) {
+	qp := url.Values(filter.QueryParams())
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			for _, v := range vs {
+				if v != "" {
+					qp.Add(k, v)
+				}
+			}
+		}
+	}
+
+	var res queryHistoryResponse
+	if err := s.client().do(ctx, "GET", queryHistoryPath, qp, nil, &res); err != nil {
+		return nil, err
+	}
+
+	es := make([]*platform.QueryHistoryEntry, 0, len(res.History))
+	for _, r := range res.History {
+		e, err := r.queryHistoryEntry()
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+	return es, nil
+}
+
+func (s *QueryHistoryService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
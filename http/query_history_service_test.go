@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestQueryHistoryHandler(t *testing.T, s platform.QueryHistoryService) *QueryHistoryHandler {
+	return NewQueryHistoryHandler(&QueryHistoryBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		QueryHistoryService: s,
+		OrganizationService: mock.NewOrganizationService(),
+	})
+}
+
+func TestQueryHistoryHandler_handleGetQueryHistory(t *testing.T) {
+	var (
+		gotFilter platform.QueryHistoryFilter
+		gotOpts   []platform.FindOptions
+	)
+	s := mock.NewQueryHistoryService()
+	s.FindQueryHistoryFn = func(ctx context.Context, filter platform.QueryHistoryFilter, opt ...platform.FindOptions) ([]*platform.QueryHistoryEntry, error) {
+		gotFilter, gotOpts = filter, opt
+		return []*platform.QueryHistoryEntry{{
+			ID:             1,
+			OrganizationID: 2,
+			UserID:         3,
+			Query:          `from(bucket: "telegraf")`,
+			Outcome:        platform.QuerySucceeded,
+			CompletedAt:    time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+			Duration:       90 * time.Second,
+			BytesScanned:   4096,
+		}}, nil
+	}
+
+	r := httptest.NewRequest("GET", queryHistoryPath+"?orgID=0000000000000002&outcome=success&minDuration=1m&sortBy=duration&limit=5", nil)
+	w := httptest.NewRecorder()
+	newTestQueryHistoryHandler(t, s).ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	if gotFilter.OrganizationID == nil || *gotFilter.OrganizationID != 2 || gotFilter.Outcome == nil || *gotFilter.Outcome != platform.QuerySucceeded || gotFilter.MinDuration != time.Minute {
+		t.Errorf("unexpected filter %+v", gotFilter)
+	}
+	if len(gotOpts) != 1 || gotOpts[0].SortBy != "duration" || gotOpts[0].Limit != 5 {
+		t.Errorf("unexpected find options %+v", gotOpts)
+	}
+
+	var res queryHistoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.History) != 1 {
+		t.Fatalf("got %d entries, want 1", len(res.History))
+	}
+	e := res.History[0]
+	if e.Duration != "1m30s" || e.BytesScanned != 4096 || e.Links.Org != "/api/v2/orgs/0000000000000002" {
+		t.Errorf("unexpected response %+v", e)
+	}
+}
+
+func TestQueryHistoryHandler_handleGetQueryHistory_invalid(t *testing.T) {
+	for _, q := range []string{"outcome=slow", "minDuration=fast", "sortBy=bytes"} {
+		t.Run(q, func(t *testing.T) {
+			s := mock.NewQueryHistoryService()
+			r := httptest.NewRequest("GET", queryHistoryPath+"?"+q, nil)
+			w := httptest.NewRecorder()
+			newTestQueryHistoryHandler(t, s).ServeHTTP(w, r)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package http
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+)
+
+// Codes of the warnings of a Flux query.
+const (
+	warningUnusedVariable    = "unused-variable"
+	warningMissingYield      = "missing-yield"
+	warningIncompatibleRange = "incompatible-range"
+)
+
+// queryWarning is a problem of a query that does not keep it from compiling,
+// but is likely a mistake.
+type queryWarning struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func newQueryWarning(n ast.Node, code, msg string) queryWarning {
+	loc := n.Location()
+	return queryWarning{
+		Line:    loc.Start.Line,
+		Column:  loc.Start.Column,
+		Code:    code,
+		Message: msg,
+	}
+}
+
+// lintFluxQuery returns the warnings of a query that parses, ordered by their location.
+func lintFluxQuery(pkg *ast.Package) []queryWarning {
+	ws := []queryWarning{}
+	ws = append(ws, lintUnusedVariables(pkg)...)
+	ws = append(ws, lintYields(pkg)...)
+	ws = append(ws, lintRanges(pkg)...)
+	sort.SliceStable(ws, func(i, j int) bool {
+		if ws[i].Line != ws[j].Line {
+			return ws[i].Line < ws[j].Line
+		}
+		return ws[i].Column < ws[j].Column
+	})
+	return ws
+}
+
+// lintUnusedVariables warns of variables that are never referenced. Scopes are
+// not told apart, so a variable is used if any variable of its name is.
+func lintUnusedVariables(pkg *ast.Package) []queryWarning {
+	refs := &referenceCollector{names: make(map[string]bool)}
+	ast.Walk(refs, pkg)
+
+	var ws []queryWarning
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		a, ok := n.(*ast.VariableAssignment)
+		if !ok || refs.names[a.ID.Name] || refs.options[a] {
+			return
+		}
+		ws = append(ws, newQueryWarning(a, warningUnusedVariable, fmt.Sprintf("variable %q is declared but never used", a.ID.Name)))
+	}), pkg)
+	return ws
+}
+
+// referenceCollector collects the names of the identifiers that reference a
+// value, and the assignments of options, which are used by Flux itself.
+type referenceCollector struct {
+	names   map[string]bool
+	options map[*ast.VariableAssignment]bool
+}
+
+func (c *referenceCollector) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.Identifier:
+		c.names[n.Name] = true
+	case *ast.OptionStatement:
+		if a, ok := n.Assignment.(*ast.VariableAssignment); ok {
+			if c.options == nil {
+				c.options = make(map[*ast.VariableAssignment]bool)
+			}
+			c.options[a] = true
+		}
+	case *ast.VariableAssignment:
+		ast.Walk(c, n.Init)
+		return nil
+	case *ast.MemberExpression:
+		// The property of a member is not a variable.
+		ast.Walk(c, n.Object)
+		if _, ok := n.Property.(*ast.Identifier); !ok {
+			ast.Walk(c, n.Property)
+		}
+		return nil
+	case *ast.FunctionExpression:
+		// Parameters declare variables, and only their defaults reference any.
+		for _, p := range n.Params {
+			ast.Walk(c, p.Value)
+		}
+		ast.Walk(c, n.Body)
+		return nil
+	case *ast.Property:
+		// The key of a property references a variable only if it has no value,
+		// as in {a, b}.
+		if n.Value == nil {
+			ast.Walk(c, n.Key)
+		}
+		ast.Walk(c, n.Value)
+		return nil
+	case *ast.PackageClause, *ast.ImportDeclaration, *ast.BuiltinStatement:
+		return nil
+	}
+	return c
+}
+
+func (c *referenceCollector) Done(ast.Node) {}
+
+// lintYields warns of queries without a result, and of results that Flux
+// would give the same name, since every result needs a name of its own.
+func lintYields(pkg *ast.Package) []queryWarning {
+	var results []*ast.ExpressionStatement
+	for _, f := range pkg.Files {
+		for _, s := range f.Body {
+			if es, ok := s.(*ast.ExpressionStatement); ok {
+				results = append(results, es)
+			}
+		}
+	}
+
+	if len(results) == 0 {
+		if len(pkg.Files) == 0 || len(pkg.Files[0].Body) == 0 {
+			return nil
+		}
+		return []queryWarning{newQueryWarning(pkg.Files[0].Body[0], warningMissingYield, "query has no result; it must end in an expression such as from() |> range() to return data")}
+	}
+	if len(results) == 1 {
+		return nil
+	}
+
+	var ws []queryWarning
+	names := make(map[string]bool)
+	for _, es := range results {
+		name, yielded := yieldName(es.Expression)
+		switch {
+		case !yielded:
+			ws = append(ws, newQueryWarning(es, warningMissingYield, "result is not yielded; when a query has more than one result, each must end in yield() with a name of its own"))
+		case names[name]:
+			ws = append(ws, newQueryWarning(es, warningMissingYield, fmt.Sprintf("result is yielded with the name %q of another result", name)))
+		}
+		names[name] = true
+	}
+	return ws
+}
+
+// yieldName returns the name a result is yielded with, and whether it ends in yield().
+func yieldName(e ast.Expression) (string, bool) {
+	var call *ast.CallExpression
+	switch e := e.(type) {
+	case *ast.PipeExpression:
+		call = e.Call
+	case *ast.CallExpression:
+		call = e
+	}
+	if call == nil || !isIdentifier(call.Callee, "yield") {
+		return "_result", false
+	}
+	if v, ok := callArgument(call, "name").(*ast.StringLiteral); ok {
+		return v.Value, true
+	}
+	return "_result", true
+}
+
+// lintRanges warns of calls of range() that select no time, because the start
+// is not before the stop. Only literal bounds are compared.
+func lintRanges(pkg *ast.Package) []queryWarning {
+	var ws []queryWarning
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		call, ok := n.(*ast.CallExpression)
+		if !ok || !isIdentifier(call.Callee, "range") {
+			return
+		}
+		startExpr := callArgument(call, "start")
+		stopExpr := callArgument(call, "stop")
+		start, startOK := rangeBound(startExpr)
+		stop, stopOK := rangeBound(stopExpr)
+		if stopExpr == nil {
+			// The stop defaults to now.
+			stop, stopOK = rangeTime{}, true
+		}
+		if !startOK || !stopOK || !start.comparable(stop) || start.before(stop) {
+			return
+		}
+		stopText := "now()"
+		if stopExpr != nil {
+			stopText = ast.Format(stopExpr)
+		}
+		ws = append(ws, newQueryWarning(call, warningIncompatibleRange,
+			fmt.Sprintf("range start %s is not before its stop %s, so it selects no data", ast.Format(startExpr), stopText)))
+	}), pkg)
+	return ws
+}
+
+// rangeTime is a bound of a range: a time, or a duration relative to now.
+type rangeTime struct {
+	absolute bool
+	t        time.Time
+	d        time.Duration
+}
+
+func (t rangeTime) comparable(o rangeTime) bool {
+	return t.absolute == o.absolute
+}
+
+func (t rangeTime) before(o rangeTime) bool {
+	if t.absolute {
+		return t.t.Before(o.t)
+	}
+	return t.d < o.d
+}
+
+// rangeBound returns the bound of a range that is a literal.
+func rangeBound(e ast.Expression) (rangeTime, bool) {
+	switch e := e.(type) {
+	case *ast.DateTimeLiteral:
+		return rangeTime{absolute: true, t: e.Value}, true
+	case *ast.DurationLiteral:
+		d, err := ast.DurationFrom(e, time.Time{})
+		return rangeTime{d: d}, err == nil
+	case *ast.UnaryExpression:
+		if e.Operator != ast.SubtractionOperator {
+			return rangeTime{}, false
+		}
+		if b, ok := rangeBound(e.Argument); ok && !b.absolute {
+			return rangeTime{d: -b.d}, true
+		}
+	}
+	return rangeTime{}, false
+}
+
+// callArgument returns the expression of a named argument of a call, or nil.
+func callArgument(call *ast.CallExpression, name string) ast.Expression {
+	if len(call.Arguments) == 0 {
+		return nil
+	}
+	obj, ok := call.Arguments[0].(*ast.ObjectExpression)
+	if !ok {
+		return nil
+	}
+	for _, p := range obj.Properties {
+		if isIdentifier(p.Key, name) {
+			return p.Value
+		}
+	}
+	return nil
+}
+
+func isIdentifier(n ast.Node, name string) bool {
+	id, ok := n.(*ast.Identifier)
+	return ok && id.Name == name
+}
+
+// hasFluxComments returns true if the source of a package that parses has a
+// comment: a // outside of its string and regular expression literals.
+func hasFluxComments(source string, pkg *ast.Package) bool {
+	lines := strings.SplitAfter(source, "\n")
+	// Blank out the literals, which may contain //, line by line.
+	blanked := make([][]byte, len(lines))
+	for i, l := range lines {
+		blanked[i] = []byte(l)
+	}
+	blank := func(n ast.Node) {
+		loc := n.Location()
+		for line := loc.Start.Line; line <= loc.End.Line && line <= len(blanked); line++ {
+			if line < 1 {
+				continue
+			}
+			b := blanked[line-1]
+			from, to := 0, len(b)
+			if line == loc.Start.Line {
+				from = loc.Start.Column - 1
+			}
+			if line == loc.End.Line {
+				to = loc.End.Column - 1
+			}
+			for j := from; j >= 0 && j < to && j < len(b); j++ {
+				b[j] = ' '
+			}
+		}
+	}
+	ast.Walk(ast.CreateVisitor(func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.StringLiteral, *ast.RegexpLiteral:
+			blank(n)
+		}
+	}), pkg)
+
+	for _, b := range blanked {
+		if strings.Contains(string(b), "//") {
+			return true
+		}
+	}
+	return false
+}
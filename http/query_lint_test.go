@@ -0,0 +1,90 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/parser"
+)
+
+func TestLintFluxQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []queryWarning
+	}{
+		{
+			name: "no warnings",
+			query: `option task = {name: "t", every: 1h}
+bucket = "telegraf"
+f = (tables=<-, threshold=1.0) => tables |> filter(fn: (r) => r._value > threshold)
+from(bucket: bucket) |> range(start: -1h) |> f()`,
+			want: []queryWarning{},
+		},
+		{
+			name: "unused variable",
+			query: `unused = 1
+from(bucket: "telegraf") |> range(start: -1h) |> filter(fn: (r) => r.unused == "x")`,
+			want: []queryWarning{
+				{Line: 1, Column: 1, Code: warningUnusedVariable, Message: `variable "unused" is declared but never used`},
+			},
+		},
+		{
+			name: "missing yield",
+			query: `from(bucket: "a") |> range(start: -1h) |> yield(name: "a")
+from(bucket: "b") |> range(start: -1h)
+from(bucket: "c") |> range(start: -1h) |> yield(name: "a")`,
+			want: []queryWarning{
+				{Line: 2, Column: 1, Code: warningMissingYield, Message: "result is not yielded; when a query has more than one result, each must end in yield() with a name of its own"},
+				{Line: 3, Column: 1, Code: warningMissingYield, Message: `result is yielded with the name "a" of another result`},
+			},
+		},
+		{
+			name:  "no result",
+			query: `x = from(bucket: "a") |> range(start: -1h)` + "\n" + `option now = () => 2019-01-01T00:00:00Z`,
+			want: []queryWarning{
+				{Line: 1, Column: 1, Code: warningUnusedVariable, Message: `variable "x" is declared but never used`},
+				{Line: 1, Column: 1, Code: warningMissingYield, Message: "query has no result; it must end in an expression such as from() |> range() to return data"},
+			},
+		},
+		{
+			name: "incompatible range",
+			query: `from(bucket: "a")
+	|> range(start: -1h, stop: -2h)
+	|> range(start: 2019-01-02T00:00:00Z, stop: 2019-01-01T00:00:00Z)
+	|> range(start: 1h)
+	|> range(start: -2h, stop: -1h)
+	|> range(start: 2019-01-01T00:00:00Z, stop: -1h)`,
+			want: []queryWarning{
+				{Line: 2, Column: 5, Code: warningIncompatibleRange, Message: "range start -1h is not before its stop -2h, so it selects no data"},
+				{Line: 3, Column: 5, Code: warningIncompatibleRange, Message: "range start 2019-01-02T00:00:00Z is not before its stop 2019-01-01T00:00:00Z, so it selects no data"},
+				{Line: 4, Column: 5, Code: warningIncompatibleRange, Message: "range start 1h is not before its stop now(), so it selects no data"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lintFluxQuery(parser.ParseSource(tt.query))
+			if !cmp.Equal(tt.want, got) {
+				t.Errorf("unexpected warnings -want/+got:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestHasFluxComments(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{query: `from(bucket: "a") |> range(start: -1h)`, want: false},
+		{query: `from(bucket: "a//b") |> filter(fn: (r) => r.path =~ /^\/\/x/)`, want: false},
+		{query: "// the cpu\nfrom(bucket: \"a\")", want: true},
+		{query: "from(bucket: \"a\") // the cpu", want: true},
+	}
+	for _, tt := range tests {
+		if got := hasFluxComments(tt.query, parser.ParseSource(tt.query)); got != tt.want {
+			t.Errorf("hasFluxComments(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
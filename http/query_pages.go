@@ -0,0 +1,427 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	queryPagesPath      = "/api/v2/query/pages"
+	queryPagesTokenPath = "/api/v2/query/pages/:token"
+
+	defaultQueryPageSize = 1000
+	maxQueryPageSize     = 10000
+
+	// maxQueryPageCells bounds the number of values buffered for a single
+	// paginated query, so that a wide query can't exhaust the memory of the server.
+	maxQueryPageCells = 4 << 20
+	// maxQueryPageCursors is the number of paginated queries buffered at once.
+	maxQueryPageCursors = 64
+	// queryPageCursorTTL is how long the results of a paginated query are kept
+	// after the last page was fetched.
+	queryPageCursorTTL = 10 * time.Minute
+)
+
+var errQueryPageNotFound = &platform.Error{
+	Code: platform.ENotFound,
+	Msg:  "query page not found; the continue token is invalid or the results expired",
+}
+
+// queryPageColumn is the metadata of a column of a paginated table.
+type queryPageColumn struct {
+	Label    string `json:"label"`
+	DataType string `json:"dataType"`
+	Group    bool   `json:"group"`
+}
+
+// queryPageTable is a buffered table of a query result.
+type queryPageTable struct {
+	result  string
+	table   int
+	columns []queryPageColumn
+	rows    [][]interface{}
+}
+
+// queryPageCursor holds the buffered results of a paginated query.
+// Rows are addressed by their offset across all tables of all results.
+type queryPageCursor struct {
+	key          string
+	authorizerID platform.ID
+	pageSize     int
+	tables       []*queryPageTable
+	rows         int
+	expiresAt    time.Time
+}
+
+// queryPageStore keeps the buffered results of paginated queries until they expire.
+type queryPageStore struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	cursors map[string]*queryPageCursor
+}
+
+func newQueryPageStore(now func() time.Time) *queryPageStore {
+	return &queryPageStore{
+		now:     now,
+		cursors: make(map[string]*queryPageCursor),
+	}
+}
+
+// put stores the cursor, evicting expired cursors and, if the store is full,
+// the cursor closest to expiring.
+func (s *queryPageStore) put(c *queryPageCursor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var oldest *queryPageCursor
+	for key, o := range s.cursors {
+		if !now.Before(o.expiresAt) {
+			delete(s.cursors, key)
+			continue
+		}
+		if oldest == nil || o.expiresAt.Before(oldest.expiresAt) {
+			oldest = o
+		}
+	}
+	if len(s.cursors) >= maxQueryPageCursors && oldest != nil {
+		delete(s.cursors, oldest.key)
+	}
+
+	c.expiresAt = now.Add(queryPageCursorTTL)
+	s.cursors[c.key] = c
+}
+
+// get returns the cursor created by the authorizer and extends its expiry.
+func (s *queryPageStore) get(key string, authorizerID platform.ID) (*queryPageCursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	c, ok := s.cursors[key]
+	if !ok || c.authorizerID != authorizerID {
+		return nil, errQueryPageNotFound
+	}
+	if !now.Before(c.expiresAt) {
+		delete(s.cursors, key)
+		return nil, errQueryPageNotFound
+	}
+
+	c.expiresAt = now.Add(queryPageCursorTTL)
+	return c, nil
+}
+
+// encodeQueryPageToken returns the continue token of the page starting at offset.
+func encodeQueryPageToken(key string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + ":" + strconv.Itoa(offset)))
+}
+
+func decodeQueryPageToken(token string) (string, int, error) {
+	octets, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", 0, errQueryPageNotFound
+	}
+	parts := strings.SplitN(string(octets), ":", 2)
+	if len(parts) != 2 {
+		return "", 0, errQueryPageNotFound
+	}
+	offset, err := strconv.Atoi(parts[1])
+	if err != nil || offset < 0 {
+		return "", 0, errQueryPageNotFound
+	}
+	return parts[0], offset, nil
+}
+
+// newQueryPageKey returns a random key that can't be guessed from other keys.
+func newQueryPageKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type queryPageTableResponse struct {
+	Result string `json:"result"`
+	Table  int    `json:"table"`
+	// Offset is the index in the table of the first row of the page.
+	Offset int `json:"offset"`
+	// More is true if more rows of the table follow on the next page.
+	More    bool              `json:"more"`
+	Columns []queryPageColumn `json:"columns"`
+	Rows    [][]interface{}   `json:"rows"`
+}
+
+type queryPageResponse struct {
+	Links map[string]string `json:"links"`
+	// ContinueToken fetches the next page, and is omitted on the last page.
+	ContinueToken string                   `json:"continueToken,omitempty"`
+	Offset        int                      `json:"offset"`
+	TotalRows     int                      `json:"totalRows"`
+	Tables        []queryPageTableResponse `json:"tables"`
+}
+
+// page returns the page of rows starting at offset. A page contains the rows
+// of as many tables as fit, so it may end and start tables.
+func (c *queryPageCursor) page(offset int) *queryPageResponse {
+	res := &queryPageResponse{
+		Links:     map[string]string{},
+		Offset:    offset,
+		TotalRows: c.rows,
+		Tables:    []queryPageTableResponse{},
+	}
+	if c.key != "" {
+		res.Links["self"] = path.Join(queryPagesPath, encodeQueryPageToken(c.key, offset))
+	}
+
+	remaining := c.pageSize
+	start := 0
+	for _, t := range c.tables {
+		end := start + len(t.rows)
+		if end <= offset || len(t.rows) == 0 {
+			start = end
+			continue
+		}
+		if remaining == 0 {
+			break
+		}
+
+		from := 0
+		if offset > start {
+			from = offset - start
+		}
+		to := from + remaining
+		if to > len(t.rows) {
+			to = len(t.rows)
+		}
+		res.Tables = append(res.Tables, queryPageTableResponse{
+			Result:  t.result,
+			Table:   t.table,
+			Offset:  from,
+			More:    to < len(t.rows),
+			Columns: t.columns,
+			Rows:    t.rows[from:to],
+		})
+		remaining -= to - from
+		start = end
+	}
+
+	if next := offset + c.pageSize; next < c.rows {
+		res.ContinueToken = encodeQueryPageToken(c.key, next)
+		res.Links["next"] = path.Join(queryPagesPath, res.ContinueToken)
+	}
+	return res
+}
+
+// bufferQueryPages runs the query and buffers all of its tables.
+func bufferQueryPages(ctx context.Context, qs query.QueryService, req *query.Request) ([]*queryPageTable, int, error) {
+	itr, err := qs.Query(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer itr.Release()
+
+	var (
+		tables []*queryPageTable
+		rows   int
+		cells  int
+	)
+	for itr.More() {
+		res := itr.Next()
+		n := 0
+		err := res.Tables().Do(func(tbl flux.Table) error {
+			t := &queryPageTable{
+				result: res.Name(),
+				table:  n,
+				rows:   [][]interface{}{},
+			}
+			n++
+
+			key := tbl.Key()
+			for _, col := range tbl.Cols() {
+				t.columns = append(t.columns, queryPageColumn{
+					Label:    col.Label,
+					DataType: col.Type.String(),
+					Group:    key.HasCol(col.Label),
+				})
+			}
+
+			if err := tbl.Do(func(cr flux.ColReader) error {
+				cells += cr.Len() * len(cr.Cols())
+				if cells > maxQueryPageCells {
+					return &platform.Error{
+						Code: platform.EInvalid,
+						Msg:  fmt.Sprintf("query results exceed the %d values that can be paginated; narrow the query", maxQueryPageCells),
+					}
+				}
+				for i := 0; i < cr.Len(); i++ {
+					row := make([]interface{}, len(cr.Cols()))
+					for j := range cr.Cols() {
+						row[j] = sampleValue(cr, i, j)
+					}
+					t.rows = append(t.rows, row)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			rows += len(t.rows)
+			tables = append(tables, t)
+			return nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := itr.Err(); err != nil {
+		return nil, 0, err
+	}
+	return tables, rows, nil
+}
+
+// handlePostQueryPages is the HTTP handler for the POST /api/v2/query/pages route.
+// It runs the query and responds with the first page of its results. The remaining
+// pages are buffered and fetched with the continue token of the previous page.
+func (h *FluxHandler) handlePostQueryPages(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handlePostQueryPages"
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	pageSize, err := decodeQueryPageSize(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, _, err := decodeProxyQueryRequest(ctx, r, a, h.OrganizationService, h.DefaultTimezone)
+	if err != nil && err != platform.ErrAuthorizerNotSupported {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request body",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	// Transform the context into one with the request's authorization.
+	qctx := pcontext.SetAuthorizer(ctx, req.Request.Authorization)
+
+	tables, rows, err := bufferQueryPages(qctx, h.QueryService, &req.Request)
+	if err != nil {
+		h.HandleHTTPError(ctx, handleFluxError(err), w)
+		return
+	}
+
+	c := &queryPageCursor{
+		authorizerID: a.Identifier(),
+		pageSize:     pageSize,
+		tables:       tables,
+		rows:         rows,
+	}
+	// Results that fit in a single page are not kept.
+	if rows > pageSize {
+		if c.key, err = newQueryPageKey(); err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		h.pages.put(c)
+	}
+
+	h.Logger.Debug("paginated query buffered", zap.Int("rows", rows), zap.Int("tables", len(tables)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, c.page(0)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeQueryPageSize(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("pageSize")
+	if v == "" {
+		return defaultQueryPageSize, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > maxQueryPageSize {
+		return 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("pageSize must be between 1 and %d", maxQueryPageSize),
+		}
+	}
+	return n, nil
+}
+
+// handleGetQueryPage is the HTTP handler for the GET /api/v2/query/pages/:token route.
+func (h *FluxHandler) handleGetQueryPage(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handleGetQueryPage"
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EUnauthorized,
+			Msg:  "authorization is invalid or missing in the query request",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	key, offset, err := decodeQueryPageToken(params.ByName("token"))
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	c, err := h.pages.get(key, a.Identifier())
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if offset >= c.rows {
+		h.HandleHTTPError(ctx, errQueryPageNotFound, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, c.page(offset)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestFluxHandler_QueryPages(t *testing.T) {
+	t0 := execute.Time(time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC).UnixNano())
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "host", Type: flux.TString},
+	}
+
+	i := inmem.NewService()
+	org := influxdb.Organization{Name: t.Name()}
+	if err := i.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewFluxHandler(&FluxBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		QueryEventRecorder:  noopEventRecorder{},
+		OrganizationService: i,
+		QueryService: &mock.QueryService{
+			QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+				return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+					Nm: "_result",
+					Tbls: []*executetest.Table{
+						{
+							KeyCols: []string{"host"},
+							ColMeta: cols,
+							Data: [][]interface{}{
+								{t0, 1.0, "a"},
+								{t0 + 1, 2.0, "a"},
+								{t0 + 2, 3.0, "a"},
+							},
+						},
+						{
+							KeyCols: []string{"host"},
+							ColMeta: cols,
+							Data: [][]interface{}{
+								{t0, 4.0, "b"},
+								{t0 + 1, nil, "b"},
+							},
+						},
+					},
+				}}), nil
+			},
+		},
+	})
+
+	do := func(t *testing.T, authz influxdb.Authorizer, method, url string) (int, *queryPageResponse) {
+		t.Helper()
+
+		var body *strings.Reader
+		if method == "POST" {
+			body = strings.NewReader(`from(bucket: "b") |> range(start: -1h)`)
+		} else {
+			body = strings.NewReader("")
+		}
+		r := httptest.NewRequest(method, url, body)
+		r.Header.Set("Content-Type", "application/vnd.flux")
+		r = r.WithContext(icontext.SetAuthorizer(r.Context(), authz))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		var res queryPageResponse
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, &res
+	}
+
+	owner := &influxdb.Authorization{ID: 1}
+	other := &influxdb.Authorization{ID: 2}
+
+	t.Run("pages continue across table boundaries", func(t *testing.T) {
+		type table struct {
+			offset int
+			more   bool
+			rows   int
+		}
+		want := [][]table{
+			{{offset: 0, more: true, rows: 2}},
+			{{offset: 2, more: false, rows: 1}, {offset: 0, more: true, rows: 1}},
+			{{offset: 1, more: false, rows: 1}},
+		}
+
+		code, res := do(t, owner, "POST", "/api/v2/query/pages?pageSize=2&orgID="+org.ID.String())
+		if code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", code, http.StatusOK)
+		}
+		for n, tables := range want {
+			if res.TotalRows != 5 || res.Offset != n*2 {
+				t.Fatalf("page %d: unexpected offset %d and total rows %d", n, res.Offset, res.TotalRows)
+			}
+			if len(res.Tables) != len(tables) {
+				t.Fatalf("page %d: got %d tables, want %d", n, len(res.Tables), len(tables))
+			}
+			for j, tbl := range tables {
+				got := res.Tables[j]
+				if got.Offset != tbl.offset || got.More != tbl.more || len(got.Rows) != tbl.rows {
+					t.Errorf("page %d table %d: got offset %d, more %v and %d rows, want %+v", n, j, got.Offset, got.More, len(got.Rows), tbl)
+				}
+				if len(got.Columns) != 3 || !got.Columns[2].Group || got.Columns[0].DataType != "time" {
+					t.Errorf("page %d table %d: unexpected columns %+v", n, j, got.Columns)
+				}
+			}
+
+			if n == len(want)-1 {
+				if res.ContinueToken != "" {
+					t.Fatalf("expected no continue token on the last page, got %q", res.ContinueToken)
+				}
+				if v := res.Tables[0].Rows[0][1]; v != nil {
+					t.Errorf("expected null value, got %v", v)
+				}
+				break
+			}
+			if res.Links["next"] != "/api/v2/query/pages/"+res.ContinueToken {
+				t.Fatalf("unexpected next link %q", res.Links["next"])
+			}
+
+			// Pages can only be fetched by the authorizer that ran the query.
+			if code, _ := do(t, other, "GET", res.Links["next"]); code != http.StatusNotFound {
+				t.Fatalf("got status %d for another authorizer, want %d", code, http.StatusNotFound)
+			}
+
+			if code, res = do(t, owner, "GET", res.Links["next"]); code != http.StatusOK {
+				t.Fatalf("got status %d, want %d", code, http.StatusOK)
+			}
+		}
+	})
+
+	t.Run("single page results are not kept", func(t *testing.T) {
+		code, res := do(t, owner, "POST", "/api/v2/query/pages?orgID="+org.ID.String())
+		if code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", code, http.StatusOK)
+		}
+		if res.ContinueToken != "" || len(res.Tables) != 2 {
+			t.Errorf("expected all rows in one page, got %+v", res)
+		}
+	})
+
+	t.Run("invalid page size", func(t *testing.T) {
+		if code, _ := do(t, owner, "POST", "/api/v2/query/pages?pageSize=0&orgID="+org.ID.String()); code != http.StatusBadRequest {
+			t.Errorf("got status %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("invalid continue token", func(t *testing.T) {
+		if code, _ := do(t, owner, "GET", "/api/v2/query/pages/oops"); code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", code, http.StatusNotFound)
+		}
+	})
+}
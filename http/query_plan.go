@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+)
+
+const queryPlanPath = "/api/v2/query/plan"
+
+// handlePostQueryPlan is the HTTP handler for the POST /api/v2/query/plan route.
+// It returns the logical and physical plan of a query without executing it.
+func (h *FluxHandler) handlePostQueryPlan(w http.ResponseWriter, r *http.Request) {
+	const op = "http/handlePostQueryPlan"
+	span, r := tracing.ExtractFromHTTPRequest(r, "FluxHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+
+	req, _, err := decodeQueryRequest(ctx, r, h.OrganizationService)
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request body",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+	if req.Timezone == "" && req.Org.Timezone == "" {
+		req.Timezone = h.DefaultTimezone
+	}
+
+	pr, err := req.ProxyRequest()
+	if err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request body",
+			Op:   op,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	qp, err := query.PlanQuery(ctx, pr.Request.Compiler)
+	if err != nil {
+		h.HandleHTTPError(ctx, handleFluxError(err), w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, qp); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// Plan returns the logical and physical plan of a query without executing it.
+func (s *FluxService) Plan(ctx context.Context, r *query.ProxyRequest) (*query.QueryPlan, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(s.Addr, queryPlanPath)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	params := url.Values{}
+	params.Set(OrgID, r.Request.OrganizationID.String())
+	u.RawQuery = params.Encode()
+
+	qreq, err := QueryRequestFromProxyRequest(r)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(qreq); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+
+	hreq, err := http.NewRequest("POST", u.String(), &body)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	SetToken(s.Token, hreq)
+	hreq.Header.Set("Content-Type", "application/json")
+	hreq = hreq.WithContext(ctx)
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(hreq)
+	if err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+
+	var qp query.QueryPlan
+	if err := json.NewDecoder(resp.Body).Decode(&qp); err != nil {
+		return nil, tracing.LogError(span, err)
+	}
+	return &qp, nil
+}
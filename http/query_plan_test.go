@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestFluxHandler_QueryPlan(t *testing.T) {
+	i := inmem.NewService()
+	org := influxdb.Organization{Name: t.Name()}
+	if err := i.CreateOrganization(context.Background(), &org); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewFluxHandler(&FluxBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		QueryEventRecorder:  noopEventRecorder{},
+		OrganizationService: i,
+	})
+	server := httptest.NewServer(h)
+	defer server.Close()
+	client := FluxService{Addr: server.URL}
+
+	newRequest := func(q string) *query.ProxyRequest {
+		return &query.ProxyRequest{
+			Request: query.Request{
+				OrganizationID: org.ID,
+				Compiler:       lang.FluxCompiler{Query: q},
+			},
+			Dialect: &csv.Dialect{},
+		}
+	}
+
+	t.Run("pushed down filter", func(t *testing.T) {
+		qp, err := client.Plan(context.Background(), newRequest(`from(bucket: "b")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._value * 2.0 > 1.0)`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(qp.Logical) != 4 {
+			t.Errorf("expected from, range, filter and yield in the logical plan, got %d nodes", len(qp.Logical))
+		}
+
+		// Only the predicate on the tag is pushed down to storage,
+		// the rest of the filter is evaluated after the read.
+		var (
+			reads   []*query.StorageRead
+			filters int
+		)
+		for _, n := range qp.Physical {
+			if n.StorageRead != nil {
+				reads = append(reads, n.StorageRead)
+			}
+			if n.Kind == "filter" {
+				filters++
+			}
+		}
+		if filters != 1 {
+			t.Errorf("expected the filter to remain in the physical plan, got %d filters", filters)
+		}
+		if len(reads) != 1 {
+			t.Fatalf("expected a single read from storage, got %d", len(reads))
+		}
+		if got, want := reads[0].Predicate, `r._measurement == "cpu"`; got != want {
+			t.Errorf("unexpected pushed down predicate: got %q, want %q", got, want)
+		}
+		if reads[0].Bucket != "b" {
+			t.Errorf("unexpected bucket of the read: %q", reads[0].Bucket)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		r := httptest.NewRequest("POST", queryPlanPath+"?orgID="+org.ID.String(), strings.NewReader(`from(`))
+		r.Header.Set("Content-Type", "application/vnd.flux")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("unexpected status code: %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/influxdb/query/ndjson"
+)
+
+// QueryStreamConfig configures how the results of queries are streamed to clients.
+type QueryStreamConfig struct {
+	// ChunkSize is the number of bytes of results that are buffered and then
+	// flushed to the client together. Results that fill less than a chunk are
+	// flushed after a second. Results are written as they are encoded if it is zero.
+	ChunkSize int
+	// KeepAliveInterval is how often a keep-alive is written while a query has
+	// not produced results yet, so that proxies do not close the connections of
+	// slow queries as idle. Keep-alives are disabled if it is zero.
+	KeepAliveInterval time.Duration
+}
+
+// queryStreamFlushInterval is how long results that fill less than a chunk
+// are buffered before they are flushed.
+const queryStreamFlushInterval = time.Second
+
+// queryStreamDialect returns the keep-alive of a dialect and the encoder of
+// errors in the dialect, or false if the dialect has no keep-alive. A keep-alive
+// is a blank line, which readers of annotated CSV and newline delimited JSON skip
+// before the first result; comments are not part of either format.
+func queryStreamDialect(d flux.Dialect) ([]byte, func(io.Writer, error) error, bool) {
+	switch d := d.(type) {
+	case *csv.Dialect:
+		return []byte("\r\n"), csv.NewResultEncoder(d.ResultEncoderConfig).EncodeError, true
+	case *ndjson.Dialect:
+		return []byte("\n"), new(ndjson.ResultEncoder).EncodeError, true
+	}
+	return nil, nil, false
+}
+
+// queryStreamWriter writes the results of a query to the response in chunks,
+// and keep-alives until the first results are written. It cancels the query
+// when a write fails, since the client is gone.
+type queryStreamWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	c       QueryStreamConfig
+	cancel  func()
+
+	keepAlive   []byte
+	encodeError func(io.Writer, error) error
+
+	now func() time.Time
+
+	mu        sync.Mutex
+	pending   []byte
+	results   bool
+	started   bool
+	lastWrite time.Time
+	err       error
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newQueryStreamWriter returns a writer of the results of a query in the
+// dialect to w. Keep-alives are only written if keepAlive is true.
+func newQueryStreamWriter(w http.ResponseWriter, c QueryStreamConfig, d flux.Dialect, keepAlive bool, cancel func()) *queryStreamWriter {
+	s := newQueryStreamWriterAt(w, c, d, keepAlive, cancel, time.Now)
+	if interval := s.interval(); interval > 0 {
+		s.wg.Add(1)
+		go s.run(time.NewTicker(interval))
+	}
+	return s
+}
+
+// newQueryStreamWriterAt returns a writer like newQueryStreamWriter that
+// tells the time with now, and that writes keep-alives and buffered results
+// only when tick is called.
+func newQueryStreamWriterAt(w http.ResponseWriter, c QueryStreamConfig, d flux.Dialect, keepAlive bool, cancel func(), now func() time.Time) *queryStreamWriter {
+	s := &queryStreamWriter{
+		w:         w,
+		c:         c,
+		cancel:    cancel,
+		now:       now,
+		lastWrite: now(),
+		stop:      make(chan struct{}),
+	}
+	s.flusher, _ = w.(http.Flusher)
+	if keepAlive && c.KeepAliveInterval > 0 {
+		s.keepAlive, s.encodeError, _ = queryStreamDialect(d)
+	}
+	return s
+}
+
+// interval returns how often the writer ticks, or zero if it does not need to.
+func (s *queryStreamWriter) interval() time.Duration {
+	interval := time.Duration(0)
+	if s.keepAlive != nil {
+		interval = s.c.KeepAliveInterval
+	}
+	if s.c.ChunkSize > 0 && (interval == 0 || interval > queryStreamFlushInterval) {
+		interval = queryStreamFlushInterval
+	}
+	return interval
+}
+
+// Write writes results, buffering them until a chunk is filled.
+func (s *queryStreamWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.results = true
+	s.lastWrite = s.now()
+	if s.c.ChunkSize <= 0 {
+		n, err := s.w.Write(p)
+		if err != nil {
+			s.fail(err)
+		}
+		return n, err
+	}
+
+	s.pending = append(s.pending, p...)
+	if len(s.pending) >= s.c.ChunkSize {
+		s.flush()
+	}
+	return len(p), s.err
+}
+
+func (s *queryStreamWriter) run(ticker *time.Ticker) {
+	defer s.wg.Done()
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.tick(now)
+		}
+	}
+}
+
+// tick writes a keep-alive if no results were written for the keep-alive
+// interval, or flushes the buffered results.
+func (s *queryStreamWriter) tick(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.err != nil:
+	case !s.results && s.keepAlive != nil && now.Sub(s.lastWrite) >= s.c.KeepAliveInterval:
+		s.started = true
+		s.lastWrite = now
+		if _, err := s.w.Write(s.keepAlive); err != nil {
+			s.fail(err)
+		} else {
+			s.flush()
+		}
+	case len(s.pending) > 0:
+		s.flush()
+	}
+}
+
+// flush writes the pending results and flushes the response.
+// It must be called with the lock held.
+func (s *queryStreamWriter) flush() {
+	if len(s.pending) > 0 {
+		_, err := s.w.Write(s.pending)
+		s.pending = s.pending[:0]
+		if err != nil {
+			s.fail(err)
+			return
+		}
+	}
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
+
+// fail records the error of a write and cancels the query.
+// It must be called with the lock held.
+func (s *queryStreamWriter) fail(err error) {
+	s.err = err
+	s.cancel()
+}
+
+// Close stops the keep-alives and writes the pending results. It returns
+// true if keep-alives started the response, so that the response can not
+// report an error with its status anymore.
+func (s *queryStreamWriter) Close() bool {
+	close(s.stop)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil && len(s.pending) > 0 {
+		_, err := s.w.Write(s.pending)
+		s.pending = nil
+		if err != nil {
+			s.err = err
+		}
+	}
+	return s.started
+}
+
+// EncodeError writes the error of a query in the dialect of its results,
+// once keep-alives started the response.
+func (s *queryStreamWriter) EncodeError(err error) error {
+	return s.encodeError(s.w, err)
+}
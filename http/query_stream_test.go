@@ -0,0 +1,152 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/csv"
+)
+
+func TestQueryStreamWriter_Chunks(t *testing.T) {
+	w := httptest.NewRecorder()
+	clock := &streamClock{now: time.Unix(0, 0)}
+	s := newQueryStreamWriterAt(w, QueryStreamConfig{ChunkSize: 8}, csv.DefaultDialect(), false, func() {}, clock.Now)
+
+	if _, err := io.WriteString(s, "abcd"); err != nil {
+		t.Fatal(err)
+	}
+	if w.Body.Len() != 0 || w.Flushed {
+		t.Fatalf("expected results that fill less than a chunk to be buffered, got %q", w.Body.String())
+	}
+	if _, err := io.WriteString(s, "efgh"); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Body.String(); got != "abcdefgh" || !w.Flushed {
+		t.Fatalf("expected a full chunk to be flushed, got %q", got)
+	}
+	if _, err := io.WriteString(s, "ij"); err != nil {
+		t.Fatal(err)
+	}
+	clock.tick(s, queryStreamFlushInterval)
+	if got := w.Body.String(); got != "abcdefghij" {
+		t.Fatalf("expected the results that fill less than a chunk to be flushed every interval, got %q", got)
+	}
+	if _, err := io.WriteString(s, "kl"); err != nil {
+		t.Fatal(err)
+	}
+	if started := s.Close(); started {
+		t.Error("expected no keep-alives")
+	}
+	if got := w.Body.String(); got != "abcdefghijkl" {
+		t.Errorf("expected the pending results to be written on close, got %q", got)
+	}
+}
+
+// streamClock is the clock of a queryStreamWriter that only moves when told to.
+type streamClock struct {
+	now time.Time
+}
+
+func (c *streamClock) Now() time.Time { return c.now }
+
+// tick advances the clock by d and ticks s.
+func (c *streamClock) tick(s *queryStreamWriter, d time.Duration) {
+	c.now = c.now.Add(d)
+	s.tick(c.now)
+}
+
+func TestQueryStreamWriter_KeepAlive(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := QueryStreamConfig{KeepAliveInterval: 10 * time.Millisecond}
+	clock := &streamClock{now: time.Unix(0, 0)}
+	s := newQueryStreamWriterAt(w, c, csv.DefaultDialect(), true, func() {}, clock.Now)
+
+	clock.tick(s, 5*time.Millisecond)
+	if w.Body.Len() != 0 {
+		t.Fatalf("expected no keep-alive before the interval passed, got %q", w.Body.String())
+	}
+	clock.tick(s, 5*time.Millisecond)
+	clock.tick(s, 10*time.Millisecond)
+	if got := w.Body.String(); got != "\r\n\r\n" || !w.Flushed {
+		t.Fatalf("expected a keep-alive to be flushed every interval, got %q", got)
+	}
+
+	if _, err := io.WriteString(s, "#datatype,string\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	clock.tick(s, 10*time.Millisecond)
+	clock.tick(s, 10*time.Millisecond)
+	if started := s.Close(); !started {
+		t.Fatal("expected keep-alives to start the response")
+	}
+	if got := w.Body.String(); got != "\r\n\r\n#datatype,string\r\n" {
+		t.Errorf("expected no keep-alives after the results, got %q", got)
+	}
+}
+
+func TestQueryStreamWriter_NoKeepAlive(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := QueryStreamConfig{KeepAliveInterval: 10 * time.Millisecond}
+	clock := &streamClock{now: time.Unix(0, 0)}
+	// Cacheable responses have no keep-alives, so that errors can still be reported with their status.
+	s := newQueryStreamWriterAt(w, c, csv.DefaultDialect(), false, func() {}, clock.Now)
+	if interval := s.interval(); interval != 0 {
+		t.Errorf("expected the writer not to tick, got an interval of %s", interval)
+	}
+
+	clock.tick(s, 50*time.Millisecond)
+	if started := s.Close(); started {
+		t.Fatal("expected no keep-alives")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected an empty response, got %q", w.Body.String())
+	}
+}
+
+func TestQueryStreamWriter_EncodeError(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := QueryStreamConfig{KeepAliveInterval: 10 * time.Millisecond}
+	clock := &streamClock{now: time.Unix(0, 0)}
+	s := newQueryStreamWriterAt(w, c, csv.DefaultDialect(), true, func() {}, clock.Now)
+
+	clock.tick(s, 10*time.Millisecond)
+	if started := s.Close(); !started {
+		t.Fatal("expected keep-alives to start the response")
+	}
+	if err := s.EncodeError(errors.New("query failed")); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Body.String(); !strings.Contains(got, "query failed") {
+		t.Errorf("expected the error in the response, got %q", got)
+	}
+}
+
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("client disconnected")
+}
+
+func TestQueryStreamWriter_CancelOnWriteError(t *testing.T) {
+	canceled := false
+	s := newQueryStreamWriter(failingWriter{httptest.NewRecorder()}, QueryStreamConfig{}, csv.DefaultDialect(), false, func() {
+		canceled = true
+	})
+
+	if _, err := io.WriteString(s, "abcd"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if !canceled {
+		t.Error("expected the query to be canceled")
+	}
+	if _, err := io.WriteString(s, "efgh"); err == nil {
+		t.Error("expected writes after an error to fail")
+	}
+	s.Close()
+}
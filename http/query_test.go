@@ -20,7 +20,9 @@ import (
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/mock"
 	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/arrow"
 	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/ndjson"
 )
 
 var cmpOptions = cmp.Options{
@@ -76,13 +78,14 @@ func TestQueryRequest_WithDefaults(t *testing.T) {
 
 func TestQueryRequest_Validate(t *testing.T) {
 	type fields struct {
-		Extern  *ast.File
-		Spec    *flux.Spec
-		AST     *ast.Package
-		Query   string
-		Type    string
-		Dialect QueryDialect
-		org     *platform.Organization
+		Extern   *ast.File
+		Spec     *flux.Spec
+		AST      *ast.Package
+		Query    string
+		Type     string
+		Dialect  QueryDialect
+		Priority query.Priority
+		org      *platform.Organization
 	}
 	tests := []struct {
 		name    string
@@ -174,6 +177,19 @@ func TestQueryRequest_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unknown priority",
+			fields: fields{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+				Priority: "urgent",
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid query",
 			fields: fields{
@@ -185,17 +201,30 @@ func TestQueryRequest_Validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid batch query",
+			fields: fields{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+				},
+				Priority: query.PriorityBatch,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := QueryRequest{
-				Extern:  tt.fields.Extern,
-				Spec:    tt.fields.Spec,
-				AST:     tt.fields.AST,
-				Query:   tt.fields.Query,
-				Type:    tt.fields.Type,
-				Dialect: tt.fields.Dialect,
-				Org:     tt.fields.org,
+				Extern:   tt.fields.Extern,
+				Spec:     tt.fields.Spec,
+				AST:      tt.fields.AST,
+				Query:    tt.fields.Query,
+				Type:     tt.fields.Type,
+				Dialect:  tt.fields.Dialect,
+				Priority: tt.fields.Priority,
+				Org:      tt.fields.org,
 			}
 			if err := r.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("QueryRequest.Validate() error = %v, wantErr %v", err, tt.wantErr)
@@ -385,6 +414,127 @@ func TestQueryRequest_proxyRequest(t *testing.T) {
 	}
 }
 
+func TestQueryRequest_proxyRequest_calendar(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Berlin"); err != nil {
+		t.Skipf("timezone database is not available: %v", err)
+	}
+	now := func() time.Time { return time.Date(2019, 10, 16, 10, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		timezone string
+		org      *platform.Organization
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "timezone of the organization",
+			org:  &platform.Organization{Timezone: "Europe/Berlin"},
+			want: `timezone: "Europe/Berlin"`,
+		},
+		{
+			name:     "timezone of the request",
+			timezone: "America/New_York",
+			org:      &platform.Organization{Timezone: "Europe/Berlin"},
+			want:     `timezone: "America/New_York"`,
+		},
+		{
+			name: "UTC without a timezone",
+			org:  &platform.Organization{},
+			want: `timezone: "UTC"`,
+		},
+		{
+			name:     "unknown timezone",
+			timezone: "Mars/Olympus_Mons",
+			org:      &platform.Organization{},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := QueryRequest{
+				Query:    `from(bucket: "sales") |> range(start: calendar.startOfMonth)`,
+				Calendar: true,
+				Timezone: tt.timezone,
+				Org:      tt.org,
+			}.WithDefaults()
+
+			got, err := r.proxyRequest(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryRequest.ProxyRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c := got.Request.Compiler.(lang.FluxCompiler)
+			if c.Extern == nil {
+				t.Fatal("expected the calendar to be declared")
+			}
+			if extern := ast.Format(c.Extern); !strings.Contains(extern, tt.want) {
+				t.Errorf("expected %q in the declarations:\n%s", tt.want, extern)
+			}
+		})
+	}
+}
+
+func TestQueryRequest_proxyRequest_params(t *testing.T) {
+	now := func() time.Time { return time.Date(2019, 10, 16, 10, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		req     QueryRequest
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "params are declared as an option",
+			req: QueryRequest{
+				Query:  `from(bucket: "telegraf") |> range(start: params.start) |> filter(fn: (r) => r.host == params.host)`,
+				Params: map[string]interface{}{"start": "-1h", "host": `a" or true or "`},
+			},
+			want: `option params = {host: "a\" or true or \"", start: -1h}`,
+		},
+		{
+			name: "params are validated",
+			req: QueryRequest{
+				Query:  `from(bucket: "telegraf")`,
+				Params: map[string]interface{}{"start time": "-1h"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "params cannot be declared for a spec",
+			req: QueryRequest{
+				Spec:   &flux.Spec{},
+				Params: map[string]interface{}{"start": "-1h"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.req
+			r.Org = &platform.Organization{}
+			got, err := r.WithDefaults().proxyRequest(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryRequest.ProxyRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c := got.Request.Compiler.(lang.FluxCompiler)
+			if c.Extern == nil {
+				t.Fatal("expected the params to be declared")
+			}
+			if extern := ast.Format(c.Extern); !strings.Contains(extern, tt.want) {
+				t.Errorf("expected %q in the declarations:\n%s", tt.want, extern)
+			}
+		})
+	}
+}
+
 func Test_decodeQueryRequest(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -451,6 +601,36 @@ func Test_decodeQueryRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "priority parameter of a flux query",
+			args: args{
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/?priority=batch", bytes.NewBufferString(`from()`))
+					r.Header.Set("Content-Type", "application/vnd.flux")
+					return r
+				}(),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+			},
+			want: &QueryRequest{
+				Query: "from()",
+				Type:  "flux",
+				Dialect: QueryDialect{
+					Delimiter:      ",",
+					DateTimeFormat: "RFC3339",
+					Header:         func(x bool) *bool { return &x }(true),
+				},
+				Priority: query.PriorityBatch,
+				Org: &platform.Organization{
+					ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+				},
+			},
+		},
 		{
 			name: "error decoding json",
 			args: args{
@@ -611,6 +791,32 @@ func Test_decodeProxyQueryRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "valid post query request accepting arrow",
+			args: args{
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"query": "from()"}`))
+					r.Header.Set("Accept", "text/csv;q=0.5, application/vnd.apache.arrow.stream")
+					return r
+				}(),
+				svc: &mock.OrganizationService{
+					FindOrganizationF: func(ctx context.Context, filter platform.OrganizationFilter) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+						}, nil
+					},
+				},
+			},
+			want: &query.ProxyRequest{
+				Request: query.Request{
+					OrganizationID: func() platform.ID { s, _ := platform.IDFromString("deadbeefdeadbeef"); return *s }(),
+					Compiler: lang.FluxCompiler{
+						Query: "from()",
+					},
+				},
+				Dialect: &arrow.Dialect{},
+			},
+		},
 	}
 	cmpOptions := append(cmpOptions,
 		cmpopts.IgnoreFields(lang.ASTCompiler{}, "Now"),
@@ -618,7 +824,7 @@ func Test_decodeProxyQueryRequest(t *testing.T) {
 	)
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, _, err := decodeProxyQueryRequest(tt.args.ctx, tt.args.r, tt.args.auth, tt.args.svc)
+			got, _, err := decodeProxyQueryRequest(tt.args.ctx, tt.args.r, tt.args.auth, tt.args.svc, "")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("decodeProxyQueryRequest() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -629,3 +835,30 @@ func Test_decodeProxyQueryRequest(t *testing.T) {
 		})
 	}
 }
+
+func Test_acceptedDialect(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   flux.Dialect
+	}{
+		{accept: "", want: nil},
+		{accept: "*/*", want: nil},
+		{accept: "text/csv", want: nil},
+		{accept: "application/vnd.apache.arrow.stream", want: &arrow.Dialect{}},
+		{accept: "text/csv, application/vnd.apache.arrow.stream", want: &arrow.Dialect{}},
+		{accept: "text/csv, application/vnd.apache.arrow.stream;q=0.9", want: nil},
+		{accept: "text/csv;q=0.1, application/vnd.apache.arrow.stream;q=0.9", want: &arrow.Dialect{}},
+		{accept: "application/vnd.apache.arrow.stream;q=0", want: nil},
+		{accept: "application/x-ndjson", want: &ndjson.Dialect{}},
+		{accept: "application/x-ndjson, text/csv;q=0.5", want: &ndjson.Dialect{}},
+		{accept: "application/x-ndjson;q=0.5, application/vnd.apache.arrow.stream", want: &arrow.Dialect{}},
+		{accept: "application/x-ndjson, application/vnd.apache.arrow.stream;q=0.5", want: &ndjson.Dialect{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			if got := acceptedDialect(tt.accept); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("acceptedDialect(%q) = %T, want %T", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
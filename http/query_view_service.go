@@ -0,0 +1,298 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	queryViewsPath   = "/api/v2/queryviews"
+	queryViewsIDPath = "/api/v2/queryviews/:id"
+)
+
+// QueryViewBackend is all services and associated parameters required to construct
+// the QueryViewHandler.
+type QueryViewBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	QueryViewService    platform.QueryViewService
+	OrganizationService platform.OrganizationService
+}
+
+// NewQueryViewBackend returns a new instance of QueryViewBackend.
+func NewQueryViewBackend(b *APIBackend) *QueryViewBackend {
+	return &QueryViewBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Logger:              b.Logger.With(zap.String("handler", "query_view")),
+		QueryViewService:    b.QueryViewService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// QueryViewHandler represents an HTTP API handler for query views.
+type QueryViewHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	QueryViewService    platform.QueryViewService
+	OrganizationService platform.OrganizationService
+}
+
+// NewQueryViewHandler returns a new instance of QueryViewHandler.
+func NewQueryViewHandler(b *QueryViewBackend) *QueryViewHandler {
+	h := &QueryViewHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		QueryViewService:    b.QueryViewService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", queryViewsPath, h.handleGetQueryViews)
+	h.HandlerFunc("POST", queryViewsPath, h.handlePostQueryView)
+
+	h.HandlerFunc("GET", queryViewsIDPath, h.handleGetQueryView)
+	h.HandlerFunc("DELETE", queryViewsIDPath, h.handleDeleteQueryView)
+
+	return h
+}
+
+type queryViewLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+}
+
+type queryViewResponse struct {
+	*platform.QueryView
+	// Flux is a query that reads the result stored by the view.
+	Flux  string         `json:"flux"`
+	Links queryViewLinks `json:"links"`
+}
+
+func newQueryViewResponse(v *platform.QueryView) queryViewResponse {
+	return queryViewResponse{
+		QueryView: v,
+		Flux: fmt.Sprintf(`from(bucketID: %q) |> range(start: 0) |> filter(fn: (r) => r._measurement == %q)`,
+			v.BucketID.String(), v.Name),
+		Links: queryViewLinks{
+			Self: queryViewIDPath(v.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", v.OrganizationID),
+		},
+	}
+}
+
+type queryViewsResponse struct {
+	Links      map[string]string   `json:"links"`
+	QueryViews []queryViewResponse `json:"queryViews"`
+}
+
+func newQueryViewsResponse(vs []*platform.QueryView) queryViewsResponse {
+	res := queryViewsResponse{
+		Links: map[string]string{
+			"self": queryViewsPath,
+		},
+		QueryViews: make([]queryViewResponse, 0, len(vs)),
+	}
+	for _, v := range vs {
+		res.QueryViews = append(res.QueryViews, newQueryViewResponse(v))
+	}
+	return res
+}
+
+func queryViewIDPath(id platform.ID) string {
+	return path.Join(queryViewsPath, id.String())
+}
+
+// handleGetQueryViews is the HTTP handler for the GET /api/v2/queryviews route.
+func (h *QueryViewHandler) handleGetQueryViews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := h.decodeGetQueryViewsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	vs, err := h.QueryViewService.FindQueryViews(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newQueryViewsResponse(vs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *QueryViewHandler) decodeGetQueryViewsRequest(ctx context.Context, r *http.Request) (*platform.QueryViewFilter, error) {
+	qp := r.URL.Query()
+	filter := &platform.QueryViewFilter{}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	return filter, nil
+}
+
+// handlePostQueryView is the HTTP handler for the POST /api/v2/queryviews route.
+func (h *QueryViewHandler) handlePostQueryView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	v, err := decodePostQueryViewRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.QueryViewService.CreateQueryView(ctx, v); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newQueryViewResponse(v)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostQueryViewRequest(ctx context.Context, r *http.Request) (*platform.QueryView, error) {
+	v := &platform.QueryView{}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := v.Valid(); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// handleGetQueryView is the HTTP handler for the GET /api/v2/queryviews/:id route.
+func (h *QueryViewHandler) handleGetQueryView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	v, err := h.QueryViewService.FindQueryViewByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newQueryViewResponse(v)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteQueryView is the HTTP handler for the DELETE /api/v2/queryviews/:id route.
+func (h *QueryViewHandler) handleDeleteQueryView(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.QueryViewService.DeleteQueryView(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// QueryViewService connects to Influx via HTTP using tokens to manage query views.
+type QueryViewService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.QueryViewService = (*QueryViewService)(nil)
+
+// FindQueryViewByID returns a single query view by ID.
+func (s *QueryViewService) FindQueryViewByID(ctx context.Context, id platform.ID) (*platform.QueryView, error) {
+	var res queryViewResponse
+	if err := s.client().do(ctx, "GET", queryViewIDPath(id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.QueryView, nil
+}
+
+// FindQueryViews returns all query views that match the filter.
+func (s *QueryViewService) FindQueryViews(ctx context.Context, filter platform.QueryViewFilter) ([]*platform.QueryView, error) {
+	query := url.Values{}
+	if filter.OrganizationID != nil {
+		query.Set("orgID", filter.OrganizationID.String())
+	}
+	if filter.Name != nil {
+		query.Set("name", *filter.Name)
+	}
+
+	var res queryViewsResponse
+	if err := s.client().do(ctx, "GET", queryViewsPath, query, nil, &res); err != nil {
+		return nil, err
+	}
+
+	vs := make([]*platform.QueryView, 0, len(res.QueryViews))
+	for _, v := range res.QueryViews {
+		vs = append(vs, v.QueryView)
+	}
+	return vs, nil
+}
+
+// CreateQueryView creates a new query view and sets v.ID with the new identifier.
+func (s *QueryViewService) CreateQueryView(ctx context.Context, v *platform.QueryView) error {
+	var res queryViewResponse
+	if err := s.client().do(ctx, "POST", queryViewsPath, nil, v, &res); err != nil {
+		return err
+	}
+	*v = *res.QueryView
+	return nil
+}
+
+// DeleteQueryView removes a query view by ID.
+func (s *QueryViewService) DeleteQueryView(ctx context.Context, id platform.ID) error {
+	return s.client().do(ctx, "DELETE", queryViewIDPath(id), nil, nil, nil)
+}
+
+func (s *QueryViewService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestQueryViewHandler(t *testing.T, s platform.QueryViewService) *QueryViewHandler {
+	return NewQueryViewHandler(&QueryViewBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		QueryViewService:    s,
+		OrganizationService: mock.NewOrganizationService(),
+	})
+}
+
+func TestQueryViewHandler_handlePostQueryView(t *testing.T) {
+	t.Run("creates the view", func(t *testing.T) {
+		s := mock.NewQueryViewService()
+		s.CreateQueryViewFn = func(ctx context.Context, v *platform.QueryView) error {
+			v.ID = 1
+			v.BucketID = 12
+			return nil
+		}
+
+		r := httptest.NewRequest("POST", queryViewsPath, bytes.NewBufferString(`{"orgID":"0000000000000002","name":"cpu_daily","query":"from(bucket: \"telegraf\") |> range(start: -1d)","ttlSeconds":600}`))
+		w := httptest.NewRecorder()
+		newTestQueryViewHandler(t, s).ServeHTTP(w, r)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+		}
+
+		var res queryViewResponse
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		if res.TTLSeconds != 600 || res.Links.Self != "/api/v2/queryviews/0000000000000001" {
+			t.Errorf("unexpected response %+v", res)
+		}
+		want := `from(bucketID: "000000000000000c") |> range(start: 0) |> filter(fn: (r) => r._measurement == "cpu_daily")`
+		if res.Flux != want {
+			t.Errorf("unexpected flux:\ngot  %s\nwant %s", res.Flux, want)
+		}
+	})
+
+	t.Run("invalid view is rejected", func(t *testing.T) {
+		s := mock.NewQueryViewService()
+		s.CreateQueryViewFn = func(ctx context.Context, v *platform.QueryView) error {
+			t.Fatal("no view should be created")
+			return nil
+		}
+
+		r := httptest.NewRequest("POST", queryViewsPath, bytes.NewBufferString(`{"orgID":"0000000000000002","name":"_internal","query":"from(bucket: \"telegraf\")"}`))
+		w := httptest.NewRecorder()
+		newTestQueryViewHandler(t, s).ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+		}
+	})
+}
+
+func TestQueryViewHandler_handleGetQueryViews(t *testing.T) {
+	var got platform.QueryViewFilter
+	s := mock.NewQueryViewService()
+	s.FindQueryViewsFn = func(ctx context.Context, filter platform.QueryViewFilter) ([]*platform.QueryView, error) {
+		got = filter
+		return []*platform.QueryView{{ID: 1, OrganizationID: 2, Name: "cpu_daily", BucketID: 12}}, nil
+	}
+
+	r := httptest.NewRequest("GET", queryViewsPath+"?orgID=0000000000000002&name=cpu_daily", nil)
+	w := httptest.NewRecorder()
+	newTestQueryViewHandler(t, s).ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	if got.OrganizationID == nil || *got.OrganizationID != 2 || got.Name == nil || *got.Name != "cpu_daily" {
+		t.Errorf("unexpected filter %+v", got)
+	}
+
+	var res queryViewsResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.QueryViews) != 1 || res.QueryViews[0].ID != 1 {
+		t.Errorf("unexpected response %+v", res)
+	}
+}
@@ -2,9 +2,11 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
 )
 
 const (
@@ -30,3 +32,21 @@ func queryOrganization(ctx context.Context, r *http.Request, svc platform.Organi
 
 	return svc.FindOrganization(ctx, filter)
 }
+
+// decodeIDParam decodes the ID of the route parameter name.
+func decodeIDParam(ctx context.Context, name string) (platform.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName(name)
+	if id == "" {
+		return 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("url missing %s", name),
+		}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return 0, err
+	}
+	return i, nil
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// RevisionBackend is all services and associated parameters required to construct
+// the revision handlers of a resource type.
+type RevisionBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RevisionService platform.RevisionService
+	ResourceType    platform.ResourceType
+}
+
+type revisionResponse struct {
+	Links map[string]string `json:"links"`
+	*platform.Revision
+}
+
+func newRevisionResponse(r *platform.Revision) *revisionResponse {
+	resource := fmt.Sprintf("/api/v2/%s/%s", r.ResourceType, r.ResourceID)
+	self := path.Join(resource, "revisions", strconv.Itoa(r.Version))
+	links := map[string]string{
+		"self":     self,
+		"restore":  path.Join(self, "restore"),
+		"resource": resource,
+	}
+	if r.UserID.Valid() {
+		links["user"] = fmt.Sprintf("/api/v2/users/%s", r.UserID)
+	}
+	return &revisionResponse{
+		Links:    links,
+		Revision: r,
+	}
+}
+
+type revisionsResponse struct {
+	Links     map[string]string   `json:"links"`
+	Revisions []*revisionResponse `json:"revisions"`
+}
+
+func newRevisionsResponse(typ platform.ResourceType, id platform.ID, rs []*platform.Revision) *revisionsResponse {
+	res := &revisionsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/%s/%s/revisions", typ, id),
+		},
+		Revisions: make([]*revisionResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		res.Revisions = append(res.Revisions, newRevisionResponse(r))
+	}
+	return res
+}
+
+// newGetRevisionsHandler returns a handler func for a GET to /revisions endpoints.
+func newGetRevisionsHandler(b *RevisionBackend) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, err := decodeIDParam(ctx, "id")
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		opts, err := decodeFindOptions(ctx, r)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		rs, _, err := b.RevisionService.FindRevisions(ctx, b.ResourceType, id, *opts)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		if err := encodeResponse(ctx, w, http.StatusOK, newRevisionsResponse(b.ResourceType, id, rs)); err != nil {
+			logEncodingError(b.Logger, r, err)
+			return
+		}
+	}
+}
+
+// newGetRevisionHandler returns a handler func for a GET to /revisions/:version endpoints.
+func newGetRevisionHandler(b *RevisionBackend) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, version, err := decodeRevisionParams(ctx)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		rev, err := b.RevisionService.FindRevision(ctx, b.ResourceType, id, version)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		if err := encodeResponse(ctx, w, http.StatusOK, newRevisionResponse(rev)); err != nil {
+			logEncodingError(b.Logger, r, err)
+			return
+		}
+	}
+}
+
+// newPostRevisionRestoreHandler returns a handler func for a POST to /revisions/:version/restore endpoints.
+func newPostRevisionRestoreHandler(b *RevisionBackend) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		id, version, err := decodeRevisionParams(ctx)
+		if err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		if err := b.RevisionService.RestoreRevision(ctx, b.ResourceType, id, version); err != nil {
+			b.HandleHTTPError(ctx, err, w)
+			return
+		}
+		b.Logger.Debug("revision restored", zap.String("resourceType", string(b.ResourceType)), zap.String("resourceID", id.String()), zap.Int("version", version))
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func decodeRevisionParams(ctx context.Context) (platform.ID, int, error) {
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		return platform.InvalidID(), 0, err
+	}
+
+	params := httprouter.ParamsFromContext(ctx)
+	version, err := strconv.Atoi(params.ByName("version"))
+	if err != nil || version < 1 {
+		return platform.InvalidID(), 0, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "revision version must be a positive integer",
+		}
+	}
+	return id, version, nil
+}
+
+// RevisionService connects to Influx via HTTP using tokens to manage the
+// revisions of dashboards and tasks.
+type RevisionService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.RevisionService = (*RevisionService)(nil)
+
+func revisionsPath(typ platform.ResourceType, id platform.ID) string {
+	return fmt.Sprintf("/api/v2/%s/%s/revisions", typ, id)
+}
+
+// FindRevisions returns the revisions of a resource, latest first.
+func (s *RevisionService) FindRevisions(ctx context.Context, typ platform.ResourceType, id platform.ID, opt ...platform.FindOptions) ([]*platform.Revision, int, error) {
+	var qp url.Values
+	if len(opt) > 0 {
+		qp = url.Values(opt[0].QueryParams())
+	}
+
+	var res revisionsResponse
+	if err := s.client().do(ctx, "GET", revisionsPath(typ, id), qp, nil, &res); err != nil {
+		return nil, 0, err
+	}
+
+	rs := make([]*platform.Revision, 0, len(res.Revisions))
+	for _, r := range res.Revisions {
+		rs = append(rs, r.Revision)
+	}
+	return rs, len(rs), nil
+}
+
+// FindRevision returns a single revision of a resource by its version.
+func (s *RevisionService) FindRevision(ctx context.Context, typ platform.ResourceType, id platform.ID, version int) (*platform.Revision, error) {
+	var res revisionResponse
+	if err := s.client().do(ctx, "GET", path.Join(revisionsPath(typ, id), strconv.Itoa(version)), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Revision, nil
+}
+
+// RestoreRevision puts a resource back as it was at a revision.
+func (s *RevisionService) RestoreRevision(ctx context.Context, typ platform.ResourceType, id platform.ID, version int) error {
+	return s.client().do(ctx, "POST", path.Join(revisionsPath(typ, id), strconv.Itoa(version), "restore"), nil, nil, nil)
+}
+
+func (s *RevisionService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+func newTestRevisionRouter(rs platform.RevisionService) *httprouter.Router {
+	b := &RevisionBackend{
+		HTTPErrorHandler: ErrorHandler(0),
+		Logger:           zap.NewNop(),
+		RevisionService:  rs,
+		ResourceType:     platform.TasksResourceType,
+	}
+	r := NewRouter(b.HTTPErrorHandler)
+	r.HandlerFunc("GET", tasksIDRevisionsPath, newGetRevisionsHandler(b))
+	r.HandlerFunc("GET", tasksIDRevisionsVersionPath, newGetRevisionHandler(b))
+	r.HandlerFunc("POST", tasksIDRevisionsRestorePath, newPostRevisionRestoreHandler(b))
+	return r
+}
+
+func TestRevisionHandlers_getRevisions(t *testing.T) {
+	at := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	var gotOpts platform.FindOptions
+	rs := mock.NewRevisionService()
+	rs.FindRevisionsFn = func(ctx context.Context, typ platform.ResourceType, id platform.ID, opts ...platform.FindOptions) ([]*platform.Revision, int, error) {
+		gotOpts = opts[0]
+		return []*platform.Revision{
+			{ResourceType: typ, ResourceID: id, OrgID: 1, Version: 2, UserID: 3, Time: at, Diff: "-a\n+b", Content: "b"},
+		}, 2, nil
+	}
+	h := newTestRevisionRouter(rs)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/tasks/0000000000000002/revisions?limit=1", nil))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if gotOpts.Limit != 1 {
+		t.Errorf("got find options %+v", gotOpts)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/tasks/0000000000000002/revisions"
+  },
+  "revisions": [
+    {
+      "links": {
+        "self": "/api/v2/tasks/0000000000000002/revisions/2",
+        "restore": "/api/v2/tasks/0000000000000002/revisions/2/restore",
+        "resource": "/api/v2/tasks/0000000000000002",
+        "user": "/api/v2/users/0000000000000003"
+      },
+      "resourceType": "tasks",
+      "resourceID": "0000000000000002",
+      "orgID": "0000000000000001",
+      "version": 2,
+      "userID": "0000000000000003",
+      "time": "2019-07-01T12:00:00Z",
+      "diff": "-a\n+b",
+      "content": "b"
+    }
+  ]
+}`); err != nil || !eq {
+		t.Errorf("unexpected response, err %v, diff %s", err, diff)
+	}
+}
+
+func TestRevisionHandlers_restoreRevision(t *testing.T) {
+	var restored int
+	rs := mock.NewRevisionService()
+	rs.RestoreRevisionFn = func(ctx context.Context, typ platform.ResourceType, id platform.ID, version int) error {
+		if typ != platform.TasksResourceType || id != 2 {
+			t.Errorf("got %s %s restored", typ, id)
+		}
+		restored = version
+		return nil
+	}
+	h := newTestRevisionRouter(rs)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/tasks/0000000000000002/revisions/1/restore", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if restored != 1 {
+		t.Errorf("got version %d restored, want 1", restored)
+	}
+
+	// Versions count from 1.
+	for _, version := range []string{"0", "latest"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/tasks/0000000000000002/revisions/"+version, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("got status %d for version %q, want %d", w.Code, version, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	runningQueriesPath   = "/api/v2/queries"
+	runningQueriesIDPath = "/api/v2/queries/:id"
+)
+
+// RunningQueryBackend is all services and associated parameters required to construct
+// the RunningQueryHandler.
+type RunningQueryBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RunningQueryService platform.RunningQueryService
+	OrganizationService platform.OrganizationService
+}
+
+// NewRunningQueryBackend returns a new instance of RunningQueryBackend.
+func NewRunningQueryBackend(b *APIBackend) *RunningQueryBackend {
+	return &RunningQueryBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Logger:              b.Logger.With(zap.String("handler", "running_query")),
+		RunningQueryService: b.RunningQueryService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// RunningQueryHandler represents an HTTP API handler for the queries the query engine is running.
+type RunningQueryHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	RunningQueryService platform.RunningQueryService
+	OrganizationService platform.OrganizationService
+}
+
+// NewRunningQueryHandler returns a new instance of RunningQueryHandler.
+func NewRunningQueryHandler(b *RunningQueryBackend) *RunningQueryHandler {
+	h := &RunningQueryHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		RunningQueryService: b.RunningQueryService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", runningQueriesPath, h.handleGetRunningQueries)
+
+	h.HandlerFunc("GET", runningQueriesIDPath, h.handleGetRunningQuery)
+	h.HandlerFunc("DELETE", runningQueriesIDPath, h.handleDeleteRunningQuery)
+
+	return h
+}
+
+type runningQueryLinks struct {
+	Self string `json:"self"`
+	Org  string `json:"org"`
+}
+
+type runningQueryResponse struct {
+	*platform.RunningQuery
+	Duration string            `json:"duration"`
+	Links    runningQueryLinks `json:"links"`
+}
+
+func newRunningQueryResponse(q *platform.RunningQuery) runningQueryResponse {
+	return runningQueryResponse{
+		RunningQuery: q,
+		Duration:     q.Duration.String(),
+		Links: runningQueryLinks{
+			Self: runningQueryIDPath(q.ID),
+			Org:  fmt.Sprintf("/api/v2/orgs/%s", q.OrganizationID),
+		},
+	}
+}
+
+// runningQuery returns the running query of the response.
+func (res runningQueryResponse) runningQuery() (*platform.RunningQuery, error) {
+	d, err := time.ParseDuration(res.Duration)
+	if err != nil {
+		return nil, err
+	}
+	res.RunningQuery.Duration = d
+	return res.RunningQuery, nil
+}
+
+type runningQueriesResponse struct {
+	Links   map[string]string      `json:"links"`
+	Queries []runningQueryResponse `json:"queries"`
+}
+
+func newRunningQueriesResponse(qs []*platform.RunningQuery) runningQueriesResponse {
+	res := runningQueriesResponse{
+		Links: map[string]string{
+			"self": runningQueriesPath,
+		},
+		Queries: make([]runningQueryResponse, 0, len(qs)),
+	}
+	for _, q := range qs {
+		res.Queries = append(res.Queries, newRunningQueryResponse(q))
+	}
+	return res
+}
+
+func runningQueryIDPath(id platform.ID) string {
+	return path.Join(runningQueriesPath, id.String())
+}
+
+// handleGetRunningQueries is the HTTP handler for the GET /api/v2/queries route.
+func (h *RunningQueryHandler) handleGetRunningQueries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := h.decodeGetRunningQueriesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	qs, err := h.RunningQueryService.FindRunningQueries(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueriesResponse(qs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *RunningQueryHandler) decodeGetRunningQueriesRequest(ctx context.Context, r *http.Request) (*platform.RunningQueryFilter, error) {
+	qp := r.URL.Query()
+	filter := &platform.RunningQueryFilter{}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = &o.ID
+	}
+
+	if userID := qp.Get("userID"); userID != "" {
+		id, err := platform.IDFromString(userID)
+		if err != nil {
+			return nil, err
+		}
+		filter.UserID = id
+	}
+
+	return filter, nil
+}
+
+// handleGetRunningQuery is the HTTP handler for the GET /api/v2/queries/:id route.
+func (h *RunningQueryHandler) handleGetRunningQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	q, err := h.RunningQueryService.FindRunningQueryByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRunningQueryResponse(q)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRunningQuery is the HTTP handler for the DELETE /api/v2/queries/:id route.
+// It cancels the query.
+func (h *RunningQueryHandler) handleDeleteRunningQuery(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.RunningQueryService.CancelRunningQuery(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunningQueryService connects to Influx via HTTP using tokens to list and cancel running queries.
+type RunningQueryService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.RunningQueryService = (*RunningQueryService)(nil)
+
+// FindRunningQueryByID returns a single running query by ID.
+func (s *RunningQueryService) FindRunningQueryByID(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+	var res runningQueryResponse
+	if err := s.client().do(ctx, "GET", runningQueryIDPath(id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.runningQuery()
+}
+
+// FindRunningQueries returns all running queries that match the filter.
+func (s *RunningQueryService) FindRunningQueries(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+	query := url.Values{}
+	if filter.OrganizationID != nil {
+		query.Set("orgID", filter.OrganizationID.String())
+	}
+	if filter.UserID != nil {
+		query.Set("userID", filter.UserID.String())
+	}
+
+	var res runningQueriesResponse
+	if err := s.client().do(ctx, "GET", runningQueriesPath, query, nil, &res); err != nil {
+		return nil, err
+	}
+
+	qs := make([]*platform.RunningQuery, 0, len(res.Queries))
+	for _, r := range res.Queries {
+		q, err := r.runningQuery()
+		if err != nil {
+			return nil, err
+		}
+		qs = append(qs, q)
+	}
+	return qs, nil
+}
+
+// CancelRunningQuery cancels a running query by ID.
+func (s *RunningQueryService) CancelRunningQuery(ctx context.Context, id platform.ID) error {
+	return s.client().do(ctx, "DELETE", runningQueryIDPath(id), nil, nil, nil)
+}
+
+func (s *RunningQueryService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestRunningQueryHandler(t *testing.T, s platform.RunningQueryService) *RunningQueryHandler {
+	return NewRunningQueryHandler(&RunningQueryBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zaptest.NewLogger(t),
+		RunningQueryService: s,
+		OrganizationService: mock.NewOrganizationService(),
+	})
+}
+
+func TestRunningQueryHandler_handleGetRunningQueries(t *testing.T) {
+	var got platform.RunningQueryFilter
+	s := mock.NewRunningQueryService()
+	s.FindRunningQueriesFn = func(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+		got = filter
+		return []*platform.RunningQuery{{
+			ID:             1,
+			OrganizationID: 2,
+			UserID:         3,
+			State:          "executing",
+			Duration:       90 * time.Second,
+			MemoryBytes:    1024,
+		}}, nil
+	}
+
+	r := httptest.NewRequest("GET", runningQueriesPath+"?orgID=0000000000000002&userID=0000000000000003", nil)
+	w := httptest.NewRecorder()
+	newTestRunningQueryHandler(t, s).ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	if got.OrganizationID == nil || *got.OrganizationID != 2 || got.UserID == nil || *got.UserID != 3 {
+		t.Errorf("unexpected filter %+v", got)
+	}
+
+	var res runningQueriesResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Queries) != 1 {
+		t.Fatalf("got %d queries, want 1", len(res.Queries))
+	}
+	q := res.Queries[0]
+	if q.Duration != "1m30s" || q.MemoryBytes != 1024 || q.Links.Self != "/api/v2/queries/0000000000000001" || q.Links.Org != "/api/v2/orgs/0000000000000002" {
+		t.Errorf("unexpected response %+v", q)
+	}
+}
+
+func TestRunningQueryHandler_handleDeleteRunningQuery(t *testing.T) {
+	t.Run("cancels the query", func(t *testing.T) {
+		var canceled platform.ID
+		s := mock.NewRunningQueryService()
+		s.CancelRunningQueryFn = func(ctx context.Context, id platform.ID) error {
+			canceled = id
+			return nil
+		}
+
+		r := httptest.NewRequest("DELETE", "/api/v2/queries/0000000000000001", nil)
+		w := httptest.NewRecorder()
+		newTestRunningQueryHandler(t, s).ServeHTTP(w, r)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusNoContent, w.Body.String())
+		}
+		if canceled != 1 {
+			t.Errorf("got canceled query %s, want 0000000000000001", canceled)
+		}
+	})
+
+	t.Run("finished query is not found", func(t *testing.T) {
+		s := mock.NewRunningQueryService()
+		s.CancelRunningQueryFn = func(ctx context.Context, id platform.ID) error {
+			return &platform.Error{
+				Code: platform.ENotFound,
+				Msg:  platform.ErrRunningQueryNotFound,
+			}
+		}
+
+		r := httptest.NewRequest("DELETE", "/api/v2/queries/0000000000000001", nil)
+		w := httptest.NewRecorder()
+		newTestRunningQueryHandler(t, s).ServeHTTP(w, r)
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusNotFound, w.Body.String())
+		}
+	})
+}
@@ -21,6 +21,7 @@ type ScraperBackend struct {
 	Logger *zap.Logger
 
 	ScraperStorageService      influxdb.ScraperTargetStoreService
+	ScraperTargetStatusService influxdb.ScraperTargetStatusService
 	BucketService              influxdb.BucketService
 	OrganizationService        influxdb.OrganizationService
 	UserService                influxdb.UserService
@@ -35,6 +36,7 @@ func NewScraperBackend(b *APIBackend) *ScraperBackend {
 		Logger:           b.Logger.With(zap.String("handler", "scraper")),
 
 		ScraperStorageService:      b.ScraperTargetStoreService,
+		ScraperTargetStatusService: b.ScraperTargetStatusService,
 		BucketService:              b.BucketService,
 		OrganizationService:        b.OrganizationService,
 		UserService:                b.UserService,
@@ -52,6 +54,7 @@ type ScraperHandler struct {
 	UserResourceMappingService influxdb.UserResourceMappingService
 	LabelService               influxdb.LabelService
 	ScraperStorageService      influxdb.ScraperTargetStoreService
+	ScraperTargetStatusService influxdb.ScraperTargetStatusService
 	BucketService              influxdb.BucketService
 	OrganizationService        influxdb.OrganizationService
 }
@@ -76,6 +79,7 @@ func NewScraperHandler(b *ScraperBackend) *ScraperHandler {
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		ScraperStorageService:      b.ScraperStorageService,
+		ScraperTargetStatusService: b.ScraperTargetStatusService,
 		BucketService:              b.BucketService,
 		OrganizationService:        b.OrganizationService,
 	}
@@ -84,6 +88,7 @@ func NewScraperHandler(b *ScraperBackend) *ScraperHandler {
 	h.HandlerFunc("GET", targetsPath+"/:id", h.handleGetScraperTarget)
 	h.HandlerFunc("PATCH", targetsPath+"/:id", h.handlePatchScraperTarget)
 	h.HandlerFunc("DELETE", targetsPath+"/:id", h.handleDeleteScraperTarget)
+	h.HandlerFunc("GET", targetsIDStatusPath, h.handleGetScraperTargetStatus)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -242,6 +247,7 @@ func (h *ScraperHandler) handleGetScraperTarget(w http.ResponseWriter, r *http.R
 
 type getScraperTargetsRequest struct {
 	filter influxdb.ScraperTargetFilter
+	labels labelFilter
 }
 
 func decodeScraperTargetsRequest(ctx context.Context, r *http.Request) (*getScraperTargetsRequest, error) {
@@ -272,6 +278,8 @@ func decodeScraperTargetsRequest(ctx context.Context, r *http.Request) (*getScra
 		req.filter.Org = &org
 	}
 
+	req.labels = decodeLabelFilter(r)
+
 	return req, nil
 }
 
@@ -290,6 +298,19 @@ func (h *ScraperHandler) handleGetScraperTargets(w http.ResponseWriter, r *http.
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+
+	if len(req.labels) > 0 {
+		idx, err := req.labels.filter(ctx, h.LabelService, influxdb.FindOptions{}, len(targets), func(i int) influxdb.ID { return targets[i].ID })
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		labeled := make([]influxdb.ScraperTarget, 0, len(idx))
+		for _, i := range idx {
+			labeled = append(labeled, targets[i])
+		}
+		targets = labeled
+	}
 	h.Logger.Debug("scrapers retrieved", zap.String("scrapers", fmt.Sprint(targets)))
 
 	resp, err := h.newListTargetsResponse(ctx, targets)
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const (
+	targetsIDStatusPath = targetsPath + "/:id/status"
+)
+
+type scraperTargetStatusResponse struct {
+	Links    map[string]string `json:"links"`
+	TargetID influxdb.ID       `json:"targetID"`
+	OrgID    influxdb.ID       `json:"orgID"`
+	// Health is healthy or failing after the target was scraped, and unknown before.
+	Health string `json:"health"`
+	*influxdb.ScraperTargetStatus
+}
+
+// handleGetScraperTargetStatus is the HTTP handler for the GET /api/v2/scrapers/:id/status route.
+// It returns the outcome of the last scrape of the target, so users can tell why it produces no data.
+func (h *ScraperHandler) handleGetScraperTargetStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("scraper status retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeScraperTargetIDRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	target, err := h.ScraperStorageService.GetTargetByID(ctx, *id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	resp := &scraperTargetStatusResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/scrapers/%s/status", target.ID),
+			"target": targetIDPath(target.ID),
+		},
+		TargetID: target.ID,
+		OrgID:    target.OrgID,
+		Health:   influxdb.ScraperTargetUnknown,
+	}
+
+	st, err := h.ScraperTargetStatusService.FindTargetStatus(ctx, target.ID)
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	if err == nil {
+		resp.Health = st.Health()
+		resp.ScraperTargetStatus = st
+	}
+	h.Logger.Debug("scraper status retrieved", zap.String("scraperTargetID", target.ID.String()), zap.String("health", resp.Health))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, resp); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
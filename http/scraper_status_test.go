@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	platformtesting "github.com/influxdata/influxdb/testing"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestService_handleGetScraperTargetStatus(t *testing.T) {
+	lastSuccess := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		id         string
+		status     *influxdb.ScraperTargetStatus
+		statusCode int
+		body       string
+	}{
+		{
+			name: "failing target",
+			id:   targetOneIDString,
+			status: &influxdb.ScraperTargetStatus{
+				TargetID:            targetOneID,
+				OrgID:               platformtesting.MustIDBase16("0000000000000211"),
+				LastScrape:          lastSuccess.Add(time.Minute),
+				Duration:            3,
+				LastError:           "server returned 401 Unauthorized",
+				LastSuccess:         &lastSuccess,
+				ConsecutiveFailures: 1,
+			},
+			statusCode: http.StatusOK,
+			body: `
+{
+  "links": {
+    "self": "/api/v2/scrapers/0000000000000111/status",
+    "target": "/api/v2/scrapers/0000000000000111"
+  },
+  "targetID": "0000000000000111",
+  "orgID": "0000000000000211",
+  "health": "failing",
+  "lastScrape": "2019-07-01T12:01:00Z",
+  "duration": 3,
+  "samples": 0,
+  "lastError": "server returned 401 Unauthorized",
+  "lastSuccess": "2019-07-01T12:00:00Z",
+  "consecutiveFailures": 1
+}
+`,
+		},
+		{
+			name:       "target not scraped yet",
+			id:         targetOneIDString,
+			statusCode: http.StatusOK,
+			body: `
+{
+  "links": {
+    "self": "/api/v2/scrapers/0000000000000111/status",
+    "target": "/api/v2/scrapers/0000000000000111"
+  },
+  "targetID": "0000000000000111",
+  "orgID": "0000000000000211",
+  "health": "unknown"
+}
+`,
+		},
+		{
+			name:       "missing target",
+			id:         targetTwoIDString,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scraperBackend := NewMockScraperBackend()
+			scraperBackend.HTTPErrorHandler = ErrorHandler(0)
+			scraperBackend.ScraperStorageService = &mock.ScraperTargetStoreService{
+				GetTargetByIDF: func(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTarget, error) {
+					if id != targetOneID {
+						return nil, &influxdb.Error{
+							Code: influxdb.ENotFound,
+							Msg:  "scraper target is not found",
+						}
+					}
+					return &influxdb.ScraperTarget{ID: targetOneID, OrgID: platformtesting.MustIDBase16("0000000000000211")}, nil
+				},
+			}
+			statusService := mock.NewScraperTargetStatusService()
+			statusService.FindTargetStatusFn = func(ctx context.Context, id influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+				if tt.status == nil {
+					return nil, &influxdb.Error{
+						Code: influxdb.ENotFound,
+						Msg:  influxdb.ErrScraperTargetStatusNotFound,
+					}
+				}
+				return tt.status, nil
+			}
+			scraperBackend.ScraperTargetStatusService = statusService
+			h := NewScraperHandler(scraperBackend)
+
+			r := httptest.NewRequest("GET", "http://any.tld", nil)
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{
+						Key:   "id",
+						Value: tt.id,
+					},
+				}))
+			w := httptest.NewRecorder()
+
+			h.handleGetScraperTargetStatus(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.statusCode {
+				t.Fatalf("handleGetScraperTargetStatus() = %v, want %v: %s", res.StatusCode, tt.statusCode, body)
+			}
+			if tt.body != "" {
+				if eq, diff, err := jsonEqual(string(body), tt.body); err != nil || !eq {
+					t.Errorf("handleGetScraperTargetStatus() = ***%v***", diff)
+				}
+			}
+		})
+	}
+}
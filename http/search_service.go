@@ -0,0 +1,198 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	searchPath = "/api/v2/search"
+)
+
+// SearchBackend is all services and associated parameters required to construct
+// the SearchHandler.
+type SearchBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	SearchService       platform.SearchService
+	OrganizationService platform.OrganizationService
+}
+
+// NewSearchBackend returns a new instance of SearchBackend.
+func NewSearchBackend(b *APIBackend) *SearchBackend {
+	return &SearchBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Logger:              b.Logger.With(zap.String("handler", "search")),
+		SearchService:       b.SearchService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// SearchHandler represents an HTTP API handler for searching resources.
+type SearchHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	SearchService       platform.SearchService
+	OrganizationService platform.OrganizationService
+}
+
+// NewSearchHandler returns a new instance of SearchHandler.
+func NewSearchHandler(b *SearchBackend) *SearchHandler {
+	h := &SearchHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		SearchService:       b.SearchService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", searchPath, h.handleGetSearch)
+
+	return h
+}
+
+type searchResultResponse struct {
+	*platform.SearchResult
+	Links map[string]string `json:"links"`
+}
+
+type searchResponse struct {
+	Links   *platform.PagingLinks  `json:"links"`
+	Results []searchResultResponse `json:"results"`
+}
+
+func newSearchResponse(opts platform.FindOptions, f platform.SearchFilter, rs []*platform.SearchResult) searchResponse {
+	res := searchResponse{
+		Links:   newPagingLinks(searchPath, opts, f, len(rs)),
+		Results: make([]searchResultResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		res.Results = append(res.Results, searchResultResponse{
+			SearchResult: r,
+			Links: map[string]string{
+				"self": fmt.Sprintf("/api/v2/%s/%s", r.Type, r.ID),
+				"org":  fmt.Sprintf("/api/v2/orgs/%s", r.OrgID),
+			},
+		})
+	}
+	return res
+}
+
+// handleGetSearch is the HTTP handler for the GET /api/v2/search route.
+func (h *SearchHandler) handleGetSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := h.decodeGetSearchRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, err := h.SearchService.Search(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := newSearchResponse(req.opts, req.filter, rs)
+	if err := encodeListResponse(ctx, w, req.opts.Fields, res.Links, "results", res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type getSearchRequest struct {
+	filter platform.SearchFilter
+	opts   platform.FindOptions
+}
+
+func (h *SearchHandler) decodeGetSearchRequest(ctx context.Context, r *http.Request) (*getSearchRequest, error) {
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	// Results are sorted by how well they match.
+	if opts.SortBy != "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "search results cannot be sorted",
+		}
+	}
+
+	req := &getSearchRequest{
+		opts: *opts,
+	}
+
+	qp := r.URL.Query()
+	req.filter.Query = qp.Get("q")
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = &o.ID
+	}
+
+	for _, t := range qp["type"] {
+		req.filter.Types = append(req.filter.Types, platform.ResourceType(t))
+	}
+
+	if err := req.filter.Valid(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// SearchService connects to Influx via HTTP using tokens to search resources.
+type SearchService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.SearchService = (*SearchService)(nil)
+
+// Search returns the resources that match the filter, best matches first.
+func (s *SearchService) Search(ctx context.Context, filter platform.SearchFilter, opt ...platform.FindOptions) ([]*platform.SearchResult, error) {
+	qp := url.Values(filter.QueryParams())
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			for _, v := range vs {
+				qp.Add(k, v)
+			}
+		}
+	}
+
+	var res searchResponse
+	if err := s.client().do(ctx, "GET", searchPath, qp, nil, &res); err != nil {
+		return nil, err
+	}
+
+	rs := make([]*platform.SearchResult, 0, len(res.Results))
+	for _, r := range res.Results {
+		rs = append(rs, r.SearchResult)
+	}
+	return rs, nil
+}
+
+func (s *SearchService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
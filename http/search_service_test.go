@@ -0,0 +1,82 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newTestSearchHandler(ss platform.SearchService) *SearchHandler {
+	return NewSearchHandler(&SearchBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		SearchService:       ss,
+		OrganizationService: mock.NewOrganizationService(),
+	})
+}
+
+func TestSearchHandler_handleGetSearch(t *testing.T) {
+	var got platform.SearchFilter
+	ss := mock.NewSearchService()
+	ss.SearchFn = func(ctx context.Context, filter platform.SearchFilter, opts ...platform.FindOptions) ([]*platform.SearchResult, error) {
+		got = filter
+		return []*platform.SearchResult{
+			{Type: platform.BucketsResourceType, ID: 2, OrgID: 1, Name: "cpu", Description: "cpu usage", Score: 9},
+		}, nil
+	}
+	h := newTestSearchHandler(ss)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/search?q=cpu&type=buckets", nil))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if got.Query != "cpu" || len(got.Types) != 1 || got.Types[0] != platform.BucketsResourceType {
+		t.Errorf("got filter %+v", got)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/search?descending=false&limit=20&offset=0&q=cpu&type=buckets"
+  },
+  "results": [
+    {
+      "links": {
+        "self": "/api/v2/buckets/0000000000000002",
+        "org": "/api/v2/orgs/0000000000000001"
+      },
+      "type": "buckets",
+      "id": "0000000000000002",
+      "orgID": "0000000000000001",
+      "name": "cpu",
+      "description": "cpu usage",
+      "score": 9
+    }
+  ]
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	// A query needs at least one word, and results are ordered by how well they match.
+	for _, u := range []string{
+		"http://any.url/api/v2/search?q=%20-",
+		"http://any.url/api/v2/search?q=cpu&type=orgs",
+		"http://any.url/api/v2/search?q=cpu&sortBy=name",
+	} {
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", u, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want %d", u, w.Code, http.StatusBadRequest)
+		}
+	}
+}
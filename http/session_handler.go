@@ -67,6 +67,13 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.PasswordsService.ComparePassword(ctx, req.Username, req.Password); err != nil {
+		// The password matched but has expired, so the user is told to change it.
+		if vs := platform.PasswordViolations(err); len(vs) > 0 {
+			if err := encodePasswordError(ctx, h, w, err); err != nil {
+				logEncodingError(h.Logger, r, err)
+			}
+			return
+		}
 		// Don't log here, it should already be handled by the service
 		UnauthorizedError(ctx, h, w)
 		return
@@ -17,7 +17,8 @@ import (
 // NewMockSessionBackend returns a SessionBackend with mock services.
 func NewMockSessionBackend() *platformhttp.SessionBackend {
 	return &platformhttp.SessionBackend{
-		Logger: zap.NewNop().With(zap.String("handler", "session")),
+		HTTPErrorHandler: platformhttp.ErrorHandler(0),
+		Logger:           zap.NewNop().With(zap.String("handler", "session")),
 
 		SessionService:   mock.NewSessionService(),
 		PasswordsService: mock.NewPasswordsService("", ""),
@@ -73,6 +74,45 @@ func TestSessionHandler_handleSignin(t *testing.T) {
 				code:   http.StatusNoContent,
 			},
 		},
+		{
+			name: "expired password",
+			fields: fields{
+				SessionService: mock.NewSessionService(),
+				PasswordsService: &mock.PasswordsService{
+					ComparePasswordFn: func(context.Context, string, string) error {
+						return platform.NewPasswordPolicyError(platform.EForbidden, platform.PasswordViolation{
+							Rule:    platform.PasswordRuleMaxAge,
+							Message: "password has expired",
+						})
+					},
+				},
+			},
+			args: args{
+				user:     "user1",
+				password: "supersecret",
+			},
+			wants: wants{
+				code: http.StatusForbidden,
+			},
+		},
+		{
+			name: "incorrect password",
+			fields: fields{
+				SessionService: mock.NewSessionService(),
+				PasswordsService: &mock.PasswordsService{
+					ComparePasswordFn: func(context.Context, string, string) error {
+						return &platform.Error{Code: platform.EForbidden, Msg: "your username or password is incorrect"}
+					},
+				},
+			},
+			args: args{
+				user:     "user1",
+				password: "supersecret",
+			},
+			wants: wants{
+				code: http.StatusUnauthorized,
+			},
+		},
 	}
 
 	for _, tt := range tests {
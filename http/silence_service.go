@@ -0,0 +1,267 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+)
+
+// SilenceBackend is all services and associated parameters
+// required to construct the SilenceHandler.
+type SilenceBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	SilenceService      influxdb.SilenceService
+	OrganizationService influxdb.OrganizationService
+}
+
+// NewSilenceBackend returns a new instance of SilenceBackend.
+func NewSilenceBackend(b *APIBackend) *SilenceBackend {
+	return &SilenceBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "silence")),
+
+		SilenceService:      b.SilenceService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// SilenceHandler represents an HTTP API handler for silences.
+type SilenceHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	SilenceService      influxdb.SilenceService
+	OrganizationService influxdb.OrganizationService
+}
+
+const (
+	silencesPath         = "/api/v2/silences"
+	silencesIDPath       = "/api/v2/silences/:id"
+	silencesIDExpirePath = "/api/v2/silences/:id/expire"
+)
+
+// NewSilenceHandler returns a new instance of SilenceHandler.
+func NewSilenceHandler(b *SilenceBackend) *SilenceHandler {
+	h := &SilenceHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		SilenceService:      b.SilenceService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("POST", silencesPath, h.handlePostSilence)
+	h.HandlerFunc("GET", silencesPath, h.handleGetSilences)
+	h.HandlerFunc("GET", silencesIDPath, h.handleGetSilence)
+	h.HandlerFunc("POST", silencesIDExpirePath, h.handlePostSilenceExpire)
+
+	return h
+}
+
+type silenceResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Silence
+}
+
+func newSilenceResponse(sl *influxdb.Silence) *silenceResponse {
+	res := &silenceResponse{
+		Links: map[string]string{
+			"self":   fmt.Sprintf("/api/v2/silences/%s", sl.ID),
+			"expire": fmt.Sprintf("/api/v2/silences/%s/expire", sl.ID),
+			"org":    fmt.Sprintf("/api/v2/orgs/%s", sl.OrgID),
+		},
+		Silence: *sl,
+	}
+	if sl.CheckID.Valid() {
+		res.Links["check"] = fmt.Sprintf("/api/v2/checks/%s", sl.CheckID)
+	}
+	if sl.RuleID.Valid() {
+		res.Links["rule"] = fmt.Sprintf("/api/v2/notificationRules/%s", sl.RuleID)
+	}
+	return res
+}
+
+type silencesResponse struct {
+	Links    map[string]string  `json:"links"`
+	Silences []*silenceResponse `json:"silences"`
+}
+
+func newSilencesResponse(ss []*influxdb.Silence) *silencesResponse {
+	res := &silencesResponse{
+		Links: map[string]string{
+			"self": silencesPath,
+		},
+		Silences: make([]*silenceResponse, 0, len(ss)),
+	}
+	for _, sl := range ss {
+		res.Silences = append(res.Silences, newSilenceResponse(sl))
+	}
+	return res
+}
+
+func decodeSilence(r *http.Request) (*influxdb.Silence, error) {
+	sl := &influxdb.Silence{}
+	if err := json.NewDecoder(r.Body).Decode(sl); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode silence request",
+			Err:  err,
+		}
+	}
+	return sl, nil
+}
+
+// handlePostSilence is the HTTP handler for the POST /api/v2/silences route.
+func (h *SilenceHandler) handlePostSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("silence create request", zap.String("r", fmt.Sprint(r)))
+
+	sl, err := decodeSilence(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.SilenceService.CreateSilence(ctx, sl); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("silence created", zap.String("silenceID", sl.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newSilenceResponse(sl)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetSilences is the HTTP handler for the GET /api/v2/silences route.
+func (h *SilenceHandler) handleGetSilences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("silences retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	filter, opts, err := h.decodeGetSilencesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ss, _, err := h.SilenceService.FindSilences(ctx, filter, opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("silences retrieved", zap.Int("silences", len(ss)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilencesResponse(ss)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *SilenceHandler) decodeGetSilencesRequest(ctx context.Context, r *http.Request) (influxdb.SilenceFilter, influxdb.FindOptions, error) {
+	qp := r.URL.Query()
+	var filter influxdb.SilenceFilter
+
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return filter, influxdb.FindOptions{}, err
+	}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	if checkID := qp.Get("checkID"); checkID != "" {
+		id, err := influxdb.IDFromString(checkID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.CheckID = id
+	}
+
+	if ruleID := qp.Get("ruleID"); ruleID != "" {
+		id, err := influxdb.IDFromString(ruleID)
+		if err != nil {
+			return filter, *opts, err
+		}
+		filter.RuleID = id
+	}
+
+	if state := qp.Get("state"); state != "" {
+		st := influxdb.SilenceState(state)
+		if err := st.Valid(); err != nil {
+			return filter, *opts, err
+		}
+		filter.State = &st
+	}
+
+	return filter, *opts, nil
+}
+
+// handleGetSilence is the HTTP handler for the GET /api/v2/silences/:id route.
+func (h *SilenceHandler) handleGetSilence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("silence retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	sl, err := h.SilenceService.FindSilenceByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("silence retrieved", zap.String("silenceID", sl.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilenceResponse(sl)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostSilenceExpire is the HTTP handler for the POST /api/v2/silences/:id/expire route.
+func (h *SilenceHandler) handlePostSilenceExpire(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("silence expire request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	sl, err := h.SilenceService.ExpireSilence(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("silence expired", zap.String("silenceID", sl.ID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSilenceResponse(sl)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newTestSilenceHandler(ss platform.SilenceService) *SilenceHandler {
+	return NewSilenceHandler(&SilenceBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		SilenceService:      ss,
+		OrganizationService: mock.NewOrganizationService(),
+	})
+}
+
+func TestSilenceHandler_handlePostSilence(t *testing.T) {
+	ss := mock.NewSilenceService()
+	ss.CreateSilenceFn = func(ctx context.Context, sl *platform.Silence) error {
+		if len(sl.TagRules) != 1 || sl.TagRules[0].Value != "web1" {
+			t.Errorf("unexpected tag rules %+v", sl.TagRules)
+		}
+		sl.ID = 2
+		sl.StartsAt = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+		sl.CreatedBy = 5
+		return nil
+	}
+	h := newTestSilenceHandler(ss)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/silences", bytes.NewBufferString(`
+{
+  "orgID": "0000000000000001",
+  "checkID": "0000000000000003",
+  "tagRules": [{"key": "host", "value": "web1", "operator": "equal"}],
+  "endsAt": "2019-07-01T14:00:00Z",
+  "comment": "replacing the disk of web1"
+}`)))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/silences/0000000000000002",
+    "expire": "/api/v2/silences/0000000000000002/expire",
+    "check": "/api/v2/checks/0000000000000003",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "checkID": "0000000000000003",
+  "tagRules": [{"key": "host", "value": "web1", "operator": "equal"}],
+  "startsAt": "2019-07-01T12:00:00Z",
+  "endsAt": "2019-07-01T14:00:00Z",
+  "comment": "replacing the disk of web1",
+  "createdBy": "0000000000000005",
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+}
+
+func TestSilenceHandler_handleGetSilences(t *testing.T) {
+	ss := mock.NewSilenceService()
+	ss.FindSilencesFn = func(ctx context.Context, filter platform.SilenceFilter, opt ...platform.FindOptions) ([]*platform.Silence, int, error) {
+		if filter.OrgID == nil || *filter.OrgID != 1 || filter.State == nil || *filter.State != platform.SilenceActive {
+			t.Errorf("expected the active silences of org 0000000000000001, got %+v", filter)
+		}
+		return nil, 0, nil
+	}
+	h := newTestSilenceHandler(ss)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/silences?orgID=0000000000000001&state=active", nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `{"links": {"self": "/api/v2/silences"}, "silences": []}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/silences?state=forever", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSilenceHandler_handlePostSilenceExpire(t *testing.T) {
+	now := time.Date(2019, 7, 1, 13, 0, 0, 0, time.UTC)
+	ss := mock.NewSilenceService()
+	ss.ExpireSilenceFn = func(ctx context.Context, id platform.ID) (*platform.Silence, error) {
+		if id != 2 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrSilenceNotFound}
+		}
+		return &platform.Silence{ID: 2, OrgID: 1, RuleID: 4, StartsAt: now.Add(-time.Hour), EndsAt: now, Comment: "done"}, nil
+	}
+	h := newTestSilenceHandler(ss)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/silences/0000000000000002/expire", nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/silences/0000000000000002",
+    "expire": "/api/v2/silences/0000000000000002/expire",
+    "rule": "/api/v2/notificationRules/0000000000000004",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "ruleID": "0000000000000004",
+  "startsAt": "2019-07-01T12:00:00Z",
+  "endsAt": "2019-07-01T13:00:00Z",
+  "comment": "done",
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/silences/0000000000000003/expire", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
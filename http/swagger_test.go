@@ -1,8 +1,11 @@
 package http
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"io/ioutil"
+	"regexp"
 	"testing"
 
 	"github.com/getkin/kin-openapi/openapi3"
@@ -21,3 +24,41 @@ func TestValidSwagger(t *testing.T) {
 		t.Errorf("invalid swagger specification: %v", err)
 	}
 }
+
+// TestSwaggerSchemaNamesUnique checks that no two schemas of the specification
+// have the same name, which the loader does not report: the last one silently
+// replaces the others for every reference to the name.
+func TestSwaggerSchemaNamesUnique(t *testing.T) {
+	data, err := ioutil.ReadFile("./swagger.yml")
+	if err != nil {
+		t.Fatalf("unable to read swagger specification: %v", err)
+	}
+
+	section := regexp.MustCompile(`^  (\w+):$`)
+	schema := regexp.MustCompile(`^    (\w+):$`)
+	inSchemas := false
+	lines := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if m := section.FindStringSubmatch(line); m != nil {
+			inSchemas = m[1] == "schemas"
+			continue
+		}
+		if !inSchemas {
+			continue
+		}
+		if m := schema.FindStringSubmatch(line); m != nil {
+			if prev, ok := lines[m[1]]; ok {
+				t.Errorf("schema %s on line %d is already defined on line %d", m[1], n, prev)
+			}
+			lines[m[1]] = n
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) == 0 {
+		t.Error("found no schemas in the swagger specification")
+	}
+}
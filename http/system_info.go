@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const systemInfoPath = "/api/v2/system/info"
+
+// SystemInfoBackend is all services and associated parameters required to construct
+// the SystemInfoHandler.
+type SystemInfoBackend struct {
+	platform.HTTPErrorHandler
+	Logger            *zap.Logger
+	SystemInfoService platform.SystemInfoService
+}
+
+// NewSystemInfoBackend returns a new instance of SystemInfoBackend.
+func NewSystemInfoBackend(b *APIBackend) *SystemInfoBackend {
+	return &SystemInfoBackend{
+		HTTPErrorHandler:  b.HTTPErrorHandler,
+		Logger:            b.Logger.With(zap.String("handler", "system_info")),
+		SystemInfoService: b.SystemInfoService,
+	}
+}
+
+// SystemInfoHandler is the handler for the build and runtime information of the server.
+type SystemInfoHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	SystemInfoService platform.SystemInfoService
+}
+
+// NewSystemInfoHandler returns a new instance of SystemInfoHandler.
+func NewSystemInfoHandler(b *SystemInfoBackend) *SystemInfoHandler {
+	h := &SystemInfoHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		SystemInfoService: b.SystemInfoService,
+	}
+
+	h.HandlerFunc("GET", systemInfoPath, h.handleGetSystemInfo)
+	return h
+}
+
+// handleGetSystemInfo is the HTTP handler for the GET /api/v2/system/info route.
+func (h *SystemInfoHandler) handleGetSystemInfo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	info, err := h.SystemInfoService.SystemInfo(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, info); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// SystemInfoService connects to Influx via HTTP using tokens to read the information about the server.
+type SystemInfoService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.SystemInfoService = (*SystemInfoService)(nil)
+
+// SystemInfo returns the information about the remote server.
+func (s *SystemInfoService) SystemInfo(ctx context.Context) (*platform.SystemInfo, error) {
+	var info platform.SystemInfo
+	if err := s.client().do(ctx, "GET", systemInfoPath, nil, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (s *SystemInfoService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
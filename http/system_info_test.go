@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/inmem"
+	"go.uber.org/zap"
+)
+
+func TestSystemInfoHandler_handleGetSystemInfo(t *testing.T) {
+	want := platform.SystemInfo{
+		Version:   "2.0.0",
+		Commit:    "abc123",
+		BuildDate: "2019-01-01T00:00:00Z",
+		Storage: platform.SystemStorageInfo{
+			Engine:        "tsm1",
+			Index:         "tsi1",
+			LayoutVersion: 1,
+			WALEnabled:    true,
+		},
+		Runtime: platform.SystemRuntimeInfo{
+			GoVersion: runtime.Version(),
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			NumCPU:    runtime.NumCPU(),
+		},
+		Limits: platform.SystemLimits{
+			QueryConcurrency: 10,
+			QueryQueueSize:   10,
+			QueryMemoryBytes: 1024,
+		},
+	}
+
+	h := NewSystemInfoHandler(&SystemInfoBackend{
+		HTTPErrorHandler:  ErrorHandler(0),
+		Logger:            zap.NewNop(),
+		SystemInfoService: inmem.NewSystemInfoService(want),
+	})
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/system/info", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	res := w.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: got %d, want %d", res.StatusCode, http.StatusOK)
+	}
+	var got platform.SystemInfo
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Runtime.StartedAt.IsZero() || got.Runtime.Uptime == "" || got.Runtime.Goroutines == 0 {
+		t.Errorf("expected the current runtime, got %+v", got.Runtime)
+	}
+	ignore := cmpopts.IgnoreFields(platform.SystemRuntimeInfo{}, "GOMAXPROCS", "Goroutines", "StartedAt", "Uptime")
+	if diff := cmp.Diff(want, got, ignore); diff != "" {
+		t.Errorf("unexpected system info -want/+got:\n%s", diff)
+	}
+}
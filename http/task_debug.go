@@ -0,0 +1,165 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"path"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+type debugRunRequest struct {
+	ScheduledFor  string `json:"scheduledFor,omitempty"`
+	ScratchBucket string `json:"scratchBucket,omitempty"`
+	Timeout       string `json:"timeout,omitempty"`
+	SampleRows    int    `json:"sampleRows,omitempty"`
+}
+
+type debugRunResponse struct {
+	Links map[string]string `json:"links"`
+	platform.TaskDebugRun
+}
+
+func newDebugRunResponse(run *platform.TaskDebugRun) *debugRunResponse {
+	return &debugRunResponse{
+		Links: map[string]string{
+			"self": taskIDDebugPath(run.TaskID),
+			"task": taskIDPath(run.TaskID),
+		},
+		TaskDebugRun: *run,
+	}
+}
+
+// handlePostDebugRun is the HTTP handler for the POST /api/v2/tasks/:id/debug route.
+func (h *TaskHandler) handlePostDebugRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, req, err := decodePostDebugRunRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	run, err := h.TaskDebugService.DebugRun(ctx, id, req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newDebugRunResponse(run)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func decodePostDebugRunRequest(ctx context.Context, r *http.Request) (platform.ID, platform.TaskDebugRequest, error) {
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		return 0, platform.TaskDebugRequest{}, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid task ID",
+			Err:  err,
+		}
+	}
+
+	var body debugRunRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return 0, platform.TaskDebugRequest{}, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "failed to decode request",
+				Err:  err,
+			}
+		}
+	}
+
+	req := platform.TaskDebugRequest{
+		ScratchBucket: body.ScratchBucket,
+		SampleRows:    body.SampleRows,
+	}
+	if body.ScheduledFor != "" {
+		t, err := time.Parse(time.RFC3339, body.ScheduledFor)
+		if err != nil {
+			return 0, platform.TaskDebugRequest{}, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "scheduledFor must be an RFC3339 time",
+				Err:  err,
+			}
+		}
+		req.ScheduledFor = t
+	}
+	if body.Timeout != "" {
+		d, err := time.ParseDuration(body.Timeout)
+		if err != nil {
+			return 0, platform.TaskDebugRequest{}, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "timeout must be a duration such as 30s",
+				Err:  err,
+			}
+		}
+		req.Timeout = d
+	}
+	if err := req.Validate(); err != nil {
+		return 0, platform.TaskDebugRequest{}, err
+	}
+	return id, req, nil
+}
+
+// DebugRun runs the task once with its authorization without writing its results,
+// and returns samples of its tables.
+func (t TaskService) DebugRun(ctx context.Context, taskID platform.ID, req platform.TaskDebugRequest) (*platform.TaskDebugRun, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, taskIDDebugPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	body := debugRunRequest{
+		ScratchBucket: req.ScratchBucket,
+		SampleRows:    req.SampleRows,
+	}
+	if !req.ScheduledFor.IsZero() {
+		body.ScheduledFor = req.ScheduledFor.UTC().Format(time.RFC3339)
+	}
+	if req.Timeout > 0 {
+		body.Timeout = req.Timeout.String()
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	hreq, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, hreq)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var res debugRunResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return &res.TaskDebugRun, nil
+}
+
+func taskIDDebugPath(id platform.ID) string {
+	return path.Join(tasksPath, id.String(), "debug")
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestTaskHandler_handlePostDebugRun(t *testing.T) {
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	run := &platform.TaskDebugRun{
+		TaskID:       1,
+		ScheduledFor: now,
+		StartedAt:    now.Add(time.Second),
+		FinishedAt:   now.Add(2 * time.Second),
+		Script:       `from(bucket: "a") |> range(start: -1h)`,
+		TraceID:      "7f3a",
+		Samples: []*platform.TaskDebugSample{{
+			Name: "_result",
+			Tables: []*platform.TaskDebugTable{{
+				Group:     map[string]interface{}{"host": "a"},
+				Columns:   []string{"host", "_value"},
+				Rows:      [][]interface{}{{"a", 1.5}},
+				Truncated: true,
+			}},
+		}},
+	}
+
+	var got platform.TaskDebugRequest
+	b := NewMockTaskBackend(t)
+	b.HTTPErrorHandler = ErrorHandler(0)
+	ds := mock.NewTaskDebugService()
+	ds.DebugRunFn = func(_ context.Context, id platform.ID, req platform.TaskDebugRequest) (*platform.TaskDebugRun, error) {
+		if id != run.TaskID {
+			t.Errorf("got task ID %s, want %s", id, run.TaskID)
+		}
+		got = req
+		return run, nil
+	}
+	b.TaskDebugService = ds
+	h := NewTaskHandler(b)
+
+	t.Run("runs the task", func(t *testing.T) {
+		server := httptest.NewServer(h)
+		defer server.Close()
+
+		req := platform.TaskDebugRequest{
+			ScheduledFor:  now,
+			ScratchBucket: "scratch",
+			Timeout:       30 * time.Second,
+			SampleRows:    5,
+		}
+		client := TaskService{Addr: server.URL}
+		res, err := client.DebugRun(context.Background(), run.TaskID, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, req) {
+			t.Errorf("got request %+v, want %+v", got, req)
+		}
+		if !reflect.DeepEqual(res, run) {
+			t.Errorf("got %+v, want %+v", res, run)
+		}
+	})
+
+	t.Run("defaults the limits", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/api/v2/tasks/0000000000000001/debug", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+		}
+		want := platform.TaskDebugRequest{
+			Timeout:    platform.DefaultTaskDebugTimeout,
+			SampleRows: platform.DefaultTaskDebugSampleRows,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got request %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("rejects a timeout that is too long", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/api/v2/tasks/0000000000000001/debug", strings.NewReader(`{"timeout": "1h"}`))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
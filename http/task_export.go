@@ -0,0 +1,468 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/authorizer"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"go.uber.org/zap"
+)
+
+// handleGetTaskExport is the HTTP handler for the GET /api/v2/tasks/:id/export route.
+// Recent runs, including their logs, are exported when the runs parameter is set.
+func (h *TaskHandler) handleGetTaskExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetTaskExportRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	task, err := h.TaskService.FindTaskByID(ctx, req.TaskID)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.ENotFound,
+			Msg:  "failed to find task",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	export, err := h.exportTask(ctx, task, req.Runs)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	h.logger.Debug("task exported", zap.String("taskID", task.ID.String()), zap.Int("runs", len(export.Runs)))
+	if err := encodeResponse(ctx, w, http.StatusOK, export); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+// exportTask returns the export of the task with its labels, owners and up to runs recent runs.
+func (h *TaskHandler) exportTask(ctx context.Context, task *platform.Task, runs int) (*platform.TaskExport, error) {
+	export := platform.NewTaskExport(task, time.Now())
+
+	labels, err := h.LabelService.FindResourceLabels(ctx, platform.LabelMappingFilter{
+		ResourceID:   task.ID,
+		ResourceType: platform.TasksResourceType,
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Msg: "failed to find resource labels",
+		}
+	}
+	for _, l := range labels {
+		export.Labels = append(export.Labels, platform.TaskExportLabel{
+			Name:       l.Name,
+			Properties: l.Properties,
+		})
+	}
+
+	owners, _, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		ResourceID:   task.ID,
+		ResourceType: platform.TasksResourceType,
+		UserType:     platform.Owner,
+	})
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Msg: "failed to find task owners",
+		}
+	}
+	for _, m := range owners {
+		u, err := h.UserService.FindUserByID(ctx, m.UserID)
+		if err != nil {
+			// The mapping may outlive its user; there is nothing to match it against on import.
+			h.logger.Info("Skipping task owner without user", zap.String("taskID", task.ID.String()), zap.String("userID", m.UserID.String()))
+			continue
+		}
+		export.Owners = append(export.Owners, platform.TaskExportOwner{
+			ID:   u.ID,
+			Name: u.Name,
+		})
+	}
+
+	if runs > 0 {
+		rs, _, err := h.TaskService.FindRuns(ctx, platform.RunFilter{
+			Task:  task.ID,
+			Limit: runs,
+		})
+		if err != nil {
+			return nil, &platform.Error{
+				Err: err,
+				Msg: "failed to find task runs",
+			}
+		}
+		export.Runs = rs
+	}
+
+	return export, nil
+}
+
+type getTaskExportRequest struct {
+	TaskID platform.ID
+	Runs   int
+}
+
+func decodeGetTaskExportRequest(ctx context.Context, r *http.Request) (*getTaskExportRequest, error) {
+	tr, err := decodeGetTaskRequest(ctx, r)
+	if err != nil {
+		return nil, &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+		}
+	}
+
+	req := &getTaskExportRequest{TaskID: tr.TaskID}
+	if runs := r.URL.Query().Get("runs"); runs != "" {
+		n, err := strconv.Atoi(runs)
+		if err != nil || n < 0 {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "runs must be a non-negative integer",
+			}
+		}
+		if n > platform.TaskExportMaxRuns {
+			n = platform.TaskExportMaxRuns
+		}
+		req.Runs = n
+	}
+
+	return req, nil
+}
+
+type taskImportResult struct {
+	SourceTaskID platform.ID  `json:"sourceTaskID,omitempty"`
+	Task         taskResponse `json:"task"`
+	RunsImported int          `json:"runsImported"`
+	// UnmatchedOwners lists the exported owners without a user of the same name on this instance.
+	UnmatchedOwners []string `json:"unmatchedOwners"`
+}
+
+type taskImportResponse struct {
+	Tasks []taskImportResult `json:"tasks"`
+}
+
+// handlePostTasksImport is the HTTP handler for the POST /api/v2/tasks:import route.
+// It creates one task per export, or none if any of them fails to import.
+func (h *TaskHandler) handlePostTasksImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EUnauthorized,
+			Msg:  "failed to get authorizer",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodePostTasksImportRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	org := platform.TaskCreate{
+		OrganizationID: req.OrganizationID,
+		Organization:   req.Organization,
+	}
+	if err := h.populateTaskCreateOrg(ctx, &org); err != nil {
+		err = &platform.Error{
+			Err: err,
+			Msg: "could not identify organization",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tcs := make([]platform.TaskCreate, 0, len(req.Tasks))
+	for i := range req.Tasks {
+		tc := req.Tasks[i].TaskCreate(org.OrganizationID, org.Organization, req.Token, req.Status)
+		if err := tc.Validate(); err != nil {
+			err = &platform.Error{
+				Err:  err,
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("task at index %d is not a valid task", i),
+			}
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		tcs = append(tcs, tc)
+	}
+
+	res := taskImportResponse{
+		Tasks: make([]taskImportResult, 0, len(tcs)),
+	}
+	created := make([]*platform.Task, 0, len(tcs))
+	for i, tc := range tcs {
+		result, err := h.importTask(ctx, auth, tc, &req.Tasks[i], req.SkipRuns, &created)
+		if err != nil {
+			// Don't leave a partial set of tasks behind.
+			h.deleteCreatedTasks(ctx, created)
+			h.HandleHTTPError(ctx, &platform.Error{
+				Err: err,
+				Msg: fmt.Sprintf("failed to import task at index %d: %s", i, platform.ErrorMessage(err)),
+			}, w)
+			return
+		}
+		res.Tasks = append(res.Tasks, *result)
+	}
+
+	h.logger.Debug("tasks imported", zap.String("orgID", org.OrganizationID.String()), zap.Int("count", len(created)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, res); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+// importTask creates the task of the export, then restores its labels, owners and runs.
+// The task is appended to created as soon as it exists, so that it can be rolled back.
+func (h *TaskHandler) importTask(ctx context.Context, auth platform.Authorizer, tc platform.TaskCreate, export *platform.TaskExport, skipRuns bool, created *[]*platform.Task) (*taskImportResult, error) {
+	task, err := h.createTask(ctx, auth, tc)
+	if err != nil {
+		return nil, err
+	}
+	*created = append(*created, task)
+
+	labels, err := h.importTaskLabels(ctx, task, export.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	unmatched, err := h.importTaskOwners(ctx, task, export.Owners)
+	if err != nil {
+		return nil, err
+	}
+
+	var imported int
+	if !skipRuns && len(export.Runs) > 0 && h.TaskRunImporter != nil {
+		imported, err = h.TaskRunImporter.ImportRuns(ctx, task.ID, export.Runs)
+		if err != nil {
+			return nil, &platform.Error{
+				Err: err,
+				Msg: "failed to import task runs",
+			}
+		}
+	}
+
+	return &taskImportResult{
+		SourceTaskID:    export.SourceTaskID,
+		Task:            newTaskResponse(*task, labels),
+		RunsImported:    imported,
+		UnmatchedOwners: unmatched,
+	}, nil
+}
+
+// importTaskLabels maps the labels to the task, creating the ones missing from its organization.
+func (h *TaskHandler) importTaskLabels(ctx context.Context, task *platform.Task, labels []platform.TaskExportLabel) ([]*platform.Label, error) {
+	ls := authorizer.NewLabelService(h.LabelService)
+	mapped := make([]*platform.Label, 0, len(labels))
+	for _, el := range labels {
+		found, err := ls.FindLabels(ctx, platform.LabelFilter{
+			Name:  el.Name,
+			OrgID: &task.OrganizationID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var l *platform.Label
+		if len(found) > 0 {
+			l = found[0]
+		} else {
+			l = &platform.Label{
+				OrgID:      task.OrganizationID,
+				Name:       el.Name,
+				Properties: el.Properties,
+			}
+			if err := ls.CreateLabel(ctx, l); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := ls.CreateLabelMapping(ctx, &platform.LabelMapping{
+			LabelID:      l.ID,
+			ResourceID:   task.ID,
+			ResourceType: platform.TasksResourceType,
+		}); err != nil {
+			return nil, err
+		}
+		mapped = append(mapped, l)
+	}
+	return mapped, nil
+}
+
+// importTaskOwners makes the users with the names of the exported owners owners of the task.
+// The caller already owns the task it created. It returns the names without a matching user.
+func (h *TaskHandler) importTaskOwners(ctx context.Context, task *platform.Task, owners []platform.TaskExportOwner) ([]string, error) {
+	existing, _, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		ResourceID:   task.ID,
+		ResourceType: platform.TasksResourceType,
+		UserType:     platform.Owner,
+	})
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[platform.ID]bool, len(existing))
+	for _, m := range existing {
+		owned[m.UserID] = true
+	}
+
+	unmatched := []string{}
+	for _, o := range owners {
+		name := o.Name
+		u, err := h.UserService.FindUser(ctx, platform.UserFilter{Name: &name})
+		if platform.ErrorCode(err) == platform.ENotFound {
+			unmatched = append(unmatched, o.Name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if owned[u.ID] {
+			continue
+		}
+
+		if err := h.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+			UserID:       u.ID,
+			UserType:     platform.Owner,
+			MappingType:  platform.UserMappingType,
+			ResourceType: platform.TasksResourceType,
+			ResourceID:   task.ID,
+		}); err != nil {
+			return nil, err
+		}
+		owned[u.ID] = true
+	}
+	return unmatched, nil
+}
+
+// deleteCreatedTasks removes the tasks created by a request that failed part way.
+func (h *TaskHandler) deleteCreatedTasks(ctx context.Context, tasks []*platform.Task) {
+	for _, t := range tasks {
+		if err := h.TaskService.DeleteTask(ctx, t.ID); err != nil {
+			h.logger.Warn("Failed to delete task after failed request", zap.String("taskID", t.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+func decodePostTasksImportRequest(ctx context.Context, r *http.Request) (*platform.TaskImport, error) {
+	var req platform.TaskImport
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+// ExportTask returns the export of a task, including up to runs of its recent runs.
+func (t TaskService) ExportTask(ctx context.Context, id platform.ID, runs int) (*platform.TaskExport, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, path.Join(taskIDPath(id), "export"))
+	if err != nil {
+		return nil, err
+	}
+
+	if runs > 0 {
+		val := url.Values{}
+		val.Set("runs", strconv.Itoa(runs))
+		u.RawQuery = val.Encode()
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var export platform.TaskExport
+	if err := json.NewDecoder(resp.Body).Decode(&export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ImportTasks creates the exported tasks in an organization and returns the created tasks.
+func (t TaskService) ImportTasks(ctx context.Context, ti platform.TaskImport) ([]*platform.Task, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, tasksImportPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBytes, err := json.Marshal(ti)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var tr taskImportResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*platform.Task, 0, len(tr.Tasks))
+	for i := range tr.Tasks {
+		tasks = append(tasks, &tr.Tasks[i].Task.Task)
+	}
+	return tasks, nil
+}
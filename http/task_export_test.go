@@ -0,0 +1,331 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+)
+
+type taskRunImporterFn func(context.Context, platform.ID, []*platform.Run) (int, error)
+
+func (fn taskRunImporterFn) ImportRuns(ctx context.Context, taskID platform.ID, runs []*platform.Run) (int, error) {
+	return fn(ctx, taskID, runs)
+}
+
+func TestTaskHandler_handleGetTaskExport(t *testing.T) {
+	task := &platform.Task{
+		ID:             1,
+		OrganizationID: 2,
+		Organization:   "test",
+		Name:           "downsample",
+		Status:         platform.TaskStatusActive,
+		Every:          "1h",
+		Flux:           `option task = {name: "downsample", every: 1h} from(bucket: "b") |> range(start: -1h)`,
+	}
+
+	b := NewMockTaskBackend(t)
+	b.HTTPErrorHandler = ErrorHandler(0)
+	b.TaskService = &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id platform.ID) (*platform.Task, error) {
+			if id != task.ID {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: "task not found"}
+			}
+			return task, nil
+		},
+		FindRunsFn: func(ctx context.Context, f platform.RunFilter) ([]*platform.Run, int, error) {
+			if f.Task != task.ID || f.Limit != platform.TaskExportMaxRuns {
+				t.Errorf("unexpected run filter %+v", f)
+			}
+			runs := []*platform.Run{{ID: 3, TaskID: task.ID, Status: "success", FinishedAt: "2019-01-01T00:00:00Z"}}
+			return runs, len(runs), nil
+		},
+	}
+	ls := mock.NewLabelService()
+	ls.FindResourceLabelsFn = func(ctx context.Context, f platform.LabelMappingFilter) ([]*platform.Label, error) {
+		return []*platform.Label{{ID: 4, OrgID: 2, Name: "prod", Properties: map[string]string{"color": "red"}}}, nil
+	}
+	b.LabelService = ls
+	us := mock.NewUserService()
+	us.FindUserByIDFn = func(ctx context.Context, id platform.ID) (*platform.User, error) {
+		return &platform.User{ID: id, Name: "alice"}, nil
+	}
+	b.UserService = us
+	urm := inmem.NewService()
+	if err := urm.CreateUserResourceMapping(context.Background(), &platform.UserResourceMapping{
+		UserID:       5,
+		UserType:     platform.Owner,
+		ResourceType: platform.TasksResourceType,
+		ResourceID:   task.ID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	b.UserResourceMappingService = urm
+	h := NewTaskHandler(b)
+
+	r := httptest.NewRequest("GET", "/api/v2/tasks/0000000000000001/export?runs=1000", nil)
+	r = r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "id", Value: "0000000000000001"}}))
+	w := httptest.NewRecorder()
+	h.handleGetTaskExport(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var export platform.TaskExport
+	if err := json.NewDecoder(w.Body).Decode(&export); err != nil {
+		t.Fatal(err)
+	}
+	if err := export.Validate(); err != nil {
+		t.Fatalf("export does not validate: %v", err)
+	}
+	if export.SourceTaskID != task.ID || export.Flux != task.Flux || export.Every != task.Every {
+		t.Errorf("unexpected export %+v", export)
+	}
+	if len(export.Labels) != 1 || export.Labels[0].Name != "prod" || export.Labels[0].Properties["color"] != "red" {
+		t.Errorf("unexpected labels %+v", export.Labels)
+	}
+	if len(export.Owners) != 1 || export.Owners[0].Name != "alice" {
+		t.Errorf("unexpected owners %+v", export.Owners)
+	}
+	if len(export.Runs) != 1 || export.Runs[0].ID != 3 {
+		t.Errorf("unexpected runs %+v", export.Runs)
+	}
+}
+
+func TestTaskHandler_handlePostTasksImport(t *testing.T) {
+	const flux = `option task = {name: "a", every: 1h}` + "\n" + `from(bucket: "b") |> range(start: -1h)`
+
+	type backend struct {
+		h       *TaskHandler
+		labels  []*platform.LabelMapping
+		created []*platform.Label
+		deleted []platform.ID
+		runs    int
+	}
+
+	newBackend := func(createTask func(platform.TaskCreate) (*platform.Task, error)) *backend {
+		be := &backend{}
+
+		b := NewMockTaskBackend(t)
+		b.HTTPErrorHandler = ErrorHandler(0)
+		b.TaskService = &mock.TaskService{
+			CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+				return createTask(tc)
+			},
+			DeleteTaskFn: func(ctx context.Context, id platform.ID) error {
+				be.deleted = append(be.deleted, id)
+				return nil
+			},
+		}
+
+		ls := mock.NewLabelService()
+		ls.FindLabelsFn = func(ctx context.Context, f platform.LabelFilter) ([]*platform.Label, error) {
+			if f.Name == "prod" {
+				return []*platform.Label{{ID: 20, OrgID: *f.OrgID, Name: "prod"}}, nil
+			}
+			return []*platform.Label{}, nil
+		}
+		ls.FindLabelByIDFn = func(ctx context.Context, id platform.ID) (*platform.Label, error) {
+			return &platform.Label{ID: id, OrgID: 2}, nil
+		}
+		ls.CreateLabelFn = func(ctx context.Context, l *platform.Label) error {
+			l.ID = 21
+			be.created = append(be.created, l)
+			return nil
+		}
+		ls.CreateLabelMappingFn = func(ctx context.Context, m *platform.LabelMapping) error {
+			be.labels = append(be.labels, m)
+			return nil
+		}
+		b.LabelService = ls
+
+		us := mock.NewUserService()
+		us.FindUserFn = func(ctx context.Context, f platform.UserFilter) (*platform.User, error) {
+			if *f.Name == "alice" {
+				return &platform.User{ID: 30, Name: "alice"}, nil
+			}
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "user not found"}
+		}
+		b.UserService = us
+
+		b.TaskRunImporter = taskRunImporterFn(func(ctx context.Context, id platform.ID, runs []*platform.Run) (int, error) {
+			be.runs += len(runs)
+			return len(runs), nil
+		})
+
+		be.h = NewTaskHandler(b)
+		return be
+	}
+
+	serve := func(h *TaskHandler, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/api/v2/tasks:import", bytes.NewBufferString(body))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	imp := platform.TaskImport{
+		OrganizationID: 2,
+		Status:         platform.TaskStatusInactive,
+		Tasks: []platform.TaskExport{
+			{
+				Version:      platform.TaskExportVersion,
+				SourceTaskID: 100,
+				Flux:         flux,
+				Status:       platform.TaskStatusActive,
+				Labels:       []platform.TaskExportLabel{{Name: "prod"}, {Name: "new"}},
+				Owners:       []platform.TaskExportOwner{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}},
+				Runs:         []*platform.Run{{ID: 3, TaskID: 100, Status: "success", FinishedAt: "2019-01-01T00:00:00Z"}},
+			},
+			{
+				Version: platform.TaskExportVersion,
+				Flux:    strings.Replace(flux, `"a"`, `"fail"`, 1),
+			},
+		},
+	}
+
+	t.Run("imports tasks with labels, owners and runs", func(t *testing.T) {
+		var created []platform.TaskCreate
+		be := newBackend(func(tc platform.TaskCreate) (*platform.Task, error) {
+			created = append(created, tc)
+			return &platform.Task{ID: platform.ID(len(created) + 10), OrganizationID: tc.OrganizationID, AuthorizationID: 40, Flux: tc.Flux, Status: tc.Status}, nil
+		})
+
+		body, err := json.Marshal(imp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := serve(be.h, string(body))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+		}
+
+		var res taskImportResponse
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Tasks) != 2 || len(created) != 2 {
+			t.Fatalf("expected 2 tasks, got %d in response and %d created", len(res.Tasks), len(created))
+		}
+		for _, tc := range created {
+			if tc.Status != platform.TaskStatusInactive || tc.Organization != "test" {
+				t.Errorf("unexpected status %q or organization %q", tc.Status, tc.Organization)
+			}
+		}
+
+		first := res.Tasks[0]
+		if first.SourceTaskID != 100 || first.RunsImported != 1 || be.runs != 1 {
+			t.Errorf("unexpected result %+v with %d runs imported", first, be.runs)
+		}
+		if len(first.UnmatchedOwners) != 1 || first.UnmatchedOwners[0] != "bob" {
+			t.Errorf("unexpected unmatched owners %v", first.UnmatchedOwners)
+		}
+		if len(be.created) != 1 || be.created[0].Name != "new" {
+			t.Errorf("expected the missing label to be created, got %+v", be.created)
+		}
+		if len(be.labels) != 2 || be.labels[0].LabelID != 20 || be.labels[1].LabelID != 21 {
+			t.Errorf("unexpected label mappings %+v", be.labels)
+		}
+
+		owners, _, err := be.h.UserResourceMappingService.FindUserResourceMappings(context.Background(), platform.UserResourceMappingFilter{
+			ResourceID: 11,
+			UserType:   platform.Owner,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(owners) != 1 || owners[0].UserID != 30 {
+			t.Errorf("unexpected owners %+v", owners)
+		}
+	})
+
+	t.Run("failed import removes imported tasks", func(t *testing.T) {
+		be := newBackend(func(tc platform.TaskCreate) (*platform.Task, error) {
+			if strings.Contains(tc.Flux, "fail") {
+				return nil, errors.New("boom")
+			}
+			return &platform.Task{ID: 10, OrganizationID: tc.OrganizationID, AuthorizationID: 40, Flux: tc.Flux}, nil
+		})
+
+		body, err := json.Marshal(imp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := serve(be.h, string(body))
+		if w.Code == http.StatusCreated {
+			t.Fatalf("expected import to fail: %s", w.Body.String())
+		}
+		if len(be.deleted) != 1 || be.deleted[0] != 10 {
+			t.Errorf("expected imported task to be deleted, got %v", be.deleted)
+		}
+	})
+
+	t.Run("client imports the export of another instance", func(t *testing.T) {
+		be := newBackend(func(tc platform.TaskCreate) (*platform.Task, error) {
+			return &platform.Task{ID: 10, OrganizationID: tc.OrganizationID, AuthorizationID: 40, Flux: tc.Flux, Status: tc.Status}, nil
+		})
+		var importedTo platform.ID
+		be.h.TaskRunImporter = taskRunImporterFn(func(ctx context.Context, id platform.ID, runs []*platform.Run) (int, error) {
+			importedTo = id
+			return len(runs), nil
+		})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+			be.h.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+
+		// An export as written by influx task export on another instance,
+		// whose runs belong to a task ID this instance doesn't know.
+		exported := `{
+			"version": 1,
+			"sourceTaskID": "00000000000000ff",
+			"flux": ` + strconv.Quote(flux) + `,
+			"status": "active",
+			"runs": [{"id": "0000000000000003", "taskID": "00000000000000ff", "status": "success", "finishedAt": "2019-01-01T00:00:00Z"}]
+		}`
+		var export platform.TaskExport
+		if err := json.Unmarshal([]byte(exported), &export); err != nil {
+			t.Fatal(err)
+		}
+
+		s := TaskService{Addr: server.URL}
+		tasks, err := s.ImportTasks(context.Background(), platform.TaskImport{
+			OrganizationID: 2,
+			Tasks:          []platform.TaskExport{export},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tasks) != 1 || tasks[0].ID != 10 {
+			t.Fatalf("unexpected tasks %+v", tasks)
+		}
+		if importedTo != 10 {
+			t.Errorf("expected runs to be imported into task 10, got %s", importedTo)
+		}
+	})
+
+	t.Run("unsupported version imports nothing", func(t *testing.T) {
+		be := newBackend(func(tc platform.TaskCreate) (*platform.Task, error) {
+			t.Fatal("no task should be created")
+			return nil, nil
+		})
+
+		w := serve(be.h, `{"orgID":"0000000000000002","tasks":[{"version":99,"flux":"x"}]}`)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+)
+
+// runLogsStreamKeepAlive is how often a comment is sent on an idle log stream,
+// so proxies don't close the connection while a run is quiet.
+const runLogsStreamKeepAlive = 15 * time.Second
+
+// handleGetRunLogsStream is the HTTP handler for the GET /api/v2/tasks/:id/runs/:rid/logs/stream route.
+// It streams the logs of a run as server-sent events: the lines logged so far, then every new line
+// until the run finishes. Each line is a "log" event; an "end" event is sent once the run has finished.
+func (h *TaskHandler) handleGetRunLogsStream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeGetRunRequest(ctx, r)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if h.RunLogStreamer == nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EUnavailable,
+			Msg:  "streaming run logs is not available",
+		}, w)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  "streaming is not supported by the response writer",
+		}, w)
+		return
+	}
+
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EUnauthorized,
+			Msg:  "failed to get authorizer",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if k := auth.Kind(); k != platform.AuthorizationKind {
+		// Get the authorization for the task, if allowed.
+		authz, err := h.getAuthorizationForTask(ctx, req.TaskID)
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+
+		// We were able to access the authorizer for the task, so reassign that on the context for the rest of this call.
+		ctx = pcontext.SetAuthorizer(ctx, authz)
+	}
+
+	// Finding the run checks that the caller may read the task.
+	if _, err := h.TaskService.FindRunByID(ctx, req.TaskID, req.RunID); err != nil {
+		err := &platform.Error{
+			Err: err,
+			Msg: "failed to find run",
+		}
+		if err.Err == platform.ErrTaskNotFound || err.Err == platform.ErrRunNotFound {
+			err.Code = platform.ENotFound
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// Subscribe before reading the lines logged so far, so no line is missed in between.
+	// A run that is not in progress has all its lines logged already.
+	stream, err := h.RunLogStreamer.StreamRunLogs(ctx, req.TaskID, req.RunID)
+	if err != nil && err != platform.ErrRunNotInProgress {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	logs, _, err := h.TaskService.FindLogs(ctx, platform.LogFilter{Task: req.TaskID, Run: &req.RunID})
+	if err != nil && err != platform.ErrNoRunsFound {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Err: err,
+			Msg: "failed to find task logs",
+		}, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	// The lines logged so far may be streamed as well, so they are only sent once.
+	sent := make(map[platform.Log]bool, len(logs))
+	for _, l := range logs {
+		sent[*l] = true
+		if err := writeRunLogEvent(w, "log", l); err != nil {
+			logEncodingError(h.logger, r, err)
+			return
+		}
+	}
+	flusher.Flush()
+
+	if stream != nil {
+		keepAlive := time.NewTicker(runLogsStreamKeepAlive)
+		defer keepAlive.Stop()
+
+	Stream:
+		for {
+			select {
+			case l, ok := <-stream:
+				if !ok {
+					break Stream
+				}
+				if sent[*l] {
+					continue
+				}
+				if err := writeRunLogEvent(w, "log", l); err != nil {
+					logEncodingError(h.logger, r, err)
+					return
+				}
+			case <-keepAlive.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			flusher.Flush()
+		}
+	}
+
+	if err := writeRunLogEvent(w, "end", struct{}{}); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+	flusher.Flush()
+}
+
+// writeRunLogEvent writes v as the JSON data of a server-sent event.
+func writeRunLogEvent(w http.ResponseWriter, event string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+	return err
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestTaskHandler_handleGetRunLogsStream(t *testing.T) {
+	const taskID, runID platform.ID = 1, 2
+
+	history := []*platform.Log{
+		{RunID: runID, Time: "2019-07-01T00:00:00Z", Message: "Started task from script"},
+	}
+
+	tests := []struct {
+		name     string
+		stream   func() (<-chan *platform.Log, error)
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "streams the logs of an in-progress run",
+			stream: func() (<-chan *platform.Log, error) {
+				ch := make(chan *platform.Log, 2)
+				// The first line was logged so far as well, so it is not sent twice.
+				ch <- history[0]
+				ch <- &platform.Log{RunID: runID, Time: "2019-07-01T00:00:01Z", Message: "Completed(success)"}
+				close(ch)
+				return ch, nil
+			},
+			wantCode: http.StatusOK,
+			wantBody: `event: log
+data: {"runID":"0000000000000002","time":"2019-07-01T00:00:00Z","message":"Started task from script"}
+
+event: log
+data: {"runID":"0000000000000002","time":"2019-07-01T00:00:01Z","message":"Completed(success)"}
+
+event: end
+data: {}
+
+`,
+		},
+		{
+			name: "ends after the logs of a finished run",
+			stream: func() (<-chan *platform.Log, error) {
+				return nil, platform.ErrRunNotInProgress
+			},
+			wantCode: http.StatusOK,
+			wantBody: `event: log
+data: {"runID":"0000000000000002","time":"2019-07-01T00:00:00Z","message":"Started task from script"}
+
+event: end
+data: {}
+
+`,
+		},
+		{
+			name: "fails if the logs can't be streamed",
+			stream: func() (<-chan *platform.Log, error) {
+				return nil, &platform.Error{Code: platform.EInternal, Msg: "oops"}
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := &mock.TaskService{
+				FindRunByIDFn: func(_ context.Context, tid, rid platform.ID) (*platform.Run, error) {
+					return &platform.Run{ID: rid, TaskID: tid}, nil
+				},
+				FindLogsFn: func(_ context.Context, f platform.LogFilter) ([]*platform.Log, int, error) {
+					if f.Task != taskID || f.Run == nil || *f.Run != runID {
+						t.Errorf("unexpected log filter %+v", f)
+					}
+					return history, len(history), nil
+				},
+			}
+			ls := mock.NewRunLogStreamer()
+			ls.StreamRunLogsFn = func(_ context.Context, tid, rid platform.ID) (<-chan *platform.Log, error) {
+				if tid != taskID || rid != runID {
+					t.Errorf("unexpected run %s of task %s", rid, tid)
+				}
+				return tt.stream()
+			}
+
+			b := NewMockTaskBackend(t)
+			b.HTTPErrorHandler = ErrorHandler(0)
+			b.TaskService = ts
+			b.RunLogStreamer = ls
+			h := NewTaskHandler(b)
+
+			r := httptest.NewRequest("GET", "http://any.url", nil)
+			r = r.WithContext(context.WithValue(
+				context.Background(),
+				httprouter.ParamsKey,
+				httprouter.Params{
+					{Key: "id", Value: taskID.String()},
+					{Key: "rid", Value: runID.String()},
+				}))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Permissions: platform.OperPermissions()}))
+			w := httptest.NewRecorder()
+			h.handleGetRunLogsStream(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+				t.Errorf("got content type %q, want text/event-stream", ct)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("got body\n%s\nwant\n%s", got, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+const taskOptionsParsePath = "/api/v2/tasks/options/parse"
+
+// TaskOptionsHandler parses the task options of Flux scripts, so that clients
+// can check them without a parser of their own. It is separate from the
+// TaskHandler, whose routes with task IDs its path would conflict with.
+type TaskOptionsHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+}
+
+// NewTaskOptionsHandler returns a new instance of TaskOptionsHandler.
+func NewTaskOptionsHandler(b *APIBackend) *TaskOptionsHandler {
+	h := &TaskOptionsHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "task_options")),
+	}
+
+	h.HandlerFunc("POST", taskOptionsParsePath, h.handlePostParse)
+	return h
+}
+
+type taskOptionsParseRequest struct {
+	Flux string `json:"flux"`
+}
+
+// taskOptionsParseResponse holds the task options of a script if they are
+// valid, and the errors that keep them from being valid otherwise.
+type taskOptionsParseResponse struct {
+	Valid   bool             `json:"valid"`
+	Options *options.Options `json:"options,omitempty"`
+	// EffectiveCron is the cron schedule of the task, which is derived from
+	// every if the script has no cron option.
+	EffectiveCron string          `json:"effectiveCron,omitempty"`
+	Errors        []options.Error `json:"errors"`
+}
+
+func newTaskOptionsParseResponse(opts options.Options, errs []options.Error) *taskOptionsParseResponse {
+	if len(errs) > 0 {
+		return &taskOptionsParseResponse{Errors: errs}
+	}
+	return &taskOptionsParseResponse{
+		Valid:         true,
+		Options:       &opts,
+		EffectiveCron: opts.EffectiveCronString(),
+		Errors:        []options.Error{},
+	}
+}
+
+// handlePostParse is the HTTP handler for the POST /api/v2/tasks/options/parse route.
+func (h *TaskOptionsHandler) handlePostParse(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req taskOptionsParseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request body",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if req.Flux == "" {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "flux is required",
+		}, w)
+		return
+	}
+
+	opts, errs := options.Parse(req.Flux)
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskOptionsParseResponse(opts, errs)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestTaskOptionsHandler_handlePostParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "valid options",
+			body:     `{"flux": "option task = {name: \"a\", every: 1h}\nfrom(bucket: \"b\") |> range(start: -1h)"}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"valid": true, "options": {"name": "a", "every": "1h", "concurrency": 1, "retry": 1}, "effectiveCron": "@every 1h", "errors": []}`,
+		},
+		{
+			name:     "invalid options",
+			body:     `{"flux": "option task = {\n  name: \"a\",\n  every: 1h,\n  retry: 0,\n}"}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"valid": false, "errors": [{"option": "retry", "line": 4, "column": 10, "message": "retry must be at least 1"}]}`,
+		},
+		{
+			name:     "missing flux",
+			body:     `{}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"code": "invalid", "message": "flux is required"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewTaskOptionsHandler(&APIBackend{
+				HTTPErrorHandler: ErrorHandler(0),
+				Logger:           zap.NewNop(),
+			})
+
+			r := httptest.NewRequest("POST", "/api/v2/tasks/options/parse", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantCode, body)
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil || !eq {
+				t.Errorf("unexpected body -got/+want\n%s\n%v", diff, err)
+			}
+		})
+	}
+}
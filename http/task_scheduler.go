@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+type schedulerStateResponse struct {
+	Links               map[string]string            `json:"links"`
+	InstanceID          string                       `json:"instanceID,omitempty"`
+	Now                 time.Time                    `json:"now"`
+	LastTick            *time.Time                   `json:"lastTick,omitempty"`
+	TickLatencySeconds  float64                      `json:"tickLatencySeconds"`
+	TickDurationSeconds float64                      `json:"tickDurationSeconds"`
+	Backlog             int                          `json:"backlog"`
+	Tasks               []taskSchedulerStateResponse `json:"tasks"`
+}
+
+type taskSchedulerStateResponse struct {
+	Links           map[string]string `json:"links"`
+	TaskID          platform.ID       `json:"taskID"`
+	OrganizationID  platform.ID       `json:"orgID"`
+	AuthorizationID platform.ID       `json:"authorizationID"`
+	Name            string            `json:"name"`
+	Status          string            `json:"status"`
+	ClaimedBy       string            `json:"claimedBy,omitempty"`
+	ClaimedAt       time.Time         `json:"claimedAt"`
+	NextDue         time.Time         `json:"nextDue"`
+	HasQueue        bool              `json:"hasQueue"`
+	RunsActive      int               `json:"runsActive"`
+	MaxConcurrency  int               `json:"maxConcurrency"`
+}
+
+func newSchedulerStateResponse(s *platform.SchedulerState) *schedulerStateResponse {
+	res := &schedulerStateResponse{
+		Links: map[string]string{
+			"self": tasksSchedulerPath,
+		},
+		InstanceID:          s.InstanceID,
+		Now:                 s.Now,
+		TickLatencySeconds:  s.TickLatency.Seconds(),
+		TickDurationSeconds: s.TickDuration.Seconds(),
+		Backlog:             s.Backlog,
+		Tasks:               make([]taskSchedulerStateResponse, 0, len(s.Tasks)),
+	}
+	if !s.LastTick.IsZero() {
+		lastTick := s.LastTick
+		res.LastTick = &lastTick
+	}
+
+	for _, t := range s.Tasks {
+		res.Tasks = append(res.Tasks, taskSchedulerStateResponse{
+			Links: map[string]string{
+				"task": taskIDPath(t.TaskID),
+				"runs": taskIDRunsPath(t.TaskID),
+			},
+			TaskID:          t.TaskID,
+			OrganizationID:  t.OrganizationID,
+			AuthorizationID: t.AuthorizationID,
+			Name:            t.Name,
+			Status:          t.Status,
+			ClaimedBy:       t.ClaimedBy,
+			ClaimedAt:       t.ClaimedAt,
+			NextDue:         t.NextDue,
+			HasQueue:        t.HasQueue,
+			RunsActive:      t.RunsActive,
+			MaxConcurrency:  t.MaxConcurrency,
+		})
+	}
+	return res
+}
+
+func (r *schedulerStateResponse) toPlatform() *platform.SchedulerState {
+	s := &platform.SchedulerState{
+		InstanceID:   r.InstanceID,
+		Now:          r.Now,
+		TickLatency:  time.Duration(math.Round(r.TickLatencySeconds * float64(time.Second))),
+		TickDuration: time.Duration(math.Round(r.TickDurationSeconds * float64(time.Second))),
+		Backlog:      r.Backlog,
+		Tasks:        make([]platform.TaskSchedulerState, 0, len(r.Tasks)),
+	}
+	if r.LastTick != nil {
+		s.LastTick = *r.LastTick
+	}
+
+	for _, t := range r.Tasks {
+		s.Tasks = append(s.Tasks, platform.TaskSchedulerState{
+			TaskID:          t.TaskID,
+			OrganizationID:  t.OrganizationID,
+			AuthorizationID: t.AuthorizationID,
+			Name:            t.Name,
+			Status:          t.Status,
+			ClaimedBy:       t.ClaimedBy,
+			ClaimedAt:       t.ClaimedAt,
+			NextDue:         t.NextDue,
+			HasQueue:        t.HasQueue,
+			RunsActive:      t.RunsActive,
+			MaxConcurrency:  t.MaxConcurrency,
+		})
+	}
+	return s
+}
+
+// handleGetSchedulerState is the HTTP handler for the GET /api/v2/tasks/scheduler route.
+func (h *TaskHandler) handleGetSchedulerState(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	state, err := h.SchedulerStateService.SchedulerState(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newSchedulerStateResponse(state)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+// SchedulerState returns a snapshot of the task scheduler and the tasks it has claimed.
+func (t TaskService) SchedulerState(ctx context.Context) (*platform.SchedulerState, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, tasksSchedulerPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var res schedulerStateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.toPlatform(), nil
+}
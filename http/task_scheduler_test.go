@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestTaskHandler_handleGetSchedulerState(t *testing.T) {
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	state := &platform.SchedulerState{
+		InstanceID:   "node-1",
+		Now:          now,
+		LastTick:     now.Add(20 * time.Millisecond),
+		TickLatency:  20 * time.Millisecond,
+		TickDuration: 5 * time.Millisecond,
+		Backlog:      1,
+		Tasks: []platform.TaskSchedulerState{
+			{
+				TaskID:          1,
+				OrganizationID:  2,
+				AuthorizationID: 3,
+				Name:            "downsample",
+				Status:          platform.TaskSchedulerStatusBlocked,
+				ClaimedBy:       "node-1",
+				ClaimedAt:       now.Add(-time.Hour),
+				NextDue:         now,
+				RunsActive:      1,
+				MaxConcurrency:  1,
+			},
+		},
+	}
+
+	b := NewMockTaskBackend(t)
+	b.HTTPErrorHandler = ErrorHandler(0)
+	ss := mock.NewSchedulerStateService()
+	ss.SchedulerStateFn = func(context.Context) (*platform.SchedulerState, error) {
+		return state, nil
+	}
+	b.SchedulerStateService = ss
+	h := NewTaskHandler(b)
+
+	t.Run("returns the scheduler state", func(t *testing.T) {
+		server := httptest.NewServer(h)
+		defer server.Close()
+
+		client := TaskService{Addr: server.URL}
+		got, err := client.SchedulerState(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, state) {
+			t.Errorf("got %+v, want %+v", got, state)
+		}
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/api/v2/tasks/scheduler", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusMethodNotAllowed)
+		}
+		if allow := w.Header().Get("Allow"); allow != "GET" {
+			t.Errorf("got Allow %q, want GET", allow)
+		}
+	})
+}
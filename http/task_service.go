@@ -6,11 +6,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/influxdata/flux/lang"
@@ -40,6 +40,12 @@ type TaskBackend struct {
 	LabelService               platform.LabelService
 	UserService                platform.UserService
 	BucketService              platform.BucketService
+	TaskTemplateService        platform.TaskTemplateService
+	TaskRunImporter            platform.TaskRunImporter
+	SchedulerStateService      platform.SchedulerStateService
+	RunLogStreamer             platform.RunLogStreamer
+	TaskDebugService           platform.TaskDebugService
+	RevisionService            platform.RevisionService
 }
 
 // NewTaskBackend returns a new instance of TaskBackend.
@@ -54,6 +60,12 @@ func NewTaskBackend(b *APIBackend) *TaskBackend {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TaskTemplateService:        b.TaskTemplateService,
+		TaskRunImporter:            b.TaskRunImporter,
+		SchedulerStateService:      b.SchedulerStateService,
+		RunLogStreamer:             b.RunLogStreamer,
+		TaskDebugService:           b.TaskDebugService,
+		RevisionService:            b.RevisionService,
 	}
 }
 
@@ -70,22 +82,43 @@ type TaskHandler struct {
 	LabelService               platform.LabelService
 	UserService                platform.UserService
 	BucketService              platform.BucketService
+	TaskTemplateService        platform.TaskTemplateService
+	TaskRunImporter            platform.TaskRunImporter
+	SchedulerStateService      platform.SchedulerStateService
+	RunLogStreamer             platform.RunLogStreamer
+	TaskDebugService           platform.TaskDebugService
+	RevisionService            platform.RevisionService
 }
 
 const (
-	tasksPath              = "/api/v2/tasks"
-	tasksIDPath            = "/api/v2/tasks/:id"
-	tasksIDLogsPath        = "/api/v2/tasks/:id/logs"
-	tasksIDMembersPath     = "/api/v2/tasks/:id/members"
-	tasksIDMembersIDPath   = "/api/v2/tasks/:id/members/:userID"
-	tasksIDOwnersPath      = "/api/v2/tasks/:id/owners"
-	tasksIDOwnersIDPath    = "/api/v2/tasks/:id/owners/:userID"
-	tasksIDRunsPath        = "/api/v2/tasks/:id/runs"
-	tasksIDRunsIDPath      = "/api/v2/tasks/:id/runs/:rid"
-	tasksIDRunsIDLogsPath  = "/api/v2/tasks/:id/runs/:rid/logs"
-	tasksIDRunsIDRetryPath = "/api/v2/tasks/:id/runs/:rid/retry"
-	tasksIDLabelsPath      = "/api/v2/tasks/:id/labels"
-	tasksIDLabelsIDPath    = "/api/v2/tasks/:id/labels/:lid"
+	tasksPath                   = "/api/v2/tasks"
+	tasksIDPath                 = "/api/v2/tasks/:id"
+	tasksIDLogsPath             = "/api/v2/tasks/:id/logs"
+	tasksIDMembersPath          = "/api/v2/tasks/:id/members"
+	tasksIDMembersIDPath        = "/api/v2/tasks/:id/members/:userID"
+	tasksIDOwnersPath           = "/api/v2/tasks/:id/owners"
+	tasksIDOwnersIDPath         = "/api/v2/tasks/:id/owners/:userID"
+	tasksIDRunsPath             = "/api/v2/tasks/:id/runs"
+	tasksIDRunsIDPath           = "/api/v2/tasks/:id/runs/:rid"
+	tasksIDRunsIDLogsPath       = "/api/v2/tasks/:id/runs/:rid/logs"
+	tasksIDRunsIDLogsStreamPath = "/api/v2/tasks/:id/runs/:rid/logs/stream"
+	tasksIDRunsIDRetryPath      = "/api/v2/tasks/:id/runs/:rid/retry"
+	tasksIDLabelsPath           = "/api/v2/tasks/:id/labels"
+	tasksIDLabelsIDPath         = "/api/v2/tasks/:id/labels/:lid"
+	tasksIDExportPath           = "/api/v2/tasks/:id/export"
+	tasksIDDebugPath            = "/api/v2/tasks/:id/debug"
+	tasksIDRevisionsPath        = "/api/v2/tasks/:id/revisions"
+	tasksIDRevisionsVersionPath = "/api/v2/tasks/:id/revisions/:version"
+	tasksIDRevisionsRestorePath = "/api/v2/tasks/:id/revisions/:version/restore"
+
+	// tasksFromTemplatePath, tasksImportPath and tasksConvertTickscriptPath are custom methods on the tasks collection.
+	// httprouter treats ':' as the start of a parameter, so they are routed by ServeHTTP.
+	tasksFromTemplatePath      = "/api/v2/tasks:fromTemplate"
+	tasksImportPath            = "/api/v2/tasks:import"
+	tasksConvertTickscriptPath = "/api/v2/tasks:convertTickscript"
+
+	// tasksSchedulerPath is routed by ServeHTTP too, since httprouter can't tell it from tasksIDPath.
+	tasksSchedulerPath = "/api/v2/tasks/scheduler"
 )
 
 // NewTaskHandler returns a new instance of TaskHandler.
@@ -102,6 +135,12 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
 		BucketService:              b.BucketService,
+		TaskTemplateService:        b.TaskTemplateService,
+		TaskRunImporter:            b.TaskRunImporter,
+		SchedulerStateService:      b.SchedulerStateService,
+		RunLogStreamer:             b.RunLogStreamer,
+		TaskDebugService:           b.TaskDebugService,
+		RevisionService:            b.RevisionService,
 	}
 
 	h.HandlerFunc("GET", tasksPath, h.handleGetTasks)
@@ -110,9 +149,11 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("GET", tasksIDPath, h.handleGetTask)
 	h.HandlerFunc("PATCH", tasksIDPath, h.handleUpdateTask)
 	h.HandlerFunc("DELETE", tasksIDPath, h.handleDeleteTask)
+	h.HandlerFunc("GET", tasksIDExportPath, h.handleGetTaskExport)
 
 	h.HandlerFunc("GET", tasksIDLogsPath, h.handleGetLogs)
 	h.HandlerFunc("GET", tasksIDRunsIDLogsPath, h.handleGetLogs)
+	h.HandlerFunc("GET", tasksIDRunsIDLogsStreamPath, h.handleGetRunLogsStream)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -142,6 +183,7 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDRunsPath, h.handleForceRun)
 	h.HandlerFunc("GET", tasksIDRunsIDPath, h.handleGetRun)
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
+	h.HandlerFunc("POST", tasksIDDebugPath, h.handlePostDebugRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
 	labelBackend := &LabelBackend{
@@ -154,9 +196,50 @@ func NewTaskHandler(b *TaskBackend) *TaskHandler {
 	h.HandlerFunc("POST", tasksIDLabelsPath, newPostLabelHandler(labelBackend))
 	h.HandlerFunc("DELETE", tasksIDLabelsIDPath, newDeleteLabelHandler(labelBackend))
 
+	revisionBackend := &RevisionBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "revision")),
+		RevisionService:  b.RevisionService,
+		ResourceType:     platform.TasksResourceType,
+	}
+	h.HandlerFunc("GET", tasksIDRevisionsPath, newGetRevisionsHandler(revisionBackend))
+	h.HandlerFunc("GET", tasksIDRevisionsVersionPath, newGetRevisionHandler(revisionBackend))
+	h.HandlerFunc("POST", tasksIDRevisionsRestorePath, newPostRevisionRestoreHandler(revisionBackend))
+
+	h.HandlerFunc("POST", bucketsIDDownsamplePath, h.handlePostBucketDownsample)
+
 	return h
 }
 
+// ServeHTTP routes the custom methods on the tasks collection and the scheduler state
+// before delegating to the router.
+func (h *TaskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		method  = "POST"
+		handler http.HandlerFunc
+	)
+	switch r.URL.Path {
+	case tasksFromTemplatePath:
+		handler = h.handlePostTasksFromTemplate
+	case tasksImportPath:
+		handler = h.handlePostTasksImport
+	case tasksConvertTickscriptPath:
+		handler = h.handlePostTasksConvertTickscript
+	case tasksSchedulerPath:
+		method, handler = "GET", h.handleGetSchedulerState
+	default:
+		h.Router.ServeHTTP(w, r)
+		return
+	}
+
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		baseHandler{HTTPErrorHandler: h.HTTPErrorHandler}.methodNotAllowed(w, r)
+		return
+	}
+	handler(w, r)
+}
+
 type taskResponse struct {
 	Links  map[string]string `json:"links"`
 	Labels []platform.Label  `json:"labels"`
@@ -184,7 +267,7 @@ func newTaskResponse(t platform.Task, labels []*platform.Label) taskResponse {
 	return response
 }
 
-func newTasksPagingLinks(basePath string, ts []*platform.Task, f platform.TaskFilter) *platform.PagingLinks {
+func newTasksPagingLinks(basePath string, ts []*platform.Task, f platform.PagingFilter, limit int) *platform.PagingLinks {
 	var self, next string
 	u := url.URL{
 		Path: basePath,
@@ -202,8 +285,8 @@ func newTasksPagingLinks(basePath string, ts []*platform.Task, f platform.TaskFi
 	u.RawQuery = values.Encode()
 	self = u.String()
 
-	if len(ts) >= f.Limit {
-		values.Set("after", ts[f.Limit-1].ID.String())
+	if len(ts) >= limit {
+		values.Set("after", ts[limit-1].ID.String())
 		u.RawQuery = values.Encode()
 		next = u.String()
 	}
@@ -221,9 +304,9 @@ type tasksResponse struct {
 	Tasks []taskResponse        `json:"tasks"`
 }
 
-func newTasksResponse(ctx context.Context, ts []*platform.Task, f platform.TaskFilter, labelService platform.LabelService) tasksResponse {
+func newTasksResponse(ctx context.Context, ts []*platform.Task, f platform.TaskFilter, labels labelFilter, labelService platform.LabelService) tasksResponse {
 	rs := tasksResponse{
-		Links: newTasksPagingLinks(tasksPath, ts, f),
+		Links: newTasksPagingLinks(tasksPath, ts, labels.pagingFilter(f), f.Limit),
 		Tasks: make([]taskResponse, len(ts)),
 	}
 
@@ -286,7 +369,7 @@ func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, _, err := h.TaskService.FindTasks(ctx, req.filter)
+	tasks, err := h.findTasks(ctx, req)
 	if err != nil {
 		err = &platform.Error{
 			Err: err,
@@ -296,20 +379,64 @@ func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	h.logger.Debug("tasks retrived", zap.String("tasks", fmt.Sprint(tasks)))
-	if err := encodeResponse(ctx, w, http.StatusOK, newTasksResponse(ctx, tasks, req.filter, h.LabelService)); err != nil {
+	res := newTasksResponse(ctx, tasks, req.filter, req.labels, h.LabelService)
+	if err := encodeListResponse(ctx, w, req.fields, res.Links, "tasks", res); err != nil {
 		logEncodingError(h.logger, r, err)
 		return
 	}
 }
 
+// findTasks finds the tasks of the request. Tasks are paged by cursor, so
+// tasks filtered by label are found a page at a time, until a page of tasks
+// that carry the labels is filled.
+func (h *TaskHandler) findTasks(ctx context.Context, req *getTasksRequest) ([]*platform.Task, error) {
+	if len(req.labels) == 0 {
+		ts, _, err := h.TaskService.FindTasks(ctx, req.filter)
+		return ts, err
+	}
+
+	var labeled []*platform.Task
+	filter := req.filter
+	for {
+		ts, _, err := h.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := platform.FindOptions{Limit: filter.Limit - len(labeled)}
+		idx, err := req.labels.filter(ctx, h.LabelService, opts, len(ts), func(i int) platform.ID { return ts[i].ID })
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range idx {
+			labeled = append(labeled, ts[i])
+		}
+
+		if len(labeled) >= filter.Limit || len(ts) < filter.Limit {
+			return labeled, nil
+		}
+		filter.After = &ts[len(ts)-1].ID
+	}
+}
+
 type getTasksRequest struct {
 	filter platform.TaskFilter
+	fields []string
+	labels labelFilter
 }
 
 func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs platform.OrganizationService) (*getTasksRequest, error) {
 	qp := r.URL.Query()
 	req := &getTasksRequest{}
 
+	// Tasks are paged by cursor, in the order of their IDs.
+	if qp.Get("offset") != "" || qp.Get("sortBy") != "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "tasks are paged with after and limit, and cannot be sorted",
+		}
+	}
+
 	if after := qp.Get("after"); after != "" {
 		id, err := platform.IDFromString(after)
 		if err != nil {
@@ -366,6 +493,15 @@ func decodeGetTasksRequest(ctx context.Context, r *http.Request, orgs platform.O
 		req.filter.Limit = platform.TaskDefaultPageSize
 	}
 
+	metadata, err := platform.ParseMetadataQueryParams(qp["metadata"])
+	if err != nil {
+		return nil, err
+	}
+	req.filter.Metadata = metadata
+
+	req.fields = decodeFields(qp)
+	req.labels = decodeLabelFilter(r)
+
 	return req, nil
 }
 
@@ -507,45 +643,148 @@ func (h *TaskHandler) handlePostTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bootstrapAuthz, err := h.createBootstrapTaskAuthorizationIfNotExists(ctx, auth, &req.TaskCreate)
+	task, err := h.createTask(ctx, auth, req.TaskCreate)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+	h.logger.Debug("tasks created", zap.String("task", fmt.Sprint(task)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, newTaskResponse(*task, []*platform.Label{})); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
 
-	task, err := h.TaskService.CreateTask(ctx, req.TaskCreate)
+// createTask creates the task on behalf of auth, bootstrapping an authorization
+// for the task when the request came from a session without a token.
+func (h *TaskHandler) createTask(ctx context.Context, auth platform.Authorizer, tc platform.TaskCreate) (*platform.Task, error) {
+	bootstrapAuthz, err := h.createBootstrapTaskAuthorizationIfNotExists(ctx, auth, &tc)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := h.TaskService.CreateTask(ctx, tc)
 	if err != nil {
 		if e, ok := err.(AuthzError); ok {
 			h.logger.Error("failed authentication", zap.Errors("error messages", []error{err, e.AuthzError()}))
 		}
-		err = &platform.Error{
+		return nil, &platform.Error{
 			Err: err,
 			Msg: "failed to create task",
 		}
-		h.HandleHTTPError(ctx, err, w)
-		return
 	}
 
 	if bootstrapAuthz != nil {
 		// There was a bootstrapped authorization for this task.
 		// Now we need to apply the final authorization for the task.
 		if err := h.finalizeBootstrappedTaskAuthorization(ctx, bootstrapAuthz, task); err != nil {
-			err = &platform.Error{
+			return nil, &platform.Error{
 				Err:  err,
 				Msg:  fmt.Sprintf("successfully created task with ID %s, but failed to finalize bootstrap token for task", task.ID.String()),
 				Code: platform.EInternal,
 			}
+		}
+	}
+	return task, nil
+}
+
+// handlePostTasksFromTemplate is the HTTP handler for the POST /api/v2/tasks:fromTemplate route.
+// It creates one task per set of params, or none if any set fails to instantiate.
+func (h *TaskHandler) handlePostTasksFromTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	auth, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		err = &platform.Error{
+			Err:  err,
+			Code: platform.EUnauthorized,
+			Msg:  "failed to get authorizer",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	req, err := decodePostTasksFromTemplateRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tt, err := h.TaskTemplateService.FindTaskTemplateByID(ctx, req.TemplateID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tcs, err := tt.TaskCreates(*req)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	for i := range tcs {
+		if err := h.populateTaskCreateOrg(ctx, &tcs[i]); err != nil {
+			err = &platform.Error{
+				Err: err,
+				Msg: "could not identify organization",
+			}
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		if err := tcs[i].Validate(); err != nil {
+			err = &platform.Error{
+				Err:  err,
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("params at index %d do not describe a valid task", i),
+			}
 			h.HandleHTTPError(ctx, err, w)
 			return
 		}
 	}
-	h.logger.Debug("tasks created", zap.String("task", fmt.Sprint(task)))
-	if err := encodeResponse(ctx, w, http.StatusCreated, newTaskResponse(*task, []*platform.Label{})); err != nil {
+
+	res := tasksFromTemplateResponse{
+		Tasks: make([]taskResponse, 0, len(tcs)),
+	}
+	created := make([]*platform.Task, 0, len(tcs))
+	for _, tc := range tcs {
+		task, err := h.createTask(ctx, auth, tc)
+		if err != nil {
+			// Don't leave a partial set of tasks behind.
+			h.deleteCreatedTasks(ctx, created)
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		created = append(created, task)
+		res.Tasks = append(res.Tasks, newTaskResponse(*task, []*platform.Label{}))
+	}
+
+	h.logger.Debug("tasks created from template", zap.String("templateID", tt.ID.String()), zap.Int("count", len(created)))
+	if err := encodeResponse(ctx, w, http.StatusCreated, res); err != nil {
 		logEncodingError(h.logger, r, err)
 		return
 	}
 }
 
+type tasksFromTemplateResponse struct {
+	Tasks []taskResponse `json:"tasks"`
+}
+
+func decodePostTasksFromTemplateRequest(ctx context.Context, r *http.Request) (*platform.TasksFromTemplate, error) {
+	var req platform.TasksFromTemplate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
 type postTaskRequest struct {
 	TaskCreate platform.TaskCreate
 }
@@ -995,7 +1234,7 @@ func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	run, err := h.TaskService.ForceRun(ctx, req.TaskID, req.Timestamp)
+	run, err := h.TaskService.ForceRun(ctx, req.TaskID, req.Timestamp, req.Annotation)
 	if err != nil {
 		err := &platform.Error{
 			Err: err,
@@ -1014,8 +1253,9 @@ func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
 }
 
 type forceRunRequest struct {
-	TaskID    platform.ID
-	Timestamp int64
+	TaskID     platform.ID
+	Timestamp  int64
+	Annotation string
 }
 
 func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunRequest, error) {
@@ -1035,6 +1275,7 @@ func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunReques
 
 	var req struct {
 		ScheduledFor string `json:"scheduledFor"`
+		Annotation   string `json:"annotation"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return forceRunRequest{}, err
@@ -1052,8 +1293,9 @@ func decodeForceRunRequest(ctx context.Context, r *http.Request) (forceRunReques
 	}
 
 	return forceRunRequest{
-		TaskID:    ti,
-		Timestamp: t.Unix(),
+		TaskID:     ti,
+		Timestamp:  t.Unix(),
+		Annotation: req.Annotation,
 	}, nil
 }
 
@@ -1251,7 +1493,7 @@ func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
 		ctx = pcontext.SetAuthorizer(ctx, authz)
 	}
 
-	run, err := h.TaskService.RetryRun(ctx, req.TaskID, req.RunID)
+	run, err := h.TaskService.RetryRun(ctx, req.TaskID, req.RunID, req.Annotation)
 	if err != nil {
 		err := &platform.Error{
 			Err: err,
@@ -1271,6 +1513,7 @@ func (h *TaskHandler) handleRetryRun(w http.ResponseWriter, r *http.Request) {
 
 type retryRunRequest struct {
 	RunID, TaskID platform.ID
+	Annotation    string
 }
 
 func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunRequest, error) {
@@ -1298,9 +1541,18 @@ func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunReque
 		return nil, err
 	}
 
+	// The body is optional.
+	var req struct {
+		Annotation string `json:"annotation"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return nil, err
+	}
+
 	return &retryRunRequest{
-		RunID:  ri,
-		TaskID: ti,
+		RunID:      ri,
+		TaskID:     ti,
+		Annotation: req.Annotation,
 	}, nil
 }
 
@@ -1433,6 +1685,9 @@ func (t TaskService) FindTasks(ctx context.Context, filter platform.TaskFilter)
 	if filter.Limit != 0 {
 		val.Add("limit", strconv.Itoa(filter.Limit))
 	}
+	for _, m := range filter.Metadata.QueryParams() {
+		val.Add("metadata", m)
+	}
 
 	u.RawQuery = val.Encode()
 
@@ -1507,6 +1762,53 @@ func (t TaskService) CreateTask(ctx context.Context, tc platform.TaskCreate) (*p
 	return &tr.Task, nil
 }
 
+// CreateTasksFromTemplate creates one task per set of params from a task template.
+func (t TaskService) CreateTasksFromTemplate(ctx context.Context, tft platform.TasksFromTemplate) ([]*platform.Task, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	u, err := NewURL(t.Addr, tasksFromTemplatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	reqBytes, err := json.Marshal(tft)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(t.Token, req)
+
+	hc := NewClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var tr tasksFromTemplateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*platform.Task, len(tr.Tasks))
+	for i := range tr.Tasks {
+		tasks[i] = &tr.Tasks[i].Task
+	}
+	return tasks, nil
+}
+
 // UpdateTask updates a single task with changeset.
 func (t TaskService) UpdateTask(ctx context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
@@ -1727,7 +2029,7 @@ func (t TaskService) FindRunByID(ctx context.Context, taskID, runID platform.ID)
 }
 
 // RetryRun creates and returns a new run (which is a retry of another run).
-func (t TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID) (*platform.Run, error) {
+func (t TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID, annotation string) (*platform.Run, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -1737,7 +2039,14 @@ func (t TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID) (*
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", u.String(), nil)
+	body, err := json.Marshal(struct {
+		Annotation string `json:"annotation,omitempty"`
+	}{annotation})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -1774,7 +2083,7 @@ func (t TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID) (*
 	return &rs.Run, nil
 }
 
-func (t TaskService) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*platform.Run, error) {
+func (t TaskService) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64, annotation string) (*platform.Run, error) {
 	span, _ := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -1783,8 +2092,18 @@ func (t TaskService) ForceRun(ctx context.Context, taskID platform.ID, scheduled
 		return nil, err
 	}
 
-	body := fmt.Sprintf(`{"scheduledFor": %q}`, time.Unix(scheduledFor, 0).UTC().Format(time.RFC3339))
-	req, err := http.NewRequest("POST", u.String(), strings.NewReader(body))
+	body, err := json.Marshal(struct {
+		ScheduledFor string `json:"scheduledFor"`
+		Annotation   string `json:"annotation,omitempty"`
+	}{
+		ScheduledFor: time.Unix(scheduledFor, 0).UTC().Format(time.RFC3339),
+		Annotation:   annotation,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
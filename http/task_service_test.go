@@ -918,7 +918,7 @@ func TestTaskHandler_NotFoundStatus(t *testing.T) {
 		{
 			name: "force run",
 			svc: &mock.TaskService{
-				ForceRunFn: func(_ context.Context, tid platform.ID, _ int64) (*platform.Run, error) {
+				ForceRunFn: func(_ context.Context, tid platform.ID, _ int64, _ string) (*platform.Run, error) {
 					if tid != taskID {
 						return nil, platform.ErrTaskNotFound
 					}
@@ -954,7 +954,7 @@ func TestTaskHandler_NotFoundStatus(t *testing.T) {
 		{
 			name: "retry run",
 			svc: &mock.TaskService{
-				RetryRunFn: func(_ context.Context, tid, rid platform.ID) (*platform.Run, error) {
+				RetryRunFn: func(_ context.Context, tid, rid platform.ID, _ string) (*platform.Run, error) {
 					if tid != taskID {
 						return nil, platform.ErrTaskNotFound
 					}
@@ -1719,7 +1719,7 @@ func TestTaskHandler_Sessions(t *testing.T) {
 
 		var retryRunCtx context.Context
 		ts := &mock.TaskService{
-			RetryRunFn: func(ctx context.Context, tid, rid platform.ID) (*platform.Run, error) {
+			RetryRunFn: func(ctx context.Context, tid, rid platform.ID, _ string) (*platform.Run, error) {
 				retryRunCtx = ctx
 				if tid != taskID {
 					t.Fatalf("expected task ID %v, got %v", taskID, tid)
@@ -0,0 +1,335 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	taskTemplatesPath   = "/api/v2/tasktemplates"
+	taskTemplatesIDPath = "/api/v2/tasktemplates/:id"
+)
+
+// TaskTemplateBackend is all services and associated parameters required to construct
+// the TaskTemplateHandler.
+type TaskTemplateBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	TaskTemplateService platform.TaskTemplateService
+	OrganizationService platform.OrganizationService
+}
+
+// NewTaskTemplateBackend returns a new instance of TaskTemplateBackend.
+func NewTaskTemplateBackend(b *APIBackend) *TaskTemplateBackend {
+	return &TaskTemplateBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Logger:              b.Logger.With(zap.String("handler", "task_template")),
+		TaskTemplateService: b.TaskTemplateService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// TaskTemplateHandler represents an HTTP API handler for task templates.
+type TaskTemplateHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	TaskTemplateService platform.TaskTemplateService
+	OrganizationService platform.OrganizationService
+}
+
+// NewTaskTemplateHandler returns a new instance of TaskTemplateHandler.
+func NewTaskTemplateHandler(b *TaskTemplateBackend) *TaskTemplateHandler {
+	h := &TaskTemplateHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		TaskTemplateService: b.TaskTemplateService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", taskTemplatesPath, h.handleGetTaskTemplates)
+	h.HandlerFunc("POST", taskTemplatesPath, h.handlePostTaskTemplate)
+
+	h.HandlerFunc("GET", taskTemplatesIDPath, h.handleGetTaskTemplate)
+	h.HandlerFunc("PATCH", taskTemplatesIDPath, h.handlePatchTaskTemplate)
+	h.HandlerFunc("DELETE", taskTemplatesIDPath, h.handleDeleteTaskTemplate)
+
+	return h
+}
+
+type taskTemplateLinks struct {
+	Self  string `json:"self"`
+	Org   string `json:"org"`
+	Tasks string `json:"tasks"`
+}
+
+type taskTemplateResponse struct {
+	*platform.TaskTemplate
+	Links taskTemplateLinks `json:"links"`
+}
+
+func newTaskTemplateResponse(tt *platform.TaskTemplate) taskTemplateResponse {
+	return taskTemplateResponse{
+		TaskTemplate: tt,
+		Links: taskTemplateLinks{
+			Self:  taskTemplateIDPath(tt.ID),
+			Org:   fmt.Sprintf("/api/v2/orgs/%s", tt.OrganizationID),
+			Tasks: tasksFromTemplatePath,
+		},
+	}
+}
+
+type taskTemplatesResponse struct {
+	Links         map[string]string      `json:"links"`
+	TaskTemplates []taskTemplateResponse `json:"taskTemplates"`
+}
+
+func newTaskTemplatesResponse(tts []*platform.TaskTemplate) taskTemplatesResponse {
+	res := taskTemplatesResponse{
+		Links: map[string]string{
+			"self": taskTemplatesPath,
+		},
+		TaskTemplates: make([]taskTemplateResponse, 0, len(tts)),
+	}
+	for _, tt := range tts {
+		res.TaskTemplates = append(res.TaskTemplates, newTaskTemplateResponse(tt))
+	}
+	return res
+}
+
+func taskTemplateIDPath(id platform.ID) string {
+	return path.Join(taskTemplatesPath, id.String())
+}
+
+// handleGetTaskTemplates is the HTTP handler for the GET /api/v2/tasktemplates route.
+func (h *TaskTemplateHandler) handleGetTaskTemplates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := h.decodeGetTaskTemplatesRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tts, err := h.TaskTemplateService.FindTaskTemplates(ctx, *filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskTemplatesResponse(tts)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *TaskTemplateHandler) decodeGetTaskTemplatesRequest(ctx context.Context, r *http.Request) (*platform.TaskTemplateFilter, error) {
+	qp := r.URL.Query()
+	filter := &platform.TaskTemplateFilter{}
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		filter.OrganizationID = &o.ID
+	}
+
+	return filter, nil
+}
+
+// handlePostTaskTemplate is the HTTP handler for the POST /api/v2/tasktemplates route.
+func (h *TaskTemplateHandler) handlePostTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tt, err := decodePostTaskTemplateRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TaskTemplateService.CreateTaskTemplate(ctx, tt); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newTaskTemplateResponse(tt)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodePostTaskTemplateRequest(ctx context.Context, r *http.Request) (*platform.TaskTemplate, error) {
+	tt := &platform.TaskTemplate{}
+	if err := json.NewDecoder(r.Body).Decode(tt); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	if err := tt.Valid(); err != nil {
+		return nil, err
+	}
+
+	return tt, nil
+}
+
+// handleGetTaskTemplate is the HTTP handler for the GET /api/v2/tasktemplates/:id route.
+func (h *TaskTemplateHandler) handleGetTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tt, err := h.TaskTemplateService.FindTaskTemplateByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskTemplateResponse(tt)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchTaskTemplate is the HTTP handler for the PATCH /api/v2/tasktemplates/:id route.
+func (h *TaskTemplateHandler) handlePatchTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	upd := platform.TaskTemplateUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := upd.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tt, err := h.TaskTemplateService.UpdateTaskTemplate(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTaskTemplateResponse(tt)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteTaskTemplate is the HTTP handler for the DELETE /api/v2/tasktemplates/:id route.
+func (h *TaskTemplateHandler) handleDeleteTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TaskTemplateService.DeleteTaskTemplate(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TaskTemplateService connects to Influx via HTTP using tokens to manage task templates.
+type TaskTemplateService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.TaskTemplateService = (*TaskTemplateService)(nil)
+
+// FindTaskTemplateByID returns a single task template by ID.
+func (s *TaskTemplateService) FindTaskTemplateByID(ctx context.Context, id platform.ID) (*platform.TaskTemplate, error) {
+	var res taskTemplateResponse
+	if err := s.client().do(ctx, "GET", taskTemplateIDPath(id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.TaskTemplate, nil
+}
+
+// FindTaskTemplates returns all task templates that match the filter.
+func (s *TaskTemplateService) FindTaskTemplates(ctx context.Context, filter platform.TaskTemplateFilter) ([]*platform.TaskTemplate, error) {
+	query := url.Values{}
+	if filter.OrganizationID != nil {
+		query.Set("orgID", filter.OrganizationID.String())
+	}
+
+	var res taskTemplatesResponse
+	if err := s.client().do(ctx, "GET", taskTemplatesPath, query, nil, &res); err != nil {
+		return nil, err
+	}
+
+	tts := make([]*platform.TaskTemplate, 0, len(res.TaskTemplates))
+	for _, tt := range res.TaskTemplates {
+		tts = append(tts, tt.TaskTemplate)
+	}
+	return tts, nil
+}
+
+// CreateTaskTemplate creates a new task template and sets tt.ID with the new identifier.
+func (s *TaskTemplateService) CreateTaskTemplate(ctx context.Context, tt *platform.TaskTemplate) error {
+	var res taskTemplateResponse
+	if err := s.client().do(ctx, "POST", taskTemplatesPath, nil, tt, &res); err != nil {
+		return err
+	}
+	*tt = *res.TaskTemplate
+	return nil
+}
+
+// UpdateTaskTemplate updates a single task template with a changeset.
+func (s *TaskTemplateService) UpdateTaskTemplate(ctx context.Context, id platform.ID, upd platform.TaskTemplateUpdate) (*platform.TaskTemplate, error) {
+	var res taskTemplateResponse
+	if err := s.client().do(ctx, "PATCH", taskTemplateIDPath(id), nil, upd, &res); err != nil {
+		return nil, err
+	}
+	return res.TaskTemplate, nil
+}
+
+// DeleteTaskTemplate removes a task template by ID.
+func (s *TaskTemplateService) DeleteTaskTemplate(ctx context.Context, id platform.ID) error {
+	return s.client().do(ctx, "DELETE", taskTemplateIDPath(id), nil, nil, nil)
+}
+
+func (s *TaskTemplateService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestTaskHandler_handlePostTasksFromTemplate(t *testing.T) {
+	tmpl := &platform.TaskTemplate{
+		ID:             1,
+		OrganizationID: 2,
+		Name:           "downsample",
+		Description:    "downsample raw data",
+		Flux:           `option task = {name: "downsample-{{ .bucket }}", every: {{ .every }}}` + "\n" + `from(bucket: "{{ .bucket }}") |> range(start: -{{ .every }})`,
+		Params: []platform.TaskTemplateParam{
+			{Name: "bucket", Type: platform.TaskTemplateParamString},
+			{Name: "every", Type: platform.TaskTemplateParamDuration},
+		},
+	}
+
+	newHandler := func(ts *mock.TaskService) *TaskHandler {
+		tts := mock.NewTaskTemplateService()
+		tts.FindTaskTemplateByIDFn = func(ctx context.Context, id platform.ID) (*platform.TaskTemplate, error) {
+			if id != tmpl.ID {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrTaskTemplateNotFound}
+			}
+			return tmpl, nil
+		}
+
+		b := NewMockTaskBackend(t)
+		b.HTTPErrorHandler = ErrorHandler(0)
+		b.TaskService = ts
+		b.TaskTemplateService = tts
+		return NewTaskHandler(b)
+	}
+
+	serve := func(h *TaskHandler, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/api/v2/tasks:fromTemplate", bytes.NewBufferString(body))
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Permissions: platform.OperPermissions()}))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("creates one task per set of params", func(t *testing.T) {
+		var created []platform.TaskCreate
+		ts := &mock.TaskService{
+			CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+				created = append(created, tc)
+				return &platform.Task{
+					ID:              platform.ID(len(created) + 10),
+					OrganizationID:  tc.OrganizationID,
+					Organization:    tc.Organization,
+					AuthorizationID: 40,
+					Flux:            tc.Flux,
+					Description:     tc.Description,
+					Status:          tc.Status,
+				}, nil
+			},
+		}
+
+		w := serve(newHandler(ts), `{"templateID":"0000000000000001","status":"inactive","params":[{"bucket":"cpu","every":"1h"},{"bucket":"mem","every":"5m"}]}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+		}
+
+		var res tasksFromTemplateResponse
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Tasks) != 2 || len(created) != 2 {
+			t.Fatalf("expected 2 tasks, got %d in response and %d created", len(res.Tasks), len(created))
+		}
+
+		wantFlux := `option task = {name: "downsample-mem", every: 5m}` + "\n" + `from(bucket: "mem") |> range(start: -5m)`
+		if got := created[1].Flux; got != wantFlux {
+			t.Errorf("unexpected flux:\ngot  %s\nwant %s", got, wantFlux)
+		}
+		for _, tc := range created {
+			if tc.OrganizationID != tmpl.OrganizationID || tc.Organization != "test" {
+				t.Errorf("expected task in template organization, got %v %q", tc.OrganizationID, tc.Organization)
+			}
+			if tc.Status != platform.TaskStatusInactive || tc.Description != tmpl.Description {
+				t.Errorf("unexpected status %q or description %q", tc.Status, tc.Description)
+			}
+		}
+	})
+
+	t.Run("invalid params create no tasks", func(t *testing.T) {
+		ts := &mock.TaskService{
+			CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+				t.Fatal("no task should be created")
+				return nil, nil
+			},
+		}
+
+		w := serve(newHandler(ts), `{"templateID":"0000000000000001","params":[{"bucket":"cpu","every":"1h"},{"bucket":"mem"}]}`)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+		}
+	})
+
+	t.Run("failed create removes created tasks", func(t *testing.T) {
+		var deleted []platform.ID
+		ts := &mock.TaskService{
+			CreateTaskFn: func(ctx context.Context, tc platform.TaskCreate) (*platform.Task, error) {
+				if strings.Contains(tc.Flux, "mem") {
+					return nil, errors.New("boom")
+				}
+				return &platform.Task{ID: 10, OrganizationID: tc.OrganizationID, AuthorizationID: 40, Flux: tc.Flux}, nil
+			},
+			DeleteTaskFn: func(ctx context.Context, id platform.ID) error {
+				deleted = append(deleted, id)
+				return nil
+			},
+		}
+
+		w := serve(newHandler(ts), `{"templateID":"0000000000000001","params":[{"bucket":"cpu","every":"1h"},{"bucket":"mem","every":"1h"}]}`)
+		if w.Code == http.StatusCreated {
+			t.Fatalf("expected failure, got %d", w.Code)
+		}
+		if len(deleted) != 1 || deleted[0] != 10 {
+			t.Fatalf("expected created task to be deleted, got %v", deleted)
+		}
+	})
+
+	t.Run("only POST is allowed", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v2/tasks:fromTemplate", nil)
+		w := httptest.NewRecorder()
+		newHandler(&mock.TaskService{}).ServeHTTP(w, r)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/tickscript"
+	"go.uber.org/zap"
+)
+
+type postTasksConvertTickscriptRequest struct {
+	TICKscript     string      `json:"tickscript"`
+	Name           string      `json:"name"`
+	OrganizationID platform.ID `json:"orgID,omitempty"`
+	Organization   string      `json:"org,omitempty"`
+	AlertBucket    string      `json:"alertBucket,omitempty"`
+}
+
+func decodePostTasksConvertTickscriptRequest(ctx context.Context, r *http.Request) (*postTasksConvertTickscriptRequest, error) {
+	var req postTasksConvertTickscriptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to decode request",
+			Err:  err,
+		}
+	}
+
+	if req.TICKscript == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "tickscript is required",
+		}
+	}
+
+	if !req.OrganizationID.Valid() && req.Organization == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "org or orgID is required",
+		}
+	}
+
+	return &req, nil
+}
+
+// handlePostTasksConvertTickscript is the HTTP handler for the POST /api/v2/tasks:convertTickscript route.
+// It converts a TICKscript into the Flux of a task without creating it, and reports the constructs
+// the task doesn't reproduce.
+func (h *TaskHandler) handlePostTasksConvertTickscript(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostTasksConvertTickscriptRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	org := platform.TaskCreate{
+		OrganizationID: req.OrganizationID,
+		Organization:   req.Organization,
+	}
+	if err := h.populateTaskCreateOrg(ctx, &org); err != nil {
+		err = &platform.Error{
+			Err: err,
+			Msg: "could not identify organization",
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	conv, err := tickscript.Convert(req.TICKscript, tickscript.Options{
+		Name:        req.Name,
+		Org:         org.Organization,
+		AlertBucket: req.AlertBucket,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	for _, c := range conv.Checks {
+		c.OrgID = org.OrganizationID
+	}
+	for _, r := range conv.NotificationRules {
+		r.Rule.OrgID = org.OrganizationID
+	}
+
+	h.logger.Debug("tickscript converted", zap.String("name", req.Name), zap.Int("unconverted", len(conv.Unconverted)))
+	if err := encodeResponse(ctx, w, http.StatusOK, conv); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/task/tickscript"
+)
+
+func TestTaskHandler_handlePostTasksConvertTickscript(t *testing.T) {
+	const script = `stream
+    |from()
+        .database('telegraf')
+        .measurement('cpu')
+    |window()
+        .period(5m)
+        .every(5m)
+    |mean('usage_user')
+    |alert()
+        .crit(lambda: "mean" > 90)
+        .slack()
+`
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantFlux   []string
+	}{
+		{
+			name:       "converts the script",
+			method:     "POST",
+			body:       `{"name": "cpu", "orgID": "0000000000000002", "alertBucket": "alerts", "tickscript": ` + jsonString(script) + `}`,
+			wantStatus: http.StatusOK,
+			wantFlux: []string{
+				`option task = {name: "cpu", every: 5m}`,
+				`|> mean()`,
+				`|> to(bucket: "alerts", org: "test")`,
+			},
+		},
+		{
+			name:       "requires an organization",
+			method:     "POST",
+			body:       `{"name": "cpu", "tickscript": ` + jsonString(script) + `}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "rejects scripts that don't parse",
+			method:     "POST",
+			body:       `{"name": "cpu", "org": "test", "tickscript": "stream|from("}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "only accepts POST",
+			method:     "GET",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewMockTaskBackend(t)
+			b.HTTPErrorHandler = ErrorHandler(0)
+			h := NewTaskHandler(b)
+
+			r := httptest.NewRequest(tt.method, "/api/v2/tasks:convertTickscript", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantFlux == nil {
+				return
+			}
+
+			var conv tickscript.Conversion
+			if err := json.NewDecoder(res.Body).Decode(&conv); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.wantFlux {
+				if !strings.Contains(conv.Flux, want) {
+					t.Errorf("expected flux to contain %q, got:\n%s", want, conv.Flux)
+				}
+			}
+			if len(conv.Checks) != 1 || conv.Checks[0].Name != "cpu" {
+				t.Errorf("expected the check of the alert, got %+v", conv.Checks)
+			}
+			if len(conv.NotificationRules) != 1 || conv.NotificationRules[0].EndpointType != "slack" {
+				t.Errorf("expected the notification rule of the slack handler, got %+v", conv.NotificationRules)
+			}
+		})
+	}
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
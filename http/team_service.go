@@ -0,0 +1,456 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/influxdata/influxdb"
+)
+
+// TeamBackend is all services and associated parameters required to construct
+// the TeamHandler.
+type TeamBackend struct {
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	TeamService                influxdb.TeamService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+// NewTeamBackend returns a new instance of TeamBackend.
+func NewTeamBackend(b *APIBackend) *TeamBackend {
+	return &TeamBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "team")),
+
+		TeamService:                b.TeamService,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+}
+
+// TeamHandler represents an HTTP API handler for teams.
+type TeamHandler struct {
+	*httprouter.Router
+	influxdb.HTTPErrorHandler
+	Logger *zap.Logger
+
+	TeamService                influxdb.TeamService
+	OrganizationService        influxdb.OrganizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+	UserService                influxdb.UserService
+}
+
+const (
+	teamsPath              = "/api/v2/teams"
+	teamsIDPath            = "/api/v2/teams/:id"
+	teamsIDMembersPath     = "/api/v2/teams/:id/members"
+	teamsIDMembersIDPath   = "/api/v2/teams/:id/members/:userID"
+	teamsIDOwnersPath      = "/api/v2/teams/:id/owners"
+	teamsIDOwnersIDPath    = "/api/v2/teams/:id/owners/:userID"
+	teamsIDResourcesPath   = "/api/v2/teams/:id/resources"
+	teamsIDResourcesIDPath = "/api/v2/teams/:id/resources/:resourceID"
+)
+
+// NewTeamHandler returns a new instance of TeamHandler.
+func NewTeamHandler(b *TeamBackend) *TeamHandler {
+	h := &TeamHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		TeamService:                b.TeamService,
+		OrganizationService:        b.OrganizationService,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+
+	h.HandlerFunc("POST", teamsPath, h.handlePostTeam)
+	h.HandlerFunc("GET", teamsPath, h.handleGetTeams)
+	h.HandlerFunc("GET", teamsIDPath, h.handleGetTeam)
+	h.HandlerFunc("PATCH", teamsIDPath, h.handlePatchTeam)
+	h.HandlerFunc("DELETE", teamsIDPath, h.handleDeleteTeam)
+
+	memberBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.TeamsResourceType,
+		UserType:                   influxdb.Member,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", teamsIDMembersPath, newPostMemberHandler(memberBackend))
+	h.HandlerFunc("GET", teamsIDMembersPath, newGetMembersHandler(memberBackend))
+	h.HandlerFunc("DELETE", teamsIDMembersIDPath, newDeleteMemberHandler(memberBackend))
+
+	ownerBackend := MemberBackend{
+		HTTPErrorHandler:           b.HTTPErrorHandler,
+		Logger:                     b.Logger.With(zap.String("handler", "member")),
+		ResourceType:               influxdb.TeamsResourceType,
+		UserType:                   influxdb.Owner,
+		UserResourceMappingService: b.UserResourceMappingService,
+		UserService:                b.UserService,
+	}
+	h.HandlerFunc("POST", teamsIDOwnersPath, newPostMemberHandler(ownerBackend))
+	h.HandlerFunc("GET", teamsIDOwnersPath, newGetMembersHandler(ownerBackend))
+	h.HandlerFunc("DELETE", teamsIDOwnersIDPath, newDeleteMemberHandler(ownerBackend))
+
+	h.HandlerFunc("GET", teamsIDResourcesPath, h.handleGetTeamResources)
+	h.HandlerFunc("POST", teamsIDResourcesPath, h.handlePostTeamResource)
+	h.HandlerFunc("DELETE", teamsIDResourcesIDPath, h.handleDeleteTeamResource)
+
+	return h
+}
+
+type teamResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.Team
+}
+
+func newTeamResponse(t *influxdb.Team) *teamResponse {
+	return &teamResponse{
+		Links: map[string]string{
+			"self":      fmt.Sprintf("/api/v2/teams/%s", t.ID),
+			"members":   fmt.Sprintf("/api/v2/teams/%s/members", t.ID),
+			"owners":    fmt.Sprintf("/api/v2/teams/%s/owners", t.ID),
+			"resources": fmt.Sprintf("/api/v2/teams/%s/resources", t.ID),
+			"org":       fmt.Sprintf("/api/v2/orgs/%s", t.OrgID),
+		},
+		Team: *t,
+	}
+}
+
+type teamsResponse struct {
+	Links map[string]string `json:"links"`
+	Teams []*teamResponse   `json:"teams"`
+}
+
+func newTeamsResponse(ts []*influxdb.Team) *teamsResponse {
+	res := &teamsResponse{
+		Links: map[string]string{
+			"self": teamsPath,
+		},
+		Teams: make([]*teamResponse, 0, len(ts)),
+	}
+	for _, t := range ts {
+		res.Teams = append(res.Teams, newTeamResponse(t))
+	}
+	return res
+}
+
+// handlePostTeam is the HTTP handler for the POST /api/v2/teams route.
+func (h *TeamHandler) handlePostTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("team create request", zap.String("r", fmt.Sprint(r)))
+
+	t := &influxdb.Team{}
+	if err := json.NewDecoder(r.Body).Decode(t); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode team request",
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := t.Valid(); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TeamService.CreateTeam(ctx, t); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("team created", zap.String("team", fmt.Sprint(t)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newTeamResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetTeams is the HTTP handler for the GET /api/v2/teams route.
+func (h *TeamHandler) handleGetTeams(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("teams retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	filter, err := h.decodeGetTeamsRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ts, _, err := h.TeamService.FindTeams(ctx, filter)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("teams retrieved", zap.String("teams", fmt.Sprint(ts)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTeamsResponse(ts)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *TeamHandler) decodeGetTeamsRequest(ctx context.Context, r *http.Request) (influxdb.TeamFilter, error) {
+	qp := r.URL.Query()
+	var filter influxdb.TeamFilter
+
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := influxdb.IDFromString(orgID)
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, influxdb.OrganizationFilter{Name: &org})
+		if err != nil {
+			return filter, err
+		}
+		filter.OrgID = &o.ID
+	}
+
+	if name := qp.Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	return filter, nil
+}
+
+// handleGetTeam is the HTTP handler for the GET /api/v2/teams/:id route.
+func (h *TeamHandler) handleGetTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("team retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	t, err := h.TeamService.FindTeamByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("team retrieved", zap.String("team", fmt.Sprint(t)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTeamResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchTeam is the HTTP handler for the PATCH /api/v2/teams/:id route.
+func (h *TeamHandler) handlePatchTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("team update request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var upd influxdb.TeamUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode team update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	t, err := h.TeamService.UpdateTeam(ctx, id, upd)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("team updated", zap.String("team", fmt.Sprint(t)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTeamResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteTeam is the HTTP handler for the DELETE /api/v2/teams/:id route.
+func (h *TeamHandler) handleDeleteTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("team delete request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TeamService.DeleteTeam(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("team deleted", zap.String("teamID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type teamResourceResponse struct {
+	Links map[string]string `json:"links"`
+	influxdb.UserResourceMapping
+}
+
+func newTeamResourceResponse(m *influxdb.UserResourceMapping) *teamResourceResponse {
+	return &teamResourceResponse{
+		Links: map[string]string{
+			"self":     fmt.Sprintf("/api/v2/teams/%s/resources/%s", m.UserID, m.ResourceID),
+			"team":     fmt.Sprintf("/api/v2/teams/%s", m.UserID),
+			"resource": fmt.Sprintf("/api/v2/%s/%s", m.ResourceType, m.ResourceID),
+		},
+		UserResourceMapping: *m,
+	}
+}
+
+type teamResourcesResponse struct {
+	Links     map[string]string       `json:"links"`
+	Resources []*teamResourceResponse `json:"resources"`
+}
+
+// handleGetTeamResources is the HTTP handler for the GET /api/v2/teams/:id/resources route.
+// It lists the resources the team is an owner or member of.
+func (h *TeamHandler) handleGetTeamResources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("team resources retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if _, err := h.TeamService.FindTeamByID(ctx, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	ms, _, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		UserID: id,
+	})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := &teamResourcesResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/teams/%s/resources", id),
+		},
+		Resources: []*teamResourceResponse{},
+	}
+	for _, m := range ms {
+		if m.MappingType != influxdb.TeamMappingType {
+			continue
+		}
+		res.Resources = append(res.Resources, newTeamResourceResponse(m))
+	}
+	h.Logger.Debug("team resources retrieved", zap.Int("resources", len(res.Resources)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// postTeamResourceRequest shares a resource with a team. The team is an owner
+// or member of the resource, as given by its user type.
+type postTeamResourceRequest struct {
+	ResourceType influxdb.ResourceType `json:"resourceType"`
+	ResourceID   influxdb.ID           `json:"resourceID"`
+	UserType     influxdb.UserType     `json:"userType"`
+}
+
+// handlePostTeamResource is the HTTP handler for the POST /api/v2/teams/:id/resources route.
+func (h *TeamHandler) handlePostTeamResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("team resource create request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var req postTeamResourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode team resource request",
+			Err:  err,
+		}, w)
+		return
+	}
+	if req.UserType == "" {
+		req.UserType = influxdb.Member
+	}
+
+	m := &influxdb.UserResourceMapping{
+		UserID:       id,
+		UserType:     req.UserType,
+		MappingType:  influxdb.TeamMappingType,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+	}
+	if err := m.Validate(); err != nil {
+		h.HandleHTTPError(ctx, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if err := h.UserResourceMappingService.CreateUserResourceMapping(ctx, m); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("team resource created", zap.String("mapping", fmt.Sprint(m)))
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newTeamResourceResponse(m)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteTeamResource is the HTTP handler for the DELETE /api/v2/teams/:id/resources/:resourceID route.
+func (h *TeamHandler) handleDeleteTeamResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("team resource delete request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	resourceID, err := decodeIDParam(ctx, "resourceID")
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.UserResourceMappingService.DeleteUserResourceMapping(ctx, resourceID, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("team resource deleted", zap.String("teamID", id.String()), zap.String("resourceID", resourceID.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newTestTeamHandler(ts platform.TeamService, urms platform.UserResourceMappingService) *TeamHandler {
+	return NewTeamHandler(&TeamBackend{
+		HTTPErrorHandler:           ErrorHandler(0),
+		Logger:                     zap.NewNop(),
+		TeamService:                ts,
+		OrganizationService:        mock.NewOrganizationService(),
+		UserResourceMappingService: urms,
+		UserService:                mock.NewUserService(),
+	})
+}
+
+func TestTeamHandler_handlePostTeam(t *testing.T) {
+	ts := mock.NewTeamService()
+	ts.CreateTeamFn = func(ctx context.Context, team *platform.Team) error {
+		team.ID = 2
+		return nil
+	}
+	h := newTestTeamHandler(ts, mock.NewUserResourceMappingService())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/teams", bytes.NewBufferString(`{"orgID": "0000000000000001", "name": "ops"}`)))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/teams/0000000000000002",
+    "members": "/api/v2/teams/0000000000000002/members",
+    "owners": "/api/v2/teams/0000000000000002/owners",
+    "resources": "/api/v2/teams/0000000000000002/resources",
+    "org": "/api/v2/orgs/0000000000000001"
+  },
+  "id": "0000000000000002",
+  "orgID": "0000000000000001",
+  "name": "ops",
+  "createdAt": "0001-01-01T00:00:00Z",
+  "updatedAt": "0001-01-01T00:00:00Z"
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	// A team needs a name.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/teams", bytes.NewBufferString(`{"orgID": "0000000000000001"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestTeamHandler_TeamResources(t *testing.T) {
+	ts := mock.NewTeamService()
+	ts.FindTeamByIDFn = func(ctx context.Context, id platform.ID) (*platform.Team, error) {
+		return &platform.Team{ID: id, OrgID: 1, Name: "ops"}, nil
+	}
+
+	var created *platform.UserResourceMapping
+	urms := mock.NewUserResourceMappingService()
+	urms.CreateMappingFn = func(ctx context.Context, m *platform.UserResourceMapping) error {
+		created = m
+		return nil
+	}
+	urms.FindMappingsFn = func(ctx context.Context, filter platform.UserResourceMappingFilter) ([]*platform.UserResourceMapping, int, error) {
+		return []*platform.UserResourceMapping{
+			{UserID: filter.UserID, UserType: platform.Member, MappingType: platform.TeamMappingType, ResourceType: platform.DashboardsResourceType, ResourceID: 3},
+			// The team is itself a member of the org; that is not a resource shared with the team.
+			{UserID: filter.UserID, UserType: platform.Member, MappingType: platform.UserMappingType, ResourceType: platform.OrgsResourceType, ResourceID: 1},
+		}, 2, nil
+	}
+	h := newTestTeamHandler(ts, urms)
+
+	// Sharing a resource with a team maps the team to the resource, as a member by default.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/teams/0000000000000002/resources", bytes.NewBufferString(`{"resourceType": "dashboards", "resourceID": "0000000000000003"}`)))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusCreated, body)
+	}
+	want := platform.UserResourceMapping{UserID: 2, UserType: platform.Member, MappingType: platform.TeamMappingType, ResourceType: platform.DashboardsResourceType, ResourceID: 3}
+	if created == nil || *created != want {
+		t.Errorf("got mapping %+v, want %+v", created, want)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/teams/0000000000000002/resources", nil))
+	body, _ = ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/teams/0000000000000002/resources"
+  },
+  "resources": [
+    {
+      "links": {
+        "self": "/api/v2/teams/0000000000000002/resources/0000000000000003",
+        "team": "/api/v2/teams/0000000000000002",
+        "resource": "/api/v2/dashboards/0000000000000003"
+      },
+      "userID": "0000000000000002",
+      "userType": "member",
+      "mappingType": "team",
+      "resourceType": "dashboards",
+      "resourceID": "0000000000000003"
+    }
+  ]
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	// Teams can not be shared with teams.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/teams/0000000000000002/resources", bytes.NewBufferString(`{"resourceType": "teams", "resourceID": "0000000000000004"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/golang/gddo/httputil"
@@ -22,6 +23,7 @@ type TelegrafBackend struct {
 	Logger *zap.Logger
 
 	TelegrafService            platform.TelegrafConfigStore
+	TelegrafAgentService       platform.TelegrafAgentService
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
@@ -35,6 +37,7 @@ func NewTelegrafBackend(b *APIBackend) *TelegrafBackend {
 		Logger:           b.Logger.With(zap.String("handler", "telegraf")),
 
 		TelegrafService:            b.TelegrafService,
+		TelegrafAgentService:       b.TelegrafAgentService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -49,6 +52,7 @@ type TelegrafHandler struct {
 	Logger *zap.Logger
 
 	TelegrafService            platform.TelegrafConfigStore
+	TelegrafAgentService       platform.TelegrafAgentService
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
@@ -64,6 +68,7 @@ const (
 	telegrafsIDOwnersIDPath  = "/api/v2/telegrafs/:id/owners/:userID"
 	telegrafsIDLabelsPath    = "/api/v2/telegrafs/:id/labels"
 	telegrafsIDLabelsIDPath  = "/api/v2/telegrafs/:id/labels/:lid"
+	telegrafsIDAgentsPath    = "/api/v2/telegrafs/:id/agents"
 )
 
 // NewTelegrafHandler returns a new instance of TelegrafHandler.
@@ -74,6 +79,7 @@ func NewTelegrafHandler(b *TelegrafBackend) *TelegrafHandler {
 		Logger:           b.Logger,
 
 		TelegrafService:            b.TelegrafService,
+		TelegrafAgentService:       b.TelegrafAgentService,
 		UserResourceMappingService: b.UserResourceMappingService,
 		LabelService:               b.LabelService,
 		UserService:                b.UserService,
@@ -84,6 +90,11 @@ func NewTelegrafHandler(b *TelegrafBackend) *TelegrafHandler {
 	h.HandlerFunc("GET", telegrafsIDPath, h.handleGetTelegraf)
 	h.HandlerFunc("DELETE", telegrafsIDPath, h.handleDeleteTelegraf)
 	h.HandlerFunc("PUT", telegrafsIDPath, h.handlePutTelegraf)
+	h.HandlerFunc("POST", telegrafsIDAgentsPath, h.handlePostTelegrafAgent)
+	h.HandlerFunc("GET", telegrafsIDAgentsPath, h.handleGetTelegrafAgents)
+
+	h.HandlerFunc("GET", telegrafPluginsPath, h.handleGetTelegrafPlugins)
+	h.HandlerFunc("POST", telegrafRenderPath, h.handlePostTelegrafRender)
 
 	memberBackend := MemberBackend{
 		HTTPErrorHandler:           b.HTTPErrorHandler,
@@ -248,6 +259,19 @@ func (h *TelegrafHandler) handleGetTelegrafs(w http.ResponseWriter, r *http.Requ
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+
+	if labels := decodeLabelFilter(r); len(labels) > 0 {
+		idx, err := labels.filter(ctx, h.LabelService, platform.FindOptions{}, len(tcs), func(i int) platform.ID { return tcs[i].ID })
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		labeled := make([]*platform.TelegrafConfig, 0, len(idx))
+		for _, i := range idx {
+			labeled = append(labeled, tcs[i])
+		}
+		tcs = labeled
+	}
 	h.Logger.Debug("telegrafs retrieved", zap.String("telegrafs", fmt.Sprint(tcs)))
 
 	if err := encodeResponse(ctx, w, http.StatusOK, newTelegrafResponses(ctx, tcs, h.LabelService)); err != nil {
@@ -271,6 +295,8 @@ func (h *TelegrafHandler) handleGetTelegraf(w http.ResponseWriter, r *http.Reque
 	}
 	h.Logger.Debug("telegraf retrieved", zap.String("telegraf", fmt.Sprint(tc)))
 
+	// Agents report the version of the config they downloaded when they check in.
+	w.Header().Set("ETag", strconv.Quote(tc.Version()))
 	offers := []string{"application/toml", "application/json", "application/octet-stream"}
 	defaultOffer := "application/toml"
 	mimeType := httputil.NegotiateContentType(r, offers, defaultOffer)
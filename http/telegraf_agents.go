@@ -0,0 +1,134 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	platform "github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+type telegrafAgentResponse struct {
+	platform.TelegrafAgent
+	// Stale reports whether the agent runs a version of the config other than its current one.
+	Stale bool `json:"stale"`
+}
+
+type telegrafAgentsResponse struct {
+	Links map[string]string `json:"links"`
+	// ConfigVersion is the current version of the telegraf config.
+	ConfigVersion string                   `json:"configVersion"`
+	Agents        []*telegrafAgentResponse `json:"agents"`
+}
+
+func newTelegrafAgentResponse(tc *platform.TelegrafConfig, a *platform.TelegrafAgent) *telegrafAgentResponse {
+	return &telegrafAgentResponse{
+		TelegrafAgent: *a,
+		Stale:         a.Stale(tc),
+	}
+}
+
+// handlePostTelegrafAgent is the HTTP handler for the POST /api/v2/telegrafs/:id/agents route.
+// Telegraf agents check in with the telegraf config they run to report their state, and are told
+// whether the config they run is stale.
+func (h *TelegrafHandler) handlePostTelegrafAgent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("telegraf agent check in request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeGetTelegrafRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	a := &platform.TelegrafAgent{}
+	if err := json.NewDecoder(r.Body).Decode(a); err != nil {
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "unable to decode telegraf agent",
+			Err:  err,
+		}, w)
+		return
+	}
+	a.ConfigID = id
+
+	tc, err := h.TelegrafService.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TelegrafAgentService.CheckInTelegrafAgent(ctx, a); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("telegraf agent checked in", zap.String("telegrafID", id.String()), zap.String("hostname", a.Hostname))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTelegrafAgentResponse(tc, a)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetTelegrafAgents is the HTTP handler for the GET /api/v2/telegrafs/:id/agents route.
+// It returns the agents that checked in with the telegraf config, optionally only those that run a stale config.
+func (h *TelegrafHandler) handleGetTelegrafAgents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("telegraf agents retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	id, err := decodeGetTelegrafRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	var stale *bool
+	if s := r.URL.Query().Get("stale"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			h.HandleHTTPError(ctx, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "stale must be true or false",
+				Err:  err,
+			}, w)
+			return
+		}
+		stale = &b
+	}
+
+	tc, err := h.TelegrafService.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	as, err := h.TelegrafAgentService.FindTelegrafAgents(ctx, platform.TelegrafAgentFilter{ConfigID: &id})
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := &telegrafAgentsResponse{
+		Links: map[string]string{
+			"self":     fmt.Sprintf("/api/v2/telegrafs/%s/agents", id),
+			"telegraf": fmt.Sprintf("/api/v2/telegrafs/%s", id),
+		},
+		ConfigVersion: tc.Version(),
+		Agents:        []*telegrafAgentResponse{},
+	}
+	for _, a := range as {
+		ar := newTelegrafAgentResponse(tc, a)
+		if stale != nil && ar.Stale != *stale {
+			continue
+		}
+		res.Agents = append(res.Agents, ar)
+	}
+	h.Logger.Debug("telegraf agents retrieved", zap.String("telegrafID", id.String()), zap.Int("agents", len(res.Agents)))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
+)
+
+func TestTelegrafHandler_handleTelegrafAgents(t *testing.T) {
+	tc := &platform.TelegrafConfig{ID: 1, OrgID: 2, Name: "hosts", Plugins: []platform.TelegrafPlugin{{Config: &inputs.CPUStats{}}}}
+	agents := map[string]*platform.TelegrafAgent{}
+
+	b := NewMockTelegrafBackend()
+	b.HTTPErrorHandler = ErrorHandler(0)
+	b.TelegrafService = &mock.TelegrafConfigStore{
+		FindTelegrafConfigByIDF: func(ctx context.Context, id platform.ID) (*platform.TelegrafConfig, error) {
+			if id != tc.ID {
+				return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrTelegrafConfigNotFound}
+			}
+			return tc, nil
+		},
+	}
+	as := mock.NewTelegrafAgentService()
+	as.CheckInTelegrafAgentFn = func(ctx context.Context, a *platform.TelegrafAgent) error {
+		a.OrgID = tc.OrgID
+		agents[a.Hostname] = a
+		return nil
+	}
+	as.FindTelegrafAgentsFn = func(ctx context.Context, filter platform.TelegrafAgentFilter) ([]*platform.TelegrafAgent, error) {
+		if filter.ConfigID == nil || *filter.ConfigID != tc.ID {
+			t.Errorf("expected the agents of the config, got %+v", filter)
+		}
+		return []*platform.TelegrafAgent{agents["db1"], agents["web1"]}, nil
+	}
+	b.TelegrafAgentService = as
+	h := NewTelegrafHandler(b)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/telegrafs/0000000000000001", nil))
+	version, err := strconv.Unquote(w.Header().Get("ETag"))
+	if err != nil || version != tc.Version() {
+		t.Fatalf("expected the config to be versioned, got %q", w.Header().Get("ETag"))
+	}
+
+	for _, body := range []string{
+		`{"hostname": "web1", "version": "1.11.0", "configVersion": "` + version + `"}`,
+		`{"hostname": "db1", "version": "1.10.4", "configVersion": "0123456789abcdef", "lastError": "connection refused"}`,
+	} {
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/telegrafs/0000000000000001/agents", bytes.NewBufferString(body)))
+		resBody, _ := ioutil.ReadAll(w.Result().Body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, resBody)
+		}
+	}
+	if agents["db1"].ConfigID != tc.ID || agents["db1"].LastError != "connection refused" {
+		t.Errorf("unexpected agent %+v", agents["db1"])
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/telegrafs/0000000000000001/agents?stale=true", nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	var res telegrafAgentsResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.ConfigVersion != version || len(res.Agents) != 1 || res.Agents[0].Hostname != "db1" || !res.Agents[0].Stale {
+		t.Errorf("expected db1 to run a stale config, got %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/telegrafs/0000000000000003/agents", bytes.NewBufferString(`{"hostname": "web1"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("got status %d for a missing config, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/gddo/httputil"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/telegraf/plugins"
+	"go.uber.org/zap"
+)
+
+const (
+	telegrafPluginsPath = "/api/v2/telegraf/plugins"
+	telegrafRenderPath  = "/api/v2/telegraf/render"
+)
+
+// telegrafRenderTokenDefault is the token of rendered configs that are given none;
+// telegraf reads it from the environment so that the token is not kept in the config.
+const telegrafRenderTokenDefault = "$INFLUX_TOKEN"
+
+type telegrafPluginsResponse struct {
+	Links   map[string]string             `json:"links"`
+	Plugins []platform.TelegrafPluginInfo `json:"plugins"`
+}
+
+// handleGetTelegrafPlugins is the HTTP handler for the GET /api/v2/telegraf/plugins route.
+// It returns the catalog of the plugins telegraf configs may contain, optionally of a single type.
+func (h *TelegrafHandler) handleGetTelegrafPlugins(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("telegraf plugins retrieve request", zap.String("r", fmt.Sprint(r)))
+
+	typ := plugins.Type(r.URL.Query().Get("type"))
+	switch typ {
+	case "", plugins.Input, plugins.Output, plugins.Processor, plugins.Aggregator:
+	default:
+		h.HandleHTTPError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf(platform.ErrUnsupportTelegrafPluginType, typ),
+		}, w)
+		return
+	}
+
+	res := &telegrafPluginsResponse{
+		Links: map[string]string{
+			"self":   telegrafPluginsPath,
+			"render": telegrafRenderPath,
+		},
+		Plugins: []platform.TelegrafPluginInfo{},
+	}
+	for _, p := range platform.TelegrafPlugins() {
+		if typ == "" || p.Type == typ {
+			res.Plugins = append(res.Plugins, p)
+		}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type postTelegrafRenderRequest struct {
+	Config   platform.TelegrafConfig
+	Defaults platform.TelegrafOutputDefaults
+}
+
+// decodePostTelegrafRenderRequest decodes a telegraf config along with the bucket, token and url
+// its influxdb_v2 output writes with. The url defaults to the url of this server.
+func decodePostTelegrafRenderRequest(r *http.Request) (*postTelegrafRenderRequest, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "unable to read telegraf render request",
+			Err:  err,
+		}
+	}
+
+	req := &postTelegrafRenderRequest{}
+	if err := json.Unmarshal(body, &req.Config); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "unable to decode telegraf render request",
+			Err:  err,
+		}
+	}
+	var output struct {
+		Bucket string `json:"bucket"`
+		Token  string `json:"token"`
+		URL    string `json:"url"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&output); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "unable to decode telegraf render request",
+			Err:  err,
+		}
+	}
+
+	if !req.Config.OrgID.Valid() {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  platform.ErrTelegrafConfigInvalidOrgID,
+		}
+	}
+
+	req.Defaults = platform.TelegrafOutputDefaults{
+		URL:    output.URL,
+		Token:  output.Token,
+		Bucket: output.Bucket,
+	}
+	if req.Defaults.URL == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		req.Defaults.URL = scheme + "://" + r.Host
+	}
+	if req.Defaults.Token == "" {
+		req.Defaults.Token = telegrafRenderTokenDefault
+	}
+	return req, nil
+}
+
+// handlePostTelegrafRender is the HTTP handler for the POST /api/v2/telegraf/render route.
+// It returns the telegraf.conf of a telegraf config without storing it, with an influxdb_v2
+// output that writes to the organization of the config.
+func (h *TelegrafHandler) handlePostTelegrafRender(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("telegraf render request", zap.String("r", fmt.Sprint(r)))
+
+	req, err := decodePostTelegrafRenderRequest(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	org, err := h.OrganizationService.FindOrganizationByID(ctx, req.Config.OrgID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	req.Defaults.Organization = org.Name
+
+	conf, err := platform.RenderTelegrafConfig(req.Config, req.Defaults)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("telegraf rendered", zap.String("orgID", org.ID.String()))
+
+	offers := []string{"application/toml", "application/octet-stream"}
+	switch httputil.NegotiateContentType(r, offers, "application/toml") {
+	case "application/octet-stream":
+		name := strings.Replace(strings.TrimSpace(req.Config.Name), " ", "_", -1)
+		if name == "" {
+			name = "telegraf"
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.toml\"", name))
+	default:
+		w.Header().Set("Content-Type", "application/toml; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(conf))
+}
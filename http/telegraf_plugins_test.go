@@ -0,0 +1,91 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestTelegrafHandler_handleGetTelegrafPlugins(t *testing.T) {
+	b := NewMockTelegrafBackend()
+	b.HTTPErrorHandler = ErrorHandler(0)
+	h := NewTelegrafHandler(b)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/telegraf/plugins?type=output", nil))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	var res telegrafPluginsResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Plugins) != 2 || res.Plugins[0].Name != "file" || res.Plugins[1].Name != "influxdb_v2" {
+		t.Errorf("expected the output plugins, got %+v", res.Plugins)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/telegraf/plugins?type=parser", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestTelegrafHandler_handlePostTelegrafRender(t *testing.T) {
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationByIDF = func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+		if id != 2 {
+			return nil, &platform.Error{Code: platform.ENotFound, Msg: "organization not found"}
+		}
+		return &platform.Organization{ID: id, Name: "acme"}, nil
+	}
+	b := NewMockTelegrafBackend()
+	b.HTTPErrorHandler = ErrorHandler(0)
+	b.OrganizationService = orgs
+	h := NewTelegrafHandler(b)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://localhost:9999/api/v2/telegraf/render", bytes.NewBufferString(`
+{
+  "orgID": "0000000000000002",
+  "agent": {"collectionInterval": 15000},
+  "plugins": [{"name": "cpu", "type": "input"}],
+  "bucket": "hosts"
+}`)))
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/toml; charset=utf-8" {
+		t.Errorf("got content type %q", ct)
+	}
+	for _, s := range []string{`interval = "15s"`, "[[inputs.cpu]]", `urls = ["http://localhost:9999"]`, `token = "$INFLUX_TOKEN"`, `organization = "acme"`, `bucket = "hosts"`} {
+		if !strings.Contains(string(body), s) {
+			t.Errorf("expected the rendered config to contain %s, got:\n%s", s, body)
+		}
+	}
+
+	for _, tt := range []struct {
+		body       string
+		wantStatus int
+	}{
+		{body: `{"plugins": [{"name": "cpu", "type": "input"}], "bucket": "hosts"}`, wantStatus: http.StatusBadRequest},
+		{body: `{"orgID": "0000000000000002", "plugins": [{"name": "cpu", "type": "input"}]}`, wantStatus: http.StatusBadRequest},
+		{body: `{"orgID": "0000000000000003", "plugins": [{"name": "cpu", "type": "input"}], "bucket": "hosts"}`, wantStatus: http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "http://localhost:9999/api/v2/telegraf/render", bytes.NewBufferString(tt.body)))
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: got status %d, want %d", tt.body, w.Code, tt.wantStatus)
+		}
+	}
+}
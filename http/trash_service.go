@@ -0,0 +1,303 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+const (
+	trashPath                  = "/api/v2/trash"
+	trashedResourcePath        = "/api/v2/trash/:type/:id"
+	trashedResourceRestorePath = "/api/v2/trash/:type/:id/restore"
+)
+
+// TrashBackend is all services and associated parameters required to construct
+// the TrashHandler.
+type TrashBackend struct {
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	TrashService        platform.TrashService
+	OrganizationService platform.OrganizationService
+}
+
+// NewTrashBackend returns a new instance of TrashBackend.
+func NewTrashBackend(b *APIBackend) *TrashBackend {
+	return &TrashBackend{
+		HTTPErrorHandler:    b.HTTPErrorHandler,
+		Logger:              b.Logger.With(zap.String("handler", "trash")),
+		TrashService:        b.TrashService,
+		OrganizationService: b.OrganizationService,
+	}
+}
+
+// TrashHandler represents an HTTP API handler for deleted resources.
+type TrashHandler struct {
+	*httprouter.Router
+	platform.HTTPErrorHandler
+	Logger *zap.Logger
+
+	TrashService        platform.TrashService
+	OrganizationService platform.OrganizationService
+}
+
+// NewTrashHandler returns a new instance of TrashHandler.
+func NewTrashHandler(b *TrashBackend) *TrashHandler {
+	h := &TrashHandler{
+		Router:           NewRouter(b.HTTPErrorHandler),
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger,
+
+		TrashService:        b.TrashService,
+		OrganizationService: b.OrganizationService,
+	}
+
+	h.HandlerFunc("GET", trashPath, h.handleGetTrash)
+	h.HandlerFunc("GET", trashedResourcePath, h.handleGetTrashedResource)
+	h.HandlerFunc("DELETE", trashedResourcePath, h.handleDeleteTrashedResource)
+	h.HandlerFunc("POST", trashedResourceRestorePath, h.handlePostTrashedResourceRestore)
+
+	return h
+}
+
+type trashedResourceResponse struct {
+	*platform.TrashedResource
+	Links map[string]string `json:"links"`
+}
+
+func newTrashedResourceResponse(r *platform.TrashedResource) trashedResourceResponse {
+	self := path.Join(trashPath, string(r.Type), r.ID.String())
+	return trashedResourceResponse{
+		TrashedResource: r,
+		Links: map[string]string{
+			"self":    self,
+			"restore": path.Join(self, "restore"),
+			"org":     fmt.Sprintf("/api/v2/orgs/%s", r.OrgID),
+		},
+	}
+}
+
+type trashResponse struct {
+	Links     *platform.PagingLinks     `json:"links"`
+	Resources []trashedResourceResponse `json:"resources"`
+}
+
+func newTrashResponse(opts platform.FindOptions, f platform.TrashFilter, rs []*platform.TrashedResource) trashResponse {
+	res := trashResponse{
+		Links:     newPagingLinks(trashPath, opts, f, len(rs)),
+		Resources: make([]trashedResourceResponse, 0, len(rs)),
+	}
+	for _, r := range rs {
+		res.Resources = append(res.Resources, newTrashedResourceResponse(r))
+	}
+	return res
+}
+
+// handleGetTrash is the HTTP handler for the GET /api/v2/trash route.
+func (h *TrashHandler) handleGetTrash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := h.decodeGetTrashRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	rs, _, err := h.TrashService.FindTrashedResources(ctx, req.filter, req.opts)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res := newTrashResponse(req.opts, req.filter, rs)
+	if err := encodeListResponse(ctx, w, req.opts.Fields, res.Links, "resources", res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+type getTrashRequest struct {
+	filter platform.TrashFilter
+	opts   platform.FindOptions
+}
+
+func (h *TrashHandler) decodeGetTrashRequest(ctx context.Context, r *http.Request) (*getTrashRequest, error) {
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	// The most recently deleted resources are listed first.
+	if opts.SortBy != "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "trashed resources cannot be sorted",
+		}
+	}
+
+	req := &getTrashRequest{
+		opts: *opts,
+	}
+
+	qp := r.URL.Query()
+	if orgID := qp.Get("orgID"); orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = id
+	} else if org := qp.Get("org"); org != "" {
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &org})
+		if err != nil {
+			return nil, err
+		}
+		req.filter.OrgID = &o.ID
+	}
+
+	if typ := qp.Get("type"); typ != "" {
+		rt := platform.ResourceType(typ)
+		req.filter.Type = &rt
+	}
+
+	if err := req.filter.Valid(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// handleGetTrashedResource is the HTTP handler for the GET /api/v2/trash/:type/:id route.
+func (h *TrashHandler) handleGetTrashedResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	typ, id, err := decodeTrashedResourceParams(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	tr, err := h.TrashService.FindTrashedResource(ctx, typ, id)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newTrashedResourceResponse(tr)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteTrashedResource is the HTTP handler for the DELETE /api/v2/trash/:type/:id route.
+func (h *TrashHandler) handleDeleteTrashedResource(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	typ, id, err := decodeTrashedResourceParams(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TrashService.PurgeTrashedResource(ctx, typ, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("trashed resource purged", zap.String("resourceType", string(typ)), zap.String("resourceID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePostTrashedResourceRestore is the HTTP handler for the POST /api/v2/trash/:type/:id/restore route.
+func (h *TrashHandler) handlePostTrashedResourceRestore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	typ, id, err := decodeTrashedResourceParams(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if err := h.TrashService.RestoreTrashedResource(ctx, typ, id); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("trashed resource restored", zap.String("resourceType", string(typ)), zap.String("resourceID", id.String()))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeTrashedResourceParams(ctx context.Context) (platform.ResourceType, platform.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	typ := platform.ResourceType(params.ByName("type"))
+	if err := (platform.TrashFilter{Type: &typ}).Valid(); err != nil {
+		return "", 0, err
+	}
+
+	id, err := decodeIDParam(ctx, "id")
+	if err != nil {
+		return "", 0, err
+	}
+	return typ, id, nil
+}
+
+// TrashService connects to Influx via HTTP using tokens to manage deleted resources.
+type TrashService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.TrashService = (*TrashService)(nil)
+
+// FindTrashedResource returns a single trashed resource by its type and ID.
+func (s *TrashService) FindTrashedResource(ctx context.Context, typ platform.ResourceType, id platform.ID) (*platform.TrashedResource, error) {
+	var res trashedResourceResponse
+	if err := s.client().do(ctx, "GET", path.Join(trashPath, string(typ), id.String()), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.TrashedResource, nil
+}
+
+// FindTrashedResources returns the trashed resources that match filter, most
+// recently deleted first.
+func (s *TrashService) FindTrashedResources(ctx context.Context, filter platform.TrashFilter, opt ...platform.FindOptions) ([]*platform.TrashedResource, int, error) {
+	qp := url.Values(filter.QueryParams())
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			for _, v := range vs {
+				qp.Add(k, v)
+			}
+		}
+	}
+
+	var res trashResponse
+	if err := s.client().do(ctx, "GET", trashPath, qp, nil, &res); err != nil {
+		return nil, 0, err
+	}
+
+	rs := make([]*platform.TrashedResource, 0, len(res.Resources))
+	for _, r := range res.Resources {
+		rs = append(rs, r.TrashedResource)
+	}
+	return rs, len(rs), nil
+}
+
+// RestoreTrashedResource moves a resource out of the trash.
+func (s *TrashService) RestoreTrashedResource(ctx context.Context, typ platform.ResourceType, id platform.ID) error {
+	return s.client().do(ctx, "POST", path.Join(trashPath, string(typ), id.String(), "restore"), nil, nil, nil)
+}
+
+// PurgeTrashedResource permanently deletes a trashed resource.
+func (s *TrashService) PurgeTrashedResource(ctx context.Context, typ platform.ResourceType, id platform.ID) error {
+	return s.client().do(ctx, "DELETE", path.Join(trashPath, string(typ), id.String()), nil, nil, nil)
+}
+
+func (s *TrashService) client() apiClient {
+	return apiClient{Addr: s.Addr, Token: s.Token, InsecureSkipVerify: s.InsecureSkipVerify}
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func newTestTrashHandler(ts platform.TrashService) *TrashHandler {
+	return NewTrashHandler(&TrashBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		TrashService:        ts,
+		OrganizationService: mock.NewOrganizationService(),
+	})
+}
+
+func TestTrashHandler_handleGetTrash(t *testing.T) {
+	deletedAt := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	var got platform.TrashFilter
+	ts := mock.NewTrashService()
+	ts.FindTrashedResourcesFn = func(ctx context.Context, filter platform.TrashFilter, opts ...platform.FindOptions) ([]*platform.TrashedResource, int, error) {
+		got = filter
+		return []*platform.TrashedResource{
+			{Type: platform.DashboardsResourceType, ID: 2, OrgID: 1, Name: "overview", DeletedAt: deletedAt, ExpiresAt: deletedAt.Add(time.Hour)},
+		}, 1, nil
+	}
+	h := newTestTrashHandler(ts)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/trash?orgID=0000000000000001", nil))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, body)
+	}
+	if got.OrgID == nil || *got.OrgID != 1 || got.Type != nil {
+		t.Errorf("got filter %+v", got)
+	}
+	if eq, diff, err := jsonEqual(string(body), `
+{
+  "links": {
+    "self": "/api/v2/trash?descending=false&limit=20&offset=0&orgID=0000000000000001"
+  },
+  "resources": [
+    {
+      "links": {
+        "self": "/api/v2/trash/dashboards/0000000000000002",
+        "restore": "/api/v2/trash/dashboards/0000000000000002/restore",
+        "org": "/api/v2/orgs/0000000000000001"
+      },
+      "type": "dashboards",
+      "id": "0000000000000002",
+      "orgID": "0000000000000001",
+      "name": "overview",
+      "deletedAt": "2019-07-01T12:00:00Z",
+      "expiresAt": "2019-07-01T13:00:00Z"
+    }
+  ]
+}`); err != nil {
+		t.Errorf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("***%s***", diff)
+	}
+
+	// Only buckets, dashboards and tasks are moved to the trash.
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "http://any.url/api/v2/trash?type=labels", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestTrashHandler_handlePostTrashedResourceRestore(t *testing.T) {
+	var restored platform.ID
+	ts := mock.NewTrashService()
+	ts.RestoreTrashedResourceFn = func(ctx context.Context, typ platform.ResourceType, id platform.ID) error {
+		if typ != platform.BucketsResourceType {
+			t.Errorf("got resource type %q, want buckets", typ)
+		}
+		restored = id
+		return nil
+	}
+	h := newTestTrashHandler(ts)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "http://any.url/api/v2/trash/buckets/0000000000000002/restore", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if restored != 2 {
+		t.Errorf("got restored resource %s, want 0000000000000002", restored)
+	}
+}
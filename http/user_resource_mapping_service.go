@@ -168,7 +168,7 @@ func newGetMembersHandler(b MemberBackend) http.HandlerFunc {
 
 		users := make([]*platform.User, 0, len(mappings))
 		for _, m := range mappings {
-			if m.MappingType == platform.OrgMappingType {
+			if m.MappingType != platform.UserMappingType {
 				continue
 			}
 			user, err := b.UserService.FindUserByID(ctx, m.UserID)
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+const usersResourcesPath = "/api/v2/users/:id/resources"
+
+// userResource is a resource owned by a user.
+type userResource struct {
+	ID    influxdb.ID       `json:"id"`
+	Name  string            `json:"name"`
+	OrgID influxdb.ID       `json:"orgID"`
+	Links map[string]string `json:"links"`
+}
+
+// userResourcesResponse lists the resources a user owns, by type.
+type userResourcesResponse struct {
+	Links          map[string]string `json:"links"`
+	UserID         influxdb.ID       `json:"userID"`
+	Dashboards     []userResource    `json:"dashboards"`
+	Tasks          []userResource    `json:"tasks"`
+	Buckets        []userResource    `json:"buckets"`
+	Authorizations []userResource    `json:"authorizations"`
+}
+
+func newUserResourcesResponse(id influxdb.ID) *userResourcesResponse {
+	return &userResourcesResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/users/%s/resources", id),
+		},
+		UserID:         id,
+		Dashboards:     []userResource{},
+		Tasks:          []userResource{},
+		Buckets:        []userResource{},
+		Authorizations: []userResource{},
+	}
+}
+
+// handleGetUserResources is the HTTP handler for the GET /api/v2/users/:id/resources route.
+// It lists the dashboards, tasks and buckets the user owns, and the user's tokens.
+func (h *UserHandler) handleGetUserResources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	h.Logger.Debug("user resources retrieve request", zap.String("r", fmt.Sprint(r)))
+	req, err := decodeGetUserRequest(ctx, r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if _, err := h.UserService.FindUserByID(ctx, req.UserID); err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	res, err := h.findUserResources(ctx, req.UserID)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	h.Logger.Debug("user resources retrieved", zap.String("userID", req.UserID.String()))
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// findUserResources finds the resources the user owns through user resource mappings.
+// Tokens are not mapped to users that way, so they are found by their user instead.
+func (h *UserHandler) findUserResources(ctx context.Context, id influxdb.ID) (*userResourcesResponse, error) {
+	mappings, _, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		UserID:   id,
+		UserType: influxdb.Owner,
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+			Msg: "failed to find user resource mappings",
+		}
+	}
+
+	res := newUserResourcesResponse(id)
+	for _, m := range mappings {
+		var err error
+		switch m.ResourceType {
+		case influxdb.DashboardsResourceType:
+			var d *influxdb.Dashboard
+			if d, err = h.DashboardService.FindDashboardByID(ctx, m.ResourceID); err == nil {
+				res.Dashboards = append(res.Dashboards, userResource{
+					ID:    d.ID,
+					Name:  d.Name,
+					OrgID: d.OrganizationID,
+					Links: map[string]string{"self": fmt.Sprintf("/api/v2/dashboards/%s", d.ID)},
+				})
+			}
+		case influxdb.TasksResourceType:
+			var t *influxdb.Task
+			if t, err = h.TaskService.FindTaskByID(ctx, m.ResourceID); err == nil {
+				res.Tasks = append(res.Tasks, userResource{
+					ID:    t.ID,
+					Name:  t.Name,
+					OrgID: t.OrganizationID,
+					Links: map[string]string{"self": fmt.Sprintf("/api/v2/tasks/%s", t.ID)},
+				})
+			}
+		case influxdb.BucketsResourceType:
+			var b *influxdb.Bucket
+			if b, err = h.BucketService.FindBucketByID(ctx, m.ResourceID); err == nil {
+				res.Buckets = append(res.Buckets, userResource{
+					ID:    b.ID,
+					Name:  b.Name,
+					OrgID: b.OrgID,
+					Links: map[string]string{"self": fmt.Sprintf("/api/v2/buckets/%s", b.ID)},
+				})
+			}
+		}
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			// The mapping may outlive its resource.
+			h.Logger.Info("Skipping mapping without resource",
+				zap.String("resourceType", string(m.ResourceType)), zap.String("resourceID", m.ResourceID.String()))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	auths, _, err := h.AuthorizationService.FindAuthorizations(ctx, influxdb.AuthorizationFilter{UserID: &id})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+			Msg: "failed to find user authorizations",
+		}
+	}
+	for _, a := range auths {
+		res.Authorizations = append(res.Authorizations, userResource{
+			ID:    a.ID,
+			Name:  a.Description,
+			OrgID: a.OrgID,
+			Links: map[string]string{"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID)},
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,160 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestUserHandler_handleGetUserResources(t *testing.T) {
+	userID := platformtesting.MustIDBase16("020f755c3c082000")
+	orgID := platformtesting.MustIDBase16("020f755c3c083000")
+
+	tests := []struct {
+		name       string
+		userExists bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "lists owned resources by type",
+			userExists: true,
+			wantStatus: http.StatusOK,
+			wantBody: `
+{
+  "links": {
+    "self": "/api/v2/users/020f755c3c082000/resources"
+  },
+  "userID": "020f755c3c082000",
+  "dashboards": [
+    {
+      "id": "0000000000000001",
+      "name": "hosts",
+      "orgID": "020f755c3c083000",
+      "links": {
+        "self": "/api/v2/dashboards/0000000000000001"
+      }
+    }
+  ],
+  "tasks": [
+    {
+      "id": "0000000000000002",
+      "name": "downsample",
+      "orgID": "020f755c3c083000",
+      "links": {
+        "self": "/api/v2/tasks/0000000000000002"
+      }
+    }
+  ],
+  "buckets": [
+    {
+      "id": "0000000000000003",
+      "name": "telegraf",
+      "orgID": "020f755c3c083000",
+      "links": {
+        "self": "/api/v2/buckets/0000000000000003"
+      }
+    }
+  ],
+  "authorizations": [
+    {
+      "id": "0000000000000005",
+      "name": "telegraf token",
+      "orgID": "020f755c3c083000",
+      "links": {
+        "self": "/api/v2/authorizations/0000000000000005"
+      }
+    }
+  ]
+}
+`,
+		},
+		{
+			name:       "user not found",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewMockUserBackend()
+			b.HTTPErrorHandler = ErrorHandler(0)
+			b.UserService = &mock.UserService{
+				FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+					if !tt.userExists {
+						return nil, &platform.Error{Code: platform.ENotFound, Msg: "user not found"}
+					}
+					return &platform.User{ID: id, Name: "jdoe"}, nil
+				},
+			}
+			b.UserResourceMappingService = &mock.UserResourceMappingService{
+				FindMappingsFn: func(ctx context.Context, f platform.UserResourceMappingFilter) ([]*platform.UserResourceMapping, int, error) {
+					if f.UserID != userID || f.UserType != platform.Owner {
+						t.Errorf("unexpected filter %+v", f)
+					}
+					ms := []*platform.UserResourceMapping{
+						{UserID: userID, UserType: platform.Owner, ResourceType: platform.DashboardsResourceType, ResourceID: 1},
+						{UserID: userID, UserType: platform.Owner, ResourceType: platform.TasksResourceType, ResourceID: 2},
+						{UserID: userID, UserType: platform.Owner, ResourceType: platform.BucketsResourceType, ResourceID: 3},
+						{UserID: userID, UserType: platform.Owner, ResourceType: platform.DashboardsResourceType, ResourceID: 4},
+						{UserID: userID, UserType: platform.Owner, ResourceType: platform.OrgsResourceType, ResourceID: orgID},
+					}
+					return ms, len(ms), nil
+				},
+			}
+			dashboards := mock.NewDashboardService()
+			dashboards.FindDashboardByIDF = func(ctx context.Context, id platform.ID) (*platform.Dashboard, error) {
+				if id != 1 {
+					return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrDashboardNotFound}
+				}
+				return &platform.Dashboard{ID: id, OrganizationID: orgID, Name: "hosts"}, nil
+			}
+			b.DashboardService = dashboards
+			b.TaskService = &mock.TaskService{
+				FindTaskByIDFn: func(ctx context.Context, id platform.ID) (*platform.Task, error) {
+					return &platform.Task{ID: id, OrganizationID: orgID, Name: "downsample"}, nil
+				},
+			}
+			buckets := mock.NewBucketService()
+			buckets.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: id, OrgID: orgID, Name: "telegraf"}, nil
+			}
+			b.BucketService = buckets
+			auths := mock.NewAuthorizationService()
+			auths.FindAuthorizationsFn = func(ctx context.Context, f platform.AuthorizationFilter, opts ...platform.FindOptions) ([]*platform.Authorization, int, error) {
+				if f.UserID == nil || *f.UserID != userID {
+					t.Errorf("unexpected filter %+v", f)
+				}
+				return []*platform.Authorization{
+					{ID: 5, Token: "secret", OrgID: orgID, UserID: userID, Description: "telegraf token"},
+				}, 1, nil
+			}
+			b.AuthorizationService = auths
+			h := NewUserHandler(b)
+
+			r := httptest.NewRequest("GET", "/api/v2/users/020f755c3c082000/resources", nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body, _ := ioutil.ReadAll(res.Body)
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("got status %d, want %d: %s", res.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(string(body), tt.wantBody); err != nil {
+				t.Fatal(err)
+			} else if !eq {
+				t.Errorf("unexpected body -got/+want\n%s", diff)
+			}
+		})
+	}
+}
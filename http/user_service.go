@@ -22,6 +22,14 @@ type UserBackend struct {
 	UserService             influxdb.UserService
 	UserOperationLogService influxdb.UserOperationLogService
 	PasswordsService        influxdb.PasswordsService
+	PasswordResetService    influxdb.PasswordResetService
+
+	UserResourceMappingService influxdb.UserResourceMappingService
+	DashboardService           influxdb.DashboardService
+	TaskService                influxdb.TaskService
+	BucketService              influxdb.BucketService
+	AuthorizationService       influxdb.AuthorizationService
+	LabelService               influxdb.LabelService
 }
 
 // NewUserBackend creates a UserBackend using information in the APIBackend.
@@ -32,6 +40,14 @@ func NewUserBackend(b *APIBackend) *UserBackend {
 		UserService:             b.UserService,
 		UserOperationLogService: b.UserOperationLogService,
 		PasswordsService:        b.PasswordsService,
+		PasswordResetService:    b.PasswordResetService,
+
+		UserResourceMappingService: b.UserResourceMappingService,
+		DashboardService:           b.DashboardService,
+		TaskService:                b.TaskService,
+		BucketService:              b.BucketService,
+		AuthorizationService:       b.AuthorizationService,
+		LabelService:               b.LabelService,
 	}
 }
 
@@ -43,15 +59,25 @@ type UserHandler struct {
 	UserService             influxdb.UserService
 	UserOperationLogService influxdb.UserOperationLogService
 	PasswordsService        influxdb.PasswordsService
+	PasswordResetService    influxdb.PasswordResetService
+
+	UserResourceMappingService influxdb.UserResourceMappingService
+	DashboardService           influxdb.DashboardService
+	TaskService                influxdb.TaskService
+	BucketService              influxdb.BucketService
+	AuthorizationService       influxdb.AuthorizationService
+	LabelService               influxdb.LabelService
 }
 
 const (
-	usersPath         = "/api/v2/users"
-	mePath            = "/api/v2/me"
-	mePasswordPath    = "/api/v2/me/password"
-	usersIDPath       = "/api/v2/users/:id"
-	usersPasswordPath = "/api/v2/users/:id/password"
-	usersLogPath      = "/api/v2/users/:id/logs"
+	usersPath           = "/api/v2/users"
+	mePath              = "/api/v2/me"
+	mePasswordPath      = "/api/v2/me/password"
+	usersIDPath         = "/api/v2/users/:id"
+	usersPasswordPath   = "/api/v2/users/:id/password"
+	usersLogPath        = "/api/v2/users/:id/logs"
+	usersIDLabelsPath   = "/api/v2/users/:id/labels"
+	usersIDLabelsIDPath = "/api/v2/users/:id/labels/:lid"
 )
 
 // NewUserHandler returns a new instance of UserHandler.
@@ -64,19 +90,39 @@ func NewUserHandler(b *UserBackend) *UserHandler {
 		UserService:             b.UserService,
 		UserOperationLogService: b.UserOperationLogService,
 		PasswordsService:        b.PasswordsService,
+		PasswordResetService:    b.PasswordResetService,
+
+		UserResourceMappingService: b.UserResourceMappingService,
+		DashboardService:           b.DashboardService,
+		TaskService:                b.TaskService,
+		BucketService:              b.BucketService,
+		AuthorizationService:       b.AuthorizationService,
+		LabelService:               b.LabelService,
 	}
 
 	h.HandlerFunc("POST", usersPath, h.handlePostUser)
 	h.HandlerFunc("GET", usersPath, h.handleGetUsers)
 	h.HandlerFunc("GET", usersIDPath, h.handleGetUser)
 	h.HandlerFunc("GET", usersLogPath, h.handleGetUserLog)
+	h.HandlerFunc("GET", usersResourcesPath, h.handleGetUserResources)
 	h.HandlerFunc("PATCH", usersIDPath, h.handlePatchUser)
 	h.HandlerFunc("DELETE", usersIDPath, h.handleDeleteUser)
 	h.HandlerFunc("PUT", usersPasswordPath, h.handlePutUserPassword)
+	h.HandlerFunc("POST", usersPasswordResetPath, h.handlePostUserPasswordReset)
 
 	h.HandlerFunc("GET", mePath, h.handleGetMe)
 	h.HandlerFunc("PUT", mePasswordPath, h.handlePutUserPassword)
 
+	labelBackend := &LabelBackend{
+		HTTPErrorHandler: b.HTTPErrorHandler,
+		Logger:           b.Logger.With(zap.String("handler", "label")),
+		LabelService:     b.LabelService,
+		ResourceType:     influxdb.UsersResourceType,
+	}
+	h.HandlerFunc("GET", usersIDLabelsPath, newGetLabelsHandler(labelBackend))
+	h.HandlerFunc("POST", usersIDLabelsPath, newPostLabelHandler(labelBackend))
+	h.HandlerFunc("DELETE", usersIDLabelsIDPath, newDeleteLabelHandler(labelBackend))
+
 	return h
 }
 
@@ -100,13 +146,40 @@ func (h *UserHandler) handlePutUserPassword(w http.ResponseWriter, r *http.Reque
 	h.Logger.Debug("user update password request", zap.String("r", fmt.Sprint(r)))
 	_, err := h.putPassword(ctx, w, r)
 	if err != nil {
-		h.HandleHTTPError(ctx, err, w)
+		if err := encodePasswordError(ctx, h, w, err); err != nil {
+			logEncodingError(h.Logger, r, err)
+		}
 		return
 	}
 	h.Logger.Debug("user password updated")
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// passwordErrorResponse explains why a password was rejected, rule by rule.
+type passwordErrorResponse struct {
+	Code       string                       `json:"code"`
+	Message    string                       `json:"message"`
+	Violations []influxdb.PasswordViolation `json:"violations"`
+}
+
+// encodePasswordError encodes an error of a password that violates the password
+// policy with its violations, and any other error with the error handler.
+func encodePasswordError(ctx context.Context, h influxdb.HTTPErrorHandler, w http.ResponseWriter, err error) error {
+	vs := influxdb.PasswordViolations(err)
+	if len(vs) == 0 {
+		h.HandleHTTPError(ctx, err, w)
+		return nil
+	}
+
+	code := influxdb.ErrorCode(err)
+	w.Header().Set(PlatformErrorCodeHeader, code)
+	return encodeResponse(ctx, w, statusCodePlatformError[code], &passwordErrorResponse{
+		Code:       code,
+		Message:    influxdb.ErrorMessage(err),
+		Violations: vs,
+	})
+}
+
 type passwordResetRequest struct {
 	Username    string
 	PasswordOld string
@@ -342,14 +415,30 @@ func (h *UserHandler) handleGetUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	users, _, err := h.UserService.FindUsers(ctx, req.filter)
+	users, _, err := h.UserService.FindUsers(ctx, req.filter, req.labels.findOptions(req.opts))
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
 	}
+
+	if len(req.labels) > 0 {
+		idx, err := req.labels.filter(ctx, h.LabelService, req.opts, len(users), func(i int) influxdb.ID { return users[i].ID })
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		labeled := make([]*influxdb.User, 0, len(idx))
+		for _, i := range idx {
+			labeled = append(labeled, users[i])
+		}
+		users = labeled
+	}
 	h.Logger.Debug("users retrieved", zap.String("users", fmt.Sprint(users)))
 
-	err = encodeResponse(ctx, w, http.StatusOK, newUsersResponse(users))
+	links := newPagingLinks(usersPath, req.opts, req.labels.pagingFilter(req.filter), len(users))
+	res := newUsersResponse(users)
+	addPagingLinks(res.Links, links)
+	err = encodeListResponse(ctx, w, req.opts.Fields, links, "users", res)
 	if err != nil {
 		h.HandleHTTPError(ctx, err, w)
 		return
@@ -358,12 +447,23 @@ func (h *UserHandler) handleGetUsers(w http.ResponseWriter, r *http.Request) {
 
 type getUsersRequest struct {
 	filter influxdb.UserFilter
+	opts   influxdb.FindOptions
+	labels labelFilter
 }
 
 func decodeGetUsersRequest(ctx context.Context, r *http.Request) (*getUsersRequest, error) {
 	qp := r.URL.Query()
 	req := &getUsersRequest{}
 
+	opts, err := decodeFindOptions(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.ValidSortBy(influxdb.UserSortKeys...); err != nil {
+		return nil, err
+	}
+	req.opts = *opts
+
 	if userID := qp.Get("id"); userID != "" {
 		id, err := influxdb.IDFromString(userID)
 		if err != nil {
@@ -376,6 +476,8 @@ func decodeGetUsersRequest(ctx context.Context, r *http.Request) (*getUsersReque
 		req.filter.Name = &name
 	}
 
+	req.labels = decodeLabelFilter(r)
+
 	return req, nil
 }
 
@@ -553,6 +655,14 @@ func (s *UserService) FindUsers(ctx context.Context, filter influxdb.UserFilter,
 		query.Add("name", *filter.Name)
 	}
 
+	if len(opt) > 0 {
+		for k, vs := range opt[0].QueryParams() {
+			for _, v := range vs {
+				query.Add(k, v)
+			}
+		}
+	}
+
 	req.URL.RawQuery = query.Encode()
 	SetToken(s.Token, req)
 
@@ -2,7 +2,10 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	platform "github.com/influxdata/influxdb"
@@ -19,6 +22,13 @@ func NewMockUserBackend() *UserBackend {
 		UserService:             mock.NewUserService(),
 		UserOperationLogService: mock.NewUserOperationLogService(),
 		PasswordsService:        mock.NewPasswordsService("", ""),
+
+		UserResourceMappingService: mock.NewUserResourceMappingService(),
+		DashboardService:           mock.NewDashboardService(),
+		TaskService:                &mock.TaskService{},
+		BucketService:              mock.NewBucketService(),
+		AuthorizationService:       mock.NewAuthorizationService(),
+		LabelService:               mock.NewLabelService(),
 	}
 }
 
@@ -53,3 +63,55 @@ func TestUserService(t *testing.T) {
 	t.Parallel()
 	platformtesting.UserService(initUserService, t)
 }
+
+func TestUserHandler_handleGetUsersByLabel(t *testing.T) {
+	users := []*platform.User{
+		{ID: platformtesting.MustIDBase16("0000000000000001"), Name: "alice"},
+		{ID: platformtesting.MustIDBase16("0000000000000002"), Name: "bob"},
+		{ID: platformtesting.MustIDBase16("0000000000000003"), Name: "carol"},
+		{ID: platformtesting.MustIDBase16("0000000000000004"), Name: "dave"},
+	}
+	labels := map[platform.ID][]*platform.Label{
+		users[0].ID: {{Name: "prod"}, {Name: "team-a"}},
+		users[1].ID: {{Name: "prod"}},
+		users[2].ID: {{Name: "prod"}, {Name: "team-a"}},
+		users[3].ID: {{Name: "team-a"}},
+	}
+
+	backend := NewMockUserBackend()
+	backend.HTTPErrorHandler = ErrorHandler(0)
+	backend.UserService = &mock.UserService{
+		FindUsersFn: func(ctx context.Context, f platform.UserFilter, opts ...platform.FindOptions) ([]*platform.User, int, error) {
+			if len(opts) == 0 || opts[0].Limit != 0 || opts[0].Offset != 0 {
+				t.Errorf("expected users to be found unpaged, got %+v", opts)
+			}
+			return users, len(users), nil
+		},
+	}
+	backend.LabelService = &mock.LabelService{
+		FindResourceLabelsFn: func(ctx context.Context, f platform.LabelMappingFilter) ([]*platform.Label, error) {
+			return labels[f.ResourceID], nil
+		},
+	}
+	h := NewUserHandler(backend)
+
+	r := httptest.NewRequest("GET", "http://any.url/api/v2/users?label=prod&label=team-a&limit=1&offset=1", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var res usersResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Users) != 1 || res.Users[0].Name != "carol" {
+		t.Fatalf("expected the second user with both labels, got %+v", res.Users)
+	}
+	for _, rel := range []string{"prev", "next"} {
+		if !strings.Contains(res.Links[rel], "label=prod&label=team-a") {
+			t.Errorf("expected the %s link to keep the label filter, got %q", rel, res.Links[rel])
+		}
+	}
+}
@@ -95,7 +95,7 @@ func (r getVariablesResponse) ToPlatform() []*platform.Variable {
 	return variables
 }
 
-func newGetVariablesResponse(ctx context.Context, variables []*platform.Variable, f platform.VariableFilter, opts platform.FindOptions, labelService platform.LabelService) getVariablesResponse {
+func newGetVariablesResponse(ctx context.Context, variables []*platform.Variable, f platform.PagingFilter, opts platform.FindOptions, labelService platform.LabelService) getVariablesResponse {
 	num := len(variables)
 	resp := getVariablesResponse{
 		Variables: make([]variableResponse, 0, num),
@@ -113,6 +113,7 @@ func newGetVariablesResponse(ctx context.Context, variables []*platform.Variable
 type getVariablesRequest struct {
 	filter platform.VariableFilter
 	opts   platform.FindOptions
+	labels labelFilter
 }
 
 func decodeGetVariablesRequest(ctx context.Context, r *http.Request) (*getVariablesRequest, error) {
@@ -138,6 +139,8 @@ func decodeGetVariablesRequest(ctx context.Context, r *http.Request) (*getVariab
 		req.filter.Organization = &org
 	}
 
+	req.labels = decodeLabelFilter(r)
+
 	return req, nil
 }
 
@@ -150,7 +153,7 @@ func (h *VariableHandler) handleGetVariables(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	variables, err := h.VariableService.FindVariables(ctx, req.filter, req.opts)
+	variables, err := h.VariableService.FindVariables(ctx, req.filter, req.labels.findOptions(req.opts))
 	if err != nil {
 		h.HandleHTTPError(ctx, &platform.Error{
 			Code: platform.EInternal,
@@ -159,8 +162,21 @@ func (h *VariableHandler) handleGetVariables(w http.ResponseWriter, r *http.Requ
 		}, w)
 		return
 	}
+
+	if len(req.labels) > 0 {
+		idx, err := req.labels.filter(ctx, h.LabelService, req.opts, len(variables), func(i int) platform.ID { return variables[i].ID })
+		if err != nil {
+			h.HandleHTTPError(ctx, err, w)
+			return
+		}
+		labeled := make([]*platform.Variable, 0, len(idx))
+		for _, i := range idx {
+			labeled = append(labeled, variables[i])
+		}
+		variables = labeled
+	}
 	h.Logger.Debug("variables retrieved", zap.String("vars", fmt.Sprint(variables)))
-	err = encodeResponse(ctx, w, http.StatusOK, newGetVariablesResponse(ctx, variables, req.filter, req.opts, h.LabelService))
+	err = encodeResponse(ctx, w, http.StatusOK, newGetVariablesResponse(ctx, variables, req.labels.pagingFilter(req.filter), req.opts, h.LabelService))
 	if err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
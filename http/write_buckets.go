@@ -0,0 +1,414 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/http/metric"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	writeBucketsPath = "/api/v2/write/buckets"
+	// writeBucketsMaxBodySize is the size of the largest body of a write to
+	// several buckets. The body is read whole, unlike the body of a write to
+	// one bucket, which is read in batches.
+	writeBucketsMaxBodySize = 32 << 20
+)
+
+// WriteBucketSection is the line protocol of a write to several buckets that
+// is written to one of the buckets.
+type WriteBucketSection struct {
+	// Bucket is the name or ID of the bucket.
+	Bucket string `json:"bucket"`
+	Lines  string `json:"lines"`
+}
+
+type writeBucketsRequestBody struct {
+	Sections []WriteBucketSection `json:"sections"`
+}
+
+// WriteBucketResult is the outcome of the write of a section to its bucket.
+// The lines of a section are written together, or not at all.
+type WriteBucketResult struct {
+	Bucket   string      `json:"bucket"`
+	BucketID platform.ID `json:"bucketID"`
+	// Code and Message are the error that kept the lines from being written,
+	// and are empty if they were written.
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Lines is the number of lines of line protocol, ignoring blank lines and comments.
+	Lines int `json:"lines"`
+	// Accepted is the number of points that were written.
+	Accepted int `json:"accepted"`
+	// RejectedLines is the number of invalid lines, which may exceed the number of lines in Rejected.
+	RejectedLines int              `json:"rejectedLines"`
+	Rejected      []writeLineError `json:"rejected"`
+}
+
+// Written returns true if the lines of the section were written.
+func (r WriteBucketResult) Written() bool {
+	return r.Code == ""
+}
+
+type writeBucketsResponse struct {
+	Results []WriteBucketResult `json:"results"`
+}
+
+// writeBucketsSection is a section of a write to several buckets, with what it
+// is validated against.
+type writeBucketsSection struct {
+	WriteBucketSection
+	bucket       *platform.Bucket
+	mm           []byte
+	measurements map[string]bool
+	schemas      map[string]*platform.MeasurementSchema
+}
+
+// handleWriteBuckets is the HTTP handler for the POST /api/v2/write/buckets route.
+// It writes sections of line protocol to buckets of an organization. Every
+// bucket must exist and be writable by the token before anything is written.
+// Each section is then written on its own, so that a section with an invalid
+// line or over the quota of its bucket doesn't keep the others from being
+// written. The response is 200 if every section was written, or 207 with the
+// result of each section if some weren't.
+func (h *WriteHandler) handleWriteBuckets(w http.ResponseWriter, r *http.Request) {
+	span, r := tracing.ExtractFromHTTPRequest(r, "WriteHandler")
+	defer span.Finish()
+
+	ctx := r.Context()
+	defer r.Body.Close()
+
+	var orgID platform.ID
+	var requestBytes int
+	sw := newStatusResponseWriter(w)
+	w = sw
+	defer func() {
+		h.EventRecorder.Record(ctx, metric.Event{
+			OrgID:         orgID,
+			Endpoint:      r.URL.Path,
+			RequestBytes:  requestBytes,
+			ResponseBytes: sw.responseBytes,
+			Status:        sw.code(),
+		})
+	}()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	in, err := decompressWriteBody(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+	defer in.Close()
+
+	req, err := decodeWriteBucketsRequest(ctx, r, in, &requestBytes)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	logger := h.Logger.With(zap.String("org", req.Org), zap.Int("sections", len(req.Sections)))
+
+	sections, err := h.findWriteBucketsSections(ctx, a, req, &orgID, logger)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	now := time.Now()
+	res := &writeBucketsResponse{Results: make([]WriteBucketResult, 0, len(sections))}
+	status := http.StatusOK
+	for _, s := range sections {
+		result := h.writeBucketSection(ctx, a, s, now, req.Precision, logger)
+		if !result.Written() {
+			status = http.StatusMultiStatus
+		}
+		res.Results = append(res.Results, result)
+	}
+
+	if err := encodeResponse(ctx, w, status, res); err != nil {
+		logEncodingError(logger, r, err)
+		return
+	}
+}
+
+// findWriteBucketsSections returns the sections of a write with their buckets,
+// and an error if a bucket can't be found or written to by a.
+func (h *WriteHandler) findWriteBucketsSections(ctx context.Context, a platform.Authorizer, req *postWriteBucketsRequest, orgID *platform.ID, logger *zap.Logger) ([]*writeBucketsSection, error) {
+	sections := make([]*writeBucketsSection, 0, len(req.Sections))
+	seen := make(map[platform.ID]bool, len(req.Sections))
+	for _, ws := range req.Sections {
+		org, bucket, err := h.findBucket(ctx, req.Org, ws.Bucket, logger)
+		if org != nil {
+			*orgID = org.ID
+		}
+		if err != nil {
+			if org != nil && platform.ErrorCode(err) == platform.ENotFound {
+				h.recordRejection(ctx, a, platform.WriteRejection{OrgID: org.ID, Reason: platform.WriteRejectionNoBucket}, err)
+			}
+			return nil, &platform.Error{
+				Code: platform.ErrorCode(err),
+				Op:   "http/handleWriteBuckets",
+				Msg:  fmt.Sprintf("bucket %q of section %d", ws.Bucket, len(sections)+1),
+				Err:  err,
+			}
+		}
+		if seen[bucket.ID] {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/handleWriteBuckets",
+				Msg:  fmt.Sprintf("bucket %q is written to by more than one section", ws.Bucket),
+			}
+		}
+		seen[bucket.ID] = true
+
+		measurements, err := authorizeWrite(a, org, bucket)
+		if err != nil {
+			h.recordRejection(ctx, a, platform.WriteRejection{OrgID: org.ID, BucketID: bucket.ID, Reason: platform.WriteRejectionForbidden}, err)
+			return nil, &platform.Error{
+				Code: platform.ErrorCode(err),
+				Op:   "http/handleWriteBuckets",
+				Msg:  fmt.Sprintf("bucket %q", ws.Bucket),
+				Err:  err,
+			}
+		}
+
+		schemas, err := h.measurementSchemas(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded := tsdb.EncodeName(org.ID, bucket.ID)
+		s := &writeBucketsSection{
+			WriteBucketSection: ws,
+			bucket:             bucket,
+			mm:                 models.EscapeMeasurement(encoded[:]),
+			measurements:       measurements,
+			schemas:            schemas,
+		}
+		sections = append(sections, s)
+	}
+	return sections, nil
+}
+
+// writeBucketSection writes the lines of a section to its bucket if every line
+// is valid, and returns the result.
+func (h *WriteHandler) writeBucketSection(ctx context.Context, a platform.Authorizer, s *writeBucketsSection, now time.Time, precision string, logger *zap.Logger) WriteBucketResult {
+	b := s.bucket
+	res := WriteBucketResult{
+		Bucket:   s.Bucket,
+		BucketID: b.ID,
+		Rejected: []writeLineError{},
+	}
+	fail := func(err error) WriteBucketResult {
+		res.Code = platform.ErrorCode(err)
+		res.Message = platform.ErrorMessage(err)
+		return res
+	}
+
+	hooks, err := newLineHooks(b, s.mm, now, precision)
+	if err != nil {
+		logger.Error("Failed to find write hooks of bucket", zap.String("bucket", s.Bucket), zap.Error(err))
+		return fail(err)
+	}
+
+	v := newLineValidator(now, precision)
+	v.measurements = s.measurements
+	v.schemas = s.schemas
+
+	data := []byte(s.Lines)
+	var points []models.Point
+	for _, line := range models.ParseLinesWithPrecision(data, s.mm, now, precision, 1) {
+		res.Lines++
+		line = hooks.apply(ctx, line)
+		if _, err := v.validate(line); err != nil {
+			h.recordRejection(ctx, a, platform.WriteRejection{OrgID: b.OrgID, BucketID: b.ID, Reason: platform.WriteRejectionInvalidLine, Line: line.Text}, err)
+			res.RejectedLines++
+			if len(res.Rejected) < writeMaxLineErrors {
+				res.Rejected = append(res.Rejected, writeLineError{Line: line.Number, Message: err.Error()})
+			}
+			continue
+		}
+		points = append(points, line.Points...)
+	}
+	if res.RejectedLines > 0 {
+		return fail(&platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("%d of %d lines were rejected and no points were written", res.RejectedLines, res.Lines),
+		})
+	}
+	if len(points) == 0 {
+		return res
+	}
+
+	if h.BucketQuotaService != nil {
+		if _, err := h.BucketQuotaService.ReserveWrite(ctx, b, seriesKeys(points), int64(len(data))); err != nil {
+			h.recordRejection(ctx, a, platform.WriteRejection{OrgID: b.OrgID, BucketID: b.ID, Reason: platform.WriteRejectionOverQuota}, err)
+			return fail(err)
+		}
+	}
+
+	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+		logger.Error("Error writing points", zap.String("bucket", s.Bucket), zap.Error(err))
+		return fail(&platform.Error{
+			Code: platform.EInternal,
+			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+			Err:  err,
+		})
+	}
+	res.Accepted = len(points)
+	return res
+}
+
+type postWriteBucketsRequest struct {
+	Org       string
+	Precision string
+	Sections  []WriteBucketSection
+}
+
+// decodeWriteBucketsRequest decodes a write to several buckets, and sets n to
+// the number of bytes of its body that were read.
+func decodeWriteBucketsRequest(ctx context.Context, r *http.Request, body io.Reader, n *int) (*postWriteBucketsRequest, error) {
+	qp := r.URL.Query()
+	p := qp.Get("precision")
+	if p == "" {
+		p = "ns"
+	}
+	if !models.ValidPrecision(p) {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeWriteBucketsRequest",
+			Msg:  errInvalidPrecision,
+		}
+	}
+
+	var buf bytes.Buffer
+	read, err := buf.ReadFrom(io.LimitReader(body, writeBucketsMaxBodySize+1))
+	*n = int(read)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeWriteBucketsRequest",
+			Msg:  fmt.Sprintf("unable to read data: %v", err),
+			Err:  err,
+		}
+	}
+	if read > writeBucketsMaxBodySize {
+		return nil, &platform.Error{
+			Code: platform.ELimitExceeded,
+			Op:   "http/decodeWriteBucketsRequest",
+			Msg:  fmt.Sprintf("body exceeds the maximum size of %d bytes", writeBucketsMaxBodySize),
+		}
+	}
+
+	var rb writeBucketsRequestBody
+	if err := json.Unmarshal(buf.Bytes(), &rb); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeWriteBucketsRequest",
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+	if len(rb.Sections) == 0 {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeWriteBucketsRequest",
+			Msg:  "write requires at least one section",
+		}
+	}
+	for i, s := range rb.Sections {
+		if s.Bucket == "" {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeWriteBucketsRequest",
+				Msg:  fmt.Sprintf("section %d is missing a bucket", i+1),
+			}
+		}
+	}
+
+	return &postWriteBucketsRequest{
+		Org:       qp.Get("org"),
+		Precision: p,
+		Sections:  rb.Sections,
+	}, nil
+}
+
+// WriteBuckets writes sections of line protocol to buckets of an organization
+// in one request, and returns the result of each section. An error is only
+// returned if nothing was written, such as when a bucket can't be written to.
+func (s *WriteService) WriteBuckets(ctx context.Context, orgID platform.ID, sections []WriteBucketSection) ([]WriteBucketResult, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	precision := s.Precision
+	if precision == "" {
+		precision = "ns"
+	}
+	if !models.ValidPrecision(precision) {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/WriteBuckets",
+			Msg:  errInvalidPrecision,
+		}
+	}
+
+	u, err := NewURL(s.Addr, writeBucketsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(writeBucketsRequestBody{Sections: sections})
+	if err != nil {
+		return nil, err
+	}
+	body, err := compressWithGzip(bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	SetToken(s.Token, req)
+
+	params := req.URL.Query()
+	params.Set("org", orgID.String())
+	params.Set("precision", precision)
+	req.URL.RawQuery = params.Encode()
+
+	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var res writeBucketsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+	return res.Results, nil
+}
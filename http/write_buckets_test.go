@@ -0,0 +1,170 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	platformtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestWriteHandler_handleWriteBuckets(t *testing.T) {
+	write := func(bucketID platform.ID) platform.Permission {
+		return platform.Permission{
+			Action:   platform.WriteAction,
+			Resource: platform.Resource{Type: platform.BucketsResourceType, OrgID: platformtesting.IDPtr(1), ID: &bucketID},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		permissions []platform.Permission
+		wantCode    int
+		wantPoints  int
+		wantBody    string
+	}{
+		{
+			name: "every section is written",
+			body: `{"sections": [
+				{"bucket": "0000000000000002", "lines": "cpu f=1 1\ncpu f=2 2\n"},
+				{"bucket": "0000000000000003", "lines": "mem f=1 1"}
+			]}`,
+			permissions: []platform.Permission{write(2), write(3)},
+			wantCode:    http.StatusOK,
+			wantPoints:  3,
+			wantBody: `
+{
+  "results": [
+    {"bucket": "0000000000000002", "bucketID": "0000000000000002", "lines": 2, "accepted": 2, "rejectedLines": 0, "rejected": []},
+    {"bucket": "0000000000000003", "bucketID": "0000000000000003", "lines": 1, "accepted": 1, "rejectedLines": 0, "rejected": []}
+  ]
+}
+`,
+		},
+		{
+			name: "section with an invalid line is not written",
+			body: `{"sections": [
+				{"bucket": "0000000000000002", "lines": "cpu f=1 1\ncpu f=\"x\" 2\n"},
+				{"bucket": "0000000000000003", "lines": "mem f=1 1"}
+			]}`,
+			permissions: []platform.Permission{write(2), write(3)},
+			wantCode:    http.StatusMultiStatus,
+			wantPoints:  1,
+			wantBody: `
+{
+  "results": [
+    {
+      "bucket": "0000000000000002",
+      "bucketID": "0000000000000002",
+      "code": "invalid",
+      "message": "1 of 2 lines were rejected and no points were written",
+      "lines": 2,
+      "accepted": 0,
+      "rejectedLines": 1,
+      "rejected": [{"line": 2, "message": "field type conflict: field \"f\" of measurement \"cpu\" is string, but was float on line 1"}]
+    },
+    {"bucket": "0000000000000003", "bucketID": "0000000000000003", "lines": 1, "accepted": 1, "rejectedLines": 0, "rejected": []}
+  ]
+}
+`,
+		},
+		{
+			name: "bucket that can not be written to",
+			body: `{"sections": [
+				{"bucket": "0000000000000002", "lines": "cpu f=1 1"},
+				{"bucket": "0000000000000003", "lines": "mem f=1 1"}
+			]}`,
+			permissions: []platform.Permission{write(2)},
+			wantCode:    http.StatusForbidden,
+		},
+		{
+			name: "bucket in more than one section",
+			body: `{"sections": [
+				{"bucket": "0000000000000002", "lines": "cpu f=1 1"},
+				{"bucket": "0000000000000002", "lines": "mem f=1 1"}
+			]}`,
+			permissions: []platform.Permission{write(2)},
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "no sections",
+			body:        `{"sections": []}`,
+			permissions: []platform.Permission{write(2)},
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "section without a bucket",
+			body:        `{"sections": [{"lines": "cpu f=1 1"}]}`,
+			permissions: []platform.Permission{write(2)},
+			wantCode:    http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := newTestWriteHandler(pw)
+
+			r := httptest.NewRequest("POST", "/api/v2/write/buckets?org=0000000000000001", strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: tt.permissions}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if len(pw.Points) != tt.wantPoints {
+				t.Errorf("got %d points, want %d", len(pw.Points), tt.wantPoints)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(w.Body.String(), tt.wantBody); err != nil {
+				t.Fatalf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("unexpected body: %s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteService_WriteBuckets(t *testing.T) {
+	pw := &mock.PointsWriter{}
+	h := newTestWriteHandler(pw)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+		h.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	s := &WriteService{Addr: server.URL, Precision: "s"}
+	results, err := s.WriteBuckets(context.Background(), 1, []WriteBucketSection{
+		{Bucket: "0000000000000002", Lines: "cpu f=1 1\n"},
+		{Bucket: "0000000000000003", Lines: "mem f=1 1\nmem f=true 2\n"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if !results[0].Written() || results[0].Accepted != 1 {
+		t.Errorf("expected the first section to be written, got %+v", results[0])
+	}
+	if results[1].Written() || results[1].Code != platform.EInvalid || results[1].RejectedLines != 1 {
+		t.Errorf("expected the second section to be rejected, got %+v", results[1])
+	}
+	if len(pw.Points) != 1 || pw.Points[0].Time().Unix() != 1 {
+		t.Errorf("expected one point written with the precision of the service, got %v", pw.Points)
+	}
+
+	if _, err := s.WriteBuckets(context.Background(), 1, nil); platform.ErrorCode(err) != platform.EInvalid {
+		t.Errorf("expected invalid error for a write without sections, got %v", err)
+	}
+}
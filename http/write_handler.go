@@ -1,14 +1,17 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/golang/snappy"
 	"github.com/influxdata/influxdb/http/metric"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
@@ -25,24 +28,30 @@ import (
 // the WriteHandler.
 type WriteBackend struct {
 	platform.HTTPErrorHandler
-	Logger             *zap.Logger
-	WriteEventRecorder metric.EventRecorder
-
-	PointsWriter        storage.PointsWriter
-	BucketService       platform.BucketService
-	OrganizationService platform.OrganizationService
+	Logger                 *zap.Logger
+	WriteEventRecorder     metric.EventRecorder
+	WriteRejectionRecorder platform.WriteRejectionRecorder
+
+	PointsWriter             storage.PointsWriter
+	BucketService            platform.BucketService
+	OrganizationService      platform.OrganizationService
+	BucketQuotaService       platform.BucketQuotaService
+	MeasurementSchemaService platform.MeasurementSchemaService
 }
 
 // NewWriteBackend returns a new instance of WriteBackend.
 func NewWriteBackend(b *APIBackend) *WriteBackend {
 	return &WriteBackend{
-		HTTPErrorHandler:   b.HTTPErrorHandler,
-		Logger:             b.Logger.With(zap.String("handler", "write")),
-		WriteEventRecorder: b.WriteEventRecorder,
-
-		PointsWriter:        b.PointsWriter,
-		BucketService:       b.BucketService,
-		OrganizationService: b.OrganizationService,
+		HTTPErrorHandler:       b.HTTPErrorHandler,
+		Logger:                 b.Logger.With(zap.String("handler", "write")),
+		WriteEventRecorder:     b.WriteEventRecorder,
+		WriteRejectionRecorder: b.WriteRejectionRecorder,
+
+		PointsWriter:             b.PointsWriter,
+		BucketService:            b.BucketService,
+		OrganizationService:      b.OrganizationService,
+		BucketQuotaService:       b.BucketQuotaService,
+		MeasurementSchemaService: b.MeasurementSchemaService,
 	}
 }
 
@@ -55,9 +64,21 @@ type WriteHandler struct {
 	BucketService       platform.BucketService
 	OrganizationService platform.OrganizationService
 
+	// BucketQuotaService enforces the quotas of buckets; if it is nil, they aren't.
+	BucketQuotaService platform.BucketQuotaService
+
+	// MeasurementSchemaService has the measurement schemas that the points written
+	// to buckets with an explicit schema type must match; if it is nil, they can't
+	// be written to.
+	MeasurementSchemaService platform.MeasurementSchemaService
+
 	PointsWriter storage.PointsWriter
 
 	EventRecorder metric.EventRecorder
+
+	// RejectionRecorder records the writes, and lines of writes, that are rejected;
+	// if it is nil, they aren't.
+	RejectionRecorder platform.WriteRejectionRecorder
 }
 
 const (
@@ -66,20 +87,26 @@ const (
 	errInvalidPrecision  = "invalid precision; valid precision units are ns, us, ms, and s"
 )
 
-// NewWriteHandler creates a new handler at /api/v2/write to receive line protocol.
+// NewWriteHandler creates a new handler at /api/v2/write to receive line protocol,
+// and at /api/v2/otlp/v1/metrics to receive OpenTelemetry metrics.
 func NewWriteHandler(b *WriteBackend) *WriteHandler {
 	h := &WriteHandler{
 		Router:           NewRouter(b.HTTPErrorHandler),
 		HTTPErrorHandler: b.HTTPErrorHandler,
 		Logger:           b.Logger,
 
-		PointsWriter:        b.PointsWriter,
-		BucketService:       b.BucketService,
-		OrganizationService: b.OrganizationService,
-		EventRecorder:       b.WriteEventRecorder,
+		PointsWriter:             b.PointsWriter,
+		BucketService:            b.BucketService,
+		OrganizationService:      b.OrganizationService,
+		BucketQuotaService:       b.BucketQuotaService,
+		EventRecorder:            b.WriteEventRecorder,
+		MeasurementSchemaService: b.MeasurementSchemaService,
+		RejectionRecorder:        b.WriteRejectionRecorder,
 	}
 
 	h.HandlerFunc("POST", writePath, h.handleWrite)
+	h.HandlerFunc("POST", writeBucketsPath, h.handleWriteBuckets)
+	h.HandlerFunc("POST", otlpMetricsPath, h.handleOTLPMetrics)
 	return h
 }
 
@@ -106,21 +133,12 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		})
 	}()
 
-	in := r.Body
-	if r.Header.Get("Content-Encoding") == "gzip" {
-		var err error
-		in, err = gzip.NewReader(r.Body)
-		if err != nil {
-			h.HandleHTTPError(ctx, &platform.Error{
-				Code: platform.EInvalid,
-				Op:   "http/handleWrite",
-				Msg:  errInvalidGzipHeader,
-				Err:  err,
-			}, w)
-			return
-		}
-		defer in.Close()
+	in, err := decompressWriteBody(r)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
 	}
+	defer in.Close()
 
 	a, err := pcontext.GetAuthorizer(ctx)
 	if err != nil {
@@ -136,31 +154,195 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 	logger := h.Logger.With(zap.String("org", req.Org), zap.String("bucket", req.Bucket))
 
+	org, bucket, err := h.findBucket(ctx, req.Org, req.Bucket, logger)
+	if org != nil {
+		orgID = org.ID
+	}
+	if err != nil {
+		if org != nil && platform.ErrorCode(err) == platform.ENotFound {
+			h.recordRejection(ctx, a, platform.WriteRejection{OrgID: org.ID, Reason: platform.WriteRejectionNoBucket}, err)
+		}
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	measurements, err := authorizeWrite(a, org, bucket)
+	if err != nil {
+		h.recordRejection(ctx, a, platform.WriteRejection{OrgID: org.ID, BucketID: bucket.ID, Reason: platform.WriteRejectionForbidden}, err)
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	schemas, err := h.measurementSchemas(ctx, bucket)
+	if err != nil {
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	// TODO(jeff): we should be publishing with the org and bucket instead of
+	// parsing, rewriting, and publishing, but the interface isn't quite there yet.
+	// be sure to remove this when it is there!
+	//
+	// The body is parsed and written in batches of complete lines, so the memory used
+	// doesn't grow with the size of the body. If a batch fails, the batches before it
+	// have been written already.
+	encoded := tsdb.EncodeName(org.ID, bucket.ID)
+	mm := models.EscapeMeasurement(encoded[:])
+	now := time.Now()
+	lines := newLineBatchReader(in, writeBatchSize, writeMaxLineSize)
+	defer func() { requestBytes = lines.n }()
+
+	v := newLineValidator(now, req.Precision)
+	v.measurements = measurements
+	v.schemas = schemas
+
+	hooks, err := newLineHooks(bucket, mm, now, req.Precision)
+	if err != nil {
+		logger.Error("Failed to find write hooks of bucket", zap.Error(err))
+		h.HandleHTTPError(ctx, err, w)
+		return
+	}
+
+	if req.DryRun {
+		h.handleValidate(w, r, lines, mm, hooks, v, logger)
+		return
+	}
+
+	res := newPartialWriteResponse()
+	number := 1
+	for {
+		data, err := lines.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if err == errWriteLineTooLong {
+				h.recordRejection(ctx, a, platform.WriteRejection{OrgID: org.ID, BucketID: bucket.ID, Reason: platform.WriteRejectionLineTooLong}, err)
+			}
+			h.handleReadError(ctx, w, err, logger)
+			return
+		}
+
+		parsed := models.ParseLinesWithPrecision(data, mm, now, req.Precision, number)
+		number += bytes.Count(data, []byte{'\n'})
+
+		points := make([]models.Point, 0, len(parsed))
+		for _, line := range parsed {
+			line = hooks.apply(ctx, line)
+			if _, err := v.validate(line); err != nil {
+				h.recordRejection(ctx, a, platform.WriteRejection{OrgID: org.ID, BucketID: bucket.ID, Reason: platform.WriteRejectionInvalidLine, Line: line.Text}, err)
+				res.reject(line, err)
+				continue
+			}
+			res.Lines++
+			points = append(points, line.Points...)
+		}
+
+		// Unless invalid lines are dropped, nothing is written from the first batch with
+		// an invalid line on. The rest of the body is still read to report every invalid line.
+		if len(points) == 0 || res.RejectedLines > 0 && !req.DropInvalid {
+			continue
+		}
+
+		if h.BucketQuotaService != nil {
+			if wait, err := h.BucketQuotaService.ReserveWrite(ctx, bucket, seriesKeys(points), int64(len(data))); err != nil {
+				h.recordRejection(ctx, a, platform.WriteRejection{OrgID: org.ID, BucketID: bucket.ID, Reason: platform.WriteRejectionOverQuota}, err)
+				h.handleQuotaError(ctx, w, wait, err, logger)
+				return
+			}
+		}
+
+		if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
+			logger.Error("Error writing points", zap.Error(err))
+			h.HandleHTTPError(ctx, &platform.Error{
+				Code: platform.EInternal,
+				Op:   "http/handleWrite",
+				Msg:  fmt.Sprintf("unable to write points to database: %v", err),
+				Err:  err,
+			}, w)
+			return
+		}
+		res.Accepted += len(points)
+	}
+
+	if res.RejectedLines > 0 {
+		logger.Info("Rejected lines of write", zap.Int("rejected", res.RejectedLines), zap.Int("accepted", res.Accepted))
+		res.Message = fmt.Sprintf("%d of %d lines were rejected and %d points were written", res.RejectedLines, res.Lines, res.Accepted)
+		w.Header().Set(PlatformErrorCodeHeader, res.Code)
+		if err := encodeResponse(ctx, w, http.StatusBadRequest, res); err != nil {
+			logEncodingError(logger, r, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decompressWriteBody returns the body of a write, decompressed according to
+// its Content-Encoding.
+func decompressWriteBody(r *http.Request) (io.ReadCloser, error) {
+	switch r.Header.Get("Content-Encoding") {
+	case "gzip":
+		in, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/handleWrite",
+				Msg:  errInvalidGzipHeader,
+				Err:  err,
+			}
+		}
+		return in, nil
+	case "snappy":
+		return ioutil.NopCloser(snappy.NewReader(r.Body)), nil
+	}
+	return ioutil.NopCloser(r.Body), nil
+}
+
+// recordRejection records the rejection r of a write by a with the recorder of the
+// handler, if there is one.
+func (h *WriteHandler) recordRejection(ctx context.Context, a platform.Authorizer, r platform.WriteRejection, err error) {
+	if h.RejectionRecorder == nil {
+		return
+	}
+	switch a := a.(type) {
+	case *platform.Authorization:
+		r.TokenHash = platform.HashToken(a.Token)
+	case *platform.Session:
+		r.TokenHash = platform.HashToken(a.Key)
+	}
+	r.Message = err.Error()
+	if _, ok := err.(*platform.Error); ok {
+		r.Message = platform.ErrorMessage(err)
+	}
+	h.RejectionRecorder.RecordWriteRejection(ctx, r)
+}
+
+// findBucket returns the bucket a write is to and its organization, which are
+// given by their IDs or names. Once the organization is found, it is returned
+// even if the bucket is not.
+func (h *WriteHandler) findBucket(ctx context.Context, orgName, bucketName string, logger *zap.Logger) (*platform.Organization, *platform.Bucket, error) {
 	var org *platform.Organization
-	if id, err := platform.IDFromString(req.Org); err == nil {
+	if id, err := platform.IDFromString(orgName); err == nil {
 		// Decoded ID successfully. Make sure it's a real org.
 		o, err := h.OrganizationService.FindOrganizationByID(ctx, *id)
 		if err == nil {
 			org = o
 		} else if platform.ErrorCode(err) != platform.ENotFound {
-			h.HandleHTTPError(ctx, err, w)
-			return
+			return nil, nil, err
 		}
 	}
 	if org == nil {
-		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &req.Org})
+		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &orgName})
 		if err != nil {
 			logger.Info("Failed to find organization", zap.Error(err))
-			h.HandleHTTPError(ctx, err, w)
-			return
+			return nil, nil, err
 		}
 
 		org = o
 	}
-	orgID = org.ID
 
 	var bucket *platform.Bucket
-	if id, err := platform.IDFromString(req.Bucket); err == nil {
+	if id, err := platform.IDFromString(bucketName); err == nil {
 		// Decoded ID successfully. Make sure it's a real bucket.
 		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
 			OrganizationID: &org.ID,
@@ -169,89 +351,239 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 		if err == nil {
 			bucket = b
 		} else if platform.ErrorCode(err) != platform.ENotFound {
-			h.HandleHTTPError(ctx, err, w)
-			return
+			return org, nil, err
 		}
 	}
 
 	if bucket == nil {
 		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
 			OrganizationID: &org.ID,
-			Name:           &req.Bucket,
+			Name:           &bucketName,
 		})
 		if err != nil {
-			h.HandleHTTPError(ctx, &platform.Error{
+			return org, nil, &platform.Error{
 				Op:  "http/handleWrite",
 				Err: err,
-			}, w)
-			return
+			}
 		}
 
 		bucket = b
 	}
 
-	p, err := platform.NewPermissionAtID(bucket.ID, platform.WriteAction, platform.BucketsResourceType, org.ID)
-	if err != nil {
-		h.HandleHTTPError(ctx, &platform.Error{
-			Code: platform.EInternal,
+	return org, bucket, nil
+}
+
+// measurementSchemas returns the schemas of the measurements of a bucket with an
+// explicit schema type by measurement, or nil if the bucket has no explicit schema type.
+func (h *WriteHandler) measurementSchemas(ctx context.Context, bucket *platform.Bucket) (map[string]*platform.MeasurementSchema, error) {
+	if !bucket.SchemaType.Explicit() {
+		return nil, nil
+	}
+	if h.MeasurementSchemaService == nil {
+		return nil, &platform.Error{
+			Code: platform.EUnavailable,
 			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to create permission for bucket: %v", err),
-			Err:  err,
-		}, w)
-		return
+			Msg:  "measurement schemas are not available",
+		}
 	}
 
-	if !a.Allowed(*p) {
-		h.HandleHTTPError(ctx, &platform.Error{
+	ms, err := h.MeasurementSchemaService.FindMeasurementSchemas(ctx, platform.MeasurementSchemaFilter{BucketID: bucket.ID})
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  "http/handleWrite",
+			Err: err,
+		}
+	}
+
+	schemas := make(map[string]*platform.MeasurementSchema, len(ms))
+	for _, m := range ms {
+		schemas[m.Name] = m
+	}
+	return schemas, nil
+}
+
+// authorizeWrite returns the measurements the authorizer may write to the
+// bucket, or nil if it may write any measurement.
+func authorizeWrite(a platform.Authorizer, org *platform.Organization, bucket *platform.Bucket) (map[string]bool, error) {
+	measurements, all := platform.WriteMeasurements(a, org.ID, bucket.ID)
+	if all {
+		return nil, nil
+	}
+	if len(measurements) == 0 {
+		return nil, &platform.Error{
 			Code: platform.EForbidden,
 			Op:   "http/handleWrite",
 			Msg:  "insufficient permissions for write",
-		}, w)
-		return
+		}
 	}
 
-	// TODO(jeff): we should be publishing with the org and bucket instead of
-	// parsing, rewriting, and publishing, but the interface isn't quite there yet.
-	// be sure to remove this when it is there!
-	data, err := ioutil.ReadAll(in)
-	if err != nil {
-		logger.Error("Error reading body", zap.Error(err))
-		h.HandleHTTPError(ctx, &platform.Error{
-			Code: platform.EInternal,
-			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to read data: %v", err),
-			Err:  err,
-		}, w)
+	allowed := make(map[string]bool, len(measurements))
+	for _, m := range measurements {
+		allowed[m] = true
+	}
+	return allowed, nil
+}
+
+// handleValidate responds to a dry-run write with the errors that hooks and v find
+// that would keep the lines from being written, without writing anything.
+func (h *WriteHandler) handleValidate(w http.ResponseWriter, r *http.Request, lines *lineBatchReader, mm []byte, hooks *lineHooks, v *lineValidator, logger *zap.Logger) {
+	ctx := r.Context()
+
+	res := newWriteValidationResponse()
+	number := 1
+	for {
+		data, err := lines.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			h.handleReadError(ctx, w, err, logger)
+			return
+		}
+
+		for _, line := range models.ParseLinesWithPrecision(data, mm, v.now, v.precision, number) {
+			line = hooks.apply(ctx, line)
+			warnings, err := v.validate(line)
+			res.add(line, warnings, err)
+		}
+		number += bytes.Count(data, []byte{'\n'})
+	}
+
+	logger.Debug("write validated", zap.Int("lines", res.Lines), zap.Int("rejected", res.Rejected))
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(logger, r, err)
 		return
 	}
-	requestBytes = len(data)
+}
 
-	encoded := tsdb.EncodeName(org.ID, bucket.ID)
-	mm := models.EscapeMeasurement(encoded[:])
-	points, err := models.ParsePointsWithPrecision(data, mm, time.Now(), req.Precision)
-	if err != nil {
-		logger.Error("Error parsing points", zap.Error(err))
+// seriesKeys returns the series keys of points, which the series quota of their bucket limits.
+func seriesKeys(points []models.Point) [][]byte {
+	keys := make([][]byte, len(points))
+	for i, p := range points {
+		keys[i] = p.Key()
+	}
+	return keys
+}
+
+// handleQuotaError responds with err, an error reserving a batch of a write against
+// the quota of its bucket. A write over the quota is to be retried after wait.
+func (h *WriteHandler) handleQuotaError(ctx context.Context, w http.ResponseWriter, wait time.Duration, err error, logger *zap.Logger) {
+	if platform.ErrorCode(err) == platform.ETooManyRequests {
+		logger.Info("Write over bucket quota", zap.Duration("retry_after", wait), zap.Error(err))
+		// Retry-After is in whole seconds, so round up to not retry too early.
+		secs := int64((wait + time.Second - 1) / time.Second)
+		if secs < 1 {
+			secs = 1
+		}
+		w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+	}
+	h.HandleHTTPError(ctx, &platform.Error{
+		Op:  "http/handleWrite",
+		Err: err,
+	}, w)
+}
+
+// handleReadError responds with err, an error reading the body of a write.
+func (h *WriteHandler) handleReadError(ctx context.Context, w http.ResponseWriter, err error, logger *zap.Logger) {
+	if err == errWriteLineTooLong {
+		logger.Info("Error reading body", zap.Error(err))
 		h.HandleHTTPError(ctx, &platform.Error{
 			Code: platform.EInvalid,
 			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to parse points: %v", err),
-			Err:  err,
+			Msg:  err.Error(),
 		}, w)
 		return
 	}
 
-	if err := h.PointsWriter.WritePoints(ctx, points); err != nil {
-		logger.Error("Error writing points", zap.Error(err))
-		h.HandleHTTPError(ctx, &platform.Error{
-			Code: platform.EInternal,
-			Op:   "http/handleWrite",
-			Msg:  fmt.Sprintf("unable to write points to database: %v", err),
-			Err:  err,
-		}, w)
-		return
+	logger.Error("Error reading body", zap.Error(err))
+	h.HandleHTTPError(ctx, &platform.Error{
+		Code: platform.EInternal,
+		Op:   "http/handleWrite",
+		Msg:  fmt.Sprintf("unable to read data: %v", err),
+		Err:  err,
+	}, w)
+}
+
+const (
+	// writeBatchSize is the size of the batches of line protocol that are parsed and written at a time.
+	writeBatchSize = 1 << 20
+	// writeMaxLineSize is the size of the longest line of line protocol accepted.
+	writeMaxLineSize = 16 << 20
+)
+
+var errWriteLineTooLong = fmt.Errorf("line exceeds the maximum size of %d bytes", writeMaxLineSize)
+
+// lineBatchReader reads line protocol in batches of complete lines.
+type lineBatchReader struct {
+	r           io.Reader
+	batchSize   int
+	maxLineSize int
+
+	rest []byte // the start of a line that didn't fit into the previous batch
+	eof  bool
+	n    int // number of bytes read
+}
+
+func newLineBatchReader(r io.Reader, batchSize, maxLineSize int) *lineBatchReader {
+	return &lineBatchReader{
+		r:           r,
+		batchSize:   batchSize,
+		maxLineSize: maxLineSize,
 	}
+}
 
-	w.WriteHeader(http.StatusNoContent)
+// Next returns the next batch of lines, or io.EOF once every line has been returned.
+// Batches are about batchSize bytes, unless they hold a longer line. The memory of a
+// batch is not reused, so the points parsed from it remain valid.
+func (br *lineBatchReader) Next() ([]byte, error) {
+	if br.eof && len(br.rest) == 0 {
+		return nil, io.EOF
+	}
+
+	size := br.batchSize
+	if len(br.rest) >= size {
+		size = 2 * len(br.rest)
+	}
+	buf := make([]byte, len(br.rest), size)
+	copy(buf, br.rest)
+	br.rest = nil
+
+	for !br.eof {
+		if len(buf) == cap(buf) {
+			if n := models.CompleteLines(buf); n > 0 {
+				br.rest = buf[n:]
+				return buf[:n:n], nil
+			}
+			if len(buf) >= br.maxLineSize {
+				return nil, errWriteLineTooLong
+			}
+
+			// The batch holds a single line that is longer than the batch size.
+			grown := make([]byte, len(buf), 2*cap(buf))
+			copy(grown, buf)
+			buf = grown
+		}
+
+		n, err := br.r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		br.n += n
+		if err == io.EOF {
+			br.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	// The tail read at EOF is split into batches like the ones before it:
+	// the complete lines of about batchSize bytes, or a longer first line.
+	for size := br.batchSize; size < len(buf); size *= 2 {
+		if n := models.CompleteLines(buf[:size]); n > 0 {
+			br.rest = buf[n:]
+			return buf[:n:n], nil
+		}
+	}
+
+	// The last line doesn't need to be terminated by a newline.
+	return buf, nil
 }
 
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
@@ -269,17 +601,39 @@ func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest
 		}
 	}
 
-	return &postWriteRequest{
+	req := &postWriteRequest{
 		Bucket:    qp.Get("bucket"),
 		Org:       qp.Get("org"),
 		Precision: p,
-	}, nil
+	}
+	for param, b := range map[string]*bool{"dry_run": &req.DryRun, "drop_invalid": &req.DropInvalid} {
+		s := qp.Get(param)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeWriteRequest",
+				Msg:  fmt.Sprintf("%s must be a boolean", param),
+				Err:  err,
+			}
+		}
+		*b = v
+	}
+
+	return req, nil
 }
 
 type postWriteRequest struct {
 	Org       string
 	Bucket    string
 	Precision string
+	// DryRun validates the lines without writing them.
+	DryRun bool
+	// DropInvalid writes the valid lines of a body with invalid lines.
+	DropInvalid bool
 }
 
 // WriteService sends data over HTTP to influxdb via line protocol.
@@ -288,6 +642,9 @@ type WriteService struct {
 	Token              string
 	Precision          string
 	InsecureSkipVerify bool
+	// DropInvalidLines writes the valid lines of data with invalid lines.
+	// The invalid lines are reported by the error Write returns.
+	DropInvalidLines bool
 }
 
 var _ platform.WriteService = (*WriteService)(nil)
@@ -339,6 +696,9 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 	params.Set("org", string(org))
 	params.Set("bucket", string(bucket))
 	params.Set("precision", string(precision))
+	if s.DropInvalidLines {
+		params.Set("drop_invalid", "true")
+	}
 	req.URL.RawQuery = params.Encode()
 
 	hc := NewClient(u.Scheme, s.InsecureSkipVerify)
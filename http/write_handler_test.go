@@ -1,16 +1,28 @@
 package http
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
+	"github.com/golang/snappy"
 	platform "github.com/influxdata/influxdb"
+	pcontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/models"
+	platformtesting "github.com/influxdata/influxdb/testing"
+	"github.com/influxdata/influxdb/writehook"
+	"go.uber.org/zap"
 )
 
 func TestWriteService_Write(t *testing.T) {
@@ -69,3 +81,532 @@ func TestWriteService_Write(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteHandler_handleWrite(t *testing.T) {
+	const body = "m,t=a f=1 1\nm,t=b f=2 2\nm,t=c s=\"multi\nline\" 3\n"
+
+	encode := map[string]func(t *testing.T, b []byte) []byte{
+		"": func(t *testing.T, b []byte) []byte { return b },
+		"gzip": func(t *testing.T, b []byte) []byte {
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			if _, err := gw.Write(b); err != nil {
+				t.Fatal(err)
+			}
+			if err := gw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			return buf.Bytes()
+		},
+		"snappy": func(t *testing.T, b []byte) []byte {
+			var buf bytes.Buffer
+			sw := snappy.NewBufferedWriter(&buf)
+			if _, err := sw.Write(b); err != nil {
+				t.Fatal(err)
+			}
+			if err := sw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			return buf.Bytes()
+		},
+	}
+
+	for encoding, fn := range encode {
+		t.Run("encoding "+encoding, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := newTestWriteHandler(pw)
+
+			r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002", bytes.NewReader(fn(t, []byte(body))))
+			if encoding != "" {
+				r.Header.Set("Content-Encoding", encoding)
+			}
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusNoContent, w.Body.String())
+			}
+			if len(pw.Points) != 3 {
+				t.Fatalf("got %d points, want 3", len(pw.Points))
+			}
+		})
+	}
+}
+
+func TestWriteHandler_handleWrite_dryRun(t *testing.T) {
+	const body = `# comment
+cpu,host=a usage=1.5 1562000000000000000
+cpu,host=b usage=2i 1562000000000000000
+cpu,host=c usage=
+mem,time=x free=3i 1562000000000000000
+mem free=4i 1562000000
+`
+
+	pw := &mock.PointsWriter{}
+	h := newTestWriteHandler(pw)
+
+	r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002&dry_run=true", strings.NewReader(body))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(pw.Points) != 0 {
+		t.Errorf("expected a dry run not to write points, got %d", len(pw.Points))
+	}
+
+	want := `
+{
+  "valid": false,
+  "lines": 5,
+  "points": 2,
+  "rejected": 3,
+  "errors": [
+    {"line": 3, "message": "field type conflict: field \"usage\" of measurement \"cpu\" is integer, but was float on line 2"},
+    {"line": 4, "message": "missing field value"},
+    {"line": 5, "message": "invalid tag key: input tag \"time\" on measurement \"mem\" is invalid"}
+  ],
+  "warnings": [
+    {"line": 6, "message": "timestamp 1970-01-01T00:00:01.562Z is before 1971; is the precision \"ns\" right?"}
+  ]
+}
+`
+	if eq, diff, err := jsonEqual(w.Body.String(), want); err != nil {
+		t.Fatalf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("unexpected body: %s", diff)
+	}
+}
+
+func TestWriteHandler_handleWrite_rejectedLines(t *testing.T) {
+	const body = "m f=1 1\nm f=\nm f=3 3\n"
+
+	tests := []struct {
+		name       string
+		query      string
+		wantPoints int
+		wantBody   string
+	}{
+		{
+			name:       "rejects the batch",
+			wantPoints: 0,
+			wantBody: `
+{
+  "code": "invalid",
+  "op": "http/handleWrite",
+  "message": "1 of 3 lines were rejected and 0 points were written",
+  "line": 2,
+  "lines": 3,
+  "accepted": 0,
+  "rejectedLines": 1,
+  "rejected": [{"line": 2, "message": "missing field value"}]
+}
+`,
+		},
+		{
+			name:       "drops invalid lines",
+			query:      "&drop_invalid=true",
+			wantPoints: 2,
+			wantBody: `
+{
+  "code": "invalid",
+  "op": "http/handleWrite",
+  "message": "1 of 3 lines were rejected and 2 points were written",
+  "line": 2,
+  "lines": 3,
+  "accepted": 2,
+  "rejectedLines": 1,
+  "rejected": [{"line": 2, "message": "missing field value"}]
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := newTestWriteHandler(pw)
+
+			r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002"+tt.query, strings.NewReader(body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+			if got := w.Header().Get(PlatformErrorCodeHeader); got != platform.EInvalid {
+				t.Errorf("got error code header %q, want %q", got, platform.EInvalid)
+			}
+			if len(pw.Points) != tt.wantPoints {
+				t.Errorf("got %d points, want %d", len(pw.Points), tt.wantPoints)
+			}
+			if eq, diff, err := jsonEqual(w.Body.String(), tt.wantBody); err != nil {
+				t.Fatalf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("unexpected body: %s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteHandler_handleWrite_restrictedMeasurements(t *testing.T) {
+	const body = "cpu f=1 1\nmem f=2 2\ncpu f=3 3\n"
+
+	write := func(bucketID platform.ID, measurements ...string) platform.Permission {
+		return platform.Permission{
+			Action:       platform.WriteAction,
+			Resource:     platform.Resource{Type: platform.BucketsResourceType, OrgID: platformtesting.IDPtr(1), ID: &bucketID},
+			Measurements: measurements,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		permissions []platform.Permission
+		wantCode    int
+		wantPoints  int
+		wantBody    string
+	}{
+		{
+			name:        "allowed measurements",
+			permissions: []platform.Permission{write(2, "cpu", "mem")},
+			wantCode:    http.StatusNoContent,
+			wantPoints:  3,
+		},
+		{
+			name:        "other measurements are rejected",
+			permissions: []platform.Permission{write(2, "cpu")},
+			wantCode:    http.StatusBadRequest,
+			wantPoints:  2,
+			wantBody: `
+{
+  "code": "invalid",
+  "op": "http/handleWrite",
+  "message": "1 of 3 lines were rejected and 2 points were written",
+  "line": 2,
+  "lines": 3,
+  "accepted": 2,
+  "rejectedLines": 1,
+  "rejected": [{"line": 2, "message": "measurement \"mem\" is not allowed by the permissions of the token"}]
+}
+`,
+		},
+		{
+			name:        "measurements of other buckets",
+			permissions: []platform.Permission{write(3, "cpu", "mem")},
+			wantCode:    http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pw := &mock.PointsWriter{}
+			h := newTestWriteHandler(pw)
+
+			r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002&drop_invalid=true", strings.NewReader(body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: tt.permissions}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("got status %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if len(pw.Points) != tt.wantPoints {
+				t.Errorf("got %d points, want %d", len(pw.Points), tt.wantPoints)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if eq, diff, err := jsonEqual(w.Body.String(), tt.wantBody); err != nil {
+				t.Fatalf("error unmarshaling json %v", err)
+			} else if !eq {
+				t.Errorf("unexpected body: %s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteHandler_handleWrite_explicitSchema(t *testing.T) {
+	const body = "cpu,host=a usage=1 1\ncpu,host=a usage=2i 2\ncpu,region=b usage=3 3\ncpu,host=a idle=4 4\nmem,host=a used=5 5\n"
+
+	pw := &mock.PointsWriter{}
+	h := newTestWriteHandler(pw)
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(_ context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: *f.ID, OrgID: *f.OrganizationID, SchemaType: platform.BucketSchemaTypeExplicit}, nil
+	}
+	h.BucketService = buckets
+	schemas := mock.NewMeasurementSchemaService()
+	schemas.FindMeasurementSchemasFn = func(_ context.Context, f platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error) {
+		return []*platform.MeasurementSchema{{
+			BucketID: f.BucketID,
+			Name:     "cpu",
+			Columns: []platform.MeasurementSchemaColumn{
+				{Name: "time", Type: platform.SemanticColumnTypeTimestamp},
+				{Name: "host", Type: platform.SemanticColumnTypeTag},
+				{Name: "usage", Type: platform.SemanticColumnTypeField, DataType: platform.SchemaColumnDataTypeFloat},
+			},
+		}}, nil
+	}
+	h.MeasurementSchemaService = schemas
+
+	r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002&drop_invalid=true", strings.NewReader(body))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+	if len(pw.Points) != 1 {
+		t.Errorf("got %d points, want 1", len(pw.Points))
+	}
+	want := `
+{
+  "code": "invalid",
+  "op": "http/handleWrite",
+  "message": "4 of 5 lines were rejected and 1 points were written",
+  "line": 2,
+  "lines": 5,
+  "accepted": 1,
+  "rejectedLines": 4,
+  "rejected": [
+    {"line": 2, "message": "field type conflict: field \"usage\" of measurement \"cpu\" is integer, but its schema declares float"},
+    {"line": 3, "message": "tag \"region\" is not a tag of the schema of measurement \"cpu\""},
+    {"line": 4, "message": "field \"idle\" is not a field of the schema of measurement \"cpu\""},
+    {"line": 5, "message": "measurement \"mem\" has no schema in the bucket, which has an explicit schema type"}
+  ]
+}
+`
+	if eq, diff, err := jsonEqual(w.Body.String(), want); err != nil {
+		t.Fatalf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("unexpected body: %s", diff)
+	}
+}
+
+func TestWriteHandler_handleWrite_writeHooks(t *testing.T) {
+	writehook.Register("test-handle-write", writehook.HookFunc(func(_ context.Context, _ *platform.Bucket, points []models.Point) ([]models.Point, error) {
+		var out []models.Point
+		for _, p := range points {
+			if p.Tags().GetString("host") == "" {
+				return nil, fmt.Errorf("point of measurement %q has no host", p.Name())
+			}
+			if string(p.Name()) == "debug" {
+				continue
+			}
+			fields, err := p.Fields()
+			if err != nil {
+				return nil, err
+			}
+			tags := p.Tags().Clone()
+			tags.Set([]byte("dc"), []byte("west"))
+			out = append(out, models.MustNewPoint(string(p.Name()), tags, fields, p.Time()))
+		}
+		return out, nil
+	}))
+
+	const body = "cpu,host=a usage=1 1\ncpu usage=2 2\ndebug,host=a msg=\"x\" 3\n"
+
+	pw := &mock.PointsWriter{}
+	h := newTestWriteHandler(pw)
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(_ context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: *f.ID, OrgID: *f.OrganizationID, WriteHooks: []string{"test-handle-write"}}, nil
+	}
+	h.BucketService = buckets
+
+	r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002&drop_invalid=true", strings.NewReader(body))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+	if len(pw.Points) != 1 {
+		t.Fatalf("got %d points, want 1", len(pw.Points))
+	}
+	if p := pw.Points[0]; p.Tags().GetString("dc") != "west" || p.Tags().GetString("host") != "a" || p.Time().UnixNano() != 1 {
+		t.Errorf("unexpected point written: %s", p)
+	}
+	want := `
+{
+  "code": "invalid",
+  "op": "http/handleWrite",
+  "message": "1 of 3 lines were rejected and 1 points were written",
+  "line": 2,
+  "lines": 3,
+  "accepted": 1,
+  "rejectedLines": 1,
+  "rejected": [
+    {"line": 2, "message": "write hook \"test-handle-write\": point of measurement \"cpu\" has no host"}
+  ]
+}
+`
+	if eq, diff, err := jsonEqual(w.Body.String(), want); err != nil {
+		t.Fatalf("error unmarshaling json %v", err)
+	} else if !eq {
+		t.Errorf("unexpected body: %s", diff)
+	}
+}
+
+func TestWriteHandler_handleWrite_overQuota(t *testing.T) {
+	const body = "m f=1 1\nm f=2 2\n"
+
+	pw := &mock.PointsWriter{}
+	h := newTestWriteHandler(pw)
+	quotas := mock.NewBucketQuotaService()
+	quotas.ReserveWriteFn = func(_ context.Context, b *platform.Bucket, keys [][]byte, bytes int64) (time.Duration, error) {
+		if len(keys) != 2 || bytes != int64(len(body)) {
+			t.Errorf("got reservation of %d points and %d bytes, want 2 points and %d bytes", len(keys), bytes, len(body))
+		}
+		return 1500 * time.Millisecond, &platform.Error{
+			Code: platform.ETooManyRequests,
+			Msg:  "bucket \"telegraf\" is over its quota of 1 points per second",
+		}
+	}
+	h.BucketQuotaService = quotas
+
+	r := httptest.NewRequest("POST", "/api/v2/write?org=0000000000000001&bucket=0000000000000002", strings.NewReader(body))
+	r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{Status: platform.Active, Permissions: platform.OperPermissions()}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want %d: %s", w.Code, http.StatusTooManyRequests, w.Body.String())
+	}
+	if got, want := w.Header().Get("Retry-After"), "2"; got != want {
+		t.Errorf("got Retry-After %q, want %q", got, want)
+	}
+	if len(pw.Points) != 0 {
+		t.Errorf("expected a write over quota not to write points, got %d", len(pw.Points))
+	}
+}
+
+func TestWriteHandler_handleWrite_recordsRejections(t *testing.T) {
+	const body = "m f=1 1\nm f=\nm f=3 3\n"
+
+	auth := &platform.Authorization{Token: "secret", Status: platform.Active, Permissions: platform.OperPermissions()}
+	forbidden := &platform.Authorization{Token: "other", Status: platform.Active}
+
+	tests := []struct {
+		name string
+		auth platform.Authorizer
+		url  string
+		want []platform.WriteRejection
+	}{
+		{
+			name: "invalid lines",
+			auth: auth,
+			url:  "/api/v2/write?org=0000000000000001&bucket=0000000000000002",
+			want: []platform.WriteRejection{
+				{OrgID: 1, BucketID: 2, Reason: platform.WriteRejectionInvalidLine, TokenHash: platform.HashToken("secret"), Line: []byte("m f="), Message: "missing field value"},
+			},
+		},
+		{
+			name: "forbidden",
+			auth: forbidden,
+			url:  "/api/v2/write?org=0000000000000001&bucket=0000000000000002",
+			want: []platform.WriteRejection{
+				{OrgID: 1, BucketID: 2, Reason: platform.WriteRejectionForbidden, TokenHash: platform.HashToken("other"), Message: "insufficient permissions for write"},
+			},
+		},
+		{
+			name: "bucket not found",
+			auth: auth,
+			url:  "/api/v2/write?org=0000000000000001&bucket=telegraf",
+			want: []platform.WriteRejection{
+				{OrgID: 1, Reason: platform.WriteRejectionNoBucket, TokenHash: platform.HashToken("secret"), Message: "bucket not found"},
+			},
+		},
+		{
+			name: "dry run",
+			auth: auth,
+			url:  "/api/v2/write?org=0000000000000001&bucket=0000000000000002&dry_run=true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestWriteHandler(&mock.PointsWriter{})
+			buckets := mock.NewBucketService()
+			buckets.FindBucketFn = func(_ context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+				if f.ID == nil {
+					return nil, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+				}
+				return &platform.Bucket{ID: *f.ID, OrgID: *f.OrganizationID}, nil
+			}
+			h.BucketService = buckets
+			rejections := &mock.WriteRejectionRecorder{}
+			h.RejectionRecorder = rejections
+
+			r := httptest.NewRequest("POST", tt.url, strings.NewReader(body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), tt.auth))
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			if !reflect.DeepEqual(rejections.Rejections, tt.want) {
+				t.Errorf("got rejections %+v, want %+v", rejections.Rejections, tt.want)
+			}
+		})
+	}
+}
+
+func newTestWriteHandler(pw *mock.PointsWriter) *WriteHandler {
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationByIDF = func(_ context.Context, id platform.ID) (*platform.Organization, error) {
+		return &platform.Organization{ID: id}, nil
+	}
+	buckets := mock.NewBucketService()
+	buckets.FindBucketFn = func(_ context.Context, f platform.BucketFilter) (*platform.Bucket, error) {
+		return &platform.Bucket{ID: *f.ID, OrgID: *f.OrganizationID}, nil
+	}
+
+	return NewWriteHandler(&WriteBackend{
+		HTTPErrorHandler:    ErrorHandler(0),
+		Logger:              zap.NewNop(),
+		WriteEventRecorder:  noopEventRecorder{},
+		PointsWriter:        pw,
+		BucketService:       buckets,
+		OrganizationService: orgs,
+	})
+}
+
+func TestLineBatchReader(t *testing.T) {
+	const body = "m f=1 1\nm f=2 2\nlong s=\"0123456789\" 3\nm s=\"a\nb\" 4\nm f=5 5"
+
+	// A one byte reader and small batches split lines across reads and batches.
+	br := newLineBatchReader(iotest.OneByteReader(strings.NewReader(body)), 8, 64)
+	var batches []string
+	for {
+		b, err := br.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		batches = append(batches, string(b))
+	}
+
+	want := []string{"m f=1 1\n", "m f=2 2\n", "long s=\"0123456789\" 3\n", "m s=\"a\nb\" 4\n", "m f=5 5"}
+	if strings.Join(batches, "|") != strings.Join(want, "|") {
+		t.Errorf("got batches %q, want %q", batches, want)
+	}
+	if br.n != len(body) {
+		t.Errorf("got %d bytes read, want %d", br.n, len(body))
+	}
+
+	br = newLineBatchReader(strings.NewReader(body), 8, 16)
+	for {
+		_, err := br.Next()
+		if err == errWriteLineTooLong {
+			break
+		} else if err != nil {
+			t.Fatalf("expected errWriteLineTooLong, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/writehook"
+)
+
+// lineHooks runs the write hooks of a bucket on the lines of line protocol
+// written to it.
+type lineHooks struct {
+	hook   writehook.Hook
+	bucket *platform.Bucket
+	// mm is the encoded name of the bucket the points are parsed for.
+	mm        []byte
+	now       time.Time
+	precision string
+}
+
+// newLineHooks returns the write hooks of a bucket, or nil if it has none.
+func newLineHooks(bucket *platform.Bucket, mm []byte, now time.Time, precision string) (*lineHooks, error) {
+	hook, err := writehook.Chain(bucket.WriteHooks)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  "http/handleWrite",
+			Err: err,
+		}
+	}
+	if hook == nil {
+		return nil, nil
+	}
+	return &lineHooks{
+		hook:      hook,
+		bucket:    bucket,
+		mm:        mm,
+		now:       now,
+		precision: precision,
+	}, nil
+}
+
+// apply returns line with the points the hooks return for the points of the line,
+// parsed like the points of the lines written to the bucket. A line that the hooks
+// reject has their error.
+func (lh *lineHooks) apply(ctx context.Context, line models.Line) models.Line {
+	if lh == nil || line.Err != nil {
+		return line
+	}
+
+	points, err := models.ParsePointsWithPrecisionV1(line.Text, nil, lh.now, lh.precision)
+	if err == nil {
+		points, err = lh.hook.ProcessPoints(ctx, lh.bucket, points)
+	}
+	if err != nil {
+		line.Points, line.Err = nil, err
+		return line
+	}
+
+	// The points the hooks return are written as line protocol, so that they are
+	// checked and encoded for the bucket like the points of any other line.
+	var buf []byte
+	for _, p := range points {
+		buf = p.AppendString(buf)
+		buf = append(buf, '\n')
+	}
+	line.Points = nil
+	for _, l := range models.ParseLinesWithPrecision(buf, lh.mm, lh.now, "ns", line.Number) {
+		if l.Err != nil {
+			line.Points, line.Err = nil, l.Err
+			return line
+		}
+		line.Points = append(line.Points, l.Points...)
+	}
+	return line
+}
@@ -0,0 +1,239 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+)
+
+// writeMaxLineErrors is the most errors or warnings about lines reported in a response.
+const writeMaxLineErrors = 1000
+
+// writeLineError is an error or a warning about a line of line protocol.
+type writeLineError struct {
+	// Line is the number of the line in the body of the request, starting at 1.
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// writeValidationResponse is the response to a dry-run write.
+type writeValidationResponse struct {
+	Valid bool `json:"valid"`
+	// Lines is the number of lines of line protocol, ignoring blank lines and comments.
+	Lines int `json:"lines"`
+	// Points is the number of points the valid lines would write.
+	Points int `json:"points"`
+	// Rejected is the number of lines with errors, which may exceed the number of errors reported.
+	Rejected int              `json:"rejected"`
+	Errors   []writeLineError `json:"errors"`
+	Warnings []writeLineError `json:"warnings"`
+}
+
+func newWriteValidationResponse() *writeValidationResponse {
+	return &writeValidationResponse{
+		Valid:    true,
+		Errors:   []writeLineError{},
+		Warnings: []writeLineError{},
+	}
+}
+
+// add adds the result of validating a line to the response.
+func (res *writeValidationResponse) add(line models.Line, warnings []string, err error) {
+	res.Lines++
+	if err != nil {
+		res.Valid = false
+		res.Rejected++
+		if len(res.Errors) < writeMaxLineErrors {
+			res.Errors = append(res.Errors, writeLineError{Line: line.Number, Message: err.Error()})
+		}
+		return
+	}
+
+	res.Points += len(line.Points)
+	for _, w := range warnings {
+		if len(res.Warnings) < writeMaxLineErrors {
+			res.Warnings = append(res.Warnings, writeLineError{Line: line.Number, Message: w})
+		}
+	}
+}
+
+// partialWriteResponse is the response to a write with invalid lines.
+// It extends the error responses with the lines that were rejected.
+type partialWriteResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Op      string `json:"op"`
+	// Line is the number of the first rejected line.
+	Line int `json:"line"`
+	// Lines is the number of lines of line protocol, ignoring blank lines and comments.
+	Lines int `json:"lines"`
+	// Accepted is the number of points that were written.
+	Accepted int `json:"accepted"`
+	// RejectedLines is the number of rejected lines, which may exceed the number of lines in Rejected.
+	RejectedLines int              `json:"rejectedLines"`
+	Rejected      []writeLineError `json:"rejected"`
+}
+
+func newPartialWriteResponse() *partialWriteResponse {
+	return &partialWriteResponse{
+		Code:     platform.EInvalid,
+		Op:       "http/handleWrite",
+		Rejected: []writeLineError{},
+	}
+}
+
+func (res *partialWriteResponse) reject(line models.Line, err error) {
+	res.Lines++
+	if res.RejectedLines == 0 {
+		res.Line = line.Number
+	}
+	res.RejectedLines++
+	if len(res.Rejected) < writeMaxLineErrors {
+		res.Rejected = append(res.Rejected, writeLineError{Line: line.Number, Message: err.Error()})
+	}
+}
+
+// fieldSchema is the type of a field of a measurement and the line that set it.
+type fieldSchema struct {
+	typ  models.FieldType
+	line int
+}
+
+// lineValidator checks lines of line protocol for the errors that keep their points
+// from being stored.
+type lineValidator struct {
+	// now is the time of points without a timestamp.
+	now       time.Time
+	precision string
+	// fields holds the type of each field seen so far, by measurement and field key.
+	fields map[string]map[string]fieldSchema
+	// measurements are the measurements the write may write to, or nil if it may write any.
+	measurements map[string]bool
+	// schemas are the schemas of the measurements of a bucket with an explicit schema type,
+	// by measurement, or nil if the bucket takes the schemas from the points written.
+	schemas map[string]*platform.MeasurementSchema
+}
+
+func newLineValidator(now time.Time, precision string) *lineValidator {
+	return &lineValidator{
+		now:       now,
+		precision: precision,
+		fields:    make(map[string]map[string]fieldSchema),
+	}
+}
+
+// writeMinPlausibleTime is the earliest timestamp that isn't reported as a likely precision mistake.
+// Timestamps of the current time, written with a coarser precision than the one of the request,
+// fall well before it.
+var writeMinPlausibleTime = time.Date(1971, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// validate returns warnings about the mistakes in line that don't keep its points from
+// being stored, and the error that does, if any.
+// A field that has a different type than in an earlier line is an error,
+// so that the types of the fields of valid lines are consistent.
+func (v *lineValidator) validate(line models.Line) ([]string, error) {
+	if line.Err != nil {
+		return nil, line.Err
+	}
+
+	type typedField struct {
+		measurement, key string
+		typ              models.FieldType
+	}
+	fields := make([]typedField, 0, len(line.Points))
+	for _, p := range line.Points {
+		measurement := p.Tags().Get(models.MeasurementTagKeyBytes)
+		if v.measurements != nil && !v.measurements[string(measurement)] {
+			return nil, fmt.Errorf("measurement %q is not allowed by the permissions of the token", measurement)
+		}
+		if err := storage.ValidateSeries(p.Key(), measurement, p.Tags()); err != nil {
+			return nil, err
+		}
+		if v.schemas != nil {
+			if err := validatePointSchema(v.schemas, measurement, p); err != nil {
+				return nil, err
+			}
+		}
+
+		iter := p.FieldIterator()
+		for iter.Next() {
+			f := typedField{measurement: string(measurement), key: string(iter.FieldKey()), typ: iter.Type()}
+			if s, ok := v.fields[f.measurement][f.key]; ok && s.typ != f.typ {
+				return nil, fmt.Errorf("field type conflict: field %q of measurement %q is %s, but was %s on line %d",
+					f.key, f.measurement, fieldTypeName(f.typ), fieldTypeName(s.typ), s.line)
+			}
+			fields = append(fields, f)
+		}
+	}
+
+	// Only record the types of the fields of valid lines.
+	for _, f := range fields {
+		if v.fields[f.measurement] == nil {
+			v.fields[f.measurement] = make(map[string]fieldSchema)
+		}
+		if _, ok := v.fields[f.measurement][f.key]; !ok {
+			v.fields[f.measurement][f.key] = fieldSchema{typ: f.typ, line: line.Number}
+		}
+	}
+
+	var warnings []string
+	if len(line.Points) > 0 {
+		if t := line.Points[0].Time(); !t.Equal(v.now) && t.Before(writeMinPlausibleTime) {
+			warnings = append(warnings, fmt.Sprintf("timestamp %s is before 1971; is the precision %q right?", t.UTC().Format(time.RFC3339Nano), v.precision))
+		}
+	}
+	return warnings, nil
+}
+
+// validatePointSchema returns an error if a point written to a bucket with an
+// explicit schema type does not match the schema of its measurement: if the
+// measurement has no schema, or the point has tags or fields the schema does not
+// declare, or fields of other types than the schema declares.
+func validatePointSchema(schemas map[string]*platform.MeasurementSchema, measurement []byte, p models.Point) error {
+	s, ok := schemas[string(measurement)]
+	if !ok {
+		return fmt.Errorf("measurement %q has no schema in the bucket, which has an explicit schema type", measurement)
+	}
+
+	for _, t := range p.Tags() {
+		if bytes.Equal(t.Key, models.MeasurementTagKeyBytes) || bytes.Equal(t.Key, models.FieldKeyTagKeyBytes) {
+			continue
+		}
+		if c, ok := s.Column(string(t.Key)); !ok || c.Type != platform.SemanticColumnTypeTag {
+			return fmt.Errorf("tag %q is not a tag of the schema of measurement %q", t.Key, measurement)
+		}
+	}
+
+	iter := p.FieldIterator()
+	for iter.Next() {
+		key := iter.FieldKey()
+		c, ok := s.Column(string(key))
+		if !ok || c.Type != platform.SemanticColumnTypeField {
+			return fmt.Errorf("field %q is not a field of the schema of measurement %q", key, measurement)
+		}
+		if typ := fieldTypeName(iter.Type()); typ != string(c.DataType) {
+			return fmt.Errorf("field type conflict: field %q of measurement %q is %s, but its schema declares %s", key, measurement, typ, c.DataType)
+		}
+	}
+	return nil
+}
+
+func fieldTypeName(t models.FieldType) string {
+	switch t {
+	case models.Float:
+		return "float"
+	case models.Integer:
+		return "integer"
+	case models.Unsigned:
+		return "unsigned"
+	case models.String:
+		return "string"
+	case models.Boolean:
+		return "boolean"
+	}
+	return "unknown"
+}
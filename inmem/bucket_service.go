@@ -123,7 +123,7 @@ func (s *Service) FindBucket(ctx context.Context, filter platform.BucketFilter)
 	}
 
 	// filter by bucket id
-	if filter.ID != nil {
+	if filter.ID != nil && len(filter.Metadata) == 0 {
 		b, err = s.FindBucketByID(ctx, *filter.ID)
 		if err != nil {
 			return nil, &platform.Error{
@@ -165,6 +165,9 @@ func (s *Service) findBuckets(ctx context.Context, filter platform.BucketFilter,
 				Err: err,
 			}
 		}
+		if !b.Metadata.Matches(filter.Metadata) {
+			return []*platform.Bucket{}, nil
+		}
 
 		return []*platform.Bucket{b}, nil
 	}
@@ -197,6 +200,13 @@ func (s *Service) findBuckets(ctx context.Context, filter platform.BucketFilter,
 		}
 	}
 
+	if len(filter.Metadata) > 0 {
+		fn := filterFunc
+		filterFunc = func(b *platform.Bucket) bool {
+			return fn(b) && b.Metadata.Matches(filter.Metadata)
+		}
+	}
+
 	bs, err := s.filterBuckets(ctx, filterFunc, opt...)
 	if err != nil {
 		return nil, &platform.Error{
@@ -241,6 +251,18 @@ func (s *Service) CreateBucket(ctx context.Context, b *platform.Bucket) error {
 			Msg:  fmt.Sprintf("bucket with name %s already exists", b.Name),
 		}
 	}
+	if err := b.Metadata.Valid(); err != nil {
+		return &platform.Error{
+			Op:  OpPrefix + platform.OpCreateBucket,
+			Err: err,
+		}
+	}
+	if err := b.SchemaType.Valid(); err != nil {
+		return &platform.Error{
+			Op:  OpPrefix + platform.OpCreateBucket,
+			Err: err,
+		}
+	}
 	b.ID = s.IDGenerator.ID()
 	b.CreatedAt = s.Now()
 	b.UpdatedAt = s.Now()
@@ -272,10 +294,35 @@ func (s *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ReorderWindow != nil {
+		b.ReorderWindow = *upd.ReorderWindow
+	}
+
+	if upd.Quota != nil {
+		b.Quota = *upd.Quota
+	}
+
+	if upd.QueryCacheDisabled != nil {
+		b.QueryCacheDisabled = *upd.QueryCacheDisabled
+	}
+
+	if upd.WriteHooks != nil {
+		b.WriteHooks = *upd.WriteHooks
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
 
+	if upd.Metadata != nil {
+		if b.Metadata, err = upd.Metadata.Apply(b.Metadata); err != nil {
+			return nil, &platform.Error{
+				Op:  OpPrefix + platform.OpUpdateBucket,
+				Err: err,
+			}
+		}
+	}
+
 	b0, err := s.FindBucket(ctx, platform.BucketFilter{
 		Name: upd.Name,
 	})
@@ -39,6 +39,16 @@ func (s *Service) FindDashboardByID(ctx context.Context, id platform.ID) (*platf
 }
 
 func filterDashboardFn(filter platform.DashboardFilter) func(d *platform.Dashboard) bool {
+	fn := filterDashboardByIDFn(filter)
+	if len(filter.Metadata) == 0 {
+		return fn
+	}
+	return func(d *platform.Dashboard) bool {
+		return fn(d) && d.Metadata.Matches(filter.Metadata)
+	}
+}
+
+func filterDashboardByIDFn(filter platform.DashboardFilter) func(d *platform.Dashboard) bool {
 	if filter.OrganizationID != nil {
 		return func(d *platform.Dashboard) bool {
 			return d.OrganizationID == *filter.OrganizationID
@@ -98,7 +108,7 @@ func (s *Service) FindDashboards(ctx context.Context, filter platform.DashboardF
 				Op:  op,
 			}
 		}
-		if d == nil {
+		if d == nil || !d.Metadata.Matches(filter.Metadata) {
 			return ds, 0, nil
 		}
 		return []*platform.Dashboard{d}, 1, nil
@@ -128,6 +138,12 @@ func (s *Service) FindDashboards(ctx context.Context, filter platform.DashboardF
 
 // CreateDashboard implements platform.DashboardService interface.
 func (s *Service) CreateDashboard(ctx context.Context, d *platform.Dashboard) error {
+	if err := d.Metadata.Valid(); err != nil {
+		return &platform.Error{
+			Err: err,
+			Op:  platform.OpCreateDashboard,
+		}
+	}
 	d.ID = s.IDGenerator.ID()
 	d.Meta.CreatedAt = s.Now()
 	d.Meta.UpdatedAt = s.Now()
@@ -122,25 +122,39 @@ func (s *Service) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilte
 }
 
 // Create creates a new dbrp mapping.
+// Creating a default mapping makes the other mappings of its database non-default.
 func (s *Service) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
 	if err := m.Validate(); err != nil {
 		return nil
 	}
 	existing, err := s.loadDBRPMapping(ctx, m.Cluster, m.Database, m.RetentionPolicy)
-	if err != nil {
-		if err == errDBRPMappingNotFound {
-			return s.PutDBRPMapping(ctx, m)
-		}
+	if err != nil && err != errDBRPMappingNotFound {
 		return err
 	}
 
-	if !existing.Equal(m) {
+	if existing != nil && !existing.Equal(m) {
 		return &influxdb.Error{
 			Code: influxdb.EConflict,
 			Msg:  "dbrp mapping already exists",
 		}
 	}
 
+	if m.Default {
+		defaults, err := s.filterDBRPMappings(ctx, func(o *influxdb.DBRPMapping) bool {
+			return o.Default && o.Cluster == m.Cluster && o.Database == m.Database &&
+				o.OrganizationID == m.OrganizationID && o.RetentionPolicy != m.RetentionPolicy
+		})
+		if err != nil {
+			return err
+		}
+		for _, o := range defaults {
+			o.Default = false
+			if err := s.PutDBRPMapping(ctx, o); err != nil {
+				return err
+			}
+		}
+	}
+
 	return s.PutDBRPMapping(ctx, m)
 }
 
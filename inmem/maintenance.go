@@ -0,0 +1,46 @@
+package inmem
+
+import (
+	"context"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.MaintenanceService = (*MaintenanceService)(nil)
+
+// MaintenanceService keeps the maintenance state of a single node in memory.
+// The state is not persisted, so a restarted node comes back with the state it was launched with.
+type MaintenanceService struct {
+	mu     sync.RWMutex
+	status platform.MaintenanceStatus
+}
+
+// NewMaintenanceService creates a MaintenanceService starting in the given state.
+func NewMaintenanceService(initial platform.MaintenanceStatus) *MaintenanceService {
+	return &MaintenanceService{status: initial}
+}
+
+// MaintenanceStatus returns the current maintenance state.
+func (s *MaintenanceService) MaintenanceStatus(ctx context.Context) (*platform.MaintenanceStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	status := s.status
+	return &status, nil
+}
+
+// UpdateMaintenanceStatus updates the maintenance state.
+func (s *MaintenanceService) UpdateMaintenanceStatus(ctx context.Context, upd platform.MaintenanceStatusUpdate) (*platform.MaintenanceStatus, error) {
+	if err := upd.Valid(); err != nil {
+		return nil, &platform.Error{
+			Op:  OpPrefix + platform.OpUpdateMaintenanceStatus,
+			Err: err,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upd.Apply(&s.status)
+	status := s.status
+	return &status, nil
+}
@@ -26,3 +26,11 @@ func initOnboardingService(f platformtesting.OnboardingFields, t *testing.T) (pl
 func TestGenerate(t *testing.T) {
 	platformtesting.Generate(initOnboardingService, t)
 }
+
+func TestRegenerate(t *testing.T) {
+	platformtesting.Regenerate(initOnboardingService, t)
+}
+
+func TestOnboardUser(t *testing.T) {
+	platformtesting.OnboardUser(initOnboardingService, t)
+}
@@ -189,6 +189,28 @@ func (s *Service) CreateOrganization(ctx context.Context, o *platform.Organizati
 			Msg:  fmt.Sprintf("organization with name %s already exists", o.Name),
 		}
 	}
+	if _, err := platform.LoadTimezone(o.Timezone); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	if o.BucketDefaults != nil {
+		if err := o.BucketDefaults.Valid(); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+	}
+	if o.NamePolicy != nil {
+		if err := o.NamePolicy.Valid(); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+	}
 	o.ID = s.IDGenerator.ID()
 	o.CreatedAt = s.Now()
 	o.UpdatedAt = s.Now()
@@ -235,6 +257,33 @@ func (s *Service) UpdateOrganization(ctx context.Context, id platform.ID, upd pl
 		o.Description = *upd.Description
 	}
 
+	if upd.Timezone != nil {
+		if _, err := platform.LoadTimezone(*upd.Timezone); err != nil {
+			return nil, err
+		}
+		o.Timezone = *upd.Timezone
+	}
+
+	if upd.BucketDefaults != nil {
+		if err := upd.BucketDefaults.Valid(); err != nil {
+			return nil, err
+		}
+		o.BucketDefaults = nil
+		if d := *upd.BucketDefaults; !d.IsZero() {
+			o.BucketDefaults = &d
+		}
+	}
+
+	if upd.NamePolicy != nil {
+		o.NamePolicy = nil
+		if p := *upd.NamePolicy; !p.IsZero() {
+			if err := p.Valid(); err != nil {
+				return nil, err
+			}
+			o.NamePolicy = &p
+		}
+	}
+
 	o.UpdatedAt = s.Now()
 
 	s.organizationKV.Store(o.ID.String(), o)
@@ -0,0 +1,45 @@
+package inmem
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.SystemInfoService = (*SystemInfoService)(nil)
+
+// SystemInfoService reports the build, storage engine and limits a node was
+// launched with, and the current state of its Go runtime.
+type SystemInfoService struct {
+	info    platform.SystemInfo
+	started time.Time
+	now     func() time.Time
+}
+
+// NewSystemInfoService creates a SystemInfoService of a node started now.
+// The runtime of info is ignored.
+func NewSystemInfoService(info platform.SystemInfo) *SystemInfoService {
+	return &SystemInfoService{
+		info:    info,
+		started: time.Now(),
+		now:     time.Now,
+	}
+}
+
+// SystemInfo returns the information about the node.
+func (s *SystemInfoService) SystemInfo(ctx context.Context) (*platform.SystemInfo, error) {
+	info := s.info
+	info.Runtime = platform.SystemRuntimeInfo{
+		GoVersion:  runtime.Version(),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Goroutines: runtime.NumGoroutine(),
+		StartedAt:  s.started.UTC(),
+		Uptime:     s.now().Sub(s.started).Round(time.Second).String(),
+	}
+	return &info, nil
+}
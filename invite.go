@@ -0,0 +1,119 @@
+package influxdb
+
+import (
+	"context"
+	"net/mail"
+	"time"
+)
+
+// ErrInviteNotFound is the error msg for a missing invite.
+const ErrInviteNotFound = "invite not found"
+
+// DefaultInviteTTL is how long an invite can be accepted for when it has no expiration.
+const DefaultInviteTTL = 7 * 24 * time.Hour
+
+// ops for invite errors and op log.
+const (
+	OpCreateInvite      = "CreateInvite"
+	OpFindInviteByID    = "FindInviteByID"
+	OpFindInviteByToken = "FindInviteByToken"
+	OpFindInvites       = "FindInvites"
+	OpRevokeInvite      = "RevokeInvite"
+	OpAcceptInvite      = "AcceptInvite"
+)
+
+// InviteStatus is the state of an invite.
+type InviteStatus string
+
+const (
+	// InvitePending is an invite that can be accepted.
+	InvitePending InviteStatus = "pending"
+	// InviteAccepted is an invite a user was created for.
+	InviteAccepted InviteStatus = "accepted"
+	// InviteExpired is a pending invite that can no longer be accepted.
+	InviteExpired InviteStatus = "expired"
+)
+
+// Invite invites someone to join an organization as a member or owner. The
+// token of the invite is the credential that accepts it, which creates the
+// user and makes them a member or owner of the organization.
+type Invite struct {
+	ID         ID           `json:"id,omitempty"`
+	OrgID      ID           `json:"orgID"`
+	Email      string       `json:"email"`
+	Role       UserType     `json:"role"`
+	Token      string       `json:"token,omitempty"`
+	Status     InviteStatus `json:"status"`
+	ExpiresAt  time.Time    `json:"expiresAt"`
+	UserID     ID           `json:"userID,omitempty"`
+	AcceptedAt *time.Time   `json:"acceptedAt,omitempty"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+// Valid returns an error if the invite has no organization, email or role.
+func (i *Invite) Valid() error {
+	if !i.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if _, err := mail.ParseAddress(i.Email); err != nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "a valid email is required",
+			Err:  err,
+		}
+	}
+	if err := i.Role.Valid(); err != nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role must be owner or member",
+			Err:  err,
+		}
+	}
+	return nil
+}
+
+// StatusAt returns the status of the invite at now; a pending invite expires at its expiration.
+func (i *Invite) StatusAt(now time.Time) InviteStatus {
+	if i.Status == InvitePending && !now.Before(i.ExpiresAt) {
+		return InviteExpired
+	}
+	return i.Status
+}
+
+// InviteFilter selects invites.
+type InviteFilter struct {
+	OrgID *ID
+}
+
+// InviteAcceptance is the user created by accepting an invite.
+// The name of the user is the email of the invite when it is empty.
+type InviteAcceptance struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// InviteService manages the invites of users to organizations.
+type InviteService interface {
+	// CreateInvite creates a pending invite and sets its ID and token. The
+	// invite expires after the DefaultInviteTTL if it has no expiration.
+	CreateInvite(ctx context.Context, i *Invite) error
+
+	// FindInviteByID returns a single invite by ID.
+	FindInviteByID(ctx context.Context, id ID) (*Invite, error)
+
+	// FindInviteByToken returns the invite with the token.
+	FindInviteByToken(ctx context.Context, token string) (*Invite, error)
+
+	// FindInvites returns the invites that match the filter.
+	FindInvites(ctx context.Context, filter InviteFilter) ([]*Invite, error)
+
+	// RevokeInvite deletes an invite so it can no longer be accepted.
+	RevokeInvite(ctx context.Context, id ID) error
+
+	// AcceptInvite accepts the pending invite with the token. It creates the
+	// user with the password and maps them to the organization of the invite.
+	AcceptInvite(ctx context.Context, token string, a InviteAcceptance) (*User, error)
+}
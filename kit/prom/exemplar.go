@@ -0,0 +1,155 @@
+package prom
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// TraceIDLabel is the label of the trace ID of an exemplar.
+const TraceIDLabel = "trace_id"
+
+// Exemplar is an observation of a histogram kept as an example of the
+// observations of its bucket, such as the trace of a slow request.
+type Exemplar struct {
+	Labels    prometheus.Labels
+	Value     float64
+	Timestamp time.Time
+}
+
+// ExemplarObserver is an observer that can keep an observation as an exemplar.
+type ExemplarObserver interface {
+	prometheus.Observer
+	ObserveWithExemplar(v float64, labels prometheus.Labels)
+}
+
+// ObserveWithTraceID observes v, and keeps it as an exemplar with the trace ID
+// if the observer keeps exemplars and the trace ID is not empty.
+func ObserveWithTraceID(o prometheus.Observer, v float64, traceID string) {
+	if eo, ok := o.(ExemplarObserver); ok && traceID != "" {
+		eo.ObserveWithExemplar(v, prometheus.Labels{TraceIDLabel: traceID})
+		return
+	}
+	o.Observe(v)
+}
+
+// HistogramVec is a prometheus.HistogramVec that keeps the last exemplar
+// observed in each bucket of its histograms.
+// The exemplars are exposed when the metrics of a Registry the vector is
+// registered with are requested as OpenMetrics.
+type HistogramVec struct {
+	*prometheus.HistogramVec
+
+	name       string
+	labelNames []string
+	buckets    []float64
+
+	mu        sync.Mutex
+	exemplars map[string][]*Exemplar
+}
+
+// NewHistogramVec returns a new HistogramVec.
+func NewHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *HistogramVec {
+	buckets := opts.Buckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+	return &HistogramVec{
+		HistogramVec: prometheus.NewHistogramVec(opts, labelNames),
+		name:         prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name),
+		labelNames:   labelNames,
+		buckets:      buckets,
+		exemplars:    make(map[string][]*Exemplar),
+	}
+}
+
+// WithLabelValues returns the histogram of the label values,
+// which is an ExemplarObserver.
+func (v *HistogramVec) WithLabelValues(lvs ...string) prometheus.Observer {
+	labels := make(prometheus.Labels, len(lvs))
+	for i, lv := range lvs {
+		if i < len(v.labelNames) {
+			labels[v.labelNames[i]] = lv
+		}
+	}
+	return &exemplarObserver{
+		Observer: v.HistogramVec.WithLabelValues(lvs...),
+		vec:      v,
+		key:      exemplarKey(labels),
+	}
+}
+
+// With returns the histogram of the labels, which is an ExemplarObserver.
+func (v *HistogramVec) With(labels prometheus.Labels) prometheus.Observer {
+	return &exemplarObserver{
+		Observer: v.HistogramVec.With(labels),
+		vec:      v,
+		key:      exemplarKey(labels),
+	}
+}
+
+func (v *HistogramVec) observeExemplar(key string, e *Exemplar) {
+	i := sort.SearchFloat64s(v.buckets, e.Value)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	es, ok := v.exemplars[key]
+	if !ok {
+		// The last exemplar is the one of the +Inf bucket.
+		es = make([]*Exemplar, len(v.buckets)+1)
+		v.exemplars[key] = es
+	}
+	es[i] = e
+}
+
+// exemplar returns the exemplar of the bucket with the upper bound of the
+// histogram with the labels, or nil if it has none.
+func (v *HistogramVec) exemplar(labels []*dto.LabelPair, upperBound float64) *Exemplar {
+	m := make(prometheus.Labels, len(v.labelNames))
+	for _, lp := range labels {
+		for _, name := range v.labelNames {
+			if lp.GetName() == name {
+				m[name] = lp.GetValue()
+			}
+		}
+	}
+	i := sort.SearchFloat64s(v.buckets, upperBound)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	es, ok := v.exemplars[exemplarKey(m)]
+	if !ok || i >= len(es) {
+		return nil
+	}
+	return es[i]
+}
+
+// exemplarKey returns the key of the exemplars of the histogram with the labels.
+func exemplarKey(labels prometheus.Labels) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\xff")
+}
+
+type exemplarObserver struct {
+	prometheus.Observer
+	vec *HistogramVec
+	key string
+}
+
+// ObserveWithExemplar observes v and keeps it as the exemplar of its bucket.
+func (o *exemplarObserver) ObserveWithExemplar(v float64, labels prometheus.Labels) {
+	o.Observer.Observe(v)
+	o.vec.observeExemplar(o.key, &Exemplar{
+		Labels:    labels,
+		Value:     v,
+		Timestamp: time.Now(),
+	})
+}
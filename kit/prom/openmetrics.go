@@ -0,0 +1,189 @@
+package prom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// OpenMetricsContentType is the content type of metrics exposed as OpenMetrics text.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// acceptsOpenMetrics returns true if an Accept header lists OpenMetrics text.
+func acceptsOpenMetrics(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mt != "application/openmetrics-text" {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if v, err := strconv.ParseFloat(q, 64); err != nil || v <= 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// openMetricsWriter writes metric families as OpenMetrics text,
+// with the exemplars of the histograms that keep them.
+type openMetricsWriter struct {
+	w         *bufio.Writer
+	exemplars map[string]*HistogramVec
+}
+
+// writeOpenMetrics writes the metric families as OpenMetrics text to w.
+func writeOpenMetrics(w io.Writer, mfs []*dto.MetricFamily, exemplars map[string]*HistogramVec) error {
+	ow := &openMetricsWriter{w: bufio.NewWriter(w), exemplars: exemplars}
+	for _, mf := range mfs {
+		ow.writeFamily(mf)
+	}
+	ow.w.WriteString("# EOF\n")
+	return ow.w.Flush()
+}
+
+func (ow *openMetricsWriter) writeFamily(mf *dto.MetricFamily) {
+	name := mf.GetName()
+	typ := "unknown"
+	switch mf.GetType() {
+	case dto.MetricType_COUNTER:
+		// The samples of a counter are suffixed with _total, but its name is not.
+		typ, name = "counter", strings.TrimSuffix(name, "_total")
+	case dto.MetricType_GAUGE:
+		typ = "gauge"
+	case dto.MetricType_SUMMARY:
+		typ = "summary"
+	case dto.MetricType_HISTOGRAM:
+		typ = "histogram"
+	}
+	fmt.Fprintf(ow.w, "# TYPE %s %s\n", name, typ)
+	if mf.Help != nil {
+		fmt.Fprintf(ow.w, "# HELP %s %s\n", name, escapeOpenMetrics(mf.GetHelp()))
+	}
+
+	vec := ow.exemplars[mf.GetName()]
+	for _, m := range mf.Metric {
+		switch mf.GetType() {
+		case dto.MetricType_COUNTER:
+			ow.writeSample(name+"_total", m, "", "", m.GetCounter().GetValue(), nil)
+		case dto.MetricType_GAUGE:
+			ow.writeSample(name, m, "", "", m.GetGauge().GetValue(), nil)
+		case dto.MetricType_SUMMARY:
+			s := m.GetSummary()
+			for _, q := range s.Quantile {
+				ow.writeSample(name, m, "quantile", formatFloat(q.GetQuantile()), q.GetValue(), nil)
+			}
+			ow.writeSample(name+"_sum", m, "", "", s.GetSampleSum(), nil)
+			ow.writeSample(name+"_count", m, "", "", float64(s.GetSampleCount()), nil)
+		case dto.MetricType_HISTOGRAM:
+			h := m.GetHistogram()
+			infSeen := false
+			for _, b := range h.Bucket {
+				ow.writeSample(name+"_bucket", m, "le", formatFloat(b.GetUpperBound()), float64(b.GetCumulativeCount()), ow.exemplar(vec, m, b.GetUpperBound()))
+				if math.IsInf(b.GetUpperBound(), +1) {
+					infSeen = true
+				}
+			}
+			if !infSeen {
+				ow.writeSample(name+"_bucket", m, "le", "+Inf", float64(h.GetSampleCount()), ow.exemplar(vec, m, math.Inf(+1)))
+			}
+			ow.writeSample(name+"_sum", m, "", "", h.GetSampleSum(), nil)
+			ow.writeSample(name+"_count", m, "", "", float64(h.GetSampleCount()), nil)
+		default:
+			ow.writeSample(name, m, "", "", m.GetUntyped().GetValue(), nil)
+		}
+	}
+}
+
+func (ow *openMetricsWriter) exemplar(vec *HistogramVec, m *dto.Metric, upperBound float64) *Exemplar {
+	if vec == nil {
+		return nil
+	}
+	return vec.exemplar(m.Label, upperBound)
+}
+
+// writeSample writes a sample of a metric, with an additional label
+// such as the upper bound of a bucket if extraName is not empty.
+func (ow *openMetricsWriter) writeSample(name string, m *dto.Metric, extraName, extraValue string, v float64, e *Exemplar) {
+	ow.w.WriteString(name)
+	if len(m.Label) > 0 || extraName != "" {
+		ow.w.WriteByte('{')
+		for i, lp := range m.Label {
+			if i > 0 {
+				ow.w.WriteByte(',')
+			}
+			writeLabel(ow.w, lp.GetName(), lp.GetValue())
+		}
+		if extraName != "" {
+			if len(m.Label) > 0 {
+				ow.w.WriteByte(',')
+			}
+			writeLabel(ow.w, extraName, extraValue)
+		}
+		ow.w.WriteByte('}')
+	}
+	ow.w.WriteByte(' ')
+	ow.w.WriteString(formatFloat(v))
+	if m.TimestampMs != nil {
+		ow.w.WriteByte(' ')
+		ow.w.WriteString(strconv.FormatFloat(float64(m.GetTimestampMs())/1000, 'f', -1, 64))
+	}
+	if e != nil {
+		ow.w.WriteString(" # {")
+		names := sortedLabelNames(e.Labels)
+		for i, k := range names {
+			if i > 0 {
+				ow.w.WriteByte(',')
+			}
+			writeLabel(ow.w, k, e.Labels[k])
+		}
+		ow.w.WriteString("} ")
+		ow.w.WriteString(formatFloat(e.Value))
+		ow.w.WriteByte(' ')
+		ow.w.WriteString(strconv.FormatFloat(float64(e.Timestamp.UnixNano())/1e9, 'f', -1, 64))
+	}
+	ow.w.WriteByte('\n')
+}
+
+func writeLabel(w *bufio.Writer, name, value string) {
+	w.WriteString(name)
+	w.WriteString(`="`)
+	w.WriteString(escapeOpenMetrics(value))
+	w.WriteByte('"')
+}
+
+var openMetricsEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeOpenMetrics(s string) string {
+	return openMetricsEscaper.Replace(s)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, +1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,85 @@
+package prom_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRegistry_OpenMetrics(t *testing.T) {
+	reg := prom.NewRegistry()
+
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "requests_total",
+		Help: "Number of requests",
+	}, []string{"path"})
+	dur := prom.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "request_duration_seconds",
+		Help:    "Time taken to respond to a request",
+		Buckets: []float64{0.1, 1},
+	}, []string{"path"})
+	reg.MustRegister(requests, dur)
+
+	requests.WithLabelValues("/a").Inc()
+	prom.ObserveWithTraceID(dur.WithLabelValues("/a"), 0.5, "7f3a")
+	prom.ObserveWithTraceID(dur.With(prometheus.Labels{"path": "/a"}), 2, "")
+	prom.ObserveWithTraceID(dur.WithLabelValues("/b"), 0.05, "b0b")
+
+	get := func(accept string) (string, string) {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		reg.HTTPHandler().ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+		body, _ := ioutil.ReadAll(w.Body)
+		return w.Header().Get("Content-Type"), string(body)
+	}
+
+	contentType, body := get("application/openmetrics-text; version=1.0.0")
+	if contentType != prom.OpenMetricsContentType {
+		t.Errorf("unexpected content type: %q", contentType)
+	}
+	for _, want := range []string{
+		"# TYPE requests counter\n",
+		"# HELP requests Number of requests\n",
+		`requests_total{path="/a"} 1` + "\n",
+		"# TYPE request_duration_seconds histogram\n",
+		`request_duration_seconds_bucket{path="/a",le="0.1"} 0` + "\n",
+		`request_duration_seconds_bucket{path="/a",le="+Inf"} 2` + "\n",
+		`request_duration_seconds_count{path="/a"} 2` + "\n",
+		`request_duration_seconds_sum{path="/a"} 2.5` + "\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	for _, re := range []string{
+		`request_duration_seconds_bucket\{path="/a",le="1"\} 1 # \{trace_id="7f3a"\} 0.5 [0-9.]+\n`,
+		`request_duration_seconds_bucket\{path="/b",le="0.1"\} 1 # \{trace_id="b0b"\} 0.05 [0-9.]+\n`,
+	} {
+		if !regexp.MustCompile(re).MatchString(body) {
+			t.Errorf("expected metrics to match %q, got:\n%s", re, body)
+		}
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Errorf("expected metrics to end with # EOF, got:\n%s", body)
+	}
+
+	// Other formats are left to the prometheus handler, without exemplars.
+	contentType, body = get("text/plain")
+	if strings.HasPrefix(contentType, "application/openmetrics-text") {
+		t.Errorf("unexpected content type: %q", contentType)
+	}
+	if strings.Contains(body, "trace_id") || strings.Contains(body, "# EOF") {
+		t.Errorf("unexpected exemplars in text format:\n%s", body)
+	}
+}
@@ -4,6 +4,7 @@ package prom
 
 import (
 	"net/http"
+	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -24,16 +25,52 @@ type Registry struct {
 	*prometheus.Registry
 
 	logger *zap.Logger
+
+	mu        sync.RWMutex
+	exemplars map[string]*HistogramVec
 }
 
 // NewRegistry returns a new registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		Registry: prometheus.NewRegistry(),
-		logger:   zap.NewNop(),
+		Registry:  prometheus.NewRegistry(),
+		logger:    zap.NewNop(),
+		exemplars: make(map[string]*HistogramVec),
+	}
+}
+
+// Register registers the collector, and the exemplars it keeps if it is a HistogramVec.
+func (r *Registry) Register(c prometheus.Collector) error {
+	if err := r.Registry.Register(c); err != nil {
+		return err
+	}
+	if v, ok := c.(*HistogramVec); ok {
+		r.mu.Lock()
+		r.exemplars[v.name] = v
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+// MustRegister registers the collectors like Register, and panics if one can not be registered.
+func (r *Registry) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
 	}
 }
 
+// Unregister unregisters the collector, and the exemplars it keeps.
+func (r *Registry) Unregister(c prometheus.Collector) bool {
+	if v, ok := c.(*HistogramVec); ok {
+		r.mu.Lock()
+		delete(r.exemplars, v.name)
+		r.mu.Unlock()
+	}
+	return r.Registry.Unregister(c)
+}
+
 // WithLogger sets the logger for the Registry.
 // The logger will print any errors that occur while serving metrics over HTTP.
 func (r *Registry) WithLogger(l *zap.Logger) {
@@ -42,12 +79,38 @@ func (r *Registry) WithLogger(l *zap.Logger) {
 
 // HTTPHandler returns an http.Handler for the registry,
 // so that the /metrics HTTP handler is uniformly configured across all apps in the platform.
+//
+// Requests accepting application/openmetrics-text get the metrics as
+// OpenMetrics, with the exemplars of the histograms that keep them.
 func (r *Registry) HTTPHandler() http.Handler {
 	opts := promhttp.HandlerOpts{
 		ErrorLog: promLogger{r: r},
 		// TODO(mr): decide if we want to set MaxRequestsInFlight or Timeout.
 	}
-	return promhttp.HandlerFor(r.Registry, opts)
+	h := promhttp.HandlerFor(r.Registry, opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !acceptsOpenMetrics(req.Header.Get("Accept")) {
+			h.ServeHTTP(w, req)
+			return
+		}
+		r.serveOpenMetrics(w)
+	})
+}
+
+func (r *Registry) serveOpenMetrics(w http.ResponseWriter) {
+	mfs, err := r.Gather()
+	if err != nil {
+		r.logger.Sugar().Info("error gathering metrics: ", err)
+		http.Error(w, "An error has occurred during metrics gathering:\n\n"+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	w.Header().Set("Content-Type", OpenMetricsContentType)
+	if err := writeOpenMetrics(w, mfs, r.exemplars); err != nil {
+		r.logger.Sugar().Info("error encoding metrics: ", err)
+	}
 }
 
 // promLogger satisfies the promhttp.Logger interface with the registry.
@@ -10,6 +10,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/opentracing/opentracing-go/log"
+	"github.com/uber/jaeger-client-go"
 )
 
 // LogError adds a span log for an error.
@@ -35,6 +36,18 @@ func LogError(span opentracing.Span, err error) error {
 	return err
 }
 
+// TraceID returns the (Jaeger) trace ID of a span,
+// or an empty string if the span is not traced by Jaeger.
+func TraceID(span opentracing.Span) string {
+	if span == nil {
+		return ""
+	}
+	if spanContext, ok := span.Context().(jaeger.SpanContext); ok && spanContext.IsValid() {
+		return spanContext.TraceID().String()
+	}
+	return ""
+}
+
 // InjectToHTTPRequest adds tracing headers to an HTTP request.
 // Easier than adding this boilerplate everywhere.
 func InjectToHTTPRequest(span opentracing.Span, req *http.Request) {
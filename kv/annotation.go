@@ -0,0 +1,288 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+var (
+	annotationBucket = []byte("annotationsv1")
+)
+
+var _ influxdb.AnnotationService = (*Service)(nil)
+
+func (s *Service) initializeAnnotations(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(annotationBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindAnnotationByID retrieves an annotation by id.
+func (s *Service) FindAnnotationByID(ctx context.Context, id influxdb.ID) (*influxdb.Annotation, error) {
+	var a *influxdb.Annotation
+	err := s.kv.View(ctx, func(tx Tx) error {
+		annotation, err := s.findAnnotationByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		a = annotation
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindAnnotationByID,
+			Err: err,
+		}
+	}
+
+	return a, nil
+}
+
+func (s *Service) findAnnotationByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Annotation, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(annotationBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrAnnotationNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a := &influxdb.Annotation{}
+	if err := json.Unmarshal(v, a); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return a, nil
+}
+
+// FindAnnotations returns the annotations that match the filter, ordered by start time.
+func (s *Service) FindAnnotations(ctx context.Context, filter influxdb.AnnotationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Annotation, int, error) {
+	as := []*influxdb.Annotation{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		annotations, err := s.findAnnotations(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		as = annotations
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindAnnotations,
+			Err: err,
+		}
+	}
+
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(len(as))
+		as = as[lo:hi]
+	}
+
+	return as, len(as), nil
+}
+
+func (s *Service) findAnnotations(ctx context.Context, tx Tx, filter influxdb.AnnotationFilter) ([]*influxdb.Annotation, error) {
+	if filter.ID != nil {
+		a, err := s.findAnnotationByID(ctx, tx, *filter.ID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.Annotation{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filter.Matches(a) {
+			return []*influxdb.Annotation{}, nil
+		}
+		return []*influxdb.Annotation{a}, nil
+	}
+
+	b, err := tx.Bucket(annotationBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	as := []*influxdb.Annotation{}
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		a := &influxdb.Annotation{}
+		if err := json.Unmarshal(v, a); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if filter.Matches(a) {
+			as = append(as, a)
+		}
+	}
+	influxdb.SortAnnotations(as)
+
+	return as, nil
+}
+
+// CreateAnnotations creates the annotations in a single transaction. The user on the
+// context is their creator.
+func (s *Service) CreateAnnotations(ctx context.Context, as []*influxdb.Annotation) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		var createdBy influxdb.ID
+		if a, err := icontext.GetAuthorizer(ctx); err == nil {
+			createdBy = a.GetUserID()
+		}
+
+		now := s.Now()
+		orgs := map[influxdb.ID]bool{}
+		for _, a := range as {
+			if a.Stream == "" {
+				a.Stream = influxdb.DefaultAnnotationStream
+			}
+			if a.StartTime.IsZero() {
+				a.StartTime = now
+			}
+			if a.EndTime.IsZero() {
+				a.EndTime = a.StartTime
+			}
+			if err := a.Valid(); err != nil {
+				return err
+			}
+
+			if !orgs[a.OrgID] {
+				if _, err := s.findOrganizationByID(ctx, tx, a.OrgID); err != nil {
+					return err
+				}
+				orgs[a.OrgID] = true
+			}
+		}
+
+		// The annotations are only written once all of them are valid, as not
+		// every store rolls back the writes of a failed transaction.
+		for _, a := range as {
+			a.ID = s.IDGenerator.ID()
+			a.CreatedBy = createdBy
+			a.CreatedAt = now
+			a.UpdatedAt = now
+			if err := s.putAnnotation(ctx, tx, a); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateAnnotations,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateAnnotation updates an annotation according the parameters set on upd.
+func (s *Service) UpdateAnnotation(ctx context.Context, id influxdb.ID, upd influxdb.AnnotationUpdate) (*influxdb.Annotation, error) {
+	var a *influxdb.Annotation
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		annotation, err := s.findAnnotationByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(annotation)
+		if err := annotation.Valid(); err != nil {
+			return err
+		}
+
+		annotation.UpdatedAt = s.Now()
+		if err := s.putAnnotation(ctx, tx, annotation); err != nil {
+			return err
+		}
+		a = annotation
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateAnnotation,
+			Err: err,
+		}
+	}
+
+	return a, nil
+}
+
+// DeleteAnnotation deletes an annotation.
+func (s *Service) DeleteAnnotation(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findAnnotationByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(annotationBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(encodedID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteAnnotation,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) putAnnotation(ctx context.Context, tx Tx, a *influxdb.Annotation) error {
+	v, err := json.Marshal(a)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := a.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(annotationBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, v)
+}
@@ -0,0 +1,48 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltAnnotationService(t *testing.T) {
+	influxdbtesting.AnnotationService(initBoltAnnotationService, t)
+}
+
+func TestInmemAnnotationService(t *testing.T) {
+	influxdbtesting.AnnotationService(initInmemAnnotationService, t)
+}
+
+func initBoltAnnotationService(f influxdbtesting.AnnotationFields, t *testing.T) (influxdb.AnnotationService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initAnnotationService(s, f, t), closeBolt
+}
+
+func initInmemAnnotationService(f influxdbtesting.AnnotationFields, t *testing.T) (influxdb.AnnotationService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initAnnotationService(s, f, t), closeInmem
+}
+
+func initAnnotationService(s kv.Store, f influxdbtesting.AnnotationFields, t *testing.T) influxdb.AnnotationService {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, a := range f.Annotations {
+		if err := createWithID(svc, a.ID, func() error {
+			return svc.CreateAnnotations(ctx, []*influxdb.Annotation{a})
+		}); err != nil {
+			t.Fatalf("failed to populate annotations: %v", err)
+		}
+	}
+	return svc
+}
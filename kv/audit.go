@@ -0,0 +1,339 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	auditRecordBucket = []byte("auditrecordsv1")
+	auditExportBucket = []byte("auditexportsv1")
+)
+
+var _ influxdb.AuditService = (*Service)(nil)
+var _ influxdb.AuditExportService = (*Service)(nil)
+
+func (s *Service) initializeAudit(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(auditRecordBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(auditExportBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// auditRecordLimit returns the number of audit records kept per organization.
+func (s *Service) auditRecordLimit() int {
+	if s.Config.AuditRecordLimit > 0 {
+		return s.Config.AuditRecordLimit
+	}
+	return influxdb.DefaultAuditRecordLimit
+}
+
+// auditRecordKey returns the key of an audit record. The records of an
+// organization share a prefix and are ordered by their IDs, which increase
+// as they are generated.
+func auditRecordKey(orgID, id influxdb.ID) ([]byte, error) {
+	prefix, err := auditOrgKey(orgID)
+	if err != nil {
+		return nil, err
+	}
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return append(prefix, encID...), nil
+}
+
+func auditOrgKey(orgID influxdb.ID) ([]byte, error) {
+	encOrgID, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return encOrgID, nil
+}
+
+// RecordAudit records r and removes the oldest records of its organization
+// beyond the retention limit.
+func (s *Service) RecordAudit(ctx context.Context, r *influxdb.AuditRecord) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		r.ID = s.IDGenerator.ID()
+		if r.Time.IsZero() {
+			r.Time = s.Now()
+		}
+
+		k, err := auditRecordKey(r.OrgID, r.ID)
+		if err != nil {
+			return err
+		}
+		v, err := json.Marshal(r)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(auditRecordBucket)
+		if err != nil {
+			return err
+		}
+		if err := b.Put(k, v); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+
+		return s.trimAuditRecords(ctx, b, r.OrgID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpRecordAudit,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// trimAuditRecords removes the oldest audit records of the organization
+// beyond the retention limit.
+func (s *Service) trimAuditRecords(ctx context.Context, b Bucket, orgID influxdb.ID) error {
+	prefix, err := auditOrgKey(orgID)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	for k, _ := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
+		keys = append(keys, k)
+	}
+
+	for n := len(keys) - s.auditRecordLimit(); n > 0; n-- {
+		if err := b.Delete(keys[n-1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindAuditRecords returns the audit records of an organization that match
+// the filter, oldest first.
+func (s *Service) FindAuditRecords(ctx context.Context, filter influxdb.AuditRecordFilter) ([]*influxdb.AuditRecord, error) {
+	var rs []*influxdb.AuditRecord
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		rs, err = s.findAuditRecords(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindAuditRecords,
+			Err: err,
+		}
+	}
+	return rs, nil
+}
+
+func (s *Service) findAuditRecords(ctx context.Context, tx Tx, filter influxdb.AuditRecordFilter) ([]*influxdb.AuditRecord, error) {
+	prefix, err := auditOrgKey(filter.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	// The records after a record are those with a greater key, whether or
+	// not the record itself is still kept.
+	var after []byte
+	if filter.After != nil {
+		if after, err = auditRecordKey(filter.OrgID, *filter.After); err != nil {
+			return nil, err
+		}
+	}
+
+	b, err := tx.Bucket(auditRecordBucket)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	rs := []*influxdb.AuditRecord{}
+	for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		if after != nil && bytes.Compare(k, after) <= 0 {
+			continue
+		}
+		r := &influxdb.AuditRecord{}
+		if err := json.Unmarshal(v, r); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		rs = append(rs, r)
+		if filter.Limit > 0 && len(rs) >= filter.Limit {
+			break
+		}
+	}
+	return rs, nil
+}
+
+// FindAuditExport returns the audit export of an organization.
+func (s *Service) FindAuditExport(ctx context.Context, orgID influxdb.ID) (*influxdb.AuditExport, error) {
+	var e *influxdb.AuditExport
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		e, err = s.findAuditExport(ctx, tx, orgID)
+		return err
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindAuditExport,
+			Err: err,
+		}
+	}
+	return e, nil
+}
+
+func (s *Service) findAuditExport(ctx context.Context, tx Tx, orgID influxdb.ID) (*influxdb.AuditExport, error) {
+	k, err := auditOrgKey(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(auditExportBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(k)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrAuditExportNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e := &influxdb.AuditExport{}
+	if err := json.Unmarshal(v, e); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return e, nil
+}
+
+// FindAuditExports returns the audit exports of all organizations.
+func (s *Service) FindAuditExports(ctx context.Context) ([]*influxdb.AuditExport, error) {
+	es := []*influxdb.AuditExport{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(auditExportBucket)
+		if err != nil {
+			return err
+		}
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			e := &influxdb.AuditExport{}
+			if err := json.Unmarshal(v, e); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			es = append(es, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindAuditExports,
+			Err: err,
+		}
+	}
+	return es, nil
+}
+
+// PutAuditExport creates or replaces the audit export of an organization.
+func (s *Service) PutAuditExport(ctx context.Context, e *influxdb.AuditExport) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := e.Valid(); err != nil {
+			return err
+		}
+		if _, err := s.findOrganizationByID(ctx, tx, e.OrgID); err != nil {
+			return err
+		}
+
+		k, err := auditOrgKey(e.OrgID)
+		if err != nil {
+			return err
+		}
+		v, err := json.Marshal(e)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(auditExportBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put(k, v)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpPutAuditExport,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// DeleteAuditExport removes the audit export of an organization.
+func (s *Service) DeleteAuditExport(ctx context.Context, orgID influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findAuditExport(ctx, tx, orgID); err != nil {
+			return err
+		}
+
+		k, err := auditOrgKey(orgID)
+		if err != nil {
+			return err
+		}
+		b, err := tx.Bucket(auditExportBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(k)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteAuditExport,
+			Err: err,
+		}
+	}
+	return nil
+}
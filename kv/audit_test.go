@@ -0,0 +1,170 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+)
+
+func TestBoltAuditService(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	testAuditService(s, t)
+}
+
+func TestInmemAuditService(t *testing.T) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeInmem()
+
+	testAuditService(s, t)
+}
+
+// testAuditService checks that the audit records of an organization are
+// found oldest first after a record, and that the oldest records beyond the
+// limit are removed.
+func testAuditService(st kv.Store, t *testing.T) {
+	now := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	svc := initTestService(st, mock.NewIDGenerator("0000000000000010", t), mock.TimeGenerator{FakeValue: now}, nil, t)
+	svc.Config.AuditRecordLimit = 3
+
+	ctx := context.Background()
+	var ids []influxdb.ID
+	for i, orgID := range []influxdb.ID{1, 1, 2, 1, 1} {
+		id := influxdb.ID(0x10 + i)
+		r := &influxdb.AuditRecord{
+			OrgID:        orgID,
+			Kind:         influxdb.AuditResource,
+			Action:       "create",
+			ResourceType: influxdb.BucketsResourceType,
+			ResourceID:   influxdb.ID(100 + i),
+		}
+		if err := createWithID(svc, id, func() error {
+			return svc.RecordAudit(ctx, r)
+		}); err != nil {
+			t.Fatalf("failed to record audit: %v", err)
+		}
+		if r.ID != id || !r.Time.Equal(now) {
+			t.Fatalf("expected the record to get the ID %s and the time %s, got %s and %s", id, now, r.ID, r.Time)
+		}
+		if orgID == 1 {
+			ids = append(ids, id)
+		}
+	}
+
+	recordIDs := func(filter influxdb.AuditRecordFilter) []influxdb.ID {
+		t.Helper()
+		rs, err := svc.FindAuditRecords(ctx, filter)
+		if err != nil {
+			t.Fatalf("failed to find audit records: %v", err)
+		}
+		got := []influxdb.ID{}
+		for _, r := range rs {
+			got = append(got, r.ID)
+		}
+		return got
+	}
+
+	// The first record of the organization was removed by the fourth.
+	if got, want := recordIDs(influxdb.AuditRecordFilter{OrgID: 1}), ids[1:]; !cmp.Equal(got, want) {
+		t.Errorf("unexpected records -got/+want\n%s", cmp.Diff(got, want))
+	}
+	if got, want := recordIDs(influxdb.AuditRecordFilter{OrgID: 2}), []influxdb.ID{0x12}; !cmp.Equal(got, want) {
+		t.Errorf("unexpected records of the other organization -got/+want\n%s", cmp.Diff(got, want))
+	}
+	// Records are found after a record that was removed.
+	if got, want := recordIDs(influxdb.AuditRecordFilter{OrgID: 1, After: &ids[0], Limit: 2}), ids[1:3]; !cmp.Equal(got, want) {
+		t.Errorf("unexpected records after the removed record -got/+want\n%s", cmp.Diff(got, want))
+	}
+	if got, want := recordIDs(influxdb.AuditRecordFilter{OrgID: 1, After: &ids[3]}), []influxdb.ID{}; !cmp.Equal(got, want) {
+		t.Errorf("unexpected records after the last record -got/+want\n%s", cmp.Diff(got, want))
+	}
+}
+
+func TestBoltAuditExportService(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	testAuditExportService(s, t)
+}
+
+func TestInmemAuditExportService(t *testing.T) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeInmem()
+
+	testAuditExportService(s, t)
+}
+
+func testAuditExportService(st kv.Store, t *testing.T) {
+	orgID := influxdb.ID(1)
+	svc := initTestService(st, nil, nil, []*influxdb.Organization{{ID: orgID, Name: "theorg"}}, t)
+
+	ctx := context.Background()
+	if _, err := svc.FindAuditExport(ctx, orgID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Fatalf("expected no audit export, got %v", err)
+	}
+
+	e := &influxdb.AuditExport{
+		OrgID: orgID,
+		Destination: influxdb.AuditDestination{
+			Type: influxdb.AuditDestinationHTTP,
+			URL:  "https://archive.example.com/audit",
+		},
+		SigningSecretKey: "audit-signing",
+		Every:            "1h",
+		Status:           influxdb.Active,
+	}
+	if err := svc.PutAuditExport(ctx, e); err != nil {
+		t.Fatalf("failed to put audit export: %v", err)
+	}
+
+	invalid := *e
+	invalid.OrgID = 2
+	if err := svc.PutAuditExport(ctx, &invalid); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the audit export of a missing organization to be not found, got %v", err)
+	}
+	invalid = *e
+	invalid.Every = "1s"
+	if err := svc.PutAuditExport(ctx, &invalid); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an audit export every second to be invalid, got %v", err)
+	}
+
+	got, err := svc.FindAuditExport(ctx, orgID)
+	if err != nil {
+		t.Fatalf("failed to find audit export: %v", err)
+	}
+	if !cmp.Equal(got, e) {
+		t.Errorf("unexpected audit export -got/+want\n%s", cmp.Diff(got, e))
+	}
+	es, err := svc.FindAuditExports(ctx)
+	if err != nil {
+		t.Fatalf("failed to find audit exports: %v", err)
+	}
+	if want := []*influxdb.AuditExport{e}; !cmp.Equal(es, want) {
+		t.Errorf("unexpected audit exports -got/+want\n%s", cmp.Diff(es, want))
+	}
+
+	if err := svc.DeleteAuditExport(ctx, orgID); err != nil {
+		t.Fatalf("failed to delete audit export: %v", err)
+	}
+	if err := svc.DeleteAuditExport(ctx, orgID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the deleted audit export to be not found, got %v", err)
+	}
+}
@@ -204,6 +204,12 @@ func (s *Service) FindAuthorizations(ctx context.Context, filter influxdb.Author
 		}
 	}
 
+	if len(opt) > 0 {
+		influxdb.SortAuthorizations(opt[0], as)
+		lo, hi := opt[0].Page(len(as))
+		as = as[lo:hi]
+	}
+
 	return as, len(as), nil
 }
 
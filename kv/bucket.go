@@ -186,7 +186,8 @@ func (s *Service) FindBucket(ctx context.Context, filter influxdb.BucketFilter)
 	var b *influxdb.Bucket
 	var err error
 
-	if filter.ID != nil {
+	// Filters on metadata need the linear scan.
+	if filter.ID != nil && len(filter.Metadata) == 0 {
 		b, err = s.FindBucketByID(ctx, *filter.ID)
 		if err != nil {
 			return nil, &influxdb.Error{
@@ -196,7 +197,7 @@ func (s *Service) FindBucket(ctx context.Context, filter influxdb.BucketFilter)
 		return b, nil
 	}
 
-	if filter.Name != nil && filter.OrganizationID != nil {
+	if filter.Name != nil && filter.OrganizationID != nil && len(filter.Metadata) == 0 {
 		return s.FindBucketByName(ctx, *filter.OrganizationID, *filter.Name)
 	}
 
@@ -236,6 +237,16 @@ func (s *Service) FindBucket(ctx context.Context, filter influxdb.BucketFilter)
 }
 
 func filterBucketsFn(filter influxdb.BucketFilter) func(b *influxdb.Bucket) bool {
+	fn := filterBucketsByIDOrNameFn(filter)
+	if len(filter.Metadata) == 0 {
+		return fn
+	}
+	return func(b *influxdb.Bucket) bool {
+		return fn(b) && b.Metadata.Matches(filter.Metadata)
+	}
+}
+
+func filterBucketsByIDOrNameFn(filter influxdb.BucketFilter) func(b *influxdb.Bucket) bool {
 	if filter.ID != nil {
 		return func(b *influxdb.Bucket) bool {
 			return b.ID == *filter.ID
@@ -275,6 +286,9 @@ func (s *Service) FindBuckets(ctx context.Context, filter influxdb.BucketFilter,
 		if err != nil {
 			return nil, 0, err
 		}
+		if !b.Metadata.Matches(filter.Metadata) {
+			return []*influxdb.Bucket{}, 0, nil
+		}
 
 		return []*influxdb.Bucket{b}, 1, nil
 	}
@@ -284,6 +298,9 @@ func (s *Service) FindBuckets(ctx context.Context, filter influxdb.BucketFilter,
 		if err != nil {
 			return nil, 0, err
 		}
+		if !b.Metadata.Matches(filter.Metadata) {
+			return []*influxdb.Bucket{}, 0, nil
+		}
 
 		return []*influxdb.Bucket{b}, 1, nil
 	}
@@ -320,6 +337,26 @@ func (s *Service) findBuckets(ctx context.Context, tx Tx, filter influxdb.Bucket
 		filter.OrganizationID = &o.ID
 	}
 
+	filterFn := filterBucketsFn(filter)
+
+	// Sorted buckets are all found before they are paged.
+	if len(opts) > 0 && opts[0].SortBy != "" {
+		err := s.forEachBucket(ctx, tx, false, func(b *influxdb.Bucket) bool {
+			if filterFn(b) {
+				bs = append(bs, b)
+			}
+			return true
+		})
+		if err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+		influxdb.SortBuckets(opts[0], bs)
+		lo, hi := opts[0].Page(len(bs))
+		return bs[lo:hi], nil
+	}
+
 	var offset, limit, count int
 	var descending bool
 	if len(opts) > 0 {
@@ -328,7 +365,6 @@ func (s *Service) findBuckets(ctx context.Context, tx Tx, filter influxdb.Bucket
 		descending = opts[0].Descending
 	}
 
-	filterFn := filterBucketsFn(filter)
 	err := s.forEachBucket(ctx, tx, descending, func(b *influxdb.Bucket) bool {
 		if filterFn(b) {
 			if count >= offset {
@@ -382,6 +418,14 @@ func (s *Service) createBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) e
 		return err
 	}
 
+	if err := b.Metadata.Valid(); err != nil {
+		return err
+	}
+
+	if err := b.SchemaType.Valid(); err != nil {
+		return err
+	}
+
 	b.ID = s.IDGenerator.ID()
 	b.CreatedAt = s.Now()
 	b.UpdatedAt = s.Now()
@@ -483,7 +527,7 @@ func (s *Service) putBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) erro
 			Err: err,
 		}
 	}
-	return nil
+	return s.indexSearchDocument(ctx, tx, bucketSearchDocument(b))
 }
 
 // bucketIndexKey is a combination of the orgID and the bucket name.
@@ -591,10 +635,32 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
+	if upd.ReorderWindow != nil {
+		b.ReorderWindow = *upd.ReorderWindow
+	}
+
+	if upd.Quota != nil {
+		b.Quota = *upd.Quota
+	}
+
+	if upd.QueryCacheDisabled != nil {
+		b.QueryCacheDisabled = *upd.QueryCacheDisabled
+	}
+
+	if upd.WriteHooks != nil {
+		b.WriteHooks = *upd.WriteHooks
+	}
+
 	if upd.Description != nil {
 		b.Description = *upd.Description
 	}
 
+	if upd.Metadata != nil {
+		if b.Metadata, err = upd.Metadata.Apply(b.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
 	if upd.Name != nil {
 		b0, err := s.findBucketByName(ctx, tx, b.OrgID, *upd.Name)
 		if err == nil && b0.ID != id {
@@ -631,11 +697,14 @@ func (s *Service) updateBucket(ctx context.Context, tx Tx, id influxdb.ID, upd i
 	return b, nil
 }
 
-// DeleteBucket deletes a bucket and prunes it from the index.
+// DeleteBucket deletes a bucket and prunes it from the index. The bucket is
+// moved to the trash when the service has a trash retention.
 func (s *Service) DeleteBucket(ctx context.Context, id influxdb.ID) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
 		var err error
-		if pe := s.deleteBucket(ctx, tx, id); pe != nil {
+		if s.trashEnabled() {
+			err = s.trashBucket(ctx, tx, id)
+		} else if pe := s.deleteBucket(ctx, tx, id); pe != nil {
 			err = pe
 		}
 		return err
@@ -648,6 +717,35 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return pe
 	}
 
+	if err := s.removeBucket(ctx, tx, b); err != nil {
+		return err
+	}
+
+	return s.purgeBucket(ctx, tx, id)
+}
+
+// trashBucket removes a bucket and moves it to the trash. What belongs to the
+// bucket is kept until it is purged from the trash.
+func (s *Service) trashBucket(ctx context.Context, tx Tx, id influxdb.ID) error {
+	b, err := s.findBucketByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.removeBucket(ctx, tx, b); err != nil {
+		return err
+	}
+
+	return s.putTrashedResource(ctx, tx, influxdb.TrashedResource{
+		Type:  influxdb.BucketsResourceType,
+		ID:    b.ID,
+		OrgID: b.OrgID,
+		Name:  b.Name,
+	}, b)
+}
+
+// removeBucket removes a bucket and its indexes, so it can no longer be found.
+func (s *Service) removeBucket(ctx context.Context, tx Tx, b *influxdb.Bucket) error {
 	key, pe := bucketIndexKey(b)
 	if pe != nil {
 		return pe
@@ -664,7 +762,7 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		}
 	}
 
-	encodedID, err := id.Encode()
+	encodedID, err := b.ID.Encode()
 	if err != nil {
 		return &influxdb.Error{
 			Code: influxdb.EInvalid,
@@ -683,6 +781,11 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		}
 	}
 
+	return s.unindexSearchDocument(ctx, tx, influxdb.BucketsResourceType, b.ID)
+}
+
+// purgeBucket deletes what belongs to a removed bucket.
+func (s *Service) purgeBucket(ctx context.Context, tx Tx, id influxdb.ID) error {
 	if err := s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
 		ResourceID:   id,
 		ResourceType: influxdb.BucketsResourceType,
@@ -690,9 +793,41 @@ func (s *Service) deleteBucket(ctx context.Context, tx Tx, id influxdb.ID) error
 		return err
 	}
 
+	if err := s.deleteMeasurementSchemas(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err := s.deleteSchemaDescriptions(ctx, tx, id); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// restoreBucket puts a trashed bucket back, unless its organization was
+// deleted or another bucket of the organization took its name.
+func (s *Service) restoreBucket(ctx context.Context, tx Tx, v []byte) error {
+	var b influxdb.Bucket
+	if err := json.Unmarshal(v, &b); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	if b.OrgID.Valid() {
+		if _, err := s.findOrganizationByID(ctx, tx, b.OrgID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.uniqueBucketName(ctx, tx, &b); err != nil {
+		return err
+	}
+
+	return s.putBucket(ctx, tx, &b)
+}
+
 const bucketOperationLogKeyPrefix = "bucket"
 
 func encodeBucketOperationLogKey(id influxdb.ID) ([]byte, error) {
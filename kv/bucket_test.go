@@ -78,3 +78,11 @@ func initBucketService(s kv.Store, f influxdbtesting.BucketFields, t *testing.T)
 		}
 	}
 }
+
+func TestBoltBucketMetadata(t *testing.T) {
+	influxdbtesting.BucketMetadata(initBoltBucketService, t)
+}
+
+func TestInmemBucketMetadata(t *testing.T) {
+	influxdbtesting.BucketMetadata(initInmemBucketService, t)
+}
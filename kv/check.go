@@ -0,0 +1,371 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var (
+	checkBucket = []byte("checksv1")
+)
+
+var _ influxdb.CheckService = (*Service)(nil)
+
+func (s *Service) initializeChecks(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(checkBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindCheckByID retrieves a check by id.
+func (s *Service) FindCheckByID(ctx context.Context, id influxdb.ID) (*influxdb.Check, error) {
+	var c *influxdb.Check
+	err := s.kv.View(ctx, func(tx Tx) error {
+		check, err := s.findCheckByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		c = check
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindCheckByID,
+			Err: err,
+		}
+	}
+
+	return c, nil
+}
+
+func (s *Service) findCheckByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Check, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(checkBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrCheckNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &influxdb.Check{}
+	if err := json.Unmarshal(v, c); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return c, nil
+}
+
+func filterChecksFn(filter influxdb.CheckFilter) func(c *influxdb.Check) bool {
+	return func(c *influxdb.Check) bool {
+		return (filter.ID == nil || *filter.ID == c.ID) &&
+			(filter.OrgID == nil || *filter.OrgID == c.OrgID) &&
+			(filter.Name == nil || *filter.Name == c.Name) &&
+			(filter.Type == nil || *filter.Type == c.Type)
+	}
+}
+
+// FindChecks returns the checks that match the filter, ordered by ID.
+func (s *Service) FindChecks(ctx context.Context, filter influxdb.CheckFilter, opt ...influxdb.FindOptions) ([]*influxdb.Check, int, error) {
+	cs := []*influxdb.Check{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		checks, err := s.findChecks(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		cs = checks
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindChecks,
+			Err: err,
+		}
+	}
+
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(len(cs))
+		cs = cs[lo:hi]
+	}
+
+	return cs, len(cs), nil
+}
+
+func (s *Service) findChecks(ctx context.Context, tx Tx, filter influxdb.CheckFilter) ([]*influxdb.Check, error) {
+	if filter.ID != nil {
+		c, err := s.findCheckByID(ctx, tx, *filter.ID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.Check{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filterChecksFn(filter)(c) {
+			return []*influxdb.Check{}, nil
+		}
+		return []*influxdb.Check{c}, nil
+	}
+
+	cs := []*influxdb.Check{}
+	filterFn := filterChecksFn(filter)
+	err := s.forEachCheck(ctx, tx, func(c *influxdb.Check) bool {
+		if filterFn(c) {
+			cs = append(cs, c)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cs, nil
+}
+
+func (s *Service) forEachCheck(ctx context.Context, tx Tx, fn func(*influxdb.Check) bool) error {
+	b, err := tx.Bucket(checkBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		c := &influxdb.Check{}
+		if err := json.Unmarshal(v, c); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if !fn(c) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// CreateCheck creates a check and makes the user on the context its owner.
+func (s *Service) CreateCheck(ctx context.Context, c *influxdb.Check) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if c.Status == "" {
+			c.Status = influxdb.Active
+		}
+		if err := c.Valid(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, c.OrgID); err != nil {
+			return err
+		}
+
+		if err := s.uniqueCheckName(ctx, tx, c); err != nil {
+			return err
+		}
+
+		c.ID = s.IDGenerator.ID()
+		c.CreatedAt = s.Now()
+		c.UpdatedAt = s.Now()
+		if err := s.putCheck(ctx, tx, c); err != nil {
+			return err
+		}
+
+		if err := s.addResourceOwner(ctx, tx, influxdb.ChecksResourceType, c.ID); err != nil {
+			s.Logger.Info("failed to make user owner of check", zap.Error(err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateCheck,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// uniqueCheckName returns an error if another check of the organization of c
+// has its name.
+func (s *Service) uniqueCheckName(ctx context.Context, tx Tx, c *influxdb.Check) error {
+	cs, err := s.findChecks(ctx, tx, influxdb.CheckFilter{OrgID: &c.OrgID, Name: &c.Name})
+	if err != nil {
+		return err
+	}
+	for _, other := range cs {
+		if other.ID != c.ID {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("check with name %s already exists", c.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateCheck updates a check with the changeset.
+func (s *Service) UpdateCheck(ctx context.Context, id influxdb.ID, upd influxdb.CheckUpdate) (*influxdb.Check, error) {
+	var c *influxdb.Check
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := upd.Valid(); err != nil {
+			return err
+		}
+
+		check, err := s.findCheckByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(check)
+		if err := s.uniqueCheckName(ctx, tx, check); err != nil {
+			return err
+		}
+
+		check.UpdatedAt = s.Now()
+		if err := s.putCheck(ctx, tx, check); err != nil {
+			return err
+		}
+		c = check
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateCheck,
+			Err: err,
+		}
+	}
+
+	return c, nil
+}
+
+// ReplaceCheck replaces the definition of a check. The check keeps its task
+// unless it is given another one.
+func (s *Service) ReplaceCheck(ctx context.Context, c *influxdb.Check) (*influxdb.Check, error) {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		current, err := s.findCheckByID(ctx, tx, c.ID)
+		if err != nil {
+			return err
+		}
+
+		c.OrgID = current.OrgID
+		c.CreatedAt = current.CreatedAt
+		if !c.TaskID.Valid() {
+			c.TaskID = current.TaskID
+		}
+		if c.Status == "" {
+			c.Status = current.Status
+		}
+		if err := c.Valid(); err != nil {
+			return err
+		}
+		if err := s.uniqueCheckName(ctx, tx, c); err != nil {
+			return err
+		}
+
+		c.UpdatedAt = s.Now()
+		return s.putCheck(ctx, tx, c)
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpReplaceCheck,
+			Err: err,
+		}
+	}
+
+	return c, nil
+}
+
+func (s *Service) putCheck(ctx context.Context, tx Tx, c *influxdb.Check) error {
+	// The last status is read from the monitoring bucket, not stored.
+	stored := *c
+	stored.LastStatus = nil
+	v, err := json.Marshal(&stored)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := c.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(checkBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, v)
+}
+
+// DeleteCheck deletes a check and the mappings of users to it. The task of
+// the check is left to the caller.
+func (s *Service) DeleteCheck(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteCheck(ctx, tx, id)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteCheck,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteCheck(ctx context.Context, tx Tx, id influxdb.ID) error {
+	if _, err := s.findCheckByID(ctx, tx, id); err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(checkBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(encodedID); err != nil {
+		return err
+	}
+
+	return s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   id,
+		ResourceType: influxdb.ChecksResourceType,
+	})
+}
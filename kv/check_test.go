@@ -0,0 +1,48 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltCheckService(t *testing.T) {
+	influxdbtesting.CheckService(initBoltCheckService, t)
+}
+
+func TestInmemCheckService(t *testing.T) {
+	influxdbtesting.CheckService(initInmemCheckService, t)
+}
+
+func initBoltCheckService(f influxdbtesting.CheckFields, t *testing.T) (influxdb.CheckService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initCheckService(s, f, t), closeBolt
+}
+
+func initInmemCheckService(f influxdbtesting.CheckFields, t *testing.T) (influxdb.CheckService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initCheckService(s, f, t), closeInmem
+}
+
+func initCheckService(s kv.Store, f influxdbtesting.CheckFields, t *testing.T) influxdb.CheckService {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, c := range f.Checks {
+		if err := createWithID(svc, c.ID, func() error {
+			return svc.CreateCheck(ctx, c)
+		}); err != nil {
+			t.Fatalf("failed to populate checks: %v", err)
+		}
+	}
+	return svc
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -28,6 +29,8 @@ const (
 	dashboardCellAddedEvent     = "Dashboard Cell Added"
 	dashboardCellRemovedEvent   = "Dashboard Cell Removed"
 	dashboardCellUpdatedEvent   = "Dashboard Cell Updated"
+
+	dashboardRevisionRestoredEvent = "Dashboard Revision Restored"
 )
 
 var _ influxdb.DashboardService = (*Service)(nil)
@@ -105,7 +108,7 @@ func (s *Service) findDashboardByID(ctx context.Context, tx Tx, id influxdb.ID)
 
 // FindDashboard retrieves a dashboard using an arbitrary dashboard filter.
 func (s *Service) FindDashboard(ctx context.Context, filter influxdb.DashboardFilter, opts ...influxdb.FindOptions) (*influxdb.Dashboard, error) {
-	if len(filter.IDs) == 1 {
+	if len(filter.IDs) == 1 && len(filter.Metadata) == 0 {
 		return s.FindDashboardByID(ctx, *filter.IDs[0])
 	}
 
@@ -143,11 +146,11 @@ func filterDashboardsFn(filter influxdb.DashboardFilter) func(d *influxdb.Dashbo
 		}
 		return func(d *influxdb.Dashboard) bool {
 			_, ok := m[d.ID.String()]
-			return ok
+			return ok && d.Metadata.Matches(filter.Metadata)
 		}
 	}
 
-	return func(d *influxdb.Dashboard) bool { return true }
+	return func(d *influxdb.Dashboard) bool { return d.Metadata.Matches(filter.Metadata) }
 }
 
 // FindDashboards retrives all dashboards that match an arbitrary dashboard filter.
@@ -160,7 +163,7 @@ func (s *Service) FindDashboards(ctx context.Context, filter influxdb.DashboardF
 				Err: err,
 			}
 		}
-		if d == nil {
+		if d == nil || !d.Metadata.Matches(filter.Metadata) {
 			return ds, 0, nil
 		}
 		return []*influxdb.Dashboard{d}, 1, nil
@@ -180,8 +183,6 @@ func (s *Service) FindDashboards(ctx context.Context, filter influxdb.DashboardF
 		}
 	}
 
-	influxdb.SortDashboards(opts, ds)
-
 	return ds, len(ds), nil
 }
 
@@ -220,6 +221,13 @@ func (s *Service) findOrganizationDashboards(ctx context.Context, tx Tx, orgID i
 	return ds, nil
 }
 
+// pageDashboards sorts the dashboards and returns the page of them the options select.
+func pageDashboards(opts influxdb.FindOptions, ds []*influxdb.Dashboard) []*influxdb.Dashboard {
+	influxdb.SortDashboards(opts, ds)
+	lo, hi := opts.Page(len(ds))
+	return ds[lo:hi]
+}
+
 func decodeOrgDashboardIndexKey(indexKey []byte) (orgID influxdb.ID, dashID influxdb.ID, err error) {
 	if len(indexKey) != 2*influxdb.IDLength {
 		return 0, 0, &influxdb.Error{Code: influxdb.EInternal, Msg: "malformed org dashboard index key (please report this error)"}
@@ -237,8 +245,17 @@ func decodeOrgDashboardIndexKey(indexKey []byte) (orgID influxdb.ID, dashID infl
 }
 
 func (s *Service) findDashboards(ctx context.Context, tx Tx, filter influxdb.DashboardFilter, opts ...influxdb.FindOptions) ([]*influxdb.Dashboard, error) {
+	var opt influxdb.FindOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
 	if filter.OrganizationID != nil {
-		return s.findOrganizationDashboards(ctx, tx, *filter.OrganizationID)
+		ds, err := s.findOrganizationDashboards(ctx, tx, *filter.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		return pageDashboards(opt, filter.FilterByMetadata(ds)), nil
 	}
 
 	if filter.Organization != nil {
@@ -246,7 +263,28 @@ func (s *Service) findDashboards(ctx context.Context, tx Tx, filter influxdb.Das
 		if err != nil {
 			return nil, err
 		}
-		return s.findOrganizationDashboards(ctx, tx, o.ID)
+		ds, err := s.findOrganizationDashboards(ctx, tx, o.ID)
+		if err != nil {
+			return nil, err
+		}
+		return pageDashboards(opt, filter.FilterByMetadata(ds)), nil
+	}
+
+	// Dashboards are stored in ID order, so only dashboards sorted by
+	// anything else are all found before they are paged.
+	if opt.SortBy != "" && !strings.EqualFold(opt.SortBy, "ID") {
+		ds := []*influxdb.Dashboard{}
+		filterFn := filterDashboardsFn(filter)
+		err := s.forEachDashboard(ctx, tx, false, func(d *influxdb.Dashboard) bool {
+			if filterFn(d) {
+				ds = append(ds, d)
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		return pageDashboards(opt, ds), nil
 	}
 
 	var offset, limit, count int
@@ -282,8 +320,16 @@ func (s *Service) findDashboards(ctx context.Context, tx Tx, filter influxdb.Das
 // CreateDashboard creates a influxdb dashboard and sets d.ID.
 func (s *Service) CreateDashboard(ctx context.Context, d *influxdb.Dashboard) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := d.Metadata.Valid(); err != nil {
+			return err
+		}
+
 		d.ID = s.IDGenerator.ID()
 
+		if err := s.uniqueDashboardName(ctx, tx, d.OrganizationID, d.ID, d.Name); err != nil {
+			return err
+		}
+
 		for _, cell := range d.Cells {
 			cell.ID = s.IDGenerator.ID()
 
@@ -345,6 +391,11 @@ func (s *Service) ReplaceDashboardCells(ctx context.Context, id influxdb.ID, cs
 			return err
 		}
 
+		prev, err := s.dashboardRevisionOf(ctx, tx, d)
+		if err != nil {
+			return err
+		}
+
 		ids := map[string]*influxdb.Cell{}
 		for _, cell := range d.Cells {
 			ids[cell.ID.String()] = cell
@@ -371,7 +422,11 @@ func (s *Service) ReplaceDashboardCells(ctx context.Context, id influxdb.ID, cs
 			return err
 		}
 
-		return s.putDashboardWithMeta(ctx, tx, d)
+		if err := s.putDashboardWithMeta(ctx, tx, d); err != nil {
+			return err
+		}
+
+		return s.putDashboardRevision(ctx, tx, prev, d)
 	})
 	if err != nil {
 		return &influxdb.Error{
@@ -386,6 +441,11 @@ func (s *Service) addDashboardCell(ctx context.Context, tx Tx, id influxdb.ID, c
 	if err != nil {
 		return err
 	}
+	prev, err := s.dashboardRevisionOf(ctx, tx, d)
+	if err != nil {
+		return err
+	}
+
 	cell.ID = s.IDGenerator.ID()
 	if err := s.createCellView(ctx, tx, id, cell.ID, opts.View); err != nil {
 		return err
@@ -397,7 +457,11 @@ func (s *Service) addDashboardCell(ctx context.Context, tx Tx, id influxdb.ID, c
 		return err
 	}
 
-	return s.putDashboardWithMeta(ctx, tx, d)
+	if err := s.putDashboardWithMeta(ctx, tx, d); err != nil {
+		return err
+	}
+
+	return s.putDashboardRevision(ctx, tx, prev, d)
 }
 
 // AddDashboardCell adds a cell to a dashboard and sets the cells ID.
@@ -422,6 +486,12 @@ func (s *Service) RemoveDashboardCell(ctx context.Context, dashboardID, cellID i
 				Err: err,
 			}
 		}
+		prev, err := s.dashboardRevisionOf(ctx, tx, d)
+		if err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
 
 		idx := -1
 		for i, cell := range d.Cells {
@@ -456,6 +526,12 @@ func (s *Service) RemoveDashboardCell(ctx context.Context, dashboardID, cellID i
 				Err: err,
 			}
 		}
+
+		if err := s.putDashboardRevision(ctx, tx, prev, d); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
 		return nil
 	})
 }
@@ -579,6 +655,15 @@ func (s *Service) UpdateDashboardCellView(ctx context.Context, dashboardID, cell
 	var v *influxdb.View
 
 	err := s.kv.Update(ctx, func(tx Tx) error {
+		d, err := s.findDashboardByID(ctx, tx, dashboardID)
+		if err != nil {
+			return err
+		}
+		prev, err := s.dashboardRevisionOf(ctx, tx, d)
+		if err != nil {
+			return err
+		}
+
 		view, err := s.findDashboardCellView(ctx, tx, dashboardID, cellID)
 		if err != nil {
 			return err
@@ -593,7 +678,7 @@ func (s *Service) UpdateDashboardCellView(ctx context.Context, dashboardID, cell
 		}
 
 		v = view
-		return nil
+		return s.putDashboardRevision(ctx, tx, prev, d)
 	})
 
 	if err != nil {
@@ -619,6 +704,10 @@ func (s *Service) UpdateDashboardCell(ctx context.Context, dashboardID, cellID i
 		if err != nil {
 			return err
 		}
+		prev, err := s.dashboardRevisionOf(ctx, tx, d)
+		if err != nil {
+			return err
+		}
 
 		idx := -1
 		for i, cell := range d.Cells {
@@ -644,7 +733,11 @@ func (s *Service) UpdateDashboardCell(ctx context.Context, dashboardID, cellID i
 			return err
 		}
 
-		return s.putDashboardWithMeta(ctx, tx, d)
+		if err := s.putDashboardWithMeta(ctx, tx, d); err != nil {
+			return err
+		}
+
+		return s.putDashboardRevision(ctx, tx, prev, d)
 	})
 
 	if err != nil {
@@ -747,7 +840,7 @@ func (s *Service) putDashboard(ctx context.Context, tx Tx, d *influxdb.Dashboard
 		return err
 	}
 
-	return nil
+	return s.indexSearchDocument(ctx, tx, dashboardSearchDocument(d))
 }
 
 func (s *Service) putDashboardWithMeta(ctx context.Context, tx Tx, d *influxdb.Dashboard) error {
@@ -825,6 +918,16 @@ func (s *Service) updateDashboard(ctx context.Context, tx Tx, id influxdb.ID, up
 	if err != nil {
 		return nil, err
 	}
+	prev, err := s.dashboardRevisionOf(ctx, tx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	if upd.Name != nil && *upd.Name != d.Name {
+		if err := s.uniqueDashboardName(ctx, tx, d.OrganizationID, d.ID, *upd.Name); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := upd.Apply(d); err != nil {
 		return nil, err
@@ -838,12 +941,20 @@ func (s *Service) updateDashboard(ctx context.Context, tx Tx, id influxdb.ID, up
 		return nil, err
 	}
 
+	if err := s.putDashboardRevision(ctx, tx, prev, d); err != nil {
+		return nil, err
+	}
+
 	return d, nil
 }
 
-// DeleteDashboard deletes a dashboard and prunes it from the index.
+// DeleteDashboard deletes a dashboard and prunes it from the index. The
+// dashboard is moved to the trash when the service has a trash retention.
 func (s *Service) DeleteDashboard(ctx context.Context, id influxdb.ID) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
+		if s.trashEnabled() {
+			return s.trashDashboard(ctx, tx, id)
+		}
 		if pe := s.deleteDashboard(ctx, tx, id); pe != nil {
 			return &influxdb.Error{
 				Err: pe,
@@ -859,15 +970,36 @@ func (s *Service) deleteDashboard(ctx context.Context, tx Tx, id influxdb.ID) er
 		return pe
 	}
 
-	for _, cell := range d.Cells {
-		if err := s.deleteDashboardCellView(ctx, tx, d.ID, cell.ID); err != nil {
-			return &influxdb.Error{
-				Err: err,
-			}
-		}
+	if err := s.removeDashboard(ctx, tx, d); err != nil {
+		return err
 	}
 
-	encodedID, err := id.Encode()
+	return s.purgeDashboard(ctx, tx, d)
+}
+
+// trashDashboard removes a dashboard and moves it to the trash. The views of
+// its cells are kept until it is purged from the trash.
+func (s *Service) trashDashboard(ctx context.Context, tx Tx, id influxdb.ID) error {
+	d, err := s.findDashboardByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.removeDashboard(ctx, tx, d); err != nil {
+		return err
+	}
+
+	return s.putTrashedResource(ctx, tx, influxdb.TrashedResource{
+		Type:  influxdb.DashboardsResourceType,
+		ID:    d.ID,
+		OrgID: d.OrganizationID,
+		Name:  d.Name,
+	}, d)
+}
+
+// removeDashboard removes a dashboard and its indexes, so it can no longer be found.
+func (s *Service) removeDashboard(ctx context.Context, tx Tx, d *influxdb.Dashboard) error {
+	encodedID, err := d.ID.Encode()
 	if err != nil {
 		return &influxdb.Error{
 			Err: err,
@@ -889,8 +1021,27 @@ func (s *Service) deleteDashboard(ctx context.Context, tx Tx, id influxdb.ID) er
 		}
 	}
 
-	err = s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
-		ResourceID:   id,
+	if err := s.appendDashboardEventToLog(ctx, tx, d.ID, dashboardRemovedEvent); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return s.unindexSearchDocument(ctx, tx, influxdb.DashboardsResourceType, d.ID)
+}
+
+// purgeDashboard deletes what belongs to a removed dashboard.
+func (s *Service) purgeDashboard(ctx context.Context, tx Tx, d *influxdb.Dashboard) error {
+	for _, cell := range d.Cells {
+		if err := s.deleteDashboardCellView(ctx, tx, d.ID, cell.ID); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+	}
+
+	err := s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   d.ID,
 		ResourceType: influxdb.DashboardsResourceType,
 	})
 	if err != nil {
@@ -899,13 +1050,35 @@ func (s *Service) deleteDashboard(ctx context.Context, tx Tx, id influxdb.ID) er
 		}
 	}
 
-	if err := s.appendDashboardEventToLog(ctx, tx, d.ID, dashboardRemovedEvent); err != nil {
+	return s.deleteRevisions(ctx, tx, influxdb.DashboardsResourceType, d.ID)
+}
+
+// restoreDashboard puts a trashed dashboard back, unless its organization was
+// deleted or another dashboard of the organization took its name.
+func (s *Service) restoreDashboard(ctx context.Context, tx Tx, v []byte) error {
+	var d influxdb.Dashboard
+	if err := json.Unmarshal(v, &d); err != nil {
 		return &influxdb.Error{
-			Err: err,
+			Code: influxdb.EInternal,
+			Err:  err,
 		}
 	}
 
-	return nil
+	if d.OrganizationID.Valid() {
+		if _, err := s.findOrganizationByID(ctx, tx, d.OrganizationID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.uniqueDashboardName(ctx, tx, d.OrganizationID, d.ID, d.Name); err != nil {
+		return err
+	}
+
+	if err := s.putOrganizationDashboardIndex(ctx, tx, &d); err != nil {
+		return err
+	}
+
+	return s.putDashboard(ctx, tx, &d)
 }
 
 const dashboardOperationLogKeyPrefix = "dashboard"
@@ -0,0 +1,303 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+var (
+	dashboardShareBucket = []byte("dashboardsharesv1")
+	dashboardShareIndex  = []byte("dashboardshareindexv1")
+)
+
+var _ influxdb.DashboardShareService = (*Service)(nil)
+
+func (s *Service) initializeDashboardShares(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(dashboardShareBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(dashboardShareIndex); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateDashboardShare creates a share of a dashboard with a new token.
+// The share belongs to the organization of the dashboard.
+func (s *Service) CreateDashboardShare(ctx context.Context, ds *influxdb.DashboardShare) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		d, err := s.findDashboardByID(ctx, tx, ds.DashboardID)
+		if err != nil {
+			return err
+		}
+		if ds.OrgID.Valid() && ds.OrgID != d.OrganizationID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "dashboard share must belong to the organization of the dashboard",
+			}
+		}
+		ds.OrgID = d.OrganizationID
+
+		if err := ds.Valid(); err != nil {
+			return err
+		}
+
+		token, err := s.TokenGenerator.Token()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		now := s.Now()
+		ds.ID = s.IDGenerator.ID()
+		ds.Token = token
+		ds.Status = influxdb.DashboardShareActive
+		ds.CreatedAt = now
+		ds.CreatedBy = 0
+		if a, err := icontext.GetAuthorizer(ctx); err == nil {
+			ds.CreatedBy = a.GetUserID()
+		}
+		if ds.ExpiresAt.IsZero() {
+			ds.ExpiresAt = now.Add(influxdb.DefaultDashboardShareTTL)
+		}
+		if !ds.ExpiresAt.After(now) {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "dashboard share must expire in the future",
+			}
+		}
+		if ds.Cells == nil {
+			ds.Cells = []influxdb.DashboardShareCell{}
+		}
+
+		return s.putDashboardShare(ctx, tx, ds)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateDashboardShare,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindDashboardShareByID returns a single dashboard share by ID.
+func (s *Service) FindDashboardShareByID(ctx context.Context, id influxdb.ID) (*influxdb.DashboardShare, error) {
+	var ds *influxdb.DashboardShare
+	err := s.kv.View(ctx, func(tx Tx) error {
+		share, err := s.findDashboardShareByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		ds = share
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindDashboardShareByID,
+			Err: err,
+		}
+	}
+	return ds, nil
+}
+
+func (s *Service) findDashboardShareByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.DashboardShare, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(dashboardShareBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrDashboardShareNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.decodeDashboardShare(v)
+}
+
+func (s *Service) decodeDashboardShare(v []byte) (*influxdb.DashboardShare, error) {
+	ds := &influxdb.DashboardShare{}
+	if err := json.Unmarshal(v, ds); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	ds.Status = ds.StatusAt(s.Now())
+	return ds, nil
+}
+
+// FindDashboardShareByToken returns the dashboard share with the token.
+func (s *Service) FindDashboardShareByToken(ctx context.Context, token string) (*influxdb.DashboardShare, error) {
+	var ds *influxdb.DashboardShare
+	err := s.kv.View(ctx, func(tx Tx) error {
+		idx, err := tx.Bucket(dashboardShareIndex)
+		if err != nil {
+			return err
+		}
+
+		encodedID, err := idx.Get([]byte(token))
+		if IsNotFound(err) {
+			return &influxdb.Error{
+				Code: influxdb.ENotFound,
+				Msg:  influxdb.ErrDashboardShareNotFound,
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		var id influxdb.ID
+		if err := id.Decode(encodedID); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		share, err := s.findDashboardShareByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		ds = share
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindDashboardShareByToken,
+			Err: err,
+		}
+	}
+	return ds, nil
+}
+
+// FindDashboardShares returns the dashboard shares that match the filter.
+func (s *Service) FindDashboardShares(ctx context.Context, filter influxdb.DashboardShareFilter) ([]*influxdb.DashboardShare, error) {
+	shares := []*influxdb.DashboardShare{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(dashboardShareBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			ds, err := s.decodeDashboardShare(v)
+			if err != nil {
+				return err
+			}
+			if filter.DashboardID != nil && *filter.DashboardID != ds.DashboardID {
+				continue
+			}
+			if filter.OrgID != nil && *filter.OrgID != ds.OrgID {
+				continue
+			}
+			shares = append(shares, ds)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindDashboardShares,
+			Err: err,
+		}
+	}
+	return shares, nil
+}
+
+// RevokeDashboardShare deletes a dashboard share.
+func (s *Service) RevokeDashboardShare(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		ds, err := s.findDashboardShareByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		return s.deleteDashboardShare(ctx, tx, ds)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpRevokeDashboardShare,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) putDashboardShare(ctx context.Context, tx Tx, ds *influxdb.DashboardShare) error {
+	v, err := json.Marshal(ds)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := ds.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(dashboardShareIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Put([]byte(ds.Token), encodedID); err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(dashboardShareBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+func (s *Service) deleteDashboardShare(ctx context.Context, tx Tx, ds *influxdb.DashboardShare) error {
+	encodedID, err := ds.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(dashboardShareIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Delete([]byte(ds.Token)); err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(dashboardShareBucket)
+	if err != nil {
+		return err
+	}
+	return b.Delete(encodedID)
+}
@@ -0,0 +1,63 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltDashboardShareService(t *testing.T) {
+	influxdbtesting.DashboardShareService(initBoltDashboardShareService, t)
+}
+
+func TestInmemDashboardShareService(t *testing.T) {
+	influxdbtesting.DashboardShareService(initInmemDashboardShareService, t)
+}
+
+func initBoltDashboardShareService(f influxdbtesting.DashboardShareFields, t *testing.T) (influxdb.DashboardShareService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initDashboardShareService(s, f, t), closeBolt
+}
+
+func initInmemDashboardShareService(f influxdbtesting.DashboardShareFields, t *testing.T) (influxdb.DashboardShareService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initDashboardShareService(s, f, t), closeInmem
+}
+
+func initDashboardShareService(s kv.Store, f influxdbtesting.DashboardShareFields, t *testing.T) influxdb.DashboardShareService {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+	if f.TokenGenerator != nil {
+		svc.TokenGenerator = f.TokenGenerator
+	}
+
+	ctx := context.Background()
+	for _, d := range f.Dashboards {
+		if err := createWithID(svc, d.ID, func() error {
+			return svc.CreateDashboard(ctx, d)
+		}); err != nil {
+			t.Fatalf("failed to populate dashboards: %v", err)
+		}
+	}
+	tg, tokens := svc.TimeGenerator, svc.TokenGenerator
+	for _, ds := range f.DashboardShares {
+		svc.TimeGenerator = mock.TimeGenerator{FakeValue: ds.CreatedAt}
+		svc.TokenGenerator = mock.NewTokenGenerator(ds.Token, nil)
+		if err := createWithID(svc, ds.ID, func() error {
+			return svc.CreateDashboardShare(ctx, ds)
+		}); err != nil {
+			t.Fatalf("failed to populate dashboard shares: %v", err)
+		}
+	}
+	svc.TimeGenerator, svc.TokenGenerator = tg, tokens
+	return svc
+}
@@ -0,0 +1,251 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	dbrpMappingBucket = []byte("dbrpmappingsv1")
+
+	errDBRPMappingNotFound = &influxdb.Error{
+		Code: influxdb.ENotFound,
+		Msg:  "dbrp mapping not found",
+	}
+)
+
+var _ influxdb.DBRPMappingService = (*Service)(nil)
+
+func (s *Service) initializeDBRPMappings(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(dbrpMappingBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// The names of a mapping cannot contain a slash, so joining them makes a unique key.
+func encodeDBRPMappingKey(cluster, db, rp string) []byte {
+	return []byte(path.Join(cluster, db, rp))
+}
+
+// FindBy returns the dbrp mapping for the cluster, db and rp.
+func (s *Service) FindBy(ctx context.Context, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	var m *influxdb.DBRPMapping
+	err := s.kv.View(ctx, func(tx Tx) error {
+		mapping, err := s.findDBRPMapping(ctx, tx, cluster, db, rp)
+		if err != nil {
+			return err
+		}
+		m = mapping
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (s *Service) findDBRPMapping(ctx context.Context, tx Tx, cluster, db, rp string) (*influxdb.DBRPMapping, error) {
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodeDBRPMappingKey(cluster, db, rp))
+	if IsNotFound(err) {
+		return nil, errDBRPMappingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &influxdb.DBRPMapping{}
+	if err := json.Unmarshal(v, m); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	return m, nil
+}
+
+// Find returns the first dbrp mapping that matches filter.
+func (s *Service) Find(ctx context.Context, filter influxdb.DBRPMappingFilter) (*influxdb.DBRPMapping, error) {
+	if filter.Cluster == nil && filter.Database == nil && filter.RetentionPolicy == nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "no filter parameters provided",
+		}
+	}
+
+	mappings, n, err := s.FindMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 1 {
+		return nil, errDBRPMappingNotFound
+	}
+
+	return mappings[0], nil
+}
+
+// FindMany returns the dbrp mappings that match filter and the total count of matching dbrp mappings.
+func (s *Service) FindMany(ctx context.Context, filter influxdb.DBRPMappingFilter, opt ...influxdb.FindOptions) ([]*influxdb.DBRPMapping, int, error) {
+	// A filter by every name finds at most one mapping, which is looked up by its key.
+	if filter.Cluster != nil && filter.Database != nil && filter.RetentionPolicy != nil {
+		m, err := s.FindBy(ctx, *filter.Cluster, *filter.Database, *filter.RetentionPolicy)
+		if err != nil {
+			return nil, 0, err
+		}
+		if filter.Default != nil && *filter.Default != m.Default {
+			return []*influxdb.DBRPMapping{}, 0, nil
+		}
+		return []*influxdb.DBRPMapping{m}, 1, nil
+	}
+
+	mappings := []*influxdb.DBRPMapping{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(dbrpMappingBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			m := &influxdb.DBRPMapping{}
+			if err := json.Unmarshal(v, m); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			if filterDBRPMapping(filter, m) {
+				mappings = append(mappings, m)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return mappings, len(mappings), nil
+}
+
+func filterDBRPMapping(filter influxdb.DBRPMappingFilter, m *influxdb.DBRPMapping) bool {
+	return (filter.Cluster == nil || *filter.Cluster == m.Cluster) &&
+		(filter.Database == nil || *filter.Database == m.Database) &&
+		(filter.RetentionPolicy == nil || *filter.RetentionPolicy == m.RetentionPolicy) &&
+		(filter.Default == nil || *filter.Default == m.Default)
+}
+
+// Create creates a new dbrp mapping. Creating a mapping that exists already is
+// not an error, but creating a different one with the same names is.
+// A database has at most one default retention policy in an organization, so
+// creating a default mapping makes the other mappings of its database non-default.
+func (s *Service) Create(ctx context.Context, m *influxdb.DBRPMapping) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		existing, err := s.findDBRPMapping(ctx, tx, m.Cluster, m.Database, m.RetentionPolicy)
+		if err != nil && err != errDBRPMappingNotFound {
+			return err
+		}
+		if existing != nil && !existing.Equal(m) {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  "dbrp mapping already exists",
+			}
+		}
+
+		if m.Default {
+			if err := s.unsetDefaultDBRPMappings(ctx, tx, m); err != nil {
+				return err
+			}
+		}
+
+		return s.putDBRPMapping(ctx, tx, m)
+	})
+}
+
+// unsetDefaultDBRPMappings makes the other mappings of the database of m in
+// its cluster and organization non-default.
+func (s *Service) unsetDefaultDBRPMappings(ctx context.Context, tx Tx, m *influxdb.DBRPMapping) error {
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var defaults []*influxdb.DBRPMapping
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		o := &influxdb.DBRPMapping{}
+		if err := json.Unmarshal(v, o); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if o.Default && o.Cluster == m.Cluster && o.Database == m.Database &&
+			o.OrganizationID == m.OrganizationID && o.RetentionPolicy != m.RetentionPolicy {
+			defaults = append(defaults, o)
+		}
+	}
+
+	for _, o := range defaults {
+		o.Default = false
+		if err := s.putDBRPMapping(ctx, tx, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) putDBRPMapping(ctx context.Context, tx Tx, m *influxdb.DBRPMapping) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(dbrpMappingBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodeDBRPMappingKey(m.Cluster, m.Database, m.RetentionPolicy), v)
+}
+
+// Delete removes a dbrp mapping.
+// Deleting a mapping that does not exist is not an error.
+func (s *Service) Delete(ctx context.Context, cluster, db, rp string) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(dbrpMappingBucket)
+		if err != nil {
+			return err
+		}
+
+		if err := b.Delete(encodeDBRPMappingKey(cluster, db, rp)); err != nil && !IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
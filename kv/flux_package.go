@@ -0,0 +1,279 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	fluxPackageBucket = []byte("fluxpackagesv1")
+)
+
+var _ influxdb.FluxPackageService = (*Service)(nil)
+
+func (s *Service) initializeFluxPackages(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(fluxPackageBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindFluxPackageByID retrieves a flux package by id.
+func (s *Service) FindFluxPackageByID(ctx context.Context, id influxdb.ID) (*influxdb.FluxPackage, error) {
+	var fp *influxdb.FluxPackage
+	err := s.kv.View(ctx, func(tx Tx) error {
+		t, err := s.findFluxPackageByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		fp = t
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindFluxPackageByID,
+			Err: err,
+		}
+	}
+
+	return fp, nil
+}
+
+func (s *Service) findFluxPackageByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.FluxPackage, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(fluxPackageBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrFluxPackageNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fp := &influxdb.FluxPackage{}
+	if err := json.Unmarshal(v, fp); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	return fp, nil
+}
+
+// FindFluxPackages returns all flux packages that match the filter.
+func (s *Service) FindFluxPackages(ctx context.Context, filter influxdb.FluxPackageFilter) ([]*influxdb.FluxPackage, error) {
+	fps := []*influxdb.FluxPackage{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(fluxPackageBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			fp := &influxdb.FluxPackage{}
+			if err := json.Unmarshal(v, fp); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			if filter.OrganizationID != nil && fp.OrganizationID != *filter.OrganizationID {
+				continue
+			}
+			if filter.Name != nil && fp.Name != *filter.Name {
+				continue
+			}
+			fps = append(fps, fp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindFluxPackages,
+			Err: err,
+		}
+	}
+
+	return fps, nil
+}
+
+// CreateFluxPackage creates a new flux package and assigns it an ID.
+func (s *Service) CreateFluxPackage(ctx context.Context, fp *influxdb.FluxPackage) error {
+	if err := fp.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateFluxPackage,
+			Err: err,
+		}
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, fp.OrganizationID); err != nil {
+			return err
+		}
+		if err := s.uniqueFluxPackageName(ctx, tx, fp); err != nil {
+			return err
+		}
+
+		fp.ID = s.IDGenerator.ID()
+		now := s.Now()
+		fp.CreatedAt = now
+		fp.UpdatedAt = now
+		return s.putFluxPackage(ctx, tx, fp)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateFluxPackage,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// uniqueFluxPackageName returns a conflict error if another package of the
+// organization has the name of the package, since they would share an import path.
+func (s *Service) uniqueFluxPackageName(ctx context.Context, tx Tx, fp *influxdb.FluxPackage) error {
+	b, err := tx.Bucket(fluxPackageBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		other := &influxdb.FluxPackage{}
+		if err := json.Unmarshal(v, other); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if other.OrganizationID == fp.OrganizationID && other.Name == fp.Name {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("flux package %q already exists in the organization", fp.Name),
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) putFluxPackage(ctx context.Context, tx Tx, fp *influxdb.FluxPackage) error {
+	v, err := json.Marshal(fp)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encID, err := fp.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(fluxPackageBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encID, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// UpdateFluxPackage updates a single flux package with a changeset.
+func (s *Service) UpdateFluxPackage(ctx context.Context, id influxdb.ID, upd influxdb.FluxPackageUpdate) (*influxdb.FluxPackage, error) {
+	var fp *influxdb.FluxPackage
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		t, err := s.findFluxPackageByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := upd.Apply(t); err != nil {
+			return err
+		}
+		t.UpdatedAt = s.Now()
+
+		if err := s.putFluxPackage(ctx, tx, t); err != nil {
+			return err
+		}
+		fp = t
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateFluxPackage,
+			Err: err,
+		}
+	}
+
+	return fp, nil
+}
+
+// DeleteFluxPackage removes a flux package by its ID.
+// Queries that import the package fail to compile afterwards.
+func (s *Service) DeleteFluxPackage(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findFluxPackageByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		encID, err := id.Encode()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(fluxPackageBucket)
+		if err != nil {
+			return err
+		}
+
+		return b.Delete(encID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteFluxPackage,
+			Err: err,
+		}
+	}
+
+	return nil
+}
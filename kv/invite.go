@@ -0,0 +1,362 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	inviteBucket = []byte("invitesv1")
+	inviteIndex  = []byte("inviteindexv1")
+)
+
+var _ influxdb.InviteService = (*Service)(nil)
+
+func (s *Service) initializeInvites(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(inviteBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(inviteIndex); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateInvite creates a pending invite with a new token.
+func (s *Service) CreateInvite(ctx context.Context, i *influxdb.Invite) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := i.Valid(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, i.OrgID); err != nil {
+			return err
+		}
+
+		token, err := s.TokenGenerator.Token()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		now := s.Now()
+		i.ID = s.IDGenerator.ID()
+		i.Token = token
+		i.Status = influxdb.InvitePending
+		i.UserID = 0
+		i.AcceptedAt = nil
+		i.CreatedAt = now
+		if i.ExpiresAt.IsZero() {
+			i.ExpiresAt = now.Add(influxdb.DefaultInviteTTL)
+		}
+		if !i.ExpiresAt.After(now) {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "invite must expire in the future",
+			}
+		}
+
+		return s.putInvite(ctx, tx, i)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateInvite,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindInviteByID returns a single invite by ID.
+func (s *Service) FindInviteByID(ctx context.Context, id influxdb.ID) (*influxdb.Invite, error) {
+	var i *influxdb.Invite
+	err := s.kv.View(ctx, func(tx Tx) error {
+		invite, err := s.findInviteByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		i = invite
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindInviteByID,
+			Err: err,
+		}
+	}
+	return i, nil
+}
+
+func (s *Service) findInviteByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Invite, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(inviteBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrInviteNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.decodeInvite(v)
+}
+
+func (s *Service) decodeInvite(v []byte) (*influxdb.Invite, error) {
+	i := &influxdb.Invite{}
+	if err := json.Unmarshal(v, i); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	i.Status = i.StatusAt(s.Now())
+	return i, nil
+}
+
+// FindInviteByToken returns the invite with the token.
+func (s *Service) FindInviteByToken(ctx context.Context, token string) (*influxdb.Invite, error) {
+	var i *influxdb.Invite
+	err := s.kv.View(ctx, func(tx Tx) error {
+		invite, err := s.findInviteByToken(ctx, tx, token)
+		if err != nil {
+			return err
+		}
+		i = invite
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindInviteByToken,
+			Err: err,
+		}
+	}
+	return i, nil
+}
+
+func (s *Service) findInviteByToken(ctx context.Context, tx Tx, token string) (*influxdb.Invite, error) {
+	idx, err := tx.Bucket(inviteIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedID, err := idx.Get([]byte(token))
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrInviteNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var id influxdb.ID
+	if err := id.Decode(encodedID); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	return s.findInviteByID(ctx, tx, id)
+}
+
+// FindInvites returns the invites that match the filter.
+func (s *Service) FindInvites(ctx context.Context, filter influxdb.InviteFilter) ([]*influxdb.Invite, error) {
+	is := []*influxdb.Invite{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(inviteBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			i, err := s.decodeInvite(v)
+			if err != nil {
+				return err
+			}
+			if filter.OrgID != nil && *filter.OrgID != i.OrgID {
+				continue
+			}
+			is = append(is, i)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindInvites,
+			Err: err,
+		}
+	}
+	return is, nil
+}
+
+// RevokeInvite deletes an invite.
+func (s *Service) RevokeInvite(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		i, err := s.findInviteByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		return s.deleteInvite(ctx, tx, i)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpRevokeInvite,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// AcceptInvite creates the user of a pending invite and maps them to its organization.
+// The invite is kept, accepted, so that it records who joined the organization.
+func (s *Service) AcceptInvite(ctx context.Context, token string, a influxdb.InviteAcceptance) (*influxdb.User, error) {
+	var u *influxdb.User
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		i, err := s.findInviteByToken(ctx, tx, token)
+		if err != nil {
+			return err
+		}
+
+		switch i.Status {
+		case influxdb.InviteAccepted:
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  "invite has already been accepted",
+			}
+		case influxdb.InviteExpired:
+			return &influxdb.Error{
+				Code: influxdb.EForbidden,
+				Msg:  "invite has expired",
+			}
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, i.OrgID); err != nil {
+			return err
+		}
+
+		// Check the password before creating the user, so that a password
+		// the policy rejects creates nothing on stores that do not roll back.
+		if err := s.passwordPolicy().Check(a.Password); err != nil {
+			return err
+		}
+
+		user := &influxdb.User{Name: a.Name}
+		if user.Name == "" {
+			user.Name = i.Email
+		}
+		if err := s.createUser(ctx, tx, user); err != nil {
+			return err
+		}
+		if err := s.setPassword(ctx, tx, user.Name, a.Password); err != nil {
+			return err
+		}
+
+		if err := s.createUserResourceMapping(ctx, tx, &influxdb.UserResourceMapping{
+			UserID:       user.ID,
+			UserType:     i.Role,
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   i.OrgID,
+		}); err != nil {
+			return err
+		}
+
+		now := s.Now()
+		i.Status = influxdb.InviteAccepted
+		i.UserID = user.ID
+		i.AcceptedAt = &now
+		if err := s.putInvite(ctx, tx, i); err != nil {
+			return err
+		}
+
+		u = user
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpAcceptInvite,
+			Err: err,
+		}
+	}
+	return u, nil
+}
+
+func (s *Service) putInvite(ctx context.Context, tx Tx, i *influxdb.Invite) error {
+	v, err := json.Marshal(i)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := i.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(inviteIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Put([]byte(i.Token), encodedID); err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(inviteBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+func (s *Service) deleteInvite(ctx context.Context, tx Tx, i *influxdb.Invite) error {
+	encodedID, err := i.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(inviteIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Delete([]byte(i.Token)); err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(inviteBucket)
+	if err != nil {
+		return err
+	}
+	return b.Delete(encodedID)
+}
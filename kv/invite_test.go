@@ -0,0 +1,56 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltInviteService(t *testing.T) {
+	influxdbtesting.InviteService(initBoltInviteService, t)
+}
+
+func TestInmemInviteService(t *testing.T) {
+	influxdbtesting.InviteService(initInmemInviteService, t)
+}
+
+func initBoltInviteService(f influxdbtesting.InviteFields, t *testing.T) (influxdbtesting.InviteServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initInviteService(s, f, t), closeBolt
+}
+
+func initInmemInviteService(f influxdbtesting.InviteFields, t *testing.T) (influxdbtesting.InviteServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initInviteService(s, f, t), closeInmem
+}
+
+func initInviteService(s kv.Store, f influxdbtesting.InviteFields, t *testing.T) influxdbtesting.InviteServices {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	tokens := svc.TokenGenerator
+	if f.TokenGenerator != nil {
+		tokens = f.TokenGenerator
+	}
+
+	ctx := context.Background()
+	for _, i := range f.Invites {
+		// Invites are created with the token they are populated with.
+		svc.TokenGenerator = mock.NewTokenGenerator(i.Token, nil)
+		if err := createWithID(svc, i.ID, func() error {
+			return svc.CreateInvite(ctx, i)
+		}); err != nil {
+			t.Fatalf("failed to populate invites: %v", err)
+		}
+	}
+	svc.TokenGenerator = tokens
+	return svc
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+	"testing"
 
+	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/bolt"
 	"github.com/influxdata/influxdb/inmem"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
 )
 
 func NewTestBoltStore() (kv.Store, func(), error) {
@@ -35,3 +38,45 @@ func NewTestBoltStore() (kv.Store, func(), error) {
 func NewTestInmemStore() (kv.Store, func(), error) {
 	return inmem.NewKVStore(), func() {}, nil
 }
+
+// initTestService initializes a service of the store that uses the ID and time
+// generators and populates it with the organizations.
+func initTestService(s kv.Store, ids influxdb.IDGenerator, tg influxdb.TimeGenerator, orgs []*influxdb.Organization, t *testing.T) *kv.Service {
+	svc := kv.NewService(s)
+	if ids != nil {
+		svc.IDGenerator = ids
+	}
+	svc.TimeGenerator = tg
+	if tg == nil {
+		svc.TimeGenerator = influxdb.RealTimeGenerator{}
+	}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+	for _, o := range orgs {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations: %v", err)
+		}
+	}
+	return svc
+}
+
+// createWithID calls create while the service generates id, so that the store
+// can be populated with resources of known IDs through their create methods.
+func createWithID(svc *kv.Service, id influxdb.ID, create func() error) error {
+	ids := svc.IDGenerator
+	defer func() { svc.IDGenerator = ids }()
+
+	svc.IDGenerator = mock.IDGenerator{
+		IDFn: func() influxdb.ID {
+			return id
+		},
+	}
+	return create()
+}
+
+func strPtr(s string) *string {
+	return &s
+}
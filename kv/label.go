@@ -190,6 +190,61 @@ func (s *Service) FindResourceLabels(ctx context.Context, filter influxdb.LabelM
 	return ls, nil
 }
 
+var _ influxdb.LabelMappingBatchService = (*Service)(nil)
+
+// FindResourcesLabels returns the labels of each of the resources by the ID of
+// the resource. The label mappings are scanned once, and every label is found
+// once however many of the resources carry it.
+func (s *Service) FindResourcesLabels(ctx context.Context, resourceIDs []influxdb.ID) (map[influxdb.ID][]*influxdb.Label, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	m := make(map[influxdb.ID][]*influxdb.Label, len(resourceIDs))
+	for _, id := range resourceIDs {
+		m[id] = []*influxdb.Label{}
+	}
+
+	err := s.kv.View(ctx, func(tx Tx) error {
+		idx, err := tx.Bucket(labelMappingBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := idx.Cursor()
+		if err != nil {
+			return err
+		}
+
+		labels := map[influxdb.ID]*influxdb.Label{}
+		for k, _ := cur.First(); k != nil; k, _ = cur.Next() {
+			resourceID, labelID, err := decodeLabelMappingKey(k)
+			if err != nil {
+				return err
+			}
+			if _, ok := m[resourceID]; !ok {
+				continue
+			}
+
+			l, ok := labels[labelID]
+			if !ok {
+				l, err = s.findLabelByID(ctx, tx, labelID)
+				if l == nil && err != nil {
+					// Orphaned mappings are skipped, as by findResourceLabels.
+					continue
+				}
+				labels[labelID] = l
+			}
+			m[resourceID] = append(m[resourceID], l)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
 // CreateLabelMapping creates a new mapping between a resource and a label.
 func (s *Service) CreateLabelMapping(ctx context.Context, m *influxdb.LabelMapping) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
@@ -441,7 +496,7 @@ func (s *Service) putLabel(ctx context.Context, tx Tx, l *influxdb.Label) error
 		}
 	}
 
-	return nil
+	return s.indexSearchDocument(ctx, tx, labelSearchDocument(l))
 }
 
 // PutLabelMapping writes a label mapping to boltdb
@@ -528,5 +583,9 @@ func (s *Service) deleteLabel(ctx context.Context, tx Tx, id influxdb.ID) error
 		return err
 	}
 
+	if err := s.unindexSearchDocument(ctx, tx, influxdb.LabelsResourceType, id); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
@@ -71,3 +72,62 @@ func initLabelService(s kv.Store, f influxdbtesting.LabelFields, t *testing.T) (
 		}
 	}
 }
+
+func TestBoltLabelService_FindResourcesLabels(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	testFindResourcesLabels(s, t)
+}
+
+func TestInmemLabelService_FindResourcesLabels(t *testing.T) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeInmem()
+
+	testFindResourcesLabels(s, t)
+}
+
+// testFindResourcesLabels checks that the labels of many resources are found
+// at once, including resources without labels, and that other resources are
+// left out.
+func testFindResourcesLabels(st kv.Store, t *testing.T) {
+	svc := initTestService(st, nil, nil, nil, t)
+
+	ctx := context.Background()
+	prod := &influxdb.Label{ID: 10, OrgID: 1, Name: "prod"}
+	team := &influxdb.Label{ID: 11, OrgID: 1, Name: "team-a"}
+	for _, l := range []*influxdb.Label{prod, team} {
+		if err := svc.PutLabel(ctx, l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, m := range []*influxdb.LabelMapping{
+		{LabelID: prod.ID, ResourceID: 1, ResourceType: influxdb.OrgsResourceType},
+		{LabelID: team.ID, ResourceID: 1, ResourceType: influxdb.OrgsResourceType},
+		{LabelID: team.ID, ResourceID: 2, ResourceType: influxdb.TelegrafsResourceType},
+		{LabelID: prod.ID, ResourceID: 4, ResourceType: influxdb.UsersResourceType},
+	} {
+		if err := svc.CreateLabelMapping(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := svc.FindResourcesLabels(ctx, []influxdb.ID{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[influxdb.ID][]*influxdb.Label{
+		1: {prod, team},
+		2: {team},
+		3: {},
+	}
+	if !cmp.Equal(got, want) {
+		t.Errorf("unexpected labels -got/+want\n%s", cmp.Diff(got, want))
+	}
+}
@@ -57,6 +57,30 @@ func (s *Service) Name(ctx context.Context, resource influxdb.ResourceType, id i
 			return "", err
 		}
 		return r.Name, nil
+	case influxdb.TeamsResourceType: // 14
+		r, err := s.FindTeamByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case influxdb.NotificationEndpointsResourceType: // 15
+		r, err := s.FindNotificationEndpointByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case influxdb.ChecksResourceType: // 16
+		r, err := s.FindCheckByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case influxdb.NotificationRulesResourceType: // 17
+		r, err := s.FindNotificationRuleByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
 	}
 
 	return "", nil
@@ -0,0 +1,295 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	measurementSchemaBucket = []byte("measurementschemasv1")
+)
+
+var _ influxdb.MeasurementSchemaService = (*Service)(nil)
+
+func (s *Service) initializeMeasurementSchemas(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(measurementSchemaBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// measurementSchemaKey is the ID of the bucket of a schema followed by the ID
+// of the schema, so the schemas of a bucket are found by a prefix.
+func measurementSchemaKey(bucketID, id influxdb.ID) ([]byte, error) {
+	prefix, err := bucketID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return append(prefix, encID...), nil
+}
+
+// FindMeasurementSchemaByID retrieves a measurement schema of a bucket by id.
+func (s *Service) FindMeasurementSchemaByID(ctx context.Context, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	var ms *influxdb.MeasurementSchema
+	err := s.kv.View(ctx, func(tx Tx) error {
+		m, err := s.findMeasurementSchemaByID(ctx, tx, bucketID, id)
+		if err != nil {
+			return err
+		}
+		ms = m
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMeasurementSchemaByID,
+			Err: err,
+		}
+	}
+
+	return ms, nil
+}
+
+func (s *Service) findMeasurementSchemaByID(ctx context.Context, tx Tx, bucketID, id influxdb.ID) (*influxdb.MeasurementSchema, error) {
+	key, err := measurementSchemaKey(bucketID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(measurementSchemaBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrMeasurementSchemaNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeMeasurementSchema(v)
+}
+
+// FindMeasurementSchemas returns the measurement schemas of a bucket that match the filter.
+func (s *Service) FindMeasurementSchemas(ctx context.Context, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+	var mss []*influxdb.MeasurementSchema
+	err := s.kv.View(ctx, func(tx Tx) error {
+		ms, err := s.findMeasurementSchemas(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		mss = ms
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindMeasurementSchemas,
+			Err: err,
+		}
+	}
+
+	return mss, nil
+}
+
+func (s *Service) findMeasurementSchemas(ctx context.Context, tx Tx, filter influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+	prefix, err := filter.BucketID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "bucketID is required",
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(measurementSchemaBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	mss := []*influxdb.MeasurementSchema{}
+	for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		m, err := decodeMeasurementSchema(v)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Name != nil && m.Name != *filter.Name {
+			continue
+		}
+		mss = append(mss, m)
+	}
+	return mss, nil
+}
+
+// CreateMeasurementSchema creates a new measurement schema and assigns it an ID
+// and the organization of its bucket. The bucket of the schema must have an
+// explicit schema type, and the name of a schema must be unique within its bucket.
+func (s *Service) CreateMeasurementSchema(ctx context.Context, m *influxdb.MeasurementSchema) error {
+	if err := m.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateMeasurementSchema,
+			Err: err,
+		}
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := s.findBucketByID(ctx, tx, m.BucketID)
+		if err != nil {
+			return err
+		}
+		if m.OrgID.Valid() && m.OrgID != b.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID does not match the organization of the bucket",
+			}
+		}
+		if !b.SchemaType.Explicit() {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("bucket %s does not have an explicit schema type", b.Name),
+			}
+		}
+
+		ms, err := s.findMeasurementSchemas(ctx, tx, influxdb.MeasurementSchemaFilter{
+			BucketID: m.BucketID,
+			Name:     &m.Name,
+		})
+		if err != nil {
+			return err
+		}
+		if len(ms) > 0 {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("measurement schema with name %s already exists", m.Name),
+			}
+		}
+
+		m.ID = s.IDGenerator.ID()
+		m.OrgID = b.OrgID
+		m.CreatedAt = s.Now()
+		m.UpdatedAt = s.Now()
+		return s.putMeasurementSchema(ctx, tx, m)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateMeasurementSchema,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// UpdateMeasurementSchema adds columns to a measurement schema.
+func (s *Service) UpdateMeasurementSchema(ctx context.Context, bucketID, id influxdb.ID, upd influxdb.MeasurementSchemaUpdate) (*influxdb.MeasurementSchema, error) {
+	var ms *influxdb.MeasurementSchema
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		m, err := s.findMeasurementSchemaByID(ctx, tx, bucketID, id)
+		if err != nil {
+			return err
+		}
+
+		if err := upd.Apply(m); err != nil {
+			return err
+		}
+
+		m.UpdatedAt = s.Now()
+		ms = m
+		return s.putMeasurementSchema(ctx, tx, m)
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateMeasurementSchema,
+			Err: err,
+		}
+	}
+
+	return ms, nil
+}
+
+func (s *Service) putMeasurementSchema(ctx context.Context, tx Tx, m *influxdb.MeasurementSchema) error {
+	v, err := json.Marshal(m)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	key, err := measurementSchemaKey(m.BucketID, m.ID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(measurementSchemaBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// deleteMeasurementSchemas removes the measurement schemas of a deleted bucket.
+func (s *Service) deleteMeasurementSchemas(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	ms, err := s.findMeasurementSchemas(ctx, tx, influxdb.MeasurementSchemaFilter{BucketID: bucketID})
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(measurementSchemaBucket)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range ms {
+		key, err := measurementSchemaKey(m.BucketID, m.ID)
+		if err != nil {
+			return err
+		}
+		if err := b.Delete(key); err != nil {
+			return &influxdb.Error{
+				Err: err,
+			}
+		}
+	}
+	return nil
+}
+
+func decodeMeasurementSchema(v []byte) (*influxdb.MeasurementSchema, error) {
+	m := &influxdb.MeasurementSchema{}
+	if err := json.Unmarshal(v, m); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return m, nil
+}
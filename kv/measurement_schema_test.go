@@ -0,0 +1,52 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltMeasurementSchemaService(t *testing.T) {
+	influxdbtesting.MeasurementSchemaService(initBoltMeasurementSchemaService, t)
+}
+
+func TestInmemMeasurementSchemaService(t *testing.T) {
+	influxdbtesting.MeasurementSchemaService(initInmemMeasurementSchemaService, t)
+}
+
+func initBoltMeasurementSchemaService(f influxdbtesting.MeasurementSchemaFields, t *testing.T) (influxdbtesting.MeasurementSchemaServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initMeasurementSchemaService(s, f, t), closeBolt
+}
+
+func initInmemMeasurementSchemaService(f influxdbtesting.MeasurementSchemaFields, t *testing.T) (influxdbtesting.MeasurementSchemaServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initMeasurementSchemaService(s, f, t), closeInmem
+}
+
+func initMeasurementSchemaService(s kv.Store, f influxdbtesting.MeasurementSchemaFields, t *testing.T) influxdbtesting.MeasurementSchemaServices {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets: %v", err)
+		}
+	}
+	for _, m := range f.MeasurementSchemas {
+		if err := createWithID(svc, m.ID, func() error {
+			return svc.CreateMeasurementSchema(ctx, m)
+		}); err != nil {
+			t.Fatalf("failed to populate measurement schemas: %v", err)
+		}
+	}
+	return svc
+}
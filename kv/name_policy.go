@@ -0,0 +1,98 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+// namePolicy returns the name policy of the organization, or the name policy
+// of the service if the organization has none.
+func (s *Service) namePolicy(ctx context.Context, tx Tx, orgID influxdb.ID) (influxdb.NamePolicy, error) {
+	o, err := s.findOrganizationByID(ctx, tx, orgID)
+	if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return influxdb.NamePolicy{}, err
+	}
+	if o != nil && o.NamePolicy != nil {
+		return *o.NamePolicy, nil
+	}
+	if s.Config.NamePolicy != nil {
+		return *s.Config.NamePolicy, nil
+	}
+	return influxdb.DefaultNamePolicy, nil
+}
+
+// uniqueTaskName returns a conflict error if the names of tasks of the
+// organization must be unique and another of its tasks has the name.
+func (s *Service) uniqueTaskName(ctx context.Context, tx Tx, orgID, id influxdb.ID, name string) error {
+	p, err := s.namePolicy(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	if !p.Unique(influxdb.TasksResourceType) {
+		return nil
+	}
+
+	indexBucket, err := tx.Bucket(taskIndexBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	c, err := indexBucket.Cursor()
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	prefix, err := orgID.Encode()
+	if err != nil {
+		return influxdb.ErrInvalidTaskID
+	}
+	prefix = append(prefix, '/')
+
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		var taskID influxdb.ID
+		if err := taskID.Decode(v); err != nil {
+			return influxdb.ErrInvalidTaskID
+		}
+		if taskID == id {
+			continue
+		}
+		t, err := s.findTaskByID(ctx, tx, taskID)
+		if err == influxdb.ErrTaskNotFound {
+			// The index may have entries of deleted tasks.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if t.Name == name {
+			return influxdb.NameConflictError(influxdb.TasksResourceType, name)
+		}
+	}
+	return nil
+}
+
+// uniqueDashboardName returns a conflict error if the names of dashboards of
+// the organization must be unique and another of its dashboards has the name.
+func (s *Service) uniqueDashboardName(ctx context.Context, tx Tx, orgID, id influxdb.ID, name string) error {
+	if !orgID.Valid() {
+		return nil
+	}
+	p, err := s.namePolicy(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	if !p.Unique(influxdb.DashboardsResourceType) {
+		return nil
+	}
+
+	ds, err := s.findOrganizationDashboards(ctx, tx, orgID)
+	if err != nil {
+		return err
+	}
+	for _, d := range ds {
+		if d.ID != id && d.Name == name {
+			return influxdb.NameConflictError(influxdb.DashboardsResourceType, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltNamePolicyService(t *testing.T) {
+	influxdbtesting.NamePolicyService(initBoltNamePolicyService, t)
+}
+
+func TestInmemNamePolicyService(t *testing.T) {
+	influxdbtesting.NamePolicyService(initInmemNamePolicyService, t)
+}
+
+func initBoltNamePolicyService(f influxdbtesting.NamePolicyFields, t *testing.T) (influxdbtesting.NamePolicyServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initNamePolicyService(s, f, t), closeBolt
+}
+
+func initInmemNamePolicyService(f influxdbtesting.NamePolicyFields, t *testing.T) (influxdbtesting.NamePolicyServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initNamePolicyService(s, f, t), closeInmem
+}
+
+func initNamePolicyService(s kv.Store, f influxdbtesting.NamePolicyFields, t *testing.T) influxdbtesting.NamePolicyServices {
+	svc := initTestService(s, nil, nil, f.Organizations, t)
+	svc.Config.NamePolicy = f.NamePolicy
+
+	ctx := context.Background()
+	for _, d := range f.Dashboards {
+		if err := createWithID(svc, d.ID, func() error {
+			return svc.CreateDashboard(ctx, d)
+		}); err != nil {
+			t.Fatalf("failed to populate dashboards: %v", err)
+		}
+	}
+	return svc
+}
@@ -0,0 +1,431 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var (
+	notificationEndpointBucket = []byte("notificationEndpointv1")
+)
+
+var _ influxdb.NotificationEndpointService = (*Service)(nil)
+
+func (s *Service) initializeNotificationEndpoints(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(notificationEndpointBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindNotificationEndpointByID retrieves a notification endpoint by id.
+func (s *Service) FindNotificationEndpointByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationEndpoint, error) {
+	var e *influxdb.NotificationEndpoint
+	err := s.kv.View(ctx, func(tx Tx) error {
+		endpoint, err := s.findNotificationEndpointByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		e = endpoint
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindNotificationEndpointByID,
+			Err: err,
+		}
+	}
+
+	return e, nil
+}
+
+func (s *Service) findNotificationEndpointByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.NotificationEndpoint, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(notificationEndpointBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrNotificationEndpointNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e := &influxdb.NotificationEndpoint{}
+	if err := json.Unmarshal(v, e); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return e, nil
+}
+
+func filterNotificationEndpointsFn(filter influxdb.NotificationEndpointFilter) func(e *influxdb.NotificationEndpoint) bool {
+	return func(e *influxdb.NotificationEndpoint) bool {
+		return (filter.ID == nil || *filter.ID == e.ID) &&
+			(filter.OrgID == nil || *filter.OrgID == e.OrgID) &&
+			(filter.Name == nil || *filter.Name == e.Name) &&
+			(filter.Type == nil || *filter.Type == e.Type)
+	}
+}
+
+// FindNotificationEndpoints returns the notification endpoints that match the filter.
+func (s *Service) FindNotificationEndpoints(ctx context.Context, filter influxdb.NotificationEndpointFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationEndpoint, int, error) {
+	es := []*influxdb.NotificationEndpoint{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		endpoints, err := s.findNotificationEndpoints(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		es = endpoints
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindNotificationEndpoints,
+			Err: err,
+		}
+	}
+
+	return es, len(es), nil
+}
+
+func (s *Service) findNotificationEndpoints(ctx context.Context, tx Tx, filter influxdb.NotificationEndpointFilter) ([]*influxdb.NotificationEndpoint, error) {
+	if filter.ID != nil {
+		e, err := s.findNotificationEndpointByID(ctx, tx, *filter.ID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.NotificationEndpoint{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filterNotificationEndpointsFn(filter)(e) {
+			return []*influxdb.NotificationEndpoint{}, nil
+		}
+		return []*influxdb.NotificationEndpoint{e}, nil
+	}
+
+	es := []*influxdb.NotificationEndpoint{}
+	filterFn := filterNotificationEndpointsFn(filter)
+	err := s.forEachNotificationEndpoint(ctx, tx, func(e *influxdb.NotificationEndpoint) bool {
+		if filterFn(e) {
+			es = append(es, e)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return es, nil
+}
+
+func (s *Service) forEachNotificationEndpoint(ctx context.Context, tx Tx, fn func(*influxdb.NotificationEndpoint) bool) error {
+	b, err := tx.Bucket(notificationEndpointBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		e := &influxdb.NotificationEndpoint{}
+		if err := json.Unmarshal(v, e); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if !fn(e) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// CreateNotificationEndpoint creates a notification endpoint, puts the
+// credentials given by value in the secrets of its organization and makes the
+// user on the context its owner.
+func (s *Service) CreateNotificationEndpoint(ctx context.Context, e *influxdb.NotificationEndpoint) error {
+	err := s.createNotificationEndpoint(ctx, e)
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateNotificationEndpoint,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) createNotificationEndpoint(ctx context.Context, e *influxdb.NotificationEndpoint) error {
+	if e.Status == "" {
+		e.Status = influxdb.Active
+	}
+	if err := e.Valid(); err != nil {
+		return err
+	}
+
+	e.ID = s.IDGenerator.ID()
+	e.BackfillSecretKeys()
+	creds := s.newCredentials()
+	if err := creds.stage(ctx, e.OrgID, e.SecretFields()); err != nil {
+		return creds.done(ctx, err)
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, e.OrgID); err != nil {
+			return err
+		}
+
+		if err := s.uniqueNotificationEndpointName(ctx, tx, e); err != nil {
+			return err
+		}
+
+		if err := creds.resolve(ctx, tx); err != nil {
+			return err
+		}
+
+		e.CreatedAt = s.Now()
+		e.UpdatedAt = s.Now()
+		if err := s.putNotificationEndpoint(ctx, tx, e); err != nil {
+			return err
+		}
+
+		if err := s.addResourceOwner(ctx, tx, influxdb.NotificationEndpointsResourceType, e.ID); err != nil {
+			s.Logger.Info("failed to make user owner of notification endpoint", zap.Error(err))
+		}
+
+		return nil
+	})
+	return creds.done(ctx, err)
+}
+
+// uniqueNotificationEndpointName returns an error if another notification
+// endpoint of the organization of e has its name.
+func (s *Service) uniqueNotificationEndpointName(ctx context.Context, tx Tx, e *influxdb.NotificationEndpoint) error {
+	es, err := s.findNotificationEndpoints(ctx, tx, influxdb.NotificationEndpointFilter{OrgID: &e.OrgID, Name: &e.Name})
+	if err != nil {
+		return err
+	}
+	for _, other := range es {
+		if other.ID != e.ID {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("notification endpoint with name %s already exists", e.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// deleteNotificationEndpointSecrets deletes the secrets of e named after it
+// that keep is not using anymore. Secrets it references by key are left.
+func (s *Service) deleteNotificationEndpointSecrets(ctx context.Context, tx Tx, creds *credentials, e, keep *influxdb.NotificationEndpoint) error {
+	kept := map[string]bool{}
+	if keep != nil {
+		for _, f := range keep.SecretFields() {
+			kept[f.Key] = true
+		}
+	}
+
+	for _, f := range e.SecretFields() {
+		if kept[f.Key] || !strings.HasPrefix(f.Key, e.ID.String()+"-") {
+			continue
+		}
+		if err := creds.delete(ctx, tx, e.OrgID, f.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateNotificationEndpoint updates a notification endpoint with the changeset.
+func (s *Service) UpdateNotificationEndpoint(ctx context.Context, id influxdb.ID, upd influxdb.NotificationEndpointUpdate) (*influxdb.NotificationEndpoint, error) {
+	var e *influxdb.NotificationEndpoint
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := upd.Valid(); err != nil {
+			return err
+		}
+
+		endpoint, err := s.findNotificationEndpointByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(endpoint)
+		if err := s.uniqueNotificationEndpointName(ctx, tx, endpoint); err != nil {
+			return err
+		}
+
+		endpoint.UpdatedAt = s.Now()
+		if err := s.putNotificationEndpoint(ctx, tx, endpoint); err != nil {
+			return err
+		}
+		e = endpoint
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateNotificationEndpoint,
+			Err: err,
+		}
+	}
+
+	return e, nil
+}
+
+// ReplaceNotificationEndpoint replaces the configuration of a notification
+// endpoint. Secrets named after the endpoint that it does not use anymore are
+// deleted.
+func (s *Service) ReplaceNotificationEndpoint(ctx context.Context, e *influxdb.NotificationEndpoint) (*influxdb.NotificationEndpoint, error) {
+	err := s.replaceNotificationEndpoint(ctx, e)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpReplaceNotificationEndpoint,
+			Err: err,
+		}
+	}
+
+	return e, nil
+}
+
+func (s *Service) replaceNotificationEndpoint(ctx context.Context, e *influxdb.NotificationEndpoint) error {
+	current, err := s.FindNotificationEndpointByID(ctx, e.ID)
+	if err != nil {
+		return err
+	}
+
+	e.OrgID = current.OrgID
+	e.CreatedAt = current.CreatedAt
+	if e.Status == "" {
+		e.Status = current.Status
+	}
+	if err := e.Valid(); err != nil {
+		return err
+	}
+
+	e.BackfillSecretKeys()
+	creds := s.newCredentials()
+	if err := creds.stage(ctx, e.OrgID, e.SecretFields()); err != nil {
+		return creds.done(ctx, err)
+	}
+
+	err = s.kv.Update(ctx, func(tx Tx) error {
+		// The endpoint may have changed since it was found above; the
+		// secrets it uses now are the ones to keep.
+		current, err := s.findNotificationEndpointByID(ctx, tx, e.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.uniqueNotificationEndpointName(ctx, tx, e); err != nil {
+			return err
+		}
+
+		if err := creds.resolve(ctx, tx); err != nil {
+			return err
+		}
+
+		e.UpdatedAt = s.Now()
+		if err := s.deleteNotificationEndpointSecrets(ctx, tx, creds, current, e); err != nil {
+			return err
+		}
+		return s.putNotificationEndpoint(ctx, tx, e)
+	})
+	return creds.done(ctx, err)
+}
+
+func (s *Service) putNotificationEndpoint(ctx context.Context, tx Tx, e *influxdb.NotificationEndpoint) error {
+	v, err := json.Marshal(e)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := e.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(notificationEndpointBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, v)
+}
+
+// DeleteNotificationEndpoint deletes a notification endpoint, the secrets
+// named after it and the mappings of users to it.
+func (s *Service) DeleteNotificationEndpoint(ctx context.Context, id influxdb.ID) error {
+	creds := s.newCredentials()
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteNotificationEndpoint(ctx, tx, creds, id)
+	})
+	if err := creds.done(ctx, err); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteNotificationEndpoint,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteNotificationEndpoint(ctx context.Context, tx Tx, creds *credentials, id influxdb.ID) error {
+	e, err := s.findNotificationEndpointByID(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(notificationEndpointBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(encodedID); err != nil {
+		return err
+	}
+
+	if err := s.deleteNotificationEndpointSecrets(ctx, tx, creds, e, nil); err != nil {
+		return err
+	}
+
+	return s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   id,
+		ResourceType: influxdb.NotificationEndpointsResourceType,
+	})
+}
@@ -0,0 +1,155 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltNotificationEndpointService(t *testing.T) {
+	influxdbtesting.NotificationEndpointService(initBoltNotificationEndpointService, t)
+}
+
+func TestInmemNotificationEndpointService(t *testing.T) {
+	influxdbtesting.NotificationEndpointService(initInmemNotificationEndpointService, t)
+}
+
+func TestBoltNotificationEndpointService_SecretService(t *testing.T) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeBolt()
+
+	testNotificationEndpointSecretService(s, t)
+}
+
+func TestInmemNotificationEndpointService_SecretService(t *testing.T) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeInmem()
+
+	testNotificationEndpointSecretService(s, t)
+}
+
+// testNotificationEndpointSecretService checks that the credentials of
+// notification endpoints are kept in the SecretService of the service when
+// it has one, here the secrets of another service.
+func testNotificationEndpointSecretService(st kv.Store, t *testing.T) {
+	secretStore, closeSecrets, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeSecrets()
+	secrets := initTestService(secretStore, nil, nil, nil, t)
+
+	orgID := influxdb.ID(1)
+	svc := initTestService(st, nil, nil, []*influxdb.Organization{{ID: orgID, Name: "theorg"}}, t)
+	svc.SecretService = secrets
+
+	ctx := context.Background()
+	routingKey := "abc123"
+	e := &influxdb.NotificationEndpoint{
+		OrgID:      orgID,
+		Name:       "oncall",
+		Type:       influxdb.PagerDutyNotificationEndpoint,
+		RoutingKey: &influxdb.SecretField{Value: &routingKey},
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, e); err != nil {
+		t.Fatalf("failed to create notification endpoint: %v", err)
+	}
+	key := e.RoutingKey.Key
+	if v, err := secrets.LoadSecret(ctx, orgID, key); err != nil || v != routingKey {
+		t.Errorf("expected the routing key to be put in the secret service, got %q, %v", v, err)
+	}
+	if _, err := svc.LoadSecret(ctx, orgID, key); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the routing key not to be put in the secrets of the service, got %v", err)
+	}
+
+	if err := secrets.PutSecret(ctx, orgID, "shared", "xyz789"); err != nil {
+		t.Fatal(err)
+	}
+	shared := &influxdb.NotificationEndpoint{
+		OrgID:      orgID,
+		Name:       "backup",
+		Type:       influxdb.PagerDutyNotificationEndpoint,
+		RoutingKey: &influxdb.SecretField{Key: "shared"},
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, shared); err != nil {
+		t.Errorf("expected an endpoint referencing a secret of the secret service to be created, got %v", err)
+	}
+
+	// The credentials put for changes that fail are reverted.
+	otherKey := "def456"
+	dup := &influxdb.NotificationEndpoint{
+		OrgID:      orgID,
+		Name:       "backup",
+		Type:       influxdb.PagerDutyNotificationEndpoint,
+		RoutingKey: &influxdb.SecretField{Value: &otherKey},
+	}
+	if err := svc.CreateNotificationEndpoint(ctx, dup); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected creating an endpoint with a taken name to conflict, got %v", err)
+	}
+	if _, err := secrets.LoadSecret(ctx, orgID, dup.RoutingKey.Key); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the routing key of the endpoint that was not created to be deleted, got %v", err)
+	}
+	replaced := &influxdb.NotificationEndpoint{
+		ID:         e.ID,
+		Name:       "backup",
+		Type:       influxdb.PagerDutyNotificationEndpoint,
+		RoutingKey: &influxdb.SecretField{Value: &otherKey},
+	}
+	if _, err := svc.ReplaceNotificationEndpoint(ctx, replaced); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected replacing an endpoint with a taken name to conflict, got %v", err)
+	}
+	if v, err := secrets.LoadSecret(ctx, orgID, key); err != nil || v != routingKey {
+		t.Errorf("expected the routing key of the endpoint that was not replaced to be restored, got %q, %v", v, err)
+	}
+
+	if err := svc.DeleteNotificationEndpoint(ctx, e.ID); err != nil {
+		t.Fatalf("failed to delete notification endpoint: %v", err)
+	}
+	if _, err := secrets.LoadSecret(ctx, orgID, key); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the routing key to be deleted from the secret service, got %v", err)
+	}
+}
+
+func initBoltNotificationEndpointService(f influxdbtesting.NotificationEndpointFields, t *testing.T) (influxdbtesting.NotificationEndpointServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initNotificationEndpointService(s, f, t), closeBolt
+}
+
+func initInmemNotificationEndpointService(f influxdbtesting.NotificationEndpointFields, t *testing.T) (influxdbtesting.NotificationEndpointServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initNotificationEndpointService(s, f, t), closeInmem
+}
+
+func initNotificationEndpointService(s kv.Store, f influxdbtesting.NotificationEndpointFields, t *testing.T) influxdbtesting.NotificationEndpointServices {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, sec := range f.Secrets {
+		if err := svc.PutSecrets(ctx, sec.OrganizationID, sec.Env); err != nil {
+			t.Fatalf("failed to populate secrets: %v", err)
+		}
+	}
+	for _, e := range f.NotificationEndpoints {
+		if err := createWithID(svc, e.ID, func() error {
+			return svc.CreateNotificationEndpoint(ctx, e)
+		}); err != nil {
+			t.Fatalf("failed to populate notification endpoints: %v", err)
+		}
+	}
+	return svc
+}
@@ -0,0 +1,401 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var (
+	notificationRuleBucket = []byte("notificationrulesv1")
+)
+
+var _ influxdb.NotificationRuleService = (*Service)(nil)
+
+func (s *Service) initializeNotificationRules(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(notificationRuleBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindNotificationRuleByID retrieves a notification rule by id.
+func (s *Service) FindNotificationRuleByID(ctx context.Context, id influxdb.ID) (*influxdb.NotificationRule, error) {
+	var r *influxdb.NotificationRule
+	err := s.kv.View(ctx, func(tx Tx) error {
+		rule, err := s.findNotificationRuleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		r = rule
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindNotificationRuleByID,
+			Err: err,
+		}
+	}
+
+	return r, nil
+}
+
+func (s *Service) findNotificationRuleByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.NotificationRule, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(notificationRuleBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrNotificationRuleNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := &influxdb.NotificationRule{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return r, nil
+}
+
+func filterNotificationRulesFn(filter influxdb.NotificationRuleFilter) func(r *influxdb.NotificationRule) bool {
+	return func(r *influxdb.NotificationRule) bool {
+		return (filter.ID == nil || *filter.ID == r.ID) &&
+			(filter.OrgID == nil || *filter.OrgID == r.OrgID) &&
+			(filter.Name == nil || *filter.Name == r.Name) &&
+			(filter.EndpointID == nil || *filter.EndpointID == r.EndpointID) &&
+			(filter.CheckID == nil || *filter.CheckID == r.CheckID)
+	}
+}
+
+// FindNotificationRules returns the notification rules that match the filter, ordered by ID.
+func (s *Service) FindNotificationRules(ctx context.Context, filter influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRule, int, error) {
+	rs := []*influxdb.NotificationRule{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		rules, err := s.findNotificationRules(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		rs = rules
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindNotificationRules,
+			Err: err,
+		}
+	}
+
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(len(rs))
+		rs = rs[lo:hi]
+	}
+
+	return rs, len(rs), nil
+}
+
+func (s *Service) findNotificationRules(ctx context.Context, tx Tx, filter influxdb.NotificationRuleFilter) ([]*influxdb.NotificationRule, error) {
+	if filter.ID != nil {
+		r, err := s.findNotificationRuleByID(ctx, tx, *filter.ID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.NotificationRule{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filterNotificationRulesFn(filter)(r) {
+			return []*influxdb.NotificationRule{}, nil
+		}
+		return []*influxdb.NotificationRule{r}, nil
+	}
+
+	rs := []*influxdb.NotificationRule{}
+	filterFn := filterNotificationRulesFn(filter)
+	err := s.forEachNotificationRule(ctx, tx, func(r *influxdb.NotificationRule) bool {
+		if filterFn(r) {
+			rs = append(rs, r)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+func (s *Service) forEachNotificationRule(ctx context.Context, tx Tx, fn func(*influxdb.NotificationRule) bool) error {
+	b, err := tx.Bucket(notificationRuleBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		r := &influxdb.NotificationRule{}
+		if err := json.Unmarshal(v, r); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if !fn(r) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// CreateNotificationRule creates a notification rule and makes the user on the context its owner.
+func (s *Service) CreateNotificationRule(ctx context.Context, r *influxdb.NotificationRule) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		r.SetDefaults()
+		if err := r.Valid(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, r.OrgID); err != nil {
+			return err
+		}
+
+		if err := s.validNotificationRuleReferences(ctx, tx, r); err != nil {
+			return err
+		}
+
+		if err := s.uniqueNotificationRuleName(ctx, tx, r); err != nil {
+			return err
+		}
+
+		r.ID = s.IDGenerator.ID()
+		r.CreatedAt = s.Now()
+		r.UpdatedAt = s.Now()
+		if err := s.putNotificationRule(ctx, tx, r); err != nil {
+			return err
+		}
+
+		if err := s.addResourceOwner(ctx, tx, influxdb.NotificationRulesResourceType, r.ID); err != nil {
+			s.Logger.Info("failed to make user owner of notification rule", zap.Error(err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateNotificationRule,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// validNotificationRuleReferences returns an error if the notification
+// endpoint or the check of r is not one of its organization.
+func (s *Service) validNotificationRuleReferences(ctx context.Context, tx Tx, r *influxdb.NotificationRule) error {
+	e, err := s.findNotificationEndpointByID(ctx, tx, r.EndpointID)
+	if err != nil {
+		return err
+	}
+	if e.OrgID != r.OrgID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "notification endpoint belongs to another organization",
+		}
+	}
+
+	if !r.CheckID.Valid() {
+		return nil
+	}
+	c, err := s.findCheckByID(ctx, tx, r.CheckID)
+	if err != nil {
+		return err
+	}
+	if c.OrgID != r.OrgID {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "check belongs to another organization",
+		}
+	}
+	return nil
+}
+
+// uniqueNotificationRuleName returns an error if another notification rule
+// of the organization of r has its name.
+func (s *Service) uniqueNotificationRuleName(ctx context.Context, tx Tx, r *influxdb.NotificationRule) error {
+	rs, err := s.findNotificationRules(ctx, tx, influxdb.NotificationRuleFilter{OrgID: &r.OrgID, Name: &r.Name})
+	if err != nil {
+		return err
+	}
+	for _, other := range rs {
+		if other.ID != r.ID {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("notification rule with name %s already exists", r.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateNotificationRule updates a notification rule with the changeset.
+func (s *Service) UpdateNotificationRule(ctx context.Context, id influxdb.ID, upd influxdb.NotificationRuleUpdate) (*influxdb.NotificationRule, error) {
+	var r *influxdb.NotificationRule
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := upd.Valid(); err != nil {
+			return err
+		}
+
+		rule, err := s.findNotificationRuleByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(rule)
+		if err := s.uniqueNotificationRuleName(ctx, tx, rule); err != nil {
+			return err
+		}
+
+		rule.UpdatedAt = s.Now()
+		if err := s.putNotificationRule(ctx, tx, rule); err != nil {
+			return err
+		}
+		r = rule
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateNotificationRule,
+			Err: err,
+		}
+	}
+
+	return r, nil
+}
+
+// ReplaceNotificationRule replaces the definition of a notification rule.
+func (s *Service) ReplaceNotificationRule(ctx context.Context, r *influxdb.NotificationRule) (*influxdb.NotificationRule, error) {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		current, err := s.findNotificationRuleByID(ctx, tx, r.ID)
+		if err != nil {
+			return err
+		}
+
+		r.OrgID = current.OrgID
+		r.CreatedAt = current.CreatedAt
+		if r.Status == "" {
+			r.Status = current.Status
+		}
+		r.SetDefaults()
+		if err := r.Valid(); err != nil {
+			return err
+		}
+		if err := s.validNotificationRuleReferences(ctx, tx, r); err != nil {
+			return err
+		}
+		if err := s.uniqueNotificationRuleName(ctx, tx, r); err != nil {
+			return err
+		}
+
+		r.UpdatedAt = s.Now()
+		return s.putNotificationRule(ctx, tx, r)
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpReplaceNotificationRule,
+			Err: err,
+		}
+	}
+
+	return r, nil
+}
+
+func (s *Service) putNotificationRule(ctx context.Context, tx Tx, r *influxdb.NotificationRule) error {
+	v, err := json.Marshal(r)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := r.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(notificationRuleBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, v)
+}
+
+// DeleteNotificationRule deletes a notification rule and the mappings of
+// users to it. The notifications it sent stay in the history.
+func (s *Service) DeleteNotificationRule(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteNotificationRule(ctx, tx, id)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteNotificationRule,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteNotificationRule(ctx context.Context, tx Tx, id influxdb.ID) error {
+	if _, err := s.findNotificationRuleByID(ctx, tx, id); err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(notificationRuleBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(encodedID); err != nil {
+		return err
+	}
+
+	return s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   id,
+		ResourceType: influxdb.NotificationRulesResourceType,
+	})
+}
@@ -0,0 +1,106 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltNotificationRuleService(t *testing.T) {
+	influxdbtesting.NotificationRuleService(initBoltNotificationRuleService, t)
+}
+
+func TestInmemNotificationRuleService(t *testing.T) {
+	influxdbtesting.NotificationRuleService(initInmemNotificationRuleService, t)
+}
+
+func initBoltNotificationRuleService(f influxdbtesting.NotificationRuleFields, t *testing.T) (influxdb.NotificationRuleService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initNotificationRuleService(s, f, t), closeBolt
+}
+
+func initInmemNotificationRuleService(f influxdbtesting.NotificationRuleFields, t *testing.T) (influxdb.NotificationRuleService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initNotificationRuleService(s, f, t), closeInmem
+}
+
+func initNotificationRuleService(s kv.Store, f influxdbtesting.NotificationRuleFields, t *testing.T) influxdb.NotificationRuleService {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, e := range f.NotificationEndpoints {
+		if err := createWithID(svc, e.ID, func() error {
+			return svc.CreateNotificationEndpoint(ctx, e)
+		}); err != nil {
+			t.Fatalf("failed to populate notification endpoints: %v", err)
+		}
+	}
+	for _, c := range f.Checks {
+		if err := createWithID(svc, c.ID, func() error {
+			return svc.CreateCheck(ctx, c)
+		}); err != nil {
+			t.Fatalf("failed to populate checks: %v", err)
+		}
+	}
+	for _, r := range f.NotificationRules {
+		if err := createWithID(svc, r.ID, func() error {
+			return svc.CreateNotificationRule(ctx, r)
+		}); err != nil {
+			t.Fatalf("failed to populate notification rules: %v", err)
+		}
+	}
+	return svc
+}
+
+func TestBoltSentNotificationService(t *testing.T) {
+	influxdbtesting.SentNotificationService(initBoltSentNotificationService, t)
+}
+
+func TestInmemSentNotificationService(t *testing.T) {
+	influxdbtesting.SentNotificationService(initInmemSentNotificationService, t)
+}
+
+func initBoltSentNotificationService(f influxdbtesting.SentNotificationFields, t *testing.T) (influxdb.SentNotificationService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSentNotificationService(s, f, t), closeBolt
+}
+
+func initInmemSentNotificationService(f influxdbtesting.SentNotificationFields, t *testing.T) (influxdb.SentNotificationService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSentNotificationService(s, f, t), closeInmem
+}
+
+func initSentNotificationService(s kv.Store, f influxdbtesting.SentNotificationFields, t *testing.T) influxdb.SentNotificationService {
+	svc := kv.NewService(s, kv.ServiceConfig{SentNotificationLimit: f.Limit})
+	if f.IDGenerator != nil {
+		svc.IDGenerator = f.IDGenerator
+	}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+	for _, n := range f.SentNotifications {
+		if err := createWithID(svc, n.ID, func() error {
+			return svc.AddSentNotification(ctx, n)
+		}); err != nil {
+			t.Fatalf("failed to populate sent notifications: %v", err)
+		}
+	}
+	return svc
+}
@@ -96,22 +96,37 @@ func (s *Service) setOnboarded(ctx context.Context, tx Tx) error {
 
 // Generate OnboardingResults from onboarding request,
 // update db so this request will be disabled for the second run.
+// A second run with the same request gets the same results.
 func (s *Service) Generate(ctx context.Context, req *influxdb.OnboardingRequest) (*influxdb.OnboardingResults, error) {
 	isOnboarding, err := s.IsOnboarding(ctx)
 	if err != nil {
 		return nil, err
 	}
 	if !isOnboarding {
-		return nil, &influxdb.Error{
-			Code: influxdb.EConflict,
-			Msg:  "onboarding has already been completed",
-		}
+		return s.findOnboardingResults(ctx, req)
 	}
 
 	if err := req.Valid(); err != nil {
 		return nil, err
 	}
 
+	return s.onboard(ctx, req, false)
+}
+
+// OnboardUser creates an additional user along with an organization it owns,
+// a bucket and a token with all access to the organization.
+func (s *Service) OnboardUser(ctx context.Context, req *influxdb.OnboardingRequest) (*influxdb.OnboardingResults, error) {
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	return s.onboard(ctx, req, true)
+}
+
+// onboard creates the user, organization, bucket and token of the request
+// in a single transaction. The token of the first user has operator
+// permissions, and creating it completes onboarding.
+func (s *Service) onboard(ctx context.Context, req *influxdb.OnboardingRequest, additional bool) (*influxdb.OnboardingResults, error) {
 	u := &influxdb.User{Name: req.User}
 	o := &influxdb.Organization{Name: req.Org}
 	bucket := &influxdb.Bucket{
@@ -128,7 +143,7 @@ func (s *Service) Generate(ctx context.Context, req *influxdb.OnboardingRequest)
 		Token:       req.Token,
 	}
 
-	err = s.kv.Update(ctx, func(tx Tx) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
 		if err := s.createUser(ctx, tx, u); err != nil {
 			return err
 		}
@@ -154,10 +169,16 @@ func (s *Service) Generate(ctx context.Context, req *influxdb.OnboardingRequest)
 
 		auth.UserID = u.ID
 		auth.OrgID = o.ID
+		if additional {
+			auth.Permissions = influxdb.OnboardUserPermissions(o.ID, u.ID)
+		}
 		if err := s.createAuthorization(ctx, tx, auth); err != nil {
 			return err
 		}
 
+		if additional {
+			return nil
+		}
 		return s.putOnboardingStatus(ctx, tx, true)
 	})
 	if err != nil {
@@ -171,3 +192,88 @@ func (s *Service) Generate(ctx context.Context, req *influxdb.OnboardingRequest)
 		Auth:   auth,
 	}, nil
 }
+
+// findOnboardingResults returns the results of the completed onboarding if
+// the request matches them: the password of the user, which must not have
+// expired, the organization, the bucket and its retention period. The token
+// is the one of the request if set, else the first active token of the user
+// for the organization. Requests that do not match are a conflict, whatever
+// differs.
+func (s *Service) findOnboardingResults(ctx context.Context, req *influxdb.OnboardingRequest) (*influxdb.OnboardingResults, error) {
+	conflict := &influxdb.Error{
+		Code: influxdb.EConflict,
+		Msg:  influxdb.ErrOnboardingCompleted,
+	}
+	if req == nil || req.Valid() != nil {
+		return nil, conflict
+	}
+
+	// The password is compared as when signing in, so that a password that
+	// has expired does not return the results either.
+	err := s.ComparePassword(ctx, req.User, req.Password)
+	if influxdb.ErrorCode(err) == influxdb.EInternal {
+		return nil, err
+	}
+	if err != nil {
+		return nil, conflict
+	}
+
+	var results *influxdb.OnboardingResults
+	err = s.kv.View(ctx, func(tx Tx) error {
+		u, err := s.findUserByName(ctx, tx, req.User)
+		if err != nil {
+			return err
+		}
+		o, err := s.findOrganizationByName(ctx, tx, req.Org)
+		if err != nil {
+			return err
+		}
+		b, err := s.findBucketByName(ctx, tx, o.ID, req.Bucket)
+		if err != nil {
+			return err
+		}
+		if b.RetentionPeriod != time.Duration(req.RetentionPeriod)*time.Hour {
+			return conflict
+		}
+
+		var auth *influxdb.Authorization
+		if req.Token != "" {
+			a, err := s.findAuthorizationByToken(ctx, tx, req.Token)
+			if err != nil {
+				return err
+			}
+			if a.UserID == u.ID && a.OrgID == o.ID && a.IsActive() {
+				auth = a
+			}
+		} else {
+			as, err := s.findAuthorizations(ctx, tx, influxdb.AuthorizationFilter{UserID: &u.ID, OrgID: &o.ID})
+			if err != nil {
+				return err
+			}
+			for _, a := range as {
+				if a.IsActive() {
+					auth = a
+					break
+				}
+			}
+		}
+		if auth == nil {
+			return conflict
+		}
+
+		results = &influxdb.OnboardingResults{
+			User:   u,
+			Org:    o,
+			Bucket: b,
+			Auth:   auth,
+		}
+		return nil
+	})
+	if influxdb.ErrorCode(err) == influxdb.EInternal {
+		return nil, err
+	}
+	if err != nil {
+		return nil, conflict
+	}
+	return results, nil
+}
@@ -3,9 +3,11 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
 	influxdbtesting "github.com/influxdata/influxdb/testing"
 )
 
@@ -17,6 +19,79 @@ func TestInmemOnboardingService(t *testing.T) {
 	influxdbtesting.Generate(initInmemOnboardingService, t)
 }
 
+func TestBoltOnboardingService_Regenerate(t *testing.T) {
+	influxdbtesting.Regenerate(initBoltOnboardingService, t)
+}
+
+func TestInmemOnboardingService_Regenerate(t *testing.T) {
+	influxdbtesting.Regenerate(initInmemOnboardingService, t)
+}
+
+func TestBoltOnboardingService_RegenerateExpiredPassword(t *testing.T) {
+	s, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new bolt kv store: %v", err)
+	}
+	defer closeStore()
+
+	testRegenerateExpiredPassword(s, t)
+}
+
+func TestInmemOnboardingService_RegenerateExpiredPassword(t *testing.T) {
+	s, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new inmem kv store: %v", err)
+	}
+	defer closeStore()
+
+	testRegenerateExpiredPassword(s, t)
+}
+
+// testRegenerateExpiredPassword checks that the results of the onboarding are
+// not returned again once the password of the user has expired.
+func testRegenerateExpiredPassword(s kv.Store, t *testing.T) {
+	policy := influxdb.DefaultPasswordPolicy
+	policy.MaxAge = 24 * time.Hour
+	svc := kv.NewService(s, kv.ServiceConfig{
+		SessionLength:  influxdb.DefaultSessionLength,
+		PasswordPolicy: &policy,
+	})
+	onboarded := time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: onboarded}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("unable to initialize kv store: %v", err)
+	}
+
+	req := influxdb.OnboardingRequest{
+		User:            "admin",
+		Org:             "org1",
+		Bucket:          "bucket1",
+		Password:        "password1",
+		RetentionPeriod: 24 * 7,
+	}
+	if _, err := svc.Generate(ctx, &req); err != nil {
+		t.Fatalf("failed to onboard: %v", err)
+	}
+	if _, err := svc.Generate(ctx, &req); err != nil {
+		t.Fatalf("expected the results to be returned again before the password expires, got %v", err)
+	}
+
+	svc.TimeGenerator = mock.TimeGenerator{FakeValue: onboarded.Add(policy.MaxAge + time.Minute)}
+	if _, err := svc.Generate(ctx, &req); influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected the results not to be returned with an expired password, got %v", err)
+	}
+}
+
+func TestBoltOnboardingService_OnboardUser(t *testing.T) {
+	influxdbtesting.OnboardUser(initBoltOnboardingService, t)
+}
+
+func TestInmemOnboardingService_OnboardUser(t *testing.T) {
+	influxdbtesting.OnboardUser(initInmemOnboardingService, t)
+}
+
 func initBoltOnboardingService(f influxdbtesting.OnboardingFields, t *testing.T) (influxdb.OnboardingService, func()) {
 	s, closeStore, err := NewTestBoltStore()
 	if err != nil {
@@ -217,6 +217,12 @@ func (s *Service) FindOrganizations(ctx context.Context, filter influxdb.Organiz
 		}
 	}
 
+	if len(opt) > 0 {
+		influxdb.SortOrganizations(opt[0], os)
+		lo, hi := opt[0].Page(len(os))
+		os = os[lo:hi]
+	}
+
 	return os, len(os), nil
 }
 
@@ -247,6 +253,19 @@ func (s *Service) createOrganization(ctx context.Context, tx Tx, o *influxdb.Org
 	if err := s.validOrganizationName(ctx, tx, o); err != nil {
 		return err
 	}
+	if _, err := influxdb.LoadTimezone(o.Timezone); err != nil {
+		return err
+	}
+	if o.BucketDefaults != nil {
+		if err := o.BucketDefaults.Valid(); err != nil {
+			return err
+		}
+	}
+	if o.NamePolicy != nil {
+		if err := o.NamePolicy.Valid(); err != nil {
+			return err
+		}
+	}
 
 	o.ID = s.IDGenerator.ID()
 	o.CreatedAt = s.Now()
@@ -404,6 +423,33 @@ func (s *Service) updateOrganization(ctx context.Context, tx Tx, id influxdb.ID,
 		o.Description = *upd.Description
 	}
 
+	if upd.Timezone != nil {
+		if _, err := influxdb.LoadTimezone(*upd.Timezone); err != nil {
+			return nil, err
+		}
+		o.Timezone = *upd.Timezone
+	}
+
+	if upd.BucketDefaults != nil {
+		if err := upd.BucketDefaults.Valid(); err != nil {
+			return nil, err
+		}
+		o.BucketDefaults = nil
+		if d := *upd.BucketDefaults; !d.IsZero() {
+			o.BucketDefaults = &d
+		}
+	}
+
+	if upd.NamePolicy != nil {
+		o.NamePolicy = nil
+		if p := *upd.NamePolicy; !p.IsZero() {
+			if err := p.Valid(); err != nil {
+				return nil, err
+			}
+			o.NamePolicy = &p
+		}
+	}
+
 	o.UpdatedAt = s.Now()
 
 	if err := s.appendOrganizationEventToLog(ctx, tx, o.ID, organizationUpdatedEvent); err != nil {
@@ -632,6 +678,48 @@ func (s *Service) FindResourceOrganizationID(ctx context.Context, rt influxdb.Re
 			return influxdb.InvalidID(), err
 		}
 		return r.OrgID, nil
+	case influxdb.TeamsResourceType:
+		r, err := s.FindTeamByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
+	case influxdb.NotificationEndpointsResourceType:
+		r, err := s.FindNotificationEndpointByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
+	case influxdb.ChecksResourceType:
+		r, err := s.FindCheckByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
+	case influxdb.NotificationRulesResourceType:
+		r, err := s.FindNotificationRuleByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
+	case influxdb.SilencesResourceType:
+		r, err := s.FindSilenceByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
+	case influxdb.AnnotationsResourceType:
+		r, err := s.FindAnnotationByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
+	case influxdb.StacksResourceType:
+		r, err := s.FindStackByID(ctx, id)
+		if err != nil {
+			return influxdb.InvalidID(), err
+		}
+		return r.OrgID, nil
 	}
 
 	return influxdb.InvalidID(), &influxdb.Error{
@@ -0,0 +1,45 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltFindSorted(t *testing.T) {
+	influxdbtesting.FindSorted(initBoltFindSortedService, t)
+}
+
+func TestInmemFindSorted(t *testing.T) {
+	influxdbtesting.FindSorted(initInmemFindSortedService, t)
+}
+
+func initBoltFindSortedService(f influxdbtesting.FindSortedFields, t *testing.T) (influxdbtesting.FindSortedServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initFindSortedService(s, f, t), closeBolt
+}
+
+func initInmemFindSortedService(f influxdbtesting.FindSortedFields, t *testing.T) (influxdbtesting.FindSortedServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initFindSortedService(s, f, t), closeInmem
+}
+
+func initFindSortedService(s kv.Store, f influxdbtesting.FindSortedFields, t *testing.T) influxdbtesting.FindSortedServices {
+	svc := initTestService(s, nil, nil, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets: %v", err)
+		}
+	}
+	return svc
+}
@@ -0,0 +1,60 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltPasswordPolicyService(t *testing.T) {
+	influxdbtesting.PasswordPolicyService(initBoltPasswordPolicyService, t)
+}
+
+func TestInmemPasswordPolicyService(t *testing.T) {
+	influxdbtesting.PasswordPolicyService(initInmemPasswordPolicyService, t)
+}
+
+func initBoltPasswordPolicyService(f influxdbtesting.PasswordPolicyFields, t *testing.T) (influxdb.PasswordsService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initPasswordPolicyService(s, f, t), closeBolt
+}
+
+func initInmemPasswordPolicyService(f influxdbtesting.PasswordPolicyFields, t *testing.T) (influxdb.PasswordsService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initPasswordPolicyService(s, f, t), closeInmem
+}
+
+func initPasswordPolicyService(s kv.Store, f influxdbtesting.PasswordPolicyFields, t *testing.T) influxdb.PasswordsService {
+	svc := kv.NewService(s, kv.ServiceConfig{PasswordPolicy: f.PasswordPolicy})
+	svc.TimeGenerator = f.TimeGenerator
+	if f.TimeGenerator == nil {
+		svc.TimeGenerator = influxdb.RealTimeGenerator{}
+	}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+	for _, u := range f.Users {
+		if err := svc.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users: %v", err)
+		}
+	}
+	for name, passwords := range f.Passwords {
+		for _, password := range passwords {
+			if err := svc.SetPassword(ctx, name, password); err != nil {
+				t.Fatalf("failed to populate passwords: %v", err)
+			}
+		}
+	}
+	return svc
+}
@@ -0,0 +1,216 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	passwordResetBucket = []byte("passwordresetsv1")
+	passwordResetIndex  = []byte("passwordresetindexv1")
+)
+
+var _ influxdb.PasswordResetService = (*Service)(nil)
+
+func (s *Service) initializePasswordResets(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(passwordResetBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(passwordResetIndex); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreatePasswordReset creates a password reset for the user with a new token,
+// replacing any password reset the user had.
+func (s *Service) CreatePasswordReset(ctx context.Context, userID influxdb.ID) (*influxdb.PasswordReset, error) {
+	var r *influxdb.PasswordReset
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findUserByID(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		if err := s.deleteUserPasswordReset(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		token, err := s.TokenGenerator.Token()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+
+		now := s.Now()
+		r = &influxdb.PasswordReset{
+			UserID:    userID,
+			Token:     token,
+			ExpiresAt: now.Add(influxdb.DefaultPasswordResetTTL),
+			CreatedAt: now,
+		}
+		return s.putPasswordReset(ctx, tx, r)
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpCreatePasswordReset,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// ResetPassword sets the password of the user of the password reset with the
+// token, which must satisfy the password policy, and deletes the password reset.
+func (s *Service) ResetPassword(ctx context.Context, token string, password string) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		r, err := s.findPasswordReset(ctx, tx, token)
+		if err != nil {
+			return err
+		}
+		if r.Expired(s.Now()) {
+			return &influxdb.Error{
+				Code: influxdb.EForbidden,
+				Msg:  "password reset has expired",
+			}
+		}
+
+		u, err := s.findUserByID(ctx, tx, r.UserID)
+		if err != nil {
+			return err
+		}
+
+		// The password is set before the reset is deleted, so that a password
+		// the policy rejects leaves the reset usable on stores that do not roll back.
+		if err := s.setPassword(ctx, tx, u.Name, password); err != nil {
+			return err
+		}
+
+		return s.deletePasswordReset(ctx, tx, r)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpResetPassword,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) findPasswordReset(ctx context.Context, tx Tx, token string) (*influxdb.PasswordReset, error) {
+	b, err := tx.Bucket(passwordResetBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get([]byte(token))
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrPasswordResetNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := &influxdb.PasswordReset{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return r, nil
+}
+
+func (s *Service) putPasswordReset(ctx context.Context, tx Tx, r *influxdb.PasswordReset) error {
+	v, err := json.Marshal(r)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := r.UserID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(passwordResetIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Put(encodedID, []byte(r.Token)); err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(passwordResetBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(r.Token), v)
+}
+
+func (s *Service) deletePasswordReset(ctx context.Context, tx Tx, r *influxdb.PasswordReset) error {
+	encodedID, err := r.UserID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(passwordResetIndex)
+	if err != nil {
+		return err
+	}
+	if err := idx.Delete(encodedID); err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(passwordResetBucket)
+	if err != nil {
+		return err
+	}
+	return b.Delete([]byte(r.Token))
+}
+
+// deleteUserPasswordReset deletes the password reset of the user, if any.
+func (s *Service) deleteUserPasswordReset(ctx context.Context, tx Tx, userID influxdb.ID) error {
+	encodedID, err := userID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	idx, err := tx.Bucket(passwordResetIndex)
+	if err != nil {
+		return err
+	}
+	token, err := idx.Get(encodedID)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	r, err := s.findPasswordReset(ctx, tx, string(token))
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return idx.Delete(encodedID)
+	}
+	if err != nil {
+		return err
+	}
+	return s.deletePasswordReset(ctx, tx, r)
+}
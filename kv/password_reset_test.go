@@ -0,0 +1,63 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltPasswordResetService(t *testing.T) {
+	influxdbtesting.PasswordResetService(initBoltPasswordResetService, t)
+}
+
+func TestInmemPasswordResetService(t *testing.T) {
+	influxdbtesting.PasswordResetService(initInmemPasswordResetService, t)
+}
+
+func initBoltPasswordResetService(f influxdbtesting.PasswordResetFields, t *testing.T) (influxdbtesting.PasswordResetServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initPasswordResetService(s, f, t), closeBolt
+}
+
+func initInmemPasswordResetService(f influxdbtesting.PasswordResetFields, t *testing.T) (influxdbtesting.PasswordResetServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initPasswordResetService(s, f, t), closeInmem
+}
+
+func initPasswordResetService(s kv.Store, f influxdbtesting.PasswordResetFields, t *testing.T) influxdbtesting.PasswordResetServices {
+	svc := initTestService(s, nil, f.TimeGenerator, nil, t)
+	tokens := svc.TokenGenerator
+	if f.TokenGenerator != nil {
+		tokens = f.TokenGenerator
+	}
+
+	ctx := context.Background()
+	for _, u := range f.Users {
+		if err := svc.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users: %v", err)
+		}
+	}
+	for name, password := range f.Passwords {
+		if err := svc.SetPassword(ctx, name, password); err != nil {
+			t.Fatalf("failed to populate passwords: %v", err)
+		}
+	}
+	for _, r := range f.PasswordResets {
+		// Password resets are created with the token they are populated with.
+		svc.TokenGenerator = mock.NewTokenGenerator(r.Token, nil)
+		if _, err := svc.CreatePasswordReset(ctx, r.UserID); err != nil {
+			t.Fatalf("failed to populate password resets: %v", err)
+		}
+	}
+	svc.TokenGenerator = tokens
+	return svc
+}
@@ -2,14 +2,17 @@ package kv
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/influxdata/influxdb"
 )
 
-// MinPasswordLength is the shortest password we allow into the system.
+// MinPasswordLength is the shortest password we allow into the system
+// under the default password policy.
 const MinPasswordLength = 8
 
 var (
@@ -60,18 +63,39 @@ func InternalPasswordHashError(err error) *influxdb.Error {
 }
 
 var (
-	userpasswordBucket = []byte("userspasswordv1")
+	userpasswordBucket     = []byte("userspasswordv1")
+	userpasswordMetaBucket = []byte("userspasswordmetav1")
 )
 
+// passwordMeta is when the password of a user was set, and the hashes of the
+// passwords the user had before it, most recent first.
+type passwordMeta struct {
+	SetAt   time.Time `json:"setAt"`
+	History [][]byte  `json:"history,omitempty"`
+}
+
 var _ influxdb.PasswordsService = (*Service)(nil)
 
 func (s *Service) initializePasswords(ctx context.Context, tx Tx) error {
-	_, err := tx.Bucket(userpasswordBucket)
-	return err
+	if _, err := tx.Bucket(userpasswordBucket); err != nil {
+		return err
+	}
+	if _, err := tx.Bucket(userpasswordMetaBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// passwordPolicy returns the password policy of the service.
+func (s *Service) passwordPolicy() influxdb.PasswordPolicy {
+	if s.Config.PasswordPolicy != nil {
+		return *s.Config.PasswordPolicy
+	}
+	return influxdb.DefaultPasswordPolicy
 }
 
 // CompareAndSetPassword checks the password and if they match
-// updates to the new password.
+// updates to the new password. An expired password may still be changed.
 func (s *Service) CompareAndSetPassword(ctx context.Context, name string, old string, new string) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
 		if err := s.comparePassword(ctx, tx, name, old); err != nil {
@@ -89,16 +113,21 @@ func (s *Service) SetPassword(ctx context.Context, name string, password string)
 }
 
 // ComparePassword checks if the password matches the password recorded.
-// Passwords that do not match return errors.
+// Passwords that do not match return errors, and passwords that match but are
+// older than the maximum age of the password policy return a forbidden error.
 func (s *Service) ComparePassword(ctx context.Context, name string, password string) error {
 	return s.kv.View(ctx, func(tx Tx) error {
-		return s.comparePassword(ctx, tx, name, password)
+		if err := s.comparePassword(ctx, tx, name, password); err != nil {
+			return err
+		}
+		return s.checkPasswordAge(ctx, tx, name)
 	})
 }
 
 func (s *Service) setPassword(ctx context.Context, tx Tx, name string, password string) error {
-	if len(password) < MinPasswordLength {
-		return EShortPassword
+	policy := s.passwordPolicy()
+	if err := policy.Check(password); err != nil {
+		return err
 	}
 
 	u, err := s.findUserByName(ctx, tx, name)
@@ -121,6 +150,21 @@ func (s *Service) setPassword(ctx context.Context, tx Tx, name string, password
 		hasher = &Bcrypt{}
 	}
 
+	var history [][]byte
+	if policy.HistorySize > 0 {
+		if history, err = s.passwordHistory(ctx, tx, encodedID, policy.HistorySize); err != nil {
+			return err
+		}
+		for _, h := range history {
+			if hasher.CompareHashAndPassword(h, []byte(password)) == nil {
+				return influxdb.NewPasswordPolicyError(influxdb.EInvalid, influxdb.PasswordViolation{
+					Rule:    influxdb.PasswordRuleHistory,
+					Message: fmt.Sprintf("passwords must not be one of the last %d passwords", policy.HistorySize),
+				})
+			}
+		}
+	}
+
 	hash, err := hasher.GenerateFromPassword([]byte(password), DefaultCost)
 	if err != nil {
 		return InternalPasswordHashError(err)
@@ -129,6 +173,117 @@ func (s *Service) setPassword(ctx context.Context, tx Tx, name string, password
 	if err := b.Put(encodedID, hash); err != nil {
 		return UnavailablePasswordServiceError(err)
 	}
+
+	// The history keeps the passwords before the new one that may not be set again.
+	meta := &passwordMeta{SetAt: s.Now()}
+	if n := policy.HistorySize - 1; n > 0 {
+		if len(history) > n {
+			history = history[:n]
+		}
+		meta.History = history
+	}
+	return s.putPasswordMeta(ctx, tx, encodedID, meta)
+}
+
+// passwordHistory returns the hashes of the most recent passwords of the user,
+// the current password first.
+func (s *Service) passwordHistory(ctx context.Context, tx Tx, encodedID []byte, size int) ([][]byte, error) {
+	meta, err := s.findPasswordMeta(ctx, tx, encodedID)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(userpasswordBucket)
+	if err != nil {
+		return nil, UnavailablePasswordServiceError(err)
+	}
+	current, err := b.Get(encodedID)
+	if err != nil && !IsNotFound(err) {
+		return nil, UnavailablePasswordServiceError(err)
+	}
+
+	history := meta.History
+	if current != nil {
+		history = append([][]byte{append([]byte(nil), current...)}, history...)
+	}
+	if len(history) > size {
+		history = history[:size]
+	}
+	return history, nil
+}
+
+// checkPasswordAge returns a forbidden error if the password of the user is
+// older than the maximum age of the password policy. Passwords set before
+// their age was recorded never expire.
+func (s *Service) checkPasswordAge(ctx context.Context, tx Tx, name string) error {
+	maxAge := s.passwordPolicy().MaxAge
+	if maxAge <= 0 {
+		return nil
+	}
+
+	u, err := s.findUserByName(ctx, tx, name)
+	if err != nil {
+		return EIncorrectPassword
+	}
+
+	encodedID, err := u.ID.Encode()
+	if err != nil {
+		return CorruptUserIDError(name, err)
+	}
+
+	meta, err := s.findPasswordMeta(ctx, tx, encodedID)
+	if err != nil {
+		return err
+	}
+	if meta.SetAt.IsZero() || s.Now().Sub(meta.SetAt) <= maxAge {
+		return nil
+	}
+	return influxdb.NewPasswordPolicyError(influxdb.EForbidden, influxdb.PasswordViolation{
+		Rule:    influxdb.PasswordRuleMaxAge,
+		Message: fmt.Sprintf("password has expired; passwords must be changed every %s", maxAge),
+	})
+}
+
+func (s *Service) findPasswordMeta(ctx context.Context, tx Tx, encodedID []byte) (*passwordMeta, error) {
+	b, err := tx.Bucket(userpasswordMetaBucket)
+	if err != nil {
+		return nil, UnavailablePasswordServiceError(err)
+	}
+
+	meta := &passwordMeta{}
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return meta, nil
+	}
+	if err != nil {
+		return nil, UnavailablePasswordServiceError(err)
+	}
+
+	if err := json.Unmarshal(v, meta); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return meta, nil
+}
+
+func (s *Service) putPasswordMeta(ctx context.Context, tx Tx, encodedID []byte, meta *passwordMeta) error {
+	v, err := json.Marshal(meta)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(userpasswordMetaBucket)
+	if err != nil {
+		return UnavailablePasswordServiceError(err)
+	}
+	if err := b.Put(encodedID, v); err != nil {
+		return UnavailablePasswordServiceError(err)
+	}
 	return nil
 }
 
@@ -0,0 +1,220 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	queryHistoryBucket = []byte("queryhistoryv1")
+)
+
+var _ influxdb.QueryHistoryService = (*Service)(nil)
+
+func (s *Service) initializeQueryHistory(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(queryHistoryBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// queryHistoryLimit returns the number of entries kept per organization.
+func (s *Service) queryHistoryLimit() int {
+	if s.Config.QueryHistoryLimit > 0 {
+		return s.Config.QueryHistoryLimit
+	}
+	return influxdb.DefaultQueryHistoryLimit
+}
+
+// queryHistoryKey returns the key of an entry. The entries of an organization
+// share a prefix and are ordered by the time they completed.
+func queryHistoryKey(e *influxdb.QueryHistoryEntry) ([]byte, error) {
+	prefix, err := queryHistoryPrefix(e.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	encID, err := e.ID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	k := make([]byte, 0, len(prefix)+8+len(encID))
+	k = append(k, prefix...)
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(e.CompletedAt.UnixNano()))
+	k = append(k, ts[:]...)
+	return append(k, encID...), nil
+}
+
+func queryHistoryPrefix(orgID influxdb.ID) ([]byte, error) {
+	encOrgID, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return encOrgID, nil
+}
+
+// AddQueryHistoryEntry adds a completed query to the history of its organization
+// and removes the oldest entries beyond the retention limit.
+func (s *Service) AddQueryHistoryEntry(ctx context.Context, e *influxdb.QueryHistoryEntry) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.addQueryHistoryEntry(ctx, tx, e)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpAddQueryHistoryEntry,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) addQueryHistoryEntry(ctx context.Context, tx Tx, e *influxdb.QueryHistoryEntry) error {
+	e.ID = s.IDGenerator.ID()
+	if e.CompletedAt.IsZero() {
+		e.CompletedAt = s.Now()
+	}
+
+	k, err := queryHistoryKey(e)
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(e)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(queryHistoryBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(k, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return s.trimQueryHistory(ctx, b, e.OrganizationID)
+}
+
+// trimQueryHistory removes the oldest entries of the organization beyond the retention limit.
+func (s *Service) trimQueryHistory(ctx context.Context, b Bucket, orgID influxdb.ID) error {
+	prefix, err := queryHistoryPrefix(orgID)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	for k, _ := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
+		keys = append(keys, k)
+	}
+
+	// The keys are ordered by the time the queries completed, oldest first.
+	for n := len(keys) - s.queryHistoryLimit(); n > 0; n-- {
+		if err := b.Delete(keys[n-1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindQueryHistory returns the entries that match the filter, most recent first,
+// or slowest first if the options sort by duration.
+func (s *Service) FindQueryHistory(ctx context.Context, filter influxdb.QueryHistoryFilter, opt ...influxdb.FindOptions) ([]*influxdb.QueryHistoryEntry, error) {
+	var es []*influxdb.QueryHistoryEntry
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		es, err = s.findQueryHistory(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindQueryHistory,
+			Err: err,
+		}
+	}
+
+	if len(opt) > 0 && opt[0].SortBy == "duration" {
+		sort.SliceStable(es, func(i, j int) bool {
+			return es[i].Duration > es[j].Duration
+		})
+	}
+
+	if len(opt) > 0 {
+		es = pageQueryHistory(es, opt[0])
+	}
+	return es, nil
+}
+
+func (s *Service) findQueryHistory(ctx context.Context, tx Tx, filter influxdb.QueryHistoryFilter) ([]*influxdb.QueryHistoryEntry, error) {
+	b, err := tx.Bucket(queryHistoryBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefix []byte
+	if filter.OrganizationID != nil {
+		if prefix, err = queryHistoryPrefix(*filter.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	es := []*influxdb.QueryHistoryEntry{}
+	for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		e := &influxdb.QueryHistoryEntry{}
+		if err := json.Unmarshal(v, e); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if filter.UserID != nil && e.UserID != *filter.UserID {
+			continue
+		}
+		if filter.Outcome != nil && e.Outcome != *filter.Outcome {
+			continue
+		}
+		if e.Duration < filter.MinDuration {
+			continue
+		}
+		es = append(es, e)
+	}
+
+	// The entries of every organization are ordered oldest first.
+	sort.SliceStable(es, func(i, j int) bool {
+		return es[i].CompletedAt.After(es[j].CompletedAt)
+	})
+	return es, nil
+}
+
+func pageQueryHistory(es []*influxdb.QueryHistoryEntry, opt influxdb.FindOptions) []*influxdb.QueryHistoryEntry {
+	if opt.Offset >= len(es) {
+		return []*influxdb.QueryHistoryEntry{}
+	}
+	es = es[opt.Offset:]
+	if opt.Limit > 0 && opt.Limit < len(es) {
+		es = es[:opt.Limit]
+	}
+	return es
+}
@@ -0,0 +1,49 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltQueryHistoryService(t *testing.T) {
+	influxdbtesting.QueryHistoryService(initBoltQueryHistoryService, t)
+}
+
+func TestInmemQueryHistoryService(t *testing.T) {
+	influxdbtesting.QueryHistoryService(initInmemQueryHistoryService, t)
+}
+
+func initBoltQueryHistoryService(f influxdbtesting.QueryHistoryFields, t *testing.T) (influxdb.QueryHistoryService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initQueryHistoryService(s, f, t), closeBolt
+}
+
+func initInmemQueryHistoryService(f influxdbtesting.QueryHistoryFields, t *testing.T) (influxdb.QueryHistoryService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initQueryHistoryService(s, f, t), closeInmem
+}
+
+func initQueryHistoryService(s kv.Store, f influxdbtesting.QueryHistoryFields, t *testing.T) influxdb.QueryHistoryService {
+	svc := initTestService(s, f.IDGenerator, nil, nil, t)
+	svc.Config.QueryHistoryLimit = f.Limit
+
+	ctx := context.Background()
+	for _, e := range f.QueryHistory {
+		if err := createWithID(svc, e.ID, func() error {
+			return svc.AddQueryHistoryEntry(ctx, e)
+		}); err != nil {
+			t.Fatalf("failed to populate query history: %v", err)
+		}
+	}
+	return svc
+}
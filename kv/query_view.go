@@ -0,0 +1,238 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	queryViewBucket = []byte("queryviewsv1")
+)
+
+var _ influxdb.QueryViewService = (*Service)(nil)
+
+func (s *Service) initializeQueryViews(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(queryViewBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindQueryViewByID retrieves a query view by id.
+func (s *Service) FindQueryViewByID(ctx context.Context, id influxdb.ID) (*influxdb.QueryView, error) {
+	var qv *influxdb.QueryView
+	err := s.kv.View(ctx, func(tx Tx) error {
+		v, err := s.findQueryViewByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		qv = v
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindQueryViewByID,
+			Err: err,
+		}
+	}
+
+	return qv, nil
+}
+
+func (s *Service) findQueryViewByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.QueryView, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(queryViewBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrQueryViewNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeQueryView(v)
+}
+
+// FindQueryViews returns all query views that match the filter.
+func (s *Service) FindQueryViews(ctx context.Context, filter influxdb.QueryViewFilter) ([]*influxdb.QueryView, error) {
+	var qvs []*influxdb.QueryView
+	err := s.kv.View(ctx, func(tx Tx) error {
+		vs, err := s.findQueryViews(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		qvs = vs
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindQueryViews,
+			Err: err,
+		}
+	}
+
+	return qvs, nil
+}
+
+func (s *Service) findQueryViews(ctx context.Context, tx Tx, filter influxdb.QueryViewFilter) ([]*influxdb.QueryView, error) {
+	b, err := tx.Bucket(queryViewBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	qvs := []*influxdb.QueryView{}
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		qv, err := decodeQueryView(v)
+		if err != nil {
+			return nil, err
+		}
+		if filter.OrganizationID != nil && qv.OrganizationID != *filter.OrganizationID {
+			continue
+		}
+		if filter.Name != nil && qv.Name != *filter.Name {
+			continue
+		}
+		qvs = append(qvs, qv)
+	}
+	return qvs, nil
+}
+
+// CreateQueryView creates a new query view and assigns it an ID. The name of
+// a view must be unique within its organization.
+func (s *Service) CreateQueryView(ctx context.Context, qv *influxdb.QueryView) error {
+	if err := qv.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateQueryView,
+			Err: err,
+		}
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, qv.OrganizationID); err != nil {
+			return err
+		}
+
+		vs, err := s.findQueryViews(ctx, tx, influxdb.QueryViewFilter{
+			OrganizationID: &qv.OrganizationID,
+			Name:           &qv.Name,
+		})
+		if err != nil {
+			return err
+		}
+		if len(vs) > 0 {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("query view with name %s already exists", qv.Name),
+			}
+		}
+
+		qv.ID = s.IDGenerator.ID()
+		qv.CreatedAt = s.Now()
+		qv.ExpiresAt = qv.CreatedAt.Add(qv.TTL())
+		return s.putQueryView(ctx, tx, qv)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateQueryView,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) putQueryView(ctx context.Context, tx Tx, qv *influxdb.QueryView) error {
+	v, err := json.Marshal(qv)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encID, err := qv.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(queryViewBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encID, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// DeleteQueryView removes a query view by its ID.
+func (s *Service) DeleteQueryView(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findQueryViewByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		encID, err := id.Encode()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(queryViewBucket)
+		if err != nil {
+			return err
+		}
+
+		return b.Delete(encID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteQueryView,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func decodeQueryView(v []byte) (*influxdb.QueryView, error) {
+	qv := &influxdb.QueryView{}
+	if err := json.Unmarshal(v, qv); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return qv, nil
+}
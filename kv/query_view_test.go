@@ -0,0 +1,48 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltQueryViewService(t *testing.T) {
+	influxdbtesting.QueryViewService(initBoltQueryViewService, t)
+}
+
+func TestInmemQueryViewService(t *testing.T) {
+	influxdbtesting.QueryViewService(initInmemQueryViewService, t)
+}
+
+func initBoltQueryViewService(f influxdbtesting.QueryViewFields, t *testing.T) (influxdb.QueryViewService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initQueryViewService(s, f, t), closeBolt
+}
+
+func initInmemQueryViewService(f influxdbtesting.QueryViewFields, t *testing.T) (influxdb.QueryViewService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initQueryViewService(s, f, t), closeInmem
+}
+
+func initQueryViewService(s kv.Store, f influxdbtesting.QueryViewFields, t *testing.T) influxdb.QueryViewService {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, qv := range f.QueryViews {
+		if err := createWithID(svc, qv.ID, func() error {
+			return svc.CreateQueryView(ctx, qv)
+		}); err != nil {
+			t.Fatalf("failed to populate query views: %v", err)
+		}
+	}
+	return svc
+}
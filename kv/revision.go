@@ -0,0 +1,435 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+var (
+	revisionBucket = []byte("revisionsv1")
+)
+
+// maxRevisions is the number of revisions kept for a resource; the oldest
+// revisions are dropped beyond it.
+const maxRevisions = 100
+
+var _ influxdb.RevisionService = (*Service)(nil)
+
+func (s *Service) initializeRevisions(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(revisionBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+func revisionPrefix(typ influxdb.ResourceType, id influxdb.ID) ([]byte, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	prefix := append([]byte(typ+"/"), encodedID...)
+	return append(prefix, '/'), nil
+}
+
+func revisionKey(typ influxdb.ResourceType, id influxdb.ID, version int) ([]byte, error) {
+	prefix, err := revisionPrefix(typ, id)
+	if err != nil {
+		return nil, err
+	}
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(version))
+	return append(prefix, v...), nil
+}
+
+// putRevision records content as the latest revision of a resource, unless
+// it is the content prev describes, which is the state of the resource before
+// the change. When the resource has no revisions yet, prev is kept as the
+// first one.
+func (s *Service) putRevision(ctx context.Context, tx Tx, prev *influxdb.Revision, content string) error {
+	if content == prev.Content {
+		return nil
+	}
+
+	rs, err := s.findRevisions(ctx, tx, prev.ResourceType, prev.ResourceID)
+	if err != nil {
+		return err
+	}
+	if len(rs) == 0 {
+		prev.Version = 1
+		prev.Diff = diff.LineDiff("", prev.Content)
+		if err := s.writeRevision(ctx, tx, prev); err != nil {
+			return err
+		}
+		rs = append(rs, prev)
+	}
+
+	r := &influxdb.Revision{
+		ResourceType: prev.ResourceType,
+		ResourceID:   prev.ResourceID,
+		OrgID:        prev.OrgID,
+		Version:      rs[len(rs)-1].Version + 1,
+		Time:         s.Now(),
+		Diff:         diff.LineDiff(prev.Content, content),
+		Content:      content,
+	}
+	// Like the operation log, the user is recorded if there is one on context.
+	if a, err := icontext.GetAuthorizer(ctx); err == nil {
+		r.UserID = a.GetUserID()
+	}
+	if err := s.writeRevision(ctx, tx, r); err != nil {
+		return err
+	}
+
+	for len(rs)+1 > maxRevisions {
+		if err := s.deleteRevision(ctx, tx, rs[0]); err != nil {
+			return err
+		}
+		rs = rs[1:]
+	}
+	return nil
+}
+
+func (s *Service) writeRevision(ctx context.Context, tx Tx, r *influxdb.Revision) error {
+	v, err := json.Marshal(r)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	key, err := revisionKey(r.ResourceType, r.ResourceID, r.Version)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(revisionBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(key, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteRevision(ctx context.Context, tx Tx, r *influxdb.Revision) error {
+	key, err := revisionKey(r.ResourceType, r.ResourceID, r.Version)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(revisionBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(key); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// deleteRevisions deletes every revision of a resource.
+func (s *Service) deleteRevisions(ctx context.Context, tx Tx, typ influxdb.ResourceType, id influxdb.ID) error {
+	rs, err := s.findRevisions(ctx, tx, typ, id)
+	if err != nil {
+		return err
+	}
+	for _, r := range rs {
+		if err := s.deleteRevision(ctx, tx, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindRevisions returns the revisions of a resource and the total count of its
+// revisions, latest first.
+func (s *Service) FindRevisions(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, opt ...influxdb.FindOptions) ([]*influxdb.Revision, int, error) {
+	if err := influxdb.ValidRevisionResourceType(typ); err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindRevisions,
+			Err: err,
+		}
+	}
+
+	var rs []*influxdb.Revision
+	err := s.kv.View(ctx, func(tx Tx) error {
+		revs, err := s.findRevisions(ctx, tx, typ, id)
+		if err != nil {
+			return err
+		}
+		rs = revs
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindRevisions,
+			Err: err,
+		}
+	}
+
+	for i, j := 0, len(rs)-1; i < j; i, j = i+1, j-1 {
+		rs[i], rs[j] = rs[j], rs[i]
+	}
+
+	n := len(rs)
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(n)
+		rs = rs[lo:hi]
+	}
+	return rs, n, nil
+}
+
+// findRevisions returns the revisions of a resource, oldest first.
+func (s *Service) findRevisions(ctx context.Context, tx Tx, typ influxdb.ResourceType, id influxdb.ID) ([]*influxdb.Revision, error) {
+	prefix, err := revisionPrefix(typ, id)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(revisionBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	rs := []*influxdb.Revision{}
+	for k, v := cur.Seek(prefix); bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		r := &influxdb.Revision{}
+		if err := json.Unmarshal(v, r); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// FindRevision returns a single revision of a resource by its version.
+func (s *Service) FindRevision(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, version int) (*influxdb.Revision, error) {
+	var r *influxdb.Revision
+	err := s.kv.View(ctx, func(tx Tx) error {
+		rev, err := s.findRevision(ctx, tx, typ, id, version)
+		if err != nil {
+			return err
+		}
+		r = rev
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindRevision,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+func (s *Service) findRevision(ctx context.Context, tx Tx, typ influxdb.ResourceType, id influxdb.ID, version int) (*influxdb.Revision, error) {
+	if err := influxdb.ValidRevisionResourceType(typ); err != nil {
+		return nil, err
+	}
+
+	key, err := revisionKey(typ, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(revisionBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrRevisionNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := &influxdb.Revision{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return r, nil
+}
+
+// RestoreRevision puts a resource back as it was at a revision, which records
+// a new revision of the resource.
+func (s *Service) RestoreRevision(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID, version int) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		r, err := s.findRevision(ctx, tx, typ, id, version)
+		if err != nil {
+			return err
+		}
+
+		switch r.ResourceType {
+		case influxdb.DashboardsResourceType:
+			return s.restoreDashboardRevision(ctx, tx, r)
+		case influxdb.TasksResourceType:
+			_, err := s.updateTask(ctx, tx, r.ResourceID, influxdb.TaskUpdate{Flux: &r.Content})
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpRestoreRevision,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// dashboardRevision is the content of a dashboard revision.
+type dashboardRevision struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Cells       []*dashboardRevisionCell `json:"cells"`
+}
+
+type dashboardRevisionCell struct {
+	influxdb.Cell
+	View *influxdb.View `json:"view,omitempty"`
+}
+
+// dashboardRevisionOf returns the revision describing the current state of d.
+func (s *Service) dashboardRevisionOf(ctx context.Context, tx Tx, d *influxdb.Dashboard) (*influxdb.Revision, error) {
+	dr := &dashboardRevision{
+		Name:        d.Name,
+		Description: d.Description,
+		Cells:       make([]*dashboardRevisionCell, 0, len(d.Cells)),
+	}
+	for _, c := range d.Cells {
+		v, err := s.findDashboardCellView(ctx, tx, d.ID, c.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return nil, err
+		}
+		dr.Cells = append(dr.Cells, &dashboardRevisionCell{Cell: *c, View: v})
+	}
+
+	content, err := json.MarshalIndent(dr, "", "  ")
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return &influxdb.Revision{
+		ResourceType: influxdb.DashboardsResourceType,
+		ResourceID:   d.ID,
+		OrgID:        d.OrganizationID,
+		Time:         d.Meta.UpdatedAt,
+		Content:      string(content),
+	}, nil
+}
+
+// putDashboardRevision records the current state of d as its latest revision,
+// given prev, the revision describing its state before the change.
+func (s *Service) putDashboardRevision(ctx context.Context, tx Tx, prev *influxdb.Revision, d *influxdb.Dashboard) error {
+	r, err := s.dashboardRevisionOf(ctx, tx, d)
+	if err != nil {
+		return err
+	}
+	return s.putRevision(ctx, tx, prev, r.Content)
+}
+
+func (s *Service) restoreDashboardRevision(ctx context.Context, tx Tx, r *influxdb.Revision) error {
+	var dr dashboardRevision
+	if err := json.Unmarshal([]byte(r.Content), &dr); err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	d, err := s.findDashboardByID(ctx, tx, r.ResourceID)
+	if err != nil {
+		return err
+	}
+	prev, err := s.dashboardRevisionOf(ctx, tx, d)
+	if err != nil {
+		return err
+	}
+
+	if dr.Name != d.Name {
+		if err := s.uniqueDashboardName(ctx, tx, d.OrganizationID, d.ID, dr.Name); err != nil {
+			return err
+		}
+	}
+
+	kept := make(map[influxdb.ID]bool, len(dr.Cells))
+	cells := make([]*influxdb.Cell, 0, len(dr.Cells))
+	for _, c := range dr.Cells {
+		kept[c.ID] = true
+		cell := c.Cell
+		cells = append(cells, &cell)
+		if err := s.createCellView(ctx, tx, d.ID, c.ID, c.View); err != nil {
+			return err
+		}
+	}
+	for _, c := range d.Cells {
+		if kept[c.ID] {
+			continue
+		}
+		if err := s.deleteDashboardCellView(ctx, tx, d.ID, c.ID); err != nil {
+			return err
+		}
+	}
+
+	d.Name = dr.Name
+	d.Description = dr.Description
+	d.Cells = cells
+	if err := s.appendDashboardEventToLog(ctx, tx, d.ID, dashboardRevisionRestoredEvent); err != nil {
+		return err
+	}
+	if err := s.putDashboardWithMeta(ctx, tx, d); err != nil {
+		return err
+	}
+	return s.putDashboardRevision(ctx, tx, prev, d)
+}
+
+// taskRevisionOf returns the revision describing the current Flux of t.
+func taskRevisionOf(t *influxdb.Task) *influxdb.Revision {
+	updatedAt := t.UpdatedAt
+	if updatedAt == "" {
+		updatedAt = t.CreatedAt
+	}
+	// The time is only missing for tasks created before it was recorded.
+	at, _ := time.Parse(time.RFC3339, updatedAt)
+
+	return &influxdb.Revision{
+		ResourceType: influxdb.TasksResourceType,
+		ResourceID:   t.ID,
+		OrgID:        t.OrganizationID,
+		Time:         at,
+		Content:      t.Flux,
+	}
+}
@@ -0,0 +1,81 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltDashboardRevisionService(t *testing.T) {
+	influxdbtesting.DashboardRevisionService(initBoltDashboardRevisionService, t)
+}
+
+func TestInmemDashboardRevisionService(t *testing.T) {
+	influxdbtesting.DashboardRevisionService(initInmemDashboardRevisionService, t)
+}
+
+func TestBoltTaskRevisionService(t *testing.T) {
+	influxdbtesting.TaskRevisionService(initBoltTaskRevisionService, t)
+}
+
+func TestInmemTaskRevisionService(t *testing.T) {
+	influxdbtesting.TaskRevisionService(initInmemTaskRevisionService, t)
+}
+
+func initBoltDashboardRevisionService(f influxdbtesting.RevisionFields, t *testing.T) (influxdbtesting.DashboardRevisionServices, func()) {
+	return initBoltRevisionService(f, t)
+}
+
+func initInmemDashboardRevisionService(f influxdbtesting.RevisionFields, t *testing.T) (influxdbtesting.DashboardRevisionServices, func()) {
+	return initInmemRevisionService(f, t)
+}
+
+func initBoltTaskRevisionService(f influxdbtesting.RevisionFields, t *testing.T) (influxdbtesting.TaskRevisionServices, func()) {
+	return initBoltRevisionService(f, t)
+}
+
+func initInmemTaskRevisionService(f influxdbtesting.RevisionFields, t *testing.T) (influxdbtesting.TaskRevisionServices, func()) {
+	return initInmemRevisionService(f, t)
+}
+
+func initBoltRevisionService(f influxdbtesting.RevisionFields, t *testing.T) (*kv.Service, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initRevisionService(s, f, t), closeBolt
+}
+
+func initInmemRevisionService(f influxdbtesting.RevisionFields, t *testing.T) (*kv.Service, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initRevisionService(s, f, t), closeInmem
+}
+
+func initRevisionService(s kv.Store, f influxdbtesting.RevisionFields, t *testing.T) *kv.Service {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, u := range f.Users {
+		if err := svc.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users: %v", err)
+		}
+	}
+	for _, a := range f.Authorizations {
+		if err := svc.CreateAuthorization(ctx, a); err != nil {
+			t.Fatalf("failed to populate authorizations: %v", err)
+		}
+	}
+	for _, d := range f.Dashboards {
+		if err := createWithID(svc, d.ID, func() error {
+			return svc.CreateDashboard(ctx, d)
+		}); err != nil {
+			t.Fatalf("failed to populate dashboards: %v", err)
+		}
+	}
+	return svc
+}
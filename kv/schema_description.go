@@ -0,0 +1,175 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	schemaDescriptionBucket = []byte("schemadescriptionsv1")
+)
+
+var _ influxdb.SchemaDescriptionService = (*Service)(nil)
+
+func (s *Service) initializeSchemaDescriptions(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(schemaDescriptionBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindSchemaDescriptions retrieves the schema descriptions of a bucket.
+func (s *Service) FindSchemaDescriptions(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDescriptions, error) {
+	var d *influxdb.SchemaDescriptions
+	err := s.kv.View(ctx, func(tx Tx) error {
+		sd, err := s.findSchemaDescriptions(ctx, tx, bucketID)
+		if err != nil {
+			return err
+		}
+		d = sd
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindSchemaDescriptions,
+			Err: err,
+		}
+	}
+
+	return d, nil
+}
+
+func (s *Service) findSchemaDescriptions(ctx context.Context, tx Tx, bucketID influxdb.ID) (*influxdb.SchemaDescriptions, error) {
+	key, err := bucketID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(schemaDescriptionBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrSchemaDescriptionsNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d := &influxdb.SchemaDescriptions{}
+	if err := json.Unmarshal(v, d); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return d, nil
+}
+
+// PutSchemaDescriptions replaces the schema descriptions of a bucket, and
+// assigns them the organization of the bucket.
+func (s *Service) PutSchemaDescriptions(ctx context.Context, d *influxdb.SchemaDescriptions) error {
+	if err := d.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpPutSchemaDescriptions,
+			Err: err,
+		}
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := s.findBucketByID(ctx, tx, d.BucketID)
+		if err != nil {
+			return err
+		}
+		if d.OrgID.Valid() && d.OrgID != b.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "orgID does not match the organization of the bucket",
+			}
+		}
+		d.OrgID = b.OrgID
+
+		d.CreatedAt = s.Now()
+		existing, err := s.findSchemaDescriptions(ctx, tx, d.BucketID)
+		if err == nil {
+			d.CreatedAt = existing.CreatedAt
+		} else if influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+		d.UpdatedAt = s.Now()
+
+		return s.putSchemaDescriptions(ctx, tx, d)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpPutSchemaDescriptions,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) putSchemaDescriptions(ctx context.Context, tx Tx, d *influxdb.SchemaDescriptions) error {
+	v, err := json.Marshal(d)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	key, err := d.BucketID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(schemaDescriptionBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(key, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// deleteSchemaDescriptions removes the schema descriptions of a deleted bucket.
+func (s *Service) deleteSchemaDescriptions(ctx context.Context, tx Tx, bucketID influxdb.ID) error {
+	key, err := bucketID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(schemaDescriptionBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Delete(key); err != nil && !IsNotFound(err) {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltSchemaDescriptionService(t *testing.T) {
+	influxdbtesting.SchemaDescriptionService(initBoltSchemaDescriptionService, t)
+}
+
+func TestInmemSchemaDescriptionService(t *testing.T) {
+	influxdbtesting.SchemaDescriptionService(initInmemSchemaDescriptionService, t)
+}
+
+func initBoltSchemaDescriptionService(f influxdbtesting.SchemaDescriptionFields, t *testing.T) (influxdbtesting.SchemaDescriptionServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSchemaDescriptionService(s, f, t), closeBolt
+}
+
+func initInmemSchemaDescriptionService(f influxdbtesting.SchemaDescriptionFields, t *testing.T) (influxdbtesting.SchemaDescriptionServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSchemaDescriptionService(s, f, t), closeInmem
+}
+
+func initSchemaDescriptionService(s kv.Store, f influxdbtesting.SchemaDescriptionFields, t *testing.T) influxdbtesting.SchemaDescriptionServices {
+	svc := initTestService(s, nil, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets: %v", err)
+		}
+	}
+	for _, d := range f.SchemaDescriptions {
+		if err := svc.PutSchemaDescriptions(ctx, d); err != nil {
+			t.Fatalf("failed to populate schema descriptions: %v", err)
+		}
+	}
+	return svc
+}
@@ -0,0 +1,149 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	scraperStatusBucket = []byte("scraperstatusv1")
+)
+
+var _ influxdb.ScraperTargetStatusService = (*Service)(nil)
+
+func (s *Service) initializeScraperTargetStatuses(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(scraperStatusBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PutTargetStatus records the outcome of the last scrape of a target.
+func (s *Service) PutTargetStatus(ctx context.Context, st *influxdb.ScraperTargetStatus) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		target, err := s.findTargetByID(ctx, tx, st.TargetID)
+		if err != nil {
+			return err
+		}
+		st.OrgID = target.OrgID
+
+		current, err := s.findTargetStatus(ctx, tx, st.TargetID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+
+		st.LastSuccess = nil
+		st.ConsecutiveFailures = 0
+		if st.LastError == "" {
+			lastSuccess := st.LastScrape
+			st.LastSuccess = &lastSuccess
+		} else {
+			st.ConsecutiveFailures = 1
+			if current != nil {
+				st.LastSuccess = current.LastSuccess
+				st.ConsecutiveFailures = current.ConsecutiveFailures + 1
+			}
+		}
+
+		return s.putTargetStatus(ctx, tx, st)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpPutTargetStatus,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindTargetStatus returns the outcome of the last scrape of a target.
+func (s *Service) FindTargetStatus(ctx context.Context, targetID influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+	var st *influxdb.ScraperTargetStatus
+	err := s.kv.View(ctx, func(tx Tx) error {
+		status, err := s.findTargetStatus(ctx, tx, targetID)
+		if err != nil {
+			return err
+		}
+		st = status
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindTargetStatus,
+			Err: err,
+		}
+	}
+	return st, nil
+}
+
+func (s *Service) findTargetStatus(ctx context.Context, tx Tx, targetID influxdb.ID) (*influxdb.ScraperTargetStatus, error) {
+	encodedID, err := targetID.Encode()
+	if err != nil {
+		return nil, ErrInvalidScraperID
+	}
+
+	b, err := tx.Bucket(scraperStatusBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrScraperTargetStatusNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	st := &influxdb.ScraperTargetStatus{}
+	if err := json.Unmarshal(v, st); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return st, nil
+}
+
+func (s *Service) putTargetStatus(ctx context.Context, tx Tx, st *influxdb.ScraperTargetStatus) error {
+	v, err := json.Marshal(st)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := st.TargetID.Encode()
+	if err != nil {
+		return ErrInvalidScraperID
+	}
+
+	b, err := tx.Bucket(scraperStatusBucket)
+	if err != nil {
+		return err
+	}
+	return b.Put(encodedID, v)
+}
+
+// deleteTargetStatus forgets the outcome of the scrapes of a target.
+func (s *Service) deleteTargetStatus(ctx context.Context, tx Tx, targetID influxdb.ID) error {
+	encodedID, err := targetID.Encode()
+	if err != nil {
+		return ErrInvalidScraperID
+	}
+
+	b, err := tx.Bucket(scraperStatusBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(encodedID); err != nil && !IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltScraperTargetStatusService(t *testing.T) {
+	influxdbtesting.ScraperTargetStatusService(initBoltScraperTargetStatusService, t)
+}
+
+func TestInmemScraperTargetStatusService(t *testing.T) {
+	influxdbtesting.ScraperTargetStatusService(initInmemScraperTargetStatusService, t)
+}
+
+func initBoltScraperTargetStatusService(f influxdbtesting.ScraperTargetStatusFields, t *testing.T) (influxdbtesting.ScraperTargetStatusServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initScraperTargetStatusService(s, f, t), closeBolt
+}
+
+func initInmemScraperTargetStatusService(f influxdbtesting.ScraperTargetStatusFields, t *testing.T) (influxdbtesting.ScraperTargetStatusServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initScraperTargetStatusService(s, f, t), closeInmem
+}
+
+func initScraperTargetStatusService(s kv.Store, f influxdbtesting.ScraperTargetStatusFields, t *testing.T) influxdbtesting.ScraperTargetStatusServices {
+	svc := initTestService(s, nil, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, target := range f.Targets {
+		if err := svc.PutTarget(ctx, target); err != nil {
+			t.Fatalf("failed to populate scraper targets: %v", err)
+		}
+	}
+	for _, st := range f.TargetStatuses {
+		if err := svc.PutTargetStatus(ctx, st); err != nil {
+			t.Fatalf("failed to populate scraper target statuses: %v", err)
+		}
+	}
+	return svc
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/influxdata/influxdb"
 )
@@ -156,12 +157,6 @@ func (s *Service) listTargets(ctx context.Context, tx Tx, filter influxdb.Scrape
 
 // AddTarget add a new scraper target into storage.
 func (s *Service) AddTarget(ctx context.Context, target *influxdb.ScraperTarget, userID influxdb.ID) (err error) {
-	return s.kv.Update(ctx, func(tx Tx) error {
-		return s.addTarget(ctx, tx, target, userID)
-	})
-}
-
-func (s *Service) addTarget(ctx context.Context, tx Tx, target *influxdb.ScraperTarget, userID influxdb.ID) error {
 	if !target.OrgID.Valid() {
 		return ErrInvalidScrapersOrgID
 	}
@@ -170,7 +165,27 @@ func (s *Service) addTarget(ctx context.Context, tx Tx, target *influxdb.Scraper
 		return ErrInvalidScrapersBucketID
 	}
 
+	if err := target.Valid(); err != nil {
+		return err
+	}
+
 	target.ID = s.IDGenerator.ID()
+	target.BackfillSecretKeys()
+	creds := s.newCredentials()
+	if err := creds.stage(ctx, target.OrgID, target.SecretFields()); err != nil {
+		return creds.done(ctx, err)
+	}
+
+	err = s.kv.Update(ctx, func(tx Tx) error {
+		return s.addTarget(ctx, tx, creds, target, userID)
+	})
+	return creds.done(ctx, err)
+}
+
+func (s *Service) addTarget(ctx context.Context, tx Tx, creds *credentials, target *influxdb.ScraperTarget, userID influxdb.ID) error {
+	if err := creds.resolve(ctx, tx); err != nil {
+		return err
+	}
 	if err := s.putTarget(ctx, tx, target); err != nil {
 		return err
 	}
@@ -186,13 +201,15 @@ func (s *Service) addTarget(ctx context.Context, tx Tx, target *influxdb.Scraper
 
 // RemoveTarget removes a scraper target from the bucket.
 func (s *Service) RemoveTarget(ctx context.Context, id influxdb.ID) error {
-	return s.kv.Update(ctx, func(tx Tx) error {
-		return s.removeTarget(ctx, tx, id)
+	creds := s.newCredentials()
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.removeTarget(ctx, tx, creds, id)
 	})
+	return creds.done(ctx, err)
 }
 
-func (s *Service) removeTarget(ctx context.Context, tx Tx, id influxdb.ID) error {
-	_, pe := s.findTargetByID(ctx, tx, id)
+func (s *Service) removeTarget(ctx context.Context, tx Tx, creds *credentials, id influxdb.ID) error {
+	target, pe := s.findTargetByID(ctx, tx, id)
 	if pe != nil {
 		return pe
 	}
@@ -218,6 +235,14 @@ func (s *Service) removeTarget(ctx context.Context, tx Tx, id influxdb.ID) error
 		return InternalScraperServiceError(err)
 	}
 
+	if err := s.deleteTargetSecrets(ctx, tx, creds, target, nil); err != nil {
+		return err
+	}
+
+	if err := s.deleteTargetStatus(ctx, tx, id); err != nil {
+		return err
+	}
+
 	return s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
 		ResourceID:   id,
 		ResourceType: influxdb.ScraperResourceType,
@@ -226,22 +251,11 @@ func (s *Service) removeTarget(ctx context.Context, tx Tx, id influxdb.ID) error
 
 // UpdateTarget updates a scraper target.
 func (s *Service) UpdateTarget(ctx context.Context, update *influxdb.ScraperTarget, userID influxdb.ID) (*influxdb.ScraperTarget, error) {
-	var target *influxdb.ScraperTarget
-	err := s.kv.Update(ctx, func(tx Tx) error {
-		var err error
-		target, err = s.updateTarget(ctx, tx, update, userID)
-		return err
-	})
-
-	return target, err
-}
-
-func (s *Service) updateTarget(ctx context.Context, tx Tx, update *influxdb.ScraperTarget, userID influxdb.ID) (*influxdb.ScraperTarget, error) {
 	if !update.ID.Valid() {
 		return nil, ErrInvalidScraperID
 	}
 
-	target, err := s.findTargetByID(ctx, tx, update.ID)
+	target, err := s.GetTargetByID(ctx, update.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -253,8 +267,61 @@ func (s *Service) updateTarget(ctx context.Context, tx Tx, update *influxdb.Scra
 	if !update.OrgID.Valid() {
 		update.OrgID = target.OrgID
 	}
-	target = update
-	return target, s.putTarget(ctx, tx, target)
+
+	if err := update.Valid(); err != nil {
+		return nil, err
+	}
+	update.BackfillSecretKeys()
+	creds := s.newCredentials()
+	if err := creds.stage(ctx, update.OrgID, update.SecretFields()); err != nil {
+		return nil, creds.done(ctx, err)
+	}
+
+	err = s.kv.Update(ctx, func(tx Tx) error {
+		return s.updateTarget(ctx, tx, creds, update)
+	})
+	if err := creds.done(ctx, err); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+func (s *Service) updateTarget(ctx context.Context, tx Tx, creds *credentials, update *influxdb.ScraperTarget) error {
+	// The target may have changed since it was found before the
+	// transaction; the secrets it uses now are the ones to replace.
+	target, err := s.findTargetByID(ctx, tx, update.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := creds.resolve(ctx, tx); err != nil {
+		return err
+	}
+	if err := s.deleteTargetSecrets(ctx, tx, creds, target, update); err != nil {
+		return err
+	}
+	return s.putTarget(ctx, tx, update)
+}
+
+// deleteTargetSecrets deletes the secrets of the scraper target named after it
+// that keep is not using anymore. Secrets it references by key are left.
+func (s *Service) deleteTargetSecrets(ctx context.Context, tx Tx, creds *credentials, target, keep *influxdb.ScraperTarget) error {
+	kept := map[string]bool{}
+	if keep != nil {
+		for _, f := range keep.SecretFields() {
+			kept[f.Key] = true
+		}
+	}
+
+	for _, f := range target.SecretFields() {
+		if kept[f.Key] || !strings.HasPrefix(f.Key, target.ID.String()+"-") {
+			continue
+		}
+		if err := creds.delete(ctx, tx, target.OrgID, f.Key); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GetTargetByID retrieves a scraper target by id.
@@ -2,6 +2,7 @@ package kv_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/influxdata/influxdb"
@@ -17,6 +18,131 @@ func TestInmemScraperTargetStoreService(t *testing.T) {
 	influxdbtesting.ScraperService(initInmemTargetService, t)
 }
 
+func TestBoltScraperTargetSecrets(t *testing.T) {
+	influxdbtesting.ScraperTargetSecrets(initBoltTargetSecretService, t)
+}
+
+func TestInmemScraperTargetSecrets(t *testing.T) {
+	influxdbtesting.ScraperTargetSecrets(initInmemTargetSecretService, t)
+}
+
+func TestBoltScraperTargetSecrets_SecretService(t *testing.T) {
+	s, closeFn, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	testScraperTargetSecretService(s, t)
+}
+
+func TestInmemScraperTargetSecrets_SecretService(t *testing.T) {
+	s, closeFn, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeFn()
+
+	testScraperTargetSecretService(s, t)
+}
+
+// testScraperTargetSecretService checks that the credentials of scraper
+// targets are kept in the SecretService of the service when it has one, here
+// the secrets of another service.
+func testScraperTargetSecretService(st kv.Store, t *testing.T) {
+	secretStore, closeSecrets, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeSecrets()
+	secrets := initTestService(secretStore, nil, nil, nil, t)
+
+	orgID := influxdb.ID(1)
+	store := &failingStore{Store: st}
+	svc := initTestService(store, nil, nil, []*influxdb.Organization{{ID: orgID, Name: "theorg"}}, t)
+	svc.SecretService = secrets
+
+	ctx := context.Background()
+	token := "abc123"
+	target := &influxdb.ScraperTarget{
+		Name:       "node",
+		Type:       influxdb.PrometheusScraperType,
+		URL:        "https://node:9100/metrics",
+		OrgID:      orgID,
+		BucketID:   2,
+		AuthMethod: influxdb.HTTPAuthBearer,
+		Token:      &influxdb.SecretField{Value: &token},
+	}
+	if err := svc.AddTarget(ctx, target, 3); err != nil {
+		t.Fatalf("failed to add scraper target: %v", err)
+	}
+	key := target.Token.Key
+	if v, err := secrets.LoadSecret(ctx, orgID, key); err != nil || v != token {
+		t.Errorf("expected the token to be put in the secret service, got %q, %v", v, err)
+	}
+	if _, err := svc.LoadSecret(ctx, orgID, key); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the token not to be put in the secrets of the service, got %v", err)
+	}
+
+	if err := secrets.PutSecret(ctx, orgID, "shared", "xyz789"); err != nil {
+		t.Fatal(err)
+	}
+	shared := *target
+	shared.Name = "other"
+	shared.Token = &influxdb.SecretField{Key: "shared"}
+	if err := svc.AddTarget(ctx, &shared, 3); err != nil {
+		t.Errorf("expected a target referencing a secret of the secret service to be added, got %v", err)
+	}
+
+	// The credentials put for changes that fail are reverted.
+	store.fail = true
+	otherToken := "def456"
+	failed := *target
+	failed.Name = "failed"
+	failed.Token = &influxdb.SecretField{Value: &otherToken}
+	if err := svc.AddTarget(ctx, &failed, 3); err == nil {
+		t.Fatal("expected adding a target to fail")
+	}
+	if _, err := secrets.LoadSecret(ctx, orgID, failed.Token.Key); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the token of the target that was not added to be deleted, got %v", err)
+	}
+	updated := *target
+	updated.Token = &influxdb.SecretField{Value: &otherToken}
+	if _, err := svc.UpdateTarget(ctx, &updated, 3); err == nil {
+		t.Fatal("expected updating a target to fail")
+	}
+	if v, err := secrets.LoadSecret(ctx, orgID, key); err != nil || v != token {
+		t.Errorf("expected the token of the target that was not updated to be restored, got %q, %v", v, err)
+	}
+	if err := svc.RemoveTarget(ctx, target.ID); err == nil {
+		t.Fatal("expected removing a target to fail")
+	}
+	if v, err := secrets.LoadSecret(ctx, orgID, key); err != nil || v != token {
+		t.Errorf("expected the token of the target that was not removed to be kept, got %q, %v", v, err)
+	}
+	store.fail = false
+
+	if err := svc.RemoveTarget(ctx, target.ID); err != nil {
+		t.Fatalf("failed to remove scraper target: %v", err)
+	}
+	if _, err := secrets.LoadSecret(ctx, orgID, key); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the token to be deleted from the secret service, got %v", err)
+	}
+}
+
+// failingStore is a kv.Store whose updates fail while fail is set.
+type failingStore struct {
+	kv.Store
+	fail bool
+}
+
+func (s *failingStore) Update(ctx context.Context, fn func(kv.Tx) error) error {
+	if s.fail {
+		return errors.New("store is unavailable")
+	}
+	return s.Store.Update(ctx, fn)
+}
+
 func initBoltTargetService(f influxdbtesting.TargetFields, t *testing.T) (influxdb.ScraperTargetStoreService, string, func()) {
 	s, closeFn, err := NewTestBoltStore()
 	if err != nil {
@@ -43,7 +169,44 @@ func initInmemTargetService(f influxdbtesting.TargetFields, t *testing.T) (influ
 	}
 }
 
-func initScraperTargetStoreService(s kv.Store, f influxdbtesting.TargetFields, t *testing.T) (influxdb.ScraperTargetStoreService, string, func()) {
+func initBoltTargetSecretService(f influxdbtesting.ScraperTargetSecretFields, t *testing.T) (influxdbtesting.ScraperTargetSecretServices, func()) {
+	s, closeFn, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	return initScraperTargetSecretService(s, f, t), closeFn
+}
+
+func initInmemTargetSecretService(f influxdbtesting.ScraperTargetSecretFields, t *testing.T) (influxdbtesting.ScraperTargetSecretServices, func()) {
+	s, closeFn, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+
+	return initScraperTargetSecretService(s, f, t), closeFn
+}
+
+func initScraperTargetSecretService(s kv.Store, f influxdbtesting.ScraperTargetSecretFields, t *testing.T) *kv.Service {
+	svc := initTestService(s, f.IDGenerator, nil, f.Organizations, t)
+
+	ctx := context.Background()
+	for k, v := range f.Secrets {
+		if err := svc.PutSecret(ctx, f.Organizations[0].ID, k, v); err != nil {
+			t.Fatalf("failed to populate secrets: %v", err)
+		}
+	}
+	for _, target := range f.Targets {
+		if err := createWithID(svc, target.ID, func() error {
+			return svc.AddTarget(ctx, target, 1)
+		}); err != nil {
+			t.Fatalf("failed to populate targets: %v", err)
+		}
+	}
+	return svc
+}
+
+func initScraperTargetStoreService(s kv.Store, f influxdbtesting.TargetFields, t *testing.T) (*kv.Service, string, func()) {
 	svc := kv.NewService(s)
 	svc.IDGenerator = f.IDGenerator
 
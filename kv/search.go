@@ -0,0 +1,540 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	// searchIndexBucket is the inverted index of the words of the resources:
+	// its keys are word/type/id/field.
+	searchIndexBucket = []byte("searchindexv1")
+	// searchDocumentsBucket holds the indexed resources by type/id, so their
+	// words can be removed from the index when they change.
+	searchDocumentsBucket = []byte("searchdocumentsv1")
+)
+
+var _ influxdb.SearchService = (*Service)(nil)
+
+// Fields of resources that are indexed, and the weight of their matches.
+const (
+	searchFieldName        = "name"
+	searchFieldDescription = "description"
+)
+
+var searchFieldWeights = map[string]int{
+	searchFieldName:        2,
+	searchFieldDescription: 1,
+}
+
+// Scores of the matches of a term and a word.
+const (
+	searchMatchFuzzy  = 1
+	searchMatchPrefix = 2
+	searchMatchExact  = 3
+)
+
+// searchDocument is an indexed resource.
+type searchDocument struct {
+	Type        influxdb.ResourceType `json:"type"`
+	ID          influxdb.ID           `json:"id"`
+	OrgID       influxdb.ID           `json:"orgID"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+}
+
+func (d *searchDocument) fields() map[string]string {
+	return map[string]string{
+		searchFieldName:        d.Name,
+		searchFieldDescription: d.Description,
+	}
+}
+
+func (s *Service) initializeSearch(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(searchIndexBucket); err != nil {
+		return err
+	}
+	docs, err := tx.Bucket(searchDocumentsBucket)
+	if err != nil {
+		return err
+	}
+
+	// Resources created before there was an index are indexed once.
+	cur, err := docs.Cursor()
+	if err != nil {
+		return err
+	}
+	if k, _ := cur.First(); k != nil {
+		return nil
+	}
+	return s.reindexSearch(ctx, tx)
+}
+
+// reindexSearch indexes every resource that can be searched.
+func (s *Service) reindexSearch(ctx context.Context, tx Tx) error {
+	var ds []*searchDocument
+	err := s.forEachBucket(ctx, tx, false, func(b *influxdb.Bucket) bool {
+		ds = append(ds, bucketSearchDocument(b))
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.forEachDashboard(ctx, tx, false, func(d *influxdb.Dashboard) bool {
+		ds = append(ds, dashboardSearchDocument(d))
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	ls, err := s.findLabels(ctx, tx, influxdb.LabelFilter{})
+	if err != nil {
+		return err
+	}
+	for _, l := range ls {
+		ds = append(ds, labelSearchDocument(l))
+	}
+
+	tb, err := tx.Bucket(taskBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	cur, err := tb.Cursor()
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		t := &influxdb.Task{}
+		if err := json.Unmarshal(v, t); err != nil {
+			return influxdb.ErrInternalTaskServiceError(err)
+		}
+		ds = append(ds, taskSearchDocument(t))
+	}
+
+	tcs, _, err := s.findTelegrafConfigs(ctx, tx, influxdb.TelegrafConfigFilter{})
+	if err != nil {
+		return err
+	}
+	for _, tc := range tcs {
+		ds = append(ds, telegrafSearchDocument(tc))
+	}
+
+	for _, d := range ds {
+		if err := s.indexSearchDocument(ctx, tx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bucketSearchDocument(b *influxdb.Bucket) *searchDocument {
+	return &searchDocument{
+		Type:        influxdb.BucketsResourceType,
+		ID:          b.ID,
+		OrgID:       b.OrgID,
+		Name:        b.Name,
+		Description: b.Description,
+	}
+}
+
+func dashboardSearchDocument(d *influxdb.Dashboard) *searchDocument {
+	return &searchDocument{
+		Type:        influxdb.DashboardsResourceType,
+		ID:          d.ID,
+		OrgID:       d.OrganizationID,
+		Name:        d.Name,
+		Description: d.Description,
+	}
+}
+
+func labelSearchDocument(l *influxdb.Label) *searchDocument {
+	return &searchDocument{
+		Type:        influxdb.LabelsResourceType,
+		ID:          l.ID,
+		OrgID:       l.OrgID,
+		Name:        l.Name,
+		Description: l.Properties["description"],
+	}
+}
+
+func taskSearchDocument(t *influxdb.Task) *searchDocument {
+	return &searchDocument{
+		Type:        influxdb.TasksResourceType,
+		ID:          t.ID,
+		OrgID:       t.OrganizationID,
+		Name:        t.Name,
+		Description: t.Description,
+	}
+}
+
+func telegrafSearchDocument(tc *influxdb.TelegrafConfig) *searchDocument {
+	return &searchDocument{
+		Type:        influxdb.TelegrafsResourceType,
+		ID:          tc.ID,
+		OrgID:       tc.OrgID,
+		Name:        tc.Name,
+		Description: tc.Description,
+	}
+}
+
+func searchDocumentKey(rt influxdb.ResourceType, id influxdb.ID) ([]byte, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	k := make([]byte, 0, len(rt)+1+len(encodedID))
+	k = append(k, rt...)
+	k = append(k, '/')
+	return append(k, encodedID...), nil
+}
+
+func searchIndexKey(word string, docKey []byte, field string) []byte {
+	k := make([]byte, 0, len(word)+len(docKey)+len(field)+2)
+	k = append(k, word...)
+	k = append(k, '/')
+	k = append(k, docKey...)
+	k = append(k, '/')
+	return append(k, field...)
+}
+
+// decodeSearchIndexKey returns the word, document key and field of an index key.
+// Words have no slashes, and encoded IDs are of fixed length.
+func decodeSearchIndexKey(k []byte) (word string, docKey []byte, field string, ok bool) {
+	i := bytes.IndexByte(k, '/')
+	if i < 0 {
+		return "", nil, "", false
+	}
+	rest := k[i+1:]
+	j := bytes.IndexByte(rest, '/')
+	if j < 0 || len(rest) < j+1+influxdb.IDLength+1 {
+		return "", nil, "", false
+	}
+	docKey = rest[:j+1+influxdb.IDLength]
+	return string(k[:i]), docKey, string(rest[j+1+influxdb.IDLength+1:]), true
+}
+
+// indexSearchDocument replaces the words of the resource in the index.
+// Resources that do not belong to an organization cannot be read through
+// search and are left out of the index.
+func (s *Service) indexSearchDocument(ctx context.Context, tx Tx, d *searchDocument) error {
+	if err := s.unindexSearchDocument(ctx, tx, d.Type, d.ID); err != nil {
+		return err
+	}
+	if !d.OrgID.Valid() {
+		return nil
+	}
+
+	docKey, err := searchDocumentKey(d.Type, d.ID)
+	if err != nil {
+		return err
+	}
+
+	v, err := json.Marshal(d)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	docs, err := tx.Bucket(searchDocumentsBucket)
+	if err != nil {
+		return err
+	}
+	if err := docs.Put(docKey, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	idx, err := tx.Bucket(searchIndexBucket)
+	if err != nil {
+		return err
+	}
+	for field, text := range d.fields() {
+		for _, word := range influxdb.SearchTerms(text) {
+			if err := idx.Put(searchIndexKey(word, docKey, field), []byte{}); err != nil {
+				return &influxdb.Error{
+					Err: err,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// unindexSearchDocument removes the words of the resource from the index, if it was indexed.
+func (s *Service) unindexSearchDocument(ctx context.Context, tx Tx, rt influxdb.ResourceType, id influxdb.ID) error {
+	docKey, err := searchDocumentKey(rt, id)
+	if err != nil {
+		return err
+	}
+
+	docs, err := tx.Bucket(searchDocumentsBucket)
+	if err != nil {
+		return err
+	}
+
+	v, err := docs.Get(docKey)
+	if IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d := &searchDocument{}
+	if err := json.Unmarshal(v, d); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	idx, err := tx.Bucket(searchIndexBucket)
+	if err != nil {
+		return err
+	}
+	for field, text := range d.fields() {
+		for _, word := range influxdb.SearchTerms(text) {
+			if err := idx.Delete(searchIndexKey(word, docKey, field)); err != nil {
+				return &influxdb.Error{
+					Err: err,
+				}
+			}
+		}
+	}
+
+	if err := docs.Delete(docKey); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// Search returns the resources whose names or descriptions match every term
+// of the query, best matches first.
+func (s *Service) Search(ctx context.Context, filter influxdb.SearchFilter, opt ...influxdb.FindOptions) ([]*influxdb.SearchResult, error) {
+	if err := filter.Valid(); err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpSearch,
+			Err: err,
+		}
+	}
+
+	var rs []*influxdb.SearchResult
+	err := s.kv.View(ctx, func(tx Tx) error {
+		res, err := s.search(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		rs = res
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpSearch,
+			Err: err,
+		}
+	}
+
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(len(rs))
+		rs = rs[lo:hi]
+	}
+	return rs, nil
+}
+
+func (s *Service) search(ctx context.Context, tx Tx, filter influxdb.SearchFilter) ([]*influxdb.SearchResult, error) {
+	terms := influxdb.SearchTerms(filter.Query)
+
+	idx, err := tx.Bucket(searchIndexBucket)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := idx.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	// The best score of each term for each document. The index is scanned
+	// once, so that words with typos are matched too.
+	scores := map[string][]int{}
+	var matches []int
+	var lastWord string
+	for k, _ := cur.First(); k != nil; k, _ = cur.Next() {
+		word, docKey, field, ok := decodeSearchIndexKey(k)
+		if !ok {
+			continue
+		}
+		if matches == nil || word != lastWord {
+			lastWord = word
+			matches = make([]int, len(terms))
+			for i, t := range terms {
+				matches[i] = matchSearchTerm(t, word)
+			}
+		}
+
+		var ss []int
+		for i, m := range matches {
+			if m == 0 {
+				continue
+			}
+			if ss == nil {
+				if ss = scores[string(docKey)]; ss == nil {
+					ss = make([]int, len(terms))
+					scores[string(docKey)] = ss
+				}
+			}
+			if score := m * searchFieldWeights[field]; score > ss[i] {
+				ss[i] = score
+			}
+		}
+	}
+
+	docs, err := tx.Bucket(searchDocumentsBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	rs := []*influxdb.SearchResult{}
+	for docKey, ss := range scores {
+		score := 0
+		for _, sc := range ss {
+			if sc == 0 {
+				score = 0
+				break
+			}
+			score += sc
+		}
+		if score == 0 {
+			continue
+		}
+
+		v, err := docs.Get([]byte(docKey))
+		if err != nil {
+			return nil, err
+		}
+		d := &searchDocument{}
+		if err := json.Unmarshal(v, d); err != nil {
+			return nil, &influxdb.Error{
+				Err: err,
+			}
+		}
+
+		if filter.OrgID != nil && d.OrgID != *filter.OrgID {
+			continue
+		}
+		if len(filter.Types) > 0 && !containsResourceType(filter.Types, d.Type) {
+			continue
+		}
+
+		rs = append(rs, &influxdb.SearchResult{
+			Type:        d.Type,
+			ID:          d.ID,
+			OrgID:       d.OrgID,
+			Name:        d.Name,
+			Description: d.Description,
+			Score:       score,
+		})
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Score != rs[j].Score {
+			return rs[i].Score > rs[j].Score
+		}
+		if rs[i].Name != rs[j].Name {
+			return rs[i].Name < rs[j].Name
+		}
+		return rs[i].ID < rs[j].ID
+	})
+	return rs, nil
+}
+
+// matchSearchTerm returns the score of the match of a term and a word, or zero
+// if they do not match. Terms of four or more letters may have one typo, and
+// terms of eight or more letters two.
+func matchSearchTerm(term, word string) int {
+	switch {
+	case term == word:
+		return searchMatchExact
+	case strings.HasPrefix(word, term):
+		return searchMatchPrefix
+	}
+
+	n := len([]rune(term))
+	maxTypos := 0
+	switch {
+	case n >= 8:
+		maxTypos = 2
+	case n >= 4:
+		maxTypos = 1
+	}
+	if maxTypos > 0 && editDistance(term, word, maxTypos) <= maxTypos {
+		return searchMatchFuzzy
+	}
+	return 0
+}
+
+// editDistance returns the Levenshtein distance of a and b, or max+1 if it
+// is greater than max.
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > max || -d > max {
+		return max + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(ns ...int) int {
+	m := ns[0]
+	for _, n := range ns[1:] {
+		if n < m {
+			m = n
+		}
+	}
+	return m
+}
+
+func containsResourceType(rts []influxdb.ResourceType, rt influxdb.ResourceType) bool {
+	for _, t := range rts {
+		if t == rt {
+			return true
+		}
+	}
+	return false
+}
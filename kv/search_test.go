@@ -0,0 +1,68 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltSearchService(t *testing.T) {
+	influxdbtesting.SearchService(initBoltSearchService, t)
+}
+
+func TestInmemSearchService(t *testing.T) {
+	influxdbtesting.SearchService(initInmemSearchService, t)
+}
+
+func initBoltSearchService(f influxdbtesting.SearchFields, t *testing.T) (influxdbtesting.SearchServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSearchService(s, f, t), closeBolt
+}
+
+func initInmemSearchService(f influxdbtesting.SearchFields, t *testing.T) (influxdbtesting.SearchServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSearchService(s, f, t), closeInmem
+}
+
+func initSearchService(s kv.Store, f influxdbtesting.SearchFields, t *testing.T) influxdbtesting.SearchServices {
+	svc := initTestService(s, nil, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, b := range f.Buckets {
+		if err := createWithID(svc, b.ID, func() error {
+			return svc.CreateBucket(ctx, b)
+		}); err != nil {
+			t.Fatalf("failed to populate buckets: %v", err)
+		}
+	}
+	for _, tc := range f.TelegrafConfigs {
+		if err := createWithID(svc, tc.ID, func() error {
+			return svc.CreateTelegrafConfig(ctx, tc, 1)
+		}); err != nil {
+			t.Fatalf("failed to populate telegraf configs: %v", err)
+		}
+	}
+	for _, d := range f.Dashboards {
+		if err := createWithID(svc, d.ID, func() error {
+			return svc.CreateDashboard(ctx, d)
+		}); err != nil {
+			t.Fatalf("failed to populate dashboards: %v", err)
+		}
+	}
+	for _, l := range f.Labels {
+		if err := createWithID(svc, l.ID, func() error {
+			return svc.CreateLabel(ctx, l)
+		}); err != nil {
+			t.Fatalf("failed to populate labels: %v", err)
+		}
+	}
+	return svc
+}
@@ -7,10 +7,12 @@ import (
 	"fmt"
 
 	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
 )
 
 var (
-	secretBucket = []byte("secretsv1")
+	secretBucket        = []byte("secretsv1")
+	secretVersionBucket = []byte("secretversionsv1")
 )
 
 var _ influxdb.SecretService = (*Service)(nil)
@@ -19,6 +21,9 @@ func (s *Service) initializeSecrets(ctx context.Context, tx Tx) error {
 	if _, err := tx.Bucket(secretBucket); err != nil {
 		return err
 	}
+	if _, err := tx.Bucket(secretVersionBucket); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -42,6 +47,177 @@ func (s *Service) LoadSecret(ctx context.Context, orgID influxdb.ID, k string) (
 	return v, nil
 }
 
+// credentials are the changes to the credentials of a resource of the Service
+// in the secrets of its organization. The secrets of the Service are changed
+// in the transaction that changes the resource. The SecretService can not take
+// part in it, and is not reached while it holds the store: the credentials are
+// resolved and put before it, the ones put are reverted if it fails, and
+// credentials are deleted only once it committed.
+type credentials struct {
+	s      *Service
+	orgID  influxdb.ID
+	fields map[string]*influxdb.SecretField
+
+	// put are the credentials put in the SecretService before the transaction.
+	put []putCredential
+	// deletes are the keys deleted from the SecretService after the transaction.
+	deletes []credentialKey
+}
+
+type credentialKey struct {
+	orgID influxdb.ID
+	key   string
+}
+
+// putCredential is a credential put in the SecretService, with the value it
+// replaced if it had one.
+type putCredential struct {
+	key  string
+	prev *string
+}
+
+func (s *Service) newCredentials() *credentials {
+	return &credentials{s: s}
+}
+
+// stage stages the credential fields of a resource of the organization
+// orgID. Fields that reference a secret must reference one that exists, and
+// fields given by value are put in the secrets of the organization and have
+// their values cleared. With a SecretService, this happens right away;
+// otherwise it happens when the credentials are resolved in the transaction.
+// The credentials put before an error are reverted by done.
+func (c *credentials) stage(ctx context.Context, orgID influxdb.ID, fields map[string]*influxdb.SecretField) error {
+	c.orgID, c.fields = orgID, fields
+	if c.s.SecretService == nil {
+		return nil
+	}
+
+	if err := c.valid(func(k string) error {
+		_, err := c.s.SecretService.LoadSecret(ctx, orgID, k)
+		return err
+	}); err != nil {
+		return err
+	}
+	return c.putValues(func(k, v string) error {
+		put := putCredential{key: k}
+		prev, err := c.s.SecretService.LoadSecret(ctx, orgID, k)
+		switch {
+		case err == nil:
+			put.prev = &prev
+		case influxdb.ErrorCode(err) != influxdb.ENotFound:
+			return err
+		}
+
+		if err := c.s.SecretService.PutSecret(ctx, orgID, k, v); err != nil {
+			return err
+		}
+		c.put = append(c.put, put)
+		return nil
+	})
+}
+
+// resolve resolves and puts the staged credential fields in the secrets of
+// the Service in tx. It does nothing with a SecretService.
+func (c *credentials) resolve(ctx context.Context, tx Tx) error {
+	if c.s.SecretService != nil {
+		return nil
+	}
+
+	if err := c.valid(func(k string) error {
+		_, err := c.s.loadSecret(ctx, tx, c.orgID, k)
+		return err
+	}); err != nil {
+		return err
+	}
+	return c.putValues(func(k, v string) error {
+		return c.s.putSecret(ctx, tx, c.orgID, k, v)
+	})
+}
+
+// valid returns an error if a field references a secret that load does not find.
+func (c *credentials) valid(load func(k string) error) error {
+	for name, f := range c.fields {
+		if f.Value != nil {
+			continue
+		}
+		if err := load(f.Key); err != nil {
+			if influxdb.ErrorCode(err) == influxdb.ENotFound {
+				return &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("%s references secret %s that does not exist", name, f.Key),
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// putValues puts the fields given by value, and clears their values.
+func (c *credentials) putValues(put func(k, v string) error) error {
+	for _, f := range c.fields {
+		if f.Value == nil {
+			continue
+		}
+		if err := put(f.Key, *f.Value); err != nil {
+			return err
+		}
+		f.Value = nil
+	}
+	return nil
+}
+
+// delete deletes a credential of a resource of the organization orgID from
+// its secrets. Credentials that do not exist are ignored.
+func (c *credentials) delete(ctx context.Context, tx Tx, orgID influxdb.ID, k string) error {
+	if c.s.SecretService != nil {
+		c.deletes = append(c.deletes, credentialKey{orgID: orgID, key: k})
+		return nil
+	}
+
+	if err := c.s.deleteSecret(ctx, tx, orgID, k); err != nil && !IsNotFound(err) && influxdb.ErrorCode(err) != influxdb.ENotFound {
+		return err
+	}
+	return nil
+}
+
+// done finishes the changes to the credentials in the SecretService once the
+// transaction returned err, and returns err. If it failed, the credentials
+// put are reverted to the values they replaced or deleted; otherwise the
+// deleted credentials are deleted. The resource is already stored or rolled
+// back, so errors of the SecretService are only logged.
+func (c *credentials) done(ctx context.Context, err error) error {
+	if c.s.SecretService == nil {
+		return err
+	}
+
+	if err != nil {
+		for _, put := range c.put {
+			if put.prev != nil {
+				c.logError("failed to restore credential in secret store", c.orgID, put.key,
+					c.s.SecretService.PutSecret(ctx, c.orgID, put.key, *put.prev))
+				continue
+			}
+			c.logError("failed to delete credential from secret store", c.orgID, put.key,
+				c.s.SecretService.DeleteSecret(ctx, c.orgID, put.key))
+		}
+		return err
+	}
+
+	for _, k := range c.deletes {
+		c.logError("failed to delete credential from secret store", k.orgID, k.key,
+			c.s.SecretService.DeleteSecret(ctx, k.orgID, k.key))
+	}
+	return nil
+}
+
+func (c *credentials) logError(msg string, orgID influxdb.ID, k string, err error) {
+	if err == nil || influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return
+	}
+	c.s.Logger.Error(msg, zap.String("org_id", orgID.String()), zap.String("key", k), zap.Error(err))
+}
+
 func (s *Service) loadSecret(ctx context.Context, tx Tx, orgID influxdb.ID, k string) (string, error) {
 	key, err := encodeSecretKey(orgID, k)
 	if err != nil {
@@ -157,23 +333,35 @@ func (s *Service) PutSecret(ctx context.Context, orgID influxdb.ID, k, v string)
 }
 
 func (s *Service) putSecret(ctx context.Context, tx Tx, orgID influxdb.ID, k, v string) error {
+	_, err := s.putSecretValue(ctx, tx, orgID, k, v)
+	return err
+}
+
+// putSecretValue stores v as the value of the secret at key k, and returns
+// the version of the secret with the value.
+func (s *Service) putSecretValue(ctx context.Context, tx Tx, orgID influxdb.ID, k, v string) (*influxdb.SecretVersion, error) {
 	key, err := encodeSecretKey(orgID, k)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	val := encodeSecretValue(v)
 
+	sv, err := s.putSecretVersion(ctx, tx, orgID, k, val)
+	if err != nil {
+		return nil, err
+	}
+
 	b, err := tx.Bucket(secretBucket)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := b.Put(key, val); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return sv, nil
 }
 
 func encodeSecretKey(orgID influxdb.ID, k string) ([]byte, error) {
@@ -208,11 +396,12 @@ func decodeSecretKey(key []byte) (influxdb.ID, string, error) {
 func decodeSecretValue(val []byte) (string, error) {
 	// store the secret value base64 encoded so that it's marginally better than plaintext
 	v := make([]byte, base64.StdEncoding.DecodedLen(len(val)))
-	if _, err := base64.StdEncoding.Decode(v, val); err != nil {
+	n, err := base64.StdEncoding.Decode(v, val)
+	if err != nil {
 		return "", err
 	}
 
-	return string(v), nil
+	return string(v[:n]), nil
 }
 
 func encodeSecretValue(v string) []byte {
@@ -279,5 +468,14 @@ func (s *Service) deleteSecret(ctx context.Context, tx Tx, orgID influxdb.ID, k
 		return err
 	}
 
-	return b.Delete(key)
+	if err := b.Delete(key); err != nil {
+		return err
+	}
+
+	vb, err := tx.Bucket(secretVersionBucket)
+	if err != nil {
+		return err
+	}
+
+	return vb.Delete(key)
 }
@@ -0,0 +1,204 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// maxSecretVersions is the number of versions kept of each secret.
+const maxSecretVersions = 10
+
+var _ influxdb.SecretRotationService = (*Service)(nil)
+
+// secretVersion is a stored version of a secret. The versions of a secret are
+// stored together, oldest first, under the key of the secret.
+type secretVersion struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Value is encoded like the current value of the secret.
+	Value string `json:"value"`
+}
+
+func (s *Service) findSecretVersions(ctx context.Context, tx Tx, orgID influxdb.ID, k string) ([]secretVersion, error) {
+	key, err := encodeSecretKey(orgID, k)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(secretVersionBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var vs []secretVersion
+	if err := json.Unmarshal(v, &vs); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return vs, nil
+}
+
+// putSecretVersion records val as the latest version of the secret at key k,
+// unless it is the value of the latest version already. The value of a
+// secret stored before its versions were kept is recorded as its first
+// version.
+func (s *Service) putSecretVersion(ctx context.Context, tx Tx, orgID influxdb.ID, k string, val []byte) (*influxdb.SecretVersion, error) {
+	vs, err := s.findSecretVersions(ctx, tx, orgID, k)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := encodeSecretKey(orgID, k)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.Now()
+	if len(vs) == 0 {
+		b, err := tx.Bucket(secretBucket)
+		if err != nil {
+			return nil, err
+		}
+		prev, err := b.Get(key)
+		if err != nil && !IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil {
+			vs = append(vs, secretVersion{Version: 1, CreatedAt: now, Value: string(prev)})
+		}
+	}
+
+	if n := len(vs); n > 0 && vs[n-1].Value == string(val) {
+		return newSecretVersion(orgID, k, vs[n-1]), nil
+	}
+
+	latest := secretVersion{Version: 1, CreatedAt: now, Value: string(val)}
+	if n := len(vs); n > 0 {
+		latest.Version = vs[n-1].Version + 1
+	}
+	vs = append(vs, latest)
+	if len(vs) > maxSecretVersions {
+		vs = vs[len(vs)-maxSecretVersions:]
+	}
+
+	v, err := json.Marshal(vs)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(secretVersionBucket)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Put(key, v); err != nil {
+		return nil, err
+	}
+
+	return newSecretVersion(orgID, k, latest), nil
+}
+
+func newSecretVersion(orgID influxdb.ID, k string, v secretVersion) *influxdb.SecretVersion {
+	return &influxdb.SecretVersion{
+		OrgID:     orgID,
+		Key:       k,
+		Version:   v.Version,
+		CreatedAt: v.CreatedAt,
+	}
+}
+
+// FindSecretVersions returns the versions of the secret at key k for
+// organization orgID, latest first.
+func (s *Service) FindSecretVersions(ctx context.Context, orgID influxdb.ID, k string) ([]*influxdb.SecretVersion, error) {
+	var svs []*influxdb.SecretVersion
+	err := s.kv.View(ctx, func(tx Tx) error {
+		if _, err := s.loadSecret(ctx, tx, orgID, k); err != nil {
+			return err
+		}
+
+		vs, err := s.findSecretVersions(ctx, tx, orgID, k)
+		if err != nil {
+			return err
+		}
+
+		svs = make([]*influxdb.SecretVersion, 0, len(vs))
+		for i := len(vs) - 1; i >= 0; i-- {
+			svs = append(svs, newSecretVersion(orgID, k, vs[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return svs, nil
+}
+
+// LoadSecretVersion retrieves the value of a version of the secret at key k
+// for organization orgID.
+func (s *Service) LoadSecretVersion(ctx context.Context, orgID influxdb.ID, k string, version int) (string, error) {
+	var val string
+	err := s.kv.View(ctx, func(tx Tx) error {
+		vs, err := s.findSecretVersions(ctx, tx, orgID, k)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range vs {
+			if v.Version == version {
+				val, err = decodeSecretValue([]byte(v.Value))
+				return err
+			}
+		}
+
+		return &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrSecretVersionNotFound,
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return val, nil
+}
+
+// RotateSecret stores v as the new value of the existing secret at key k for
+// organization orgID. The rotation hooks of the service are notified of the
+// new version once it is stored.
+func (s *Service) RotateSecret(ctx context.Context, orgID influxdb.ID, k, v string) (*influxdb.SecretVersion, error) {
+	var sv *influxdb.SecretVersion
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.loadSecret(ctx, tx, orgID, k); err != nil {
+			return err
+		}
+
+		var err error
+		sv, err = s.putSecretValue(ctx, tx, orgID, k, v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hook := range s.SecretRotationHooks {
+		hook(ctx, sv)
+	}
+
+	return sv, nil
+}
@@ -0,0 +1,56 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltSecretRotationService(t *testing.T) {
+	influxdbtesting.SecretRotationService(initBoltSecretRotationService, t)
+}
+
+func TestInmemSecretRotationService(t *testing.T) {
+	influxdbtesting.SecretRotationService(initInmemSecretRotationService, t)
+}
+
+func initBoltSecretRotationService(f influxdbtesting.SecretRotationFields, t *testing.T) (influxdbtesting.SecretRotationServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSecretRotationService(s, f, t), closeBolt
+}
+
+func initInmemSecretRotationService(f influxdbtesting.SecretRotationFields, t *testing.T) (influxdbtesting.SecretRotationServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSecretRotationService(s, f, t), closeInmem
+}
+
+func initSecretRotationService(s kv.Store, f influxdbtesting.SecretRotationFields, t *testing.T) influxdbtesting.SecretRotationServices {
+	svc := kv.NewService(s)
+	svc.TimeGenerator = f.TimeGenerator
+	if f.TimeGenerator == nil {
+		svc.TimeGenerator = influxdb.RealTimeGenerator{}
+	}
+	svc.SecretRotationHooks = append(svc.SecretRotationHooks, f.RotationHooks...)
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing secret service: %v", err)
+	}
+	for k, vs := range f.Secrets {
+		for _, v := range vs {
+			if err := svc.PutSecret(ctx, f.OrganizationID, k, v); err != nil {
+				t.Fatalf("failed to populate secrets: %v", err)
+			}
+		}
+	}
+	return svc
+}
@@ -0,0 +1,203 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	sentNotificationBucket = []byte("sentnotificationsv1")
+)
+
+var _ influxdb.SentNotificationService = (*Service)(nil)
+
+func (s *Service) initializeSentNotifications(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(sentNotificationBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sentNotificationLimit returns the number of notifications kept per organization.
+func (s *Service) sentNotificationLimit() int {
+	if s.Config.SentNotificationLimit > 0 {
+		return s.Config.SentNotificationLimit
+	}
+	return influxdb.DefaultSentNotificationLimit
+}
+
+// sentNotificationKey returns the key of a sent notification. The
+// notifications of an organization share a prefix and are ordered by the
+// time they were sent.
+func sentNotificationKey(n *influxdb.SentNotification) ([]byte, error) {
+	prefix, err := sentNotificationPrefix(n.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	encID, err := n.ID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	k := make([]byte, 0, len(prefix)+8+len(encID))
+	k = append(k, prefix...)
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(n.SentAt.UnixNano()))
+	k = append(k, ts[:]...)
+	return append(k, encID...), nil
+}
+
+func sentNotificationPrefix(orgID influxdb.ID) ([]byte, error) {
+	encOrgID, err := orgID.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return encOrgID, nil
+}
+
+// AddSentNotification adds a notification to the history of its organization
+// and removes the oldest notifications beyond the retention limit.
+func (s *Service) AddSentNotification(ctx context.Context, n *influxdb.SentNotification) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.addSentNotification(ctx, tx, n)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpAddSentNotification,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) addSentNotification(ctx context.Context, tx Tx, n *influxdb.SentNotification) error {
+	n.ID = s.IDGenerator.ID()
+	if n.SentAt.IsZero() {
+		n.SentAt = s.Now()
+	}
+
+	k, err := sentNotificationKey(n)
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(n)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(sentNotificationBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(k, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return s.trimSentNotifications(ctx, b, n.OrgID)
+}
+
+// trimSentNotifications removes the oldest notifications of the organization
+// beyond the retention limit.
+func (s *Service) trimSentNotifications(ctx context.Context, b Bucket, orgID influxdb.ID) error {
+	prefix, err := sentNotificationPrefix(orgID)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	for k, _ := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
+		keys = append(keys, k)
+	}
+
+	// The keys are ordered by the time the notifications were sent, oldest first.
+	for n := len(keys) - s.sentNotificationLimit(); n > 0; n-- {
+		if err := b.Delete(keys[n-1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindSentNotifications returns the notifications that match the filter,
+// most recent first.
+func (s *Service) FindSentNotifications(ctx context.Context, filter influxdb.SentNotificationFilter, opt ...influxdb.FindOptions) ([]*influxdb.SentNotification, int, error) {
+	var ns []*influxdb.SentNotification
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		ns, err = s.findSentNotifications(ctx, tx, filter)
+		return err
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindSentNotifications,
+			Err: err,
+		}
+	}
+
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(len(ns))
+		ns = ns[lo:hi]
+	}
+	return ns, len(ns), nil
+}
+
+func (s *Service) findSentNotifications(ctx context.Context, tx Tx, filter influxdb.SentNotificationFilter) ([]*influxdb.SentNotification, error) {
+	b, err := tx.Bucket(sentNotificationBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefix []byte
+	if filter.OrgID != nil {
+		if prefix, err = sentNotificationPrefix(*filter.OrgID); err != nil {
+			return nil, err
+		}
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	ns := []*influxdb.SentNotification{}
+	for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+		n := &influxdb.SentNotification{}
+		if err := json.Unmarshal(v, n); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if (filter.RuleID != nil && n.RuleID != *filter.RuleID) ||
+			(filter.CheckID != nil && n.CheckID != *filter.CheckID) ||
+			(filter.EndpointID != nil && n.EndpointID != *filter.EndpointID) ||
+			n.SentAt.Before(filter.Since) {
+			continue
+		}
+		ns = append(ns, n)
+	}
+
+	// The notifications of every organization are ordered oldest first.
+	sort.SliceStable(ns, func(i, j int) bool {
+		return ns[i].SentAt.After(ns[j].SentAt)
+	})
+	return ns, nil
+}
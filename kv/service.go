@@ -28,6 +28,19 @@ type Service struct {
 	TokenGenerator influxdb.TokenGenerator
 	influxdb.TimeGenerator
 	Hash Crypt
+
+	// SessionStore stores the sessions of the Service. It defaults to storing
+	// the sessions along with the other resources of the Service.
+	SessionStore SessionStore
+
+	// SecretService stores the credentials of the resources of the Service,
+	// such as notification endpoints and scraper targets, when the secrets
+	// of organizations are kept in another secret store. They are kept in
+	// the secrets of the Service when it is nil.
+	SecretService influxdb.SecretService
+
+	// SecretRotationHooks are notified of the secrets rotated by the Service.
+	SecretRotationHooks []influxdb.SecretRotationHook
 }
 
 // NewService returns an instance of a Service.
@@ -39,6 +52,7 @@ func NewService(kv Store, configs ...ServiceConfig) *Service {
 		Hash:           &Bcrypt{},
 		kv:             kv,
 		TimeGenerator:  influxdb.RealTimeGenerator{},
+		SessionStore:   NewLocalSessionStore(kv),
 	}
 
 	if len(configs) > 0 {
@@ -53,11 +67,33 @@ func NewService(kv Store, configs ...ServiceConfig) *Service {
 // ServiceConfig allows us to configure Services
 type ServiceConfig struct {
 	SessionLength time.Duration
+	// QueryHistoryLimit is the number of completed queries kept per organization.
+	// It defaults to influxdb.DefaultQueryHistoryLimit.
+	QueryHistoryLimit int
+	// SentNotificationLimit is the number of sent notifications kept per
+	// organization. It defaults to influxdb.DefaultSentNotificationLimit.
+	SentNotificationLimit int
+	// AuditRecordLimit is the number of audit records kept per organization.
+	// It defaults to influxdb.DefaultAuditRecordLimit.
+	AuditRecordLimit int
+	// NamePolicy is the name policy of organizations that have none.
+	// It defaults to influxdb.DefaultNamePolicy.
+	NamePolicy *influxdb.NamePolicy
+	// PasswordPolicy is the policy passwords must satisfy.
+	// It defaults to influxdb.DefaultPasswordPolicy.
+	PasswordPolicy *influxdb.PasswordPolicy
+	// TrashRetention is how long deleted buckets, dashboards and tasks are kept
+	// in the trash. They are deleted at once when it is zero.
+	TrashRetention time.Duration
 }
 
 // Initialize creates Buckets needed.
 func (s *Service) Initialize(ctx context.Context) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
+		if err := s.initializeAudit(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeAuths(ctx, tx); err != nil {
 			return err
 		}
@@ -74,6 +110,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeDBRPMappings(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeKVLog(ctx, tx); err != nil {
 			return err
 		}
@@ -94,6 +134,74 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeTaskTemplates(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeFluxPackages(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeQueryViews(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeMeasurementSchemas(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeSchemaDescriptions(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeTeams(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeNotificationEndpoints(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeChecks(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeNotificationRules(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeSentNotifications(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeSilences(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeAnnotations(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeStacks(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeInvites(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeDashboardShares(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializePasswordResets(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeQueryHistory(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializePasswords(ctx, tx); err != nil {
 			return err
 		}
@@ -102,6 +210,10 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeScraperTargetStatuses(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeSecrets(ctx, tx); err != nil {
 			return err
 		}
@@ -118,6 +230,18 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		if err := s.initializeTelegrafAgents(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeTrash(ctx, tx); err != nil {
+			return err
+		}
+
+		if err := s.initializeRevisions(ctx, tx); err != nil {
+			return err
+		}
+
 		if err := s.initializeURMs(ctx, tx); err != nil {
 			return err
 		}
@@ -126,6 +250,11 @@ func (s *Service) Initialize(ctx context.Context) error {
 			return err
 		}
 
+		// The search index is initialized after the resources it indexes.
+		if err := s.initializeSearch(ctx, tx); err != nil {
+			return err
+		}
+
 		return s.initializeUsers(ctx, tx)
 	})
 }
@@ -2,19 +2,16 @@ package kv
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/influxdata/influxdb"
 )
 
-var (
-	sessionBucket = []byte("sessionsv1")
-)
-
 var _ influxdb.SessionService = (*Service)(nil)
 
 func (s *Service) initializeSessions(ctx context.Context, tx Tx) error {
+	// The bucket is created for the default SessionStore, which stores the
+	// sessions along with the other resources.
 	if _, err := tx.Bucket([]byte(sessionBucket)); err != nil {
 		return err
 	}
@@ -28,30 +25,34 @@ func (s *Service) RenewSession(ctx context.Context, session *influxdb.Session, n
 			Msg: "session is nil",
 		}
 	}
-	return s.kv.Update(ctx, func(tx Tx) error {
-		session.ExpiresAt = newExpiration
-		if err := s.putSession(ctx, tx, session); err != nil {
-			return &influxdb.Error{
-				Err: err,
-			}
+
+	session.ExpiresAt = newExpiration
+	if err := s.PutSession(ctx, session); err != nil {
+		return &influxdb.Error{
+			Err: err,
 		}
-		return nil
-	})
+	}
+	return nil
 }
 
 // FindSession retrieves the session found at the provided key.
 func (s *Service) FindSession(ctx context.Context, key string) (*influxdb.Session, error) {
-	var sess *influxdb.Session
-	err := s.kv.View(ctx, func(tx Tx) error {
-		s, err := s.findSession(ctx, tx, key)
+	sess, err := s.findSession(ctx, key)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	err = s.kv.View(ctx, func(tx Tx) error {
+		ps, err := s.userPermissions(ctx, tx, sess.UserID)
 		if err != nil {
 			return err
 		}
 
-		sess = s
+		sess.Permissions = ps
 		return nil
 	})
-
 	if err != nil {
 		return nil, &influxdb.Error{
 			Err: err,
@@ -67,13 +68,9 @@ func (s *Service) FindSession(ctx context.Context, key string) (*influxdb.Sessio
 	return sess, nil
 }
 
-func (s *Service) findSession(ctx context.Context, tx Tx, key string) (*influxdb.Session, error) {
-	b, err := tx.Bucket(sessionBucket)
-	if err != nil {
-		return nil, err
-	}
-
-	v, err := b.Get([]byte(key))
+// findSession returns the stored session at key, without its permissions.
+func (s *Service) findSession(ctx context.Context, key string) (*influxdb.Session, error) {
+	sn, err := s.SessionStore.Get(ctx, key)
 	if IsNotFound(err) {
 		return nil, &influxdb.Error{
 			Code: influxdb.ENotFound,
@@ -82,18 +79,36 @@ func (s *Service) findSession(ctx context.Context, tx Tx, key string) (*influxdb
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, &influxdb.Error{
+			Err: err,
+		}
 	}
 
-	sn := &influxdb.Session{}
-	if err := json.Unmarshal(v, sn); err != nil {
+	return sn, nil
+}
+
+var _ influxdb.UserPermissionService = (*Service)(nil)
+
+// FindUserPermissions returns the maximal privileges of the user.
+func (s *Service) FindUserPermissions(ctx context.Context, userID influxdb.ID) ([]influxdb.Permission, error) {
+	var ps []influxdb.Permission
+	err := s.kv.View(ctx, func(tx Tx) error {
+		var err error
+		ps, err = s.userPermissions(ctx, tx, userID)
+		return err
+	})
+	if err != nil {
 		return nil, &influxdb.Error{
 			Err: err,
 		}
 	}
+	return ps, nil
+}
 
+// userPermissions returns the maximal privileges of the user.
+func (s *Service) userPermissions(ctx context.Context, tx Tx, userID influxdb.ID) ([]influxdb.Permission, error) {
 	// TODO(desa): these values should be cached so it's not so expensive to lookup each time.
-	f := influxdb.UserResourceMappingFilter{UserID: sn.UserID}
+	f := influxdb.UserResourceMappingFilter{UserID: userID}
 	mappings, err := s.findUserResourceMappings(ctx, tx, f)
 	if err != nil {
 		return nil, &influxdb.Error{
@@ -101,6 +116,15 @@ func (s *Service) findSession(ctx context.Context, tx Tx, key string) (*influxdb
 		}
 	}
 
+	// A user is given the permissions of the mappings of their teams as well.
+	teamMappings, err := s.teamUserResourceMappings(ctx, tx, userID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Err: err,
+		}
+	}
+	mappings = append(mappings, teamMappings...)
+
 	ps := make([]influxdb.Permission, 0, len(mappings))
 	for _, m := range mappings {
 		p, err := m.ToPermissions()
@@ -112,11 +136,11 @@ func (s *Service) findSession(ctx context.Context, tx Tx, key string) (*influxdb
 
 		ps = append(ps, p...)
 	}
-	ps = append(ps, influxdb.MePermissions(sn.UserID)...)
+	ps = append(ps, influxdb.MePermissions(userID)...)
 
 	// TODO(desa): this is super expensive, we should keep a list of a users maximal privileges somewhere
 	// we did this so that the oper token would be used in a users permissions.
-	af := influxdb.AuthorizationFilter{UserID: &sn.UserID}
+	af := influxdb.AuthorizationFilter{UserID: &userID}
 	as, err := s.findAuthorizations(ctx, tx, af)
 	if err != nil {
 		return nil, err
@@ -125,34 +149,12 @@ func (s *Service) findSession(ctx context.Context, tx Tx, key string) (*influxdb
 		ps = append(ps, a.Permissions...)
 	}
 
-	sn.Permissions = ps
-	return sn, nil
+	return ps, nil
 }
 
 // PutSession puts the session at key.
 func (s *Service) PutSession(ctx context.Context, sn *influxdb.Session) error {
-	return s.kv.Update(ctx, func(tx Tx) error {
-		if err := s.putSession(ctx, tx, sn); err != nil {
-			return err
-		}
-		return nil
-	})
-}
-
-func (s *Service) putSession(ctx context.Context, tx Tx, sn *influxdb.Session) error {
-	v, err := json.Marshal(sn)
-	if err != nil {
-		return &influxdb.Error{
-			Err: err,
-		}
-	}
-
-	b, err := tx.Bucket(sessionBucket)
-	if err != nil {
-		return err
-	}
-
-	if err := b.Put([]byte(sn.Key), v); err != nil {
+	if err := s.SessionStore.Put(ctx, sn); err != nil {
 		return &influxdb.Error{
 			Err: err,
 		}
@@ -162,48 +164,31 @@ func (s *Service) putSession(ctx context.Context, tx Tx, sn *influxdb.Session) e
 
 // ExpireSession expires the session at the provided key.
 func (s *Service) ExpireSession(ctx context.Context, key string) error {
-	return s.kv.Update(ctx, func(tx Tx) error {
-		sn, err := s.findSession(ctx, tx, key)
-		if err != nil {
-			return err
-		}
-
-		sn.ExpiresAt = time.Now()
+	sn, err := s.findSession(ctx, key)
+	if err != nil {
+		return err
+	}
 
-		if err := s.putSession(ctx, tx, sn); err != nil {
-			return err
-		}
-		return nil
-	})
+	sn.ExpiresAt = time.Now()
+	return s.PutSession(ctx, sn)
 }
 
 // CreateSession creates a session for a user with the users maximal privileges.
 func (s *Service) CreateSession(ctx context.Context, user string) (*influxdb.Session, error) {
-	var sess *influxdb.Session
-	err := s.kv.Update(ctx, func(tx Tx) error {
-		sn, err := s.createSession(ctx, tx, user)
-		if err != nil {
-			return err
+	var u *influxdb.User
+	err := s.kv.View(ctx, func(tx Tx) error {
+		usr, pe := s.findUserByName(ctx, tx, user)
+		if pe != nil {
+			return pe
 		}
 
-		sess = sn
-
+		u = usr
 		return nil
 	})
-
 	if err != nil {
 		return nil, err
 	}
 
-	return sess, nil
-}
-
-func (s *Service) createSession(ctx context.Context, tx Tx, user string) (*influxdb.Session, error) {
-	u, pe := s.findUserByName(ctx, tx, user)
-	if pe != nil {
-		return nil, pe
-	}
-
 	sn := &influxdb.Session{}
 	sn.ID = s.IDGenerator.ID()
 	k, err := s.TokenGenerator.Token()
@@ -219,7 +204,7 @@ func (s *Service) createSession(ctx context.Context, tx Tx, user string) (*influ
 	// TODO(desa): not totally sure what to do here. Possibly we should have a maximal privilege permission.
 	sn.Permissions = []influxdb.Permission{}
 
-	if err := s.putSession(ctx, tx, sn); err != nil {
+	if err := s.PutSession(ctx, sn); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,154 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var (
+	sessionBucket = []byte("sessionsv1")
+)
+
+// SessionStore stores the sessions of a Service by key. Sessions may be kept
+// apart from the other resources of the Service, such as in a store that is
+// shared by several API nodes.
+type SessionStore interface {
+	// Get returns the session stored at key, or ErrKeyNotFound if there is none.
+	// Expired sessions may be returned until the store removes them.
+	Get(ctx context.Context, key string) (*influxdb.Session, error)
+	// Put stores the session at its key. The store may remove the session once
+	// it expired.
+	Put(ctx context.Context, sn *influxdb.Session) error
+}
+
+var _ SessionStore = (*LocalSessionStore)(nil)
+
+// LocalSessionStore stores sessions in a bucket of a Store. Expired sessions
+// are kept until DeleteExpired removes them.
+type LocalSessionStore struct {
+	kv     Store
+	Logger *zap.Logger
+}
+
+// NewLocalSessionStore returns a SessionStore that stores sessions in st.
+func NewLocalSessionStore(st Store) *LocalSessionStore {
+	return &LocalSessionStore{
+		kv:     st,
+		Logger: zap.NewNop(),
+	}
+}
+
+// Initialize creates the bucket of the sessions.
+func (s *LocalSessionStore) Initialize(ctx context.Context) error {
+	return s.kv.Update(ctx, func(tx Tx) error {
+		_, err := tx.Bucket(sessionBucket)
+		return err
+	})
+}
+
+// Get returns the session stored at key.
+func (s *LocalSessionStore) Get(ctx context.Context, key string) (*influxdb.Session, error) {
+	var v []byte
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(sessionBucket)
+		if err != nil {
+			return err
+		}
+
+		v, err = b.Get([]byte(key))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSession(v)
+}
+
+// Put stores the session at its key.
+func (s *LocalSessionStore) Put(ctx context.Context, sn *influxdb.Session) error {
+	v, err := json.Marshal(sn)
+	if err != nil {
+		return err
+	}
+
+	return s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(sessionBucket)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(sn.Key), v)
+	})
+}
+
+// DeleteExpired removes the sessions that expired before now and returns how
+// many were removed.
+func (s *LocalSessionStore) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	var n int
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(sessionBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		var expired [][]byte
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			sn, err := decodeSession(v)
+			if err != nil {
+				return err
+			}
+			if sn.ExpiresAt.Before(now) {
+				expired = append(expired, k)
+			}
+		}
+
+		for _, k := range expired {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		n = len(expired)
+		return nil
+	})
+	return n, err
+}
+
+// Cleanup removes the expired sessions every interval until ctx is done.
+func (s *LocalSessionStore) Cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			n, err := s.DeleteExpired(ctx, now)
+			if err != nil {
+				s.Logger.Error("failed to delete expired sessions", zap.Error(err))
+				continue
+			}
+			if n > 0 {
+				s.Logger.Debug("deleted expired sessions", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+func decodeSession(v []byte) (*influxdb.Session, error) {
+	sn := &influxdb.Session{}
+	if err := json.Unmarshal(v, sn); err != nil {
+		return nil, err
+	}
+	return sn, nil
+}
@@ -3,6 +3,7 @@ package kv_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kv"
@@ -71,3 +72,59 @@ func initSessionService(s kv.Store, f influxdbtesting.SessionFields, t *testing.
 		}
 	}
 }
+
+func TestBoltLocalSessionStore_DeleteExpired(t *testing.T) {
+	st, closeStore, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+	testLocalSessionStoreDeleteExpired(st, t)
+}
+
+func TestInmemLocalSessionStore_DeleteExpired(t *testing.T) {
+	st, closeStore, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	defer closeStore()
+	testLocalSessionStoreDeleteExpired(st, t)
+}
+
+func testLocalSessionStoreDeleteExpired(st kv.Store, t *testing.T) {
+	ctx := context.Background()
+	s := kv.NewLocalSessionStore(st)
+	if err := s.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	sessions := []*influxdb.Session{
+		{ID: 1, Key: "expired", UserID: 10, ExpiresAt: now.Add(-time.Minute)},
+		{ID: 2, Key: "active", UserID: 10, ExpiresAt: now.Add(time.Minute)},
+	}
+	for _, sn := range sessions {
+		if err := s.Put(ctx, sn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := s.DeleteExpired(ctx, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("got %d deleted sessions, want 1", n)
+	}
+
+	if _, err := s.Get(ctx, "expired"); !kv.IsNotFound(err) {
+		t.Errorf("expected expired session to be deleted, got %v", err)
+	}
+	sn, err := s.Get(ctx, "active")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sn.ID != 2 || !sn.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("unexpected session %+v", sn)
+	}
+}
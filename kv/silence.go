@@ -0,0 +1,287 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+)
+
+var (
+	silenceBucket = []byte("silencesv1")
+)
+
+var _ influxdb.SilenceService = (*Service)(nil)
+
+func (s *Service) initializeSilences(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(silenceBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindSilenceByID retrieves a silence by id.
+func (s *Service) FindSilenceByID(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.View(ctx, func(tx Tx) error {
+		silence, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		sl = silence
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindSilenceByID,
+			Err: err,
+		}
+	}
+
+	return sl, nil
+}
+
+func (s *Service) findSilenceByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Silence, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrSilenceNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sl := &influxdb.Silence{}
+	if err := json.Unmarshal(v, sl); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return sl, nil
+}
+
+func (s *Service) filterSilencesFn(filter influxdb.SilenceFilter) func(sl *influxdb.Silence) bool {
+	now := s.Now()
+	return func(sl *influxdb.Silence) bool {
+		return (filter.ID == nil || *filter.ID == sl.ID) &&
+			(filter.OrgID == nil || *filter.OrgID == sl.OrgID) &&
+			(filter.CheckID == nil || *filter.CheckID == sl.CheckID) &&
+			(filter.RuleID == nil || *filter.RuleID == sl.RuleID) &&
+			(filter.State == nil || *filter.State == sl.State(now))
+	}
+}
+
+// FindSilences returns the silences that match the filter, ordered by ID.
+func (s *Service) FindSilences(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+	ss := []*influxdb.Silence{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		silences, err := s.findSilences(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		ss = silences
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindSilences,
+			Err: err,
+		}
+	}
+
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(len(ss))
+		ss = ss[lo:hi]
+	}
+
+	return ss, len(ss), nil
+}
+
+func (s *Service) findSilences(ctx context.Context, tx Tx, filter influxdb.SilenceFilter) ([]*influxdb.Silence, error) {
+	filterFn := s.filterSilencesFn(filter)
+	if filter.ID != nil {
+		sl, err := s.findSilenceByID(ctx, tx, *filter.ID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.Silence{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filterFn(sl) {
+			return []*influxdb.Silence{}, nil
+		}
+		return []*influxdb.Silence{sl}, nil
+	}
+
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	ss := []*influxdb.Silence{}
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		sl := &influxdb.Silence{}
+		if err := json.Unmarshal(v, sl); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if filterFn(sl) {
+			ss = append(ss, sl)
+		}
+	}
+
+	return ss, nil
+}
+
+// CreateSilence creates a silence. The user on the context is its creator.
+func (s *Service) CreateSilence(ctx context.Context, sl *influxdb.Silence) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if sl.StartsAt.IsZero() {
+			sl.StartsAt = s.Now()
+		}
+		if err := sl.Valid(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, sl.OrgID); err != nil {
+			return err
+		}
+
+		if err := s.validSilenceReferences(ctx, tx, sl); err != nil {
+			return err
+		}
+
+		sl.CreatedBy = 0
+		if a, err := icontext.GetAuthorizer(ctx); err == nil {
+			sl.CreatedBy = a.GetUserID()
+		}
+
+		sl.ID = s.IDGenerator.ID()
+		sl.CreatedAt = s.Now()
+		sl.UpdatedAt = s.Now()
+		return s.putSilence(ctx, tx, sl)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateSilence,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// validSilenceReferences returns an error if the check or the notification
+// rule of sl is not one of its organization.
+func (s *Service) validSilenceReferences(ctx context.Context, tx Tx, sl *influxdb.Silence) error {
+	if sl.CheckID.Valid() {
+		c, err := s.findCheckByID(ctx, tx, sl.CheckID)
+		if err != nil {
+			return err
+		}
+		if c.OrgID != sl.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "check belongs to another organization",
+			}
+		}
+	}
+
+	if sl.RuleID.Valid() {
+		r, err := s.findNotificationRuleByID(ctx, tx, sl.RuleID)
+		if err != nil {
+			return err
+		}
+		if r.OrgID != sl.OrgID {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "notification rule belongs to another organization",
+			}
+		}
+	}
+	return nil
+}
+
+// ExpireSilence ends a silence now, and starts a pending silence so that it
+// ended as soon as it started.
+func (s *Service) ExpireSilence(ctx context.Context, id influxdb.ID) (*influxdb.Silence, error) {
+	var sl *influxdb.Silence
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		silence, err := s.findSilenceByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		now := s.Now()
+		if silence.State(now) == influxdb.SilenceExpired {
+			sl = silence
+			return nil
+		}
+		if silence.StartsAt.After(now) {
+			silence.StartsAt = now
+		}
+		silence.EndsAt = now
+		silence.UpdatedAt = now
+		if err := s.putSilence(ctx, tx, silence); err != nil {
+			return err
+		}
+		sl = silence
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpExpireSilence,
+			Err: err,
+		}
+	}
+
+	return sl, nil
+}
+
+func (s *Service) putSilence(ctx context.Context, tx Tx, sl *influxdb.Silence) error {
+	v, err := json.Marshal(sl)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := sl.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(silenceBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, v)
+}
@@ -0,0 +1,55 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltSilenceService(t *testing.T) {
+	influxdbtesting.SilenceService(initBoltSilenceService, t)
+}
+
+func TestInmemSilenceService(t *testing.T) {
+	influxdbtesting.SilenceService(initInmemSilenceService, t)
+}
+
+func initBoltSilenceService(f influxdbtesting.SilenceFields, t *testing.T) (influxdb.SilenceService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSilenceService(s, f, t), closeBolt
+}
+
+func initInmemSilenceService(f influxdbtesting.SilenceFields, t *testing.T) (influxdb.SilenceService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initSilenceService(s, f, t), closeInmem
+}
+
+func initSilenceService(s kv.Store, f influxdbtesting.SilenceFields, t *testing.T) influxdb.SilenceService {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, c := range f.Checks {
+		if err := createWithID(svc, c.ID, func() error {
+			return svc.CreateCheck(ctx, c)
+		}); err != nil {
+			t.Fatalf("failed to populate checks: %v", err)
+		}
+	}
+	for _, sl := range f.Silences {
+		if err := createWithID(svc, sl.ID, func() error {
+			return svc.CreateSilence(ctx, sl)
+		}); err != nil {
+			t.Fatalf("failed to populate silences: %v", err)
+		}
+	}
+	return svc
+}
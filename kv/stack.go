@@ -0,0 +1,273 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	stackBucket = []byte("stacksv1")
+)
+
+var _ influxdb.StackService = (*Service)(nil)
+
+func (s *Service) initializeStacks(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(stackBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindStackByID retrieves a stack by id.
+func (s *Service) FindStackByID(ctx context.Context, id influxdb.ID) (*influxdb.Stack, error) {
+	var st *influxdb.Stack
+	err := s.kv.View(ctx, func(tx Tx) error {
+		stack, err := s.findStackByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		st = stack
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindStackByID,
+			Err: err,
+		}
+	}
+
+	return st, nil
+}
+
+func (s *Service) findStackByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Stack, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(stackBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrStackNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	st := &influxdb.Stack{}
+	if err := json.Unmarshal(v, st); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return st, nil
+}
+
+// FindStacks returns the stacks that match the filter, ordered by ID.
+func (s *Service) FindStacks(ctx context.Context, filter influxdb.StackFilter, opt ...influxdb.FindOptions) ([]*influxdb.Stack, int, error) {
+	ss := []*influxdb.Stack{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		stacks, err := s.findStacks(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		ss = stacks
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindStacks,
+			Err: err,
+		}
+	}
+
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(len(ss))
+		ss = ss[lo:hi]
+	}
+
+	return ss, len(ss), nil
+}
+
+func (s *Service) findStacks(ctx context.Context, tx Tx, filter influxdb.StackFilter) ([]*influxdb.Stack, error) {
+	b, err := tx.Bucket(stackBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	ss := []*influxdb.Stack{}
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		st := &influxdb.Stack{}
+		if err := json.Unmarshal(v, st); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if (filter.OrgID == nil || *filter.OrgID == st.OrgID) &&
+			(filter.Name == nil || *filter.Name == st.Name) {
+			ss = append(ss, st)
+		}
+	}
+
+	return ss, nil
+}
+
+// CreateStack creates a stack.
+func (s *Service) CreateStack(ctx context.Context, st *influxdb.Stack) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := st.Valid(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, st.OrgID); err != nil {
+			return err
+		}
+
+		st.ID = s.IDGenerator.ID()
+		if err := s.uniqueStackName(ctx, tx, st); err != nil {
+			return err
+		}
+
+		if st.Resources == nil {
+			st.Resources = []influxdb.StackResource{}
+		}
+		st.CreatedAt = s.Now()
+		st.UpdatedAt = s.Now()
+		return s.putStack(ctx, tx, st)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateStack,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateStack updates a stack.
+func (s *Service) UpdateStack(ctx context.Context, id influxdb.ID, upd influxdb.StackUpdate) (*influxdb.Stack, error) {
+	var st *influxdb.Stack
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		stack, err := s.findStackByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(stack)
+		if err := stack.Valid(); err != nil {
+			return err
+		}
+		if upd.Name != nil {
+			if err := s.uniqueStackName(ctx, tx, stack); err != nil {
+				return err
+			}
+		}
+
+		stack.UpdatedAt = s.Now()
+		if err := s.putStack(ctx, tx, stack); err != nil {
+			return err
+		}
+		st = stack
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateStack,
+			Err: err,
+		}
+	}
+
+	return st, nil
+}
+
+// DeleteStack deletes a stack.
+func (s *Service) DeleteStack(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findStackByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		encodedID, err := id.Encode()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(stackBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(encodedID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteStack,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// uniqueStackName returns an error if another stack of the organization has the name of st.
+func (s *Service) uniqueStackName(ctx context.Context, tx Tx, st *influxdb.Stack) error {
+	stacks, err := s.findStacks(ctx, tx, influxdb.StackFilter{OrgID: &st.OrgID, Name: &st.Name})
+	if err != nil {
+		return err
+	}
+	for _, other := range stacks {
+		if other.ID != st.ID {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("stack with name %s already exists", st.Name),
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) putStack(ctx context.Context, tx Tx, st *influxdb.Stack) error {
+	v, err := json.Marshal(st)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := st.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(stackBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, v)
+}
@@ -0,0 +1,48 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltStackService(t *testing.T) {
+	influxdbtesting.StackService(initBoltStackService, t)
+}
+
+func TestInmemStackService(t *testing.T) {
+	influxdbtesting.StackService(initInmemStackService, t)
+}
+
+func initBoltStackService(f influxdbtesting.StackFields, t *testing.T) (influxdb.StackService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initStackService(s, f, t), closeBolt
+}
+
+func initInmemStackService(f influxdbtesting.StackFields, t *testing.T) (influxdb.StackService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initStackService(s, f, t), closeInmem
+}
+
+func initStackService(s kv.Store, f influxdbtesting.StackFields, t *testing.T) influxdb.StackService {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, st := range f.Stacks {
+		if err := createWithID(svc, st.ID, func() error {
+			return svc.CreateStack(ctx, st)
+		}); err != nil {
+			t.Fatalf("failed to populate stacks: %v", err)
+		}
+	}
+	return svc
+}
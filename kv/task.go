@@ -227,6 +227,10 @@ func (s *Service) findTasksByUser(ctx context.Context, tx Tx, filter influxdb.Ta
 			continue
 		}
 
+		if !task.Metadata.Matches(filter.Metadata) {
+			continue
+		}
+
 		ts = append(ts, task)
 
 		if len(ts) >= filter.Limit {
@@ -297,9 +301,8 @@ func (s *Service) findTaskByOrg(ctx context.Context, tx Tx, filter influxdb.Task
 			}
 
 			// insert the new task into the list
-			if t != nil {
+			if t != nil && t.Metadata.Matches(filter.Metadata) {
 				ts = append(ts, t)
-
 			}
 		}
 	}
@@ -334,6 +337,10 @@ func (s *Service) findTaskByOrg(ctx context.Context, tx Tx, filter influxdb.Task
 			break
 		}
 
+		if !t.Metadata.Matches(filter.Metadata) {
+			continue
+		}
+
 		// insert the new task into the list
 		ts = append(ts, t)
 
@@ -390,7 +397,9 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter influxdb.TaskF
 			t.LatestCompleted = t.CreatedAt
 		}
 		// insert the new task into the list
-		ts = append(ts, t)
+		if t.Metadata.Matches(filter.Metadata) {
+			ts = append(ts, t)
+		}
 	}
 
 	// if someone has a limit of 1
@@ -416,6 +425,9 @@ func (s *Service) findAllTasks(ctx context.Context, tx Tx, filter influxdb.TaskF
 		} else {
 			t.LatestCompleted = t.CreatedAt
 		}
+		if !t.Metadata.Matches(filter.Metadata) {
+			continue
+		}
 		// insert the new task into the list
 		ts = append(ts, t)
 
@@ -504,10 +516,22 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 		Cron:            opt.Cron,
 		CreatedAt:       createdAt,
 		LatestCompleted: createdAt,
+
+		MemoryBytesQuota:   tc.MemoryBytesQuota,
+		MaxDuration:        tc.MaxDuration,
+		DeleteAfterSuccess: tc.DeleteAfterSuccess,
+		Metadata:           tc.Metadata,
 	}
 	if opt.Offset != nil {
 		task.Offset = opt.Offset.String()
 	}
+	if opt.At != nil {
+		task.At = opt.At.Format(time.RFC3339)
+	}
+
+	if err := s.uniqueTaskName(ctx, tx, task.OrganizationID, task.ID, task.Name); err != nil {
+		return nil, err
+	}
 
 	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
@@ -545,6 +569,10 @@ func (s *Service) createTask(ctx context.Context, tx Tx, tc influxdb.TaskCreate)
 	if err != nil {
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
+
+	if err := s.indexSearchDocument(ctx, tx, taskSearchDocument(task)); err != nil {
+		return nil, err
+	}
 	if err := s.createUserResourceMapping(ctx, tx, &influxdb.UserResourceMapping{
 		ResourceType: influxdb.TasksResourceType,
 		ResourceID:   task.ID,
@@ -581,6 +609,7 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 	if err != nil {
 		return nil, err
 	}
+	prev := taskRevisionOf(task)
 
 	// update the flux script
 	if !upd.Options.IsZero() || upd.Flux != nil {
@@ -593,12 +622,28 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		if err != nil {
 			return nil, influxdb.ErrTaskOptionParse(err)
 		}
+		if options.Name != task.Name {
+			if err := s.uniqueTaskName(ctx, tx, task.OrganizationID, task.ID, options.Name); err != nil {
+				return nil, err
+			}
+		}
 		task.Name = options.Name
 		task.Every = options.Every.String()
 		task.Cron = options.Cron
 		if options.Offset != nil {
 			task.Offset = options.Offset.String()
 		}
+		oldAt := task.At
+		task.At = ""
+		if options.At != nil {
+			task.At = options.At.Format(time.RFC3339)
+		}
+		if task.RunsOnce() && task.At != oldAt {
+			// A task that runs once and is given a new time runs again.
+			if err := s.deleteLatestCompleted(ctx, tx, task.ID); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// update the Token
@@ -614,6 +659,10 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.Description = *upd.Description
 	}
 
+	if upd.DeleteAfterSuccess != nil {
+		task.DeleteAfterSuccess = *upd.DeleteAfterSuccess
+	}
+
 	if upd.Status != nil {
 		task.Status = *upd.Status
 	}
@@ -622,6 +671,14 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		task.LatestCompleted = *upd.LatestCompleted
 	}
 
+	if upd.Metadata != nil {
+		m, err := upd.Metadata.Apply(task.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		task.Metadata = m
+	}
+
 	task.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
 	// save the updated task
 	bucket, err := tx.Bucket(taskBucket)
@@ -638,12 +695,25 @@ func (s *Service) updateTask(ctx context.Context, tx Tx, id influxdb.ID, upd inf
 		return nil, influxdb.ErrInternalTaskServiceError(err)
 	}
 
-	return task, bucket.Put(key, taskBytes)
+	if err := bucket.Put(key, taskBytes); err != nil {
+		return nil, err
+	}
+
+	if err := s.putRevision(ctx, tx, prev, task.Flux); err != nil {
+		return nil, err
+	}
+
+	return task, s.indexSearchDocument(ctx, tx, taskSearchDocument(task))
 }
 
 // DeleteTask removes a task by ID and purges all associated data and scheduled runs.
+// The task is moved to the trash instead when the service has a trash retention;
+// its runs are then kept until it is purged from the trash.
 func (s *Service) DeleteTask(ctx context.Context, id influxdb.ID) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
+		if s.trashEnabled() {
+			return s.trashTask(ctx, tx, id)
+		}
 		err := s.deleteTask(ctx, tx, id)
 		if err != nil {
 			return err
@@ -658,25 +728,48 @@ func (s *Service) DeleteTask(ctx context.Context, id influxdb.ID) error {
 }
 
 func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
-	taskBucket, err := tx.Bucket(taskBucket)
+	// retrieve the task
+	task, err := s.findTaskByID(ctx, tx, id)
 	if err != nil {
-		return influxdb.ErrUnexpectedTaskBucketErr(err)
+		return err
 	}
 
-	runBucket, err := tx.Bucket(taskRunBucket)
+	if err := s.removeTask(ctx, tx, task); err != nil {
+		return err
+	}
+
+	return s.purgeTask(ctx, tx, task.ID)
+}
+
+// trashTask removes a task and moves it to the trash.
+func (s *Service) trashTask(ctx context.Context, tx Tx, id influxdb.ID) error {
+	task, err := s.findTaskByID(ctx, tx, id)
 	if err != nil {
-		return influxdb.ErrUnexpectedTaskBucketErr(err)
+		return err
 	}
 
-	indexBucket, err := tx.Bucket(taskIndexBucket)
+	if err := s.removeTask(ctx, tx, task); err != nil {
+		return err
+	}
+
+	return s.putTrashedResource(ctx, tx, influxdb.TrashedResource{
+		Type:  influxdb.TasksResourceType,
+		ID:    task.ID,
+		OrgID: task.OrganizationID,
+		Name:  task.Name,
+	}, task)
+}
+
+// removeTask removes a task and its indexes, so it can no longer be found.
+func (s *Service) removeTask(ctx context.Context, tx Tx, task *influxdb.Task) error {
+	taskBucket, err := tx.Bucket(taskBucket)
 	if err != nil {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
-	// retrieve the task
-	task, err := s.findTaskByID(ctx, tx, id)
+	indexBucket, err := tx.Bucket(taskIndexBucket)
 	if err != nil {
-		return err
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
 	// remove the orgs index
@@ -689,8 +782,28 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	// remove the task
+	key, err := taskKey(task.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := taskBucket.Delete(key); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	return s.unindexSearchDocument(ctx, tx, influxdb.TasksResourceType, task.ID)
+}
+
+// purgeTask deletes the runs and everything else that belongs to a removed task.
+func (s *Service) purgeTask(ctx context.Context, tx Tx, id influxdb.ID) error {
+	runBucket, err := tx.Bucket(taskRunBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
 	// remove latest completed
-	lastCompletedKey, err := taskLatestCompletedKey(task.ID)
+	lastCompletedKey, err := taskLatestCompletedKey(id)
 	if err != nil {
 		return err
 	}
@@ -700,13 +813,13 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 	}
 
 	// remove the runs
-	runs, _, err := s.findRuns(ctx, tx, influxdb.RunFilter{Task: task.ID})
+	runs, _, err := s.findRuns(ctx, tx, influxdb.RunFilter{Task: id})
 	if err != nil {
 		return err
 	}
 
 	for _, run := range runs {
-		key, err := taskRunKey(task.ID, run.ID)
+		key, err := taskRunKey(id, run.ID)
 		if err != nil {
 			return err
 		}
@@ -715,19 +828,70 @@ func (s *Service) deleteTask(ctx context.Context, tx Tx, id influxdb.ID) error {
 			return influxdb.ErrUnexpectedTaskBucketErr(err)
 		}
 	}
-	// remove the task
-	key, err := taskKey(task.ID)
+
+	if err := s.deleteUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID: id,
+	}); err != nil {
+		return err
+	}
+
+	return s.deleteRevisions(ctx, tx, influxdb.TasksResourceType, id)
+}
+
+// restoreTask puts a trashed task back, unless its organization was deleted
+// or another task of the organization took its name. The task is restored
+// inactive, so it is only scheduled again once it is activated.
+func (s *Service) restoreTask(ctx context.Context, tx Tx, v []byte) error {
+	var task influxdb.Task
+	if err := json.Unmarshal(v, &task); err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	if _, err := s.findOrganizationByID(ctx, tx, task.OrganizationID); err != nil {
+		return err
+	}
+
+	if err := s.uniqueTaskName(ctx, tx, task.OrganizationID, task.ID, task.Name); err != nil {
+		return err
+	}
+
+	task.Status = string(backend.TaskInactive)
+	task.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	taskBucket, err := tx.Bucket(taskBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	indexBucket, err := tx.Bucket(taskIndexBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	taskBytes, err := json.Marshal(task)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	taskKey, err := taskKey(task.ID)
 	if err != nil {
 		return err
 	}
 
-	if err := taskBucket.Delete(key); err != nil {
+	orgKey, err := taskOrgKey(task.OrganizationID, task.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := taskBucket.Put(taskKey, taskBytes); err != nil {
 		return influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
-	return s.deleteUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
-		ResourceID: task.ID,
-	})
+	if err := indexBucket.Put(orgKey, taskKey); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	return s.indexSearchDocument(ctx, tx, taskSearchDocument(&task))
 }
 
 // FindLogs returns logs for a run.
@@ -920,10 +1084,10 @@ func (s *Service) cancelRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 }
 
 // RetryRun creates and returns a new run (which is a retry of another run).
-func (s *Service) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+func (s *Service) RetryRun(ctx context.Context, taskID, runID influxdb.ID, annotation string) (*influxdb.Run, error) {
 	var r *influxdb.Run
 	err := s.kv.Update(ctx, func(tx Tx) error {
-		run, err := s.retryRun(ctx, tx, taskID, runID)
+		run, err := s.retryRun(ctx, tx, taskID, runID, annotation)
 		if err != nil {
 			return err
 		}
@@ -933,7 +1097,7 @@ func (s *Service) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*inf
 	return r, err
 }
 
-func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID, annotation string) (*influxdb.Run, error) {
 	// find the run
 	r, err := s.findRunByID(ctx, tx, taskID, runID)
 	if err != nil {
@@ -945,6 +1109,7 @@ func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID
 	r.StartedAt = ""
 	r.FinishedAt = ""
 	r.RequestedAt = ""
+	r.Annotation = annotation
 
 	// add a clean copy of the run to the manual runs
 	bucket, err := tx.Bucket(taskRunBucket)
@@ -990,10 +1155,10 @@ func (s *Service) retryRun(ctx context.Context, tx Tx, taskID, runID influxdb.ID
 
 // ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 // The value of scheduledFor may or may not align with the task's schedule.
-func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor int64, annotation string) (*influxdb.Run, error) {
 	var r *influxdb.Run
 	err := s.kv.Update(ctx, func(tx Tx) error {
-		run, err := s.forceRun(ctx, tx, taskID, scheduledFor)
+		run, err := s.forceRun(ctx, tx, taskID, scheduledFor, annotation)
 		if err != nil {
 			return err
 		}
@@ -1003,7 +1168,7 @@ func (s *Service) ForceRun(ctx context.Context, taskID influxdb.ID, scheduledFor
 	return r, err
 }
 
-func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor int64) (*influxdb.Run, error) {
+func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, scheduledFor int64, annotation string) (*influxdb.Run, error) {
 	// create a run
 	t := time.Unix(scheduledFor, 0).UTC()
 	r := &influxdb.Run{
@@ -1012,6 +1177,7 @@ func (s *Service) forceRun(ctx context.Context, tx Tx, taskID influxdb.ID, sched
 		Status:       backend.RunScheduled.String(),
 		RequestedAt:  time.Now().UTC().Format(time.RFC3339),
 		ScheduledFor: t.Format(time.RFC3339),
+		Annotation:   annotation,
 		Log:          []influxdb.Log{},
 	}
 
@@ -1148,6 +1314,10 @@ func (s *Service) createNextRun(ctx context.Context, tx Tx, taskID influxdb.ID,
 		return rc, nil
 	}
 
+	if task.RunsOnce() {
+		return s.createOnceRun(ctx, tx, task, now)
+	}
+
 	// get the latest completed and the latest currently running run's time
 	// the earliest it could have been completed is "created at"
 	latestCompleted, err := time.Parse(time.RFC3339, task.CreatedAt)
@@ -1263,6 +1433,57 @@ func (s *Service) createNextRun(ctx context.Context, tx Tx, taskID influxdb.ID,
 	}, nil
 }
 
+// createOnceRun creates the run of a task that runs once. It is scheduled for
+// the at time of the task, even if that has passed, and is created only once.
+func (s *Service) createOnceRun(ctx context.Context, tx Tx, task *influxdb.Task, now int64) (backend.RunCreation, error) {
+	dueAt, err := s.nextDueOnceRun(ctx, tx, task)
+	if err != nil {
+		return backend.RunCreation{}, err
+	}
+	if dueAt == backend.RunNeverDue {
+		return backend.RunCreation{}, influxdb.ErrTaskRunOnceCreated
+	}
+	if dueAt > now {
+		return backend.RunCreation{}, influxdb.ErrRunNotDueYet(dueAt)
+	}
+
+	run := influxdb.Run{
+		ID:           s.IDGenerator.ID(),
+		TaskID:       task.ID,
+		ScheduledFor: time.Unix(dueAt, 0).UTC().Format(time.RFC3339),
+		Status:       backend.RunScheduled.String(),
+		Log:          []influxdb.Log{},
+	}
+	b, err := tx.Bucket(taskRunBucket)
+	if err != nil {
+		return backend.RunCreation{}, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	runBytes, err := json.Marshal(run)
+	if err != nil {
+		return backend.RunCreation{}, influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	runKey, err := taskRunKey(task.ID, run.ID)
+	if err != nil {
+		return backend.RunCreation{}, err
+	}
+	if err := b.Put(runKey, runBytes); err != nil {
+		return backend.RunCreation{}, influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+
+	return backend.RunCreation{
+		Created: backend.QueuedRun{
+			TaskID: task.ID,
+			RunID:  run.ID,
+			DueAt:  dueAt,
+			Now:    dueAt,
+		},
+		NextDue:  backend.RunNeverDue,
+		HasQueue: false,
+	}, nil
+}
+
 // CreateRun creates a run with a scheduledFor time as now.
 func (s *Service) CreateRun(ctx context.Context, taskID influxdb.ID, scheduledFor time.Time) (*influxdb.Run, error) {
 	var r *influxdb.Run
@@ -1546,9 +1767,54 @@ func (s *Service) finishRun(ctx context.Context, tx Tx, taskID, runID influxdb.I
 		return nil, influxdb.ErrUnexpectedTaskBucketErr(err)
 	}
 
+	if r.Status == backend.RunSuccess.String() {
+		if err := s.completeOnceTask(ctx, tx, taskID); err != nil {
+			return nil, err
+		}
+	}
+
 	return r, nil
 }
 
+// completeOnceTask completes a task that runs once after a run of it
+// succeeded, by making it inactive or, if it is to be deleted after success,
+// deleting it. A task that runs by schedule is left as it is.
+func (s *Service) completeOnceTask(ctx context.Context, tx Tx, taskID influxdb.ID) error {
+	task, err := s.findTaskByID(ctx, tx, taskID)
+	if err != nil {
+		return err
+	}
+	if !task.RunsOnce() {
+		return nil
+	}
+
+	if task.DeleteAfterSuccess {
+		return s.deleteTask(ctx, tx, taskID)
+	}
+
+	task.Status = string(backend.TaskInactive)
+	task.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	bucket, err := tx.Bucket(taskBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	key, err := taskKey(taskID)
+	if err != nil {
+		return err
+	}
+
+	taskBytes, err := json.Marshal(task)
+	if err != nil {
+		return influxdb.ErrInternalTaskServiceError(err)
+	}
+
+	if err := bucket.Put(key, taskBytes); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
 // NextDueRun returns the Unix timestamp of when the next call to CreateNextRun will be ready.
 // The returned timestamp reflects the task's offset, so it does not necessarily exactly match the schedule time.
 func (s *Service) NextDueRun(ctx context.Context, taskID influxdb.ID) (int64, error) {
@@ -1574,6 +1840,10 @@ func (s *Service) nextDueRun(ctx context.Context, tx Tx, taskID influxdb.ID) (in
 		return 0, err
 	}
 
+	if task.RunsOnce() {
+		return s.nextDueOnceRun(ctx, tx, task)
+	}
+
 	latestCompleted, err := time.Parse(time.RFC3339, task.LatestCompleted)
 	if err != nil {
 		return 0, err
@@ -1589,6 +1859,33 @@ func (s *Service) nextDueRun(ctx context.Context, tx Tx, taskID influxdb.ID) (in
 	return nextScheduled.Unix(), nil
 }
 
+// nextDueOnceRun returns when the run of a task that runs once is due, which is
+// never once the run has been created.
+func (s *Service) nextDueOnceRun(ctx context.Context, tx Tx, task *influxdb.Task) (int64, error) {
+	at, err := task.AtTime()
+	if err != nil {
+		return 0, influxdb.ErrTaskTimeParse(err)
+	}
+
+	latestRun, err := s.findLatestCompleted(ctx, tx, task.ID)
+	if err != nil {
+		return 0, err
+	}
+	if latestRun != nil {
+		return backend.RunNeverDue, nil
+	}
+
+	runs, err := s.currentlyRunning(ctx, tx, task.ID)
+	if err != nil {
+		return 0, err
+	}
+	if len(runs) > 0 {
+		return backend.RunNeverDue, nil
+	}
+
+	return at.Unix(), nil
+}
+
 // UpdateRunState sets the run state at the respective time.
 func (s *Service) UpdateRunState(ctx context.Context, taskID, runID influxdb.ID, when time.Time, state backend.RunStatus) error {
 	err := s.kv.Update(ctx, func(tx Tx) error {
@@ -1709,6 +2006,22 @@ func (s *Service) findLatestCompleted(ctx context.Context, tx Tx, id influxdb.ID
 	return run, nil
 }
 
+func (s *Service) deleteLatestCompleted(ctx context.Context, tx Tx, id influxdb.ID) error {
+	bucket, err := tx.Bucket(taskRunBucket)
+	if err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	key, err := taskLatestCompletedKey(id)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Delete(key); err != nil {
+		return influxdb.ErrUnexpectedTaskBucketErr(err)
+	}
+	return nil
+}
+
 func (s *Service) findLatestCompletedTime(ctx context.Context, tx Tx, id influxdb.ID) (time.Time, error) {
 	run, err := s.findLatestCompleted(ctx, tx, id)
 	if err != nil {
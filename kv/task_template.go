@@ -0,0 +1,241 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	taskTemplateBucket = []byte("tasktemplatesv1")
+)
+
+var _ influxdb.TaskTemplateService = (*Service)(nil)
+
+func (s *Service) initializeTaskTemplates(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(taskTemplateBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindTaskTemplateByID retrieves a task template by id.
+func (s *Service) FindTaskTemplateByID(ctx context.Context, id influxdb.ID) (*influxdb.TaskTemplate, error) {
+	var tt *influxdb.TaskTemplate
+	err := s.kv.View(ctx, func(tx Tx) error {
+		t, err := s.findTaskTemplateByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		tt = t
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindTaskTemplateByID,
+			Err: err,
+		}
+	}
+
+	return tt, nil
+}
+
+func (s *Service) findTaskTemplateByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.TaskTemplate, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(taskTemplateBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrTaskTemplateNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tt := &influxdb.TaskTemplate{}
+	if err := json.Unmarshal(v, tt); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	return tt, nil
+}
+
+// FindTaskTemplates returns all task templates that match the filter.
+func (s *Service) FindTaskTemplates(ctx context.Context, filter influxdb.TaskTemplateFilter) ([]*influxdb.TaskTemplate, error) {
+	tts := []*influxdb.TaskTemplate{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(taskTemplateBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			tt := &influxdb.TaskTemplate{}
+			if err := json.Unmarshal(v, tt); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			if filter.OrganizationID != nil && tt.OrganizationID != *filter.OrganizationID {
+				continue
+			}
+			tts = append(tts, tt)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindTaskTemplates,
+			Err: err,
+		}
+	}
+
+	return tts, nil
+}
+
+// CreateTaskTemplate creates a new task template and assigns it an ID.
+func (s *Service) CreateTaskTemplate(ctx context.Context, tt *influxdb.TaskTemplate) error {
+	if err := tt.Valid(); err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateTaskTemplate,
+			Err: err,
+		}
+	}
+
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findOrganizationByID(ctx, tx, tt.OrganizationID); err != nil {
+			return err
+		}
+
+		tt.ID = s.IDGenerator.ID()
+		now := s.Now()
+		tt.CreatedAt = now
+		tt.UpdatedAt = now
+		return s.putTaskTemplate(ctx, tx, tt)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateTaskTemplate,
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) putTaskTemplate(ctx context.Context, tx Tx, tt *influxdb.TaskTemplate) error {
+	v, err := json.Marshal(tt)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encID, err := tt.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(taskTemplateBucket)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Put(encID, v); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// UpdateTaskTemplate updates a single task template with a changeset.
+func (s *Service) UpdateTaskTemplate(ctx context.Context, id influxdb.ID, upd influxdb.TaskTemplateUpdate) (*influxdb.TaskTemplate, error) {
+	var tt *influxdb.TaskTemplate
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		t, err := s.findTaskTemplateByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := upd.Apply(t); err != nil {
+			return err
+		}
+		t.UpdatedAt = s.Now()
+
+		if err := s.putTaskTemplate(ctx, tx, t); err != nil {
+			return err
+		}
+		tt = t
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateTaskTemplate,
+			Err: err,
+		}
+	}
+
+	return tt, nil
+}
+
+// DeleteTaskTemplate removes a task template by its ID.
+// Tasks created from the template are left untouched.
+func (s *Service) DeleteTaskTemplate(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if _, err := s.findTaskTemplateByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		encID, err := id.Encode()
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+
+		b, err := tx.Bucket(taskTemplateBucket)
+		if err != nil {
+			return err
+		}
+
+		return b.Delete(encID)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteTaskTemplate,
+			Err: err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltTaskTemplateService(t *testing.T) {
+	influxdbtesting.TaskTemplateService(initBoltTaskTemplateService, t)
+}
+
+func TestInmemTaskTemplateService(t *testing.T) {
+	influxdbtesting.TaskTemplateService(initInmemTaskTemplateService, t)
+}
+
+func initBoltTaskTemplateService(f influxdbtesting.TaskTemplateFields, t *testing.T) (influxdb.TaskTemplateService, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initTaskTemplateService(s, f, t), closeBolt
+}
+
+func initInmemTaskTemplateService(f influxdbtesting.TaskTemplateFields, t *testing.T) (influxdb.TaskTemplateService, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initTaskTemplateService(s, f, t), closeInmem
+}
+
+func initTaskTemplateService(s kv.Store, f influxdbtesting.TaskTemplateFields, t *testing.T) influxdb.TaskTemplateService {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, tt := range f.TaskTemplates {
+		if err := createWithID(svc, tt.ID, func() error {
+			return svc.CreateTaskTemplate(ctx, tt)
+		}); err != nil {
+			t.Fatalf("failed to populate task templates: %v", err)
+		}
+	}
+	return svc
+}
@@ -0,0 +1,353 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var (
+	teamBucket = []byte("teamsv1")
+)
+
+var _ influxdb.TeamService = (*Service)(nil)
+
+func (s *Service) initializeTeams(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(teamBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindTeamByID retrieves a team by id.
+func (s *Service) FindTeamByID(ctx context.Context, id influxdb.ID) (*influxdb.Team, error) {
+	var t *influxdb.Team
+	err := s.kv.View(ctx, func(tx Tx) error {
+		team, err := s.findTeamByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		t = team
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindTeamByID,
+			Err: err,
+		}
+	}
+
+	return t, nil
+}
+
+func (s *Service) findTeamByID(ctx context.Context, tx Tx, id influxdb.ID) (*influxdb.Team, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(teamBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(encodedID)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrTeamNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t := &influxdb.Team{}
+	if err := json.Unmarshal(v, t); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return t, nil
+}
+
+func filterTeamsFn(filter influxdb.TeamFilter) func(t *influxdb.Team) bool {
+	return func(t *influxdb.Team) bool {
+		return (filter.ID == nil || *filter.ID == t.ID) &&
+			(filter.OrgID == nil || *filter.OrgID == t.OrgID) &&
+			(filter.Name == nil || *filter.Name == t.Name)
+	}
+}
+
+// FindTeams returns the teams that match the filter.
+func (s *Service) FindTeams(ctx context.Context, filter influxdb.TeamFilter, opt ...influxdb.FindOptions) ([]*influxdb.Team, int, error) {
+	ts := []*influxdb.Team{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		teams, err := s.findTeams(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		ts = teams
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindTeams,
+			Err: err,
+		}
+	}
+
+	return ts, len(ts), nil
+}
+
+func (s *Service) findTeams(ctx context.Context, tx Tx, filter influxdb.TeamFilter) ([]*influxdb.Team, error) {
+	if filter.ID != nil {
+		t, err := s.findTeamByID(ctx, tx, *filter.ID)
+		if influxdb.ErrorCode(err) == influxdb.ENotFound {
+			return []*influxdb.Team{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !filterTeamsFn(filter)(t) {
+			return []*influxdb.Team{}, nil
+		}
+		return []*influxdb.Team{t}, nil
+	}
+
+	ts := []*influxdb.Team{}
+	filterFn := filterTeamsFn(filter)
+	err := s.forEachTeam(ctx, tx, func(t *influxdb.Team) bool {
+		if filterFn(t) {
+			ts = append(ts, t)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+func (s *Service) forEachTeam(ctx context.Context, tx Tx, fn func(*influxdb.Team) bool) error {
+	b, err := tx.Bucket(teamBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		t := &influxdb.Team{}
+		if err := json.Unmarshal(v, t); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if !fn(t) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// CreateTeam creates a team and makes the user on the context its owner.
+func (s *Service) CreateTeam(ctx context.Context, t *influxdb.Team) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := t.Valid(); err != nil {
+			return err
+		}
+
+		if _, err := s.findOrganizationByID(ctx, tx, t.OrgID); err != nil {
+			return err
+		}
+
+		if err := s.uniqueTeamName(ctx, tx, t); err != nil {
+			return err
+		}
+
+		t.ID = s.IDGenerator.ID()
+		t.CreatedAt = s.Now()
+		t.UpdatedAt = s.Now()
+		if err := s.putTeam(ctx, tx, t); err != nil {
+			return err
+		}
+
+		if err := s.addResourceOwner(ctx, tx, influxdb.TeamsResourceType, t.ID); err != nil {
+			s.Logger.Info("failed to make user owner of team", zap.Error(err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateTeam,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// uniqueTeamName returns an error if another team of the organization of t has its name.
+func (s *Service) uniqueTeamName(ctx context.Context, tx Tx, t *influxdb.Team) error {
+	ts, err := s.findTeams(ctx, tx, influxdb.TeamFilter{OrgID: &t.OrgID, Name: &t.Name})
+	if err != nil {
+		return err
+	}
+	for _, other := range ts {
+		if other.ID != t.ID {
+			return &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("team with name %s already exists", t.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// UpdateTeam updates a team with the changeset.
+func (s *Service) UpdateTeam(ctx context.Context, id influxdb.ID, upd influxdb.TeamUpdate) (*influxdb.Team, error) {
+	var t *influxdb.Team
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		team, err := s.findTeamByID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		upd.Apply(team)
+		if err := team.Valid(); err != nil {
+			return err
+		}
+		if err := s.uniqueTeamName(ctx, tx, team); err != nil {
+			return err
+		}
+
+		team.UpdatedAt = s.Now()
+		if err := s.putTeam(ctx, tx, team); err != nil {
+			return err
+		}
+		t = team
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateTeam,
+			Err: err,
+		}
+	}
+
+	return t, nil
+}
+
+func (s *Service) putTeam(ctx context.Context, tx Tx, t *influxdb.Team) error {
+	v, err := json.Marshal(t)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	encodedID, err := t.ID.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(teamBucket)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(encodedID, v)
+}
+
+// DeleteTeam deletes a team, the mappings of its users to it and the mappings
+// of the team to resources.
+func (s *Service) DeleteTeam(ctx context.Context, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		return s.deleteTeam(ctx, tx, id)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpDeleteTeam,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteTeam(ctx context.Context, tx Tx, id influxdb.ID) error {
+	if _, err := s.findTeamByID(ctx, tx, id); err != nil {
+		return err
+	}
+
+	encodedID, err := id.Encode()
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+
+	b, err := tx.Bucket(teamBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(encodedID); err != nil {
+		return err
+	}
+
+	if err := s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID:   id,
+		ResourceType: influxdb.TeamsResourceType,
+	}); err != nil {
+		return err
+	}
+
+	return s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		UserID: id,
+	})
+}
+
+// teamUserResourceMappings returns the mappings to resources of the teams the user belongs to.
+func (s *Service) teamUserResourceMappings(ctx context.Context, tx Tx, userID influxdb.ID) ([]*influxdb.UserResourceMapping, error) {
+	memberships, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+		UserID:       userID,
+		ResourceType: influxdb.TeamsResourceType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ms []*influxdb.UserResourceMapping
+	for _, membership := range memberships {
+		teamMappings, err := s.findUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
+			UserID: membership.ResourceID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range teamMappings {
+			if m.MappingType == influxdb.TeamMappingType {
+				ms = append(ms, m)
+			}
+		}
+	}
+	return ms, nil
+}
@@ -0,0 +1,62 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltTeamService(t *testing.T) {
+	influxdbtesting.TeamService(initBoltTeamService, t)
+}
+
+func TestInmemTeamService(t *testing.T) {
+	influxdbtesting.TeamService(initInmemTeamService, t)
+}
+
+func initBoltTeamService(f influxdbtesting.TeamFields, t *testing.T) (influxdbtesting.TeamServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initTeamService(s, f, t), closeBolt
+}
+
+func initInmemTeamService(f influxdbtesting.TeamFields, t *testing.T) (influxdbtesting.TeamServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initTeamService(s, f, t), closeInmem
+}
+
+func initTeamService(s kv.Store, f influxdbtesting.TeamFields, t *testing.T) influxdbtesting.TeamServices {
+	svc := initTestService(s, f.IDGenerator, f.TimeGenerator, f.Organizations, t)
+
+	ctx := context.Background()
+	for _, u := range f.Users {
+		if err := svc.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users: %v", err)
+		}
+	}
+	for _, b := range f.Buckets {
+		if err := svc.PutBucket(ctx, b); err != nil {
+			t.Fatalf("failed to populate buckets: %v", err)
+		}
+	}
+	for _, team := range f.Teams {
+		if err := createWithID(svc, team.ID, func() error {
+			return svc.CreateTeam(ctx, team)
+		}); err != nil {
+			t.Fatalf("failed to populate teams: %v", err)
+		}
+	}
+	for _, m := range f.UserResourceMappings {
+		if err := svc.CreateUserResourceMapping(ctx, m); err != nil {
+			t.Fatalf("failed to populate user resource mappings: %v", err)
+		}
+	}
+	return svc
+}
@@ -195,7 +195,7 @@ func (s *Service) putTelegrafConfig(ctx context.Context, tx Tx, tc *influxdb.Tel
 	if err := bucket.Put(encodedID, v); err != nil {
 		return UnavailableTelegrafServiceError(err)
 	}
-	return nil
+	return s.indexSearchDocument(ctx, tx, telegrafSearchDocument(tc))
 }
 
 // CreateTelegrafConfig creates a new telegraf config and sets b.ID with the new identifier.
@@ -274,6 +274,14 @@ func (s *Service) deleteTelegrafConfig(ctx context.Context, tx Tx, id influxdb.I
 		return UnavailableTelegrafServiceError(err)
 	}
 
+	if err := s.unindexSearchDocument(ctx, tx, influxdb.TelegrafsResourceType, id); err != nil {
+		return err
+	}
+
+	if err := s.deleteTelegrafAgents(ctx, tx, id); err != nil {
+		return err
+	}
+
 	return s.deleteUserResourceMappings(ctx, tx, influxdb.UserResourceMappingFilter{
 		ResourceID:   id,
 		ResourceType: influxdb.TelegrafsResourceType,
@@ -0,0 +1,176 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	telegrafAgentBucket = []byte("telegrafagentsv1")
+)
+
+var _ influxdb.TelegrafAgentService = (*Service)(nil)
+
+func (s *Service) initializeTelegrafAgents(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(telegrafAgentBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// telegrafAgentKey is the key of an agent, which is prefixed by the ID of its config.
+func telegrafAgentKey(configID influxdb.ID, hostname string) ([]byte, error) {
+	encodedID, err := configID.Encode()
+	if err != nil {
+		return nil, ErrInvalidTelegrafID
+	}
+	return append(encodedID, hostname...), nil
+}
+
+// CheckInTelegrafAgent records the state a telegraf agent reports.
+func (s *Service) CheckInTelegrafAgent(ctx context.Context, a *influxdb.TelegrafAgent) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		if err := a.Valid(); err != nil {
+			return err
+		}
+
+		tc, err := s.findTelegrafConfigByID(ctx, tx, a.ConfigID)
+		if err != nil {
+			return err
+		}
+		a.OrgID = tc.OrgID
+
+		key, err := telegrafAgentKey(a.ConfigID, a.Hostname)
+		if err != nil {
+			return err
+		}
+
+		b, err := tx.Bucket(telegrafAgentBucket)
+		if err != nil {
+			return err
+		}
+
+		now := s.Now()
+		a.FirstCheckIn = now
+		v, err := b.Get(key)
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			current := &influxdb.TelegrafAgent{}
+			if err := json.Unmarshal(v, current); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			a.FirstCheckIn = current.FirstCheckIn
+		}
+		a.LastCheckIn = now
+
+		v, err = json.Marshal(a)
+		if err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		return b.Put(key, v)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpCheckInTelegrafAgent,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindTelegrafAgents returns the telegraf agents that match the filter, ordered by hostname.
+func (s *Service) FindTelegrafAgents(ctx context.Context, filter influxdb.TelegrafAgentFilter) ([]*influxdb.TelegrafAgent, error) {
+	as := []*influxdb.TelegrafAgent{}
+	err := s.kv.View(ctx, func(tx Tx) error {
+		b, err := tx.Bucket(telegrafAgentBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		var prefix []byte
+		if filter.ConfigID != nil {
+			if prefix, err = filter.ConfigID.Encode(); err != nil {
+				return ErrInvalidTelegrafID
+			}
+		}
+
+		k, v := cur.First()
+		if prefix != nil {
+			k, v = cur.Seek(prefix)
+		}
+		for ; k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+			a := &influxdb.TelegrafAgent{}
+			if err := json.Unmarshal(v, a); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			if filter.OrgID != nil && *filter.OrgID != a.OrgID {
+				continue
+			}
+			as = append(as, a)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindTelegrafAgents,
+			Err: err,
+		}
+	}
+
+	sort.Slice(as, func(i, j int) bool {
+		if as[i].Hostname != as[j].Hostname {
+			return as[i].Hostname < as[j].Hostname
+		}
+		return as[i].ConfigID < as[j].ConfigID
+	})
+	return as, nil
+}
+
+// deleteTelegrafAgents forgets the agents of a telegraf config.
+func (s *Service) deleteTelegrafAgents(ctx context.Context, tx Tx, configID influxdb.ID) error {
+	prefix, err := configID.Encode()
+	if err != nil {
+		return ErrInvalidTelegrafID
+	}
+
+	b, err := tx.Bucket(telegrafAgentBucket)
+	if err != nil {
+		return err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	for k, _ := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/mock"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltTelegrafAgentService(t *testing.T) {
+	influxdbtesting.TelegrafAgentService(initBoltTelegrafAgentService, t)
+}
+
+func TestInmemTelegrafAgentService(t *testing.T) {
+	influxdbtesting.TelegrafAgentService(initInmemTelegrafAgentService, t)
+}
+
+func initBoltTelegrafAgentService(f influxdbtesting.TelegrafAgentFields, t *testing.T) (influxdbtesting.TelegrafAgentServices, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initTelegrafAgentService(s, f, t), closeBolt
+}
+
+func initInmemTelegrafAgentService(f influxdbtesting.TelegrafAgentFields, t *testing.T) (influxdbtesting.TelegrafAgentServices, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initTelegrafAgentService(s, f, t), closeInmem
+}
+
+func initTelegrafAgentService(s kv.Store, f influxdbtesting.TelegrafAgentFields, t *testing.T) influxdbtesting.TelegrafAgentServices {
+	svc := initTestService(s, nil, f.TimeGenerator, nil, t)
+
+	ctx := context.Background()
+	for _, tc := range f.TelegrafConfigs {
+		if err := svc.PutTelegrafConfig(ctx, tc); err != nil {
+			t.Fatalf("failed to populate telegraf configs: %v", err)
+		}
+	}
+	tg := svc.TimeGenerator
+	for _, a := range f.TelegrafAgents {
+		svc.TimeGenerator = mock.TimeGenerator{FakeValue: a.LastCheckIn}
+		if err := svc.CheckInTelegrafAgent(ctx, a); err != nil {
+			t.Fatalf("failed to populate telegraf agents: %v", err)
+		}
+	}
+	svc.TimeGenerator = tg
+	return svc
+}
@@ -0,0 +1,290 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/influxdata/influxdb"
+)
+
+var (
+	trashBucket = []byte("trashv1")
+)
+
+var _ influxdb.TrashService = (*Service)(nil)
+
+// trashedResource is a trashed resource along with the resource as it was
+// when it was deleted, which is put back when it is restored.
+type trashedResource struct {
+	influxdb.TrashedResource
+	Resource json.RawMessage `json:"resource"`
+}
+
+func (s *Service) initializeTrash(ctx context.Context, tx Tx) error {
+	if _, err := tx.Bucket(trashBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// trashEnabled returns whether deleted resources are moved to the trash.
+func (s *Service) trashEnabled() bool {
+	return s.Config.TrashRetention > 0
+}
+
+func trashKey(typ influxdb.ResourceType, id influxdb.ID) ([]byte, error) {
+	encodedID, err := id.Encode()
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  err,
+		}
+	}
+	return append([]byte(typ+"/"), encodedID...), nil
+}
+
+// putTrashedResource moves the removed resource v to the trash, where it is
+// kept for the trash retention of the service.
+func (s *Service) putTrashedResource(ctx context.Context, tx Tx, r influxdb.TrashedResource, v interface{}) error {
+	resource, err := json.Marshal(v)
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	r.DeletedAt = s.Now()
+	r.ExpiresAt = r.DeletedAt.Add(s.Config.TrashRetention)
+	tr, err := json.Marshal(&trashedResource{
+		TrashedResource: r,
+		Resource:        resource,
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+
+	key, err := trashKey(r.Type, r.ID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(key, tr); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// FindTrashedResource returns a single trashed resource by its type and ID.
+func (s *Service) FindTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) (*influxdb.TrashedResource, error) {
+	var r *influxdb.TrashedResource
+	err := s.kv.View(ctx, func(tx Tx) error {
+		tr, err := s.findTrashedResource(ctx, tx, typ, id)
+		if err != nil {
+			return err
+		}
+		r = &tr.TrashedResource
+		return nil
+	})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindTrashedResource,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+func (s *Service) findTrashedResource(ctx context.Context, tx Tx, typ influxdb.ResourceType, id influxdb.ID) (*trashedResource, error) {
+	key, err := trashKey(typ, id)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := b.Get(key)
+	if IsNotFound(err) {
+		return nil, &influxdb.Error{
+			Code: influxdb.ENotFound,
+			Msg:  influxdb.ErrTrashedResourceNotFound,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tr := &trashedResource{}
+	if err := json.Unmarshal(v, tr); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+	return tr, nil
+}
+
+// FindTrashedResources returns the trashed resources that match filter and
+// the total count of matching resources, most recently deleted first.
+func (s *Service) FindTrashedResources(ctx context.Context, filter influxdb.TrashFilter, opt ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+	var rs []*influxdb.TrashedResource
+	err := s.kv.View(ctx, func(tx Tx) error {
+		trs, err := s.findTrashedResources(ctx, tx, filter)
+		if err != nil {
+			return err
+		}
+		rs = trs
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &influxdb.Error{
+			Op:  influxdb.OpFindTrashedResources,
+			Err: err,
+		}
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		if !rs[i].DeletedAt.Equal(rs[j].DeletedAt) {
+			return rs[i].DeletedAt.After(rs[j].DeletedAt)
+		}
+		return rs[i].ID < rs[j].ID
+	})
+
+	n := len(rs)
+	if len(opt) > 0 {
+		lo, hi := opt[0].Page(n)
+		rs = rs[lo:hi]
+	}
+	return rs, n, nil
+}
+
+func (s *Service) findTrashedResources(ctx context.Context, tx Tx, filter influxdb.TrashFilter) ([]*influxdb.TrashedResource, error) {
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	cur, err := b.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	rs := []*influxdb.TrashedResource{}
+	for k, v := cur.First(); k != nil; k, v = cur.Next() {
+		tr := &trashedResource{}
+		if err := json.Unmarshal(v, tr); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Err:  err,
+			}
+		}
+		if filter.OrgID != nil && tr.OrgID != *filter.OrgID {
+			continue
+		}
+		if filter.Type != nil && tr.Type != *filter.Type {
+			continue
+		}
+		rs = append(rs, &tr.TrashedResource)
+	}
+	return rs, nil
+}
+
+// RestoreTrashedResource moves a resource out of the trash, as it was when it
+// was deleted.
+func (s *Service) RestoreTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		tr, err := s.findTrashedResource(ctx, tx, typ, id)
+		if err != nil {
+			return err
+		}
+
+		switch tr.Type {
+		case influxdb.BucketsResourceType:
+			err = s.restoreBucket(ctx, tx, tr.Resource)
+		case influxdb.DashboardsResourceType:
+			err = s.restoreDashboard(ctx, tx, tr.Resource)
+		case influxdb.TasksResourceType:
+			err = s.restoreTask(ctx, tx, tr.Resource)
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.deleteTrashedResource(ctx, tx, tr)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpRestoreTrashedResource,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// PurgeTrashedResource permanently deletes a trashed resource and what
+// belongs to it.
+func (s *Service) PurgeTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) error {
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		tr, err := s.findTrashedResource(ctx, tx, typ, id)
+		if err != nil {
+			return err
+		}
+
+		switch tr.Type {
+		case influxdb.BucketsResourceType:
+			err = s.purgeBucket(ctx, tx, tr.ID)
+		case influxdb.DashboardsResourceType:
+			var d influxdb.Dashboard
+			if err := json.Unmarshal(tr.Resource, &d); err != nil {
+				return &influxdb.Error{
+					Code: influxdb.EInternal,
+					Err:  err,
+				}
+			}
+			err = s.purgeDashboard(ctx, tx, &d)
+		case influxdb.TasksResourceType:
+			err = s.purgeTask(ctx, tx, tr.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.deleteTrashedResource(ctx, tx, tr)
+	})
+	if err != nil {
+		return &influxdb.Error{
+			Op:  influxdb.OpPurgeTrashedResource,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteTrashedResource(ctx context.Context, tx Tx, tr *trashedResource) error {
+	key, err := trashKey(tr.Type, tr.ID)
+	if err != nil {
+		return err
+	}
+
+	b, err := tx.Bucket(trashBucket)
+	if err != nil {
+		return err
+	}
+	if err := b.Delete(key); err != nil {
+		return &influxdb.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
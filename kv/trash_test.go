@@ -0,0 +1,122 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	influxdbtesting "github.com/influxdata/influxdb/testing"
+)
+
+func TestBoltTrashService(t *testing.T) {
+	influxdbtesting.TrashService(initBoltTrashService, t)
+}
+
+func TestInmemTrashService(t *testing.T) {
+	influxdbtesting.TrashService(initInmemTrashService, t)
+}
+
+func TestBoltTaskTrashService(t *testing.T) {
+	influxdbtesting.TaskTrashService(initBoltTaskTrashService, t)
+}
+
+func TestInmemTaskTrashService(t *testing.T) {
+	influxdbtesting.TaskTrashService(initInmemTaskTrashService, t)
+}
+
+func initBoltTrashService(f influxdbtesting.TrashFields, t *testing.T) (influxdbtesting.TrashServices, func()) {
+	return initBoltTrashStore(f, t)
+}
+
+func initInmemTrashService(f influxdbtesting.TrashFields, t *testing.T) (influxdbtesting.TrashServices, func()) {
+	return initInmemTrashStore(f, t)
+}
+
+func initBoltTaskTrashService(f influxdbtesting.TrashFields, t *testing.T) (influxdbtesting.TaskTrashServices, func()) {
+	return initBoltTrashStore(f, t)
+}
+
+func initInmemTaskTrashService(f influxdbtesting.TrashFields, t *testing.T) (influxdbtesting.TaskTrashServices, func()) {
+	return initInmemTrashStore(f, t)
+}
+
+func initBoltTrashStore(f influxdbtesting.TrashFields, t *testing.T) (*kv.Service, func()) {
+	s, closeBolt, err := NewTestBoltStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initTrashService(s, f, t), closeBolt
+}
+
+func initInmemTrashStore(f influxdbtesting.TrashFields, t *testing.T) (*kv.Service, func()) {
+	s, closeInmem, err := NewTestInmemStore()
+	if err != nil {
+		t.Fatalf("failed to create new kv store: %v", err)
+	}
+	return initTrashService(s, f, t), closeInmem
+}
+
+func initTrashService(s kv.Store, f influxdbtesting.TrashFields, t *testing.T) *kv.Service {
+	svc := kv.NewService(s, kv.ServiceConfig{TrashRetention: f.Retention})
+	svc.TimeGenerator = f.TimeGenerator
+	if f.TimeGenerator == nil {
+		svc.TimeGenerator = influxdb.RealTimeGenerator{}
+	}
+
+	ctx := context.Background()
+	if err := svc.Initialize(ctx); err != nil {
+		t.Fatalf("error initializing kv service: %v", err)
+	}
+	for _, u := range f.Users {
+		if err := svc.PutUser(ctx, u); err != nil {
+			t.Fatalf("failed to populate users: %v", err)
+		}
+	}
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations: %v", err)
+		}
+	}
+	for _, a := range f.Authorizations {
+		if err := svc.CreateAuthorization(ctx, a); err != nil {
+			t.Fatalf("failed to populate authorizations: %v", err)
+		}
+	}
+	for _, b := range f.TrashedBuckets {
+		if err := createWithID(svc, b.ID, func() error {
+			return svc.CreateBucket(ctx, b)
+		}); err != nil {
+			t.Fatalf("failed to populate trashed buckets: %v", err)
+		}
+		if err := svc.DeleteBucket(ctx, b.ID); err != nil {
+			t.Fatalf("failed to populate trashed buckets: %v", err)
+		}
+	}
+	for _, d := range f.TrashedDashboards {
+		if err := createWithID(svc, d.ID, func() error {
+			return svc.CreateDashboard(ctx, d)
+		}); err != nil {
+			t.Fatalf("failed to populate trashed dashboards: %v", err)
+		}
+		if err := svc.DeleteDashboard(ctx, d.ID); err != nil {
+			t.Fatalf("failed to populate trashed dashboards: %v", err)
+		}
+	}
+	for _, b := range f.Buckets {
+		if err := createWithID(svc, b.ID, func() error {
+			return svc.CreateBucket(ctx, b)
+		}); err != nil {
+			t.Fatalf("failed to populate buckets: %v", err)
+		}
+	}
+	for _, d := range f.Dashboards {
+		if err := createWithID(svc, d.ID, func() error {
+			return svc.CreateDashboard(ctx, d)
+		}); err != nil {
+			t.Fatalf("failed to populate dashboards: %v", err)
+		}
+	}
+
+	return svc
+}
@@ -138,6 +138,10 @@ func (s *Service) createUserResourceMapping(ctx context.Context, tx Tx, m *influ
 		return err
 	}
 
+	if err := s.validTeamMapping(ctx, tx, m); err != nil {
+		return err
+	}
+
 	v, err := json.Marshal(m)
 	if err != nil {
 		return ErrUnprocessableMapping(err)
@@ -180,6 +184,7 @@ func (s *Service) createOrgDependentMappings(ctx context.Context, tx Tx, m *infl
 			ResourceID:   b.ID,
 			UserType:     m.UserType,
 			UserID:       m.UserID,
+			MappingType:  m.MappingType,
 		}
 		if err := s.createUserResourceMapping(ctx, tx, m); err != nil {
 			return err
@@ -190,6 +195,22 @@ func (s *Service) createOrgDependentMappings(ctx context.Context, tx Tx, m *infl
 	return nil
 }
 
+// validTeamMapping returns an error if m maps anything but a user to a team,
+// or maps a team that does not exist.
+func (s *Service) validTeamMapping(ctx context.Context, tx Tx, m *influxdb.UserResourceMapping) error {
+	if m.ResourceType == influxdb.TeamsResourceType && m.MappingType != influxdb.UserMappingType {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Err:  influxdb.ErrInvalidTeamMapping,
+		}
+	}
+	if m.MappingType != influxdb.TeamMappingType {
+		return nil
+	}
+	_, err := s.findTeamByID(ctx, tx, m.UserID)
+	return err
+}
+
 func userResourceKey(m *influxdb.UserResourceMapping) ([]byte, error) {
 	encodedResourceID, err := m.ResourceID.Encode()
 	if err != nil {
@@ -255,29 +276,117 @@ func (s *Service) uniqueUserResourceMapping(ctx context.Context, tx Tx, m *influ
 // DeleteUserResourceMapping deletes a user resource mapping.
 func (s *Service) DeleteUserResourceMapping(ctx context.Context, resourceID influxdb.ID, userID influxdb.ID) error {
 	return s.kv.Update(ctx, func(tx Tx) error {
-		// TODO(goller): I don't think this find is needed as delete also finds.
-		m, err := s.findUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
-			ResourceID: resourceID,
-			UserID:     userID,
-		})
-		if err != nil {
+		return s.removeUserResourceMapping(ctx, tx, resourceID, userID)
+	})
+}
+
+// removeUserResourceMapping deletes the mapping of a user to a resource along with
+// the mappings that depend on it.
+func (s *Service) removeUserResourceMapping(ctx context.Context, tx Tx, resourceID influxdb.ID, userID influxdb.ID) error {
+	// TODO(goller): I don't think this find is needed as delete also finds.
+	m, err := s.findUserResourceMapping(ctx, tx, influxdb.UserResourceMappingFilter{
+		ResourceID: resourceID,
+		UserID:     userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	filter := influxdb.UserResourceMappingFilter{
+		ResourceID: resourceID,
+		UserID:     userID,
+	}
+	if err := s.deleteUserResourceMapping(ctx, tx, filter); err != nil {
+		return err
+	}
+
+	if m.ResourceType == influxdb.OrgsResourceType {
+		return s.deleteOrgDependentMappings(ctx, tx, m)
+	}
+
+	return nil
+}
+
+// ApplyUserResourceMappings applies ops in order within a single transaction.
+// Every op is checked before any is applied, since not every store rolls back
+// a failed transaction, and so that the results report all the ops that fail.
+func (s *Service) ApplyUserResourceMappings(ctx context.Context, ops []influxdb.UserResourceMappingOp) ([]influxdb.UserResourceMappingResult, error) {
+	rs := make([]influxdb.UserResourceMappingResult, len(ops))
+	err := s.kv.Update(ctx, func(tx Tx) error {
+		// mapped holds whether the earlier ops leave a user mapped to a resource, by key.
+		mapped := make(map[string]bool)
+		for i, op := range ops {
+			rs[i] = influxdb.UserResourceMappingResult{
+				UserResourceMappingOp: op,
+				Err:                   s.checkUserResourceMappingOp(ctx, tx, op, mapped),
+			}
+		}
+		if err := influxdb.ErrMappingOpsFailed(rs); err != nil {
 			return err
 		}
 
-		filter := influxdb.UserResourceMappingFilter{
-			ResourceID: resourceID,
-			UserID:     userID,
+		for i, op := range ops {
+			if err := s.applyUserResourceMappingOp(ctx, tx, op); err != nil {
+				rs[i].Err = err
+				return err
+			}
 		}
-		if err := s.deleteUserResourceMapping(ctx, tx, filter); err != nil {
+		return nil
+	})
+	return rs, err
+}
+
+func (s *Service) checkUserResourceMappingOp(ctx context.Context, tx Tx, op influxdb.UserResourceMappingOp, mapped map[string]bool) error {
+	if err := op.Validate(); err != nil {
+		return err
+	}
+
+	if op.Action == influxdb.AddMapping {
+		if err := s.validTeamMapping(ctx, tx, &op.UserResourceMapping); err != nil {
 			return err
 		}
+	}
+
+	key, err := userResourceKey(&op.UserResourceMapping)
+	if err != nil {
+		return err
+	}
 
-		if m.ResourceType == influxdb.OrgsResourceType {
-			return s.deleteOrgDependentMappings(ctx, tx, m)
+	exists, ok := mapped[string(key)]
+	if !ok {
+		b, err := tx.Bucket(urmBucket)
+		if err != nil {
+			return UnavailableURMServiceError(err)
 		}
 
-		return nil
-	})
+		_, err = b.Get(key)
+		if err != nil && !IsNotFound(err) {
+			return UnavailableURMServiceError(err)
+		}
+		exists = err == nil
+	}
+
+	switch {
+	case op.Action == influxdb.AddMapping && exists:
+		return &influxdb.Error{
+			Code: influxdb.EConflict,
+			Msg:  fmt.Sprintf("user %s is already mapped to the resource", op.UserID),
+		}
+	case op.Action == influxdb.RemoveMapping && !exists:
+		return ErrURMNotFound
+	}
+
+	mapped[string(key)] = op.Action == influxdb.AddMapping
+	return nil
+}
+
+func (s *Service) applyUserResourceMappingOp(ctx context.Context, tx Tx, op influxdb.UserResourceMappingOp) error {
+	if op.Action == influxdb.RemoveMapping {
+		return s.removeUserResourceMapping(ctx, tx, op.ResourceID, op.UserID)
+	}
+
+	m := op.UserResourceMapping
+	return s.createUserResourceMapping(ctx, tx, &m)
 }
 
 func (s *Service) deleteUserResourceMapping(ctx context.Context, tx Tx, filter influxdb.UserResourceMappingFilter) error {
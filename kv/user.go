@@ -220,6 +220,12 @@ func (s *Service) FindUsers(ctx context.Context, filter influxdb.UserFilter, opt
 		return nil, 0, err
 	}
 
+	if len(opt) > 0 {
+		influxdb.SortUsers(opt[0], us)
+		lo, hi := opt[0].Page(len(us))
+		us = us[lo:hi]
+	}
+
 	return us, len(us), nil
 }
 
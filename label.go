@@ -45,6 +45,13 @@ type LabelService interface {
 	DeleteLabelMapping(ctx context.Context, m *LabelMapping) error
 }
 
+// LabelMappingBatchService finds the labels of many resources at once.
+type LabelMappingBatchService interface {
+	// FindResourcesLabels returns the labels of each of the resources by the
+	// ID of the resource. Resources without labels have none.
+	FindResourcesLabels(ctx context.Context, resourceIDs []ID) (map[ID][]*Label, error)
+}
+
 // Label is a tag set on a resource, typically used for filtering on a UI.
 type Label struct {
 	ID         ID                `json:"id,omitempty"`
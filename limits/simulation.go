@@ -0,0 +1,129 @@
+// Package limits evaluates the usage of organizations against limits.
+package limits
+
+import (
+	"context"
+
+	"github.com/influxdata/influxdb"
+)
+
+// Services are the services the usage of organizations is read from. They are
+// used without authorization, which is checked when a simulation is requested.
+type Services struct {
+	OrganizationService influxdb.OrganizationService
+	BucketService       influxdb.BucketService
+
+	// BucketQuotaService reports the series and write rate of buckets. Its
+	// write rate only covers the writes of this process.
+	BucketQuotaService influxdb.BucketQuotaService
+
+	// RunningQueryService reports the queries of organizations that are running.
+	RunningQueryService influxdb.RunningQueryService
+}
+
+var _ influxdb.LimitsSimulationService = (*SimulationService)(nil)
+
+// SimulationService evaluates the current usage of every organization against
+// hypothetical limits.
+//
+// The usage is a snapshot: the write rate is the one of the last full second
+// and the concurrent queries are the ones running at the time of the simulation,
+// so a simulation should be repeated at the peaks of the usage.
+type SimulationService struct {
+	Services
+}
+
+// NewSimulationService returns a new SimulationService that reads the usage of organizations from s.
+func NewSimulationService(s Services) *SimulationService {
+	return &SimulationService{
+		Services: s,
+	}
+}
+
+// SimulateLimits returns the usage of every organization against the limits of sim.
+func (s *SimulationService) SimulateLimits(ctx context.Context, sim influxdb.LimitsSimulation) (*influxdb.LimitsSimulationResult, error) {
+	if err := sim.Valid(); err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpSimulateLimits,
+			Err: err,
+		}
+	}
+
+	orgs, _, err := s.OrganizationService.FindOrganizations(ctx, influxdb.OrganizationFilter{})
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpSimulateLimits,
+			Err: err,
+		}
+	}
+
+	queries, err := s.runningQueries(ctx)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpSimulateLimits,
+			Err: err,
+		}
+	}
+
+	res := &influxdb.LimitsSimulationResult{
+		Orgs: make([]*influxdb.OrgLimitsResult, 0, len(orgs)),
+	}
+	for _, o := range orgs {
+		usage, err := s.orgUsage(ctx, o.ID)
+		if err != nil {
+			return nil, &influxdb.Error{
+				Op:  influxdb.OpSimulateLimits,
+				Err: err,
+			}
+		}
+		usage.ConcurrentQueries = queries[o.ID]
+
+		limits := sim.OrgLimits(o.ID)
+		r := &influxdb.OrgLimitsResult{
+			OrgID:      o.ID,
+			OrgName:    o.Name,
+			Limits:     limits,
+			Usage:      usage,
+			Violations: usage.Violations(limits),
+		}
+		if len(r.Violations) > 0 {
+			res.Violating++
+		}
+		res.Orgs = append(res.Orgs, r)
+	}
+	return res, nil
+}
+
+// orgUsage returns the series and write rate of all buckets of an organization.
+func (s *SimulationService) orgUsage(ctx context.Context, orgID influxdb.ID) (influxdb.OrgUsage, error) {
+	var usage influxdb.OrgUsage
+	bs, _, err := s.BucketService.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &orgID})
+	if err != nil {
+		return usage, err
+	}
+	for _, b := range bs {
+		u, err := s.BucketQuotaService.FindBucketQuotaUsage(ctx, b)
+		if err != nil {
+			return usage, err
+		}
+		usage.Series += u.Series
+		usage.PointsPerSecond += u.PointsPerSecond
+	}
+	return usage, nil
+}
+
+// runningQueries returns the number of running queries of each organization.
+func (s *SimulationService) runningQueries(ctx context.Context) (map[influxdb.ID]int64, error) {
+	n := make(map[influxdb.ID]int64)
+	if s.RunningQueryService == nil {
+		return n, nil
+	}
+	qs, err := s.RunningQueryService.FindRunningQueries(ctx, influxdb.RunningQueryFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range qs {
+		n[q.OrganizationID]++
+	}
+	return n, nil
+}
@@ -0,0 +1,139 @@
+package limits_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/limits"
+	"github.com/influxdata/influxdb/mock"
+)
+
+// testServices returns services of two organizations: acme with two buckets and
+// a running query, and globex with a single bucket.
+func testServices() limits.Services {
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationsF = func(ctx context.Context, f influxdb.OrganizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Organization, int, error) {
+		return []*influxdb.Organization{{ID: 1, Name: "acme"}, {ID: 2, Name: "globex"}}, 2, nil
+	}
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketsFn = func(ctx context.Context, f influxdb.BucketFilter, opt ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		if *f.OrganizationID == 1 {
+			return []*influxdb.Bucket{{ID: 10, OrgID: 1}, {ID: 11, OrgID: 1}}, 2, nil
+		}
+		return []*influxdb.Bucket{{ID: 20, OrgID: 2}}, 1, nil
+	}
+
+	quotas := mock.NewBucketQuotaService()
+	quotas.FindBucketQuotaUsageFn = func(ctx context.Context, b *influxdb.Bucket) (*influxdb.BucketQuotaUsage, error) {
+		usage := map[influxdb.ID]influxdb.BucketQuotaUsage{
+			10: {Series: 600, PointsPerSecond: 100},
+			11: {Series: 500, PointsPerSecond: 50},
+			20: {Series: 200, PointsPerSecond: 1000},
+		}[b.ID]
+		return &usage, nil
+	}
+
+	queries := mock.NewRunningQueryService()
+	queries.FindRunningQueriesFn = func(ctx context.Context, f influxdb.RunningQueryFilter) ([]*influxdb.RunningQuery, error) {
+		return []*influxdb.RunningQuery{{ID: 1, OrganizationID: 1}, {ID: 2, OrganizationID: 1}}, nil
+	}
+
+	return limits.Services{
+		OrganizationService: orgs,
+		BucketService:       buckets,
+		BucketQuotaService:  quotas,
+		RunningQueryService: queries,
+	}
+}
+
+func TestSimulationService_SimulateLimits(t *testing.T) {
+	s := limits.NewSimulationService(testServices())
+
+	got, err := s.SimulateLimits(context.Background(), influxdb.LimitsSimulation{
+		Limits: influxdb.OrgLimits{MaxSeries: 1000, MaxConcurrentQueries: 1},
+		Overrides: []influxdb.OrgLimitsOverride{
+			{OrgID: 2, Limits: influxdb.OrgLimits{MaxPointsPerSecond: 500}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &influxdb.LimitsSimulationResult{
+		Orgs: []*influxdb.OrgLimitsResult{
+			{
+				OrgID:   1,
+				OrgName: "acme",
+				Limits:  influxdb.OrgLimits{MaxSeries: 1000, MaxConcurrentQueries: 1},
+				Usage:   influxdb.OrgUsage{Series: 1100, PointsPerSecond: 150, ConcurrentQueries: 2},
+				Violations: []influxdb.LimitViolation{
+					{Limit: influxdb.LimitMaxSeries, Max: 1000, Usage: 1100},
+					{Limit: influxdb.LimitMaxConcurrentQueries, Max: 1, Usage: 2},
+				},
+			},
+			{
+				OrgID:   2,
+				OrgName: "globex",
+				Limits:  influxdb.OrgLimits{MaxPointsPerSecond: 500},
+				Usage:   influxdb.OrgUsage{Series: 200, PointsPerSecond: 1000},
+				Violations: []influxdb.LimitViolation{
+					{Limit: influxdb.LimitMaxPointsPerSecond, Max: 500, Usage: 1000},
+				},
+			},
+		},
+		Violating: 2,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected simulation -want/+got:\n%s", diff)
+	}
+}
+
+func TestSimulationService_SimulateLimits_NoViolations(t *testing.T) {
+	s := limits.NewSimulationService(testServices())
+
+	got, err := s.SimulateLimits(context.Background(), influxdb.LimitsSimulation{
+		Limits: influxdb.OrgLimits{MaxSeries: 2000, MaxPointsPerSecond: 1000, MaxConcurrentQueries: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Violating != 0 {
+		t.Errorf("expected limits at the usage not to be violated, got %d violating orgs", got.Violating)
+	}
+	for _, o := range got.Orgs {
+		if len(o.Violations) != 0 {
+			t.Errorf("unexpected violations of %s: %+v", o.OrgName, o.Violations)
+		}
+	}
+}
+
+func TestSimulationService_SimulateLimits_Invalid(t *testing.T) {
+	s := limits.NewSimulationService(testServices())
+
+	tests := []struct {
+		name string
+		sim  influxdb.LimitsSimulation
+	}{
+		{
+			name: "negative limit",
+			sim:  influxdb.LimitsSimulation{Limits: influxdb.OrgLimits{MaxSeries: -1}},
+		},
+		{
+			name: "org overridden twice",
+			sim: influxdb.LimitsSimulation{Overrides: []influxdb.OrgLimitsOverride{
+				{OrgID: 1, Limits: influxdb.OrgLimits{MaxSeries: 1}},
+				{OrgID: 1, Limits: influxdb.OrgLimits{MaxSeries: 2}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.SimulateLimits(context.Background(), tt.sim); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package influxdb
+
+import "context"
+
+// OpSimulateLimits is the operation of a limits simulation.
+const OpSimulateLimits = "SimulateLimits"
+
+// Names of the limits of an organization, as reported in its violations.
+const (
+	LimitMaxSeries            = "maxSeries"
+	LimitMaxPointsPerSecond   = "maxPointsPerSecond"
+	LimitMaxConcurrentQueries = "maxConcurrentQueries"
+)
+
+// OrgLimits are limits of the usage of an organization across all of its
+// buckets. A limit of zero is no limit.
+type OrgLimits struct {
+	// MaxSeries is the number of series of all buckets of the organization.
+	MaxSeries int64 `json:"maxSeries,omitempty"`
+	// MaxPointsPerSecond is the rate of points written to all buckets of the organization.
+	MaxPointsPerSecond int64 `json:"maxPointsPerSecond,omitempty"`
+	// MaxConcurrentQueries is the number of queries of the organization that run at once.
+	MaxConcurrentQueries int64 `json:"maxConcurrentQueries,omitempty"`
+}
+
+// Valid returns an error if a limit is negative.
+func (l OrgLimits) Valid() error {
+	if l.MaxSeries < 0 || l.MaxPointsPerSecond < 0 || l.MaxConcurrentQueries < 0 {
+		return &Error{
+			Code: EUnprocessableEntity,
+			Msg:  "limits must not be negative",
+		}
+	}
+	return nil
+}
+
+// OrgLimitsOverride replaces the default limits of a simulation for a single organization.
+type OrgLimitsOverride struct {
+	OrgID  ID        `json:"orgID"`
+	Limits OrgLimits `json:"limits"`
+}
+
+// LimitsSimulation are hypothetical limits to evaluate the current usage of
+// organizations against.
+type LimitsSimulation struct {
+	// Limits apply to every organization without an override.
+	Limits    OrgLimits           `json:"limits"`
+	Overrides []OrgLimitsOverride `json:"overrides,omitempty"`
+}
+
+// Valid returns an error if a limit is negative or an organization is overridden twice.
+func (s LimitsSimulation) Valid() error {
+	if err := s.Limits.Valid(); err != nil {
+		return err
+	}
+	seen := make(map[ID]bool, len(s.Overrides))
+	for _, o := range s.Overrides {
+		if !o.OrgID.Valid() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "override must have a valid orgID",
+			}
+		}
+		if seen[o.OrgID] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "organization " + o.OrgID.String() + " is overridden more than once",
+			}
+		}
+		seen[o.OrgID] = true
+		if err := o.Limits.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OrgLimits returns the limits of the simulation for an organization.
+func (s LimitsSimulation) OrgLimits(orgID ID) OrgLimits {
+	for _, o := range s.Overrides {
+		if o.OrgID == orgID {
+			return o.Limits
+		}
+	}
+	return s.Limits
+}
+
+// OrgUsage is the current usage of an organization across all of its buckets.
+type OrgUsage struct {
+	// Series is the number of series of the buckets of the organization.
+	Series int64 `json:"series"`
+	// PointsPerSecond is the number of points written in the last full second.
+	PointsPerSecond int64 `json:"pointsPerSecond"`
+	// ConcurrentQueries is the number of queries of the organization that are running.
+	ConcurrentQueries int64 `json:"concurrentQueries"`
+}
+
+// LimitViolation is a limit that the usage of an organization is over.
+type LimitViolation struct {
+	// Limit is the name of the limit, such as maxSeries.
+	Limit string `json:"limit"`
+	Max   int64  `json:"max"`
+	Usage int64  `json:"usage"`
+}
+
+// Violations returns the limits of l that usage u is over.
+func (u OrgUsage) Violations(l OrgLimits) []LimitViolation {
+	vs := []LimitViolation{}
+	check := func(limit string, max, usage int64) {
+		if max > 0 && usage > max {
+			vs = append(vs, LimitViolation{Limit: limit, Max: max, Usage: usage})
+		}
+	}
+	check(LimitMaxSeries, l.MaxSeries, u.Series)
+	check(LimitMaxPointsPerSecond, l.MaxPointsPerSecond, u.PointsPerSecond)
+	check(LimitMaxConcurrentQueries, l.MaxConcurrentQueries, u.ConcurrentQueries)
+	return vs
+}
+
+// OrgLimitsResult is the usage of an organization against the limits of a simulation.
+type OrgLimitsResult struct {
+	OrgID      ID               `json:"orgID"`
+	OrgName    string           `json:"orgName"`
+	Limits     OrgLimits        `json:"limits"`
+	Usage      OrgUsage         `json:"usage"`
+	Violations []LimitViolation `json:"violations"`
+}
+
+// LimitsSimulationResult is the usage of every organization against the limits of a simulation.
+type LimitsSimulationResult struct {
+	Orgs []*OrgLimitsResult `json:"orgs"`
+	// Violating is the number of organizations that are over a limit.
+	Violating int `json:"violating"`
+}
+
+// LimitsSimulationService evaluates the current usage of organizations against
+// hypothetical limits, so that limits can be rolled out without surprising the
+// organizations that would be over them.
+type LimitsSimulationService interface {
+	// SimulateLimits returns the usage of every organization against the limits of s.
+	// Nothing is enforced.
+	SimulateLimits(ctx context.Context, s LimitsSimulation) (*LimitsSimulationResult, error)
+}
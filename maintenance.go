@@ -0,0 +1,262 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultWritesDisabledMessage is returned by the write path while it is disabled without a custom message.
+	DefaultWritesDisabledMessage = "writes are disabled for maintenance"
+	// DefaultQueriesDisabledMessage is returned by the query path while it is disabled without a custom message.
+	DefaultQueriesDisabledMessage = "queries are disabled for maintenance"
+)
+
+// MaintenanceToggle describes whether a request path is disabled and
+// the message returned to clients while it is.
+type MaintenanceToggle struct {
+	Disabled bool   `json:"disabled"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Err returns the error clients receive while the path is disabled, or nil if it is enabled.
+// The default message is used when no custom message was set.
+func (t MaintenanceToggle) Err(op, defaultMsg string) error {
+	if !t.Disabled {
+		return nil
+	}
+	msg := t.Message
+	if msg == "" {
+		msg = defaultMsg
+	}
+	return &Error{
+		Code: EUnavailable,
+		Op:   op,
+		Msg:  msg,
+	}
+}
+
+// MaintenanceStatus is the maintenance state of the request paths that can be
+// disabled independently of the metadata APIs.
+type MaintenanceStatus struct {
+	Writes  MaintenanceToggle `json:"writes"`
+	Queries MaintenanceToggle `json:"queries"`
+}
+
+// WritesErr returns the error write requests receive, or nil if writes are enabled.
+func (s MaintenanceStatus) WritesErr() error {
+	return s.Writes.Err("http/write", DefaultWritesDisabledMessage)
+}
+
+// QueriesErr returns the error query requests receive, or nil if queries are enabled.
+func (s MaintenanceStatus) QueriesErr() error {
+	return s.Queries.Err("http/query", DefaultQueriesDisabledMessage)
+}
+
+// MaintenanceStatusUpdate is the set of changes to the maintenance state.
+// Nil fields are left unchanged.
+type MaintenanceStatusUpdate struct {
+	Writes  *MaintenanceToggle `json:"writes,omitempty"`
+	Queries *MaintenanceToggle `json:"queries,omitempty"`
+}
+
+// Valid returns an error if the update is empty.
+func (u MaintenanceStatusUpdate) Valid() error {
+	if u.Writes == nil && u.Queries == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "maintenance update must change writes or queries",
+		}
+	}
+	return nil
+}
+
+// Apply applies the update to the status.
+func (u MaintenanceStatusUpdate) Apply(s *MaintenanceStatus) {
+	if u.Writes != nil {
+		s.Writes = *u.Writes
+	}
+	if u.Queries != nil {
+		s.Queries = *u.Queries
+	}
+}
+
+// ops for maintenance.
+const (
+	OpMaintenanceStatus       = "MaintenanceStatus"
+	OpUpdateMaintenanceStatus = "UpdateMaintenanceStatus"
+)
+
+// MaintenanceService controls which request paths are disabled for maintenance.
+type MaintenanceService interface {
+	// MaintenanceStatus returns the current maintenance state.
+	MaintenanceStatus(ctx context.Context) (*MaintenanceStatus, error)
+
+	// UpdateMaintenanceStatus updates the maintenance state and returns the new state.
+	UpdateMaintenanceStatus(ctx context.Context, upd MaintenanceStatusUpdate) (*MaintenanceStatus, error)
+}
+
+// MaintenanceWindow is a daily period, in UTC, in which heavy background work of
+// the storage engine, such as full compactions, retention sweeps and series index
+// rebuilds, may run. A window whose end is before its start spans midnight.
+type MaintenanceWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// maintenanceWindowLayout is the layout of the start and end of maintenance windows.
+const maintenanceWindowLayout = "15:04"
+
+// ParseMaintenanceWindow parses a window in the form 01:00-05:00.
+func ParseMaintenanceWindow(s string) (MaintenanceWindow, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return MaintenanceWindow{}, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid maintenance window %q; windows are of the form 01:00-05:00", s),
+		}
+	}
+
+	w := MaintenanceWindow{
+		Start: strings.TrimSpace(parts[0]),
+		End:   strings.TrimSpace(parts[1]),
+	}
+	if err := w.Valid(); err != nil {
+		return MaintenanceWindow{}, err
+	}
+	return w, nil
+}
+
+// Valid returns an error if the start or end of the window is not a time of day.
+func (w MaintenanceWindow) Valid() error {
+	for _, t := range []string{w.Start, w.End} {
+		if _, err := time.Parse(maintenanceWindowLayout, t); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid maintenance window time %q; times are of the form 15:04", t),
+			}
+		}
+	}
+	if w.Start == w.End {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "maintenance window must not be empty",
+		}
+	}
+	return nil
+}
+
+// Contains reports whether t is within the window.
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	start, err := time.Parse(maintenanceWindowLayout, w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(maintenanceWindowLayout, w.End)
+	if err != nil {
+		return false
+	}
+
+	t = t.UTC()
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	from := time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	to := time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute
+	if from < to {
+		return from <= now && now < to
+	}
+	return now >= from || now < to
+}
+
+// String returns the window in the form ParseMaintenanceWindow parses.
+func (w MaintenanceWindow) String() string {
+	return w.Start + "-" + w.End
+}
+
+// MaintenanceOverride overrides the maintenance windows of an instance.
+type MaintenanceOverride string
+
+const (
+	// MaintenanceOverrideNone confines heavy background work to the maintenance windows.
+	MaintenanceOverrideNone MaintenanceOverride = ""
+	// MaintenanceOverrideAllow lets heavy background work run outside of the maintenance windows.
+	MaintenanceOverrideAllow MaintenanceOverride = "allow"
+	// MaintenanceOverrideDeny keeps heavy background work from running within the maintenance windows.
+	MaintenanceOverrideDeny MaintenanceOverride = "deny"
+)
+
+// MaintenanceSchedule is the schedule of the heavy background work of an instance.
+type MaintenanceSchedule struct {
+	// Windows are the configured maintenance windows. Without windows,
+	// heavy background work may run at any time.
+	Windows []MaintenanceWindow `json:"windows"`
+
+	// Override overrides the windows until OverrideUntil, or until it is
+	// cleared if OverrideUntil is nil.
+	Override      MaintenanceOverride `json:"override,omitempty"`
+	OverrideUntil *time.Time          `json:"overrideUntil,omitempty"`
+
+	// Open reports whether heavy background work may run now.
+	Open bool `json:"open"`
+}
+
+// IsOpen reports whether heavy background work may run at t.
+func (s MaintenanceSchedule) IsOpen(t time.Time) bool {
+	if s.Override != MaintenanceOverrideNone && (s.OverrideUntil == nil || t.Before(*s.OverrideUntil)) {
+		return s.Override == MaintenanceOverrideAllow
+	}
+
+	if len(s.Windows) == 0 {
+		return true
+	}
+	for _, w := range s.Windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenanceScheduleUpdate overrides the maintenance windows. An update with no
+// override clears the override.
+type MaintenanceScheduleUpdate struct {
+	Override MaintenanceOverride `json:"override"`
+	Until    *time.Time          `json:"until,omitempty"`
+}
+
+// Valid returns an error if the override is unknown or is cleared until a time.
+func (u MaintenanceScheduleUpdate) Valid() error {
+	switch u.Override {
+	case MaintenanceOverrideNone:
+		if u.Until != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "until requires an override",
+			}
+		}
+	case MaintenanceOverrideAllow, MaintenanceOverrideDeny:
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid maintenance override %q; valid overrides are allow and deny", u.Override),
+		}
+	}
+	return nil
+}
+
+// ops for maintenance schedules.
+const (
+	OpMaintenanceSchedule       = "MaintenanceSchedule"
+	OpUpdateMaintenanceSchedule = "UpdateMaintenanceSchedule"
+)
+
+// MaintenanceScheduleService confines the heavy background work of an instance
+// to its maintenance windows, which can be overridden.
+type MaintenanceScheduleService interface {
+	// MaintenanceSchedule returns the current maintenance schedule.
+	MaintenanceSchedule(ctx context.Context) (*MaintenanceSchedule, error)
+
+	// UpdateMaintenanceSchedule overrides the maintenance windows and returns the new schedule.
+	UpdateMaintenanceSchedule(ctx context.Context, upd MaintenanceScheduleUpdate) (*MaintenanceSchedule, error)
+}
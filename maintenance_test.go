@@ -0,0 +1,135 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestParseMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    influxdb.MaintenanceWindow
+		wantErr bool
+	}{
+		{s: "01:00-05:00", want: influxdb.MaintenanceWindow{Start: "01:00", End: "05:00"}},
+		{s: "22:30 - 02:00", want: influxdb.MaintenanceWindow{Start: "22:30", End: "02:00"}},
+		{s: "01:00", wantErr: true},
+		{s: "1am-5am", wantErr: true},
+		{s: "25:00-05:00", wantErr: true},
+		{s: "03:00-03:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			w, err := influxdb.ParseMaintenanceWindow(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %t", err, tt.wantErr)
+			}
+			if w != tt.want {
+				t.Errorf("got window %+v, want %+v", w, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceSchedule_IsOpen(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2019, 6, 1, hour, min, 0, 0, time.UTC)
+	}
+	later := at(12, 0)
+
+	tests := []struct {
+		name     string
+		schedule influxdb.MaintenanceSchedule
+		t        time.Time
+		want     bool
+	}{
+		{
+			name: "no windows",
+			t:    at(12, 0),
+			want: true,
+		},
+		{
+			name:     "within window",
+			schedule: influxdb.MaintenanceSchedule{Windows: []influxdb.MaintenanceWindow{{Start: "01:00", End: "05:00"}}},
+			t:        at(1, 0),
+			want:     true,
+		},
+		{
+			name:     "end of window",
+			schedule: influxdb.MaintenanceSchedule{Windows: []influxdb.MaintenanceWindow{{Start: "01:00", End: "05:00"}}},
+			t:        at(5, 0),
+			want:     false,
+		},
+		{
+			name:     "window spanning midnight",
+			schedule: influxdb.MaintenanceSchedule{Windows: []influxdb.MaintenanceWindow{{Start: "22:00", End: "02:00"}}},
+			t:        at(23, 30),
+			want:     true,
+		},
+		{
+			name:     "after window spanning midnight",
+			schedule: influxdb.MaintenanceSchedule{Windows: []influxdb.MaintenanceWindow{{Start: "22:00", End: "02:00"}}},
+			t:        at(2, 30),
+			want:     false,
+		},
+		{
+			name:     "time in another zone",
+			schedule: influxdb.MaintenanceSchedule{Windows: []influxdb.MaintenanceWindow{{Start: "01:00", End: "05:00"}}},
+			t:        time.Date(2019, 6, 1, 4, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			want:     true,
+		},
+		{
+			name: "allowed outside of windows",
+			schedule: influxdb.MaintenanceSchedule{
+				Windows:  []influxdb.MaintenanceWindow{{Start: "01:00", End: "05:00"}},
+				Override: influxdb.MaintenanceOverrideAllow,
+			},
+			t:    at(10, 0),
+			want: true,
+		},
+		{
+			name: "denied within windows",
+			schedule: influxdb.MaintenanceSchedule{
+				Windows:  []influxdb.MaintenanceWindow{{Start: "01:00", End: "05:00"}},
+				Override: influxdb.MaintenanceOverrideDeny,
+			},
+			t:    at(2, 0),
+			want: false,
+		},
+		{
+			name: "expired override",
+			schedule: influxdb.MaintenanceSchedule{
+				Windows:       []influxdb.MaintenanceWindow{{Start: "01:00", End: "05:00"}},
+				Override:      influxdb.MaintenanceOverrideAllow,
+				OverrideUntil: &later,
+			},
+			t:    at(13, 0),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.IsOpen(tt.t); got != tt.want {
+				t.Errorf("got open %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceScheduleUpdate_Valid(t *testing.T) {
+	until := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := (influxdb.MaintenanceScheduleUpdate{Override: influxdb.MaintenanceOverrideAllow, Until: &until}).Valid(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (influxdb.MaintenanceScheduleUpdate{Override: "sometimes"}).Valid(); err == nil {
+		t.Error("expected an error for an unknown override")
+	}
+	if err := (influxdb.MaintenanceScheduleUpdate{Until: &until}).Valid(); err == nil {
+		t.Error("expected an error for clearing an override until a time")
+	}
+}
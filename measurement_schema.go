@@ -0,0 +1,258 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// BucketSchemaType is how the schemas of the measurements of a bucket are defined.
+type BucketSchemaType string
+
+const (
+	// BucketSchemaTypeImplicit buckets take the schemas of their measurements
+	// from the points written to them. It is the schema type of buckets without one.
+	BucketSchemaTypeImplicit BucketSchemaType = "implicit"
+	// BucketSchemaTypeExplicit buckets only accept points of the measurements
+	// that have a MeasurementSchema, with the tags and fields of their schemas.
+	BucketSchemaTypeExplicit BucketSchemaType = "explicit"
+)
+
+// Valid returns an error if the schema type is not known.
+func (t BucketSchemaType) Valid() error {
+	switch t {
+	case "", BucketSchemaTypeImplicit, BucketSchemaTypeExplicit:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("schema type must be %q or %q", BucketSchemaTypeImplicit, BucketSchemaTypeExplicit),
+	}
+}
+
+// Explicit returns true if points must match the measurement schemas of the bucket.
+func (t BucketSchemaType) Explicit() bool {
+	return t == BucketSchemaTypeExplicit
+}
+
+// ErrMeasurementSchemaNotFound is the error msg for a missing measurement schema.
+const ErrMeasurementSchemaNotFound = "measurement schema not found"
+
+// ops for measurement schema error.
+const (
+	OpFindMeasurementSchemaByID = "FindMeasurementSchemaByID"
+	OpFindMeasurementSchemas    = "FindMeasurementSchemas"
+	OpCreateMeasurementSchema   = "CreateMeasurementSchema"
+	OpUpdateMeasurementSchema   = "UpdateMeasurementSchema"
+)
+
+// MeasurementSchemaService describes a service for managing the schemas of
+// the measurements of buckets with an explicit schema type.
+type MeasurementSchemaService interface {
+	// FindMeasurementSchemaByID finds a single measurement schema of a bucket by its ID.
+	FindMeasurementSchemaByID(ctx context.Context, bucketID, id ID) (*MeasurementSchema, error)
+
+	// FindMeasurementSchemas returns the measurement schemas of a bucket that match the filter.
+	FindMeasurementSchemas(ctx context.Context, filter MeasurementSchemaFilter) ([]*MeasurementSchema, error)
+
+	// CreateMeasurementSchema creates a measurement schema and assigns it an ID.
+	CreateMeasurementSchema(ctx context.Context, m *MeasurementSchema) error
+
+	// UpdateMeasurementSchema updates the columns of a measurement schema.
+	// Columns can only be added, since points of the existing columns may have been written.
+	UpdateMeasurementSchema(ctx context.Context, bucketID, id ID, upd MeasurementSchemaUpdate) (*MeasurementSchema, error)
+}
+
+// SemanticColumnType is the role of a column of a measurement.
+type SemanticColumnType string
+
+// Semantic column types.
+const (
+	SemanticColumnTypeTimestamp SemanticColumnType = "timestamp"
+	SemanticColumnTypeTag       SemanticColumnType = "tag"
+	SemanticColumnTypeField     SemanticColumnType = "field"
+)
+
+// SchemaColumnDataType is the data type of a field column of a measurement.
+type SchemaColumnDataType string
+
+// Data types of field columns.
+const (
+	SchemaColumnDataTypeFloat    SchemaColumnDataType = "float"
+	SchemaColumnDataTypeInteger  SchemaColumnDataType = "integer"
+	SchemaColumnDataTypeUnsigned SchemaColumnDataType = "unsigned"
+	SchemaColumnDataTypeString   SchemaColumnDataType = "string"
+	SchemaColumnDataTypeBoolean  SchemaColumnDataType = "boolean"
+)
+
+// MeasurementSchemaColumn is a column of a measurement: its timestamp, a tag or a field.
+type MeasurementSchemaColumn struct {
+	Name string             `json:"name"`
+	Type SemanticColumnType `json:"type"`
+	// DataType is the data type of a field. Tags are strings and the timestamp
+	// is a time, so they have no data type.
+	DataType SchemaColumnDataType `json:"dataType,omitempty"`
+}
+
+// MeasurementSchema declares the tags and fields of a measurement of a bucket with
+// an explicit schema type. Points with other tags or fields, or fields of other
+// types, are rejected, so a field can not end up with conflicting types.
+type MeasurementSchema struct {
+	ID       ID     `json:"id,omitempty"`
+	OrgID    ID     `json:"orgID,omitempty"`
+	BucketID ID     `json:"bucketID,omitempty"`
+	Name     string `json:"name"`
+	// Columns are the timestamp, which is named "time", and the tags and fields of the measurement.
+	Columns []MeasurementSchemaColumn `json:"columns"`
+	CRUDLog
+}
+
+// Valid returns an error if the measurement schema can't be created.
+func (m *MeasurementSchema) Valid() error {
+	if m.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "name is required",
+		}
+	}
+	if strings.HasPrefix(m.Name, "_") {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "name must not start with an underscore",
+		}
+	}
+	return validMeasurementSchemaColumns(m.Columns)
+}
+
+func validMeasurementSchemaColumns(columns []MeasurementSchemaColumn) error {
+	names := make(map[string]bool, len(columns))
+	var timestamps, fields int
+	for _, c := range columns {
+		if c.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "column name is required",
+			}
+		}
+		if names[c.Name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("column %q is declared more than once", c.Name),
+			}
+		}
+		names[c.Name] = true
+
+		switch c.Type {
+		case SemanticColumnTypeTimestamp:
+			timestamps++
+			if c.Name != "time" {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("timestamp column must be named \"time\", not %q", c.Name),
+				}
+			}
+		case SemanticColumnTypeTag, SemanticColumnTypeField:
+			if c.Name == "time" || strings.HasPrefix(c.Name, "_") {
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("%s column name %q is reserved", c.Type, c.Name),
+				}
+			}
+			if c.Type == SemanticColumnTypeField {
+				fields++
+			}
+		default:
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("column %q must be of type %q, %q or %q", c.Name, SemanticColumnTypeTimestamp, SemanticColumnTypeTag, SemanticColumnTypeField),
+			}
+		}
+
+		if err := validSchemaColumnDataType(c); err != nil {
+			return err
+		}
+	}
+
+	if timestamps != 1 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "schema must have one timestamp column",
+		}
+	}
+	if fields == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "schema must have at least one field column",
+		}
+	}
+	return nil
+}
+
+func validSchemaColumnDataType(c MeasurementSchemaColumn) error {
+	if c.Type != SemanticColumnTypeField {
+		if c.DataType != "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("%s column %q must not have a data type", c.Type, c.Name),
+			}
+		}
+		return nil
+	}
+	switch c.DataType {
+	case SchemaColumnDataTypeFloat, SchemaColumnDataTypeInteger, SchemaColumnDataTypeUnsigned,
+		SchemaColumnDataTypeString, SchemaColumnDataTypeBoolean:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("field column %q must have a data type of float, integer, unsigned, string or boolean", c.Name),
+	}
+}
+
+// Column returns the column with the name, and false if the schema has none.
+func (m *MeasurementSchema) Column(name string) (MeasurementSchemaColumn, bool) {
+	for _, c := range m.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return MeasurementSchemaColumn{}, false
+}
+
+// MeasurementSchemaUpdate is the columns a measurement schema is updated to.
+type MeasurementSchemaUpdate struct {
+	Columns []MeasurementSchemaColumn `json:"columns"`
+}
+
+// Apply updates the columns of m. It returns an error if the update removes
+// or changes a column of m, or if the updated columns are not valid.
+func (upd MeasurementSchemaUpdate) Apply(m *MeasurementSchema) error {
+	updated := &MeasurementSchema{Columns: upd.Columns}
+	for _, c := range m.Columns {
+		u, ok := updated.Column(c.Name)
+		if !ok {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("column %q can not be removed", c.Name),
+			}
+		}
+		if u != c {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("column %q can not be changed", c.Name),
+			}
+		}
+	}
+	if err := validMeasurementSchemaColumns(upd.Columns); err != nil {
+		return err
+	}
+	m.Columns = upd.Columns
+	return nil
+}
+
+// MeasurementSchemaFilter represents a set of filters that restrict the returned measurement schemas.
+type MeasurementSchemaFilter struct {
+	// BucketID is the bucket of the schemas; it is required.
+	BucketID ID
+	Name     *string
+}
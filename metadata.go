@@ -0,0 +1,134 @@
+package influxdb
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits of the metadata of a resource.
+const (
+	MaxMetadataKeys        = 32
+	MaxMetadataKeyLength   = 128
+	MaxMetadataValueLength = 256
+)
+
+var metadataKeyRE = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+
+// Metadata is free-form key/value metadata of a resource, so integrations can
+// stamp a resource with the identifiers of external systems, such as a ticket
+// number or a cost center. Unlike labels, metadata belongs to a single resource.
+type Metadata map[string]string
+
+// Valid returns an error if the metadata has too many keys, or a key or value
+// that is too long or a key that has characters other than letters, digits
+// and "_", ".", "-" or "/".
+func (m Metadata) Valid() error {
+	if len(m) > MaxMetadataKeys {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("metadata can have at most %d keys", MaxMetadataKeys),
+		}
+	}
+	for k, v := range m {
+		if err := validMetadataKey(k); err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(v) > MaxMetadataValueLength {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("metadata value of %q is longer than %d characters", k, MaxMetadataValueLength),
+			}
+		}
+	}
+	return nil
+}
+
+func validMetadataKey(k string) error {
+	if !metadataKeyRE.MatchString(k) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("metadata key %q must be letters, digits, \"_\", \".\", \"-\" or \"/\"", k),
+		}
+	}
+	if len(k) > MaxMetadataKeyLength {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("metadata key %q is longer than %d characters", k, MaxMetadataKeyLength),
+		}
+	}
+	return nil
+}
+
+// Matches returns true if the metadata holds every key of the filter with
+// the same value. Every metadata matches an empty filter.
+func (m Metadata) Matches(filter Metadata) bool {
+	for k, v := range filter {
+		if mv, ok := m[k]; !ok || mv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryParams returns the metadata as the values of a metadata query
+// parameter, as "key:value".
+func (m Metadata) QueryParams() []string {
+	ps := make([]string, 0, len(m))
+	for k, v := range m {
+		ps = append(ps, k+":"+v)
+	}
+	sort.Strings(ps)
+	return ps
+}
+
+// ParseMetadataQueryParams parses the values of a metadata query parameter,
+// "key:value", into metadata.
+func ParseMetadataQueryParams(ps []string) (Metadata, error) {
+	if len(ps) == 0 {
+		return nil, nil
+	}
+	m := make(Metadata, len(ps))
+	for _, p := range ps {
+		i := strings.IndexByte(p, ':')
+		if i < 0 {
+			return nil, &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("metadata filter %q must be key:value", p),
+			}
+		}
+		m[p[:i]] = p[i+1:]
+	}
+	if err := m.Valid(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MetadataUpdate sets the keys of metadata to its values and removes the keys
+// that are null.
+type MetadataUpdate map[string]*string
+
+// Apply returns a copy of the metadata with the update applied.
+func (u MetadataUpdate) Apply(m Metadata) (Metadata, error) {
+	n := make(Metadata, len(m)+len(u))
+	for k, v := range m {
+		n[k] = v
+	}
+	for k, v := range u {
+		if v == nil {
+			delete(n, k)
+			continue
+		}
+		n[k] = *v
+	}
+	if err := n.Valid(); err != nil {
+		return nil, err
+	}
+	if len(n) == 0 {
+		return nil, nil
+	}
+	return n, nil
+}
@@ -0,0 +1,97 @@
+package influxdb_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+func TestMetadata_Valid(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata platform.Metadata
+		valid    bool
+	}{
+		{name: "empty", valid: true},
+		{name: "valid", metadata: platform.Metadata{"jira/ticket": "OPS-1", "cost_center": "42"}, valid: true},
+		{name: "key with space", metadata: platform.Metadata{"cost center": "42"}},
+		{name: "empty key", metadata: platform.Metadata{"": "42"}},
+		{name: "long key", metadata: platform.Metadata{strings.Repeat("k", platform.MaxMetadataKeyLength+1): "v"}},
+		{name: "long value", metadata: platform.Metadata{"k": strings.Repeat("v", platform.MaxMetadataValueLength+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.metadata.Valid()
+			if tt.valid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.valid && platform.ErrorCode(err) != platform.EInvalid {
+				t.Fatalf("expected the metadata to be invalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMetadata_Matches(t *testing.T) {
+	m := platform.Metadata{"team": "ops", "ticket": "OPS-1"}
+	if !m.Matches(nil) {
+		t.Error("expected metadata to match an empty filter")
+	}
+	if !m.Matches(platform.Metadata{"team": "ops"}) {
+		t.Error("expected metadata to match a subset")
+	}
+	if m.Matches(platform.Metadata{"team": "dev"}) {
+		t.Error("expected metadata not to match a different value")
+	}
+	if m.Matches(platform.Metadata{"owner": "ops"}) {
+		t.Error("expected metadata not to match a missing key")
+	}
+}
+
+func TestParseMetadataQueryParams(t *testing.T) {
+	m, err := platform.ParseMetadataQueryParams([]string{"team:ops", "url:http://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (platform.Metadata{"team": "ops", "url": "http://example.com"}); !reflect.DeepEqual(m, want) {
+		t.Fatalf("got %v, want %v", m, want)
+	}
+	if got, want := m.QueryParams(), []string{"team:ops", "url:http://example.com"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got query params %v, want %v", got, want)
+	}
+
+	if _, err := platform.ParseMetadataQueryParams([]string{"team"}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected a param without a value to be invalid, got %v", err)
+	}
+}
+
+func TestMetadataUpdate_Apply(t *testing.T) {
+	ticket := "OPS-2"
+	m := platform.Metadata{"team": "ops", "ticket": "OPS-1"}
+
+	n, err := platform.MetadataUpdate{"ticket": &ticket, "team": nil}.Apply(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (platform.Metadata{"ticket": "OPS-2"}); !reflect.DeepEqual(n, want) {
+		t.Fatalf("got %v, want %v", n, want)
+	}
+	if m["team"] != "ops" || m["ticket"] != "OPS-1" {
+		t.Fatalf("expected the metadata not to be modified, got %v", m)
+	}
+
+	n, err = platform.MetadataUpdate{"team": nil, "ticket": nil}.Apply(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != nil {
+		t.Fatalf("expected removing every key to leave no metadata, got %v", n)
+	}
+
+	bad := "x"
+	if _, err := (platform.MetadataUpdate{"bad key": &bad}).Apply(m); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected an invalid key to be rejected, got %v", err)
+	}
+}
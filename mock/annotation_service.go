@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.AnnotationService = (*AnnotationService)(nil)
+
+// AnnotationService is a mock implementation of platform.AnnotationService.
+type AnnotationService struct {
+	FindAnnotationByIDFn func(context.Context, platform.ID) (*platform.Annotation, error)
+	FindAnnotationsFn    func(context.Context, platform.AnnotationFilter, ...platform.FindOptions) ([]*platform.Annotation, int, error)
+	CreateAnnotationsFn  func(context.Context, []*platform.Annotation) error
+	UpdateAnnotationFn   func(context.Context, platform.ID, platform.AnnotationUpdate) (*platform.Annotation, error)
+	DeleteAnnotationFn   func(context.Context, platform.ID) error
+}
+
+// NewAnnotationService returns a mock of AnnotationService where its methods will return zero values.
+func NewAnnotationService() *AnnotationService {
+	return &AnnotationService{
+		FindAnnotationByIDFn: func(context.Context, platform.ID) (*platform.Annotation, error) { return nil, nil },
+		FindAnnotationsFn: func(context.Context, platform.AnnotationFilter, ...platform.FindOptions) ([]*platform.Annotation, int, error) {
+			return nil, 0, nil
+		},
+		CreateAnnotationsFn: func(context.Context, []*platform.Annotation) error { return nil },
+		UpdateAnnotationFn: func(context.Context, platform.ID, platform.AnnotationUpdate) (*platform.Annotation, error) {
+			return nil, nil
+		},
+		DeleteAnnotationFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindAnnotationByID returns a single annotation by ID.
+func (s *AnnotationService) FindAnnotationByID(ctx context.Context, id platform.ID) (*platform.Annotation, error) {
+	return s.FindAnnotationByIDFn(ctx, id)
+}
+
+// FindAnnotations returns the annotations that match the filter.
+func (s *AnnotationService) FindAnnotations(ctx context.Context, filter platform.AnnotationFilter, opt ...platform.FindOptions) ([]*platform.Annotation, int, error) {
+	return s.FindAnnotationsFn(ctx, filter, opt...)
+}
+
+// CreateAnnotations creates the annotations.
+func (s *AnnotationService) CreateAnnotations(ctx context.Context, as []*platform.Annotation) error {
+	return s.CreateAnnotationsFn(ctx, as)
+}
+
+// UpdateAnnotation updates an annotation.
+func (s *AnnotationService) UpdateAnnotation(ctx context.Context, id platform.ID, upd platform.AnnotationUpdate) (*platform.Annotation, error) {
+	return s.UpdateAnnotationFn(ctx, id, upd)
+}
+
+// DeleteAnnotation deletes an annotation.
+func (s *AnnotationService) DeleteAnnotation(ctx context.Context, id platform.ID) error {
+	return s.DeleteAnnotationFn(ctx, id)
+}
@@ -0,0 +1,64 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.AuditService = (*AuditService)(nil)
+var _ platform.AuditExportService = (*AuditService)(nil)
+
+// AuditService is a mock implementation of platform.AuditService and platform.AuditExportService.
+type AuditService struct {
+	RecordAuditFn       func(context.Context, *platform.AuditRecord) error
+	FindAuditRecordsFn  func(context.Context, platform.AuditRecordFilter) ([]*platform.AuditRecord, error)
+	FindAuditExportFn   func(context.Context, platform.ID) (*platform.AuditExport, error)
+	FindAuditExportsFn  func(context.Context) ([]*platform.AuditExport, error)
+	PutAuditExportFn    func(context.Context, *platform.AuditExport) error
+	DeleteAuditExportFn func(context.Context, platform.ID) error
+}
+
+// NewAuditService returns a mock of AuditService where its methods will return zero values.
+func NewAuditService() *AuditService {
+	return &AuditService{
+		RecordAuditFn: func(context.Context, *platform.AuditRecord) error { return nil },
+		FindAuditRecordsFn: func(context.Context, platform.AuditRecordFilter) ([]*platform.AuditRecord, error) {
+			return nil, nil
+		},
+		FindAuditExportFn:   func(context.Context, platform.ID) (*platform.AuditExport, error) { return nil, nil },
+		FindAuditExportsFn:  func(context.Context) ([]*platform.AuditExport, error) { return nil, nil },
+		PutAuditExportFn:    func(context.Context, *platform.AuditExport) error { return nil },
+		DeleteAuditExportFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// RecordAudit records an audit record.
+func (s *AuditService) RecordAudit(ctx context.Context, r *platform.AuditRecord) error {
+	return s.RecordAuditFn(ctx, r)
+}
+
+// FindAuditRecords returns the audit records that match the filter.
+func (s *AuditService) FindAuditRecords(ctx context.Context, filter platform.AuditRecordFilter) ([]*platform.AuditRecord, error) {
+	return s.FindAuditRecordsFn(ctx, filter)
+}
+
+// FindAuditExport returns the audit export of an organization.
+func (s *AuditService) FindAuditExport(ctx context.Context, orgID platform.ID) (*platform.AuditExport, error) {
+	return s.FindAuditExportFn(ctx, orgID)
+}
+
+// FindAuditExports returns the audit exports of all organizations.
+func (s *AuditService) FindAuditExports(ctx context.Context) ([]*platform.AuditExport, error) {
+	return s.FindAuditExportsFn(ctx)
+}
+
+// PutAuditExport creates or replaces the audit export of an organization.
+func (s *AuditService) PutAuditExport(ctx context.Context, e *platform.AuditExport) error {
+	return s.PutAuditExportFn(ctx, e)
+}
+
+// DeleteAuditExport removes the audit export of an organization.
+func (s *AuditService) DeleteAuditExport(ctx context.Context, orgID platform.ID) error {
+	return s.DeleteAuditExportFn(ctx, orgID)
+}
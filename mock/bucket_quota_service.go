@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.BucketQuotaService = (*BucketQuotaService)(nil)
+
+// BucketQuotaService is a mock implementation of platform.BucketQuotaService.
+type BucketQuotaService struct {
+	ReserveWriteFn         func(context.Context, *platform.Bucket, [][]byte, int64) (time.Duration, error)
+	FindBucketQuotaUsageFn func(context.Context, *platform.Bucket) (*platform.BucketQuotaUsage, error)
+}
+
+// NewBucketQuotaService returns a mock of BucketQuotaService where its methods will return zero values.
+func NewBucketQuotaService() *BucketQuotaService {
+	return &BucketQuotaService{
+		ReserveWriteFn: func(context.Context, *platform.Bucket, [][]byte, int64) (time.Duration, error) {
+			return 0, nil
+		},
+		FindBucketQuotaUsageFn: func(ctx context.Context, b *platform.Bucket) (*platform.BucketQuotaUsage, error) {
+			return &platform.BucketQuotaUsage{BucketID: b.ID, Quota: b.Quota}, nil
+		},
+	}
+}
+
+// ReserveWrite accounts for a write of points and bytes to the bucket.
+func (s *BucketQuotaService) ReserveWrite(ctx context.Context, b *platform.Bucket, keys [][]byte, bytes int64) (time.Duration, error) {
+	return s.ReserveWriteFn(ctx, b, keys, bytes)
+}
+
+// FindBucketQuotaUsage returns the usage of the bucket against its quota.
+func (s *BucketQuotaService) FindBucketQuotaUsage(ctx context.Context, b *platform.Bucket) (*platform.BucketQuotaUsage, error) {
+	return s.FindBucketQuotaUsageFn(ctx, b)
+}
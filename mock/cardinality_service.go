@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.CardinalityService = (*CardinalityService)(nil)
+
+// CardinalityService is a mock implementation of platform.CardinalityService.
+type CardinalityService struct {
+	FindCardinalityTrendFn   func(context.Context, platform.CardinalityTrendFilter) ([]*platform.CardinalitySnapshot, error)
+	FindCurrentCardinalityFn func(context.Context, platform.ID, platform.ID) (*platform.CardinalitySnapshot, error)
+}
+
+// NewCardinalityService returns a mock of CardinalityService where its methods will return zero values.
+func NewCardinalityService() *CardinalityService {
+	return &CardinalityService{
+		FindCardinalityTrendFn: func(context.Context, platform.CardinalityTrendFilter) ([]*platform.CardinalitySnapshot, error) {
+			return nil, nil
+		},
+		FindCurrentCardinalityFn: func(context.Context, platform.ID, platform.ID) (*platform.CardinalitySnapshot, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindCardinalityTrend returns the snapshots of the bucket selected by filter.
+func (s *CardinalityService) FindCardinalityTrend(ctx context.Context, filter platform.CardinalityTrendFilter) ([]*platform.CardinalitySnapshot, error) {
+	return s.FindCardinalityTrendFn(ctx, filter)
+}
+
+// FindCurrentCardinality returns the current cardinality of the bucket.
+func (s *CardinalityService) FindCurrentCardinality(ctx context.Context, orgID, bucketID platform.ID) (*platform.CardinalitySnapshot, error) {
+	return s.FindCurrentCardinalityFn(ctx, orgID, bucketID)
+}
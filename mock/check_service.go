@@ -0,0 +1,97 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.CheckService = (*CheckService)(nil)
+
+// CheckService is a mock implementation of platform.CheckService.
+type CheckService struct {
+	FindCheckByIDFn func(context.Context, platform.ID) (*platform.Check, error)
+	FindChecksFn    func(context.Context, platform.CheckFilter, ...platform.FindOptions) ([]*platform.Check, int, error)
+	CreateCheckFn   func(context.Context, *platform.Check) error
+	UpdateCheckFn   func(context.Context, platform.ID, platform.CheckUpdate) (*platform.Check, error)
+	ReplaceCheckFn  func(context.Context, *platform.Check) (*platform.Check, error)
+	DeleteCheckFn   func(context.Context, platform.ID) error
+}
+
+// NewCheckService returns a mock of CheckService where its methods will return zero values.
+func NewCheckService() *CheckService {
+	return &CheckService{
+		FindCheckByIDFn: func(context.Context, platform.ID) (*platform.Check, error) { return nil, nil },
+		FindChecksFn: func(context.Context, platform.CheckFilter, ...platform.FindOptions) ([]*platform.Check, int, error) {
+			return nil, 0, nil
+		},
+		CreateCheckFn: func(context.Context, *platform.Check) error { return nil },
+		UpdateCheckFn: func(context.Context, platform.ID, platform.CheckUpdate) (*platform.Check, error) {
+			return nil, nil
+		},
+		ReplaceCheckFn: func(context.Context, *platform.Check) (*platform.Check, error) {
+			return nil, nil
+		},
+		DeleteCheckFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindCheckByID returns a single check by ID.
+func (s *CheckService) FindCheckByID(ctx context.Context, id platform.ID) (*platform.Check, error) {
+	return s.FindCheckByIDFn(ctx, id)
+}
+
+// FindChecks returns the checks that match the filter.
+func (s *CheckService) FindChecks(ctx context.Context, filter platform.CheckFilter, opt ...platform.FindOptions) ([]*platform.Check, int, error) {
+	return s.FindChecksFn(ctx, filter, opt...)
+}
+
+// CreateCheck creates a check.
+func (s *CheckService) CreateCheck(ctx context.Context, c *platform.Check) error {
+	return s.CreateCheckFn(ctx, c)
+}
+
+// UpdateCheck updates a check with the changeset.
+func (s *CheckService) UpdateCheck(ctx context.Context, id platform.ID, upd platform.CheckUpdate) (*platform.Check, error) {
+	return s.UpdateCheckFn(ctx, id, upd)
+}
+
+// ReplaceCheck replaces the definition of a check.
+func (s *CheckService) ReplaceCheck(ctx context.Context, c *platform.Check) (*platform.Check, error) {
+	return s.ReplaceCheckFn(ctx, c)
+}
+
+// DeleteCheck deletes a check.
+func (s *CheckService) DeleteCheck(ctx context.Context, id platform.ID) error {
+	return s.DeleteCheckFn(ctx, id)
+}
+
+var _ platform.CheckStatusService = (*CheckStatusService)(nil)
+
+// CheckStatusService is a mock implementation of platform.CheckStatusService.
+type CheckStatusService struct {
+	FindLastCheckStatusesFn func(context.Context, platform.ID) (map[platform.ID]*platform.CheckStatus, error)
+	FindCheckStatusesFn     func(context.Context, platform.CheckStatusFilter) ([]*platform.CheckStatus, error)
+}
+
+// NewCheckStatusService returns a mock of CheckStatusService without statuses.
+func NewCheckStatusService() *CheckStatusService {
+	return &CheckStatusService{
+		FindLastCheckStatusesFn: func(context.Context, platform.ID) (map[platform.ID]*platform.CheckStatus, error) {
+			return map[platform.ID]*platform.CheckStatus{}, nil
+		},
+		FindCheckStatusesFn: func(context.Context, platform.CheckStatusFilter) ([]*platform.CheckStatus, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindLastCheckStatuses returns the latest status of each check of the organization.
+func (s *CheckStatusService) FindLastCheckStatuses(ctx context.Context, orgID platform.ID) (map[platform.ID]*platform.CheckStatus, error) {
+	return s.FindLastCheckStatusesFn(ctx, orgID)
+}
+
+// FindCheckStatuses returns the statuses that match the filter.
+func (s *CheckStatusService) FindCheckStatuses(ctx context.Context, filter platform.CheckStatusFilter) ([]*platform.CheckStatus, error) {
+	return s.FindCheckStatusesFn(ctx, filter)
+}
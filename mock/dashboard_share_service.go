@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.DashboardShareService = (*DashboardShareService)(nil)
+
+// DashboardShareService is a mock implementation of platform.DashboardShareService.
+type DashboardShareService struct {
+	CreateDashboardShareFn      func(context.Context, *platform.DashboardShare) error
+	FindDashboardShareByIDFn    func(context.Context, platform.ID) (*platform.DashboardShare, error)
+	FindDashboardShareByTokenFn func(context.Context, string) (*platform.DashboardShare, error)
+	FindDashboardSharesFn       func(context.Context, platform.DashboardShareFilter) ([]*platform.DashboardShare, error)
+	RevokeDashboardShareFn      func(context.Context, platform.ID) error
+}
+
+// NewDashboardShareService returns a mock of DashboardShareService where its methods will return zero values.
+func NewDashboardShareService() *DashboardShareService {
+	return &DashboardShareService{
+		CreateDashboardShareFn:   func(context.Context, *platform.DashboardShare) error { return nil },
+		FindDashboardShareByIDFn: func(context.Context, platform.ID) (*platform.DashboardShare, error) { return nil, nil },
+		FindDashboardShareByTokenFn: func(context.Context, string) (*platform.DashboardShare, error) {
+			return nil, nil
+		},
+		FindDashboardSharesFn: func(context.Context, platform.DashboardShareFilter) ([]*platform.DashboardShare, error) {
+			return nil, nil
+		},
+		RevokeDashboardShareFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// CreateDashboardShare creates a share of a dashboard.
+func (s *DashboardShareService) CreateDashboardShare(ctx context.Context, ds *platform.DashboardShare) error {
+	return s.CreateDashboardShareFn(ctx, ds)
+}
+
+// FindDashboardShareByID returns a single dashboard share by ID.
+func (s *DashboardShareService) FindDashboardShareByID(ctx context.Context, id platform.ID) (*platform.DashboardShare, error) {
+	return s.FindDashboardShareByIDFn(ctx, id)
+}
+
+// FindDashboardShareByToken returns the dashboard share with the token.
+func (s *DashboardShareService) FindDashboardShareByToken(ctx context.Context, token string) (*platform.DashboardShare, error) {
+	return s.FindDashboardShareByTokenFn(ctx, token)
+}
+
+// FindDashboardShares returns the dashboard shares that match the filter.
+func (s *DashboardShareService) FindDashboardShares(ctx context.Context, filter platform.DashboardShareFilter) ([]*platform.DashboardShare, error) {
+	return s.FindDashboardSharesFn(ctx, filter)
+}
+
+// RevokeDashboardShare deletes a dashboard share.
+func (s *DashboardShareService) RevokeDashboardShare(ctx context.Context, id platform.ID) error {
+	return s.RevokeDashboardShareFn(ctx, id)
+}
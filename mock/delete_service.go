@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.DeleteService = (*DeleteService)(nil)
+
+// DeleteService is a mock implementation of platform.DeleteService.
+type DeleteService struct {
+	DeleteFn func(context.Context, platform.DeleteRequest) error
+}
+
+// NewDeleteService returns a mock of DeleteService where its methods will return zero values.
+func NewDeleteService() *DeleteService {
+	return &DeleteService{
+		DeleteFn: func(context.Context, platform.DeleteRequest) error {
+			return nil
+		},
+	}
+}
+
+// Delete deletes the data selected by the request.
+func (s *DeleteService) Delete(ctx context.Context, req platform.DeleteRequest) error {
+	return s.DeleteFn(ctx, req)
+}
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.DropSeriesService = (*DropSeriesService)(nil)
+
+// DropSeriesService is a mock implementation of platform.DropSeriesService.
+type DropSeriesService struct {
+	DropSeriesFn                  func(context.Context, platform.DropSeriesRequest) (*platform.DropSeriesOperation, error)
+	FindDropSeriesOperationByIDFn func(context.Context, platform.ID) (*platform.DropSeriesOperation, error)
+	FindDropSeriesOperationsFn    func(context.Context, platform.DropSeriesOperationFilter) ([]*platform.DropSeriesOperation, error)
+}
+
+// NewDropSeriesService returns a mock of DropSeriesService where its methods will return zero values.
+func NewDropSeriesService() *DropSeriesService {
+	return &DropSeriesService{
+		DropSeriesFn: func(context.Context, platform.DropSeriesRequest) (*platform.DropSeriesOperation, error) {
+			return nil, nil
+		},
+		FindDropSeriesOperationByIDFn: func(context.Context, platform.ID) (*platform.DropSeriesOperation, error) {
+			return nil, nil
+		},
+		FindDropSeriesOperationsFn: func(context.Context, platform.DropSeriesOperationFilter) ([]*platform.DropSeriesOperation, error) {
+			return nil, nil
+		},
+	}
+}
+
+// DropSeries starts dropping the series selected by the request.
+func (s *DropSeriesService) DropSeries(ctx context.Context, req platform.DropSeriesRequest) (*platform.DropSeriesOperation, error) {
+	return s.DropSeriesFn(ctx, req)
+}
+
+// FindDropSeriesOperationByID returns a single operation by ID.
+func (s *DropSeriesService) FindDropSeriesOperationByID(ctx context.Context, id platform.ID) (*platform.DropSeriesOperation, error) {
+	return s.FindDropSeriesOperationByIDFn(ctx, id)
+}
+
+// FindDropSeriesOperations returns the operations that match filter.
+func (s *DropSeriesService) FindDropSeriesOperations(ctx context.Context, filter platform.DropSeriesOperationFilter) ([]*platform.DropSeriesOperation, error) {
+	return s.FindDropSeriesOperationsFn(ctx, filter)
+}
@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.FluxPackageService = (*FluxPackageService)(nil)
+
+// FluxPackageService is a mock implementation of platform.FluxPackageService.
+type FluxPackageService struct {
+	FindFluxPackageByIDFn func(context.Context, platform.ID) (*platform.FluxPackage, error)
+	FindFluxPackagesFn    func(context.Context, platform.FluxPackageFilter) ([]*platform.FluxPackage, error)
+	CreateFluxPackageFn   func(context.Context, *platform.FluxPackage) error
+	UpdateFluxPackageFn   func(context.Context, platform.ID, platform.FluxPackageUpdate) (*platform.FluxPackage, error)
+	DeleteFluxPackageFn   func(context.Context, platform.ID) error
+}
+
+// NewFluxPackageService returns a mock of FluxPackageService where its methods will return zero values.
+func NewFluxPackageService() *FluxPackageService {
+	return &FluxPackageService{
+		FindFluxPackageByIDFn: func(context.Context, platform.ID) (*platform.FluxPackage, error) { return nil, nil },
+		FindFluxPackagesFn: func(context.Context, platform.FluxPackageFilter) ([]*platform.FluxPackage, error) {
+			return nil, nil
+		},
+		CreateFluxPackageFn: func(context.Context, *platform.FluxPackage) error { return nil },
+		UpdateFluxPackageFn: func(context.Context, platform.ID, platform.FluxPackageUpdate) (*platform.FluxPackage, error) {
+			return nil, nil
+		},
+		DeleteFluxPackageFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindFluxPackageByID returns a single flux package by ID.
+func (s *FluxPackageService) FindFluxPackageByID(ctx context.Context, id platform.ID) (*platform.FluxPackage, error) {
+	return s.FindFluxPackageByIDFn(ctx, id)
+}
+
+// FindFluxPackages returns a list of flux packages that match the filter.
+func (s *FluxPackageService) FindFluxPackages(ctx context.Context, filter platform.FluxPackageFilter) ([]*platform.FluxPackage, error) {
+	return s.FindFluxPackagesFn(ctx, filter)
+}
+
+// CreateFluxPackage creates a new flux package.
+func (s *FluxPackageService) CreateFluxPackage(ctx context.Context, p *platform.FluxPackage) error {
+	return s.CreateFluxPackageFn(ctx, p)
+}
+
+// UpdateFluxPackage updates a single flux package with a changeset.
+func (s *FluxPackageService) UpdateFluxPackage(ctx context.Context, id platform.ID, upd platform.FluxPackageUpdate) (*platform.FluxPackage, error) {
+	return s.UpdateFluxPackageFn(ctx, id, upd)
+}
+
+// DeleteFluxPackage removes a flux package by ID.
+func (s *FluxPackageService) DeleteFluxPackage(ctx context.Context, id platform.ID) error {
+	return s.DeleteFluxPackageFn(ctx, id)
+}
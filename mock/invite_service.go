@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.InviteService = (*InviteService)(nil)
+
+// InviteService is a mock implementation of platform.InviteService.
+type InviteService struct {
+	CreateInviteFn      func(context.Context, *platform.Invite) error
+	FindInviteByIDFn    func(context.Context, platform.ID) (*platform.Invite, error)
+	FindInviteByTokenFn func(context.Context, string) (*platform.Invite, error)
+	FindInvitesFn       func(context.Context, platform.InviteFilter) ([]*platform.Invite, error)
+	RevokeInviteFn      func(context.Context, platform.ID) error
+	AcceptInviteFn      func(context.Context, string, platform.InviteAcceptance) (*platform.User, error)
+}
+
+// NewInviteService returns a mock of InviteService where its methods will return zero values.
+func NewInviteService() *InviteService {
+	return &InviteService{
+		CreateInviteFn:      func(context.Context, *platform.Invite) error { return nil },
+		FindInviteByIDFn:    func(context.Context, platform.ID) (*platform.Invite, error) { return nil, nil },
+		FindInviteByTokenFn: func(context.Context, string) (*platform.Invite, error) { return nil, nil },
+		FindInvitesFn:       func(context.Context, platform.InviteFilter) ([]*platform.Invite, error) { return nil, nil },
+		RevokeInviteFn:      func(context.Context, platform.ID) error { return nil },
+		AcceptInviteFn: func(context.Context, string, platform.InviteAcceptance) (*platform.User, error) {
+			return nil, nil
+		},
+	}
+}
+
+// CreateInvite creates an invite.
+func (s *InviteService) CreateInvite(ctx context.Context, i *platform.Invite) error {
+	return s.CreateInviteFn(ctx, i)
+}
+
+// FindInviteByID returns a single invite by ID.
+func (s *InviteService) FindInviteByID(ctx context.Context, id platform.ID) (*platform.Invite, error) {
+	return s.FindInviteByIDFn(ctx, id)
+}
+
+// FindInviteByToken returns the invite with the token.
+func (s *InviteService) FindInviteByToken(ctx context.Context, token string) (*platform.Invite, error) {
+	return s.FindInviteByTokenFn(ctx, token)
+}
+
+// FindInvites returns the invites that match the filter.
+func (s *InviteService) FindInvites(ctx context.Context, filter platform.InviteFilter) ([]*platform.Invite, error) {
+	return s.FindInvitesFn(ctx, filter)
+}
+
+// RevokeInvite deletes an invite.
+func (s *InviteService) RevokeInvite(ctx context.Context, id platform.ID) error {
+	return s.RevokeInviteFn(ctx, id)
+}
+
+// AcceptInvite accepts the invite with the token.
+func (s *InviteService) AcceptInvite(ctx context.Context, token string, a platform.InviteAcceptance) (*platform.User, error) {
+	return s.AcceptInviteFn(ctx, token, a)
+}
@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.LimitsSimulationService = (*LimitsSimulationService)(nil)
+
+// LimitsSimulationService is a mock implementation of platform.LimitsSimulationService.
+type LimitsSimulationService struct {
+	SimulateLimitsFn func(context.Context, platform.LimitsSimulation) (*platform.LimitsSimulationResult, error)
+}
+
+// NewLimitsSimulationService returns a mock of LimitsSimulationService where its methods will return zero values.
+func NewLimitsSimulationService() *LimitsSimulationService {
+	return &LimitsSimulationService{
+		SimulateLimitsFn: func(context.Context, platform.LimitsSimulation) (*platform.LimitsSimulationResult, error) {
+			return &platform.LimitsSimulationResult{Orgs: []*platform.OrgLimitsResult{}}, nil
+		},
+	}
+}
+
+// SimulateLimits returns the usage of every organization against the limits of the simulation.
+func (s *LimitsSimulationService) SimulateLimits(ctx context.Context, sim platform.LimitsSimulation) (*platform.LimitsSimulationResult, error) {
+	return s.SimulateLimitsFn(ctx, sim)
+}
@@ -0,0 +1,68 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var (
+	_ platform.MaintenanceService         = (*MaintenanceService)(nil)
+	_ platform.MaintenanceScheduleService = (*MaintenanceScheduleService)(nil)
+)
+
+// MaintenanceService is a mock implementation of platform.MaintenanceService.
+type MaintenanceService struct {
+	MaintenanceStatusFn       func(context.Context) (*platform.MaintenanceStatus, error)
+	UpdateMaintenanceStatusFn func(context.Context, platform.MaintenanceStatusUpdate) (*platform.MaintenanceStatus, error)
+}
+
+// NewMaintenanceService returns a mock of MaintenanceService where its methods will return zero values.
+func NewMaintenanceService() *MaintenanceService {
+	return &MaintenanceService{
+		MaintenanceStatusFn: func(context.Context) (*platform.MaintenanceStatus, error) {
+			return &platform.MaintenanceStatus{}, nil
+		},
+		UpdateMaintenanceStatusFn: func(context.Context, platform.MaintenanceStatusUpdate) (*platform.MaintenanceStatus, error) {
+			return &platform.MaintenanceStatus{}, nil
+		},
+	}
+}
+
+// MaintenanceStatus returns the current maintenance state.
+func (s *MaintenanceService) MaintenanceStatus(ctx context.Context) (*platform.MaintenanceStatus, error) {
+	return s.MaintenanceStatusFn(ctx)
+}
+
+// UpdateMaintenanceStatus updates the maintenance state.
+func (s *MaintenanceService) UpdateMaintenanceStatus(ctx context.Context, upd platform.MaintenanceStatusUpdate) (*platform.MaintenanceStatus, error) {
+	return s.UpdateMaintenanceStatusFn(ctx, upd)
+}
+
+// MaintenanceScheduleService is a mock implementation of platform.MaintenanceScheduleService.
+type MaintenanceScheduleService struct {
+	MaintenanceScheduleFn       func(context.Context) (*platform.MaintenanceSchedule, error)
+	UpdateMaintenanceScheduleFn func(context.Context, platform.MaintenanceScheduleUpdate) (*platform.MaintenanceSchedule, error)
+}
+
+// NewMaintenanceScheduleService returns a mock of MaintenanceScheduleService where its methods will return zero values.
+func NewMaintenanceScheduleService() *MaintenanceScheduleService {
+	return &MaintenanceScheduleService{
+		MaintenanceScheduleFn: func(context.Context) (*platform.MaintenanceSchedule, error) {
+			return &platform.MaintenanceSchedule{}, nil
+		},
+		UpdateMaintenanceScheduleFn: func(context.Context, platform.MaintenanceScheduleUpdate) (*platform.MaintenanceSchedule, error) {
+			return &platform.MaintenanceSchedule{}, nil
+		},
+	}
+}
+
+// MaintenanceSchedule returns the current maintenance schedule.
+func (s *MaintenanceScheduleService) MaintenanceSchedule(ctx context.Context) (*platform.MaintenanceSchedule, error) {
+	return s.MaintenanceScheduleFn(ctx)
+}
+
+// UpdateMaintenanceSchedule overrides the maintenance windows.
+func (s *MaintenanceScheduleService) UpdateMaintenanceSchedule(ctx context.Context, upd platform.MaintenanceScheduleUpdate) (*platform.MaintenanceSchedule, error) {
+	return s.UpdateMaintenanceScheduleFn(ctx, upd)
+}
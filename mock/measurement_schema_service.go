@@ -0,0 +1,53 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.MeasurementSchemaService = (*MeasurementSchemaService)(nil)
+
+// MeasurementSchemaService is a mock implementation of platform.MeasurementSchemaService.
+type MeasurementSchemaService struct {
+	FindMeasurementSchemaByIDFn func(context.Context, platform.ID, platform.ID) (*platform.MeasurementSchema, error)
+	FindMeasurementSchemasFn    func(context.Context, platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error)
+	CreateMeasurementSchemaFn   func(context.Context, *platform.MeasurementSchema) error
+	UpdateMeasurementSchemaFn   func(context.Context, platform.ID, platform.ID, platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error)
+}
+
+// NewMeasurementSchemaService returns a mock of MeasurementSchemaService where its methods will return zero values.
+func NewMeasurementSchemaService() *MeasurementSchemaService {
+	return &MeasurementSchemaService{
+		FindMeasurementSchemaByIDFn: func(context.Context, platform.ID, platform.ID) (*platform.MeasurementSchema, error) {
+			return nil, nil
+		},
+		FindMeasurementSchemasFn: func(context.Context, platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error) {
+			return nil, nil
+		},
+		CreateMeasurementSchemaFn: func(context.Context, *platform.MeasurementSchema) error { return nil },
+		UpdateMeasurementSchemaFn: func(context.Context, platform.ID, platform.ID, platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindMeasurementSchemaByID returns a single measurement schema of a bucket by ID.
+func (s *MeasurementSchemaService) FindMeasurementSchemaByID(ctx context.Context, bucketID, id platform.ID) (*platform.MeasurementSchema, error) {
+	return s.FindMeasurementSchemaByIDFn(ctx, bucketID, id)
+}
+
+// FindMeasurementSchemas returns a list of measurement schemas that match the filter.
+func (s *MeasurementSchemaService) FindMeasurementSchemas(ctx context.Context, filter platform.MeasurementSchemaFilter) ([]*platform.MeasurementSchema, error) {
+	return s.FindMeasurementSchemasFn(ctx, filter)
+}
+
+// CreateMeasurementSchema creates a new measurement schema.
+func (s *MeasurementSchemaService) CreateMeasurementSchema(ctx context.Context, m *platform.MeasurementSchema) error {
+	return s.CreateMeasurementSchemaFn(ctx, m)
+}
+
+// UpdateMeasurementSchema updates the columns of a measurement schema.
+func (s *MeasurementSchemaService) UpdateMeasurementSchema(ctx context.Context, bucketID, id platform.ID, upd platform.MeasurementSchemaUpdate) (*platform.MeasurementSchema, error) {
+	return s.UpdateMeasurementSchemaFn(ctx, bucketID, id, upd)
+}
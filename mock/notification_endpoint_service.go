@@ -0,0 +1,86 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.NotificationEndpointService = (*NotificationEndpointService)(nil)
+
+// NotificationEndpointService is a mock implementation of platform.NotificationEndpointService.
+type NotificationEndpointService struct {
+	FindNotificationEndpointByIDFn func(context.Context, platform.ID) (*platform.NotificationEndpoint, error)
+	FindNotificationEndpointsFn    func(context.Context, platform.NotificationEndpointFilter, ...platform.FindOptions) ([]*platform.NotificationEndpoint, int, error)
+	CreateNotificationEndpointFn   func(context.Context, *platform.NotificationEndpoint) error
+	UpdateNotificationEndpointFn   func(context.Context, platform.ID, platform.NotificationEndpointUpdate) (*platform.NotificationEndpoint, error)
+	ReplaceNotificationEndpointFn  func(context.Context, *platform.NotificationEndpoint) (*platform.NotificationEndpoint, error)
+	DeleteNotificationEndpointFn   func(context.Context, platform.ID) error
+}
+
+// NewNotificationEndpointService returns a mock of NotificationEndpointService where its methods will return zero values.
+func NewNotificationEndpointService() *NotificationEndpointService {
+	return &NotificationEndpointService{
+		FindNotificationEndpointByIDFn: func(context.Context, platform.ID) (*platform.NotificationEndpoint, error) { return nil, nil },
+		FindNotificationEndpointsFn: func(context.Context, platform.NotificationEndpointFilter, ...platform.FindOptions) ([]*platform.NotificationEndpoint, int, error) {
+			return nil, 0, nil
+		},
+		CreateNotificationEndpointFn: func(context.Context, *platform.NotificationEndpoint) error { return nil },
+		UpdateNotificationEndpointFn: func(context.Context, platform.ID, platform.NotificationEndpointUpdate) (*platform.NotificationEndpoint, error) {
+			return nil, nil
+		},
+		ReplaceNotificationEndpointFn: func(context.Context, *platform.NotificationEndpoint) (*platform.NotificationEndpoint, error) {
+			return nil, nil
+		},
+		DeleteNotificationEndpointFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindNotificationEndpointByID returns a single notification endpoint by ID.
+func (s *NotificationEndpointService) FindNotificationEndpointByID(ctx context.Context, id platform.ID) (*platform.NotificationEndpoint, error) {
+	return s.FindNotificationEndpointByIDFn(ctx, id)
+}
+
+// FindNotificationEndpoints returns the notification endpoints that match the filter.
+func (s *NotificationEndpointService) FindNotificationEndpoints(ctx context.Context, filter platform.NotificationEndpointFilter, opt ...platform.FindOptions) ([]*platform.NotificationEndpoint, int, error) {
+	return s.FindNotificationEndpointsFn(ctx, filter, opt...)
+}
+
+// CreateNotificationEndpoint creates a notification endpoint.
+func (s *NotificationEndpointService) CreateNotificationEndpoint(ctx context.Context, e *platform.NotificationEndpoint) error {
+	return s.CreateNotificationEndpointFn(ctx, e)
+}
+
+// UpdateNotificationEndpoint updates a notification endpoint with the changeset.
+func (s *NotificationEndpointService) UpdateNotificationEndpoint(ctx context.Context, id platform.ID, upd platform.NotificationEndpointUpdate) (*platform.NotificationEndpoint, error) {
+	return s.UpdateNotificationEndpointFn(ctx, id, upd)
+}
+
+// ReplaceNotificationEndpoint replaces the configuration of a notification endpoint.
+func (s *NotificationEndpointService) ReplaceNotificationEndpoint(ctx context.Context, e *platform.NotificationEndpoint) (*platform.NotificationEndpoint, error) {
+	return s.ReplaceNotificationEndpointFn(ctx, e)
+}
+
+// DeleteNotificationEndpoint deletes a notification endpoint.
+func (s *NotificationEndpointService) DeleteNotificationEndpoint(ctx context.Context, id platform.ID) error {
+	return s.DeleteNotificationEndpointFn(ctx, id)
+}
+
+var _ platform.NotificationSender = (*NotificationSender)(nil)
+
+// NotificationSender is a mock implementation of platform.NotificationSender.
+type NotificationSender struct {
+	SendNotificationFn func(context.Context, *platform.NotificationEndpoint, platform.Notification) error
+}
+
+// NewNotificationSender returns a mock of NotificationSender that sends nothing.
+func NewNotificationSender() *NotificationSender {
+	return &NotificationSender{
+		SendNotificationFn: func(context.Context, *platform.NotificationEndpoint, platform.Notification) error { return nil },
+	}
+}
+
+// SendNotification sends a notification to a notification endpoint.
+func (s *NotificationSender) SendNotification(ctx context.Context, e *platform.NotificationEndpoint, n platform.Notification) error {
+	return s.SendNotificationFn(ctx, e, n)
+}
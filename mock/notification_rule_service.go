@@ -0,0 +1,95 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.NotificationRuleService = (*NotificationRuleService)(nil)
+
+// NotificationRuleService is a mock implementation of platform.NotificationRuleService.
+type NotificationRuleService struct {
+	FindNotificationRuleByIDFn func(context.Context, platform.ID) (*platform.NotificationRule, error)
+	FindNotificationRulesFn    func(context.Context, platform.NotificationRuleFilter, ...platform.FindOptions) ([]*platform.NotificationRule, int, error)
+	CreateNotificationRuleFn   func(context.Context, *platform.NotificationRule) error
+	UpdateNotificationRuleFn   func(context.Context, platform.ID, platform.NotificationRuleUpdate) (*platform.NotificationRule, error)
+	ReplaceNotificationRuleFn  func(context.Context, *platform.NotificationRule) (*platform.NotificationRule, error)
+	DeleteNotificationRuleFn   func(context.Context, platform.ID) error
+}
+
+// NewNotificationRuleService returns a mock of NotificationRuleService where its methods will return zero values.
+func NewNotificationRuleService() *NotificationRuleService {
+	return &NotificationRuleService{
+		FindNotificationRuleByIDFn: func(context.Context, platform.ID) (*platform.NotificationRule, error) { return nil, nil },
+		FindNotificationRulesFn: func(context.Context, platform.NotificationRuleFilter, ...platform.FindOptions) ([]*platform.NotificationRule, int, error) {
+			return nil, 0, nil
+		},
+		CreateNotificationRuleFn: func(context.Context, *platform.NotificationRule) error { return nil },
+		UpdateNotificationRuleFn: func(context.Context, platform.ID, platform.NotificationRuleUpdate) (*platform.NotificationRule, error) {
+			return nil, nil
+		},
+		ReplaceNotificationRuleFn: func(context.Context, *platform.NotificationRule) (*platform.NotificationRule, error) {
+			return nil, nil
+		},
+		DeleteNotificationRuleFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindNotificationRuleByID returns a single notification rule by ID.
+func (s *NotificationRuleService) FindNotificationRuleByID(ctx context.Context, id platform.ID) (*platform.NotificationRule, error) {
+	return s.FindNotificationRuleByIDFn(ctx, id)
+}
+
+// FindNotificationRules returns the notification rules that match the filter.
+func (s *NotificationRuleService) FindNotificationRules(ctx context.Context, filter platform.NotificationRuleFilter, opt ...platform.FindOptions) ([]*platform.NotificationRule, int, error) {
+	return s.FindNotificationRulesFn(ctx, filter, opt...)
+}
+
+// CreateNotificationRule creates a notification rule.
+func (s *NotificationRuleService) CreateNotificationRule(ctx context.Context, c *platform.NotificationRule) error {
+	return s.CreateNotificationRuleFn(ctx, c)
+}
+
+// UpdateNotificationRule updates a notification rule with the changeset.
+func (s *NotificationRuleService) UpdateNotificationRule(ctx context.Context, id platform.ID, upd platform.NotificationRuleUpdate) (*platform.NotificationRule, error) {
+	return s.UpdateNotificationRuleFn(ctx, id, upd)
+}
+
+// ReplaceNotificationRule replaces the definition of a notification rule.
+func (s *NotificationRuleService) ReplaceNotificationRule(ctx context.Context, c *platform.NotificationRule) (*platform.NotificationRule, error) {
+	return s.ReplaceNotificationRuleFn(ctx, c)
+}
+
+// DeleteNotificationRule deletes a notification rule.
+func (s *NotificationRuleService) DeleteNotificationRule(ctx context.Context, id platform.ID) error {
+	return s.DeleteNotificationRuleFn(ctx, id)
+}
+
+var _ platform.SentNotificationService = (*SentNotificationService)(nil)
+
+// SentNotificationService is a mock implementation of platform.SentNotificationService.
+type SentNotificationService struct {
+	AddSentNotificationFn   func(context.Context, *platform.SentNotification) error
+	FindSentNotificationsFn func(context.Context, platform.SentNotificationFilter, ...platform.FindOptions) ([]*platform.SentNotification, int, error)
+}
+
+// NewSentNotificationService returns a mock of SentNotificationService without notifications.
+func NewSentNotificationService() *SentNotificationService {
+	return &SentNotificationService{
+		AddSentNotificationFn: func(context.Context, *platform.SentNotification) error { return nil },
+		FindSentNotificationsFn: func(context.Context, platform.SentNotificationFilter, ...platform.FindOptions) ([]*platform.SentNotification, int, error) {
+			return []*platform.SentNotification{}, 0, nil
+		},
+	}
+}
+
+// AddSentNotification adds a notification to the history.
+func (s *SentNotificationService) AddSentNotification(ctx context.Context, n *platform.SentNotification) error {
+	return s.AddSentNotificationFn(ctx, n)
+}
+
+// FindSentNotifications returns the notifications that match the filter.
+func (s *SentNotificationService) FindSentNotifications(ctx context.Context, filter platform.SentNotificationFilter, opt ...platform.FindOptions) ([]*platform.SentNotification, int, error) {
+	return s.FindSentNotificationsFn(ctx, filter, opt...)
+}
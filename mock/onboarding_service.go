@@ -18,6 +18,7 @@ type OnboardingService struct {
 
 	IsOnboardingFn func(context.Context) (bool, error)
 	GenerateFn     func(context.Context, *platform.OnboardingRequest) (*platform.OnboardingResults, error)
+	OnboardUserFn  func(context.Context, *platform.OnboardingRequest) (*platform.OnboardingResults, error)
 }
 
 // NewOnboardingService returns a mock of OnboardingService where its methods will return zero values.
@@ -27,6 +28,9 @@ func NewOnboardingService() *OnboardingService {
 		GenerateFn: func(context.Context, *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
 			return nil, nil
 		},
+		OnboardUserFn: func(context.Context, *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+			return nil, nil
+		},
 	}
 }
 
@@ -39,3 +43,8 @@ func (s *OnboardingService) IsOnboarding(ctx context.Context) (bool, error) {
 func (s *OnboardingService) Generate(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
 	return s.GenerateFn(ctx, req)
 }
+
+// OnboardUser creates an additional user, org, bucket and token.
+func (s *OnboardingService) OnboardUser(ctx context.Context, req *platform.OnboardingRequest) (*platform.OnboardingResults, error) {
+	return s.OnboardUserFn(ctx, req)
+}
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.OrgDeletionService = (*OrgDeletionService)(nil)
+
+// OrgDeletionService is a mock implementation of platform.OrgDeletionService.
+type OrgDeletionService struct {
+	DeleteOrganizationResourcesFn func(context.Context, platform.OrgDeletionRequest) (*platform.OrgDeletion, error)
+	FindOrgDeletionByIDFn         func(context.Context, platform.ID) (*platform.OrgDeletion, error)
+	FindOrgDeletionsFn            func(context.Context, platform.OrgDeletionFilter) ([]*platform.OrgDeletion, error)
+}
+
+// NewOrgDeletionService returns a mock of OrgDeletionService where its methods will return zero values.
+func NewOrgDeletionService() *OrgDeletionService {
+	return &OrgDeletionService{
+		DeleteOrganizationResourcesFn: func(context.Context, platform.OrgDeletionRequest) (*platform.OrgDeletion, error) {
+			return nil, nil
+		},
+		FindOrgDeletionByIDFn: func(context.Context, platform.ID) (*platform.OrgDeletion, error) {
+			return nil, nil
+		},
+		FindOrgDeletionsFn: func(context.Context, platform.OrgDeletionFilter) ([]*platform.OrgDeletion, error) {
+			return nil, nil
+		},
+	}
+}
+
+// DeleteOrganizationResources starts deleting the organization of the request.
+func (s *OrgDeletionService) DeleteOrganizationResources(ctx context.Context, req platform.OrgDeletionRequest) (*platform.OrgDeletion, error) {
+	return s.DeleteOrganizationResourcesFn(ctx, req)
+}
+
+// FindOrgDeletionByID returns a single deletion by ID.
+func (s *OrgDeletionService) FindOrgDeletionByID(ctx context.Context, id platform.ID) (*platform.OrgDeletion, error) {
+	return s.FindOrgDeletionByIDFn(ctx, id)
+}
+
+// FindOrgDeletions returns the deletions that match filter.
+func (s *OrgDeletionService) FindOrgDeletions(ctx context.Context, filter platform.OrgDeletionFilter) ([]*platform.OrgDeletion, error) {
+	return s.FindOrgDeletionsFn(ctx, filter)
+}
@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.OrgUsageService = (*OrgUsageService)(nil)
+
+// OrgUsageService is a mock implementation of platform.OrgUsageService.
+type OrgUsageService struct {
+	FindOrgUsageFn func(context.Context, platform.OrgUsageFilter) (*platform.OrgUsageReport, error)
+}
+
+// NewOrgUsageService returns a mock of OrgUsageService where its methods will return zero values.
+func NewOrgUsageService() *OrgUsageService {
+	return &OrgUsageService{
+		FindOrgUsageFn: func(context.Context, platform.OrgUsageFilter) (*platform.OrgUsageReport, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindOrgUsage returns the usage of the organization selected by filter.
+func (s *OrgUsageService) FindOrgUsage(ctx context.Context, filter platform.OrgUsageFilter) (*platform.OrgUsageReport, error) {
+	return s.FindOrgUsageFn(ctx, filter)
+}
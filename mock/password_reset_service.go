@@ -0,0 +1,33 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.PasswordResetService = (*PasswordResetService)(nil)
+
+// PasswordResetService is a mock implementation of platform.PasswordResetService.
+type PasswordResetService struct {
+	CreatePasswordResetFn func(context.Context, platform.ID) (*platform.PasswordReset, error)
+	ResetPasswordFn       func(context.Context, string, string) error
+}
+
+// NewPasswordResetService returns a mock of PasswordResetService where its methods will return zero values.
+func NewPasswordResetService() *PasswordResetService {
+	return &PasswordResetService{
+		CreatePasswordResetFn: func(context.Context, platform.ID) (*platform.PasswordReset, error) { return nil, nil },
+		ResetPasswordFn:       func(context.Context, string, string) error { return nil },
+	}
+}
+
+// CreatePasswordReset creates a password reset for the user.
+func (s *PasswordResetService) CreatePasswordReset(ctx context.Context, userID platform.ID) (*platform.PasswordReset, error) {
+	return s.CreatePasswordResetFn(ctx, userID)
+}
+
+// ResetPassword sets the password of the user of the password reset with the token.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token string, password string) error {
+	return s.ResetPasswordFn(ctx, token, password)
+}
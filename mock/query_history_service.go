@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.QueryHistoryService = (*QueryHistoryService)(nil)
+
+// QueryHistoryService is a mock implementation of platform.QueryHistoryService.
+type QueryHistoryService struct {
+	AddQueryHistoryEntryFn func(context.Context, *platform.QueryHistoryEntry) error
+	FindQueryHistoryFn     func(context.Context, platform.QueryHistoryFilter, ...platform.FindOptions) ([]*platform.QueryHistoryEntry, error)
+}
+
+// NewQueryHistoryService returns a mock of QueryHistoryService where its methods will return zero values.
+func NewQueryHistoryService() *QueryHistoryService {
+	return &QueryHistoryService{
+		AddQueryHistoryEntryFn: func(context.Context, *platform.QueryHistoryEntry) error { return nil },
+		FindQueryHistoryFn: func(context.Context, platform.QueryHistoryFilter, ...platform.FindOptions) ([]*platform.QueryHistoryEntry, error) {
+			return nil, nil
+		},
+	}
+}
+
+// AddQueryHistoryEntry adds a completed query to the history.
+func (s *QueryHistoryService) AddQueryHistoryEntry(ctx context.Context, e *platform.QueryHistoryEntry) error {
+	return s.AddQueryHistoryEntryFn(ctx, e)
+}
+
+// FindQueryHistory returns the entries of the history that match the filter.
+func (s *QueryHistoryService) FindQueryHistory(ctx context.Context, filter platform.QueryHistoryFilter, opt ...platform.FindOptions) ([]*platform.QueryHistoryEntry, error) {
+	return s.FindQueryHistoryFn(ctx, filter, opt...)
+}
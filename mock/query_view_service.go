@@ -0,0 +1,49 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.QueryViewService = (*QueryViewService)(nil)
+
+// QueryViewService is a mock implementation of platform.QueryViewService.
+type QueryViewService struct {
+	FindQueryViewByIDFn func(context.Context, platform.ID) (*platform.QueryView, error)
+	FindQueryViewsFn    func(context.Context, platform.QueryViewFilter) ([]*platform.QueryView, error)
+	CreateQueryViewFn   func(context.Context, *platform.QueryView) error
+	DeleteQueryViewFn   func(context.Context, platform.ID) error
+}
+
+// NewQueryViewService returns a mock of QueryViewService where its methods will return zero values.
+func NewQueryViewService() *QueryViewService {
+	return &QueryViewService{
+		FindQueryViewByIDFn: func(context.Context, platform.ID) (*platform.QueryView, error) { return nil, nil },
+		FindQueryViewsFn: func(context.Context, platform.QueryViewFilter) ([]*platform.QueryView, error) {
+			return nil, nil
+		},
+		CreateQueryViewFn: func(context.Context, *platform.QueryView) error { return nil },
+		DeleteQueryViewFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindQueryViewByID returns a single query view by ID.
+func (s *QueryViewService) FindQueryViewByID(ctx context.Context, id platform.ID) (*platform.QueryView, error) {
+	return s.FindQueryViewByIDFn(ctx, id)
+}
+
+// FindQueryViews returns a list of query views that match the filter.
+func (s *QueryViewService) FindQueryViews(ctx context.Context, filter platform.QueryViewFilter) ([]*platform.QueryView, error) {
+	return s.FindQueryViewsFn(ctx, filter)
+}
+
+// CreateQueryView creates a new query view.
+func (s *QueryViewService) CreateQueryView(ctx context.Context, v *platform.QueryView) error {
+	return s.CreateQueryViewFn(ctx, v)
+}
+
+// DeleteQueryView removes a query view by ID.
+func (s *QueryViewService) DeleteQueryView(ctx context.Context, id platform.ID) error {
+	return s.DeleteQueryViewFn(ctx, id)
+}
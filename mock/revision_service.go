@@ -0,0 +1,44 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RevisionService = (*RevisionService)(nil)
+
+// RevisionService is a mock implementation of platform.RevisionService.
+type RevisionService struct {
+	FindRevisionsFn   func(context.Context, platform.ResourceType, platform.ID, ...platform.FindOptions) ([]*platform.Revision, int, error)
+	FindRevisionFn    func(context.Context, platform.ResourceType, platform.ID, int) (*platform.Revision, error)
+	RestoreRevisionFn func(context.Context, platform.ResourceType, platform.ID, int) error
+}
+
+// NewRevisionService returns a mock of RevisionService where its methods will return zero values.
+func NewRevisionService() *RevisionService {
+	return &RevisionService{
+		FindRevisionsFn: func(context.Context, platform.ResourceType, platform.ID, ...platform.FindOptions) ([]*platform.Revision, int, error) {
+			return nil, 0, nil
+		},
+		FindRevisionFn: func(context.Context, platform.ResourceType, platform.ID, int) (*platform.Revision, error) {
+			return nil, nil
+		},
+		RestoreRevisionFn: func(context.Context, platform.ResourceType, platform.ID, int) error { return nil },
+	}
+}
+
+// FindRevisions returns the revisions of a resource.
+func (s *RevisionService) FindRevisions(ctx context.Context, typ platform.ResourceType, id platform.ID, opt ...platform.FindOptions) ([]*platform.Revision, int, error) {
+	return s.FindRevisionsFn(ctx, typ, id, opt...)
+}
+
+// FindRevision returns a single revision of a resource by its version.
+func (s *RevisionService) FindRevision(ctx context.Context, typ platform.ResourceType, id platform.ID, version int) (*platform.Revision, error) {
+	return s.FindRevisionFn(ctx, typ, id, version)
+}
+
+// RestoreRevision puts a resource back as it was at a revision.
+func (s *RevisionService) RestoreRevision(ctx context.Context, typ platform.ResourceType, id platform.ID, version int) error {
+	return s.RestoreRevisionFn(ctx, typ, id, version)
+}
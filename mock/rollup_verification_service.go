@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RollupVerificationService = (*RollupVerificationService)(nil)
+
+// RollupVerificationService is a mock implementation of platform.RollupVerificationService.
+type RollupVerificationService struct {
+	VerifyRollupFn func(context.Context, platform.RollupVerificationRequest) (*platform.RollupVerification, error)
+}
+
+// NewRollupVerificationService returns a mock of RollupVerificationService where its methods will return zero values.
+func NewRollupVerificationService() *RollupVerificationService {
+	return &RollupVerificationService{
+		VerifyRollupFn: func(context.Context, platform.RollupVerificationRequest) (*platform.RollupVerification, error) {
+			return nil, nil
+		},
+	}
+}
+
+// VerifyRollup verifies the rollup selected by req.
+func (s *RollupVerificationService) VerifyRollup(ctx context.Context, req platform.RollupVerificationRequest) (*platform.RollupVerification, error) {
+	return s.VerifyRollupFn(ctx, req)
+}
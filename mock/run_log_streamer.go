@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RunLogStreamer = (*RunLogStreamer)(nil)
+
+// RunLogStreamer is a mock implementation of platform.RunLogStreamer.
+type RunLogStreamer struct {
+	StreamRunLogsFn func(context.Context, platform.ID, platform.ID) (<-chan *platform.Log, error)
+}
+
+// NewRunLogStreamer returns a mock of RunLogStreamer where its methods will return zero values.
+func NewRunLogStreamer() *RunLogStreamer {
+	return &RunLogStreamer{
+		StreamRunLogsFn: func(context.Context, platform.ID, platform.ID) (<-chan *platform.Log, error) {
+			return nil, platform.ErrRunNotInProgress
+		},
+	}
+}
+
+// StreamRunLogs returns a channel receiving the log lines added to an in-progress run.
+func (s *RunLogStreamer) StreamRunLogs(ctx context.Context, taskID, runID platform.ID) (<-chan *platform.Log, error) {
+	return s.StreamRunLogsFn(ctx, taskID, runID)
+}
@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.SchedulerStateService = (*SchedulerStateService)(nil)
+
+// SchedulerStateService is a mock implementation of platform.SchedulerStateService.
+type SchedulerStateService struct {
+	SchedulerStateFn func(context.Context) (*platform.SchedulerState, error)
+}
+
+// NewSchedulerStateService returns a mock of SchedulerStateService where its methods will return zero values.
+func NewSchedulerStateService() *SchedulerStateService {
+	return &SchedulerStateService{
+		SchedulerStateFn: func(context.Context) (*platform.SchedulerState, error) {
+			return &platform.SchedulerState{}, nil
+		},
+	}
+}
+
+// SchedulerState returns a snapshot of the task scheduler.
+func (s *SchedulerStateService) SchedulerState(ctx context.Context) (*platform.SchedulerState, error) {
+	return s.SchedulerStateFn(ctx)
+}
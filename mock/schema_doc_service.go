@@ -0,0 +1,54 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.SchemaDescriptionService = (*SchemaDescriptionService)(nil)
+
+// SchemaDescriptionService is a mock implementation of platform.SchemaDescriptionService.
+type SchemaDescriptionService struct {
+	FindSchemaDescriptionsFn func(context.Context, platform.ID) (*platform.SchemaDescriptions, error)
+	PutSchemaDescriptionsFn  func(context.Context, *platform.SchemaDescriptions) error
+}
+
+// NewSchemaDescriptionService returns a mock of SchemaDescriptionService where its methods will return zero values.
+func NewSchemaDescriptionService() *SchemaDescriptionService {
+	return &SchemaDescriptionService{
+		FindSchemaDescriptionsFn: func(context.Context, platform.ID) (*platform.SchemaDescriptions, error) {
+			return nil, nil
+		},
+		PutSchemaDescriptionsFn: func(context.Context, *platform.SchemaDescriptions) error { return nil },
+	}
+}
+
+// FindSchemaDescriptions returns the descriptions of the schema of a bucket.
+func (s *SchemaDescriptionService) FindSchemaDescriptions(ctx context.Context, bucketID platform.ID) (*platform.SchemaDescriptions, error) {
+	return s.FindSchemaDescriptionsFn(ctx, bucketID)
+}
+
+// PutSchemaDescriptions replaces the descriptions of the schema of their bucket.
+func (s *SchemaDescriptionService) PutSchemaDescriptions(ctx context.Context, d *platform.SchemaDescriptions) error {
+	return s.PutSchemaDescriptionsFn(ctx, d)
+}
+
+var _ platform.SchemaDocService = (*SchemaDocService)(nil)
+
+// SchemaDocService is a mock implementation of platform.SchemaDocService.
+type SchemaDocService struct {
+	FindSchemaDocFn func(context.Context, platform.ID) (*platform.SchemaDoc, error)
+}
+
+// NewSchemaDocService returns a mock of SchemaDocService where its methods will return zero values.
+func NewSchemaDocService() *SchemaDocService {
+	return &SchemaDocService{
+		FindSchemaDocFn: func(context.Context, platform.ID) (*platform.SchemaDoc, error) { return nil, nil },
+	}
+}
+
+// FindSchemaDoc returns the documentation of the schema of a bucket.
+func (s *SchemaDocService) FindSchemaDoc(ctx context.Context, bucketID platform.ID) (*platform.SchemaDoc, error) {
+	return s.FindSchemaDocFn(ctx, bucketID)
+}
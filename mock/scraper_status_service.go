@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.ScraperTargetStatusService = (*ScraperTargetStatusService)(nil)
+
+// ScraperTargetStatusService is a mock implementation of platform.ScraperTargetStatusService.
+type ScraperTargetStatusService struct {
+	PutTargetStatusFn  func(context.Context, *platform.ScraperTargetStatus) error
+	FindTargetStatusFn func(context.Context, platform.ID) (*platform.ScraperTargetStatus, error)
+}
+
+// NewScraperTargetStatusService returns a mock of ScraperTargetStatusService where its methods will return zero values.
+func NewScraperTargetStatusService() *ScraperTargetStatusService {
+	return &ScraperTargetStatusService{
+		PutTargetStatusFn: func(context.Context, *platform.ScraperTargetStatus) error { return nil },
+		FindTargetStatusFn: func(context.Context, platform.ID) (*platform.ScraperTargetStatus, error) {
+			return nil, nil
+		},
+	}
+}
+
+// PutTargetStatus records the outcome of the last scrape of a target.
+func (s *ScraperTargetStatusService) PutTargetStatus(ctx context.Context, st *platform.ScraperTargetStatus) error {
+	return s.PutTargetStatusFn(ctx, st)
+}
+
+// FindTargetStatus returns the outcome of the last scrape of a target.
+func (s *ScraperTargetStatusService) FindTargetStatus(ctx context.Context, targetID platform.ID) (*platform.ScraperTargetStatus, error) {
+	return s.FindTargetStatusFn(ctx, targetID)
+}
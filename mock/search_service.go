@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.SearchService = (*SearchService)(nil)
+
+// SearchService is a mock implementation of platform.SearchService.
+type SearchService struct {
+	SearchFn func(context.Context, platform.SearchFilter, ...platform.FindOptions) ([]*platform.SearchResult, error)
+}
+
+// NewSearchService returns a mock of SearchService where its methods will return zero values.
+func NewSearchService() *SearchService {
+	return &SearchService{
+		SearchFn: func(context.Context, platform.SearchFilter, ...platform.FindOptions) ([]*platform.SearchResult, error) {
+			return nil, nil
+		},
+	}
+}
+
+// Search returns the resources that match the filter.
+func (s *SearchService) Search(ctx context.Context, filter platform.SearchFilter, opt ...platform.FindOptions) ([]*platform.SearchResult, error) {
+	return s.SearchFn(ctx, filter, opt...)
+}
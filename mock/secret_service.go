@@ -72,3 +72,43 @@ func (s *SecretService) PatchSecrets(ctx context.Context, orgID platform.ID, m m
 func (s *SecretService) DeleteSecret(ctx context.Context, orgID platform.ID, ks ...string) error {
 	return s.DeleteSecretFn(ctx, orgID, ks...)
 }
+
+var _ platform.SecretRotationService = (*SecretRotationService)(nil)
+
+// SecretRotationService is a mock implementation of a platform.SecretRotationService.
+type SecretRotationService struct {
+	FindSecretVersionsFn func(ctx context.Context, orgID platform.ID, k string) ([]*platform.SecretVersion, error)
+	LoadSecretVersionFn  func(ctx context.Context, orgID platform.ID, k string, version int) (string, error)
+	RotateSecretFn       func(ctx context.Context, orgID platform.ID, k string, v string) (*platform.SecretVersion, error)
+}
+
+// NewSecretRotationService returns a mock SecretRotationService where its methods will return
+// zero values.
+func NewSecretRotationService() *SecretRotationService {
+	return &SecretRotationService{
+		FindSecretVersionsFn: func(ctx context.Context, orgID platform.ID, k string) ([]*platform.SecretVersion, error) {
+			return nil, fmt.Errorf("not implmemented")
+		},
+		LoadSecretVersionFn: func(ctx context.Context, orgID platform.ID, k string, version int) (string, error) {
+			return "", fmt.Errorf("not implmemented")
+		},
+		RotateSecretFn: func(ctx context.Context, orgID platform.ID, k string, v string) (*platform.SecretVersion, error) {
+			return nil, fmt.Errorf("not implmemented")
+		},
+	}
+}
+
+// FindSecretVersions returns the versions of the secret at key k for organization orgID.
+func (s *SecretRotationService) FindSecretVersions(ctx context.Context, orgID platform.ID, k string) ([]*platform.SecretVersion, error) {
+	return s.FindSecretVersionsFn(ctx, orgID, k)
+}
+
+// LoadSecretVersion retrieves the value of a version of the secret at key k for organization orgID.
+func (s *SecretRotationService) LoadSecretVersion(ctx context.Context, orgID platform.ID, k string, version int) (string, error) {
+	return s.LoadSecretVersionFn(ctx, orgID, k, version)
+}
+
+// RotateSecret stores v as the new value of the secret at key k for organization orgID.
+func (s *SecretRotationService) RotateSecret(ctx context.Context, orgID platform.ID, k string, v string) (*platform.SecretVersion, error) {
+	return s.RotateSecretFn(ctx, orgID, k, v)
+}
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.SeriesSchemaService = (*SeriesSchemaService)(nil)
+
+// SeriesSchemaService is a mock implementation of platform.SeriesSchemaService.
+type SeriesSchemaService struct {
+	FindMeasurementsFn func(context.Context, platform.SeriesSchemaFilter) ([]string, error)
+	FindTagKeysFn      func(context.Context, platform.SeriesSchemaFilter) ([]string, error)
+	FindTagValuesFn    func(context.Context, string, platform.SeriesSchemaFilter) ([]string, error)
+}
+
+// NewSeriesSchemaService returns a mock of SeriesSchemaService where its methods will return zero values.
+func NewSeriesSchemaService() *SeriesSchemaService {
+	return &SeriesSchemaService{
+		FindMeasurementsFn: func(context.Context, platform.SeriesSchemaFilter) ([]string, error) {
+			return nil, nil
+		},
+		FindTagKeysFn: func(context.Context, platform.SeriesSchemaFilter) ([]string, error) {
+			return nil, nil
+		},
+		FindTagValuesFn: func(context.Context, string, platform.SeriesSchemaFilter) ([]string, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindMeasurements returns the measurements of the series selected by filter.
+func (s *SeriesSchemaService) FindMeasurements(ctx context.Context, filter platform.SeriesSchemaFilter) ([]string, error) {
+	return s.FindMeasurementsFn(ctx, filter)
+}
+
+// FindTagKeys returns the tag keys of the series selected by filter.
+func (s *SeriesSchemaService) FindTagKeys(ctx context.Context, filter platform.SeriesSchemaFilter) ([]string, error) {
+	return s.FindTagKeysFn(ctx, filter)
+}
+
+// FindTagValues returns the values of the tag key of the series selected by filter.
+func (s *SeriesSchemaService) FindTagValues(ctx context.Context, tagKey string, filter platform.SeriesSchemaFilter) ([]string, error) {
+	return s.FindTagValuesFn(ctx, tagKey, filter)
+}
@@ -0,0 +1,49 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.SilenceService = (*SilenceService)(nil)
+
+// SilenceService is a mock implementation of platform.SilenceService.
+type SilenceService struct {
+	FindSilenceByIDFn func(context.Context, platform.ID) (*platform.Silence, error)
+	FindSilencesFn    func(context.Context, platform.SilenceFilter, ...platform.FindOptions) ([]*platform.Silence, int, error)
+	CreateSilenceFn   func(context.Context, *platform.Silence) error
+	ExpireSilenceFn   func(context.Context, platform.ID) (*platform.Silence, error)
+}
+
+// NewSilenceService returns a mock of SilenceService where its methods will return zero values.
+func NewSilenceService() *SilenceService {
+	return &SilenceService{
+		FindSilenceByIDFn: func(context.Context, platform.ID) (*platform.Silence, error) { return nil, nil },
+		FindSilencesFn: func(context.Context, platform.SilenceFilter, ...platform.FindOptions) ([]*platform.Silence, int, error) {
+			return nil, 0, nil
+		},
+		CreateSilenceFn: func(context.Context, *platform.Silence) error { return nil },
+		ExpireSilenceFn: func(context.Context, platform.ID) (*platform.Silence, error) { return nil, nil },
+	}
+}
+
+// FindSilenceByID returns a single silence by ID.
+func (s *SilenceService) FindSilenceByID(ctx context.Context, id platform.ID) (*platform.Silence, error) {
+	return s.FindSilenceByIDFn(ctx, id)
+}
+
+// FindSilences returns the silences that match the filter.
+func (s *SilenceService) FindSilences(ctx context.Context, filter platform.SilenceFilter, opt ...platform.FindOptions) ([]*platform.Silence, int, error) {
+	return s.FindSilencesFn(ctx, filter, opt...)
+}
+
+// CreateSilence creates a silence.
+func (s *SilenceService) CreateSilence(ctx context.Context, sl *platform.Silence) error {
+	return s.CreateSilenceFn(ctx, sl)
+}
+
+// ExpireSilence ends a silence now.
+func (s *SilenceService) ExpireSilence(ctx context.Context, id platform.ID) (*platform.Silence, error) {
+	return s.ExpireSilenceFn(ctx, id)
+}
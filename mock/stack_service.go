@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.StackService = (*StackService)(nil)
+
+// StackService is a mock implementation of platform.StackService.
+type StackService struct {
+	FindStackByIDFn func(context.Context, platform.ID) (*platform.Stack, error)
+	FindStacksFn    func(context.Context, platform.StackFilter, ...platform.FindOptions) ([]*platform.Stack, int, error)
+	CreateStackFn   func(context.Context, *platform.Stack) error
+	UpdateStackFn   func(context.Context, platform.ID, platform.StackUpdate) (*platform.Stack, error)
+	DeleteStackFn   func(context.Context, platform.ID) error
+}
+
+// NewStackService returns a mock of StackService where its methods will return zero values.
+func NewStackService() *StackService {
+	return &StackService{
+		FindStackByIDFn: func(context.Context, platform.ID) (*platform.Stack, error) { return nil, nil },
+		FindStacksFn: func(context.Context, platform.StackFilter, ...platform.FindOptions) ([]*platform.Stack, int, error) {
+			return nil, 0, nil
+		},
+		CreateStackFn: func(context.Context, *platform.Stack) error { return nil },
+		UpdateStackFn: func(context.Context, platform.ID, platform.StackUpdate) (*platform.Stack, error) { return nil, nil },
+		DeleteStackFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindStackByID returns a single stack by ID.
+func (s *StackService) FindStackByID(ctx context.Context, id platform.ID) (*platform.Stack, error) {
+	return s.FindStackByIDFn(ctx, id)
+}
+
+// FindStacks returns the stacks that match the filter.
+func (s *StackService) FindStacks(ctx context.Context, filter platform.StackFilter, opt ...platform.FindOptions) ([]*platform.Stack, int, error) {
+	return s.FindStacksFn(ctx, filter, opt...)
+}
+
+// CreateStack creates a stack.
+func (s *StackService) CreateStack(ctx context.Context, st *platform.Stack) error {
+	return s.CreateStackFn(ctx, st)
+}
+
+// UpdateStack updates a stack.
+func (s *StackService) UpdateStack(ctx context.Context, id platform.ID, upd platform.StackUpdate) (*platform.Stack, error) {
+	return s.UpdateStackFn(ctx, id, upd)
+}
+
+// DeleteStack deletes a stack.
+func (s *StackService) DeleteStack(ctx context.Context, id platform.ID) error {
+	return s.DeleteStackFn(ctx, id)
+}
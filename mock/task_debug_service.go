@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.TaskDebugService = (*TaskDebugService)(nil)
+
+// TaskDebugService is a mock implementation of platform.TaskDebugService.
+type TaskDebugService struct {
+	DebugRunFn func(context.Context, platform.ID, platform.TaskDebugRequest) (*platform.TaskDebugRun, error)
+}
+
+// NewTaskDebugService returns a mock of TaskDebugService where its methods will return zero values.
+func NewTaskDebugService() *TaskDebugService {
+	return &TaskDebugService{
+		DebugRunFn: func(_ context.Context, id platform.ID, _ platform.TaskDebugRequest) (*platform.TaskDebugRun, error) {
+			return &platform.TaskDebugRun{TaskID: id}, nil
+		},
+	}
+}
+
+// DebugRun runs the task once to debug it.
+func (s *TaskDebugService) DebugRun(ctx context.Context, taskID platform.ID, req platform.TaskDebugRequest) (*platform.TaskDebugRun, error) {
+	return s.DebugRunFn(ctx, taskID, req)
+}
@@ -18,8 +18,8 @@ type TaskService struct {
 	FindRunsFn     func(context.Context, platform.RunFilter) ([]*platform.Run, int, error)
 	FindRunByIDFn  func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
 	CancelRunFn    func(context.Context, platform.ID, platform.ID) error
-	RetryRunFn     func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
-	ForceRunFn     func(context.Context, platform.ID, int64) (*platform.Run, error)
+	RetryRunFn     func(context.Context, platform.ID, platform.ID, string) (*platform.Run, error)
+	ForceRunFn     func(context.Context, platform.ID, int64, string) (*platform.Run, error)
 }
 
 func (s *TaskService) FindTaskByID(ctx context.Context, id platform.ID) (*platform.Task, error) {
@@ -58,10 +58,10 @@ func (s *TaskService) CancelRun(ctx context.Context, taskID, runID platform.ID)
 	return s.CancelRunFn(ctx, taskID, runID)
 }
 
-func (s *TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID) (*platform.Run, error) {
-	return s.RetryRunFn(ctx, taskID, runID)
+func (s *TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID, annotation string) (*platform.Run, error) {
+	return s.RetryRunFn(ctx, taskID, runID, annotation)
 }
 
-func (s *TaskService) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*platform.Run, error) {
-	return s.ForceRunFn(ctx, taskID, scheduledFor)
+func (s *TaskService) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64, annotation string) (*platform.Run, error) {
+	return s.ForceRunFn(ctx, taskID, scheduledFor, annotation)
 }
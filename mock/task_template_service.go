@@ -0,0 +1,58 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.TaskTemplateService = (*TaskTemplateService)(nil)
+
+// TaskTemplateService is a mock implementation of platform.TaskTemplateService.
+type TaskTemplateService struct {
+	FindTaskTemplateByIDFn func(context.Context, platform.ID) (*platform.TaskTemplate, error)
+	FindTaskTemplatesFn    func(context.Context, platform.TaskTemplateFilter) ([]*platform.TaskTemplate, error)
+	CreateTaskTemplateFn   func(context.Context, *platform.TaskTemplate) error
+	UpdateTaskTemplateFn   func(context.Context, platform.ID, platform.TaskTemplateUpdate) (*platform.TaskTemplate, error)
+	DeleteTaskTemplateFn   func(context.Context, platform.ID) error
+}
+
+// NewTaskTemplateService returns a mock of TaskTemplateService where its methods will return zero values.
+func NewTaskTemplateService() *TaskTemplateService {
+	return &TaskTemplateService{
+		FindTaskTemplateByIDFn: func(context.Context, platform.ID) (*platform.TaskTemplate, error) { return nil, nil },
+		FindTaskTemplatesFn: func(context.Context, platform.TaskTemplateFilter) ([]*platform.TaskTemplate, error) {
+			return nil, nil
+		},
+		CreateTaskTemplateFn: func(context.Context, *platform.TaskTemplate) error { return nil },
+		UpdateTaskTemplateFn: func(context.Context, platform.ID, platform.TaskTemplateUpdate) (*platform.TaskTemplate, error) {
+			return nil, nil
+		},
+		DeleteTaskTemplateFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindTaskTemplateByID returns a single task template by ID.
+func (s *TaskTemplateService) FindTaskTemplateByID(ctx context.Context, id platform.ID) (*platform.TaskTemplate, error) {
+	return s.FindTaskTemplateByIDFn(ctx, id)
+}
+
+// FindTaskTemplates returns a list of task templates that match the filter.
+func (s *TaskTemplateService) FindTaskTemplates(ctx context.Context, filter platform.TaskTemplateFilter) ([]*platform.TaskTemplate, error) {
+	return s.FindTaskTemplatesFn(ctx, filter)
+}
+
+// CreateTaskTemplate creates a new task template.
+func (s *TaskTemplateService) CreateTaskTemplate(ctx context.Context, tt *platform.TaskTemplate) error {
+	return s.CreateTaskTemplateFn(ctx, tt)
+}
+
+// UpdateTaskTemplate updates a single task template with a changeset.
+func (s *TaskTemplateService) UpdateTaskTemplate(ctx context.Context, id platform.ID, upd platform.TaskTemplateUpdate) (*platform.TaskTemplate, error) {
+	return s.UpdateTaskTemplateFn(ctx, id, upd)
+}
+
+// DeleteTaskTemplate removes a task template by ID.
+func (s *TaskTemplateService) DeleteTaskTemplate(ctx context.Context, id platform.ID) error {
+	return s.DeleteTaskTemplateFn(ctx, id)
+}
@@ -0,0 +1,56 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.TeamService = (*TeamService)(nil)
+
+// TeamService is a mock implementation of platform.TeamService.
+type TeamService struct {
+	FindTeamByIDFn func(context.Context, platform.ID) (*platform.Team, error)
+	FindTeamsFn    func(context.Context, platform.TeamFilter, ...platform.FindOptions) ([]*platform.Team, int, error)
+	CreateTeamFn   func(context.Context, *platform.Team) error
+	UpdateTeamFn   func(context.Context, platform.ID, platform.TeamUpdate) (*platform.Team, error)
+	DeleteTeamFn   func(context.Context, platform.ID) error
+}
+
+// NewTeamService returns a mock of TeamService where its methods will return zero values.
+func NewTeamService() *TeamService {
+	return &TeamService{
+		FindTeamByIDFn: func(context.Context, platform.ID) (*platform.Team, error) { return nil, nil },
+		FindTeamsFn: func(context.Context, platform.TeamFilter, ...platform.FindOptions) ([]*platform.Team, int, error) {
+			return nil, 0, nil
+		},
+		CreateTeamFn: func(context.Context, *platform.Team) error { return nil },
+		UpdateTeamFn: func(context.Context, platform.ID, platform.TeamUpdate) (*platform.Team, error) { return nil, nil },
+		DeleteTeamFn: func(context.Context, platform.ID) error { return nil },
+	}
+}
+
+// FindTeamByID returns a single team by ID.
+func (s *TeamService) FindTeamByID(ctx context.Context, id platform.ID) (*platform.Team, error) {
+	return s.FindTeamByIDFn(ctx, id)
+}
+
+// FindTeams returns the teams that match the filter.
+func (s *TeamService) FindTeams(ctx context.Context, filter platform.TeamFilter, opt ...platform.FindOptions) ([]*platform.Team, int, error) {
+	return s.FindTeamsFn(ctx, filter, opt...)
+}
+
+// CreateTeam creates a team.
+func (s *TeamService) CreateTeam(ctx context.Context, t *platform.Team) error {
+	return s.CreateTeamFn(ctx, t)
+}
+
+// UpdateTeam updates a team with the changeset.
+func (s *TeamService) UpdateTeam(ctx context.Context, id platform.ID, upd platform.TeamUpdate) (*platform.Team, error) {
+	return s.UpdateTeamFn(ctx, id, upd)
+}
+
+// DeleteTeam deletes a team.
+func (s *TeamService) DeleteTeam(ctx context.Context, id platform.ID) error {
+	return s.DeleteTeamFn(ctx, id)
+}
@@ -0,0 +1,35 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.TelegrafAgentService = (*TelegrafAgentService)(nil)
+
+// TelegrafAgentService is a mock implementation of platform.TelegrafAgentService.
+type TelegrafAgentService struct {
+	CheckInTelegrafAgentFn func(context.Context, *platform.TelegrafAgent) error
+	FindTelegrafAgentsFn   func(context.Context, platform.TelegrafAgentFilter) ([]*platform.TelegrafAgent, error)
+}
+
+// NewTelegrafAgentService returns a mock of TelegrafAgentService where its methods will return zero values.
+func NewTelegrafAgentService() *TelegrafAgentService {
+	return &TelegrafAgentService{
+		CheckInTelegrafAgentFn: func(context.Context, *platform.TelegrafAgent) error { return nil },
+		FindTelegrafAgentsFn: func(context.Context, platform.TelegrafAgentFilter) ([]*platform.TelegrafAgent, error) {
+			return nil, nil
+		},
+	}
+}
+
+// CheckInTelegrafAgent records the state a telegraf agent reports.
+func (s *TelegrafAgentService) CheckInTelegrafAgent(ctx context.Context, a *platform.TelegrafAgent) error {
+	return s.CheckInTelegrafAgentFn(ctx, a)
+}
+
+// FindTelegrafAgents returns the telegraf agents that match the filter.
+func (s *TelegrafAgentService) FindTelegrafAgents(ctx context.Context, filter platform.TelegrafAgentFilter) ([]*platform.TelegrafAgent, error) {
+	return s.FindTelegrafAgentsFn(ctx, filter)
+}
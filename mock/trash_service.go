@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.TrashService = (*TrashService)(nil)
+
+// TrashService is a mock implementation of platform.TrashService.
+type TrashService struct {
+	FindTrashedResourceFn    func(context.Context, platform.ResourceType, platform.ID) (*platform.TrashedResource, error)
+	FindTrashedResourcesFn   func(context.Context, platform.TrashFilter, ...platform.FindOptions) ([]*platform.TrashedResource, int, error)
+	RestoreTrashedResourceFn func(context.Context, platform.ResourceType, platform.ID) error
+	PurgeTrashedResourceFn   func(context.Context, platform.ResourceType, platform.ID) error
+}
+
+// NewTrashService returns a mock of TrashService where its methods will return zero values.
+func NewTrashService() *TrashService {
+	return &TrashService{
+		FindTrashedResourceFn: func(context.Context, platform.ResourceType, platform.ID) (*platform.TrashedResource, error) {
+			return nil, nil
+		},
+		FindTrashedResourcesFn: func(context.Context, platform.TrashFilter, ...platform.FindOptions) ([]*platform.TrashedResource, int, error) {
+			return nil, 0, nil
+		},
+		RestoreTrashedResourceFn: func(context.Context, platform.ResourceType, platform.ID) error { return nil },
+		PurgeTrashedResourceFn:   func(context.Context, platform.ResourceType, platform.ID) error { return nil },
+	}
+}
+
+// FindTrashedResource returns a single trashed resource by type and ID.
+func (s *TrashService) FindTrashedResource(ctx context.Context, typ platform.ResourceType, id platform.ID) (*platform.TrashedResource, error) {
+	return s.FindTrashedResourceFn(ctx, typ, id)
+}
+
+// FindTrashedResources returns a list of trashed resources that match the filter.
+func (s *TrashService) FindTrashedResources(ctx context.Context, filter platform.TrashFilter, opt ...platform.FindOptions) ([]*platform.TrashedResource, int, error) {
+	return s.FindTrashedResourcesFn(ctx, filter, opt...)
+}
+
+// RestoreTrashedResource moves a resource out of the trash.
+func (s *TrashService) RestoreTrashedResource(ctx context.Context, typ platform.ResourceType, id platform.ID) error {
+	return s.RestoreTrashedResourceFn(ctx, typ, id)
+}
+
+// PurgeTrashedResource permanently deletes a trashed resource.
+func (s *TrashService) PurgeTrashedResource(ctx context.Context, typ platform.ResourceType, id platform.ID) error {
+	return s.PurgeTrashedResourceFn(ctx, typ, id)
+}
@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.UserPermissionService = (*UserPermissionService)(nil)
+
+// UserPermissionService is a mock implementation of platform.UserPermissionService.
+type UserPermissionService struct {
+	FindUserPermissionsFn func(ctx context.Context, userID platform.ID) ([]platform.Permission, error)
+}
+
+// NewUserPermissionService returns a mock of UserPermissionService where its methods will return zero values.
+func NewUserPermissionService() *UserPermissionService {
+	return &UserPermissionService{
+		FindUserPermissionsFn: func(ctx context.Context, userID platform.ID) ([]platform.Permission, error) {
+			return nil, nil
+		},
+	}
+}
+
+// FindUserPermissions returns the maximal privileges of the user.
+func (s *UserPermissionService) FindUserPermissions(ctx context.Context, userID platform.ID) ([]platform.Permission, error) {
+	return s.FindUserPermissionsFn(ctx, userID)
+}
@@ -7,12 +7,14 @@ import (
 )
 
 var _ platform.UserResourceMappingService = &UserResourceMappingService{}
+var _ platform.UserResourceMappingBatchService = &UserResourceMappingService{}
 
 // UserResourceMappingService is a mock implementation of platform.UserResourceMappingService
 type UserResourceMappingService struct {
 	FindMappingsFn  func(context.Context, platform.UserResourceMappingFilter) ([]*platform.UserResourceMapping, int, error)
 	CreateMappingFn func(context.Context, *platform.UserResourceMapping) error
 	DeleteMappingFn func(context.Context, platform.ID, platform.ID) error
+	ApplyMappingsFn func(context.Context, []platform.UserResourceMappingOp) ([]platform.UserResourceMappingResult, error)
 }
 
 // NewUserResourceMappingService returns a mock of UserResourceMappingService
@@ -24,6 +26,13 @@ func NewUserResourceMappingService() *UserResourceMappingService {
 		},
 		CreateMappingFn: func(context.Context, *platform.UserResourceMapping) error { return nil },
 		DeleteMappingFn: func(context.Context, platform.ID, platform.ID) error { return nil },
+		ApplyMappingsFn: func(_ context.Context, ops []platform.UserResourceMappingOp) ([]platform.UserResourceMappingResult, error) {
+			rs := make([]platform.UserResourceMappingResult, len(ops))
+			for i, op := range ops {
+				rs[i].UserResourceMappingOp = op
+			}
+			return rs, nil
+		},
 	}
 }
 
@@ -41,3 +50,8 @@ func (s *UserResourceMappingService) CreateUserResourceMapping(ctx context.Conte
 func (s *UserResourceMappingService) DeleteUserResourceMapping(ctx context.Context, resourceID platform.ID, userID platform.ID) error {
 	return s.DeleteMappingFn(ctx, resourceID, userID)
 }
+
+// ApplyUserResourceMappings applies changes to many UserResourceMappings.
+func (s *UserResourceMappingService) ApplyUserResourceMappings(ctx context.Context, ops []platform.UserResourceMappingOp) ([]platform.UserResourceMappingResult, error) {
+	return s.ApplyMappingsFn(ctx, ops)
+}
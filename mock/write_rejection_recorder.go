@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+	"sync"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.WriteRejectionRecorder = (*WriteRejectionRecorder)(nil)
+
+// WriteRejectionRecorder is a mock WriteRejectionRecorder that keeps the rejections it records.
+type WriteRejectionRecorder struct {
+	mu         sync.Mutex
+	Rejections []platform.WriteRejection
+}
+
+// RecordWriteRejection keeps the rejection in Rejections.
+func (r *WriteRejectionRecorder) RecordWriteRejection(ctx context.Context, rej platform.WriteRejection) {
+	r.mu.Lock()
+	r.Rejections = append(r.Rejections, rej)
+	r.mu.Unlock()
+}
@@ -421,6 +421,52 @@ func parsePointsWithPrecision(buf []byte, mm []byte, defaultTime time.Time, prec
 	return points, nil
 }
 
+// Line is a line of line protocol and the points parsed from it.
+type Line struct {
+	// Number is the number of the line within the parsed buffer.
+	Number int
+	Text   []byte
+	Points []Point
+	// Err is the error parsing the line. Points is empty if it is set.
+	Err error
+}
+
+// ParseLinesWithPrecision is similar to ParsePointsWithPrecision, but returns the points
+// and the error of each line separately. Blank lines and comments are skipped, and lines
+// are numbered from firstLine.
+func ParseLinesWithPrecision(buf []byte, mm []byte, defaultTime time.Time, precision string, firstLine int) []Line {
+	var (
+		lines  []Line
+		points []Point
+		pos    int
+		block  []byte
+		number = firstLine
+	)
+	for pos < len(buf) {
+		pos, block = scanLine(buf, pos)
+		pos++
+
+		n := number
+		number += bytes.Count(block, []byte{'\n'}) + 1
+
+		start := skipWhitespace(block, 0)
+		if start >= len(block) || block[start] == '#' {
+			continue
+		}
+
+		line := Line{Number: n, Text: block[start:]}
+		parsed, err := parsePointsAppend(points, line.Text, mm, defaultTime, precision, true)
+		if err != nil {
+			line.Err = err
+		} else {
+			line.Points = parsed[len(points):len(parsed):len(parsed)]
+			points = parsed
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 func parsePointsAppend(points []Point, buf []byte, mm []byte, defaultTime time.Time, precision string, rewrite bool) ([]Point, error) {
 	// scan the first block which is measurement[,tag1=value1,tag2=value=2...]
 	pos, key, err := scanKey(buf, 0)
@@ -1161,6 +1207,22 @@ func skipWhitespace(buf []byte, i int) int {
 	return i
 }
 
+// CompleteLines returns the length of the longest prefix of buf that consists of
+// complete lines of line protocol. A line is complete once it is terminated by a
+// newline that is not part of a quoted field value.
+func CompleteLines(buf []byte) int {
+	n := 0
+	for pos := 0; pos < len(buf); {
+		end, _ := scanLine(buf, pos)
+		if end >= len(buf) {
+			break
+		}
+		pos = end + 1
+		n = pos
+	}
+	return n
+}
+
 // scanLine returns the end position in buf and the next line found within
 // buf.
 func scanLine(buf []byte, i int) (int, []byte) {
@@ -2749,3 +2749,54 @@ func BenchmarkMakeKey(b *testing.B) {
 		})
 	}
 }
+
+func TestCompleteLines(t *testing.T) {
+	tests := []struct {
+		buf  string
+		want int
+	}{
+		{buf: "", want: 0},
+		{buf: "cpu value=1", want: 0},
+		{buf: "cpu value=1\n", want: 12},
+		{buf: "cpu value=1\ncpu value=2", want: 12},
+		{buf: "cpu value=1\n\ncpu value=2\n", want: 25},
+		{buf: "cpu value=1\nlog msg=\"a\nb\"", want: 12},
+		{buf: "cpu value=1\nlog msg=\"a\nb\"\n", want: 26},
+		{buf: "cpu\\\n,host=a value=1\n", want: 21},
+	}
+	for _, tt := range tests {
+		if got := models.CompleteLines([]byte(tt.buf)); got != tt.want {
+			t.Errorf("CompleteLines(%q) = %d, want %d", tt.buf, got, tt.want)
+		}
+	}
+}
+
+func TestParseLinesWithPrecision(t *testing.T) {
+	buf := "# comment\ncpu,host=a value=1,n=2i 1000000000\n\nlog msg=\"a\nb\" 1000000000\ncpu value=\n  mem free=3i\n"
+	now := time.Unix(0, 0)
+	lines := models.ParseLinesWithPrecision([]byte(buf), []byte("mm"), now, "ns", 10)
+
+	type line struct {
+		number int
+		text   string
+		points int
+		err    bool
+	}
+	var got []line
+	for _, l := range lines {
+		got = append(got, line{number: l.Number, text: string(l.Text), points: len(l.Points), err: l.Err != nil})
+	}
+	want := []line{
+		{number: 11, text: "cpu,host=a value=1,n=2i 1000000000", points: 2},
+		{number: 13, text: "log msg=\"a\nb\" 1000000000", points: 1},
+		{number: 15, text: "cpu value=", err: true},
+		{number: 16, text: "mem free=3i", points: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got lines %+v, want %+v", got, want)
+	}
+
+	if got := string(lines[3].Points[0].Tags().Get(models.MeasurementTagKeyBytes)); got != "mem" {
+		t.Errorf("got measurement %q, want mem", got)
+	}
+}
@@ -0,0 +1,82 @@
+package influxdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamePolicy is the policy of which resources of an organization must have
+// names unique within the organization.
+type NamePolicy struct {
+	// UniqueNames are the types of the resources with unique names.
+	UniqueNames []ResourceType `json:"uniqueNames"`
+}
+
+// DefaultNamePolicy is the name policy of organizations that have none if the
+// server sets no policy: only the names of buckets are unique.
+var DefaultNamePolicy = NamePolicy{
+	UniqueNames: []ResourceType{BucketsResourceType},
+}
+
+// NamePolicyResourceTypes are the types of resources a name policy applies to.
+var NamePolicyResourceTypes = []ResourceType{
+	BucketsResourceType,
+	DashboardsResourceType,
+	TasksResourceType,
+}
+
+// Valid returns an error if the policy applies to resources it can not apply to,
+// or allows buckets to share names.
+func (p NamePolicy) Valid() error {
+	for _, rt := range p.UniqueNames {
+		if !containsResourceType(NamePolicyResourceTypes, rt) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("name policy can not apply to %s; it applies to %s", rt, joinResourceTypes(NamePolicyResourceTypes)),
+			}
+		}
+	}
+	if !p.Unique(BucketsResourceType) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucket names must be unique, since queries read buckets by name",
+		}
+	}
+	return nil
+}
+
+// Unique returns true if the names of resources of the type must be unique.
+func (p NamePolicy) Unique(rt ResourceType) bool {
+	return containsResourceType(p.UniqueNames, rt)
+}
+
+// IsZero returns true if the policy applies to no resources.
+func (p NamePolicy) IsZero() bool {
+	return len(p.UniqueNames) == 0
+}
+
+// NameConflictError is returned when a resource is given the name of another
+// resource of its organization and the names of its type must be unique.
+func NameConflictError(rt ResourceType, name string) *Error {
+	return &Error{
+		Code: EConflict,
+		Msg:  fmt.Sprintf("name %q is already used by other %s of the organization, and the names of %s must be unique", name, rt, rt),
+	}
+}
+
+func containsResourceType(rts []ResourceType, rt ResourceType) bool {
+	for _, t := range rts {
+		if t == rt {
+			return true
+		}
+	}
+	return false
+}
+
+func joinResourceTypes(rts []ResourceType) string {
+	ss := make([]string, len(rts))
+	for i, rt := range rts {
+		ss[i] = string(rt)
+	}
+	return strings.Join(ss, ", ")
+}
@@ -0,0 +1,41 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestNamePolicy_Valid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy influxdb.NamePolicy
+		valid  bool
+	}{
+		{name: "default", policy: influxdb.DefaultNamePolicy, valid: true},
+		{
+			name:   "tasks and dashboards",
+			policy: influxdb.NamePolicy{UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.TasksResourceType, influxdb.DashboardsResourceType}},
+			valid:  true,
+		},
+		{
+			name:   "buckets may not share names",
+			policy: influxdb.NamePolicy{UniqueNames: []influxdb.ResourceType{influxdb.TasksResourceType}},
+		},
+		{
+			name:   "unsupported resource",
+			policy: influxdb.NamePolicy{UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.UsersResourceType}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Valid()
+			if tt.valid && err != nil {
+				t.Errorf("expected the policy to be valid, got %v", err)
+			}
+			if !tt.valid && influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected the policy to be invalid, got %v", err)
+			}
+		})
+	}
+}
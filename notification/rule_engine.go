@@ -0,0 +1,329 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// DefaultRuleEngineDelay is how long the rule engine leaves the tasks of
+// checks to write their statuses before it reads them.
+const DefaultRuleEngineDelay = 10 * time.Second
+
+// RuleEngine sends the notifications of the rules of organizations. It reads
+// the statuses the checks of each organization with an active rule wrote
+// since it last looked, and every status that changes the level of its
+// series is matched against the rules of the organization.
+//
+// The engine starts reading the statuses of an organization at the time it
+// first sees an active rule of it, so transitions are only notified once
+// the engine runs. Transitions that a silence of the organization matches
+// at the time of their status are not notified.
+type RuleEngine struct {
+	RuleService     influxdb.NotificationRuleService
+	EndpointService influxdb.NotificationEndpointService
+	StatusService   influxdb.CheckStatusService
+	SilenceService  influxdb.SilenceService
+	SentService     influxdb.SentNotificationService
+	Sender          influxdb.NotificationSender
+
+	// Delay is subtracted from the time up to which statuses are read.
+	Delay time.Duration
+
+	now    func() time.Time
+	logger *zap.Logger
+	orgs   map[influxdb.ID]*orgState
+}
+
+// orgState is what the engine knows of the statuses of an organization.
+type orgState struct {
+	// read is the time up to which statuses were read.
+	read time.Time
+	// levels is the last level of each series, by series key.
+	levels map[string]influxdb.CheckLevel
+}
+
+// NewRuleEngine returns a RuleEngine that reads statuses with statuses,
+// sends notifications with sender and records them with sent.
+func NewRuleEngine(rules influxdb.NotificationRuleService, endpoints influxdb.NotificationEndpointService, statuses influxdb.CheckStatusService, silences influxdb.SilenceService, sent influxdb.SentNotificationService, sender influxdb.NotificationSender, logger *zap.Logger) *RuleEngine {
+	return &RuleEngine{
+		RuleService:     rules,
+		EndpointService: endpoints,
+		StatusService:   statuses,
+		SilenceService:  silences,
+		SentService:     sent,
+		Sender:          sender,
+		Delay:           DefaultRuleEngineDelay,
+		now:             time.Now,
+		logger:          logger.With(zap.String("service", "notification_rules")),
+		orgs:            map[influxdb.ID]*orgState{},
+	}
+}
+
+// Run evaluates the rules every interval until ctx is done.
+func (e *RuleEngine) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.evaluate(ctx)
+		}
+	}
+}
+
+// evaluate sends the notifications of the statuses written since the last
+// evaluation.
+func (e *RuleEngine) evaluate(ctx context.Context) {
+	rules, _, err := e.RuleService.FindNotificationRules(ctx, influxdb.NotificationRuleFilter{})
+	if err != nil {
+		e.logger.Error("Unable to find notification rules", zap.Error(err))
+		return
+	}
+
+	byOrg := map[influxdb.ID][]*influxdb.NotificationRule{}
+	for _, r := range rules {
+		if r.Status == influxdb.Active {
+			byOrg[r.OrgID] = append(byOrg[r.OrgID], r)
+		}
+	}
+
+	// Organizations without active rules are forgotten, and start over
+	// once they have one again.
+	for orgID := range e.orgs {
+		if _, ok := byOrg[orgID]; !ok {
+			delete(e.orgs, orgID)
+		}
+	}
+
+	stop := e.now().Add(-e.Delay)
+	for orgID, rules := range byOrg {
+		if err := e.evaluateOrg(ctx, orgID, rules, stop); err != nil {
+			e.logger.Error("Unable to evaluate notification rules", zap.String("org_id", orgID.String()), zap.Error(err))
+		}
+	}
+}
+
+// statusAuthorization returns the authorization the engine reads the
+// statuses of an organization with.
+func statusAuthorization(orgID influxdb.ID) (*influxdb.Authorization, error) {
+	p, err := influxdb.NewPermission(influxdb.ReadAction, influxdb.BucketsResourceType, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return &influxdb.Authorization{
+		OrgID:       orgID,
+		Status:      influxdb.Active,
+		Permissions: []influxdb.Permission{*p},
+	}, nil
+}
+
+// evaluateOrg matches the statuses the organization wrote up to stop against its rules.
+func (e *RuleEngine) evaluateOrg(ctx context.Context, orgID influxdb.ID, rules []*influxdb.NotificationRule, stop time.Time) error {
+	auth, err := statusAuthorization(orgID)
+	if err != nil {
+		return err
+	}
+	ctx = icontext.SetAuthorizer(ctx, auth)
+
+	st, ok := e.orgs[orgID]
+	if !ok {
+		// The last status of each series is its level when the engine starts.
+		statuses, err := e.StatusService.FindCheckStatuses(ctx, influxdb.CheckStatusFilter{
+			OrgID: orgID,
+			Start: stop.Add(-influxdb.MonitoringBucketRetention),
+			Stop:  stop,
+			Last:  true,
+		})
+		if err != nil {
+			return err
+		}
+
+		st = &orgState{read: stop, levels: map[string]influxdb.CheckLevel{}}
+		for _, s := range statuses {
+			st.levels[s.SeriesKey()] = s.Level
+		}
+		e.orgs[orgID] = st
+		return nil
+	}
+	if !stop.After(st.read) {
+		return nil
+	}
+
+	statuses, err := e.StatusService.FindCheckStatuses(ctx, influxdb.CheckStatusFilter{
+		OrgID: orgID,
+		Start: st.read,
+		Stop:  stop,
+	})
+	if err != nil {
+		return err
+	}
+	st.read = stop
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	silences, _, err := e.SilenceService.FindSilences(ctx, influxdb.SilenceFilter{OrgID: &orgID})
+	if err != nil {
+		return err
+	}
+
+	ev := &evaluation{
+		engine:    e,
+		sent:      map[influxdb.ID]map[string]int{},
+		endpoints: map[influxdb.ID]*influxdb.NotificationEndpoint{},
+	}
+	for _, s := range statuses {
+		key := s.SeriesKey()
+		previous, seen := st.levels[key]
+		st.levels[key] = s.Level
+		if seen && previous == s.Level {
+			continue
+		}
+
+		for _, r := range rules {
+			if !r.Matches(s, previous) {
+				continue
+			}
+			if sl := silencedBy(silences, r, s); sl != nil {
+				e.logger.Debug("Notification silenced", zap.String("notification_rule_id", r.ID.String()), zap.String("series", key), zap.String("silence_id", sl.ID.String()))
+				continue
+			}
+			ev.notify(ctx, r, s, key, previous)
+		}
+	}
+	return nil
+}
+
+// silencedBy returns the first of silences that suppresses the notification
+// of the rule for s, or nil.
+func silencedBy(silences []*influxdb.Silence, r *influxdb.NotificationRule, s *influxdb.CheckStatus) *influxdb.Silence {
+	for _, sl := range silences {
+		if sl.Matches(r.ID, s) {
+			return sl
+		}
+	}
+	return nil
+}
+
+// evaluation holds what is looked up once per evaluation of an organization.
+type evaluation struct {
+	engine *RuleEngine
+	// sent is the number of notifications each rule sent to each series
+	// within its limit interval.
+	sent      map[influxdb.ID]map[string]int
+	endpoints map[influxdb.ID]*influxdb.NotificationEndpoint
+}
+
+// sentBy returns the number of notifications the rule sent to each series
+// within its limit interval.
+func (ev *evaluation) sentBy(ctx context.Context, r *influxdb.NotificationRule) (map[string]int, error) {
+	if sent, ok := ev.sent[r.ID]; ok {
+		return sent, nil
+	}
+
+	ns, _, err := ev.engine.SentService.FindSentNotifications(ctx, influxdb.SentNotificationFilter{
+		OrgID:  &r.OrgID,
+		RuleID: &r.ID,
+		Since:  ev.engine.now().Add(-time.Duration(r.LimitEvery) * time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sent := map[string]int{}
+	for _, n := range ns {
+		if n.Error == "" {
+			sent[n.Series]++
+		}
+	}
+	ev.sent[r.ID] = sent
+	return sent, nil
+}
+
+func (ev *evaluation) endpoint(ctx context.Context, id influxdb.ID) (*influxdb.NotificationEndpoint, error) {
+	if e, ok := ev.endpoints[id]; ok {
+		return e, nil
+	}
+	e, err := ev.engine.EndpointService.FindNotificationEndpointByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ev.endpoints[id] = e
+	return e, nil
+}
+
+// notify sends the notification of the rule for the transition of the
+// series of s from the previous level, unless the rule reached its limit
+// for the series, and records it.
+func (ev *evaluation) notify(ctx context.Context, r *influxdb.NotificationRule, s *influxdb.CheckStatus, key string, previous influxdb.CheckLevel) {
+	logger := ev.engine.logger.With(zap.String("notification_rule_id", r.ID.String()), zap.String("series", key))
+
+	sent, err := ev.sentBy(ctx, r)
+	if err != nil {
+		logger.Error("Unable to find sent notifications", zap.Error(err))
+		return
+	}
+	if r.Limit > 0 && sent[key] >= r.Limit {
+		logger.Debug("Notification rule reached its limit", zap.Int("limit", r.Limit), zap.Int("limit_every", r.LimitEvery))
+		return
+	}
+
+	endpoint, err := ev.endpoint(ctx, r.EndpointID)
+	if err != nil {
+		logger.Error("Unable to find notification endpoint", zap.String("notification_endpoint_id", r.EndpointID.String()), zap.Error(err))
+		return
+	}
+	if endpoint.Status != influxdb.Active {
+		return
+	}
+
+	n := &influxdb.SentNotification{
+		OrgID:         r.OrgID,
+		RuleID:        r.ID,
+		EndpointID:    r.EndpointID,
+		CheckID:       s.CheckID,
+		Series:        key,
+		Level:         s.Level,
+		PreviousLevel: previous,
+		Message:       r.Message(s, previous),
+		StatusTime:    s.Time,
+	}
+	err = ev.engine.Sender.SendNotification(ctx, endpoint, influxdb.Notification{
+		Level:   notificationLevel(s.Level),
+		Message: n.Message,
+		Source:  key,
+		Time:    s.Time,
+	})
+	if err != nil {
+		logger.Info("Unable to send notification", zap.Error(err))
+		n.Error = err.Error()
+	} else {
+		sent[key]++
+	}
+
+	n.SentAt = ev.engine.now()
+	if err := ev.engine.SentService.AddSentNotification(ctx, n); err != nil {
+		logger.Error("Unable to record sent notification", zap.Error(err))
+	}
+}
+
+// notificationLevel returns the notification level of a check level.
+func notificationLevel(l influxdb.CheckLevel) string {
+	switch l {
+	case influxdb.CheckLevelCrit:
+		return influxdb.NotificationLevelCrit
+	case influxdb.CheckLevelWarn:
+		return influxdb.NotificationLevelWarn
+	case influxdb.CheckLevelOK:
+		return influxdb.NotificationLevelOK
+	default:
+		return influxdb.NotificationLevelInfo
+	}
+}
@@ -0,0 +1,207 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+	"go.uber.org/zap"
+)
+
+func TestRuleEngine_evaluate(t *testing.T) {
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	status := func(host string, level influxdb.CheckLevel, minutes int) *influxdb.CheckStatus {
+		return &influxdb.CheckStatus{
+			CheckID:   2,
+			CheckName: "cpu",
+			Level:     level,
+			Message:   "cpu is " + string(level),
+			Time:      now.Add(time.Duration(minutes) * time.Minute),
+			Tags:      map[string]string{"host": host},
+		}
+	}
+
+	rules := mock.NewNotificationRuleService()
+	rules.FindNotificationRulesFn = func(ctx context.Context, filter influxdb.NotificationRuleFilter, opt ...influxdb.FindOptions) ([]*influxdb.NotificationRule, int, error) {
+		return []*influxdb.NotificationRule{
+			{
+				ID:         1,
+				OrgID:      10,
+				Name:       "crit",
+				Status:     influxdb.Active,
+				EndpointID: 3,
+				TagRules:   []influxdb.TagRule{{Key: "host", Value: "^a", Operator: influxdb.TagRuleEqualRegex}},
+				StatusRules: []influxdb.StatusRule{
+					{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelCrit, Operation: influxdb.LevelRuleEqual}},
+				},
+				MessageTemplate: "${host} went from ${_previous_level} to ${_level}",
+				Limit:           1,
+				LimitEvery:      900,
+			},
+			{
+				ID:         4,
+				OrgID:      10,
+				Name:       "inactive",
+				Status:     influxdb.Inactive,
+				EndpointID: 3,
+				StatusRules: []influxdb.StatusRule{
+					{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelCrit, Operation: influxdb.LevelRuleEqual}},
+				},
+			},
+		}, 2, nil
+	}
+
+	endpoints := mock.NewNotificationEndpointService()
+	endpoints.FindNotificationEndpointByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.NotificationEndpoint, error) {
+		return &influxdb.NotificationEndpoint{ID: id, OrgID: 10, Name: "ops", Status: influxdb.Active, Type: influxdb.SlackNotificationEndpoint}, nil
+	}
+
+	var filters []influxdb.CheckStatusFilter
+	var windows [][]*influxdb.CheckStatus
+	statuses := mock.NewCheckStatusService()
+	statuses.FindCheckStatusesFn = func(ctx context.Context, filter influxdb.CheckStatusFilter) ([]*influxdb.CheckStatus, error) {
+		a, err := icontext.GetAuthorizer(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if a.(*influxdb.Authorization).OrgID != 10 {
+			t.Errorf("expected statuses to be read with an authorization of the org, got %+v", a)
+		}
+		filters = append(filters, filter)
+		ss := windows[0]
+		windows = windows[1:]
+		return ss, nil
+	}
+
+	start := now
+	silences := mock.NewSilenceService()
+	silences.FindSilencesFn = func(ctx context.Context, filter influxdb.SilenceFilter, opt ...influxdb.FindOptions) ([]*influxdb.Silence, int, error) {
+		return []*influxdb.Silence{
+			{
+				ID:       8,
+				OrgID:    10,
+				TagRules: []influxdb.TagRule{{Key: "host", Value: "abcd", Operator: influxdb.TagRuleEqual}},
+				StartsAt: start,
+				EndsAt:   start.Add(time.Hour),
+			},
+			// The silence of another rule.
+			{
+				ID:       9,
+				OrgID:    10,
+				RuleID:   4,
+				TagRules: []influxdb.TagRule{{Key: "host", Value: "abc", Operator: influxdb.TagRuleEqual}},
+				StartsAt: start,
+				EndsAt:   start.Add(time.Hour),
+			},
+			// The silence ended before the statuses.
+			{
+				ID:       10,
+				OrgID:    10,
+				TagRules: []influxdb.TagRule{{Key: "host", Value: "abc", Operator: influxdb.TagRuleEqual}},
+				StartsAt: start.Add(-time.Hour),
+				EndsAt:   start,
+			},
+		}, 3, nil
+	}
+
+	var history []*influxdb.SentNotification
+	sent := mock.NewSentNotificationService()
+	sent.AddSentNotificationFn = func(ctx context.Context, n *influxdb.SentNotification) error {
+		history = append(history, n)
+		return nil
+	}
+	sent.FindSentNotificationsFn = func(ctx context.Context, filter influxdb.SentNotificationFilter, opt ...influxdb.FindOptions) ([]*influxdb.SentNotification, int, error) {
+		var ns []*influxdb.SentNotification
+		for _, n := range history {
+			if n.RuleID == *filter.RuleID && !n.SentAt.Before(filter.Since) {
+				ns = append(ns, n)
+			}
+		}
+		return ns, len(ns), nil
+	}
+
+	var notifications []influxdb.Notification
+	var sendErr error
+	sender := mock.NewNotificationSender()
+	sender.SendNotificationFn = func(ctx context.Context, e *influxdb.NotificationEndpoint, n influxdb.Notification) error {
+		notifications = append(notifications, n)
+		return sendErr
+	}
+
+	e := NewRuleEngine(rules, endpoints, statuses, silences, sent, sender, zap.NewNop())
+	e.Delay = 0
+	e.now = func() time.Time { return now }
+
+	windows = [][]*influxdb.CheckStatus{
+		// The level of each series when the engine starts.
+		{status("a", influxdb.CheckLevelOK, -5)},
+		{
+			status("a", influxdb.CheckLevelCrit, 1),
+			// The level of the series does not change.
+			status("a", influxdb.CheckLevelCrit, 2),
+			status("a", influxdb.CheckLevelOK, 3),
+			// The rule reached its limit for the series.
+			status("a", influxdb.CheckLevelCrit, 4),
+			// The tag rule does not match.
+			status("b", influxdb.CheckLevelCrit, 4),
+			// A series seen for the first time has no previous level.
+			status("ab", influxdb.CheckLevelCrit, 4),
+		},
+	}
+	e.evaluate(context.Background())
+	if len(notifications) != 0 {
+		t.Fatalf("expected the statuses before the engine started not to be notified, got %+v", notifications)
+	}
+	if f := filters[0]; !f.Last || f.OrgID != 10 || !f.Stop.Equal(now) {
+		t.Fatalf("expected the last statuses of the org to be read, got %+v", f)
+	}
+
+	now = now.Add(5 * time.Minute)
+	e.evaluate(context.Background())
+	if f := filters[1]; f.Last || !f.Start.Equal(now.Add(-5*time.Minute)) || !f.Stop.Equal(now) {
+		t.Fatalf("expected the statuses since the last evaluation to be read, got %+v", f)
+	}
+
+	if len(notifications) != 2 {
+		t.Fatalf("expected 2 notifications, got %+v", notifications)
+	}
+	if n := notifications[0]; n.Level != influxdb.NotificationLevelCrit || n.Message != "a went from OK to CRIT" || n.Source != "0000000000000002,host=a" {
+		t.Fatalf("unexpected notification %+v", n)
+	}
+	if n := notifications[1]; n.Message != "ab went from  to CRIT" {
+		t.Fatalf("unexpected notification %+v", n)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 sent notifications, got %+v", history)
+	}
+	if n := history[0]; n.RuleID != 1 || n.EndpointID != 3 || n.CheckID != 2 || n.PreviousLevel != influxdb.CheckLevelOK || !n.SentAt.Equal(now) || n.Error != "" {
+		t.Fatalf("unexpected sent notification %+v", n)
+	}
+
+	// The limit holds across evaluations, and notifications that could not be
+	// sent are recorded with their error.
+	windows = [][]*influxdb.CheckStatus{{
+		status("a", influxdb.CheckLevelOK, 6),
+		status("a", influxdb.CheckLevelCrit, 7),
+		status("ab", influxdb.CheckLevelOK, 7),
+		status("abc", influxdb.CheckLevelCrit, 7),
+		// The series is silenced.
+		status("abcd", influxdb.CheckLevelCrit, 7),
+	}}
+	sendErr = errors.New("slack is down")
+	now = now.Add(5 * time.Minute)
+	e.evaluate(context.Background())
+	if len(notifications) != 3 || notifications[2].Message != "abc went from  to CRIT" {
+		t.Fatalf("expected only the new series that is not silenced to be notified, got %+v", notifications)
+	}
+	if n := history[2]; n.Series != "0000000000000002,host=abc" || n.Error != "slack is down" {
+		t.Fatalf("expected the failed notification to be recorded, got %+v", n)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected silenced notifications not to be recorded, got %+v", history)
+	}
+}
@@ -0,0 +1,228 @@
+// Package notification sends notifications to the slack, pagerduty and http
+// notification endpoints of organizations.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// DefaultTimeout is how long a notification is given to be sent.
+const DefaultTimeout = 30 * time.Second
+
+var _ influxdb.NotificationSender = (*Sender)(nil)
+
+// Sender sends notifications to notification endpoints. The credentials of
+// an endpoint are loaded from the secrets of its organization when a
+// notification is sent, so endpoints use the latest value of their secrets.
+type Sender struct {
+	SecretService influxdb.SecretService
+	Client        *http.Client
+}
+
+// NewSender returns a Sender loading credentials from secrets.
+func NewSender(secrets influxdb.SecretService) *Sender {
+	return &Sender{
+		SecretService: secrets,
+		Client:        &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// SendNotification sends n to the notification endpoint e.
+func (s *Sender) SendNotification(ctx context.Context, e *influxdb.NotificationEndpoint, n influxdb.Notification) error {
+	if e.Status == influxdb.Inactive {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("notification endpoint %s is inactive", e.Name),
+		}
+	}
+	if n.Time.IsZero() {
+		n.Time = time.Now().UTC()
+	}
+
+	var req *http.Request
+	var err error
+	switch e.Type {
+	case influxdb.SlackNotificationEndpoint:
+		req, err = s.slackRequest(ctx, e, n)
+	case influxdb.PagerDutyNotificationEndpoint:
+		req, err = s.pagerDutyRequest(ctx, e, n)
+	case influxdb.HTTPNotificationEndpoint:
+		req, err = s.httpRequest(ctx, e, n)
+	default:
+		err = &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid notification endpoint type %q", e.Type),
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.do(req.WithContext(ctx))
+}
+
+// loadSecret returns the value of the credential f of e, or "" when e has none.
+func (s *Sender) loadSecret(ctx context.Context, e *influxdb.NotificationEndpoint, f *influxdb.SecretField) (string, error) {
+	if f.Empty() {
+		return "", nil
+	}
+	if f.Value != nil {
+		return *f.Value, nil
+	}
+	return s.SecretService.LoadSecret(ctx, e.OrgID, f.Key)
+}
+
+func newJSONRequest(method, url string, v interface{}) (*http.Request, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(b))
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid notification endpoint url",
+			Err:  err,
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func (s *Sender) slackRequest(ctx context.Context, e *influxdb.NotificationEndpoint, n influxdb.Notification) (*http.Request, error) {
+	token, err := s.loadSecret(ctx, e, e.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	url := e.URL
+	if url == "" {
+		url = "https://slack.com/api/chat.postMessage"
+	}
+	req, err := newJSONRequest("POST", url, map[string]interface{}{
+		"text": fmt.Sprintf("[%s] %s", n.Level, n.Message),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// pagerDutySeverity maps notification levels to pagerduty event severities.
+func pagerDutySeverity(level string) string {
+	switch level {
+	case influxdb.NotificationLevelCrit:
+		return "critical"
+	case influxdb.NotificationLevelWarn:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func (s *Sender) pagerDutyRequest(ctx context.Context, e *influxdb.NotificationEndpoint, n influxdb.Notification) (*http.Request, error) {
+	routingKey, err := s.loadSecret(ctx, e, e.RoutingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	action := "trigger"
+	if n.Level == influxdb.NotificationLevelOK {
+		action = "resolve"
+	}
+	source := n.Source
+	if source == "" {
+		source = e.Name
+	}
+
+	url := e.URL
+	if url == "" {
+		url = influxdb.DefaultPagerDutyURL
+	}
+	return newJSONRequest("POST", url, map[string]interface{}{
+		"routing_key":  routingKey,
+		"event_action": action,
+		"dedup_key":    source,
+		"client":       "influxdata",
+		"client_url":   e.ClientURL,
+		"payload": map[string]interface{}{
+			"summary":   n.Message,
+			"source":    source,
+			"severity":  pagerDutySeverity(n.Level),
+			"timestamp": n.Time.Format(time.RFC3339Nano),
+		},
+	})
+}
+
+func (s *Sender) httpRequest(ctx context.Context, e *influxdb.NotificationEndpoint, n influxdb.Notification) (*http.Request, error) {
+	req, err := newJSONRequest(e.Method, e.URL, n)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range e.Headers {
+		req.Header.Set(k, v)
+	}
+
+	switch e.AuthMethod {
+	case influxdb.HTTPAuthBasic:
+		username, err := s.loadSecret(ctx, e, e.Username)
+		if err != nil {
+			return nil, err
+		}
+		password, err := s.loadSecret(ctx, e, e.Password)
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(username, password)
+	case influxdb.HTTPAuthBearer:
+		token, err := s.loadSecret(ctx, e, e.Token)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+func (s *Sender) do(req *http.Request) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  "failed to send notification",
+			Err:  err,
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &influxdb.Error{
+			Code: influxdb.EUnavailable,
+			Msg:  fmt.Sprintf("notification endpoint responded with %s: %s", resp.Status, bytes.TrimSpace(body)),
+		}
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,183 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/notification"
+)
+
+func newSecretService(secrets map[string]string) *mock.SecretService {
+	s := mock.NewSecretService()
+	s.LoadSecretFn = func(ctx context.Context, orgID influxdb.ID, k string) (string, error) {
+		v, ok := secrets[k]
+		if !ok {
+			return "", &influxdb.Error{Code: influxdb.ENotFound, Msg: influxdb.ErrSecretNotFound}
+		}
+		return v, nil
+	}
+	return s
+}
+
+type request struct {
+	method string
+	header http.Header
+	body   map[string]interface{}
+}
+
+func newServer(t *testing.T, status int, reqs *[]request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode notification: %v", err)
+		}
+		*reqs = append(*reqs, request{method: r.Method, header: r.Header, body: body})
+		w.WriteHeader(status)
+	}))
+}
+
+func TestSender_SendNotification(t *testing.T) {
+	n := influxdb.Notification{
+		Level:   influxdb.NotificationLevelCrit,
+		Message: "cpu is high",
+		Time:    time.Date(2019, 8, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		endpoint influxdb.NotificationEndpoint
+		check    func(t *testing.T, r request)
+	}{
+		{
+			name: "slack with a token",
+			endpoint: influxdb.NotificationEndpoint{
+				Type:  influxdb.SlackNotificationEndpoint,
+				Token: &influxdb.SecretField{Key: "slack-token"},
+			},
+			check: func(t *testing.T, r request) {
+				if got := r.header.Get("Authorization"); got != "Bearer s3cr3t" {
+					t.Errorf("got authorization %q", got)
+				}
+				if got := r.body["text"]; got != "[crit] cpu is high" {
+					t.Errorf("got text %v", got)
+				}
+			},
+		},
+		{
+			name: "pagerduty",
+			endpoint: influxdb.NotificationEndpoint{
+				Name:       "oncall",
+				Type:       influxdb.PagerDutyNotificationEndpoint,
+				RoutingKey: &influxdb.SecretField{Key: "pd-key"},
+			},
+			check: func(t *testing.T, r request) {
+				if got := r.body["routing_key"]; got != "abc123" {
+					t.Errorf("got routing key %v", got)
+				}
+				if got := r.body["event_action"]; got != "trigger" {
+					t.Errorf("got event action %v", got)
+				}
+				payload := r.body["payload"].(map[string]interface{})
+				if payload["severity"] != "critical" || payload["summary"] != "cpu is high" || payload["source"] != "oncall" {
+					t.Errorf("got payload %v", payload)
+				}
+			},
+		},
+		{
+			name: "http with basic auth and headers",
+			endpoint: influxdb.NotificationEndpoint{
+				Type:       influxdb.HTTPNotificationEndpoint,
+				Method:     "PUT",
+				AuthMethod: influxdb.HTTPAuthBasic,
+				Username:   &influxdb.SecretField{Key: "http-user"},
+				Password:   &influxdb.SecretField{Key: "http-pass"},
+				Headers:    map[string]string{"X-Team": "ops"},
+			},
+			check: func(t *testing.T, r request) {
+				if r.method != "PUT" {
+					t.Errorf("got method %s", r.method)
+				}
+				req := &http.Request{Header: r.header}
+				if u, p, ok := req.BasicAuth(); !ok || u != "admin" || p != "hunter2" {
+					t.Errorf("got basic auth %q %q %v", u, p, ok)
+				}
+				if got := r.header.Get("X-Team"); got != "ops" {
+					t.Errorf("got header %q", got)
+				}
+				if r.body["level"] != "crit" || r.body["message"] != "cpu is high" {
+					t.Errorf("got body %v", r.body)
+				}
+			},
+		},
+	}
+
+	secrets := newSecretService(map[string]string{
+		"slack-token": "s3cr3t",
+		"pd-key":      "abc123",
+		"http-user":   "admin",
+		"http-pass":   "hunter2",
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reqs []request
+			srv := newServer(t, http.StatusOK, &reqs)
+			defer srv.Close()
+
+			e := tt.endpoint
+			e.OrgID = 1
+			e.Status = influxdb.Active
+			e.URL = srv.URL
+
+			if err := notification.NewSender(secrets).SendNotification(context.Background(), &e, n); err != nil {
+				t.Fatalf("failed to send notification: %v", err)
+			}
+			if len(reqs) != 1 {
+				t.Fatalf("expected one request, got %d", len(reqs))
+			}
+			tt.check(t, reqs[0])
+		})
+	}
+}
+
+func TestSender_SendNotificationErrors(t *testing.T) {
+	var reqs []request
+	srv := newServer(t, http.StatusForbidden, &reqs)
+	defer srv.Close()
+
+	s := notification.NewSender(newSecretService(nil))
+	e := &influxdb.NotificationEndpoint{
+		OrgID:      1,
+		Status:     influxdb.Active,
+		Type:       influxdb.HTTPNotificationEndpoint,
+		URL:        srv.URL,
+		Method:     "POST",
+		AuthMethod: influxdb.HTTPAuthNone,
+	}
+	n := influxdb.Notification{Level: influxdb.NotificationLevelInfo, Message: "test"}
+
+	if err := s.SendNotification(context.Background(), e, n); influxdb.ErrorCode(err) != influxdb.EUnavailable {
+		t.Errorf("expected a rejected notification to be unavailable, got %v", err)
+	}
+
+	e.AuthMethod = influxdb.HTTPAuthBearer
+	e.Token = &influxdb.SecretField{Key: "missing"}
+	if err := s.SendNotification(context.Background(), e, n); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a missing secret to be not found, got %v", err)
+	}
+
+	e.Status = influxdb.Inactive
+	if err := s.SendNotification(context.Background(), e, n); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an inactive endpoint to be invalid, got %v", err)
+	}
+
+	if len(reqs) != 1 {
+		t.Errorf("expected one request to be sent, got %d", len(reqs))
+	}
+}
@@ -0,0 +1,289 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotificationEndpointNotFound is the error msg for a missing notification endpoint.
+const ErrNotificationEndpointNotFound = "notification endpoint not found"
+
+// ops for notification endpoint errors and op log.
+const (
+	OpFindNotificationEndpointByID = "FindNotificationEndpointByID"
+	OpFindNotificationEndpoints    = "FindNotificationEndpoints"
+	OpCreateNotificationEndpoint   = "CreateNotificationEndpoint"
+	OpUpdateNotificationEndpoint   = "UpdateNotificationEndpoint"
+	OpReplaceNotificationEndpoint  = "ReplaceNotificationEndpoint"
+	OpDeleteNotificationEndpoint   = "DeleteNotificationEndpoint"
+)
+
+// NotificationEndpointType is the kind of service a notification endpoint sends to.
+type NotificationEndpointType string
+
+// Notification endpoint types.
+const (
+	SlackNotificationEndpoint     NotificationEndpointType = "slack"
+	PagerDutyNotificationEndpoint NotificationEndpointType = "pagerduty"
+	HTTPNotificationEndpoint      NotificationEndpointType = "http"
+)
+
+// Authentication methods of http notification endpoints.
+const (
+	HTTPAuthNone   = "none"
+	HTTPAuthBasic  = "basic"
+	HTTPAuthBearer = "bearer"
+)
+
+// DefaultPagerDutyURL is where pagerduty notification endpoints send events.
+const DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// SecretField is a credential of a notification endpoint or a scraper target
+// kept in the secret store of its organization under Key. Value is only set when the
+// credential is created or changed; it is never returned.
+type SecretField struct {
+	Key   string  `json:"key,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+// Empty reports whether the field references no secret.
+func (f *SecretField) Empty() bool {
+	return f == nil || (f.Key == "" && f.Value == nil)
+}
+
+// NotificationEndpoint is a service notifications are sent to. Only the
+// fields of its type are used.
+type NotificationEndpoint struct {
+	ID          ID                       `json:"id,omitempty"`
+	OrgID       ID                       `json:"orgID,omitempty"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Status      Status                   `json:"status"`
+	Type        NotificationEndpointType `json:"type"`
+
+	// URL is the slack webhook, the pagerduty events API or the http endpoint.
+	URL string `json:"url,omitempty"`
+	// Token is the slack token or the bearer token of an http endpoint.
+	Token *SecretField `json:"token,omitempty"`
+
+	// RoutingKey is the pagerduty integration key.
+	RoutingKey *SecretField `json:"routingKey,omitempty"`
+	// ClientURL is linked from pagerduty events.
+	ClientURL string `json:"clientURL,omitempty"`
+
+	// Method is the http method of an http endpoint, POST or PUT.
+	Method string `json:"method,omitempty"`
+	// AuthMethod is none, basic or bearer.
+	AuthMethod string            `json:"authMethod,omitempty"`
+	Username   *SecretField      `json:"username,omitempty"`
+	Password   *SecretField      `json:"password,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the notification endpoint is missing what its type needs.
+func (e *NotificationEndpoint) Valid() error {
+	if strings.TrimSpace(e.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification endpoint name is required",
+		}
+	}
+	if !e.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if err := e.Status.Valid(); err != nil {
+		return err
+	}
+
+	switch e.Type {
+	case SlackNotificationEndpoint:
+		if e.URL == "" && e.Token.Empty() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "slack notification endpoint needs a url or a token",
+			}
+		}
+	case PagerDutyNotificationEndpoint:
+		if e.RoutingKey.Empty() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "pagerduty notification endpoint needs a routing key",
+			}
+		}
+	case HTTPNotificationEndpoint:
+		if e.URL == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "http notification endpoint needs a url",
+			}
+		}
+		switch e.Method {
+		case "POST", "PUT":
+		default:
+			return &Error{
+				Code: EInvalid,
+				Msg:  "http notification endpoint method must be POST or PUT",
+			}
+		}
+		switch e.AuthMethod {
+		case HTTPAuthNone:
+		case HTTPAuthBasic:
+			if e.Username.Empty() || e.Password.Empty() {
+				return &Error{
+					Code: EInvalid,
+					Msg:  "basic auth needs a username and a password",
+				}
+			}
+		case HTTPAuthBearer:
+			if e.Token.Empty() {
+				return &Error{
+					Code: EInvalid,
+					Msg:  "bearer auth needs a token",
+				}
+			}
+		default:
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid auth method %q: must be none, basic or bearer", e.AuthMethod),
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid notification endpoint type %q: must be slack, pagerduty or http", e.Type),
+		}
+	}
+
+	if e.URL != "" {
+		if _, err := url.Parse(e.URL); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "invalid notification endpoint url",
+				Err:  err,
+			}
+		}
+	}
+	return nil
+}
+
+// SecretFields returns the credentials the notification endpoint has, by name.
+func (e *NotificationEndpoint) SecretFields() map[string]*SecretField {
+	fs := map[string]*SecretField{}
+	for name, f := range map[string]*SecretField{
+		"token":      e.Token,
+		"routingKey": e.RoutingKey,
+		"username":   e.Username,
+		"password":   e.Password,
+	} {
+		if !f.Empty() {
+			fs[name] = f
+		}
+	}
+	return fs
+}
+
+// BackfillSecretKeys names the secrets of the credentials given by value
+// after the notification endpoint, so each endpoint keeps its own secrets.
+func (e *NotificationEndpoint) BackfillSecretKeys() {
+	for name, f := range e.SecretFields() {
+		if f.Key == "" && f.Value != nil {
+			f.Key = fmt.Sprintf("%s-%s", e.ID, name)
+		}
+	}
+}
+
+// NotificationEndpointUpdate is the changeset of a notification endpoint.
+type NotificationEndpointUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *Status `json:"status,omitempty"`
+}
+
+// Valid returns an error if the changeset is invalid.
+func (u NotificationEndpointUpdate) Valid() error {
+	if u.Name != nil && strings.TrimSpace(*u.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification endpoint name cannot be empty",
+		}
+	}
+	if u.Status != nil {
+		return u.Status.Valid()
+	}
+	return nil
+}
+
+// Apply applies the changeset to the notification endpoint.
+func (u NotificationEndpointUpdate) Apply(e *NotificationEndpoint) {
+	if u.Name != nil {
+		e.Name = *u.Name
+	}
+	if u.Description != nil {
+		e.Description = *u.Description
+	}
+	if u.Status != nil {
+		e.Status = *u.Status
+	}
+}
+
+// NotificationEndpointFilter selects notification endpoints.
+type NotificationEndpointFilter struct {
+	ID    *ID
+	OrgID *ID
+	Name  *string
+	Type  *NotificationEndpointType
+}
+
+// NotificationEndpointService manages the notification endpoints of organizations.
+type NotificationEndpointService interface {
+	// FindNotificationEndpointByID returns a single notification endpoint by ID.
+	FindNotificationEndpointByID(ctx context.Context, id ID) (*NotificationEndpoint, error)
+
+	// FindNotificationEndpoints returns the notification endpoints that match
+	// the filter, and the total count of matching notification endpoints.
+	FindNotificationEndpoints(ctx context.Context, filter NotificationEndpointFilter, opt ...FindOptions) ([]*NotificationEndpoint, int, error)
+
+	// CreateNotificationEndpoint creates a notification endpoint and sets its
+	// ID. Credentials given by value are put in the secret store of its
+	// organization.
+	CreateNotificationEndpoint(ctx context.Context, e *NotificationEndpoint) error
+
+	// UpdateNotificationEndpoint updates a notification endpoint with the changeset.
+	UpdateNotificationEndpoint(ctx context.Context, id ID, upd NotificationEndpointUpdate) (*NotificationEndpoint, error)
+
+	// ReplaceNotificationEndpoint replaces the configuration of a notification
+	// endpoint. Credentials given by value replace the stored ones.
+	ReplaceNotificationEndpoint(ctx context.Context, e *NotificationEndpoint) (*NotificationEndpoint, error)
+
+	// DeleteNotificationEndpoint deletes a notification endpoint and its secrets.
+	DeleteNotificationEndpoint(ctx context.Context, id ID) error
+}
+
+// Notification levels.
+const (
+	NotificationLevelOK   = "ok"
+	NotificationLevelInfo = "info"
+	NotificationLevelWarn = "warn"
+	NotificationLevelCrit = "crit"
+)
+
+// Notification is a message sent to a notification endpoint.
+type Notification struct {
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Source  string    `json:"source,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// NotificationSender sends notifications to notification endpoints.
+type NotificationSender interface {
+	SendNotification(ctx context.Context, e *NotificationEndpoint, n Notification) error
+}
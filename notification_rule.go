@@ -0,0 +1,432 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNotificationRuleNotFound is the error msg for a missing notification rule.
+const ErrNotificationRuleNotFound = "notification rule not found"
+
+// ops for notification rule errors and op log.
+const (
+	OpFindNotificationRuleByID = "FindNotificationRuleByID"
+	OpFindNotificationRules    = "FindNotificationRules"
+	OpCreateNotificationRule   = "CreateNotificationRule"
+	OpUpdateNotificationRule   = "UpdateNotificationRule"
+	OpReplaceNotificationRule  = "ReplaceNotificationRule"
+	OpDeleteNotificationRule   = "DeleteNotificationRule"
+	OpAddSentNotification      = "AddSentNotification"
+	OpFindSentNotifications    = "FindSentNotifications"
+)
+
+// Defaults of notification rules.
+const (
+	// DefaultNotificationRuleLimit is the number of notifications a rule
+	// sends per series within its limit interval.
+	DefaultNotificationRuleLimit = 1
+	// DefaultNotificationRuleLimitEvery is the limit interval of rules in seconds.
+	DefaultNotificationRuleLimitEvery = 15 * 60
+	// DefaultNotificationRuleMessageTemplate is the message of rules without a template.
+	DefaultNotificationRuleMessageTemplate = "${_message}"
+)
+
+// DefaultSentNotificationLimit is the number of sent notifications kept in
+// the history of an organization.
+const DefaultSentNotificationLimit = 1000
+
+// TagRuleOperator is how a tag rule compares the value of a tag.
+type TagRuleOperator string
+
+// Tag rule operators.
+const (
+	TagRuleEqual         TagRuleOperator = "equal"
+	TagRuleNotEqual      TagRuleOperator = "notequal"
+	TagRuleEqualRegex    TagRuleOperator = "equalregex"
+	TagRuleNotEqualRegex TagRuleOperator = "notequalregex"
+)
+
+// TagRule matches the statuses of series by the value of a tag. A series
+// without the tag has an empty value.
+type TagRule struct {
+	Key      string          `json:"key"`
+	Value    string          `json:"value"`
+	Operator TagRuleOperator `json:"operator"`
+}
+
+// Valid returns an error if the tag rule can't match statuses.
+func (r TagRule) Valid() error {
+	if r.Key == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "tag rule key is required",
+		}
+	}
+	switch r.Operator {
+	case TagRuleEqual, TagRuleNotEqual:
+	case TagRuleEqualRegex, TagRuleNotEqualRegex:
+		if _, err := regexp.Compile(r.Value); err != nil {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid regular expression of tag rule %s", r.Key),
+				Err:  err,
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid tag rule operator %q: must be equal, notequal, equalregex or notequalregex", r.Operator),
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the tags match the rule.
+func (r TagRule) Matches(tags map[string]string) bool {
+	v := tags[r.Key]
+	switch r.Operator {
+	case TagRuleEqual:
+		return v == r.Value
+	case TagRuleNotEqual:
+		return v != r.Value
+	case TagRuleEqualRegex, TagRuleNotEqualRegex:
+		re, err := regexp.Compile(r.Value)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(v) == (r.Operator == TagRuleEqualRegex)
+	}
+	return false
+}
+
+// Level rule operations.
+const (
+	LevelRuleEqual    = "equal"
+	LevelRuleNotEqual = "notequal"
+)
+
+// LevelRule matches a check level.
+type LevelRule struct {
+	Level     CheckLevel `json:"level"`
+	Operation string     `json:"operation"`
+}
+
+// Valid returns an error if the level rule can't match levels.
+func (r LevelRule) Valid() error {
+	if err := r.Level.Valid(); err != nil {
+		return err
+	}
+	switch r.Operation {
+	case LevelRuleEqual, LevelRuleNotEqual:
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid level rule operation %q: must be equal or notequal", r.Operation),
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the level matches the rule.
+func (r LevelRule) Matches(level CheckLevel) bool {
+	return (level == r.Level) == (r.Operation == LevelRuleEqual)
+}
+
+// StatusRule matches the transition of a series from its previous level to
+// its current one. Without a previous level rule, every transition to a
+// matching current level matches.
+type StatusRule struct {
+	CurrentLevel  LevelRule  `json:"currentLevel"`
+	PreviousLevel *LevelRule `json:"previousLevel,omitempty"`
+}
+
+// Valid returns an error if the status rule can't match transitions.
+func (r StatusRule) Valid() error {
+	if err := r.CurrentLevel.Valid(); err != nil {
+		return err
+	}
+	if r.PreviousLevel != nil {
+		return r.PreviousLevel.Valid()
+	}
+	return nil
+}
+
+// Matches reports whether the transition from previous to current matches
+// the rule. The previous level of a series without one is empty, which only
+// matches a previous level rule that is not equal to a level.
+func (r StatusRule) Matches(previous, current CheckLevel) bool {
+	if !r.CurrentLevel.Matches(current) {
+		return false
+	}
+	return r.PreviousLevel == nil || r.PreviousLevel.Matches(previous)
+}
+
+// NotificationRule sends a notification to a notification endpoint when a
+// series of check statuses changes level as one of its status rules
+// describes. A rule sends at most Limit notifications per series every
+// LimitEvery seconds.
+type NotificationRule struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Status      Status `json:"status"`
+	EndpointID  ID     `json:"endpointID,omitempty"`
+	// CheckID restricts the rule to the statuses of one check.
+	CheckID ID `json:"checkID,omitempty"`
+
+	TagRules    []TagRule    `json:"tagRules,omitempty"`
+	StatusRules []StatusRule `json:"statusRules"`
+
+	// MessageTemplate is the message of notifications, where ${column} is
+	// replaced by the value of a column of the status.
+	MessageTemplate string `json:"messageTemplate,omitempty"`
+
+	Limit      int `json:"limit,omitempty"`
+	LimitEvery int `json:"limitEvery,omitempty"`
+
+	CRUDLog
+}
+
+// SetDefaults sets the limits of the rule that are not set.
+func (r *NotificationRule) SetDefaults() {
+	if r.Status == "" {
+		r.Status = Active
+	}
+	if r.Limit == 0 {
+		r.Limit = DefaultNotificationRuleLimit
+	}
+	if r.LimitEvery == 0 {
+		r.LimitEvery = DefaultNotificationRuleLimitEvery
+	}
+}
+
+// Valid returns an error if the notification rule is missing what it needs.
+func (r *NotificationRule) Valid() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification rule name is required",
+		}
+	}
+	if !r.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if !r.EndpointID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "endpointID is required",
+		}
+	}
+	if err := r.Status.Valid(); err != nil {
+		return err
+	}
+	if len(r.StatusRules) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification rule needs at least one status rule",
+		}
+	}
+	for _, sr := range r.StatusRules {
+		if err := sr.Valid(); err != nil {
+			return err
+		}
+	}
+	for _, tr := range r.TagRules {
+		if err := tr.Valid(); err != nil {
+			return err
+		}
+	}
+	if r.Limit < 0 || r.LimitEvery < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "limit and limitEvery cannot be negative",
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the transition of the series of status from the
+// previous level matches the rule.
+func (r *NotificationRule) Matches(status *CheckStatus, previous CheckLevel) bool {
+	if r.CheckID.Valid() && r.CheckID != status.CheckID {
+		return false
+	}
+	for _, tr := range r.TagRules {
+		if !tr.Matches(status.Tags) {
+			return false
+		}
+	}
+	for _, sr := range r.StatusRules {
+		if sr.Matches(previous, status.Level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Message renders the message template of the rule for the transition of
+// the series of status from the previous level. Besides the tags of the
+// series, the template can use _check_id, _check_name, _level,
+// _previous_level, _message, _time and _notification_rule_name.
+func (r *NotificationRule) Message(status *CheckStatus, previous CheckLevel) string {
+	tmpl := r.MessageTemplate
+	if tmpl == "" {
+		tmpl = DefaultNotificationRuleMessageTemplate
+	}
+
+	return checkMessageColumnRegexp.ReplaceAllStringFunc(tmpl, func(m string) string {
+		col := checkMessageColumnRegexp.FindStringSubmatch(m)[1]
+		switch col {
+		case "_check_id":
+			return status.CheckID.String()
+		case "_check_name":
+			return status.CheckName
+		case "_level":
+			return string(status.Level)
+		case "_previous_level":
+			return string(previous)
+		case "_message":
+			return status.Message
+		case "_time":
+			return status.Time.Format(time.RFC3339Nano)
+		case "_notification_rule_name":
+			return r.Name
+		}
+		return status.Tags[col]
+	})
+}
+
+// NotificationRuleUpdate is the changeset of a notification rule.
+type NotificationRuleUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Status      *Status `json:"status,omitempty"`
+}
+
+// Valid returns an error if the changeset is invalid.
+func (u NotificationRuleUpdate) Valid() error {
+	if u.Name != nil && strings.TrimSpace(*u.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "notification rule name cannot be empty",
+		}
+	}
+	if u.Status != nil {
+		return u.Status.Valid()
+	}
+	return nil
+}
+
+// Apply applies the changeset to the notification rule.
+func (u NotificationRuleUpdate) Apply(r *NotificationRule) {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Status != nil {
+		r.Status = *u.Status
+	}
+}
+
+// NotificationRuleFilter selects notification rules.
+type NotificationRuleFilter struct {
+	ID         *ID
+	OrgID      *ID
+	Name       *string
+	EndpointID *ID
+	CheckID    *ID
+}
+
+// NotificationRuleService manages the notification rules of organizations.
+type NotificationRuleService interface {
+	// FindNotificationRuleByID returns a single notification rule by ID.
+	FindNotificationRuleByID(ctx context.Context, id ID) (*NotificationRule, error)
+
+	// FindNotificationRules returns the notification rules that match the
+	// filter, and the total count of matching notification rules.
+	FindNotificationRules(ctx context.Context, filter NotificationRuleFilter, opt ...FindOptions) ([]*NotificationRule, int, error)
+
+	// CreateNotificationRule creates a notification rule and sets its ID.
+	CreateNotificationRule(ctx context.Context, r *NotificationRule) error
+
+	// UpdateNotificationRule updates a notification rule with the changeset.
+	UpdateNotificationRule(ctx context.Context, id ID, upd NotificationRuleUpdate) (*NotificationRule, error)
+
+	// ReplaceNotificationRule replaces the definition of a notification rule.
+	ReplaceNotificationRule(ctx context.Context, r *NotificationRule) (*NotificationRule, error)
+
+	// DeleteNotificationRule deletes a notification rule.
+	DeleteNotificationRule(ctx context.Context, id ID) error
+}
+
+// SentNotification is a notification a rule sent, or failed to send, for
+// the transition of a series of check statuses.
+type SentNotification struct {
+	ID            ID         `json:"id"`
+	OrgID         ID         `json:"orgID"`
+	RuleID        ID         `json:"ruleID"`
+	EndpointID    ID         `json:"endpointID,omitempty"`
+	CheckID       ID         `json:"checkID,omitempty"`
+	Series        string     `json:"series"`
+	Level         CheckLevel `json:"level"`
+	PreviousLevel CheckLevel `json:"previousLevel,omitempty"`
+	Message       string     `json:"message"`
+	// StatusTime is the time of the status that changed the level.
+	StatusTime time.Time `json:"statusTime"`
+	SentAt     time.Time `json:"sentAt"`
+	// Error is why the notification could not be sent.
+	Error string `json:"error,omitempty"`
+}
+
+// SentNotificationFilter selects sent notifications.
+type SentNotificationFilter struct {
+	OrgID      *ID
+	RuleID     *ID
+	CheckID    *ID
+	EndpointID *ID
+	// Since leaves out the notifications sent before.
+	Since time.Time
+}
+
+// QueryParams converts SentNotificationFilter fields to url query params.
+func (f SentNotificationFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.OrgID != nil {
+		qp["orgID"] = []string{f.OrgID.String()}
+	}
+	if f.RuleID != nil {
+		qp["ruleID"] = []string{f.RuleID.String()}
+	}
+	if f.CheckID != nil {
+		qp["checkID"] = []string{f.CheckID.String()}
+	}
+	if f.EndpointID != nil {
+		qp["endpointID"] = []string{f.EndpointID.String()}
+	}
+	if !f.Since.IsZero() {
+		qp["since"] = []string{f.Since.Format(time.RFC3339Nano)}
+	}
+	return qp
+}
+
+// SentNotificationService keeps a rolling history of the notifications
+// sent by the rules of organizations.
+type SentNotificationService interface {
+	// AddSentNotification adds a notification to the history of its
+	// organization and sets n.ID. The oldest notifications of the
+	// organization are removed once it holds more than the retention limit.
+	AddSentNotification(ctx context.Context, n *SentNotification) error
+
+	// FindSentNotifications returns the notifications that match the filter,
+	// most recent first.
+	FindSentNotifications(ctx context.Context, filter SentNotificationFilter, opt ...FindOptions) ([]*SentNotification, int, error)
+}
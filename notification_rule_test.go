@@ -0,0 +1,118 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestNotificationRule_Matches(t *testing.T) {
+	level := func(l influxdb.CheckLevel, op string) influxdb.LevelRule {
+		return influxdb.LevelRule{Level: l, Operation: op}
+	}
+	warn := level(influxdb.CheckLevelWarn, influxdb.LevelRuleEqual)
+
+	r := &influxdb.NotificationRule{
+		CheckID: 2,
+		TagRules: []influxdb.TagRule{
+			{Key: "host", Value: "^web", Operator: influxdb.TagRuleEqualRegex},
+			{Key: "env", Value: "dev", Operator: influxdb.TagRuleNotEqual},
+		},
+		StatusRules: []influxdb.StatusRule{
+			{CurrentLevel: level(influxdb.CheckLevelCrit, influxdb.LevelRuleEqual)},
+			{CurrentLevel: level(influxdb.CheckLevelOK, influxdb.LevelRuleEqual), PreviousLevel: &warn},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		checkID  influxdb.ID
+		tags     map[string]string
+		previous influxdb.CheckLevel
+		current  influxdb.CheckLevel
+		want     bool
+	}{
+		{name: "to crit from any level", checkID: 2, tags: map[string]string{"host": "web1"}, previous: influxdb.CheckLevelOK, current: influxdb.CheckLevelCrit, want: true},
+		{name: "to crit without previous level", checkID: 2, tags: map[string]string{"host": "web1"}, current: influxdb.CheckLevelCrit, want: true},
+		{name: "from warn to ok", checkID: 2, tags: map[string]string{"host": "web1", "env": "prod"}, previous: influxdb.CheckLevelWarn, current: influxdb.CheckLevelOK, want: true},
+		{name: "from crit to ok", checkID: 2, tags: map[string]string{"host": "web1"}, previous: influxdb.CheckLevelCrit, current: influxdb.CheckLevelOK},
+		{name: "other check", checkID: 3, tags: map[string]string{"host": "web1"}, current: influxdb.CheckLevelCrit},
+		{name: "regex does not match", checkID: 2, tags: map[string]string{"host": "db1"}, current: influxdb.CheckLevelCrit},
+		{name: "excluded tag value", checkID: 2, tags: map[string]string{"host": "web1", "env": "dev"}, current: influxdb.CheckLevelCrit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &influxdb.CheckStatus{CheckID: tt.checkID, Level: tt.current, Tags: tt.tags}
+			if got := r.Matches(s, tt.previous); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationRule_Message(t *testing.T) {
+	r := &influxdb.NotificationRule{
+		Name:            "pager",
+		MessageTemplate: "[${_notification_rule_name}] ${_check_name} on ${host} went from ${ _previous_level } to ${_level} at ${_time}: ${_message}${missing}",
+	}
+	s := &influxdb.CheckStatus{
+		CheckID:   2,
+		CheckName: "cpu",
+		Level:     influxdb.CheckLevelCrit,
+		Message:   "usage is 95",
+		Time:      time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC),
+		Tags:      map[string]string{"host": "web1"},
+	}
+
+	want := "[pager] cpu on web1 went from WARN to CRIT at 2019-07-01T12:00:00Z: usage is 95"
+	if got := r.Message(s, influxdb.CheckLevelWarn); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	r.MessageTemplate = ""
+	if got := r.Message(s, influxdb.CheckLevelWarn); got != "usage is 95" {
+		t.Errorf("expected the default template to be the status message, got %q", got)
+	}
+}
+
+func TestNotificationRule_Valid(t *testing.T) {
+	valid := func() *influxdb.NotificationRule {
+		r := &influxdb.NotificationRule{
+			OrgID:      1,
+			Name:       "crit",
+			EndpointID: 2,
+			StatusRules: []influxdb.StatusRule{
+				{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelCrit, Operation: influxdb.LevelRuleEqual}},
+			},
+		}
+		r.SetDefaults()
+		return r
+	}
+	if err := valid().Valid(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *influxdb.NotificationRule)
+	}{
+		{name: "no endpoint", modify: func(r *influxdb.NotificationRule) { r.EndpointID = 0 }},
+		{name: "no status rules", modify: func(r *influxdb.NotificationRule) { r.StatusRules = nil }},
+		{name: "invalid level", modify: func(r *influxdb.NotificationRule) { r.StatusRules[0].CurrentLevel.Level = "BAD" }},
+		{name: "invalid operation", modify: func(r *influxdb.NotificationRule) { r.StatusRules[0].CurrentLevel.Operation = "gt" }},
+		{name: "invalid regex", modify: func(r *influxdb.NotificationRule) {
+			r.TagRules = []influxdb.TagRule{{Key: "host", Value: "(", Operator: influxdb.TagRuleEqualRegex}}
+		}},
+		{name: "negative limit", modify: func(r *influxdb.NotificationRule) { r.Limit = -1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(r)
+			if err := r.Valid(); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected the rule to be invalid, got %v", err)
+			}
+		})
+	}
+}
@@ -12,8 +12,23 @@ type OnboardingService interface {
 
 	// IsOnboarding determine if onboarding request is allowed.
 	IsOnboarding(ctx context.Context) (bool, error)
-	// Generate OnboardingResults.
+	// Generate OnboardingResults. Once onboarding has been completed, requests
+	// that match its results get them again so that setup can be re-run, and
+	// other requests are a conflict.
 	Generate(ctx context.Context, req *OnboardingRequest) (*OnboardingResults, error)
+	// OnboardUser creates an additional user along with an organization it owns,
+	// a bucket and a token with all access to the organization.
+	OnboardUser(ctx context.Context, req *OnboardingRequest) (*OnboardingResults, error)
+}
+
+// ErrOnboardingCompleted is the error msg for onboarding requests once
+// onboarding has been completed.
+const ErrOnboardingCompleted = "onboarding has already been completed"
+
+// OnboardUserPermissions are the permissions of the token of an additional
+// user: all access to the organization and to the user.
+func OnboardUserPermissions(orgID, userID ID) []Permission {
+	return append(OwnerPermissions(orgID), MePermissions(userID)...)
 }
 
 // OnboardingResults is a group of elements required for first run.
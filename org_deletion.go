@@ -0,0 +1,131 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// Statuses of an OrgDeletion.
+const (
+	OrgDeletionStatusQueued  = "queued"
+	OrgDeletionStatusRunning = "running"
+	OrgDeletionStatusSuccess = "success"
+	OrgDeletionStatusFailed  = "failed"
+)
+
+// Stages of an OrgDeletion, in the order they run. Resources are deleted before
+// the resources they depend on, and the organization last, so that a deletion
+// that fails midway leaves no resource without its organization, and can be
+// started again to delete the rest.
+const (
+	// OrgDeletionStageExport exports the resources and data of the organization.
+	// It only runs if the deletion was requested with an export.
+	OrgDeletionStageExport = "export"
+	// OrgDeletionStageTasks deletes the tasks, which write to buckets with tokens.
+	OrgDeletionStageTasks = "tasks"
+	// OrgDeletionStageTokens deletes the tokens, which grant access to buckets.
+	OrgDeletionStageTokens = "tokens"
+	// OrgDeletionStageBuckets deletes the buckets and their data.
+	OrgDeletionStageBuckets = "buckets"
+	// OrgDeletionStageMappings deletes the members and owners of the organization
+	// and of its tasks.
+	OrgDeletionStageMappings = "mappings"
+	// OrgDeletionStageOrganization deletes the organization itself.
+	OrgDeletionStageOrganization = "organization"
+)
+
+// ErrOrgDeletionNotFound is returned when an organization deletion is not found.
+var ErrOrgDeletionNotFound = &Error{
+	Code: ENotFound,
+	Msg:  "organization deletion not found",
+}
+
+// OrgDeletionRequest requests the deletion of an organization and all of its resources.
+type OrgDeletionRequest struct {
+	OrgID ID `json:"orgID"`
+	// Export exports the resources and data of the organization before anything is deleted.
+	Export bool `json:"export,omitempty"`
+}
+
+// Valid returns an error if the request has no organization.
+func (r OrgDeletionRequest) Valid() error {
+	if !r.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	return nil
+}
+
+// OrgDeletionStage is the progress of a stage of an organization deletion.
+type OrgDeletionStage struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Total is the number of resources of the stage, once the stage started.
+	Total int `json:"total"`
+	// Done is the number of resources the stage exported or deleted.
+	Done int `json:"done"`
+}
+
+// OrgDeletion is the handle of an asynchronous deletion of an organization.
+type OrgDeletion struct {
+	ID ID `json:"id"`
+	OrgDeletionRequest
+	OrgName string `json:"orgName"`
+
+	Status string `json:"status"`
+	// Stages lists the progress of every stage the deletion runs, in order.
+	Stages []OrgDeletionStage `json:"stages"`
+	// Error is the reason a failed deletion failed.
+	Error string `json:"error,omitempty"`
+	// ExportPath is the directory on the server the organization was exported to.
+	ExportPath string `json:"exportPath,omitempty"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// NewOrgDeletionStages returns the queued stages of a deletion for the request.
+func NewOrgDeletionStages(req OrgDeletionRequest) []OrgDeletionStage {
+	names := []string{
+		OrgDeletionStageTasks,
+		OrgDeletionStageTokens,
+		OrgDeletionStageBuckets,
+		OrgDeletionStageMappings,
+		OrgDeletionStageOrganization,
+	}
+	if req.Export {
+		names = append([]string{OrgDeletionStageExport}, names...)
+	}
+
+	stages := make([]OrgDeletionStage, len(names))
+	for i, name := range names {
+		stages[i] = OrgDeletionStage{Name: name, Status: OrgDeletionStatusQueued}
+	}
+	return stages
+}
+
+// Done returns true if the deletion finished, successfully or not.
+func (d *OrgDeletion) Done() bool {
+	return d.Status == OrgDeletionStatusSuccess || d.Status == OrgDeletionStatusFailed
+}
+
+// OrgDeletionFilter represents a set of filters that restrict the returned deletions.
+type OrgDeletionFilter struct {
+	OrgID *ID
+}
+
+// OrgDeletionService deletes organizations and all of their resources.
+type OrgDeletionService interface {
+	// DeleteOrganizationResources starts deleting the organization of the request
+	// with all of its resources and returns the handle of the deletion.
+	DeleteOrganizationResources(ctx context.Context, req OrgDeletionRequest) (*OrgDeletion, error)
+
+	// FindOrgDeletionByID returns a single deletion by ID.
+	FindOrgDeletionByID(ctx context.Context, id ID) (*OrgDeletion, error)
+
+	// FindOrgDeletions returns the deletions that match filter, most recent first.
+	FindOrgDeletions(ctx context.Context, filter OrgDeletionFilter) ([]*OrgDeletion, error)
+}
@@ -1,12 +1,26 @@
 package influxdb
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
 
 // Organization is an organization. 🎉
 type Organization struct {
 	ID          ID     `json:"id,omitempty"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// Timezone is the IANA name of the timezone calendar-aligned query
+	// windows of the organization are resolved in.
+	Timezone string `json:"timezone,omitempty"`
+	// BucketDefaults are applied to the buckets of the organization created
+	// without the values.
+	BucketDefaults *BucketDefaults `json:"bucketDefaults,omitempty"`
+	// NamePolicy is which resources of the organization must have unique names.
+	// Organizations without one have the name policy of the server.
+	NamePolicy *NamePolicy `json:"namePolicy,omitempty"`
 	CRUDLog
 }
 
@@ -57,6 +71,26 @@ type OrganizationService interface {
 type OrganizationUpdate struct {
 	Name        *string
 	Description *string `json:"description,omitempty"`
+	Timezone    *string `json:"timezone,omitempty"`
+	// BucketDefaults replaces the bucket defaults of the organization.
+	// Defaults that set no value remove them.
+	BucketDefaults *BucketDefaults `json:"bucketDefaults,omitempty"`
+	// NamePolicy replaces the name policy of the organization.
+	// A policy that applies to no resources removes it.
+	NamePolicy *NamePolicy `json:"namePolicy,omitempty"`
+}
+
+// LoadTimezone returns the location of the IANA timezone name, UTC if name is empty.
+// The local timezone of the server is not accepted, as it differs between servers.
+func LoadTimezone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unknown timezone %q", name),
+		}
+	}
+	return loc, nil
 }
 
 // ErrInvalidOrgFilter is the error indicate org filter is empty
@@ -65,8 +99,37 @@ var ErrInvalidOrgFilter = &Error{
 	Msg:  "Please provide either orgID or org",
 }
 
+// OrganizationSortKeys are the keys organizations can be sorted by.
+var OrganizationSortKeys = []string{"ID", "Name"}
+
+// SortOrganizations sorts organizations by the sort key of the options, or by ID if they have none.
+func SortOrganizations(opts FindOptions, os []*Organization) {
+	var less func(i, j int) bool
+	switch opts.sortKey() {
+	case "name":
+		less = func(i, j int) bool { return os[i].Name < os[j].Name }
+	default:
+		less = func(i, j int) bool { return os[i].ID < os[j].ID }
+	}
+	sort.SliceStable(os, opts.ordered(less))
+}
+
 // OrganizationFilter represents a set of filter that restrict the returned results.
 type OrganizationFilter struct {
 	Name *string
 	ID   *ID
 }
+
+// QueryParams converts OrganizationFilter fields to url query params.
+func (f OrganizationFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.ID != nil {
+		qp["orgID"] = []string{f.ID.String()}
+	}
+
+	if f.Name != nil {
+		qp["org"] = []string{*f.Name}
+	}
+
+	return qp
+}
@@ -0,0 +1,537 @@
+// Package orgdelete deletes organizations with all of their resources, in stages.
+package orgdelete
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/snowflake"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxFinishedDeletions is the number of finished deletions kept for lookup.
+	maxFinishedDeletions = 100
+
+	// taskPageSize is the number of tasks found at a time.
+	taskPageSize = 100
+)
+
+// Services are the services the resources of organizations are exported and deleted with.
+// They are used without authorization, which is checked when a deletion is requested.
+type Services struct {
+	OrganizationService        influxdb.OrganizationService
+	TaskService                influxdb.TaskService
+	AuthorizationService       influxdb.AuthorizationService
+	UserResourceMappingService influxdb.UserResourceMappingService
+
+	// BucketService must delete the data of buckets along with them.
+	BucketService influxdb.BucketService
+
+	// QueryService reads the data of buckets for exports.
+	QueryService query.QueryService
+}
+
+var _ influxdb.OrgDeletionService = (*Service)(nil)
+
+// Service deletes organizations asynchronously, one stage after the other.
+//
+// Deletions are only tracked in memory, so they can't be looked up after a
+// restart. A deletion that was interrupted can be started again however, and
+// deletes the resources that are left.
+type Service struct {
+	Services
+	IDGenerator influxdb.IDGenerator
+
+	// ExportPath is the directory organizations are exported to. Deletions
+	// with an export are rejected if it is empty.
+	ExportPath string
+
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu  sync.Mutex // Protects ops and the deletions in it.
+	ops map[influxdb.ID]*influxdb.OrgDeletion
+
+	wg sync.WaitGroup
+}
+
+// NewService returns a new Service that deletes the resources of organizations with s.
+func NewService(s Services, exportPath string, logger *zap.Logger) *Service {
+	return &Service{
+		Services:    s,
+		IDGenerator: snowflake.NewIDGenerator(),
+		ExportPath:  exportPath,
+		logger:      logger.With(zap.String("service", "org_deletion")),
+		now:         time.Now,
+		ops:         make(map[influxdb.ID]*influxdb.OrgDeletion),
+	}
+}
+
+// DeleteOrganizationResources validates the request and queues the deletion of the organization.
+// The deletion runs with the authorizer of ctx, which reads the data of exports.
+func (s *Service) DeleteOrganizationResources(ctx context.Context, req influxdb.OrgDeletionRequest) (*influxdb.OrgDeletion, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	if req.Export && s.ExportPath == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "organization exports are not enabled",
+		}
+	}
+
+	org, err := s.OrganizationService.FindOrganizationByID(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The deletion outlives the request, so it only keeps its authorizer.
+	runCtx := context.Background()
+	if a, err := icontext.GetAuthorizer(ctx); err == nil {
+		runCtx = icontext.SetAuthorizer(runCtx, a)
+	}
+
+	d := &influxdb.OrgDeletion{
+		ID:                 s.IDGenerator.ID(),
+		OrgDeletionRequest: req,
+		OrgName:            org.Name,
+		Status:             influxdb.OrgDeletionStatusQueued,
+		Stages:             influxdb.NewOrgDeletionStages(req),
+		CreatedAt:          s.now().UTC(),
+	}
+
+	s.mu.Lock()
+	for _, op := range s.ops {
+		if op.OrgID == req.OrgID && !op.Done() {
+			s.mu.Unlock()
+			return nil, &influxdb.Error{
+				Code: influxdb.EConflict,
+				Msg:  fmt.Sprintf("organization %q is already being deleted", org.Name),
+			}
+		}
+	}
+	s.ops[d.ID] = d
+	s.evictLocked()
+	res := copyOrgDeletion(d)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(runCtx, d)
+	}()
+
+	return res, nil
+}
+
+// run runs the stages of the deletion in order, and stops at the first that fails.
+func (s *Service) run(ctx context.Context, d *influxdb.OrgDeletion) {
+	s.update(d, func(d *influxdb.OrgDeletion) {
+		now := s.now().UTC()
+		d.Status = influxdb.OrgDeletionStatusRunning
+		d.StartedAt = &now
+	})
+
+	log, logEnd := logger.NewOperation(ctx, s.logger, "Delete organization", "org_deletion",
+		zap.String("org_id", d.OrgID.String()),
+		zap.String("deletion_id", d.ID.String()))
+	defer logEnd()
+
+	var err error
+	for i := range d.Stages {
+		r := &stageRun{s: s, d: d, i: i}
+		r.setStatus(influxdb.OrgDeletionStatusRunning)
+		if err = s.runStage(ctx, r); err != nil {
+			log.Error("Unable to delete organization", zap.String("stage", d.Stages[i].Name), zap.Error(err))
+			r.setStatus(influxdb.OrgDeletionStatusFailed)
+			break
+		}
+		r.setStatus(influxdb.OrgDeletionStatusSuccess)
+	}
+
+	s.update(d, func(d *influxdb.OrgDeletion) {
+		now := s.now().UTC()
+		d.FinishedAt = &now
+		if err != nil {
+			d.Status = influxdb.OrgDeletionStatusFailed
+			d.Error = err.Error()
+			return
+		}
+		d.Status = influxdb.OrgDeletionStatusSuccess
+	})
+}
+
+func (s *Service) runStage(ctx context.Context, r *stageRun) error {
+	switch name := r.name(); name {
+	case influxdb.OrgDeletionStageExport:
+		return s.export(ctx, r)
+	case influxdb.OrgDeletionStageTasks:
+		return s.deleteTasks(ctx, r)
+	case influxdb.OrgDeletionStageTokens:
+		return s.deleteTokens(ctx, r)
+	case influxdb.OrgDeletionStageBuckets:
+		return s.deleteBuckets(ctx, r)
+	case influxdb.OrgDeletionStageMappings:
+		return s.deleteMappings(ctx, r)
+	case influxdb.OrgDeletionStageOrganization:
+		r.start(1)
+		if err := s.OrganizationService.DeleteOrganization(ctx, r.d.OrgID); err != nil {
+			return err
+		}
+		r.progress()
+		return nil
+	default:
+		return fmt.Errorf("unknown organization deletion stage %q", name)
+	}
+}
+
+// stageRun reports the progress of a stage of a deletion.
+type stageRun struct {
+	s *Service
+	d *influxdb.OrgDeletion
+	i int
+}
+
+func (r *stageRun) name() string {
+	return r.d.Stages[r.i].Name
+}
+
+func (r *stageRun) setStatus(status string) {
+	r.s.update(r.d, func(d *influxdb.OrgDeletion) {
+		d.Stages[r.i].Status = status
+	})
+}
+
+// start sets the number of resources of the stage.
+func (r *stageRun) start(total int) {
+	r.s.update(r.d, func(d *influxdb.OrgDeletion) {
+		d.Stages[r.i].Total = total
+	})
+}
+
+// progress counts a resource of the stage as done.
+func (r *stageRun) progress() {
+	r.s.update(r.d, func(d *influxdb.OrgDeletion) {
+		d.Stages[r.i].Done++
+	})
+}
+
+// findTasks returns all tasks of the organization.
+func (s *Service) findTasks(ctx context.Context, orgID influxdb.ID) ([]*influxdb.Task, error) {
+	var tasks []*influxdb.Task
+	filter := influxdb.TaskFilter{OrganizationID: &orgID, Limit: taskPageSize}
+	for {
+		page, _, err := s.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page...)
+		if len(page) < taskPageSize {
+			return tasks, nil
+		}
+		filter.After = &page[len(page)-1].ID
+	}
+}
+
+func (s *Service) deleteTasks(ctx context.Context, r *stageRun) error {
+	tasks, err := s.findTasks(ctx, r.d.OrgID)
+	if err != nil {
+		return err
+	}
+
+	r.start(len(tasks))
+	for _, t := range tasks {
+		if err := s.TaskService.DeleteTask(ctx, t.ID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+		// Tasks leave their owners behind when they are deleted.
+		if err := s.deleteResourceMappings(ctx, t.ID, influxdb.TasksResourceType); err != nil {
+			return err
+		}
+		r.progress()
+	}
+	return nil
+}
+
+func (s *Service) deleteTokens(ctx context.Context, r *stageRun) error {
+	auths, _, err := s.AuthorizationService.FindAuthorizations(ctx, influxdb.AuthorizationFilter{OrgID: &r.d.OrgID})
+	if err != nil {
+		return err
+	}
+
+	r.start(len(auths))
+	for _, a := range auths {
+		if err := s.AuthorizationService.DeleteAuthorization(ctx, a.ID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+		r.progress()
+	}
+	return nil
+}
+
+func (s *Service) deleteBuckets(ctx context.Context, r *stageRun) error {
+	buckets, _, err := s.BucketService.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &r.d.OrgID})
+	if err != nil {
+		return err
+	}
+
+	r.start(len(buckets))
+	for _, b := range buckets {
+		if err := s.BucketService.DeleteBucket(ctx, b.ID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+		r.progress()
+	}
+	return nil
+}
+
+func (s *Service) deleteMappings(ctx context.Context, r *stageRun) error {
+	mappings, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceID:   r.d.OrgID,
+		ResourceType: influxdb.OrgsResourceType,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.start(len(mappings))
+	for _, m := range mappings {
+		if err := s.UserResourceMappingService.DeleteUserResourceMapping(ctx, m.ResourceID, m.UserID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+		r.progress()
+	}
+	return nil
+}
+
+// deleteResourceMappings deletes the members and owners of a resource.
+func (s *Service) deleteResourceMappings(ctx context.Context, id influxdb.ID, rt influxdb.ResourceType) error {
+	mappings, _, err := s.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceID:   id,
+		ResourceType: rt,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mappings {
+		if err := s.UserResourceMappingService.DeleteUserResourceMapping(ctx, m.ResourceID, m.UserID); err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// orgExport is the document the resources of an organization are exported to.
+type orgExport struct {
+	Organization   *influxdb.Organization          `json:"organization"`
+	Buckets        []*influxdb.Bucket              `json:"buckets"`
+	Tasks          []*influxdb.Task                `json:"tasks"`
+	Authorizations []*influxdb.Authorization       `json:"authorizations"`
+	Mappings       []*influxdb.UserResourceMapping `json:"mappings"`
+}
+
+// export writes the resources of the organization to resources.json, and the
+// data of each of its buckets to buckets/<bucket ID>.csv, in a directory of its
+// own in the export path.
+func (s *Service) export(ctx context.Context, r *stageRun) error {
+	dir := filepath.Join(s.ExportPath, fmt.Sprintf("%s-%s", r.d.OrgID, r.d.ID))
+	if err := os.MkdirAll(filepath.Join(dir, "buckets"), 0700); err != nil {
+		return err
+	}
+	s.update(r.d, func(d *influxdb.OrgDeletion) {
+		d.ExportPath = dir
+	})
+
+	e, err := s.findExport(ctx, r.d.OrgID)
+	if err != nil {
+		return err
+	}
+
+	// The resources count as one, and the data of every bucket as another.
+	r.start(1 + len(e.Buckets))
+	if err := writeJSONFile(filepath.Join(dir, "resources.json"), e); err != nil {
+		return err
+	}
+	r.progress()
+
+	// No data is read when there are no buckets, so no authorization is needed.
+	if len(e.Buckets) == 0 {
+		return nil
+	}
+	auth, err := icontext.GetAuthorization(ctx, r.d.OrgID)
+	if err != nil {
+		return err
+	}
+	for _, b := range e.Buckets {
+		if err := s.exportBucket(ctx, auth, b, filepath.Join(dir, "buckets", b.ID.String()+".csv")); err != nil {
+			return fmt.Errorf("failed to export bucket %q: %v", b.Name, err)
+		}
+		r.progress()
+	}
+	return nil
+}
+
+// findExport finds the resources of the organization to export.
+func (s *Service) findExport(ctx context.Context, orgID influxdb.ID) (*orgExport, error) {
+	org, err := s.OrganizationService.FindOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	e := &orgExport{Organization: org}
+
+	if e.Buckets, _, err = s.BucketService.FindBuckets(ctx, influxdb.BucketFilter{OrganizationID: &orgID}); err != nil {
+		return nil, err
+	}
+	if e.Tasks, err = s.findTasks(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if e.Authorizations, _, err = s.AuthorizationService.FindAuthorizations(ctx, influxdb.AuthorizationFilter{OrgID: &orgID}); err != nil {
+		return nil, err
+	}
+	// Tokens are secrets and can't be restored anyway.
+	for i, a := range e.Authorizations {
+		cp := *a
+		cp.Token = ""
+		e.Authorizations[i] = &cp
+	}
+	if e.Mappings, _, err = s.UserResourceMappingService.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+		ResourceID:   orgID,
+		ResourceType: influxdb.OrgsResourceType,
+	}); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// exportBucket writes all data of the bucket to the file at path as annotated CSV.
+func (s *Service) exportBucket(ctx context.Context, auth *influxdb.Authorization, b *influxdb.Bucket, path string) error {
+	script := fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: 1677-09-21T00:12:43.145224194Z, stop: 2262-04-11T23:47:16.854775807Z)`, b.ID.String())
+
+	it, err := s.QueryService.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: b.OrgID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	})
+	if err != nil {
+		return err
+	}
+	defer it.Release()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	enc := csv.NewMultiResultEncoder(csv.DefaultEncoderConfig())
+	if _, err := enc.Encode(f, it); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// update applies fn to the deletion under the lock.
+func (s *Service) update(d *influxdb.OrgDeletion, fn func(*influxdb.OrgDeletion)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(d)
+}
+
+// evictLocked removes the oldest finished deletions beyond the retained number.
+// It must be called under the lock.
+func (s *Service) evictLocked() {
+	var finished []*influxdb.OrgDeletion
+	for _, d := range s.ops {
+		if d.Done() {
+			finished = append(finished, d)
+		}
+	}
+	if len(finished) <= maxFinishedDeletions {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CreatedAt.Before(finished[j].CreatedAt)
+	})
+	for _, d := range finished[:len(finished)-maxFinishedDeletions] {
+		delete(s.ops, d.ID)
+	}
+}
+
+// FindOrgDeletionByID returns a single deletion by ID.
+func (s *Service) FindOrgDeletionByID(ctx context.Context, id influxdb.ID) (*influxdb.OrgDeletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.ops[id]
+	if !ok {
+		return nil, influxdb.ErrOrgDeletionNotFound
+	}
+	return copyOrgDeletion(d), nil
+}
+
+// FindOrgDeletions returns the deletions that match filter, most recent first.
+func (s *Service) FindOrgDeletions(ctx context.Context, filter influxdb.OrgDeletionFilter) ([]*influxdb.OrgDeletion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ds := []*influxdb.OrgDeletion{}
+	for _, d := range s.ops {
+		if filter.OrgID != nil && d.OrgID != *filter.OrgID {
+			continue
+		}
+		ds = append(ds, copyOrgDeletion(d))
+	}
+
+	sort.Slice(ds, func(i, j int) bool {
+		return ds[i].CreatedAt.After(ds[j].CreatedAt)
+	})
+	return ds, nil
+}
+
+// Wait blocks until all queued and running deletions have finished.
+func (s *Service) Wait() {
+	s.wg.Wait()
+}
+
+// copyOrgDeletion returns a copy of d that is safe to use outside of the lock.
+func copyOrgDeletion(d *influxdb.OrgDeletion) *influxdb.OrgDeletion {
+	cp := *d
+	cp.Stages = append([]influxdb.OrgDeletionStage(nil), d.Stages...)
+	return &cp
+}
@@ -0,0 +1,237 @@
+package orgdelete_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/orgdelete"
+	"go.uber.org/zap"
+)
+
+const orgID = influxdb.ID(1)
+
+// testServices returns services of an organization with a task, a token, a
+// bucket and a member, which record the deletions in calls.
+func testServices(calls *[]string) orgdelete.Services {
+	record := func(call string) { *calls = append(*calls, call) }
+
+	orgs := mock.NewOrganizationService()
+	orgs.FindOrganizationByIDF = func(ctx context.Context, id influxdb.ID) (*influxdb.Organization, error) {
+		return &influxdb.Organization{ID: id, Name: "acme"}, nil
+	}
+	orgs.DeleteOrganizationF = func(ctx context.Context, id influxdb.ID) error {
+		record("delete org " + id.String())
+		return nil
+	}
+
+	tasks := &mock.TaskService{
+		FindTasksFn: func(ctx context.Context, f influxdb.TaskFilter) ([]*influxdb.Task, int, error) {
+			if f.After != nil {
+				return nil, 0, nil
+			}
+			return []*influxdb.Task{{ID: 20, OrganizationID: orgID, AuthorizationID: 30, Name: "downsample"}}, 1, nil
+		},
+		DeleteTaskFn: func(ctx context.Context, id influxdb.ID) error {
+			record("delete task " + id.String())
+			return nil
+		},
+	}
+
+	auths := mock.NewAuthorizationService()
+	auths.FindAuthorizationsFn = func(ctx context.Context, f influxdb.AuthorizationFilter, opt ...influxdb.FindOptions) ([]*influxdb.Authorization, int, error) {
+		return []*influxdb.Authorization{{ID: 30, OrgID: orgID, UserID: 50, Token: "secret"}}, 1, nil
+	}
+	auths.DeleteAuthorizationFn = func(ctx context.Context, id influxdb.ID) error {
+		record("delete token " + id.String())
+		return nil
+	}
+
+	buckets := mock.NewBucketService()
+	buckets.FindBucketsFn = func(ctx context.Context, f influxdb.BucketFilter, opt ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return []*influxdb.Bucket{{ID: 40, OrgID: orgID, Name: "telegraf"}}, 1, nil
+	}
+	buckets.DeleteBucketFn = func(ctx context.Context, id influxdb.ID) error {
+		record("delete bucket " + id.String())
+		return nil
+	}
+
+	urms := mock.NewUserResourceMappingService()
+	urms.FindMappingsFn = func(ctx context.Context, f influxdb.UserResourceMappingFilter) ([]*influxdb.UserResourceMapping, int, error) {
+		m := &influxdb.UserResourceMapping{UserID: 50, UserType: influxdb.Owner, ResourceType: f.ResourceType, ResourceID: f.ResourceID}
+		return []*influxdb.UserResourceMapping{m}, 1, nil
+	}
+	urms.DeleteMappingFn = func(ctx context.Context, resourceID, userID influxdb.ID) error {
+		record("delete mapping " + resourceID.String() + " " + userID.String())
+		return nil
+	}
+
+	return orgdelete.Services{
+		OrganizationService:        orgs,
+		TaskService:                tasks,
+		AuthorizationService:       auths,
+		UserResourceMappingService: urms,
+		BucketService:              buckets,
+	}
+}
+
+func TestService_DeleteOrganizationResources(t *testing.T) {
+	var calls []string
+	s := orgdelete.NewService(testServices(&calls), "", zap.NewNop())
+
+	d, err := s.DeleteOrganizationResources(context.Background(), influxdb.OrgDeletionRequest{OrgID: orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+
+	wantCalls := []string{
+		"delete task 0000000000000014",
+		"delete mapping 0000000000000014 0000000000000032",
+		"delete token 000000000000001e",
+		"delete bucket 0000000000000028",
+		"delete mapping 0000000000000001 0000000000000032",
+		"delete org 0000000000000001",
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("unexpected calls -got/+want\n%v\n%v", calls, wantCalls)
+	}
+
+	got, err := s.FindOrgDeletionByID(context.Background(), d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != influxdb.OrgDeletionStatusSuccess || got.OrgName != "acme" || got.FinishedAt == nil {
+		t.Errorf("unexpected deletion %+v", got)
+	}
+	for _, stage := range got.Stages {
+		if stage.Status != influxdb.OrgDeletionStatusSuccess || stage.Done != 1 || stage.Total != 1 {
+			t.Errorf("unexpected progress of stage %+v", stage)
+		}
+	}
+}
+
+func TestService_DeleteOrganizationResources_failedStage(t *testing.T) {
+	var calls []string
+	services := testServices(&calls)
+	buckets := services.BucketService.(*mock.BucketService)
+	buckets.DeleteBucketFn = func(ctx context.Context, id influxdb.ID) error {
+		return errors.New("disk is read-only")
+	}
+	s := orgdelete.NewService(services, "", zap.NewNop())
+
+	d, err := s.DeleteOrganizationResources(context.Background(), influxdb.OrgDeletionRequest{OrgID: orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+
+	for _, call := range calls {
+		if call == "delete org 0000000000000001" {
+			t.Fatal("the organization must not be deleted after a stage failed")
+		}
+	}
+
+	got, err := s.FindOrgDeletionByID(context.Background(), d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != influxdb.OrgDeletionStatusFailed || got.Error != "disk is read-only" {
+		t.Errorf("unexpected deletion %+v", got)
+	}
+
+	var statuses []string
+	for _, stage := range got.Stages {
+		statuses = append(statuses, stage.Name+" "+stage.Status)
+	}
+	want := []string{"tasks success", "tokens success", "buckets failed", "mappings queued", "organization queued"}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("unexpected stages -got/+want\n%v\n%v", statuses, want)
+	}
+}
+
+func TestService_DeleteOrganizationResources_export(t *testing.T) {
+	dir, err := ioutil.TempDir("", "orgdelete")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var calls []string
+	services := testServices(&calls)
+	// Without buckets, the export only holds the resources.
+	buckets := services.BucketService.(*mock.BucketService)
+	buckets.FindBucketsFn = func(ctx context.Context, f influxdb.BucketFilter, opt ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return nil, 0, nil
+	}
+
+	s := orgdelete.NewService(services, "", zap.NewNop())
+	if _, err := s.DeleteOrganizationResources(context.Background(), influxdb.OrgDeletionRequest{OrgID: orgID, Export: true}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected exports without an export path to be invalid, got %v", err)
+	}
+
+	s.ExportPath = dir
+	d, err := s.DeleteOrganizationResources(context.Background(), influxdb.OrgDeletionRequest{OrgID: orgID, Export: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+
+	got, err := s.FindOrgDeletionByID(context.Background(), d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != influxdb.OrgDeletionStatusSuccess || got.Stages[0].Name != influxdb.OrgDeletionStageExport {
+		t.Fatalf("unexpected deletion %+v", got)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(got.ExportPath, "resources.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var export struct {
+		Organization   influxdb.Organization
+		Tasks          []influxdb.Task
+		Authorizations []influxdb.Authorization
+	}
+	if err := json.Unmarshal(b, &export); err != nil {
+		t.Fatal(err)
+	}
+	if export.Organization.Name != "acme" || len(export.Tasks) != 1 || len(export.Authorizations) != 1 {
+		t.Errorf("unexpected export %s", b)
+	}
+	if export.Authorizations[0].Token != "" {
+		t.Error("expected tokens to be left out of the export")
+	}
+}
+
+func TestService_DeleteOrganizationResources_conflict(t *testing.T) {
+	var calls []string
+	services := testServices(&calls)
+	release := make(chan struct{})
+	tasks := services.TaskService.(*mock.TaskService)
+	deleteTask := tasks.DeleteTaskFn
+	tasks.DeleteTaskFn = func(ctx context.Context, id influxdb.ID) error {
+		<-release
+		return deleteTask(ctx, id)
+	}
+	s := orgdelete.NewService(services, "", zap.NewNop())
+
+	if _, err := s.DeleteOrganizationResources(context.Background(), influxdb.OrgDeletionRequest{OrgID: orgID}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.DeleteOrganizationResources(context.Background(), influxdb.OrgDeletionRequest{OrgID: orgID})
+	close(release)
+	s.Wait()
+
+	if influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Errorf("expected a second deletion of the organization to conflict, got %v", err)
+	}
+}
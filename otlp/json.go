@@ -0,0 +1,251 @@
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// The types below are the messages of an ExportMetricsServiceRequest in the
+// JSON encoding of OTLP, where 64 bit integers may be strings, and floats may
+// be the strings NaN, Infinity and -Infinity.
+
+type jsonRequest struct {
+	ResourceMetrics []jsonResourceMetrics `json:"resourceMetrics"`
+}
+
+type jsonResourceMetrics struct {
+	Resource struct {
+		Attributes []jsonKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeMetrics []jsonScopeMetrics `json:"scopeMetrics"`
+	// InstrumentationLibraryMetrics is the deprecated name of ScopeMetrics.
+	InstrumentationLibraryMetrics []jsonScopeMetrics `json:"instrumentationLibraryMetrics"`
+}
+
+type jsonScopeMetrics struct {
+	Metrics []jsonMetric `json:"metrics"`
+}
+
+type jsonMetric struct {
+	Name  string `json:"name"`
+	Gauge *struct {
+		DataPoints []jsonDataPoint `json:"dataPoints"`
+	} `json:"gauge"`
+	Sum *struct {
+		DataPoints  []jsonDataPoint `json:"dataPoints"`
+		IsMonotonic bool            `json:"isMonotonic"`
+	} `json:"sum"`
+	Histogram *struct {
+		DataPoints []jsonDataPoint `json:"dataPoints"`
+	} `json:"histogram"`
+	ExponentialHistogram *struct {
+		DataPoints []jsonDataPoint `json:"dataPoints"`
+	} `json:"exponentialHistogram"`
+	Summary *struct {
+		DataPoints []jsonDataPoint `json:"dataPoints"`
+	} `json:"summary"`
+}
+
+// jsonDataPoint has the fields of the data points of every kind of metric.
+type jsonDataPoint struct {
+	Attributes     []jsonKeyValue `json:"attributes"`
+	TimeUnixNano   jsonUint64     `json:"timeUnixNano"`
+	Flags          uint32         `json:"flags"`
+	AsDouble       *jsonFloat     `json:"asDouble"`
+	AsInt          *jsonInt64     `json:"asInt"`
+	Count          jsonUint64     `json:"count"`
+	Sum            jsonFloat      `json:"sum"`
+	BucketCounts   []jsonUint64   `json:"bucketCounts"`
+	ExplicitBounds []jsonFloat    `json:"explicitBounds"`
+	QuantileValues []struct {
+		Quantile jsonFloat `json:"quantile"`
+		Value    jsonFloat `json:"value"`
+	} `json:"quantileValues"`
+}
+
+type jsonKeyValue struct {
+	Key   string        `json:"key"`
+	Value *jsonAnyValue `json:"value"`
+}
+
+type jsonAnyValue struct {
+	StringValue *string    `json:"stringValue"`
+	BoolValue   *bool      `json:"boolValue"`
+	IntValue    *jsonInt64 `json:"intValue"`
+	DoubleValue *jsonFloat `json:"doubleValue"`
+	BytesValue  []byte     `json:"bytesValue"`
+	ArrayValue  *struct {
+		Values []jsonAnyValue `json:"values"`
+	} `json:"arrayValue"`
+	KvlistValue *struct {
+		Values []jsonKeyValue `json:"values"`
+	} `json:"kvlistValue"`
+}
+
+// value returns the value as a string, bool, int64, float64, []byte,
+// []interface{} or map[string]interface{}, or nil if it is empty.
+func (v *jsonAnyValue) value() interface{} {
+	switch {
+	case v == nil:
+		return nil
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.BoolValue != nil:
+		return *v.BoolValue
+	case v.IntValue != nil:
+		return int64(*v.IntValue)
+	case v.DoubleValue != nil:
+		return float64(*v.DoubleValue)
+	case v.BytesValue != nil:
+		return v.BytesValue
+	case v.ArrayValue != nil:
+		values := make([]interface{}, 0, len(v.ArrayValue.Values))
+		for i := range v.ArrayValue.Values {
+			values = append(values, v.ArrayValue.Values[i].value())
+		}
+		return values
+	case v.KvlistValue != nil:
+		values := make(map[string]interface{}, len(v.KvlistValue.Values))
+		for _, kv := range v.KvlistValue.Values {
+			values[kv.Key] = kv.Value.value()
+		}
+		return values
+	}
+	return nil
+}
+
+// jsonUint64 is a uint64 encoded as a number or a string.
+type jsonUint64 uint64
+
+func (n *jsonUint64) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseUint(string(bytes.Trim(b, `"`)), 10, 64)
+	*n = jsonUint64(v)
+	return err
+}
+
+// jsonInt64 is an int64 encoded as a number or a string.
+type jsonInt64 int64
+
+func (n *jsonInt64) UnmarshalJSON(b []byte) error {
+	v, err := strconv.ParseInt(string(bytes.Trim(b, `"`)), 10, 64)
+	*n = jsonInt64(v)
+	return err
+}
+
+// jsonFloat is a float64 encoded as a number, or as the string NaN, Infinity
+// or -Infinity.
+type jsonFloat float64
+
+func (f *jsonFloat) UnmarshalJSON(b []byte) error {
+	switch string(b) {
+	case `"NaN"`:
+		*f = jsonFloat(math.NaN())
+	case `"Infinity"`:
+		*f = jsonFloat(math.Inf(1))
+	case `"-Infinity"`:
+		*f = jsonFloat(math.Inf(-1))
+	default:
+		v, err := strconv.ParseFloat(string(bytes.Trim(b, `"`)), 64)
+		if err != nil {
+			return err
+		}
+		*f = jsonFloat(v)
+	}
+	return nil
+}
+
+// DecodeJSON decodes the metrics of an ExportMetricsServiceRequest encoded as JSON.
+func DecodeJSON(b []byte) ([]Metric, error) {
+	var req jsonRequest
+	if err := json.Unmarshal(b, &req); err != nil {
+		return nil, err
+	}
+
+	var metrics []Metric
+	for _, rm := range req.ResourceMetrics {
+		attrs := jsonAttributes(nil, rm.Resource.Attributes)
+		for _, sm := range append(rm.ScopeMetrics, rm.InstrumentationLibraryMetrics...) {
+			for _, jm := range sm.Metrics {
+				metrics = append(metrics, jm.metric(attrs))
+			}
+		}
+	}
+	return metrics, nil
+}
+
+func (jm jsonMetric) metric(attrs map[string]string) Metric {
+	m := Metric{Name: jm.Name}
+	var dps []jsonDataPoint
+	switch {
+	case jm.Gauge != nil:
+		m.Kind = KindGauge
+		dps = jm.Gauge.DataPoints
+	case jm.Sum != nil:
+		m.Kind = KindSum
+		m.Monotonic = jm.Sum.IsMonotonic
+		dps = jm.Sum.DataPoints
+	case jm.Histogram != nil:
+		m.Kind = KindHistogram
+		dps = jm.Histogram.DataPoints
+	case jm.ExponentialHistogram != nil:
+		m.Kind = KindExponentialHistogram
+		dps = jm.ExponentialHistogram.DataPoints
+	case jm.Summary != nil:
+		m.Kind = KindSummary
+		dps = jm.Summary.DataPoints
+	}
+
+	for _, jdp := range dps {
+		dp := DataPoint{
+			Attributes: jsonAttributes(attrs, jdp.Attributes),
+			Time:       uint64(jdp.TimeUnixNano),
+			Flags:      jdp.Flags,
+			Count:      uint64(jdp.Count),
+			Sum:        float64(jdp.Sum),
+		}
+		switch {
+		case jdp.AsDouble != nil:
+			dp.Value = float64(*jdp.AsDouble)
+		case jdp.AsInt != nil:
+			dp.Value = float64(*jdp.AsInt)
+		}
+		for _, c := range jdp.BucketCounts {
+			dp.BucketCounts = append(dp.BucketCounts, uint64(c))
+		}
+		for _, b := range jdp.ExplicitBounds {
+			dp.Bounds = append(dp.Bounds, float64(b))
+		}
+		for _, q := range jdp.QuantileValues {
+			dp.Quantiles = append(dp.Quantiles, Quantile{Quantile: float64(q.Quantile), Value: float64(q.Value)})
+		}
+		m.Points = append(m.Points, dp)
+	}
+	return m
+}
+
+// jsonAttributes returns the attributes of base overridden by the key values.
+func jsonAttributes(base map[string]string, kvs []jsonKeyValue) map[string]string {
+	attrs := copyAttributes(base)
+	for _, kv := range kvs {
+		setAttribute(attrs, kv.Key, kv.Value.value())
+	}
+	return attrs
+}
+
+// EncodeJSONResponse encodes an ExportMetricsServiceResponse as JSON. The
+// response reports a partial success if data points were rejected.
+func EncodeJSONResponse(rejected int64, message string) ([]byte, error) {
+	type partialSuccess struct {
+		RejectedDataPoints int64  `json:"rejectedDataPoints,string"`
+		ErrorMessage       string `json:"errorMessage,omitempty"`
+	}
+	var res struct {
+		PartialSuccess *partialSuccess `json:"partialSuccess,omitempty"`
+	}
+	if rejected != 0 || message != "" {
+		res.PartialSuccess = &partialSuccess{RejectedDataPoints: rejected, ErrorMessage: message}
+	}
+	return json.Marshal(res)
+}
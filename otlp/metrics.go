@@ -0,0 +1,145 @@
+// Package otlp converts the metrics of the OpenTelemetry protocol (OTLP) to points.
+//
+// Metrics are converted like the metrics scraped from Prometheus targets: the
+// measurement is the name of the metric, the tags are the attributes of the
+// resource and of the data point, and the fields depend on the kind of metric.
+// Gauges have a gauge field, monotonic sums a counter field and other sums a
+// gauge field. Histograms have count and sum fields, and a field per bucket with
+// the cumulative count of the bucket, named after its upper bound, such as 0.5
+// or +Inf. Summaries have count and sum fields, and a field per quantile, named
+// after the quantile. Every field is a float.
+package otlp
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// Kinds of a Metric.
+const (
+	KindGauge = iota
+	KindSum
+	KindHistogram
+	KindExponentialHistogram
+	KindSummary
+)
+
+// flagNoRecordedValue is the flag of data points that replace a value that is
+// no longer reported, rather than a value.
+const flagNoRecordedValue = 1
+
+// Metric is an OpenTelemetry metric with its data points.
+type Metric struct {
+	Name      string
+	Kind      int
+	Monotonic bool
+	Points    []DataPoint
+}
+
+// DataPoint is a data point of a Metric. Which of its values are set depends on
+// the kind of the metric.
+type DataPoint struct {
+	// Attributes are the attributes of the data point and of its resource.
+	Attributes map[string]string
+	// Time is the time of the data point in nanoseconds since the epoch, or 0.
+	Time  uint64
+	Flags uint32
+
+	// Value is the value of gauges and sums.
+	Value float64
+
+	// Count and Sum are the count and sum of histograms and summaries.
+	Count uint64
+	Sum   float64
+	// Bounds are the upper bounds of the buckets of histograms but the last one,
+	// and BucketCounts the count of every bucket.
+	Bounds       []float64
+	BucketCounts []uint64
+	// Quantiles are the quantiles of summaries.
+	Quantiles []Quantile
+}
+
+// Quantile is a quantile of a summary.
+type Quantile struct {
+	Quantile float64
+	Value    float64
+}
+
+// Points converts the metrics to points. Points without a time are at now. It
+// returns the number of data points that could not be converted too, which are
+// those of exponential histograms and those with an invalid value.
+func Points(metrics []Metric, now time.Time) (models.Points, int) {
+	var points models.Points
+	var rejected int
+	for _, m := range metrics {
+		for _, dp := range m.Points {
+			if dp.Flags&flagNoRecordedValue != 0 {
+				continue
+			}
+
+			fields := m.fields(dp)
+			if len(fields) == 0 {
+				rejected++
+				continue
+			}
+
+			t := now
+			if dp.Time > 0 {
+				t = time.Unix(0, int64(dp.Time))
+			}
+
+			pt, err := models.NewPoint(m.Name, models.NewTags(dp.Attributes), fields, t)
+			if err != nil {
+				rejected++
+				continue
+			}
+			points = append(points, pt)
+		}
+	}
+	return points, rejected
+}
+
+// fields returns the fields of the data point, leaving out values that are not a number.
+func (m Metric) fields(dp DataPoint) models.Fields {
+	fields := make(models.Fields)
+	set := func(k string, v float64) {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			fields[k] = v
+		}
+	}
+
+	switch m.Kind {
+	case KindGauge:
+		set("gauge", dp.Value)
+	case KindSum:
+		if m.Monotonic {
+			set("counter", dp.Value)
+		} else {
+			set("gauge", dp.Value)
+		}
+	case KindHistogram:
+		if len(dp.BucketCounts) > 0 && len(dp.BucketCounts) != len(dp.Bounds)+1 {
+			return nil
+		}
+		var cumulative uint64
+		for i, bound := range dp.Bounds {
+			cumulative += dp.BucketCounts[i]
+			set(fmt.Sprint(bound), float64(cumulative))
+		}
+		if len(dp.BucketCounts) > 0 {
+			set(fmt.Sprint(math.Inf(1)), float64(dp.Count))
+		}
+		set("count", float64(dp.Count))
+		set("sum", dp.Sum)
+	case KindSummary:
+		for _, q := range dp.Quantiles {
+			set(fmt.Sprint(q.Quantile), q.Value)
+		}
+		set("count", float64(dp.Count))
+		set("sum", dp.Sum)
+	}
+	return fields
+}
@@ -0,0 +1,284 @@
+package otlp
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/models"
+)
+
+// msg encodes a protocol buffer message of the fields, which are appended by
+// the field functions below.
+func msg(fields ...[]byte) []byte {
+	var b []byte
+	for _, f := range fields {
+		b = append(b, f...)
+	}
+	return b
+}
+
+func key(field, wire int) []byte {
+	return appendVarint(nil, uint64(field<<3|wire))
+}
+
+func bytesField(field int, v []byte) []byte {
+	b := appendVarint(key(field, wireBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+func stringField(field int, v string) []byte {
+	return bytesField(field, []byte(v))
+}
+
+func varintField(field int, v uint64) []byte {
+	return appendVarint(key(field, wireVarint), v)
+}
+
+func fixed64Field(field int, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(key(field, wireFixed64), buf[:]...)
+}
+
+func doubleField(field int, v float64) []byte {
+	return fixed64Field(field, math.Float64bits(v))
+}
+
+func packedField(field int, vs ...uint64) []byte {
+	var b []byte
+	for _, v := range vs {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], v)
+		b = append(b, buf[:]...)
+	}
+	return bytesField(field, b)
+}
+
+func stringAttr(field int, k, v string) []byte {
+	return bytesField(field, msg(stringField(1, k), bytesField(2, stringField(1, v))))
+}
+
+func TestDecodeProto(t *testing.T) {
+	const ts = 1565000000000000000
+	req := msg(bytesField(1, msg(
+		// The resource may follow its metrics.
+		bytesField(2, msg(
+			bytesField(1, stringField(1, "io.opentelemetry.runtime")),
+			bytesField(2, msg(
+				stringField(1, "requests"),
+				stringField(3, "1"),
+				bytesField(7, msg(
+					bytesField(1, msg(
+						stringAttr(7, "method", "GET"),
+						fixed64Field(3, ts),
+						fixed64Field(6, uint64(12)),
+					)),
+					varintField(2, 2),
+					varintField(3, 1),
+				)),
+			)),
+			bytesField(2, msg(
+				stringField(1, "latency"),
+				bytesField(9, msg(
+					bytesField(1, msg(
+						fixed64Field(3, ts),
+						fixed64Field(4, 6),
+						doubleField(5, 1.5),
+						packedField(6, 1, 2, 3),
+						packedField(7, math.Float64bits(0.1), math.Float64bits(0.5)),
+						bytesField(9, msg(stringField(1, "route"), bytesField(2, varintField(3, 7)))),
+					)),
+				)),
+			)),
+			bytesField(2, msg(
+				stringField(1, "sizes"),
+				bytesField(10, msg(
+					bytesField(1, msg(fixed64Field(3, ts), varintField(6, 2))),
+				)),
+			)),
+		)),
+		bytesField(1, msg(
+			stringAttr(1, "service.name", "checkout"),
+			stringAttr(1, "host.name", "a"),
+		)),
+	)))
+
+	got, err := DecodeProto(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Metric{
+		{
+			Name:      "requests",
+			Kind:      KindSum,
+			Monotonic: true,
+			Points: []DataPoint{{
+				Attributes: map[string]string{"service.name": "checkout", "host.name": "a", "method": "GET"},
+				Time:       ts,
+				Value:      12,
+			}},
+		},
+		{
+			Name: "latency",
+			Kind: KindHistogram,
+			Points: []DataPoint{{
+				Attributes:   map[string]string{"service.name": "checkout", "host.name": "a", "route": "7"},
+				Time:         ts,
+				Count:        6,
+				Sum:          1.5,
+				BucketCounts: []uint64{1, 2, 3},
+				Bounds:       []float64{0.1, 0.5},
+			}},
+		},
+		{
+			Name: "sizes",
+			Kind: KindExponentialHistogram,
+			Points: []DataPoint{{
+				Attributes: map[string]string{"service.name": "checkout", "host.name": "a"},
+				Time:       ts,
+			}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected metrics\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestDecodeProto_truncated(t *testing.T) {
+	req := msg(bytesField(1, msg(bytesField(2, msg(stringField(1, "requests"))))))
+	if _, err := DecodeProto(req[:len(req)-2]); err == nil {
+		t.Error("expected an error decoding a truncated request")
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	req := []byte(`{
+	  "resourceMetrics": [{
+	    "resource": {"attributes": [
+	      {"key": "service.name", "value": {"stringValue": "checkout"}},
+	      {"key": "service.replicas", "value": {"intValue": "3"}},
+	      {"key": "service.tags", "value": {"arrayValue": {"values": [{"stringValue": "a"}, {"boolValue": true}]}}},
+	      {"key": "empty", "value": {}}
+	    ]},
+	    "scopeMetrics": [{
+	      "scope": {"name": "io.opentelemetry.runtime"},
+	      "metrics": [
+	        {
+	          "name": "memory",
+	          "unit": "By",
+	          "gauge": {"dataPoints": [
+	            {"timeUnixNano": "1565000000000000000", "asDouble": 1024.5, "attributes": [{"key": "service.name", "value": {"stringValue": "cart"}}]},
+	            {"timeUnixNano": 1565000000000000000, "asInt": "2048"}
+	          ]}
+	        },
+	        {
+	          "name": "rpc",
+	          "summary": {"dataPoints": [{
+	            "timeUnixNano": "1565000000000000000",
+	            "count": "10",
+	            "sum": 12.5,
+	            "quantileValues": [{"quantile": 0.5, "value": 1}, {"quantile": 0.99, "value": "NaN"}]
+	          }]}
+	        }
+	      ]
+	    }]
+	  }]
+	}`)
+
+	got, err := DecodeJSON(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attrs := map[string]string{"service.name": "checkout", "service.replicas": "3", "service.tags": `["a",true]`}
+	if !reflect.DeepEqual(got[0].Points[1].Attributes, attrs) {
+		t.Errorf("unexpected resource attributes %v", got[0].Points[1].Attributes)
+	}
+	if got[0].Points[0].Attributes["service.name"] != "cart" {
+		t.Errorf("expected data point attributes to override resource attributes, got %v", got[0].Points[0].Attributes)
+	}
+
+	points, rejected := Points(got, time.Now())
+	if rejected != 0 {
+		t.Errorf("expected no rejected data points, got %d", rejected)
+	}
+	var lines []string
+	for _, p := range points {
+		lines = append(lines, p.String())
+	}
+	want := []string{
+		"memory,service.name=cart,service.replicas=3,service.tags=[\"a\"\\,true] gauge=1024.5 1565000000000000000",
+		"memory,service.name=checkout,service.replicas=3,service.tags=[\"a\"\\,true] gauge=2048 1565000000000000000",
+		"rpc,service.name=checkout,service.replicas=3,service.tags=[\"a\"\\,true] 0.5=1,count=10,sum=12.5 1565000000000000000",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("unexpected points\ngot:  %q\nwant: %q", lines, want)
+	}
+}
+
+func TestPoints(t *testing.T) {
+	now := time.Unix(0, 1565000000000000000)
+	metrics := []Metric{
+		{Name: "up", Kind: KindGauge, Points: []DataPoint{{Value: 1}}},
+		{Name: "queue", Kind: KindSum, Points: []DataPoint{{Value: -3, Time: 1}}},
+		{Name: "requests", Kind: KindSum, Monotonic: true, Points: []DataPoint{
+			{Value: 7, Time: 1},
+			{Time: 2, Flags: flagNoRecordedValue},
+			{Value: math.NaN(), Time: 3},
+		}},
+		{Name: "latency", Kind: KindHistogram, Points: []DataPoint{
+			{Time: 1, Count: 6, Sum: 1.5, Bounds: []float64{0.1, 0.5}, BucketCounts: []uint64{1, 2, 3}},
+			{Time: 2, Count: 6, Sum: 1.5, Bounds: []float64{0.1, 0.5}, BucketCounts: []uint64{1, 2}},
+		}},
+		{Name: "sizes", Kind: KindExponentialHistogram, Points: []DataPoint{{Time: 1}}},
+	}
+
+	points, rejected := Points(metrics, now)
+	if rejected != 3 {
+		t.Errorf("expected 3 rejected data points, got %d", rejected)
+	}
+
+	want := map[string]models.Fields{
+		"up":       {"gauge": 1.0},
+		"queue":    {"gauge": -3.0},
+		"requests": {"counter": 7.0},
+		"latency":  {"0.1": 1.0, "0.5": 3.0, "+Inf": 6.0, "count": 6.0, "sum": 1.5},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("expected %d points, got %d", len(want), len(points))
+	}
+	for _, p := range points {
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(fields, want[string(p.Name())]) {
+			t.Errorf("unexpected fields of %s: %v", p.Name(), fields)
+		}
+	}
+	if !points[0].Time().Equal(now) {
+		t.Errorf("expected data point without a time to be at now, got %v", points[0].Time())
+	}
+}
+
+func TestEncodeResponse(t *testing.T) {
+	if b := EncodeProtoResponse(0, ""); len(b) != 0 {
+		t.Errorf("expected empty response, got %x", b)
+	}
+	if b, want := EncodeProtoResponse(3, "no"), msg(bytesField(1, msg(varintField(1, 3), stringField(2, "no")))); !reflect.DeepEqual(b, want) {
+		t.Errorf("unexpected response %x, want %x", b, want)
+	}
+
+	b, err := EncodeJSONResponse(0, "")
+	if err != nil || string(b) != "{}" {
+		t.Errorf("unexpected response %s, %v", b, err)
+	}
+	b, err = EncodeJSONResponse(3, "no")
+	if want := `{"partialSuccess":{"rejectedDataPoints":"3","errorMessage":"no"}}`; err != nil || string(b) != want {
+		t.Errorf("unexpected response %s, %v", b, err)
+	}
+}
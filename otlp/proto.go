@@ -0,0 +1,704 @@
+package otlp
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Wire types of protocol buffers.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated message")
+
+// pbReader reads the fields of an encoded protocol buffer message.
+type pbReader struct {
+	b []byte
+}
+
+// next reads the key of the next field. It returns false at the end of the message.
+func (r *pbReader) next() (field int, wire int, ok bool, err error) {
+	if len(r.b) == 0 {
+		return 0, 0, false, nil
+	}
+	k, err := r.varint()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return int(k >> 3), int(k & 7), true, nil
+}
+
+func (r *pbReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *pbReader) fixed64() (uint64, error) {
+	if len(r.b) < 8 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint64(r.b)
+	r.b = r.b[8:]
+	return v, nil
+}
+
+func (r *pbReader) double() (float64, error) {
+	v, err := r.fixed64()
+	return math.Float64frombits(v), err
+}
+
+func (r *pbReader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(r.b)) < n {
+		return nil, errTruncated
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v, nil
+}
+
+// skip skips the value of a field of the wire type.
+func (r *pbReader) skip(wire int) error {
+	var err error
+	switch wire {
+	case wireVarint:
+		_, err = r.varint()
+	case wireFixed64:
+		_, err = r.fixed64()
+	case wireBytes:
+		_, err = r.bytes()
+	case wireFixed32:
+		if len(r.b) < 4 {
+			return errTruncated
+		}
+		r.b = r.b[4:]
+	default:
+		return fmt.Errorf("unsupported wire type %d", wire)
+	}
+	return err
+}
+
+// repeatedFixed64 reads a value of a repeated fixed64 field, which is either
+// packed or a single value.
+func (r *pbReader) repeatedFixed64(wire int) ([]uint64, error) {
+	if wire == wireFixed64 {
+		v, err := r.fixed64()
+		return []uint64{v}, err
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(b)%8 != 0 {
+		return nil, errTruncated
+	}
+	vs := make([]uint64, 0, len(b)/8)
+	for i := 0; i < len(b); i += 8 {
+		vs = append(vs, binary.LittleEndian.Uint64(b[i:]))
+	}
+	return vs, nil
+}
+
+// DecodeProto decodes the metrics of an ExportMetricsServiceRequest encoded as a protocol buffer.
+func DecodeProto(b []byte) ([]Metric, error) {
+	var metrics []Metric
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return metrics, nil
+		}
+		if field != 1 || wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		rm, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		ms, err := decodeResourceMetrics(rm)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, ms...)
+	}
+}
+
+func decodeResourceMetrics(b []byte) ([]Metric, error) {
+	var attrs map[string]string
+	var scopes [][]byte
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			res, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			if attrs, err = decodeAttributes(res, 1); err != nil {
+				return nil, err
+			}
+		// 1000 is the deprecated instrumentation_library_metrics, which is
+		// encoded like scope_metrics.
+		case (field == 2 || field == 1000) && wire == wireBytes:
+			sm, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			scopes = append(scopes, sm)
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The resource may follow its metrics, so they are decoded once it is known.
+	var metrics []Metric
+	for _, sm := range scopes {
+		r := &pbReader{b: sm}
+		for {
+			field, wire, ok, err := r.next()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				break
+			}
+			if field != 2 || wire != wireBytes {
+				if err := r.skip(wire); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			mb, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			m, err := decodeMetric(mb, attrs)
+			if err != nil {
+				return nil, err
+			}
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, nil
+}
+
+func decodeMetric(b []byte, attrs map[string]string) (Metric, error) {
+	var m Metric
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return m, err
+		}
+		if !ok {
+			return m, nil
+		}
+		if wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return m, err
+			}
+			continue
+		}
+
+		v, err := r.bytes()
+		if err != nil {
+			return m, err
+		}
+		switch field {
+		case 1:
+			m.Name = string(v)
+		case 5:
+			m.Kind = KindGauge
+			m.Points, err = decodeDataPoints(v, attrs, decodeNumberDataPoint, nil)
+		case 7:
+			m.Kind = KindSum
+			m.Points, err = decodeDataPoints(v, attrs, decodeNumberDataPoint, &m.Monotonic)
+		case 9:
+			m.Kind = KindHistogram
+			m.Points, err = decodeDataPoints(v, attrs, decodeHistogramDataPoint, nil)
+		case 10:
+			m.Kind = KindExponentialHistogram
+			m.Points, err = decodeDataPoints(v, attrs, decodeExponentialHistogramDataPoint, nil)
+		case 11:
+			m.Kind = KindSummary
+			m.Points, err = decodeDataPoints(v, attrs, decodeSummaryDataPoint, nil)
+		}
+		if err != nil {
+			return m, err
+		}
+	}
+}
+
+// decodeDataPoints decodes the data points of a gauge, sum, histogram or
+// summary, which are its field 1. The is_monotonic field of sums is decoded
+// into monotonic.
+func decodeDataPoints(b []byte, attrs map[string]string, decode func([]byte, *DataPoint) error, monotonic *bool) ([]DataPoint, error) {
+	var dps []DataPoint
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return dps, nil
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			v, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			dp := DataPoint{Attributes: copyAttributes(attrs)}
+			if err := decode(v, &dp); err != nil {
+				return nil, err
+			}
+			dps = append(dps, dp)
+		case field == 3 && wire == wireVarint && monotonic != nil:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			*monotonic = v != 0
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// decodeCommon decodes the fields that data points have in common. It returns
+// false if the field is not one of them.
+func decodeCommon(r *pbReader, field, wire, attrsField, flagsField int, dp *DataPoint) (bool, error) {
+	switch {
+	case field == attrsField && wire == wireBytes:
+		v, err := r.bytes()
+		if err != nil {
+			return true, err
+		}
+		return true, decodeKeyValue(v, dp.Attributes)
+	case field == 3 && wire == wireFixed64:
+		var err error
+		dp.Time, err = r.fixed64()
+		return true, err
+	case field == flagsField && wire == wireVarint:
+		v, err := r.varint()
+		dp.Flags = uint32(v)
+		return true, err
+	}
+	return false, nil
+}
+
+func decodeNumberDataPoint(b []byte, dp *DataPoint) error {
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return err
+		}
+		if ok, err := decodeCommon(r, field, wire, 7, 8, dp); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case field == 4 && wire == wireFixed64:
+			dp.Value, err = r.double()
+		case field == 6 && wire == wireFixed64:
+			var v uint64
+			v, err = r.fixed64()
+			dp.Value = float64(int64(v))
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func decodeHistogramDataPoint(b []byte, dp *DataPoint) error {
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return err
+		}
+		if ok, err := decodeCommon(r, field, wire, 9, 10, dp); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case field == 4 && wire == wireFixed64:
+			dp.Count, err = r.fixed64()
+		case field == 5 && wire == wireFixed64:
+			dp.Sum, err = r.double()
+		case field == 6 && (wire == wireBytes || wire == wireFixed64):
+			var vs []uint64
+			vs, err = r.repeatedFixed64(wire)
+			dp.BucketCounts = append(dp.BucketCounts, vs...)
+		case field == 7 && (wire == wireBytes || wire == wireFixed64):
+			var vs []uint64
+			vs, err = r.repeatedFixed64(wire)
+			for _, v := range vs {
+				dp.Bounds = append(dp.Bounds, math.Float64frombits(v))
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// decodeExponentialHistogramDataPoint decodes only the attributes and time of
+// the data point, since exponential histograms are not converted to points.
+func decodeExponentialHistogramDataPoint(b []byte, dp *DataPoint) error {
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return err
+		}
+		if ok, err := decodeCommon(r, field, wire, 1, 10, dp); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.skip(wire); err != nil {
+			return err
+		}
+	}
+}
+
+func decodeSummaryDataPoint(b []byte, dp *DataPoint) error {
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return err
+		}
+		if ok, err := decodeCommon(r, field, wire, 7, 8, dp); ok || err != nil {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case field == 4 && wire == wireFixed64:
+			dp.Count, err = r.fixed64()
+		case field == 5 && wire == wireFixed64:
+			dp.Sum, err = r.double()
+		case field == 6 && wire == wireBytes:
+			var v []byte
+			if v, err = r.bytes(); err == nil {
+				var q Quantile
+				q, err = decodeQuantile(v)
+				dp.Quantiles = append(dp.Quantiles, q)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func decodeQuantile(b []byte) (Quantile, error) {
+	var q Quantile
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return q, err
+		}
+		switch {
+		case field == 1 && wire == wireFixed64:
+			q.Quantile, err = r.double()
+		case field == 2 && wire == wireFixed64:
+			q.Value, err = r.double()
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return q, err
+		}
+	}
+}
+
+// decodeAttributes decodes the attributes of a message, which are its key
+// values at the field.
+func decodeAttributes(b []byte, field int) (map[string]string, error) {
+	attrs := make(map[string]string)
+	r := &pbReader{b: b}
+	for {
+		f, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return attrs, nil
+		}
+		if f != field || wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		v, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeKeyValue(v, attrs); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// decodeKeyValue decodes a KeyValue into attrs.
+func decodeKeyValue(b []byte, attrs map[string]string) error {
+	key, value, err := decodeKeyValuePair(b)
+	if err != nil {
+		return err
+	}
+	setAttribute(attrs, key, value)
+	return nil
+}
+
+// decodeKeyValuePair decodes the key and the value of a KeyValue.
+func decodeKeyValuePair(b []byte) (string, interface{}, error) {
+	var key string
+	var value interface{}
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return key, value, err
+		}
+		var v []byte
+		switch {
+		case field == 1 && wire == wireBytes:
+			v, err = r.bytes()
+			key = string(v)
+		case field == 2 && wire == wireBytes:
+			if v, err = r.bytes(); err == nil {
+				value, err = decodeAnyValue(v)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+}
+
+// decodeAnyValue decodes an AnyValue into a string, bool, int64, float64,
+// []byte, []interface{} or map[string]interface{}.
+func decodeAnyValue(b []byte) (interface{}, error) {
+	var value interface{}
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil || !ok {
+			return value, err
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			var v []byte
+			v, err = r.bytes()
+			value = string(v)
+		case field == 2 && wire == wireVarint:
+			var v uint64
+			v, err = r.varint()
+			value = v != 0
+		case field == 3 && wire == wireVarint:
+			var v uint64
+			v, err = r.varint()
+			value = int64(v)
+		case field == 4 && wire == wireFixed64:
+			value, err = r.double()
+		case field == 5 && wire == wireBytes:
+			var v []byte
+			if v, err = r.bytes(); err == nil {
+				value, err = decodeArrayValue(v)
+			}
+		case field == 6 && wire == wireBytes:
+			var v []byte
+			if v, err = r.bytes(); err == nil {
+				value, err = decodeKeyValueList(v)
+			}
+		case field == 7 && wire == wireBytes:
+			var v []byte
+			v, err = r.bytes()
+			value = v
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func decodeArrayValue(b []byte) ([]interface{}, error) {
+	values := []interface{}{}
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return values, nil
+		}
+		if field != 1 || wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		v, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeAnyValue(v)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+}
+
+func decodeKeyValueList(b []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	r := &pbReader{b: b}
+	for {
+		field, wire, ok, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return values, nil
+		}
+		if field != 1 || wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		v, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		key, value, err := decodeKeyValuePair(v)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+}
+
+// setAttribute sets the attribute key to the value as a string. Attributes
+// without a key or value are left out, since tags cannot be empty.
+func setAttribute(attrs map[string]string, key string, value interface{}) {
+	if key == "" || value == nil {
+		return
+	}
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case bool:
+		s = strconv.FormatBool(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case []byte:
+		s = base64.StdEncoding.EncodeToString(v)
+	default:
+		// Arrays and key value lists are encoded as JSON.
+		b, err := json.Marshal(v)
+		if err != nil {
+			return
+		}
+		s = string(b)
+	}
+	if s != "" {
+		attrs[key] = s
+	}
+}
+
+func copyAttributes(attrs map[string]string) map[string]string {
+	c := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		c[k] = v
+	}
+	return c
+}
+
+// EncodeProtoResponse encodes an ExportMetricsServiceResponse as a protocol
+// buffer. The response reports a partial success if data points were rejected.
+func EncodeProtoResponse(rejected int64, message string) []byte {
+	if rejected == 0 && message == "" {
+		return nil
+	}
+
+	var ps []byte
+	ps = append(ps, 1<<3|wireVarint)
+	ps = appendVarint(ps, uint64(rejected))
+	ps = append(ps, 2<<3|wireBytes)
+	ps = appendVarint(ps, uint64(len(message)))
+	ps = append(ps, message...)
+
+	b := []byte{1<<3 | wireBytes}
+	b = appendVarint(b, uint64(len(ps)))
+	return append(b, ps...)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
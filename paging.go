@@ -1,7 +1,9 @@
 package influxdb
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -28,6 +30,8 @@ type FindOptions struct {
 	Offset     int
 	SortBy     string
 	Descending bool
+	// Fields are the fields of the results to return over HTTP, or all of them if empty.
+	Fields []string
 }
 
 // QueryParams returns a map containing url query params.
@@ -45,5 +49,63 @@ func (f FindOptions) QueryParams() map[string][]string {
 		qp["sortBy"] = []string{f.SortBy}
 	}
 
+	if len(f.Fields) > 0 {
+		qp["fields"] = []string{strings.Join(f.Fields, ",")}
+	}
+
 	return qp
 }
+
+// Unpaged returns the options without their offset and limit, to find every
+// result in order, such as when results are filtered before they are paged.
+func (f FindOptions) Unpaged() FindOptions {
+	f.Offset = 0
+	f.Limit = 0
+	return f
+}
+
+// Page returns the bounds of the page of n ordered results the options select.
+func (f FindOptions) Page(n int) (lo, hi int) {
+	lo = f.Offset
+	if lo < 0 {
+		lo = 0
+	}
+	if lo > n {
+		lo = n
+	}
+	hi = n
+	if f.Limit > 0 && lo+f.Limit < hi {
+		hi = lo + f.Limit
+	}
+	return lo, hi
+}
+
+// ValidSortBy returns an error if the options sort by a key other than the keys.
+// Keys match regardless of case.
+func (f FindOptions) ValidSortBy(keys ...string) error {
+	if f.SortBy == "" {
+		return nil
+	}
+	for _, k := range keys {
+		if strings.EqualFold(f.SortBy, k) {
+			return nil
+		}
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("sortBy must be one of %s", strings.Join(keys, ", ")),
+	}
+}
+
+// sortKey is the sort key of the options, regardless of case.
+func (f FindOptions) sortKey() string {
+	return strings.ToLower(f.SortBy)
+}
+
+// ordered returns less, or less reversed if the options are descending.
+func (f FindOptions) ordered(less func(i, j int) bool) func(i, j int) bool {
+	if f.Descending {
+		return func(i, j int) bool { return less(j, i) }
+	}
+	return less
+}
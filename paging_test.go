@@ -0,0 +1,73 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func TestFindOptions_Page(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   influxdb.FindOptions
+		n      int
+		lo, hi int
+	}{
+		{name: "unpaged", n: 5, lo: 0, hi: 5},
+		{name: "first page", opts: influxdb.FindOptions{Limit: 2}, n: 5, lo: 0, hi: 2},
+		{name: "last page", opts: influxdb.FindOptions{Offset: 4, Limit: 2}, n: 5, lo: 4, hi: 5},
+		{name: "past the last page", opts: influxdb.FindOptions{Offset: 10, Limit: 2}, n: 5, lo: 5, hi: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lo, hi := tt.opts.Page(tt.n)
+			if lo != tt.lo || hi != tt.hi {
+				t.Errorf("got page [%d:%d], want [%d:%d]", lo, hi, tt.lo, tt.hi)
+			}
+		})
+	}
+}
+
+func TestFindOptions_ValidSortBy(t *testing.T) {
+	if err := (influxdb.FindOptions{SortBy: "name"}).ValidSortBy(influxdb.BucketSortKeys...); err != nil {
+		t.Errorf("expected sort keys to match regardless of case, got %v", err)
+	}
+	if err := (influxdb.FindOptions{}).ValidSortBy(influxdb.BucketSortKeys...); err != nil {
+		t.Errorf("expected no sort key to be valid, got %v", err)
+	}
+	err := (influxdb.FindOptions{SortBy: "size"}).ValidSortBy(influxdb.BucketSortKeys...)
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an unknown sort key to be invalid, got %v", err)
+	}
+}
+
+func TestSortBuckets(t *testing.T) {
+	bs := []*influxdb.Bucket{
+		{ID: 1, Name: "b", RetentionPeriod: 2},
+		{ID: 2, Name: "c", RetentionPeriod: 1},
+		{ID: 3, Name: "a", RetentionPeriod: 2},
+	}
+	ids := func() []influxdb.ID {
+		var ids []influxdb.ID
+		for _, b := range bs {
+			ids = append(ids, b.ID)
+		}
+		return ids
+	}
+
+	influxdb.SortBuckets(influxdb.FindOptions{SortBy: "Name"}, bs)
+	if diff := cmp.Diff(ids(), []influxdb.ID{3, 1, 2}); diff != "" {
+		t.Errorf("buckets sorted by name are different -got/+want\ndiff %s", diff)
+	}
+
+	influxdb.SortBuckets(influxdb.FindOptions{SortBy: "RetentionPeriod", Descending: true}, bs)
+	if diff := cmp.Diff(ids(), []influxdb.ID{3, 1, 2}); diff != "" {
+		t.Errorf("buckets sorted by descending retention period are different -got/+want\ndiff %s", diff)
+	}
+
+	influxdb.SortBuckets(influxdb.FindOptions{Descending: true}, bs)
+	if diff := cmp.Diff(ids(), []influxdb.ID{3, 2, 1}); diff != "" {
+		t.Errorf("buckets sorted by descending ID are different -got/+want\ndiff %s", diff)
+	}
+}
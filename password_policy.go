@@ -0,0 +1,219 @@
+package influxdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordCharacterClass is a class of characters a password policy may require.
+type PasswordCharacterClass string
+
+// Character classes of passwords.
+const (
+	PasswordLowercase PasswordCharacterClass = "lowercase"
+	PasswordUppercase PasswordCharacterClass = "uppercase"
+	PasswordDigit     PasswordCharacterClass = "digit"
+	// PasswordSymbol is any character that is neither a letter nor a digit.
+	PasswordSymbol PasswordCharacterClass = "symbol"
+)
+
+// PasswordCharacterClasses are all the character classes of passwords.
+var PasswordCharacterClasses = []PasswordCharacterClass{
+	PasswordLowercase,
+	PasswordUppercase,
+	PasswordDigit,
+	PasswordSymbol,
+}
+
+// Rules of a password policy that a password may violate.
+const (
+	PasswordRuleMinLength      = "min_length"
+	PasswordRuleCharacterClass = "character_class"
+	PasswordRuleDenyList       = "deny_list"
+	PasswordRuleHistory        = "history"
+	PasswordRuleMaxAge         = "max_age"
+)
+
+// PasswordPolicy is the policy passwords must satisfy when they are set, and
+// how long they may be used for.
+type PasswordPolicy struct {
+	// MinLength is the least number of characters of a password.
+	MinLength int `json:"minLength"`
+	// RequiredClasses are the classes of characters a password must have at least one of each of.
+	RequiredClasses []PasswordCharacterClass `json:"requiredClasses,omitempty"`
+	// DenyList are passwords that may not be used, regardless of case.
+	DenyList []string `json:"-"`
+	// HistorySize is the number of most recent passwords of a user, including
+	// the current one, that the user may not set again. Zero allows any reuse.
+	HistorySize int `json:"historySize,omitempty"`
+	// MaxAge is how long a password may be used before it has to be changed.
+	// Passwords never expire if zero.
+	MaxAge time.Duration `json:"maxAge,omitempty"`
+}
+
+// DefaultPasswordPolicy is the password policy if the server sets no policy:
+// passwords must be at least 8 characters long.
+var DefaultPasswordPolicy = PasswordPolicy{
+	MinLength: 8,
+}
+
+// Valid returns an error if the policy has negative limits or requires unknown character classes.
+func (p PasswordPolicy) Valid() error {
+	if p.MinLength < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "minimum password length must not be negative",
+		}
+	}
+	if p.HistorySize < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "password history size must not be negative",
+		}
+	}
+	if p.MaxAge < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "maximum password age must not be negative",
+		}
+	}
+	for _, c := range p.RequiredClasses {
+		if !containsPasswordCharacterClass(PasswordCharacterClasses, c) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("unknown password character class %q", c),
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns an invalid error listing every rule of the policy the password
+// violates. The rules that depend on the passwords a user had before, history
+// and max age, are left to the PasswordsService.
+func (p PasswordPolicy) Check(password string) error {
+	var vs []PasswordViolation
+	if utf8.RuneCountInString(password) < p.MinLength {
+		vs = append(vs, PasswordViolation{
+			Rule:    PasswordRuleMinLength,
+			Message: fmt.Sprintf("passwords must be at least %d characters long", p.MinLength),
+		})
+	}
+
+	for _, c := range p.RequiredClasses {
+		if !hasPasswordCharacterClass(password, c) {
+			vs = append(vs, PasswordViolation{
+				Rule:    PasswordRuleCharacterClass,
+				Message: fmt.Sprintf("passwords must contain at least one %s character", c),
+			})
+		}
+	}
+
+	for _, denied := range p.DenyList {
+		if strings.EqualFold(password, denied) {
+			vs = append(vs, PasswordViolation{
+				Rule:    PasswordRuleDenyList,
+				Message: "password is too common",
+			})
+			break
+		}
+	}
+
+	if len(vs) == 0 {
+		return nil
+	}
+	return NewPasswordPolicyError(EInvalid, vs...)
+}
+
+// PasswordViolation is a rule of a password policy that a password violates.
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError is the error of a password that violates a password policy.
+// It is wrapped in an *Error, so that it has a code, and its violations can be
+// retrieved with PasswordViolations.
+type PasswordPolicyError struct {
+	Code       string
+	Violations []PasswordViolation
+}
+
+// NewPasswordPolicyError returns an error with the code whose message lists the violations.
+func NewPasswordPolicyError(code string, vs ...PasswordViolation) *Error {
+	pe := &PasswordPolicyError{
+		Code:       code,
+		Violations: vs,
+	}
+	return &Error{
+		Code: code,
+		Msg:  pe.message(),
+		Err:  pe,
+	}
+}
+
+// Error formats the violations like an *Error with the code and the violations
+// as message, so wrapping it does not change the message.
+func (e *PasswordPolicyError) Error() string {
+	return (&Error{Code: e.Code, Msg: e.message()}).Error()
+}
+
+func (e *PasswordPolicyError) message() string {
+	ms := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		ms[i] = v.Message
+	}
+	return strings.Join(ms, "; ")
+}
+
+// PasswordViolations returns the violations of the password policy error in the
+// stack of err, if any.
+func PasswordViolations(err error) []PasswordViolation {
+	for err != nil {
+		switch e := err.(type) {
+		case *PasswordPolicyError:
+			return e.Violations
+		case *Error:
+			err = e.Err
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func hasPasswordCharacterClass(password string, c PasswordCharacterClass) bool {
+	for _, r := range password {
+		switch c {
+		case PasswordLowercase:
+			if unicode.IsLower(r) {
+				return true
+			}
+		case PasswordUppercase:
+			if unicode.IsUpper(r) {
+				return true
+			}
+		case PasswordDigit:
+			if unicode.IsDigit(r) {
+				return true
+			}
+		case PasswordSymbol:
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsPasswordCharacterClass(cs []PasswordCharacterClass, c PasswordCharacterClass) bool {
+	for _, class := range cs {
+		if class == c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func TestPasswordPolicy_Valid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy influxdb.PasswordPolicy
+		valid  bool
+	}{
+		{name: "default", policy: influxdb.DefaultPasswordPolicy, valid: true},
+		{
+			name: "every rule",
+			policy: influxdb.PasswordPolicy{
+				MinLength:       12,
+				RequiredClasses: influxdb.PasswordCharacterClasses,
+				DenyList:        []string{"password"},
+				HistorySize:     5,
+				MaxAge:          90 * 24 * time.Hour,
+			},
+			valid: true,
+		},
+		{
+			name:   "unknown character class",
+			policy: influxdb.PasswordPolicy{RequiredClasses: []influxdb.PasswordCharacterClass{"emoji"}},
+		},
+		{
+			name:   "negative history",
+			policy: influxdb.PasswordPolicy{HistorySize: -1},
+		},
+		{
+			name:   "negative max age",
+			policy: influxdb.PasswordPolicy{MaxAge: -time.Hour},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Valid()
+			if tt.valid && err != nil {
+				t.Errorf("expected the policy to be valid, got %v", err)
+			}
+			if !tt.valid && influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected the policy to be invalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	policy := influxdb.PasswordPolicy{
+		MinLength:       8,
+		RequiredClasses: []influxdb.PasswordCharacterClass{influxdb.PasswordUppercase, influxdb.PasswordDigit, influxdb.PasswordSymbol},
+		DenyList:        []string{"Password1!"},
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		rules    []string
+	}{
+		{name: "satisfies every rule", password: "C0rrect-horse"},
+		{
+			name:     "short and missing classes",
+			password: "abc",
+			rules:    []string{influxdb.PasswordRuleMinLength, influxdb.PasswordRuleCharacterClass, influxdb.PasswordRuleCharacterClass, influxdb.PasswordRuleCharacterClass},
+		},
+		{
+			name:     "denied regardless of case",
+			password: "PASSWORD1!",
+			rules:    []string{influxdb.PasswordRuleDenyList},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.password)
+			if len(tt.rules) == 0 {
+				if err != nil {
+					t.Fatalf("expected the password to be accepted, got %v", err)
+				}
+				return
+			}
+			if influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected the password to be invalid, got %v", err)
+			}
+
+			var rules []string
+			for _, v := range influxdb.PasswordViolations(err) {
+				rules = append(rules, v.Rule)
+			}
+			if diff := cmp.Diff(rules, tt.rules); diff != "" {
+				t.Errorf("violated rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicy_CheckDefaultMessage(t *testing.T) {
+	// The message of a short password is the message of passwords shorter than
+	// the minimum length from before there were password policies.
+	err := influxdb.DefaultPasswordPolicy.Check("short")
+	if got, want := err.Error(), "<invalid> passwords must be at least 8 characters long"; got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ErrPasswordResetNotFound is the error msg for a missing or used password reset.
+const ErrPasswordResetNotFound = "password reset not found"
+
+// DefaultPasswordResetTTL is how long a password reset can be used for.
+const DefaultPasswordResetTTL = time.Hour
+
+// ops for password reset errors.
+const (
+	OpCreatePasswordReset = "CreatePasswordReset"
+	OpResetPassword       = "ResetPassword"
+)
+
+// PasswordReset is a single-use token that sets the password of a user
+// without the old password. It is delivered to the user by whoever created it.
+type PasswordReset struct {
+	UserID    ID        `json:"userID"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Expired returns true if the password reset can no longer be used at the time.
+func (r *PasswordReset) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// PasswordResetService creates and consumes password resets.
+type PasswordResetService interface {
+	// CreatePasswordReset creates a password reset for the user, replacing any
+	// password reset the user had.
+	CreatePasswordReset(ctx context.Context, userID ID) (*PasswordReset, error)
+	// ResetPassword sets the password of the user of the password reset with
+	// the token, and uses up the token.
+	ResetPassword(ctx context.Context, token string, password string) error
+}
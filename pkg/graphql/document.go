@@ -0,0 +1,156 @@
+package graphql
+
+// Document is a parsed GraphQL document.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is an operation of a document, such as a query.
+type Operation struct {
+	// Type is query, mutation or subscription.
+	Type      string
+	Name      string
+	Variables []*VariableDefinition
+	Selection []Selection
+	Location  Location
+}
+
+// VariableDefinition declares a variable of an operation.
+type VariableDefinition struct {
+	Name     string
+	Type     TypeRef
+	Default  Value
+	Location Location
+}
+
+// TypeRef is a reference to a type of the schema in a document, such as [ID!]!.
+type TypeRef struct {
+	Name    string
+	Elem    *TypeRef
+	NonNull bool
+}
+
+func (t TypeRef) String() string {
+	s := t.Name
+	if t.Elem != nil {
+		s = "[" + t.Elem.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+// Fragment is a named fragment that selections may be spread into.
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	Directives    []*Directive
+	Selection     []Selection
+	Location      Location
+}
+
+// Selection is a Field, a FragmentSpread or an InlineFragment.
+type Selection interface {
+	selection()
+}
+
+// Field selects a field of an object.
+type Field struct {
+	Alias      string
+	Name       string
+	Arguments  []*Argument
+	Directives []*Directive
+	Selection  []Selection
+	Location   Location
+}
+
+// ResponseKey is the key of the field in the response, which is its alias if it has one.
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread spreads a named fragment into a selection.
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Location   Location
+}
+
+// InlineFragment is a selection with an optional type condition.
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	Selection     []Selection
+	Location      Location
+}
+
+func (*Field) selection()          {}
+func (*FragmentSpread) selection() {}
+func (*InlineFragment) selection() {}
+
+// Directive is a directive of a selection, such as @include(if: $x).
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+	Location  Location
+}
+
+// Argument is an argument of a field or a directive.
+type Argument struct {
+	Name     string
+	Value    Value
+	Location Location
+}
+
+// Value is a literal value or a variable of a document.
+type Value interface {
+	value()
+}
+
+// Variable is a reference to a variable of the operation.
+type Variable struct {
+	Name string
+}
+
+// ScalarValue is an int, float, string, boolean or enum literal.
+type ScalarValue struct {
+	// Kind is the kind of the token of the literal.
+	Kind  TokenKind
+	Value string
+}
+
+// NullValue is the null literal.
+type NullValue struct{}
+
+// ListValue is a list literal.
+type ListValue struct {
+	Values []Value
+}
+
+// ObjectValue is an input object literal.
+type ObjectValue struct {
+	Fields []*ObjectField
+}
+
+// ObjectField is a field of an input object literal.
+type ObjectField struct {
+	Name  string
+	Value Value
+}
+
+func (*Variable) value()    {}
+func (*ScalarValue) value() {}
+func (*NullValue) value()   {}
+func (*ListValue) value()   {}
+func (*ObjectValue) value() {}
+
+// Location is a position in the source of a document.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
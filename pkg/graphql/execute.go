@@ -0,0 +1,859 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Params are the parameters of the execution of a query.
+type Params struct {
+	Query string
+	// OperationName selects the operation to execute when the query has several.
+	OperationName string
+	// Variables are the decoded JSON values of the variables of the operation.
+	Variables map[string]interface{}
+	// MaxDepth limits how deeply fields may be nested, if it is positive.
+	MaxDepth int
+}
+
+// Result is the result of the execution of a query.
+type Result struct {
+	// Data is the data of the response, or nil if the query was not executed
+	// or the null of a field propagated to its root.
+	Data interface{}
+	// Errors are the errors of the query and of its fields.
+	Errors []*Error
+
+	// executed is true if the query was valid and executed, in which case the
+	// response has data, which may be null.
+	executed bool
+}
+
+// MarshalJSON encodes the result as the response of the query.
+func (r *Result) MarshalJSON() ([]byte, error) {
+	if !r.executed {
+		return json.Marshal(struct {
+			Errors []*Error `json:"errors,omitempty"`
+		}{Errors: r.Errors})
+	}
+	return json.Marshal(struct {
+		Errors []*Error    `json:"errors,omitempty"`
+		Data   interface{} `json:"data"`
+	}{Errors: r.Errors, Data: r.Data})
+}
+
+// Error is an error of a query or of one of its fields.
+type Error struct {
+	Message    string                 `json:"message"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	// Err is the error returned by a resolver, if any.
+	Err error `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error returned by the resolver, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Execute parses, validates and executes the query with the schema. Only
+// query operations are supported. Errors of resolvers are added to the errors
+// of the result with the path of their field, which is null in the data.
+func Execute(ctx context.Context, schema *Schema, p Params) *Result {
+	doc, err := Parse(p.Query)
+	if err != nil {
+		if serr, ok := err.(*SyntaxError); ok {
+			return &Result{Errors: []*Error{{Message: serr.Error(), Locations: []Location{serr.Location}}}}
+		}
+		return &Result{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	op, gerr := selectOperation(doc, p.OperationName)
+	if gerr != nil {
+		return &Result{Errors: []*Error{gerr}}
+	}
+
+	v := &validator{schema: schema, doc: doc, op: op, maxDepth: p.MaxDepth}
+	if errs := v.validate(); len(errs) > 0 {
+		return &Result{Errors: errs}
+	}
+
+	vars, errs := coerceVariables(schema, op, p.Variables)
+	if len(errs) > 0 {
+		return &Result{Errors: errs}
+	}
+
+	e := &executor{schema: schema, doc: doc, vars: vars}
+	res := &Result{executed: true}
+	if data, ok := e.executeSelection(ctx, schema.Query, nil, op.Selection, nil); ok {
+		res.Data = data
+	}
+	res.Errors = e.errors
+	return res
+}
+
+func selectOperation(doc *Document, name string) (*Operation, *Error) {
+	var op *Operation
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, &Error{Message: "operation name is required for a document with several operations"}
+		}
+		op = doc.Operations[0]
+	} else {
+		for _, o := range doc.Operations {
+			if o.Name == name {
+				op = o
+				break
+			}
+		}
+		if op == nil {
+			return nil, &Error{Message: fmt.Sprintf("unknown operation %q", name)}
+		}
+	}
+
+	if op.Type != "query" {
+		return nil, &Error{
+			Message:   fmt.Sprintf("%s operations are not supported", op.Type),
+			Locations: []Location{op.Location},
+		}
+	}
+	return op, nil
+}
+
+// typeNameField is the meta field that every object has, whose value is the
+// name of the object.
+const typeNameField = "__typename"
+
+// validator checks that an operation is valid for the schema before it is executed.
+type validator struct {
+	schema   *Schema
+	doc      *Document
+	op       *Operation
+	maxDepth int
+
+	vars   map[string]*VariableDefinition
+	errors []*Error
+}
+
+func (v *validator) errorf(loc Location, format string, args ...interface{}) {
+	v.errors = append(v.errors, &Error{
+		Message:   fmt.Sprintf(format, args...),
+		Locations: []Location{loc},
+	})
+}
+
+func (v *validator) validate() []*Error {
+	v.vars = make(map[string]*VariableDefinition)
+	for _, d := range v.op.Variables {
+		if _, ok := v.vars[d.Name]; ok {
+			v.errorf(d.Location, "variable $%s is defined more than once", d.Name)
+			continue
+		}
+		v.vars[d.Name] = d
+		if _, err := v.schema.inputType(d.Type); err != nil {
+			v.errorf(d.Location, "variable $%s: %v", d.Name, err)
+		}
+	}
+
+	for name, f := range v.doc.Fragments {
+		if f.TypeCondition != v.schema.Query.Name && v.objectNamed(f.TypeCondition) == nil {
+			v.errorf(f.Location, "fragment %s is on unknown type %s", name, f.TypeCondition)
+		}
+	}
+
+	v.selection(v.schema.Query, v.op.Selection, 1, nil)
+	return v.errors
+}
+
+// objectNamed returns the object of the schema with the name, if any.
+func (v *validator) objectNamed(name string) *Object {
+	var found *Object
+	seen := make(map[*Object]bool)
+	var walk func(o *Object)
+	walk = func(o *Object) {
+		if found != nil || seen[o] {
+			return
+		}
+		seen[o] = true
+		if o.Name == name {
+			found = o
+			return
+		}
+		for _, fname := range sortedFieldNames(o) {
+			if obj, ok := namedType(o.Fields[fname].Type).(*Object); ok {
+				walk(obj)
+			}
+		}
+	}
+	walk(v.schema.Query)
+	return found
+}
+
+// selection validates a selection of the object at the depth. spreading
+// holds the fragments being spread, to find fragments that spread themselves.
+func (v *validator) selection(obj *Object, sel []Selection, depth int, spreading []string) {
+	if v.maxDepth > 0 && depth > v.maxDepth {
+		v.errorf(selectionSet(sel).location(), "query is nested deeper than %d levels", v.maxDepth)
+		return
+	}
+
+	fieldNames := make(map[string]string)
+	for _, s := range sel {
+		switch s := s.(type) {
+		case *Field:
+			v.directives(s.Directives)
+			if name, ok := fieldNames[s.ResponseKey()]; ok && name != s.Name {
+				v.errorf(s.Location, "fields %s and %s both have the response key %s", name, s.Name, s.ResponseKey())
+			}
+			fieldNames[s.ResponseKey()] = s.Name
+			v.field(obj, s, depth, spreading)
+		case *FragmentSpread:
+			v.directives(s.Directives)
+			f, ok := v.doc.Fragments[s.Name]
+			if !ok {
+				v.errorf(s.Location, "unknown fragment %s", s.Name)
+				continue
+			}
+			if f.TypeCondition != obj.Name {
+				v.errorf(s.Location, "fragment %s on %s cannot be spread on %s", s.Name, f.TypeCondition, obj.Name)
+				continue
+			}
+			cycle := false
+			for _, name := range spreading {
+				cycle = cycle || name == s.Name
+			}
+			if cycle {
+				v.errorf(s.Location, "fragment %s spreads itself", s.Name)
+				continue
+			}
+			v.directives(f.Directives)
+			v.selection(obj, f.Selection, depth, append(spreading[:len(spreading):len(spreading)], s.Name))
+		case *InlineFragment:
+			v.directives(s.Directives)
+			if s.TypeCondition != "" && s.TypeCondition != obj.Name {
+				v.errorf(s.Location, "fragment on %s cannot be spread on %s", s.TypeCondition, obj.Name)
+				continue
+			}
+			v.selection(obj, s.Selection, depth, spreading)
+		}
+	}
+}
+
+func (v *validator) field(obj *Object, f *Field, depth int, spreading []string) {
+	if f.Name == typeNameField {
+		if len(f.Arguments) > 0 || len(f.Selection) > 0 {
+			v.errorf(f.Location, "field %s has no arguments or fields", typeNameField)
+		}
+		return
+	}
+
+	def, ok := obj.Fields[f.Name]
+	if !ok {
+		v.errorf(f.Location, "unknown field %s of type %s", f.Name, obj.Name)
+		return
+	}
+	v.arguments(def.Args, f.Arguments, f.Location, fmt.Sprintf("field %s", f.Name))
+
+	switch t := namedType(def.Type).(type) {
+	case *Object:
+		if len(f.Selection) == 0 {
+			v.errorf(f.Location, "field %s of type %s must have a selection of fields", f.Name, def.Type)
+			return
+		}
+		v.selection(t, f.Selection, depth+1, spreading)
+	default:
+		if len(f.Selection) > 0 {
+			v.errorf(f.Location, "field %s of type %s cannot have a selection of fields", f.Name, def.Type)
+		}
+	}
+}
+
+func (v *validator) arguments(defs map[string]*ArgDef, args []*Argument, loc Location, of string) {
+	given := make(map[string]bool)
+	for _, a := range args {
+		if given[a.Name] {
+			v.errorf(a.Location, "argument %s of %s is given more than once", a.Name, of)
+			continue
+		}
+		given[a.Name] = true
+
+		def, ok := defs[a.Name]
+		if !ok {
+			v.errorf(a.Location, "unknown argument %s of %s", a.Name, of)
+			continue
+		}
+		if err := v.value(a.Value, def.Type, def.Default != nil); err != nil {
+			v.errorf(a.Location, "argument %s of %s: %v", a.Name, of, err)
+		}
+	}
+
+	for name, def := range defs {
+		if _, ok := def.Type.(*NonNull); ok && !given[name] && def.Default == nil {
+			v.errorf(loc, "argument %s of %s is required", name, of)
+		}
+	}
+}
+
+// value checks that the value may be an input of the type. Literals are
+// coerced, and variables must be defined with a type the type accepts.
+func (v *validator) value(val Value, t Type, hasDefault bool) error {
+	if ref, ok := val.(*Variable); ok {
+		d, ok := v.vars[ref.Name]
+		if !ok {
+			return fmt.Errorf("variable $%s is not defined", ref.Name)
+		}
+		vt, err := v.schema.inputType(d.Type)
+		if err != nil {
+			return nil // Reported with the definition of the variable.
+		}
+		if !acceptsType(t, vt, d.Default != nil || hasDefault) {
+			return fmt.Errorf("variable $%s of type %s cannot be used as %s", ref.Name, d.Type, t)
+		}
+		return nil
+	}
+
+	switch val := val.(type) {
+	case *ListValue:
+		if lt, ok := nullableType(t).(*List); ok {
+			for _, item := range val.Values {
+				if err := v.value(item, lt.OfType, false); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case *ObjectValue:
+		return fmt.Errorf("input objects are not supported")
+	}
+	if hasVariable(val) {
+		return fmt.Errorf("list value cannot be used as %s", t)
+	}
+	_, err := coerceLiteral(val, t, nil)
+	return err
+}
+
+func (v *validator) directives(dirs []*Directive) {
+	for _, d := range dirs {
+		if d.Name != "include" && d.Name != "skip" {
+			v.errorf(d.Location, "unknown directive @%s", d.Name)
+			continue
+		}
+		v.arguments(map[string]*ArgDef{"if": {Type: NewNonNull(Boolean)}}, d.Arguments, d.Location, "directive @"+d.Name)
+	}
+}
+
+func hasVariable(val Value) bool {
+	switch val := val.(type) {
+	case *Variable:
+		return true
+	case *ListValue:
+		for _, item := range val.Values {
+			if hasVariable(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acceptsType returns true if a variable of type vt may be used where t is
+// expected. A nullable variable may be used for a non-null type if it or the
+// location has a default.
+func acceptsType(t, vt Type, hasDefault bool) bool {
+	if nn, ok := t.(*NonNull); ok {
+		if vnn, ok := vt.(*NonNull); ok {
+			return acceptsType(nn.OfType, vnn.OfType, false)
+		}
+		return hasDefault && acceptsType(nn.OfType, vt, false)
+	}
+	if vnn, ok := vt.(*NonNull); ok {
+		return acceptsType(t, vnn.OfType, false)
+	}
+	if l, ok := t.(*List); ok {
+		vl, ok := vt.(*List)
+		return ok && acceptsType(l.OfType, vl.OfType, false)
+	}
+	return t == vt
+}
+
+func nullableType(t Type) Type {
+	if nn, ok := t.(*NonNull); ok {
+		return nn.OfType
+	}
+	return t
+}
+
+// inputType returns the type of the schema that a variable refers to.
+func (s *Schema) inputType(ref TypeRef) (Type, error) {
+	var t Type
+	if ref.Elem != nil {
+		elem, err := s.inputType(*ref.Elem)
+		if err != nil {
+			return nil, err
+		}
+		t = NewList(elem)
+	} else {
+		sc, ok := s.inputs[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown input type %s", ref.Name)
+		}
+		t = sc
+	}
+	if ref.NonNull {
+		t = NewNonNull(t)
+	}
+	return t, nil
+}
+
+// coerceVariables coerces the values of the variables of the operation to
+// their types, with the defaults of the variables that are left out.
+func coerceVariables(schema *Schema, op *Operation, values map[string]interface{}) (map[string]interface{}, []*Error) {
+	vars := make(map[string]interface{})
+	var errs []*Error
+	for _, d := range op.Variables {
+		t, err := schema.inputType(d.Type)
+		if err != nil {
+			errs = append(errs, &Error{Message: err.Error(), Locations: []Location{d.Location}})
+			continue
+		}
+
+		val, ok := values[d.Name]
+		if !ok {
+			if d.Default != nil {
+				if vars[d.Name], err = coerceLiteral(d.Default, t, nil); err != nil {
+					errs = append(errs, &Error{
+						Message:   fmt.Sprintf("default of variable $%s: %v", d.Name, err),
+						Locations: []Location{d.Location},
+					})
+				}
+				continue
+			}
+			if _, ok := t.(*NonNull); ok {
+				errs = append(errs, &Error{
+					Message:   fmt.Sprintf("variable $%s of type %s is required", d.Name, d.Type),
+					Locations: []Location{d.Location},
+				})
+			}
+			continue
+		}
+
+		if vars[d.Name], err = coerceValue(val, t); err != nil {
+			errs = append(errs, &Error{
+				Message:   fmt.Sprintf("variable $%s: %v", d.Name, err),
+				Locations: []Location{d.Location},
+			})
+		}
+	}
+	return vars, errs
+}
+
+// coerceValue coerces the decoded JSON value of a variable to the type.
+func coerceValue(val interface{}, t Type) (interface{}, error) {
+	if nn, ok := t.(*NonNull); ok {
+		if val == nil {
+			return nil, fmt.Errorf("expected a value of type %s, got null", t)
+		}
+		return coerceValue(val, nn.OfType)
+	}
+	if val == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		items, ok := val.([]interface{})
+		if !ok {
+			// A single value is coerced to a list of one.
+			items = []interface{}{val}
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			v, err := coerceValue(item, t.OfType)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case *Scalar:
+		return t.ParseValue(val)
+	}
+	return nil, fmt.Errorf("%s is not an input type", t)
+}
+
+// coerceLiteral coerces a value of a document to the type, with the coerced
+// values of the variables of the operation.
+func coerceLiteral(val Value, t Type, vars map[string]interface{}) (interface{}, error) {
+	if ref, ok := val.(*Variable); ok {
+		v := vars[ref.Name]
+		if _, ok := t.(*NonNull); ok && v == nil {
+			return nil, fmt.Errorf("expected a value of type %s, got null", t)
+		}
+		return v, nil
+	}
+	if nn, ok := t.(*NonNull); ok {
+		if _, ok := val.(*NullValue); ok {
+			return nil, fmt.Errorf("expected a value of type %s, got null", t)
+		}
+		return coerceLiteral(val, nn.OfType, vars)
+	}
+	if _, ok := val.(*NullValue); ok {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		items := []Value{val}
+		if l, ok := val.(*ListValue); ok {
+			items = l.Values
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			v, err := coerceLiteral(item, t.OfType, vars)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = v
+		}
+		return list, nil
+	case *Scalar:
+		sv, ok := val.(*ScalarValue)
+		if !ok {
+			return nil, fmt.Errorf("expected a value of type %s", t)
+		}
+		return t.ParseLiteral(sv)
+	}
+	return nil, fmt.Errorf("%s is not an input type", t)
+}
+
+// executor executes a validated operation.
+type executor struct {
+	schema *Schema
+	doc    *Document
+	vars   map[string]interface{}
+	errors []*Error
+}
+
+func (e *executor) addError(err error, fields []*Field, path []interface{}) {
+	gerr := &Error{
+		Message: err.Error(),
+		Path:    path,
+		Err:     err,
+	}
+	for _, f := range fields {
+		gerr.Locations = append(gerr.Locations, f.Location)
+	}
+	e.errors = append(e.errors, gerr)
+}
+
+// executeSelection executes the selection of the object with the source
+// value. It returns false if a non-null field is null, so the object is null.
+func (e *executor) executeSelection(ctx context.Context, obj *Object, source interface{}, sel []Selection, path []interface{}) (*responseMap, bool) {
+	m := &responseMap{values: make(map[string]interface{})}
+	for _, g := range e.collectFields(obj, sel, nil, make(map[string]bool)) {
+		fpath := appendPath(path, g.key)
+		if g.fields[0].Name == typeNameField {
+			m.set(g.key, obj.Name)
+			continue
+		}
+
+		v, ok := e.executeField(ctx, obj, source, g.fields, fpath)
+		if !ok {
+			return nil, false
+		}
+		m.set(g.key, v)
+	}
+	return m, true
+}
+
+// executeField resolves and completes the value of the field. It returns
+// false if the value is null while the field is non-null.
+func (e *executor) executeField(ctx context.Context, obj *Object, source interface{}, fields []*Field, path []interface{}) (interface{}, bool) {
+	f := fields[0]
+	def := obj.Fields[f.Name]
+	_, nonNull := def.Type.(*NonNull)
+
+	args, err := e.arguments(def.Args, f.Arguments)
+	if err != nil {
+		e.addError(err, fields, path)
+		return nil, !nonNull
+	}
+
+	resolve := def.Resolve
+	if resolve == nil {
+		resolve = defaultResolve(f.Name)
+	}
+	v, err := resolve(ctx, ResolveParams{Source: source, Args: args, Path: path})
+	if err != nil {
+		e.addError(err, fields, path)
+		return nil, !nonNull
+	}
+	return e.completeValue(ctx, def.Type, fields, v, path)
+}
+
+// completeValue converts a resolved value to its value in the response. It
+// returns false if the value is null while the type is non-null.
+func (e *executor) completeValue(ctx context.Context, t Type, fields []*Field, v interface{}, path []interface{}) (interface{}, bool) {
+	if nn, ok := t.(*NonNull); ok {
+		r, ok := e.completeNullable(ctx, nn.OfType, fields, v, path)
+		if !ok {
+			return nil, false
+		}
+		if r == nil {
+			e.addError(fmt.Errorf("non-null field %s returned null", fields[0].Name), fields, path)
+			return nil, false
+		}
+		return r, true
+	}
+
+	r, ok := e.completeNullable(ctx, t, fields, v, path)
+	if !ok {
+		return nil, true
+	}
+	return r, true
+}
+
+// completeNullable completes a value of a nullable type. It returns false if
+// the value is null because of an error, which it has added.
+func (e *executor) completeNullable(ctx context.Context, t Type, fields []*Field, v interface{}, path []interface{}) (interface{}, bool) {
+	if t, ok := t.(*List); ok {
+		rv := reflect.ValueOf(v)
+		if v == nil || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+			return nil, true
+		}
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.addError(fmt.Errorf("field %s of type %s resolved to a value that is not a list", fields[0].Name, t), fields, path)
+			return nil, false
+		}
+		// A nil slice is an empty list.
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			item, ok := e.completeValue(ctx, t.OfType, fields, rv.Index(i).Interface(), appendPath(path, i))
+			if !ok {
+				return nil, false
+			}
+			list[i] = item
+		}
+		return list, true
+	}
+
+	if isNull(v) {
+		return nil, true
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		r, err := t.Serialize(v)
+		if err != nil {
+			e.addError(err, fields, path)
+			return nil, false
+		}
+		return r, true
+	case *Object:
+		var sel []Selection
+		for _, f := range fields {
+			sel = append(sel, f.Selection...)
+		}
+		m, ok := e.executeSelection(ctx, t, v, sel, path)
+		if !ok {
+			return nil, false
+		}
+		return m, true
+	}
+	e.addError(fmt.Errorf("unknown type %s", t), fields, path)
+	return nil, false
+}
+
+func (e *executor) arguments(defs map[string]*ArgDef, args []*Argument) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(defs))
+	for name, def := range defs {
+		if def.Default != nil {
+			values[name] = def.Default
+		}
+	}
+	for _, a := range args {
+		def := defs[a.Name]
+		if ref, ok := a.Value.(*Variable); ok {
+			if _, ok := e.vars[ref.Name]; !ok {
+				// A variable that is left out leaves the argument to its default.
+				if _, ok := def.Type.(*NonNull); ok && def.Default == nil {
+					return nil, fmt.Errorf("argument %s is required", a.Name)
+				}
+				continue
+			}
+		}
+		v, err := coerceLiteral(a.Value, def.Type, e.vars)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %v", a.Name, err)
+		}
+		values[a.Name] = v
+	}
+	return values, nil
+}
+
+// fieldGroup is the fields of a selection with the same response key.
+type fieldGroup struct {
+	key    string
+	fields []*Field
+}
+
+// collectFields returns the fields of the selection in order, grouped by
+// their response keys, after applying directives and spreading fragments.
+func (e *executor) collectFields(obj *Object, sel []Selection, groups []*fieldGroup, visited map[string]bool) []*fieldGroup {
+	for _, s := range sel {
+		switch s := s.(type) {
+		case *Field:
+			if !e.included(s.Directives) {
+				continue
+			}
+			key := s.ResponseKey()
+			found := false
+			for _, g := range groups {
+				if g.key == key {
+					g.fields = append(g.fields, s)
+					found = true
+					break
+				}
+			}
+			if !found {
+				groups = append(groups, &fieldGroup{key: key, fields: []*Field{s}})
+			}
+		case *FragmentSpread:
+			if visited[s.Name] || !e.included(s.Directives) {
+				continue
+			}
+			visited[s.Name] = true
+			f := e.doc.Fragments[s.Name]
+			if !e.included(f.Directives) {
+				continue
+			}
+			groups = e.collectFields(obj, f.Selection, groups, visited)
+		case *InlineFragment:
+			if !e.included(s.Directives) {
+				continue
+			}
+			groups = e.collectFields(obj, s.Selection, groups, visited)
+		}
+	}
+	return groups
+}
+
+// included returns false if the directives skip the selection.
+func (e *executor) included(dirs []*Directive) bool {
+	for _, d := range dirs {
+		if len(d.Arguments) == 0 {
+			continue
+		}
+		v, _ := coerceLiteral(d.Arguments[0].Value, Boolean, e.vars)
+		b, _ := v.(bool)
+		if (d.Name == "include" && !b) || (d.Name == "skip" && b) {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultResolve returns the resolver of a field without one, which takes the
+// value of the key of the field from a map, or the struct field whose json
+// name, or else name, is the name of the field.
+func defaultResolve(name string) ResolveFunc {
+	return func(ctx context.Context, p ResolveParams) (interface{}, error) {
+		rv := reflect.ValueOf(p.Source)
+		for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+			if rv.IsNil() {
+				return nil, nil
+			}
+			rv = rv.Elem()
+		}
+
+		switch rv.Kind() {
+		case reflect.Map:
+			if rv.Type().Key().Kind() != reflect.String {
+				return nil, nil
+			}
+			v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+			if !v.IsValid() {
+				return nil, nil
+			}
+			return v.Interface(), nil
+		case reflect.Struct:
+			rt := rv.Type()
+			for i := 0; i < rt.NumField(); i++ {
+				sf := rt.Field(i)
+				if sf.PkgPath != "" {
+					continue
+				}
+				tag := strings.Split(sf.Tag.Get("json"), ",")[0]
+				if tag == name || (tag == "" && strings.EqualFold(sf.Name, name)) {
+					return rv.Field(i).Interface(), nil
+				}
+			}
+		}
+		return nil, nil
+	}
+}
+
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	p := make([]interface{}, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, elem)
+}
+
+// responseMap is an object of the response, which keeps the order of the
+// fields of its selection.
+type responseMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *responseMap) set(key string, v interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+// MarshalJSON encodes the object with its fields in order.
+func (m *responseMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
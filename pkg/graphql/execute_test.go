@@ -0,0 +1,379 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/graphql"
+)
+
+type testRun struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Logs   []string
+}
+
+type testTask struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	runs []*testRun
+}
+
+var testTasks = []*testTask{
+	{ID: "1", Name: "a", runs: []*testRun{
+		{ID: "10", Status: "success", Logs: []string{"started", "done"}},
+		{ID: "11", Status: "failed", Logs: []string{"started", "error"}},
+	}},
+	{ID: "2", Name: "b"},
+}
+
+func newTestSchema(t *testing.T) *graphql.Schema {
+	t.Helper()
+
+	run := &graphql.Object{
+		Name: "Run",
+		Fields: map[string]*graphql.FieldDef{
+			"id":     {Type: graphql.NewNonNull(graphql.ID)},
+			"status": {Type: graphql.String},
+			"logs":   {Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			"failing": {
+				Type: graphql.String,
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					return nil, errors.New("run failed")
+				},
+			},
+			"required": {
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					return nil, nil
+				},
+			},
+		},
+	}
+	task := &graphql.Object{
+		Name: "Task",
+		Fields: map[string]*graphql.FieldDef{
+			"id":   {Type: graphql.NewNonNull(graphql.ID)},
+			"name": {Type: graphql.String},
+			"runs": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(run))),
+				Args: map[string]*graphql.ArgDef{
+					"limit":  {Type: graphql.Int, Default: 100},
+					"status": {Type: graphql.String},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					var runs []*testRun
+					for _, r := range p.Source.(*testTask).runs {
+						if s, ok := p.Args["status"].(string); ok && r.Status != s {
+							continue
+						}
+						if len(runs) == p.Args["limit"].(int) {
+							break
+						}
+						runs = append(runs, r)
+					}
+					return runs, nil
+				},
+			},
+		},
+	}
+	query := &graphql.Object{
+		Name: "Query",
+		Fields: map[string]*graphql.FieldDef{
+			"tasks": {
+				Type: graphql.NewList(task),
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					return testTasks, nil
+				},
+			},
+			"task": {
+				Type: task,
+				Args: map[string]*graphql.ArgDef{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					for _, t := range testTasks {
+						if t.ID == p.Args["id"] {
+							return t, nil
+						}
+					}
+					return nil, fmt.Errorf("task %s not found", p.Args["id"])
+				},
+			},
+			"tasksByID": {
+				Type: graphql.NewList(task),
+				Args: map[string]*graphql.ArgDef{
+					"ids": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					var tasks []*testTask
+					for _, id := range p.Args["ids"].([]interface{}) {
+						for _, t := range testTasks {
+							if t.ID == id {
+								tasks = append(tasks, t)
+							}
+						}
+					}
+					return tasks, nil
+				},
+			},
+			"ratio": {
+				Type: graphql.Float,
+				Args: map[string]*graphql.ArgDef{
+					"value": {Type: graphql.NewNonNull(graphql.Float)},
+				},
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					return p.Args["value"].(float64) / 2, nil
+				},
+			},
+			"flag": {
+				Type: graphql.Boolean,
+				Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+					return true, nil
+				},
+			},
+		},
+	}
+
+	s, err := graphql.NewSchema(query)
+	if err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	return s
+}
+
+func TestExecute(t *testing.T) {
+	schema := newTestSchema(t)
+
+	for _, tt := range []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		maxDepth  int
+		exp       string
+	}{
+		{
+			name:  "nested fields in order",
+			query: `{ tasks { name id runs { id logs } } }`,
+			exp:   `{"data":{"tasks":[{"name":"a","id":"1","runs":[{"id":"10","logs":["started","done"]},{"id":"11","logs":["started","error"]}]},{"name":"b","id":"2","runs":[]}]}}`,
+		},
+		{
+			name:  "arguments and aliases",
+			query: `{ first: task(id: "1") { runs(status: "failed") { id status } } second: task(id: 2) { __typename name } }`,
+			exp:   `{"data":{"first":{"runs":[{"id":"11","status":"failed"}]},"second":{"__typename":"Task","name":"b"}}}`,
+		},
+		{
+			name:      "variables",
+			query:     `query ($id: ID!, $limit: Int = 1) { task(id: $id) { runs(limit: $limit) { id } } }`,
+			variables: map[string]interface{}{"id": "1"},
+			exp:       `{"data":{"task":{"runs":[{"id":"10"}]}}}`,
+		},
+		{
+			name:      "list variable",
+			query:     `query ($ids: [ID!]!) { tasksByID(ids: $ids) { id } }`,
+			variables: map[string]interface{}{"ids": []interface{}{"2", "1"}},
+			exp:       `{"data":{"tasksByID":[{"id":"2"},{"id":"1"}]}}`,
+		},
+		{
+			name:  "list literal with a single value",
+			query: `{ tasksByID(ids: "2") { id } }`,
+			exp:   `{"data":{"tasksByID":[{"id":"2"}]}}`,
+		},
+		{
+			name:  "float from int",
+			query: `{ ratio(value: 3) flag }`,
+			exp:   `{"data":{"ratio":1.5,"flag":true}}`,
+		},
+		{
+			name:      "fragments and directives",
+			query:     `query ($skip: Boolean!) { task(id: "1") { ...task ... on Task { id @skip(if: $skip) } runs @include(if: false) { id } } } fragment task on Task { name }`,
+			variables: map[string]interface{}{"skip": true},
+			exp:       `{"data":{"task":{"name":"a"}}}`,
+		},
+		{
+			name:      "operation name",
+			query:     `query A { flag } query B { ratio(value: 1) }`,
+			operation: "B",
+			exp:       `{"data":{"ratio":0.5}}`,
+		},
+		{
+			name:  "error of a nullable field",
+			query: `{ flag task(id: "3") { id } }`,
+			exp:   `{"errors":[{"message":"task 3 not found","locations":[{"line":1,"column":8}],"path":["task"]}],"data":{"flag":true,"task":null}}`,
+		},
+		{
+			name:  "error in a list",
+			query: `{ task(id: "1") { runs(limit: 1) { id failing } } }`,
+			exp:   `{"errors":[{"message":"run failed","locations":[{"line":1,"column":39}],"path":["task","runs",0,"failing"]}],"data":{"task":{"runs":[{"id":"10","failing":null}]}}}`,
+		},
+		{
+			name:  "null of a non-null field propagates",
+			query: `{ flag task(id: "1") { id runs(limit: 1) { required } } }`,
+			exp:   `{"errors":[{"message":"non-null field required returned null","locations":[{"line":1,"column":44}],"path":["task","runs",0,"required"]}],"data":{"flag":true,"task":null}}`,
+		},
+		{
+			name:  "syntax error",
+			query: `{ task(id: ) { id } }`,
+			exp:   `{"errors":[{"message":"syntax error at 1:12: unexpected \")\"","locations":[{"line":1,"column":12}]}]}`,
+		},
+		{
+			name:  "unknown field",
+			query: `{ task(id: "1") { owner } }`,
+			exp:   `{"errors":[{"message":"unknown field owner of type Task","locations":[{"line":1,"column":19}]}]}`,
+		},
+		{
+			name:  "unknown argument",
+			query: `{ tasks(limit: 1) { id } }`,
+			exp:   `{"errors":[{"message":"unknown argument limit of field tasks","locations":[{"line":1,"column":9}]}]}`,
+		},
+		{
+			name:  "missing argument",
+			query: `{ task { id } }`,
+			exp:   `{"errors":[{"message":"argument id of field task is required","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:  "invalid argument",
+			query: `{ task(id: "1") { runs(limit: "1") { id } } }`,
+			exp:   `{"errors":[{"message":"argument limit of field runs: Int cannot represent 1","locations":[{"line":1,"column":24}]}]}`,
+		},
+		{
+			name:  "missing selection",
+			query: `{ task(id: "1") }`,
+			exp:   `{"errors":[{"message":"field task of type Task must have a selection of fields","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:  "selection of a scalar",
+			query: `{ flag { id } }`,
+			exp:   `{"errors":[{"message":"field flag of type Boolean cannot have a selection of fields","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:  "fragment on another type",
+			query: `{ ...task } fragment task on Task { id }`,
+			exp:   `{"errors":[{"message":"fragment task on Task cannot be spread on Query","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:  "fragment cycle",
+			query: `{ tasks { ...a } } fragment a on Task { ...b } fragment b on Task { ...a }`,
+			exp:   `{"errors":[{"message":"fragment a spreads itself","locations":[{"line":1,"column":69}]}]}`,
+		},
+		{
+			name:  "undefined variable",
+			query: `{ task(id: $id) { id } }`,
+			exp:   `{"errors":[{"message":"argument id of field task: variable $id is not defined","locations":[{"line":1,"column":8}]}]}`,
+		},
+		{
+			name:  "variable of another type",
+			query: `query ($id: Int!) { task(id: $id) { id } }`,
+			exp:   `{"errors":[{"message":"argument id of field task: variable $id of type Int! cannot be used as ID!","locations":[{"line":1,"column":26}]}]}`,
+		},
+		{
+			name:  "nullable variable for a non-null argument",
+			query: `query ($id: ID) { task(id: $id) { id } }`,
+			exp:   `{"errors":[{"message":"argument id of field task: variable $id of type ID cannot be used as ID!","locations":[{"line":1,"column":24}]}]}`,
+		},
+		{
+			name:  "missing variable",
+			query: `query ($id: ID!) { task(id: $id) { id } }`,
+			exp:   `{"errors":[{"message":"variable $id of type ID! is required","locations":[{"line":1,"column":8}]}]}`,
+		},
+		{
+			name:      "invalid variable",
+			query:     `query ($id: ID!) { task(id: $id) { id } }`,
+			variables: map[string]interface{}{"id": true},
+			exp:       `{"errors":[{"message":"variable $id: ID cannot represent true","locations":[{"line":1,"column":8}]}]}`,
+		},
+		{
+			name:     "too deep",
+			query:    `{ task(id: "1") { runs { id } } }`,
+			exp:      `{"errors":[{"message":"query is nested deeper than 2 levels","locations":[{"line":1,"column":26}]}]}`,
+			maxDepth: 2,
+		},
+		{
+			name:  "mutation",
+			query: `mutation { flag }`,
+			exp:   `{"errors":[{"message":"mutation operations are not supported","locations":[{"line":1,"column":1}]}]}`,
+		},
+		{
+			name:  "missing operation name",
+			query: `query A { flag } query B { flag }`,
+			exp:   `{"errors":[{"message":"operation name is required for a document with several operations"}]}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			res := graphql.Execute(context.Background(), schema, graphql.Params{
+				Query:         tt.query,
+				OperationName: tt.operation,
+				Variables:     tt.variables,
+				MaxDepth:      tt.maxDepth,
+			})
+			b, err := json.Marshal(res)
+			if err != nil {
+				t.Fatalf("failed to encode result: %v", err)
+			}
+			if got := string(b); got != tt.exp {
+				t.Errorf("unexpected result:\n\tgot: %s\n\texp: %s", got, tt.exp)
+			}
+		})
+	}
+}
+
+func TestExecute_ResolverError(t *testing.T) {
+	schema := newTestSchema(t)
+
+	res := graphql.Execute(context.Background(), schema, graphql.Params{Query: `{ task(id: "5") { id } }`})
+	if len(res.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %d", len(res.Errors))
+	}
+	if err := res.Errors[0].Err; err == nil || err.Error() != "task 5 not found" {
+		t.Errorf("expected the error of the resolver, got %v", err)
+	}
+}
+
+func TestNewSchema_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		query *graphql.Object
+		err   string
+	}{
+		{
+			name:  "no fields",
+			query: &graphql.Object{Name: "Query"},
+			err:   "object Query has no fields",
+		},
+		{
+			name: "field without type",
+			query: &graphql.Object{Name: "Query", Fields: map[string]*graphql.FieldDef{
+				"a": {},
+			}},
+			err: "field Query.a has no type",
+		},
+		{
+			name: "object argument",
+			query: &graphql.Object{Name: "Query", Fields: map[string]*graphql.FieldDef{
+				"a": {Type: graphql.String, Args: map[string]*graphql.ArgDef{
+					"b": {Type: &graphql.Object{Name: "B"}},
+				}},
+			}},
+			err: "argument b of field Query.a is not an input type",
+		},
+		{
+			name: "duplicate names",
+			query: &graphql.Object{Name: "Query", Fields: map[string]*graphql.FieldDef{
+				"a": {Type: &graphql.Object{Name: "A", Fields: map[string]*graphql.FieldDef{"id": {Type: graphql.ID}}}},
+				"b": {Type: &graphql.Object{Name: "A", Fields: map[string]*graphql.FieldDef{"id": {Type: graphql.ID}}}},
+			}},
+			err: "type A is defined more than once",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.NewSchema(tt.query)
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenKind is the kind of a lexical token of a document.
+type TokenKind int
+
+// The kinds of tokens.
+const (
+	TokenEOF TokenKind = iota
+	TokenPunctuator
+	TokenName
+	TokenInt
+	TokenFloat
+	TokenString
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenEOF:
+		return "EOF"
+	case TokenPunctuator:
+		return "punctuator"
+	case TokenName:
+		return "name"
+	case TokenInt:
+		return "int"
+	case TokenFloat:
+		return "float"
+	case TokenString:
+		return "string"
+	}
+	return "unknown"
+}
+
+type token struct {
+	kind TokenKind
+	// value is the text of the token, which for a string is its unescaped value.
+	value string
+	loc   Location
+}
+
+func (t token) String() string {
+	if t.kind == TokenEOF {
+		return "EOF"
+	}
+	return strconv.Quote(t.value)
+}
+
+// lexer splits the source of a document into tokens. Whitespace, commas and
+// comments are ignored.
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	col  int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1, col: 1}
+}
+
+func (l *lexer) errorf(loc Location, format string, args ...interface{}) error {
+	return &SyntaxError{Message: fmt.Sprintf(format, args...), Location: loc}
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n; i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.advance(1)
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	loc := Location{Line: l.line, Column: l.col}
+	if l.pos >= len(l.src) {
+		return token{kind: TokenEOF, loc: loc}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.advance(3)
+		return token{kind: TokenPunctuator, value: "...", loc: loc}, nil
+	case strings.IndexByte("!$()[]{}:=@|&", c) >= 0:
+		l.advance(1)
+		return token{kind: TokenPunctuator, value: string(c), loc: loc}, nil
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.advance(1)
+		}
+		return token{kind: TokenName, value: l.src[start:l.pos], loc: loc}, nil
+	case c == '-' || isDigit(c):
+		return l.number(loc)
+	case c == '"':
+		return l.string(loc)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return token{}, l.errorf(loc, "unexpected character %q", r)
+}
+
+func (l *lexer) number(loc Location) (token, error) {
+	start := l.pos
+	kind := TokenInt
+	if l.src[l.pos] == '-' {
+		l.advance(1)
+	}
+	digits := func() int {
+		n := 0
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.advance(1)
+			n++
+		}
+		return n
+	}
+	if digits() == 0 {
+		return token{}, l.errorf(loc, "invalid number %q", l.src[start:l.pos])
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = TokenFloat
+		l.advance(1)
+		if digits() == 0 {
+			return token{}, l.errorf(loc, "invalid number %q", l.src[start:l.pos])
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = TokenFloat
+		l.advance(1)
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.advance(1)
+		}
+		if digits() == 0 {
+			return token{}, l.errorf(loc, "invalid number %q", l.src[start:l.pos])
+		}
+	}
+	return token{kind: kind, value: l.src[start:l.pos], loc: loc}, nil
+}
+
+// string lexes a quoted string. Block strings are not supported.
+func (l *lexer) string(loc Location) (token, error) {
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		return token{}, l.errorf(loc, "block strings are not supported")
+	}
+	l.advance(1)
+
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' {
+			return token{}, l.errorf(loc, "unterminated string")
+		}
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.advance(1)
+			return token{kind: TokenString, value: b.String(), loc: loc}, nil
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, l.errorf(loc, "unterminated string")
+			}
+			esc := l.src[l.pos+1]
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+6 > len(l.src) {
+					return token{}, l.errorf(loc, "invalid unicode escape in string")
+				}
+				r, err := strconv.ParseUint(l.src[l.pos+2:l.pos+6], 16, 32)
+				if err != nil {
+					return token{}, l.errorf(loc, "invalid unicode escape in string")
+				}
+				b.WriteRune(rune(r))
+				l.advance(4)
+			default:
+				return token{}, l.errorf(loc, "invalid escape \\%c in string", esc)
+			}
+			l.advance(2)
+		default:
+			r, n := utf8.DecodeRuneInString(l.src[l.pos:])
+			b.WriteRune(r)
+			l.pos += n
+			l.col++
+		}
+	}
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,442 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+// SyntaxError is an error in the syntax of a document.
+type SyntaxError struct {
+	Message  string
+	Location Location
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %d:%d: %s", e.Location.Line, e.Location.Column, e.Message)
+}
+
+// Parse parses the executable definitions of a document: its operations and
+// fragments. Type system definitions are not supported.
+func Parse(src string) (*Document, error) {
+	p := &parser{lex: newLexer(src)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.tok.kind != TokenEOF {
+		switch {
+		case p.peek("{"):
+			sel, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selection: sel, Location: sel.location()})
+		case p.tok.kind == TokenName && (p.tok.value == "query" || p.tok.value == "mutation" || p.tok.value == "subscription"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.tok.kind == TokenName && p.tok.value == "fragment":
+			f, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[f.Name]; ok {
+				return nil, &SyntaxError{Message: fmt.Sprintf("fragment %q is defined more than once", f.Name), Location: f.Location}
+			}
+			doc.Fragments[f.Name] = f
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, &SyntaxError{Message: "document has no operation", Location: p.tok.loc}
+	}
+	return doc, nil
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	return &SyntaxError{Message: fmt.Sprintf("unexpected %s", p.tok), Location: p.tok.loc}
+}
+
+// peek returns true if the token is the punctuator.
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == TokenPunctuator && p.tok.value == punct
+}
+
+// skip advances past the token and returns true if it is the punctuator.
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return &SyntaxError{Message: fmt.Sprintf("expected %q, got %s", punct, p.tok), Location: p.tok.loc}
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != TokenName {
+		return "", &SyntaxError{Message: fmt.Sprintf("expected name, got %s", p.tok), Location: p.tok.loc}
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) operation() (*Operation, error) {
+	op := &Operation{Type: p.tok.value, Location: p.tok.loc}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == TokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := p.skip("("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(")") {
+			v, err := p.variableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, v)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Directives of operations are parsed but have no effect.
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	sel, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.Selection = sel
+	return op, nil
+}
+
+func (p *parser) variableDefinition() (*VariableDefinition, error) {
+	v := &VariableDefinition{Location: p.tok.loc}
+	if err := p.expect("$"); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	v.Name = name
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	if v.Type, err = p.typeRef(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip("="); err != nil {
+		return nil, err
+	} else if ok {
+		if v.Default, err = p.value(true); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (p *parser) typeRef() (TypeRef, error) {
+	var t TypeRef
+	if ok, err := p.skip("["); err != nil {
+		return t, err
+	} else if ok {
+		elem, err := p.typeRef()
+		if err != nil {
+			return t, err
+		}
+		if err := p.expect("]"); err != nil {
+			return t, err
+		}
+		t.Elem = &elem
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return t, err
+		}
+		t.Name = name
+	}
+
+	ok, err := p.skip("!")
+	t.NonNull = ok
+	return t, err
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	f := &Fragment{Location: p.tok.loc}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if name == "on" {
+		return nil, &SyntaxError{Message: "fragment must have a name", Location: f.Location}
+	}
+	f.Name = name
+
+	if p.tok.kind != TokenName || p.tok.value != "on" {
+		return nil, &SyntaxError{Message: fmt.Sprintf("expected \"on\", got %s", p.tok), Location: p.tok.loc}
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if f.TypeCondition, err = p.name(); err != nil {
+		return nil, err
+	}
+	if f.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if f.Selection, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+type selectionSet []Selection
+
+func (s selectionSet) location() Location {
+	if len(s) == 0 {
+		return Location{}
+	}
+	switch sel := s[0].(type) {
+	case *Field:
+		return sel.Location
+	case *FragmentSpread:
+		return sel.Location
+	case *InlineFragment:
+		return sel.Location
+	}
+	return Location{}
+}
+
+func (p *parser) selectionSet() (selectionSet, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var sel selectionSet
+	for !p.peek("}") {
+		s, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, s)
+	}
+	if len(sel) == 0 {
+		return nil, &SyntaxError{Message: "selection set is empty", Location: p.tok.loc}
+	}
+	return sel, p.advance()
+}
+
+func (p *parser) selection() (Selection, error) {
+	loc := p.tok.loc
+	if ok, err := p.skip("..."); err != nil {
+		return nil, err
+	} else if ok {
+		return p.fragmentSelection(loc)
+	}
+
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	f := &Field{Name: name, Location: loc}
+	if ok, err := p.skip(":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.Alias = name
+		if f.Name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+
+	if f.Arguments, err = p.arguments(); err != nil {
+		return nil, err
+	}
+	if f.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if p.peek("{") {
+		if f.Selection, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// fragmentSelection parses a fragment spread or an inline fragment, after its "...".
+func (p *parser) fragmentSelection(loc Location) (Selection, error) {
+	if p.tok.kind == TokenName && p.tok.value != "on" {
+		s := &FragmentSpread{Name: p.tok.value, Location: loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if s.Directives, err = p.directives(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	f := &InlineFragment{Location: loc}
+	var err error
+	if p.tok.kind == TokenName && p.tok.value == "on" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if f.TypeCondition, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if f.Directives, err = p.directives(); err != nil {
+		return nil, err
+	}
+	if f.Selection, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (p *parser) arguments() ([]*Argument, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+
+	var args []*Argument
+	for !p.peek(")") {
+		a := &Argument{Location: p.tok.loc}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		a.Name = name
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if a.Value, err = p.value(false); err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+	}
+	if len(args) == 0 {
+		return nil, &SyntaxError{Message: "argument list is empty", Location: p.tok.loc}
+	}
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]*Directive, error) {
+	var dirs []*Directive
+	for p.peek("@") {
+		d := &Directive{Location: p.tok.loc}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		d.Name = name
+		if d.Arguments, err = p.arguments(); err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, d)
+	}
+	return dirs, nil
+}
+
+// value parses a value. Variables are not allowed in constant values, such as
+// the defaults of variables.
+func (p *parser) value(constant bool) (Value, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == TokenPunctuator && tok.value == "$":
+		if constant {
+			return nil, &SyntaxError{Message: "variable is not allowed in a constant value", Location: tok.loc}
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		return &Variable{Name: name}, nil
+	case tok.kind == TokenPunctuator && tok.value == "[":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := &ListValue{}
+		for !p.peek("]") {
+			v, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			list.Values = append(list.Values, v)
+		}
+		return list, p.advance()
+	case tok.kind == TokenPunctuator && tok.value == "{":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		obj := &ObjectValue{}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			v, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			obj.Fields = append(obj.Fields, &ObjectField{Name: name, Value: v})
+		}
+		return obj, p.advance()
+	case tok.kind == TokenInt || tok.kind == TokenFloat || tok.kind == TokenString:
+		return &ScalarValue{Kind: tok.kind, Value: tok.value}, p.advance()
+	case tok.kind == TokenName:
+		if tok.value == "null" {
+			return &NullValue{}, p.advance()
+		}
+		// true, false and enum values.
+		return &ScalarValue{Kind: TokenName, Value: tok.value}, p.advance()
+	}
+	return nil, p.unexpected()
+}
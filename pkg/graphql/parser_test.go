@@ -0,0 +1,151 @@
+package graphql_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/graphql"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := graphql.Parse(`
+		# Find a task and its runs.
+		query Task($id: ID!, $limit: Int = 10, $ids: [ID!]) {
+			task(id: $id) {
+				id
+				name: taskName
+				runs(limit: $limit, status: "failed") @include(if: true) {
+					...run
+				}
+				... on Task { status }
+			}
+		}
+
+		fragment run on Run {
+			id, scheduledFor
+		}
+	`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(doc.Operations) != 1 {
+		t.Fatalf("expected 1 operation, got %d", len(doc.Operations))
+	}
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "Task" {
+		t.Errorf("unexpected operation %s %s", op.Type, op.Name)
+	}
+
+	var types []string
+	for _, v := range op.Variables {
+		types = append(types, "$"+v.Name+": "+v.Type.String())
+	}
+	if exp := []string{"$id: ID!", "$limit: Int", "$ids: [ID!]"}; !reflect.DeepEqual(types, exp) {
+		t.Errorf("unexpected variables: -got/+exp\n\t%v\n\t%v", types, exp)
+	}
+	if exp := (&graphql.ScalarValue{Kind: graphql.TokenInt, Value: "10"}); !reflect.DeepEqual(op.Variables[1].Default, exp) {
+		t.Errorf("unexpected default %#v", op.Variables[1].Default)
+	}
+
+	task := op.Selection[0].(*graphql.Field)
+	if task.Name != "task" || task.Location != (graphql.Location{Line: 4, Column: 4}) {
+		t.Errorf("unexpected field %s at %v", task.Name, task.Location)
+	}
+	if !reflect.DeepEqual(task.Arguments[0].Value, &graphql.Variable{Name: "id"}) {
+		t.Errorf("unexpected argument %#v", task.Arguments[0].Value)
+	}
+
+	name := task.Selection[1].(*graphql.Field)
+	if name.Alias != "name" || name.Name != "taskName" || name.ResponseKey() != "name" {
+		t.Errorf("unexpected alias %s of %s", name.Alias, name.Name)
+	}
+
+	runs := task.Selection[2].(*graphql.Field)
+	if len(runs.Arguments) != 2 || len(runs.Directives) != 1 || runs.Directives[0].Name != "include" {
+		t.Errorf("unexpected arguments or directives of runs")
+	}
+	if spread, ok := runs.Selection[0].(*graphql.FragmentSpread); !ok || spread.Name != "run" {
+		t.Errorf("expected spread of fragment run, got %#v", runs.Selection[0])
+	}
+	if inline, ok := task.Selection[3].(*graphql.InlineFragment); !ok || inline.TypeCondition != "Task" {
+		t.Errorf("expected inline fragment on Task, got %#v", task.Selection[3])
+	}
+
+	f, ok := doc.Fragments["run"]
+	if !ok || f.TypeCondition != "Run" || len(f.Selection) != 2 {
+		t.Errorf("unexpected fragment %#v", f)
+	}
+}
+
+func TestParse_Shorthand(t *testing.T) {
+	doc, err := graphql.Parse(`{ organizations { name } }`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if op := doc.Operations[0]; op.Type != "query" || op.Name != "" || len(op.Selection) != 1 {
+		t.Errorf("unexpected operation %#v", op)
+	}
+}
+
+func TestParse_Values(t *testing.T) {
+	doc, err := graphql.Parse(`{ f(a: -1.5e3, b: "x\"é\n", c: [1, null, $v], d: false, e: { k: ENUM }) }`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	args := doc.Operations[0].Selection[0].(*graphql.Field).Arguments
+	exp := []graphql.Value{
+		&graphql.ScalarValue{Kind: graphql.TokenFloat, Value: "-1.5e3"},
+		&graphql.ScalarValue{Kind: graphql.TokenString, Value: "x\"é\n"},
+		&graphql.ListValue{Values: []graphql.Value{
+			&graphql.ScalarValue{Kind: graphql.TokenInt, Value: "1"},
+			&graphql.NullValue{},
+			&graphql.Variable{Name: "v"},
+		}},
+		&graphql.ScalarValue{Kind: graphql.TokenName, Value: "false"},
+		&graphql.ObjectValue{Fields: []*graphql.ObjectField{
+			{Name: "k", Value: &graphql.ScalarValue{Kind: graphql.TokenName, Value: "ENUM"}},
+		}},
+	}
+	for i, a := range args {
+		if !reflect.DeepEqual(a.Value, exp[i]) {
+			t.Errorf("unexpected value of argument %s: %#v", a.Name, a.Value)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		query string
+		err   string
+		loc   graphql.Location
+	}{
+		{name: "empty", query: ``, err: "document has no operation", loc: graphql.Location{Line: 1, Column: 1}},
+		{name: "empty selection", query: `{ }`, err: "selection set is empty", loc: graphql.Location{Line: 1, Column: 3}},
+		{name: "unclosed selection", query: "{\n  a", err: `expected name, got EOF`, loc: graphql.Location{Line: 2, Column: 4}},
+		{name: "unterminated string", query: `{ a(b: "c) }`, err: "unterminated string", loc: graphql.Location{Line: 1, Column: 8}},
+		{name: "invalid number", query: `{ a(b: 1.) }`, err: `invalid number "1."`, loc: graphql.Location{Line: 1, Column: 8}},
+		{name: "unexpected character", query: `{ a % }`, err: `unexpected character '%'`, loc: graphql.Location{Line: 1, Column: 5}},
+		{name: "variable in default", query: `query ($a: Int = $b) { a }`, err: "variable is not allowed in a constant value", loc: graphql.Location{Line: 1, Column: 18}},
+		{name: "fragment without name", query: `fragment on T { a }`, err: "fragment must have a name", loc: graphql.Location{Line: 1, Column: 1}},
+		{name: "duplicate fragment", query: "{ a }\nfragment f on T { a }\nfragment f on T { b }", err: `fragment "f" is defined more than once`, loc: graphql.Location{Line: 3, Column: 1}},
+		{name: "block string", query: `{ a(b: """c""") }`, err: "block strings are not supported", loc: graphql.Location{Line: 1, Column: 8}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.Parse(tt.query)
+			serr, ok := err.(*graphql.SyntaxError)
+			if !ok {
+				t.Fatalf("expected syntax error, got %v", err)
+			}
+			if !strings.Contains(serr.Message, tt.err) {
+				t.Errorf("expected error %q, got %q", tt.err, serr.Message)
+			}
+			if serr.Location != tt.loc {
+				t.Errorf("expected error at %v, got %v", tt.loc, serr.Location)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Type is a type of a schema: a *Scalar, an *Object, a *List or a *NonNull.
+type Type interface {
+	String() string
+	isType()
+}
+
+// Scalar is a leaf type of a schema.
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize converts a resolved value to its value in the response.
+	Serialize func(v interface{}) (interface{}, error)
+	// ParseValue converts the decoded JSON value of a variable to an input value.
+	ParseValue func(v interface{}) (interface{}, error)
+	// ParseLiteral converts a literal of a document to an input value.
+	ParseLiteral func(v *ScalarValue) (interface{}, error)
+}
+
+// Object is an object type of a schema. The fields of an object may refer to
+// the object itself, or to objects that refer back to it, as long as they are
+// set before the schema is created.
+type Object struct {
+	Name        string
+	Description string
+	Fields      map[string]*FieldDef
+}
+
+// List is a list of values of a type.
+type List struct {
+	OfType Type
+}
+
+// NonNull is a type whose values are never null.
+type NonNull struct {
+	OfType Type
+}
+
+// NewList returns the list type of t.
+func NewList(t Type) *List {
+	return &List{OfType: t}
+}
+
+// NewNonNull returns the non-null type of t.
+func NewNonNull(t Type) *NonNull {
+	return &NonNull{OfType: t}
+}
+
+func (t *Scalar) String() string  { return t.Name }
+func (t *Object) String() string  { return t.Name }
+func (t *List) String() string    { return "[" + t.OfType.String() + "]" }
+func (t *NonNull) String() string { return t.OfType.String() + "!" }
+
+func (*Scalar) isType()  {}
+func (*Object) isType()  {}
+func (*List) isType()    {}
+func (*NonNull) isType() {}
+
+// FieldDef defines a field of an object.
+type FieldDef struct {
+	Type        Type
+	Description string
+	Args        map[string]*ArgDef
+	// Resolve returns the value of the field. Fields without a resolver take
+	// the value of the key of the field from a map, or the struct field whose
+	// json name is the name of the field.
+	Resolve ResolveFunc
+}
+
+// ArgDef defines an argument of a field. Arguments are scalars or lists of scalars.
+type ArgDef struct {
+	Type        Type
+	Description string
+	// Default is the value of the argument when a query leaves it out.
+	Default interface{}
+}
+
+// ResolveFunc resolves the value of a field.
+type ResolveFunc func(ctx context.Context, p ResolveParams) (interface{}, error)
+
+// ResolveParams are the parameters of a ResolveFunc.
+type ResolveParams struct {
+	// Source is the value of the object the field belongs to.
+	Source interface{}
+	// Args are the coerced arguments of the field, including their defaults.
+	Args map[string]interface{}
+	// Path is the path of the field in the response.
+	Path []interface{}
+}
+
+// Schema is a schema for the queries of an API.
+type Schema struct {
+	Query *Object
+
+	// inputs are the scalars that variables may have, by name.
+	inputs map[string]*Scalar
+}
+
+// NewSchema returns a schema with the query type, after checking that every
+// type reachable from it is well formed.
+func NewSchema(query *Object) (*Schema, error) {
+	s := &Schema{
+		Query:  query,
+		inputs: make(map[string]*Scalar),
+	}
+	for _, t := range []*Scalar{String, Int, Float, Boolean, ID} {
+		s.inputs[t.Name] = t
+	}
+
+	if err := s.check(query, make(map[string]Type)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// check checks the type and the types it refers to. seen holds the named
+// types checked so far, so each is checked once and no two share a name.
+func (s *Schema) check(t Type, seen map[string]Type) error {
+	switch t := t.(type) {
+	case *NonNull:
+		if _, ok := t.OfType.(*NonNull); ok {
+			return fmt.Errorf("type %s is non-null twice", t)
+		}
+		return s.check(t.OfType, seen)
+	case *List:
+		return s.check(t.OfType, seen)
+	case *Scalar, *Object:
+		name := t.String()
+		if name == "" {
+			return fmt.Errorf("type has no name")
+		}
+		if prev, ok := seen[name]; ok {
+			if prev != t {
+				return fmt.Errorf("type %s is defined more than once", name)
+			}
+			return nil
+		}
+		seen[name] = t
+	case nil:
+		return fmt.Errorf("type is nil")
+	default:
+		return fmt.Errorf("unknown type %s", t)
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		if t.Serialize == nil {
+			return fmt.Errorf("scalar %s has no serializer", t.Name)
+		}
+	case *Object:
+		if len(t.Fields) == 0 {
+			return fmt.Errorf("object %s has no fields", t.Name)
+		}
+		for _, name := range sortedFieldNames(t) {
+			f := t.Fields[name]
+			if f == nil || f.Type == nil {
+				return fmt.Errorf("field %s.%s has no type", t.Name, name)
+			}
+			for an, a := range f.Args {
+				if a == nil || a.Type == nil {
+					return fmt.Errorf("argument %s of field %s.%s has no type", an, t.Name, name)
+				}
+				sc, ok := namedType(a.Type).(*Scalar)
+				if !ok || sc.ParseValue == nil || sc.ParseLiteral == nil {
+					return fmt.Errorf("argument %s of field %s.%s is not an input type", an, t.Name, name)
+				}
+				if err := s.check(a.Type, seen); err != nil {
+					return err
+				}
+				s.inputs[sc.Name] = sc
+			}
+			if err := s.check(f.Type, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sortedFieldNames(o *Object) []string {
+	names := make([]string, 0, len(o.Fields))
+	for name := range o.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// namedType returns the type wrapped by lists and non-null types.
+func namedType(t Type) Type {
+	for {
+		switch tt := t.(type) {
+		case *List:
+			t = tt.OfType
+		case *NonNull:
+			t = tt.OfType
+		default:
+			return t
+		}
+	}
+}
+
+// The built-in scalars.
+var (
+	String = &Scalar{
+		Name:      "String",
+		Serialize: serializeString,
+		ParseValue: func(v interface{}) (interface{}, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("String cannot represent %v", v)
+			}
+			return s, nil
+		},
+		ParseLiteral: func(v *ScalarValue) (interface{}, error) {
+			if v.Kind != TokenString {
+				return nil, fmt.Errorf("String cannot represent %s", v.Value)
+			}
+			return v.Value, nil
+		},
+	}
+
+	// ID is an opaque identifier, which is a string in the response.
+	ID = &Scalar{
+		Name:      "ID",
+		Serialize: serializeString,
+		ParseValue: func(v interface{}) (interface{}, error) {
+			switch v := v.(type) {
+			case string:
+				return v, nil
+			case float64:
+				if v == math.Trunc(v) {
+					return strconv.FormatFloat(v, 'f', -1, 64), nil
+				}
+			}
+			return nil, fmt.Errorf("ID cannot represent %v", v)
+		},
+		ParseLiteral: func(v *ScalarValue) (interface{}, error) {
+			if v.Kind != TokenString && v.Kind != TokenInt {
+				return nil, fmt.Errorf("ID cannot represent %s", v.Value)
+			}
+			return v.Value, nil
+		},
+	}
+
+	// Int is a signed 32-bit integer. Its input values are ints.
+	Int = &Scalar{
+		Name: "Int",
+		Serialize: func(v interface{}) (interface{}, error) {
+			n, ok := toFloat(v)
+			if !ok || n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32 {
+				return nil, fmt.Errorf("Int cannot represent %v", v)
+			}
+			return int64(n), nil
+		},
+		ParseValue: func(v interface{}) (interface{}, error) {
+			n, ok := toFloat(v)
+			if !ok || n != math.Trunc(n) || n < math.MinInt32 || n > math.MaxInt32 {
+				return nil, fmt.Errorf("Int cannot represent %v", v)
+			}
+			return int(n), nil
+		},
+		ParseLiteral: func(v *ScalarValue) (interface{}, error) {
+			if v.Kind != TokenInt {
+				return nil, fmt.Errorf("Int cannot represent %s", v.Value)
+			}
+			n, err := strconv.ParseInt(v.Value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("Int cannot represent %s", v.Value)
+			}
+			return int(n), nil
+		},
+	}
+
+	// Float is a double-precision floating point number.
+	Float = &Scalar{
+		Name: "Float",
+		Serialize: func(v interface{}) (interface{}, error) {
+			n, ok := toFloat(v)
+			if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+				return nil, fmt.Errorf("Float cannot represent %v", v)
+			}
+			return n, nil
+		},
+		ParseValue: func(v interface{}) (interface{}, error) {
+			n, ok := toFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("Float cannot represent %v", v)
+			}
+			return n, nil
+		},
+		ParseLiteral: func(v *ScalarValue) (interface{}, error) {
+			if v.Kind != TokenInt && v.Kind != TokenFloat {
+				return nil, fmt.Errorf("Float cannot represent %s", v.Value)
+			}
+			return strconv.ParseFloat(v.Value, 64)
+		},
+	}
+
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Serialize: func(v interface{}) (interface{}, error) {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("Boolean cannot represent %v", v)
+			}
+			return b, nil
+		},
+		ParseValue: func(v interface{}) (interface{}, error) {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("Boolean cannot represent %v", v)
+			}
+			return b, nil
+		},
+		ParseLiteral: func(v *ScalarValue) (interface{}, error) {
+			if v.Kind == TokenName && (v.Value == "true" || v.Value == "false") {
+				return v.Value == "true", nil
+			}
+			return nil, fmt.Errorf("Boolean cannot represent %s", v.Value)
+		},
+	}
+)
+
+// serializeString serializes strings, and values with a String method such as IDs.
+func serializeString(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case bool, int, int32, int64, uint, uint32, uint64, float64:
+		return fmt.Sprint(v), nil
+	}
+	return nil, fmt.Errorf("String cannot represent %v", v)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
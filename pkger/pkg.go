@@ -0,0 +1,355 @@
+// Package pkger installs packages: declarative bundles of the buckets, labels,
+// variables, dashboards, tasks and telegraf configs of a monitoring stack, so
+// that a whole stack can be installed into an organization as a template.
+package pkger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ghodss/yaml"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+// APIVersion is the version of the package format read by this instance.
+const APIVersion = "0.1.0"
+
+// KindPackage is the kind of packages.
+const KindPackage = "Package"
+
+// Kind is the kind of a resource of a package.
+type Kind string
+
+// Kinds of the resources of a package, in the order they are applied. Labels
+// come first so that the other resources can be associated with them, and
+// tasks last as their queries may read the buckets of the package.
+const (
+	KindLabel     Kind = "Label"
+	KindBucket    Kind = "Bucket"
+	KindVariable  Kind = "Variable"
+	KindTelegraf  Kind = "Telegraf"
+	KindDashboard Kind = "Dashboard"
+	KindTask      Kind = "Task"
+)
+
+var kinds = []Kind{KindLabel, KindBucket, KindVariable, KindTelegraf, KindDashboard, KindTask}
+
+func (k Kind) valid() bool {
+	for _, kind := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Encoding is the encoding of a package.
+type Encoding int
+
+// Encodings of packages.
+const (
+	EncodingJSON Encoding = iota
+	EncodingYAML
+)
+
+// Pkg is a package of resources.
+type Pkg struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Metadata   Metadata `json:"meta"`
+	Spec       Spec     `json:"spec"`
+}
+
+// Metadata describes a package.
+type Metadata struct {
+	Name        string `json:"pkgName"`
+	Version     string `json:"pkgVersion,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Spec lists the resources of a package.
+type Spec struct {
+	Resources []Resource `json:"resources"`
+}
+
+// Resource is a resource of a package. Resources are identified in an
+// organization by their kind and name, and only the fields of their kind
+// are used.
+type Resource struct {
+	Kind        Kind   `json:"kind"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Labels are the names of the labels the resource is associated with,
+	// which are labels of the package or of the organization.
+	Labels []string `json:"labels,omitempty"`
+
+	// Color is the color of a label.
+	Color string `json:"color,omitempty"`
+
+	// RetentionPeriod is the retention period of a bucket as a duration such
+	// as 168h. Buckets without one keep their data forever.
+	RetentionPeriod string `json:"retentionPeriod,omitempty"`
+
+	// Arguments and Selected are the values of a variable.
+	Arguments *influxdb.VariableArguments `json:"arguments,omitempty"`
+	Selected  []string                    `json:"selected,omitempty"`
+
+	// Config is the TOML of a telegraf config.
+	Config string `json:"config,omitempty"`
+
+	// Cells are the cells of a dashboard with their views.
+	Cells []influxdb.DashboardExportCell `json:"cells,omitempty"`
+
+	// Flux is the script of a task. The name of the task is the name in its
+	// task option, which the name of the resource may be left out for.
+	Flux string `json:"flux,omitempty"`
+	// Status is the status of a task, active unless set.
+	Status string `json:"status,omitempty"`
+}
+
+// Parse decodes a package and returns it if it is valid.
+func Parse(enc Encoding, r io.Reader) (*Pkg, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to read package",
+			Err:  err,
+		}
+	}
+	if enc == EncodingYAML {
+		if b, err = yaml.YAMLToJSON(b); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "package is not valid YAML",
+				Err:  err,
+			}
+		}
+	}
+
+	pkg := &Pkg{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(pkg); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "unable to decode package",
+			Err:  err,
+		}
+	}
+	if err := pkg.Validate(); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// Validate returns an error if the package cannot be applied. The names of
+// tasks left out are set from their task option.
+func (p *Pkg) Validate() error {
+	switch {
+	case p.APIVersion != APIVersion:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("unsupported package apiVersion %q: must be %s", p.APIVersion, APIVersion),
+		}
+	case p.Kind != KindPackage:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid package kind %q: must be %s", p.Kind, KindPackage),
+		}
+	case strings.TrimSpace(p.Metadata.Name) == "":
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "package pkgName is required",
+		}
+	}
+
+	seen := map[Kind]map[string]bool{}
+	for i := range p.Spec.Resources {
+		r := &p.Spec.Resources[i]
+		if err := r.valid(); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("resource at index %d: %s", i, influxdb.ErrorMessage(err)),
+				Err:  err,
+			}
+		}
+		if seen[r.Kind] == nil {
+			seen[r.Kind] = map[string]bool{}
+		}
+		if seen[r.Kind][r.Name] {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("package has more than one %s named %s", r.Kind, r.Name),
+			}
+		}
+		seen[r.Kind][r.Name] = true
+	}
+	return nil
+}
+
+// Resources returns the resources of the package of the kind.
+func (p *Pkg) Resources(kind Kind) []*Resource {
+	rs := []*Resource{}
+	for i := range p.Spec.Resources {
+		if p.Spec.Resources[i].Kind == kind {
+			rs = append(rs, &p.Spec.Resources[i])
+		}
+	}
+	return rs
+}
+
+func (r *Resource) valid() error {
+	if !r.Kind.valid() {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid resource kind %q", r.Kind),
+		}
+	}
+	if r.Kind == KindTask {
+		if err := r.validTask(); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("%s name is required", r.Kind),
+		}
+	}
+	if r.Kind == KindLabel && len(r.Labels) > 0 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "labels cannot have labels",
+		}
+	}
+
+	switch r.Kind {
+	case KindBucket:
+		if _, err := r.retentionPeriod(); err != nil {
+			return err
+		}
+	case KindVariable:
+		if r.Arguments == nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "variable arguments are required",
+			}
+		}
+		v := r.variable(influxdb.InvalidID())
+		if err := v.Valid(); err != nil {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Err:  err,
+			}
+		}
+	case KindTelegraf:
+		if _, err := r.telegrafConfig(influxdb.InvalidID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resource) validTask() error {
+	if strings.TrimSpace(r.Flux) == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "task flux is required",
+		}
+	}
+	opts, err := options.FromScript(r.Flux)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid task flux",
+			Err:  err,
+		}
+	}
+	if r.Name == "" {
+		r.Name = opts.Name
+	}
+	if r.Name != opts.Name {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("task name %q differs from the name %q of its task option", r.Name, opts.Name),
+		}
+	}
+	switch r.Status {
+	case "", influxdb.TaskStatusActive, influxdb.TaskStatusInactive:
+	default:
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid task status %q", r.Status),
+		}
+	}
+	return nil
+}
+
+func (r *Resource) retentionPeriod() (time.Duration, error) {
+	if r.RetentionPeriod == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(r.RetentionPeriod)
+	if err != nil || d < 0 {
+		return 0, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("invalid bucket retentionPeriod %q: must be a duration such as 168h", r.RetentionPeriod),
+		}
+	}
+	return d, nil
+}
+
+func (r *Resource) labelProperties() map[string]string {
+	props := map[string]string{}
+	if r.Color != "" {
+		props["color"] = r.Color
+	}
+	if r.Description != "" {
+		props["description"] = r.Description
+	}
+	return props
+}
+
+func (r *Resource) variable(orgID influxdb.ID) *influxdb.Variable {
+	selected := r.Selected
+	if selected == nil {
+		selected = []string{}
+	}
+	return &influxdb.Variable{
+		OrganizationID: orgID,
+		Name:           r.Name,
+		Description:    r.Description,
+		Selected:       selected,
+		Arguments:      r.Arguments,
+	}
+}
+
+func (r *Resource) telegrafConfig(orgID influxdb.ID) (*influxdb.TelegrafConfig, error) {
+	tc := &influxdb.TelegrafConfig{}
+	if err := toml.Unmarshal([]byte(r.Config), tc); err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid telegraf config",
+			Err:  err,
+		}
+	}
+	tc.OrgID = orgID
+	tc.Name = r.Name
+	tc.Description = r.Description
+	return tc, nil
+}
+
+func (r *Resource) taskStatus() string {
+	if r.Status == "" {
+		return influxdb.TaskStatusActive
+	}
+	return r.Status
+}
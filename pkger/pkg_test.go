@@ -0,0 +1,160 @@
+package pkger_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkger"
+	_ "github.com/influxdata/influxdb/query/builtin"
+)
+
+const testPkgYAML = `
+apiVersion: 0.1.0
+kind: Package
+meta:
+  pkgName: system
+  pkgVersion: 1.0.0
+  description: system monitoring
+spec:
+  resources:
+    - kind: Label
+      name: system
+      color: "#326BBA"
+    - kind: Bucket
+      name: telegraf
+      retentionPeriod: 168h
+      labels: [system]
+    - kind: Variable
+      name: hosts
+      arguments:
+        type: constant
+        values: [a, b]
+    - kind: Telegraf
+      name: system
+      labels: [system]
+      config: |
+        [agent]
+          interval = "10s"
+        [[inputs.cpu]]
+        [[inputs.mem]]
+    - kind: Dashboard
+      name: System
+      labels: [system]
+      cells:
+        - x: 0
+          "y": 0
+          w: 4
+          h: 4
+          view:
+            name: CPU
+            properties:
+              shape: chronograf-v2
+              type: markdown
+              note: cpu
+    - kind: Task
+      flux: |
+        option task = {name: "downsample", every: 1h}
+        from(bucket: "telegraf") |> range(start: -1h)
+`
+
+func TestParse(t *testing.T) {
+	pkg, err := pkger.Parse(pkger.EncodingYAML, strings.NewReader(testPkgYAML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pkg.Metadata.Name != "system" || pkg.Metadata.Version != "1.0.0" {
+		t.Errorf("unexpected metadata: %+v", pkg.Metadata)
+	}
+	if n := len(pkg.Spec.Resources); n != 6 {
+		t.Fatalf("expected 6 resources, got %d", n)
+	}
+	tasks := pkg.Resources(pkger.KindTask)
+	if len(tasks) != 1 || tasks[0].Name != "downsample" {
+		t.Errorf("expected the task to be named after its task option, got %+v", tasks)
+	}
+	dashboards := pkg.Resources(pkger.KindDashboard)
+	if len(dashboards) != 1 || len(dashboards[0].Cells) != 1 || dashboards[0].Cells[0].View.Name != "CPU" {
+		t.Errorf("unexpected dashboards: %+v", dashboards)
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	pkg, err := pkger.Parse(pkger.EncodingJSON, strings.NewReader(`{
+		"apiVersion": "0.1.0",
+		"kind": "Package",
+		"meta": {"pkgName": "buckets"},
+		"spec": {"resources": [{"kind": "Bucket", "name": "b1"}]}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs := pkg.Resources(pkger.KindBucket); len(rs) != 1 || rs[0].Name != "b1" {
+		t.Errorf("unexpected buckets: %+v", rs)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  string
+		msg  string
+	}{
+		{
+			name: "unsupported version",
+			pkg:  "apiVersion: 9.0.0\nkind: Package\nmeta: {pkgName: p}\nspec: {resources: []}",
+			msg:  `unsupported package apiVersion "9.0.0"`,
+		},
+		{
+			name: "unknown field",
+			pkg:  "apiVersion: 0.1.0\nkind: Package\nmeta: {pkgName: p}\nspec: {resources: [{kind: Bucket, name: b, retention: 1h}]}",
+			msg:  "unable to decode package",
+		},
+		{
+			name: "unknown kind",
+			pkg:  "apiVersion: 0.1.0\nkind: Package\nmeta: {pkgName: p}\nspec: {resources: [{kind: Widget, name: w}]}",
+			msg:  `resource at index 0: invalid resource kind "Widget"`,
+		},
+		{
+			name: "missing name",
+			pkg:  "apiVersion: 0.1.0\nkind: Package\nmeta: {pkgName: p}\nspec: {resources: [{kind: Bucket}]}",
+			msg:  "Bucket name is required",
+		},
+		{
+			name: "duplicate name",
+			pkg:  "apiVersion: 0.1.0\nkind: Package\nmeta: {pkgName: p}\nspec: {resources: [{kind: Bucket, name: b}, {kind: Bucket, name: b}]}",
+			msg:  "package has more than one Bucket named b",
+		},
+		{
+			name: "invalid retention period",
+			pkg:  "apiVersion: 0.1.0\nkind: Package\nmeta: {pkgName: p}\nspec: {resources: [{kind: Bucket, name: b, retentionPeriod: 7d}]}",
+			msg:  `invalid bucket retentionPeriod "7d"`,
+		},
+		{
+			name: "variable without arguments",
+			pkg:  "apiVersion: 0.1.0\nkind: Package\nmeta: {pkgName: p}\nspec: {resources: [{kind: Variable, name: v}]}",
+			msg:  "variable arguments are required",
+		},
+		{
+			name: "task name differs from its option",
+			pkg:  "apiVersion: 0.1.0\nkind: Package\nmeta: {pkgName: p}\nspec: {resources: [{kind: Task, name: t, flux: 'option task = {name: \"other\", every: 1h}'}]}",
+			msg:  `task name "t" differs from the name "other" of its task option`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pkger.Parse(pkger.EncodingYAML, strings.NewReader(tt.pkg))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected code %s, got %s", influxdb.EInvalid, influxdb.ErrorCode(err))
+			}
+			if msg := influxdb.ErrorMessage(err); !strings.Contains(msg, tt.msg) {
+				t.Errorf("expected message to contain %q, got %q", tt.msg, msg)
+			}
+		})
+	}
+}
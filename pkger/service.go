@@ -0,0 +1,724 @@
+package pkger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"go.uber.org/zap"
+)
+
+// Action is what applying a package does to a resource.
+type Action string
+
+// Actions of the changes of a package.
+const (
+	// ActionCreate resources do not exist in the organization yet.
+	ActionCreate Action = "create"
+	// ActionUpdate resources exist in the organization and differ from the package.
+	ActionUpdate Action = "update"
+	// ActionNone resources exist in the organization as in the package.
+	ActionNone Action = "none"
+	// ActionDelete resources are owned by the stack the package is applied
+	// with, but are no longer in the package.
+	ActionDelete Action = "delete"
+)
+
+// Change is the change applying a package makes to one of its resources.
+type Change struct {
+	Kind   Kind   `json:"kind"`
+	Name   string `json:"name"`
+	Action Action `json:"action"`
+	// ID is the ID of the resource, unknown for resources a dry run would create.
+	ID influxdb.ID `json:"id,omitempty"`
+	// Fields are the fields an update changes.
+	Fields []string `json:"fields,omitempty"`
+	// Labels are the labels the resource is newly associated with.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// SVC dry runs and applies packages, and keeps track of the stacks they are
+// applied with.
+type SVC interface {
+	// DryRun returns the changes applying the package to the organization would make.
+	DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) ([]Change, error)
+	// Apply applies the package to the organization and returns the changes it made.
+	Apply(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) ([]Change, error)
+	// Drift compares the resources owned by a stack with the package last applied with it.
+	Drift(ctx context.Context, stackID influxdb.ID) ([]Drift, error)
+	// Uninstall deletes the resources owned by a stack, then the stack.
+	Uninstall(ctx context.Context, stackID influxdb.ID) ([]Change, error)
+}
+
+// ApplyOpt are the options of dry runs and applies of packages.
+type ApplyOpt struct {
+	// StackID is the stack the package is applied with, if any.
+	StackID influxdb.ID
+}
+
+// ApplyOptFn sets an option of dry runs and applies of packages.
+type ApplyOptFn func(*ApplyOpt)
+
+// ApplyWithStackID applies the package with the stack. Only the resources the
+// stack owns are updated, resources of the stack that are no longer in the
+// package are deleted, and the resources the package creates are added to
+// the stack.
+func ApplyWithStackID(id influxdb.ID) ApplyOptFn {
+	return func(o *ApplyOpt) {
+		o.StackID = id
+	}
+}
+
+var _ SVC = (*Service)(nil)
+
+// Service applies packages to organizations. Resources of a package are
+// matched by kind and name with the resources of the organization: missing
+// ones are created, and buckets, labels and variables that differ from the
+// package are updated. Existing dashboards, tasks and telegraf configs are
+// left as they are.
+//
+// Packages applied with a stack are recorded in it, along with the resources
+// the stack owns.
+type Service struct {
+	Logger *zap.Logger
+
+	BucketService         influxdb.BucketService
+	LabelService          influxdb.LabelService
+	VariableService       influxdb.VariableService
+	DashboardService      influxdb.DashboardService
+	TaskService           influxdb.TaskService
+	TelegrafConfigService influxdb.TelegrafConfigStore
+	StackService          influxdb.StackService
+}
+
+// DryRun returns the changes applying the package to the organization would make.
+func (s *Service) DryRun(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) ([]Change, error) {
+	p, err := s.plan(ctx, orgID, pkg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return p.changes(), nil
+}
+
+// Apply applies the package to the organization and returns the changes it made.
+// Either all changes are made, or none: if one fails, the changes made before
+// it are reverted. Resources of a stack that are deleted cannot be restored,
+// so they are deleted last.
+func (s *Service) Apply(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) ([]Change, error) {
+	p, err := s.plan(ctx, orgID, pkg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &applier{Service: s, orgID: orgID, labelIDs: p.labelIDs}
+	for _, step := range p.steps {
+		if err := a.apply(ctx, step); err != nil {
+			a.rollback(ctx)
+			return nil, &influxdb.Error{
+				Msg: fmt.Sprintf("failed to apply %s %s: %s", step.resource.Kind, step.resource.Name, influxdb.ErrorMessage(err)),
+				Err: err,
+			}
+		}
+	}
+
+	if p.stack == nil {
+		return p.changes(), nil
+	}
+
+	// The stack keeps the resources to delete until they are deleted, so
+	// that it still owns those that fail to be.
+	if err := s.updateStack(ctx, p, pkg, p.deletes); err != nil {
+		a.rollback(ctx)
+		return nil, err
+	}
+	if len(p.deletes) == 0 {
+		return p.changes(), nil
+	}
+	for _, step := range p.deletes {
+		if err := s.deleteResource(ctx, step.resource.Kind, step.change.ID); err != nil {
+			return nil, &influxdb.Error{
+				Msg: fmt.Sprintf("failed to delete %s %s: %s", step.resource.Kind, step.resource.Name, influxdb.ErrorMessage(err)),
+				Err: err,
+			}
+		}
+	}
+	if err := s.updateStack(ctx, p, pkg, nil); err != nil {
+		return nil, err
+	}
+	return p.changes(), nil
+}
+
+// updateStack records the package in the stack of the plan, along with the
+// resources of the package and the deleted ones as the resources it owns.
+func (s *Service) updateStack(ctx context.Context, p *plan, pkg *Pkg, deletes []*step) error {
+	b, err := json.Marshal(pkg)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Err:  err,
+		}
+	}
+
+	resources := []influxdb.StackResource{}
+	for _, steps := range [][]*step{p.steps, deletes} {
+		for _, st := range steps {
+			resources = append(resources, influxdb.StackResource{
+				Kind: string(st.change.Kind),
+				Name: st.change.Name,
+				ID:   st.change.ID,
+			})
+		}
+	}
+	_, err = s.StackService.UpdateStack(ctx, p.stack.ID, influxdb.StackUpdate{Package: b, Resources: resources})
+	return err
+}
+
+// plan is what applying a package to an organization does.
+type plan struct {
+	steps []*step
+	// deletes are the resources of the stack that are no longer in the package.
+	deletes []*step
+	// labelIDs are the IDs of the labels of the organization by name.
+	labelIDs map[string]influxdb.ID
+
+	stack *influxdb.Stack
+}
+
+func (p *plan) changes() []Change {
+	changes := make([]Change, 0, len(p.steps)+len(p.deletes))
+	for _, steps := range [][]*step{p.steps, p.deletes} {
+		for _, step := range steps {
+			changes = append(changes, step.change)
+		}
+	}
+	return changes
+}
+
+// step is the change applying a package makes to one of its resources.
+type step struct {
+	resource *Resource
+	change   Change
+
+	bucket   *influxdb.Bucket
+	label    *influxdb.Label
+	variable *influxdb.Variable
+}
+
+func (s *Service) plan(ctx context.Context, orgID influxdb.ID, pkg *Pkg, opts ...ApplyOptFn) (*plan, error) {
+	opt := &ApplyOpt{}
+	for _, fn := range opts {
+		fn(opt)
+	}
+
+	if err := pkg.Validate(); err != nil {
+		return nil, err
+	}
+
+	p := &plan{labelIDs: map[string]influxdb.ID{}}
+	owned := map[stackKey]influxdb.ID{}
+	if opt.StackID.Valid() {
+		stack, err := s.StackService.FindStackByID(ctx, opt.StackID)
+		if err != nil {
+			return nil, err
+		}
+		if stack.OrgID != orgID {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  "stack belongs to another organization",
+			}
+		}
+		p.stack = stack
+		for _, r := range stack.Resources {
+			owned[stackKey{Kind(r.Kind), r.Name}] = r.ID
+		}
+	}
+
+	labels, err := s.LabelService.FindLabels(ctx, influxdb.LabelFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range labels {
+		p.labelIDs[l.Name] = l.ID
+	}
+
+	for _, kind := range kinds {
+		planFn := map[Kind]func(context.Context, influxdb.ID, *Resource) (*step, error){
+			KindLabel:     s.planLabel,
+			KindBucket:    s.planBucket,
+			KindVariable:  s.planVariable,
+			KindTelegraf:  s.planTelegraf,
+			KindDashboard: s.planDashboard,
+			KindTask:      s.planTask,
+		}[kind]
+
+		for _, r := range pkg.Resources(kind) {
+			st, err := planFn(ctx, orgID, r)
+			if err != nil {
+				return nil, err
+			}
+			if p.stack != nil && st.change.Action != ActionCreate {
+				if id, ok := owned[stackKey{r.Kind, r.Name}]; !ok || id != st.change.ID {
+					return nil, &influxdb.Error{
+						Code: influxdb.EConflict,
+						Msg:  fmt.Sprintf("%s %s exists in the organization but does not belong to stack %s", r.Kind, r.Name, p.stack.Name),
+					}
+				}
+			}
+			delete(owned, stackKey{r.Kind, r.Name})
+			if err := s.planLabels(ctx, p, r, st); err != nil {
+				return nil, err
+			}
+			p.steps = append(p.steps, st)
+		}
+	}
+
+	if p.stack != nil {
+		for i := len(kinds) - 1; i >= 0; i-- {
+			for _, r := range p.stack.Resources {
+				id, ok := owned[stackKey{kinds[i], r.Name}]
+				if !ok || Kind(r.Kind) != kinds[i] {
+					continue
+				}
+				st := newStep(&Resource{Kind: kinds[i], Name: r.Name}, id, nil)
+				st.change.Action = ActionDelete
+				p.deletes = append(p.deletes, st)
+			}
+		}
+	}
+	return p, nil
+}
+
+// stackKey identifies a resource of a stack.
+type stackKey struct {
+	kind Kind
+	name string
+}
+
+// planLabels sets the labels the resource is newly associated with.
+func (s *Service) planLabels(ctx context.Context, p *plan, r *Resource, st *step) error {
+	if r.Kind == KindLabel {
+		p.labelIDs[r.Name] = st.change.ID
+		return nil
+	}
+
+	mapped := map[string]bool{}
+	if st.change.Action != ActionCreate {
+		labels, err := s.LabelService.FindResourceLabels(ctx, influxdb.LabelMappingFilter{
+			ResourceID:   st.change.ID,
+			ResourceType: resourceTypes[r.Kind],
+		})
+		if err != nil {
+			return err
+		}
+		for _, l := range labels {
+			mapped[l.Name] = true
+		}
+	}
+
+	for _, name := range r.Labels {
+		if _, ok := p.labelIDs[name]; !ok {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("%s %s has label %s that is neither in the package nor in the organization", r.Kind, r.Name, name),
+			}
+		}
+		if !mapped[name] {
+			st.change.Labels = append(st.change.Labels, name)
+		}
+	}
+	return nil
+}
+
+var resourceTypes = map[Kind]influxdb.ResourceType{
+	KindBucket:    influxdb.BucketsResourceType,
+	KindVariable:  influxdb.VariablesResourceType,
+	KindTelegraf:  influxdb.TelegrafsResourceType,
+	KindDashboard: influxdb.DashboardsResourceType,
+	KindTask:      influxdb.TasksResourceType,
+}
+
+func newStep(r *Resource, id influxdb.ID, fields []string) *step {
+	action := ActionNone
+	switch {
+	case !id.Valid():
+		action = ActionCreate
+	case len(fields) > 0:
+		action = ActionUpdate
+	}
+	return &step{
+		resource: r,
+		change: Change{
+			Kind:   r.Kind,
+			Name:   r.Name,
+			Action: action,
+			ID:     id,
+			Fields: fields,
+		},
+	}
+}
+
+func (s *Service) planLabel(ctx context.Context, orgID influxdb.ID, r *Resource) (*step, error) {
+	labels, err := s.LabelService.FindLabels(ctx, influxdb.LabelFilter{Name: r.Name, OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return newStep(r, 0, nil), nil
+	}
+
+	l := labels[0]
+	st := newStep(r, l.ID, labelFields(r, l))
+	st.label = l
+	return st, nil
+}
+
+// labelFields returns the fields of the label that differ from the resource.
+// Properties the resource leaves out are not compared, as they are kept by updates.
+func labelFields(r *Resource, l *influxdb.Label) []string {
+	fields := []string{}
+	if r.Color != "" && r.Color != l.Properties["color"] {
+		fields = append(fields, "color")
+	}
+	if r.Description != "" && r.Description != l.Properties["description"] {
+		fields = append(fields, "description")
+	}
+	return fields
+}
+
+// bucketFields returns the fields of the bucket that differ from the resource.
+func bucketFields(r *Resource, b *influxdb.Bucket) []string {
+	rp, _ := r.retentionPeriod()
+	fields := []string{}
+	if r.Description != b.Description {
+		fields = append(fields, "description")
+	}
+	if rp != b.RetentionPeriod {
+		fields = append(fields, "retentionPeriod")
+	}
+	return fields
+}
+
+// variableFields returns the fields of the variable that differ from the resource.
+// An empty description is not compared, as updates keep the description.
+func variableFields(r *Resource, v *influxdb.Variable) []string {
+	want := r.variable(v.OrganizationID)
+	fields := []string{}
+	if want.Description != "" && want.Description != v.Description {
+		fields = append(fields, "description")
+	}
+	if !reflect.DeepEqual(want.Arguments, v.Arguments) {
+		fields = append(fields, "arguments")
+	}
+	if len(want.Selected) != len(v.Selected) || (len(want.Selected) > 0 && !reflect.DeepEqual(want.Selected, v.Selected)) {
+		fields = append(fields, "selected")
+	}
+	return fields
+}
+
+func (s *Service) planBucket(ctx context.Context, orgID influxdb.ID, r *Resource) (*step, error) {
+	b, err := s.BucketService.FindBucket(ctx, influxdb.BucketFilter{Name: &r.Name, OrganizationID: &orgID})
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return newStep(r, 0, nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	st := newStep(r, b.ID, bucketFields(r, b))
+	st.bucket = b
+	return st, nil
+}
+
+func (s *Service) planVariable(ctx context.Context, orgID influxdb.ID, r *Resource) (*step, error) {
+	vs, err := s.VariableService.FindVariables(ctx, influxdb.VariableFilter{OrganizationID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range vs {
+		if v.Name != r.Name {
+			continue
+		}
+
+		st := newStep(r, v.ID, variableFields(r, v))
+		st.variable = v
+		return st, nil
+	}
+	return newStep(r, 0, nil), nil
+}
+
+func (s *Service) planTelegraf(ctx context.Context, orgID influxdb.ID, r *Resource) (*step, error) {
+	tcs, _, err := s.TelegrafConfigService.FindTelegrafConfigs(ctx, influxdb.TelegrafConfigFilter{OrgID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, tc := range tcs {
+		if tc.Name == r.Name {
+			return newStep(r, tc.ID, nil), nil
+		}
+	}
+	return newStep(r, 0, nil), nil
+}
+
+func (s *Service) planDashboard(ctx context.Context, orgID influxdb.ID, r *Resource) (*step, error) {
+	ds, _, err := s.DashboardService.FindDashboards(ctx, influxdb.DashboardFilter{OrganizationID: &orgID}, influxdb.FindOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range ds {
+		if d.Name == r.Name {
+			return newStep(r, d.ID, nil), nil
+		}
+	}
+	return newStep(r, 0, nil), nil
+}
+
+func (s *Service) planTask(ctx context.Context, orgID influxdb.ID, r *Resource) (*step, error) {
+	filter := influxdb.TaskFilter{OrganizationID: &orgID, Limit: influxdb.TaskMaxPageSize}
+	for {
+		ts, _, err := s.TaskService.FindTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range ts {
+			if t.Name == r.Name {
+				return newStep(r, t.ID, nil), nil
+			}
+		}
+		if len(ts) < filter.Limit {
+			return newStep(r, 0, nil), nil
+		}
+		after := ts[len(ts)-1].ID
+		filter.After = &after
+	}
+}
+
+// applier applies the steps of a plan, keeping track of how to revert them.
+type applier struct {
+	*Service
+	orgID    influxdb.ID
+	labelIDs map[string]influxdb.ID
+	undos    []func(context.Context) error
+}
+
+func (a *applier) undo(fn func(context.Context) error) {
+	a.undos = append(a.undos, fn)
+}
+
+// rollback reverts the steps applied so far, the last one first.
+func (a *applier) rollback(ctx context.Context) {
+	for i := len(a.undos) - 1; i >= 0; i-- {
+		if err := a.undos[i](ctx); err != nil {
+			a.Logger.Error("failed to revert package change", zap.Error(err))
+		}
+	}
+	a.undos = nil
+}
+
+func (a *applier) apply(ctx context.Context, st *step) error {
+	if st.change.Action != ActionNone {
+		var err error
+		switch st.resource.Kind {
+		case KindLabel:
+			err = a.applyLabel(ctx, st)
+		case KindBucket:
+			err = a.applyBucket(ctx, st)
+		case KindVariable:
+			err = a.applyVariable(ctx, st)
+		case KindTelegraf:
+			err = a.applyTelegraf(ctx, st)
+		case KindDashboard:
+			err = a.applyDashboard(ctx, st)
+		case KindTask:
+			err = a.applyTask(ctx, st)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if st.resource.Kind == KindLabel {
+		a.labelIDs[st.resource.Name] = st.change.ID
+		return nil
+	}
+	for _, name := range st.change.Labels {
+		m := &influxdb.LabelMapping{
+			LabelID:      a.labelIDs[name],
+			ResourceID:   st.change.ID,
+			ResourceType: resourceTypes[st.resource.Kind],
+		}
+		if err := a.LabelService.CreateLabelMapping(ctx, m); err != nil {
+			return err
+		}
+		a.undo(func(ctx context.Context) error {
+			return a.LabelService.DeleteLabelMapping(ctx, m)
+		})
+	}
+	return nil
+}
+
+func (a *applier) applyLabel(ctx context.Context, st *step) error {
+	r := st.resource
+	if st.change.Action == ActionCreate {
+		l := &influxdb.Label{
+			OrgID:      a.orgID,
+			Name:       r.Name,
+			Properties: r.labelProperties(),
+		}
+		if err := a.LabelService.CreateLabel(ctx, l); err != nil {
+			return err
+		}
+		st.change.ID = l.ID
+		a.undo(func(ctx context.Context) error {
+			return a.LabelService.DeleteLabel(ctx, l.ID)
+		})
+		return nil
+	}
+
+	props := r.labelProperties()
+	if _, err := a.LabelService.UpdateLabel(ctx, st.label.ID, influxdb.LabelUpdate{Properties: props}); err != nil {
+		return err
+	}
+	// Properties left out of an update are kept, and empty ones are removed.
+	previous := map[string]string{}
+	for k := range props {
+		previous[k] = st.label.Properties[k]
+	}
+	a.undo(func(ctx context.Context) error {
+		_, err := a.LabelService.UpdateLabel(ctx, st.label.ID, influxdb.LabelUpdate{Properties: previous})
+		return err
+	})
+	return nil
+}
+
+func (a *applier) applyBucket(ctx context.Context, st *step) error {
+	r := st.resource
+	rp, _ := r.retentionPeriod()
+	if st.change.Action == ActionCreate {
+		b := &influxdb.Bucket{
+			OrgID:           a.orgID,
+			Name:            r.Name,
+			Description:     r.Description,
+			RetentionPeriod: rp,
+		}
+		if err := a.BucketService.CreateBucket(ctx, b); err != nil {
+			return err
+		}
+		st.change.ID = b.ID
+		a.undo(func(ctx context.Context) error {
+			return a.BucketService.DeleteBucket(ctx, b.ID)
+		})
+		return nil
+	}
+
+	upd := influxdb.BucketUpdate{Description: &r.Description, RetentionPeriod: &rp}
+	if _, err := a.BucketService.UpdateBucket(ctx, st.bucket.ID, upd); err != nil {
+		return err
+	}
+	a.undo(func(ctx context.Context) error {
+		_, err := a.BucketService.UpdateBucket(ctx, st.bucket.ID, influxdb.BucketUpdate{
+			Description:     &st.bucket.Description,
+			RetentionPeriod: &st.bucket.RetentionPeriod,
+		})
+		return err
+	})
+	return nil
+}
+
+func (a *applier) applyVariable(ctx context.Context, st *step) error {
+	v := st.resource.variable(a.orgID)
+	if st.change.Action == ActionCreate {
+		if err := a.VariableService.CreateVariable(ctx, v); err != nil {
+			return err
+		}
+		st.change.ID = v.ID
+		a.undo(func(ctx context.Context) error {
+			return a.VariableService.DeleteVariable(ctx, v.ID)
+		})
+		return nil
+	}
+
+	upd := &influxdb.VariableUpdate{
+		Name:        v.Name,
+		Description: v.Description,
+		Selected:    v.Selected,
+		Arguments:   v.Arguments,
+	}
+	if _, err := a.VariableService.UpdateVariable(ctx, st.variable.ID, upd); err != nil {
+		return err
+	}
+	a.undo(func(ctx context.Context) error {
+		_, err := a.VariableService.UpdateVariable(ctx, st.variable.ID, &influxdb.VariableUpdate{
+			Name:        st.variable.Name,
+			Description: st.variable.Description,
+			Selected:    st.variable.Selected,
+			Arguments:   st.variable.Arguments,
+		})
+		return err
+	})
+	return nil
+}
+
+func (a *applier) applyTelegraf(ctx context.Context, st *step) error {
+	auth, err := icontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	tc, err := st.resource.telegrafConfig(a.orgID)
+	if err != nil {
+		return err
+	}
+	if err := a.TelegrafConfigService.CreateTelegrafConfig(ctx, tc, auth.GetUserID()); err != nil {
+		return err
+	}
+	st.change.ID = tc.ID
+	a.undo(func(ctx context.Context) error {
+		return a.TelegrafConfigService.DeleteTelegrafConfig(ctx, tc.ID)
+	})
+	return nil
+}
+
+func (a *applier) applyDashboard(ctx context.Context, st *step) error {
+	r := st.resource
+	d := &influxdb.Dashboard{
+		OrganizationID: a.orgID,
+		Name:           r.Name,
+		Description:    r.Description,
+	}
+	if err := a.DashboardService.CreateDashboard(ctx, d); err != nil {
+		return err
+	}
+	st.change.ID = d.ID
+	a.undo(func(ctx context.Context) error {
+		return a.DashboardService.DeleteDashboard(ctx, d.ID)
+	})
+
+	for i := range r.Cells {
+		view := r.Cells[i].View
+		c := &influxdb.Cell{CellProperty: r.Cells[i].CellProperty}
+		if err := a.DashboardService.AddDashboardCell(ctx, d.ID, c, influxdb.AddDashboardCellOptions{View: &view}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyTask(ctx context.Context, st *step) error {
+	r := st.resource
+	t, err := a.TaskService.CreateTask(ctx, influxdb.TaskCreate{
+		OrganizationID: a.orgID,
+		Flux:           r.Flux,
+		Description:    r.Description,
+		Status:         r.taskStatus(),
+	})
+	if err != nil {
+		return err
+	}
+	st.change.ID = t.ID
+	a.undo(func(ctx context.Context) error {
+		return a.TaskService.DeleteTask(ctx, t.ID)
+	})
+	return nil
+}
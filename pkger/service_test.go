@@ -0,0 +1,239 @@
+package pkger_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/inmem"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/pkger"
+	"go.uber.org/zap"
+)
+
+func newTestService(t *testing.T) (context.Context, *kv.Service, *pkger.Service, influxdb.ID) {
+	t.Helper()
+
+	ctx := context.Background()
+	s := kv.NewService(inmem.NewKVStore())
+	if err := s.Initialize(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	u := &influxdb.User{Name: "admin"}
+	if err := s.CreateUser(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	o := &influxdb.Organization{Name: "org"}
+	if err := s.CreateOrganization(ctx, o); err != nil {
+		t.Fatal(err)
+	}
+	a := &influxdb.Authorization{OrgID: o.ID, UserID: u.ID, Permissions: influxdb.OperPermissions()}
+	if err := s.CreateAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := &pkger.Service{
+		Logger:                zap.NewNop(),
+		BucketService:         s,
+		LabelService:          s,
+		VariableService:       s,
+		DashboardService:      s,
+		TaskService:           s,
+		TelegrafConfigService: s,
+		StackService:          s,
+	}
+	return icontext.SetAuthorizer(ctx, a), s, svc, o.ID
+}
+
+func parseTestPkg(t *testing.T) *pkger.Pkg {
+	t.Helper()
+	pkg, err := pkger.Parse(pkger.EncodingYAML, strings.NewReader(testPkgYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkg
+}
+
+func actions(changes []pkger.Change) map[string]pkger.Action {
+	m := map[string]pkger.Action{}
+	for _, c := range changes {
+		m[string(c.Kind)+"/"+c.Name] = c.Action
+	}
+	return m
+}
+
+func TestService_DryRun(t *testing.T) {
+	ctx, s, svc, orgID := newTestService(t)
+
+	changes, err := svc.DryRun(ctx, orgID, parseTestPkg(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 6 {
+		t.Fatalf("expected 6 changes, got %+v", changes)
+	}
+	for _, c := range changes {
+		if c.Action != pkger.ActionCreate || c.ID.Valid() {
+			t.Errorf("expected %s %s to be created without ID, got %+v", c.Kind, c.Name, c)
+		}
+	}
+	if changes[0].Kind != pkger.KindLabel || changes[len(changes)-1].Kind != pkger.KindTask {
+		t.Errorf("expected labels first and tasks last, got %+v", changes)
+	}
+
+	if _, err := s.FindBucket(ctx, influxdb.BucketFilter{Name: strPtr("telegraf"), OrganizationID: &orgID}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a dry run not to create the bucket, got %v", err)
+	}
+}
+
+func TestService_Apply(t *testing.T) {
+	ctx, s, svc, orgID := newTestService(t)
+
+	changes, err := svc.Apply(ctx, orgID, parseTestPkg(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, c := range changes {
+		if c.Action != pkger.ActionCreate || !c.ID.Valid() {
+			t.Errorf("expected %s %s to be created, got %+v", c.Kind, c.Name, c)
+		}
+	}
+
+	b, err := s.FindBucket(ctx, influxdb.BucketFilter{Name: strPtr("telegraf"), OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.RetentionPeriod != 168*time.Hour {
+		t.Errorf("expected retention period of 168h, got %s", b.RetentionPeriod)
+	}
+	labels, err := s.FindResourceLabels(ctx, influxdb.LabelMappingFilter{ResourceID: b.ID, ResourceType: influxdb.BucketsResourceType})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 1 || labels[0].Name != "system" || labels[0].Properties["color"] != "#326BBA" {
+		t.Errorf("expected bucket to have the system label, got %+v", labels)
+	}
+
+	ds, _, err := s.FindDashboards(ctx, influxdb.DashboardFilter{OrganizationID: &orgID}, influxdb.FindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 || len(ds[0].Cells) != 1 {
+		t.Fatalf("expected a dashboard with a cell, got %+v", ds)
+	}
+	v, err := s.GetDashboardCellView(ctx, ds[0].ID, ds[0].Cells[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "CPU" {
+		t.Errorf("expected the view of the cell to be CPU, got %q", v.Name)
+	}
+
+	// Applying the package again changes nothing.
+	changes, err = svc.Apply(ctx, orgID, parseTestPkg(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, c := range changes {
+		if c.Action != pkger.ActionNone || len(c.Labels) != 0 {
+			t.Errorf("expected %s %s to be unchanged, got %+v", c.Kind, c.Name, c)
+		}
+	}
+}
+
+func TestService_Apply_update(t *testing.T) {
+	ctx, s, svc, orgID := newTestService(t)
+	if _, err := svc.Apply(ctx, orgID, parseTestPkg(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := parseTestPkg(t)
+	pkg.Resources(pkger.KindBucket)[0].RetentionPeriod = "24h"
+	pkg.Resources(pkger.KindLabel)[0].Color = "#FFFFFF"
+	pkg.Resources(pkger.KindVariable)[0].Labels = []string{"system"}
+
+	changes, err := svc.DryRun(ctx, orgID, pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := actions(changes)
+	if got["Bucket/telegraf"] != pkger.ActionUpdate || got["Label/system"] != pkger.ActionUpdate || got["Dashboard/System"] != pkger.ActionNone {
+		t.Errorf("unexpected actions: %v", got)
+	}
+	for _, c := range changes {
+		if c.Kind == pkger.KindBucket && (len(c.Fields) != 1 || c.Fields[0] != "retentionPeriod") {
+			t.Errorf("expected the bucket retention period to change, got %v", c.Fields)
+		}
+		if c.Kind == pkger.KindVariable && (len(c.Labels) != 1 || c.Labels[0] != "system") {
+			t.Errorf("expected the variable to be associated with the system label, got %v", c.Labels)
+		}
+	}
+
+	if _, err := svc.Apply(ctx, orgID, pkg); err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.FindBucket(ctx, influxdb.BucketFilter{Name: strPtr("telegraf"), OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.RetentionPeriod != 24*time.Hour {
+		t.Errorf("expected retention period of 24h, got %s", b.RetentionPeriod)
+	}
+}
+
+func TestService_Apply_unknownLabel(t *testing.T) {
+	ctx, _, svc, orgID := newTestService(t)
+
+	pkg := parseTestPkg(t)
+	pkg.Resources(pkger.KindBucket)[0].Labels = []string{"missing"}
+	_, err := svc.Apply(ctx, orgID, pkg)
+	if influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Fatalf("expected an invalid error, got %v", err)
+	}
+}
+
+type failingTaskService struct {
+	influxdb.TaskService
+}
+
+func (failingTaskService) CreateTask(ctx context.Context, tc influxdb.TaskCreate) (*influxdb.Task, error) {
+	return nil, errors.New("task service unavailable")
+}
+
+func TestService_Apply_rollback(t *testing.T) {
+	ctx, s, svc, orgID := newTestService(t)
+
+	// Apply the package once without the task, then change the bucket.
+	pkg := parseTestPkg(t)
+	pkg.Spec.Resources = pkg.Spec.Resources[:5]
+	if _, err := svc.Apply(ctx, orgID, pkg); err != nil {
+		t.Fatal(err)
+	}
+	pkg = parseTestPkg(t)
+	pkg.Resources(pkger.KindBucket)[0].RetentionPeriod = "24h"
+	pkg.Spec.Resources = append(pkg.Spec.Resources, pkger.Resource{Kind: pkger.KindBucket, Name: "other"})
+
+	svc.TaskService = failingTaskService{s}
+	_, err := svc.Apply(ctx, orgID, pkg)
+	if err == nil || !strings.Contains(influxdb.ErrorMessage(err), "failed to apply Task downsample") {
+		t.Fatalf("expected the task to fail, got %v", err)
+	}
+
+	b, err := s.FindBucket(ctx, influxdb.BucketFilter{Name: strPtr("telegraf"), OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.RetentionPeriod != 168*time.Hour {
+		t.Errorf("expected the retention period update to be reverted, got %s", b.RetentionPeriod)
+	}
+	if _, err := s.FindBucket(ctx, influxdb.BucketFilter{Name: strPtr("other"), OrganizationID: &orgID}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the created bucket to be deleted, got %v", err)
+	}
+}
+
+func strPtr(s string) *string { return &s }
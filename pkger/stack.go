@@ -0,0 +1,222 @@
+package pkger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// DriftStatus is how a resource owned by a stack compares with the package
+// last applied with the stack.
+type DriftStatus string
+
+// Drift statuses.
+const (
+	// DriftInSync resources are as in the package.
+	DriftInSync DriftStatus = "in-sync"
+	// DriftModified resources were changed since the package was applied.
+	DriftModified DriftStatus = "modified"
+	// DriftMissing resources were deleted since the package was applied.
+	DriftMissing DriftStatus = "missing"
+)
+
+// Drift is how a resource owned by a stack compares with the package last
+// applied with the stack.
+type Drift struct {
+	Kind   Kind        `json:"kind"`
+	Name   string      `json:"name"`
+	ID     influxdb.ID `json:"id"`
+	Status DriftStatus `json:"status"`
+	// Fields are the fields of modified resources that differ from the package.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Drift compares the resources owned by a stack with the package last applied
+// with it. Resources are found by ID, so renamed resources are modified rather
+// than missing. The views of the cells of dashboards and the plugins of
+// telegraf configs are not compared.
+func (s *Service) Drift(ctx context.Context, stackID influxdb.ID) ([]Drift, error) {
+	stack, err := s.StackService.FindStackByID(ctx, stackID)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &Pkg{}
+	if len(stack.Package) > 0 {
+		if pkg, err = Parse(EncodingJSON, bytes.NewReader(stack.Package)); err != nil {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  "package of the stack is invalid",
+				Err:  err,
+			}
+		}
+	}
+	resources := map[stackKey]*Resource{}
+	for i := range pkg.Spec.Resources {
+		r := &pkg.Spec.Resources[i]
+		resources[stackKey{r.Kind, r.Name}] = r
+	}
+
+	drifts := make([]Drift, 0, len(stack.Resources))
+	for _, sr := range stack.Resources {
+		r, ok := resources[stackKey{Kind(sr.Kind), sr.Name}]
+		if !ok {
+			// Resources left out of the package are deleted when it is applied.
+			continue
+		}
+
+		fields, err := s.driftFields(ctx, r, sr.ID)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ENotFound {
+			return nil, err
+		}
+
+		d := Drift{Kind: r.Kind, Name: r.Name, ID: sr.ID, Status: DriftInSync}
+		switch {
+		case err != nil:
+			d.Status = DriftMissing
+		case len(fields) > 0:
+			d.Status = DriftModified
+			d.Fields = fields
+		}
+		drifts = append(drifts, d)
+	}
+	return drifts, nil
+}
+
+// driftFields returns the fields of the resource with the id that differ from r.
+func (s *Service) driftFields(ctx context.Context, r *Resource, id influxdb.ID) ([]string, error) {
+	var (
+		name   string
+		fields []string
+	)
+	switch r.Kind {
+	case KindLabel:
+		l, err := s.LabelService.FindLabelByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		name, fields = l.Name, labelFields(r, l)
+	case KindBucket:
+		b, err := s.BucketService.FindBucketByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		name, fields = b.Name, bucketFields(r, b)
+	case KindVariable:
+		v, err := s.VariableService.FindVariableByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		name, fields = v.Name, variableFields(r, v)
+	case KindTelegraf:
+		tc, err := s.TelegrafConfigService.FindTelegrafConfigByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		name, fields = tc.Name, []string{}
+		if tc.Description != r.Description {
+			fields = append(fields, "description")
+		}
+	case KindDashboard:
+		d, err := s.DashboardService.FindDashboardByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		name, fields = d.Name, []string{}
+		if d.Description != r.Description {
+			fields = append(fields, "description")
+		}
+		if len(d.Cells) != len(r.Cells) {
+			fields = append(fields, "cells")
+		}
+	case KindTask:
+		t, err := s.TaskService.FindTaskByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		name, fields = t.Name, []string{}
+		if t.Description != r.Description {
+			fields = append(fields, "description")
+		}
+		if t.Flux != r.Flux {
+			fields = append(fields, "flux")
+		}
+		if t.Status != r.taskStatus() {
+			fields = append(fields, "status")
+		}
+	}
+
+	if name != r.Name {
+		fields = append([]string{"name"}, fields...)
+	}
+	return fields, nil
+}
+
+// Uninstall deletes the resources owned by a stack, then the stack. Resources
+// that were deleted already are skipped. If a resource fails to be deleted,
+// the stack keeps owning those that were not deleted yet.
+func (s *Service) Uninstall(ctx context.Context, stackID influxdb.ID) ([]Change, error) {
+	stack, err := s.StackService.FindStackByID(ctx, stackID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	deleted := map[influxdb.ID]bool{}
+	for i := len(kinds) - 1; i >= 0; i-- {
+		for _, sr := range stack.Resources {
+			if Kind(sr.Kind) != kinds[i] {
+				continue
+			}
+			if err := s.deleteResource(ctx, kinds[i], sr.ID); err != nil {
+				remaining := []influxdb.StackResource{}
+				for _, r := range stack.Resources {
+					if !deleted[r.ID] {
+						remaining = append(remaining, r)
+					}
+				}
+				if _, uerr := s.StackService.UpdateStack(ctx, stackID, influxdb.StackUpdate{Resources: remaining}); uerr != nil {
+					s.Logger.Error("failed to update the resources of stack", zap.Stringer("stackID", stackID), zap.Error(uerr))
+				}
+				return nil, &influxdb.Error{
+					Msg: fmt.Sprintf("failed to delete %s %s: %s", sr.Kind, sr.Name, influxdb.ErrorMessage(err)),
+					Err: err,
+				}
+			}
+			deleted[sr.ID] = true
+			changes = append(changes, Change{Kind: kinds[i], Name: sr.Name, Action: ActionDelete, ID: sr.ID})
+		}
+	}
+
+	if err := s.StackService.DeleteStack(ctx, stackID); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// deleteResource deletes the resource of the kind with the id, unless it was
+// deleted already.
+func (s *Service) deleteResource(ctx context.Context, kind Kind, id influxdb.ID) error {
+	var err error
+	switch kind {
+	case KindLabel:
+		err = s.LabelService.DeleteLabel(ctx, id)
+	case KindBucket:
+		err = s.BucketService.DeleteBucket(ctx, id)
+	case KindVariable:
+		err = s.VariableService.DeleteVariable(ctx, id)
+	case KindTelegraf:
+		err = s.TelegrafConfigService.DeleteTelegrafConfig(ctx, id)
+	case KindDashboard:
+		err = s.DashboardService.DeleteDashboard(ctx, id)
+	case KindTask:
+		err = s.TaskService.DeleteTask(ctx, id)
+	}
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package pkger_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+	"github.com/influxdata/influxdb/pkger"
+)
+
+func newTestStack(ctx context.Context, t *testing.T, s *kv.Service, orgID influxdb.ID) *influxdb.Stack {
+	t.Helper()
+	st := &influxdb.Stack{OrgID: orgID, Name: "system"}
+	if err := s.CreateStack(ctx, st); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestService_Apply_stack(t *testing.T) {
+	ctx, s, svc, orgID := newTestService(t)
+	st := newTestStack(ctx, t, s, orgID)
+
+	if _, err := svc.Apply(ctx, orgID, parseTestPkg(t), pkger.ApplyWithStackID(st.ID)); err != nil {
+		t.Fatal(err)
+	}
+	st, err := s.FindStackByID(ctx, st.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Resources) != 6 || len(st.Package) == 0 {
+		t.Fatalf("expected the stack to own the 6 resources of the package, got %+v", st.Resources)
+	}
+
+	// Resources left out of the package are deleted.
+	pkg := parseTestPkg(t)
+	pkg.Spec.Resources = pkg.Spec.Resources[:2]
+	changes, err := svc.DryRun(ctx, orgID, pkg, pkger.ApplyWithStackID(st.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := actions(changes)
+	if got["Bucket/telegraf"] != pkger.ActionNone || got["Task/downsample"] != pkger.ActionDelete || got["Dashboard/System"] != pkger.ActionDelete {
+		t.Errorf("unexpected actions: %v", got)
+	}
+
+	if _, err := svc.Apply(ctx, orgID, pkg, pkger.ApplyWithStackID(st.ID)); err != nil {
+		t.Fatal(err)
+	}
+	vs, err := s.FindVariables(ctx, influxdb.VariableFilter{OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) != 0 {
+		t.Errorf("expected the variable to be deleted, got %+v", vs)
+	}
+	st, err = s.FindStackByID(ctx, st.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.Resources) != 2 {
+		t.Errorf("expected the stack to own 2 resources, got %+v", st.Resources)
+	}
+}
+
+func TestService_Apply_stackConflict(t *testing.T) {
+	ctx, s, svc, orgID := newTestService(t)
+	st := newTestStack(ctx, t, s, orgID)
+
+	if err := s.CreateBucket(ctx, &influxdb.Bucket{OrgID: orgID, Name: "telegraf"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := svc.Apply(ctx, orgID, parseTestPkg(t), pkger.ApplyWithStackID(st.ID))
+	if influxdb.ErrorCode(err) != influxdb.EConflict {
+		t.Fatalf("expected a conflict with the bucket the stack does not own, got %v", err)
+	}
+}
+
+func TestService_Drift(t *testing.T) {
+	ctx, s, svc, orgID := newTestService(t)
+	st := newTestStack(ctx, t, s, orgID)
+	if _, err := svc.Apply(ctx, orgID, parseTestPkg(t), pkger.ApplyWithStackID(st.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := s.FindBucket(ctx, influxdb.BucketFilter{Name: strPtr("telegraf"), OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpdateBucket(ctx, b.ID, influxdb.BucketUpdate{Name: strPtr("renamed")}); err != nil {
+		t.Fatal(err)
+	}
+	l, err := s.FindLabels(ctx, influxdb.LabelFilter{Name: "system", OrgID: &orgID})
+	if err != nil || len(l) != 1 {
+		t.Fatalf("expected the system label, got %v %v", l, err)
+	}
+	if err := s.DeleteLabel(ctx, l[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	drifts, err := svc.Drift(ctx, st.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drifts) != 6 {
+		t.Fatalf("expected the drift of 6 resources, got %+v", drifts)
+	}
+	for _, d := range drifts {
+		switch d.Kind {
+		case pkger.KindBucket:
+			if d.Status != pkger.DriftModified || len(d.Fields) != 1 || d.Fields[0] != "name" {
+				t.Errorf("expected the bucket name to be modified, got %+v", d)
+			}
+		case pkger.KindLabel:
+			if d.Status != pkger.DriftMissing {
+				t.Errorf("expected the label to be missing, got %+v", d)
+			}
+		default:
+			if d.Status != pkger.DriftInSync {
+				t.Errorf("expected %s %s to be in sync, got %+v", d.Kind, d.Name, d)
+			}
+		}
+	}
+}
+
+func TestService_Uninstall(t *testing.T) {
+	ctx, s, svc, orgID := newTestService(t)
+	st := newTestStack(ctx, t, s, orgID)
+	if _, err := svc.Apply(ctx, orgID, parseTestPkg(t), pkger.ApplyWithStackID(st.ID)); err != nil {
+		t.Fatal(err)
+	}
+	other := &influxdb.Bucket{OrgID: orgID, Name: "other"}
+	if err := s.CreateBucket(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := svc.Uninstall(ctx, st.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 6 || changes[0].Kind != pkger.KindTask || changes[5].Kind != pkger.KindLabel {
+		t.Errorf("expected the 6 resources to be deleted from tasks to labels, got %+v", changes)
+	}
+
+	if _, err := s.FindBucket(ctx, influxdb.BucketFilter{Name: strPtr("telegraf"), OrganizationID: &orgID}); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the bucket of the stack to be deleted, got %v", err)
+	}
+	if _, err := s.FindBucketByID(ctx, other.ID); err != nil {
+		t.Errorf("expected the bucket the stack does not own to be kept, got %v", err)
+	}
+	if _, err := s.FindStackByID(ctx, st.ID); influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected the stack to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+// Package arrow encodes the results of queries as Apache Arrow IPC streams,
+// which clients such as pandas read without parsing the values of the rows.
+package arrow
+
+import (
+	"net/http"
+
+	"github.com/influxdata/flux"
+)
+
+const (
+	// DialectType is the type of the Arrow dialect.
+	DialectType = "arrow"
+	// MediaType is the media type of Arrow IPC streams.
+	MediaType = "application/vnd.apache.arrow.stream"
+)
+
+// AddDialectMappings adds the Arrow dialect mappings.
+func AddDialectMappings(mappings flux.DialectMappings) error {
+	return mappings.Add(DialectType, func() flux.Dialect {
+		return new(Dialect)
+	})
+}
+
+// Dialect describes the results of a query encoded as an Arrow IPC stream.
+type Dialect struct{}
+
+// SetHeaders sets the content type of Arrow IPC streams.
+func (d *Dialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", MediaType)
+}
+
+// Encoder returns the encoder of Arrow IPC streams.
+func (d *Dialect) Encoder() flux.MultiResultEncoder {
+	return new(MultiResultEncoder)
+}
+
+// DialectType returns the type of the Arrow dialect.
+func (d *Dialect) DialectType() flux.DialectType {
+	return DialectType
+}
@@ -0,0 +1,286 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/iocounter"
+)
+
+// Columns every record batch starts with, like the annotated CSV,
+// so the rows of tables can be told apart.
+const (
+	resultColumn = "result"
+	tableColumn  = "table"
+)
+
+// MultiResultEncoder encodes results as a single Arrow IPC stream, with a
+// record batch for every buffer of a table.
+//
+// The schema of the stream is declared by the first table. The columns of
+// later tables must be in the schema with the same type; the columns a table
+// does not have are null. Queries whose tables have different columns should
+// pivot or keep the columns the results are read with.
+//
+// The format has no way to report an error after the schema is written,
+// so a query that fails while its results are written ends the stream
+// without its end marker, which readers report as a truncated stream.
+type MultiResultEncoder struct{}
+
+// Encode writes the results as an Arrow IPC stream to w.
+func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (int64, error) {
+	defer results.Release()
+
+	wc := &iocounter.Writer{Writer: w}
+	enc := &encoder{stream: streamWriter{w: wc}}
+	for results.More() {
+		res := results.Next()
+		var id int64
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			defer func() { id++ }()
+			return enc.encodeTable(res.Name(), id, tbl)
+		}); err != nil {
+			return wc.Count(), err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return wc.Count(), err
+	}
+	if err := enc.close(); err != nil {
+		return wc.Count(), err
+	}
+	return wc.Count(), nil
+}
+
+type encoder struct {
+	stream streamWriter
+	fields []field
+	body   body
+}
+
+// encodeTable writes the record batches of a table, and the schema of the
+// stream first if the table is the first one.
+func (e *encoder) encodeTable(result string, id int64, tbl flux.Table) error {
+	if e.fields == nil {
+		if err := e.writeSchema(tbl.Cols()); err != nil {
+			return err
+		}
+	}
+
+	cols, err := e.columns(tbl.Cols())
+	if err != nil {
+		tbl.Done()
+		return err
+	}
+	return tbl.Do(func(cr flux.ColReader) error {
+		if cr.Len() == 0 {
+			return nil
+		}
+		return e.writeRecordBatch(result, id, cols, cr)
+	})
+}
+
+func (e *encoder) writeSchema(cols []flux.ColMeta) error {
+	e.fields = make([]field, 0, len(cols)+2)
+	e.fields = append(e.fields,
+		field{name: resultColumn, typ: flux.TString},
+		field{name: tableColumn, typ: flux.TInt},
+	)
+	for _, c := range cols {
+		e.fields = append(e.fields, field{name: c.Label, typ: c.Type})
+	}
+	return e.stream.writeMessage(schemaMessage(e.fields), nil)
+}
+
+// columns returns the index of the column of the table for every column of
+// the schema after the result and table columns, or -1 if the table does not
+// have the column.
+func (e *encoder) columns(cols []flux.ColMeta) ([]int, error) {
+	idx := make([]int, len(e.fields)-2)
+	for i := range idx {
+		idx[i] = -1
+	}
+	for j, c := range cols {
+		i := e.fieldIndex(c.Label)
+		if i < 0 || e.fields[i+2].typ != c.Type {
+			return nil, &flux.Error{
+				Code: codes.Invalid,
+				Msg:  fmt.Sprintf("column %q of type %s is not in the schema of the first table; the tables of an Arrow stream must have the same columns", c.Label, c.Type),
+			}
+		}
+		idx[i] = j
+	}
+	return idx, nil
+}
+
+func (e *encoder) fieldIndex(label string) int {
+	for i, f := range e.fields[2:] {
+		if f.name == label {
+			return i
+		}
+	}
+	return -1
+}
+
+func (e *encoder) writeRecordBatch(result string, id int64, cols []int, cr flux.ColReader) error {
+	n := cr.Len()
+	e.body.reset()
+	e.body.appendConstString(result, n)
+	e.body.appendConstInt(id, n)
+	for i, j := range cols {
+		e.body.appendColumn(cr, j, e.fields[i+2].typ, n)
+	}
+	return e.stream.writeMessage(recordBatchMessage(n, &e.body), e.body.buf)
+}
+
+// close writes the end of the stream, and the schema of the result and
+// table columns if there were no tables.
+func (e *encoder) close() error {
+	if e.fields == nil {
+		if err := e.writeSchema(nil); err != nil {
+			return err
+		}
+	}
+	return e.stream.writeEnd()
+}
+
+// nullable is a column that may have nulls.
+type nullable interface {
+	NullN() int
+	IsValid(i int) bool
+}
+
+// appendValidity appends the validity bitmap of a column, which is left
+// out if the column has no nulls.
+func (b *body) appendValidity(vs nullable, n int) {
+	if vs.NullN() == 0 {
+		b.nodes = append(b.nodes, fieldNode{length: n})
+		b.add(nil)
+		return
+	}
+	bits := make([]byte, bitmapLen(n))
+	for i := 0; i < n; i++ {
+		if vs.IsValid(i) {
+			setBit(bits, i)
+		}
+	}
+	b.nodes = append(b.nodes, fieldNode{length: n, nullCount: vs.NullN()})
+	b.add(bits)
+}
+
+// appendColumn appends the buffers of column j of the reader, or of a
+// column of nulls if j is negative.
+func (b *body) appendColumn(cr flux.ColReader, j int, typ flux.ColType, n int) {
+	if j < 0 {
+		b.appendNulls(typ, n)
+		return
+	}
+
+	switch typ {
+	case flux.TBool:
+		vs := cr.Bools(j)
+		b.appendValidity(vs, n)
+		bits := make([]byte, bitmapLen(n))
+		for i := 0; i < n; i++ {
+			if vs.IsValid(i) && vs.Value(i) {
+				setBit(bits, i)
+			}
+		}
+		b.add(bits)
+	case flux.TInt:
+		vs := cr.Ints(j)
+		b.appendValidity(vs, n)
+		b.appendInt64s(vs.Int64Values())
+	case flux.TTime:
+		vs := cr.Times(j)
+		b.appendValidity(vs, n)
+		b.appendInt64s(vs.Int64Values())
+	case flux.TUInt:
+		vs := cr.UInts(j)
+		b.appendValidity(vs, n)
+		p := make([]byte, 8*n)
+		for i, v := range vs.Uint64Values() {
+			binary.LittleEndian.PutUint64(p[8*i:], v)
+		}
+		b.add(p)
+	case flux.TFloat:
+		vs := cr.Floats(j)
+		b.appendValidity(vs, n)
+		p := make([]byte, 8*n)
+		for i, v := range vs.Float64Values() {
+			binary.LittleEndian.PutUint64(p[8*i:], math.Float64bits(v))
+		}
+		b.add(p)
+	case flux.TString:
+		vs := cr.Strings(j)
+		b.appendValidity(vs, n)
+		offsets := make([]byte, 4*(n+1))
+		var data []byte
+		for i := 0; i < n; i++ {
+			if vs.IsValid(i) {
+				data = append(data, vs.Value(i)...)
+			}
+			binary.LittleEndian.PutUint32(offsets[4*(i+1):], uint32(len(data)))
+		}
+		b.add(offsets)
+		b.add(data)
+	}
+}
+
+func (b *body) appendInt64s(vs []int64) {
+	p := make([]byte, 8*len(vs))
+	for i, v := range vs {
+		binary.LittleEndian.PutUint64(p[8*i:], uint64(v))
+	}
+	b.add(p)
+}
+
+// appendNulls appends the buffers of a column of n nulls.
+func (b *body) appendNulls(typ flux.ColType, n int) {
+	b.nodes = append(b.nodes, fieldNode{length: n, nullCount: n})
+	b.add(make([]byte, bitmapLen(n)))
+	switch typ {
+	case flux.TBool:
+		b.add(make([]byte, bitmapLen(n)))
+	case flux.TString:
+		b.add(make([]byte, 4*(n+1)))
+		b.add(nil)
+	default:
+		b.add(make([]byte, 8*n))
+	}
+}
+
+func (b *body) appendConstString(s string, n int) {
+	b.nodes = append(b.nodes, fieldNode{length: n})
+	b.add(nil)
+	offsets := make([]byte, 4*(n+1))
+	data := make([]byte, 0, len(s)*n)
+	for i := 0; i < n; i++ {
+		data = append(data, s...)
+		binary.LittleEndian.PutUint32(offsets[4*(i+1):], uint32(len(data)))
+	}
+	b.add(offsets)
+	b.add(data)
+}
+
+func (b *body) appendConstInt(v int64, n int) {
+	b.nodes = append(b.nodes, fieldNode{length: n})
+	b.add(nil)
+	p := make([]byte, 8*n)
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint64(p[8*i:], uint64(v))
+	}
+	b.add(p)
+}
+
+func bitmapLen(n int) int {
+	return (n + 7) / 8
+}
+
+func setBit(bits []byte, i int) {
+	bits[i/8] |= 1 << uint(i%8)
+}
@@ -0,0 +1,220 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+)
+
+// streamMessage is a message read back from a stream.
+type streamMessage struct {
+	headerType uint8
+	bodyLength int
+}
+
+// readStream returns the messages of a stream, checking that it is framed
+// correctly and ends with the end of stream marker.
+func readStream(t *testing.T, p []byte) []streamMessage {
+	t.Helper()
+	var msgs []streamMessage
+	for {
+		if len(p) < 8 {
+			t.Fatalf("stream ended without end of stream marker")
+		}
+		if c := binary.LittleEndian.Uint32(p); c != continuation {
+			t.Fatalf("expected continuation marker, got %x", c)
+		}
+		n := int(binary.LittleEndian.Uint32(p[4:]))
+		p = p[8:]
+		if n == 0 {
+			if len(p) != 0 {
+				t.Fatalf("unexpected %d bytes after end of stream", len(p))
+			}
+			return msgs
+		}
+		if n%8 != 0 {
+			t.Fatalf("metadata of %d bytes is not padded to 8 bytes", n)
+		}
+
+		meta := p[:n]
+		m := streamMessage{
+			headerType: uint8(tableField(meta, 1, 1)),
+			bodyLength: int(tableField(meta, 3, 8)),
+		}
+		if v := tableField(meta, 0, 2); v != metadataV4 {
+			t.Fatalf("unexpected metadata version %d", v)
+		}
+		msgs = append(msgs, m)
+		p = p[n+m.bodyLength:]
+	}
+}
+
+// tableField reads a scalar field of the root table of a flatbuffer.
+func tableField(buf []byte, i, size int) uint64 {
+	table := int(binary.LittleEndian.Uint32(buf))
+	vtable := table - int(int32(binary.LittleEndian.Uint32(buf[table:])))
+	if 4+2*i >= int(binary.LittleEndian.Uint16(buf[vtable:])) {
+		return 0
+	}
+	off := int(binary.LittleEndian.Uint16(buf[vtable+4+2*i:]))
+	if off == 0 {
+		return 0
+	}
+	v := make([]byte, 8)
+	copy(v, buf[table+off:table+off+size])
+	return binary.LittleEndian.Uint64(v)
+}
+
+func TestMultiResultEncoder_Encode(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{
+		&executetest.Result{
+			Nm: "_result",
+			Tbls: []*executetest.Table{
+				{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(0), "a", 1.5},
+						{execute.Time(10), "a", nil},
+					},
+				},
+				{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "host", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(0), "b"},
+					},
+				},
+			},
+		},
+	})
+
+	var buf bytes.Buffer
+	n, err := new(MultiResultEncoder).Encode(&buf, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("unexpected count of bytes written -want/+got:\n\t- %d\n\t+ %d", buf.Len(), n)
+	}
+
+	msgs := readStream(t, buf.Bytes())
+	want := []uint8{headerSchema, headerRecordBatch, headerRecordBatch}
+	if len(msgs) != len(want) {
+		t.Fatalf("unexpected number of messages -want/+got:\n\t- %d\n\t+ %d", len(want), len(msgs))
+	}
+	for i, m := range msgs {
+		if m.headerType != want[i] {
+			t.Errorf("unexpected header of message %d -want/+got:\n\t- %d\n\t+ %d", i, want[i], m.headerType)
+		}
+		if m.bodyLength%8 != 0 {
+			t.Errorf("body of message %d is not padded to 8 bytes: %d", i, m.bodyLength)
+		}
+	}
+	if msgs[0].bodyLength != 0 {
+		t.Errorf("unexpected body of schema message: %d bytes", msgs[0].bodyLength)
+	}
+}
+
+func TestMultiResultEncoder_Encode_noTables(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := new(MultiResultEncoder).Encode(&buf, flux.NewSliceResultIterator(nil)); err != nil {
+		t.Fatal(err)
+	}
+	msgs := readStream(t, buf.Bytes())
+	if len(msgs) != 1 || msgs[0].headerType != headerSchema {
+		t.Fatalf("expected only a schema message, got %v", msgs)
+	}
+}
+
+func TestMultiResultEncoder_Encode_schemaMismatch(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{
+		&executetest.Result{
+			Nm: "_result",
+			Tbls: []*executetest.Table{
+				{
+					ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TFloat}},
+					Data:    [][]interface{}{{1.0}},
+				},
+				{
+					ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TString}},
+					Data:    [][]interface{}{{"a"}},
+				},
+			},
+		},
+	})
+
+	if _, err := new(MultiResultEncoder).Encode(new(bytes.Buffer), results); err == nil {
+		t.Fatal("expected an error for a table with a column of another type")
+	}
+}
+
+func TestBody_appendColumn(t *testing.T) {
+	tbl := &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_value", Type: flux.TInt},
+		},
+		Data: [][]interface{}{
+			{int64(1)},
+			{nil},
+			{int64(3)},
+		},
+	}
+
+	var b body
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		b.appendColumn(cr, 0, flux.TInt, cr.Len())
+		b.appendColumn(cr, -1, flux.TString, cr.Len())
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	wantNodes := []fieldNode{
+		{length: 3, nullCount: 1},
+		{length: 3, nullCount: 3},
+	}
+	if len(b.nodes) != len(wantNodes) {
+		t.Fatalf("unexpected nodes: %v", b.nodes)
+	}
+	for i := range wantNodes {
+		if b.nodes[i] != wantNodes[i] {
+			t.Errorf("unexpected node %d -want/+got:\n\t- %v\n\t+ %v", i, wantNodes[i], b.nodes[i])
+		}
+	}
+
+	// validity, values; validity, offsets, data
+	wantBuffers := []buffer{
+		{offset: 0, length: 1},
+		{offset: 8, length: 24},
+		{offset: 32, length: 1},
+		{offset: 40, length: 16},
+		{offset: 56, length: 0},
+	}
+	if len(b.buffers) != len(wantBuffers) {
+		t.Fatalf("unexpected buffers: %v", b.buffers)
+	}
+	for i := range wantBuffers {
+		if b.buffers[i] != wantBuffers[i] {
+			t.Errorf("unexpected buffer %d -want/+got:\n\t- %v\n\t+ %v", i, wantBuffers[i], b.buffers[i])
+		}
+	}
+
+	if got := b.buf[0]; got != 0x5 {
+		t.Errorf("unexpected validity bitmap %08b", got)
+	}
+	if got := int64(binary.LittleEndian.Uint64(b.buf[8+16:])); got != 3 {
+		t.Errorf("unexpected third value %d", got)
+	}
+}
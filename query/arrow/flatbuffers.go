@@ -0,0 +1,201 @@
+package arrow
+
+import "encoding/binary"
+
+// builder builds a flatbuffer back to front, like the builder of the
+// flatbuffers library, which this encodes the few tables of the Arrow
+// IPC messages with rather than depending on it.
+// Offsets of objects are counted from the end of the buffer.
+type builder struct {
+	buf       []byte
+	head      int
+	minAlign  int
+	vtable    []uint32
+	objectEnd uint32
+}
+
+func newBuilder(size int) *builder {
+	return &builder{buf: make([]byte, size), head: size, minAlign: 1}
+}
+
+// offset returns the offset of the object written last.
+func (b *builder) offset() uint32 {
+	return uint32(len(b.buf) - b.head)
+}
+
+func (b *builder) grow() {
+	size := len(b.buf) * 2
+	if size == 0 {
+		size = 64
+	}
+	buf := make([]byte, size)
+	copy(buf[size-len(b.buf):], b.buf)
+	b.head += size - len(b.buf)
+	b.buf = buf
+}
+
+func (b *builder) pad(n int) {
+	for i := 0; i < n; i++ {
+		b.head--
+		b.buf[b.head] = 0
+	}
+}
+
+// prep aligns the buffer so that size bytes are aligned after additional
+// bytes are written, and makes room for them.
+func (b *builder) prep(size, additional int) {
+	if size > b.minAlign {
+		b.minAlign = size
+	}
+	alignSize := (^(len(b.buf) - b.head + additional) + 1) & (size - 1)
+	for b.head <= alignSize+size+additional {
+		b.grow()
+	}
+	b.pad(alignSize)
+}
+
+func (b *builder) placeUint8(v uint8) {
+	b.head--
+	b.buf[b.head] = v
+}
+
+func (b *builder) placeUint16(v uint16) {
+	b.head -= 2
+	binary.LittleEndian.PutUint16(b.buf[b.head:], v)
+}
+
+func (b *builder) placeUint32(v uint32) {
+	b.head -= 4
+	binary.LittleEndian.PutUint32(b.buf[b.head:], v)
+}
+
+func (b *builder) placeUint64(v uint64) {
+	b.head -= 8
+	binary.LittleEndian.PutUint64(b.buf[b.head:], v)
+}
+
+func (b *builder) prependUint8(v uint8) {
+	b.prep(1, 0)
+	b.placeUint8(v)
+}
+
+func (b *builder) prependUint16(v uint16) {
+	b.prep(2, 0)
+	b.placeUint16(v)
+}
+
+func (b *builder) prependUint32(v uint32) {
+	b.prep(4, 0)
+	b.placeUint32(v)
+}
+
+func (b *builder) prependUint64(v uint64) {
+	b.prep(8, 0)
+	b.placeUint64(v)
+}
+
+// prependOffset prepends the offset to an object written before,
+// relative to where the offset is written.
+func (b *builder) prependOffset(off uint32) {
+	b.prep(4, 0)
+	b.placeUint32(b.offset() - off + 4)
+}
+
+// createString writes a string and returns its offset.
+func (b *builder) createString(s string) uint32 {
+	b.prep(4, len(s)+1)
+	b.placeUint8(0)
+	b.head -= len(s)
+	copy(b.buf[b.head:], s)
+	return b.endVector(len(s))
+}
+
+// startVector starts a vector of n elements of elemSize bytes.
+// The elements are prepended in reverse order.
+func (b *builder) startVector(elemSize, n, alignment int) {
+	b.prep(4, elemSize*n)
+	b.prep(alignment, elemSize*n)
+}
+
+func (b *builder) endVector(n int) uint32 {
+	b.placeUint32(uint32(n))
+	return b.offset()
+}
+
+// createOffsetVector writes a vector of offsets to objects and returns its offset.
+func (b *builder) createOffsetVector(offs []uint32) uint32 {
+	b.startVector(4, len(offs), 4)
+	for i := len(offs) - 1; i >= 0; i-- {
+		b.prependOffset(offs[i])
+	}
+	return b.endVector(len(offs))
+}
+
+// startObject starts a table with n fields.
+// Objects cannot be nested, so the objects a table refers to are written before.
+func (b *builder) startObject(n int) {
+	b.vtable = make([]uint32, n)
+	b.objectEnd = b.offset()
+}
+
+func (b *builder) slot(i int) {
+	b.vtable[i] = b.offset()
+}
+
+func (b *builder) addUint8(i int, v uint8) {
+	b.prependUint8(v)
+	b.slot(i)
+}
+
+func (b *builder) addUint16(i int, v uint16) {
+	b.prependUint16(v)
+	b.slot(i)
+}
+
+func (b *builder) addUint32(i int, v uint32) {
+	b.prependUint32(v)
+	b.slot(i)
+}
+
+func (b *builder) addUint64(i int, v uint64) {
+	b.prependUint64(v)
+	b.slot(i)
+}
+
+func (b *builder) addOffset(i int, off uint32) {
+	b.prependOffset(off)
+	b.slot(i)
+}
+
+// endObject writes the vtable of the table and returns the offset of the table.
+func (b *builder) endObject() uint32 {
+	b.prependUint32(0)
+	object := b.offset()
+
+	n := len(b.vtable)
+	for n > 0 && b.vtable[n-1] == 0 {
+		n--
+	}
+	for i := n - 1; i >= 0; i-- {
+		var off uint16
+		if b.vtable[i] != 0 {
+			off = uint16(object - b.vtable[i])
+		}
+		b.prependUint16(off)
+	}
+	b.prependUint16(uint16(object - b.objectEnd))
+	b.prependUint16(uint16((n + 2) * 2))
+
+	// The table starts with the signed offset from the table to its vtable.
+	vtable := b.offset()
+	binary.LittleEndian.PutUint32(b.buf[len(b.buf)-int(object):], uint32(int32(vtable)-int32(object)))
+	b.vtable = nil
+	return object
+}
+
+// finish writes the offset of the root table and returns the flatbuffer.
+func (b *builder) finish(root uint32) []byte {
+	b.prep(b.minAlign, 4)
+	b.prependOffset(root)
+	return b.buf[b.head:]
+}
@@ -0,0 +1,189 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/influxdata/flux"
+)
+
+// Values of the Arrow format, see Schema.fbs and Message.fbs of Apache Arrow.
+const (
+	metadataV4 = 3
+
+	headerSchema      = 1
+	headerRecordBatch = 3
+
+	typeInt           = 2
+	typeFloatingPoint = 3
+	typeUtf8          = 5
+	typeBool          = 6
+	typeTimestamp     = 10
+
+	precisionDouble    = 2
+	timeUnitNanosecond = 3
+)
+
+// continuation starts every message of a stream, so readers can tell
+// the length of a message apart from the legacy stream format.
+const continuation = 0xFFFFFFFF
+
+// field is a column of the schema of a stream.
+type field struct {
+	name string
+	typ  flux.ColType
+}
+
+// schemaMessage returns the metadata of the message declaring the schema of a stream.
+// Every field is nullable, times are nanosecond timestamps in UTC.
+func schemaMessage(fields []field) []byte {
+	b := newBuilder(1024)
+	offs := make([]uint32, len(fields))
+	for i, f := range fields {
+		name := b.createString(f.name)
+		typeType, typ := fieldType(b, f.typ)
+		children := b.createOffsetVector(nil)
+
+		b.startObject(7)
+		b.addOffset(0, name)
+		b.addUint8(1, 1)
+		b.addUint8(2, typeType)
+		b.addOffset(3, typ)
+		b.addOffset(5, children)
+		offs[i] = b.endObject()
+	}
+	fs := b.createOffsetVector(offs)
+
+	b.startObject(3)
+	b.addOffset(1, fs)
+	schema := b.endObject()
+	return b.finish(message(b, headerSchema, schema, 0))
+}
+
+// fieldType writes the type of a column and returns it with its type in the Type union.
+func fieldType(b *builder, typ flux.ColType) (uint8, uint32) {
+	switch typ {
+	case flux.TBool:
+		b.startObject(0)
+		return typeBool, b.endObject()
+	case flux.TInt, flux.TUInt:
+		b.startObject(2)
+		b.addUint32(0, 64)
+		if typ == flux.TInt {
+			b.addUint8(1, 1)
+		}
+		return typeInt, b.endObject()
+	case flux.TFloat:
+		b.startObject(1)
+		b.addUint16(0, precisionDouble)
+		return typeFloatingPoint, b.endObject()
+	case flux.TTime:
+		tz := b.createString("UTC")
+		b.startObject(2)
+		b.addUint16(0, timeUnitNanosecond)
+		b.addOffset(1, tz)
+		return typeTimestamp, b.endObject()
+	default:
+		b.startObject(0)
+		return typeUtf8, b.endObject()
+	}
+}
+
+// recordBatchMessage returns the metadata of the message of a record batch.
+func recordBatchMessage(length int, body *body) []byte {
+	b := newBuilder(1024)
+
+	b.startVector(16, len(body.buffers), 8)
+	for i := len(body.buffers) - 1; i >= 0; i-- {
+		b.prep(8, 16)
+		b.placeUint64(uint64(body.buffers[i].length))
+		b.placeUint64(uint64(body.buffers[i].offset))
+	}
+	buffers := b.endVector(len(body.buffers))
+
+	b.startVector(16, len(body.nodes), 8)
+	for i := len(body.nodes) - 1; i >= 0; i-- {
+		b.prep(8, 16)
+		b.placeUint64(uint64(body.nodes[i].nullCount))
+		b.placeUint64(uint64(body.nodes[i].length))
+	}
+	nodes := b.endVector(len(body.nodes))
+
+	b.startObject(3)
+	b.addUint64(0, uint64(length))
+	b.addOffset(1, nodes)
+	b.addOffset(2, buffers)
+	batch := b.endObject()
+	return b.finish(message(b, headerRecordBatch, batch, len(body.buf)))
+}
+
+func message(b *builder, headerType uint8, header uint32, bodyLength int) uint32 {
+	b.startObject(5)
+	b.addUint64(3, uint64(bodyLength))
+	b.addOffset(2, header)
+	b.addUint16(0, metadataV4)
+	b.addUint8(1, headerType)
+	return b.endObject()
+}
+
+type fieldNode struct {
+	length    int
+	nullCount int
+}
+
+type buffer struct {
+	offset int
+	length int
+}
+
+// body is the body of a record batch: the buffers of its columns, each
+// aligned to 8 bytes.
+type body struct {
+	buf     []byte
+	nodes   []fieldNode
+	buffers []buffer
+}
+
+func (b *body) reset() {
+	b.buf = b.buf[:0]
+	b.nodes = b.nodes[:0]
+	b.buffers = b.buffers[:0]
+}
+
+// add appends a buffer of the body.
+func (b *body) add(p []byte) {
+	b.buffers = append(b.buffers, buffer{offset: len(b.buf), length: len(p)})
+	b.buf = append(b.buf, p...)
+	for len(b.buf)%8 != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// streamWriter writes the messages of an Arrow IPC stream.
+type streamWriter struct {
+	w io.Writer
+}
+
+var padding [8]byte
+
+// writeMessage writes a message with its metadata padded to 8 bytes, so the body is aligned.
+func (s *streamWriter) writeMessage(meta, body []byte) error {
+	n := (len(meta) + 7) &^ 7
+	var prefix [8]byte
+	binary.LittleEndian.PutUint32(prefix[:4], continuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(n))
+	for _, p := range [][]byte{prefix[:], meta, padding[:n-len(meta)], body} {
+		if _, err := s.w.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeEnd writes the end of the stream.
+func (s *streamWriter) writeEnd() error {
+	var eos [8]byte
+	binary.LittleEndian.PutUint32(eos[:4], continuation)
+	_, err := s.w.Write(eos[:])
+	return err
+}
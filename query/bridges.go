@@ -97,6 +97,9 @@ func (b ProxyQueryServiceAsyncBridge) Query(ctx context.Context, w io.Writer, re
 	}
 
 	results := flux.NewResultIteratorFromQuery(q)
+	if req.Profile != "" {
+		results = NewProfilingResultIterator(results, req.Profile)
+	}
 	defer results.Release()
 
 	encoder := req.Dialect.Encoder()
@@ -0,0 +1,112 @@
+package query
+
+import (
+	"time"
+
+	"github.com/influxdata/flux/ast"
+)
+
+// CalendarIdentifier is the identifier the calendar is declared as in queries.
+const CalendarIdentifier = "calendar"
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// Calendar holds the calendar boundaries of a timezone at a point in time, so
+// a query can align its windows to the days, weeks and months of the timezone
+// without offset math of its own, for example:
+//
+//	from(bucket: "sales")
+//		|> range(start: calendar.startOfMonth)
+//		|> window(every: 1d, offset: calendar.dayOffset)
+//
+// Weeks start on Monday. The offsets hold for the current UTC offset of the
+// timezone, so windows that span a daylight saving change are off by the change.
+type Calendar struct {
+	Timezone string
+
+	// DayOffset and WeekOffset align windows of 1d and 1w to the midnights
+	// of the timezone.
+	DayOffset  time.Duration
+	WeekOffset time.Duration
+
+	StartOfDay   time.Time
+	StartOfWeek  time.Time
+	StartOfMonth time.Time
+	StartOfYear  time.Time
+}
+
+// NewCalendar returns the calendar of the location at now.
+func NewCalendar(now time.Time, loc *time.Location) Calendar {
+	now = now.In(loc)
+	y, m, d := now.Date()
+	_, utcOffset := now.Zone()
+
+	startOfDay := time.Date(y, m, d, 0, 0, 0, 0, loc)
+	// Weekday counts from Sunday, weeks start on Monday.
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+
+	return Calendar{
+		Timezone: loc.String(),
+		// Windows are aligned to the Unix epoch, a Thursday at midnight UTC.
+		DayOffset:    positiveMod(-time.Duration(utcOffset)*time.Second, day),
+		WeekOffset:   positiveMod(4*day-time.Duration(utcOffset)*time.Second, week),
+		StartOfDay:   startOfDay,
+		StartOfWeek:  startOfDay.AddDate(0, 0, -daysSinceMonday),
+		StartOfMonth: time.Date(y, m, 1, 0, 0, 0, 0, loc),
+		StartOfYear:  time.Date(y, time.January, 1, 0, 0, 0, 0, loc),
+	}
+}
+
+func positiveMod(d, m time.Duration) time.Duration {
+	if d %= m; d < 0 {
+		d += m
+	}
+	return d
+}
+
+// Statement returns the statement that declares the calendar as a record.
+func (c Calendar) Statement() ast.Statement {
+	return &ast.VariableAssignment{
+		ID: &ast.Identifier{Name: CalendarIdentifier},
+		Init: &ast.ObjectExpression{
+			Properties: []*ast.Property{
+				calendarProperty("timezone", &ast.StringLiteral{Value: c.Timezone}),
+				calendarProperty("dayOffset", durationLiteral(c.DayOffset)),
+				calendarProperty("weekOffset", durationLiteral(c.WeekOffset)),
+				calendarProperty("startOfDay", &ast.DateTimeLiteral{Value: c.StartOfDay}),
+				calendarProperty("startOfWeek", &ast.DateTimeLiteral{Value: c.StartOfWeek}),
+				calendarProperty("startOfMonth", &ast.DateTimeLiteral{Value: c.StartOfMonth}),
+				calendarProperty("startOfYear", &ast.DateTimeLiteral{Value: c.StartOfYear}),
+			},
+		},
+	}
+}
+
+func calendarProperty(key string, value ast.Expression) *ast.Property {
+	return &ast.Property{
+		Key:   &ast.Identifier{Name: key},
+		Value: value,
+	}
+}
+
+// durationLiteral returns the literal of a duration of whole minutes, which
+// all UTC offsets in use are.
+func durationLiteral(d time.Duration) *ast.DurationLiteral {
+	return &ast.DurationLiteral{
+		Values: []ast.Duration{{Magnitude: int64(d / time.Minute), Unit: "m"}},
+	}
+}
+
+// WithCalendar returns a copy of the external declarations of a query with
+// the calendar declared in addition. The extern may be nil.
+func WithCalendar(extern *ast.File, c Calendar) *ast.File {
+	f := &ast.File{}
+	if extern != nil {
+		f = extern.Copy().(*ast.File)
+	}
+	f.Body = append(f.Body, c.Statement())
+	return f
+}
@@ -0,0 +1,121 @@
+package query_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/query"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone database is not available: %v", err)
+	}
+	return loc
+}
+
+func TestNewCalendar(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		loc        *time.Location
+		dayOffset  time.Duration
+		weekOffset time.Duration
+		week       time.Time
+	}{
+		{
+			name:       "ahead of UTC",
+			now:        time.Date(2019, 10, 16, 10, 0, 0, 0, time.UTC),
+			loc:        berlin,
+			dayOffset:  22 * time.Hour,
+			weekOffset: 94 * time.Hour,
+			week:       time.Date(2019, 10, 14, 0, 0, 0, 0, berlin),
+		},
+		{
+			name:       "behind UTC",
+			now:        time.Date(2019, 10, 16, 10, 0, 0, 0, time.UTC),
+			loc:        newYork,
+			dayOffset:  4 * time.Hour,
+			weekOffset: 100 * time.Hour,
+			week:       time.Date(2019, 10, 14, 0, 0, 0, 0, newYork),
+		},
+		{
+			name:       "sunday",
+			now:        time.Date(2019, 10, 20, 21, 0, 0, 0, time.UTC),
+			loc:        berlin,
+			dayOffset:  22 * time.Hour,
+			weekOffset: 94 * time.Hour,
+			week:       time.Date(2019, 10, 14, 0, 0, 0, 0, berlin),
+		},
+		{
+			name: "UTC",
+			now:  time.Date(2019, 10, 16, 10, 0, 0, 0, time.UTC),
+			loc:  time.UTC,
+			// The epoch is a Thursday.
+			weekOffset: 96 * time.Hour,
+			week:       time.Date(2019, 10, 14, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := query.NewCalendar(tt.now, tt.loc)
+			if c.DayOffset != tt.dayOffset {
+				t.Errorf("got day offset %v, want %v", c.DayOffset, tt.dayOffset)
+			}
+			if c.WeekOffset != tt.weekOffset {
+				t.Errorf("got week offset %v, want %v", c.WeekOffset, tt.weekOffset)
+			}
+			if !c.StartOfWeek.Equal(tt.week) {
+				t.Errorf("got start of week %v, want %v", c.StartOfWeek, tt.week)
+			}
+			if c.StartOfDay.After(tt.now) || tt.now.Sub(c.StartOfDay) >= 24*time.Hour {
+				t.Errorf("start of day %v does not precede %v", c.StartOfDay, tt.now)
+			}
+			if y, m, d := c.StartOfMonth.In(tt.loc).Date(); d != 1 || m != tt.now.In(tt.loc).Month() || y != 2019 {
+				t.Errorf("unexpected start of month %v", c.StartOfMonth)
+			}
+			if want := time.Date(2019, time.January, 1, 0, 0, 0, 0, tt.loc); !c.StartOfYear.Equal(want) {
+				t.Errorf("got start of year %v, want %v", c.StartOfYear, want)
+			}
+		})
+	}
+}
+
+func TestWithCalendar(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	c := query.NewCalendar(time.Date(2019, 10, 16, 10, 0, 0, 0, time.UTC), berlin)
+
+	extern := parser.ParseSource(`threshold = 10`).Files[0]
+	f := query.WithCalendar(extern, c)
+	if len(extern.Body) != 1 {
+		t.Fatalf("expected the extern not to be modified, got %d statements", len(extern.Body))
+	}
+
+	src := ast.Format(f)
+	for _, want := range []string{
+		`threshold = 10`,
+		`timezone: "Europe/Berlin"`,
+		`dayOffset: 1320m`,
+		`weekOffset: 5640m`,
+		`startOfMonth: 2019-10-01T00:00:00+02:00`,
+		`startOfYear: 2019-01-01T00:00:00+01:00`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected %q in the declarations:\n%s", want, src)
+		}
+	}
+
+	// The declarations must be valid Flux.
+	if pkg := parser.ParseSource(src); ast.Check(pkg) > 0 {
+		t.Errorf("declarations do not parse: %v", ast.GetError(pkg))
+	}
+}
@@ -0,0 +1,524 @@
+package control
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/influxdb/kit/errors"
+	"github.com/influxdata/influxdb/query"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ResultCacheConfig configures the cache of query results.
+//
+// Dashboards refresh the same cells every few seconds, so the controller can
+// serve their queries from the results of an earlier run instead of scanning
+// the same data again. To make those runs share results, now() is truncated
+// to the NowPrecision for every query that is eligible for the cache.
+type ResultCacheConfig struct {
+	// TTL is how long results are served from the cache.
+	// The cache is disabled if it is zero.
+	TTL time.Duration
+	// MaxBytes is the approximate size of the results the cache holds.
+	// The least recently used results are evicted to stay within it.
+	MaxBytes int64
+	// NowPrecision is the interval now() is truncated to.
+	NowPrecision time.Duration
+}
+
+// Validate returns an error if the cache is enabled but can't hold any results.
+func (c ResultCacheConfig) Validate() error {
+	if c.TTL <= 0 {
+		return nil
+	}
+	if c.MaxBytes <= 0 {
+		return errors.New("ResultCache.MaxBytes must be positive")
+	}
+	if c.NowPrecision < 0 {
+		return errors.New("ResultCache.NowPrecision must not be negative")
+	}
+	return nil
+}
+
+// uncacheableImports are the packages whose functions have side effects or
+// read data that isn't bound by now(), so queries importing them always run.
+var uncacheableImports = map[string]bool{
+	"http":   true,
+	"sql":    true,
+	"system": true,
+}
+
+// resultCache holds the results of Flux queries keyed on the normalized query,
+// the truncated now(), the organization and the permissions the query ran with.
+type resultCache struct {
+	ttl          time.Duration
+	maxBytes     int64
+	nowPrecision time.Duration
+	now          func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+
+	hits, misses prometheus.Counter
+	bytes        prometheus.Gauge
+}
+
+type cacheEntry struct {
+	key     string
+	results []*cachedResult
+	size    int64
+	expires time.Time
+}
+
+func newResultCache(c ResultCacheConfig) *resultCache {
+	const (
+		namespace = "query"
+		subsystem = "control_cache"
+	)
+
+	return &resultCache{
+		ttl:          c.TTL,
+		maxBytes:     c.MaxBytes,
+		nowPrecision: c.NowPrecision,
+		now:          time.Now,
+		entries:      make(map[string]*list.Element),
+		lru:          list.New(),
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "hits_total",
+			Help:      "Count of queries served from the result cache",
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "misses_total",
+			Help:      "Count of cacheable queries that were not in the result cache",
+		}),
+		bytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "bytes",
+			Help:      "Approximate size of the results in the result cache",
+		}),
+	}
+}
+
+// PrometheusCollectors satisifies the prom.PrometheusCollector interface.
+func (c *resultCache) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{c.hits, c.misses, c.bytes}
+}
+
+// prepare returns the cache key of the request and the request to run on a miss,
+// whose now() is truncated to the precision of the cache. It returns false if the
+// results of the request can't be cached.
+func (c *resultCache) prepare(req *query.Request) (string, *query.Request, bool) {
+	compiler, ok := req.Compiler.(lang.FluxCompiler)
+	if !ok || !compiler.Now.IsZero() {
+		return "", nil, false
+	}
+
+	pkg := parser.ParseSource(compiler.Query)
+	if ast.Check(pkg) > 0 || !cacheable(pkg) {
+		return "", nil, false
+	}
+
+	now := c.now()
+	if c.nowPrecision > 0 {
+		now = now.Truncate(c.nowPrecision)
+	}
+	compiler.Now = now
+
+	var key strings.Builder
+	key.WriteString(req.OrganizationID.String())
+	key.WriteByte('\n')
+	if req.Authorization != nil {
+		for _, p := range req.Authorization.Permissions {
+			key.WriteString(p.String())
+			key.WriteByte(',')
+		}
+	}
+	key.WriteByte('\n')
+	key.WriteString(now.UTC().Format(time.RFC3339Nano))
+	key.WriteByte('\n')
+	if compiler.Extern != nil {
+		key.WriteString(ast.Format(compiler.Extern))
+	}
+	key.WriteByte('\n')
+	key.WriteString(ast.Format(pkg))
+
+	r := *req
+	r.Compiler = compiler
+	return key.String(), &r, true
+}
+
+// cacheable returns false if the query has side effects or reads data that isn't bound by now().
+func cacheable(pkg *ast.Package) bool {
+	ok := true
+	ast.Visit(pkg, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.ImportDeclaration:
+			if n.Path != nil && uncacheableImports[n.Path.Value] {
+				ok = false
+			}
+		case *ast.CallExpression:
+			switch callee := n.Callee.(type) {
+			case *ast.Identifier:
+				if callee.Name == "to" {
+					ok = false
+				}
+			case *ast.MemberExpression:
+				if p, isIdent := callee.Property.(*ast.Identifier); isIdent && p.Name == "to" {
+					ok = false
+				}
+			}
+		}
+	})
+	return ok
+}
+
+// get returns a query that replays the cached results of the key.
+func (c *resultCache) get(key string) (flux.Query, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses.Inc()
+		return nil, false
+	}
+	e := elem.Value.(*cacheEntry)
+	if !c.now().Before(e.expires) {
+		c.remove(elem)
+		c.misses.Inc()
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	c.hits.Inc()
+
+	// The copies are made while holding the lock, so the entry can't be
+	// evicted while they are made.
+	results := make(chan flux.Result, len(e.results))
+	for _, r := range e.results {
+		results <- r.copy()
+	}
+	close(results)
+	return &cachedQuery{results: results}, true
+}
+
+// set adds the results to the cache, evicting the least recently used results
+// to make room for them.
+func (c *resultCache) set(key string, results []*cachedResult, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	// Results are usually stale before they expire, since the truncated now()
+	// is part of their key, so make room by removing expired results first.
+	now := c.now()
+	for elem := c.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if !now.Before(elem.Value.(*cacheEntry).expires) {
+			c.remove(elem)
+		}
+		elem = prev
+	}
+	for c.size+size > c.maxBytes && c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+	}
+
+	e := &cacheEntry{
+		key:     key,
+		results: results,
+		size:    size,
+		expires: now.Add(c.ttl),
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	c.size += size
+	c.bytes.Set(float64(c.size))
+}
+
+// remove must be called with the lock held.
+func (c *resultCache) remove(elem *list.Element) {
+	e := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, e.key)
+	for _, r := range e.results {
+		r.release()
+	}
+	c.size -= e.size
+	c.bytes.Set(float64(c.size))
+}
+
+// track returns a query that adds the results of q to the cache under the key,
+// once they were read completely and without errors.
+func (c *resultCache) track(key string, q flux.Query) flux.Query {
+	cq := &cachingQuery{
+		Query:     q,
+		cache:     c,
+		key:       key,
+		results:   make(chan flux.Result),
+		done:      make(chan struct{}),
+		forwarded: make(chan struct{}),
+	}
+	go cq.forward()
+	return cq
+}
+
+// cachedResult holds the tables of a result. The tables are never read
+// themselves, only copies of them are.
+type cachedResult struct {
+	name   string
+	tables []flux.BufferedTable
+}
+
+func (r *cachedResult) Name() string {
+	return r.name
+}
+
+func (r *cachedResult) Tables() flux.TableIterator {
+	return r
+}
+
+func (r *cachedResult) Do(f func(flux.Table) error) error {
+	for _, t := range r.tables {
+		if err := f(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *cachedResult) copy() *cachedResult {
+	cp := &cachedResult{
+		name:   r.name,
+		tables: make([]flux.BufferedTable, len(r.tables)),
+	}
+	for i, t := range r.tables {
+		cp.tables[i] = t.Copy()
+	}
+	return cp
+}
+
+func (r *cachedResult) release() {
+	for _, t := range r.tables {
+		t.Done()
+	}
+}
+
+// cachedQuery replays cached results.
+type cachedQuery struct {
+	results chan flux.Result
+}
+
+func (q *cachedQuery) Results() <-chan flux.Result {
+	return q.results
+}
+
+func (q *cachedQuery) Done() {
+	// Release the results that were not read.
+	for r := range q.results {
+		r.(*cachedResult).release()
+	}
+}
+
+func (q *cachedQuery) Cancel() {}
+
+func (q *cachedQuery) Err() error {
+	return nil
+}
+
+func (q *cachedQuery) Statistics() flux.Statistics {
+	return flux.Statistics{}
+}
+
+// cachingQuery passes on the results of a query while it buffers them for the cache.
+type cachingQuery struct {
+	flux.Query
+
+	cache *resultCache
+	key   string
+
+	results   chan flux.Result
+	done      chan struct{}
+	doneOnce  sync.Once
+	forwarded chan struct{}
+
+	mu       sync.Mutex
+	buffered []*cachedResult
+	size     int64
+	// pending is the number of results whose tables were not read completely.
+	pending  int
+	complete bool
+	failed   bool
+}
+
+func (q *cachingQuery) Results() <-chan flux.Result {
+	return q.results
+}
+
+func (q *cachingQuery) forward() {
+	defer close(q.forwarded)
+	defer close(q.results)
+
+	for r := range q.Query.Results() {
+		cr := &cachedResult{name: r.Name()}
+		q.mu.Lock()
+		q.buffered = append(q.buffered, cr)
+		q.pending++
+		q.mu.Unlock()
+
+		select {
+		case q.results <- &cachingResult{Result: r, q: q, cr: cr}:
+		case <-q.done:
+			return
+		}
+	}
+
+	// The results of a query that was done before they were all sent may be
+	// cut short, even though the query reports no error.
+	select {
+	case <-q.done:
+		return
+	default:
+	}
+
+	q.mu.Lock()
+	q.complete = true
+	q.mu.Unlock()
+}
+
+// Done adds the results to the cache if they were read completely and without errors.
+func (q *cachingQuery) Done() {
+	q.doneOnce.Do(func() {
+		close(q.done)
+		q.Query.Done()
+		<-q.forwarded
+
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		ok := q.complete && !q.failed && q.pending == 0 && q.Query.Err() == nil &&
+			len(q.Query.Statistics().RuntimeErrors) == 0
+		if !ok {
+			q.release()
+			return
+		}
+		q.cache.set(q.key, q.buffered, q.size)
+		q.buffered = nil
+	})
+}
+
+// buffer adds the table to the buffered result. It returns false if the results
+// grew too large to be cached, in which case nothing is buffered anymore.
+func (q *cachingQuery) buffer(cr *cachedResult, t flux.BufferedTable) bool {
+	size := tableSize(t)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.failed {
+		return false
+	}
+	if q.size+size > q.cache.maxBytes {
+		q.failed = true
+		q.release()
+		return false
+	}
+	cr.tables = append(cr.tables, t)
+	q.size += size
+	return true
+}
+
+func (q *cachingQuery) buffering() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return !q.failed
+}
+
+func (q *cachingQuery) finishResult(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending--
+	if err != nil && !q.failed {
+		q.failed = true
+		q.release()
+	}
+}
+
+// release must be called with the lock held.
+func (q *cachingQuery) release() {
+	for _, r := range q.buffered {
+		r.release()
+	}
+	q.buffered = nil
+}
+
+type cachingResult struct {
+	flux.Result
+	q  *cachingQuery
+	cr *cachedResult
+}
+
+func (r *cachingResult) Tables() flux.TableIterator {
+	return &cachingTableIterator{
+		ti: r.Result.Tables(),
+		q:  r.q,
+		cr: r.cr,
+	}
+}
+
+type cachingTableIterator struct {
+	ti flux.TableIterator
+	q  *cachingQuery
+	cr *cachedResult
+}
+
+func (ti *cachingTableIterator) Do(f func(flux.Table) error) error {
+	err := ti.ti.Do(func(t flux.Table) error {
+		if !ti.q.buffering() {
+			return f(t)
+		}
+
+		bt, err := execute.CopyTable(t)
+		if err != nil {
+			return err
+		}
+		if !ti.q.buffer(ti.cr, bt) {
+			return f(bt)
+		}
+		return f(bt.Copy())
+	})
+	ti.q.finishResult(err)
+	return err
+}
+
+// tableSize approximates the memory used by the buffered table.
+func tableSize(t flux.BufferedTable) int64 {
+	var size int64
+	_ = t.Copy().Do(func(cr flux.ColReader) error {
+		for j, c := range cr.Cols() {
+			if c.Type != flux.TString {
+				size += int64(cr.Len()) * 8
+				continue
+			}
+			vs := cr.Strings(j)
+			for i := 0; i < vs.Len(); i++ {
+				size += int64(len(vs.ValueString(i))) + 8
+			}
+		}
+		return nil
+	})
+	return size
+}
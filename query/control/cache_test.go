@@ -0,0 +1,174 @@
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/mock"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+func newTestResultCache(now time.Time) *resultCache {
+	c := newResultCache(ResultCacheConfig{
+		TTL:          time.Minute,
+		MaxBytes:     1024,
+		NowPrecision: 10 * time.Second,
+	})
+	c.now = func() time.Time { return now }
+	return c
+}
+
+func TestResultCache_prepare(t *testing.T) {
+	now := time.Date(2019, 7, 1, 13, 14, 15, 0, time.UTC)
+	c := newTestResultCache(now)
+
+	request := func(q string, perms ...platform.Permission) *query.Request {
+		return &query.Request{
+			Authorization:  &platform.Authorization{Permissions: perms},
+			OrganizationID: 1,
+			Compiler:       lang.FluxCompiler{Query: q},
+		}
+	}
+
+	key, req, ok := c.prepare(request(`from(bucket: "telegraf") |> range(start: -1h)`))
+	if !ok {
+		t.Fatal("expected query to be cacheable")
+	}
+	if got, want := req.Compiler.(lang.FluxCompiler).Now, now.Truncate(10*time.Second); !got.Equal(want) {
+		t.Errorf("got now %s, want %s", got, want)
+	}
+
+	if other, _, _ := c.prepare(request("from(bucket:\"telegraf\")\n  |> range(start:-1h)")); other != key {
+		t.Errorf("expected queries that only differ in formatting to share a key")
+	}
+
+	perm := platform.Permission{Action: platform.ReadAction, Resource: platform.Resource{Type: platform.BucketsResourceType}}
+	if other, _, _ := c.prepare(request(`from(bucket: "telegraf") |> range(start: -1h)`, perm)); other == key {
+		t.Errorf("expected queries with different permissions not to share a key")
+	}
+
+	for _, q := range []string{
+		`from(bucket: "telegraf") |> range(start: -1h) |> to(bucket: "copy")`,
+		`import "experimental"` + "\n" + `from(bucket: "telegraf") |> range(start: -1h) |> experimental.to(bucket: "copy")`,
+		`import "http"` + "\n" + `http.post(url: "http://localhost")`,
+		`from(bucket: `,
+	} {
+		if _, _, ok := c.prepare(request(q)); ok {
+			t.Errorf("expected query not to be cacheable: %s", q)
+		}
+	}
+
+	r := request(`from(bucket: "telegraf") |> range(start: -1h)`)
+	r.Compiler = lang.FluxCompiler{Query: `from(bucket: "telegraf") |> range(start: -1h)`, Now: now}
+	if _, _, ok := c.prepare(r); ok {
+		t.Error("expected query with an explicit now not to be cacheable")
+	}
+}
+
+func newTestResultQuery(tbls ...*executetest.Table) *mock.Query {
+	q := &mock.Query{}
+	q.ProduceResults(func(results chan<- flux.Result, canceled <-chan struct{}) {
+		select {
+		case results <- &executetest.Result{Nm: "_result", Tbls: tbls}:
+		case <-canceled:
+		}
+	})
+	return q
+}
+
+func readResults(t *testing.T, q flux.Query) []*executetest.Table {
+	t.Helper()
+
+	var tbls []*executetest.Table
+	for r := range q.Results() {
+		if err := r.Tables().Do(func(tbl flux.Table) error {
+			et, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			tbls = append(tbls, et)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Done()
+	return tbls
+}
+
+func TestResultCache(t *testing.T) {
+	table := func(host string, v float64) *executetest.Table {
+		return &executetest.Table{
+			KeyCols: []string{"host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "host", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), v, host},
+			},
+		}
+	}
+
+	now := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	c := newTestResultCache(now)
+
+	if _, ok := c.get("a"); ok {
+		t.Fatal("expected empty cache to miss")
+	}
+
+	got := readResults(t, c.track("a", newTestResultQuery(table("a", 1), table("b", 2))))
+	if len(got) != 2 {
+		t.Fatalf("expected the results to be passed on, got %d tables", len(got))
+	}
+
+	for i := 0; i < 2; i++ {
+		q, ok := c.get("a")
+		if !ok {
+			t.Fatal("expected the results to be cached")
+		}
+		cached := readResults(t, q)
+		executetest.NormalizeTables(got)
+		executetest.NormalizeTables(cached)
+		if len(cached) != len(got) || cached[1].Data[0][1] != 2.0 {
+			t.Fatalf("unexpected cached tables %v", cached)
+		}
+	}
+
+	t.Run("partially read results are not cached", func(t *testing.T) {
+		q := c.track("b", newTestResultQuery(table("a", 1)))
+		q.Done()
+		if _, ok := c.get("b"); ok {
+			t.Error("expected results that were not read not to be cached")
+		}
+	})
+
+	t.Run("large results are not cached", func(t *testing.T) {
+		tbls := make([]*executetest.Table, 0, 100)
+		for i := 0; i < 100; i++ {
+			tbls = append(tbls, table("host", float64(i)))
+		}
+		if got := readResults(t, c.track("c", newTestResultQuery(tbls...))); len(got) != 100 {
+			t.Fatalf("expected the results to be passed on, got %d tables", len(got))
+		}
+		if _, ok := c.get("c"); ok {
+			t.Error("expected results larger than the cache not to be cached")
+		}
+	})
+
+	t.Run("expired results are removed", func(t *testing.T) {
+		c.now = func() time.Time { return now.Add(time.Minute) }
+		if _, ok := c.get("a"); ok {
+			t.Error("expected expired results to miss")
+		}
+		if c.size != 0 || c.lru.Len() != 0 {
+			t.Errorf("expected expired results to be removed, got %d entries of %d bytes", c.lru.Len(), c.size)
+		}
+	})
+}
@@ -21,6 +21,8 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,6 +33,7 @@ import (
 	"github.com/influxdata/flux/memory"
 	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/kit/errors"
+	"github.com/influxdata/influxdb/kit/prom"
 	"github.com/influxdata/influxdb/kit/tracing"
 	"github.com/influxdata/influxdb/query"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -48,7 +51,7 @@ type Controller struct {
 	lastID     uint64
 	queriesMu  sync.RWMutex
 	queries    map[QueryID]*Query
-	queryQueue chan *Query
+	queryQueue *queryQueue
 	wg         sync.WaitGroup
 	shutdown   bool
 	done       chan struct{}
@@ -63,6 +66,11 @@ type Controller struct {
 	logger *zap.Logger
 
 	dependencies execute.Dependencies
+
+	// cache is nil if the result cache is disabled.
+	cache *resultCache
+
+	fluxPackageService platform.FluxPackageService
 }
 
 type Config struct {
@@ -84,6 +92,23 @@ type Config struct {
 	MetricLabelKeys []string
 
 	ExecutorDependencies execute.Dependencies
+
+	// ResultCache configures the cache of query results. It is disabled by default.
+	ResultCache ResultCacheConfig
+
+	// OrgConcurrencyQuota is the number of queries of a single organization that are allowed to execute
+	// concurrently. The queries of an organization are not limited if it is zero.
+	OrgConcurrencyQuota int
+	// BatchConcurrencyQuota is the number of batch queries that are allowed to execute concurrently,
+	// so slots are left for interactive queries. Batch queries are not limited if it is zero.
+	BatchConcurrencyQuota int
+	// OrgQueueWeights are the weights of the shares of organizations in the admission of queued
+	// queries. An organization with a weight of 2 has twice as many of its queries admitted as one
+	// with a weight of 1 while both have queries queued. Organizations without a weight have a weight of 1.
+	OrgQueueWeights map[platform.ID]int
+	// FluxPackageService finds the Flux packages queries import. Queries can only import
+	// the standard library if it is nil.
+	FluxPackageService platform.FluxPackageService
 }
 
 func (c *Config) Validate() error {
@@ -96,7 +121,41 @@ func (c *Config) Validate() error {
 	if c.QueueSize <= 0 {
 		return errors.New("QueueSize must be positive")
 	}
-	return nil
+	if c.OrgConcurrencyQuota < 0 {
+		return errors.New("OrgConcurrencyQuota must not be negative")
+	}
+	if c.BatchConcurrencyQuota < 0 {
+		return errors.New("BatchConcurrencyQuota must not be negative")
+	}
+	for org, w := range c.OrgQueueWeights {
+		if w <= 0 {
+			return fmt.Errorf("OrgQueueWeights of organization %s must be positive", org)
+		}
+	}
+	return c.ResultCache.Validate()
+}
+
+// ParseOrgQueueWeights parses weights of organizations for OrgQueueWeights,
+// which are each written as the ID of the organization and its weight, such as
+// 0000000000000001=2.
+func ParseOrgQueueWeights(ss []string) (map[platform.ID]int, error) {
+	weights := make(map[platform.ID]int, len(ss))
+	for _, s := range ss {
+		i := strings.LastIndex(s, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid organization weight %q: expected orgID=weight", s)
+		}
+		id, err := platform.IDFromString(s[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid organization weight %q: %v", s, err)
+		}
+		w, err := strconv.Atoi(s[i+1:])
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("invalid organization weight %q: weight must be a positive integer", s)
+		}
+		weights[*id] = w
+	}
+	return weights, nil
 }
 
 type QueryID uint64
@@ -113,17 +172,29 @@ func New(c Config) (*Controller, error) {
 	logger.Info("Starting query controller",
 		zap.Int("concurrency_quota", c.ConcurrencyQuota),
 		zap.Int64("memory_bytes_quota_per_query", c.MemoryBytesQuotaPerQuery),
-		zap.Int("queue_size", c.QueueSize))
+		zap.Int("queue_size", c.QueueSize),
+		zap.Int("org_concurrency_quota", c.OrgConcurrencyQuota),
+		zap.Int("batch_concurrency_quota", c.BatchConcurrencyQuota),
+		zap.Int("org_queue_weights", len(c.OrgQueueWeights)))
+	metrics := newControllerMetrics(c.MetricLabelKeys)
 	ctrl := &Controller{
 		queries:                  make(map[QueryID]*Query),
-		queryQueue:               make(chan *Query, c.QueueSize),
+		queryQueue:               newQueryQueue(c.QueueSize, c.OrgConcurrencyQuota, c.BatchConcurrencyQuota, c.OrgQueueWeights, metrics),
 		done:                     make(chan struct{}),
 		abort:                    make(chan struct{}),
 		memoryBytesQuotaPerQuery: c.MemoryBytesQuotaPerQuery,
 		logger:                   logger,
-		metrics:                  newControllerMetrics(c.MetricLabelKeys),
+		metrics:                  metrics,
 		labelKeys:                c.MetricLabelKeys,
 		dependencies:             c.ExecutorDependencies,
+		fluxPackageService:       c.FluxPackageService,
+	}
+	if c.ResultCache.TTL > 0 {
+		logger.Info("Enabling query result cache",
+			zap.Duration("ttl", c.ResultCache.TTL),
+			zap.Int64("max_bytes", c.ResultCache.MaxBytes),
+			zap.Duration("now_precision", c.ResultCache.NowPrecision))
+		ctrl.cache = newResultCache(c.ResultCache)
 	}
 	ctrl.wg.Add(c.ConcurrencyQuota)
 	for i := 0; i < c.ConcurrencyQuota; i++ {
@@ -140,11 +211,25 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	var cacheKey string
+	if c.cache != nil {
+		if key, r, ok := c.cache.prepare(req); ok {
+			if q, ok := c.cache.get(key); ok {
+				return q, nil
+			}
+			cacheKey, req = key, r
+		}
+	}
+
 	// Set the request on the context so platform specific Flux operations can retrieve it later.
 	ctx = query.ContextWithRequest(ctx, req)
 	// Set the org label value for controller metrics
 	ctx = context.WithValue(ctx, orgLabel, req.OrganizationID.String())
-	q, err := c.query(ctx, req.Compiler)
+	compiler, err := c.fluxPackageCompiler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	q, err := c.query(ctx, compiler)
 	if err != nil {
 		// If the controller reports an error, it's usually because of a syntax error
 		// or other problem that the client must fix.
@@ -154,13 +239,51 @@ func (c *Controller) Query(ctx context.Context, req *query.Request) (flux.Query,
 		}
 	}
 
+	if cacheKey != "" {
+		return c.cache.track(cacheKey, q), nil
+	}
 	return q, nil
 }
 
+// fluxPackageCompiler returns the compiler of the request, wrapped so that the
+// query can import the Flux packages of its organization if it imports any.
+func (c *Controller) fluxPackageCompiler(ctx context.Context, req *query.Request) (flux.Compiler, error) {
+	if c.fluxPackageService == nil {
+		return req.Compiler, nil
+	}
+	paths := query.ImportedFluxPackages(req.Compiler)
+	if len(paths) == 0 {
+		return req.Compiler, nil
+	}
+
+	orgID := req.OrganizationID
+	pkgs, err := c.fluxPackageService.FindFluxPackages(ctx, platform.FluxPackageFilter{OrganizationID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	var imported []*platform.FluxPackage
+	for _, p := range pkgs {
+		for _, path := range paths {
+			if p.Name == path {
+				imported = append(imported, p)
+				break
+			}
+		}
+	}
+	if len(imported) == 0 {
+		// Flux reports the unknown imports.
+		return req.Compiler, nil
+	}
+	return query.FluxPackageCompiler{
+		Compiler: req.Compiler,
+		Packages: imported,
+	}, nil
+}
+
 // query submits a query for execution returning immediately.
 // Done must be called on any returned Query objects.
 func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Query, error) {
-	q, err := c.createQuery(ctx, compiler.CompilerType())
+	q, err := c.createQuery(ctx, compiler)
 	if err != nil {
 		return nil, err
 	}
@@ -180,7 +303,7 @@ func (c *Controller) query(ctx context.Context, compiler flux.Compiler) (flux.Qu
 	return q, nil
 }
 
-func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Query, error) {
+func (c *Controller) createQuery(ctx context.Context, compiler flux.Compiler) (*Query, error) {
 	c.queriesMu.RLock()
 	if c.shutdown {
 		c.queriesMu.RUnlock()
@@ -203,9 +326,40 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 		labelValues[i] = str
 		compileLabelValues[i] = str
 	}
-	compileLabelValues[len(compileLabelValues)-1] = string(ct)
+	compileLabelValues[len(compileLabelValues)-1] = string(compiler.CompilerType())
 
-	cctx, cancel := context.WithCancel(ctx)
+	memoryBytesQuota := c.memoryBytesQuotaPerQuery
+	var (
+		maxDuration time.Duration
+		orgID       platform.ID
+		userID      platform.ID
+		priority    = query.PriorityInteractive
+	)
+	if req := query.RequestFromContext(ctx); req != nil {
+		if req.MemoryBytesQuota > 0 && req.MemoryBytesQuota < memoryBytesQuota {
+			memoryBytesQuota = req.MemoryBytesQuota
+		}
+		maxDuration = req.MaxDuration
+		orgID = req.OrganizationID
+		if req.Authorization != nil {
+			userID = req.Authorization.UserID
+		}
+		if req.Priority != "" {
+			priority = req.Priority
+		}
+	}
+
+	var (
+		cctx   context.Context
+		cancel context.CancelFunc
+	)
+	if maxDuration > 0 {
+		// The timeout starts when the query is created, so time spent
+		// compiling and queueing counts against the limit too.
+		cctx, cancel = context.WithTimeout(ctx, maxDuration)
+	} else {
+		cctx, cancel = context.WithCancel(ctx)
+	}
 	parentSpan, parentCtx := StartSpanFromContext(
 		cctx,
 		"all",
@@ -223,6 +377,12 @@ func (c *Controller) createQuery(ctx context.Context, ct flux.CompilerType) (*Qu
 		parentSpan:         parentSpan,
 		cancel:             cancel,
 		doneCh:             make(chan struct{}),
+		alloc:              &memory.Allocator{Limit: &memoryBytesQuota},
+		memoryBytesQuota:   memoryBytesQuota,
+		orgID:              orgID,
+		userID:             userID,
+		priority:           priority,
+		text:               query.CompilerText(compiler),
 	}
 
 	// Lock the queries mutex for the rest of this method.
@@ -292,23 +452,17 @@ func (c *Controller) enqueueQuery(q *Query) error {
 		return errors.New("failed to transition query to queueing state")
 	}
 
-	select {
-	case c.queryQueue <- q:
-	default:
-		return errors.New("queue length exceeded")
-	}
-
-	return nil
+	return c.queryQueue.push(q)
 }
 
 func (c *Controller) processQueryQueue() {
 	for {
-		select {
-		case <-c.done:
+		q, ok := c.queryQueue.pop()
+		if !ok {
 			return
-		case q := <-c.queryQueue:
-			c.executeQuery(q)
 		}
+		c.executeQuery(q)
+		c.queryQueue.release(q)
 	}
 }
 
@@ -338,8 +492,6 @@ func (c *Controller) executeQuery(q *Query) {
 		return
 	}
 
-	q.alloc = new(memory.Allocator)
-	q.alloc.Limit = func(v int64) *int64 { return &v }(c.memoryBytesQuotaPerQuery)
 	exec, err := q.program.Start(ctx, q.alloc)
 	if err != nil {
 		q.addRuntimeError(err)
@@ -355,6 +507,7 @@ func (c *Controller) finish(q *Query) {
 	delete(c.queries, q.id)
 	if len(c.queries) == 0 && c.shutdown {
 		close(c.done)
+		c.queryQueue.close()
 	}
 	c.queriesMu.Unlock()
 }
@@ -410,7 +563,11 @@ func (c *Controller) Shutdown(ctx context.Context) error {
 
 // PrometheusCollectors satisifies the prom.PrometheusCollector interface.
 func (c *Controller) PrometheusCollectors() []prometheus.Collector {
-	return c.metrics.PrometheusCollectors()
+	collectors := c.metrics.PrometheusCollectors()
+	if c.cache != nil {
+		collectors = append(collectors, c.cache.PrometheusCollectors()...)
+	}
+	return collectors
 }
 
 // Query represents a single request.
@@ -440,6 +597,26 @@ type Query struct {
 	exec    flux.Query
 	results chan flux.Result
 	alloc   *memory.Allocator
+
+	// memoryBytesQuota is the memory limit of this query.
+	// It is the controller's per-query quota unless the request asked for less.
+	memoryBytesQuota int64
+
+	// orgID and priority determine when the query is admitted to execution.
+	orgID    platform.ID
+	priority query.Priority
+
+	// userID and text describe the query in the list of running queries.
+	userID platform.ID
+	text   string
+}
+
+// priorityLabelValues returns the metric label values of the query followed by its priority.
+func (q *Query) priorityLabelValues() []string {
+	lvs := make([]string, len(q.labelValues)+1)
+	copy(lvs, q.labelValues)
+	lvs[len(q.labelValues)] = string(q.priority)
+	return lvs
 }
 
 // ID reports an ephemeral unique ID for the query.
@@ -624,7 +801,7 @@ TRANSITION:
 
 	// Start a new span and set a new context.
 	var (
-		dur         *prometheus.HistogramVec
+		dur         *prom.HistogramVec
 		gauge       *prometheus.GaugeVec
 		labelValues = q.labelValues
 	)
@@ -886,6 +1063,6 @@ func (s *span) Finish() {
 	s.s.FinishWithOptions(opentracing.FinishOptions{
 		FinishTime: finish,
 	})
-	s.hist.Observe(s.Duration.Seconds())
+	prom.ObserveWithTraceID(s.hist, s.Duration.Seconds(), tracing.TraceID(s.s))
 	s.gauge.Dec()
 }
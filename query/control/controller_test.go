@@ -18,6 +18,7 @@ import (
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/stdlib/universe"
+	platform "github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/query"
 	"github.com/influxdata/influxdb/query/control"
 	"github.com/prometheus/client_golang/prometheus"
@@ -457,6 +458,116 @@ func TestController_LimitExceededError(t *testing.T) {
 	}
 }
 
+func TestController_RequestMemoryBytesQuota(t *testing.T) {
+	const memoryBytesQuota = 64
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			// Allocate less than the controller allows, but more than the request does.
+			pts := plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("allocating-from-test", &executetest.AllocatingFromProcedureSpec{
+						ByteCount: memoryBytesQuota + 1,
+					}),
+					plan.CreatePhysicalNode("yield", &universe.YieldProcedureSpec{Name: "_result"}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+				Resources: flux.ResourceManagement{
+					ConcurrencyQuota: 1,
+				},
+			}
+
+			ps := plantest.CreatePlanSpec(&pts)
+			prog := &lang.Program{
+				Logger:   zaptest.NewLogger(t),
+				PlanSpec: ps,
+			}
+
+			return prog, nil
+		},
+	}
+
+	req := makeRequest(compiler)
+	req.MemoryBytesQuota = memoryBytesQuota
+	q, err := ctrl.Query(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ri := flux.NewResultIteratorFromQuery(q)
+	defer ri.Release()
+	for ri.More() {
+		res := ri.Next()
+		err = res.Tables().Do(func(t flux.Table) error {
+			return nil
+		})
+		if err != nil {
+			break
+		}
+	}
+	ri.Release()
+
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if !strings.Contains(err.Error(), "memory") {
+		t.Fatalf("expected an error about memory limit exceeded, got %v", err)
+	}
+}
+
+func TestController_RequestMaxDuration(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					// Block well past the requested duration unless canceled.
+					t := time.NewTimer(10 * time.Second)
+					defer t.Stop()
+
+					select {
+					case <-t.C:
+						q.ResultsCh <- &executetest.Result{}
+					case <-ctx.Done():
+					}
+				},
+			}, nil
+		},
+	}
+
+	req := makeRequest(compiler)
+	req.MaxDuration = 10 * time.Millisecond
+	q, err := ctrl.Query(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	timer := time.NewTimer(5 * time.Second)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		t.Fatal("expected the query to be canceled after its max duration")
+	case _, ok := <-q.Results():
+		if ok {
+			t.Fatal("expected no results")
+		}
+	}
+	q.Done()
+}
+
 func TestController_CompilePanic(t *testing.T) {
 	ctrl, err := control.New(config)
 	if err != nil {
@@ -878,6 +989,66 @@ func TestController_DoneWithoutRead(t *testing.T) {
 	wg.Wait()
 }
 
+func TestController_RunningQueries(t *testing.T) {
+	ctrl, err := control.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(t, ctrl)
+
+	executing := make(chan struct{})
+	compiler := &mock.Compiler{
+		CompileFn: func(ctx context.Context) (flux.Program, error) {
+			return &mock.Program{
+				ExecuteFn: func(ctx context.Context, q *mock.Query, alloc *memory.Allocator) {
+					close(executing)
+					<-ctx.Done()
+				},
+			}, nil
+		},
+	}
+
+	req := makeRequest(compiler)
+	req.OrganizationID = 2
+	q, err := ctrl.Query(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-executing
+
+	orgID := platform.ID(2)
+	qs, err := ctrl.FindRunningQueries(context.Background(), platform.RunningQueryFilter{OrganizationID: &orgID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 1 {
+		t.Fatalf("got %d running queries, want 1", len(qs))
+	}
+	if qs[0].OrganizationID != orgID || qs[0].State != "executing" {
+		t.Errorf("unexpected running query %+v", qs[0])
+	}
+
+	otherOrgID := platform.ID(3)
+	if qs, err := ctrl.FindRunningQueries(context.Background(), platform.RunningQueryFilter{OrganizationID: &otherOrgID}); err != nil {
+		t.Fatal(err)
+	} else if len(qs) != 0 {
+		t.Errorf("got %d running queries of another organization, want 0", len(qs))
+	}
+
+	id := qs[0].ID
+	if err := ctrl.CancelRunningQuery(context.Background(), id); err != nil {
+		t.Fatal(err)
+	}
+	for range q.Results() {
+		// discard the results
+	}
+	q.Done()
+
+	if _, err := ctrl.FindRunningQueryByID(context.Background(), id); platform.ErrorCode(err) != platform.ENotFound {
+		t.Errorf("got error %v, want a not found error", err)
+	}
+}
+
 func shutdown(t *testing.T, ctrl *control.Controller) {
 	t.Helper()
 
@@ -893,3 +1064,19 @@ func makeRequest(c flux.Compiler) *query.Request {
 		Compiler: c,
 	}
 }
+
+func TestParseOrgQueueWeights(t *testing.T) {
+	weights, err := control.ParseOrgQueueWeights([]string{"0000000000000001=2", "0000000000000002=5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weights) != 2 || weights[1] != 2 || weights[2] != 5 {
+		t.Errorf("got weights %v", weights)
+	}
+
+	for _, s := range []string{"0000000000000001", "org=2", "0000000000000001=0", "0000000000000001=x"} {
+		if _, err := control.ParseOrgQueueWeights([]string{s}); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
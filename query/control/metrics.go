@@ -1,6 +1,9 @@
 package control
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"github.com/influxdata/influxdb/kit/prom"
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 // controllerMetrics holds metrics related to the query controller.
 type controllerMetrics struct {
@@ -12,10 +15,14 @@ type controllerMetrics struct {
 	queueing  *prometheus.GaugeVec
 	executing *prometheus.GaugeVec
 
-	allDur       *prometheus.HistogramVec
-	compilingDur *prometheus.HistogramVec
-	queueingDur  *prometheus.HistogramVec
-	executingDur *prometheus.HistogramVec
+	queueDepth   *prometheus.GaugeVec
+	queueWaitDur *prometheus.HistogramVec
+
+	// The durations keep the traces of queries as exemplars.
+	allDur       *prom.HistogramVec
+	compilingDur *prom.HistogramVec
+	queueingDur  *prom.HistogramVec
+	executingDur *prom.HistogramVec
 }
 
 type requestsLabel string
@@ -76,7 +83,22 @@ func newControllerMetrics(labels []string) *controllerMetrics {
 			Help:      "Number of queries actively executing",
 		}, labels),
 
-		allDur: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		queueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_depth",
+			Help:      "Number of queries waiting to be admitted to execution by priority",
+		}, append(labels, "priority")),
+
+		queueWaitDur: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "queue_wait_duration_seconds",
+			Help:      "Histogram of times queries waited to be admitted to execution by priority",
+			Buckets:   prometheus.ExponentialBuckets(1e-3, 5, 7),
+		}, append(labels, "priority")),
+
+		allDur: prom.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "all_duration_seconds",
@@ -84,7 +106,7 @@ func newControllerMetrics(labels []string) *controllerMetrics {
 			Buckets:   prometheus.ExponentialBuckets(1e-3, 5, 7),
 		}, labels),
 
-		compilingDur: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		compilingDur: prom.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "compiling_duration_seconds",
@@ -92,7 +114,7 @@ func newControllerMetrics(labels []string) *controllerMetrics {
 			Buckets:   prometheus.ExponentialBuckets(1e-3, 5, 7),
 		}, append(labels, "compiler_type")),
 
-		queueingDur: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		queueingDur: prom.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "queueing_duration_seconds",
@@ -100,7 +122,7 @@ func newControllerMetrics(labels []string) *controllerMetrics {
 			Buckets:   prometheus.ExponentialBuckets(1e-3, 5, 7),
 		}, labels),
 
-		executingDur: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		executingDur: prom.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: namespace,
 			Subsystem: subsystem,
 			Name:      "executing_duration_seconds",
@@ -121,6 +143,9 @@ func (cm *controllerMetrics) PrometheusCollectors() []prometheus.Collector {
 		cm.queueing,
 		cm.executing,
 
+		cm.queueDepth,
+		cm.queueWaitDur,
+
 		cm.allDur,
 		cm.compilingDur,
 		cm.queueingDur,
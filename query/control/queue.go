@@ -0,0 +1,236 @@
+package control
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+// priorities are the priorities of queries from the highest to the lowest.
+var priorities = []query.Priority{query.PriorityInteractive, query.PriorityBatch}
+
+// priorityIndex returns the index of p in priorities.
+func priorityIndex(p query.Priority) int {
+	if p == query.PriorityBatch {
+		return 1
+	}
+	return 0
+}
+
+var (
+	errQueueFull   = errors.New("queue length exceeded")
+	errQueueClosed = errors.New("query queue closed")
+)
+
+// queryQueue holds the queries that wait to be executed and admits them to
+// execution.
+//
+// Queries of a higher priority are admitted before the queries of a lower one.
+// Within a priority, the organizations with queued queries take turns in
+// proportion to their weights, and the queries of an organization are
+// admitted in the order they were queued, so a burst of queries from one
+// organization delays only the queries of that organization. A query is
+// skipped while its organization executes as many queries as its quota allows,
+// or while it is a batch query and the quota of batch queries is used up, so a
+// single organization can't take every slot of the controller.
+type queryQueue struct {
+	mu    sync.Mutex
+	ready *sync.Cond
+
+	queues  []*fairQueue // by priority index
+	seq     uint64
+	size    int
+	maxSize int
+	closed  bool
+
+	orgQuota   int                 // 0 means unlimited
+	batchQuota int                 // 0 means unlimited
+	weights    map[platform.ID]int // organizations without a weight have a weight of 1
+
+	executing      map[platform.ID]int // executing queries by organization
+	executingBatch int
+
+	metrics *controllerMetrics
+}
+
+// fairQueue holds the queued queries of a priority by organization.
+//
+// The organizations are served by stride scheduling: each one has a pass, the
+// organization with the lowest pass is served next, and its pass advances by
+// the inverse of its weight every time one of its queries is admitted.
+type fairQueue struct {
+	orgs map[platform.ID]*orgQueue
+	// vtime is the pass of the last organization that was served. An
+	// organization that starts queueing starts at it, so it can't make up for
+	// the time it wasn't queueing by taking every turn.
+	vtime float64
+}
+
+type orgQueue struct {
+	queries []queuedQuery
+	pass    float64
+}
+
+type queuedQuery struct {
+	*Query
+	// seq orders the queries of different organizations that have the same pass.
+	seq      uint64
+	queuedAt time.Time
+}
+
+func newQueryQueue(maxSize, orgQuota, batchQuota int, weights map[platform.ID]int, metrics *controllerMetrics) *queryQueue {
+	qq := &queryQueue{
+		queues:     make([]*fairQueue, len(priorities)),
+		maxSize:    maxSize,
+		orgQuota:   orgQuota,
+		batchQuota: batchQuota,
+		weights:    weights,
+		executing:  make(map[platform.ID]int),
+		metrics:    metrics,
+	}
+	for i := range qq.queues {
+		qq.queues[i] = &fairQueue{orgs: make(map[platform.ID]*orgQueue)}
+	}
+	qq.ready = sync.NewCond(&qq.mu)
+	return qq
+}
+
+// push queues the query, unless the queue is full.
+func (qq *queryQueue) push(q *Query) error {
+	qq.mu.Lock()
+	defer qq.mu.Unlock()
+
+	if qq.closed {
+		return errQueueClosed
+	}
+	if qq.size >= qq.maxSize {
+		return errQueueFull
+	}
+
+	fq := qq.queues[priorityIndex(q.priority)]
+	oq, ok := fq.orgs[q.orgID]
+	if !ok {
+		oq = &orgQueue{}
+		fq.orgs[q.orgID] = oq
+	}
+	if len(oq.queries) == 0 && oq.pass < fq.vtime {
+		oq.pass = fq.vtime
+	}
+	qq.seq++
+	oq.queries = append(oq.queries, queuedQuery{Query: q, seq: qq.seq, queuedAt: time.Now()})
+	qq.size++
+	qq.metrics.queueDepth.WithLabelValues(q.priorityLabelValues()...).Inc()
+
+	qq.ready.Signal()
+	return nil
+}
+
+// pop waits for a query that may be executed and removes it from the queue.
+// Once the query has been executed, release must be called with it.
+// It returns false once the queue is closed.
+func (qq *queryQueue) pop() (*Query, bool) {
+	qq.mu.Lock()
+	defer qq.mu.Unlock()
+
+	for {
+		if qq.closed {
+			return nil, false
+		}
+		if q := qq.admit(); q != nil {
+			return q, true
+		}
+		qq.ready.Wait()
+	}
+}
+
+// admit removes and returns the first query that may be executed, if any.
+// qq.mu must be held.
+func (qq *queryQueue) admit() *Query {
+	for i, fq := range qq.queues {
+		if priorities[i] == query.PriorityBatch && qq.batchQuota > 0 && qq.executingBatch >= qq.batchQuota {
+			continue
+		}
+
+		var (
+			next    *orgQueue
+			nextOrg platform.ID
+		)
+		for org, oq := range fq.orgs {
+			if len(oq.queries) == 0 {
+				// The pass of an organization that isn't queueing is only
+				// kept while it is ahead of the others.
+				if oq.pass <= fq.vtime {
+					delete(fq.orgs, org)
+				}
+				continue
+			}
+			if qq.orgQuota > 0 && qq.executing[org] >= qq.orgQuota {
+				continue
+			}
+			if next == nil || oq.pass < next.pass || (oq.pass == next.pass && oq.queries[0].seq < next.queries[0].seq) {
+				next, nextOrg = oq, org
+			}
+		}
+		if next == nil {
+			continue
+		}
+
+		qd := next.queries[0]
+		next.queries[0] = queuedQuery{}
+		next.queries = next.queries[1:]
+		fq.vtime = next.pass
+		next.pass += 1 / float64(qq.weight(nextOrg))
+		qq.size--
+
+		q := qd.Query
+		lvs := q.priorityLabelValues()
+		qq.metrics.queueDepth.WithLabelValues(lvs...).Dec()
+		qq.metrics.queueWaitDur.WithLabelValues(lvs...).Observe(time.Since(qd.queuedAt).Seconds())
+
+		qq.executing[q.orgID]++
+		if q.priority == query.PriorityBatch {
+			qq.executingBatch++
+		}
+		return q
+	}
+	return nil
+}
+
+// weight returns the weight of the share of an organization in the admission
+// of queries.
+func (qq *queryQueue) weight(org platform.ID) int {
+	if w, ok := qq.weights[org]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// release gives up the slot of a query returned by pop, so the queries
+// waiting for it may be admitted.
+func (qq *queryQueue) release(q *Query) {
+	qq.mu.Lock()
+	defer qq.mu.Unlock()
+
+	if n := qq.executing[q.orgID]; n > 1 {
+		qq.executing[q.orgID] = n - 1
+	} else {
+		delete(qq.executing, q.orgID)
+	}
+	if q.priority == query.PriorityBatch {
+		qq.executingBatch--
+	}
+	// A query that was skipped may be admitted now, whatever its priority
+	// and organization, so every waiting worker has to check.
+	qq.ready.Broadcast()
+}
+
+// close wakes the workers waiting in pop, which then return false.
+func (qq *queryQueue) close() {
+	qq.mu.Lock()
+	qq.closed = true
+	qq.mu.Unlock()
+	qq.ready.Broadcast()
+}
@@ -0,0 +1,266 @@
+package control
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func newTestQueryQueue(maxSize, orgQuota, batchQuota int) *queryQueue {
+	return newQueryQueue(maxSize, orgQuota, batchQuota, nil, newControllerMetrics([]string{orgLabel}))
+}
+
+func newTestQueuedQuery(id QueryID, orgID platform.ID, priority query.Priority) *Query {
+	return &Query{
+		id:          id,
+		labelValues: []string{orgID.String()},
+		orgID:       orgID,
+		priority:    priority,
+	}
+}
+
+// popNow pops a query if one may be executed right away.
+func popNow(t *testing.T, qq *queryQueue) *Query {
+	t.Helper()
+
+	qq.mu.Lock()
+	defer qq.mu.Unlock()
+	return qq.admit()
+}
+
+func TestQueryQueue_priority(t *testing.T) {
+	qq := newTestQueryQueue(10, 0, 0)
+	for _, q := range []*Query{
+		newTestQueuedQuery(1, 1, query.PriorityBatch),
+		newTestQueuedQuery(2, 1, query.PriorityInteractive),
+		newTestQueuedQuery(3, 2, query.PriorityBatch),
+		newTestQueuedQuery(4, 2, query.PriorityInteractive),
+	} {
+		if err := qq.push(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []QueryID{2, 4, 1, 3} {
+		q, ok := qq.pop()
+		if !ok {
+			t.Fatal("expected a query to be admitted")
+		}
+		if q.id != want {
+			t.Errorf("got query %d, want %d", q.id, want)
+		}
+	}
+	if q := popNow(t, qq); q != nil {
+		t.Errorf("expected the queue to be empty, got query %d", q.id)
+	}
+}
+
+func TestQueryQueue_orgQuota(t *testing.T) {
+	qq := newTestQueryQueue(10, 1, 0)
+	for _, q := range []*Query{
+		newTestQueuedQuery(1, 1, query.PriorityInteractive),
+		newTestQueuedQuery(2, 1, query.PriorityInteractive),
+		newTestQueuedQuery(3, 2, query.PriorityBatch),
+	} {
+		if err := qq.push(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first := popNow(t, qq)
+	if first == nil || first.id != 1 {
+		t.Fatalf("expected query 1 to be admitted, got %v", first)
+	}
+	// The second query of the organization waits for the first one, so the
+	// query of the other organization is admitted although it is a batch query.
+	if q := popNow(t, qq); q == nil || q.id != 3 {
+		t.Fatalf("expected query 3 to be admitted, got %v", q)
+	}
+	if q := popNow(t, qq); q != nil {
+		t.Fatalf("expected no query to be admitted while organization 1 is at its quota, got query %d", q.id)
+	}
+
+	qq.release(first)
+	if q := popNow(t, qq); q == nil || q.id != 2 {
+		t.Fatalf("expected query 2 to be admitted once query 1 finished, got %v", q)
+	}
+}
+
+func TestQueryQueue_batchQuota(t *testing.T) {
+	qq := newTestQueryQueue(10, 0, 1)
+	for _, q := range []*Query{
+		newTestQueuedQuery(1, 1, query.PriorityBatch),
+		newTestQueuedQuery(2, 2, query.PriorityBatch),
+		newTestQueuedQuery(3, 3, query.PriorityInteractive),
+	} {
+		if err := qq.push(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ids []QueryID
+	for q := popNow(t, qq); q != nil; q = popNow(t, qq) {
+		ids = append(ids, q.id)
+	}
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 1 {
+		t.Fatalf("expected queries 3 and 1 to be admitted, got %v", ids)
+	}
+}
+
+func TestQueryQueue_fairness(t *testing.T) {
+	qq := newTestQueryQueue(10, 0, 0)
+	// A burst of queries of organization 1 is queued before a query of organization 2.
+	for _, q := range []*Query{
+		newTestQueuedQuery(1, 1, query.PriorityInteractive),
+		newTestQueuedQuery(2, 1, query.PriorityInteractive),
+		newTestQueuedQuery(3, 1, query.PriorityInteractive),
+		newTestQueuedQuery(4, 1, query.PriorityInteractive),
+	} {
+		if err := qq.push(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if q := popNow(t, qq); q == nil || q.id != 1 {
+		t.Fatalf("expected query 1 to be admitted, got %v", q)
+	}
+	if err := qq.push(newTestQueuedQuery(5, 2, query.PriorityInteractive)); err != nil {
+		t.Fatal(err)
+	}
+	if err := qq.push(newTestQueuedQuery(6, 2, query.PriorityInteractive)); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []QueryID
+	for q := popNow(t, qq); q != nil; q = popNow(t, qq) {
+		ids = append(ids, q.id)
+	}
+	// The organizations take turns, so organization 2 doesn't wait for the burst.
+	want := []QueryID{5, 2, 6, 3, 4}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Fatalf("got queries %v admitted, want %v", ids, want)
+	}
+}
+
+func TestQueryQueue_weights(t *testing.T) {
+	qq := newQueryQueue(10, 0, 0, map[platform.ID]int{1: 2}, newControllerMetrics([]string{orgLabel}))
+	for id := QueryID(1); id <= 6; id++ {
+		org := platform.ID(1)
+		if id > 3 {
+			org = 2
+		}
+		if err := qq.push(newTestQueuedQuery(id, org, query.PriorityInteractive)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ids []QueryID
+	for q := popNow(t, qq); q != nil; q = popNow(t, qq) {
+		ids = append(ids, q.id)
+	}
+	// Organization 1 has twice the share of organization 2.
+	want := []QueryID{1, 4, 2, 3, 5, 6}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Fatalf("got queries %v admitted, want %v", ids, want)
+	}
+}
+
+func TestQueryQueue_idleOrg(t *testing.T) {
+	qq := newTestQueryQueue(10, 0, 0)
+	for id := QueryID(1); id <= 3; id++ {
+		if err := qq.push(newTestQueuedQuery(id, 1, query.PriorityInteractive)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for want := QueryID(1); want <= 3; want++ {
+		if q := popNow(t, qq); q == nil || q.id != want {
+			t.Fatalf("expected query %d to be admitted, got %v", want, q)
+		}
+	}
+
+	// Organization 2 was not queueing while organization 1 was served, so it
+	// can't take every turn once both are queueing.
+	for _, q := range []*Query{
+		newTestQueuedQuery(4, 2, query.PriorityInteractive),
+		newTestQueuedQuery(5, 2, query.PriorityInteractive),
+		newTestQueuedQuery(6, 2, query.PriorityInteractive),
+		newTestQueuedQuery(7, 1, query.PriorityInteractive),
+		newTestQueuedQuery(8, 1, query.PriorityInteractive),
+	} {
+		if err := qq.push(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var ids []QueryID
+	for q := popNow(t, qq); q != nil; q = popNow(t, qq) {
+		ids = append(ids, q.id)
+	}
+	want := []QueryID{4, 5, 7, 6, 8}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Fatalf("got queries %v admitted, want %v", ids, want)
+	}
+}
+
+func TestQueryQueue_full(t *testing.T) {
+	qq := newTestQueryQueue(1, 0, 0)
+	if err := qq.push(newTestQueuedQuery(1, 1, query.PriorityBatch)); err != nil {
+		t.Fatal(err)
+	}
+	if err := qq.push(newTestQueuedQuery(2, 1, query.PriorityInteractive)); err != errQueueFull {
+		t.Fatalf("got error %v, want %v", err, errQueueFull)
+	}
+
+	if got := queueDepth(t, qq.metrics, "0000000000000001", query.PriorityBatch); got != 1 {
+		t.Errorf("got queue depth %v, want 1", got)
+	}
+	popNow(t, qq)
+	if got := queueDepth(t, qq.metrics, "0000000000000001", query.PriorityBatch); got != 0 {
+		t.Errorf("got queue depth %v, want 0", got)
+	}
+
+	var metric dto.Metric
+	if err := qq.metrics.queueWaitDur.WithLabelValues("0000000000000001", string(query.PriorityBatch)).(prometheus.Histogram).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	if got := metric.GetHistogram().GetSampleCount(); got != 1 {
+		t.Errorf("got %d queue waits observed, want 1", got)
+	}
+}
+
+func TestQueryQueue_close(t *testing.T) {
+	qq := newTestQueryQueue(1, 0, 0)
+
+	done := make(chan bool)
+	go func() {
+		_, ok := qq.pop()
+		done <- ok
+	}()
+
+	qq.close()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("expected pop of a closed queue to return false")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected close to wake the waiting pop")
+	}
+
+	if err := qq.push(newTestQueuedQuery(1, 1, query.PriorityInteractive)); err != errQueueClosed {
+		t.Errorf("got error %v, want %v", err, errQueueClosed)
+	}
+}
+
+func queueDepth(t *testing.T, m *controllerMetrics, org string, priority query.Priority) float64 {
+	t.Helper()
+
+	var metric dto.Metric
+	if err := m.queueDepth.WithLabelValues(org, string(priority)).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetGauge().GetValue()
+}
@@ -0,0 +1,89 @@
+package control
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	platform "github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var _ platform.RunningQueryService = (*Controller)(nil)
+
+// runningQuery describes the query as it is at now.
+func (q *Query) runningQuery(now time.Time) *platform.RunningQuery {
+	createdAt := q.parentSpan.start
+	return &platform.RunningQuery{
+		ID:             platform.ID(q.id),
+		OrganizationID: q.orgID,
+		UserID:         q.userID,
+		Query:          q.text,
+		State:          q.State().String(),
+		Priority:       string(q.priority),
+		CreatedAt:      createdAt,
+		Duration:       now.Sub(createdAt),
+		MemoryBytes:    q.alloc.Allocated(),
+	}
+}
+
+// FindRunningQueryByID returns the query with the ID, unless it finished.
+func (c *Controller) FindRunningQueryByID(ctx context.Context, id platform.ID) (*platform.RunningQuery, error) {
+	q, err := c.findQuery(id)
+	if err != nil {
+		return nil, &platform.Error{
+			Op:  platform.OpFindRunningQueryByID,
+			Err: err,
+		}
+	}
+	return q.runningQuery(time.Now()), nil
+}
+
+// FindRunningQueries returns the queries that match the filter, oldest first.
+func (c *Controller) FindRunningQueries(ctx context.Context, filter platform.RunningQueryFilter) ([]*platform.RunningQuery, error) {
+	now := time.Now()
+	rqs := make([]*platform.RunningQuery, 0)
+	for _, q := range c.Queries() {
+		if filter.OrganizationID != nil && q.orgID != *filter.OrganizationID {
+			continue
+		}
+		if filter.UserID != nil && q.userID != *filter.UserID {
+			continue
+		}
+		rqs = append(rqs, q.runningQuery(now))
+	}
+
+	sort.Slice(rqs, func(i, j int) bool {
+		return rqs[i].ID < rqs[j].ID
+	})
+	return rqs, nil
+}
+
+// CancelRunningQuery cancels the query with the ID, unless it finished.
+func (c *Controller) CancelRunningQuery(ctx context.Context, id platform.ID) error {
+	q, err := c.findQuery(id)
+	if err != nil {
+		return &platform.Error{
+			Op:  platform.OpCancelRunningQuery,
+			Err: err,
+		}
+	}
+
+	c.logger.Info("Canceling running query", zap.Stringer("query_id", id), zap.Stringer("org_id", q.orgID))
+	q.Cancel()
+	return nil
+}
+
+// findQuery returns the query with the ID.
+func (c *Controller) findQuery(id platform.ID) (*Query, error) {
+	c.queriesMu.RLock()
+	q, ok := c.queries[QueryID(id)]
+	c.queriesMu.RUnlock()
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrRunningQueryNotFound,
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,147 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	platform "github.com/influxdata/influxdb"
+)
+
+// FluxPackageCompiler compiles a Flux query that imports Flux packages of its
+// organization as well as the standard library. The packages are evaluated
+// whenever the query is compiled, so a query imports their current source.
+type FluxPackageCompiler struct {
+	// Compiler is the Flux compiler of the query.
+	Compiler flux.Compiler
+
+	// Packages are the packages the query may import.
+	Packages []*platform.FluxPackage
+}
+
+// Compile evaluates the packages and then the query.
+func (c FluxPackageCompiler) Compile(ctx context.Context) (flux.Program, error) {
+	pkg, now, err := compilerAST(c.Compiler)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return c.Compiler.Compile(ctx)
+	}
+
+	importer := fluxPackageImporter{
+		Importer: flux.StdLib(),
+		pkgs:     make(map[string]*interpreter.Package, len(c.Packages)),
+	}
+	for _, p := range c.Packages {
+		ip, err := evalFluxPackage(p, now)
+		if err != nil {
+			return nil, err
+		}
+		importer.pkgs[p.Name] = ip
+	}
+
+	fs, err := specFromAST(pkg, now, importer)
+	if err != nil {
+		return nil, err
+	}
+	ps, err := plan.PlannerBuilder{}.Build().Plan(fs)
+	if err != nil {
+		return nil, err
+	}
+	return &lang.Program{PlanSpec: ps}, nil
+}
+
+// CompilerType returns the type of the compiler of the query.
+func (c FluxPackageCompiler) CompilerType() flux.CompilerType {
+	return c.Compiler.CompilerType()
+}
+
+// ImportedFluxPackages returns the paths the query of a compiler imports that
+// are not in the standard library, and so may be Flux packages of its organization.
+// Compilers that do not compile Flux, and queries that do not parse, import none.
+func ImportedFluxPackages(c flux.Compiler) []string {
+	pkg, _, err := compilerAST(c)
+	if err != nil || pkg == nil {
+		return nil
+	}
+
+	stdlib := flux.StdLib()
+	var paths []string
+	for _, f := range pkg.Files {
+		for _, imp := range f.Imports {
+			if imp.Path == nil {
+				continue
+			}
+			if _, ok := stdlib.Import(imp.Path.Value); !ok {
+				paths = append(paths, imp.Path.Value)
+			}
+		}
+	}
+	return paths
+}
+
+// evalFluxPackage evaluates the source of a package the way Flux evaluates
+// the packages of its standard library.
+func evalFluxPackage(p *platform.FluxPackage, now time.Time) (*interpreter.Package, error) {
+	invalid := func(err error) error {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("failed to evaluate flux package %q", p.Name),
+			Err:  err,
+		}
+	}
+
+	astPkg := parser.ParseSource(p.Source)
+	if ast.Check(astPkg) > 0 {
+		return nil, invalid(ast.GetError(astPkg))
+	}
+	semPkg, err := semantic.New(astPkg)
+	if err != nil {
+		return nil, invalid(err)
+	}
+
+	pkg := interpreter.NewPackage(astPkg.Package)
+	scope := flux.Prelude()
+	scope.Set("now", nowFunc(now))
+	if _, err := interpreter.NewInterpreter().Eval(semPkg, scope.Nest(pkg), flux.StdLib()); err != nil {
+		return nil, invalid(err)
+	}
+	return pkg, nil
+}
+
+// fluxPackageImporter imports the standard library and Flux packages of an
+// organization. The standard library takes precedence.
+type fluxPackageImporter struct {
+	interpreter.Importer
+	pkgs map[string]*interpreter.Package
+}
+
+func (imp fluxPackageImporter) Import(path string) (semantic.PackageType, bool) {
+	if t, ok := imp.Importer.Import(path); ok {
+		return t, true
+	}
+	p, ok := imp.pkgs[path]
+	if !ok {
+		return semantic.PackageType{}, false
+	}
+	return semantic.PackageType{
+		Name: p.Name(),
+		Type: p.PolyType(),
+	}, true
+}
+
+func (imp fluxPackageImporter) ImportPackageObject(path string) (*interpreter.Package, bool) {
+	if p, ok := imp.Importer.ImportPackageObject(path); ok {
+		return p, true
+	}
+	p, ok := imp.pkgs[path]
+	return p, ok
+}
@@ -0,0 +1,88 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/plan"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+func TestFluxPackageCompiler(t *testing.T) {
+	alerts := &platform.FluxPackage{
+		OrganizationID: 1,
+		Name:           "acme/alerts",
+		Source: `package alerts
+
+measurement = "cpu"
+critical = (tables=<-) => tables |> filter(fn: (r) => r._measurement == measurement)`,
+	}
+
+	c := lang.FluxCompiler{
+		Query: `import "acme/alerts"
+
+from(bucket: "my-bucket")
+	|> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)
+	|> alerts.critical()`,
+	}
+	if got, want := query.ImportedFluxPackages(c), []string{"acme/alerts"}; !cmp.Equal(want, got) {
+		t.Fatalf("unexpected imported packages -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	prog, err := query.FluxPackageCompiler{
+		Compiler: c,
+		Packages: []*platform.FluxPackage{alerts},
+	}.Compile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The filter of the package is pushed down to storage like any other.
+	roots := prog.(*lang.Program).PlanSpec.Roots
+	if len(roots) != 1 {
+		t.Fatalf("got %d roots, want 1", len(roots))
+	}
+	var read plan.Node
+	for root := range roots {
+		read = root.Predecessors()[0]
+	}
+	if read.Kind() != influxdb.ReadRangePhysKind {
+		t.Fatalf("got %s, want the read with the filter of the package", read.Kind())
+	}
+
+	t.Run("unknown package", func(t *testing.T) {
+		_, err := query.FluxPackageCompiler{
+			Compiler: lang.FluxCompiler{Query: `import "acme/other"` + "\n" + `from(bucket: "my-bucket") |> range(start: -1h)`},
+			Packages: []*platform.FluxPackage{alerts},
+		}.Compile(context.Background())
+		if err == nil {
+			t.Fatal("expected an error importing a package that does not exist")
+		}
+	})
+
+	t.Run("invalid package", func(t *testing.T) {
+		bad := &platform.FluxPackage{
+			OrganizationID: 1,
+			Name:           "acme/bad",
+			Source:         "package bad\nx = undefined()",
+		}
+		_, err := query.FluxPackageCompiler{
+			Compiler: lang.FluxCompiler{Query: `import "acme/bad"` + "\n" + `from(bucket: "my-bucket") |> range(start: -1h)`},
+			Packages: []*platform.FluxPackage{bad},
+		}.Compile(context.Background())
+		if code := platform.ErrorCode(err); code != platform.EInvalid {
+			t.Fatalf("got error code %q, want %q: %v", code, platform.EInvalid, err)
+		}
+	})
+
+	t.Run("standard library only", func(t *testing.T) {
+		c := lang.FluxCompiler{Query: `import "strings"` + "\n" + `from(bucket: "my-bucket") |> range(start: -1h)`}
+		if got := query.ImportedFluxPackages(c); len(got) != 0 {
+			t.Fatalf("got imported packages %v, want none", got)
+		}
+	})
+}
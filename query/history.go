@@ -0,0 +1,94 @@
+package query
+
+import (
+	"context"
+	"unicode/utf8"
+
+	platform "github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+// maxHistoryQueryLength is the length of the text of a query beyond which it
+// is truncated in the query history.
+const maxHistoryQueryLength = 16 << 10
+
+// HistoryLogger is a Logger that adds the logged queries to the query history.
+type HistoryLogger struct {
+	QueryHistoryService platform.QueryHistoryService
+	Logger              *zap.Logger
+}
+
+// NewHistoryLogger returns a Logger that adds the logged queries to the query history.
+func NewHistoryLogger(s platform.QueryHistoryService, logger *zap.Logger) *HistoryLogger {
+	return &HistoryLogger{
+		QueryHistoryService: s,
+		Logger:              logger,
+	}
+}
+
+// Log adds the query to the query history.
+func (l *HistoryLogger) Log(log Log) error {
+	e := historyEntry(log)
+	if err := l.QueryHistoryService.AddQueryHistoryEntry(context.Background(), e); err != nil {
+		l.Logger.Info("Failed to add query to the query history", zap.String("orgID", e.OrganizationID.String()), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// historyEntry returns the entry of the query history for the logged query.
+func historyEntry(log Log) *platform.QueryHistoryEntry {
+	e := &platform.QueryHistoryEntry{
+		OrganizationID: log.OrganizationID,
+		Outcome:        platform.QuerySucceeded,
+		CompletedAt:    log.Time,
+		Duration:       log.Statistics.TotalDuration,
+		BytesScanned:   scannedBytes(log.Statistics.Metadata[MetadataScannedBytes]),
+		ResponseBytes:  log.ResponseSize,
+		MemoryBytes:    log.Statistics.MaxAllocated,
+	}
+
+	if req := log.ProxyRequest; req != nil {
+		if auth := req.Request.Authorization; auth != nil {
+			e.UserID = auth.UserID
+		}
+		e.Query = truncateQuery(CompilerText(req.Request.Compiler), maxHistoryQueryLength)
+	}
+
+	if log.Error != nil {
+		e.Outcome = platform.QueryFailed
+		if log.Error == context.Canceled {
+			e.Outcome = platform.QueryCanceled
+		}
+		e.Error = log.Error.Error()
+	}
+	return e
+}
+
+// scannedBytes sums the bytes scanned by every source of a query.
+func scannedBytes(vs []interface{}) int64 {
+	var n int64
+	for _, v := range vs {
+		switch v := v.(type) {
+		case int:
+			n += int64(v)
+		case int64:
+			n += v
+		case float64:
+			// The metadata of remote queries is decoded from JSON.
+			n += int64(v)
+		}
+	}
+	return n
+}
+
+// truncateQuery cuts the text of a query to at most n bytes without splitting a character.
+func truncateQuery(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
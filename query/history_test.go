@@ -0,0 +1,107 @@
+package query_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	platformmock "github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHistoryLogger_Log(t *testing.T) {
+	completedAt := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	req := &query.ProxyRequest{
+		Request: query.Request{
+			Authorization:  &platform.Authorization{UserID: 3},
+			OrganizationID: orgID,
+			Compiler:       lang.FluxCompiler{Query: `from(bucket: "telegraf")`},
+		},
+	}
+	stats := flux.Statistics{
+		TotalDuration: 2 * time.Second,
+		MaxAllocated:  2048,
+		Metadata: flux.Metadata{
+			"influxdb/scanned-bytes": []interface{}{100, int64(20)},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		err         error
+		wantOutcome string
+	}{
+		{name: "success", wantOutcome: platform.QuerySucceeded},
+		{name: "error", err: errors.New("bad query"), wantOutcome: platform.QueryFailed},
+		{name: "canceled", err: context.Canceled, wantOutcome: platform.QueryCanceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *platform.QueryHistoryEntry
+			s := platformmock.NewQueryHistoryService()
+			s.AddQueryHistoryEntryFn = func(ctx context.Context, e *platform.QueryHistoryEntry) error {
+				got = e
+				return nil
+			}
+
+			l := query.NewHistoryLogger(s, zaptest.NewLogger(t))
+			if err := l.Log(query.Log{
+				Time:           completedAt,
+				OrganizationID: orgID,
+				Error:          tt.err,
+				ProxyRequest:   req,
+				ResponseSize:   10,
+				Statistics:     stats,
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			want := platform.QueryHistoryEntry{
+				OrganizationID: orgID,
+				UserID:         3,
+				Query:          `from(bucket: "telegraf")`,
+				Outcome:        tt.wantOutcome,
+				CompletedAt:    completedAt,
+				Duration:       2 * time.Second,
+				BytesScanned:   120,
+				ResponseBytes:  10,
+				MemoryBytes:    2048,
+			}
+			if tt.err != nil {
+				want.Error = tt.err.Error()
+			}
+			if got == nil || *got != want {
+				t.Fatalf("got entry %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestHistoryLogger_Log_truncatesQuery(t *testing.T) {
+	var got *platform.QueryHistoryEntry
+	s := platformmock.NewQueryHistoryService()
+	s.AddQueryHistoryEntryFn = func(ctx context.Context, e *platform.QueryHistoryEntry) error {
+		got = e
+		return nil
+	}
+
+	text := strings.Repeat("é", 10<<10)
+	l := query.NewHistoryLogger(s, zaptest.NewLogger(t))
+	if err := l.Log(query.Log{
+		OrganizationID: orgID,
+		ProxyRequest: &query.ProxyRequest{
+			Request: query.Request{Compiler: lang.FluxCompiler{Query: text}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Query) >= len(text) || !strings.HasPrefix(text, got.Query) {
+		t.Fatalf("expected the query to be truncated on a character boundary, got %d bytes", len(got.Query))
+	}
+}
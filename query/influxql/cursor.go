@@ -38,10 +38,14 @@ func createVarRefCursor(t *transpilerState, ref *influxql.VarRef) (cursor, error
 		return nil, errors.New("unimplemented: only one source is allowed")
 	}
 
-	// Only support a direct measurement. Subqueries are not supported yet.
-	mm, ok := t.stmt.Sources[0].(*influxql.Measurement)
-	if !ok {
-		return nil, errors.New("unimplemented: source must be a measurement")
+	var mm *influxql.Measurement
+	switch source := t.stmt.Sources[0].(type) {
+	case *influxql.Measurement:
+		mm = source
+	case *influxql.SubQuery:
+		return createSubQueryCursor(t, source, ref)
+	default:
+		return nil, errors.New("unimplemented: source must be a measurement or subquery")
 	}
 
 	// Create the from spec and add it to the list of operations.
@@ -13,7 +13,11 @@ import (
 )
 
 // MultiResultEncoder encodes results as InfluxQL JSON format.
-type MultiResultEncoder struct{}
+type MultiResultEncoder struct {
+	// TimeFormat is the format of the timestamps; they are RFC3339Nano
+	// strings by default, or integers of the epoch in the unit of the format.
+	TimeFormat TimeFormat
+}
 
 // Encode writes a collection of results to the influxdb 1.X http response format.
 // Expectations/Assumptions:
@@ -154,7 +158,7 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 						vs := cr.Times(idx)
 						for i := 0; i < vs.Len(); i++ {
 							if vs.IsValid(i) {
-								values[i][j] = execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano)
+								values[i][j] = e.formatTime(vs.Value(i))
 							}
 						}
 					default:
@@ -185,6 +189,29 @@ func (e *MultiResultEncoder) Encode(w io.Writer, results flux.ResultIterator) (i
 	err := json.NewEncoder(wc).Encode(resp)
 	return wc.Count(), err
 }
+
+// formatTime formats the timestamp ts, in nanoseconds, in the time format.
+func (e *MultiResultEncoder) formatTime(ts int64) interface{} {
+	var d time.Duration
+	switch e.TimeFormat {
+	case Hour:
+		d = time.Hour
+	case Minute:
+		d = time.Minute
+	case Second:
+		d = time.Second
+	case Millisecond:
+		d = time.Millisecond
+	case Microsecond:
+		d = time.Microsecond
+	case Nanosecond:
+		d = time.Nanosecond
+	default:
+		return execute.Time(ts).Time().Format(time.RFC3339Nano)
+	}
+	return ts / int64(d)
+}
+
 func NewMultiResultEncoder() *MultiResultEncoder {
 	return new(MultiResultEncoder)
 }
@@ -15,9 +15,10 @@ import (
 
 func TestMultiResultEncoder_Encode(t *testing.T) {
 	for _, tt := range []struct {
-		name string
-		in   flux.ResultIterator
-		out  string
+		name       string
+		in         flux.ResultIterator
+		timeFormat influxql.TimeFormat
+		out        string
 	}{
 		{
 			name: "Default",
@@ -40,6 +41,27 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 			),
 			out: `{"results":[{"statement_id":0,"series":[{"name":"m0","tags":{"host":"server01"},"columns":["time","value"],"values":[["2018-05-24T09:00:00Z",2]]}]}]}`,
 		},
+		{
+			name: "Epoch",
+			in: flux.NewSliceResultIterator(
+				[]flux.Result{&executetest.Result{
+					Nm: "0",
+					Tbls: []*executetest.Table{{
+						KeyCols: []string{"_measurement"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "value", Type: flux.TFloat},
+						},
+						Data: [][]interface{}{
+							{ts("2018-05-24T09:00:00Z"), "m0", float64(2)},
+						},
+					}},
+				}},
+			),
+			timeFormat: influxql.Millisecond,
+			out:        `{"results":[{"statement_id":0,"series":[{"name":"m0","columns":["time","value"],"values":[[1527152400000,2]]}]}]}`,
+		},
 		{
 			name: "No _time column",
 			in: flux.NewSliceResultIterator(
@@ -91,6 +113,7 @@ func TestMultiResultEncoder_Encode(t *testing.T) {
 
 			var buf bytes.Buffer
 			enc := influxql.NewMultiResultEncoder()
+			enc.TimeFormat = tt.timeFormat
 			n, err := enc.Encode(&buf, tt.in)
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
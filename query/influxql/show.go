@@ -0,0 +1,312 @@
+package influxql
+
+import (
+	"context"
+	"errors"
+	"math"
+	"regexp"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/influxql"
+)
+
+// underscorePrefix matches the columns reserved by the storage engine, such as the measurement and field.
+var underscorePrefix = regexp.MustCompile(`^_`)
+
+// metaCursor is a pseudo-cursor used to evaluate the condition of a meta query. Every variable
+// reference within the condition is a tag, except for _name which is the measurement name.
+type metaCursor struct{}
+
+func (metaCursor) Expr() ast.Expression {
+	panic("unimplemented")
+}
+
+func (metaCursor) Keys() []influxql.Expr {
+	panic("unimplemented")
+}
+
+func (metaCursor) Value(expr influxql.Expr) (string, bool) {
+	ref, ok := expr.(*influxql.VarRef)
+	if !ok {
+		return "", false
+	}
+	if ref.Val == "_name" {
+		return "_measurement", true
+	}
+	return ref.Val, true
+}
+
+// metaSource creates the expression that reads the series a meta query is evaluated against. This
+// reads the default retention policy of the database, filtered by the sources and the condition.
+func (t *transpilerState) metaSource(database string, sources influxql.Sources, condition influxql.Expr) (ast.Expression, error) {
+	// While the sources are measurements, they do not actually contain the database and we do not
+	// factor in retention policies. So we are always going to use the default retention policy when
+	// evaluating which bucket we are querying.
+	if database == "" {
+		if t.config.DefaultDatabase == "" {
+			return nil, errDatabaseNameRequired
+		}
+		database = t.config.DefaultDatabase
+	}
+
+	expr, err := t.from(&influxql.Measurement{Database: database})
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO(jsternberg): Read the range from the condition expression. 1.x doesn't actually do this so it isn't
+	// urgent to implement this functionality so we can use the default range.
+	expr = pipe(expr, "range", &ast.Property{
+		Key: &ast.Identifier{Name: "start"},
+		Value: &ast.DurationLiteral{
+			Values: []ast.Duration{{
+				Magnitude: -1,
+				Unit:      "h",
+			}},
+		},
+	})
+
+	// If we have a list of sources, filter the series to those measurements.
+	if len(sources) > 0 {
+		var filterExpr ast.Expression
+		for i := len(sources) - 1; i >= 0; i-- {
+			mm := sources[i].(*influxql.Measurement)
+			var cmp ast.Expression
+			if mm.Regex != nil {
+				cmp = &ast.BinaryExpression{
+					Operator: ast.RegexpMatchOperator,
+					Left: &ast.MemberExpression{
+						Object:   &ast.Identifier{Name: "r"},
+						Property: &ast.Identifier{Name: "_measurement"},
+					},
+					Right: &ast.RegexpLiteral{Value: mm.Regex.Val},
+				}
+			} else {
+				cmp = &ast.BinaryExpression{
+					Operator: ast.EqualOperator,
+					Left: &ast.MemberExpression{
+						Object:   &ast.Identifier{Name: "r"},
+						Property: &ast.Identifier{Name: "_measurement"},
+					},
+					Right: &ast.StringLiteral{Value: mm.Name},
+				}
+			}
+
+			if filterExpr == nil {
+				filterExpr = cmp
+				continue
+			}
+			filterExpr = &ast.LogicalExpression{
+				Operator: ast.OrOperator,
+				Left:     cmp,
+				Right:    filterExpr,
+			}
+		}
+		expr = filter(expr, filterExpr)
+	}
+
+	// Filter the series by the condition. The time range within the condition is ignored
+	// like it is by 1.x.
+	if condition != nil {
+		valuer := influxql.NowValuer{Now: t.config.Now}
+		cond, _, err := influxql.ConditionExpr(condition, &valuer)
+		if err != nil {
+			return nil, err
+		}
+		if cond != nil {
+			filterExpr, err := t.mapField(cond, metaCursor{})
+			if err != nil {
+				return nil, err
+			}
+			expr = filter(expr, filterExpr)
+		}
+	}
+	return expr, nil
+}
+
+func (t *transpilerState) transpileShowMeasurements(ctx context.Context, stmt *influxql.ShowMeasurementsStatement) (ast.Expression, error) {
+	var sources influxql.Sources
+	if stmt.Source != nil {
+		sources = influxql.Sources{stmt.Source}
+	}
+	expr, err := t.metaSource(stmt.Database, sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the distinct measurement names and return them within a single series
+	// named measurements.
+	expr = pipe(expr, "keep", &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray("_measurement"),
+	})
+	expr = pipe(expr, "group")
+	expr = pipe(expr, "distinct", &ast.Property{
+		Key:   &ast.Identifier{Name: "column"},
+		Value: &ast.StringLiteral{Value: "_measurement"},
+	})
+	expr = pipe(expr, "sort")
+	expr = limit(expr, stmt.Limit, stmt.Offset)
+	expr = pipe(expr, "set",
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "key"},
+			Value: &ast.StringLiteral{Value: "_measurement"},
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "value"},
+			Value: &ast.StringLiteral{Value: "measurements"},
+		},
+	)
+	expr = pipe(expr, "group",
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: stringArray("_measurement"),
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "mode"},
+			Value: &ast.StringLiteral{Value: "by"},
+		},
+	)
+	return rename(expr, "_value", "name"), nil
+}
+
+func (t *transpilerState) transpileShowTagKeys(ctx context.Context, stmt *influxql.ShowTagKeysStatement) (ast.Expression, error) {
+	if stmt.SLimit > 0 || stmt.SOffset > 0 {
+		return nil, errors.New("unimplemented: SLIMIT and SOFFSET")
+	}
+
+	expr, err := t.metaSource(stmt.Database, stmt.Sources, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the distinct keys of the series for each measurement. The columns reserved
+	// by the storage engine are not tags.
+	expr = pipe(expr, "keys")
+	expr = pipe(expr, "keep", &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray("_measurement", "_value"),
+	})
+	expr = pipe(expr, "distinct")
+	expr = filter(expr, &ast.BinaryExpression{
+		Operator: ast.NotRegexpMatchOperator,
+		Left: &ast.MemberExpression{
+			Object:   &ast.Identifier{Name: "r"},
+			Property: &ast.Identifier{Name: "_value"},
+		},
+		Right: &ast.RegexpLiteral{Value: underscorePrefix},
+	})
+	expr = pipe(expr, "sort")
+	expr = limit(expr, stmt.Limit, stmt.Offset)
+	return rename(expr, "_value", "tagKey"), nil
+}
+
+func (t *transpilerState) transpileShowFieldKeys(ctx context.Context, stmt *influxql.ShowFieldKeysStatement) (ast.Expression, error) {
+	expr, err := t.metaSource(stmt.Database, stmt.Sources, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the distinct fields for each measurement. The type of a field cannot be
+	// determined from within a query, so only the field keys are returned.
+	expr = pipe(expr, "keep", &ast.Property{
+		Key:   &ast.Identifier{Name: "columns"},
+		Value: stringArray("_measurement", "_field"),
+	})
+	expr = pipe(expr, "group",
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: stringArray("_measurement"),
+		},
+		&ast.Property{
+			Key:   &ast.Identifier{Name: "mode"},
+			Value: &ast.StringLiteral{Value: "by"},
+		},
+	)
+	expr = pipe(expr, "distinct", &ast.Property{
+		Key:   &ast.Identifier{Name: "column"},
+		Value: &ast.StringLiteral{Value: "_field"},
+	})
+	expr = pipe(expr, "sort")
+	expr = limit(expr, stmt.Limit, stmt.Offset)
+	return rename(expr, "_value", "fieldKey"), nil
+}
+
+// pipe pipes the expression into a call of the named function with the arguments.
+func pipe(expr ast.Expression, name string, args ...*ast.Property) ast.Expression {
+	call := &ast.CallExpression{
+		Callee: &ast.Identifier{Name: name},
+	}
+	if len(args) > 0 {
+		call.Arguments = []ast.Expression{
+			&ast.ObjectExpression{Properties: args},
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: expr,
+		Call:     call,
+	}
+}
+
+// filter pipes the expression into a filter with the body as the predicate.
+func filter(expr ast.Expression, body ast.Expression) ast.Expression {
+	return pipe(expr, "filter", &ast.Property{
+		Key: &ast.Identifier{Name: "fn"},
+		Value: &ast.FunctionExpression{
+			Params: []*ast.Property{{
+				Key: &ast.Identifier{Name: "r"},
+			}},
+			Body: body,
+		},
+	})
+}
+
+// limit pipes the expression into a limit when the limit or offset are set.
+func limit(expr ast.Expression, n, offset int) ast.Expression {
+	if n <= 0 && offset <= 0 {
+		return expr
+	}
+
+	args := make([]*ast.Property, 0, 2)
+	if n > 0 {
+		args = append(args, &ast.Property{
+			Key:   &ast.Identifier{Name: "n"},
+			Value: &ast.IntegerLiteral{Value: int64(n)},
+		})
+	} else {
+		// The limit function requires the number of rows, so use the
+		// largest possible number when only an offset is given.
+		args = append(args, &ast.Property{
+			Key:   &ast.Identifier{Name: "n"},
+			Value: &ast.IntegerLiteral{Value: math.MaxInt64},
+		})
+	}
+	if offset > 0 {
+		args = append(args, &ast.Property{
+			Key:   &ast.Identifier{Name: "offset"},
+			Value: &ast.IntegerLiteral{Value: int64(offset)},
+		})
+	}
+	return pipe(expr, "limit", args...)
+}
+
+// rename pipes the expression into a rename of the column.
+func rename(expr ast.Expression, from, to string) ast.Expression {
+	return pipe(expr, "rename", &ast.Property{
+		Key: &ast.Identifier{Name: "columns"},
+		Value: &ast.ObjectExpression{
+			Properties: []*ast.Property{{
+				Key:   &ast.Identifier{Name: from},
+				Value: &ast.StringLiteral{Value: to},
+			}},
+		},
+	})
+}
+
+func stringArray(values ...string) *ast.ArrayExpression {
+	elements := make([]ast.Expression, 0, len(values))
+	for _, v := range values {
+		elements = append(elements, &ast.StringLiteral{Value: v})
+	}
+	return &ast.ArrayExpression{Elements: elements}
+}
@@ -0,0 +1,22 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW MEASUREMENTS ON "db0"`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> keep(columns: ["_measurement"])
+	|> group()
+	|> distinct(column: "_measurement")
+	|> sort()
+	|> set(key: "_measurement", value: "measurements")
+	|> group(columns: ["_measurement"], mode: "by")
+	|> rename(columns: {_value: "name"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
@@ -0,0 +1,25 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SHOW MEASUREMENTS ON "db0" WITH MEASUREMENT =~ /cpu.*/ WHERE "host" = 'server01' LIMIT 10`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: -1h)
+	|> filter(fn: (r) => r._measurement =~ /cpu.*/)
+	|> filter(fn: (r) => r["host"] == "server01")
+	|> keep(columns: ["_measurement"])
+	|> group()
+	|> distinct(column: "_measurement")
+	|> sort()
+	|> limit(n: 10)
+	|> set(key: "_measurement", value: "measurements")
+	|> group(columns: ["_measurement"], mode: "by")
+	|> rename(columns: {_value: "name"})
+	|> yield(name: "0")
+`,
+		),
+	)
+}
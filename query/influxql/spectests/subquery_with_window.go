@@ -0,0 +1,30 @@
+package spectests
+
+func init() {
+	RegisterFixture(
+		NewFixture(
+			`SELECT max(mean) FROM (SELECT mean(value) FROM db0..cpu GROUP BY time(1m)) WHERE time >= now() - 10m GROUP BY time(5m)`,
+			`package main
+
+from(bucketID: "")
+	|> range(start: 2010-09-15T08:50:00Z, stop: 2010-09-15T09:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu" and r._field == "value")
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 1m)
+	|> mean()
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, mean: r._value}), mergeKey: true)
+	|> map(fn: (r) => ({_time: r._time, _value: r["mean"]}), mergeKey: true)
+	|> group(columns: ["_measurement", "_start"], mode: "by")
+	|> window(every: 5m)
+	|> max()
+	|> drop(columns: ["_time"])
+	|> duplicate(column: "_start", as: "_time")
+	|> window(every: inf)
+	|> map(fn: (r) => ({_time: r._time, max: r._value}), mergeKey: true)
+	|> yield(name: "0")
+`,
+		),
+	)
+}
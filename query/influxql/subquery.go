@@ -0,0 +1,142 @@
+package influxql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/influxql"
+)
+
+// createSubQueryCursor creates a cursor for a variable reference to one of the columns
+// produced by a subquery. The subquery is transpiled like any other select statement and
+// the referenced column is mapped to the value column so the outer query can use it like
+// it would use a field.
+func createSubQueryCursor(t *transpilerState, sub *influxql.SubQuery, ref *influxql.VarRef) (cursor, error) {
+	if len(sub.Statement.SortFields) > 0 && sub.Statement.TimeAscending() != t.stmt.TimeAscending() {
+		return nil, errors.New("subqueries must be ordered in the same direction as the query itself")
+	}
+
+	stmt := sub.Statement.Clone()
+	stmt.OmitTime = true
+
+	var column string
+	for i, name := range stmt.ColumnNames() {
+		if name != ref.Val {
+			continue
+		}
+		if f, ok := stmt.Fields[i].Expr.(*influxql.VarRef); ok && f.Val == "time" {
+			break
+		}
+		column = name
+		break
+	}
+	if column == "" {
+		// TODO: 1.x evaluates references to anything other than a column of
+		// the subquery as null.
+		return nil, fmt.Errorf("unimplemented: %q is not a column of the subquery", ref.Val)
+	}
+
+	// The time range of the outer query also limits the subquery.
+	valuer := influxql.NowValuer{Now: t.config.Now}
+	_, tr, err := influxql.ConditionExpr(t.stmt.Condition, &valuer)
+	if err != nil {
+		return nil, err
+	}
+	if !tr.Min.IsZero() {
+		stmt.Condition = andTimeCondition(stmt.Condition, influxql.GTE, tr.Min)
+	}
+	if !tr.Max.IsZero() {
+		stmt.Condition = andTimeCondition(stmt.Condition, influxql.LTE, tr.Max)
+	}
+
+	// Transpile the subquery using its own statement and restore the outer
+	// statement once it is done.
+	outer := t.stmt
+	cur, err := t.transpileSelect(context.TODO(), stmt)
+	t.stmt = outer
+	if err != nil {
+		return nil, err
+	}
+
+	var property ast.PropertyKey
+	if strings.HasPrefix(column, "_") {
+		property = &ast.Identifier{Name: column}
+	} else {
+		property = &ast.StringLiteral{Value: column}
+	}
+
+	expr := &ast.PipeExpression{
+		Argument: cur.Expr(),
+		Call: &ast.CallExpression{
+			Callee: &ast.Identifier{
+				Name: "map",
+			},
+			Arguments: []ast.Expression{
+				&ast.ObjectExpression{
+					Properties: []*ast.Property{
+						{
+							Key: &ast.Identifier{
+								Name: "fn",
+							},
+							Value: &ast.FunctionExpression{
+								Params: []*ast.Property{{
+									Key: &ast.Identifier{Name: "r"},
+								}},
+								Body: &ast.ObjectExpression{
+									Properties: []*ast.Property{
+										{
+											Key: &ast.Identifier{Name: execute.DefaultTimeColLabel},
+											Value: &ast.MemberExpression{
+												Object:   &ast.Identifier{Name: "r"},
+												Property: &ast.Identifier{Name: execute.DefaultTimeColLabel},
+											},
+										},
+										{
+											Key: &ast.Identifier{Name: execute.DefaultValueColLabel},
+											Value: &ast.MemberExpression{
+												Object:   &ast.Identifier{Name: "r"},
+												Property: property,
+											},
+										},
+									},
+								},
+							},
+						},
+						{
+							Key: &ast.Identifier{
+								Name: "mergeKey",
+							},
+							Value: &ast.BooleanLiteral{Value: true},
+						},
+					},
+				},
+			},
+		},
+	}
+	return &varRefCursor{
+		expr: expr,
+		ref:  ref,
+	}, nil
+}
+
+// andTimeCondition adds the comparison of the time with the timestamp to the condition.
+func andTimeCondition(cond influxql.Expr, op influxql.Token, ts time.Time) influxql.Expr {
+	expr := &influxql.BinaryExpr{
+		Op:  op,
+		LHS: &influxql.VarRef{Val: "time"},
+		RHS: &influxql.TimeLiteral{Val: ts},
+	}
+	if cond == nil {
+		return expr
+	}
+	return &influxql.BinaryExpr{
+		Op:  influxql.AND,
+		LHS: &influxql.ParenExpr{Expr: cond},
+		RHS: expr,
+	}
+}
@@ -118,8 +118,14 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 			return nil, err
 		}
 		return cur.Expr(), nil
+	case *influxql.ShowMeasurementsStatement:
+		return t.transpileShowMeasurements(ctx, stmt)
+	case *influxql.ShowTagKeysStatement:
+		return t.transpileShowTagKeys(ctx, stmt)
 	case *influxql.ShowTagValuesStatement:
 		return t.transpileShowTagValues(ctx, stmt)
+	case *influxql.ShowFieldKeysStatement:
+		return t.transpileShowFieldKeys(ctx, stmt)
 	case *influxql.ShowDatabasesStatement:
 		return t.transpileShowDatabases(ctx, stmt)
 	case *influxql.ShowRetentionPoliciesStatement:
@@ -130,113 +136,11 @@ func (t *transpilerState) transpile(ctx context.Context, s influxql.Statement) (
 }
 
 func (t *transpilerState) transpileShowTagValues(ctx context.Context, stmt *influxql.ShowTagValuesStatement) (ast.Expression, error) {
-	// While the ShowTagValuesStatement contains a sources section and those sources are measurements, they do
-	// not actually contain the database and we do not factor in retention policies. So we are always going to use
-	// the default retention policy when evaluating which bucket we are querying and we do not have to consult
-	// the sources in the statement.
-	if stmt.Database == "" {
-		if t.config.DefaultDatabase == "" {
-			return nil, errDatabaseNameRequired
-		}
-		stmt.Database = t.config.DefaultDatabase
-	}
-
-	expr, err := t.from(&influxql.Measurement{Database: stmt.Database})
+	expr, err := t.metaSource(stmt.Database, stmt.Sources, stmt.Condition)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO(jsternberg): Read the range from the condition expression. 1.x doesn't actually do this so it isn't
-	// urgent to implement this functionality so we can use the default range.
-	expr = &ast.PipeExpression{
-		Argument: expr,
-		Call: &ast.CallExpression{
-			Callee: &ast.Identifier{
-				Name: "range",
-			},
-			Arguments: []ast.Expression{
-				&ast.ObjectExpression{
-					Properties: []*ast.Property{
-						{
-							Key: &ast.Identifier{
-								Name: "start",
-							},
-							Value: &ast.DurationLiteral{
-								Values: []ast.Duration{{
-									Magnitude: -1,
-									Unit:      "h",
-								}},
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// If we have a list of sources, look through it and add each of the measurement names.
-	measurementNames := make([]string, 0, len(stmt.Sources))
-	for _, source := range stmt.Sources {
-		mm := source.(*influxql.Measurement)
-		measurementNames = append(measurementNames, mm.Name)
-	}
-
-	if len(measurementNames) > 0 {
-		var filterExpr ast.Expression = &ast.BinaryExpression{
-			Operator: ast.EqualOperator,
-			Left: &ast.MemberExpression{
-				Object:   &ast.Identifier{Name: "r"},
-				Property: &ast.Identifier{Name: "_measurement"},
-			},
-			Right: &ast.StringLiteral{
-				Value: measurementNames[len(measurementNames)-1],
-			},
-		}
-		for i := len(measurementNames) - 2; i >= 0; i-- {
-			filterExpr = &ast.LogicalExpression{
-				Operator: ast.OrOperator,
-				Left: &ast.BinaryExpression{
-					Operator: ast.EqualOperator,
-					Left: &ast.MemberExpression{
-						Object:   &ast.Identifier{Name: "r"},
-						Property: &ast.Identifier{Name: "_measurement"},
-					},
-					Right: &ast.StringLiteral{
-						Value: measurementNames[i],
-					},
-				},
-				Right: filterExpr,
-			}
-		}
-		expr = &ast.PipeExpression{
-			Argument: expr,
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{
-					Name: "filter",
-				},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: []*ast.Property{
-							{
-								Key: &ast.Identifier{Name: "fn"},
-								Value: &ast.FunctionExpression{
-									Params: []*ast.Property{
-										{
-											Key: &ast.Identifier{Name: "r"},
-										},
-									},
-									Body: filterExpr,
-								},
-							},
-						},
-					},
-				},
-			},
-		}
-	}
-
-	// TODO(jsternberg): Add the condition filter for the where clause.
-
 	// Create the key values op spec from the
 	var keyColumns []ast.Expression
 	switch expr := stmt.TagKeyExpr.(type) {
@@ -0,0 +1,41 @@
+// Package ndjson encodes the results of queries as newline delimited JSON,
+// one JSON object per row, for clients that do not parse annotated CSV.
+package ndjson
+
+import (
+	"net/http"
+
+	"github.com/influxdata/flux"
+)
+
+const (
+	// DialectType is the type of the newline delimited JSON dialect.
+	DialectType = "ndjson"
+	// MediaType is the media type of newline delimited JSON.
+	MediaType = "application/x-ndjson"
+)
+
+// AddDialectMappings adds the newline delimited JSON dialect mappings.
+func AddDialectMappings(mappings flux.DialectMappings) error {
+	return mappings.Add(DialectType, func() flux.Dialect {
+		return new(Dialect)
+	})
+}
+
+// Dialect describes the results of a query encoded as newline delimited JSON.
+type Dialect struct{}
+
+// SetHeaders sets the content type of newline delimited JSON.
+func (d *Dialect) SetHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", MediaType)
+}
+
+// Encoder returns the encoder of newline delimited JSON.
+func (d *Dialect) Encoder() flux.MultiResultEncoder {
+	return NewMultiResultEncoder()
+}
+
+// DialectType returns the type of the newline delimited JSON dialect.
+func (d *Dialect) DialectType() flux.DialectType {
+	return DialectType
+}
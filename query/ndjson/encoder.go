@@ -0,0 +1,174 @@
+package ndjson
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/iocounter"
+	"github.com/influxdata/flux/values"
+)
+
+// NewMultiResultEncoder returns an encoder of the rows of results as newline
+// delimited JSON. An error of the query is written as a last line with an
+// error field.
+func NewMultiResultEncoder() flux.MultiResultEncoder {
+	return &flux.DelimitedMultiResultEncoder{
+		Encoder: new(ResultEncoder),
+	}
+}
+
+// ResultEncoder encodes every row of a result as a JSON object on its own
+// line, such as:
+//
+//	{"result":"_result","table":0,"group":{"host":"a"},"values":{"_time":"2019-05-01T00:00:00Z","host":"a","_value":1.5}}
+//
+// The group holds the columns of the group key of the table of the row and
+// the values hold every column of the row. Times are RFC3339 strings, and
+// nulls and floats that JSON can not represent, such as NaN, are null.
+type ResultEncoder struct{}
+
+// Encode writes the rows of the result to w.
+func (e *ResultEncoder) Encode(w io.Writer, result flux.Result) (int64, error) {
+	wc := &iocounter.Writer{Writer: w}
+	prefix := append(append([]byte(`{"result":`), jsonString(result.Name())...), `,"table":`...)
+
+	var id int64
+	err := result.Tables().Do(func(tbl flux.Table) error {
+		line := append(strconv.AppendInt(append([]byte(nil), prefix...), id, 10), `,"group":`...)
+		line = appendGroupKey(line, tbl.Key())
+		line = append(line, `,"values":{`...)
+		id++
+
+		cols := tbl.Cols()
+		names := make([][]byte, len(cols))
+		for j, c := range cols {
+			names[j] = append(jsonString(c.Label), ':')
+		}
+
+		var buf []byte
+		return tbl.Do(func(cr flux.ColReader) error {
+			buf = buf[:0]
+			for i := 0; i < cr.Len(); i++ {
+				buf = append(buf, line...)
+				for j, c := range cols {
+					if j > 0 {
+						buf = append(buf, ',')
+					}
+					buf = append(buf, names[j]...)
+					buf = appendValue(buf, cr, c.Type, j, i)
+				}
+				buf = append(buf, "}}\n"...)
+			}
+			if _, err := wc.Write(buf); err != nil {
+				return encoderError{err}
+			}
+			return nil
+		})
+	})
+	return wc.Count(), err
+}
+
+// EncodeError writes the error as a line with an error field.
+func (e *ResultEncoder) EncodeError(w io.Writer, err error) error {
+	line := append(append([]byte(`{"error":`), jsonString(err.Error())...), "}\n"...)
+	_, werr := w.Write(line)
+	return werr
+}
+
+// appendGroupKey appends the columns of a group key as a JSON object.
+func appendGroupKey(buf []byte, key flux.GroupKey) []byte {
+	buf = append(buf, '{')
+	for j, c := range key.Cols() {
+		if j > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, jsonString(c.Label)...)
+		buf = append(buf, ':')
+		if key.IsNull(j) {
+			buf = append(buf, "null"...)
+			continue
+		}
+		switch c.Type {
+		case flux.TBool:
+			buf = strconv.AppendBool(buf, key.ValueBool(j))
+		case flux.TInt:
+			buf = strconv.AppendInt(buf, key.ValueInt(j), 10)
+		case flux.TUInt:
+			buf = strconv.AppendUint(buf, key.ValueUInt(j), 10)
+		case flux.TFloat:
+			buf = appendFloat(buf, key.ValueFloat(j))
+		case flux.TString:
+			buf = append(buf, jsonString(key.ValueString(j))...)
+		case flux.TTime:
+			buf = appendTime(buf, key.ValueTime(j))
+		default:
+			buf = append(buf, "null"...)
+		}
+	}
+	return append(buf, '}')
+}
+
+// appendValue appends the value of row i of column j of the reader.
+func appendValue(buf []byte, cr flux.ColReader, typ flux.ColType, j, i int) []byte {
+	switch typ {
+	case flux.TBool:
+		if vs := cr.Bools(j); vs.IsValid(i) {
+			return strconv.AppendBool(buf, vs.Value(i))
+		}
+	case flux.TInt:
+		if vs := cr.Ints(j); vs.IsValid(i) {
+			return strconv.AppendInt(buf, vs.Value(i), 10)
+		}
+	case flux.TUInt:
+		if vs := cr.UInts(j); vs.IsValid(i) {
+			return strconv.AppendUint(buf, vs.Value(i), 10)
+		}
+	case flux.TFloat:
+		if vs := cr.Floats(j); vs.IsValid(i) {
+			return appendFloat(buf, vs.Value(i))
+		}
+	case flux.TString:
+		if vs := cr.Strings(j); vs.IsValid(i) {
+			return append(buf, jsonString(vs.ValueString(i))...)
+		}
+	case flux.TTime:
+		if vs := cr.Times(j); vs.IsValid(i) {
+			return appendTime(buf, values.Time(vs.Value(i)))
+		}
+	}
+	return append(buf, "null"...)
+}
+
+func appendFloat(buf []byte, v float64) []byte {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return append(buf, "null"...)
+	}
+	return strconv.AppendFloat(buf, v, 'g', -1, 64)
+}
+
+func appendTime(buf []byte, t values.Time) []byte {
+	buf = append(buf, '"')
+	buf = t.Time().UTC().AppendFormat(buf, time.RFC3339Nano)
+	return append(buf, '"')
+}
+
+// jsonString returns s as a JSON string.
+func jsonString(s string) []byte {
+	// Marshaling a string never fails.
+	b, _ := json.Marshal(s)
+	return b
+}
+
+// encoderError is an error writing the results, which the encoder returns
+// rather than writing it as the error of the query.
+type encoderError struct {
+	error
+}
+
+func (e encoderError) IsEncoderError() bool {
+	return true
+}
@@ -0,0 +1,105 @@
+package ndjson_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/influxdb/query/ndjson"
+)
+
+func TestMultiResultEncoder_Encode(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{
+		&executetest.Result{
+			Nm: "_result",
+			Tbls: []*executetest.Table{
+				{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "ok", Type: flux.TBool},
+					},
+					Data: [][]interface{}{
+						{execute.Time(0), "a", 1.5, true},
+						{execute.Time(1e9), "a", nil, false},
+						{execute.Time(2e9), "a", math.NaN(), nil},
+					},
+				},
+				{
+					KeyCols: []string{"host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"b\"", int64(-2)},
+					},
+				},
+			},
+		},
+		&executetest.Result{
+			Nm: "other",
+			Tbls: []*executetest.Table{
+				{
+					ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TUInt}},
+					Data:    [][]interface{}{{uint64(3)}},
+				},
+			},
+		},
+	})
+
+	var buf bytes.Buffer
+	n, err := ndjson.NewMultiResultEncoder().Encode(&buf, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("unexpected count of bytes written -want/+got:\n\t- %d\n\t+ %d", buf.Len(), n)
+	}
+
+	want := `{"result":"_result","table":0,"group":{"host":"a"},"values":{"_time":"1970-01-01T00:00:00Z","host":"a","_value":1.5,"ok":true}}
+{"result":"_result","table":0,"group":{"host":"a"},"values":{"_time":"1970-01-01T00:00:01Z","host":"a","_value":null,"ok":false}}
+{"result":"_result","table":0,"group":{"host":"a"},"values":{"_time":"1970-01-01T00:00:02Z","host":"a","_value":null,"ok":null}}
+{"result":"_result","table":1,"group":{"host":"b\""},"values":{"host":"b\"","_value":-2}}
+{"result":"other","table":0,"group":{},"values":{"_value":3}}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected encoding -want/+got:\n%s\n%s", want, got)
+	}
+}
+
+func TestMultiResultEncoder_Encode_error(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{
+		&executetest.Result{
+			Nm: "_result",
+			Tbls: []*executetest.Table{
+				{
+					ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TInt}},
+					Data:    [][]interface{}{{int64(1)}},
+				},
+				{
+					ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TInt}},
+					Err:     errors.New("expected error"),
+				},
+			},
+		},
+	})
+
+	var buf bytes.Buffer
+	if _, err := ndjson.NewMultiResultEncoder().Encode(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"result":"_result","table":0,"group":{},"values":{"_value":1}}
+{"error":"expected error"}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected encoding -want/+got:\n%s\n%s", want, got)
+	}
+}
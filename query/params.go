@@ -0,0 +1,135 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+)
+
+// ParamsIdentifier is the identifier of the option the parameters of a query are declared as.
+const ParamsIdentifier = "params"
+
+// MaxParams is the number of parameters a query may have.
+const MaxParams = 100
+
+var paramNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParamsStatement returns the statement that declares the parameters of a query
+// as the params option, so a query can refer to them rather than having its
+// values concatenated into its text, for example:
+//
+//	from(bucket: "telegraf")
+//		|> range(start: params.start)
+//		|> filter(fn: (r) => r.host == params.host)
+//
+// The parameters are decoded from JSON. Numbers without a fraction become
+// integers, strings that are date-times or durations in Flux syntax become
+// times and durations, and arrays hold values of one kind. A string that was
+// converted may be converted back with string().
+func ParamsStatement(params map[string]interface{}) (ast.Statement, error) {
+	if len(params) > MaxParams {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("a query can have at most %d params", MaxParams),
+		}
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := make([]*ast.Property, 0, len(params))
+	for _, name := range names {
+		if !paramNameRE.MatchString(name) {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("param name %q is not a valid identifier", name),
+			}
+		}
+		v, err := paramLiteral(params[name], true)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("invalid param %q", name),
+				Err:  err,
+			}
+		}
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: name},
+			Value: v,
+		})
+	}
+
+	return &ast.OptionStatement{
+		Assignment: &ast.VariableAssignment{
+			ID:   &ast.Identifier{Name: ParamsIdentifier},
+			Init: &ast.ObjectExpression{Properties: properties},
+		},
+	}, nil
+}
+
+// paramLiteral returns the literal of a value decoded from JSON.
+func paramLiteral(v interface{}, allowArray bool) (ast.Expression, error) {
+	switch v := v.(type) {
+	case bool:
+		return &ast.BooleanLiteral{Value: v}, nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+			return &ast.IntegerLiteral{Value: int64(v)}, nil
+		}
+		return &ast.FloatLiteral{Value: v}, nil
+	case string:
+		if t, err := parser.ParseTime(v); err == nil {
+			return &ast.DateTimeLiteral{Value: t.Value}, nil
+		}
+		if d, err := parser.ParseSignedDuration(v); err == nil {
+			return &ast.DurationLiteral{Values: d.Values}, nil
+		}
+		return &ast.StringLiteral{Value: v}, nil
+	case []interface{}:
+		if !allowArray {
+			return nil, fmt.Errorf("arrays cannot be nested")
+		}
+		elements := make([]ast.Expression, 0, len(v))
+		for _, e := range v {
+			l, err := paramLiteral(e, false)
+			if err != nil {
+				return nil, err
+			}
+			if len(elements) > 0 && l.Type() != elements[0].Type() {
+				return nil, fmt.Errorf("array mixes %s and %s values", elements[0].Type(), l.Type())
+			}
+			elements = append(elements, l)
+		}
+		return &ast.ArrayExpression{Elements: elements}, nil
+	case map[string]interface{}:
+		return nil, fmt.Errorf("objects are not supported")
+	case nil:
+		return nil, fmt.Errorf("null is not a value")
+	default:
+		return nil, fmt.Errorf("values of type %T are not supported", v)
+	}
+}
+
+// WithParams returns a copy of the external declarations of a query with its
+// parameters declared in addition. The extern may be nil.
+func WithParams(extern *ast.File, params map[string]interface{}) (*ast.File, error) {
+	stmt, err := ParamsStatement(params)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &ast.File{}
+	if extern != nil {
+		f = extern.Copy().(*ast.File)
+	}
+	f.Body = append(f.Body, stmt)
+	return f, nil
+}
@@ -0,0 +1,81 @@
+package query_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestWithParams(t *testing.T) {
+	var params map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"start": "2019-10-16T10:00:00Z",
+		"every": "-7d",
+		"host": "server\"01",
+		"limit": 10,
+		"threshold": 0.5,
+		"verbose": true,
+		"regions": ["eu", "us"]
+	}`), &params); err != nil {
+		t.Fatal(err)
+	}
+
+	extern := parser.ParseSource(`threshold = 10`).Files[0]
+	f, err := query.WithParams(extern, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extern.Body) != 1 {
+		t.Fatalf("expected the extern not to be modified, got %d statements", len(extern.Body))
+	}
+
+	src := ast.Format(f)
+	for _, want := range []string{
+		`threshold = 10`,
+		`option params = {`,
+		`start: 2019-10-16T10:00:00Z`,
+		`host: "server\"01"`,
+		`limit: 10`,
+		`threshold: 0.5`,
+		`verbose: true`,
+		`regions: ["eu", "us"]`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("expected %q in the declarations:\n%s", want, src)
+		}
+	}
+
+	// The declarations must be valid Flux.
+	if pkg := parser.ParseSource(src); ast.Check(pkg) > 0 {
+		t.Errorf("declarations do not parse: %v", ast.GetError(pkg))
+	}
+}
+
+func TestParamsStatement_invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{name: "name is not an identifier", params: `{"start time": "-1h"}`},
+		{name: "null", params: `{"host": null}`},
+		{name: "object", params: `{"host": {"name": "a"}}`},
+		{name: "nested array", params: `{"hosts": [["a"]]}`},
+		{name: "mixed array", params: `{"hosts": ["a", 1]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.params), &params); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := query.ParamsStatement(params); platform.ErrorCode(err) != platform.EInvalid {
+				t.Fatalf("expected the params to be invalid, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+)
+
+// QueryPlan is how a query is executed: the logical plan of the query, and the
+// physical plan the logical plan is rewritten to. The reads from storage of the
+// physical plan show what of the query is pushed down to storage.
+type QueryPlan struct {
+	Logical  []*PlanNode `json:"logical"`
+	Physical []*PlanNode `json:"physical"`
+}
+
+// PlanNode is an operation of a plan. The nodes of a plan are ordered so that
+// every node follows its predecessors.
+type PlanNode struct {
+	ID           string   `json:"id"`
+	Kind         string   `json:"kind"`
+	Predecessors []string `json:"predecessors"`
+
+	// Bounds are the time bounds of the tables of the node,
+	// which are only known in the physical plan.
+	Bounds *PlanBounds `json:"bounds,omitempty"`
+
+	// StorageRead is set on the nodes that read from storage.
+	StorageRead *StorageRead `json:"storageRead,omitempty"`
+}
+
+// PlanBounds are the time bounds of the tables of a node of a plan.
+type PlanBounds struct {
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
+}
+
+// StorageRead describes a read from storage and what is pushed down to it.
+type StorageRead struct {
+	Bucket   string `json:"bucket,omitempty"`
+	BucketID string `json:"bucketID,omitempty"`
+
+	// Predicate is the filter pushed down to storage, if any.
+	Predicate string `json:"predicate,omitempty"`
+
+	// GroupMode and GroupKeys are set if grouping is pushed down to storage,
+	// and Aggregate if an aggregate of the groups is too.
+	GroupMode string   `json:"groupMode,omitempty"`
+	GroupKeys []string `json:"groupKeys,omitempty"`
+	Aggregate string   `json:"aggregate,omitempty"`
+
+	// Schema is "tagKeys" or "tagValues" if only the schema is read from storage,
+	// and TagKey the tag the values of which are read.
+	Schema string `json:"schema,omitempty"`
+	TagKey string `json:"tagKey,omitempty"`
+}
+
+// StorageReadSpec is a procedure spec that reads from storage.
+type StorageReadSpec interface {
+	StorageRead() *StorageRead
+}
+
+// PlanQuery compiles the query of a compiler into its logical and physical plan,
+// without executing it. Only Flux queries can be planned.
+func PlanQuery(ctx context.Context, c flux.Compiler) (*QueryPlan, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	pkg, now, err := compilerAST(c)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "only Flux queries can be planned",
+		}
+	}
+
+	fs, err := specFromAST(pkg, now, flux.StdLib())
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "failed to compile query",
+			Err:  err,
+		}
+	}
+
+	lp := plan.NewLogicalPlanner()
+	ip, err := lp.CreateInitialPlan(fs)
+	if err != nil {
+		return nil, err
+	}
+	ls, err := lp.Plan(ip)
+	if err != nil {
+		return nil, err
+	}
+	// The physical planner rewrites the nodes of the logical plan,
+	// so the logical plan is described before it is.
+	qp := &QueryPlan{Logical: describePlan(ls)}
+
+	ps, err := plan.NewPhysicalPlanner().Plan(ls)
+	if err != nil {
+		return nil, err
+	}
+	qp.Physical = describePlan(ps)
+	return qp, nil
+}
+
+func describePlan(s *plan.Spec) []*PlanNode {
+	var nodes []*PlanNode
+	// The walk only fails if the function does.
+	_ = s.BottomUpWalk(func(pn plan.Node) error {
+		n := &PlanNode{
+			ID:           string(pn.ID()),
+			Kind:         string(pn.Kind()),
+			Predecessors: []string{},
+		}
+		for _, pred := range pn.Predecessors() {
+			n.Predecessors = append(n.Predecessors, string(pred.ID()))
+		}
+		if b := pn.Bounds(); b != nil {
+			n.Bounds = &PlanBounds{
+				Start: b.Start.Time().UTC(),
+				Stop:  b.Stop.Time().UTC(),
+			}
+		}
+		if rs, ok := pn.ProcedureSpec().(StorageReadSpec); ok {
+			n.StorageRead = rs.StorageRead()
+		}
+		nodes = append(nodes, n)
+		return nil
+	})
+	return nodes
+}
+
+// compilerAST returns the package of the query of a Flux compiler, with its
+// external statements, and the time the query is compiled at.
+// The package is nil if the compiler does not compile Flux.
+func compilerAST(c flux.Compiler) (*ast.Package, time.Time, error) {
+	var (
+		pkg *ast.Package
+		now time.Time
+	)
+	switch c := c.(type) {
+	case lang.FluxCompiler:
+		var err error
+		if pkg, err = flux.Parse(c.Query); err != nil {
+			return nil, now, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "failed to parse query",
+				Err:  err,
+			}
+		}
+		if c.Extern != nil {
+			pkg.Files = append([]*ast.File{c.Extern}, pkg.Files...)
+		}
+		now = c.Now
+	case lang.ASTCompiler:
+		pkg, now = c.AST, c.Now
+	default:
+		return nil, now, nil
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return pkg, now, nil
+}
+
+// nowFunc returns the now function of a query compiled at now.
+func nowFunc(now time.Time) values.Function {
+	return values.NewFunction("now", semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+		Return: semantic.Time,
+	}), func(values.Object) (values.Value, error) {
+		return values.NewTime(values.ConvertTime(now)), nil
+	}, false)
+}
+
+// specFromAST evaluates the package into the spec of its operations
+// the same way Flux does before it plans a query, importing packages
+// with the importer.
+func specFromAST(pkg *ast.Package, now time.Time, importer interpreter.Importer) (*flux.Spec, error) {
+	semPkg, err := semantic.New(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := flux.Prelude()
+	scope.Set("now", nowFunc(now))
+	sideEffects, err := interpreter.NewInterpreter().Eval(semPkg, scope, importer)
+	if err != nil {
+		return nil, err
+	}
+
+	// The query may have set the now option itself.
+	if v, ok := scope.Lookup("now"); ok {
+		t, err := v.Function().Call(nil)
+		if err != nil {
+			return nil, err
+		}
+		now = t.Time().Time()
+	}
+
+	fs := &flux.Spec{Now: now}
+	ider := &tableObjectIDer{ids: make(map[*flux.TableObject]flux.OperationID)}
+	visited := make(map[*flux.TableObject]bool)
+	var objs []*flux.TableObject
+	for _, se := range sideEffects {
+		to, ok := se.Value.(*flux.TableObject)
+		if !ok {
+			continue
+		}
+		dup := false
+		for _, o := range objs {
+			if to.Equal(o) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			buildSpec(fs, to, ider, visited)
+			objs = append(objs, to)
+		}
+	}
+	if len(fs.Operations) == 0 {
+		return nil, errors.New("this Flux script returns no streaming data")
+	}
+	return fs, nil
+}
+
+// buildSpec adds the operation of the table object to the spec after the
+// operations of its parents.
+func buildSpec(fs *flux.Spec, to *flux.TableObject, ider *tableObjectIDer, visited map[*flux.TableObject]bool) {
+	to.Parents.Range(func(_ int, v values.Value) {
+		if p := v.(*flux.TableObject); !visited[p] {
+			buildSpec(fs, p, ider, visited)
+		}
+	})
+
+	id := ider.ID(to)
+	to.Parents.Range(func(_ int, v values.Value) {
+		fs.Edges = append(fs.Edges, flux.Edge{
+			Parent: ider.ID(v.(*flux.TableObject)),
+			Child:  id,
+		})
+	})
+
+	visited[to] = true
+	fs.Operations = append(fs.Operations, to.Operation(ider))
+}
+
+// tableObjectIDer assigns the operations of table objects the IDs Flux does,
+// such as range1.
+type tableObjectIDer struct {
+	next int
+	ids  map[*flux.TableObject]flux.OperationID
+}
+
+func (i *tableObjectIDer) ID(to *flux.TableObject) flux.OperationID {
+	id, ok := i.ids[to]
+	if !ok {
+		id = flux.OperationID(fmt.Sprintf("%s%d", to.Kind, i.next))
+		i.next++
+		i.ids[to] = id
+	}
+	return id
+}
@@ -0,0 +1,73 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb/query"
+	_ "github.com/influxdata/influxdb/query/builtin"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+func TestPlanQuery(t *testing.T) {
+	c := lang.FluxCompiler{
+		Query: `from(bucket: "my-bucket")
+	|> range(start: 2019-01-01T00:00:00Z, stop: 2019-01-02T00:00:00Z)
+	|> filter(fn: (r) => r._measurement == "cpu")`,
+	}
+	qp, err := query.PlanQuery(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ignoreBounds := cmpopts.IgnoreFields(query.PlanNode{}, "Bounds")
+	wantLogical := []*query.PlanNode{
+		{ID: "influxDBFrom0", Kind: influxdb.FromKind, Predecessors: []string{}},
+		{ID: "range1", Kind: "range", Predecessors: []string{"influxDBFrom0"}},
+		{ID: "filter2", Kind: "filter", Predecessors: []string{"range1"}},
+		{ID: "generated_yield", Kind: "generatedYield", Predecessors: []string{"filter2"}},
+	}
+	if !cmp.Equal(wantLogical, qp.Logical, ignoreBounds) {
+		t.Errorf("unexpected logical plan -want/+got:\n%s", cmp.Diff(wantLogical, qp.Logical, ignoreBounds))
+	}
+
+	// Both the range and the filter are pushed down to storage.
+	read := "merged_ReadRange_filter2"
+	wantPhysical := []*query.PlanNode{
+		{
+			ID:           read,
+			Kind:         influxdb.ReadRangePhysKind,
+			Predecessors: []string{},
+			StorageRead: &query.StorageRead{
+				Bucket:    "my-bucket",
+				Predicate: `r._measurement == "cpu"`,
+			},
+		},
+		{ID: "generated_yield", Kind: "generatedYield", Predecessors: []string{read}},
+	}
+	if !cmp.Equal(wantPhysical, qp.Physical, ignoreBounds) {
+		t.Fatalf("unexpected physical plan -want/+got:\n%s", cmp.Diff(wantPhysical, qp.Physical, ignoreBounds))
+	}
+	wantBounds := &query.PlanBounds{
+		Start: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+		Stop:  time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	if got := qp.Physical[0].Bounds; !cmp.Equal(wantBounds, got) {
+		t.Errorf("unexpected bounds of the read -want/+got:\n%s", cmp.Diff(wantBounds, got))
+	}
+}
+
+func TestPlanQuery_invalid(t *testing.T) {
+	for _, q := range []string{
+		`from(bucket: "my-bucket"`,
+		`x = 1`,
+	} {
+		if _, err := query.PlanQuery(context.Background(), lang.FluxCompiler{Query: q}); err == nil {
+			t.Errorf("expected query %q not to be planned", q)
+		}
+	}
+}
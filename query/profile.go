@@ -0,0 +1,295 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/memory"
+)
+
+// ProfileMode determines how the execution profile of a query is returned.
+type ProfileMode string
+
+const (
+	// ProfileTable returns the profile as an extra result named ProfileResultName.
+	ProfileTable ProfileMode = "table"
+	// ProfileTrailers returns the profile in the trailers of an HTTP response.
+	ProfileTrailers ProfileMode = "trailers"
+)
+
+// Valid returns true if m is a known profile mode or empty.
+func (m ProfileMode) Valid() bool {
+	switch m {
+	case "", ProfileTable, ProfileTrailers:
+		return true
+	}
+	return false
+}
+
+// ProfileResultName is the name of the result of the profile of a query.
+const ProfileResultName = "_profile"
+
+// Metadata keys of the statistics of a query that the profile of a query is made of.
+// Each source reading from storage adds an element to the values of the read keys.
+const (
+	MetadataReadDuration  = "influxdb/read-duration"
+	MetadataScannedBytes  = "influxdb/scanned-bytes"
+	MetadataScannedValues = "influxdb/scanned-values"
+	MetadataResultName    = "influxdb/result-name"
+	MetadataResultRows    = "influxdb/result-rows"
+)
+
+// Profile is the execution profile of a query: how long each part of it took
+// and how much data it processed.
+type Profile struct {
+	Operations []ProfileOperation `json:"operations"`
+	// MaxAllocated is the most memory the query had allocated at once, in bytes.
+	MaxAllocated int64 `json:"maxAllocated"`
+}
+
+// ProfileOperation is a part of the execution of a query.
+type ProfileOperation struct {
+	Name          string        `json:"name"`
+	Duration      time.Duration `json:"duration"`
+	Rows          int64         `json:"rows"`
+	ScannedBytes  int64         `json:"scannedBytes"`
+	ScannedValues int64         `json:"scannedValues"`
+}
+
+// NewProfile returns the profile of a query from its statistics.
+//
+// The phases of the query are profiled, as are the reads from storage and
+// the results; the transformations of a query are not profiled on their own.
+func NewProfile(stats flux.Statistics) *Profile {
+	p := &Profile{
+		Operations: []ProfileOperation{
+			{Name: "compile", Duration: stats.CompileDuration},
+			{Name: "queue", Duration: stats.QueueDuration + stats.RequeueDuration},
+			{Name: "plan", Duration: stats.PlanDuration},
+			{Name: "execute", Duration: stats.ExecuteDuration},
+		},
+		MaxAllocated: stats.MaxAllocated,
+	}
+
+	durs := stats.Metadata[MetadataReadDuration]
+	for i := range durs {
+		p.Operations = append(p.Operations, ProfileOperation{
+			Name:          fmt.Sprintf("read %d", i),
+			Duration:      time.Duration(metadataInt(durs, i)),
+			ScannedBytes:  metadataInt(stats.Metadata[MetadataScannedBytes], i),
+			ScannedValues: metadataInt(stats.Metadata[MetadataScannedValues], i),
+		})
+	}
+
+	names, rows := stats.Metadata[MetadataResultName], stats.Metadata[MetadataResultRows]
+	for i := range names {
+		name, _ := names[i].(string)
+		p.Operations = append(p.Operations, ProfileOperation{
+			Name: "result " + name,
+			Rows: metadataInt(rows, i),
+		})
+	}
+
+	p.Operations = append(p.Operations, ProfileOperation{Name: "total", Duration: stats.TotalDuration})
+	return p
+}
+
+// metadataInt returns element i of the values of a metadata key as an integer,
+// or 0 if there is no such integer.
+func metadataInt(vs []interface{}, i int) int64 {
+	if i >= len(vs) {
+		return 0
+	}
+	switch v := vs[i].(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case time.Duration:
+		return int64(v)
+	}
+	return 0
+}
+
+// Result returns the profile as a result with a table of a row per operation.
+// The max_allocated column is only set on the total row.
+func (p *Profile) Result() (flux.Result, error) {
+	b := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), &memory.Allocator{})
+	cols := []flux.ColMeta{
+		{Label: "operation", Type: flux.TString},
+		{Label: "duration_ns", Type: flux.TInt},
+		{Label: "rows", Type: flux.TInt},
+		{Label: "scanned_bytes", Type: flux.TInt},
+		{Label: "scanned_values", Type: flux.TInt},
+		{Label: "max_allocated", Type: flux.TInt},
+	}
+	for _, c := range cols {
+		if _, err := b.AddCol(c); err != nil {
+			return nil, err
+		}
+	}
+	for i, op := range p.Operations {
+		if err := b.AppendString(0, op.Name); err != nil {
+			return nil, err
+		}
+		for j, v := range []int64{int64(op.Duration), op.Rows, op.ScannedBytes, op.ScannedValues} {
+			if err := b.AppendInt(j+1, v); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if i == len(p.Operations)-1 {
+			err = b.AppendInt(5, p.MaxAllocated)
+		} else {
+			err = b.AppendNil(5)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	tbl, err := b.Table()
+	if err != nil {
+		return nil, err
+	}
+	return &profileResult{table: tbl}, nil
+}
+
+// profileResult is the result of a single table of the profile of a query.
+type profileResult struct {
+	table flux.Table
+}
+
+func (r *profileResult) Name() string {
+	return ProfileResultName
+}
+
+func (r *profileResult) Tables() flux.TableIterator {
+	return r
+}
+
+func (r *profileResult) Do(f func(flux.Table) error) error {
+	return f(r.table)
+}
+
+// profilingResultIterator counts the rows of the results of a query, and
+// adds the profile of the query as a last result if the mode is ProfileTable.
+type profilingResultIterator struct {
+	flux.ResultIterator
+	mode ProfileMode
+
+	names []string
+	rows  []*int64
+
+	profile  flux.Result
+	done     bool
+	released bool
+	err      error
+}
+
+// NewProfilingResultIterator returns a result iterator that profiles the results
+// of the iterator. Its statistics hold the rows of each result, see NewProfile.
+func NewProfilingResultIterator(results flux.ResultIterator, mode ProfileMode) flux.ResultIterator {
+	return &profilingResultIterator{ResultIterator: results, mode: mode}
+}
+
+func (i *profilingResultIterator) More() bool {
+	if i.done {
+		return false
+	}
+	if i.ResultIterator.More() {
+		return true
+	}
+	if i.mode != ProfileTable || i.ResultIterator.Err() != nil {
+		return false
+	}
+
+	// The statistics of a query are only complete once it is released,
+	// so the results are released before the profile is made.
+	i.release()
+	i.done = true
+	p, err := NewProfile(i.Statistics()).Result()
+	if err != nil {
+		i.err = err
+		return false
+	}
+	i.profile = p
+	return true
+}
+
+func (i *profilingResultIterator) Next() flux.Result {
+	if i.profile != nil {
+		p := i.profile
+		i.profile = nil
+		return p
+	}
+	r := i.ResultIterator.Next()
+	rows := new(int64)
+	i.names = append(i.names, r.Name())
+	i.rows = append(i.rows, rows)
+	return &countingResult{Result: r, rows: rows}
+}
+
+func (i *profilingResultIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.ResultIterator.Err()
+}
+
+func (i *profilingResultIterator) release() {
+	if !i.released {
+		i.released = true
+		i.ResultIterator.Release()
+	}
+}
+
+func (i *profilingResultIterator) Release() {
+	i.release()
+}
+
+func (i *profilingResultIterator) Statistics() flux.Statistics {
+	stats := i.ResultIterator.Statistics()
+	md := make(flux.Metadata)
+	md.AddAll(stats.Metadata)
+	for j, name := range i.names {
+		md.Add(MetadataResultName, name)
+		md.Add(MetadataResultRows, *i.rows[j])
+	}
+	stats.Metadata = md
+	return stats
+}
+
+// countingResult counts the rows of the tables of a result.
+type countingResult struct {
+	flux.Result
+	rows *int64
+}
+
+func (r *countingResult) Tables() flux.TableIterator {
+	return &countingTableIterator{TableIterator: r.Result.Tables(), rows: r.rows}
+}
+
+type countingTableIterator struct {
+	flux.TableIterator
+	rows *int64
+}
+
+func (ti *countingTableIterator) Do(f func(flux.Table) error) error {
+	return ti.TableIterator.Do(func(tbl flux.Table) error {
+		return f(&countingTable{Table: tbl, rows: ti.rows})
+	})
+}
+
+type countingTable struct {
+	flux.Table
+	rows *int64
+}
+
+func (t *countingTable) Do(f func(flux.ColReader) error) error {
+	return t.Table.Do(func(cr flux.ColReader) error {
+		*t.rows += int64(cr.Len())
+		return f(cr)
+	})
+}
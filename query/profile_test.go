@@ -0,0 +1,114 @@
+package query_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/influxdb/query"
+)
+
+func TestNewProfile(t *testing.T) {
+	stats := flux.Statistics{
+		TotalDuration:   10 * time.Second,
+		CompileDuration: time.Second,
+		QueueDuration:   2 * time.Second,
+		ExecuteDuration: 7 * time.Second,
+		MaxAllocated:    1024,
+		Metadata: flux.Metadata{
+			query.MetadataReadDuration:  []interface{}{3 * time.Second, time.Second},
+			query.MetadataScannedBytes:  []interface{}{100, 200},
+			query.MetadataScannedValues: []interface{}{10, 20},
+			query.MetadataResultName:    []interface{}{"_result"},
+			query.MetadataResultRows:    []interface{}{int64(5)},
+		},
+	}
+
+	want := &query.Profile{
+		Operations: []query.ProfileOperation{
+			{Name: "compile", Duration: time.Second},
+			{Name: "queue", Duration: 2 * time.Second},
+			{Name: "plan"},
+			{Name: "execute", Duration: 7 * time.Second},
+			{Name: "read 0", Duration: 3 * time.Second, ScannedBytes: 100, ScannedValues: 10},
+			{Name: "read 1", Duration: time.Second, ScannedBytes: 200, ScannedValues: 20},
+			{Name: "result _result", Rows: 5},
+			{Name: "total", Duration: 10 * time.Second},
+		},
+		MaxAllocated: 1024,
+	}
+	if got := query.NewProfile(stats); !cmp.Equal(want, got) {
+		t.Errorf("unexpected profile -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestProfilingResultIterator(t *testing.T) {
+	newResults := func() flux.ResultIterator {
+		r := executetest.NewResult([]*executetest.Table{
+			{
+				ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TInt}},
+				Data:    [][]interface{}{{int64(1)}, {int64(2)}},
+			},
+			{
+				ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TInt}},
+				Data:    [][]interface{}{{int64(3)}},
+			},
+		})
+		r.Nm = "_result"
+		return flux.NewSliceResultIterator([]flux.Result{r})
+	}
+
+	// consume reads the results and returns them.
+	consume := func(results flux.ResultIterator) []flux.Result {
+		var rs []flux.Result
+		for results.More() {
+			r := executetest.ConvertResult(results.Next())
+			rs = append(rs, r)
+		}
+		if err := results.Err(); err != nil {
+			t.Fatal(err)
+		}
+		results.Release()
+		return rs
+	}
+
+	t.Run("trailers", func(t *testing.T) {
+		results := query.NewProfilingResultIterator(newResults(), query.ProfileTrailers)
+		if rs := consume(results); len(rs) != 1 {
+			t.Fatalf("expected only the results of the query, got %d results", len(rs))
+		}
+		md := results.Statistics().Metadata
+		if got := md[query.MetadataResultRows]; len(got) != 1 || got[0] != int64(3) {
+			t.Errorf("unexpected rows of the results: %v", got)
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		results := query.NewProfilingResultIterator(newResults(), query.ProfileTable)
+		rs := consume(results)
+		if len(rs) != 2 {
+			t.Fatalf("expected the results of the query and the profile, got %d results", len(rs))
+		}
+		p := rs[1].(*executetest.Result)
+		if p.Name() != query.ProfileResultName {
+			t.Fatalf("unexpected name of the profile result: %q", p.Name())
+		}
+		if len(p.Tbls) != 1 {
+			t.Fatalf("expected a single profile table, got %d", len(p.Tbls))
+		}
+
+		var rows []string
+		for _, row := range p.Tbls[0].Data {
+			rows = append(rows, row[0].(string))
+		}
+		want := []string{"compile", "queue", "plan", "execute", "result _result", "total"}
+		if !cmp.Equal(want, rows) {
+			t.Errorf("unexpected operations -want/+got:\n%s", cmp.Diff(want, rows))
+		}
+		if got := p.Tbls[0].Data[4][2]; got != int64(3) {
+			t.Errorf("unexpected rows of the result: %v", got)
+		}
+	})
+}
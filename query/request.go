@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/lang"
 	platform "github.com/influxdata/influxdb"
 )
 
@@ -20,10 +23,46 @@ type Request struct {
 	// Compiler converts the query to a specification to run against the data.
 	Compiler flux.Compiler `json:"compiler"`
 
+	// Limits
+
+	// MemoryBytesQuota, when positive, lowers the memory quota of this query
+	// below the per-query quota of the controller.
+	MemoryBytesQuota int64 `json:"memory_bytes_quota,omitempty"`
+	// MaxDuration, when positive, cancels the query once it has been executing for that long.
+	MaxDuration time.Duration `json:"max_duration,omitempty"`
+
+	// Scheduling
+
+	// Priority determines the order the query is executed in when queries wait for
+	// execution. Queries without a priority are interactive.
+	Priority Priority `json:"priority,omitempty"`
+
 	// compilerMappings maps compiler types to creation methods
 	compilerMappings flux.CompilerMappings
 }
 
+// Priority is the class of a query, which determines the order queries waiting for
+// execution are executed in.
+type Priority string
+
+const (
+	// PriorityInteractive is the priority of queries someone waits for, such as the
+	// queries of dashboards. Interactive queries are executed before batch queries.
+	PriorityInteractive Priority = "interactive"
+	// PriorityBatch is the priority of queries that run in the background, such as
+	// the runs of tasks.
+	PriorityBatch Priority = "batch"
+)
+
+// Valid returns true if p is a known priority or empty.
+func (p Priority) Valid() bool {
+	switch p {
+	case "", PriorityInteractive, PriorityBatch:
+		return true
+	}
+	return false
+}
+
 // WithCompilerMappings sets the query type mappings on the request.
 func (r *Request) WithCompilerMappings(mappings flux.CompilerMappings) {
 	r.compilerMappings = mappings
@@ -89,6 +128,21 @@ func RequestFromContext(ctx context.Context) *Request {
 	return v.(*Request)
 }
 
+// CompilerText returns the text of the query the compiler compiles, if it has any.
+func CompilerText(compiler flux.Compiler) string {
+	switch c := compiler.(type) {
+	case lang.FluxCompiler:
+		return c.Query
+	case lang.ASTCompiler:
+		if c.AST != nil {
+			return ast.Format(c.AST)
+		}
+	case FluxPackageCompiler:
+		return CompilerText(c.Compiler)
+	}
+	return ""
+}
+
 // ProxyRequest specifies a query request and the dialect for the results.
 type ProxyRequest struct {
 	// Request is the basic query request
@@ -97,6 +151,9 @@ type ProxyRequest struct {
 	// Dialect is the result encoder
 	Dialect flux.Dialect `json:"dialect"`
 
+	// Profile, when set, returns the execution profile of the query.
+	Profile ProfileMode `json:"profile,omitempty"`
+
 	// dialectMappings maps dialect types to creation methods
 	dialectMappings flux.DialectMappings
 }
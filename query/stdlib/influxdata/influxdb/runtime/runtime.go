@@ -0,0 +1,46 @@
+// Package runtime exposes information about the server to Flux, so that queries
+// can branch on its capabilities:
+//
+//	import "influxdata/influxdb/runtime"
+//
+//	runtime.version()
+package runtime
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	platform "github.com/influxdata/influxdb"
+)
+
+// PackagePath is the path Flux queries import the package by.
+const PackagePath = "influxdata/influxdb/runtime"
+
+const source = `package runtime
+
+builtin version
+builtin commit
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+
+	flux.RegisterPackageValue(PackagePath, "version", buildInfoFunc("version", func(i platform.BuildInfo) string { return i.Version }))
+	flux.RegisterPackageValue(PackagePath, "commit", buildInfoFunc("commit", func(i platform.BuildInfo) string { return i.Commit }))
+}
+
+// buildInfoFunc returns a Flux function that returns a field of the build
+// information. The field is read when the function is called, since the build
+// information is set after the package is registered.
+func buildInfoFunc(name string, field func(platform.BuildInfo) string) values.Value {
+	ftype := semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+		Return: semantic.String,
+	})
+	call := func(args values.Object) (values.Value, error) {
+		return values.NewString(field(platform.GetBuildInfo())), nil
+	}
+	return values.NewFunction(name, ftype, call, false)
+}
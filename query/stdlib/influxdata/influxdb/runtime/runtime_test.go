@@ -0,0 +1,34 @@
+package runtime_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux"
+	_ "github.com/influxdata/flux/builtin"
+	platform "github.com/influxdata/influxdb"
+	_ "github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb/runtime"
+)
+
+func TestRuntime(t *testing.T) {
+	platform.SetBuildInfo("2.0.0-test", "abc123", "2019-01-01T00:00:00Z")
+
+	_, scope, err := flux.Eval(`
+import "influxdata/influxdb/runtime"
+
+v = runtime.version()
+c = runtime.commit()
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{"v": "2.0.0-test", "c": "abc123"} {
+		v, ok := scope.Lookup(name)
+		if !ok {
+			t.Fatalf("%s is not defined", name)
+		}
+		if got := v.Str(); got != want {
+			t.Errorf("unexpected %s: got %q, want %q", name, got, want)
+		}
+	}
+}
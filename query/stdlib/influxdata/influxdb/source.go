@@ -3,6 +3,7 @@ package influxdb
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
@@ -30,14 +31,17 @@ type Source struct {
 	id execute.DatasetID
 	ts []execute.Transformation
 
-	alloc *memory.Allocator
-	stats cursors.CursorStats
+	alloc    *memory.Allocator
+	stats    cursors.CursorStats
+	duration time.Duration
 
 	runner runner
 }
 
 func (s *Source) Run(ctx context.Context) {
+	start := time.Now()
 	err := s.runner.run(ctx)
+	s.duration = time.Since(start)
 	for _, t := range s.ts {
 		t.Finish(s.id, err)
 	}
@@ -49,8 +53,9 @@ func (s *Source) AddTransformation(t execute.Transformation) {
 
 func (s *Source) Metadata() flux.Metadata {
 	return flux.Metadata{
-		"influxdb/scanned-bytes":  []interface{}{s.stats.ScannedBytes},
-		"influxdb/scanned-values": []interface{}{s.stats.ScannedValues},
+		query.MetadataScannedBytes:  []interface{}{s.stats.ScannedBytes},
+		query.MetadataScannedValues: []interface{}{s.stats.ScannedValues},
+		query.MetadataReadDuration:  []interface{}{s.duration},
 	}
 }
 
@@ -0,0 +1,120 @@
+package influxdb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/influxdb/query"
+)
+
+// StorageRead implements query.StorageReadSpec.
+func (s *ReadRangePhysSpec) StorageRead() *query.StorageRead {
+	r := &query.StorageRead{
+		Bucket:   s.Bucket,
+		BucketID: s.BucketID,
+	}
+	if s.FilterSet {
+		r.Predicate = formatPredicate(s.Filter)
+	}
+	return r
+}
+
+// StorageRead implements query.StorageReadSpec.
+func (s *ReadGroupPhysSpec) StorageRead() *query.StorageRead {
+	r := s.ReadRangePhysSpec.StorageRead()
+	r.GroupMode = groupModeName(s.GroupMode)
+	r.GroupKeys = s.GroupKeys
+	r.Aggregate = s.AggregateMethod
+	return r
+}
+
+// StorageRead implements query.StorageReadSpec.
+func (s *ReadTagKeysPhysSpec) StorageRead() *query.StorageRead {
+	r := s.ReadRangePhysSpec.StorageRead()
+	r.Schema = "tagKeys"
+	return r
+}
+
+// StorageRead implements query.StorageReadSpec.
+func (s *ReadTagValuesPhysSpec) StorageRead() *query.StorageRead {
+	r := s.ReadRangePhysSpec.StorageRead()
+	r.Schema = "tagValues"
+	r.TagKey = s.TagKey
+	return r
+}
+
+func groupModeName(mode flux.GroupMode) string {
+	switch mode {
+	case flux.GroupModeBy:
+		return "by"
+	case flux.GroupModeExcept:
+		return "except"
+	default:
+		return ""
+	}
+}
+
+// formatPredicate formats the body of a filter pushed down to storage as Flux.
+// Only the expressions that can be pushed down are expected.
+func formatPredicate(fn *semantic.FunctionExpression) string {
+	if fn == nil || fn.Block == nil {
+		return ""
+	}
+	e, ok := fn.Block.Body.(semantic.Expression)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	formatExpression(&b, e)
+	return b.String()
+}
+
+func formatExpression(b *strings.Builder, e semantic.Expression) {
+	switch e := e.(type) {
+	case *semantic.LogicalExpression:
+		formatOperand(b, e.Left, false)
+		b.WriteString(" " + e.Operator.String() + " ")
+		formatOperand(b, e.Right, false)
+	case *semantic.BinaryExpression:
+		formatOperand(b, e.Left, true)
+		b.WriteString(" " + e.Operator.String() + " ")
+		formatOperand(b, e.Right, true)
+	case *semantic.UnaryExpression:
+		b.WriteString(e.Operator.String() + " ")
+		formatOperand(b, e.Argument, true)
+	case *semantic.MemberExpression:
+		formatExpression(b, e.Object)
+		b.WriteString("." + e.Property)
+	case *semantic.IdentifierExpression:
+		b.WriteString(e.Name)
+	case *semantic.StringLiteral:
+		b.WriteString(strconv.Quote(e.Value))
+	case *semantic.IntegerLiteral:
+		b.WriteString(strconv.FormatInt(e.Value, 10))
+	case *semantic.FloatLiteral:
+		b.WriteString(strconv.FormatFloat(e.Value, 'f', -1, 64))
+	case *semantic.BooleanLiteral:
+		b.WriteString(strconv.FormatBool(e.Value))
+	case *semantic.RegexpLiteral:
+		b.WriteString("/" + strings.Replace(e.Value.String(), "/", "\\/", -1) + "/")
+	default:
+		fmt.Fprintf(b, "<%s>", e.NodeType())
+	}
+}
+
+// formatOperand formats the operand of an operator, in parentheses if it is
+// a logical expression or, for the operands of other operators, a binary one.
+func formatOperand(b *strings.Builder, e semantic.Expression, parenBinary bool) {
+	_, logical := e.(*semantic.LogicalExpression)
+	_, binary := e.(*semantic.BinaryExpression)
+	if logical || (binary && parenBinary) {
+		b.WriteString("(")
+		formatExpression(b, e)
+		b.WriteString(")")
+		return
+	}
+	formatExpression(b, e)
+}
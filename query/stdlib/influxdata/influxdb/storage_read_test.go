@@ -0,0 +1,111 @@
+package influxdb_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/query/stdlib/influxdata/influxdb"
+)
+
+func TestStorageRead(t *testing.T) {
+	member := func(property string) *semantic.MemberExpression {
+		return &semantic.MemberExpression{
+			Object:   &semantic.IdentifierExpression{Name: "r"},
+			Property: property,
+		}
+	}
+	filter := &semantic.FunctionExpression{
+		Block: &semantic.FunctionBlock{
+			Parameters: &semantic.FunctionParameters{
+				List: []*semantic.FunctionParameter{
+					{Key: &semantic.Identifier{Name: "r"}},
+				},
+			},
+			Body: &semantic.LogicalExpression{
+				Operator: ast.AndOperator,
+				Left: &semantic.BinaryExpression{
+					Operator: ast.EqualOperator,
+					Left:     member("_measurement"),
+					Right:    &semantic.StringLiteral{Value: "cpu"},
+				},
+				Right: &semantic.LogicalExpression{
+					Operator: ast.OrOperator,
+					Left: &semantic.BinaryExpression{
+						Operator: ast.RegexpMatchOperator,
+						Left:     member("host"),
+						Right:    &semantic.RegexpLiteral{Value: regexp.MustCompile("^a/b")},
+					},
+					Right: &semantic.BinaryExpression{
+						Operator: ast.GreaterThanOperator,
+						Left:     member("_value"),
+						Right:    &semantic.FloatLiteral{Value: 0.5},
+					},
+				},
+			},
+		},
+	}
+	rangeSpec := influxdb.ReadRangePhysSpec{
+		Bucket:    "my-bucket",
+		FilterSet: true,
+		Filter:    filter,
+	}
+	predicate := `r._measurement == "cpu" and (r.host =~ /^a\/b/ or r._value > 0.5)`
+
+	tests := []struct {
+		name string
+		spec query.StorageReadSpec
+		want *query.StorageRead
+	}{
+		{
+			name: "range",
+			spec: &influxdb.ReadRangePhysSpec{BucketID: "0000000000000001"},
+			want: &query.StorageRead{BucketID: "0000000000000001"},
+		},
+		{
+			name: "filter",
+			spec: &rangeSpec,
+			want: &query.StorageRead{Bucket: "my-bucket", Predicate: predicate},
+		},
+		{
+			name: "group",
+			spec: &influxdb.ReadGroupPhysSpec{
+				ReadRangePhysSpec: rangeSpec,
+				GroupMode:         flux.GroupModeBy,
+				GroupKeys:         []string{"host"},
+				AggregateMethod:   "max",
+			},
+			want: &query.StorageRead{
+				Bucket:    "my-bucket",
+				Predicate: predicate,
+				GroupMode: "by",
+				GroupKeys: []string{"host"},
+				Aggregate: "max",
+			},
+		},
+		{
+			name: "tag keys",
+			spec: &influxdb.ReadTagKeysPhysSpec{ReadRangePhysSpec: rangeSpec},
+			want: &query.StorageRead{Bucket: "my-bucket", Predicate: predicate, Schema: "tagKeys"},
+		},
+		{
+			name: "tag values",
+			spec: &influxdb.ReadTagValuesPhysSpec{
+				ReadRangePhysSpec: rangeSpec,
+				TagKey:            "host",
+			},
+			want: &query.StorageRead{Bucket: "my-bucket", Predicate: predicate, Schema: "tagValues", TagKey: "host"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.StorageRead(); !cmp.Equal(tt.want, got) {
+				t.Errorf("unexpected storage read -want/+got:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultQueryHistoryLimit is the number of completed queries kept in the
+// history of an organization when no other limit is configured.
+const DefaultQueryHistoryLimit = 1000
+
+// Outcomes of queries in the query history.
+const (
+	QuerySucceeded = "success"
+	QueryFailed    = "error"
+	QueryCanceled  = "canceled"
+)
+
+// ops for query history errors.
+const (
+	OpAddQueryHistoryEntry = "AddQueryHistoryEntry"
+	OpFindQueryHistory     = "FindQueryHistory"
+)
+
+// QueryHistoryService keeps a rolling history of completed queries, so the
+// slowest queries of an organization can be found and optimized.
+type QueryHistoryService interface {
+	// AddQueryHistoryEntry adds a completed query to the history and sets e.ID.
+	// The oldest entries of the organization are removed once it holds more
+	// entries than the retention limit.
+	AddQueryHistoryEntry(ctx context.Context, e *QueryHistoryEntry) error
+
+	// FindQueryHistory returns the entries that match the filter, most recent
+	// first, or slowest first when sorted by duration.
+	FindQueryHistory(ctx context.Context, filter QueryHistoryFilter, opt ...FindOptions) ([]*QueryHistoryEntry, error)
+}
+
+// QueryHistoryEntry is a completed query.
+type QueryHistoryEntry struct {
+	ID             ID     `json:"id"`
+	OrganizationID ID     `json:"orgID"`
+	UserID         ID     `json:"userID,omitempty"`
+	Query          string `json:"query"`
+	Outcome        string `json:"outcome"`
+	Error          string `json:"error,omitempty"`
+
+	CompletedAt time.Time     `json:"completedAt"`
+	Duration    time.Duration `json:"duration"`
+	// BytesScanned is the number of bytes the query read from storage.
+	BytesScanned int64 `json:"bytesScanned"`
+	// ResponseBytes is the size of the response of the query.
+	ResponseBytes int64 `json:"responseBytes"`
+	// MemoryBytes is the most memory the query allocated at once.
+	MemoryBytes int64 `json:"memoryBytes"`
+}
+
+// QueryHistoryFilter represents a set of filters that restrict the returned entries.
+type QueryHistoryFilter struct {
+	OrganizationID *ID
+	UserID         *ID
+	Outcome        *string
+	// MinDuration leaves out the queries that completed faster.
+	MinDuration time.Duration
+}
+
+// QueryParams converts QueryHistoryFilter fields to url query params.
+func (f QueryHistoryFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.OrganizationID != nil {
+		qp["orgID"] = []string{f.OrganizationID.String()}
+	}
+
+	if f.UserID != nil {
+		qp["userID"] = []string{f.UserID.String()}
+	}
+
+	if f.Outcome != nil {
+		qp["outcome"] = []string{*f.Outcome}
+	}
+
+	if f.MinDuration > 0 {
+		qp["minDuration"] = []string{f.MinDuration.String()}
+	}
+
+	return qp
+}
@@ -0,0 +1,118 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrQueryViewNotFound is the error msg for a missing query view.
+const ErrQueryViewNotFound = "query view not found"
+
+// ops for query view error.
+const (
+	OpFindQueryViewByID = "FindQueryViewByID"
+	OpFindQueryViews    = "FindQueryViews"
+	OpCreateQueryView   = "CreateQueryView"
+	OpDeleteQueryView   = "DeleteQueryView"
+)
+
+const (
+	// DefaultQueryViewTTL is how long a query view created without a TTL lives.
+	DefaultQueryViewTTL = time.Hour
+
+	// MaxQueryViewTTL is the longest a query view can live.
+	MaxQueryViewTTL = 7 * 24 * time.Hour
+)
+
+// QueryViewService describes a service for managing query views.
+type QueryViewService interface {
+	// FindQueryViewByID finds a single query view by its ID.
+	FindQueryViewByID(ctx context.Context, id ID) (*QueryView, error)
+
+	// FindQueryViews returns all query views that match the filter.
+	FindQueryViews(ctx context.Context, filter QueryViewFilter) ([]*QueryView, error)
+
+	// CreateQueryView persists the result of the query of the view and assigns it an ID.
+	CreateQueryView(ctx context.Context, v *QueryView) error
+
+	// DeleteQueryView removes a query view and its data.
+	DeleteQueryView(ctx context.Context, id ID) error
+}
+
+// QueryView is the result of a query persisted as a named, temporary dataset,
+// so subsequent queries can build upon it without running the query again.
+//
+// The result is stored in a system bucket of the organization, under the
+// measurement named after the view. Both are removed once the view expires.
+type QueryView struct {
+	ID             ID     `json:"id,omitempty"`
+	OrganizationID ID     `json:"orgID"`
+	Name           string `json:"name"`
+	Query          string `json:"query"`
+
+	// TTLSeconds is how long the view lives after it was created.
+	// The DefaultQueryViewTTL is used when it is zero.
+	TTLSeconds int64 `json:"ttlSeconds,omitempty"`
+
+	// BucketID is the system bucket that holds the result of the query.
+	BucketID ID `json:"bucketID,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Valid returns an error if the query view can't be created.
+func (v *QueryView) Valid() error {
+	if !v.OrganizationID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if v.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "name is required",
+		}
+	}
+	if strings.HasPrefix(v.Name, "_") {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "name must not start with an underscore",
+		}
+	}
+	if v.Query == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "query is required",
+		}
+	}
+	if max := int64(MaxQueryViewTTL / time.Second); v.TTLSeconds < 0 || v.TTLSeconds > max {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("ttlSeconds must be between 0 and %d", max),
+		}
+	}
+	return nil
+}
+
+// TTL returns how long the view lives after it was created.
+func (v *QueryView) TTL() time.Duration {
+	if v.TTLSeconds == 0 {
+		return DefaultQueryViewTTL
+	}
+	return time.Duration(v.TTLSeconds) * time.Second
+}
+
+// Expired returns true if the view expired at t.
+func (v *QueryView) Expired(t time.Time) bool {
+	return !t.Before(v.ExpiresAt)
+}
+
+// QueryViewFilter represents a set of filters that restrict the returned query views.
+type QueryViewFilter struct {
+	OrganizationID *ID
+	Name           *string
+}
@@ -0,0 +1,58 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// BucketQuota limits the writes to a bucket. A limit of zero is no limit.
+type BucketQuota struct {
+	// MaxPointsPerSecond is the sustained rate of points that may be written.
+	// Writes may burst up to a second's worth of points.
+	MaxPointsPerSecond int64 `json:"maxPointsPerSecond,omitempty"`
+	// MaxSeries is the number of series past which writes that add series are rejected.
+	// Writes to the series already in the bucket are not limited by it.
+	MaxSeries int64 `json:"maxSeries,omitempty"`
+	// MaxBytesPerDay is the amount of line protocol that may be written per UTC day.
+	MaxBytesPerDay int64 `json:"maxBytesPerDay,omitempty"`
+}
+
+// Valid returns an error if a limit of the quota is negative.
+func (q BucketQuota) Valid() error {
+	if q.MaxPointsPerSecond < 0 || q.MaxSeries < 0 || q.MaxBytesPerDay < 0 {
+		return &Error{
+			Code: EUnprocessableEntity,
+			Msg:  "quota limits must not be negative",
+		}
+	}
+	return nil
+}
+
+// Unlimited returns true if the quota sets no limit.
+func (q BucketQuota) Unlimited() bool {
+	return q == BucketQuota{}
+}
+
+// BucketQuotaUsage is the usage of a bucket against its quota.
+type BucketQuotaUsage struct {
+	BucketID ID          `json:"bucketID"`
+	Quota    BucketQuota `json:"quota"`
+	// PointsPerSecond is the number of points written in the last full second.
+	PointsPerSecond int64 `json:"pointsPerSecond"`
+	// Series is the number of series in the bucket.
+	Series int64 `json:"series"`
+	// BytesToday is the amount of line protocol written since the start of the UTC day.
+	BytesToday int64 `json:"bytesToday"`
+}
+
+// BucketQuotaService enforces the quotas of buckets on writes and reports their usage.
+type BucketQuotaService interface {
+	// ReserveWrite accounts for a write of points and bytes to the bucket, where keys
+	// are the series keys of the points, one per point. If the write would exceed a
+	// quota of the bucket, nothing is accounted for, and it returns an error with code
+	// ETooManyRequests and how long to wait before retrying.
+	ReserveWrite(ctx context.Context, b *Bucket, keys [][]byte, bytes int64) (time.Duration, error)
+
+	// FindBucketQuotaUsage returns the usage of the bucket against its quota.
+	FindBucketQuotaUsage(ctx context.Context, b *Bucket) (*BucketQuotaUsage, error)
+}
@@ -0,0 +1,227 @@
+// Package redis implements the stores that are shared by several API nodes on Redis.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the default time a command may take, including the
+	// time to connect.
+	DefaultTimeout = 5 * time.Second
+	// DefaultMaxIdle is the default number of idle connections the client keeps.
+	DefaultMaxIdle = 8
+)
+
+// Error is an error reply of the server.
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// Client sends commands to a Redis server with the Redis serialization protocol.
+// It keeps up to MaxIdle connections open between commands.
+type Client struct {
+	Addr     string
+	Password string
+	DB       int
+	Timeout  time.Duration
+
+	idle chan *conn
+}
+
+// NewClient returns a client of the server at rawurl, of the form
+// redis://[:password@]host[:port][/db].
+func NewClient(rawurl string) (*Client, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("invalid redis url %q: scheme must be redis", rawurl)
+	}
+
+	c := &Client{
+		Addr:    u.Host,
+		Timeout: DefaultTimeout,
+		idle:    make(chan *conn, DefaultMaxIdle),
+	}
+	if u.Port() == "" {
+		c.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.Password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if c.DB, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis url %q: database must be a number", rawurl)
+		}
+	}
+	return c, nil
+}
+
+// conn is a connection to the server.
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// Do sends the command to the server and returns its reply. The reply is a
+// string, an int64, a []byte, a []interface{} of replies, or nil. Error replies
+// are returned as an Error.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(c.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	cn, err := c.get(ctx, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(deadline, args...)
+	if _, ok := err.(Error); err != nil && !ok {
+		// The connection is in an unknown state after an I/O error.
+		cn.Close()
+		return nil, err
+	}
+
+	c.put(cn)
+	return reply, err
+}
+
+// Close closes the idle connections of the client.
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// get returns an idle connection, or dials a new one.
+func (c *Client) get(ctx context.Context, deadline time.Time) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	d := net.Dialer{Deadline: deadline}
+	nc, err := d.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc)}
+
+	if c.Password != "" {
+		if _, err := cn.do(deadline, "AUTH", c.Password); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	if c.DB != 0 {
+		if _, err := cn.do(deadline, "SELECT", strconv.Itoa(c.DB)); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+// put keeps the connection for the next command, unless enough connections are idle.
+func (c *Client) put(cn *conn) {
+	select {
+	case c.idle <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+func (cn *conn) do(deadline time.Time, args ...string) (interface{}, error) {
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(cn, b.String()); err != nil {
+		return nil, err
+	}
+
+	return readReply(cn.r)
+}
+
+var errProtocol = errors.New("redis: invalid reply")
+
+// readReply reads a reply of the Redis serialization protocol from r.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errProtocol
+	}
+	kind, line := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return line, nil
+	case '-':
+		return nil, Error(line)
+	case ':':
+		return strconv.ParseInt(line, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		v := make([]byte, n+2)
+		if _, err := io.ReadFull(r, v); err != nil {
+			return nil, err
+		}
+		return v[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		replies := make([]interface{}, n)
+		for i := range replies {
+			reply, err := readReply(r)
+			if e, ok := err.(Error); ok {
+				// Error replies within arrays are replies like any other.
+				reply, err = e, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			replies[i] = reply
+		}
+		return replies, nil
+	default:
+		return nil, errProtocol
+	}
+}
@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+// sessionKeyPrefix is the prefix of the keys of sessions, to share a server with other data.
+const sessionKeyPrefix = "influxdb:sessions:"
+
+var _ kv.SessionStore = (*SessionStore)(nil)
+
+// SessionStore stores sessions on a Redis server, so that every API node that
+// uses the server shares the sessions. Sessions are stored with the time left
+// until they expire as their TTL, so that the server removes expired sessions.
+type SessionStore struct {
+	Client *Client
+
+	now func() time.Time
+}
+
+// NewSessionStore returns a SessionStore that stores sessions with c.
+func NewSessionStore(c *Client) *SessionStore {
+	return &SessionStore{
+		Client: c,
+		now:    time.Now,
+	}
+}
+
+// Get returns the session stored at key, or kv.ErrKeyNotFound if there is none.
+func (s *SessionStore) Get(ctx context.Context, key string) (*influxdb.Session, error) {
+	reply, err := s.Client.Do(ctx, "GET", sessionKeyPrefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, kv.ErrKeyNotFound
+	}
+
+	v, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply %v to GET of session", reply)
+	}
+
+	sn := &influxdb.Session{}
+	if err := json.Unmarshal(v, sn); err != nil {
+		return nil, err
+	}
+	return sn, nil
+}
+
+// Put stores the session until it expires. Sessions that already expired are
+// removed.
+func (s *SessionStore) Put(ctx context.Context, sn *influxdb.Session) error {
+	key := sessionKeyPrefix + sn.Key
+
+	ttl := sn.ExpiresAt.Sub(s.now()) / time.Millisecond
+	if ttl <= 0 {
+		_, err := s.Client.Do(ctx, "DEL", key)
+		return err
+	}
+
+	v, err := json.Marshal(sn)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Client.Do(ctx, "SET", key, string(v), "PX", strconv.FormatInt(int64(ttl), 10))
+	return err
+}
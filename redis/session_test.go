@@ -0,0 +1,191 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kv"
+)
+
+// testServer is a Redis server that serves GET, SET with PX, DEL, AUTH and
+// SELECT from memory.
+type testServer struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	values   map[string]string
+	ttls     map[string]string
+	commands []string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testServer{
+		ln:     ln,
+		values: make(map[string]string),
+		ttls:   make(map[string]string),
+	}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s
+}
+
+func (s *testServer) serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+		fmt.Fprint(c, s.do(args))
+	}
+}
+
+func (s *testServer) do(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commands = append(s.commands, args[0])
+
+	switch args[0] {
+	case "AUTH":
+		if args[1] != "secret" {
+			return "-ERR invalid password\r\n"
+		}
+		return "+OK\r\n"
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		v, ok := s.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "SET":
+		s.values[args[1]] = args[2]
+		s.ttls[args[1]] = args[4]
+		return "+OK\r\n"
+	case "DEL":
+		delete(s.values, args[1])
+		return ":1\r\n"
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func (s *testServer) ttl(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ttls[key]
+}
+
+func (s *testServer) history() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.commands, " ")
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient("redis://:secret@redis.example.com/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Addr != "redis.example.com:6379" || c.Password != "secret" || c.DB != 2 {
+		t.Errorf("unexpected client %+v", c)
+	}
+
+	for _, rawurl := range []string{"http://localhost:6379", "redis://localhost:6379/zero"} {
+		if _, err := NewClient(rawurl); err == nil {
+			t.Errorf("expected %q to be invalid", rawurl)
+		}
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.ln.Close()
+
+	c, err := NewClient("redis://:secret@" + srv.ln.Addr().String() + "/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	s := NewSessionStore(c)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := s.Get(ctx, "abc123xyz"); err != kv.ErrKeyNotFound {
+		t.Fatalf("expected missing session to be not found, got %v", err)
+	}
+
+	sn := &influxdb.Session{ID: 1, Key: "abc123xyz", UserID: 2, ExpiresAt: now.Add(time.Hour)}
+	if err := s.Put(ctx, sn); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := srv.ttl(sessionKeyPrefix+"abc123xyz"), "3600000"; got != want {
+		t.Errorf("got ttl %s, want %s", got, want)
+	}
+
+	got, err := s.Get(ctx, "abc123xyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 1 || got.UserID != 2 || !got.ExpiresAt.Equal(sn.ExpiresAt) {
+		t.Errorf("unexpected session %+v", got)
+	}
+
+	// Expiring the session removes it.
+	sn.ExpiresAt = now
+	if err := s.Put(ctx, sn); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, "abc123xyz"); err != kv.ErrKeyNotFound {
+		t.Fatalf("expected expired session to be removed, got %v", err)
+	}
+
+	// The connection is authenticated once and kept between commands.
+	want := "AUTH SELECT GET SET GET DEL GET"
+	if got := srv.history(); got != want {
+		t.Errorf("got commands %q, want %q", got, want)
+	}
+}
+
+func TestClient_Do_errorReply(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.ln.Close()
+
+	c, err := NewClient("redis://" + srv.ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.Do(context.Background(), "FLUSHALL"); err != Error("ERR unknown command") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
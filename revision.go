@@ -0,0 +1,70 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ErrRevisionNotFound is the error msg for a missing revision.
+const ErrRevisionNotFound = "revision not found"
+
+// ops for revision errors.
+const (
+	OpFindRevision    = "FindRevision"
+	OpFindRevisions   = "FindRevisions"
+	OpRestoreRevision = "RestoreRevision"
+)
+
+// RevisionResourceTypes are the types of resources whose changes are recorded
+// as revisions.
+var RevisionResourceTypes = []ResourceType{
+	DashboardsResourceType,
+	TasksResourceType,
+}
+
+// RevisionService records the changes made to dashboards and to the Flux of
+// tasks, so that a resource can be rolled back to an earlier revision.
+type RevisionService interface {
+	// FindRevisions returns the revisions of a resource and the total count of
+	// its revisions, latest first.
+	FindRevisions(ctx context.Context, typ ResourceType, id ID, opt ...FindOptions) ([]*Revision, int, error)
+
+	// FindRevision returns a single revision of a resource by its version.
+	FindRevision(ctx context.Context, typ ResourceType, id ID, version int) (*Revision, error)
+
+	// RestoreRevision puts a resource back as it was at a revision. Restoring
+	// is itself recorded as the latest revision of the resource.
+	RestoreRevision(ctx context.Context, typ ResourceType, id ID, version int) error
+}
+
+// Revision is a recorded state of a dashboard or of the Flux of a task.
+//
+// The state of a resource before its first recorded change is kept as its
+// first revision, so that the change can be rolled back.
+type Revision struct {
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   ID           `json:"resourceID"`
+	OrgID        ID           `json:"orgID"`
+	// Version numbers the revisions of a resource from 1.
+	Version int `json:"version"`
+	// UserID is the user who made the change, if known.
+	UserID ID        `json:"userID,omitempty"`
+	Time   time.Time `json:"time"`
+	// Diff is a line diff of the content from the previous revision.
+	Diff string `json:"diff"`
+	// Content is the Flux of a task, or a dashboard with the views of its
+	// cells as JSON.
+	Content string `json:"content"`
+}
+
+// ValidRevisionResourceType returns an error if the changes of resources of
+// type typ are not recorded.
+func ValidRevisionResourceType(typ ResourceType) error {
+	if !containsResourceType(RevisionResourceTypes, typ) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "revisions of resources of type " + string(typ) + " are not recorded",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// Aggregates a rollup may be verified with.
+const (
+	RollupAggregateMean  = "mean"
+	RollupAggregateSum   = "sum"
+	RollupAggregateCount = "count"
+	RollupAggregateMin   = "min"
+	RollupAggregateMax   = "max"
+)
+
+// Reasons of rollup discrepancies.
+const (
+	// RollupMissing is a window of a series with raw data but no rollup.
+	RollupMissing = "missing"
+	// RollupMismatch is a window of a series whose rollup differs from the
+	// aggregate of its raw data by more than the tolerance.
+	RollupMismatch = "mismatch"
+	// RollupUnexpected is a window of a series with a rollup but no raw data.
+	RollupUnexpected = "unexpected"
+)
+
+// RollupVerificationService verifies that a downsampled bucket agrees with the
+// raw data it was downsampled from, so a retention or downsampling pipeline
+// that silently drops intervals is noticed.
+type RollupVerificationService interface {
+	// VerifyRollup compares the aggregates of the raw data in sampled windows
+	// with the rollup of the windows and reports the discrepancies.
+	VerifyRollup(ctx context.Context, req RollupVerificationRequest) (*RollupVerification, error)
+}
+
+// RollupVerificationRequest selects the rollup to verify and how.
+type RollupVerificationRequest struct {
+	OrgID ID
+	// BucketID is the bucket of the rollup, SourceBucketID the bucket of the raw data.
+	BucketID       ID
+	SourceBucketID ID
+
+	Measurement string
+	Field       string
+	// Aggregate is the aggregate the rollup was computed with.
+	Aggregate string
+	// Every is the duration of the windows of the rollup, aligned to the Unix epoch.
+	Every time.Duration
+	// TimeSrc is the bound of its window a rollup is stored at, "_stop" like
+	// aggregateWindow does by default, or "_start".
+	TimeSrc string
+
+	Start time.Time
+	Stop  time.Time
+	// Samples is the number of windows between start and stop that are compared.
+	Samples int
+	// Tolerance is the relative difference between a rollup and the aggregate
+	// of its raw data that is not a mismatch.
+	Tolerance float64
+}
+
+// Limits of rollup verifications.
+const (
+	DefaultRollupSamples = 10
+	MaxRollupSamples     = 100
+)
+
+// Valid returns an error if the request does not select a rollup and a time range.
+func (r RollupVerificationRequest) Valid() error {
+	if !r.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is invalid",
+		}
+	}
+	if !r.BucketID.Valid() || !r.SourceBucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucketID and sourceBucketID must be valid",
+		}
+	}
+	if r.BucketID == r.SourceBucketID {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "the rollup and its source must be different buckets",
+		}
+	}
+	if r.Measurement == "" || r.Field == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "measurement and field are required",
+		}
+	}
+	switch r.Aggregate {
+	case RollupAggregateMean, RollupAggregateSum, RollupAggregateCount, RollupAggregateMin, RollupAggregateMax:
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "aggregate must be one of mean, sum, count, min or max",
+		}
+	}
+	if r.Every <= 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "every must be positive",
+		}
+	}
+	if r.TimeSrc != "_stop" && r.TimeSrc != "_start" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "timeSrc must be _stop or _start",
+		}
+	}
+	if !r.Start.Before(r.Stop) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "start must be before stop",
+		}
+	}
+	if r.Samples <= 0 || r.Samples > MaxRollupSamples {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "samples must be between 1 and 100",
+		}
+	}
+	if r.Tolerance < 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "tolerance must not be negative",
+		}
+	}
+	return nil
+}
+
+// RollupVerification is the result of a rollup verification.
+type RollupVerification struct {
+	// Windows is the number of windows that were compared.
+	Windows int `json:"windows"`
+	// SkippedWindows is the number of sampled windows without raw data,
+	// such as the windows the retention of the source already removed.
+	SkippedWindows int `json:"skippedWindows"`
+	// Series is the number of windows of series that were compared.
+	Series        int                  `json:"series"`
+	Discrepancies []*RollupDiscrepancy `json:"discrepancies"`
+}
+
+// RollupDiscrepancy is a window of a series whose rollup does not agree with its raw data.
+type RollupDiscrepancy struct {
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
+	// Series is the tags of the series, such as "host=a,region=eu".
+	Series string `json:"series"`
+	Reason string `json:"reason"`
+	// Expected is the aggregate of the raw data and Actual the rollup, if any.
+	Expected *float64 `json:"expected,omitempty"`
+	Actual   *float64 `json:"actual,omitempty"`
+}
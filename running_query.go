@@ -0,0 +1,53 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ErrRunningQueryNotFound is the error msg for a query that is not running.
+const ErrRunningQueryNotFound = "running query not found"
+
+// ops for running query error.
+const (
+	OpFindRunningQueryByID = "FindRunningQueryByID"
+	OpFindRunningQueries   = "FindRunningQueries"
+	OpCancelRunningQuery   = "CancelRunningQuery"
+)
+
+// RunningQueryService lists and cancels the queries the query engine is running,
+// so a runaway query can be stopped without restarting the server.
+type RunningQueryService interface {
+	// FindRunningQueryByID finds a single running query by its ID.
+	FindRunningQueryByID(ctx context.Context, id ID) (*RunningQuery, error)
+
+	// FindRunningQueries returns all running queries that match the filter.
+	FindRunningQueries(ctx context.Context, filter RunningQueryFilter) ([]*RunningQuery, error)
+
+	// CancelRunningQuery cancels a running query. The client of the query
+	// receives an error.
+	CancelRunningQuery(ctx context.Context, id ID) error
+}
+
+// RunningQuery is a query that is compiling, queued or executing in the query engine.
+// The IDs of running queries are only unique until the server restarts.
+type RunningQuery struct {
+	ID             ID     `json:"id"`
+	OrganizationID ID     `json:"orgID"`
+	UserID         ID     `json:"userID,omitempty"`
+	Query          string `json:"query,omitempty"`
+	State          string `json:"state"`
+	Priority       string `json:"priority,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	// Duration is how long the query has been running.
+	Duration time.Duration `json:"-"`
+	// MemoryBytes is the memory the query has allocated in the query engine.
+	MemoryBytes int64 `json:"memoryBytes"`
+}
+
+// RunningQueryFilter represents a set of filters that restrict the returned running queries.
+type RunningQueryFilter struct {
+	OrganizationID *ID
+	UserID         *ID
+}
@@ -0,0 +1,143 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits of the descriptions of the schema of a bucket.
+const (
+	MaxSchemaDescriptionLength       = 1024
+	MaxSchemaDescriptionMeasurements = 1000
+)
+
+// SchemaUnitMetadataPrefix prefixes the bucket metadata keys that annotate
+// fields with their unit: "unit/<measurement>/<field>" for a field of a
+// measurement, or "unit/<field>" for a field of every measurement.
+const SchemaUnitMetadataPrefix = "unit/"
+
+// ErrSchemaDescriptionsNotFound is the error msg for a bucket without schema descriptions.
+const ErrSchemaDescriptionsNotFound = "schema descriptions not found"
+
+// ops for schema doc errors.
+const (
+	OpFindSchemaDescriptions = "FindSchemaDescriptions"
+	OpPutSchemaDescriptions  = "PutSchemaDescriptions"
+	OpFindSchemaDoc          = "FindSchemaDoc"
+)
+
+// SchemaDescriptions are the descriptions of a bucket and of its measurements,
+// tags and fields, written by the people who use the bucket.
+type SchemaDescriptions struct {
+	OrgID    ID `json:"orgID,omitempty"`
+	BucketID ID `json:"bucketID,omitempty"`
+
+	Description string `json:"description,omitempty"`
+	// Measurements are the descriptions of the measurements, by name.
+	Measurements map[string]MeasurementDescriptions `json:"measurements"`
+	CRUDLog
+}
+
+// MeasurementDescriptions are the descriptions of a measurement and of its tags and fields.
+type MeasurementDescriptions struct {
+	Description string `json:"description,omitempty"`
+	// Tags are the descriptions of the tag keys, by key.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Fields are the descriptions of the fields, by key.
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Valid returns an error if the descriptions are too many or too long.
+func (d *SchemaDescriptions) Valid() error {
+	if err := validSchemaDescription("bucket", d.Description); err != nil {
+		return err
+	}
+	if len(d.Measurements) > MaxSchemaDescriptionMeasurements {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("descriptions can have at most %d measurements", MaxSchemaDescriptionMeasurements),
+		}
+	}
+	for name, m := range d.Measurements {
+		if name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "measurement name is required",
+			}
+		}
+		if err := validSchemaDescription(fmt.Sprintf("measurement %q", name), m.Description); err != nil {
+			return err
+		}
+		for k, desc := range m.Tags {
+			if err := validSchemaDescription(fmt.Sprintf("tag %q of measurement %q", k, name), desc); err != nil {
+				return err
+			}
+		}
+		for k, desc := range m.Fields {
+			if err := validSchemaDescription(fmt.Sprintf("field %q of measurement %q", k, name), desc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validSchemaDescription(of, desc string) error {
+	if utf8.RuneCountInString(desc) > MaxSchemaDescriptionLength {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("description of %s is longer than %d characters", of, MaxSchemaDescriptionLength),
+		}
+	}
+	return nil
+}
+
+// SchemaDescriptionService stores the descriptions of the schemas of buckets.
+type SchemaDescriptionService interface {
+	// FindSchemaDescriptions returns the descriptions of the schema of a bucket.
+	FindSchemaDescriptions(ctx context.Context, bucketID ID) (*SchemaDescriptions, error)
+
+	// PutSchemaDescriptions replaces the descriptions of the schema of their bucket.
+	PutSchemaDescriptions(ctx context.Context, d *SchemaDescriptions) error
+}
+
+// SchemaDoc is the documentation of the schema of a bucket: the measurements,
+// tags and fields that are written to it, with their descriptions and units.
+type SchemaDoc struct {
+	OrgID        ID                `json:"orgID"`
+	BucketID     ID                `json:"bucketID"`
+	BucketName   string            `json:"bucketName"`
+	Description  string            `json:"description,omitempty"`
+	Measurements []*MeasurementDoc `json:"measurements"`
+	// Truncated is true if the bucket has more measurements, tags or fields
+	// than are documented.
+	Truncated   bool      `json:"truncated,omitempty"`
+	GeneratedAt time.Time `json:"generatedAt"`
+}
+
+// MeasurementDoc is the documentation of a measurement of a bucket.
+type MeasurementDoc struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Tags        []SchemaDocColumn `json:"tags"`
+	Fields      []SchemaDocColumn `json:"fields"`
+}
+
+// SchemaDocColumn is the documentation of a tag or field of a measurement.
+type SchemaDocColumn struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// DataType is the data type of a field, if it is declared by the schema
+	// of its measurement.
+	DataType SchemaColumnDataType `json:"dataType,omitempty"`
+	// Unit is the unit of a field, from the metadata of its bucket.
+	Unit string `json:"unit,omitempty"`
+}
+
+// SchemaDocService generates the documentation of the schemas of buckets.
+type SchemaDocService interface {
+	// FindSchemaDoc returns the documentation of the schema of a bucket as it is now.
+	FindSchemaDoc(ctx context.Context, bucketID ID) (*SchemaDoc, error)
+}
@@ -0,0 +1,60 @@
+package schemadoc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// WriteMarkdown writes the documentation of the schema of a bucket to w as markdown.
+func WriteMarkdown(w io.Writer, doc *influxdb.SchemaDoc) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# %s\n\n", doc.BucketName)
+	if doc.Description != "" {
+		fmt.Fprintf(bw, "%s\n\n", doc.Description)
+	}
+	if len(doc.Measurements) == 0 {
+		fmt.Fprint(bw, "No data has been written to the bucket.\n\n")
+	}
+
+	for _, m := range doc.Measurements {
+		fmt.Fprintf(bw, "## %s\n\n", m.Name)
+		if m.Description != "" {
+			fmt.Fprintf(bw, "%s\n\n", m.Description)
+		}
+
+		if len(m.Tags) > 0 {
+			fmt.Fprint(bw, "### Tags\n\n| Tag | Description |\n| --- | --- |\n")
+			for _, c := range m.Tags {
+				fmt.Fprintf(bw, "| %s | %s |\n", cell(c.Name), cell(c.Description))
+			}
+			fmt.Fprint(bw, "\n")
+		}
+
+		if len(m.Fields) > 0 {
+			fmt.Fprint(bw, "### Fields\n\n| Field | Type | Unit | Description |\n| --- | --- | --- | --- |\n")
+			for _, c := range m.Fields {
+				fmt.Fprintf(bw, "| %s | %s | %s | %s |\n", cell(c.Name), cell(string(c.DataType)), cell(c.Unit), cell(c.Description))
+			}
+			fmt.Fprint(bw, "\n")
+		}
+	}
+
+	if doc.Truncated {
+		fmt.Fprint(bw, "_The bucket has more measurements, tags or fields than are documented._\n\n")
+	}
+	fmt.Fprintf(bw, "_Generated at %s._\n", doc.GeneratedAt.UTC().Format(time.RFC3339))
+	return bw.Flush()
+}
+
+var cellReplacer = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+// cell escapes s to be the content of a cell of a markdown table.
+func cell(s string) string {
+	return cellReplacer.Replace(s)
+}
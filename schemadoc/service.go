@@ -0,0 +1,224 @@
+// Package schemadoc generates the documentation of the schemas of buckets, so
+// teams that share an instance can find out what is written where.
+package schemadoc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// Services are the services the documentation of buckets is generated from.
+// They are used without authorization, which is checked when a doc is requested.
+type Services struct {
+	BucketService influxdb.BucketService
+
+	// SeriesSchemaService lists the measurements, tags and fields that are written to buckets.
+	SeriesSchemaService influxdb.SeriesSchemaService
+
+	// MeasurementSchemaService declares the data types of the fields of
+	// buckets with an explicit schema type. It is optional.
+	MeasurementSchemaService influxdb.MeasurementSchemaService
+
+	// SchemaDescriptionService stores the descriptions of the schemas. It is optional.
+	SchemaDescriptionService influxdb.SchemaDescriptionService
+}
+
+var _ influxdb.SchemaDocService = (*Service)(nil)
+
+// Service generates the documentation of the schema of a bucket when it is
+// requested, so it always documents what is in the bucket now. The
+// descriptions of measurements, tags and fields that are no longer written
+// are kept, and documented again if they are written again.
+type Service struct {
+	Services
+
+	now func() time.Time
+}
+
+// NewService returns a new Service that generates docs from the services of s.
+func NewService(s Services) *Service {
+	return &Service{
+		Services: s,
+		now:      time.Now,
+	}
+}
+
+// FindSchemaDoc returns the documentation of the schema of a bucket.
+func (s *Service) FindSchemaDoc(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDoc, error) {
+	doc, err := s.findSchemaDoc(ctx, bucketID)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpFindSchemaDoc,
+			Err: err,
+		}
+	}
+	return doc, nil
+}
+
+func (s *Service) findSchemaDoc(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDoc, error) {
+	b, err := s.BucketService.FindBucketByID(ctx, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	descs, err := s.descriptions(ctx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+	schemas, err := s.measurementSchemas(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &influxdb.SchemaDoc{
+		OrgID:        b.OrgID,
+		BucketID:     b.ID,
+		BucketName:   b.Name,
+		Description:  descs.Description,
+		Measurements: []*influxdb.MeasurementDoc{},
+		GeneratedAt:  s.now().UTC(),
+	}
+
+	filter := influxdb.SeriesSchemaFilter{OrgID: b.OrgID, BucketID: b.ID}
+	names, truncated, err := s.list(ctx, filter, s.SeriesSchemaService.FindMeasurements)
+	if err != nil {
+		return nil, err
+	}
+	doc.Truncated = truncated
+
+	for name := range schemas {
+		names = append(names, name)
+	}
+	for _, name := range unique(names) {
+		m, truncated, err := s.measurementDoc(ctx, filter, b, name, descs.Measurements[name], schemas[name])
+		if err != nil {
+			return nil, err
+		}
+		doc.Measurements = append(doc.Measurements, m)
+		doc.Truncated = doc.Truncated || truncated
+	}
+	return doc, nil
+}
+
+// measurementDoc returns the documentation of a measurement of b, and whether
+// it has more tags or fields than are documented.
+func (s *Service) measurementDoc(ctx context.Context, filter influxdb.SeriesSchemaFilter, b *influxdb.Bucket, name string, desc influxdb.MeasurementDescriptions, schema *influxdb.MeasurementSchema) (*influxdb.MeasurementDoc, bool, error) {
+	filter.Measurement = name
+	tags, tagsTruncated, err := s.list(ctx, filter, s.SeriesSchemaService.FindTagKeys)
+	if err != nil {
+		return nil, false, err
+	}
+	fields, fieldsTruncated, err := s.list(ctx, filter, func(ctx context.Context, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+		return s.SeriesSchemaService.FindTagValues(ctx, "_field", filter)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	dataTypes := make(map[string]influxdb.SchemaColumnDataType)
+	if schema != nil {
+		for _, c := range schema.Columns {
+			switch c.Type {
+			case influxdb.SemanticColumnTypeTag:
+				tags = append(tags, c.Name)
+			case influxdb.SemanticColumnTypeField:
+				fields = append(fields, c.Name)
+				dataTypes[c.Name] = c.DataType
+			}
+		}
+	}
+
+	m := &influxdb.MeasurementDoc{
+		Name:        name,
+		Description: desc.Description,
+		Tags:        []influxdb.SchemaDocColumn{},
+		Fields:      []influxdb.SchemaDocColumn{},
+	}
+	for _, k := range unique(tags) {
+		m.Tags = append(m.Tags, influxdb.SchemaDocColumn{
+			Name:        k,
+			Description: desc.Tags[k],
+		})
+	}
+	for _, k := range unique(fields) {
+		m.Fields = append(m.Fields, influxdb.SchemaDocColumn{
+			Name:        k,
+			Description: desc.Fields[k],
+			DataType:    dataTypes[k],
+			Unit:        unit(b.Metadata, name, k),
+		})
+	}
+	return m, tagsTruncated || fieldsTruncated, nil
+}
+
+// list returns the values found for the filter, up to the most that are
+// listed at once, and whether there are more.
+func (s *Service) list(ctx context.Context, filter influxdb.SeriesSchemaFilter, find func(context.Context, influxdb.SeriesSchemaFilter) ([]string, error)) ([]string, bool, error) {
+	filter.Limit = influxdb.SeriesSchemaMaxLimit
+	values, err := find(ctx, filter)
+	if err != nil || len(values) < filter.Limit {
+		return values, false, err
+	}
+
+	filter.After = values[len(values)-1]
+	filter.Limit = 1
+	more, err := find(ctx, filter)
+	if err != nil {
+		return nil, false, err
+	}
+	return values, len(more) > 0, nil
+}
+
+// descriptions returns the descriptions of the schema of a bucket, which are
+// empty if the bucket has none.
+func (s *Service) descriptions(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDescriptions, error) {
+	if s.SchemaDescriptionService == nil {
+		return &influxdb.SchemaDescriptions{}, nil
+	}
+	d, err := s.SchemaDescriptionService.FindSchemaDescriptions(ctx, bucketID)
+	if influxdb.ErrorCode(err) == influxdb.ENotFound {
+		return &influxdb.SchemaDescriptions{}, nil
+	}
+	return d, err
+}
+
+// measurementSchemas returns the measurement schemas of b by name.
+func (s *Service) measurementSchemas(ctx context.Context, b *influxdb.Bucket) (map[string]*influxdb.MeasurementSchema, error) {
+	schemas := make(map[string]*influxdb.MeasurementSchema)
+	if s.MeasurementSchemaService == nil || !b.SchemaType.Explicit() {
+		return schemas, nil
+	}
+	ms, err := s.MeasurementSchemaService.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{BucketID: b.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range ms {
+		schemas[m.Name] = m
+	}
+	return schemas, nil
+}
+
+// unit returns the unit of a field of a measurement from the metadata of its bucket.
+func unit(md influxdb.Metadata, measurement, field string) string {
+	if u, ok := md[influxdb.SchemaUnitMetadataPrefix+measurement+"/"+field]; ok {
+		return u
+	}
+	return md[influxdb.SchemaUnitMetadataPrefix+field]
+}
+
+// unique returns the distinct values, sorted.
+func unique(values []string) []string {
+	sort.Strings(values)
+	n := 0
+	for i, v := range values {
+		if i > 0 && v == values[n-1] {
+			continue
+		}
+		values[n] = v
+		n++
+	}
+	return values[:n]
+}
@@ -0,0 +1,258 @@
+package schemadoc_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/schemadoc"
+)
+
+// testServices returns services of an explicit bucket with a cpu measurement,
+// and a mem measurement that is declared but has no data yet.
+func testServices() schemadoc.Services {
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return &influxdb.Bucket{
+			ID:         id,
+			OrgID:      1,
+			Name:       "telegraf",
+			SchemaType: influxdb.BucketSchemaTypeExplicit,
+			Metadata: influxdb.Metadata{
+				"unit/cpu/usage_user": "percent",
+				"unit/usage_user":     "ratio",
+				"unit/used":           "bytes",
+				"team":                "ops",
+			},
+		}, nil
+	}
+
+	series := mock.NewSeriesSchemaService()
+	series.FindMeasurementsFn = func(ctx context.Context, f influxdb.SeriesSchemaFilter) ([]string, error) {
+		return []string{"cpu"}, nil
+	}
+	series.FindTagKeysFn = func(ctx context.Context, f influxdb.SeriesSchemaFilter) ([]string, error) {
+		if f.Measurement != "cpu" {
+			return nil, nil
+		}
+		return []string{"cpu", "host"}, nil
+	}
+	series.FindTagValuesFn = func(ctx context.Context, key string, f influxdb.SeriesSchemaFilter) ([]string, error) {
+		if key != "_field" || f.Measurement != "cpu" {
+			return nil, nil
+		}
+		return []string{"usage_system", "usage_user"}, nil
+	}
+
+	schemas := mock.NewMeasurementSchemaService()
+	schemas.FindMeasurementSchemasFn = func(ctx context.Context, f influxdb.MeasurementSchemaFilter) ([]*influxdb.MeasurementSchema, error) {
+		return []*influxdb.MeasurementSchema{
+			{
+				Name: "cpu",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+					{Name: "host", Type: influxdb.SemanticColumnTypeTag},
+					{Name: "usage_user", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat},
+				},
+			},
+			{
+				Name: "mem",
+				Columns: []influxdb.MeasurementSchemaColumn{
+					{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+					{Name: "used", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeInteger},
+				},
+			},
+		}, nil
+	}
+
+	descs := mock.NewSchemaDescriptionService()
+	descs.FindSchemaDescriptionsFn = func(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDescriptions, error) {
+		return &influxdb.SchemaDescriptions{
+			OrgID:       1,
+			BucketID:    bucketID,
+			Description: "Metrics of the hosts of the ops team.",
+			Measurements: map[string]influxdb.MeasurementDescriptions{
+				"cpu": {
+					Description: "CPU usage",
+					Tags:        map[string]string{"host": "Name of the host"},
+					Fields:      map[string]string{"usage_user": "Time spent in user space"},
+				},
+				"disk": {Description: "Not written anymore"},
+			},
+		}, nil
+	}
+
+	return schemadoc.Services{
+		BucketService:            buckets,
+		SeriesSchemaService:      series,
+		MeasurementSchemaService: schemas,
+		SchemaDescriptionService: descs,
+	}
+}
+
+func TestService_FindSchemaDoc(t *testing.T) {
+	s := schemadoc.NewService(testServices())
+
+	got, err := s.FindSchemaDoc(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &influxdb.SchemaDoc{
+		OrgID:       1,
+		BucketID:    2,
+		BucketName:  "telegraf",
+		Description: "Metrics of the hosts of the ops team.",
+		Measurements: []*influxdb.MeasurementDoc{
+			{
+				Name:        "cpu",
+				Description: "CPU usage",
+				Tags: []influxdb.SchemaDocColumn{
+					{Name: "cpu"},
+					{Name: "host", Description: "Name of the host"},
+				},
+				Fields: []influxdb.SchemaDocColumn{
+					{Name: "usage_system"},
+					{Name: "usage_user", Description: "Time spent in user space", DataType: influxdb.SchemaColumnDataTypeFloat, Unit: "percent"},
+				},
+			},
+			{
+				Name: "mem",
+				Tags: []influxdb.SchemaDocColumn{},
+				Fields: []influxdb.SchemaDocColumn{
+					{Name: "used", DataType: influxdb.SchemaColumnDataTypeInteger, Unit: "bytes"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(influxdb.SchemaDoc{}, "GeneratedAt")); diff != "" {
+		t.Errorf("unexpected schema doc (-want +got):\n%s", diff)
+	}
+	if got.GeneratedAt.IsZero() {
+		t.Error("expected the generation time to be set")
+	}
+}
+
+func TestService_FindSchemaDoc_WithoutDescriptions(t *testing.T) {
+	services := testServices()
+	services.MeasurementSchemaService = nil
+	descs := mock.NewSchemaDescriptionService()
+	descs.FindSchemaDescriptionsFn = func(ctx context.Context, bucketID influxdb.ID) (*influxdb.SchemaDescriptions, error) {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: influxdb.ErrSchemaDescriptionsNotFound}
+	}
+	services.SchemaDescriptionService = descs
+
+	got, err := schemadoc.NewService(services).FindSchemaDoc(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != "" || len(got.Measurements) != 1 || got.Measurements[0].Description != "" {
+		t.Errorf("expected only the undescribed cpu measurement, got %+v", got)
+	}
+	if unit := got.Measurements[0].Fields[1].Unit; unit != "percent" {
+		t.Errorf("got unit %q, want %q", unit, "percent")
+	}
+}
+
+func TestService_FindSchemaDoc_Truncated(t *testing.T) {
+	services := testServices()
+	series := mock.NewSeriesSchemaService()
+	series.FindMeasurementsFn = func(ctx context.Context, f influxdb.SeriesSchemaFilter) ([]string, error) {
+		if f.After != "" {
+			return []string{"more"}, nil
+		}
+		names := make([]string, f.Limit)
+		for i := range names {
+			names[i] = fmt.Sprintf("m%04d", i)
+		}
+		return names, nil
+	}
+	services.SeriesSchemaService = series
+	services.MeasurementSchemaService = nil
+
+	got, err := schemadoc.NewService(services).FindSchemaDoc(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Truncated {
+		t.Error("expected the doc to be truncated")
+	}
+	if len(got.Measurements) != influxdb.SeriesSchemaMaxLimit {
+		t.Errorf("got %d measurements, want %d", len(got.Measurements), influxdb.SeriesSchemaMaxLimit)
+	}
+}
+
+func TestService_FindSchemaDoc_BucketNotFound(t *testing.T) {
+	services := testServices()
+	buckets := mock.NewBucketService()
+	buckets.FindBucketByIDFn = func(ctx context.Context, id influxdb.ID) (*influxdb.Bucket, error) {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: "bucket not found"}
+	}
+	services.BucketService = buckets
+
+	_, err := schemadoc.NewService(services).FindSchemaDoc(context.Background(), 2)
+	if influxdb.ErrorCode(err) != influxdb.ENotFound {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	doc := &influxdb.SchemaDoc{
+		BucketName:  "telegraf",
+		Description: "Metrics of the hosts.",
+		Measurements: []*influxdb.MeasurementDoc{
+			{
+				Name:        "cpu",
+				Description: "CPU usage",
+				Tags:        []influxdb.SchemaDocColumn{{Name: "host", Description: "Name of the host | FQDN"}},
+				Fields: []influxdb.SchemaDocColumn{
+					{Name: "usage_user", Description: "Time spent\nin user space", DataType: influxdb.SchemaColumnDataTypeFloat, Unit: "percent"},
+				},
+			},
+		},
+		Truncated:   true,
+		GeneratedAt: time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	if err := schemadoc.WriteMarkdown(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"# telegraf",
+		"",
+		"Metrics of the hosts.",
+		"",
+		"## cpu",
+		"",
+		"CPU usage",
+		"",
+		"### Tags",
+		"",
+		"| Tag | Description |",
+		"| --- | --- |",
+		`| host | Name of the host \| FQDN |`,
+		"",
+		"### Fields",
+		"",
+		"| Field | Type | Unit | Description |",
+		"| --- | --- | --- | --- |",
+		"| usage_user | float | percent | Time spent in user space |",
+		"",
+		"_The bucket has more measurements, tags or fields than are documented._",
+		"",
+		"_Generated at 2019-07-01T12:00:00Z._",
+		"",
+	}, "\n")
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("unexpected markdown (-want +got):\n%s", diff)
+	}
+}
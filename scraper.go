@@ -2,6 +2,11 @@ package influxdb
 
 import (
 	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
 )
 
 // ErrScraperTargetNotFound is the error msg for a missing scraper target.
@@ -24,6 +29,127 @@ type ScraperTarget struct {
 	URL      string      `json:"url"`
 	OrgID    ID          `json:"orgID,omitempty"`
 	BucketID ID          `json:"bucketID,omitempty"`
+
+	// Interval is how often the target is scraped, as a duration such as 30s.
+	// Targets without one are scraped at the interval of the scheduler.
+	Interval string `json:"interval,omitempty"`
+
+	// AuthMethod is none, basic or bearer.
+	AuthMethod string       `json:"authMethod,omitempty"`
+	Username   *SecretField `json:"username,omitempty"`
+	Password   *SecretField `json:"password,omitempty"`
+	Token      *SecretField `json:"token,omitempty"`
+	// Headers are added to the scrape requests.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	TLS *ScraperTLSConfig `json:"tls,omitempty"`
+}
+
+// ScraperTLSConfig is how a scraper target served over https is verified.
+type ScraperTLSConfig struct {
+	// CACert is the PEM encoded certificate of the authority that signed the
+	// certificate of the target, used instead of the system roots.
+	CACert string `json:"caCert,omitempty"`
+	// ServerName is the name the certificate of the target is verified against,
+	// if it differs from the host of its url.
+	ServerName         string `json:"serverName,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+}
+
+// Valid returns an error if the interval, authentication, headers or TLS
+// config of the scraper target are invalid.
+func (t *ScraperTarget) Valid() error {
+	if t.Interval != "" {
+		d, err := time.ParseDuration(t.Interval)
+		if err != nil || d <= 0 {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("invalid scraper target interval %q: must be a positive duration such as 30s", t.Interval),
+			}
+		}
+	}
+
+	switch t.AuthMethod {
+	case "", HTTPAuthNone:
+	case HTTPAuthBasic:
+		if t.Username.Empty() || t.Password.Empty() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "basic auth needs a username and a password",
+			}
+		}
+	case HTTPAuthBearer:
+		if t.Token.Empty() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "bearer auth needs a token",
+			}
+		}
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid auth method %q: must be none, basic or bearer", t.AuthMethod),
+		}
+	}
+
+	for k := range t.Headers {
+		if strings.TrimSpace(k) == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "scraper target header names cannot be empty",
+			}
+		}
+		if http.CanonicalHeaderKey(k) == "Authorization" && t.AuthMethod != "" && t.AuthMethod != HTTPAuthNone {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "the Authorization header cannot be set with an auth method",
+			}
+		}
+	}
+
+	if t.TLS != nil && t.TLS.CACert != "" {
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(t.TLS.CACert)) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "scraper target caCert has no PEM encoded certificate",
+			}
+		}
+	}
+	return nil
+}
+
+// ScrapeInterval returns the interval of the scraper target, or def if it has none.
+func (t *ScraperTarget) ScrapeInterval(def time.Duration) time.Duration {
+	d, err := time.ParseDuration(t.Interval)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+// SecretFields returns the credentials the scraper target has, by name.
+func (t *ScraperTarget) SecretFields() map[string]*SecretField {
+	fs := map[string]*SecretField{}
+	for name, f := range map[string]*SecretField{
+		"username": t.Username,
+		"password": t.Password,
+		"token":    t.Token,
+	} {
+		if !f.Empty() {
+			fs[name] = f
+		}
+	}
+	return fs
+}
+
+// BackfillSecretKeys names the secrets of the credentials given by value
+// after the scraper target, so each target keeps its own secrets.
+func (t *ScraperTarget) BackfillSecretKeys() {
+	for name, f := range t.SecretFields() {
+		if f.Key == "" && f.Value != nil {
+			f.Key = fmt.Sprintf("%s-%s", t.ID, name)
+		}
+	}
 }
 
 // ScraperTargetStoreService defines the crud service for ScraperTarget.
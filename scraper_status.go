@@ -0,0 +1,63 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ops for scraper target status errors.
+const (
+	OpPutTargetStatus  = "PutTargetStatus"
+	OpFindTargetStatus = "FindTargetStatus"
+)
+
+// ErrScraperTargetStatusNotFound is the error msg for a scraper target that was not scraped yet.
+const ErrScraperTargetStatusNotFound = "scraper target has not been scraped yet"
+
+// Health of scraper targets.
+const (
+	// ScraperTargetHealthy targets were scraped successfully the last time.
+	ScraperTargetHealthy = "healthy"
+	// ScraperTargetFailing targets failed to be scraped the last time.
+	ScraperTargetFailing = "failing"
+	// ScraperTargetUnknown targets have not been scraped yet.
+	ScraperTargetUnknown = "unknown"
+)
+
+// ScraperTargetStatus is the outcome of the last scrape of a scraper target.
+type ScraperTargetStatus struct {
+	TargetID ID `json:"targetID"`
+	OrgID    ID `json:"orgID,omitempty"`
+
+	// LastScrape is when the last scrape started.
+	LastScrape time.Time `json:"lastScrape"`
+	// Duration is how long the last scrape took in milliseconds.
+	Duration int64 `json:"duration"`
+	// Samples is the number of metrics the last scrape gathered.
+	Samples int `json:"samples"`
+	// LastError is the error of the last scrape, empty when it succeeded.
+	LastError string `json:"lastError,omitempty"`
+
+	// LastSuccess is when the last successful scrape started, if any.
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	// ConsecutiveFailures is the number of scrapes that failed since the last successful one.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+}
+
+// Health returns whether the last scrape of the target succeeded.
+func (s *ScraperTargetStatus) Health() string {
+	if s.LastError != "" {
+		return ScraperTargetFailing
+	}
+	return ScraperTargetHealthy
+}
+
+// ScraperTargetStatusService keeps track of the outcome of the scrapes of scraper targets.
+type ScraperTargetStatusService interface {
+	// PutTargetStatus records the outcome of the last scrape of a target. The success
+	// and failure history is carried over from the status it replaces.
+	PutTargetStatus(ctx context.Context, s *ScraperTargetStatus) error
+
+	// FindTargetStatus returns the outcome of the last scrape of a target.
+	FindTargetStatus(ctx context.Context, targetID ID) (*ScraperTargetStatus, error)
+}
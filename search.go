@@ -0,0 +1,104 @@
+package influxdb
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// ops for search errors.
+const (
+	OpSearch = "Search"
+)
+
+// SearchResourceTypes are the types of resources that can be searched.
+var SearchResourceTypes = []ResourceType{
+	BucketsResourceType,
+	DashboardsResourceType,
+	LabelsResourceType,
+	TasksResourceType,
+	TelegrafsResourceType,
+}
+
+// SearchService finds resources by their names and descriptions.
+type SearchService interface {
+	// Search returns the resources whose names or descriptions match every
+	// term of the query, best matches first.
+	Search(ctx context.Context, filter SearchFilter, opt ...FindOptions) ([]*SearchResult, error)
+}
+
+// SearchResult is a resource that matches a search query.
+type SearchResult struct {
+	Type        ResourceType `json:"type"`
+	ID          ID           `json:"id"`
+	OrgID       ID           `json:"orgID"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	// Score is how well the resource matches the query; the higher the better.
+	Score int `json:"score"`
+}
+
+// SearchFilter represents a set of filters that restrict the returned results.
+type SearchFilter struct {
+	// Query are the terms to search for. Terms match words of names and
+	// descriptions exactly, by prefix, or with a typo.
+	Query string
+	OrgID *ID
+	// Types restricts the results to the types of resources, or to none if empty.
+	Types []ResourceType
+}
+
+// QueryParams converts SearchFilter fields to url query params.
+func (f SearchFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{
+		"q": {f.Query},
+	}
+
+	if f.OrgID != nil {
+		qp["orgID"] = []string{f.OrgID.String()}
+	}
+
+	for _, t := range f.Types {
+		qp["type"] = append(qp["type"], string(t))
+	}
+
+	return qp
+}
+
+// Valid returns an error if the filter has no terms or types that cannot be searched.
+func (f SearchFilter) Valid() error {
+	if len(SearchTerms(f.Query)) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "search query must have at least one word",
+		}
+	}
+
+	for _, t := range f.Types {
+		if !containsResourceType(SearchResourceTypes, t) {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "resources of type " + string(t) + " cannot be searched",
+			}
+		}
+	}
+	return nil
+}
+
+// SearchTerms splits text into its distinct lowercase words, the terms that
+// are searched for and that resources are found by.
+func SearchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := words[:0]
+	seen := map[string]bool{}
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
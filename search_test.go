@@ -0,0 +1,47 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := influxdb.SearchTerms("CPU usage: cpu_usage-per host, ÜBER")
+	want := []string{"cpu", "usage", "per", "host", "über"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("terms are different -got/+want\ndiff %s", diff)
+	}
+}
+
+func TestSearchFilter_Valid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter influxdb.SearchFilter
+		valid  bool
+	}{
+		{name: "query", filter: influxdb.SearchFilter{Query: "cpu"}, valid: true},
+		{name: "no words", filter: influxdb.SearchFilter{Query: " -- "}},
+		{
+			name:   "searchable type",
+			filter: influxdb.SearchFilter{Query: "cpu", Types: []influxdb.ResourceType{influxdb.TasksResourceType}},
+			valid:  true,
+		},
+		{
+			name:   "type that cannot be searched",
+			filter: influxdb.SearchFilter{Query: "cpu", Types: []influxdb.ResourceType{influxdb.UsersResourceType}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Valid()
+			if tt.valid && err != nil {
+				t.Errorf("expected the filter to be valid, got %v", err)
+			}
+			if !tt.valid && influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected the filter to be invalid, got %v", err)
+			}
+		})
+	}
+}
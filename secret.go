@@ -1,10 +1,20 @@
 package influxdb
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ErrSecretNotFound is the error msg for a missing secret.
 const ErrSecretNotFound = "secret not found"
 
+// ErrSecretVersionNotFound is the error msg for a missing version of a secret.
+const ErrSecretVersionNotFound = "secret version not found"
+
+// ErrSecretRotationNotSupported is the error msg of secret stores that do not
+// keep the versions of secrets.
+const ErrSecretRotationNotSupported = "secret rotation is not supported by this secret store"
+
 // SecretService a service for storing and retrieving secrets.
 type SecretService interface {
 	// LoadSecret retrieves the secret value v found at key k for organization orgID.
@@ -25,3 +35,74 @@ type SecretService interface {
 	// DeleteSecret removes a single secret from the secret store.
 	DeleteSecret(ctx context.Context, orgID ID, ks ...string) error
 }
+
+// SecretKeys returns the keys of the secrets of an organization, for secret
+// stores that load all the secrets of an organization at once.
+func SecretKeys(secrets map[string]string) []string {
+	keys := make([]string, 0, len(secrets))
+	for k := range secrets {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SecretVersion is a version of the value of a secret. Every change to the
+// value of a secret is a new version of it.
+type SecretVersion struct {
+	OrgID ID     `json:"orgID"`
+	Key   string `json:"key"`
+	// Version numbers the versions of a secret from 1.
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SecretRotationService keeps the versions of the values of secrets, so that
+// secrets can be rotated.
+type SecretRotationService interface {
+	// FindSecretVersions returns the versions of the secret at key k for
+	// organization orgID, latest first.
+	FindSecretVersions(ctx context.Context, orgID ID, k string) ([]*SecretVersion, error)
+
+	// LoadSecretVersion retrieves the value of a version of the secret at key
+	// k for organization orgID.
+	LoadSecretVersion(ctx context.Context, orgID ID, k string, version int) (string, error)
+
+	// RotateSecret stores v as the new value of the existing secret at key k
+	// for organization orgID, and notifies the rotation hooks of the new version.
+	RotateSecret(ctx context.Context, orgID ID, k string, v string) (*SecretVersion, error)
+}
+
+var _ SecretRotationService = UnsupportedSecretRotationService{}
+
+// UnsupportedSecretRotationService is the SecretRotationService of secret
+// stores whose versions of secrets are not kept by influxdb. Every method
+// returns an unavailable error.
+type UnsupportedSecretRotationService struct{}
+
+func (UnsupportedSecretRotationService) err() error {
+	return &Error{
+		Code: EUnavailable,
+		Msg:  ErrSecretRotationNotSupported,
+	}
+}
+
+// FindSecretVersions returns an unavailable error.
+func (s UnsupportedSecretRotationService) FindSecretVersions(ctx context.Context, orgID ID, k string) ([]*SecretVersion, error) {
+	return nil, s.err()
+}
+
+// LoadSecretVersion returns an unavailable error.
+func (s UnsupportedSecretRotationService) LoadSecretVersion(ctx context.Context, orgID ID, k string, version int) (string, error) {
+	return "", s.err()
+}
+
+// RotateSecret returns an unavailable error.
+func (s UnsupportedSecretRotationService) RotateSecret(ctx context.Context, orgID ID, k string, v string) (*SecretVersion, error) {
+	return nil, s.err()
+}
+
+// SecretRotationHook is notified of the new version of a rotated secret.
+// Secrets are loaded by their key when they are used, so resources that use
+// a secret use its new value without being edited; hooks let services that
+// hold on to the value of a secret reload it.
+type SecretRotationHook func(ctx context.Context, v *SecretVersion)
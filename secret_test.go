@@ -0,0 +1,38 @@
+package influxdb_test
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestSecretKeys(t *testing.T) {
+	keys := influxdb.SecretKeys(map[string]string{"b": "2", "a": "1"})
+	sort.Strings(keys)
+	if want := []string{"a", "b"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got keys %v, want %v", keys, want)
+	}
+
+	if keys := influxdb.SecretKeys(map[string]string{}); keys == nil || len(keys) != 0 {
+		t.Errorf("got keys %#v, want an empty list", keys)
+	}
+}
+
+func TestUnsupportedSecretRotationService(t *testing.T) {
+	ctx := context.Background()
+	var svc influxdb.SecretRotationService = influxdb.UnsupportedSecretRotationService{}
+
+	if _, err := svc.FindSecretVersions(ctx, 1, "k"); influxdb.ErrorCode(err) != influxdb.EUnavailable {
+		t.Errorf("expected finding versions not to be supported, got %v", err)
+	}
+	if _, err := svc.LoadSecretVersion(ctx, 1, "k", 1); influxdb.ErrorCode(err) != influxdb.EUnavailable {
+		t.Errorf("expected loading a version not to be supported, got %v", err)
+	}
+	_, err := svc.RotateSecret(ctx, 1, "k", "v")
+	if influxdb.ErrorCode(err) != influxdb.EUnavailable || influxdb.ErrorMessage(err) != influxdb.ErrSecretRotationNotSupported {
+		t.Errorf("expected rotation not to be supported, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// SeriesSchemaDefaultLimit is the number of values listed when a filter has no limit.
+	SeriesSchemaDefaultLimit = 100
+	// SeriesSchemaMaxLimit is the most values that are listed at once.
+	SeriesSchemaMaxLimit = 1000
+)
+
+// SeriesSchemaFilter selects the series of a bucket whose measurements, tag
+// keys or tag values are listed, and the page of the values.
+type SeriesSchemaFilter struct {
+	OrgID    ID
+	BucketID ID
+
+	// Start and Stop limit the series to the ones with points in the time
+	// range. The series of every point are listed if they are zero.
+	Start time.Time
+	Stop  time.Time
+
+	Measurement string
+	Tags        []SeriesTagPredicate
+
+	// Prefix limits the values to the ones that start with it.
+	Prefix string
+	// After and Limit select the page: the values that sort after After, up
+	// to Limit of them.
+	After string
+	Limit int
+}
+
+// Valid returns an error if the filter does not select a bucket and a page.
+func (f SeriesSchemaFilter) Valid() error {
+	if !f.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is invalid",
+		}
+	}
+	if !f.BucketID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "bucketID is invalid",
+		}
+	}
+	if !f.Start.IsZero() && !f.Stop.IsZero() && f.Stop.Before(f.Start) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "stop must not be before start",
+		}
+	}
+	if f.Limit < 0 || f.Limit > SeriesSchemaMaxLimit {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("limit must be between 1 and %d", SeriesSchemaMaxLimit),
+		}
+	}
+	for _, p := range f.Tags {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SeriesSchemaService lists the measurements, tag keys and tag values of the
+// series of buckets from the metadata of the storage engine, without querying
+// the points. Values are listed in lexicographical order.
+type SeriesSchemaService interface {
+	// FindMeasurements returns the measurements of the series selected by the filter.
+	FindMeasurements(ctx context.Context, filter SeriesSchemaFilter) ([]string, error)
+
+	// FindTagKeys returns the tag keys of the series selected by the filter.
+	FindTagKeys(ctx context.Context, filter SeriesSchemaFilter) ([]string, error)
+
+	// FindTagValues returns the values of a tag key of the series selected by
+	// the filter. The _measurement and _field keys list measurements and fields.
+	FindTagValues(ctx context.Context, tagKey string, filter SeriesSchemaFilter) ([]string, error)
+}
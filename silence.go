@@ -0,0 +1,160 @@
+package influxdb
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ErrSilenceNotFound is the error msg for a missing silence.
+const ErrSilenceNotFound = "silence not found"
+
+// ops for silence errors and op log.
+const (
+	OpFindSilenceByID = "FindSilenceByID"
+	OpFindSilences    = "FindSilences"
+	OpCreateSilence   = "CreateSilence"
+	OpExpireSilence   = "ExpireSilence"
+)
+
+// SilenceState is the state of a silence at a point in time.
+type SilenceState string
+
+// Silence states.
+const (
+	// SilencePending is the state of a silence that did not start yet.
+	SilencePending SilenceState = "pending"
+	// SilenceActive is the state of a silence that suppresses notifications.
+	SilenceActive SilenceState = "active"
+	// SilenceExpired is the state of a silence that ended.
+	SilenceExpired SilenceState = "expired"
+)
+
+// Valid returns an error if the state is unknown.
+func (s SilenceState) Valid() error {
+	switch s {
+	case SilencePending, SilenceActive, SilenceExpired:
+		return nil
+	}
+	return &Error{
+		Code: EInvalid,
+		Msg:  "silence state must be pending, active or expired",
+	}
+}
+
+// Silence suppresses the notifications of the check statuses it matches
+// between StartsAt and EndsAt. A silence matches the statuses of its check
+// that its tag rules match, and only suppresses the notifications of its
+// rule if it has one.
+type Silence struct {
+	ID    ID `json:"id,omitempty"`
+	OrgID ID `json:"orgID,omitempty"`
+
+	CheckID  ID        `json:"checkID,omitempty"`
+	RuleID   ID        `json:"ruleID,omitempty"`
+	TagRules []TagRule `json:"tagRules,omitempty"`
+
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+
+	// Comment tells why the notifications are suppressed.
+	Comment string `json:"comment"`
+	// CreatedBy is the user that created the silence.
+	CreatedBy ID `json:"createdBy,omitempty"`
+
+	CRUDLog
+}
+
+// Valid returns an error if the silence is missing what it needs.
+func (s *Silence) Valid() error {
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if strings.TrimSpace(s.Comment) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence comment is required",
+		}
+	}
+	if !s.CheckID.Valid() && !s.RuleID.Valid() && len(s.TagRules) == 0 {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence needs a checkID, a ruleID or at least one tag rule",
+		}
+	}
+	for _, tr := range s.TagRules {
+		if err := tr.Valid(); err != nil {
+			return err
+		}
+	}
+	if s.EndsAt.IsZero() || !s.EndsAt.After(s.StartsAt) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "silence must end after it starts",
+		}
+	}
+	return nil
+}
+
+// State returns the state of the silence at t.
+func (s *Silence) State(t time.Time) SilenceState {
+	switch {
+	case t.Before(s.StartsAt):
+		return SilencePending
+	case t.Before(s.EndsAt):
+		return SilenceActive
+	default:
+		return SilenceExpired
+	}
+}
+
+// Matches reports whether the silence suppresses the notification of the
+// rule for status.
+func (s *Silence) Matches(ruleID ID, status *CheckStatus) bool {
+	if s.State(status.Time) != SilenceActive {
+		return false
+	}
+	if s.RuleID.Valid() && s.RuleID != ruleID {
+		return false
+	}
+	if s.CheckID.Valid() && s.CheckID != status.CheckID {
+		return false
+	}
+	for _, tr := range s.TagRules {
+		if !tr.Matches(status.Tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// SilenceFilter selects silences.
+type SilenceFilter struct {
+	ID      *ID
+	OrgID   *ID
+	CheckID *ID
+	RuleID  *ID
+	// State selects the silences in the state now.
+	State *SilenceState
+}
+
+// SilenceService manages the silences of organizations.
+type SilenceService interface {
+	// FindSilenceByID returns a single silence by ID.
+	FindSilenceByID(ctx context.Context, id ID) (*Silence, error)
+
+	// FindSilences returns the silences that match the filter, and the
+	// total count of matching silences.
+	FindSilences(ctx context.Context, filter SilenceFilter, opt ...FindOptions) ([]*Silence, int, error)
+
+	// CreateSilence creates a silence and sets its ID. Silences without a
+	// start time start now.
+	CreateSilence(ctx context.Context, s *Silence) error
+
+	// ExpireSilence ends a silence now. Silences that ended already are
+	// left as they are.
+	ExpireSilence(ctx context.Context, id ID) (*Silence, error)
+}
@@ -0,0 +1,84 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestSilence_Matches(t *testing.T) {
+	t0 := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	s := &influxdb.Silence{
+		CheckID:  2,
+		TagRules: []influxdb.TagRule{{Key: "host", Value: "^web", Operator: influxdb.TagRuleEqualRegex}},
+		StartsAt: t0,
+		EndsAt:   t0.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name    string
+		ruleID  influxdb.ID
+		checkID influxdb.ID
+		host    string
+		time    time.Time
+		want    bool
+	}{
+		{name: "within the window", ruleID: 1, checkID: 2, host: "web1", time: t0, want: true},
+		{name: "before the window", ruleID: 1, checkID: 2, host: "web1", time: t0.Add(-time.Second)},
+		{name: "at the end of the window", ruleID: 1, checkID: 2, host: "web1", time: t0.Add(time.Hour)},
+		{name: "other check", ruleID: 1, checkID: 3, host: "web1", time: t0},
+		{name: "tag rule does not match", ruleID: 1, checkID: 2, host: "db1", time: t0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &influxdb.CheckStatus{CheckID: tt.checkID, Time: tt.time, Tags: map[string]string{"host": tt.host}}
+			if got := s.Matches(tt.ruleID, status); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	s.RuleID = 4
+	if s.Matches(1, &influxdb.CheckStatus{CheckID: 2, Time: t0, Tags: map[string]string{"host": "web1"}}) {
+		t.Error("expected the silence of a rule not to match the notifications of other rules")
+	}
+}
+
+func TestSilence_Valid(t *testing.T) {
+	t0 := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	valid := func() *influxdb.Silence {
+		return &influxdb.Silence{
+			OrgID:    1,
+			CheckID:  2,
+			StartsAt: t0,
+			EndsAt:   t0.Add(time.Hour),
+			Comment:  "maintenance",
+		}
+	}
+	if err := valid().Valid(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(s *influxdb.Silence)
+	}{
+		{name: "no comment", modify: func(s *influxdb.Silence) { s.Comment = " " }},
+		{name: "no matcher", modify: func(s *influxdb.Silence) { s.CheckID = 0 }},
+		{name: "no end", modify: func(s *influxdb.Silence) { s.EndsAt = time.Time{} }},
+		{name: "ends when it starts", modify: func(s *influxdb.Silence) { s.EndsAt = s.StartsAt }},
+		{name: "invalid tag rule", modify: func(s *influxdb.Silence) {
+			s.TagRules = []influxdb.TagRule{{Key: "host", Operator: "gt"}}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid()
+			tt.modify(s)
+			if err := s.Valid(); influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Errorf("expected the silence to be invalid, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// ErrStackNotFound is the error msg for a missing stack.
+const ErrStackNotFound = "stack not found"
+
+// ops for stack errors and op log.
+const (
+	OpFindStackByID = "FindStackByID"
+	OpFindStacks    = "FindStacks"
+	OpCreateStack   = "CreateStack"
+	OpUpdateStack   = "UpdateStack"
+	OpDeleteStack   = "DeleteStack"
+)
+
+// Stack is a package applied to an organization along with the resources it
+// owns: those that applying the package created. Applying a package with a
+// stack only touches the resources the stack owns.
+type Stack struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Package is the JSON of the package last applied with the stack.
+	Package json.RawMessage `json:"package,omitempty"`
+	// Resources are the resources the stack owns.
+	Resources []StackResource `json:"resources"`
+
+	CRUDLog
+}
+
+// StackResource is a resource owned by a stack.
+type StackResource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	ID   ID     `json:"id"`
+}
+
+// Valid returns an error if the stack is missing what it needs.
+func (s *Stack) Valid() error {
+	if !s.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "stack name is required",
+		}
+	}
+	return nil
+}
+
+// StackUpdate is the patch of a stack. Resources replace the resources of
+// the stack unless nil.
+type StackUpdate struct {
+	Name        *string         `json:"name,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Package     json.RawMessage `json:"package,omitempty"`
+	Resources   []StackResource `json:"resources,omitempty"`
+}
+
+// Apply applies the update to the stack.
+func (u StackUpdate) Apply(s *Stack) {
+	if u.Name != nil {
+		s.Name = *u.Name
+	}
+	if u.Description != nil {
+		s.Description = *u.Description
+	}
+	if u.Package != nil {
+		s.Package = u.Package
+	}
+	if u.Resources != nil {
+		s.Resources = u.Resources
+	}
+}
+
+// StackFilter restricts the stacks that are found.
+type StackFilter struct {
+	OrgID *ID
+	Name  *string
+}
+
+// StackService keeps track of the packages applied to organizations and of
+// the resources they own.
+type StackService interface {
+	// FindStackByID returns a single stack by ID.
+	FindStackByID(ctx context.Context, id ID) (*Stack, error)
+
+	// FindStacks returns the stacks that match the filter, ordered by ID.
+	FindStacks(ctx context.Context, filter StackFilter, opt ...FindOptions) ([]*Stack, int, error)
+
+	// CreateStack creates a stack. Stack names are unique within an organization.
+	CreateStack(ctx context.Context, s *Stack) error
+
+	// UpdateStack updates a stack.
+	UpdateStack(ctx context.Context, id ID, upd StackUpdate) (*Stack, error)
+
+	// DeleteStack forgets a stack. The resources it owns are left as they are.
+	DeleteStack(ctx context.Context, id ID) error
+}
@@ -13,6 +13,20 @@ type BucketDeleter interface {
 	DeleteBucket(platform.ID, platform.ID) error
 }
 
+// BucketDeleters deletes a bucket from each of its deleters in turn, such as
+// from the engine and from the services that keep state per bucket.
+type BucketDeleters []BucketDeleter
+
+// DeleteBucket deletes the bucket from every deleter, stopping at the first error.
+func (ds BucketDeleters) DeleteBucket(orgID, bucketID platform.ID) error {
+	for _, d := range ds {
+		if err := d.DeleteBucket(orgID, bucketID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // BucketService wraps an existing platform.BucketService implementation.
 //
 // BucketService ensures that when a bucket is deleted, all stored data
@@ -21,6 +35,9 @@ type BucketDeleter interface {
 type BucketService struct {
 	inner  platform.BucketService
 	engine BucketDeleter
+
+	// keepData is set when deleted buckets are moved to the trash.
+	keepData bool
 }
 
 // NewBucketService returns a new BucketService for the provided BucketDeleter,
@@ -32,6 +49,17 @@ func NewBucketService(s platform.BucketService, engine BucketDeleter) *BucketSer
 	}
 }
 
+// NewTrashBucketService returns a new BucketService for buckets that are moved
+// to the trash when they are deleted. Their data is kept so they can be
+// restored, and is dropped by a TrashService once they are purged.
+func NewTrashBucketService(s platform.BucketService, engine BucketDeleter) *BucketService {
+	return &BucketService{
+		inner:    s,
+		engine:   engine,
+		keepData: true,
+	}
+}
+
 // FindBucketByID returns a single bucket by ID.
 func (s *BucketService) FindBucketByID(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
@@ -99,6 +127,10 @@ func (s *BucketService) DeleteBucket(ctx context.Context, bucketID platform.ID)
 		return err
 	}
 
+	if s.keepData {
+		return s.inner.DeleteBucket(ctx, bucketID)
+	}
+
 	// The data is dropped first from the storage engine. If this fails for any
 	// reason, then the bucket will still be available in the future to retrieve
 	// the orgID, which is needed for the engine.
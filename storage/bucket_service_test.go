@@ -53,6 +53,30 @@ func TestBucketService(t *testing.T) {
 	}
 }
 
+func TestTrashBucketService(t *testing.T) {
+	inmemService := inmem.NewService()
+	org := &platform.Organization{Name: "org1"}
+	if err := inmemService.CreateOrganization(context.TODO(), org); err != nil {
+		panic(err)
+	}
+
+	bucket := &platform.Bucket{OrgID: org.ID}
+	if err := inmemService.CreateBucket(context.TODO(), bucket); err != nil {
+		panic(err)
+	}
+
+	// The data of a bucket that is moved to the trash is kept.
+	deleter := &MockDeleter{}
+	service := storage.NewTrashBucketService(inmemService, deleter)
+
+	if err := service.DeleteBucket(context.TODO(), bucket.ID); err != nil {
+		t.Fatal(err)
+	}
+	if deleter.bucketID.Valid() {
+		t.Errorf("expected the data of bucket %s to be kept", deleter.bucketID)
+	}
+}
+
 type MockDeleter struct {
 	orgID, bucketID platform.ID
 }
@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	// MonitoringBucketID is the fixed ID of the system bucket of an organization
+	// that holds the cardinality snapshots of its buckets.
+	MonitoringBucketID influxdb.ID = 11
+
+	seriesCardinalityMeasurement   = "series_cardinality"
+	tagValueCardinalityMeasurement = "tag_value_cardinality"
+	bucketIDTag                    = "bucketID"
+	tagKeyTag                      = "tagKey"
+	cardinalityField               = "n"
+)
+
+// A CardinalityEngine reports the cardinality of buckets and stores the snapshots.
+type CardinalityEngine interface {
+	BucketCardinality(orgID, bucketID influxdb.ID) (int64, map[string]int64, error)
+	WritePoints(ctx context.Context, points []models.Point) error
+}
+
+// The cardinalityRecorder writes the cardinality of every bucket to the
+// monitoring bucket of its organization.
+type cardinalityRecorder struct {
+	Engine        CardinalityEngine
+	BucketService BucketFinder
+
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+func newCardinalityRecorder(engine CardinalityEngine, finder BucketFinder) *cardinalityRecorder {
+	return &cardinalityRecorder{
+		Engine:        engine,
+		BucketService: finder,
+		now:           time.Now,
+		logger:        zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger l on the recorder. It must be called before any run calls.
+func (r *cardinalityRecorder) WithLogger(l *zap.Logger) {
+	if r == nil {
+		return // Not initialised
+	}
+	r.logger = l.With(zap.String("component", "cardinality_recorder"))
+}
+
+// run records a snapshot of every bucket. Snapshots are timestamped at the start
+// of the interval, so a snapshot taken again in the same interval, such as after
+// a restart, replaces the previous one.
+func (r *cardinalityRecorder) run(ctx context.Context, interval time.Duration) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	log, logEnd := logger.NewOperation(ctx, r.logger, "Cardinality snapshot", "cardinality_snapshot")
+	defer logEnd()
+
+	ctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
+	buckets, _, err := r.BucketService.FindBuckets(ctx, influxdb.BucketFilter{})
+	cancel()
+	if err != nil {
+		log.Error("Unable to determine bucket information", zap.Error(err))
+		return
+	}
+
+	ts := r.now().UTC().Truncate(interval)
+	byOrg := make(map[influxdb.ID]models.Points)
+	for _, b := range buckets {
+		points, err := r.snapshot(b, ts)
+		if err != nil {
+			log.Info("Unable to determine bucket cardinality",
+				zap.String("bucket_id", b.ID.String()),
+				zap.String("org_id", b.OrgID.String()),
+				zap.Error(err))
+			continue
+		}
+		byOrg[b.OrgID] = append(byOrg[b.OrgID], points...)
+	}
+
+	for orgID, points := range byOrg {
+		exploded, err := tsdb.ExplodePoints(orgID, MonitoringBucketID, points)
+		if err == nil {
+			err = r.Engine.WritePoints(context.Background(), exploded)
+		}
+		if err != nil {
+			log.Error("Unable to write cardinality snapshots", zap.String("org_id", orgID.String()), zap.Error(err))
+		}
+	}
+}
+
+// snapshot returns the points that record the cardinality of b at ts.
+func (r *cardinalityRecorder) snapshot(b *influxdb.Bucket, ts time.Time) (models.Points, error) {
+	series, tagValues, err := r.Engine.BucketCardinality(b.OrgID, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketID := b.ID.String()
+	p, err := models.NewPoint(seriesCardinalityMeasurement,
+		models.NewTags(map[string]string{bucketIDTag: bucketID}),
+		models.Fields{cardinalityField: series}, ts)
+	if err != nil {
+		return nil, err
+	}
+	points := models.Points{p}
+
+	for key, n := range tagValues {
+		p, err := models.NewPoint(tagValueCardinalityMeasurement,
+			models.NewTags(map[string]string{bucketIDTag: bucketID, tagKeyTag: key}),
+			models.Fields{cardinalityField: n}, ts)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+var _ influxdb.CardinalityService = (*CardinalityService)(nil)
+
+// CardinalityService reads the cardinality snapshots of buckets from the monitoring
+// bucket, and the current cardinality of buckets from the engine.
+type CardinalityService struct {
+	engine CardinalityEngine
+	qs     query.QueryService
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewCardinalityService returns a new CardinalityService that queries the monitoring bucket with qs.
+func NewCardinalityService(engine CardinalityEngine, qs query.QueryService, logger *zap.Logger) *CardinalityService {
+	return &CardinalityService{
+		engine: engine,
+		qs:     qs,
+		now:    time.Now,
+		logger: logger.With(zap.String("service", "cardinality")),
+	}
+}
+
+// FindCardinalityTrend returns the snapshots of a bucket in the time range of the filter, oldest first.
+func (s *CardinalityService) FindCardinalityTrend(ctx context.Context, filter influxdb.CardinalityTrendFilter) ([]*influxdb.CardinalitySnapshot, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := filter.Valid(); err != nil {
+		return nil, err
+	}
+
+	auth, err := icontext.GetAuthorization(ctx, filter.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: %s, stop: %s)
+	|> filter(fn: (r) => r.%s == %q and r._field == %q)`,
+		MonitoringBucketID.String(),
+		filter.Start.UTC().Format(time.RFC3339Nano), filter.Stop.UTC().Format(time.RFC3339Nano),
+		bucketIDTag, filter.BucketID.String(), cardinalityField)
+
+	it, err := s.qs.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: filter.OrgID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	cr := &cardinalityReader{snapshots: make(map[int64]*influxdb.CardinalitySnapshot)}
+	for it.More() {
+		if err := it.Next().Tables().Do(cr.readTable); err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return cr.trend(), nil
+}
+
+// FindCurrentCardinality returns the cardinality of a bucket as it is recorded in the index now.
+func (s *CardinalityService) FindCurrentCardinality(ctx context.Context, orgID, bucketID influxdb.ID) (*influxdb.CardinalitySnapshot, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	series, tagValues, err := s.engine.BucketCardinality(orgID, bucketID)
+	if err != nil {
+		return nil, err
+	}
+	return &influxdb.CardinalitySnapshot{
+		Time:      s.now().UTC(),
+		Series:    series,
+		TagValues: tagValues,
+	}, nil
+}
+
+// cardinalityReader collects the snapshots from the tables of the trend query.
+type cardinalityReader struct {
+	snapshots map[int64]*influxdb.CardinalitySnapshot
+}
+
+func (cr *cardinalityReader) readTable(tbl flux.Table) error {
+	return tbl.Do(cr.readSnapshots)
+}
+
+func (cr *cardinalityReader) readSnapshots(r flux.ColReader) error {
+	timeIdx, valueIdx, measurementIdx, tagKeyIdx := -1, -1, -1, -1
+	for j, col := range r.Cols() {
+		switch col.Label {
+		case "_time":
+			timeIdx = j
+		case "_value":
+			valueIdx = j
+		case "_measurement":
+			measurementIdx = j
+		case tagKeyTag:
+			tagKeyIdx = j
+		}
+	}
+	if timeIdx < 0 || valueIdx < 0 || measurementIdx < 0 {
+		return nil
+	}
+
+	for i := 0; i < r.Len(); i++ {
+		if !r.Times(timeIdx).IsValid(i) || !r.Ints(valueIdx).IsValid(i) {
+			continue
+		}
+		ts := r.Times(timeIdx).Value(i)
+		n := r.Ints(valueIdx).Value(i)
+
+		s, ok := cr.snapshots[ts]
+		if !ok {
+			s = &influxdb.CardinalitySnapshot{
+				Time:      time.Unix(0, ts).UTC(),
+				TagValues: make(map[string]int64),
+			}
+			cr.snapshots[ts] = s
+		}
+
+		switch r.Strings(measurementIdx).ValueString(i) {
+		case seriesCardinalityMeasurement:
+			s.Series = n
+		case tagValueCardinalityMeasurement:
+			if tagKeyIdx >= 0 {
+				s.TagValues[r.Strings(tagKeyIdx).ValueString(i)] = n
+			}
+		}
+	}
+	return nil
+}
+
+// trend returns the collected snapshots, oldest first.
+func (cr *cardinalityReader) trend() []*influxdb.CardinalitySnapshot {
+	trend := make([]*influxdb.CardinalitySnapshot, 0, len(cr.snapshots))
+	for _, s := range cr.snapshots {
+		trend = append(trend, s)
+	}
+	sort.Slice(trend, func(i, j int) bool {
+		return trend[i].Time.Before(trend[j].Time)
+	})
+	return trend
+}
@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+type testCardinalityEngine struct {
+	cardinality map[influxdb.ID]map[string]int64
+	written     []models.Point
+}
+
+func (e *testCardinalityEngine) BucketCardinality(orgID, bucketID influxdb.ID) (int64, map[string]int64, error) {
+	tagValues, ok := e.cardinality[bucketID]
+	if !ok {
+		return 0, nil, errors.New("bucket not found")
+	}
+	return int64(len(tagValues)) * 10, tagValues, nil
+}
+
+func (e *testCardinalityEngine) WritePoints(ctx context.Context, points []models.Point) error {
+	e.written = append(e.written, points...)
+	return nil
+}
+
+func TestCardinalityRecorder(t *testing.T) {
+	engine := &testCardinalityEngine{
+		cardinality: map[influxdb.ID]map[string]int64{
+			2: {"_measurement": 1, "host": 3},
+			3: {},
+		},
+	}
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return []*influxdb.Bucket{
+			{ID: 2, OrgID: 1},
+			{ID: 3, OrgID: 1},
+			{ID: 4, OrgID: 1}, // Its cardinality can't be determined, so it is skipped.
+		}, 3, nil
+	}
+
+	r := newCardinalityRecorder(engine, finder)
+	r.now = func() time.Time { return time.Date(2019, 7, 1, 13, 14, 15, 0, time.UTC) }
+	r.run(context.Background(), 24*time.Hour)
+
+	type snapshot struct {
+		measurement, bucketID, tagKey string
+		n                             int64
+	}
+	var got []snapshot
+	for _, p := range engine.written {
+		if name := tsdb.EncodeNameString(1, MonitoringBucketID); string(p.Name()) != name {
+			t.Fatalf("expected point to be written to the monitoring bucket, got name %q", p.Name())
+		}
+		if want := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC); !p.Time().Equal(want) {
+			t.Errorf("got time %v, want %v", p.Time(), want)
+		}
+
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, snapshot{
+			measurement: string(p.Tags().Get(models.MeasurementTagKeyBytes)),
+			bucketID:    string(p.Tags().Get([]byte(bucketIDTag))),
+			tagKey:      string(p.Tags().Get([]byte(tagKeyTag))),
+			n:           fields[cardinalityField].(int64),
+		})
+	}
+
+	want := []snapshot{
+		{measurement: seriesCardinalityMeasurement, bucketID: "0000000000000002", n: 20},
+		{measurement: tagValueCardinalityMeasurement, bucketID: "0000000000000002", tagKey: "_measurement", n: 1},
+		{measurement: tagValueCardinalityMeasurement, bucketID: "0000000000000002", tagKey: "host", n: 3},
+		{measurement: seriesCardinalityMeasurement, bucketID: "0000000000000003", n: 0},
+	}
+	// Tag keys are written in map order.
+	if len(got) == len(want) && got[1].tagKey == "host" {
+		got[1], got[2] = got[2], got[1]
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got snapshots %+v, want %+v", got, want)
+	}
+}
+
+func TestCardinalityService_FindCardinalityTrend(t *testing.T) {
+	day := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	t0, t1 := execute.Time(day.UnixNano()), execute.Time(day.Add(24*time.Hour).UnixNano())
+
+	var script string
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			script = req.Compiler.(lang.FluxCompiler).Query
+			return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{
+					{
+						KeyCols: []string{"_measurement", "_field", "bucketID"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_value", Type: flux.TInt},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "_field", Type: flux.TString},
+							{Label: "bucketID", Type: flux.TString},
+						},
+						Data: [][]interface{}{
+							{t1, int64(1000), seriesCardinalityMeasurement, "n", "0000000000000002"},
+							{t0, int64(10), seriesCardinalityMeasurement, "n", "0000000000000002"},
+						},
+					},
+					{
+						KeyCols: []string{"_measurement", "_field", "bucketID", "tagKey"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_value", Type: flux.TInt},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "_field", Type: flux.TString},
+							{Label: "bucketID", Type: flux.TString},
+							{Label: "tagKey", Type: flux.TString},
+						},
+						Data: [][]interface{}{
+							{t0, int64(5), tagValueCardinalityMeasurement, "n", "0000000000000002", "host"},
+							{t1, int64(995), tagValueCardinalityMeasurement, "n", "0000000000000002", "request_id"},
+						},
+					},
+				},
+			}}), nil
+		},
+	}
+
+	s := NewCardinalityService(&testCardinalityEngine{}, qs, zaptest.NewLogger(t))
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{OrgID: 1})
+	trend, err := s.FindCardinalityTrend(ctx, influxdb.CardinalityTrendFilter{
+		OrgID:    1,
+		BucketID: 2,
+		Start:    day,
+		Stop:     day.Add(48 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`from(bucketID: "000000000000000b")`, `range(start: 2019-07-01T00:00:00Z, stop: 2019-07-03T00:00:00Z)`, `r.bucketID == "0000000000000002"`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected query to contain %q, got:\n%s", want, script)
+		}
+	}
+
+	want := []*influxdb.CardinalitySnapshot{
+		{Time: day, Series: 10, TagValues: map[string]int64{"host": 5}},
+		{Time: day.Add(24 * time.Hour), Series: 1000, TagValues: map[string]int64{"request_id": 995}},
+	}
+	if !reflect.DeepEqual(trend, want) {
+		t.Errorf("got trend %+v, want %+v", trend, want)
+	}
+}
+
+func TestCardinalityService_FindCurrentCardinality(t *testing.T) {
+	now := time.Date(2019, 7, 1, 13, 14, 15, 0, time.UTC)
+	engine := &testCardinalityEngine{
+		cardinality: map[influxdb.ID]map[string]int64{
+			2: {"_measurement": 1, "host": 3},
+		},
+	}
+	s := NewCardinalityService(engine, nil, zaptest.NewLogger(t))
+	s.now = func() time.Time { return now }
+
+	got, err := s.FindCurrentCardinality(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &influxdb.CardinalitySnapshot{Time: now, Series: 20, TagValues: map[string]int64{"_measurement": 1, "host": 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := s.FindCurrentCardinality(context.Background(), 1, 3); err == nil {
+		t.Error("expected an error for a bucket the engine does not know")
+	}
+}
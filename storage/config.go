@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/influxdata/influxdb"
 	"github.com/influxdata/influxdb/toml"
 	"github.com/influxdata/influxdb/tsdb"
 	"github.com/influxdata/influxdb/tsdb/tsi1"
@@ -13,6 +14,9 @@ import (
 // Default configuration values.
 const (
 	DefaultRetentionInterval       = time.Hour
+	DefaultReorderCheckInterval    = time.Second
+	DefaultReorderMaxPoints        = 1000000
+	DefaultCardinalityInterval     = 24 * time.Hour
 	DefaultSeriesFileDirectoryName = "_series"
 	DefaultIndexDirectoryName      = "index"
 	DefaultWALDirectoryName        = "wal"
@@ -24,6 +28,22 @@ type Config struct {
 	// Frequency of retention in seconds.
 	RetentionInterval toml.Duration `toml:"retention-interval"`
 
+	// Frequency at which buckets with a reorder window are flushed.
+	// Zero disables reorder windows; points are then written as they arrive.
+	ReorderCheckInterval toml.Duration `toml:"reorder-check-interval"`
+
+	// Maximum number of points held by reorder windows before they are flushed early.
+	ReorderMaxPoints int `toml:"reorder-max-points"`
+
+	// Frequency at which the cardinality of buckets is recorded in the monitoring bucket.
+	// Zero disables the snapshots.
+	CardinalityInterval toml.Duration `toml:"cardinality-interval"`
+
+	// Daily windows, in UTC and of the form 01:00-05:00, to which full compactions,
+	// retention sweeps and series index rebuilds are confined.
+	// No windows lets them run at any time.
+	MaintenanceWindows []string `toml:"maintenance-windows"`
+
 	// Series file config.
 	SeriesFilePath string `toml:"series-file-path"` // Overrides the default path.
 
@@ -46,12 +66,28 @@ type Config struct {
 // NewConfig initialises a new config for an Engine.
 func NewConfig() Config {
 	return Config{
-		RetentionInterval: toml.Duration(DefaultRetentionInterval),
-		TSDB:              tsdb.NewConfig(),
-		WAL:               tsm1.NewWALConfig(),
-		Engine:            tsm1.NewConfig(),
-		Index:             tsi1.NewConfig(),
+		RetentionInterval:    toml.Duration(DefaultRetentionInterval),
+		ReorderCheckInterval: toml.Duration(DefaultReorderCheckInterval),
+		ReorderMaxPoints:     DefaultReorderMaxPoints,
+		CardinalityInterval:  toml.Duration(DefaultCardinalityInterval),
+		TSDB:                 tsdb.NewConfig(),
+		WAL:                  tsm1.NewWALConfig(),
+		Engine:               tsm1.NewConfig(),
+		Index:                tsi1.NewConfig(),
+	}
+}
+
+// ParseMaintenanceWindows returns the maintenance windows.
+func (c Config) ParseMaintenanceWindows() ([]influxdb.MaintenanceWindow, error) {
+	windows := make([]influxdb.MaintenanceWindow, 0, len(c.MaintenanceWindows))
+	for _, s := range c.MaintenanceWindows {
+		w, err := influxdb.ParseMaintenanceWindow(s)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
 	}
+	return windows, nil
 }
 
 // GetSeriesFilePath returns the path to the series file.
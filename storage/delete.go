@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+// A BucketRangeDeleter is capable of deleting the data of a bucket in a time range,
+// of every series or of the series matching a predicate.
+type BucketRangeDeleter interface {
+	DeleteBucketRange(orgID, bucketID influxdb.ID, min, max int64) error
+	DeleteBucketRangePredicate(orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error
+}
+
+var _ influxdb.DeleteService = (*DeleteService)(nil)
+
+// DeleteService deletes the data of buckets in time ranges, one delete at a time.
+type DeleteService struct {
+	engine BucketRangeDeleter
+	logger *zap.Logger
+
+	mu sync.Mutex // Serializes deletes.
+}
+
+// NewDeleteService returns a new DeleteService for the provided BucketRangeDeleter,
+// which typically will be an Engine.
+func NewDeleteService(engine BucketRangeDeleter, logger *zap.Logger) *DeleteService {
+	return &DeleteService{
+		engine: engine,
+		logger: logger.With(zap.String("service", "delete")),
+	}
+}
+
+// Delete validates the request and deletes the data it selects.
+func (s *DeleteService) Delete(ctx context.Context, req influxdb.DeleteRequest) error {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := req.Valid(); err != nil {
+		return err
+	}
+
+	pred, err := seriesPredicate(req.Measurement, req.Tags)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid delete predicate",
+			Err:  err,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	min, max := req.Start.UnixNano(), req.Stop.UnixNano()
+	if pred == nil {
+		err = s.engine.DeleteBucketRange(req.OrgID, req.BucketID, min, max)
+	} else {
+		err = s.engine.DeleteBucketRangePredicate(req.OrgID, req.BucketID, min, max, pred)
+	}
+	if err != nil {
+		s.logger.Error("Unable to delete data", zap.String("bucket_id", req.BucketID.String()), zap.Error(err))
+		return &influxdb.Error{
+			Code: influxdb.EInternal,
+			Op:   influxdb.OpDelete,
+			Msg:  "unable to delete data",
+			Err:  err,
+		}
+	}
+
+	s.logger.Info("Deleted data",
+		zap.String("org_id", req.OrgID.String()),
+		zap.String("bucket_id", req.BucketID.String()),
+		zap.Time("start", req.Start),
+		zap.Time("stop", req.Stop),
+		zap.String("measurement", req.Measurement))
+	return nil
+}
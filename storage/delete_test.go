@@ -0,0 +1,118 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestDeleteService_Delete(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	p := func(host string, ts time.Time) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{
+				models.FieldKeyTagKey:    "value",
+				models.MeasurementTagKey: "cpu",
+				"host":                   host,
+			}),
+			map[string]interface{}{"value": 1.0},
+			ts,
+		)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		p("a", time.Unix(1, 0)),
+		p("b", time.Unix(1, 0)),
+		p("c", time.Unix(100, 0)),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := storage.NewDeleteService(engine, zaptest.NewLogger(t))
+	if err := s.Delete(context.Background(), influxdb.DeleteRequest{
+		OrgID:    engine.org,
+		BucketID: engine.bucket,
+		Start:    time.Unix(0, 0),
+		Stop:     time.Unix(50, 0),
+		Tags: []influxdb.SeriesTagPredicate{
+			{Key: "host", Op: influxdb.TagPredicateRegex, Value: "^[ac]$"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The series of host a is left without data, but host c has data after the range.
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
+type bucketRangeDeleter struct {
+	min, max int64
+	pred     tsm1.Predicate
+	ranges   int
+}
+
+func (d *bucketRangeDeleter) DeleteBucketRange(orgID, bucketID influxdb.ID, min, max int64) error {
+	d.min, d.max, d.pred = min, max, nil
+	d.ranges++
+	return nil
+}
+
+func (d *bucketRangeDeleter) DeleteBucketRangePredicate(orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error {
+	d.min, d.max, d.pred = min, max, pred
+	return nil
+}
+
+func TestDeleteService_Predicate(t *testing.T) {
+	d := &bucketRangeDeleter{}
+	s := storage.NewDeleteService(d, zaptest.NewLogger(t))
+
+	req := influxdb.DeleteRequest{
+		OrgID:    1,
+		BucketID: 2,
+		Start:    time.Unix(0, 10),
+		Stop:     time.Unix(0, 20),
+	}
+	if err := s.Delete(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if d.ranges != 1 || d.min != 10 || d.max != 20 {
+		t.Fatalf("expected the whole range of the bucket to be deleted, got [%d, %d] in %d deletes", d.min, d.max, d.ranges)
+	}
+
+	req.Tags = []influxdb.SeriesTagPredicate{{Key: "host", Value: "a"}}
+	if err := s.Delete(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	if d.ranges != 1 || d.pred == nil {
+		t.Fatal("expected the series of the predicate to be deleted")
+	}
+
+	key := func(m, host string) []byte {
+		return models.MakeKey([]byte("name"), models.NewTags(map[string]string{
+			models.MeasurementTagKey: m,
+			models.FieldKeyTagKey:    "value",
+			"host":                   host,
+		}))
+	}
+	if !d.pred.Matches(key("cpu", "a")) || !d.pred.Matches(key("mem", "a")) || d.pred.Matches(key("cpu", "b")) {
+		t.Error("expected the predicate to match the series of host a of every measurement")
+	}
+
+	req.Stop = time.Unix(0, 5)
+	if err := s.Delete(context.Background(), req); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("got error %v, want an invalid error for a stop before the start", err)
+	}
+}
@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/snowflake"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+// maxFinishedDropSeriesOperations is the number of finished operations kept for lookup.
+const maxFinishedDropSeriesOperations = 100
+
+// A SeriesDropper is capable of deleting the data of the series matching a predicate.
+// Series left without data are removed from the index and series file.
+type SeriesDropper interface {
+	DeleteBucketRangePredicate(orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error
+}
+
+var _ influxdb.DropSeriesService = (*DropSeriesService)(nil)
+
+// DropSeriesService drops series asynchronously, one operation at a time.
+//
+// Operations are only tracked in memory, so they can't be looked up after a
+// restart. A drop that started is recorded in the WAL however, and completes
+// when the WAL is replayed.
+type DropSeriesService struct {
+	engine      SeriesDropper
+	IDGenerator influxdb.IDGenerator
+
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu  sync.Mutex // Protects ops and the operations in it.
+	ops map[influxdb.ID]*influxdb.DropSeriesOperation
+
+	dropMu sync.Mutex // Serializes drops, which disable compactions while they run.
+	wg     sync.WaitGroup
+}
+
+// NewDropSeriesService returns a new DropSeriesService for the provided SeriesDropper,
+// which typically will be an Engine.
+func NewDropSeriesService(engine SeriesDropper, logger *zap.Logger) *DropSeriesService {
+	return &DropSeriesService{
+		engine:      engine,
+		IDGenerator: snowflake.NewIDGenerator(),
+		logger:      logger.With(zap.String("service", "drop_series")),
+		now:         time.Now,
+		ops:         make(map[influxdb.ID]*influxdb.DropSeriesOperation),
+	}
+}
+
+// DropSeries validates the request and queues the drop of the series it selects.
+func (s *DropSeriesService) DropSeries(ctx context.Context, req influxdb.DropSeriesRequest) (*influxdb.DropSeriesOperation, error) {
+	span, _ := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	pred, err := seriesPredicate(req.Measurement, req.Tags)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "invalid drop series predicate",
+			Err:  err,
+		}
+	}
+
+	op := &influxdb.DropSeriesOperation{
+		ID:                s.IDGenerator.ID(),
+		DropSeriesRequest: req,
+		Status:            influxdb.DropSeriesStatusQueued,
+		CreatedAt:         s.now().UTC(),
+	}
+
+	s.mu.Lock()
+	s.ops[op.ID] = op
+	s.evictLocked()
+	res := copyDropSeriesOperation(op)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.drop(op, pred)
+	}()
+
+	return res, nil
+}
+
+// drop runs the operation once no other drop is running.
+func (s *DropSeriesService) drop(op *influxdb.DropSeriesOperation, pred tsm1.Predicate) {
+	s.dropMu.Lock()
+	defer s.dropMu.Unlock()
+
+	s.update(op, func(op *influxdb.DropSeriesOperation) {
+		now := s.now().UTC()
+		op.Status = influxdb.DropSeriesStatusRunning
+		op.StartedAt = &now
+	})
+
+	log, logEnd := logger.NewOperation(context.Background(), s.logger, "Drop series", "drop_series",
+		zap.String("org_id", op.OrgID.String()),
+		zap.String("bucket_id", op.BucketID.String()),
+		zap.String("measurement", op.Measurement))
+	defer logEnd()
+
+	err := s.engine.DeleteBucketRangePredicate(op.OrgID, op.BucketID, math.MinInt64, math.MaxInt64, pred)
+	if err != nil {
+		log.Error("Unable to drop series", zap.Error(err))
+	}
+
+	s.update(op, func(op *influxdb.DropSeriesOperation) {
+		now := s.now().UTC()
+		op.FinishedAt = &now
+		if err != nil {
+			op.Status = influxdb.DropSeriesStatusFailed
+			op.Error = err.Error()
+			return
+		}
+		op.Status = influxdb.DropSeriesStatusSuccess
+	})
+}
+
+// update applies fn to the operation under the lock.
+func (s *DropSeriesService) update(op *influxdb.DropSeriesOperation, fn func(*influxdb.DropSeriesOperation)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(op)
+}
+
+// evictLocked removes the oldest finished operations beyond the retained number.
+// It must be called under the lock.
+func (s *DropSeriesService) evictLocked() {
+	var finished []*influxdb.DropSeriesOperation
+	for _, op := range s.ops {
+		if op.Done() {
+			finished = append(finished, op)
+		}
+	}
+	if len(finished) <= maxFinishedDropSeriesOperations {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CreatedAt.Before(finished[j].CreatedAt)
+	})
+	for _, op := range finished[:len(finished)-maxFinishedDropSeriesOperations] {
+		delete(s.ops, op.ID)
+	}
+}
+
+// FindDropSeriesOperationByID returns a single operation by ID.
+func (s *DropSeriesService) FindDropSeriesOperationByID(ctx context.Context, id influxdb.ID) (*influxdb.DropSeriesOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op, ok := s.ops[id]
+	if !ok {
+		return nil, influxdb.ErrDropSeriesOperationNotFound
+	}
+	return copyDropSeriesOperation(op), nil
+}
+
+// FindDropSeriesOperations returns the operations that match filter, most recent first.
+func (s *DropSeriesService) FindDropSeriesOperations(ctx context.Context, filter influxdb.DropSeriesOperationFilter) ([]*influxdb.DropSeriesOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ops := []*influxdb.DropSeriesOperation{}
+	for _, op := range s.ops {
+		if filter.OrgID != nil && op.OrgID != *filter.OrgID {
+			continue
+		}
+		if filter.BucketID != nil && op.BucketID != *filter.BucketID {
+			continue
+		}
+		ops = append(ops, copyDropSeriesOperation(op))
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].CreatedAt.After(ops[j].CreatedAt)
+	})
+	return ops, nil
+}
+
+// Wait blocks until all queued and running drops have finished.
+func (s *DropSeriesService) Wait() {
+	s.wg.Wait()
+}
+
+// copyDropSeriesOperation returns a copy of op that is safe to use outside of the lock.
+func copyDropSeriesOperation(op *influxdb.DropSeriesOperation) *influxdb.DropSeriesOperation {
+	cp := *op
+	cp.Tags = append([]influxdb.SeriesTagPredicate(nil), op.Tags...)
+	return &cp
+}
+
+// seriesPredicate returns the predicate matching the keys of the series of the
+// measurement, if there is one, that match all of the tag predicates, or nil if
+// it matches every series.
+func seriesPredicate(measurement string, tags []influxdb.SeriesTagPredicate) (tsm1.Predicate, error) {
+	var root *datatypes.Node
+	if measurement != "" {
+		root = tagComparisonNode(models.MeasurementTagKey, influxdb.TagPredicateEqual, measurement)
+	}
+	for _, p := range tags {
+		node := tagComparisonNode(p.Key, p.Oper(), p.Value)
+		if root == nil {
+			root = node
+			continue
+		}
+		root = &datatypes.Node{
+			NodeType: datatypes.NodeTypeLogicalExpression,
+			Value:    &datatypes.Node_Logical_{Logical: datatypes.LogicalAnd},
+			Children: []*datatypes.Node{root, node},
+		}
+	}
+	if root == nil {
+		return nil, nil
+	}
+	return tsm1.NewProtobufPredicate(&datatypes.Predicate{Root: root})
+}
+
+// tagComparisonNode returns the predicate node comparing the value of the tag key.
+func tagComparisonNode(key, op, value string) *datatypes.Node {
+	literal := &datatypes.Node{
+		NodeType: datatypes.NodeTypeLiteral,
+		Value:    &datatypes.Node_StringValue{StringValue: value},
+	}
+
+	var comp datatypes.Node_Comparison
+	switch op {
+	case influxdb.TagPredicateNotEqual:
+		comp = datatypes.ComparisonNotEqual
+	case influxdb.TagPredicateRegex:
+		comp = datatypes.ComparisonRegex
+		literal.Value = &datatypes.Node_RegexValue{RegexValue: value}
+	case influxdb.TagPredicateNotRegex:
+		comp = datatypes.ComparisonNotRegex
+		literal.Value = &datatypes.Node_RegexValue{RegexValue: value}
+	default:
+		comp = datatypes.ComparisonEqual
+	}
+
+	return &datatypes.Node{
+		NodeType: datatypes.NodeTypeComparisonExpression,
+		Value:    &datatypes.Node_Comparison_{Comparison: comp},
+		Children: []*datatypes.Node{
+			{
+				NodeType: datatypes.NodeTypeTagRef,
+				Value:    &datatypes.Node_TagRefValue{TagRefValue: key},
+			},
+			literal,
+		},
+	}
+}
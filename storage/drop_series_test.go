@@ -0,0 +1,147 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestDropSeriesService_DropSeries(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	p := func(m, host string) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{
+				models.FieldKeyTagKey:    "value",
+				models.MeasurementTagKey: m,
+				"host":                   host,
+			}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		p("cpu", "old-1"),
+		p("cpu", "old-2"),
+		p("cpu", "new-1"),
+		p("mem", "old-1"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := storage.NewDropSeriesService(engine, zaptest.NewLogger(t))
+	op, err := s.DropSeries(context.Background(), influxdb.DropSeriesRequest{
+		OrgID:       engine.org,
+		BucketID:    engine.bucket,
+		Measurement: "cpu",
+		Tags: []influxdb.SeriesTagPredicate{
+			{Key: "host", Op: influxdb.TagPredicateRegex, Value: "^old-"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Done() {
+		t.Fatalf("expected operation to be pending, got status %q", op.Status)
+	}
+	s.Wait()
+
+	op, err = s.FindDropSeriesOperationByID(context.Background(), op.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Status != influxdb.DropSeriesStatusSuccess || op.StartedAt == nil || op.FinishedAt == nil {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+
+	// Only the old cpu hosts are dropped, not the mem series of the same host.
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	ops, err := s.FindDropSeriesOperations(context.Background(), influxdb.DropSeriesOperationFilter{BucketID: &engine.bucket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 1 || ops[0].ID != op.ID {
+		t.Fatalf("unexpected operations %+v", ops)
+	}
+}
+
+type seriesDropperFn func(orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error
+
+func (fn seriesDropperFn) DeleteBucketRangePredicate(orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error {
+	return fn(orgID, bucketID, min, max, pred)
+}
+
+func TestDropSeriesService_Predicate(t *testing.T) {
+	key := func(m string, tags ...string) []byte {
+		kvs := map[string]string{models.MeasurementTagKey: m, models.FieldKeyTagKey: "value"}
+		for i := 0; i < len(tags)-1; i += 2 {
+			kvs[tags[i]] = tags[i+1]
+		}
+		return models.MakeKey([]byte("name"), models.NewTags(kvs))
+	}
+
+	var pred tsm1.Predicate
+	s := storage.NewDropSeriesService(seriesDropperFn(func(orgID, bucketID influxdb.ID, min, max int64, p tsm1.Predicate) error {
+		if min != math.MinInt64 || max != math.MaxInt64 {
+			t.Errorf("expected series to be dropped regardless of time, got [%d, %d]", min, max)
+		}
+		pred = p
+		return errors.New("boom")
+	}), zaptest.NewLogger(t))
+
+	op, err := s.DropSeries(context.Background(), influxdb.DropSeriesRequest{
+		OrgID:       1,
+		BucketID:    2,
+		Measurement: "cpu",
+		Tags: []influxdb.SeriesTagPredicate{
+			{Key: "host", Value: "a"},
+			{Key: "region", Op: influxdb.TagPredicateNotEqual, Value: "west"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Wait()
+
+	for _, tt := range []struct {
+		key   []byte
+		match bool
+	}{
+		{key: key("cpu", "host", "a", "region", "east"), match: true},
+		{key: key("cpu", "host", "a", "region", "west")},
+		{key: key("cpu", "host", "b", "region", "east")},
+		{key: key("mem", "host", "a", "region", "east")},
+	} {
+		if got := pred.Matches(tt.key); got != tt.match {
+			t.Errorf("%s: got match %v, want %v", tt.key, got, tt.match)
+		}
+	}
+
+	op, err = s.FindDropSeriesOperationByID(context.Background(), op.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Status != influxdb.DropSeriesStatusFailed || op.Error != "boom" {
+		t.Errorf("expected failed operation, got %+v", op)
+	}
+
+	if _, err := s.DropSeries(context.Background(), influxdb.DropSeriesRequest{OrgID: 1, BucketID: 2}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected invalid request without a measurement, got %v", err)
+	}
+}
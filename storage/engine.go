@@ -43,6 +43,10 @@ type Engine struct {
 	engine            *tsm1.Engine
 	wal               *wal.WAL
 	retentionEnforcer *retentionEnforcer
+	retentionEvents   *RetentionEvents
+	reorderBuffer     *reorderBuffer
+	cardinality       *cardinalityRecorder
+	maintenance       *MaintenanceScheduler
 
 	defaultMetricLabels prometheus.Labels
 
@@ -94,6 +98,33 @@ func WithNodeID(id int) Option {
 func WithRetentionEnforcer(finder BucketFinder) Option {
 	return func(e *Engine) {
 		e.retentionEnforcer = newRetentionEnforcer(e, finder)
+		e.retentionEnforcer.Events = e.retentionEvents
+	}
+}
+
+// WithReorderBuffer initialises a buffer on the engine that holds the points of
+// buckets with a reorder window. It has no effect if the reorder check interval
+// of the engine config is not positive. WithReorderBuffer must be called after
+// other options to ensure that all metrics are labelled correctly.
+func WithReorderBuffer(finder BucketFinder) Option {
+	return func(e *Engine) {
+		if time.Duration(e.config.ReorderCheckInterval) <= 0 {
+			return
+		}
+		e.reorderBuffer = newReorderBuffer(e.writePoints, finder, e.config.ReorderMaxPoints)
+	}
+}
+
+// WithCardinalityRecorder initialises a recorder on the engine that periodically
+// writes the cardinality of each bucket to the monitoring bucket of its
+// organization. It has no effect if the cardinality interval of the engine config
+// is not positive.
+func WithCardinalityRecorder(finder BucketFinder) Option {
+	return func(e *Engine) {
+		if time.Duration(e.config.CardinalityInterval) <= 0 {
+			return
+		}
+		e.cardinality = newCardinalityRecorder(e, finder)
 	}
 }
 
@@ -118,12 +149,15 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 		config:              c,
 		path:                path,
 		defaultMetricLabels: prometheus.Labels{},
+		maintenance:         NewMaintenanceScheduler(nil),
+		retentionEvents:     NewRetentionEvents(),
 		logger:              zap.NewNop(),
 	}
 
 	// Initialize series file.
 	e.sfile = tsdb.NewSeriesFile(c.GetSeriesFilePath(path))
 	e.sfile.LargeWriteThreshold = c.TSDB.LargeSeriesWriteThreshold
+	e.sfile.CompactionsAllowed = e.maintenance.Allowed
 
 	// Initialise index.
 	e.index = tsi1.NewIndex(e.sfile, c.Index,
@@ -136,7 +170,8 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 
 	// Initialise Engine
 	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine,
-		tsm1.WithSnapshotter(e))
+		tsm1.WithSnapshotter(e),
+		tsm1.WithMaintenanceGate(e.maintenance.Allowed))
 
 	// Apply options.
 	for _, option := range options {
@@ -149,6 +184,7 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	e.index.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.wal.SetDefaultMetricLabels(e.defaultMetricLabels)
 	e.retentionEnforcer.SetDefaultMetricLabels(e.defaultMetricLabels)
+	e.reorderBuffer.SetDefaultMetricLabels(e.defaultMetricLabels)
 
 	return e
 }
@@ -171,6 +207,8 @@ func (e *Engine) WithLogger(log *zap.Logger) {
 	e.engine.WithLogger(e.logger)
 	e.wal.WithLogger(e.logger)
 	e.retentionEnforcer.WithLogger(e.logger)
+	e.reorderBuffer.WithLogger(e.logger)
+	e.cardinality.WithLogger(e.logger)
 }
 
 // PrometheusCollectors returns all the prometheus collectors associated with
@@ -182,6 +220,7 @@ func (e *Engine) PrometheusCollectors() []prometheus.Collector {
 	metrics = append(metrics, tsm1.PrometheusCollectors()...)
 	metrics = append(metrics, wal.PrometheusCollectors()...)
 	metrics = append(metrics, RetentionPrometheusCollectors()...)
+	metrics = append(metrics, ReorderPrometheusCollectors()...)
 	return metrics
 }
 
@@ -198,6 +237,20 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
+	windows, err := e.config.ParseMaintenanceWindows()
+	if err != nil {
+		return err
+	}
+	e.maintenance.setWindows(windows)
+	if len(windows) > 0 {
+		e.logger.Info("Confining heavy background work to maintenance windows", zap.Strings("windows", e.config.MaintenanceWindows))
+	}
+
+	// The services can only open the layout of this build.
+	if err := MigrateLayout(ctx, e.path, e.config, e.logger); err != nil {
+		return err
+	}
+
 	// Open the services in order and clean up if any fail.
 	var oh openHelper
 	oh.Open(ctx, e.sfile)
@@ -221,6 +274,14 @@ func (e *Engine) Open(ctx context.Context) (err error) {
 		e.runRetentionEnforcer()
 	}
 
+	if e.reorderBuffer != nil {
+		e.runReorderBuffer()
+	}
+
+	if e.cardinality != nil {
+		e.runCardinalityRecorder()
+	}
+
 	return nil
 }
 
@@ -300,9 +361,13 @@ func (e *Engine) runRetentionEnforcer() {
 	l.Info("Starting")
 
 	ticker := time.NewTicker(interval)
+	// Sweeps due outside of the maintenance windows are run as soon as a window opens.
+	pendingTicker := time.NewTicker(maintenanceCheckInterval)
 	e.wg.Add(1)
 	go func() {
 		defer e.wg.Done()
+		defer pendingTicker.Stop()
+		var pending bool
 		for {
 			// It's safe to read closing without a lock because it's never
 			// modified if this goroutine is active.
@@ -311,12 +376,86 @@ func (e *Engine) runRetentionEnforcer() {
 				l.Info("Stopping")
 				return
 			case <-ticker.C:
+				pending = true
+			case <-pendingTicker.C:
+			}
+
+			if pending && e.maintenance.Allowed() {
+				pending = false
 				e.retentionEnforcer.run()
 			}
 		}
 	}()
 }
 
+// RetentionEvents returns the deletions of the data of buckets that fell out of
+// their retention period, which other components can subscribe to.
+func (e *Engine) RetentionEvents() *RetentionEvents {
+	return e.retentionEvents
+}
+
+// MaintenanceScheduler returns the scheduler confining the heavy background work
+// of the engine to its maintenance windows.
+func (e *Engine) MaintenanceScheduler() *MaintenanceScheduler {
+	return e.maintenance
+}
+
+// runReorderBuffer periodically flushes the reorder windows that have passed
+// in a separate goroutine.
+func (e *Engine) runReorderBuffer() {
+	interval := time.Duration(e.config.ReorderCheckInterval)
+
+	l := e.logger.With(zap.String("component", "reorder_buffer"), logger.DurationLiteral("check_interval", interval))
+	l.Info("Starting")
+
+	ticker := time.NewTicker(interval)
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		defer ticker.Stop()
+		for {
+			// It's safe to read closing without a lock because it's never
+			// modified if this goroutine is active.
+			select {
+			case <-e.closing:
+				l.Info("Stopping")
+				return
+			case <-ticker.C:
+				e.reorderBuffer.run(context.Background())
+			}
+		}
+	}()
+}
+
+// runCardinalityRecorder records the cardinality of buckets when the engine
+// opens, and then periodically, in a separate goroutine.
+func (e *Engine) runCardinalityRecorder() {
+	interval := time.Duration(e.config.CardinalityInterval)
+
+	l := e.logger.With(zap.String("component", "cardinality_recorder"), logger.DurationLiteral("check_interval", interval))
+	l.Info("Starting")
+
+	ticker := time.NewTicker(interval)
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		defer ticker.Stop()
+
+		e.cardinality.run(context.Background(), interval)
+		for {
+			// It's safe to read closing without a lock because it's never
+			// modified if this goroutine is active.
+			select {
+			case <-e.closing:
+				l.Info("Stopping")
+				return
+			case <-ticker.C:
+				e.cardinality.run(context.Background(), interval)
+			}
+		}
+	}()
+}
+
 // Close closes the store and all underlying resources. It returns an error if
 // any of the underlying systems fail to close.
 func (e *Engine) Close() error {
@@ -334,6 +473,9 @@ func (e *Engine) Close() error {
 	// Wait for any other goroutines to finish.
 	e.wg.Wait()
 
+	// Write the points still held by reorder windows while the engine is open.
+	e.reorderBuffer.flushAll(context.Background())
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.closing = nil
@@ -373,8 +515,24 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator,
 // However, WritePoints will determine if any tag key-pairs are missing, or if
 // there are any field type conflicts.
 //
-// Appropriate errors are returned in those cases.
+// Appropriate errors are returned in those cases. Points of buckets with a
+// reorder window are buffered for the window, and errors writing them are only logged.
 func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
+	if e.reorderBuffer == nil {
+		return e.writePoints(ctx, points)
+	}
+
+	e.mu.RLock()
+	closed := e.closing == nil
+	e.mu.RUnlock()
+	if closed {
+		return ErrEngineClosed
+	}
+	return e.reorderBuffer.WritePoints(ctx, points)
+}
+
+// writePoints validates and writes the points to the WAL and the cache.
+func (e *Engine) writePoints(ctx context.Context, points []models.Point) error {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -391,44 +549,8 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 	}
 
 	for iter := collection.Iterator(); iter.Next(); {
-		tags := iter.Tags()
-
-		// Not enough tags present.
-		if tags.Len() < 2 {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required tags: parsed tags: %q", tags))
-			continue
-		}
-
-		// First tag key is not measurement tag.
-		if !bytes.Equal(tags[0].Key, models.MeasurementTagKeyBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required measurement tag as first tag, got: %q", tags[0].Key))
-			continue
-		}
-
-		fkey, fval := tags[len(tags)-1].Key, tags[len(tags)-1].Value
-
-		// Last tag key is not field tag.
-		if !bytes.Equal(fkey, models.FieldKeyTagKeyBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("missing required field key tag as last tag, got: %q", tags[0].Key))
-			continue
-		}
-
-		// The value representing the underlying field key is invalid if it's "time".
-		if bytes.Equal(fval, timeBytes) {
-			dropPoint(iter.Key(), fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes))
-			continue
-		}
-
-		// Filter out any tags with key equal to "time": they are invalid.
-		if tags.Get(timeBytes) != nil {
-			dropPoint(iter.Key(), fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name()))
-			continue
-		}
-
-		// Drop any point with invalid unicode characters in any of the tag keys or values.
-		// This will also cover validating the value used to represent the field key.
-		if !models.ValidTagTokens(tags) {
-			dropPoint(iter.Key(), fmt.Sprintf("key contains invalid unicode: %q", iter.Key()))
+		if err := ValidateSeries(iter.Key(), iter.Name(), iter.Tags()); err != nil {
+			dropPoint(iter.Key(), err.Error())
 			continue
 		}
 
@@ -458,6 +580,45 @@ func (e *Engine) WritePoints(ctx context.Context, points []models.Point) error {
 	return e.writePointsLocked(ctx, collection, values)
 }
 
+// ValidateSeries returns an error describing why the engine drops the points of the series
+// with the given key, name and tags, or nil if it accepts them.
+func ValidateSeries(key, name []byte, tags models.Tags) error {
+	// Not enough tags present.
+	if tags.Len() < 2 {
+		return fmt.Errorf("missing required tags: parsed tags: %q", tags)
+	}
+
+	// First tag key is not measurement tag.
+	if !bytes.Equal(tags[0].Key, models.MeasurementTagKeyBytes) {
+		return fmt.Errorf("missing required measurement tag as first tag, got: %q", tags[0].Key)
+	}
+
+	fkey, fval := tags[len(tags)-1].Key, tags[len(tags)-1].Value
+
+	// Last tag key is not field tag.
+	if !bytes.Equal(fkey, models.FieldKeyTagKeyBytes) {
+		return fmt.Errorf("missing required field key tag as last tag, got: %q", tags[0].Key)
+	}
+
+	// The value representing the underlying field key is invalid if it's "time".
+	if bytes.Equal(fval, timeBytes) {
+		return fmt.Errorf("invalid field key: input field %q is invalid", timeBytes)
+	}
+
+	// Filter out any tags with key equal to "time": they are invalid.
+	if tags.Get(timeBytes) != nil {
+		return fmt.Errorf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, name)
+	}
+
+	// Drop any point with invalid unicode characters in any of the tag keys or values.
+	// This will also cover validating the value used to represent the field key.
+	if !models.ValidTagTokens(tags) {
+		return fmt.Errorf("key contains invalid unicode: %q", key)
+	}
+
+	return nil
+}
+
 // writePointsLocked does the work of writing points and must be called under some sort of lock.
 func (e *Engine) writePointsLocked(ctx context.Context, collection *tsdb.SeriesCollection, values map[string][]value.Value) error {
 	span, _ := tracing.StartSpanFromContext(ctx)
@@ -583,6 +744,22 @@ func (e *Engine) deleteBucketRangeLocked(orgID, bucketID platform.ID,
 	return e.engine.DeletePrefixRange(name, min, max, pred)
 }
 
+// BucketRangeBytes returns the size of the blocks of data of a bucket whose last
+// timestamp is in [min, max], which compactions remove once the data of the
+// bucket up to max is deleted.
+func (e *Engine) BucketRangeBytes(orgID, bucketID platform.ID, min, max int64) (int64, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return 0, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := models.EscapeMeasurement(encoded[:])
+
+	return e.engine.PrefixRangeBlockBytes(name, min, max)
+}
+
 // SeriesCardinality returns the number of series in the engine.
 func (e *Engine) SeriesCardinality() int64 {
 	e.mu.RLock()
@@ -614,6 +791,113 @@ func (e *Engine) MeasurementCardinalityStats() tsi1.MeasurementCardinalityStats
 	return e.index.MeasurementCardinalityStats()
 }
 
+// BucketSeriesCardinality returns the number of series in the bucket, as recorded in the index.
+func (e *Engine) BucketSeriesCardinality(orgID, bucketID platform.ID) (int64, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return 0, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	return int64(e.index.MeasurementCardinalityStats()[string(encoded[:])]), nil
+}
+
+// NewSeries returns the distinct point keys whose series are not in the series
+// file yet.
+func (e *Engine) NewSeries(keys [][]byte) ([][]byte, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	var (
+		added [][]byte
+		buf   []byte
+		tags  models.Tags
+		seen  = make(map[string]struct{}, len(keys))
+	)
+	for _, key := range keys {
+		if _, ok := seen[string(key)]; ok {
+			continue
+		}
+		seen[string(key)] = struct{}{}
+
+		var name []byte
+		name, tags = models.ParseKeyBytesWithTags(key, tags[:0])
+		buf = tsdb.AppendSeriesKey(buf[:0], name, tags)
+		if e.sfile.SeriesIDTypedBySeriesKey(buf).IsZero() {
+			added = append(added, key)
+		}
+	}
+	return added, nil
+}
+
+// BucketCardinality returns the number of series in the bucket, and the number
+// of distinct values of each of its tag keys, as recorded in the index.
+func (e *Engine) BucketCardinality(orgID, bucketID platform.ID) (int64, map[string]int64, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return 0, nil, ErrEngineClosed
+	}
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	name := encoded[:]
+	series := int64(e.index.MeasurementCardinalityStats()[string(name)])
+
+	keys, err := e.index.TagKeyIterator(name)
+	if err != nil || keys == nil {
+		return series, map[string]int64{}, err
+	}
+	defer keys.Close()
+
+	tagValues := make(map[string]int64)
+	for {
+		key, err := keys.Next()
+		if err != nil {
+			return 0, nil, err
+		} else if key == nil {
+			break
+		}
+
+		n, err := e.countTagValues(name, key)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch k := string(key); k {
+		case models.MeasurementTagKey:
+			tagValues["_measurement"] = n
+		case models.FieldKeyTagKey:
+			tagValues["_field"] = n
+		default:
+			tagValues[k] = n
+		}
+	}
+	return series, tagValues, nil
+}
+
+func (e *Engine) countTagValues(name, key []byte) (int64, error) {
+	itr, err := e.index.TagValueIterator(name, key)
+	if err != nil || itr == nil {
+		return 0, err
+	}
+	defer itr.Close()
+
+	var n int64
+	for {
+		v, err := itr.Next()
+		if err != nil {
+			return 0, err
+		} else if v == nil {
+			return n, nil
+		}
+		n++
+	}
+}
+
 // MeasurementStats returns the current measurement stats for the engine.
 func (e *Engine) MeasurementStats() (tsm1.MeasurementStats, error) {
 	return e.engine.MeasurementStats()
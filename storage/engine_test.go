@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -351,6 +352,73 @@ func TestEngine_DeleteBucket_Predicate(t *testing.T) {
 
 }
 
+func TestEngine_BucketCardinality(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	var points []models.Point
+	for _, host := range []string{"a", "b", "c"} {
+		for _, field := range []string{"usage_user", "usage_system"} {
+			points = append(points, models.MustNewPoint(
+				tsdb.EncodeNameString(engine.org, engine.bucket),
+				models.NewTags(map[string]string{models.FieldKeyTagKey: field, models.MeasurementTagKey: "cpu", "host": host}),
+				map[string]interface{}{field: 1.0},
+				time.Unix(1, 2),
+			))
+		}
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), points); err != nil {
+		t.Fatal(err)
+	}
+
+	series, tagValues, err := engine.BucketCardinality(engine.org, engine.bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series != 6 {
+		t.Errorf("got %d series, exp %d", series, 6)
+	}
+	if exp := map[string]int64{"_measurement": 1, "_field": 2, "host": 3}; !reflect.DeepEqual(tagValues, exp) {
+		t.Errorf("got tag values %v, exp %v", tagValues, exp)
+	}
+
+	// Other buckets have no series.
+	series, tagValues, err = engine.BucketCardinality(engine.org, engine.bucket+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if series != 0 || len(tagValues) != 0 {
+		t.Errorf("expected an empty bucket, got %d series and tag values %v", series, tagValues)
+	}
+}
+
+func TestEngine_NewSeries(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	point := func(host string) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(map[string]string{models.FieldKeyTagKey: "usage_user", models.MeasurementTagKey: "cpu", "host": host}),
+			map[string]interface{}{"usage_user": 1.0},
+			time.Unix(1, 2),
+		)
+	}
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{point("a")}); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := [][]byte{point("a").Key(), point("b").Key(), point("b").Key(), point("c").Key()}
+	added, err := engine.NewSeries(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := [][]byte{point("b").Key(), point("c").Key()}; !reflect.DeepEqual(added, exp) {
+		t.Errorf("got new series %q, exp %q", added, exp)
+	}
+}
 func TestEngine_OpenClose(t *testing.T) {
 	engine := NewDefaultEngine()
 	engine.MustOpen()
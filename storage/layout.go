@@ -0,0 +1,333 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/influxdb/pkg/file"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsi1"
+	"go.uber.org/zap"
+)
+
+// LayoutVersion is the version of the on-disk layout of the storage directory,
+// such as the formats of the series file and the index, that this build reads
+// and writes. It must be incremented whenever one of the formats changes, and
+// a LayoutMigration to the new version added to layoutMigrations.
+const LayoutVersion = 1
+
+// LayoutFileName is the name of the file in the storage directory that records
+// the version of its layout and the progress of migrations between versions.
+const LayoutFileName = "LAYOUT"
+
+// The formats of a storage directory of layout version 1, which is the layout
+// of directories written before the layout file was introduced.
+const (
+	layoutV1SeriesSegmentVersion = 1
+	layoutV1IndexVersion         = 1
+)
+
+// LayoutMigration migrates a storage directory to Version from the version before it.
+//
+// The work of a migration is split into units, such as the partitions of the
+// series file, and progress is recorded in the layout file after each unit so
+// that an interrupted migration resumes with the first unit not yet migrated.
+// Units must list the same units when a migration resumes, and migrating a unit
+// must be safe to repeat, since a unit may be interrupted after it has been
+// migrated but before that is recorded.
+type LayoutMigration struct {
+	Version     int
+	Description string
+
+	// Units returns the units of work of the migration of the directory.
+	Units func(path string, c Config) ([]string, error)
+
+	// Migrate migrates a single unit.
+	Migrate func(ctx context.Context, path string, c Config, unit string) error
+}
+
+// layoutMigrations are the migrations between layout versions, keyed by the
+// version they migrate to.
+var layoutMigrations = map[int]LayoutMigration{}
+
+// layout is the content of the layout file.
+type layout struct {
+	Version int `json:"version"`
+
+	// Migration is set while the directory is migrated to the next version.
+	Migration *layoutMigrationProgress `json:"migration,omitempty"`
+}
+
+// layoutMigrationProgress is the progress of a migration to Version.
+type layoutMigrationProgress struct {
+	Version   int      `json:"version"`
+	Completed []string `json:"completed"`
+}
+
+// MigrateLayout migrates the storage directory at path to LayoutVersion, or
+// records the version of its layout if it has none. It refuses to open
+// directories whose layout it can not be certain of, such as directories of a
+// newer layout or with data of unknown formats, rather than risk corrupting them.
+func MigrateLayout(ctx context.Context, path string, c Config, logger *zap.Logger) error {
+	return migrateLayout(ctx, path, c, LayoutVersion, layoutMigrations, logger)
+}
+
+func migrateLayout(ctx context.Context, path string, c Config, version int, migrations map[int]LayoutMigration, logger *zap.Logger) error {
+	if err := os.MkdirAll(path, 0777); err != nil {
+		return err
+	}
+
+	l, err := readLayout(path)
+	if os.IsNotExist(err) {
+		l, err = detectLayout(path, c, version)
+		if err != nil {
+			return err
+		}
+		if err := writeLayout(path, l); err != nil {
+			return err
+		}
+		logger.Info("Recorded storage layout version", zap.Int("version", l.Version))
+	} else if err != nil {
+		return fmt.Errorf("refusing to open storage with an unreadable layout file: %v", err)
+	}
+
+	if l.Version > version {
+		return fmt.Errorf("refusing to open storage of layout version %d, which is newer than layout version %d of this build", l.Version, version)
+	}
+	if l.Migration != nil && l.Migration.Version != l.Version+1 {
+		return fmt.Errorf("refusing to open storage of layout version %d with an unfinished migration to layout version %d", l.Version, l.Migration.Version)
+	}
+
+	for l.Version < version {
+		m, ok := migrations[l.Version+1]
+		if !ok {
+			return fmt.Errorf("refusing to open storage of layout version %d, which has no migration to layout version %d", l.Version, l.Version+1)
+		}
+		if err := runLayoutMigration(ctx, path, c, l, m, logger); err != nil {
+			return fmt.Errorf("failed to migrate storage to layout version %d: %v", m.Version, err)
+		}
+	}
+	return nil
+}
+
+// runLayoutMigration runs or resumes the migration of the directory, and records
+// the new version once it is done.
+func runLayoutMigration(ctx context.Context, path string, c Config, l *layout, m LayoutMigration, logger *zap.Logger) error {
+	log := logger.With(zap.Int("from_version", l.Version), zap.Int("to_version", m.Version))
+	if l.Migration == nil {
+		l.Migration = &layoutMigrationProgress{Version: m.Version, Completed: []string{}}
+		if err := writeLayout(path, l); err != nil {
+			return err
+		}
+		log.Info("Migrating storage layout", zap.String("migration", m.Description))
+	} else {
+		log.Info("Resuming storage layout migration", zap.String("migration", m.Description), zap.Int("completed_units", len(l.Migration.Completed)))
+	}
+
+	units, err := m.Units(path, c)
+	if err != nil {
+		return err
+	}
+	completed := make(map[string]bool, len(l.Migration.Completed))
+	for _, u := range l.Migration.Completed {
+		completed[u] = true
+	}
+
+	for i, u := range units {
+		if completed[u] {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := m.Migrate(ctx, path, c, u); err != nil {
+			return fmt.Errorf("unit %q: %v", u, err)
+		}
+		l.Migration.Completed = append(l.Migration.Completed, u)
+		if err := writeLayout(path, l); err != nil {
+			return err
+		}
+		log.Info("Migrated storage layout unit", zap.String("unit", u), zap.Int("unit_index", i+1), zap.Int("units", len(units)))
+	}
+
+	l.Version, l.Migration = m.Version, nil
+	if err := writeLayout(path, l); err != nil {
+		return err
+	}
+	log.Info("Migrated storage layout")
+	return nil
+}
+
+// detectLayout returns the layout of a directory without a layout file: the
+// given version if it has no data, or version 1 if its data has the formats of
+// version 1. Data of other formats can not be told apart, and is refused.
+func detectLayout(path string, c Config, version int) (*layout, error) {
+	segments, err := seriesSegmentVersions(c.GetSeriesFilePath(path))
+	if err != nil {
+		return nil, err
+	}
+	for p, v := range segments {
+		if v != layoutV1SeriesSegmentVersion {
+			return nil, fmt.Errorf("refusing to open storage without a layout file and series segment %s of version %d", p, v)
+		}
+	}
+
+	manifests, err := indexManifestVersions(c.GetIndexPath(path))
+	if err != nil {
+		return nil, err
+	}
+	for p, v := range manifests {
+		if v != layoutV1IndexVersion {
+			return nil, fmt.Errorf("refusing to open storage without a layout file and index manifest %s of version %d", p, v)
+		}
+	}
+
+	hasData := len(segments) > 0 || len(manifests) > 0
+	for _, dir := range []string{c.GetEnginePath(path), c.GetWALPath(path)} {
+		nonEmpty, err := dirHasFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		hasData = hasData || nonEmpty
+	}
+
+	if !hasData {
+		return &layout{Version: version}, nil
+	}
+	return &layout{Version: 1}, nil
+}
+
+// seriesSegmentVersions returns the versions of the series segments of the
+// series file, keyed by their path.
+func seriesSegmentVersions(path string) (map[string]int, error) {
+	versions := make(map[string]int)
+	partitions, err := filepath.Glob(filepath.Join(path, "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range partitions {
+		fis, err := ioutil.ReadDir(p)
+		if err != nil {
+			// The series file has files besides its partitions.
+			continue
+		}
+		for _, fi := range fis {
+			if fi.IsDir() || !tsdb.IsValidSeriesSegmentFilename(fi.Name()) {
+				continue
+			}
+			segment := filepath.Join(p, fi.Name())
+			v, err := seriesSegmentVersion(segment)
+			if err != nil {
+				return nil, fmt.Errorf("refusing to open storage without a layout file and unreadable series segment %s: %v", segment, err)
+			}
+			versions[segment] = v
+		}
+	}
+	return versions, nil
+}
+
+func seriesSegmentVersion(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, tsdb.SeriesSegmentHeaderSize)
+	if _, err := io.ReadFull(f, buf); err != nil {
+		return 0, err
+	}
+	hdr, err := tsdb.ReadSeriesSegmentHeader(buf)
+	if err != nil {
+		return 0, err
+	}
+	return int(hdr.Version), nil
+}
+
+// indexManifestVersions returns the versions of the manifests of the partitions
+// of the index, keyed by their path.
+func indexManifestVersions(path string) (map[string]int, error) {
+	versions := make(map[string]int)
+	manifests, err := filepath.Glob(filepath.Join(path, "*", tsi1.ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range manifests {
+		m, _, err := tsi1.ReadManifestFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("refusing to open storage without a layout file and unreadable index manifest %s: %v", p, err)
+		}
+		versions[p] = m.Version
+	}
+	return versions, nil
+}
+
+// dirHasFiles returns true if the directory, or any directory in it, has a file.
+func dirHasFiles(path string) (bool, error) {
+	found := false
+	err := filepath.Walk(path, func(_ string, fi os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			found = true
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return found, err
+}
+
+func readLayout(path string) (*layout, error) {
+	buf, err := ioutil.ReadFile(filepath.Join(path, LayoutFileName))
+	if err != nil {
+		return nil, err
+	}
+	l := &layout{}
+	if err := json.Unmarshal(buf, l); err != nil {
+		return nil, err
+	}
+	if l.Version < 1 {
+		return nil, fmt.Errorf("invalid layout version %d", l.Version)
+	}
+	return l, nil
+}
+
+// writeLayout replaces the layout file, so that it holds either the old or the
+// new layout if the process is interrupted.
+func writeLayout(path string, l *layout) error {
+	buf, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+
+	tmp := filepath.Join(path, LayoutFileName+".tmp")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := file.RenameFile(tmp, filepath.Join(path, LayoutFileName)); err != nil {
+		return err
+	}
+	return file.SyncDir(path)
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+func TestMigrateLayout(t *testing.T) {
+	newDir := func(t *testing.T) (string, func()) {
+		dir, err := ioutil.TempDir("", "storage-layout-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dir, func() { os.RemoveAll(dir) }
+	}
+	writeFile := func(t *testing.T, path string, data []byte) {
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, data, 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	segment := func(version byte) []byte {
+		return append([]byte(tsdb.SeriesSegmentMagic), version)
+	}
+	c := NewConfig()
+
+	t.Run("new directory is of the current version", func(t *testing.T) {
+		dir, cleanup := newDir(t)
+		defer cleanup()
+
+		if err := migrateLayout(context.Background(), dir, c, 3, nil, zap.NewNop()); err != nil {
+			t.Fatal(err)
+		}
+		l, err := readLayout(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.Version != 3 || l.Migration != nil {
+			t.Fatalf("unexpected layout %+v", l)
+		}
+	})
+
+	t.Run("existing data without a layout file is version 1", func(t *testing.T) {
+		dir, cleanup := newDir(t)
+		defer cleanup()
+		writeFile(t, filepath.Join(c.GetSeriesFilePath(dir), "00", "0000"), segment(layoutV1SeriesSegmentVersion))
+
+		if err := MigrateLayout(context.Background(), dir, c, zap.NewNop()); err != nil {
+			t.Fatal(err)
+		}
+		if l, err := readLayout(dir); err != nil || l.Version != 1 {
+			t.Fatalf("got layout %+v, error %v; want version 1", l, err)
+		}
+	})
+
+	t.Run("refuses data of unknown formats without a layout file", func(t *testing.T) {
+		dir, cleanup := newDir(t)
+		defer cleanup()
+		writeFile(t, filepath.Join(c.GetSeriesFilePath(dir), "00", "0000"), segment(9))
+
+		if err := MigrateLayout(context.Background(), dir, c, zap.NewNop()); err == nil {
+			t.Fatal("expected an error opening series segments of an unknown version")
+		}
+		if _, err := os.Stat(filepath.Join(dir, LayoutFileName)); !os.IsNotExist(err) {
+			t.Fatal("expected no layout file to be written")
+		}
+	})
+
+	t.Run("refuses a newer layout", func(t *testing.T) {
+		dir, cleanup := newDir(t)
+		defer cleanup()
+		if err := writeLayout(dir, &layout{Version: LayoutVersion + 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := MigrateLayout(context.Background(), dir, c, zap.NewNop()); err == nil {
+			t.Fatal("expected an error opening a newer layout")
+		}
+	})
+
+	t.Run("migrates and resumes an interrupted migration", func(t *testing.T) {
+		dir, cleanup := newDir(t)
+		defer cleanup()
+		if err := writeLayout(dir, &layout{Version: 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		var migrated []string
+		fail := "b"
+		migrations := map[int]LayoutMigration{
+			2: {
+				Version:     2,
+				Description: "test",
+				Units: func(string, Config) ([]string, error) {
+					return []string{"a", "b", "c"}, nil
+				},
+				Migrate: func(ctx context.Context, path string, c Config, unit string) error {
+					if unit == fail {
+						return errors.New("interrupted")
+					}
+					migrated = append(migrated, unit)
+					return nil
+				},
+			},
+		}
+
+		if err := migrateLayout(context.Background(), dir, c, 2, migrations, zap.NewNop()); err == nil {
+			t.Fatal("expected the migration to fail")
+		}
+		l, err := readLayout(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &layout{Version: 1, Migration: &layoutMigrationProgress{Version: 2, Completed: []string{"a"}}}
+		if !cmp.Equal(want, l) {
+			t.Fatalf("unexpected layout -want/+got:\n%s", cmp.Diff(want, l))
+		}
+
+		fail = ""
+		if err := migrateLayout(context.Background(), dir, c, 2, migrations, zap.NewNop()); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"a", "b", "c"}; !cmp.Equal(want, migrated) {
+			t.Fatalf("unexpected migrated units -want/+got:\n%s", cmp.Diff(want, migrated))
+		}
+		if l, err := readLayout(dir); err != nil || l.Version != 2 || l.Migration != nil {
+			t.Fatalf("got layout %+v, error %v; want version 2", l, err)
+		}
+	})
+
+	t.Run("refuses a missing migration", func(t *testing.T) {
+		dir, cleanup := newDir(t)
+		defer cleanup()
+		if err := writeLayout(dir, &layout{Version: 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := migrateLayout(context.Background(), dir, c, 2, nil, zap.NewNop()); err == nil {
+			t.Fatal("expected an error without a migration to the current version")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+var _ influxdb.MaintenanceScheduleService = (*MaintenanceScheduler)(nil)
+
+// maintenanceCheckInterval is how often work that waits for a maintenance window checks whether it opened.
+const maintenanceCheckInterval = time.Minute
+
+// A MaintenanceScheduler confines the heavy background work of an engine, that
+// is full and optimize compactions, retention sweeps and series index rebuilds,
+// to the maintenance windows of the instance. The windows can be overridden,
+// which is not persisted.
+type MaintenanceScheduler struct {
+	mu       sync.RWMutex
+	schedule influxdb.MaintenanceSchedule
+
+	now func() time.Time
+}
+
+// NewMaintenanceScheduler returns a scheduler for the windows. Without windows,
+// heavy background work may run at any time.
+func NewMaintenanceScheduler(windows []influxdb.MaintenanceWindow) *MaintenanceScheduler {
+	return &MaintenanceScheduler{
+		schedule: influxdb.MaintenanceSchedule{
+			Windows: windows,
+		},
+		now: time.Now,
+	}
+}
+
+// setWindows replaces the maintenance windows.
+func (s *MaintenanceScheduler) setWindows(windows []influxdb.MaintenanceWindow) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedule.Windows = windows
+}
+
+// Allowed reports whether heavy background work may run now.
+func (s *MaintenanceScheduler) Allowed() bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.schedule.IsOpen(s.now())
+}
+
+// MaintenanceSchedule returns the current maintenance schedule.
+func (s *MaintenanceScheduler) MaintenanceSchedule(ctx context.Context) (*influxdb.MaintenanceSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.currentSchedule(), nil
+}
+
+// UpdateMaintenanceSchedule overrides the maintenance windows.
+func (s *MaintenanceScheduler) UpdateMaintenanceSchedule(ctx context.Context, upd influxdb.MaintenanceScheduleUpdate) (*influxdb.MaintenanceSchedule, error) {
+	if err := upd.Valid(); err != nil {
+		return nil, &influxdb.Error{
+			Op:  influxdb.OpUpdateMaintenanceSchedule,
+			Err: err,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.schedule.Override = upd.Override
+	s.schedule.OverrideUntil = upd.Until
+	return s.currentSchedule(), nil
+}
+
+// currentSchedule returns a copy of the schedule, clearing an override that has expired.
+// s.mu must be held for writing.
+func (s *MaintenanceScheduler) currentSchedule() *influxdb.MaintenanceSchedule {
+	now := s.now()
+	if s.schedule.OverrideUntil != nil && !now.Before(*s.schedule.OverrideUntil) {
+		s.schedule.Override = influxdb.MaintenanceOverrideNone
+		s.schedule.OverrideUntil = nil
+	}
+
+	schedule := s.schedule
+	schedule.Windows = append([]influxdb.MaintenanceWindow{}, s.schedule.Windows...)
+	schedule.Open = schedule.IsOpen(now)
+	return &schedule
+}
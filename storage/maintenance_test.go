@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestMaintenanceScheduler(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMaintenanceScheduler([]influxdb.MaintenanceWindow{{Start: "01:00", End: "05:00"}})
+	s.now = func() time.Time { return now }
+
+	if s.Allowed() {
+		t.Fatal("expected maintenance not to be allowed outside of the windows")
+	}
+
+	until := now.Add(time.Hour)
+	schedule, err := s.UpdateMaintenanceSchedule(context.Background(), influxdb.MaintenanceScheduleUpdate{
+		Override: influxdb.MaintenanceOverrideAllow,
+		Until:    &until,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schedule.Open || !s.Allowed() {
+		t.Fatal("expected the override to allow maintenance outside of the windows")
+	}
+
+	// The override is cleared once it has expired.
+	now = until
+	if s.Allowed() {
+		t.Error("expected the expired override not to allow maintenance")
+	}
+	schedule, err = s.MaintenanceSchedule(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Override != influxdb.MaintenanceOverrideNone || schedule.OverrideUntil != nil || schedule.Open {
+		t.Errorf("expected the expired override to be cleared, got %+v", schedule)
+	}
+
+	if _, err := s.UpdateMaintenanceSchedule(context.Background(), influxdb.MaintenanceScheduleUpdate{Override: "sometimes"}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid override to be rejected, got %v", err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 // monitored within the same process.
 var (
 	rms *retentionMetrics
+	ros *reorderMetrics
 	mmu sync.RWMutex
 )
 
@@ -27,6 +28,18 @@ func RetentionPrometheusCollectors() []prometheus.Collector {
 	return collectors
 }
 
+// ReorderPrometheusCollectors returns all prometheus metrics for reorder windows.
+func ReorderPrometheusCollectors() []prometheus.Collector {
+	mmu.RLock()
+	defer mmu.RUnlock()
+
+	var collectors []prometheus.Collector
+	if ros != nil {
+		collectors = append(collectors, ros.PrometheusCollectors()...)
+	}
+	return collectors
+}
+
 // namespace is the leading part of all published metrics for the Storage service.
 const namespace = "storage"
 
@@ -34,9 +47,10 @@ const retentionSubsystem = "retention" // sub-system associated with metrics for
 
 // retentionMetrics is a set of metrics concerned with tracking data about retention policies.
 type retentionMetrics struct {
-	labels        prometheus.Labels
-	Checks        *prometheus.CounterVec
-	CheckDuration *prometheus.HistogramVec
+	labels         prometheus.Labels
+	Checks         *prometheus.CounterVec
+	CheckDuration  *prometheus.HistogramVec
+	ReclaimedBytes *prometheus.CounterVec
 }
 
 func newRetentionMetrics(labels prometheus.Labels) *retentionMetrics {
@@ -52,6 +66,9 @@ func newRetentionMetrics(labels prometheus.Labels) *retentionMetrics {
 	checkDurationNames := append(append([]string(nil), names...), "status")
 	sort.Strings(checkDurationNames)
 
+	reclaimedNames := append(append([]string(nil), names...), "org_id", "bucket_id")
+	sort.Strings(reclaimedNames)
+
 	return &retentionMetrics{
 		labels: labels,
 		Checks: prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -69,6 +86,13 @@ func newRetentionMetrics(labels prometheus.Labels) *retentionMetrics {
 			// 25 buckets spaced exponentially between 10s and ~2h
 			Buckets: prometheus.ExponentialBuckets(10, 1.32, 25),
 		}, checkDurationNames),
+
+		ReclaimedBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: retentionSubsystem,
+			Name:      "reclaimed_bytes_total",
+			Help:      "Size of the blocks of data files holding only expired data by org/bucket id, which compactions remove.",
+		}, reclaimedNames),
 	}
 }
 
@@ -86,5 +110,90 @@ func (rm *retentionMetrics) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
 		rm.Checks,
 		rm.CheckDuration,
+		rm.ReclaimedBytes,
+	}
+}
+
+const reorderSubsystem = "reorder" // sub-system associated with metrics for reorder windows.
+
+// reorderMetrics is a set of metrics concerned with tracking points buffered by reorder windows.
+type reorderMetrics struct {
+	labels         prometheus.Labels
+	Points         *prometheus.CounterVec
+	OutOfOrder     *prometheus.CounterVec
+	Late           *prometheus.CounterVec
+	Flushes        *prometheus.CounterVec
+	BufferedPoints *prometheus.GaugeVec
+}
+
+func newReorderMetrics(labels prometheus.Labels) *reorderMetrics {
+	var names []string
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	bucketNames := append(append([]string(nil), names...), "org_id", "bucket_id")
+	sort.Strings(bucketNames)
+
+	flushNames := append(append([]string(nil), names...), "status", "org_id", "bucket_id")
+	sort.Strings(flushNames)
+
+	return &reorderMetrics{
+		labels: labels,
+		Points: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: reorderSubsystem,
+			Name:      "points_total",
+			Help:      "Number of points buffered by reorder windows by org/bucket id.",
+		}, bucketNames),
+
+		OutOfOrder: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: reorderSubsystem,
+			Name:      "out_of_order_points_total",
+			Help:      "Number of points that arrived out of order within the reorder window by org/bucket id.",
+		}, bucketNames),
+
+		Late: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: reorderSubsystem,
+			Name:      "late_points_total",
+			Help:      "Number of points that arrived out of order after their series was flushed by org/bucket id.",
+		}, bucketNames),
+
+		Flushes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: reorderSubsystem,
+			Name:      "flushes_total",
+			Help:      "Number of reorder window flushes by org/bucket id.",
+		}, flushNames),
+
+		BufferedPoints: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: reorderSubsystem,
+			Name:      "buffered_points",
+			Help:      "Number of points currently held by reorder windows.",
+		}, names),
+	}
+}
+
+// Labels returns a copy of labels for use with reorder metrics.
+func (m *reorderMetrics) Labels() prometheus.Labels {
+	l := make(map[string]string, len(m.labels))
+	for k, v := range m.labels {
+		l[k] = v
+	}
+	return l
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *reorderMetrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.Points,
+		m.OutOfOrder,
+		m.Late,
+		m.Flushes,
+		m.BufferedPoints,
 	}
 }
@@ -0,0 +1,321 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/storage/reads/datatypes"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+const (
+	// QueryViewsBucketID is the fixed ID of the system bucket of an organization
+	// that holds the results of its query views.
+	QueryViewsBucketID influxdb.ID = 12
+
+	// maxQueryViewPoints is the largest number of points a query view can hold.
+	maxQueryViewPoints = 1000000
+)
+
+// A QueryViewEngine stores the results of query views and drops them once the views expire.
+type QueryViewEngine interface {
+	WritePoints(ctx context.Context, points []models.Point) error
+	DeleteBucketRangePredicate(orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error
+}
+
+var _ influxdb.QueryViewService = (*QueryViewService)(nil)
+
+// QueryViewService wraps a influxdb.QueryViewService that stores the query views,
+// and stores their results in the query views bucket of their organization.
+type QueryViewService struct {
+	influxdb.QueryViewService
+
+	engine QueryViewEngine
+	qs     query.QueryService
+
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+// NewQueryViewService returns a new QueryViewService that runs the queries of views with qs
+// and stores their results in engine.
+func NewQueryViewService(s influxdb.QueryViewService, engine QueryViewEngine, qs query.QueryService, logger *zap.Logger) *QueryViewService {
+	return &QueryViewService{
+		QueryViewService: s,
+		engine:           engine,
+		qs:               qs,
+		now:              time.Now,
+		logger:           logger.With(zap.String("service", "query_views")),
+	}
+}
+
+// CreateQueryView creates the view and stores the result of its query. The query runs
+// with the authorization of the caller. If the query fails, the view is removed again.
+func (s *QueryViewService) CreateQueryView(ctx context.Context, v *influxdb.QueryView) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	auth, err := icontext.GetAuthorization(ctx, v.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	// Creating the view first reserves its name, so its result can't mix
+	// with the one of another view.
+	v.BucketID = QueryViewsBucketID
+	if err := s.QueryViewService.CreateQueryView(ctx, v); err != nil {
+		return err
+	}
+
+	if err := s.materialize(ctx, auth, v); err != nil {
+		if err := s.deleteQueryView(ctx, v); err != nil {
+			s.logger.Error("Unable to remove query view", zap.String("query_view_id", v.ID.String()), zap.Error(err))
+		}
+		return &influxdb.Error{
+			Op:  influxdb.OpCreateQueryView,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// materialize runs the query of the view and writes its result to the query views bucket.
+func (s *QueryViewService) materialize(ctx context.Context, auth *influxdb.Authorization, v *influxdb.QueryView) error {
+	it, err := s.qs.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: v.OrganizationID,
+		Compiler:       lang.FluxCompiler{Query: v.Query},
+	})
+	if err != nil {
+		return err
+	}
+	defer it.Release()
+
+	pr := &queryViewReader{name: v.Name, now: v.CreatedAt}
+	for it.More() {
+		if err := it.Next().Tables().Do(pr.readTable); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	if len(pr.points) == 0 {
+		return nil
+	}
+	exploded, err := tsdb.ExplodePoints(v.OrganizationID, QueryViewsBucketID, pr.points)
+	if err != nil {
+		return err
+	}
+	return s.engine.WritePoints(ctx, exploded)
+}
+
+// DeleteQueryView removes the view and drops its result.
+func (s *QueryViewService) DeleteQueryView(ctx context.Context, id influxdb.ID) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	v, err := s.QueryViewService.FindQueryViewByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.deleteQueryView(ctx, v)
+}
+
+// deleteQueryView drops the result of the view before removing it, so the
+// name of the view is not released while its result is still stored.
+func (s *QueryViewService) deleteQueryView(ctx context.Context, v *influxdb.QueryView) error {
+	pred, err := tsm1.NewProtobufPredicate(&datatypes.Predicate{
+		Root: tagComparisonNode(models.MeasurementTagKey, influxdb.TagPredicateEqual, v.Name),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.engine.DeleteBucketRangePredicate(v.OrganizationID, QueryViewsBucketID, math.MinInt64, math.MaxInt64, pred); err != nil {
+		return err
+	}
+	return s.QueryViewService.DeleteQueryView(ctx, v.ID)
+}
+
+// Cleanup removes the expired views every interval until ctx is done.
+func (s *QueryViewService) Cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.deleteExpired(ctx)
+		}
+	}
+}
+
+// deleteExpired removes every view that expired.
+func (s *QueryViewService) deleteExpired(ctx context.Context) {
+	vs, err := s.QueryViewService.FindQueryViews(ctx, influxdb.QueryViewFilter{})
+	if err != nil {
+		s.logger.Error("Unable to find query views", zap.Error(err))
+		return
+	}
+
+	now := s.now()
+	for _, v := range vs {
+		if !v.Expired(now) {
+			continue
+		}
+		if err := s.deleteQueryView(ctx, v); err != nil {
+			s.logger.Error("Unable to remove expired query view", zap.String("query_view_id", v.ID.String()), zap.Error(err))
+			continue
+		}
+		s.logger.Debug("Removed expired query view", zap.String("query_view_id", v.ID.String()))
+	}
+}
+
+// queryViewReader converts the tables of the result of a query into the points of a view.
+//
+// The string columns of the group key, other than the measurement, field, start
+// and stop, become the tags of the points. Tables with a _field and _value column
+// become the field by that name, otherwise every other column becomes a field.
+// Points are timestamped by the _time column, or else by the _stop column or the
+// creation time of the view.
+type queryViewReader struct {
+	name   string
+	now    time.Time
+	points models.Points
+}
+
+func (pr *queryViewReader) readTable(tbl flux.Table) error {
+	return tbl.Do(pr.readPoints)
+}
+
+func (pr *queryViewReader) readPoints(cr flux.ColReader) error {
+	key := cr.Key()
+	tags := make(map[string]string)
+	for j, c := range key.Cols() {
+		if c.Type != flux.TString || isQueryViewReservedColumn(c.Label) {
+			continue
+		}
+		if v := key.ValueString(j); v != "" {
+			tags[c.Label] = v
+		}
+	}
+
+	timeIdx, stopIdx, fieldIdx, valueIdx := -1, -1, -1, -1
+	for j, c := range cr.Cols() {
+		switch c.Label {
+		case execute.DefaultTimeColLabel:
+			timeIdx = j
+		case execute.DefaultStopColLabel:
+			stopIdx = j
+		case "_field":
+			fieldIdx = j
+		case execute.DefaultValueColLabel:
+			valueIdx = j
+		}
+	}
+	if timeIdx >= 0 && cr.Cols()[timeIdx].Type != flux.TTime {
+		timeIdx = -1
+	}
+	if stopIdx >= 0 && cr.Cols()[stopIdx].Type != flux.TTime {
+		stopIdx = -1
+	}
+	if fieldIdx >= 0 && cr.Cols()[fieldIdx].Type != flux.TString {
+		fieldIdx = -1
+	}
+
+	for i := 0; i < cr.Len(); i++ {
+		ts := pr.now
+		if timeIdx >= 0 && cr.Times(timeIdx).IsValid(i) {
+			ts = time.Unix(0, cr.Times(timeIdx).Value(i))
+		} else if stopIdx >= 0 && cr.Times(stopIdx).IsValid(i) {
+			ts = time.Unix(0, cr.Times(stopIdx).Value(i))
+		}
+
+		fields := make(models.Fields)
+		if fieldIdx >= 0 && valueIdx >= 0 {
+			if field := cr.Strings(fieldIdx).ValueString(i); field != "" {
+				if v, ok := queryViewFieldValue(cr, valueIdx, i); ok {
+					fields[field] = v
+				}
+			}
+		} else {
+			for j, c := range cr.Cols() {
+				if key.HasCol(c.Label) || j == timeIdx || isQueryViewReservedColumn(c.Label) {
+					continue
+				}
+				if v, ok := queryViewFieldValue(cr, j, i); ok {
+					fields[c.Label] = v
+				}
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(pr.points)+len(fields) > maxQueryViewPoints {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("query view result exceeds the limit of %d points", maxQueryViewPoints),
+			}
+		}
+
+		p, err := models.NewPoint(pr.name, models.NewTags(tags), fields, ts)
+		if err != nil {
+			return err
+		}
+		pr.points = append(pr.points, p)
+	}
+	return nil
+}
+
+// isQueryViewReservedColumn returns true for the columns that neither become tags nor fields.
+func isQueryViewReservedColumn(label string) bool {
+	switch label {
+	case "_measurement", "_field", execute.DefaultStartColLabel, execute.DefaultStopColLabel, execute.DefaultTimeColLabel:
+		return true
+	}
+	return false
+}
+
+// queryViewFieldValue returns the value of column j of row i as a field value.
+// Null values and columns of types that can't be stored are skipped.
+func queryViewFieldValue(cr flux.ColReader, j, i int) (interface{}, bool) {
+	switch cr.Cols()[j].Type {
+	case flux.TFloat:
+		if vs := cr.Floats(j); vs.IsValid(i) {
+			return vs.Value(i), true
+		}
+	case flux.TInt:
+		if vs := cr.Ints(j); vs.IsValid(i) {
+			return vs.Value(i), true
+		}
+	case flux.TUInt:
+		if vs := cr.UInts(j); vs.IsValid(i) {
+			return vs.Value(i), true
+		}
+	case flux.TBool:
+		if vs := cr.Bools(j); vs.IsValid(i) {
+			return vs.Value(i), true
+		}
+	case flux.TString:
+		if vs := cr.Strings(j); vs.IsValid(i) {
+			return vs.ValueString(i), true
+		}
+	}
+	return nil, false
+}
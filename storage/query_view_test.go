@@ -0,0 +1,231 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/influxdata/influxdb/tsdb/tsm1"
+	"go.uber.org/zap/zaptest"
+)
+
+type testQueryViewEngine struct {
+	written []models.Point
+	deleted []influxdb.ID
+}
+
+func (e *testQueryViewEngine) WritePoints(ctx context.Context, points []models.Point) error {
+	e.written = append(e.written, points...)
+	return nil
+}
+
+func (e *testQueryViewEngine) DeleteBucketRangePredicate(orgID, bucketID influxdb.ID, min, max int64, pred tsm1.Predicate) error {
+	e.deleted = append(e.deleted, bucketID)
+	return nil
+}
+
+// testQueryViewStore is a QueryViewService that keeps the views in memory.
+type testQueryViewStore struct {
+	views map[influxdb.ID]*influxdb.QueryView
+}
+
+func newTestQueryViewStore() *testQueryViewStore {
+	return &testQueryViewStore{views: make(map[influxdb.ID]*influxdb.QueryView)}
+}
+
+func (s *testQueryViewStore) FindQueryViewByID(ctx context.Context, id influxdb.ID) (*influxdb.QueryView, error) {
+	v, ok := s.views[id]
+	if !ok {
+		return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: influxdb.ErrQueryViewNotFound}
+	}
+	return v, nil
+}
+
+func (s *testQueryViewStore) FindQueryViews(ctx context.Context, filter influxdb.QueryViewFilter) ([]*influxdb.QueryView, error) {
+	vs := []*influxdb.QueryView{}
+	for _, v := range s.views {
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+func (s *testQueryViewStore) CreateQueryView(ctx context.Context, v *influxdb.QueryView) error {
+	v.ID = influxdb.ID(len(s.views) + 1)
+	s.views[v.ID] = v
+	return nil
+}
+
+func (s *testQueryViewStore) DeleteQueryView(ctx context.Context, id influxdb.ID) error {
+	delete(s.views, id)
+	return nil
+}
+
+func TestQueryViewService_CreateQueryView(t *testing.T) {
+	t0 := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	var script string
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			script = req.Compiler.(lang.FluxCompiler).Query
+			return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{
+					{
+						KeyCols: []string{"_start", "_stop", "_measurement", "_field", "host"},
+						ColMeta: []flux.ColMeta{
+							{Label: "_start", Type: flux.TTime},
+							{Label: "_stop", Type: flux.TTime},
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_value", Type: flux.TFloat},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "_field", Type: flux.TString},
+							{Label: "host", Type: flux.TString},
+						},
+						Data: [][]interface{}{
+							{execute.Time(0), execute.Time(t0.UnixNano()), execute.Time(t0.Add(-time.Minute).UnixNano()), 0.5, "cpu", "usage", "a"},
+							{execute.Time(0), execute.Time(t0.UnixNano()), execute.Time(t0.UnixNano()), nil, "cpu", "usage", "a"},
+						},
+					},
+					{
+						KeyCols: []string{"host"},
+						ColMeta: []flux.ColMeta{
+							{Label: "host", Type: flux.TString},
+							{Label: "n", Type: flux.TInt},
+							{Label: "up", Type: flux.TBool},
+						},
+						Data: [][]interface{}{
+							{"b", int64(3), true},
+						},
+					},
+				},
+			}}), nil
+		},
+	}
+
+	engine := &testQueryViewEngine{}
+	s := NewQueryViewService(newTestQueryViewStore(), engine, qs, zaptest.NewLogger(t))
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{OrgID: 1})
+
+	v := &influxdb.QueryView{
+		OrganizationID: 1,
+		Name:           "cpu_hosts",
+		Query:          `from(bucket: "telegraf") |> range(start: -1h)`,
+		CreatedAt:      t0,
+	}
+	if err := s.CreateQueryView(ctx, v); err != nil {
+		t.Fatal(err)
+	}
+	if script != v.Query {
+		t.Errorf("got script %q, want %q", script, v.Query)
+	}
+	if v.BucketID != QueryViewsBucketID {
+		t.Errorf("got bucket %s, want %s", v.BucketID, QueryViewsBucketID)
+	}
+
+	type point struct {
+		measurement, host string
+		fields            models.Fields
+		time              int64
+	}
+	var got []point
+	for _, p := range engine.written {
+		if name := tsdb.EncodeNameString(1, QueryViewsBucketID); string(p.Name()) != name {
+			t.Fatalf("expected point to be written to the query views bucket, got name %q", p.Name())
+		}
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		host := string(p.Tags().Get([]byte("host")))
+		// Points are written with a field each, so the fields of a point are merged back.
+		if n := len(got); n > 0 && got[n-1].host == host && got[n-1].time == p.UnixNano() {
+			for k, v := range fields {
+				got[n-1].fields[k] = v
+			}
+			continue
+		}
+		got = append(got, point{
+			measurement: string(p.Tags().Get(models.MeasurementTagKeyBytes)),
+			host:        host,
+			fields:      fields,
+			time:        p.UnixNano(),
+		})
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].host < got[j].host })
+
+	want := []point{
+		{measurement: "cpu_hosts", host: "a", fields: models.Fields{"usage": 0.5}, time: t0.Add(-time.Minute).UnixNano()},
+		{measurement: "cpu_hosts", host: "b", fields: models.Fields{"n": int64(3), "up": true}, time: t0.UnixNano()},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got points %+v, want %+v", got, want)
+	}
+}
+
+func TestQueryViewService_CreateQueryView_QueryFails(t *testing.T) {
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			return nil, errors.New("bucket not found")
+		},
+	}
+
+	store := newTestQueryViewStore()
+	engine := &testQueryViewEngine{}
+	s := NewQueryViewService(store, engine, qs, zaptest.NewLogger(t))
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{OrgID: 1})
+
+	v := &influxdb.QueryView{OrganizationID: 1, Name: "v", Query: `from(bucket: "missing")`}
+	if err := s.CreateQueryView(ctx, v); err == nil {
+		t.Fatal("expected the view not to be created")
+	}
+	if vs, _ := store.FindQueryViews(ctx, influxdb.QueryViewFilter{}); len(vs) != 0 {
+		t.Errorf("expected the view to be removed again, got %+v", vs)
+	}
+	if !reflect.DeepEqual(engine.deleted, []influxdb.ID{QueryViewsBucketID}) {
+		t.Errorf("expected the result of the view to be dropped, got %v", engine.deleted)
+	}
+}
+
+func TestQueryViewService_deleteExpired(t *testing.T) {
+	now := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	store := newTestQueryViewStore()
+	ctx := context.Background()
+	for _, v := range []*influxdb.QueryView{
+		{OrganizationID: 1, Name: "expired", ExpiresAt: now.Add(-time.Second)},
+		{OrganizationID: 1, Name: "alive", ExpiresAt: now.Add(time.Second)},
+	} {
+		if err := store.CreateQueryView(ctx, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := &testQueryViewEngine{}
+	s := NewQueryViewService(store, engine, &querymock.QueryService{}, zaptest.NewLogger(t))
+	s.now = func() time.Time { return now }
+	s.deleteExpired(ctx)
+
+	vs, err := store.FindQueryViews(ctx, influxdb.QueryViewFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) != 1 || vs[0].Name != "alive" {
+		t.Errorf("expected only the expired view to be removed, got %+v", vs)
+	}
+	if len(engine.deleted) != 1 {
+		t.Errorf("expected the result of one view to be dropped, got %d", len(engine.deleted))
+	}
+}
@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/pkg/estimator/hll"
+)
+
+// quotaSeriesRefreshInterval is how often the series cardinality of a bucket
+// with a series quota is read from the index.
+const quotaSeriesRefreshInterval = 10 * time.Second
+
+// A QuotaEngine reports the series cardinality of buckets, and which series of a write are new.
+type QuotaEngine interface {
+	BucketSeriesCardinality(orgID, bucketID influxdb.ID) (int64, error)
+	NewSeries(keys [][]byte) ([][]byte, error)
+}
+
+var _ influxdb.BucketQuotaService = (*QuotaService)(nil)
+
+// QuotaService enforces the quotas of buckets on the writes of this process.
+//
+// The points per second are limited with a token bucket that holds a second's
+// worth of points, so that a write may be as large as a second of points, and
+// larger writes are allowed when the bucket is full at the cost of the writes
+// that follow. Bytes are counted per UTC day. Only writes that add series count
+// against the series quota, so the series already in a full bucket can still
+// be written. The series of a bucket are read from the index at most every
+// quotaSeriesRefreshInterval, and the series added since are estimated with a
+// HyperLogLog sketch of their keys, which counts the same series added by
+// concurrent writes once.
+type QuotaService struct {
+	Engine QuotaEngine
+
+	mu    sync.Mutex
+	usage map[influxdb.ID]*bucketUsage
+
+	now func() time.Time
+}
+
+// NewQuotaService returns a new QuotaService that reads series cardinality from engine.
+func NewQuotaService(engine QuotaEngine) *QuotaService {
+	return &QuotaService{
+		Engine: engine,
+		usage:  make(map[influxdb.ID]*bucketUsage),
+		now:    time.Now,
+	}
+}
+
+// bucketUsage is the usage of a bucket against its quota.
+type bucketUsage struct {
+	// tokens is the number of points that may be written at filled. It is
+	// negative when a write was larger than the points that were available.
+	tokens float64
+	filled time.Time
+
+	// points is the number of points written in the second starting at second,
+	// and lastPoints the number written in the second before.
+	second             time.Time
+	points, lastPoints int64
+
+	// bytes is the number of bytes written in the UTC day starting at day.
+	day   time.Time
+	bytes int64
+
+	// series is the number of series in the index at seriesAt, and added
+	// estimates the series added by the writes accepted since.
+	series   int64
+	seriesAt time.Time
+	added    *hll.Plus
+}
+
+// seriesEstimate returns the estimated number of series in the bucket of u.
+func (u *bucketUsage) seriesEstimate() int64 {
+	if u.added == nil {
+		return u.series
+	}
+	return u.series + int64(u.added.Count())
+}
+
+// advance refills the points of u up to rate, and starts a new second or
+// day when they have passed.
+func (u *bucketUsage) advance(now time.Time, rate int64) {
+	if rate > 0 {
+		u.tokens += now.Sub(u.filled).Seconds() * float64(rate)
+		if u.tokens > float64(rate) {
+			u.tokens = float64(rate)
+		}
+	}
+	u.filled = now
+
+	if second := now.Truncate(time.Second); !second.Equal(u.second) {
+		if second.Sub(u.second) == time.Second {
+			u.lastPoints = u.points
+		} else {
+			u.lastPoints = 0
+		}
+		u.second, u.points = second, 0
+	}
+
+	if day := startOfDay(now); !day.Equal(u.day) {
+		u.day, u.bytes = day, 0
+	}
+}
+
+// startOfDay returns the start of the UTC day of t.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// bucketUsage returns the usage of b, advanced to now. It must be called with s.mu held.
+func (s *QuotaService) bucketUsage(b *influxdb.Bucket, now time.Time) *bucketUsage {
+	u, ok := s.usage[b.ID]
+	if !ok {
+		u = &bucketUsage{
+			tokens: float64(b.Quota.MaxPointsPerSecond),
+			filled: now,
+		}
+		s.usage[b.ID] = u
+	}
+	u.advance(now, b.Quota.MaxPointsPerSecond)
+	return u
+}
+
+// DeleteBucket drops the usage of a deleted bucket.
+func (s *QuotaService) DeleteBucket(orgID, bucketID influxdb.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.usage, bucketID)
+	return nil
+}
+
+// bucketSeries returns the estimated series cardinality of b, reading it from
+// the engine if the one of u is older than quotaSeriesRefreshInterval.
+func (s *QuotaService) bucketSeries(b *influxdb.Bucket, u *bucketUsage, now time.Time) (int64, error) {
+	if !u.seriesAt.IsZero() && now.Sub(u.seriesAt) < quotaSeriesRefreshInterval {
+		return u.seriesEstimate(), nil
+	}
+
+	n, err := s.Engine.BucketSeriesCardinality(b.OrgID, b.ID)
+	if err != nil {
+		return 0, err
+	}
+	// The index counts the series added before now.
+	u.series, u.seriesAt, u.added = n, now, nil
+	return n, nil
+}
+
+// ReserveWrite accounts for a write of the points with the series keys and of
+// bytes to b, unless the write would exceed the quota of b. The writes of
+// buckets without a quota are accounted for too, so that their usage can be
+// reported.
+func (s *QuotaService) ReserveWrite(ctx context.Context, b *influxdb.Bucket, keys [][]byte, bytes int64) (time.Duration, error) {
+	q := b.Quota
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.bucketUsage(b, now)
+
+	var added [][]byte
+	if q.MaxSeries > 0 {
+		series, err := s.bucketSeries(b, u, now)
+		if err != nil {
+			return 0, err
+		}
+		if added, err = s.Engine.NewSeries(keys); err != nil {
+			return 0, err
+		}
+		if len(added) > 0 && series+int64(len(added)) > q.MaxSeries {
+			return quotaSeriesRefreshInterval - now.Sub(u.seriesAt), quotaExceeded(b, "%d series", q.MaxSeries)
+		}
+	}
+
+	points := len(keys)
+
+	if q.MaxBytesPerDay > 0 && u.bytes+bytes > q.MaxBytesPerDay {
+		return u.day.Add(24 * time.Hour).Sub(now), quotaExceeded(b, "%d bytes per day", q.MaxBytesPerDay)
+	}
+
+	if rate := q.MaxPointsPerSecond; rate > 0 {
+		need := float64(points)
+		if need > float64(rate) {
+			// Larger writes than the bucket holds wait for it to be full.
+			need = float64(rate)
+		}
+		if u.tokens < need {
+			wait := time.Duration(math.Ceil((need - u.tokens) / float64(rate) * float64(time.Second)))
+			return wait, quotaExceeded(b, "%d points per second", rate)
+		}
+		u.tokens -= float64(points)
+	}
+
+	if len(added) > 0 {
+		if u.added == nil {
+			u.added = hll.NewDefaultPlus()
+		}
+		for _, key := range added {
+			u.added.Add(key)
+		}
+	}
+	u.points += int64(points)
+	u.bytes += bytes
+	return 0, nil
+}
+
+func quotaExceeded(b *influxdb.Bucket, format string, limit int64) error {
+	return &influxdb.Error{
+		Code: influxdb.ETooManyRequests,
+		Msg:  fmt.Sprintf("bucket %q is over its quota of "+format, b.Name, limit),
+	}
+}
+
+// FindBucketQuotaUsage returns the usage of b against its quota.
+func (s *QuotaService) FindBucketQuotaUsage(ctx context.Context, b *influxdb.Bucket) (*influxdb.BucketQuotaUsage, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.bucketUsage(b, now)
+	// The usage is reported even for buckets without a series quota, so it is
+	// read from the index every time rather than from the cache.
+	u.seriesAt = time.Time{}
+	series, err := s.bucketSeries(b, u, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &influxdb.BucketQuotaUsage{
+		BucketID:        b.ID,
+		Quota:           b.Quota,
+		PointsPerSecond: u.lastPoints,
+		Series:          series,
+		BytesToday:      u.bytes,
+	}, nil
+}
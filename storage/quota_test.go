@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+type testQuotaEngine struct {
+	series int64
+	reads  int
+
+	// known are the series keys in the index.
+	known map[string]bool
+}
+
+func (e *testQuotaEngine) BucketSeriesCardinality(orgID, bucketID influxdb.ID) (int64, error) {
+	e.reads++
+	return e.series, nil
+}
+
+func (e *testQuotaEngine) NewSeries(keys [][]byte) ([][]byte, error) {
+	var added [][]byte
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		if !e.known[string(key)] && !seen[string(key)] {
+			seen[string(key)] = true
+			added = append(added, key)
+		}
+	}
+	return added, nil
+}
+
+// quotaKeys returns the keys of n points of the series key.
+func quotaKeys(key string, n int) [][]byte {
+	keys := make([][]byte, n)
+	for i := range keys {
+		keys[i] = []byte(key)
+	}
+	return keys
+}
+
+func TestQuotaService_ReserveWrite(t *testing.T) {
+	setup := func(q influxdb.BucketQuota) (*QuotaService, *testQuotaEngine, *influxdb.Bucket, *time.Time) {
+		engine := &testQuotaEngine{known: map[string]bool{"cpu,host=a": true}}
+		s := NewQuotaService(engine)
+		now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+		s.now = func() time.Time { return now }
+		return s, engine, &influxdb.Bucket{ID: 2, OrgID: 1, Name: "telegraf", Quota: q}, &now
+	}
+
+	reserve := func(t *testing.T, s *QuotaService, b *influxdb.Bucket, points int, bytes int64) (time.Duration, error) {
+		t.Helper()
+		wait, err := s.ReserveWrite(context.Background(), b, quotaKeys("cpu,host=a", points), bytes)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+			t.Fatalf("unexpected error code %q: %v", influxdb.ErrorCode(err), err)
+		}
+		return wait, err
+	}
+
+	t.Run("points per second", func(t *testing.T) {
+		s, _, b, now := setup(influxdb.BucketQuota{MaxPointsPerSecond: 100})
+
+		if _, err := reserve(t, s, b, 60, 0); err != nil {
+			t.Fatalf("first write rejected: %v", err)
+		}
+		wait, err := reserve(t, s, b, 60, 0)
+		if err == nil {
+			t.Fatal("expected write over the rate to be rejected")
+		}
+		if got, want := wait, 200*time.Millisecond; got != want {
+			t.Errorf("got retry after %v, want %v", got, want)
+		}
+
+		*now = now.Add(200 * time.Millisecond)
+		if _, err := reserve(t, s, b, 60, 0); err != nil {
+			t.Fatalf("write after waiting rejected: %v", err)
+		}
+	})
+
+	t.Run("writes larger than a second of points", func(t *testing.T) {
+		s, _, b, now := setup(influxdb.BucketQuota{MaxPointsPerSecond: 100})
+
+		if _, err := reserve(t, s, b, 250, 0); err != nil {
+			t.Fatalf("large write to a full bucket rejected: %v", err)
+		}
+		wait, err := reserve(t, s, b, 1, 0)
+		if err == nil {
+			t.Fatal("expected write after large write to be rejected")
+		}
+		if got, want := wait, 1510*time.Millisecond; got != want {
+			t.Errorf("got retry after %v, want %v", got, want)
+		}
+
+		*now = now.Add(wait)
+		if _, err := reserve(t, s, b, 1, 0); err != nil {
+			t.Fatalf("write after waiting rejected: %v", err)
+		}
+	})
+
+	t.Run("bytes per day", func(t *testing.T) {
+		s, _, b, now := setup(influxdb.BucketQuota{MaxBytesPerDay: 1000})
+
+		if _, err := reserve(t, s, b, 1, 1000); err != nil {
+			t.Fatalf("write up to the quota rejected: %v", err)
+		}
+		wait, err := reserve(t, s, b, 1, 1)
+		if err == nil {
+			t.Fatal("expected write over the quota to be rejected")
+		}
+		if got, want := wait, 12*time.Hour; got != want {
+			t.Errorf("got retry after %v, want %v", got, want)
+		}
+
+		*now = now.Add(wait)
+		if _, err := reserve(t, s, b, 1, 1000); err != nil {
+			t.Fatalf("write on the next day rejected: %v", err)
+		}
+	})
+
+	reserveSeries := func(t *testing.T, s *QuotaService, b *influxdb.Bucket, keys ...string) (time.Duration, error) {
+		t.Helper()
+		var ks [][]byte
+		for _, k := range keys {
+			ks = append(ks, []byte(k))
+		}
+		wait, err := s.ReserveWrite(context.Background(), b, ks, 0)
+		if err != nil && influxdb.ErrorCode(err) != influxdb.ETooManyRequests {
+			t.Fatalf("unexpected error code %q: %v", influxdb.ErrorCode(err), err)
+		}
+		return wait, err
+	}
+
+	t.Run("series", func(t *testing.T) {
+		s, engine, b, now := setup(influxdb.BucketQuota{MaxSeries: 10})
+
+		engine.series = 9
+		if _, err := reserveSeries(t, s, b, "cpu,host=b", "cpu,host=b"); err != nil {
+			t.Fatalf("write of a new series under the quota rejected: %v", err)
+		}
+		engine.known["cpu,host=b"] = true
+
+		// The cardinality is cached until it is refreshed, but the series
+		// added since count against the quota.
+		*now = now.Add(4 * time.Second)
+		if _, err := reserveSeries(t, s, b, "cpu,host=c"); err == nil {
+			t.Fatal("expected write of a series over the quota to be rejected")
+		}
+		if _, err := reserveSeries(t, s, b, "cpu,host=b"); err != nil {
+			t.Fatalf("write of an added series rejected: %v", err)
+		}
+		if engine.reads != 1 {
+			t.Errorf("got %d cardinality reads, want 1", engine.reads)
+		}
+
+		engine.series = 10
+		*now = now.Add(6 * time.Second)
+		wait, err := reserveSeries(t, s, b, "cpu,host=c")
+		if err == nil {
+			t.Fatal("expected write of a new series to a full bucket to be rejected")
+		}
+		if got, want := wait, quotaSeriesRefreshInterval; got != want {
+			t.Errorf("got retry after %v, want %v", got, want)
+		}
+
+		// The series already in a full bucket can still be written.
+		if _, err := reserveSeries(t, s, b, "cpu,host=a", "cpu,host=b"); err != nil {
+			t.Fatalf("write of existing series to a full bucket rejected: %v", err)
+		}
+	})
+
+	t.Run("series added by concurrent writes", func(t *testing.T) {
+		s, engine, b, _ := setup(influxdb.BucketQuota{MaxSeries: 10})
+
+		// Until the first write of a series reaches the index, the writes
+		// after it add the series again, but it only counts once.
+		engine.series = 8
+		for i := 0; i < 3; i++ {
+			if _, err := reserveSeries(t, s, b, "cpu,host=b"); err != nil {
+				t.Fatalf("write %d of a new series under the quota rejected: %v", i, err)
+			}
+		}
+		if _, err := reserveSeries(t, s, b, "cpu,host=c"); err != nil {
+			t.Fatalf("write of a new series up to the quota rejected: %v", err)
+		}
+		if _, err := reserveSeries(t, s, b, "cpu,host=d"); err == nil {
+			t.Fatal("expected write of a series over the quota to be rejected")
+		}
+	})
+
+	t.Run("deleted buckets drop their usage", func(t *testing.T) {
+		s, _, b, _ := setup(influxdb.BucketQuota{MaxBytesPerDay: 1000})
+
+		if _, err := reserve(t, s, b, 1, 1000); err != nil {
+			t.Fatalf("write up to the quota rejected: %v", err)
+		}
+		if err := s.DeleteBucket(b.OrgID, b.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := s.usage[b.ID]; ok {
+			t.Fatal("expected the usage of the deleted bucket to be dropped")
+		}
+	})
+
+	t.Run("rejected writes are not accounted for", func(t *testing.T) {
+		s, _, b, _ := setup(influxdb.BucketQuota{MaxPointsPerSecond: 100, MaxBytesPerDay: 1000})
+
+		if _, err := reserve(t, s, b, 100, 500); err != nil {
+			t.Fatalf("first write rejected: %v", err)
+		}
+		if _, err := reserve(t, s, b, 1, 500); err == nil {
+			t.Fatal("expected write over the rate to be rejected")
+		}
+		u, err := s.FindBucketQuotaUsage(context.Background(), b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.BytesToday != 500 {
+			t.Errorf("got %d bytes today, want 500", u.BytesToday)
+		}
+	})
+}
+
+func TestQuotaService_FindBucketQuotaUsage(t *testing.T) {
+	engine := &testQuotaEngine{series: 42}
+	s := NewQuotaService(engine)
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	b := &influxdb.Bucket{ID: 2, OrgID: 1, Quota: influxdb.BucketQuota{MaxSeries: 100}}
+
+	for _, points := range []int{10, 20} {
+		if _, err := s.ReserveWrite(context.Background(), b, quotaKeys("cpu,host=a", points), 100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(1500 * time.Millisecond)
+
+	got, err := s.FindBucketQuotaUsage(context.Background(), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := influxdb.BucketQuotaUsage{
+		BucketID:        2,
+		Quota:           influxdb.BucketQuota{MaxSeries: 100},
+		PointsPerSecond: 30,
+		Series:          42,
+		BytesToday:      200,
+	}
+	if *got != want {
+		t.Errorf("got usage %+v, want %+v", *got, want)
+	}
+
+	// Only the last full second counts towards the points per second.
+	now = now.Add(time.Second)
+	if got, err = s.FindBucketQuotaUsage(context.Background(), b); err != nil {
+		t.Fatal(err)
+	} else if got.PointsPerSecond != 0 {
+		t.Errorf("got %d points per second, want 0", got.PointsPerSecond)
+	}
+}
@@ -0,0 +1,340 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// reorderWindowRefreshInterval is how often the reorder windows of buckets are reloaded.
+const reorderWindowRefreshInterval = 10 * time.Second
+
+// The reorderBuffer holds points written to buckets with a reorder window for the
+// duration of the window. Points that arrive out of order within the window are
+// sorted before they are written, so that the cache snapshots of the engine don't
+// produce overlapping blocks that must be merged by compactions and queries.
+//
+// Buffered points are neither durable nor queryable until they are flushed.
+type reorderBuffer struct {
+	// write writes points to the engine, bypassing the buffer.
+	write func(context.Context, []models.Point) error
+
+	// BucketService provides the reorder windows of buckets.
+	BucketService BucketFinder
+
+	// MaxPoints is the number of buffered points at which all windows are flushed early.
+	MaxPoints int
+
+	mu        sync.Mutex
+	windows   map[string]time.Duration // keyed by encoded org and bucket ID
+	refreshed time.Time
+	batches   map[string]*reorderBatch
+	points    int
+
+	now     func() time.Time
+	logger  *zap.Logger
+	tracker *reorderTracker
+}
+
+// reorderBatch is the window of points buffered for a bucket.
+type reorderBatch struct {
+	name            string
+	orgID, bucketID influxdb.ID
+
+	// opened is when the first point of the window arrived, or when the
+	// previous window was flushed if there are no points.
+	opened time.Time
+	points []models.Point
+
+	// latest holds the latest timestamp of each series key in the window, and
+	// flushed the latest timestamps of the previously flushed window.
+	latest  map[string]int64
+	flushed map[string]int64
+}
+
+func newReorderBuffer(write func(context.Context, []models.Point) error, bucketService BucketFinder, maxPoints int) *reorderBuffer {
+	return &reorderBuffer{
+		write:         write,
+		BucketService: bucketService,
+		MaxPoints:     maxPoints,
+		windows:       map[string]time.Duration{},
+		batches:       map[string]*reorderBatch{},
+		now:           time.Now,
+		logger:        zap.NewNop(),
+		tracker:       newReorderTracker(newReorderMetrics(nil), nil),
+	}
+}
+
+// SetDefaultMetricLabels sets the default labels for the reorder metrics.
+func (b *reorderBuffer) SetDefaultMetricLabels(defaultLabels prometheus.Labels) {
+	if b == nil {
+		return // Not initialized
+	}
+
+	mmu.Lock()
+	if ros == nil {
+		ros = newReorderMetrics(defaultLabels)
+	}
+	mmu.Unlock()
+
+	b.tracker = newReorderTracker(ros, defaultLabels)
+}
+
+// WithLogger sets the logger l on the buffer. It must be called before any writes.
+func (b *reorderBuffer) WithLogger(l *zap.Logger) {
+	if b == nil {
+		return // Not initialised
+	}
+	b.logger = l.With(zap.String("component", "reorder_buffer"))
+}
+
+// WritePoints buffers the points of buckets with a reorder window and writes all
+// other points immediately.
+func (b *reorderBuffer) WritePoints(ctx context.Context, points []models.Point) error {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	var (
+		now  = b.now()
+		pass []models.Point
+		full []*reorderBatch
+	)
+
+	b.mu.Lock()
+	for _, p := range points {
+		name := p.Name()
+		if b.windows[string(name)] <= 0 {
+			pass = append(pass, p)
+			continue
+		}
+
+		batch := b.batches[string(name)]
+		if batch == nil {
+			orgID, bucketID := tsdb.DecodeNameSlice(name)
+			batch = &reorderBatch{name: string(name), orgID: orgID, bucketID: bucketID}
+			b.batches[batch.name] = batch
+		}
+		b.add(batch, p, now)
+	}
+	if b.MaxPoints > 0 && b.points >= b.MaxPoints {
+		full = b.drain(now, true)
+	}
+	b.tracker.SetBufferedPoints(b.points)
+	b.mu.Unlock()
+
+	b.flush(ctx, full)
+
+	if len(pass) == 0 {
+		return nil
+	}
+	return b.write(ctx, pass)
+}
+
+// add adds p to the batch. It must be called under the lock.
+func (b *reorderBuffer) add(batch *reorderBatch, p models.Point, now time.Time) {
+	if len(batch.points) == 0 {
+		batch.opened = now
+		batch.latest = map[string]int64{}
+	}
+
+	key, ts := string(p.Key()), p.UnixNano()
+	outOfOrder, late := false, false
+	if latest, ok := batch.latest[key]; ok && ts < latest {
+		outOfOrder = true
+	} else {
+		batch.latest[key] = ts
+	}
+	if flushed, ok := batch.flushed[key]; ok && ts < flushed {
+		late = true
+	}
+	b.tracker.IncPoints(batch.orgID, batch.bucketID, outOfOrder, late)
+
+	batch.points = append(batch.points, p)
+	b.points++
+}
+
+// run flushes the windows that have passed, reloading the reorder windows of buckets as needed.
+func (b *reorderBuffer) run(ctx context.Context) {
+	if b == nil {
+		return // Not initialized
+	}
+
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	now := b.now()
+	var windows map[string]time.Duration
+	if now.Sub(b.refreshed) >= reorderWindowRefreshInterval {
+		var err error
+		if windows, err = b.getReorderWindows(ctx); err != nil {
+			b.logger.Error("Unable to determine bucket reorder windows", zap.Error(err))
+		}
+	}
+
+	b.mu.Lock()
+	if windows != nil {
+		b.windows = windows
+		b.refreshed = now
+	}
+	due := b.drain(now, false)
+	b.tracker.SetBufferedPoints(b.points)
+	b.mu.Unlock()
+
+	b.flush(ctx, due)
+}
+
+// flushAll writes all buffered points.
+func (b *reorderBuffer) flushAll(ctx context.Context) {
+	if b == nil {
+		return // Not initialized
+	}
+
+	b.mu.Lock()
+	all := b.drain(b.now(), true)
+	b.tracker.SetBufferedPoints(b.points)
+	b.mu.Unlock()
+
+	b.flush(ctx, all)
+}
+
+// drain removes the points of the batches whose window has passed, or of all
+// batches if all is set, and returns them as new batches. Batches that stayed
+// idle for a window are removed. It must be called under the lock.
+func (b *reorderBuffer) drain(now time.Time, all bool) []*reorderBatch {
+	var drained []*reorderBatch
+	for name, batch := range b.batches {
+		// Points of buckets whose window was removed are flushed right away.
+		passed := now.Sub(batch.opened) >= b.windows[name]
+		if len(batch.points) == 0 {
+			if passed {
+				delete(b.batches, name)
+			}
+			continue
+		}
+		if !passed && !all {
+			continue
+		}
+
+		drained = append(drained, &reorderBatch{
+			name:     name,
+			orgID:    batch.orgID,
+			bucketID: batch.bucketID,
+			points:   batch.points,
+		})
+		b.points -= len(batch.points)
+		batch.opened = now
+		batch.points = nil
+		batch.flushed, batch.latest = batch.latest, nil
+	}
+	return drained
+}
+
+// flush sorts the points of each batch by series key and time, and writes them.
+func (b *reorderBuffer) flush(ctx context.Context, batches []*reorderBatch) {
+	for _, batch := range batches {
+		points := batch.points
+		sort.SliceStable(points, func(i, j int) bool {
+			if c := bytes.Compare(points[i].Key(), points[j].Key()); c != 0 {
+				return c < 0
+			}
+			return points[i].UnixNano() < points[j].UnixNano()
+		})
+
+		// The writer of the points was already told the write succeeded,
+		// so a failure can only be reported here.
+		err := b.write(ctx, points)
+		if err != nil {
+			b.logger.Error("Unable to write reorder window",
+				zap.String("org_id", batch.orgID.String()),
+				zap.String("bucket_id", batch.bucketID.String()),
+				zap.Int("points", len(points)),
+				zap.Error(err))
+		}
+		b.tracker.IncFlushes(batch.orgID, batch.bucketID, err == nil)
+	}
+}
+
+// getReorderWindows returns the reorder windows of all buckets that have one.
+func (b *reorderBuffer) getReorderWindows(ctx context.Context) (map[string]time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
+	defer cancel()
+
+	buckets, _, err := b.BucketService.FindBuckets(ctx, influxdb.BucketFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	windows := make(map[string]time.Duration)
+	for _, bkt := range buckets {
+		if bkt.ReorderWindow > 0 {
+			windows[tsdb.EncodeNameString(bkt.OrgID, bkt.ID)] = bkt.ReorderWindow
+		}
+	}
+	return windows, nil
+}
+
+//
+// metrics tracker
+//
+
+type reorderTracker struct {
+	metrics *reorderMetrics
+	labels  prometheus.Labels
+}
+
+func newReorderTracker(metrics *reorderMetrics, defaultLabels prometheus.Labels) *reorderTracker {
+	return &reorderTracker{metrics: metrics, labels: defaultLabels}
+}
+
+// Labels returns a copy of labels for use with reorder metrics.
+func (t *reorderTracker) Labels() prometheus.Labels {
+	l := make(map[string]string, len(t.labels))
+	for k, v := range t.labels {
+		l[k] = v
+	}
+	return l
+}
+
+// IncPoints signals that a point was buffered for some bucket.
+func (t *reorderTracker) IncPoints(orgID, bucketID influxdb.ID, outOfOrder, late bool) {
+	labels := t.Labels()
+	labels["org_id"] = orgID.String()
+	labels["bucket_id"] = bucketID.String()
+
+	t.metrics.Points.With(labels).Inc()
+	if outOfOrder {
+		t.metrics.OutOfOrder.With(labels).Inc()
+	}
+	if late {
+		t.metrics.Late.With(labels).Inc()
+	}
+}
+
+// IncFlushes signals that the window of some bucket was flushed.
+func (t *reorderTracker) IncFlushes(orgID, bucketID influxdb.ID, success bool) {
+	labels := t.Labels()
+	labels["org_id"] = orgID.String()
+	labels["bucket_id"] = bucketID.String()
+
+	if success {
+		labels["status"] = "ok"
+	} else {
+		labels["status"] = "error"
+	}
+
+	t.metrics.Flushes.With(labels).Inc()
+}
+
+// SetBufferedPoints sets the number of points currently buffered.
+func (t *reorderTracker) SetBufferedPoints(n int) {
+	t.metrics.BufferedPoints.With(t.Labels()).Set(float64(n))
+}
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/prom/promtest"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestReorderBuffer(t *testing.T) {
+	const (
+		org      = influxdb.ID(1)
+		buffered = influxdb.ID(2)
+		direct   = influxdb.ID(3)
+	)
+
+	newPoint := func(bucketID influxdb.ID, host string, sec int64) models.Point {
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(org, bucketID),
+			models.NewTags(map[string]string{"host": host}),
+			models.Fields{"v": float64(sec)},
+			time.Unix(sec, 0),
+		)
+	}
+
+	type write struct {
+		host string
+		sec  int64
+	}
+	writes := func(points []models.Point) []write {
+		var ws []write
+		for _, p := range points {
+			ws = append(ws, write{host: string(p.Tags().Get([]byte("host"))), sec: p.Time().Unix()})
+		}
+		return ws
+	}
+
+	setup := func(t *testing.T, maxPoints int) (*reorderBuffer, *[][]models.Point, *time.Time) {
+		var written [][]models.Point
+		finder := NewTestBucketFinder()
+		finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+			return []*influxdb.Bucket{
+				{ID: buffered, OrgID: org, ReorderWindow: time.Minute},
+				{ID: direct, OrgID: org},
+			}, 2, nil
+		}
+
+		now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+		b := newReorderBuffer(func(ctx context.Context, points []models.Point) error {
+			written = append(written, points)
+			return nil
+		}, finder, maxPoints)
+		b.now = func() time.Time { return now }
+
+		// The first run loads the reorder windows.
+		b.run(context.Background())
+		return b, &written, &now
+	}
+
+	t.Run("holds points for the window and writes them in order", func(t *testing.T) {
+		b, written, now := setup(t, 0)
+
+		if err := b.WritePoints(context.Background(), []models.Point{
+			newPoint(buffered, "b", 20),
+			newPoint(buffered, "a", 30),
+			newPoint(buffered, "b", 10),
+			newPoint(direct, "a", 5),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if len(*written) != 1 || !reflect.DeepEqual(writes((*written)[0]), []write{{"a", 5}}) {
+			t.Fatalf("expected only the point without a window to be written, got %v", *written)
+		}
+
+		*now = now.Add(30 * time.Second)
+		b.run(context.Background())
+		if len(*written) != 1 {
+			t.Fatalf("expected points to be held until the window passes, got %d writes", len(*written))
+		}
+
+		*now = now.Add(30 * time.Second)
+		b.run(context.Background())
+		if len(*written) != 2 {
+			t.Fatalf("expected points to be written once the window passed, got %d writes", len(*written))
+		}
+		if got, want := writes((*written)[1]), []write{{"a", 30}, {"b", 10}, {"b", 20}}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if b.points != 0 {
+			t.Fatalf("expected no buffered points, got %d", b.points)
+		}
+	})
+
+	t.Run("flushes early when full", func(t *testing.T) {
+		b, written, _ := setup(t, 2)
+
+		if err := b.WritePoints(context.Background(), []models.Point{newPoint(buffered, "a", 2)}); err != nil {
+			t.Fatal(err)
+		}
+		if len(*written) != 0 {
+			t.Fatalf("expected point to be held, got %d writes", len(*written))
+		}
+
+		if err := b.WritePoints(context.Background(), []models.Point{newPoint(buffered, "a", 1)}); err != nil {
+			t.Fatal(err)
+		}
+		if len(*written) != 1 {
+			t.Fatalf("expected full buffer to be written, got %d writes", len(*written))
+		}
+		if got, want := writes((*written)[0]), []write{{"a", 1}, {"a", 2}}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("flushes all on close", func(t *testing.T) {
+		b, written, _ := setup(t, 0)
+
+		if err := b.WritePoints(context.Background(), []models.Point{newPoint(buffered, "a", 1)}); err != nil {
+			t.Fatal(err)
+		}
+		b.flushAll(context.Background())
+		if len(*written) != 1 || len(b.batches[tsdb.EncodeNameString(org, buffered)].points) != 0 {
+			t.Fatalf("expected buffered points to be written, got %v", *written)
+		}
+	})
+
+	t.Run("keeps the previous windows when buckets are unavailable", func(t *testing.T) {
+		b, written, now := setup(t, 0)
+		b.BucketService.(*TestBucketFinder).FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+			return nil, 0, errors.New("unavailable")
+		}
+
+		*now = now.Add(reorderWindowRefreshInterval)
+		b.run(context.Background())
+
+		if err := b.WritePoints(context.Background(), []models.Point{newPoint(buffered, "a", 1)}); err != nil {
+			t.Fatal(err)
+		}
+		if len(*written) != 0 {
+			t.Fatalf("expected point to be held, got %d writes", len(*written))
+		}
+	})
+}
+
+func TestMetrics_Reorder(t *testing.T) {
+	metrics := newReorderMetrics(prometheus.Labels{"engine_id": "", "node_id": ""})
+	tracker := newReorderTracker(metrics, prometheus.Labels{"engine_id": "0", "node_id": "0"})
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(metrics.PrometheusCollectors()...)
+
+	base := namespace + "_" + reorderSubsystem + "_"
+
+	tracker.IncPoints(1, 2, false, false)
+	tracker.IncPoints(1, 2, true, false)
+	tracker.IncPoints(1, 2, true, true)
+	tracker.IncFlushes(1, 2, true)
+	tracker.SetBufferedPoints(3)
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := prometheus.Labels{"engine_id": "0", "node_id": "0"}
+	bucketLabels := prometheus.Labels{"engine_id": "0", "node_id": "0", "org_id": influxdb.ID(1).String(), "bucket_id": influxdb.ID(2).String()}
+	for name, exp := range map[string]float64{
+		"points_total":              3,
+		"out_of_order_points_total": 2,
+		"late_points_total":         1,
+	} {
+		metric := promtest.MustFindMetric(t, mfs, base+name, bucketLabels)
+		if got := metric.GetCounter().GetValue(); got != exp {
+			t.Errorf("[%s] got %v, expected %v", name, got, exp)
+		}
+	}
+
+	bucketLabels["status"] = "ok"
+	if got := promtest.MustFindMetric(t, mfs, base+"flushes_total", bucketLabels).GetCounter().GetValue(); got != 1 {
+		t.Errorf("[flushes_total] got %v, expected 1", got)
+	}
+	if got := promtest.MustFindMetric(t, mfs, base+"buffered_points", labels).GetGauge().GetValue(); got != 3 {
+		t.Errorf("[buffered_points] got %v, expected 3", got)
+	}
+}
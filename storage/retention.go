@@ -22,6 +22,12 @@ type Deleter interface {
 	DeleteBucketRange(orgID, bucketID influxdb.ID, min, max int64) error
 }
 
+// A RangeSizer reports the size of the data of a bucket that a deletion up to
+// max reclaims, counting data whose last timestamp is at least min.
+type RangeSizer interface {
+	BucketRangeBytes(orgID, bucketID influxdb.ID, min, max int64) (int64, error)
+}
+
 // A BucketFinder is responsible for providing access to buckets via a filter.
 type BucketFinder interface {
 	FindBuckets(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error)
@@ -33,16 +39,23 @@ var ErrServiceClosed = errors.New("service is currently closed")
 // The retentionEnforcer periodically removes data that is outside of the retention
 // period of the bucket associated with the data.
 type retentionEnforcer struct {
-	// Engine provides access to data stored on the engine
+	// Engine provides access to data stored on the engine. The bytes reclaimed
+	// by deletions are only tracked if it is a RangeSizer too.
 	Engine Deleter
 
 	// BucketService provides an API for retrieving buckets associated with
 	// organisations.
 	BucketService BucketFinder
 
+	// Events receives the deletions of expired data.
+	Events *RetentionEvents
+
 	logger *zap.Logger
 
 	tracker *retentionTracker
+
+	// expired is the end of the time range that was last deleted from each bucket.
+	expired map[influxdb.ID]int64
 }
 
 // newRetentionEnforcer returns a new enforcer that ensures expired data is
@@ -54,6 +67,7 @@ func newRetentionEnforcer(engine Deleter, bucketService BucketFinder) *retention
 		BucketService: bucketService,
 		logger:        zap.NewNop(),
 		tracker:       newRetentionTracker(newRetentionMetrics(nil), nil),
+		expired:       make(map[influxdb.ID]int64),
 	}
 }
 
@@ -124,6 +138,7 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 			"retention_policy", b.RetentionPolicyName)
 
 		max := now.Add(-b.RetentionPeriod).UnixNano()
+		reclaimed := s.reclaimedBytes(b, max)
 		err := s.Engine.DeleteBucketRange(b.OrgID, b.ID, math.MinInt64, max)
 		if err != nil {
 			logger.Info("unable to delete bucket range",
@@ -131,6 +146,8 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 				zap.String("org id", b.OrgID.String()),
 				zap.Error(err))
 			tracing.LogError(span, err)
+		} else {
+			s.expiredRange(b, max, reclaimed)
 		}
 		s.tracker.IncChecks(b.OrgID, b.ID, err == nil)
 
@@ -138,6 +155,50 @@ func (s *retentionEnforcer) expireData(ctx context.Context, buckets []*influxdb.
 	}
 }
 
+// reclaimedBytes returns the size of the data of b that a deletion up to max
+// reclaims, leaving out the data the previous deletion of b reclaimed.
+func (s *retentionEnforcer) reclaimedBytes(b *influxdb.Bucket, max int64) int64 {
+	sizer, ok := s.Engine.(RangeSizer)
+	if !ok {
+		return 0
+	}
+
+	min := int64(math.MinInt64)
+	if prev, ok := s.expired[b.ID]; ok {
+		if prev >= max {
+			return 0
+		}
+		min = prev + 1
+	}
+
+	n, err := sizer.BucketRangeBytes(b.OrgID, b.ID, min, max)
+	if err != nil {
+		s.logger.Info("unable to determine bucket range size",
+			zap.String("bucket id", b.ID.String()),
+			zap.String("org id", b.OrgID.String()),
+			zap.Error(err))
+		return 0
+	}
+	return n
+}
+
+// expiredRange records the deletion of the data of b up to max, and publishes it.
+func (s *retentionEnforcer) expiredRange(b *influxdb.Bucket, max, reclaimed int64) {
+	e := RetentionEvent{
+		OrgID:    b.OrgID,
+		BucketID: b.ID,
+		Stop:     time.Unix(0, max).UTC(),
+		Bytes:    reclaimed,
+	}
+	if prev, ok := s.expired[b.ID]; ok {
+		e.Start = time.Unix(0, prev).UTC()
+	}
+	s.expired[b.ID] = max
+
+	s.tracker.AddReclaimedBytes(b.OrgID, b.ID, reclaimed)
+	s.Events.publish(e)
+}
+
 // getBucketInformation returns a slice of buckets to run retention on.
 func (s *retentionEnforcer) getBucketInformation(ctx context.Context) ([]*influxdb.Bucket, error) {
 	ctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
@@ -184,6 +245,15 @@ func (t *retentionTracker) IncChecks(orgID, bucketID influxdb.ID, success bool)
 	t.metrics.Checks.With(labels).Inc()
 }
 
+// AddReclaimedBytes records the bytes that a deletion of expired data of some bucket reclaims.
+func (t *retentionTracker) AddReclaimedBytes(orgID, bucketID influxdb.ID, n int64) {
+	labels := t.Labels()
+	labels["org_id"] = orgID.String()
+	labels["bucket_id"] = bucketID.String()
+
+	t.metrics.ReclaimedBytes.With(labels).Add(float64(n))
+}
+
 // CheckDuration records the overall duration of a full retention check.
 func (t *retentionTracker) CheckDuration(dur time.Duration, success bool) {
 	labels := t.Labels()
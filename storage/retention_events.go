@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// retentionEventBufferSize is the number of retention events buffered for each subscriber.
+// Events are dropped for subscribers that fall further behind, so a slow subscriber never
+// holds up the retention enforcer.
+const retentionEventBufferSize = 64
+
+// A RetentionEvent is the deletion of the data of a bucket that fell out of its
+// retention period.
+type RetentionEvent struct {
+	OrgID    influxdb.ID
+	BucketID influxdb.ID
+
+	// Start and Stop are the time range of the deleted data. Start is zero for
+	// the first deletion of a bucket since the engine opened, which includes any
+	// data that expired earlier.
+	Start time.Time
+	Stop  time.Time
+
+	// Bytes is the size of the blocks of data files that only hold deleted data.
+	// The space is reclaimed when compactions remove the blocks.
+	Bytes int64
+}
+
+// RetentionEvents publishes the deletions of the retention enforcer to the
+// components that subscribe to them, such as the invalidation of caches.
+type RetentionEvents struct {
+	mu   sync.Mutex
+	subs map[chan RetentionEvent]struct{}
+}
+
+// NewRetentionEvents returns a RetentionEvents without subscribers.
+func NewRetentionEvents() *RetentionEvents {
+	return &RetentionEvents{
+		subs: make(map[chan RetentionEvent]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the retention events from now on,
+// which is closed when ctx is done.
+func (r *RetentionEvents) Subscribe(ctx context.Context) <-chan RetentionEvent {
+	ch := make(chan RetentionEvent, retentionEventBufferSize)
+
+	r.mu.Lock()
+	r.subs[ch] = struct{}{}
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		delete(r.subs, ch)
+		close(ch)
+		r.mu.Unlock()
+	}()
+	return ch
+}
+
+// publish sends e to the subscribers that have room for it.
+func (r *RetentionEvents) publish(e RetentionEvent) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for ch := range r.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
@@ -100,6 +100,7 @@ func TestMetrics_Retention(t *testing.T) {
 		tracker.IncChecks(influxdb.ID(i+1), influxdb.ID(i+1), false)
 		tracker.CheckDuration(time.Second, true)
 		tracker.CheckDuration(time.Second, false)
+		tracker.AddReclaimedBytes(influxdb.ID(i+1), influxdb.ID(i+1), 1024)
 	}
 
 	// Test that all the correct metrics are present.
@@ -137,6 +138,66 @@ func TestMetrics_Retention(t *testing.T) {
 				t.Errorf("[%s %d %v] got %v, expected %v", name, i, labels, got, exp)
 			}
 		}
+
+		l := make(prometheus.Labels, len(labels))
+		for k, v := range labels {
+			l[k] = v
+		}
+		delete(l, "status")
+		l["org_id"] = influxdb.ID(i + 1).String()
+		l["bucket_id"] = influxdb.ID(i + 1).String()
+
+		name := base + "reclaimed_bytes_total"
+		metric := promtest.MustFindMetric(t, mfs, name, l)
+		if got, exp := metric.GetCounter().GetValue(), float64(1024); got != exp {
+			t.Errorf("[%s %d %v] got %v, expected %v", name, i, l, got, exp)
+		}
+	}
+}
+
+func TestRetentionService_Events(t *testing.T) {
+	engine := NewTestEngine()
+	service := newRetentionEnforcer(engine, NewTestBucketFinder())
+	service.Events = NewRetentionEvents()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := service.Events.Subscribe(ctx)
+
+	var sized [][2]int64
+	engine.BucketRangeBytesFn = func(orgID, bucketID influxdb.ID, min, max int64) (int64, error) {
+		sized = append(sized, [2]int64{min, max})
+		return 100, nil
+	}
+
+	buckets := []*influxdb.Bucket{{OrgID: 1, ID: 2, RetentionPeriod: time.Hour}}
+	now := time.Date(2018, 4, 10, 23, 0, 0, 0, time.UTC)
+	service.expireData(context.Background(), buckets, now)
+	service.expireData(context.Background(), buckets, now.Add(time.Hour))
+
+	first, second := now.Add(-time.Hour), now
+	want := []RetentionEvent{
+		{OrgID: 1, BucketID: 2, Stop: first, Bytes: 100},
+		{OrgID: 1, BucketID: 2, Start: first, Stop: second, Bytes: 100},
+	}
+	for _, w := range want {
+		if got := <-events; got != w {
+			t.Errorf("got event %+v, expected %+v", got, w)
+		}
+	}
+
+	// The second deletion only counts the data after the first one.
+	wantSized := [][2]int64{
+		{math.MinInt64, first.UnixNano()},
+		{first.UnixNano() + 1, second.UnixNano()},
+	}
+	if !reflect.DeepEqual(sized, wantSized) {
+		t.Errorf("got sized ranges %v, expected %v", sized, wantSized)
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("expected the events to be closed once the subscriber is done")
 	}
 }
 
@@ -152,11 +213,13 @@ func genMeasurementName() []byte {
 
 type TestEngine struct {
 	DeleteBucketRangeFn func(influxdb.ID, influxdb.ID, int64, int64) error
+	BucketRangeBytesFn  func(influxdb.ID, influxdb.ID, int64, int64) (int64, error)
 }
 
 func NewTestEngine() *TestEngine {
 	return &TestEngine{
 		DeleteBucketRangeFn: func(influxdb.ID, influxdb.ID, int64, int64) error { return nil },
+		BucketRangeBytesFn:  func(influxdb.ID, influxdb.ID, int64, int64) (int64, error) { return 0, nil },
 	}
 }
 
@@ -164,6 +227,10 @@ func (e *TestEngine) DeleteBucketRange(orgID, bucketID influxdb.ID, min, max int
 	return e.DeleteBucketRangeFn(orgID, bucketID, min, max)
 }
 
+func (e *TestEngine) BucketRangeBytes(orgID, bucketID influxdb.ID, min, max int64) (int64, error) {
+	return e.BucketRangeBytesFn(orgID, bucketID, min, max)
+}
+
 type TestBucketFinder struct {
 	FindBucketsFn func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error)
 }
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.RollupVerificationService = (*RollupVerificationService)(nil)
+
+// RollupVerificationService verifies rollups by querying their buckets.
+type RollupVerificationService struct {
+	qs     query.QueryService
+	logger *zap.Logger
+}
+
+// NewRollupVerificationService returns a new RollupVerificationService that queries the buckets with qs.
+func NewRollupVerificationService(qs query.QueryService, logger *zap.Logger) *RollupVerificationService {
+	return &RollupVerificationService{
+		qs:     qs,
+		logger: logger.With(zap.String("service", "rollup-verification")),
+	}
+}
+
+// rollupWindow is a window of a rollup.
+type rollupWindow struct {
+	start, stop time.Time
+}
+
+// VerifyRollup compares the aggregate of the raw data of every sampled window
+// with the rollup of the window, series by series.
+func (s *RollupVerificationService) VerifyRollup(ctx context.Context, req influxdb.RollupVerificationRequest) (*influxdb.RollupVerification, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := req.Valid(); err != nil {
+		return nil, err
+	}
+
+	windows := sampleRollupWindows(req.Start, req.Stop, req.Every, req.Samples)
+	if len(windows) == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "no complete window lies between start and stop",
+		}
+	}
+
+	auth, err := icontext.GetAuthorization(ctx, req.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &influxdb.RollupVerification{
+		Discrepancies: []*influxdb.RollupDiscrepancy{},
+	}
+	for _, w := range windows {
+		raw, err := s.readSeries(ctx, auth, req.OrgID, rawAggregateScript(req, w))
+		if err != nil {
+			return nil, err
+		}
+		if len(raw) == 0 {
+			v.SkippedWindows++
+			continue
+		}
+
+		rollup, err := s.readSeries(ctx, auth, req.OrgID, rollupScript(req, w))
+		if err != nil {
+			return nil, err
+		}
+
+		v.Windows++
+		v.Series += compareRollup(v, w, raw, rollup, req.Tolerance)
+	}
+
+	s.logger.Debug("Rollup verified",
+		zap.Stringer("bucket_id", req.BucketID),
+		zap.Stringer("source_bucket_id", req.SourceBucketID),
+		zap.Int("windows", v.Windows),
+		zap.Int("discrepancies", len(v.Discrepancies)))
+
+	return v, nil
+}
+
+// sampleRollupWindows returns up to samples windows that lie between start
+// and stop, spread evenly and including the first and the last one.
+func sampleRollupWindows(start, stop time.Time, every time.Duration, samples int) []rollupWindow {
+	first := alignTime(start, every)
+	if first.Before(start) {
+		first = first.Add(every)
+	}
+	n := int64(alignTime(stop, every).Sub(first) / every)
+	if n <= 0 {
+		return nil
+	}
+	if int64(samples) > n {
+		samples = int(n)
+	}
+
+	windows := make([]rollupWindow, 0, samples)
+	for i := 0; i < samples; i++ {
+		j := n - 1
+		if samples > 1 {
+			j = int64(i) * (n - 1) / int64(samples-1)
+		}
+		s := first.Add(time.Duration(j) * every)
+		windows = append(windows, rollupWindow{start: s, stop: s.Add(every)})
+	}
+	return windows
+}
+
+// alignTime returns the start of the window of every that t is in, aligned
+// to the Unix epoch like the windows of Flux.
+func alignTime(t time.Time, every time.Duration) time.Time {
+	ns := t.UnixNano()
+	r := ns % int64(every)
+	if r < 0 {
+		r += int64(every)
+	}
+	return time.Unix(0, ns-r).UTC()
+}
+
+func rawAggregateScript(req influxdb.RollupVerificationRequest, w rollupWindow) string {
+	return fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: %s, stop: %s)
+	|> filter(fn: (r) => r._measurement == %q and r._field == %q)
+	|> %s()`,
+		req.SourceBucketID.String(),
+		w.start.Format(time.RFC3339Nano), w.stop.Format(time.RFC3339Nano),
+		req.Measurement, req.Field,
+		req.Aggregate)
+}
+
+func rollupScript(req influxdb.RollupVerificationRequest, w rollupWindow) string {
+	ts := w.stop
+	if req.TimeSrc == "_start" {
+		ts = w.start
+	}
+	return fmt.Sprintf(`from(bucketID: %q)
+	|> range(start: %s, stop: %s)
+	|> filter(fn: (r) => r._measurement == %q and r._field == %q)
+	|> last()`,
+		req.BucketID.String(),
+		ts.Format(time.RFC3339Nano), ts.Add(time.Nanosecond).Format(time.RFC3339Nano),
+		req.Measurement, req.Field)
+}
+
+// readSeries runs the script and returns the first value of every series of its result.
+func (s *RollupVerificationService) readSeries(ctx context.Context, auth *influxdb.Authorization, orgID influxdb.ID, script string) (map[string]float64, error) {
+	it, err := s.qs.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: orgID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	series := make(map[string]float64)
+	for it.More() {
+		if err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			return readSeriesValue(tbl, series)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// readSeriesValue reads the first valid value of the table into series,
+// keyed by the tags of the table.
+func readSeriesValue(tbl flux.Table, series map[string]float64) error {
+	key := rollupSeriesKey(tbl.Key())
+	return tbl.Do(func(cr flux.ColReader) error {
+		if _, ok := series[key]; ok {
+			return nil
+		}
+		j := -1
+		for k, col := range cr.Cols() {
+			if col.Label == "_value" {
+				j = k
+			}
+		}
+		if j < 0 {
+			return nil
+		}
+
+		for i := 0; i < cr.Len(); i++ {
+			switch cr.Cols()[j].Type {
+			case flux.TFloat:
+				if vs := cr.Floats(j); vs.IsValid(i) {
+					series[key] = vs.Value(i)
+					return nil
+				}
+			case flux.TInt:
+				if vs := cr.Ints(j); vs.IsValid(i) {
+					series[key] = float64(vs.Value(i))
+					return nil
+				}
+			case flux.TUInt:
+				if vs := cr.UInts(j); vs.IsValid(i) {
+					series[key] = float64(vs.Value(i))
+					return nil
+				}
+			default:
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// rollupSeriesKey returns the tags of a group key, such as "host=a,region=eu".
+func rollupSeriesKey(key flux.GroupKey) string {
+	tags := make([]string, 0, len(key.Cols()))
+	for j, col := range key.Cols() {
+		switch col.Label {
+		case "_start", "_stop", "_measurement", "_field":
+			continue
+		}
+		if col.Type != flux.TString {
+			continue
+		}
+		tags = append(tags, col.Label+"="+key.ValueString(j))
+	}
+	sort.Strings(tags)
+	return strings.Join(tags, ",")
+}
+
+// compareRollup adds the discrepancies between the raw aggregates and the
+// rollups of a window to v and returns the number of series compared.
+func compareRollup(v *influxdb.RollupVerification, w rollupWindow, raw, rollup map[string]float64, tolerance float64) int {
+	keys := make([]string, 0, len(raw)+len(rollup))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	for k := range rollup {
+		if _, ok := raw[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		expected, hasRaw := raw[k]
+		actual, hasRollup := rollup[k]
+
+		var reason string
+		switch {
+		case !hasRollup:
+			reason = influxdb.RollupMissing
+		case !hasRaw:
+			reason = influxdb.RollupUnexpected
+		case math.Abs(actual-expected) > tolerance*math.Abs(expected):
+			reason = influxdb.RollupMismatch
+		default:
+			continue
+		}
+
+		d := &influxdb.RollupDiscrepancy{
+			Start:  w.start,
+			Stop:   w.stop,
+			Series: k,
+			Reason: reason,
+		}
+		if hasRaw {
+			d.Expected = &expected
+		}
+		if hasRollup {
+			d.Actual = &actual
+		}
+		v.Discrepancies = append(v.Discrepancies, d)
+	}
+	return len(keys)
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSampleRollupWindows(t *testing.T) {
+	day := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		start   time.Time
+		stop    time.Time
+		samples int
+		want    []time.Time
+	}{
+		{
+			name:    "every window",
+			start:   day,
+			stop:    day.Add(3 * time.Hour),
+			samples: 10,
+			want:    []time.Time{day, day.Add(time.Hour), day.Add(2 * time.Hour)},
+		},
+		{
+			name:    "incomplete windows are left out",
+			start:   day.Add(30 * time.Minute),
+			stop:    day.Add(3*time.Hour + 30*time.Minute),
+			samples: 10,
+			want:    []time.Time{day.Add(time.Hour), day.Add(2 * time.Hour)},
+		},
+		{
+			name:    "spread evenly",
+			start:   day,
+			stop:    day.Add(24 * time.Hour),
+			samples: 3,
+			want:    []time.Time{day, day.Add(11 * time.Hour), day.Add(23 * time.Hour)},
+		},
+		{
+			name:    "no complete window",
+			start:   day.Add(10 * time.Minute),
+			stop:    day.Add(50 * time.Minute),
+			samples: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows := sampleRollupWindows(tt.start, tt.stop, time.Hour, tt.samples)
+			if len(windows) != len(tt.want) {
+				t.Fatalf("got %d windows, want %d", len(windows), len(tt.want))
+			}
+			for i, w := range windows {
+				if !w.start.Equal(tt.want[i]) || w.stop.Sub(w.start) != time.Hour {
+					t.Errorf("got window %v-%v, want one starting at %v", w.start, w.stop, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRollupVerificationService_VerifyRollup(t *testing.T) {
+	day := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	seriesTable := func(host string, value float64) *executetest.Table {
+		return &executetest.Table{
+			KeyCols: []string{"_measurement", "_field", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{value, "cpu", "usage", host},
+			},
+		}
+	}
+
+	var scripts []string
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			script := req.Compiler.(lang.FluxCompiler).Query
+			scripts = append(scripts, script)
+
+			var tables []*executetest.Table
+			if strings.Contains(script, `from(bucketID: "0000000000000002")`) {
+				tables = []*executetest.Table{seriesTable("a", 10), seriesTable("b", 5), seriesTable("c", 2)}
+			} else {
+				tables = []*executetest.Table{seriesTable("a", 10.000001), seriesTable("b", 6), seriesTable("d", 1)}
+			}
+			return flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm:   "_result",
+				Tbls: tables,
+			}}), nil
+		},
+	}
+
+	s := NewRollupVerificationService(qs, zaptest.NewLogger(t))
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{OrgID: 1})
+	v, err := s.VerifyRollup(ctx, influxdb.RollupVerificationRequest{
+		OrgID:          1,
+		BucketID:       3,
+		SourceBucketID: 2,
+		Measurement:    "cpu",
+		Field:          "usage",
+		Aggregate:      influxdb.RollupAggregateMean,
+		Every:          time.Hour,
+		TimeSrc:        "_stop",
+		Start:          day,
+		Stop:           day.Add(time.Hour),
+		Samples:        1,
+		Tolerance:      0.001,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(scripts) != 2 {
+		t.Fatalf("got %d queries, want 2", len(scripts))
+	}
+	for _, want := range []string{`range(start: 2019-07-01T00:00:00Z, stop: 2019-07-01T01:00:00Z)`, `|> mean()`} {
+		if !strings.Contains(scripts[0], want) {
+			t.Errorf("expected the raw query to contain %q, got:\n%s", want, scripts[0])
+		}
+	}
+	if want := `range(start: 2019-07-01T01:00:00Z, stop: 2019-07-01T01:00:00.000000001Z)`; !strings.Contains(scripts[1], want) {
+		t.Errorf("expected the rollup query to contain %q, got:\n%s", want, scripts[1])
+	}
+
+	if v.Windows != 1 || v.SkippedWindows != 0 || v.Series != 4 {
+		t.Errorf("unexpected verification %+v", v)
+	}
+	want := []struct {
+		series string
+		reason string
+	}{
+		{series: "host=b", reason: influxdb.RollupMismatch},
+		{series: "host=c", reason: influxdb.RollupMissing},
+		{series: "host=d", reason: influxdb.RollupUnexpected},
+	}
+	if len(v.Discrepancies) != len(want) {
+		t.Fatalf("got %d discrepancies, want %d", len(v.Discrepancies), len(want))
+	}
+	for i, d := range v.Discrepancies {
+		if d.Series != want[i].series || d.Reason != want[i].reason {
+			t.Errorf("got discrepancy %s %s, want %s %s", d.Series, d.Reason, want[i].series, want[i].reason)
+		}
+	}
+	if d := v.Discrepancies[0]; d.Expected == nil || *d.Expected != 5 || d.Actual == nil || *d.Actual != 6 {
+		t.Errorf("unexpected values of the mismatch %+v", d)
+	}
+}
@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb/cursors"
+	"github.com/influxdata/influxql"
+)
+
+// A SeriesSchemaReader reads the tag keys and tag values of the series of
+// buckets from the metadata of the storage engine.
+type SeriesSchemaReader interface {
+	TagKeys(ctx context.Context, orgID, bucketID influxdb.ID, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error)
+	TagValues(ctx context.Context, orgID, bucketID influxdb.ID, tagKey string, start, end int64, predicate influxql.Expr) (cursors.StringIterator, error)
+}
+
+var _ influxdb.SeriesSchemaService = (*SeriesSchemaService)(nil)
+
+// SeriesSchemaService lists the measurements, tag keys and tag values of
+// buckets with the schema reads of the engine, which are much cheaper than the
+// Flux queries of the schema package.
+type SeriesSchemaService struct {
+	engine SeriesSchemaReader
+}
+
+// NewSeriesSchemaService returns a new SeriesSchemaService for the provided
+// SeriesSchemaReader, which typically will be an Engine.
+func NewSeriesSchemaService(engine SeriesSchemaReader) *SeriesSchemaService {
+	return &SeriesSchemaService{engine: engine}
+}
+
+// FindMeasurements returns a page of the measurements of the series selected by the filter.
+func (s *SeriesSchemaService) FindMeasurements(ctx context.Context, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := filter.Valid(); err != nil {
+		return nil, err
+	}
+	return s.findTagValues(ctx, models.MeasurementTagKey, filter)
+}
+
+// FindTagKeys returns a page of the tag keys of the series selected by the
+// filter. The keys of the measurement and the field are not tag keys, so they
+// are left out.
+func (s *SeriesSchemaService) FindTagKeys(ctx context.Context, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := filter.Valid(); err != nil {
+		return nil, err
+	}
+	pred, err := seriesSchemaPredicate(filter)
+	if err != nil {
+		return nil, err
+	}
+	start, end := seriesSchemaTimeRange(filter)
+	itr, err := s.engine.TagKeys(ctx, filter.OrgID, filter.BucketID, start, end, pred)
+	if err != nil {
+		return nil, err
+	}
+	return seriesSchemaPage(itr, filter, func(key string) bool {
+		return key != models.MeasurementTagKey && key != models.FieldKeyTagKey
+	}), nil
+}
+
+// FindTagValues returns a page of the values of a tag key of the series selected by the filter.
+func (s *SeriesSchemaService) FindTagValues(ctx context.Context, tagKey string, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if tagKey == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "tag key is required",
+		}
+	}
+	if err := filter.Valid(); err != nil {
+		return nil, err
+	}
+	return s.findTagValues(ctx, seriesSchemaTagKey(tagKey), filter)
+}
+
+func (s *SeriesSchemaService) findTagValues(ctx context.Context, tagKey string, filter influxdb.SeriesSchemaFilter) ([]string, error) {
+	pred, err := seriesSchemaPredicate(filter)
+	if err != nil {
+		return nil, err
+	}
+	start, end := seriesSchemaTimeRange(filter)
+	itr, err := s.engine.TagValues(ctx, filter.OrgID, filter.BucketID, tagKey, start, end, pred)
+	if err != nil {
+		return nil, err
+	}
+	return seriesSchemaPage(itr, filter, nil), nil
+}
+
+// seriesSchemaPage returns the page of the values of the iterator selected by
+// the filter. The values of the iterator are sorted, so it stops reading once
+// the page is full.
+func seriesSchemaPage(itr cursors.StringIterator, filter influxdb.SeriesSchemaFilter, keep func(string) bool) []string {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = influxdb.SeriesSchemaDefaultLimit
+	}
+
+	values := []string{}
+	for len(values) < limit && itr.Next() {
+		v := itr.Value()
+		if filter.After != "" && v <= filter.After {
+			continue
+		}
+		if !strings.HasPrefix(v, filter.Prefix) {
+			continue
+		}
+		if keep != nil && !keep(v) {
+			continue
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// seriesSchemaTimeRange returns the time range of the filter in nanoseconds,
+// which is every time a point may have if the filter has none.
+func seriesSchemaTimeRange(filter influxdb.SeriesSchemaFilter) (int64, int64) {
+	start, end := int64(models.MinNanoTime), int64(models.MaxNanoTime)
+	if !filter.Start.IsZero() {
+		start = filter.Start.UnixNano()
+	}
+	if !filter.Stop.IsZero() {
+		end = filter.Stop.UnixNano()
+	}
+	return start, end
+}
+
+// seriesSchemaPredicate returns the expression matching the series of the
+// measurement of the filter, if there is one, that match all of its tag
+// predicates, or nil if it matches every series.
+func seriesSchemaPredicate(filter influxdb.SeriesSchemaFilter) (influxql.Expr, error) {
+	var expr influxql.Expr
+	add := func(e influxql.Expr) {
+		if expr == nil {
+			expr = e
+			return
+		}
+		expr = &influxql.BinaryExpr{Op: influxql.AND, LHS: expr, RHS: e}
+	}
+
+	if filter.Measurement != "" {
+		add(&influxql.BinaryExpr{
+			Op:  influxql.EQ,
+			LHS: &influxql.VarRef{Val: models.MeasurementTagKey},
+			RHS: &influxql.StringLiteral{Val: filter.Measurement},
+		})
+	}
+	for _, p := range filter.Tags {
+		e := &influxql.BinaryExpr{
+			LHS: &influxql.VarRef{Val: seriesSchemaTagKey(p.Key)},
+			RHS: &influxql.StringLiteral{Val: p.Value},
+		}
+		switch p.Oper() {
+		case influxdb.TagPredicateNotEqual:
+			e.Op = influxql.NEQ
+		case influxdb.TagPredicateRegex, influxdb.TagPredicateNotRegex:
+			re, err := regexp.Compile(p.Value)
+			if err != nil {
+				return nil, &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "invalid regular expression for tag " + p.Key,
+					Err:  err,
+				}
+			}
+			e.Op, e.RHS = influxql.EQREGEX, &influxql.RegexLiteral{Val: re}
+			if p.Oper() == influxdb.TagPredicateNotRegex {
+				e.Op = influxql.NEQREGEX
+			}
+		default:
+			e.Op = influxql.EQ
+		}
+		add(e)
+	}
+	return expr, nil
+}
+
+// seriesSchemaTagKey returns the key the engine stores a tag key as, which
+// differs from the key queries use for the measurement and the field.
+func seriesSchemaTagKey(key string) string {
+	switch key {
+	case "_measurement":
+		return models.MeasurementTagKey
+	case "_field":
+		return models.FieldKeyTagKey
+	}
+	return key
+}
@@ -0,0 +1,139 @@
+package storage_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/storage"
+	"github.com/influxdata/influxdb/tsdb"
+)
+
+func TestSeriesSchemaService(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	p := func(measurement string, tags map[string]string, ts time.Time) models.Point {
+		tags[models.FieldKeyTagKey] = "value"
+		tags[models.MeasurementTagKey] = measurement
+		return models.MustNewPoint(
+			tsdb.EncodeNameString(engine.org, engine.bucket),
+			models.NewTags(tags),
+			map[string]interface{}{"value": 1.0},
+			ts,
+		)
+	}
+
+	if err := engine.Engine.WritePoints(context.TODO(), []models.Point{
+		p("cpu", map[string]string{"host": "a", "region": "east"}, time.Unix(1, 0)),
+		p("cpu", map[string]string{"host": "b", "region": "west"}, time.Unix(1, 0)),
+		p("cpu", map[string]string{"host": "c", "region": "west"}, time.Unix(100, 0)),
+		p("mem", map[string]string{"host": "d"}, time.Unix(1, 0)),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := storage.NewSeriesSchemaService(engine)
+	ctx := context.Background()
+	filter := func(f influxdb.SeriesSchemaFilter) influxdb.SeriesSchemaFilter {
+		f.OrgID, f.BucketID = engine.org, engine.bucket
+		return f
+	}
+
+	tests := []struct {
+		name string
+		find func() ([]string, error)
+		want []string
+	}{
+		{
+			name: "measurements",
+			find: func() ([]string, error) { return s.FindMeasurements(ctx, filter(influxdb.SeriesSchemaFilter{})) },
+			want: []string{"cpu", "mem"},
+		},
+		{
+			name: "measurements of tag",
+			find: func() ([]string, error) {
+				return s.FindMeasurements(ctx, filter(influxdb.SeriesSchemaFilter{
+					Tags: []influxdb.SeriesTagPredicate{{Key: "host", Value: "d"}},
+				}))
+			},
+			want: []string{"mem"},
+		},
+		{
+			name: "tag keys",
+			find: func() ([]string, error) { return s.FindTagKeys(ctx, filter(influxdb.SeriesSchemaFilter{})) },
+			want: []string{"host", "region"},
+		},
+		{
+			name: "tag keys of measurement",
+			find: func() ([]string, error) {
+				return s.FindTagKeys(ctx, filter(influxdb.SeriesSchemaFilter{Measurement: "mem"}))
+			},
+			want: []string{"host"},
+		},
+		{
+			name: "tag values",
+			find: func() ([]string, error) {
+				return s.FindTagValues(ctx, "host", filter(influxdb.SeriesSchemaFilter{Measurement: "cpu"}))
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "tag values of time range",
+			find: func() ([]string, error) {
+				return s.FindTagValues(ctx, "host", filter(influxdb.SeriesSchemaFilter{
+					Start: time.Unix(50, 0),
+					Stop:  time.Unix(150, 0),
+				}))
+			},
+			want: []string{"c"},
+		},
+		{
+			name: "tag values matching predicates",
+			find: func() ([]string, error) {
+				return s.FindTagValues(ctx, "host", filter(influxdb.SeriesSchemaFilter{
+					Tags: []influxdb.SeriesTagPredicate{
+						{Key: "region", Op: influxdb.TagPredicateRegex, Value: "^w"},
+						{Key: "host", Op: influxdb.TagPredicateNotEqual, Value: "b"},
+					},
+				}))
+			},
+			want: []string{"c"},
+		},
+		{
+			name: "page",
+			find: func() ([]string, error) {
+				return s.FindTagValues(ctx, "host", filter(influxdb.SeriesSchemaFilter{After: "a", Limit: 2}))
+			},
+			want: []string{"b", "c"},
+		},
+		{
+			name: "prefix",
+			find: func() ([]string, error) {
+				return s.FindTagValues(ctx, "region", filter(influxdb.SeriesSchemaFilter{Prefix: "we"}))
+			},
+			want: []string{"west"},
+		},
+		{
+			name: "fields",
+			find: func() ([]string, error) { return s.FindTagValues(ctx, "_field", filter(influxdb.SeriesSchemaFilter{})) },
+			want: []string{"value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.find()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
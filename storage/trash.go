@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap"
+)
+
+var _ influxdb.TrashService = (*TrashService)(nil)
+
+// TrashService wraps an influxdb.TrashService, and drops the data of buckets
+// from the storage engine when they are purged from the trash.
+type TrashService struct {
+	influxdb.TrashService
+
+	engine BucketDeleter
+
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+// NewTrashService returns a new TrashService that drops the data of purged
+// buckets from engine.
+func NewTrashService(s influxdb.TrashService, engine BucketDeleter, logger *zap.Logger) *TrashService {
+	return &TrashService{
+		TrashService: s,
+		engine:       engine,
+		now:          time.Now,
+		logger:       logger.With(zap.String("service", "trash")),
+	}
+}
+
+// PurgeTrashedResource permanently deletes a trashed resource, along with its
+// data if it is a bucket.
+func (s *TrashService) PurgeTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) error {
+	if typ == influxdb.BucketsResourceType {
+		r, err := s.TrashService.FindTrashedResource(ctx, typ, id)
+		if err != nil {
+			return err
+		}
+
+		// As when a bucket is deleted, the data is dropped first, so the bucket
+		// is still in the trash to be purged again if this fails.
+		if err := s.engine.DeleteBucket(r.OrgID, r.ID); err != nil {
+			return err
+		}
+	}
+	return s.TrashService.PurgeTrashedResource(ctx, typ, id)
+}
+
+// Cleanup purges the expired resources from the trash every interval until
+// ctx is done.
+func (s *TrashService) Cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.purgeExpired(ctx)
+		}
+	}
+}
+
+// purgeExpired purges every resource that expired.
+func (s *TrashService) purgeExpired(ctx context.Context) {
+	rs, _, err := s.TrashService.FindTrashedResources(ctx, influxdb.TrashFilter{})
+	if err != nil {
+		s.logger.Error("Unable to find trashed resources", zap.Error(err))
+		return
+	}
+
+	now := s.now()
+	for _, r := range rs {
+		if !r.Expired(now) {
+			continue
+		}
+		if err := s.PurgeTrashedResource(ctx, r.Type, r.ID); err != nil {
+			s.logger.Error("Unable to purge expired resource", zap.String("resource_type", string(r.Type)), zap.String("resource_id", r.ID.String()), zap.Error(err))
+			continue
+		}
+		s.logger.Debug("Purged expired resource", zap.String("resource_type", string(r.Type)), zap.String("resource_id", r.ID.String()))
+	}
+}
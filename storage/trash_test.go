@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"go.uber.org/zap/zaptest"
+)
+
+type testTrashEngine struct {
+	deleted []influxdb.ID
+}
+
+func (e *testTrashEngine) DeleteBucket(orgID, bucketID influxdb.ID) error {
+	e.deleted = append(e.deleted, bucketID)
+	return nil
+}
+
+// testTrashStore is a trash of resources that are purged by ID.
+type testTrashStore struct {
+	influxdb.TrashService
+	resources []*influxdb.TrashedResource
+	purged    []influxdb.ID
+}
+
+func (s *testTrashStore) FindTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) (*influxdb.TrashedResource, error) {
+	for _, r := range s.resources {
+		if r.Type == typ && r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, &influxdb.Error{Code: influxdb.ENotFound, Msg: influxdb.ErrTrashedResourceNotFound}
+}
+
+func (s *testTrashStore) FindTrashedResources(ctx context.Context, filter influxdb.TrashFilter, opt ...influxdb.FindOptions) ([]*influxdb.TrashedResource, int, error) {
+	return s.resources, len(s.resources), nil
+}
+
+func (s *testTrashStore) PurgeTrashedResource(ctx context.Context, typ influxdb.ResourceType, id influxdb.ID) error {
+	s.purged = append(s.purged, id)
+	return nil
+}
+
+func TestTrashService_purgeExpired(t *testing.T) {
+	now := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	store := &testTrashStore{
+		resources: []*influxdb.TrashedResource{
+			{Type: influxdb.BucketsResourceType, ID: 2, OrgID: 1, ExpiresAt: now.Add(-time.Second)},
+			{Type: influxdb.DashboardsResourceType, ID: 3, OrgID: 1, ExpiresAt: now.Add(-time.Second)},
+			{Type: influxdb.BucketsResourceType, ID: 4, OrgID: 1, ExpiresAt: now.Add(time.Second)},
+		},
+	}
+
+	engine := &testTrashEngine{}
+	s := NewTrashService(store, engine, zaptest.NewLogger(t))
+	s.now = func() time.Time { return now }
+	s.purgeExpired(context.Background())
+
+	if len(store.purged) != 2 || store.purged[0] != 2 || store.purged[1] != 3 {
+		t.Errorf("expected only the expired resources to be purged, got %v", store.purged)
+	}
+	if len(engine.deleted) != 1 || engine.deleted[0] != 2 {
+		t.Errorf("expected the data of the expired bucket to be dropped, got %v", engine.deleted)
+	}
+}
@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+)
+
+const usageMeasurement = "usage"
+
+// A UsageEngine reports the size of the data of buckets and stores the usage points.
+type UsageEngine interface {
+	BucketRangeBytes(orgID, bucketID influxdb.ID, min, max int64) (int64, error)
+	WritePoints(ctx context.Context, points []models.Point) error
+}
+
+var _ influxdb.UsageRecorder = (*UsageRecorder)(nil)
+
+// UsageRecorder writes the usage of organizations to their monitoring bucket.
+//
+// Usage is counted in memory and written every interval, together with the
+// number of bytes each organization stores, so recording it never slows down
+// the requests that are counted.
+type UsageRecorder struct {
+	engine        UsageEngine
+	bucketService BucketFinder
+
+	mu     sync.Mutex
+	counts map[influxdb.ID]map[influxdb.UsageMetric]int64
+
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+// NewUsageRecorder returns a new UsageRecorder that writes usage with engine.
+// Run must be called for the usage to be written.
+func NewUsageRecorder(engine UsageEngine, finder BucketFinder, logger *zap.Logger) *UsageRecorder {
+	return &UsageRecorder{
+		engine:        engine,
+		bucketService: finder,
+		counts:        make(map[influxdb.ID]map[influxdb.UsageMetric]int64),
+		now:           time.Now,
+		logger:        logger.With(zap.String("service", "usage_recorder")),
+	}
+}
+
+// RecordUsage adds n to the usage metric m of the organization, to be written at the next flush.
+func (r *UsageRecorder) RecordUsage(ctx context.Context, orgID influxdb.ID, m influxdb.UsageMetric, n int64) {
+	if !orgID.Valid() || n == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts, ok := r.counts[orgID]
+	if !ok {
+		counts = make(map[influxdb.UsageMetric]int64)
+		r.counts[orgID] = counts
+	}
+	counts[m] += n
+}
+
+// Run writes the usage counted so far every interval until ctx is done.
+func (r *UsageRecorder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.flush(ctx)
+		}
+	}
+}
+
+// flush writes a point with the usage counted since the last flush, and the
+// number of bytes stored, for every organization.
+func (r *UsageRecorder) flush(ctx context.Context) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	r.mu.Lock()
+	counts := r.counts
+	r.counts = make(map[influxdb.ID]map[influxdb.UsageMetric]int64)
+	r.mu.Unlock()
+
+	fields := make(map[influxdb.ID]models.Fields, len(counts))
+	for orgID, c := range counts {
+		f := make(models.Fields, len(c))
+		for m, n := range c {
+			f[string(m)] = n
+		}
+		fields[orgID] = f
+	}
+
+	fctx, cancel := context.WithTimeout(ctx, bucketAPITimeout)
+	buckets, _, err := r.bucketService.FindBuckets(fctx, influxdb.BucketFilter{})
+	cancel()
+	if err != nil {
+		r.logger.Error("Unable to determine bucket information", zap.Error(err))
+	}
+	for _, b := range buckets {
+		n, err := r.engine.BucketRangeBytes(b.OrgID, b.ID, math.MinInt64, math.MaxInt64)
+		if err != nil {
+			r.logger.Info("Unable to determine bucket size",
+				zap.String("bucket_id", b.ID.String()),
+				zap.String("org_id", b.OrgID.String()),
+				zap.Error(err))
+			continue
+		}
+
+		f, ok := fields[b.OrgID]
+		if !ok {
+			f = make(models.Fields)
+			fields[b.OrgID] = f
+		}
+		stored, _ := f[string(influxdb.UsageStorageBytes)].(int64)
+		f[string(influxdb.UsageStorageBytes)] = stored + n
+	}
+
+	ts := r.now().UTC()
+	for orgID, f := range fields {
+		p, err := models.NewPoint(usageMeasurement, nil, f, ts)
+		if err != nil {
+			r.logger.Info("Unable to record usage", zap.String("org_id", orgID.String()), zap.Error(err))
+			continue
+		}
+
+		exploded, err := tsdb.ExplodePoints(orgID, MonitoringBucketID, models.Points{p})
+		if err == nil {
+			err = r.engine.WritePoints(context.Background(), exploded)
+		}
+		if err != nil {
+			r.logger.Error("Unable to write usage", zap.String("org_id", orgID.String()), zap.Error(err))
+		}
+	}
+}
+
+var _ influxdb.OrgUsageService = (*OrgUsageService)(nil)
+
+// OrgUsageService reads the usage of organizations from their monitoring bucket.
+type OrgUsageService struct {
+	qs     query.QueryService
+	logger *zap.Logger
+}
+
+// NewOrgUsageService returns a new OrgUsageService that queries the monitoring bucket with qs.
+func NewOrgUsageService(qs query.QueryService, logger *zap.Logger) *OrgUsageService {
+	return &OrgUsageService{
+		qs:     qs,
+		logger: logger.With(zap.String("service", "org_usage")),
+	}
+}
+
+// FindOrgUsage returns the usage of an organization in the time range of the filter.
+// Counts are summed over the time range, and the bytes stored are the last recorded.
+func (s *OrgUsageService) FindOrgUsage(ctx context.Context, filter influxdb.OrgUsageFilter) (*influxdb.OrgUsageReport, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+
+	if err := filter.Valid(); err != nil {
+		return nil, err
+	}
+
+	auth, err := icontext.GetAuthorization(ctx, filter.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	script := fmt.Sprintf(`data = from(bucketID: %q)
+	|> range(start: %s, stop: %s)
+	|> filter(fn: (r) => r._measurement == %q)
+
+data
+	|> filter(fn: (r) => r._field != %q)
+	|> sum()
+	|> yield(name: "sum")
+
+data
+	|> filter(fn: (r) => r._field == %q)
+	|> last()
+	|> yield(name: "last")`,
+		MonitoringBucketID.String(),
+		filter.Start.UTC().Format(time.RFC3339Nano), filter.Stop.UTC().Format(time.RFC3339Nano),
+		usageMeasurement, influxdb.UsageStorageBytes, influxdb.UsageStorageBytes)
+
+	it, err := s.qs.Query(ctx, &query.Request{
+		Authorization:  auth,
+		OrganizationID: filter.OrgID,
+		Compiler:       lang.FluxCompiler{Query: script},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	usage := &influxdb.OrgUsageReport{
+		OrgID: filter.OrgID,
+		Start: filter.Start,
+		Stop:  filter.Stop,
+	}
+	for it.More() {
+		err := it.Next().Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(r flux.ColReader) error {
+				return readOrgUsage(usage, r)
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// readOrgUsage adds the usage values of r to usage.
+func readOrgUsage(usage *influxdb.OrgUsageReport, r flux.ColReader) error {
+	fieldIdx, valueIdx := -1, -1
+	for j, col := range r.Cols() {
+		switch {
+		case col.Label == "_field":
+			fieldIdx = j
+		case col.Label == "_value" && col.Type == flux.TInt:
+			valueIdx = j
+		}
+	}
+	if fieldIdx < 0 || valueIdx < 0 {
+		return nil
+	}
+
+	for i := 0; i < r.Len(); i++ {
+		if !r.Ints(valueIdx).IsValid(i) {
+			continue
+		}
+		n := r.Ints(valueIdx).Value(i)
+
+		switch influxdb.UsageMetric(r.Strings(fieldIdx).ValueString(i)) {
+		case influxdb.UsageWriteRequestCount:
+			usage.WriteRequestCount += n
+		case influxdb.UsageWriteRequestBytes:
+			usage.WriteRequestBytes += n
+		case influxdb.UsageQueryRequestCount:
+			usage.QueryRequestCount += n
+		case influxdb.UsageQueryRequestBytes:
+			usage.QueryRequestBytes += n
+		case influxdb.UsageTaskRunCount:
+			usage.TaskRunCount += n
+		case influxdb.UsageStorageBytes:
+			usage.StorageBytes = n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+type testUsageEngine struct {
+	bytes   map[influxdb.ID]int64
+	written []models.Point
+}
+
+func (e *testUsageEngine) BucketRangeBytes(orgID, bucketID influxdb.ID, min, max int64) (int64, error) {
+	n, ok := e.bytes[bucketID]
+	if !ok {
+		return 0, errors.New("bucket not found")
+	}
+	return n, nil
+}
+
+func (e *testUsageEngine) WritePoints(ctx context.Context, points []models.Point) error {
+	e.written = append(e.written, points...)
+	return nil
+}
+
+func TestUsageRecorder(t *testing.T) {
+	engine := &testUsageEngine{bytes: map[influxdb.ID]int64{2: 100, 3: 50}}
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, influxdb.BucketFilter, ...influxdb.FindOptions) ([]*influxdb.Bucket, int, error) {
+		return []*influxdb.Bucket{
+			{ID: 2, OrgID: 1},
+			{ID: 3, OrgID: 1},
+			{ID: 4, OrgID: 1}, // Its size can't be determined, so it is skipped.
+		}, 3, nil
+	}
+
+	now := time.Date(2019, 7, 1, 13, 14, 15, 0, time.UTC)
+	r := NewUsageRecorder(engine, finder, zaptest.NewLogger(t))
+	r.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	r.RecordUsage(ctx, 1, influxdb.UsageWriteRequestCount, 1)
+	r.RecordUsage(ctx, 1, influxdb.UsageWriteRequestBytes, 10)
+	r.RecordUsage(ctx, 1, influxdb.UsageWriteRequestCount, 1)
+	r.RecordUsage(ctx, 1, influxdb.UsageWriteRequestBytes, 20)
+	r.RecordUsage(ctx, 5, influxdb.UsageTaskRunCount, 1)
+	r.RecordUsage(ctx, 0, influxdb.UsageQueryRequestCount, 1) // Usage without an organization is ignored.
+	r.flush(ctx)
+
+	got := make(map[string]models.Fields)
+	for _, p := range engine.written {
+		if m := string(p.Tags().Get(models.MeasurementTagKeyBytes)); m != usageMeasurement {
+			t.Errorf("got measurement %q, want %q", m, usageMeasurement)
+		}
+		if !p.Time().Equal(now) {
+			t.Errorf("got time %v, want %v", p.Time(), now)
+		}
+
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		field := string(p.Tags().Get(models.FieldKeyTagKeyBytes))
+		name := string(p.Name())
+		if got[name] == nil {
+			got[name] = make(models.Fields)
+		}
+		got[name][field] = fields[field]
+	}
+
+	want := map[string]models.Fields{
+		tsdb.EncodeNameString(1, MonitoringBucketID): {
+			string(influxdb.UsageWriteRequestCount): int64(2),
+			string(influxdb.UsageWriteRequestBytes): int64(30),
+			string(influxdb.UsageStorageBytes):      int64(150),
+		},
+		tsdb.EncodeNameString(5, MonitoringBucketID): {
+			string(influxdb.UsageTaskRunCount): int64(1),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got usage %v, want %v", got, want)
+	}
+
+	// The counts are reset by the flush, so only the size is written again.
+	engine.written = nil
+	r.flush(ctx)
+	if len(engine.written) != 1 || string(engine.written[0].Tags().Get(models.FieldKeyTagKeyBytes)) != string(influxdb.UsageStorageBytes) {
+		t.Errorf("expected only the size to be written again, got %v", engine.written)
+	}
+}
+
+func TestOrgUsageService_FindOrgUsage(t *testing.T) {
+	day := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	var script string
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			script = req.Compiler.(lang.FluxCompiler).Query
+			table := func(field string, value int64) *executetest.Table {
+				return &executetest.Table{
+					KeyCols: []string{"_measurement", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_value", Type: flux.TInt},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{value, usageMeasurement, field},
+					},
+				}
+			}
+			return flux.NewSliceResultIterator([]flux.Result{
+				&executetest.Result{
+					Nm: "sum",
+					Tbls: []*executetest.Table{
+						table(string(influxdb.UsageWriteRequestCount), 2),
+						table(string(influxdb.UsageWriteRequestBytes), 30),
+						table(string(influxdb.UsageQueryRequestCount), 4),
+						table(string(influxdb.UsageTaskRunCount), 5),
+					},
+				},
+				&executetest.Result{
+					Nm:   "last",
+					Tbls: []*executetest.Table{table(string(influxdb.UsageStorageBytes), 150)},
+				},
+			}), nil
+		},
+	}
+
+	s := NewOrgUsageService(qs, zaptest.NewLogger(t))
+	ctx := icontext.SetAuthorizer(context.Background(), &influxdb.Authorization{OrgID: 1})
+	filter := influxdb.OrgUsageFilter{
+		OrgID: 1,
+		Start: day,
+		Stop:  day.Add(48 * time.Hour),
+	}
+	usage, err := s.FindOrgUsage(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`from(bucketID: "000000000000000b")`, `range(start: 2019-07-01T00:00:00Z, stop: 2019-07-03T00:00:00Z)`, `r._measurement == "usage"`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected query to contain %q, got:\n%s", want, script)
+		}
+	}
+
+	want := &influxdb.OrgUsageReport{
+		OrgID:             1,
+		Start:             filter.Start,
+		Stop:              filter.Stop,
+		WriteRequestCount: 2,
+		WriteRequestBytes: 30,
+		QueryRequestCount: 4,
+		TaskRunCount:      5,
+		StorageBytes:      150,
+	}
+	if !reflect.DeepEqual(usage, want) {
+		t.Errorf("got usage %+v, want %+v", usage, want)
+	}
+
+	filter.Stop = filter.Start
+	if _, err := s.FindOrgUsage(ctx, filter); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("expected an invalid error for an empty time range, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	writeRejectionsMeasurement = "write_rejections"
+	reasonTag                  = "reason"
+	tokenHashTag               = "tokenHash"
+	lineField                  = "line"
+	messageField               = "message"
+
+	// writeRejectionBufferSize is the number of rejections buffered before
+	// further rejections are dropped.
+	writeRejectionBufferSize = 1000
+)
+
+// WriteRejectionConfig configures the sampling of the rejected writes that are recorded.
+type WriteRejectionConfig struct {
+	// SampleEvery records one in every SampleEvery rejections.
+	// Rejections are not recorded if it is zero.
+	SampleEvery int
+	// MaxPerSecond is the number of rejections recorded per second at most,
+	// after sampling. There is no limit if it is zero.
+	MaxPerSecond int
+	// MaxLineBytes is the number of bytes of the offending line that are recorded.
+	MaxLineBytes int
+}
+
+var _ influxdb.WriteRejectionRecorder = (*WriteRejectionRecorder)(nil)
+
+// WriteRejectionRecorder writes rejected writes to the monitoring bucket of their
+// organization, so the clients sending data that is not accepted can be found
+// with a query.
+//
+// Rejections are sampled and written in batches in the background, so recording
+// them never slows down writes. Rejections are dropped while the buffer is full.
+type WriteRejectionRecorder struct {
+	writer       PointsWriter
+	sampleEvery  uint64
+	maxLineBytes int
+	limiter      *rate.Limiter
+
+	n          uint64 // number of rejections seen, for sampling
+	rejections chan rejection
+
+	logger *zap.Logger
+}
+
+type rejection struct {
+	influxdb.WriteRejection
+	time time.Time
+}
+
+// NewWriteRejectionRecorder returns a new WriteRejectionRecorder that writes the
+// rejections sampled according to c with w. Run must be called for the rejections
+// to be written.
+func NewWriteRejectionRecorder(w PointsWriter, c WriteRejectionConfig, logger *zap.Logger) *WriteRejectionRecorder {
+	r := &WriteRejectionRecorder{
+		writer:       w,
+		sampleEvery:  uint64(c.SampleEvery),
+		maxLineBytes: c.MaxLineBytes,
+		rejections:   make(chan rejection, writeRejectionBufferSize),
+		logger:       logger.With(zap.String("service", "write_rejections")),
+	}
+	if c.MaxPerSecond > 0 {
+		r.limiter = rate.NewLimiter(rate.Limit(c.MaxPerSecond), c.MaxPerSecond)
+	}
+	return r
+}
+
+// RecordWriteRejection buffers the rejection to be written, if it is sampled.
+func (r *WriteRejectionRecorder) RecordWriteRejection(ctx context.Context, rej influxdb.WriteRejection) {
+	if r.sampleEvery == 0 || !rej.OrgID.Valid() {
+		return
+	}
+	if n := atomic.AddUint64(&r.n, 1); (n-1)%r.sampleEvery != 0 {
+		return
+	}
+	if r.limiter != nil && !r.limiter.Allow() {
+		return
+	}
+
+	// The line is usually part of a batch of the body, which must not be
+	// retained after the write.
+	rej.Line = truncateLine(rej.Line, r.maxLineBytes)
+
+	select {
+	case r.rejections <- rejection{WriteRejection: rej, time: time.Now()}:
+	default:
+		r.logger.Debug("Dropped write rejection, buffer is full")
+	}
+}
+
+// truncateLine returns a copy of at most max bytes of the line that doesn't
+// end in the middle of a rune.
+func truncateLine(line []byte, max int) []byte {
+	if len(line) > max {
+		line = line[:max]
+		for len(line) > 0 && !utf8.Valid(line) {
+			line = line[:len(line)-1]
+		}
+	}
+	return append([]byte(nil), line...)
+}
+
+// Run writes the buffered rejections every interval until ctx is done.
+func (r *WriteRejectionRecorder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.flush()
+		}
+	}
+}
+
+// flush writes the rejections buffered so far.
+func (r *WriteRejectionRecorder) flush() {
+	byOrg := make(map[influxdb.ID]models.Points)
+	for {
+		select {
+		case rej := <-r.rejections:
+			p, err := rej.point()
+			if err != nil {
+				r.logger.Info("Unable to record write rejection", zap.Error(err))
+				continue
+			}
+			byOrg[rej.OrgID] = append(byOrg[rej.OrgID], p)
+			continue
+		default:
+		}
+		break
+	}
+
+	for orgID, points := range byOrg {
+		exploded, err := tsdb.ExplodePoints(orgID, MonitoringBucketID, points)
+		if err == nil {
+			err = r.writer.WritePoints(context.Background(), exploded)
+		}
+		if err != nil {
+			r.logger.Error("Unable to write write rejections", zap.String("org_id", orgID.String()), zap.Error(err))
+		}
+	}
+}
+
+// point returns the point that records the rejection.
+func (rej rejection) point() (models.Point, error) {
+	tags := map[string]string{reasonTag: rej.Reason}
+	if rej.BucketID.Valid() {
+		tags[bucketIDTag] = rej.BucketID.String()
+	}
+	if rej.TokenHash != "" {
+		tags[tokenHashTag] = rej.TokenHash
+	}
+
+	fields := models.Fields{messageField: rej.Message}
+	if len(rej.Line) > 0 {
+		fields[lineField] = string(rej.Line)
+	}
+	return models.NewPoint(writeRejectionsMeasurement, models.NewTags(tags), fields, rej.time)
+}
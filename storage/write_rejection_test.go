@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/tsdb"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWriteRejectionRecorder(t *testing.T) {
+	engine := &testCardinalityEngine{}
+	r := NewWriteRejectionRecorder(engine, WriteRejectionConfig{
+		SampleEvery:  2,
+		MaxPerSecond: 2,
+		MaxLineBytes: 8,
+	}, zaptest.NewLogger(t))
+
+	ctx := context.Background()
+	for i, rej := range []influxdb.WriteRejection{
+		{OrgID: 1, BucketID: 2, Reason: influxdb.WriteRejectionInvalidLine, TokenHash: "abc", Line: []byte("m,host=a v=1 1\n"), Message: "bad"},
+		{OrgID: 1, BucketID: 2, Reason: influxdb.WriteRejectionInvalidLine, TokenHash: "abc", Line: []byte("m v=2"), Message: "skipped by sampling"},
+		{OrgID: 3, Reason: influxdb.WriteRejectionNoBucket, TokenHash: "def", Message: "bucket not found"},
+		{OrgID: 1, BucketID: 2, Reason: influxdb.WriteRejectionInvalidLine, Message: "skipped by sampling"},
+		{OrgID: 1, BucketID: 2, Reason: influxdb.WriteRejectionInvalidLine, Message: "over the rate limit"},
+	} {
+		r.RecordWriteRejection(ctx, rej)
+		if i == 0 {
+			// Lines are copied, so the body of the write may be reused.
+			rej.Line[0] = 'x'
+		}
+	}
+	// Rejections of unknown organizations can't be recorded.
+	r.RecordWriteRejection(ctx, influxdb.WriteRejection{Reason: influxdb.WriteRejectionForbidden})
+	r.flush()
+
+	type rejection struct {
+		org, measurement, bucketID, reason, tokenHash, line, message string
+	}
+	var got []rejection
+	for _, p := range engine.written {
+		var org string
+		for _, id := range []influxdb.ID{1, 3} {
+			if string(p.Name()) == tsdb.EncodeNameString(id, MonitoringBucketID) {
+				org = id.String()
+			}
+		}
+
+		fields, err := p.Fields()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Points are written with a field each, so the fields of a rejection are merged back.
+		if n := len(got); n == 0 || got[n-1].org != org || got[n-1].tokenHash != string(p.Tags().Get([]byte(tokenHashTag))) {
+			got = append(got, rejection{
+				org:         org,
+				measurement: string(p.Tags().Get(models.MeasurementTagKeyBytes)),
+				bucketID:    string(p.Tags().Get([]byte(bucketIDTag))),
+				reason:      string(p.Tags().Get([]byte(reasonTag))),
+				tokenHash:   string(p.Tags().Get([]byte(tokenHashTag))),
+			})
+		}
+		r := &got[len(got)-1]
+		if line, ok := fields[lineField].(string); ok {
+			r.line = line
+		}
+		if message, ok := fields[messageField].(string); ok {
+			r.message = message
+		}
+	}
+	// Organizations are written in map order.
+	if len(got) == 2 && got[0].org != influxdb.ID(1).String() {
+		got[0], got[1] = got[1], got[0]
+	}
+
+	want := []rejection{
+		{org: "0000000000000001", measurement: writeRejectionsMeasurement, bucketID: "0000000000000002", reason: influxdb.WriteRejectionInvalidLine, tokenHash: "abc", line: "m,host=a", message: "bad"},
+		{org: "0000000000000003", measurement: writeRejectionsMeasurement, reason: influxdb.WriteRejectionNoBucket, tokenHash: "def", message: "bucket not found"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got rejections %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteRejectionRecorder_Disabled(t *testing.T) {
+	engine := &testCardinalityEngine{}
+	r := NewWriteRejectionRecorder(engine, WriteRejectionConfig{}, zaptest.NewLogger(t))
+	r.RecordWriteRejection(context.Background(), influxdb.WriteRejection{OrgID: 1, Reason: influxdb.WriteRejectionForbidden})
+	r.flush()
+
+	if len(engine.written) != 0 {
+		t.Errorf("expected no rejections to be recorded, got %d points", len(engine.written))
+	}
+}
+
+func Test_truncateLine(t *testing.T) {
+	for _, tt := range []struct {
+		line string
+		max  int
+		want string
+	}{
+		{line: "m v=1", max: 10, want: "m v=1"},
+		{line: "m v=1", max: 3, want: "m v"},
+		{line: `m v="héllo"`, max: 7, want: `m v="h`},
+		{line: `m v="héllo"`, max: 8, want: `m v="hé`},
+	} {
+		if got := string(truncateLine([]byte(tt.line), tt.max)); got != tt.want {
+			t.Errorf("truncateLine(%q, %d) = %q, want %q", tt.line, tt.max, got, tt.want)
+		}
+	}
+}
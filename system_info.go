@@ -0,0 +1,56 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// SystemInfoService reports information about the server, so that clients such
+// as dashboards and support tooling can branch on its capabilities.
+type SystemInfoService interface {
+	// SystemInfo returns the current information about the server.
+	SystemInfo(ctx context.Context) (*SystemInfo, error)
+}
+
+// SystemInfo is the build, storage engine, Go runtime and limits of a server.
+type SystemInfo struct {
+	Version   string            `json:"version"`
+	Commit    string            `json:"commit"`
+	BuildDate string            `json:"buildDate,omitempty"`
+	Storage   SystemStorageInfo `json:"storage"`
+	Runtime   SystemRuntimeInfo `json:"runtime"`
+	Limits    SystemLimits      `json:"limits"`
+}
+
+// SystemStorageInfo describes the storage engine of a server.
+type SystemStorageInfo struct {
+	// Engine is the format of the data files, such as tsm1.
+	Engine string `json:"engine"`
+	// Index is the format of the series index, such as tsi1.
+	Index string `json:"index"`
+	// LayoutVersion is the version of the layout of the storage directory.
+	LayoutVersion int  `json:"layoutVersion"`
+	WALEnabled    bool `json:"walEnabled"`
+}
+
+// SystemRuntimeInfo describes the Go runtime of a server.
+type SystemRuntimeInfo struct {
+	GoVersion  string    `json:"goVersion"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	NumCPU     int       `json:"numCPU"`
+	GOMAXPROCS int       `json:"gomaxprocs"`
+	Goroutines int       `json:"goroutines"`
+	StartedAt  time.Time `json:"startedAt"`
+	Uptime     string    `json:"uptime"`
+}
+
+// SystemLimits are the limits a server enforces on queries. The queries of an
+// organization, and batch queries, are not limited when their concurrency is zero.
+type SystemLimits struct {
+	QueryConcurrency      int   `json:"queryConcurrency"`
+	QueryQueueSize        int   `json:"queryQueueSize"`
+	QueryMemoryBytes      int64 `json:"queryMemoryBytes"`
+	OrgQueryConcurrency   int   `json:"orgQueryConcurrency"`
+	BatchQueryConcurrency int   `json:"batchQueryConcurrency"`
+}
@@ -35,9 +35,35 @@ type Task struct {
 	Every           string `json:"every,omitempty"`
 	Cron            string `json:"cron,omitempty"`
 	Offset          string `json:"offset,omitempty"`
+	At              string `json:"at,omitempty"`
 	LatestCompleted string `json:"latestCompleted,omitempty"`
 	CreatedAt       string `json:"createdAt,omitempty"`
 	UpdatedAt       string `json:"updatedAt,omitempty"`
+
+	// MemoryBytesQuota is the maximum number of bytes a single run of the task may allocate.
+	// Zero means the run is only bound by the query controller's per-query quota.
+	MemoryBytesQuota int64 `json:"memoryBytesQuota,omitempty"`
+	// MaxDuration is the maximum wall time a single run of the task may take, as a duration string.
+	// An empty MaxDuration means runs are not time bound.
+	MaxDuration string `json:"maxDuration,omitempty"`
+
+	// DeleteAfterSuccess deletes a task that runs once, rather than making it
+	// inactive, after its run succeeds.
+	DeleteAfterSuccess bool `json:"deleteAfterSuccess,omitempty"`
+
+	// Metadata is free-form key/value metadata of the task.
+	Metadata Metadata `json:"metadata,omitempty"`
+}
+
+// RunsOnce returns true if the task is scheduled for a single time, given by
+// its at option, rather than every interval or by cron.
+func (t *Task) RunsOnce() bool {
+	return t.At != ""
+}
+
+// AtTime returns the time a task that runs once is scheduled for.
+func (t *Task) AtTime() (time.Time, error) {
+	return time.Parse(time.RFC3339, t.At)
 }
 
 // EffectiveCron returns the effective cron string of the options.
@@ -55,6 +81,19 @@ func (t *Task) EffectiveCron() string {
 	return ""
 }
 
+// EffectiveMaxDuration returns the parsed MaxDuration of the task.
+// It returns zero if the task has no duration limit or the limit cannot be parsed.
+func (t *Task) EffectiveMaxDuration() time.Duration {
+	if t.MaxDuration == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(t.MaxDuration)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
 // Run is a record created when a run of a task is scheduled.
 type Run struct {
 	ID           ID     `json:"id,omitempty"`
@@ -64,6 +103,7 @@ type Run struct {
 	StartedAt    string `json:"startedAt,omitempty"`
 	FinishedAt   string `json:"finishedAt,omitempty"`
 	RequestedAt  string `json:"requestedAt,omitempty"`
+	Annotation   string `json:"annotation,omitempty"` // Why the run was forced or retried.
 	Log          []Log  `json:"log,omitempty"`
 }
 
@@ -125,11 +165,13 @@ type TaskService interface {
 	CancelRun(ctx context.Context, taskID, runID ID) error
 
 	// RetryRun creates and returns a new run (which is a retry of another run).
-	RetryRun(ctx context.Context, taskID, runID ID) (*Run, error)
+	// The annotation, if not empty, records why the run was retried.
+	RetryRun(ctx context.Context, taskID, runID ID, annotation string) (*Run, error)
 
 	// ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 	// The value of scheduledFor may or may not align with the task's schedule.
-	ForceRun(ctx context.Context, taskID ID, scheduledFor int64) (*Run, error)
+	// The annotation, if not empty, records why the run was forced.
+	ForceRun(ctx context.Context, taskID ID, scheduledFor int64, annotation string) (*Run, error)
 }
 
 // TaskCreate is the set of values to create a task.
@@ -140,6 +182,15 @@ type TaskCreate struct {
 	OrganizationID ID     `json:"orgID,omitempty"`
 	Organization   string `json:"org,omitempty"`
 	Token          string `json:"token,omitempty"`
+
+	// MemoryBytesQuota limits the memory each run of the task may allocate in the query engine.
+	MemoryBytesQuota int64 `json:"memoryBytesQuota,omitempty"`
+	// MaxDuration limits how long each run of the task may execute, i.e.: "30s".
+	MaxDuration string `json:"maxDuration,omitempty"`
+	// DeleteAfterSuccess deletes a task that runs once after its run succeeds.
+	DeleteAfterSuccess bool `json:"deleteAfterSuccess,omitempty"`
+
+	Metadata Metadata `json:"metadata,omitempty"`
 }
 
 func (t TaskCreate) Validate() error {
@@ -150,6 +201,20 @@ func (t TaskCreate) Validate() error {
 		return errors.New("missing orgID and org")
 	case t.Status != "" && t.Status != TaskStatusActive && t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", t.Status)
+	case t.MemoryBytesQuota < 0:
+		return fmt.Errorf("invalid memory bytes quota: %d", t.MemoryBytesQuota)
+	}
+	if t.MaxDuration != "" {
+		d, err := time.ParseDuration(t.MaxDuration)
+		if err != nil {
+			return fmt.Errorf("invalid max duration: %v", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid max duration: %q must be positive", t.MaxDuration)
+		}
+	}
+	if err := t.Metadata.Valid(); err != nil {
+		return err
 	}
 	return nil
 }
@@ -168,6 +233,12 @@ type TaskUpdate struct {
 
 	// Optional token override.
 	Token string `json:"token,omitempty"`
+
+	// Metadata sets or, when null, removes keys of the metadata of the task.
+	Metadata MetadataUpdate `json:"metadata,omitempty"`
+
+	// DeleteAfterSuccess sets whether a task that runs once is deleted after its run succeeds.
+	DeleteAfterSuccess *bool `json:"deleteAfterSuccess,omitempty"`
 }
 
 func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
@@ -193,7 +264,14 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 
 		Retry *int64 `json:"retry,omitempty"`
 
+		// At is the single time a task that runs once is scheduled for.
+		At *time.Time `json:"at,omitempty"`
+
 		Token string `json:"token,omitempty"`
+
+		Metadata MetadataUpdate `json:"metadata,omitempty"`
+
+		DeleteAfterSuccess *bool `json:"deleteAfterSuccess,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, &jo); err != nil {
@@ -207,11 +285,17 @@ func (t *TaskUpdate) UnmarshalJSON(data []byte) error {
 		offset := *jo.Offset
 		t.Options.Offset = &offset
 	}
+	if jo.At != nil {
+		at := jo.At.UTC()
+		t.Options.At = &at
+	}
 	t.Options.Concurrency = jo.Concurrency
 	t.Options.Retry = jo.Retry
 	t.Flux = jo.Flux
 	t.Status = jo.Status
 	t.Token = jo.Token
+	t.Metadata = jo.Metadata
+	t.DeleteAfterSuccess = jo.DeleteAfterSuccess
 
 	return nil
 }
@@ -236,7 +320,14 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 
 		Retry *int64 `json:"retry,omitempty"`
 
+		// At is the single time a task that runs once is scheduled for.
+		At *time.Time `json:"at,omitempty"`
+
 		Token string `json:"token,omitempty"`
+
+		Metadata MetadataUpdate `json:"metadata,omitempty"`
+
+		DeleteAfterSuccess *bool `json:"deleteAfterSuccess,omitempty"`
 	}{}
 	jo.Name = t.Options.Name
 	jo.Cron = t.Options.Cron
@@ -246,11 +337,14 @@ func (t TaskUpdate) MarshalJSON() ([]byte, error) {
 		offset := *t.Options.Offset
 		jo.Offset = &offset
 	}
+	jo.At = t.Options.At
 	jo.Concurrency = t.Options.Concurrency
 	jo.Retry = t.Options.Retry
 	jo.Flux = t.Flux
 	jo.Status = t.Status
 	jo.Token = t.Token
+	jo.Metadata = t.Metadata
+	jo.DeleteAfterSuccess = t.DeleteAfterSuccess
 	return json.Marshal(jo)
 }
 
@@ -258,7 +352,9 @@ func (t TaskUpdate) Validate() error {
 	switch {
 	case !t.Options.Every.IsZero() && t.Options.Cron != "":
 		return errors.New("cannot specify both every and cron")
-	case t.Flux == nil && t.Status == nil && t.Options.IsZero() && t.Token == "":
+	case t.Options.At != nil && (!t.Options.Every.IsZero() || t.Options.Cron != ""):
+		return errors.New("cannot specify at with every or cron")
+	case t.Flux == nil && t.Status == nil && t.Options.IsZero() && t.Token == "" && t.Metadata == nil && t.DeleteAfterSuccess == nil:
 		return errors.New("cannot update task without content")
 	case t.Status != nil && *t.Status != TaskStatusActive && *t.Status != TaskStatusInactive:
 		return fmt.Errorf("invalid task status: %q", *t.Status)
@@ -281,6 +377,9 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) error {
 	if !t.Options.Every.IsZero() && t.Options.Cron != "" {
 		return errors.New("cannot specify both cron and every")
 	}
+	if t.Options.At != nil && (!t.Options.Every.IsZero() || t.Options.Cron != "") {
+		return errors.New("cannot specify at with every or cron")
+	}
 	op := make(map[string]ast.Expression, 4)
 
 	if t.Options.Name != "" {
@@ -292,6 +391,9 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) error {
 	if t.Options.Cron != "" {
 		op["cron"] = &ast.StringLiteral{Value: t.Options.Cron}
 	}
+	if t.Options.At != nil {
+		op["at"] = &ast.DateTimeLiteral{Value: *t.Options.At}
+	}
 	if t.Options.Offset != nil {
 		if !t.Options.Offset.IsZero() {
 			op["offset"] = &t.Options.Offset.Node
@@ -334,6 +436,10 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) error {
 						delete(op, "cron")
 						p.Value = cron
 						p.Key = &ast.Identifier{Name: "cron"}
+					} else if at, ok := op["at"]; ok && t.Options.At != nil {
+						delete(op, "at")
+						p.Key = &ast.Identifier{Name: "at"}
+						p.Value = at
 					}
 				case "cron":
 					if cron, ok := op["cron"]; ok && t.Options.Cron != "" {
@@ -343,6 +449,23 @@ func (t *TaskUpdate) UpdateFlux(oldFlux string) error {
 						delete(op, "every")
 						p.Key = &ast.Identifier{Name: "every"}
 						p.Value = every.Copy().(*ast.DurationLiteral)
+					} else if at, ok := op["at"]; ok && t.Options.At != nil {
+						delete(op, "at")
+						p.Key = &ast.Identifier{Name: "at"}
+						p.Value = at
+					}
+				case "at":
+					if at, ok := op["at"]; ok && t.Options.At != nil {
+						delete(op, "at")
+						p.Value = at
+					} else if every, ok := op["every"]; ok && !t.Options.Every.IsZero() {
+						delete(op, "every")
+						p.Key = &ast.Identifier{Name: "every"}
+						p.Value = every.Copy().(*ast.DurationLiteral)
+					} else if cron, ok := op["cron"]; ok && t.Options.Cron != "" {
+						delete(op, "cron")
+						p.Key = &ast.Identifier{Name: "cron"}
+						p.Value = cron
 					}
 				}
 			}
@@ -379,6 +502,7 @@ type TaskFilter struct {
 	Organization   string
 	User           *ID
 	Limit          int
+	Metadata       Metadata
 }
 
 // QueryParams Converts TaskFilter fields to url query params.
@@ -404,6 +528,10 @@ func (f TaskFilter) QueryParams() map[string][]string {
 		qp["limit"] = []string{strconv.Itoa(f.Limit)}
 	}
 
+	if len(f.Metadata) > 0 {
+		qp["metadata"] = f.Metadata.QueryParams()
+	}
+
 	return qp
 }
 
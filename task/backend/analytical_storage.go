@@ -25,6 +25,7 @@ const (
 	startedAtField    = "startedAt"
 	finishedAtField   = "finishedAt"
 	requestedAtField  = "requestedAt"
+	annotationField   = "annotation"
 	statusField       = "status"
 	logField          = "logs"
 
@@ -45,26 +46,28 @@ func NewAnalyticalStorage(logger *zap.Logger, ts influxdb.TaskService, tcs TaskC
 	}
 }
 
+var _ influxdb.TaskRunImporter = (*AnalyticalStorage)(nil)
+
 type AnalyticalStorage struct {
 	influxdb.TaskService
 	TaskControlService
 
+	// UsageRecorder, if set, counts the finished runs of the organizations of tasks.
+	UsageRecorder influxdb.UsageRecorder
+
 	pw     storage.PointsWriter
 	qs     query.QueryService
 	logger *zap.Logger
 }
 
 func (as *AnalyticalStorage) FinishRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+	// The task is found before its run is finished, as finishing the run of a
+	// task that runs once may delete the task.
+	task, findErr := as.TaskService.FindTaskByID(ctx, taskID)
 	run, err := as.TaskControlService.FinishRun(ctx, taskID, runID)
 	if run != nil && run.ID.String() != "" {
-		task, err := as.TaskService.FindTaskByID(ctx, run.TaskID)
-		if err != nil {
-			return run, err
-		}
-
-		tags := models.Tags{
-			models.NewTag([]byte(taskIDTag), []byte(run.TaskID.String())),
-			models.NewTag([]byte(statusField), []byte(run.Status)),
+		if findErr != nil {
+			return run, findErr
 		}
 
 		// log an error if we have incomplete data on finish
@@ -76,28 +79,11 @@ func (as *AnalyticalStorage) FinishRun(ctx context.Context, taskID, runID influx
 			as.logger.Error("Run missing critical fields", zap.String("run", fmt.Sprintf("%+v", run)), zap.String("runID", run.ID.String()))
 		}
 
-		fields := map[string]interface{}{}
-		fields[statusField] = run.Status
-		fields[runIDField] = run.ID.String()
-		fields[startedAtField] = run.StartedAt
-		fields[finishedAtField] = run.FinishedAt
-		fields[scheduledForField] = run.ScheduledFor
-		if run.RequestedAt != "" {
-			fields[requestedAtField] = run.RequestedAt
-		}
-
-		startedAt, err := run.StartedAtTime()
-		if err != nil {
-			startedAt = time.Now()
-		}
-
-		logBytes, err := json.Marshal(run.Log)
-		if err != nil {
-			return run, err
+		if as.UsageRecorder != nil {
+			as.UsageRecorder.RecordUsage(ctx, task.OrganizationID, influxdb.UsageTaskRunCount, 1)
 		}
-		fields[logField] = string(logBytes)
 
-		point, err := models.NewPoint("runs", tags, fields, startedAt)
+		point, err := runPoint(run)
 		if err != nil {
 			return run, err
 		}
@@ -113,6 +99,76 @@ func (as *AnalyticalStorage) FinishRun(ctx context.Context, taskID, runID influx
 	return run, err
 }
 
+// ImportRuns records finished runs that were executed elsewhere as runs of taskID.
+// The runs keep their IDs, schedule and logs; runs that have not finished are ignored.
+func (as *AnalyticalStorage) ImportRuns(ctx context.Context, taskID influxdb.ID, runs []*influxdb.Run) (int, error) {
+	task, err := as.TaskService.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return 0, err
+	}
+
+	var points models.Points
+	for _, r := range runs {
+		if r.FinishedAt == "" || !r.ID.Valid() {
+			continue
+		}
+
+		run := *r
+		run.TaskID = taskID
+		point, err := runPoint(&run)
+		if err != nil {
+			return 0, err
+		}
+		points = append(points, point)
+	}
+	if len(points) == 0 {
+		return 0, nil
+	}
+
+	exploded, err := tsdb.ExplodePoints(task.OrganizationID, taskSystemBucketID, points)
+	if err != nil {
+		return 0, err
+	}
+	if err := as.pw.WritePoints(ctx, exploded); err != nil {
+		return 0, err
+	}
+	return len(points), nil
+}
+
+// runPoint returns the point that records a finished run in the system bucket.
+func runPoint(run *influxdb.Run) (models.Point, error) {
+	tags := models.Tags{
+		models.NewTag([]byte(taskIDTag), []byte(run.TaskID.String())),
+		models.NewTag([]byte(statusField), []byte(run.Status)),
+	}
+
+	fields := map[string]interface{}{}
+	fields[statusField] = run.Status
+	fields[runIDField] = run.ID.String()
+	fields[startedAtField] = run.StartedAt
+	fields[finishedAtField] = run.FinishedAt
+	fields[scheduledForField] = run.ScheduledFor
+	if run.RequestedAt != "" {
+		fields[requestedAtField] = run.RequestedAt
+	}
+	if run.Annotation != "" {
+		fields[annotationField] = run.Annotation
+	}
+
+	startedAt, err := run.StartedAtTime()
+	if err != nil {
+		startedAt = time.Now()
+	}
+
+	logBytes, err := json.Marshal(run.Log)
+	if err != nil {
+		return nil, err
+	}
+	fields[logField] = string(logBytes)
+
+	return models.NewPoint("runs", tags, fields, startedAt)
+}
+
 // FindLogs returns logs for a run.
 // First attempt to use the TaskService, then append additional analytical's logs to the list
 func (as *AnalyticalStorage) FindLogs(ctx context.Context, filter influxdb.LogFilter) ([]*influxdb.Log, int, error) {
@@ -278,8 +334,8 @@ func (as *AnalyticalStorage) FindRunByID(ctx context.Context, taskID, runID infl
 	return re.runs[0], err
 }
 
-func (as *AnalyticalStorage) RetryRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
-	run, err := as.TaskService.RetryRun(ctx, taskID, runID)
+func (as *AnalyticalStorage) RetryRun(ctx context.Context, taskID, runID influxdb.ID, annotation string) (*influxdb.Run, error) {
+	run, err := as.TaskService.RetryRun(ctx, taskID, runID, annotation)
 	if err != nil {
 		if err, ok := err.(*influxdb.Error); !ok || err.Msg != "run not found" {
 			return run, err
@@ -301,7 +357,7 @@ func (as *AnalyticalStorage) RetryRun(ctx context.Context, taskID, runID influxd
 		return run, err
 	}
 
-	return as.ForceRun(ctx, taskID, sf.Unix())
+	return as.ForceRun(ctx, taskID, sf.Unix(), annotation)
 }
 
 type runReader struct {
@@ -340,6 +396,8 @@ func (re *runReader) readRuns(cr flux.ColReader) error {
 				r.StartedAt = cr.Strings(j).ValueString(i)
 			case requestedAtField:
 				r.RequestedAt = cr.Strings(j).ValueString(i)
+			case annotationField:
+				r.Annotation = cr.Strings(j).ValueString(i)
 			case scheduledForField:
 				r.ScheduledFor = cr.Strings(j).ValueString(i)
 			case statusField:
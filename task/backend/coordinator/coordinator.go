@@ -166,13 +166,13 @@ func (c *Coordinator) CancelRun(ctx context.Context, taskID, runID platform.ID)
 	return c.TaskService.CancelRun(ctx, taskID, runID)
 }
 
-func (c *Coordinator) RetryRun(ctx context.Context, taskID, runID platform.ID) (*platform.Run, error) {
+func (c *Coordinator) RetryRun(ctx context.Context, taskID, runID platform.ID, annotation string) (*platform.Run, error) {
 	task, err := c.TaskService.FindTaskByID(ctx, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := c.TaskService.RetryRun(ctx, taskID, runID)
+	r, err := c.TaskService.RetryRun(ctx, taskID, runID, annotation)
 	if err != nil {
 		return r, err
 	}
@@ -180,13 +180,13 @@ func (c *Coordinator) RetryRun(ctx context.Context, taskID, runID platform.ID) (
 	return r, c.sch.UpdateTask(ctx, task)
 }
 
-func (c *Coordinator) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*platform.Run, error) {
+func (c *Coordinator) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64, annotation string) (*platform.Run, error) {
 	task, err := c.TaskService.FindTaskByID(ctx, taskID)
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := c.TaskService.ForceRun(ctx, taskID, scheduledFor)
+	r, err := c.TaskService.ForceRun(ctx, taskID, scheduledFor, annotation)
 	if err != nil {
 		return r, err
 	}
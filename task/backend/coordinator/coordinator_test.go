@@ -95,7 +95,7 @@ func inmemTaskService() platform.TaskService {
 			}
 			return rtn, len(rtn), nil
 		},
-		ForceRunFn: func(ctx context.Context, id platform.ID, scheduledFor int64) (*platform.Run, error) {
+		ForceRunFn: func(ctx context.Context, id platform.ID, scheduledFor int64, annotation string) (*platform.Run, error) {
 			mu.Lock()
 			defer mu.Unlock()
 			t, ok := tasks[id]
@@ -374,7 +374,7 @@ func TestCoordinator_ForceRun(t *testing.T) {
 
 	ch := sched.TaskUpdateChan()
 	manualRunTime := time.Now().Unix()
-	if _, err := coord.ForceRun(context.Background(), task.ID, manualRunTime); err != nil {
+	if _, err := coord.ForceRun(context.Background(), task.ID, manualRunTime, ""); err != nil {
 		t.Fatal(err)
 	}
 
@@ -0,0 +1,41 @@
+package coordinator
+
+import (
+	"context"
+
+	platform "github.com/influxdata/influxdb"
+)
+
+var _ platform.RevisionService = (*RevisionService)(nil)
+
+// RevisionService wraps a platform.RevisionService, and restores the revisions
+// of tasks through the coordinator, so that the scheduler runs the restored Flux.
+type RevisionService struct {
+	platform.RevisionService
+
+	c *Coordinator
+}
+
+// NewRevisionService returns a RevisionService that restores tasks through c.
+func NewRevisionService(c *Coordinator, s platform.RevisionService) *RevisionService {
+	return &RevisionService{
+		RevisionService: s,
+		c:               c,
+	}
+}
+
+// RestoreRevision puts a resource back as it was at a revision.
+func (s *RevisionService) RestoreRevision(ctx context.Context, typ platform.ResourceType, id platform.ID, version int) error {
+	if typ != platform.TasksResourceType {
+		return s.RevisionService.RestoreRevision(ctx, typ, id, version)
+	}
+
+	r, err := s.RevisionService.FindRevision(ctx, typ, id, version)
+	if err != nil {
+		return err
+	}
+
+	// Updating the Flux of the task records the restore as its latest revision.
+	_, err = s.c.UpdateTask(ctx, id, platform.TaskUpdate{Flux: &r.Content})
+	return err
+}
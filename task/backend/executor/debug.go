@@ -0,0 +1,418 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/kit/tracing"
+	"github.com/influxdata/influxdb/query"
+	"github.com/opentracing/opentracing-go/ext"
+	"go.uber.org/zap"
+)
+
+// SampleResultPrefix prefixes the names of the results a debug run yields
+// after the stages of the pipelines of a task.
+const SampleResultPrefix = "_sample "
+
+// maxSampleTables is the most tables of a result that a debug run samples.
+const maxSampleTables = 10
+
+// debugService is an implementation of influxdb.TaskDebugService that depends on a QueryService.
+type debugService struct {
+	qs     query.QueryService
+	as     influxdb.AuthorizationService
+	ts     influxdb.TaskService
+	logger *zap.Logger
+}
+
+var _ influxdb.TaskDebugService = (*debugService)(nil)
+
+// NewDebugService returns a TaskDebugService that runs tasks with the given QueryService.
+// It does not check that the caller may debug the task; wrap it with the authorizer to do so.
+func NewDebugService(logger *zap.Logger, qs query.QueryService, as influxdb.AuthorizationService, ts influxdb.TaskService) influxdb.TaskDebugService {
+	return &debugService{logger: logger, qs: qs, as: as, ts: ts}
+}
+
+// DebugRun runs the task once with its authorization, rewritten so that it writes
+// nothing but the scratch bucket of the request and yields a sample after every stage
+// of its pipelines. The trace of the run is always sampled.
+func (s *debugService) DebugRun(ctx context.Context, taskID influxdb.ID, req influxdb.TaskDebugRequest) (*influxdb.TaskDebugRun, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	t, err := s.ts.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := s.as.FindAuthorizationByID(ctx, t.AuthorizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.IsActive() {
+		return nil, &influxdb.Error{
+			Code: influxdb.EForbidden,
+			Msg:  "the authorization of the task is inactive",
+		}
+	}
+
+	pkg, err := flux.Parse(t.Flux)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to parse the script of the task",
+			Err:  err,
+		}
+	}
+	samples := rewriteForDebug(pkg, req.ScratchBucket, t.OrganizationID)
+
+	if req.ScheduledFor.IsZero() {
+		req.ScheduledFor = time.Now()
+	}
+	run := &influxdb.TaskDebugRun{
+		TaskID:       t.ID,
+		ScheduledFor: req.ScheduledFor.UTC(),
+		Script:       ast.Format(pkg.Files[0]),
+	}
+
+	// The authorization of the task is only used until the timeout.
+	ctx, cancel := context.WithTimeout(ctx, req.Timeout)
+	defer cancel()
+
+	span, ctx := tracing.StartSpanFromContext(ctx)
+	defer span.Finish()
+	ext.SamplingPriority.Set(span, 1)
+	run.TraceID = tracing.TraceID(span)
+
+	fields := []zap.Field{
+		zap.Stringer("task_id", t.ID),
+		zap.Stringer("authorization_id", auth.ID),
+		zap.String("trace_id", run.TraceID),
+	}
+	if a, err := icontext.GetAuthorizer(ctx); err == nil {
+		fields = append(fields, zap.Stringer("debugged_by", a.Identifier()))
+	}
+	s.logger.Info("Debugging task with its authorization", fields...)
+
+	run.StartedAt = time.Now().UTC()
+	defer func() {
+		run.FinishedAt = time.Now().UTC()
+	}()
+
+	it, err := s.qs.Query(icontext.SetAuthorizer(ctx, auth), &query.Request{
+		Authorization:  auth,
+		OrganizationID: t.OrganizationID,
+		Compiler: lang.ASTCompiler{
+			AST: pkg,
+			Now: req.ScheduledFor,
+		},
+		MemoryBytesQuota: t.MemoryBytesQuota,
+		MaxDuration:      req.Timeout,
+	})
+	if err != nil {
+		run.Error = err.Error()
+		return run, nil
+	}
+	defer it.Release()
+
+	run.Samples, err = sampleResults(it, req.SampleRows)
+	if err != nil {
+		run.Error = err.Error()
+	}
+	sortSamples(run.Samples, samples)
+	return run, nil
+}
+
+// rewriteForDebug rewrites the script of a task for a debug run. Calls to to()
+// are dropped, or write to the scratch bucket in the organization of the task if it is set.
+// The top-level pipelines of the script yield their tables after every stage but
+// their last. It returns the names of the results the stages are yielded as.
+func rewriteForDebug(pkg *ast.Package, scratchBucket string, orgID influxdb.ID) []string {
+	if scratchBucket == "" {
+		ast.Walk(&dropWritesVisitor{}, pkg)
+	} else {
+		ast.Visit(pkg, func(n ast.Node) {
+			if call, ok := n.(*ast.CallExpression); ok && isWrite(call) {
+				redirectWrite(call, scratchBucket, orgID)
+			}
+		})
+	}
+
+	var names []string
+	for _, f := range pkg.Files {
+		for i, stmt := range f.Body {
+			if es, ok := stmt.(*ast.ExpressionStatement); ok {
+				if p, ok := es.Expression.(*ast.PipeExpression); ok {
+					es.Expression = yieldStages(p, &names, i+1)
+				}
+			}
+		}
+	}
+	return names
+}
+
+// yieldStages yields the tables of every stage of the pipeline but its last,
+// which is yielded anyway. The source of the pipeline is not a stage, as a source
+// such as from() can only be read together with the range() that follows it.
+func yieldStages(p *ast.PipeExpression, names *[]string, stmt int) ast.Expression {
+	var stages []*ast.PipeExpression
+	for e := ast.Expression(p); ; {
+		pe, ok := e.(*ast.PipeExpression)
+		if !ok {
+			break
+		}
+		stages = append(stages, pe)
+		e = pe.Argument
+	}
+
+	// The stages were collected from the last to the first, which is numbered 1.
+	for i := len(stages) - 1; i > 0; i-- {
+		stage := stages[i]
+		name := calleeName(stage.Call)
+		if name == "yield" {
+			continue
+		}
+		n := fmt.Sprintf("%s%d.%d %s", SampleResultPrefix, stmt, len(stages)-i, name)
+		*names = append(*names, n)
+		stages[i-1].Argument = &ast.PipeExpression{
+			Argument: stage,
+			Call: &ast.CallExpression{
+				Callee: &ast.Identifier{Name: "yield"},
+				Arguments: []ast.Expression{&ast.ObjectExpression{
+					Properties: []*ast.Property{{
+						Key:   &ast.Identifier{Name: "name"},
+						Value: &ast.StringLiteral{Value: n},
+					}},
+				}},
+			},
+		}
+	}
+	return p
+}
+
+// dropWritesVisitor replaces the calls to to() with the tables they write,
+// which are the tables to() returns.
+type dropWritesVisitor struct{}
+
+func (v *dropWritesVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.ExpressionStatement:
+		n.Expression = dropWrite(n.Expression)
+	case *ast.VariableAssignment:
+		n.Init = dropWrite(n.Init)
+	case *ast.ReturnStatement:
+		n.Argument = dropWrite(n.Argument)
+	case *ast.Property:
+		n.Value = dropWrite(n.Value)
+	case *ast.PipeExpression:
+		n.Argument = dropWrite(n.Argument)
+	case *ast.FunctionExpression:
+		if e, ok := n.Body.(ast.Expression); ok {
+			n.Body = dropWrite(e)
+		}
+	case *ast.ArrayExpression:
+		for i, e := range n.Elements {
+			n.Elements[i] = dropWrite(e)
+		}
+	}
+	return v
+}
+
+func (v *dropWritesVisitor) Done(node ast.Node) {}
+
+// dropWrite returns the tables written by the expression if it is a call to to().
+func dropWrite(e ast.Expression) ast.Expression {
+	switch n := e.(type) {
+	case *ast.PipeExpression:
+		if isWrite(n.Call) {
+			return dropWrite(n.Argument)
+		}
+	case *ast.CallExpression:
+		if isWrite(n) {
+			if tables := callArgument(n, "tables"); tables != nil {
+				return dropWrite(tables)
+			}
+		}
+	}
+	return e
+}
+
+// redirectWrite makes a call to to() write to the bucket in the organization.
+func redirectWrite(call *ast.CallExpression, bucket string, orgID influxdb.ID) {
+	props := []*ast.Property{
+		{Key: &ast.Identifier{Name: "bucket"}, Value: &ast.StringLiteral{Value: bucket}},
+		{Key: &ast.Identifier{Name: "orgID"}, Value: &ast.StringLiteral{Value: orgID.String()}},
+	}
+	if len(call.Arguments) == 1 {
+		if obj, ok := call.Arguments[0].(*ast.ObjectExpression); ok {
+			for _, p := range obj.Properties {
+				switch p.Key.Key() {
+				case "bucket", "bucketID", "org", "orgID", "host", "token":
+				default:
+					props = append(props, p)
+				}
+			}
+		}
+	}
+	call.Arguments = []ast.Expression{&ast.ObjectExpression{Properties: props}}
+}
+
+// isWrite returns true if the call is a call to to(), or to a to() of a package such as influxdb.to().
+func isWrite(call *ast.CallExpression) bool {
+	switch callee := call.Callee.(type) {
+	case *ast.Identifier:
+		return callee.Name == "to"
+	case *ast.MemberExpression:
+		return callee.Property.Key() == "to"
+	}
+	return false
+}
+
+// calleeName returns the name of the function called.
+func calleeName(call *ast.CallExpression) string {
+	switch callee := call.Callee.(type) {
+	case *ast.Identifier:
+		return callee.Name
+	case *ast.MemberExpression:
+		if obj, ok := callee.Object.(*ast.Identifier); ok {
+			return obj.Name + "." + callee.Property.Key()
+		}
+		return callee.Property.Key()
+	}
+	return "call"
+}
+
+// callArgument returns the argument of the call with the name, or nil.
+func callArgument(call *ast.CallExpression, name string) ast.Expression {
+	if len(call.Arguments) != 1 {
+		return nil
+	}
+	obj, ok := call.Arguments[0].(*ast.ObjectExpression)
+	if !ok {
+		return nil
+	}
+	for _, p := range obj.Properties {
+		if p.Key.Key() == name {
+			return p.Value
+		}
+	}
+	return nil
+}
+
+// sampleResults samples the first rows of the tables of every result.
+// It returns the samples taken before an error.
+func sampleResults(it flux.ResultIterator, rows int) ([]*influxdb.TaskDebugSample, error) {
+	samples := []*influxdb.TaskDebugSample{}
+	for it.More() {
+		res := it.Next()
+		sample := &influxdb.TaskDebugSample{
+			Name:   res.Name(),
+			Tables: []*influxdb.TaskDebugTable{},
+		}
+		samples = append(samples, sample)
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			if len(sample.Tables) >= maxSampleTables {
+				tbl.Done()
+				return nil
+			}
+			st, err := sampleTable(tbl, rows)
+			if err != nil {
+				return err
+			}
+			sample.Tables = append(sample.Tables, st)
+			return nil
+		}); err != nil {
+			return samples, err
+		}
+	}
+	it.Release()
+	return samples, it.Err()
+}
+
+func sampleTable(tbl flux.Table, rows int) (*influxdb.TaskDebugTable, error) {
+	key := tbl.Key()
+	st := &influxdb.TaskDebugTable{
+		Group: make(map[string]interface{}, len(key.Cols())),
+		Rows:  [][]interface{}{},
+	}
+	for j, c := range key.Cols() {
+		st.Group[c.Label] = sampleValue(key.Value(j))
+	}
+	for _, c := range tbl.Cols() {
+		st.Columns = append(st.Columns, c.Label)
+	}
+
+	// Every row is read, as the table must be consumed, but only the first are kept.
+	err := tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			if len(st.Rows) == rows {
+				st.Truncated = true
+				return nil
+			}
+			row := make([]interface{}, len(cr.Cols()))
+			for j := range cr.Cols() {
+				row[j] = sampleValue(execute.ValueForRow(cr, i, j))
+			}
+			st.Rows = append(st.Rows, row)
+		}
+		return nil
+	})
+	return st, err
+}
+
+// sampleValue returns a value that encodes as JSON, or nil for values
+// that do not, such as the floats NaN and infinity.
+func sampleValue(v values.Value) interface{} {
+	if v.IsNull() {
+		return nil
+	}
+	switch v.Type() {
+	case semantic.String:
+		return v.Str()
+	case semantic.Int:
+		return v.Int()
+	case semantic.UInt:
+		return v.UInt()
+	case semantic.Float:
+		if f := v.Float(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return f
+		}
+	case semantic.Bool:
+		return v.Bool()
+	case semantic.Time:
+		return v.Time().Time().UTC()
+	}
+	return nil
+}
+
+// sortSamples orders the samples of the stages as the stages are in the script,
+// followed by the other results by name.
+func sortSamples(samples []*influxdb.TaskDebugSample, stages []string) {
+	index := make(map[string]int, len(stages))
+	for i, name := range stages {
+		index[name] = i
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		si, iok := index[samples[i].Name]
+		sj, jok := index[samples[j].Name]
+		switch {
+		case iok && jok:
+			return si < sj
+		case iok || jok:
+			return iok
+		}
+		return samples[i].Name < samples[j].Name
+	})
+}
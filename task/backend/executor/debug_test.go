@@ -0,0 +1,175 @@
+package executor
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	platform "github.com/influxdata/influxdb"
+)
+
+const debugScript = `option task = {name: "t", every: 1h}
+
+data = from(bucket: "a")
+	|> range(start: -1h)
+	|> to(bucket: "c", org: "o")
+
+data
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu"))
+	|> aggregateWindow(every: 1m, fn: mean)
+	|> to(bucket: "b", org: "o")`
+
+func TestRewriteForDebug(t *testing.T) {
+	t.Run("drops writes", func(t *testing.T) {
+		pkg, err := flux.Parse(debugScript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := rewriteForDebug(pkg, "", 1)
+
+		want := `option task = {name: "t", every: 1h}
+
+data = from(bucket: "a")
+	|> range(start: -1h)
+
+data
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu"))
+	|> yield(name: "_sample 3.1 filter")
+	|> aggregateWindow(every: 1m, fn: mean)`
+		if got := ast.Format(pkg.Files[0]); got != want {
+			t.Errorf("unexpected script -want/+got:\n%s", cmp.Diff(want, got))
+		}
+		if want := []string{"_sample 3.1 filter"}; !cmp.Equal(want, names) {
+			t.Errorf("unexpected sample names -want/+got:\n%s", cmp.Diff(want, names))
+		}
+	})
+
+	t.Run("writes to the scratch bucket", func(t *testing.T) {
+		pkg, err := flux.Parse(debugScript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := rewriteForDebug(pkg, "scratch", 1)
+
+		want := `option task = {name: "t", every: 1h}
+
+data = from(bucket: "a")
+	|> range(start: -1h)
+	|> to(bucket: "scratch", orgID: "0000000000000001")
+
+data
+	|> filter(fn: (r) =>
+		(r._measurement == "cpu"))
+	|> yield(name: "_sample 3.1 filter")
+	|> aggregateWindow(every: 1m, fn: mean)
+	|> yield(name: "_sample 3.2 aggregateWindow")
+	|> to(bucket: "scratch", orgID: "0000000000000001")`
+		if got := ast.Format(pkg.Files[0]); got != want {
+			t.Errorf("unexpected script -want/+got:\n%s", cmp.Diff(want, got))
+		}
+		if want := []string{"_sample 3.1 filter", "_sample 3.2 aggregateWindow"}; !cmp.Equal(want, names) {
+			t.Errorf("unexpected sample names -want/+got:\n%s", cmp.Diff(want, names))
+		}
+	})
+
+	t.Run("drops writes that are not piped", func(t *testing.T) {
+		pkg, err := flux.Parse(`to(tables: from(bucket: "a") |> range(start: -1h), bucket: "b", org: "o")`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rewriteForDebug(pkg, "", 1)
+
+		want := `from(bucket: "a")
+	|> range(start: -1h)`
+		if got := ast.Format(pkg.Files[0]); got != want {
+			t.Errorf("unexpected script -want/+got:\n%s", cmp.Diff(want, got))
+		}
+	})
+}
+
+func TestSampleResults(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{
+		&executetest.Result{
+			Nm: "_result",
+			Tbls: []*executetest.Table{{
+				KeyCols: []string{"host"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "host", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), "a", 1.5},
+					{execute.Time(1e9), "a", math.NaN()},
+					{execute.Time(2e9), "a", 2.5},
+				},
+			}},
+		},
+		&executetest.Result{
+			Nm: "_sample 1.1 filter",
+			Tbls: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TInt}},
+				Data:    [][]interface{}{{int64(1)}, {nil}},
+			}},
+		},
+	})
+
+	samples, err := sampleResults(results, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortSamples(samples, []string{"_sample 1.1 filter"})
+
+	want := []*platform.TaskDebugSample{
+		{
+			Name: "_sample 1.1 filter",
+			Tables: []*platform.TaskDebugTable{{
+				Group:   map[string]interface{}{},
+				Columns: []string{"_value"},
+				Rows:    [][]interface{}{{int64(1)}, {nil}},
+			}},
+		},
+		{
+			Name: "_result",
+			Tables: []*platform.TaskDebugTable{{
+				Group:   map[string]interface{}{"host": "a"},
+				Columns: []string{"_time", "host", "_value"},
+				Rows: [][]interface{}{
+					{execute.Time(0).Time().UTC(), "a", 1.5},
+					{execute.Time(1e9).Time().UTC(), "a", nil},
+				},
+				Truncated: true,
+			}},
+		},
+	}
+	if !cmp.Equal(want, samples) {
+		t.Errorf("unexpected samples -want/+got:\n%s", cmp.Diff(want, samples))
+	}
+}
+
+func TestSampleResults_error(t *testing.T) {
+	results := flux.NewSliceResultIterator([]flux.Result{
+		&executetest.Result{
+			Nm: "_result",
+			Tbls: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{{Label: "_value", Type: flux.TInt}},
+				Err:     errors.New("expected error"),
+			}},
+		},
+	})
+
+	samples, err := sampleResults(results, 2)
+	if err == nil || err.Error() != "expected error" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(samples) != 1 || samples[0].Name != "_result" {
+		t.Errorf("expected the sample of the result that failed, got %v", samples)
+	}
+}
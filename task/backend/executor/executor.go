@@ -9,6 +9,7 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/influxdb"
 	icontext "github.com/influxdata/influxdb/context"
 	"github.com/influxdata/influxdb/kit/tracing"
@@ -167,7 +168,11 @@ func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 			AST: pkg,
 			Now: time.Unix(p.qr.Now, 0),
 		},
+		MemoryBytesQuota: p.t.MemoryBytesQuota,
+		MaxDuration:      p.t.EffectiveMaxDuration(),
+		Priority:         query.PriorityBatch,
 	}
+	started := time.Now()
 	it, err := p.qs.Query(p.ctx, req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
@@ -194,7 +199,7 @@ func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 	}
 
 	// Is it okay to assume it.Err will be set if the query context is canceled?
-	p.finish(&runResult{err: err, statistics: it.Statistics()}, nil)
+	p.finish(&runResult{err: runLimitError(p.t, started, err), statistics: it.Statistics()}, nil)
 }
 
 func (p *syncRunPromise) cancelOnContextDone(wg *sync.WaitGroup) {
@@ -250,14 +255,18 @@ func (e *asyncQueryServiceExecutor) Execute(ctx context.Context, run backend.Que
 			AST: pkg,
 			Now: time.Unix(run.Now, 0),
 		},
+		MemoryBytesQuota: t.MemoryBytesQuota,
+		MaxDuration:      t.EffectiveMaxDuration(),
+		Priority:         query.PriorityBatch,
 	}
+	started := time.Now()
 	// Only set the authorizer on the context where we need it here.
 	q, err := e.qs.Query(icontext.SetAuthorizer(ctx, auth), req)
 	if err != nil {
 		return nil, err
 	}
 
-	return newAsyncRunPromise(ctx, run, q, e), nil
+	return newAsyncRunPromise(ctx, run, t, started, q, e), nil
 }
 
 func (e *asyncQueryServiceExecutor) Wait() {
@@ -269,6 +278,9 @@ type asyncRunPromise struct {
 	qr backend.QueuedRun
 	q  flux.Query
 
+	t       *influxdb.Task
+	started time.Time // When the query was submitted, to attribute failures to the task's duration limit.
+
 	logger *zap.Logger
 	logEnd func() // Called to log the end of the run operation.
 
@@ -280,7 +292,7 @@ type asyncRunPromise struct {
 
 var _ backend.RunPromise = (*asyncRunPromise)(nil)
 
-func newAsyncRunPromise(ctx context.Context, qr backend.QueuedRun, q flux.Query, e *asyncQueryServiceExecutor) *asyncRunPromise {
+func newAsyncRunPromise(ctx context.Context, qr backend.QueuedRun, t *influxdb.Task, started time.Time, q flux.Query, e *asyncQueryServiceExecutor) *asyncRunPromise {
 	span, ctx := tracing.StartSpanFromContext(ctx)
 	defer span.Finish()
 
@@ -288,9 +300,11 @@ func newAsyncRunPromise(ctx context.Context, qr backend.QueuedRun, q flux.Query,
 	log, logEnd := logger.NewOperation(ctx, opLogger, "Executing task", "execute")
 
 	p := &asyncRunPromise{
-		qr:    qr,
-		q:     q,
-		ready: make(chan struct{}),
+		qr:      qr,
+		q:       q,
+		t:       t,
+		started: started,
+		ready:   make(chan struct{}),
 
 		logger: log,
 		logEnd: logEnd,
@@ -355,7 +369,7 @@ SelectLoop:
 
 	if p.q.Err() != nil {
 		// Something went wrong with the flux. Set the error in the run result.
-		rr := &runResult{err: p.q.Err()}
+		rr := &runResult{err: runLimitError(p.t, p.started, p.q.Err())}
 		p.finish(rr, nil)
 		return
 	}
@@ -395,6 +409,40 @@ func (rr *runResult) Err() error                  { return rr.err }
 func (rr *runResult) IsRetryable() bool           { return rr.retryable }
 func (rr *runResult) Statistics() flux.Statistics { return rr.statistics }
 
+// runLimitError translates err into a limit error when the failed run of t
+// was stopped because it reached one of the task's resource limits.
+// Any other error is returned unchanged.
+func runLimitError(t *influxdb.Task, started time.Time, err error) error {
+	if err == nil {
+		return nil
+	}
+	if d := t.EffectiveMaxDuration(); d > 0 && time.Since(started) >= d {
+		return influxdb.ErrRunDurationLimitExceeded(d, err)
+	}
+	if t.MemoryBytesQuota > 0 && isMemoryLimitError(err) {
+		return influxdb.ErrRunMemoryLimitExceeded(t.MemoryBytesQuota, err)
+	}
+	return err
+}
+
+// isMemoryLimitError reports whether err was caused by the allocator of the
+// query engine reaching the memory limit of the query.
+func isMemoryLimitError(err error) bool {
+	for err != nil {
+		switch e := err.(type) {
+		case memory.LimitExceededError, *memory.LimitExceededError:
+			return true
+		case *flux.Error:
+			err = e.Err
+		case *influxdb.Error:
+			err = e.Err
+		default:
+			return false
+		}
+	}
+	return false
+}
+
 // exhaustResultIterators drains all the iterators from a flux query Result.
 func exhaustResultIterators(res flux.Result) error {
 	return res.Tables().Do(func(tbl flux.Table) error {
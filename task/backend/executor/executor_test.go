@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/memory"
@@ -311,6 +312,7 @@ func TestExecutor(t *testing.T) {
 		testExecutorQueryFailure(t, fn)
 		testExecutorPromiseCancel(t, fn)
 		testExecutorServiceError(t, fn)
+		testExecutorLimitExceeded(t, fn)
 		testExecutorWait(t, fn)
 	}
 }
@@ -487,6 +489,68 @@ func testExecutorServiceError(t *testing.T, fn createSysFn) {
 	})
 }
 
+func testExecutorLimitExceeded(t *testing.T, fn createSysFn) {
+	sys := fn()
+	tc := createCreds(t, sys.i)
+	for _, tt := range []struct {
+		name   string
+		create platform.TaskCreate
+		// wait is how long the query runs before it fails with err.
+		wait    time.Duration
+		err     error
+		wantErr string
+	}{
+		{
+			name:    "MaxDuration",
+			create:  platform.TaskCreate{MaxDuration: "1ms"},
+			wait:    5 * time.Millisecond,
+			err:     context.DeadlineExceeded,
+			wantErr: "run exceeded max duration of 1ms",
+		},
+		{
+			name:   "MemoryBytesQuota",
+			create: platform.TaskCreate{MemoryBytesQuota: 1024},
+			err: &flux.Error{
+				Code: codes.ResourceExhausted,
+				Err:  memory.LimitExceededError{Limit: 1024, Allocated: 1000, Wanted: 100},
+			},
+			wantErr: "run exceeded memory quota of 1024 bytes",
+		},
+	} {
+		tt := tt
+		t.Run(sys.name+"/LimitExceeded/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+			script := fmt.Sprintf(fmtTestScript, t.Name())
+			ctx := icontext.SetAuthorizer(context.Background(), tc.Auth)
+			create := tt.create
+			create.OrganizationID, create.Token, create.Flux = tc.OrgID, tc.Auth.Token, script
+			task, err := sys.ts.CreateTask(ctx, create)
+			if err != nil {
+				t.Fatal(err)
+			}
+			qr := backend.QueuedRun{TaskID: task.ID, RunID: platform.ID(1), Now: 123}
+			rp, err := sys.ex.Execute(context.Background(), qr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sys.svc.WaitForQueryLive(t, script)
+			time.Sleep(tt.wait)
+			sys.svc.FailQuery(script, tt.err)
+			res, err := rp.Wait()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code := platform.ErrorCode(res.Err()); code != platform.ELimitExceeded {
+				t.Fatalf("expected a %s run failure, got %v", platform.ELimitExceeded, res.Err())
+			}
+			if got := platform.ErrorMessage(res.Err()); got != tt.wantErr {
+				t.Fatalf("expected error message %q, got %q", tt.wantErr, got)
+			}
+		})
+	}
+}
+
 func testExecutorWait(t *testing.T, createSys createSysFn) {
 	// This is a longer delay than I'd prefer,
 	// but it needs to be large-ish for slow machines running with the race detector.
@@ -371,8 +371,12 @@ func (w *worker) executeQuery(p *Promise) {
 			AST: pkg,
 			Now: sf,
 		},
+		MemoryBytesQuota: p.task.MemoryBytesQuota,
+		MaxDuration:      p.task.EffectiveMaxDuration(),
+		Priority:         query.PriorityBatch,
 	}
 
+	started := time.Now()
 	it, err := w.te.qs.Query(ctx, req)
 	if err != nil {
 		// Assume the error should not be part of the runResult.
@@ -404,7 +408,7 @@ func (w *worker) executeQuery(p *Promise) {
 		w.te.tcs.AddRunLog(p.ctx, p.task.ID, p.run.ID, time.Now(), string(b))
 	}
 
-	w.finish(p, backend.RunSuccess, runErr)
+	w.finish(p, backend.RunSuccess, runLimitError(p.task, started, runErr))
 }
 
 // Promise represents a promise the executor makes to finish a run's execution asynchronously.
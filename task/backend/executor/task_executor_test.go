@@ -145,7 +145,7 @@ func testManualRun(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	manualRun, err := tes.i.ForceRun(ctx, task.ID, 123)
+	manualRun, err := tes.i.ForceRun(ctx, task.ID, 123, "")
 	if err != nil {
 		t.Fatal(err)
 	}
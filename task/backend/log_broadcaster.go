@@ -0,0 +1,132 @@
+package backend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+// runLogBufferSize is the number of log lines buffered for each subscriber.
+// Lines are dropped for subscribers that fall further behind, so a slow client never holds up a run.
+const runLogBufferSize = 64
+
+var _ influxdb.RunLogStreamer = (*LogBroadcaster)(nil)
+
+// LogBroadcaster is a TaskControlService middleware that publishes the log lines
+// added to runs to the subscribers streaming them.
+type LogBroadcaster struct {
+	TaskControlService
+
+	mu   sync.Mutex
+	subs map[influxdb.ID]map[*logSubscription]struct{} // Keyed by run ID.
+}
+
+type logSubscription struct {
+	logs chan *influxdb.Log
+	done chan struct{}
+}
+
+// NewLogBroadcaster returns a LogBroadcaster that adds and finishes runs with tcs.
+func NewLogBroadcaster(tcs TaskControlService) *LogBroadcaster {
+	return &LogBroadcaster{
+		TaskControlService: tcs,
+		subs:               make(map[influxdb.ID]map[*logSubscription]struct{}),
+	}
+}
+
+// AddRunLog adds a log line to the run and publishes it to the subscribers of the run.
+func (b *LogBroadcaster) AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error {
+	if err := b.TaskControlService.AddRunLog(ctx, taskID, runID, when, log); err != nil {
+		return err
+	}
+
+	l := &influxdb.Log{RunID: runID, Time: when.Format(time.RFC3339Nano), Message: log}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subs[runID] {
+		select {
+		case s.logs <- l:
+		default:
+		}
+	}
+	return nil
+}
+
+// FinishRun finishes the run and ends the streams of its logs.
+func (b *LogBroadcaster) FinishRun(ctx context.Context, taskID, runID influxdb.ID) (*influxdb.Run, error) {
+	run, err := b.TaskControlService.FinishRun(ctx, taskID, runID)
+	if err != nil {
+		return run, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subs[runID] {
+		b.unsubscribe(runID, s)
+	}
+	return run, nil
+}
+
+// StreamRunLogs returns a channel receiving the log lines added to an in-progress run from now on.
+func (b *LogBroadcaster) StreamRunLogs(ctx context.Context, taskID, runID influxdb.ID) (<-chan *influxdb.Log, error) {
+	s := &logSubscription{
+		logs: make(chan *influxdb.Log, runLogBufferSize),
+		done: make(chan struct{}),
+	}
+
+	// Subscribe before checking the run, so no line added in between is missed.
+	b.mu.Lock()
+	if b.subs[runID] == nil {
+		b.subs[runID] = make(map[*logSubscription]struct{})
+	}
+	b.subs[runID][s] = struct{}{}
+	b.mu.Unlock()
+
+	running, err := b.TaskControlService.CurrentlyRunning(ctx, taskID)
+	if err == nil && !containsRun(running, runID) {
+		err = influxdb.ErrRunNotInProgress
+	}
+	if err != nil {
+		b.mu.Lock()
+		b.unsubscribe(runID, s)
+		b.mu.Unlock()
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			b.mu.Lock()
+			b.unsubscribe(runID, s)
+			b.mu.Unlock()
+		case <-s.done:
+		}
+	}()
+	return s.logs, nil
+}
+
+// unsubscribe removes s from the subscribers of the run and closes its channel.
+// b.mu must be held.
+func (b *LogBroadcaster) unsubscribe(runID influxdb.ID, s *logSubscription) {
+	if _, ok := b.subs[runID][s]; !ok {
+		return
+	}
+	delete(b.subs[runID], s)
+	if len(b.subs[runID]) == 0 {
+		delete(b.subs, runID)
+	}
+	close(s.logs)
+	close(s.done)
+}
+
+func containsRun(runs []*influxdb.Run, runID influxdb.ID) bool {
+	for _, r := range runs {
+		if r.ID == runID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package backend_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/mock"
+)
+
+func TestLogBroadcaster(t *testing.T) {
+	tcs := mock.NewTaskControlService()
+	task := &influxdb.Task{ID: 1, OrganizationID: 2}
+	tcs.SetTask(task)
+
+	b := backend.NewLogBroadcaster(tcs)
+	ctx := context.Background()
+
+	run, err := b.CreateRun(ctx, task.ID, time.Unix(60, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The line added before subscribing is not streamed.
+	if err := b.AddRunLog(ctx, task.ID, run.ID, time.Unix(61, 0), "before"); err != nil {
+		t.Fatal(err)
+	}
+
+	logs, err := b.StreamRunLogs(ctx, task.ID, run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	when := time.Unix(62, 0)
+	if err := b.AddRunLog(ctx, task.ID, run.ID, when, "after"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case l := <-logs:
+		if l.RunID != run.ID || l.Message != "after" || l.Time != when.Format(time.RFC3339Nano) {
+			t.Fatalf("unexpected log %+v", l)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the log line")
+	}
+
+	if _, err := b.FinishRun(ctx, task.ID, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case l, ok := <-logs:
+		if ok {
+			t.Fatalf("expected the stream to end when the run finished, got %+v", l)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the stream to end")
+	}
+
+	if _, err := b.StreamRunLogs(ctx, task.ID, run.ID); err != influxdb.ErrRunNotInProgress {
+		t.Fatalf("expected ErrRunNotInProgress for a finished run, got %v", err)
+	}
+}
+
+func TestLogBroadcaster_Cancel(t *testing.T) {
+	tcs := mock.NewTaskControlService()
+	task := &influxdb.Task{ID: 1, OrganizationID: 2}
+	tcs.SetTask(task)
+
+	b := backend.NewLogBroadcaster(tcs)
+	run, err := b.CreateRun(context.Background(), task.ID, time.Unix(60, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	logs, err := b.StreamRunLogs(ctx, task.ID, run.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-logs:
+		if ok {
+			t.Fatal("expected no log lines")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the stream to end")
+	}
+
+	// Adding a line after the stream ended must not block or panic.
+	if err := b.AddRunLog(context.Background(), task.ID, run.ID, time.Unix(61, 0), "after"); err != nil {
+		t.Fatal(err)
+	}
+}
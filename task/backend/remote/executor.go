@@ -0,0 +1,362 @@
+package remote
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb"
+	kitgrpc "github.com/influxdata/influxdb/kit/grpc"
+	"github.com/influxdata/influxdb/task/backend"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// secretMetadataKey is the gRPC metadata key of the shared secret of the workers.
+	secretMetadataKey = "x-influx-task-worker-secret"
+
+	// DefaultLeaseTimeout is the default time a claimed run is kept without a heartbeat.
+	DefaultLeaseTimeout = 30 * time.Second
+	// DefaultClaimTimeout is the default time a claim waits for a run.
+	DefaultClaimTimeout = 10 * time.Second
+
+	// maxQueuedRuns is the number of runs that can wait for a worker.
+	maxQueuedRuns = 1000
+)
+
+// ErrQueueFull is returned by Execute when too many runs are waiting for a worker.
+var ErrQueueFull = &influxdb.Error{
+	Code: influxdb.EUnavailable,
+	Msg:  "too many task runs are waiting for a remote worker",
+}
+
+// Executor is a backend.Executor that hands runs to external workers.
+type Executor struct {
+	// LeaseTimeout is how long a claimed run is kept after the last heartbeat of its worker.
+	LeaseTimeout time.Duration
+	// ClaimTimeout is how long a claim waits for a run before returning no assignment.
+	ClaimTimeout time.Duration
+
+	logger *zap.Logger
+	as     influxdb.AuthorizationService
+	ts     influxdb.TaskService
+	secret string
+	now    func() time.Time
+
+	queue chan *runPromise
+
+	mu   sync.Mutex
+	runs map[influxdb.ID]*runPromise // Unfinished runs, by run ID.
+
+	wg sync.WaitGroup // Unfinished runs.
+
+	server    *grpc.Server
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var (
+	_ backend.Executor = (*Executor)(nil)
+	_ ExecutorServer   = (*Executor)(nil)
+)
+
+// NewExecutor returns a new Executor.
+// Workers must authenticate with secret, unless it is empty.
+func NewExecutor(logger *zap.Logger, as influxdb.AuthorizationService, ts influxdb.TaskService, secret string) *Executor {
+	e := &Executor{
+		LeaseTimeout: DefaultLeaseTimeout,
+		ClaimTimeout: DefaultClaimTimeout,
+
+		logger: logger,
+		as:     as,
+		ts:     ts,
+		secret: secret,
+		now:    time.Now,
+
+		queue: make(chan *runPromise, maxQueuedRuns),
+		runs:  make(map[influxdb.ID]*runPromise),
+		done:  make(chan struct{}),
+	}
+	e.server = grpc.NewServer(grpc.UnaryInterceptor(e.authenticate))
+	RegisterExecutorServer(e.server, e)
+	return e
+}
+
+// Serve serves the remote executor protocol to workers on ln.
+// It blocks until the executor is closed.
+func (e *Executor) Serve(ln net.Listener) error {
+	go e.expireLeases()
+	return e.server.Serve(ln)
+}
+
+// Close stops serving workers. Runs that were not finished are left to expire.
+func (e *Executor) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.done)
+		e.server.GracefulStop()
+	})
+	return nil
+}
+
+// authenticate rejects calls that do not carry the shared secret.
+func (e *Executor) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if e.secret != "" {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var secret string
+		if vs := md[secretMetadataKey]; len(vs) > 0 {
+			secret = vs[0]
+		}
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(e.secret)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid task worker secret")
+		}
+	}
+
+	res, err := handler(ctx, req)
+	if perr, ok := err.(*influxdb.Error); ok {
+		s, serr := kitgrpc.ToStatus(perr)
+		if serr != nil {
+			return nil, serr
+		}
+		return nil, s.Err()
+	}
+	return res, err
+}
+
+// Execute queues the run until a worker claims it.
+func (e *Executor) Execute(ctx context.Context, run backend.QueuedRun) (backend.RunPromise, error) {
+	t, err := e.ts.FindTaskByID(ctx, run.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := e.as.FindAuthorizationByID(ctx, influxdb.ID(t.AuthorizationID))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &runPromise{
+		qr: run,
+		assignment: &Assignment{
+			TaskID:           run.TaskID,
+			RunID:            run.RunID,
+			OrganizationID:   t.OrganizationID,
+			Flux:             t.Flux,
+			Now:              run.Now,
+			Authorization:    auth,
+			MemoryBytesQuota: t.MemoryBytesQuota,
+			MaxDuration:      t.EffectiveMaxDuration(),
+		},
+		e:      e,
+		logger: e.logger.With(zap.Stringer("task_id", run.TaskID), zap.Stringer("run_id", run.RunID)),
+		ready:  make(chan struct{}),
+	}
+
+	e.mu.Lock()
+	e.runs[run.RunID] = p
+	e.mu.Unlock()
+	e.wg.Add(1)
+
+	select {
+	case e.queue <- p:
+	default:
+		p.finish(nil, ErrQueueFull)
+		return nil, ErrQueueFull
+	}
+
+	p.logger.Debug("Queued run for a remote worker")
+	return p, nil
+}
+
+// Wait blocks until all runs created through Execute have finished.
+func (e *Executor) Wait() {
+	e.wg.Wait()
+}
+
+// Claim waits for a queued run and assigns it to the worker.
+func (e *Executor) Claim(ctx context.Context, req *ClaimRequest) (*ClaimResponse, error) {
+	if req.WorkerID == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "workerID is required",
+		}
+	}
+
+	timer := time.NewTimer(e.ClaimTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case p := <-e.queue:
+			// Runs canceled while they were queued are skipped.
+			if !p.claim(req.WorkerID) {
+				continue
+			}
+			p.logger.Debug("Run claimed by remote worker", zap.String("worker_id", req.WorkerID))
+			return &ClaimResponse{Assignment: p.assignment, LeaseTimeout: e.LeaseTimeout}, nil
+		case <-timer.C:
+			return &ClaimResponse{LeaseTimeout: e.LeaseTimeout}, nil
+		case <-e.done:
+			return &ClaimResponse{LeaseTimeout: e.LeaseTimeout}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Heartbeat extends the lease of a claimed run.
+func (e *Executor) Heartbeat(ctx context.Context, req *HeartbeatRequest) (*HeartbeatResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	p, ok := e.runs[req.RunID]
+	if !ok || p.workerID != req.WorkerID {
+		return &HeartbeatResponse{Canceled: true}, nil
+	}
+	p.leaseExpiresAt = e.now().Add(e.LeaseTimeout)
+	return &HeartbeatResponse{}, nil
+}
+
+// Finish sets the result of a claimed run.
+// Results of runs that are no longer assigned to the worker are ignored.
+func (e *Executor) Finish(ctx context.Context, req *FinishRequest) (*FinishResponse, error) {
+	e.mu.Lock()
+	p, ok := e.runs[req.RunID]
+	ok = ok && p.workerID == req.WorkerID
+	e.mu.Unlock()
+	if !ok {
+		return &FinishResponse{}, nil
+	}
+
+	res := &runResult{retryable: req.Retryable, statistics: req.Statistics}
+	if req.Error != "" {
+		res.err = errors.New(req.Error)
+	}
+	p.finish(res, nil)
+	return &FinishResponse{}, nil
+}
+
+// expireLeases fails the claimed runs whose worker stopped heartbeating.
+func (e *Executor) expireLeases() {
+	ticker := time.NewTicker(e.LeaseTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+		}
+
+		now := e.now()
+		expired := make(map[*runPromise]string)
+		e.mu.Lock()
+		for _, p := range e.runs {
+			if p.workerID != "" && now.After(p.leaseExpiresAt) {
+				expired[p] = p.workerID
+			}
+		}
+		e.mu.Unlock()
+
+		for p, workerID := range expired {
+			p.finish(&runResult{
+				err: &influxdb.Error{
+					Code: influxdb.EUnavailable,
+					Msg:  fmt.Sprintf("remote worker %q stopped heartbeating", workerID),
+				},
+				retryable: true,
+			}, nil)
+		}
+	}
+}
+
+// runPromise implements backend.RunPromise for a run executed by a worker.
+type runPromise struct {
+	qr         backend.QueuedRun
+	assignment *Assignment
+	e          *Executor
+	logger     *zap.Logger
+
+	// Protected by e.mu.
+	workerID       string
+	leaseExpiresAt time.Time
+
+	finishOnce sync.Once     // Ensure we set the values only once.
+	ready      chan struct{} // Closed inside finish. Indicates Wait will no longer block.
+	res        *runResult
+	err        error
+}
+
+var _ backend.RunPromise = (*runPromise)(nil)
+
+// claim assigns the run to the worker, unless it already finished.
+func (p *runPromise) claim(workerID string) bool {
+	p.e.mu.Lock()
+	defer p.e.mu.Unlock()
+
+	if _, ok := p.e.runs[p.qr.RunID]; !ok {
+		return false
+	}
+	p.workerID = workerID
+	p.leaseExpiresAt = p.e.now().Add(p.e.LeaseTimeout)
+	return true
+}
+
+func (p *runPromise) Run() backend.QueuedRun {
+	return p.qr
+}
+
+func (p *runPromise) Wait() (backend.RunResult, error) {
+	<-p.ready
+
+	// Need an explicit return nil to avoid the non-nil interface value issue.
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.res, nil
+}
+
+// Cancel finishes the run; its worker learns about it on the next heartbeat.
+func (p *runPromise) Cancel() {
+	p.finish(nil, influxdb.ErrRunCanceled)
+}
+
+func (p *runPromise) finish(res *runResult, err error) {
+	p.finishOnce.Do(func() {
+		p.e.mu.Lock()
+		delete(p.e.runs, p.qr.RunID)
+		p.e.mu.Unlock()
+		defer p.e.wg.Done()
+
+		p.res, p.err = res, err
+		close(p.ready)
+
+		if err != nil {
+			p.logger.Info("Execution failed to get result", zap.Error(err))
+		} else if res.err != nil {
+			p.logger.Info("Got result with error", zap.Error(res.err))
+		} else {
+			p.logger.Debug("Completed successfully")
+		}
+	})
+}
+
+type runResult struct {
+	err        error
+	retryable  bool
+	statistics flux.Statistics
+}
+
+var _ backend.RunResult = (*runResult)(nil)
+
+func (rr *runResult) Err() error                  { return rr.err }
+func (rr *runResult) IsRetryable() bool           { return rr.retryable }
+func (rr *runResult) Statistics() flux.Statistics { return rr.statistics }
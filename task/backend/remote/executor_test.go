@@ -0,0 +1,237 @@
+package remote_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	platform "github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/query"
+	querymock "github.com/influxdata/influxdb/query/mock"
+	"github.com/influxdata/influxdb/task/backend"
+	"github.com/influxdata/influxdb/task/backend/remote"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "s3cr3t"
+
+type testServer struct {
+	e     *remote.Executor
+	addr  string
+	conns []*grpc.ClientConn
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	ts := &mock.TaskService{
+		FindTaskByIDFn: func(ctx context.Context, id platform.ID) (*platform.Task, error) {
+			return &platform.Task{
+				ID:              id,
+				OrganizationID:  2,
+				AuthorizationID: 3,
+				Flux:            `option task = {name: "t", every: 1m} from(bucket: "b") |> range(start: -1m)`,
+			}, nil
+		},
+	}
+	as := mock.NewAuthorizationService()
+	as.FindAuthorizationByIDFn = func(ctx context.Context, id platform.ID) (*platform.Authorization, error) {
+		return &platform.Authorization{ID: id, OrgID: 2, Token: "task-token", Status: platform.Active}, nil
+	}
+
+	e := remote.NewExecutor(zaptest.NewLogger(t), as, ts, testSecret)
+	e.LeaseTimeout = 300 * time.Millisecond
+	e.ClaimTimeout = 50 * time.Millisecond
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go e.Serve(ln)
+
+	return &testServer{e: e, addr: ln.Addr().String()}
+}
+
+func (s *testServer) client(t *testing.T, opts ...grpc.DialOption) remote.ExecutorClient {
+	t.Helper()
+
+	cc, err := grpc.Dial(s.addr, append([]grpc.DialOption{grpc.WithInsecure()}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.conns = append(s.conns, cc)
+	return remote.NewExecutorClient(cc)
+}
+
+func (s *testServer) Close() {
+	for _, cc := range s.conns {
+		cc.Close()
+	}
+	s.e.Close()
+}
+
+func TestExecutor_RemoteWorker(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	var (
+		mu   sync.Mutex
+		reqs []*query.Request
+	)
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			mu.Lock()
+			reqs = append(reqs, req)
+			mu.Unlock()
+
+			if req.Compiler.(lang.ASTCompiler).Now.Unix() == 2 {
+				return nil, errors.New("boom")
+			}
+			return flux.NewSliceResultIterator(nil), nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := remote.NewWorker("worker-1", s.client(t, remote.WithSecret(testSecret)), qs, zaptest.NewLogger(t))
+	w.Concurrency = 2
+	go w.Run(ctx)
+
+	succeeded, err := s.e.Execute(ctx, backend.QueuedRun{TaskID: 1, RunID: 10, Now: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed, err := s.e.Execute(ctx, backend.QueuedRun{TaskID: 1, RunID: 11, Now: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := succeeded.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Err() != nil {
+		t.Errorf("expected run to succeed, got %v", res.Err())
+	}
+
+	res, err = failed.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Err() == nil || res.Err().Error() != "boom" || res.IsRetryable() {
+		t.Errorf("expected run to fail with a terminal error, got %v", res.Err())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 queries, got %d", len(reqs))
+	}
+	for _, req := range reqs {
+		if req.OrganizationID != 2 || req.Authorization == nil || req.Authorization.Token != "task-token" {
+			t.Errorf("expected the query to run with the authorization of the task, got %+v", req)
+		}
+	}
+}
+
+func TestExecutor_Cancel(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	qs := &querymock.QueryService{
+		QueryF: func(ctx context.Context, req *query.Request) (flux.ResultIterator, error) {
+			close(started)
+			<-ctx.Done()
+			close(stopped)
+			return nil, ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := remote.NewWorker("worker-1", s.client(t, remote.WithSecret(testSecret)), qs, zaptest.NewLogger(t))
+	go w.Run(ctx)
+
+	p, err := s.e.Execute(ctx, backend.QueuedRun{TaskID: 1, RunID: 10, Now: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	p.Cancel()
+
+	if _, err := p.Wait(); err != platform.ErrRunCanceled {
+		t.Fatalf("expected run to be canceled, got %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker did not stop the canceled run")
+	}
+}
+
+func TestExecutor_LeaseExpired(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	ctx := context.Background()
+	p, err := s.e.Execute(ctx, backend.QueuedRun{TaskID: 1, RunID: 10, Now: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Claim the run without ever heartbeating.
+	c := s.client(t, remote.WithSecret(testSecret))
+	claim, err := c.Claim(ctx, &remote.ClaimRequest{WorkerID: "worker-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.Assignment == nil || claim.Assignment.RunID != 10 {
+		t.Fatalf("expected the run to be assigned, got %+v", claim.Assignment)
+	}
+
+	res, err := p.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Err() == nil || !res.IsRetryable() {
+		t.Errorf("expected a retryable error, got %v", res.Err())
+	}
+
+	// A late result of the worker is ignored.
+	hb, err := c.Heartbeat(ctx, &remote.HeartbeatRequest{WorkerID: "worker-1", RunID: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hb.Canceled {
+		t.Error("expected the expired run to be canceled")
+	}
+	if _, err := c.Finish(ctx, &remote.FinishRequest{WorkerID: "worker-1", RunID: 10}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecutor_Secret(t *testing.T) {
+	s := newTestServer(t)
+	defer s.Close()
+
+	for _, opts := range [][]grpc.DialOption{
+		nil,
+		{remote.WithSecret("wrong")},
+	} {
+		_, err := s.client(t, opts...).Claim(context.Background(), &remote.ClaimRequest{WorkerID: "worker-1"})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("expected unauthenticated error, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// Package remote executes task runs on a pool of external worker processes.
+//
+// The Executor implements backend.Executor by queueing runs instead of running
+// their queries in-process, and serves them to workers over gRPC. Workers pull
+// runs with Claim, keep the lease of a claimed run alive with Heartbeat, and
+// report its result with Finish. A run whose worker stops heartbeating fails
+// with a retryable error. A canceled run is reported to its worker in the
+// response of the next heartbeat.
+//
+// Messages are encoded as JSON with the "json" gRPC content-subtype, so that
+// workers can be written in any language with a gRPC implementation.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/influxdb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+const (
+	codecName   = "json"
+	serviceName = "influxdata.platform.task.remote.Executor"
+)
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// jsonCodec encodes the messages of the protocol as JSON.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return codecName }
+
+// ClaimRequest asks for a run to execute.
+type ClaimRequest struct {
+	// WorkerID identifies the worker process; it must be unique in the pool.
+	WorkerID string `json:"workerID"`
+}
+
+// ClaimResponse holds the run assigned to the worker, if any became available
+// before the claim timed out.
+type ClaimResponse struct {
+	Assignment *Assignment `json:"assignment,omitempty"`
+
+	// LeaseTimeout is how long the assignment is kept without a heartbeat.
+	LeaseTimeout time.Duration `json:"leaseTimeout"`
+}
+
+// Assignment is everything a worker needs to execute a run.
+type Assignment struct {
+	TaskID         influxdb.ID `json:"taskID"`
+	RunID          influxdb.ID `json:"runID"`
+	OrganizationID influxdb.ID `json:"orgID"`
+
+	Flux string `json:"flux"`
+	// Now is the Unix timestamp to set as the "now" option of the query.
+	Now int64 `json:"now"`
+
+	// Authorization is the authorization of the task, which the query runs with.
+	Authorization *influxdb.Authorization `json:"authorization"`
+
+	MemoryBytesQuota int64         `json:"memoryBytesQuota,omitempty"`
+	MaxDuration      time.Duration `json:"maxDuration,omitempty"`
+}
+
+// HeartbeatRequest extends the lease of a claimed run.
+type HeartbeatRequest struct {
+	WorkerID string      `json:"workerID"`
+	RunID    influxdb.ID `json:"runID"`
+}
+
+// HeartbeatResponse tells the worker whether to keep executing the run.
+type HeartbeatResponse struct {
+	// Canceled is true if the run was canceled, or is no longer assigned to the worker.
+	Canceled bool `json:"canceled"`
+}
+
+// FinishRequest reports the result of a run.
+type FinishRequest struct {
+	WorkerID string      `json:"workerID"`
+	RunID    influxdb.ID `json:"runID"`
+
+	// Error is the reason the run failed, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+	// Retryable is true if the run failed for a reason unrelated to the task.
+	Retryable bool `json:"retryable,omitempty"`
+
+	Statistics flux.Statistics `json:"statistics"`
+}
+
+// FinishResponse acknowledges a FinishRequest.
+type FinishResponse struct{}
+
+// ExecutorServer is the server API of the remote executor protocol.
+type ExecutorServer interface {
+	// Claim waits for a run to execute.
+	Claim(ctx context.Context, req *ClaimRequest) (*ClaimResponse, error)
+
+	// Heartbeat extends the lease of a claimed run.
+	Heartbeat(ctx context.Context, req *HeartbeatRequest) (*HeartbeatResponse, error)
+
+	// Finish reports the result of a claimed run.
+	Finish(ctx context.Context, req *FinishRequest) (*FinishResponse, error)
+}
+
+// RegisterExecutorServer registers the remote executor protocol with s.
+func RegisterExecutorServer(s *grpc.Server, srv ExecutorServer) {
+	s.RegisterService(&executorServiceDesc, srv)
+}
+
+var executorServiceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*ExecutorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Claim",
+			Handler:    executorClaimHandler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    executorHeartbeatHandler,
+		},
+		{
+			MethodName: "Finish",
+			Handler:    executorFinishHandler,
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func executorClaimHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Claim(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/Claim",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Claim(ctx, req.(*ClaimRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func executorHeartbeatHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/Heartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func executorFinishHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Finish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + serviceName + "/Finish",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Finish(ctx, req.(*FinishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExecutorClient is the client API of the remote executor protocol.
+type ExecutorClient interface {
+	Claim(ctx context.Context, req *ClaimRequest, opts ...grpc.CallOption) (*ClaimResponse, error)
+	Heartbeat(ctx context.Context, req *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	Finish(ctx context.Context, req *FinishRequest, opts ...grpc.CallOption) (*FinishResponse, error)
+}
+
+type executorClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewExecutorClient returns a client of the remote executor protocol.
+func NewExecutorClient(cc *grpc.ClientConn) ExecutorClient {
+	return &executorClient{cc: cc}
+}
+
+func (c *executorClient) invoke(ctx context.Context, method string, in, out interface{}, opts []grpc.CallOption) error {
+	opts = append([]grpc.CallOption{grpc.CallContentSubtype(codecName)}, opts...)
+	return c.cc.Invoke(ctx, "/"+serviceName+"/"+method, in, out, opts...)
+}
+
+func (c *executorClient) Claim(ctx context.Context, req *ClaimRequest, opts ...grpc.CallOption) (*ClaimResponse, error) {
+	out := new(ClaimResponse)
+	if err := c.invoke(ctx, "Claim", req, out, opts); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorClient) Heartbeat(ctx context.Context, req *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	out := new(HeartbeatResponse)
+	if err := c.invoke(ctx, "Heartbeat", req, out, opts); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorClient) Finish(ctx context.Context, req *FinishRequest, opts ...grpc.CallOption) (*FinishResponse, error) {
+	out := new(FinishResponse)
+	if err := c.invoke(ctx, "Finish", req, out, opts); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// secretCredentials authenticates the calls of a worker with the shared secret.
+type secretCredentials string
+
+// WithSecret returns a dial option that authenticates the calls of a worker with the shared secret.
+func WithSecret(secret string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(secretCredentials(secret))
+}
+
+func (s secretCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{secretMetadataKey: string(s)}, nil
+}
+
+// RequireTransportSecurity returns false, since workers typically run on a private network.
+func (s secretCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,180 @@
+package remote
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/logger"
+	"github.com/influxdata/influxdb/query"
+	"go.uber.org/zap"
+)
+
+// claimRetryInterval is how long a worker waits to claim again after a failed claim.
+const claimRetryInterval = time.Second
+
+// Worker claims runs from a remote Executor and executes them with its own query service.
+type Worker struct {
+	// ID identifies the worker in the pool of the executor.
+	ID string
+	// Concurrency is the number of runs the worker executes at once.
+	Concurrency int
+
+	client ExecutorClient
+	qs     query.QueryService
+	logger *zap.Logger
+}
+
+// NewWorker returns a new Worker that executes one run at a time.
+func NewWorker(id string, client ExecutorClient, qs query.QueryService, logger *zap.Logger) *Worker {
+	return &Worker{
+		ID:          id,
+		Concurrency: 1,
+		client:      client,
+		qs:          qs,
+		logger:      logger.With(zap.String("worker_id", id)),
+	}
+}
+
+// Run claims and executes runs until ctx is done.
+func (w *Worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < w.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.claimLoop(ctx)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (w *Worker) claimLoop(ctx context.Context) {
+	for ctx.Err() == nil {
+		res, err := w.client.Claim(ctx, &ClaimRequest{WorkerID: w.ID})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			w.logger.Info("Failed to claim a run", zap.Error(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(claimRetryInterval):
+			}
+			continue
+		}
+		if res.Assignment != nil {
+			w.execute(ctx, res.Assignment, res.LeaseTimeout)
+		}
+	}
+}
+
+// execute runs the assignment, heartbeating until it finishes, and reports its result.
+func (w *Worker) execute(ctx context.Context, a *Assignment, leaseTimeout time.Duration) {
+	opLogger := w.logger.With(zap.Stringer("task_id", a.TaskID), zap.Stringer("run_id", a.RunID))
+	log, logEnd := logger.NewOperation(ctx, opLogger, "Executing remote task run", "execute")
+	defer logEnd()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.heartbeat(runCtx, a, leaseTimeout, done, cancel, log)
+	}()
+
+	stats, err := w.query(runCtx, a)
+	close(done)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		// The worker is stopping; the run is failed when its lease expires.
+		return
+	}
+
+	req := &FinishRequest{
+		WorkerID:   w.ID,
+		RunID:      a.RunID,
+		Statistics: stats,
+	}
+	if err != nil {
+		req.Error = err.Error()
+		log.Info("Run failed", zap.Error(err))
+	}
+	if _, err := w.client.Finish(ctx, req); err != nil {
+		log.Info("Failed to report the result of the run", zap.Error(err))
+	}
+}
+
+// heartbeat keeps the lease of the run until done is closed,
+// and cancels the run when the executor no longer wants its result.
+func (w *Worker) heartbeat(ctx context.Context, a *Assignment, leaseTimeout time.Duration, done chan struct{}, cancel context.CancelFunc, log *zap.Logger) {
+	ticker := time.NewTicker(leaseTimeout / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		res, err := w.client.Heartbeat(ctx, &HeartbeatRequest{WorkerID: w.ID, RunID: a.RunID})
+		if err != nil {
+			// The lease may still be renewed by the next heartbeat.
+			log.Info("Failed to heartbeat", zap.Error(err))
+			continue
+		}
+		if res.Canceled {
+			log.Info("Run was canceled")
+			cancel()
+			return
+		}
+	}
+}
+
+// query executes the flux of the assignment and drains its results.
+func (w *Worker) query(ctx context.Context, a *Assignment) (flux.Statistics, error) {
+	pkg, err := flux.Parse(a.Flux)
+	if err != nil {
+		return flux.Statistics{}, err
+	}
+
+	req := &query.Request{
+		Authorization:  a.Authorization,
+		OrganizationID: a.OrganizationID,
+		Compiler: lang.ASTCompiler{
+			AST: pkg,
+			Now: time.Unix(a.Now, 0),
+		},
+		MemoryBytesQuota: a.MemoryBytesQuota,
+		MaxDuration:      a.MaxDuration,
+		Priority:         query.PriorityBatch,
+	}
+	it, err := w.qs.Query(icontext.SetAuthorizer(ctx, a.Authorization), req)
+	if err != nil {
+		return flux.Statistics{}, err
+	}
+	defer it.Release()
+
+	for it.More() {
+		// Consume the full iterator so that we don't leak outstanding iterators.
+		res := it.Next()
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			return tbl.Do(func(flux.ColReader) error { return nil })
+		}); err != nil {
+			w.logger.Info("Error exhausting result iterator", zap.Error(err), zap.String("name", res.Name()))
+		}
+	}
+
+	// Must call Release to ensure Statistics are ready.
+	it.Release()
+	return it.Statistics(), it.Err()
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -156,6 +157,13 @@ func WithLogger(logger *zap.Logger) TickSchedulerOption {
 	}
 }
 
+// WithInstanceID sets the identity of the instance reported as the holder of the scheduler's claims.
+func WithInstanceID(id string) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		s.instanceID = id
+	}
+}
+
 // NewScheduler returns a new scheduler with the given desired state and the given now UTC timestamp.
 func NewScheduler(taskControlService TaskControlService, executor Executor, now int64, opts ...TickSchedulerOption) *TickScheduler {
 	o := &TickScheduler{
@@ -175,12 +183,15 @@ func NewScheduler(taskControlService TaskControlService, executor Executor, now
 	return o
 }
 
+var _ platform.SchedulerStateService = (*TickScheduler)(nil)
+
 type TickScheduler struct {
 	taskControlService TaskControlService
 	executor           Executor
 
-	now    int64
-	logger *zap.Logger
+	now        int64
+	logger     *zap.Logger
+	instanceID string
 
 	metrics *schedulerMetrics
 
@@ -188,8 +199,12 @@ type TickScheduler struct {
 	cancel context.CancelFunc
 	wg     *sync.WaitGroup
 
-	schedulerMu    sync.Mutex                     // Protects access and modification of taskSchedulers map.
+	schedulerMu    sync.Mutex                     // Protects access and modification of taskSchedulers map, and the tick stats.
 	taskSchedulers map[platform.ID]*taskScheduler // task ID -> task scheduler.
+
+	lastTick     time.Time     // Wall time the last tick finished.
+	tickLatency  time.Duration // Time between the second of the last tick and it finishing.
+	tickDuration time.Duration // Time the last tick took.
 }
 
 // CancelRun cancels a run, it has the unused Context argument so that it can implement a task.RunController
@@ -231,6 +246,7 @@ func (s *TickScheduler) Tick(now int64) {
 		// do nothing and allow ticks
 	}
 
+	start := time.Now()
 	atomic.StoreInt64(&s.now, now)
 
 	affected := 0
@@ -240,6 +256,20 @@ func (s *TickScheduler) Tick(now int64) {
 			affected++
 		}
 	}
+
+	// Tasks that are still due after their work cycle could not start a run.
+	backlog := 0
+	for _, ts := range s.taskSchedulers {
+		if isBacklogged(ts.State(now).Status) {
+			backlog++
+		}
+	}
+
+	s.lastTick = time.Now()
+	s.tickDuration = s.lastTick.Sub(start)
+	s.tickLatency = s.lastTick.Sub(time.Unix(now, 0))
+	s.metrics.Tick(s.tickLatency, backlog)
+
 	// TODO(mr): find a way to emit a more useful / less annoying tick message, maybe aggregated over the past 10s or 30s?
 	s.logger.Debug("Ticked", zap.Int64("now", now), zap.Int("tasks_affected", affected))
 }
@@ -403,6 +433,41 @@ func (s *TickScheduler) ReleaseTask(taskID platform.ID) error {
 	return nil
 }
 
+// SchedulerState returns a snapshot of the scheduler and the tasks it has claimed.
+func (s *TickScheduler) SchedulerState(ctx context.Context) (*platform.SchedulerState, error) {
+	s.schedulerMu.Lock()
+	defer s.schedulerMu.Unlock()
+
+	now := atomic.LoadInt64(&s.now)
+	state := &platform.SchedulerState{
+		InstanceID:   s.instanceID,
+		Now:          time.Unix(now, 0).UTC(),
+		LastTick:     s.lastTick,
+		TickLatency:  s.tickLatency,
+		TickDuration: s.tickDuration,
+		Tasks:        make([]platform.TaskSchedulerState, 0, len(s.taskSchedulers)),
+	}
+
+	for _, ts := range s.taskSchedulers {
+		t := ts.State(now)
+		t.ClaimedBy = s.instanceID
+		if isBacklogged(t.Status) {
+			state.Backlog++
+		}
+		state.Tasks = append(state.Tasks, t)
+	}
+	sort.Slice(state.Tasks, func(i, j int) bool {
+		return state.Tasks[i].TaskID < state.Tasks[j].TaskID
+	})
+
+	return state, nil
+}
+
+// isBacklogged returns true if a task in the given scheduling status is due but has not started a run.
+func isBacklogged(status string) bool {
+	return status == platform.TaskSchedulerStatusDue || status == platform.TaskSchedulerStatusBlocked
+}
+
 func (s *TickScheduler) PrometheusCollectors() []prometheus.Collector {
 	return s.metrics.PrometheusCollectors()
 }
@@ -423,10 +488,16 @@ type taskScheduler struct {
 	// Authorization context for using the TaskControlService
 	authCtx context.Context
 
+	// Wall time the task was claimed.
+	claimedAt time.Time
+
 	// CancelFunc for context passed to runners, to enable Cancel method.
 	cancel context.CancelFunc
 	wg     *sync.WaitGroup
 
+	// release releases the task from the outer scheduler.
+	release func()
+
 	// Fixed-length slice of runners.
 	runners   []*runner
 	running   map[platform.ID]runCtx
@@ -473,8 +544,10 @@ func newTaskScheduler(
 		now:           &s.now,
 		task:          task,
 		authCtx:       authCtx,
+		claimedAt:     time.Now().UTC(),
 		cancel:        cancel,
 		wg:            wg,
+		release:       func() { s.ReleaseTask(task.ID) },
 		runners:       make([]*runner, maxC),
 		running:       make(map[platform.ID]runCtx, maxC),
 		logger:        s.logger.With(zap.String("task_id", task.ID.String())),
@@ -556,6 +629,54 @@ func (ts *taskScheduler) SetNextDue(nextDue int64, hasQueue bool, source int64)
 	ts.hasQueue = hasQueue
 }
 
+// State returns the scheduling state of the task at the scheduler time now.
+func (ts *taskScheduler) State(now int64) platform.TaskSchedulerState {
+	nextDue, hasQueue := ts.NextDue()
+
+	ts.runningMu.Lock()
+	active := len(ts.running)
+	maxC := len(ts.runners)
+	busy := 0
+	for _, r := range ts.runners {
+		if !r.IsIdle() {
+			busy++
+		}
+	}
+	ts.runningMu.Unlock()
+
+	var next time.Time
+	if nextDue != RunNeverDue {
+		next = time.Unix(nextDue, 0).UTC()
+	}
+
+	var status string
+	switch due := now >= nextDue || hasQueue; {
+	case ts.task.Status == platform.TaskStatusInactive:
+		status = platform.TaskSchedulerStatusInactive
+	case due && busy >= maxC:
+		status = platform.TaskSchedulerStatusBlocked
+	case due:
+		status = platform.TaskSchedulerStatusDue
+	case active > 0:
+		status = platform.TaskSchedulerStatusRunning
+	default:
+		status = platform.TaskSchedulerStatusWaiting
+	}
+
+	return platform.TaskSchedulerState{
+		TaskID:          ts.task.ID,
+		OrganizationID:  ts.task.OrganizationID,
+		AuthorizationID: ts.task.AuthorizationID,
+		Name:            ts.task.Name,
+		Status:          status,
+		ClaimedAt:       ts.claimedAt,
+		NextDue:         next,
+		HasQueue:        hasQueue,
+		RunsActive:      active,
+		MaxConcurrency:  maxC,
+	}
+}
+
 // A runner is one eligible "concurrency slot" for a given task.
 type runner struct {
 	state *uint32
@@ -708,12 +829,18 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 	defer r.wg.Done()
 	errMsg := "Failed to finish run"
 	defer func() {
-		if _, err := r.taskControlService.FinishRun(r.ctx, qr.TaskID, qr.RunID); err != nil {
+		run, err := r.taskControlService.FinishRun(r.ctx, qr.TaskID, qr.RunID)
+		if err != nil {
 			// TODO(mr): Need to figure out how to reconcile this error, on the next run, if it happens.
 
 			runLogger.Error(errMsg, zap.Error(err))
 
 			atomic.StoreUint32(r.state, runnerIdle)
+			return
+		}
+		if run != nil && run.Status == RunSuccess.String() && r.ts.task.RunsOnce() {
+			// The run completed the task, which has no more runs to schedule.
+			r.ts.release()
 		}
 	}()
 
@@ -20,6 +20,9 @@ type schedulerMetrics struct {
 	claimsActive   prometheus.Gauge
 
 	queueDelta prometheus.Summary
+
+	tickLatency prometheus.Summary
+	backlog     prometheus.Gauge
 }
 
 func newSchedulerMetrics() *schedulerMetrics {
@@ -72,6 +75,20 @@ func newSchedulerMetrics() *schedulerMetrics {
 			Help:       "The duration in seconds between a run being due to start and actually starting.",
 			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
 		}),
+
+		tickLatency: prometheus.NewSummary(prometheus.SummaryOpts{
+			Namespace:  namespace,
+			Subsystem:  subsystem,
+			Name:       "tick_latency",
+			Help:       "The duration in seconds between the second a tick is for and the tick finishing.",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+		}),
+		backlog: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "backlog",
+			Help:      "Number of claimed tasks that were due but could not start a run as of the last tick.",
+		}),
 	}
 }
 
@@ -85,6 +102,8 @@ func (sm *schedulerMetrics) PrometheusCollectors() []prometheus.Collector {
 		sm.claimsComplete,
 		sm.claimsActive,
 		sm.queueDelta,
+		sm.tickLatency,
+		sm.backlog,
 	}
 }
 
@@ -126,6 +145,12 @@ func (sm *schedulerMetrics) ReleaseTask(tid string) {
 	sm.runsComplete.DeleteLabelValues(tid, statusString(false))
 }
 
+// Tick records the latency of a tick and the backlog left after it.
+func (sm *schedulerMetrics) Tick(latency time.Duration, backlog int) {
+	sm.tickLatency.Observe(latency.Seconds())
+	sm.backlog.Set(float64(backlog))
+}
+
 func statusString(succeeded bool) string {
 	if succeeded {
 		return "success"
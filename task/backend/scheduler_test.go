@@ -1018,3 +1018,92 @@ func TestScheduler_WithTicker(t *testing.T) {
 		t.Fatalf("expected 1 run queued, but got %d", len(x))
 	}
 }
+
+func TestScheduler_SchedulerState(t *testing.T) {
+	t.Parallel()
+
+	tcs := mock.NewTaskControlService()
+	e := mock.NewExecutor()
+	s := backend.NewScheduler(tcs, e, 5, backend.WithInstanceID("node-1"))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	active := &platform.Task{
+		ID:              platform.ID(1),
+		OrganizationID:  platform.ID(10),
+		AuthorizationID: platform.ID(20),
+		Name:            "x",
+		Every:           "1s",
+		LatestCompleted: "1970-01-01T00:00:05Z",
+		Flux:            `option task = {concurrency: 1, name:"x", every:1m} from(bucket:"a") |> to(bucket:"b", org: "o")`,
+	}
+	inactive := &platform.Task{
+		ID:              platform.ID(2),
+		Name:            "y",
+		Every:           "1s",
+		LatestCompleted: "1970-01-01T00:00:05Z",
+		Status:          platform.TaskStatusInactive,
+		Flux:            `option task = {concurrency: 1, name:"y", every:1m} from(bucket:"a") |> to(bucket:"b", org: "o")`,
+	}
+	for _, task := range []*platform.Task{inactive, active} {
+		tcs.SetTask(task)
+		if err := s.ClaimTask(context.Background(), task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkStatus := func(t *testing.T, backlog int, statuses ...string) *platform.SchedulerState {
+		t.Helper()
+
+		state, err := s.SchedulerState(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.InstanceID != "node-1" {
+			t.Errorf("unexpected instance ID %q", state.InstanceID)
+		}
+		if state.Backlog != backlog {
+			t.Errorf("expected backlog of %d, got %d", backlog, state.Backlog)
+		}
+		if len(state.Tasks) != len(statuses) {
+			t.Fatalf("expected %d tasks, got %d", len(statuses), len(state.Tasks))
+		}
+		for i, status := range statuses {
+			if got := state.Tasks[i].Status; got != status {
+				t.Errorf("expected task %s to be %q, got %q", state.Tasks[i].TaskID, status, got)
+			}
+		}
+		return state
+	}
+
+	state := checkStatus(t, 0, platform.TaskSchedulerStatusWaiting, platform.TaskSchedulerStatusInactive)
+	if !state.LastTick.IsZero() {
+		t.Errorf("expected no tick yet, got %v", state.LastTick)
+	}
+	task := state.Tasks[0]
+	if task.TaskID != active.ID || task.OrganizationID != active.OrganizationID || task.AuthorizationID != active.AuthorizationID || task.Name != active.Name {
+		t.Errorf("unexpected task state %+v", task)
+	}
+	if task.ClaimedBy != "node-1" || task.ClaimedAt.IsZero() {
+		t.Errorf("unexpected claim %q at %v", task.ClaimedBy, task.ClaimedAt)
+	}
+	if task.NextDue.Unix() != 6 || task.MaxConcurrency != 1 || task.RunsActive != 0 {
+		t.Errorf("unexpected schedule %+v", task)
+	}
+
+	s.Tick(6)
+	if _, err := e.PollForNumberRunning(active.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	state = checkStatus(t, 0, platform.TaskSchedulerStatusRunning, platform.TaskSchedulerStatusInactive)
+	if state.Now.Unix() != 6 || state.LastTick.IsZero() || state.TickLatency <= 0 {
+		t.Errorf("unexpected tick stats %+v", state)
+	}
+	if task := state.Tasks[0]; task.NextDue.Unix() != 7 || task.RunsActive != 1 {
+		t.Errorf("unexpected schedule %+v", task)
+	}
+
+	// The only concurrency slot is busy, so the run due at 7 can't start.
+	s.Tick(7)
+	checkStatus(t, 1, platform.TaskSchedulerStatusBlocked, platform.TaskSchedulerStatusInactive)
+}
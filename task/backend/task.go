@@ -3,6 +3,7 @@ package backend
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/influxdata/influxdb"
@@ -43,6 +44,10 @@ type TaskControlService interface {
 	AddRunLog(ctx context.Context, taskID, runID influxdb.ID, when time.Time, log string) error
 }
 
+// RunNeverDue is the next due time of a task that has no more scheduled runs,
+// which is a task that runs once after its run has been created.
+const RunNeverDue int64 = math.MaxInt64
+
 type TaskStatus string
 
 const (
@@ -36,6 +36,10 @@ type Options struct {
 	// this can be unmarshaled from json as a string i.e.: "1d" will unmarshal as 1 day
 	Offset *Duration `json:"offset,omitempty"`
 
+	// At is the single time a task that runs once is scheduled for, which can
+	// be used in place of Every and Cron.
+	At *time.Time `json:"at,omitempty"`
+
 	Concurrency *int64 `json:"concurrency,omitempty"`
 
 	Retry *int64 `json:"retry,omitempty"`
@@ -126,6 +130,7 @@ func (o *Options) Clear() {
 	o.Cron = ""
 	o.Every = Duration{}
 	o.Offset = nil
+	o.At = nil
 	o.Concurrency = nil
 	o.Retry = nil
 }
@@ -136,6 +141,7 @@ func (o *Options) IsZero() bool {
 		o.Cron == "" &&
 		o.Every.IsZero() &&
 		o.Offset == nil &&
+		o.At == nil &&
 		o.Concurrency == nil &&
 		o.Retry == nil
 }
@@ -146,6 +152,7 @@ const (
 	optCron        = "cron"
 	optEvery       = "every"
 	optOffset      = "offset"
+	optAt          = "at"
 	optConcurrency = "concurrency"
 	optRetry       = "retry"
 )
@@ -196,12 +203,68 @@ func grabTaskOptionAST(p *ast.Package, keys ...string) map[string]ast.Expression
 
 // FromScript extracts Options from a Flux script.
 func FromScript(script string) (Options, error) {
-	opt := Options{Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}
-
 	fluxAST, err := flux.Parse(script)
+	if err != nil {
+		return Options{Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}, err
+	}
+
+	opt, err := fromAST(fluxAST)
 	if err != nil {
 		return opt, err
 	}
+
+	if err := opt.Validate(); err != nil {
+		return opt, err
+	}
+
+	return opt, nil
+}
+
+// Parse extracts Options from a Flux script like FromScript, but reports every
+// syntax error of the script, or every invalid option, instead of the first.
+// The errors are located in the script where possible.
+func Parse(script string) (Options, []Error) {
+	pkg := parser.ParseSource(script)
+	if ast.Check(pkg) > 0 {
+		var errs []Error
+		ast.Walk(ast.CreateVisitor(func(node ast.Node) {
+			loc := node.Location()
+			for _, err := range node.Errs() {
+				errs = append(errs, Error{
+					Line:    loc.Start.Line,
+					Column:  loc.Start.Column,
+					Message: err.Msg,
+				})
+			}
+		}), pkg)
+		return Options{}, errs
+	}
+
+	opt, err := fromAST(pkg)
+	if err != nil {
+		return opt, []Error{{Message: err.Error()}}
+	}
+
+	errs, err := opt.validate(time.Now())
+	if err != nil {
+		return opt, []Error{{Message: err.Error()}}
+	}
+
+	exprs := grabTaskOptionAST(pkg, optName, optCron, optEvery, optOffset, optAt, optConcurrency, optRetry)
+	for i := range errs {
+		if expr, ok := exprs[errs[i].Option]; ok && expr != nil {
+			loc := expr.Location()
+			errs[i].Line = loc.Start.Line
+			errs[i].Column = loc.Start.Column
+		}
+	}
+	return opt, errs
+}
+
+// fromAST extracts Options from a parsed Flux script, without validating them.
+func fromAST(fluxAST *ast.Package) (Options, error) {
+	opt := Options{Retry: pointer.Int64(1), Concurrency: pointer.Int64(1)}
+
 	durTypes := grabTaskOptionAST(fluxAST, optEvery, optOffset)
 	_, scope, err := flux.EvalAST(fluxAST)
 	if err != nil {
@@ -233,12 +296,24 @@ func FromScript(script string) (Options, error) {
 	opt.Name = nameVal.Str()
 	crVal, cronOK := optObject.Get(optCron)
 	everyVal, everyOK := optObject.Get(optEvery)
+	atVal, atOK := optObject.Get(optAt)
 	if cronOK && everyOK {
 		return opt, ErrDuplicateIntervalField
 	}
+	if atOK && (cronOK || everyOK) {
+		return opt, ErrDuplicateScheduleField
+	}
+
+	if !cronOK && !everyOK && !atOK {
+		return opt, ErrMissingRequiredTaskOption("cron, every or at is required")
+	}
 
-	if !cronOK && !everyOK {
-		return opt, ErrMissingRequiredTaskOption("cron or every is required")
+	if atOK {
+		if err := checkNature(atVal.PolyType().Nature(), semantic.Time); err != nil {
+			return opt, err
+		}
+		at := atVal.Time().Time().UTC()
+		opt.At = &at
 	}
 
 	if cronOK {
@@ -301,78 +376,107 @@ func FromScript(script string) (Options, error) {
 		opt.Retry = pointer.Int64(retryVal.Int())
 	}
 
-	if err := opt.Validate(); err != nil {
-		return opt, err
-	}
-
 	return opt, nil
 }
 
+// Error is an error in the task options of a Flux script. Line and Column are
+// the location of the error in the script, or 0 if it is unknown.
+type Error struct {
+	// Option is the name of the invalid option, if the error is about one.
+	Option  string `json:"option,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
 // Validate returns an error if the options aren't valid.
 func (o *Options) Validate() error {
-	now := time.Now()
-	var errs []string
+	errs, err := o.validate(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Message
+	}
+	return fmt.Errorf("invalid options: %s", strings.Join(msgs, ", "))
+}
+
+// validate returns an error for each invalid option.
+func (o *Options) validate(now time.Time) ([]Error, error) {
+	var errs []Error
 	if o.Name == "" {
-		errs = append(errs, "name required")
+		errs = append(errs, Error{Option: optName, Message: "name required"})
 	}
 
 	cronPresent := o.Cron != ""
 	everyPresent := !o.Every.IsZero()
-	if cronPresent == everyPresent {
+	if o.At != nil {
+		if cronPresent || everyPresent {
+			errs = append(errs, Error{Option: optAt, Message: "at can not be specified with cron or every"})
+		}
+		if o.Offset != nil {
+			errs = append(errs, Error{Option: optOffset, Message: "offset can not be specified with at"})
+		}
+		if !o.At.Truncate(time.Second).Equal(*o.At) {
+			errs = append(errs, Error{Option: optAt, Message: "at option must be expressible as whole seconds"})
+		}
+	} else if cronPresent == everyPresent {
 		// They're both present or both missing.
-		errs = append(errs, "must specify exactly one of either cron or every")
+		errs = append(errs, Error{Message: "must specify exactly one of either cron or every"})
 	} else if cronPresent {
 		_, err := cron.Parse(o.Cron)
 		if err != nil {
-			errs = append(errs, "cron invalid: "+err.Error())
+			errs = append(errs, Error{Option: optCron, Message: "cron invalid: " + err.Error()})
 		}
 	} else if everyPresent {
 		every, err := o.Every.DurationFrom(now)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if every < time.Second {
-			errs = append(errs, "every option must be at least 1 second")
+			errs = append(errs, Error{Option: optEvery, Message: "every option must be at least 1 second"})
 		} else if every.Truncate(time.Second) != every {
-			errs = append(errs, "every option must be expressible as whole seconds")
+			errs = append(errs, Error{Option: optEvery, Message: "every option must be expressible as whole seconds"})
 		}
 	}
 	if o.Offset != nil {
 		offset, err := o.Offset.DurationFrom(now)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if offset.Truncate(time.Second) != offset {
 			// For now, allowing negative offset delays. Maybe they're useful for forecasting?
-			errs = append(errs, "offset option must be expressible as whole seconds")
+			errs = append(errs, Error{Option: optOffset, Message: "offset option must be expressible as whole seconds"})
 		}
 	}
 	if o.Concurrency != nil {
 		if *o.Concurrency < 1 {
-			errs = append(errs, "concurrency must be at least 1")
+			errs = append(errs, Error{Option: optConcurrency, Message: "concurrency must be at least 1"})
 		} else if *o.Concurrency > maxConcurrency {
-			errs = append(errs, fmt.Sprintf("concurrency exceeded max of %d", maxConcurrency))
+			errs = append(errs, Error{Option: optConcurrency, Message: fmt.Sprintf("concurrency exceeded max of %d", maxConcurrency)})
 		}
 	}
 	if o.Retry != nil {
 		if *o.Retry < 1 {
-			errs = append(errs, "retry must be at least 1")
+			errs = append(errs, Error{Option: optRetry, Message: "retry must be at least 1"})
 		} else if *o.Retry > maxRetry {
-			errs = append(errs, fmt.Sprintf("retry exceeded max of %d", maxRetry))
+			errs = append(errs, Error{Option: optRetry, Message: fmt.Sprintf("retry exceeded max of %d", maxRetry)})
 		}
 	}
 
-	if len(errs) == 0 {
-		return nil
-	}
-
-	return fmt.Errorf("invalid options: %s", strings.Join(errs, ", "))
+	return errs, nil
 }
 
 // EffectiveCronString returns the effective cron string of the options.
 // If the cron option was specified, it is returned.
 // If the every option was specified, it is converted into a cron string using "@every".
-// Otherwise, the empty string is returned.
+// Otherwise, the empty string is returned, which includes a task that runs once at a time.
 // The value of the offset option is not considered.
 // TODO(docmerlin): create an EffectiveCronStringFrom(t time.Time) string,
 // that works from a unit of time.
@@ -402,7 +506,7 @@ func validateOptionNames(o values.Object) error {
 	var unexpected []string
 	o.Range(func(name string, _ values.Value) {
 		switch name {
-		case optName, optCron, optEvery, optOffset, optConcurrency, optRetry:
+		case optName, optCron, optEvery, optOffset, optAt, optConcurrency, optRetry:
 			// Known option. Nothing to do.
 		default:
 			unexpected = append(unexpected, name)
@@ -411,7 +515,7 @@ func validateOptionNames(o values.Object) error {
 
 	if len(unexpected) > 0 {
 		u := strings.Join(unexpected, ", ")
-		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optConcurrency, optRetry}, ", ")
+		v := strings.Join([]string{optName, optCron, optEvery, optOffset, optAt, optConcurrency, optRetry}, ", ")
 		return fmt.Errorf("unknown task option(s): %s. valid options are %s", u, v)
 	}
 
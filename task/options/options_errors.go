@@ -14,4 +14,5 @@ func ErrMissingRequiredTaskOption(opt string) error {
 
 var (
 	ErrDuplicateIntervalField = fmt.Errorf("cannot use both cron and every in task options")
+	ErrDuplicateScheduleField = fmt.Errorf("cannot use at with cron or every in task options")
 )
@@ -27,6 +27,9 @@ func scriptGenerator(opt options.Options, body string) string {
 	if opt.Offset != nil && !(*opt.Offset).IsZero() {
 		taskData = fmt.Sprintf("%s  offset: %s,\n", taskData, opt.Offset.String())
 	}
+	if opt.At != nil {
+		taskData = fmt.Sprintf("%s  at: %s,\n", taskData, opt.At.Format(time.RFC3339Nano))
+	}
 	if opt.Concurrency != nil && *opt.Concurrency != 0 {
 		taskData = fmt.Sprintf("%s  concurrency: %d,\n", taskData, *opt.Concurrency)
 	}
@@ -57,6 +60,8 @@ func TestNegDurations(t *testing.T) {
 }
 
 func TestFromScript(t *testing.T) {
+	at := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	atFraction := at.Add(500 * time.Millisecond)
 	for _, c := range []struct {
 		script    string
 		exp       options.Options
@@ -78,6 +83,12 @@ func TestFromScript(t *testing.T) {
 		{script: "option task = {\n  name: \"name8\",\n  retry: 0,\n  every: 1m0s,\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 		{script: scriptGenerator(options.Options{Name: "name9"}, ""), shouldErr: true},
 		{script: scriptGenerator(options.Options{}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name10", At: &at}, ""), exp: options.Options{Name: "name10", At: &at, Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}},
+		{script: scriptGenerator(options.Options{Name: "name11", At: &at, Every: *(options.MustParseDuration("1h"))}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name12", At: &at, Cron: "* * * * *"}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name13", At: &at, Offset: options.MustParseDuration("1m")}, ""), shouldErr: true},
+		{script: scriptGenerator(options.Options{Name: "name14", At: &atFraction}, ""), shouldErr: true},
+		{script: "option task = {\n  name: \"name15\",\n  at: \"2019-06-01T00:00:00Z\",\n\n}\n\nfrom(bucket: \"test\")\n    |> range(start:-1h)", shouldErr: true},
 	} {
 		o, err := options.FromScript(c.script)
 		if c.shouldErr && err == nil {
@@ -122,7 +133,7 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 		t.Errorf("expected error to mention unrecognized options, but it said: %v", err)
 	}
 
-	validOpts := []string{"name", "cron", "every", "offset", "concurrency", "retry"}
+	validOpts := []string{"name", "cron", "every", "offset", "at", "concurrency", "retry"}
 	for _, o := range validOpts {
 		if !strings.Contains(msg, o) {
 			t.Errorf("expected error to mention valid option %q but it said: %v", o, err)
@@ -130,6 +141,68 @@ func TestFromScriptWithUnknownOptions(t *testing.T) {
 	}
 }
 
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		script  string
+		exp     options.Options
+		expErrs []options.Error
+	}{
+		{
+			name:   "valid",
+			script: "option task = {name: \"a\", every: 1h, offset: 10m}\nfrom(bucket: \"b\") |> range(start: -1h)",
+			exp:    options.Options{Name: "a", Every: *options.MustParseDuration("1h"), Offset: options.MustParseDuration("10m"), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)},
+		},
+		{
+			name:   "syntax error",
+			script: "option task = {name: \"a\", every: 1h\nfrom(bucket: \"b\")",
+			expErrs: []options.Error{
+				{Line: 1, Column: 15, Message: "expected RBRACE, got EOF"},
+				{Line: 1, Column: 34, Message: "expected an operator between two expressions"},
+			},
+		},
+		{
+			name:    "missing option",
+			script:  "option task = {every: 1h}",
+			expErrs: []options.Error{{Message: "missing required option: name"}},
+		},
+		{
+			name:   "invalid options",
+			script: "option task = {\n  name: \"a\",\n  every: 1500ms,\n  retry: 0,\n}",
+			expErrs: []options.Error{
+				{Option: "every", Line: 3, Column: 10, Message: "every option must be expressible as whole seconds"},
+				{Option: "retry", Line: 4, Column: 10, Message: "retry must be at least 1"},
+			},
+		},
+		{
+			name:   "at",
+			script: "option task = {name: \"a\", at: 2019-06-01T12:00:00+02:00}\nfrom(bucket: \"b\") |> range(start: -1h)",
+			exp:    options.Options{Name: "a", At: timePtr(time.Date(2019, 6, 1, 10, 0, 0, 0, time.UTC)), Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)},
+		},
+		{
+			name:   "invalid at",
+			script: "option task = {\n  name: \"a\",\n  at: 2019-06-01T00:00:00.5Z,\n}",
+			expErrs: []options.Error{
+				{Option: "at", Line: 3, Column: 7, Message: "at option must be expressible as whole seconds"},
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			o, errs := options.Parse(c.script)
+			if !cmp.Equal(errs, c.expErrs) {
+				t.Fatalf("got unexpected errors -got/+exp\n%s", cmp.Diff(errs, c.expErrs))
+			}
+			if len(errs) == 0 && !cmp.Equal(o, c.exp) {
+				t.Fatalf("got unexpected options -got/+exp\n%s", cmp.Diff(o, c.exp))
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
 func TestValidate(t *testing.T) {
 	good := options.Options{Name: "x", Cron: "* * * * *", Concurrency: pointer.Int64(1), Retry: pointer.Int64(1)}
 	if err := good.Validate(); err != nil {
@@ -83,6 +83,11 @@ func TestTaskService(t *testing.T, fn BackendComponentFactory, testCategory ...s
 					t.Parallel()
 					testManualRun(t, sys)
 				})
+
+				t.Run("Task Run Once", func(t *testing.T) {
+					t.Parallel()
+					testRunOnce(t, sys)
+				})
 			})
 		case "analytical":
 			t.Run("AnalyticalTaskService", func(t *testing.T) {
@@ -713,18 +718,21 @@ func testTaskRuns(t *testing.T, sys *System) {
 		}
 
 		const scheduledFor = 77
-		r, err := sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor)
+		r, err := sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor, "backfill after outage")
 		if err != nil {
 			t.Fatal(err)
 		}
 		if r.ScheduledFor != "1970-01-01T00:01:17Z" {
 			t.Fatalf("expected: 1970-01-01T00:01:17Z, got %s", r.ScheduledFor)
 		}
+		if r.Annotation != "backfill after outage" {
+			t.Fatalf("expected annotation to be recorded, got %q", r.Annotation)
+		}
 
 		// TODO(lh): Once we have moved over to kv we can list runs and see the manual queue in the list
 
 		// Forcing the same run before it's executed should be rejected.
-		if _, err = sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor); err == nil {
+		if _, err = sys.TaskService.ForceRun(sys.Ctx, task.ID, scheduledFor, ""); err == nil {
 			t.Fatalf("subsequent force should have been rejected; failed to error: %s", task.ID)
 		}
 	})
@@ -1002,7 +1010,7 @@ func testManualRun(t *testing.T, s *System) {
 	}
 	scheduledFor := time.Now().UTC()
 
-	run, err := s.TaskService.ForceRun(authorizedCtx, tsk.ID, scheduledFor.Unix())
+	run, err := s.TaskService.ForceRun(authorizedCtx, tsk.ID, scheduledFor.Unix(), "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1024,6 +1032,98 @@ func testManualRun(t *testing.T, s *System) {
 	}
 }
 
+func testRunOnce(t *testing.T, s *System) {
+	cr := creds(t, s)
+	authorizedCtx := icontext.SetAuthorizer(s.Ctx, cr.Authorizer())
+	at := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+
+	runOnce := func(t *testing.T, deleteAfterSuccess bool) *influxdb.Task {
+		t.Helper()
+
+		tsk, err := s.TaskService.CreateTask(authorizedCtx, influxdb.TaskCreate{
+			OrganizationID:     cr.OrgID,
+			Flux:               fmt.Sprintf(scriptOnceFmt, t.Name(), at.Format(time.RFC3339)),
+			Token:              cr.Token,
+			DeleteAfterSuccess: deleteAfterSuccess,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tsk.At != at.Format(time.RFC3339) {
+			t.Fatalf("expected task to run once at %s, got %q", at.Format(time.RFC3339), tsk.At)
+		}
+		if tsk.Every != "" || tsk.Cron != "" {
+			t.Fatalf("expected task that runs once to have no every or cron, got %q and %q", tsk.Every, tsk.Cron)
+		}
+
+		if _, err := s.TaskControlService.CreateNextRun(s.Ctx, tsk.ID, at.Add(-time.Second).Unix()); err == nil {
+			t.Fatal("expected run not to be due before the at time of the task")
+		}
+
+		rc, err := s.TaskControlService.CreateNextRun(s.Ctx, tsk.ID, time.Now().Unix())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rc.Created.Now != at.Unix() {
+			t.Fatalf("expected run to be scheduled for %d, got %d", at.Unix(), rc.Created.Now)
+		}
+		if rc.NextDue != backend.RunNeverDue {
+			t.Fatalf("expected no run to be due after the run once, got %d", rc.NextDue)
+		}
+
+		if _, err := s.TaskControlService.CreateNextRun(s.Ctx, tsk.ID, time.Now().Unix()); err == nil {
+			t.Fatal("expected the run of the task to be created only once")
+		}
+		if due, err := s.TaskControlService.NextDueRun(s.Ctx, tsk.ID); err != nil {
+			t.Fatal(err)
+		} else if due != backend.RunNeverDue {
+			t.Fatalf("expected no run to be due, got %d", due)
+		}
+
+		if err := s.TaskControlService.UpdateRunState(s.Ctx, tsk.ID, rc.Created.RunID, time.Now(), backend.RunStarted); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.TaskControlService.UpdateRunState(s.Ctx, tsk.ID, rc.Created.RunID, time.Now(), backend.RunSuccess); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.TaskControlService.FinishRun(s.Ctx, tsk.ID, rc.Created.RunID); err != nil {
+			t.Fatal(err)
+		}
+		return tsk
+	}
+
+	t.Run("inactive after success", func(t *testing.T) {
+		tsk := runOnce(t, false)
+
+		found, err := s.TaskService.FindTaskByID(authorizedCtx, tsk.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Status != string(backend.TaskInactive) {
+			t.Fatalf("expected task to be inactive after its run succeeded, got %q", found.Status)
+		}
+
+		// A new time schedules the task again.
+		newAt := at.Add(time.Hour)
+		if _, err := s.TaskService.UpdateTask(authorizedCtx, tsk.ID, influxdb.TaskUpdate{Options: options.Options{At: &newAt}}); err != nil {
+			t.Fatal(err)
+		}
+		if due, err := s.TaskControlService.NextDueRun(s.Ctx, tsk.ID); err != nil {
+			t.Fatal(err)
+		} else if due != newAt.Unix() {
+			t.Fatalf("expected run to be due at %d, got %d", newAt.Unix(), due)
+		}
+	})
+
+	t.Run("deleted after success", func(t *testing.T) {
+		tsk := runOnce(t, true)
+
+		if _, err := s.TaskService.FindTaskByID(authorizedCtx, tsk.ID); err == nil {
+			t.Fatal("expected task to be deleted after its run succeeded")
+		}
+	})
+}
+
 func testRunStorage(t *testing.T, sys *System) {
 	cr := creds(t, sys)
 
@@ -1189,7 +1289,7 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 		t.Fatal(err)
 	}
 	// Non-existent ID should return the right error.
-	_, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, influxdb.ID(math.MaxUint64))
+	_, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, influxdb.ID(math.MaxUint64), "")
 	if !strings.Contains(err.Error(), "run not found") {
 		t.Errorf("expected retrying run that doesn't exist to return %v, got %v", influxdb.ErrRunNotFound, err)
 	}
@@ -1218,13 +1318,16 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 	}
 
 	// Now retry the run.
-	m, err := sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID)
+	m, err := sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID, "flaky source")
 	if err != nil {
 		t.Fatal(err)
 	}
 	if m.TaskID != task.ID {
 		t.Fatalf("wrong task ID on retried run: got %s, want %s", m.TaskID, task.ID)
 	}
+	if m.Annotation != "flaky source" {
+		t.Fatalf("wrong annotation on retried run: got %q, want %q", m.Annotation, "flaky source")
+	}
 	if m.Status != "scheduled" {
 		t.Fatal("expected new retried run to have status of scheduled")
 	}
@@ -1239,7 +1342,7 @@ func testRetryAcrossStorage(t *testing.T, sys *System) {
 	exp := backend.RequestStillQueuedError{Start: rc.Created.Now, End: rc.Created.Now}
 
 	// Retrying a run which has been queued but not started, should be rejected.
-	if _, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID); err != exp && err.Error() != "<conflict> run already queued" {
+	if _, err = sys.TaskService.RetryRun(sys.Ctx, task.ID, rc.Created.RunID, ""); err != exp && err.Error() != "<conflict> run already queued" {
 		t.Fatalf("subsequent retry should have been rejected with %v; got %v", exp, err)
 	}
 }
@@ -1410,6 +1513,16 @@ option task = {
 	concurrency: 100,
 }
 
+from(bucket:"b")
+	|> http.to(url: "http://example.com")`
+
+	scriptOnceFmt = `import "http"
+
+option task = {
+	name: "%s",
+	at: %s,
+}
+
 from(bucket:"b")
 	|> http.to(url: "http://example.com")`
 
@@ -0,0 +1,1339 @@
+// Package tickscript converts Kapacitor TICKscripts into Flux tasks.
+//
+// The conversion is best-effort: it covers the stream and batch pipelines that
+// select, aggregate and alert on data, and reports every construct it can't reproduce.
+// Alert nodes also become the checks and notification rules that alert on the data
+// once the task is replaced by them.
+package tickscript
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxql"
+)
+
+// DefaultEvery is how often a task converted from a stream pipeline without a window runs.
+const DefaultEvery = "1m"
+
+// Options configure a conversion.
+type Options struct {
+	// Name is the name of the task.
+	Name string
+	// Org is the name of the organization the task writes to.
+	Org string
+	// AlertBucket is the bucket the task writes the levels of alerts to.
+	AlertBucket string
+}
+
+// A Conversion is a TICKscript converted to a Flux task.
+type Conversion struct {
+	// Flux is the script of the task.
+	Flux string `json:"flux"`
+	// Checks are the threshold checks of the alert nodes. They have no
+	// organization until they are created.
+	Checks []*influxdb.Check `json:"checks"`
+	// NotificationRules are the notification rules of the handlers of the alert nodes.
+	NotificationRules []*NotificationRule `json:"notificationRules"`
+	// Unconverted lists the constructs of the TICKscript the task doesn't reproduce.
+	Unconverted []Unconverted `json:"unconverted"`
+}
+
+// A NotificationRule is a notification rule converted from a handler of an alert node.
+// Its check and endpoint are given by the name of the check and the type of the
+// endpoint, since neither exists yet; their IDs are set when the rule is created.
+type NotificationRule struct {
+	Rule  *influxdb.NotificationRule `json:"rule"`
+	Check string                     `json:"check"`
+	// EndpointType is the type of endpoint the handler notifies.
+	EndpointType influxdb.NotificationEndpointType `json:"endpointType"`
+	// EndpointURL is the URL the handler posts to, if it names one.
+	EndpointURL string `json:"endpointURL,omitempty"`
+}
+
+// Unconverted is a construct of a TICKscript that has no equivalent in the converted task.
+type Unconverted struct {
+	Line      int    `json:"line"`
+	Construct string `json:"construct"`
+	Reason    string `json:"reason"`
+}
+
+// aggregates maps the aggregating nodes of TICKscript to whether they select a point,
+// and so keep its time, rather than compute a new one.
+var aggregates = map[string]bool{
+	"count":  false,
+	"mean":   false,
+	"median": false,
+	"spread": false,
+	"stddev": false,
+	"sum":    false,
+	"first":  true,
+	"last":   true,
+	"max":    true,
+	"min":    true,
+}
+
+// alertLevels are the levels of an alert node, from the most severe.
+var alertLevels = []string{"crit", "warn", "info"}
+
+// checkLevels maps the levels of alert nodes to the levels of checks.
+var checkLevels = map[string]influxdb.CheckLevel{
+	"crit": influxdb.CheckLevelCrit,
+	"warn": influxdb.CheckLevelWarn,
+	"info": influxdb.CheckLevelInfo,
+}
+
+// alertHandlers maps the handlers of alert nodes to the type of endpoint their
+// notification rules notify, which is empty for handlers no endpoint replaces.
+var alertHandlers = map[string]influxdb.NotificationEndpointType{
+	"slack":      influxdb.SlackNotificationEndpoint,
+	"pagerDuty":  influxdb.PagerDutyNotificationEndpoint,
+	"pagerDuty2": influxdb.PagerDutyNotificationEndpoint,
+	"post":       influxdb.HTTPNotificationEndpoint,
+	"alerta":     "",
+	"bigPanda":   "",
+	"discord":    "",
+	"email":      "",
+	"exec":       "",
+	"hipChat":    "",
+	"kafka":      "",
+	"log":        "",
+	"mqtt":       "",
+	"opsGenie":   "",
+	"opsGenie2":  "",
+	"pushover":   "",
+	"sensu":      "",
+	"serviceNow": "",
+	"snmpTrap":   "",
+	"tcp":        "",
+	"teams":      "",
+	"telegram":   "",
+	"victorOps":  "",
+	"zenoss":     "",
+}
+
+// Convert converts script into a Flux task.
+// Within where nodes that precede any aggregation, references are taken to be tags;
+// after aggregation, the aggregated value is the only field.
+func Convert(script string, opts Options) (*Conversion, error) {
+	if opts.Name == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "task name is required",
+		}
+	}
+	if opts.Org == "" {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "organization is required",
+		}
+	}
+
+	prog, err := parse(script)
+	if err != nil {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "failed to parse TICKscript",
+			Err:  err,
+		}
+	}
+
+	c := &converter{
+		opts:        opts,
+		vars:        make(map[string]expr),
+		checks:      []*influxdb.Check{},
+		rules:       []*NotificationRule{},
+		unconverted: []Unconverted{},
+	}
+	if len(prog.dbrps) > 0 {
+		c.dbrp = &prog.dbrps[0]
+	}
+	for i := 1; i < len(prog.dbrps); i++ {
+		d := prog.dbrps[i]
+		c.report(0, fmt.Sprintf("dbrp %q.%q", d.db, d.rp), "only the first dbrp statement is used")
+	}
+
+	pipelines := c.pipelines(prog)
+	if len(pipelines) == 0 {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "TICKscript has no pipeline",
+		}
+	}
+
+	var (
+		body  strings.Builder
+		every string
+	)
+	for i, nodes := range pipelines {
+		name := "data"
+		if len(pipelines) > 1 {
+			name = fmt.Sprintf("data_%d", i+1)
+		}
+
+		p, err := c.convertPipeline(nodes)
+		if err != nil {
+			return nil, err
+		}
+
+		if every == "" {
+			every = p.every
+		} else if p.every != every {
+			c.report(p.line, name, fmt.Sprintf("the task runs every %s, not every %s", every, p.every))
+		}
+		if p.alert != nil {
+			checkName := opts.Name
+			if len(pipelines) > 1 {
+				checkName = opts.Name + " " + name
+			}
+			c.convertCheck(p, checkName)
+		}
+		body.WriteString("\n")
+		p.render(&body, name, len(pipelines) > 1)
+	}
+
+	conv := &Conversion{
+		Flux:              fmt.Sprintf("option task = {name: %s, every: %s}\n", fluxString(opts.Name), every) + body.String(),
+		Checks:            c.checks,
+		NotificationRules: c.rules,
+		Unconverted:       c.unconverted,
+	}
+	sort.SliceStable(conv.Unconverted, func(i, j int) bool {
+		return conv.Unconverted[i].Line < conv.Unconverted[j].Line
+	})
+	return conv, nil
+}
+
+type converter struct {
+	opts        Options
+	dbrp        *dbrp
+	vars        map[string]expr
+	checks      []*influxdb.Check
+	rules       []*NotificationRule
+	unconverted []Unconverted
+}
+
+func (c *converter) report(line int, construct, reason string) {
+	c.unconverted = append(c.unconverted, Unconverted{Line: line, Construct: construct, Reason: reason})
+}
+
+// pipelines returns the nodes of each chain of prog that no other chain continues,
+// prefixed with the nodes of the chains they continue.
+func (c *converter) pipelines(prog *program) [][]*node {
+	continued := make(map[string]bool)
+	for _, s := range prog.statements {
+		if s.name != "" {
+			c.vars[s.name] = s.value
+		}
+		if ch, ok := s.value.(*chain); ok {
+			continued[ch.source] = true
+		}
+	}
+
+	var pipelines [][]*node
+	for _, s := range prog.statements {
+		ch, ok := s.value.(*chain)
+		if !ok {
+			continue
+		}
+		if s.name != "" && continued[s.name] {
+			continue
+		}
+
+		var nodes []*node
+		for ch != nil {
+			nodes = append(append([]*node(nil), ch.nodes...), nodes...)
+			if ch.source == "stream" || ch.source == "batch" {
+				nodes = append([]*node{{name: ch.source, line: ch.line}}, nodes...)
+				break
+			}
+			next, _ := c.vars[ch.source].(*chain)
+			if next == nil || next == ch {
+				c.report(ch.line, ch.source, "the chain doesn't start with stream or batch")
+				nodes = nil
+				break
+			}
+			ch = next
+		}
+		if nodes != nil {
+			pipelines = append(pipelines, nodes)
+		}
+	}
+	return pipelines
+}
+
+// A pipeline is the Flux equivalent of a TICKscript pipeline.
+type pipeline struct {
+	line        int
+	bucket      string
+	measurement string
+	period      string
+	every       string
+	// filters are the predicates the data is filtered with before aggregation.
+	filters  []string
+	field    string
+	groupBy  []string
+	groupAll bool
+	// aggregate is the Flux function that aggregates the data, if any.
+	aggregate string
+	selector  bool
+	// valueName is the name TICKscript gives the aggregated value.
+	valueName string
+	// after are the predicates the data is filtered with after aggregation.
+	after []string
+	// outputs are the steps each branch of the pipeline applies to the data.
+	outputs []output
+	// alert is what the check of the alert node of the pipeline is made of, if any.
+	alert *alert
+}
+
+type output struct {
+	name  string
+	steps []string
+}
+
+// An alert holds what an alert node sets for its check and notification rules.
+type alert struct {
+	line       int
+	thresholds []influxdb.Threshold
+	message    string
+	handlers   []handler
+	// recoveries is whether the handlers are notified when the level goes back to OK.
+	recoveries       bool
+	stateChangesOnly bool
+}
+
+// A handler is a handler of an alert node that an endpoint can replace.
+type handler struct {
+	name         string
+	endpointType influxdb.NotificationEndpointType
+	url          string
+}
+
+func (p *pipeline) render(b *strings.Builder, name string, qualify bool) {
+	fmt.Fprintf(b, "%s = ", name)
+	p.renderQuery(b)
+
+	if len(p.outputs) == 0 {
+		p.outputs = []output{{name: "result"}}
+	}
+	for _, out := range p.outputs {
+		yield := out.name
+		if qualify {
+			yield = name + "_" + out.name
+		}
+		fmt.Fprintf(b, "\n%s\n", name)
+		for _, step := range out.steps {
+			fmt.Fprintf(b, "\t|> %s\n", step)
+		}
+		fmt.Fprintf(b, "\t|> yield(name: %s)\n", fluxString(yield))
+	}
+}
+
+// renderQuery renders the expression that reads, filters and aggregates the data
+// of the pipeline, which the outputs of the pipeline start from.
+func (p *pipeline) renderQuery(b *strings.Builder) {
+	fmt.Fprintf(b, "from(bucket: %s)\n", fluxString(p.bucket))
+	fmt.Fprintf(b, "\t|> range(start: -%s)\n", p.period)
+	if p.measurement != "" {
+		fmt.Fprintf(b, "\t|> filter(fn: (r) => r._measurement == %s)\n", fluxString(p.measurement))
+	}
+	for _, f := range p.filters {
+		fmt.Fprintf(b, "\t|> filter(fn: (r) => %s)\n", f)
+	}
+	if p.field != "" {
+		fmt.Fprintf(b, "\t|> filter(fn: (r) => r._field == %s)\n", fluxString(p.field))
+	}
+	if p.aggregate != "" {
+		if !p.groupAll {
+			columns := append([]string{"_start", "_stop", "_measurement", "_field"}, p.groupBy...)
+			fmt.Fprintf(b, "\t|> group(columns: %s)\n", fluxStrings(columns))
+		}
+		fmt.Fprintf(b, "\t|> %s()\n", p.aggregate)
+		if !p.selector {
+			b.WriteString("\t|> duplicate(column: \"_stop\", as: \"_time\")\n")
+		}
+	}
+	for _, f := range p.after {
+		fmt.Fprintf(b, "\t|> filter(fn: (r) => %s)\n", f)
+	}
+}
+
+// convertPipeline converts the nodes of a pipeline, starting with stream or batch.
+func (c *converter) convertPipeline(nodes []*node) (*pipeline, error) {
+	p := &pipeline{line: nodes[0].line}
+	if len(nodes) < 2 || !(nodes[0].name == "stream" && nodes[1].name == "from" || nodes[0].name == "batch" && nodes[1].name == "query") {
+		return nil, &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("line %d: pipelines must start with stream|from() or batch|query()", p.line),
+		}
+	}
+
+	var err error
+	if nodes[1].name == "from" {
+		err = c.convertFrom(p, nodes[1])
+	} else {
+		err = c.convertQuery(p, nodes[1])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range nodes[2:] {
+		if n.udf {
+			c.report(n.line, "@"+n.name+"()", "user defined functions are not converted")
+			continue
+		}
+
+		_, isAggregate := aggregates[n.name]
+		switch {
+		case n.name == "window":
+			c.convertWindow(p, n)
+		case n.name == "where":
+			c.convertWhere(p, n)
+		case n.name == "groupBy":
+			c.convertGroupBy(p, n.args)
+			c.reportProps(n.props)
+		case isAggregate:
+			c.convertAggregate(p, n)
+		case n.name == "alert":
+			if err := c.convertAlert(p, n); err != nil {
+				return nil, err
+			}
+		case n.name == "influxDBOut":
+			c.convertInfluxDBOut(p, n)
+		default:
+			c.report(n.line, "|"+n.name+"()", "the node is not converted")
+		}
+	}
+
+	if p.every == "" && p.period == "" {
+		p.every = DefaultEvery
+		if nodes[0].name == "stream" {
+			c.report(p.line, "stream", fmt.Sprintf("points are processed every %s rather than as they arrive", DefaultEvery))
+		} else {
+			c.report(p.line, "batch", fmt.Sprintf("the query has no period; it runs every %s", DefaultEvery))
+		}
+	}
+	if p.every == "" {
+		p.every = p.period
+	}
+	if p.period == "" {
+		p.period = p.every
+	}
+	return p, nil
+}
+
+func (c *converter) bucket(db, rp string) string {
+	if db == "" && c.dbrp != nil {
+		db, rp = c.dbrp.db, c.dbrp.rp
+	}
+	if rp == "" {
+		rp = "autogen"
+	}
+	return db + "/" + rp
+}
+
+func (c *converter) convertFrom(p *pipeline, n *node) error {
+	var db, rp string
+	for _, prop := range n.props {
+		var ok bool
+		switch prop.name {
+		case "database":
+			db, ok = c.stringArg(prop.args)
+		case "retentionPolicy":
+			rp, ok = c.stringArg(prop.args)
+		case "measurement":
+			p.measurement, ok = c.stringArg(prop.args)
+		case "where":
+			ok = c.convertWhereLambda(p, prop.args, prop.line)
+		case "groupBy":
+			ok = c.convertGroupBy(p, prop.args)
+		default:
+			c.report(prop.line, "."+prop.name+"()", "the property is not converted")
+			continue
+		}
+		if !ok {
+			c.report(prop.line, "."+prop.name+"()", "the arguments of the property are not converted")
+		}
+	}
+
+	if db == "" && c.dbrp == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("line %d: from() needs a database or the script a dbrp statement", n.line),
+		}
+	}
+	p.bucket = c.bucket(db, rp)
+	return nil
+}
+
+func (c *converter) convertQuery(p *pipeline, n *node) error {
+	text, ok := c.stringArg(n.args)
+	if !ok {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("line %d: query() takes the text of an InfluxQL query", n.line),
+		}
+	}
+	stmt, err := influxql.ParseStatement(text)
+	if err != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("line %d: failed to parse query", n.line),
+			Err:  err,
+		}
+	}
+	sel, ok := stmt.(*influxql.SelectStatement)
+	if !ok || len(sel.Sources) != 1 {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("line %d: query() must select from a single measurement", n.line),
+		}
+	}
+	m, ok := sel.Sources[0].(*influxql.Measurement)
+	if !ok || m.Regex != nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("line %d: query() must select from a single measurement", n.line),
+		}
+	}
+	if m.Database == "" && c.dbrp == nil {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  fmt.Sprintf("line %d: the query must name its database", n.line),
+		}
+	}
+	p.bucket = c.bucket(m.Database, m.RetentionPolicy)
+	p.measurement = m.Name
+
+	c.convertSelectFields(p, sel.Fields, n.line)
+	if sel.Condition != nil {
+		if cond, err := influxqlExpr(sel.Condition); err != nil {
+			c.report(n.line, "WHERE "+sel.Condition.String(), err.Error())
+		} else {
+			p.filters = append(p.filters, cond)
+		}
+	}
+	for _, d := range sel.Dimensions {
+		if ref, ok := d.Expr.(*influxql.VarRef); ok {
+			p.groupBy = append(p.groupBy, ref.Val)
+		} else {
+			c.report(n.line, "GROUP BY "+d.String(), "only grouping by tags is converted")
+		}
+	}
+
+	for _, prop := range n.props {
+		var ok bool
+		switch prop.name {
+		case "period":
+			p.period, ok = c.durationArg(prop.args)
+		case "every":
+			p.every, ok = c.durationArg(prop.args)
+		case "groupBy":
+			ok = c.convertGroupBy(p, prop.args)
+		default:
+			c.report(prop.line, "."+prop.name+"()", "the property is not converted")
+			continue
+		}
+		if !ok {
+			c.report(prop.line, "."+prop.name+"()", "the arguments of the property are not converted")
+		}
+	}
+	return nil
+}
+
+// convertSelectFields converts the single aggregated field of a batch query.
+func (c *converter) convertSelectFields(p *pipeline, fields influxql.Fields, line int) {
+	if len(fields) != 1 {
+		c.report(line, "SELECT "+fields.String(), "only queries of a single field are converted")
+		return
+	}
+
+	f := fields[0]
+	switch e := f.Expr.(type) {
+	case *influxql.VarRef:
+		p.field = e.Val
+		p.valueName = e.Val
+	case *influxql.Call:
+		selector, ok := aggregates[e.Name]
+		ref, isRef := (*influxql.VarRef)(nil), false
+		if len(e.Args) == 1 {
+			ref, isRef = e.Args[0].(*influxql.VarRef)
+		}
+		if !ok || !isRef {
+			c.report(line, "SELECT "+f.String(), "only the aggregates of a field by "+aggregateNames()+" are converted")
+			return
+		}
+		p.field = ref.Val
+		p.aggregate = e.Name
+		p.selector = selector
+		p.valueName = e.Name
+	default:
+		c.report(line, "SELECT "+f.String(), "only fields and their aggregates are converted")
+		return
+	}
+	if f.Alias != "" {
+		p.valueName = f.Alias
+	}
+}
+
+func aggregateNames() string {
+	names := make([]string, 0, len(aggregates))
+	for name := range aggregates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func (c *converter) convertWindow(p *pipeline, n *node) {
+	for _, prop := range n.props {
+		var ok bool
+		switch prop.name {
+		case "period":
+			p.period, ok = c.durationArg(prop.args)
+		case "every":
+			p.every, ok = c.durationArg(prop.args)
+		default:
+			c.report(prop.line, "."+prop.name+"()", "the property is not converted")
+			continue
+		}
+		if !ok {
+			c.report(prop.line, "."+prop.name+"()", "the arguments of the property are not converted")
+		}
+	}
+}
+
+func (c *converter) convertWhere(p *pipeline, n *node) {
+	if !c.convertWhereLambda(p, n.args, n.line) {
+		c.report(n.line, "|where()", "the condition is not converted")
+	}
+	c.reportProps(n.props)
+}
+
+// convertWhereLambda adds the condition of a where node or property to the pipeline.
+func (c *converter) convertWhereLambda(p *pipeline, args []expr, line int) bool {
+	if len(args) != 1 {
+		return false
+	}
+	l, ok := args[0].(*lambda)
+	if !ok {
+		return false
+	}
+
+	if p.aggregate != "" {
+		cond, err := c.fluxExpr(l.body, p.aggregatedRef)
+		if err != nil {
+			c.report(line, "lambda", err.Error())
+			return true
+		}
+		p.after = append(p.after, cond)
+		return true
+	}
+
+	if comparesFieldValue(l.body) {
+		c.report(line, "lambda", "conditions on the values of fields are only converted after aggregation")
+		return true
+	}
+	cond, err := c.fluxExpr(l.body, tagRef)
+	if err != nil {
+		c.report(line, "lambda", err.Error())
+		return true
+	}
+	p.filters = append(p.filters, cond)
+	return true
+}
+
+// comparesFieldValue reports whether e compares a reference to a number or a boolean,
+// in which case the reference is to a field rather than a tag.
+func comparesFieldValue(e expr) bool {
+	switch e := e.(type) {
+	case *parenExpr:
+		return comparesFieldValue(e.x)
+	case *unaryExpr:
+		return comparesFieldValue(e.x)
+	case *binaryExpr:
+		if comparesFieldValue(e.lhs) || comparesFieldValue(e.rhs) {
+			return true
+		}
+		_, lref := e.lhs.(*reference)
+		_, rref := e.rhs.(*reference)
+		return lref && isValueLiteral(e.rhs) || rref && isValueLiteral(e.lhs)
+	}
+	return false
+}
+
+func isValueLiteral(e expr) bool {
+	l, ok := e.(*literal)
+	if !ok {
+		return false
+	}
+	return l.tok.kind == tokenNumber || l.tok.text == "TRUE" || l.tok.text == "FALSE"
+}
+
+// convertGroupBy converts the arguments of a groupBy node or property.
+func (c *converter) convertGroupBy(p *pipeline, args []expr) bool {
+	p.groupBy, p.groupAll = nil, false
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case *star:
+			p.groupAll = true
+		case *callExpr:
+			if arg.name != "time" {
+				return false
+			}
+			if d, ok := c.durationArg(arg.args); !ok || d != p.period {
+				c.report(arg.line, "time()", "grouping by time within the period is not converted")
+			}
+		default:
+			tag, ok := c.stringArg([]expr{arg})
+			if !ok {
+				return false
+			}
+			p.groupBy = append(p.groupBy, tag)
+		}
+	}
+	return true
+}
+
+func (c *converter) convertAggregate(p *pipeline, n *node) {
+	construct := "|" + n.name + "()"
+	if p.aggregate != "" {
+		c.report(n.line, construct, "only one aggregation is converted")
+		return
+	}
+	field, ok := c.stringArg(n.args)
+	if !ok {
+		c.report(n.line, construct, "the aggregate must name a field")
+		return
+	}
+
+	p.field = field
+	p.aggregate = n.name
+	p.selector = aggregates[n.name]
+	p.valueName = n.name
+	for _, prop := range n.props {
+		if prop.name != "as" {
+			c.report(prop.line, "."+prop.name+"()", "the property is not converted")
+			continue
+		}
+		if name, ok := c.stringArg(prop.args); ok {
+			p.valueName = name
+		} else {
+			c.report(prop.line, ".as()", "the arguments of the property are not converted")
+		}
+	}
+}
+
+// convertAlert converts the levels of an alert node into branches that write
+// the matching rows, tagged with their level, to the alert bucket. The levels
+// that compare the value with numbers also become the thresholds of a check,
+// and the handlers of the node its notification rules.
+func (c *converter) convertAlert(p *pipeline, n *node) error {
+	if c.opts.AlertBucket == "" {
+		return &influxdb.Error{
+			Code: influxdb.EInvalid,
+			Msg:  "an alert bucket is required to convert alerts",
+		}
+	}
+
+	a := &alert{line: n.line, recoveries: true}
+	conditions := make(map[string]string)
+	thresholds := make(map[string]influxdb.Threshold)
+	// inHandler is whether the properties that follow belong to a handler.
+	inHandler := false
+	for _, prop := range n.props {
+		construct := "." + prop.name + "()"
+		endpointType, isHandler := alertHandlers[prop.name]
+		switch {
+		case isAlertLevel(prop.name):
+		case isHandler:
+			inHandler = true
+			if endpointType == "" {
+				c.report(prop.line, construct, "no notification endpoint replaces the handler")
+				continue
+			}
+			h := handler{name: prop.name, endpointType: endpointType}
+			if prop.name == "post" && len(prop.args) > 0 {
+				var ok bool
+				if h.url, ok = c.stringArg(prop.args); !ok {
+					c.report(prop.line, construct, "the arguments of the property are not converted")
+				}
+			}
+			a.handlers = append(a.handlers, h)
+			continue
+		case prop.name == "message":
+			tmpl, ok := c.stringArg(prop.args)
+			if !ok {
+				c.report(prop.line, construct, "the arguments of the property are not converted")
+				continue
+			}
+			if a.message, ok = convertMessage(p, tmpl); !ok {
+				c.report(prop.line, construct, "the template uses data other than .ID, .Name, .Level, the tags and the value; the check uses its default message")
+			}
+			continue
+		case prop.name == "noRecoveries":
+			a.recoveries = false
+			continue
+		case prop.name == "stateChangesOnly" && len(prop.args) == 0:
+			a.stateChangesOnly = true
+			continue
+		case inHandler:
+			c.report(prop.line, construct, "the properties of handlers are not converted; set them on the notification endpoint")
+			continue
+		case prop.name == "id":
+			c.report(prop.line, construct, "alert IDs are not converted; statuses are identified by their check and the tags of their series")
+			continue
+		default:
+			c.report(prop.line, construct, "the property is not converted")
+			continue
+		}
+
+		var l *lambda
+		if len(prop.args) == 1 {
+			l, _ = prop.args[0].(*lambda)
+		}
+		if l == nil {
+			c.report(prop.line, construct, "the level must be given by a lambda")
+			continue
+		}
+		ref := p.aggregatedRef
+		if p.aggregate == "" {
+			ref = p.fieldRef
+		}
+		cond, err := c.fluxExpr(l.body, ref)
+		if err != nil {
+			c.report(prop.line, construct, err.Error())
+			continue
+		}
+		conditions[prop.name] = cond
+
+		t, ok := c.threshold(l.body, ref)
+		if !ok {
+			c.report(prop.line, construct, "the check has no threshold for the level, which doesn't compare the value with numbers")
+			continue
+		}
+		t.Level = checkLevels[prop.name]
+		thresholds[prop.name] = t
+	}
+	if len(conditions) == 0 {
+		c.report(n.line, "|alert()", "the alert has no level that could be converted")
+		return nil
+	}
+
+	// A row is at the most severe level whose condition it meets.
+	var previous []string
+	for _, level := range alertLevels {
+		if t, ok := thresholds[level]; ok {
+			a.thresholds = append(a.thresholds, t)
+		}
+
+		cond, ok := conditions[level]
+		if !ok {
+			continue
+		}
+		predicate := cond
+		if len(previous) > 0 {
+			predicate = fmt.Sprintf("not (%s) and (%s)", strings.Join(previous, " or "), cond)
+		}
+		previous = append(previous, cond)
+
+		p.outputs = append(p.outputs, output{
+			name: level,
+			steps: []string{
+				fmt.Sprintf("filter(fn: (r) => %s)", predicate),
+				fmt.Sprintf("set(key: \"level\", value: %s)", fluxString(level)),
+				fmt.Sprintf("to(bucket: %s, org: %s)", fluxString(c.opts.AlertBucket), fluxString(c.opts.Org)),
+			},
+		})
+	}
+
+	if p.alert != nil {
+		c.report(n.line, "|alert()", "only the first alert node of a pipeline is converted into a check")
+		return nil
+	}
+	if len(a.thresholds) == 0 {
+		c.report(n.line, "|alert()", "the alert has no level that could be converted into a threshold of a check")
+		return nil
+	}
+	if len(a.handlers) > 0 && !a.stateChangesOnly {
+		c.report(n.line, "|alert()", "notifications are only sent when the level of a series changes, as with .stateChangesOnly()")
+	}
+	p.alert = a
+	return nil
+}
+
+// convertCheck adds the check of the alert of the pipeline, which runs the
+// query of the pipeline, and the notification rules of its handlers.
+func (c *converter) convertCheck(p *pipeline, name string) {
+	var query strings.Builder
+	p.renderQuery(&query)
+	check := &influxdb.Check{
+		Name:   name,
+		Type:   influxdb.ThresholdCheckType,
+		Status: influxdb.Active,
+		Query: influxdb.DashboardQuery{
+			Text:     strings.TrimSuffix(query.String(), "\n"),
+			EditMode: "advanced",
+		},
+		Every:                 p.every,
+		StatusMessageTemplate: p.alert.message,
+		Thresholds:            p.alert.thresholds,
+	}
+	c.checks = append(c.checks, check)
+
+	var statusRules []influxdb.StatusRule
+	for _, t := range p.alert.thresholds {
+		statusRules = append(statusRules, influxdb.StatusRule{
+			CurrentLevel: influxdb.LevelRule{Level: t.Level, Operation: influxdb.LevelRuleEqual},
+		})
+	}
+	if p.alert.recoveries {
+		statusRules = append(statusRules, influxdb.StatusRule{
+			CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelOK, Operation: influxdb.LevelRuleEqual},
+		})
+	}
+	for _, h := range p.alert.handlers {
+		c.rules = append(c.rules, &NotificationRule{
+			Rule: &influxdb.NotificationRule{
+				Name:        name + " " + h.name,
+				Status:      influxdb.Active,
+				StatusRules: statusRules,
+			},
+			Check:        name,
+			EndpointType: h.endpointType,
+			EndpointURL:  h.url,
+		})
+	}
+}
+
+// threshold converts the condition of a level of an alert node into a threshold
+// of a check. Only comparisons of the value with numbers by > and <, and ranges of
+// them joined by AND or OR, have a threshold.
+func (c *converter) threshold(e expr, ref refFunc) (influxdb.Threshold, bool) {
+	switch e := e.(type) {
+	case *parenExpr:
+		return c.threshold(e.x, ref)
+	case *binaryExpr:
+		switch e.op {
+		case ">", "<":
+			op := e.op
+			v, ok := c.number(e.rhs)
+			if !ok || !c.isValue(e.lhs, ref) {
+				// The value is on the right of the comparison.
+				if v, ok = c.number(e.lhs); !ok || !c.isValue(e.rhs, ref) {
+					return influxdb.Threshold{}, false
+				}
+				op = map[string]string{">": "<", "<": ">"}[op]
+			}
+			if op == ">" {
+				return influxdb.Threshold{Type: influxdb.GreaterThreshold, Value: v}, true
+			}
+			return influxdb.Threshold{Type: influxdb.LesserThreshold, Value: v}, true
+		case "AND", "OR":
+			lhs, ok := c.threshold(e.lhs, ref)
+			if !ok {
+				return influxdb.Threshold{}, false
+			}
+			rhs, ok := c.threshold(e.rhs, ref)
+			if !ok || lhs.Type == rhs.Type || lhs.Type == influxdb.RangeThreshold || rhs.Type == influxdb.RangeThreshold {
+				return influxdb.Threshold{}, false
+			}
+			if lhs.Type == influxdb.LesserThreshold {
+				lhs, rhs = rhs, lhs
+			}
+			// lhs is now the greater threshold and rhs the lesser one.
+			if e.op == "AND" && lhs.Value < rhs.Value {
+				return influxdb.Threshold{Type: influxdb.RangeThreshold, Min: lhs.Value, Max: rhs.Value, Within: true}, true
+			}
+			if e.op == "OR" && rhs.Value < lhs.Value {
+				return influxdb.Threshold{Type: influxdb.RangeThreshold, Min: rhs.Value, Max: lhs.Value}, true
+			}
+		}
+	}
+	return influxdb.Threshold{}, false
+}
+
+// isValue reports whether e references the value the pipeline alerts on.
+func (c *converter) isValue(e expr, ref refFunc) bool {
+	r, ok := e.(*reference)
+	if !ok {
+		return false
+	}
+	v, err := ref(r.name)
+	return err == nil && v == "r._value"
+}
+
+// number returns the value of e if it is a number.
+func (c *converter) number(e expr) (float64, bool) {
+	switch e := c.value(e).(type) {
+	case *parenExpr:
+		return c.number(e.x)
+	case *unaryExpr:
+		if e.op != "-" {
+			return 0, false
+		}
+		v, ok := c.number(e.x)
+		return -v, ok
+	case *literal:
+		if e.tok.kind != tokenNumber {
+			return 0, false
+		}
+		v, err := strconv.ParseFloat(e.tok.text, 64)
+		return v, err == nil
+	}
+	return 0, false
+}
+
+var (
+	messageActionRegexp = regexp.MustCompile(`\{\{\s*(.*?)\s*\}\}`)
+	messageColumnRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// convertMessage converts the template of the message of an alert node into the
+// template of the statuses of its check. It reports false if the template uses data
+// that statuses don't have.
+func convertMessage(p *pipeline, tmpl string) (string, bool) {
+	converted := true
+	msg := messageActionRegexp.ReplaceAllStringFunc(tmpl, func(m string) string {
+		action := strings.Fields(messageActionRegexp.FindStringSubmatch(m)[1])
+		switch {
+		case len(action) == 1 && action[0] == ".ID":
+			return "${_check_name}"
+		case len(action) == 1 && action[0] == ".Name":
+			return "${_source_measurement}"
+		case len(action) == 1 && action[0] == ".Level":
+			return "${_level}"
+		case len(action) == 3 && action[0] == "index":
+			key, err := strconv.Unquote(action[2])
+			if err != nil {
+				break
+			}
+			if action[1] == ".Tags" && messageColumnRegexp.MatchString(key) {
+				return "${" + key + "}"
+			}
+			if action[1] == ".Fields" && key != "" && (key == p.valueName || key == p.field) {
+				return "${_value}"
+			}
+		}
+		converted = false
+		return m
+	})
+	if !converted {
+		return "", false
+	}
+	return msg, true
+}
+
+func isAlertLevel(name string) bool {
+	for _, level := range alertLevels {
+		if name == level {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *converter) convertInfluxDBOut(p *pipeline, n *node) {
+	var (
+		db, rp string
+		steps  []string
+	)
+	for _, prop := range n.props {
+		var ok bool
+		switch prop.name {
+		case "database":
+			db, ok = c.stringArg(prop.args)
+		case "retentionPolicy":
+			rp, ok = c.stringArg(prop.args)
+		case "measurement":
+			var m string
+			if m, ok = c.stringArg(prop.args); ok {
+				steps = append(steps, fmt.Sprintf("set(key: \"_measurement\", value: %s)", fluxString(m)))
+			}
+		case "tag":
+			var k, v string
+			if ok = len(prop.args) == 2; ok {
+				k, ok = c.stringArg(prop.args[:1])
+			}
+			if ok {
+				v, ok = c.stringArg(prop.args[1:])
+			}
+			if ok {
+				steps = append(steps, fmt.Sprintf("set(key: %s, value: %s)", fluxString(k), fluxString(v)))
+			}
+		case "precision", "buffer", "flushInterval", "create", "writeConsistency":
+			continue
+		default:
+			c.report(prop.line, "."+prop.name+"()", "the property is not converted")
+			continue
+		}
+		if !ok {
+			c.report(prop.line, "."+prop.name+"()", "the arguments of the property are not converted")
+		}
+	}
+	if db == "" {
+		c.report(n.line, "|influxDBOut()", "the output must name its database")
+		return
+	}
+
+	steps = append(steps, fmt.Sprintf("to(bucket: %s, org: %s)", fluxString(c.bucket(db, rp)), fluxString(c.opts.Org)))
+	p.outputs = append(p.outputs, output{name: "influxDBOut", steps: steps})
+}
+
+func (c *converter) reportProps(props []*property) {
+	for _, prop := range props {
+		c.report(prop.line, "."+prop.name+"()", "the property is not converted")
+	}
+}
+
+// value returns e, or the value of the variable e names.
+func (c *converter) value(e expr) expr {
+	for i := 0; i < len(c.vars); i++ {
+		id, ok := e.(*identifier)
+		if !ok {
+			return e
+		}
+		v, ok := c.vars[id.name]
+		if !ok {
+			return e
+		}
+		e = v
+	}
+	return e
+}
+
+func (c *converter) stringArg(args []expr) (string, bool) {
+	if len(args) != 1 {
+		return "", false
+	}
+	switch l := c.value(args[0]).(type) {
+	case *literal:
+		if l.tok.kind == tokenString {
+			return l.tok.text, true
+		}
+	case *reference:
+		return l.name, true
+	}
+	return "", false
+}
+
+func (c *converter) durationArg(args []expr) (string, bool) {
+	if len(args) != 1 {
+		return "", false
+	}
+	l, ok := c.value(args[0]).(*literal)
+	if !ok || l.tok.kind != tokenDuration {
+		return "", false
+	}
+	return fluxDuration(l.tok.text), true
+}
+
+// A refFunc returns the Flux expression for a reference in a lambda.
+type refFunc func(name string) (string, error)
+
+func tagRef(name string) (string, error) {
+	return fluxColumn(name), nil
+}
+
+// aggregatedRef resolves references after aggregation, when only the aggregated value
+// and the tags the data is grouped by are left.
+func (p *pipeline) aggregatedRef(name string) (string, error) {
+	if name == p.valueName {
+		return "r._value", nil
+	}
+	if p.groupAll || contains(p.groupBy, name) {
+		return fluxColumn(name), nil
+	}
+	return "", fmt.Errorf("%q is neither the aggregated value %q nor a tag the data is grouped by", name, p.valueName)
+}
+
+// fieldRef resolves references without aggregation, where references to anything
+// but the tags the data is grouped by are to fields. Only one field can be referenced,
+// since each row holds the value of a single field.
+func (p *pipeline) fieldRef(name string) (string, error) {
+	if contains(p.groupBy, name) {
+		return fluxColumn(name), nil
+	}
+	if p.field == "" {
+		p.field = name
+	}
+	if name != p.field {
+		return "", fmt.Errorf("conditions on more than one field, %q and %q, are not converted", p.field, name)
+	}
+	return "r._value", nil
+}
+
+var fluxOperators = map[string]string{
+	"AND": "and",
+	"OR":  "or",
+	"!":   "not ",
+}
+
+// fluxExpr converts the lambda expression e into a Flux expression on the row r.
+func (c *converter) fluxExpr(e expr, ref refFunc) (string, error) {
+	switch e := e.(type) {
+	case *literal:
+		switch e.tok.kind {
+		case tokenString:
+			return fluxString(e.tok.text), nil
+		case tokenDuration:
+			return fluxDuration(e.tok.text), nil
+		case tokenRegex:
+			return "/" + e.tok.text + "/", nil
+		case tokenIdent:
+			return strings.ToLower(e.tok.text), nil
+		}
+		return e.tok.text, nil
+	case *reference:
+		return ref(e.name)
+	case *identifier:
+		v := c.value(e)
+		if _, ok := v.(*identifier); ok {
+			return "", fmt.Errorf("unknown variable %q", e.name)
+		}
+		if _, ok := v.(*chain); ok {
+			return "", fmt.Errorf("variable %q is not a value", e.name)
+		}
+		return c.fluxExpr(v, ref)
+	case *parenExpr:
+		x, err := c.fluxExpr(e.x, ref)
+		if err != nil {
+			return "", err
+		}
+		return "(" + x + ")", nil
+	case *unaryExpr:
+		x, err := c.fluxExpr(e.x, ref)
+		if err != nil {
+			return "", err
+		}
+		if op, ok := fluxOperators[e.op]; ok {
+			return op + x, nil
+		}
+		return e.op + x, nil
+	case *binaryExpr:
+		lhs, err := c.fluxExpr(e.lhs, ref)
+		if err != nil {
+			return "", err
+		}
+		rhs, err := c.fluxExpr(e.rhs, ref)
+		if err != nil {
+			return "", err
+		}
+		op := e.op
+		if o, ok := fluxOperators[op]; ok {
+			op = o
+		}
+		return lhs + " " + op + " " + rhs, nil
+	case *callExpr:
+		return "", fmt.Errorf("the function %s() is not converted", e.name)
+	}
+	return "", fmt.Errorf("the expression is not converted")
+}
+
+var influxqlOperators = map[influxql.Token]string{
+	influxql.AND:      "and",
+	influxql.OR:       "or",
+	influxql.EQ:       "==",
+	influxql.NEQ:      "!=",
+	influxql.EQREGEX:  "=~",
+	influxql.NEQREGEX: "!~",
+	influxql.LT:       "<",
+	influxql.LTE:      "<=",
+	influxql.GT:       ">",
+	influxql.GTE:      ">=",
+	influxql.ADD:      "+",
+	influxql.SUB:      "-",
+	influxql.MUL:      "*",
+	influxql.DIV:      "/",
+}
+
+// influxqlExpr converts the condition of an InfluxQL query into a Flux expression on the row r.
+func influxqlExpr(e influxql.Expr) (string, error) {
+	switch e := e.(type) {
+	case *influxql.BinaryExpr:
+		op, ok := influxqlOperators[e.Op]
+		if !ok {
+			return "", fmt.Errorf("the operator %s is not converted", e.Op)
+		}
+		lhs, err := influxqlExpr(e.LHS)
+		if err != nil {
+			return "", err
+		}
+		rhs, err := influxqlExpr(e.RHS)
+		if err != nil {
+			return "", err
+		}
+		return lhs + " " + op + " " + rhs, nil
+	case *influxql.ParenExpr:
+		x, err := influxqlExpr(e.Expr)
+		if err != nil {
+			return "", err
+		}
+		return "(" + x + ")", nil
+	case *influxql.VarRef:
+		if e.Val == "time" {
+			return "", fmt.Errorf("conditions on time are replaced by the period of the query")
+		}
+		return fluxColumn(e.Val), nil
+	case *influxql.StringLiteral:
+		return fluxString(e.Val), nil
+	case *influxql.IntegerLiteral:
+		return strconv.FormatInt(e.Val, 10), nil
+	case *influxql.NumberLiteral:
+		return strconv.FormatFloat(e.Val, 'f', -1, 64), nil
+	case *influxql.BooleanLiteral:
+		return strconv.FormatBool(e.Val), nil
+	case *influxql.RegexLiteral:
+		return "/" + strings.Replace(e.Val.String(), "/", `\/`, -1) + "/", nil
+	}
+	return "", fmt.Errorf("the expression %s is not converted", e)
+}
+
+var fluxStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func fluxString(s string) string {
+	return `"` + fluxStringEscaper.Replace(s) + `"`
+}
+
+func fluxStrings(ss []string) string {
+	quoted := make([]string, len(ss))
+	for i, s := range ss {
+		quoted[i] = fluxString(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// fluxColumn returns the Flux expression for the column name of the row r.
+func fluxColumn(name string) string {
+	for i := 0; i < len(name); i++ {
+		if !isIdentByte(name, i) || i == 0 && isDigit(name[0]) {
+			return "r[" + fluxString(name) + "]"
+		}
+	}
+	return "r." + name
+}
+
+// fluxDuration converts a TICKscript duration literal into a Flux one.
+func fluxDuration(d string) string {
+	for _, unit := range []string{"u", "µ"} {
+		if strings.HasSuffix(d, unit) {
+			return strings.TrimSuffix(d, unit) + "us"
+		}
+	}
+	return d
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,461 @@
+package tickscript_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/task/tickscript"
+)
+
+func TestConvert(t *testing.T) {
+	opts := tickscript.Options{Name: "cpu", Org: "my-org", AlertBucket: "alerts"}
+
+	tests := []struct {
+		name            string
+		script          string
+		opts            tickscript.Options
+		wantFlux        string
+		wantChecks      []*influxdb.Check
+		wantRules       []*tickscript.NotificationRule
+		wantUnconverted []tickscript.Unconverted
+	}{
+		{
+			name: "stream alert on a windowed aggregate",
+			script: `dbrp "telegraf"."autogen"
+
+var period = 5m
+var warn = 80
+
+var data = stream
+    |from()
+        .measurement('cpu')
+        .where(lambda: "cpu" == 'cpu-total' AND "host" =~ /^web-\d+$/)
+        .groupBy('host')
+    |window()
+        .period(period)
+        .every(1m)
+    |mean('usage_user')
+        .as('value')
+
+data
+    |alert()
+        .id('{{ index .Tags "host" }}')
+        .crit(lambda: "value" > 90)
+        .warn(lambda: "value" > warn)
+        .slack()
+`,
+			opts: opts,
+			wantFlux: `option task = {name: "cpu", every: 1m}
+
+data = from(bucket: "telegraf/autogen")
+	|> range(start: -5m)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> filter(fn: (r) => r.cpu == "cpu-total" and r.host =~ /^web-\d+$/)
+	|> filter(fn: (r) => r._field == "usage_user")
+	|> group(columns: ["_start", "_stop", "_measurement", "_field", "host"])
+	|> mean()
+	|> duplicate(column: "_stop", as: "_time")
+
+data
+	|> filter(fn: (r) => r._value > 90)
+	|> set(key: "level", value: "crit")
+	|> to(bucket: "alerts", org: "my-org")
+	|> yield(name: "crit")
+
+data
+	|> filter(fn: (r) => not (r._value > 90) and (r._value > 80))
+	|> set(key: "level", value: "warn")
+	|> to(bucket: "alerts", org: "my-org")
+	|> yield(name: "warn")
+`,
+			wantChecks: []*influxdb.Check{
+				{
+					Name:   "cpu",
+					Type:   influxdb.ThresholdCheckType,
+					Status: influxdb.Active,
+					Query: influxdb.DashboardQuery{
+						Text: `from(bucket: "telegraf/autogen")
+	|> range(start: -5m)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> filter(fn: (r) => r.cpu == "cpu-total" and r.host =~ /^web-\d+$/)
+	|> filter(fn: (r) => r._field == "usage_user")
+	|> group(columns: ["_start", "_stop", "_measurement", "_field", "host"])
+	|> mean()
+	|> duplicate(column: "_stop", as: "_time")`,
+						EditMode: "advanced",
+					},
+					Every: "1m",
+					Thresholds: []influxdb.Threshold{
+						{Type: influxdb.GreaterThreshold, Level: influxdb.CheckLevelCrit, Value: 90},
+						{Type: influxdb.GreaterThreshold, Level: influxdb.CheckLevelWarn, Value: 80},
+					},
+				},
+			},
+			wantRules: []*tickscript.NotificationRule{
+				{
+					Rule: &influxdb.NotificationRule{
+						Name:   "cpu slack",
+						Status: influxdb.Active,
+						StatusRules: []influxdb.StatusRule{
+							{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelCrit, Operation: influxdb.LevelRuleEqual}},
+							{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelWarn, Operation: influxdb.LevelRuleEqual}},
+							{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelOK, Operation: influxdb.LevelRuleEqual}},
+						},
+					},
+					Check:        "cpu",
+					EndpointType: influxdb.SlackNotificationEndpoint,
+				},
+			},
+			wantUnconverted: []tickscript.Unconverted{
+				{Line: 18, Construct: "|alert()", Reason: "notifications are only sent when the level of a series changes, as with .stateChangesOnly()"},
+				{Line: 19, Construct: ".id()", Reason: "alert IDs are not converted; statuses are identified by their check and the tags of their series"},
+			},
+		},
+		{
+			name: "batch alert with handlers",
+			script: `batch
+    |query('''SELECT mean("usage_idle") FROM "telegraf"."autogen"."cpu"''')
+        .period(5m)
+        .every(5m)
+        .groupBy('host')
+    |alert()
+        .message('{{ .ID }} on {{ index .Tags "host" }} is {{ .Level }}: {{ index .Fields "mean" }}')
+        .crit(lambda: "mean" < 10)
+        .warn(lambda: "mean" > 10 AND "mean" < 20)
+        .info(lambda: "mean" <= 50)
+        .stateChangesOnly()
+        .noRecoveries()
+        .post('https://example.com/alerts')
+            .header('X-Token', 'secret')
+        .email('oncall@example.com')
+        .pagerDuty2()
+`,
+			opts: opts,
+			wantFlux: `option task = {name: "cpu", every: 5m}
+
+data = from(bucket: "telegraf/autogen")
+	|> range(start: -5m)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> filter(fn: (r) => r._field == "usage_idle")
+	|> group(columns: ["_start", "_stop", "_measurement", "_field", "host"])
+	|> mean()
+	|> duplicate(column: "_stop", as: "_time")
+
+data
+	|> filter(fn: (r) => r._value < 10)
+	|> set(key: "level", value: "crit")
+	|> to(bucket: "alerts", org: "my-org")
+	|> yield(name: "crit")
+
+data
+	|> filter(fn: (r) => not (r._value < 10) and (r._value > 10 and r._value < 20))
+	|> set(key: "level", value: "warn")
+	|> to(bucket: "alerts", org: "my-org")
+	|> yield(name: "warn")
+
+data
+	|> filter(fn: (r) => not (r._value < 10 or r._value > 10 and r._value < 20) and (r._value <= 50))
+	|> set(key: "level", value: "info")
+	|> to(bucket: "alerts", org: "my-org")
+	|> yield(name: "info")
+`,
+			wantChecks: []*influxdb.Check{
+				{
+					Name:   "cpu",
+					Type:   influxdb.ThresholdCheckType,
+					Status: influxdb.Active,
+					Query: influxdb.DashboardQuery{
+						Text: `from(bucket: "telegraf/autogen")
+	|> range(start: -5m)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> filter(fn: (r) => r._field == "usage_idle")
+	|> group(columns: ["_start", "_stop", "_measurement", "_field", "host"])
+	|> mean()
+	|> duplicate(column: "_stop", as: "_time")`,
+						EditMode: "advanced",
+					},
+					Every:                 "5m",
+					StatusMessageTemplate: "${_check_name} on ${host} is ${_level}: ${_value}",
+					Thresholds: []influxdb.Threshold{
+						{Type: influxdb.LesserThreshold, Level: influxdb.CheckLevelCrit, Value: 10},
+						{Type: influxdb.RangeThreshold, Level: influxdb.CheckLevelWarn, Min: 10, Max: 20, Within: true},
+					},
+				},
+			},
+			wantRules: []*tickscript.NotificationRule{
+				{
+					Rule: &influxdb.NotificationRule{
+						Name:   "cpu post",
+						Status: influxdb.Active,
+						StatusRules: []influxdb.StatusRule{
+							{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelCrit, Operation: influxdb.LevelRuleEqual}},
+							{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelWarn, Operation: influxdb.LevelRuleEqual}},
+						},
+					},
+					Check:        "cpu",
+					EndpointType: influxdb.HTTPNotificationEndpoint,
+					EndpointURL:  "https://example.com/alerts",
+				},
+				{
+					Rule: &influxdb.NotificationRule{
+						Name:   "cpu pagerDuty2",
+						Status: influxdb.Active,
+						StatusRules: []influxdb.StatusRule{
+							{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelCrit, Operation: influxdb.LevelRuleEqual}},
+							{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelWarn, Operation: influxdb.LevelRuleEqual}},
+						},
+					},
+					Check:        "cpu",
+					EndpointType: influxdb.PagerDutyNotificationEndpoint,
+				},
+			},
+			wantUnconverted: []tickscript.Unconverted{
+				{Line: 10, Construct: ".info()", Reason: "the check has no threshold for the level, which doesn't compare the value with numbers"},
+				{Line: 14, Construct: ".header()", Reason: "the properties of handlers are not converted; set them on the notification endpoint"},
+				{Line: 15, Construct: ".email()", Reason: "no notification endpoint replaces the handler"},
+			},
+		},
+		{
+			name: "batch alert on a query",
+			script: `batch
+    |query('''SELECT max("used_percent") AS used FROM "telegraf"."autogen"."mem" WHERE "host" = 'db-1' ''')
+        .period(10m)
+        .every(5m)
+        .groupBy('host')
+    |alert()
+        .crit(lambda: "used" > 95)
+`,
+			opts: tickscript.Options{Name: "mem", Org: "my-org", AlertBucket: "alerts"},
+			wantFlux: `option task = {name: "mem", every: 5m}
+
+data = from(bucket: "telegraf/autogen")
+	|> range(start: -10m)
+	|> filter(fn: (r) => r._measurement == "mem")
+	|> filter(fn: (r) => r.host == "db-1")
+	|> filter(fn: (r) => r._field == "used_percent")
+	|> group(columns: ["_start", "_stop", "_measurement", "_field", "host"])
+	|> max()
+
+data
+	|> filter(fn: (r) => r._value > 95)
+	|> set(key: "level", value: "crit")
+	|> to(bucket: "alerts", org: "my-org")
+	|> yield(name: "crit")
+`,
+			wantChecks: []*influxdb.Check{
+				{
+					Name:   "mem",
+					Type:   influxdb.ThresholdCheckType,
+					Status: influxdb.Active,
+					Query: influxdb.DashboardQuery{
+						Text: `from(bucket: "telegraf/autogen")
+	|> range(start: -10m)
+	|> filter(fn: (r) => r._measurement == "mem")
+	|> filter(fn: (r) => r.host == "db-1")
+	|> filter(fn: (r) => r._field == "used_percent")
+	|> group(columns: ["_start", "_stop", "_measurement", "_field", "host"])
+	|> max()`,
+						EditMode: "advanced",
+					},
+					Every: "5m",
+					Thresholds: []influxdb.Threshold{
+						{Type: influxdb.GreaterThreshold, Level: influxdb.CheckLevelCrit, Value: 95},
+					},
+				},
+			},
+			wantUnconverted: []tickscript.Unconverted{},
+		},
+		{
+			name: "stream downsampling with unconverted nodes",
+			script: `stream
+    |from()
+        .database('telegraf')
+        .measurement('disk')
+    |eval(lambda: "used" / "total")
+        .as('ratio')
+    |influxDBOut()
+        .database('telegraf')
+        .retentionPolicy('archive')
+        .measurement('disk_copy')
+        .tag('source', 'kapacitor')
+        .precision('s')
+`,
+			opts: tickscript.Options{Name: "disk", Org: "my-org"},
+			wantFlux: `option task = {name: "disk", every: 1m}
+
+data = from(bucket: "telegraf/autogen")
+	|> range(start: -1m)
+	|> filter(fn: (r) => r._measurement == "disk")
+
+data
+	|> set(key: "_measurement", value: "disk_copy")
+	|> set(key: "source", value: "kapacitor")
+	|> to(bucket: "telegraf/archive", org: "my-org")
+	|> yield(name: "influxDBOut")
+`,
+			wantUnconverted: []tickscript.Unconverted{
+				{Line: 1, Construct: "stream", Reason: "points are processed every 1m rather than as they arrive"},
+				{Line: 5, Construct: "|eval()", Reason: "the node is not converted"},
+			},
+		},
+		{
+			name: "alert on a field without aggregation",
+			script: `stream
+    |from()
+        .database('telegraf')
+        .measurement('mem')
+        .where(lambda: "used_percent" > 50)
+    |alert()
+        .crit(lambda: "used_percent" > 90 OR "available_percent" < 5)
+        .info(lambda: "used_percent" > 70)
+`,
+			opts: tickscript.Options{Name: "mem", Org: "my-org", AlertBucket: "alerts"},
+			wantFlux: `option task = {name: "mem", every: 1m}
+
+data = from(bucket: "telegraf/autogen")
+	|> range(start: -1m)
+	|> filter(fn: (r) => r._measurement == "mem")
+	|> filter(fn: (r) => r._field == "used_percent")
+
+data
+	|> filter(fn: (r) => r._value > 70)
+	|> set(key: "level", value: "info")
+	|> to(bucket: "alerts", org: "my-org")
+	|> yield(name: "info")
+`,
+			wantChecks: []*influxdb.Check{
+				{
+					Name:   "mem",
+					Type:   influxdb.ThresholdCheckType,
+					Status: influxdb.Active,
+					Query: influxdb.DashboardQuery{
+						Text: `from(bucket: "telegraf/autogen")
+	|> range(start: -1m)
+	|> filter(fn: (r) => r._measurement == "mem")
+	|> filter(fn: (r) => r._field == "used_percent")`,
+						EditMode: "advanced",
+					},
+					Every: "1m",
+					Thresholds: []influxdb.Threshold{
+						{Type: influxdb.GreaterThreshold, Level: influxdb.CheckLevelInfo, Value: 70},
+					},
+				},
+			},
+			wantUnconverted: []tickscript.Unconverted{
+				{Line: 1, Construct: "stream", Reason: "points are processed every 1m rather than as they arrive"},
+				{Line: 5, Construct: "lambda", Reason: "conditions on the values of fields are only converted after aggregation"},
+				{Line: 7, Construct: ".crit()", Reason: `conditions on more than one field, "used_percent" and "available_percent", are not converted`},
+			},
+		},
+		{
+			name: "strings are escaped",
+			script: `stream
+    |from()
+        .database('telegraf')
+        .measurement('mem "${host}"')
+`,
+			opts: tickscript.Options{Name: `mem "${host}"`, Org: "my-org"},
+			wantFlux: `option task = {name: "mem \"${host}\"", every: 1m}
+
+data = from(bucket: "telegraf/autogen")
+	|> range(start: -1m)
+	|> filter(fn: (r) => r._measurement == "mem \"${host}\"")
+
+data
+	|> yield(name: "result")
+`,
+			wantUnconverted: []tickscript.Unconverted{
+				{Line: 1, Construct: "stream", Reason: "points are processed every 1m rather than as they arrive"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tickscript.Convert(tt.script, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Flux != tt.wantFlux {
+				t.Errorf("got flux\n%s\nwant\n%s", got.Flux, tt.wantFlux)
+			}
+			if len(got.Checks) > 0 || len(tt.wantChecks) > 0 {
+				if !reflect.DeepEqual(got.Checks, tt.wantChecks) {
+					t.Errorf("got checks %+v, want %+v", got.Checks, tt.wantChecks)
+				}
+			}
+			if len(got.NotificationRules) > 0 || len(tt.wantRules) > 0 {
+				if !reflect.DeepEqual(got.NotificationRules, tt.wantRules) {
+					t.Errorf("got notification rules %+v, want %+v", got.NotificationRules, tt.wantRules)
+				}
+			}
+			if !reflect.DeepEqual(got.Unconverted, tt.wantUnconverted) {
+				t.Errorf("got unconverted %+v, want %+v", got.Unconverted, tt.wantUnconverted)
+			}
+
+			// The checks only miss the organization they are created in to run.
+			for _, c := range got.Checks {
+				c.OrgID = 1
+				if _, err := c.GenerateFlux(); err != nil {
+					t.Errorf("check %q does not generate flux: %v", c.Name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestConvert_Errors(t *testing.T) {
+	opts := tickscript.Options{Name: "cpu", Org: "my-org"}
+
+	tests := []struct {
+		name   string
+		script string
+		opts   tickscript.Options
+	}{
+		{
+			name:   "syntax error",
+			script: `stream|from(.measurement('cpu')`,
+			opts:   opts,
+		},
+		{
+			name:   "unterminated string",
+			script: `stream|from().measurement('cpu)`,
+			opts:   opts,
+		},
+		{
+			name:   "no database",
+			script: `stream|from().measurement('cpu')`,
+			opts:   opts,
+		},
+		{
+			name:   "no pipeline",
+			script: `var period = 5m`,
+			opts:   opts,
+		},
+		{
+			name:   "pipeline without a source",
+			script: `stream|window().period(5m)`,
+			opts:   opts,
+		},
+		{
+			name:   "alert without an alert bucket",
+			script: `stream|from().database('telegraf')|alert().crit(lambda: "value" > 1)`,
+			opts:   opts,
+		},
+		{
+			name:   "no task name",
+			script: `stream|from().database('telegraf')`,
+			opts:   tickscript.Options{Org: "my-org"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tickscript.Convert(tt.script, tt.opts)
+			if influxdb.ErrorCode(err) != influxdb.EInvalid {
+				t.Fatalf("expected an invalid error, got %v", err)
+			}
+		})
+	}
+}
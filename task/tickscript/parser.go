@@ -0,0 +1,366 @@
+package tickscript
+
+// An expr is an expression of a TICKscript.
+type expr interface {
+	exprNode()
+}
+
+type (
+	// A literal is a string, number, duration, regex or boolean.
+	literal struct {
+		tok token
+	}
+
+	// A reference is a double quoted name of a field or tag, such as "host".
+	reference struct {
+		name string
+	}
+
+	// An identifier names a variable.
+	identifier struct {
+		name string
+		line int
+	}
+
+	// star is the * of groupBy(*).
+	star struct{}
+
+	unaryExpr struct {
+		op string
+		x  expr
+	}
+
+	binaryExpr struct {
+		op       string
+		lhs, rhs expr
+	}
+
+	parenExpr struct {
+		x expr
+	}
+
+	callExpr struct {
+		name string
+		args []expr
+		line int
+	}
+
+	lambda struct {
+		body expr
+		line int
+	}
+
+	// A chain is a pipeline of nodes, such as stream|from().measurement('cpu')|alert().
+	chain struct {
+		// source is stream, batch or the name of a variable holding a chain.
+		source string
+		nodes  []*node
+		line   int
+	}
+)
+
+func (*literal) exprNode()    {}
+func (*reference) exprNode()  {}
+func (*identifier) exprNode() {}
+func (*star) exprNode()       {}
+func (*unaryExpr) exprNode()  {}
+func (*binaryExpr) exprNode() {}
+func (*parenExpr) exprNode()  {}
+func (*callExpr) exprNode()   {}
+func (*lambda) exprNode()     {}
+func (*chain) exprNode()      {}
+
+// A node is a stage of a chain, such as |window(), along with its properties.
+type node struct {
+	name string
+	// udf is true for user defined functions, which are chained with @.
+	udf   bool
+	args  []expr
+	props []*property
+	line  int
+}
+
+// A property configures a node, such as .period(5m).
+type property struct {
+	name string
+	args []expr
+	line int
+}
+
+// A statement is a variable declaration, a dbrp statement or a chain.
+type statement struct {
+	// name is the name of the declared variable, if any.
+	name  string
+	value expr
+	line  int
+}
+
+// A dbrp is a database and retention policy a script applies to.
+type dbrp struct {
+	db, rp string
+}
+
+type program struct {
+	statements []*statement
+	dbrps      []dbrp
+}
+
+// parse parses a TICKscript.
+func parse(src string) (*program, error) {
+	tokens, err := scan(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	prog := &program{}
+	for p.peek().kind != tokenEOF {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokenIdent && tok.text == "dbrp":
+			p.next()
+			db, err := p.expect(tokenReference)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokenDot); err != nil {
+				return nil, err
+			}
+			rp, err := p.expect(tokenReference)
+			if err != nil {
+				return nil, err
+			}
+			prog.dbrps = append(prog.dbrps, dbrp{db: db.text, rp: rp.text})
+		case tok.kind == tokenIdent && tok.text == "var":
+			p.next()
+			name, err := p.expect(tokenIdent)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokenAssign); err != nil {
+				return nil, err
+			}
+			value, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			prog.statements = append(prog.statements, &statement{name: name.text, value: value, line: tok.line})
+		default:
+			value, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			prog.statements = append(prog.statements, &statement{value: value, line: tok.line})
+		}
+	}
+	return prog, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, unexpected(tok)
+	}
+	return tok, nil
+}
+
+func unexpected(tok token) error {
+	if tok.kind == tokenEOF {
+		return syntaxErrorf(tok.line, "unexpected end of script")
+	}
+	return syntaxErrorf(tok.line, "unexpected %q", tok.text)
+}
+
+// parseExpr parses a chain, a lambda or an operand.
+func (p *parser) parseExpr() (expr, error) {
+	tok := p.peek()
+	if tok.kind == tokenIdent {
+		switch next := p.peekAt(1); {
+		case tok.text == "lambda" && next.kind == tokenColon:
+			p.pos += 2
+			body, err := p.parseBinary(0)
+			if err != nil {
+				return nil, err
+			}
+			return &lambda{body: body, line: tok.line}, nil
+		case next.kind == tokenPipe || next.kind == tokenAt:
+			p.next()
+			return p.parseChain(&chain{source: tok.text, line: tok.line})
+		}
+	}
+	return p.parseBinary(0)
+}
+
+func (p *parser) parseChain(c *chain) (expr, error) {
+	for {
+		switch p.peek().kind {
+		case tokenPipe, tokenAt:
+			udf := p.next().kind == tokenAt
+			name, err := p.expect(tokenIdent)
+			if err != nil {
+				return nil, err
+			}
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			c.nodes = append(c.nodes, &node{name: name.text, udf: udf, args: args, line: name.line})
+		case tokenDot:
+			p.next()
+			name, err := p.expect(tokenIdent)
+			if err != nil {
+				return nil, err
+			}
+			if len(c.nodes) == 0 {
+				return nil, syntaxErrorf(name.line, "property %q does not follow a node", name.text)
+			}
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			n := c.nodes[len(c.nodes)-1]
+			n.props = append(n.props, &property{name: name.text, args: args, line: name.line})
+		default:
+			return c, nil
+		}
+	}
+}
+
+func (p *parser) parseArgs() ([]expr, error) {
+	if _, err := p.expect(tokenLParen); err != nil {
+		return nil, err
+	}
+	var args []expr
+	for p.peek().kind != tokenRParen {
+		if len(args) > 0 {
+			if _, err := p.expect(tokenComma); err != nil {
+				return nil, err
+			}
+		}
+
+		if tok := p.peek(); tok.kind == tokenOperator && tok.text == "*" {
+			p.next()
+			args = append(args, &star{})
+			continue
+		}
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+	return args, nil
+}
+
+// precedence returns the precedence of the binary operator tok, or 0 if it isn't one.
+func precedence(tok token) int {
+	switch tok.kind {
+	case tokenIdent:
+		switch tok.text {
+		case "OR":
+			return 1
+		case "AND":
+			return 2
+		}
+	case tokenOperator:
+		switch tok.text {
+		case "==", "!=", "<", "<=", ">", ">=", "=~", "!~":
+			return 3
+		case "+", "-":
+			return 4
+		case "*", "/":
+			return 5
+		}
+	}
+	return 0
+}
+
+// parseBinary parses a binary expression whose operators bind tighter than min.
+func (p *parser) parseBinary(min int) (expr, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		prec := precedence(op)
+		if prec <= min {
+			return lhs, nil
+		}
+		p.next()
+		rhs, err := p.parseBinary(prec)
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryExpr{op: op.text, lhs: lhs, rhs: rhs}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if tok := p.peek(); tok.kind == tokenOperator && (tok.text == "!" || tok.text == "-") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: tok.text, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString, tokenNumber, tokenDuration, tokenRegex:
+		return &literal{tok: tok}, nil
+	case tokenReference:
+		return &reference{name: tok.text}, nil
+	case tokenIdent:
+		if tok.text == "TRUE" || tok.text == "FALSE" {
+			return &literal{tok: tok}, nil
+		}
+		if p.peek().kind == tokenLParen {
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return &callExpr{name: tok.text, args: args, line: tok.line}, nil
+		}
+		return &identifier{name: tok.text, line: tok.line}, nil
+	case tokenLParen:
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen); err != nil {
+			return nil, err
+		}
+		return &parenExpr{x: x}, nil
+	}
+	return nil, unexpected(tok)
+}
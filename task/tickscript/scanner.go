@@ -0,0 +1,225 @@
+package tickscript
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF       tokenKind = iota
+	tokenIdent               // var, stream, TRUE, AND
+	tokenReference           // "usage_idle"
+	tokenString              // 'cpu-total', '''SELECT ...'''
+	tokenNumber              // 10, 0.5
+	tokenDuration            // 5m
+	tokenRegex               // /^cpu[0-9]+$/
+	tokenPipe                // |
+	tokenAt                  // @
+	tokenDot                 // .
+	tokenLParen              // (
+	tokenRParen              // )
+	tokenComma               // ,
+	tokenColon               // :
+	tokenAssign              // =
+	tokenOperator            // == != < <= > >= =~ !~ + - * / !
+)
+
+type token struct {
+	kind tokenKind
+	// text is the text of the token, without the quotes or slashes of strings,
+	// references and regexes.
+	text string
+	line int
+}
+
+// SyntaxError is returned when a TICKscript can't be parsed.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+func syntaxErrorf(line int, format string, args ...interface{}) error {
+	return &SyntaxError{Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// durationUnits are the units of TICKscript durations, longest first.
+var durationUnits = []string{"ms", "u", "µ", "s", "m", "h", "d", "w"}
+
+// scan splits src into tokens, ending with a tokenEOF.
+func scan(src string) ([]token, error) {
+	var (
+		tokens []token
+		line   = 1
+	)
+	emit := func(kind tokenKind, text string) {
+		tokens = append(tokens, token{kind: kind, text: text, line: line})
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "'''"):
+			end := strings.Index(src[i+3:], "'''")
+			if end < 0 {
+				return nil, syntaxErrorf(line, "unterminated string")
+			}
+			text := src[i+3 : i+3+end]
+			emit(tokenString, text)
+			line += strings.Count(text, "\n")
+			i += end + 6
+		case c == '\'' || c == '"':
+			text, n, err := scanQuoted(src[i:], c, line)
+			if err != nil {
+				return nil, err
+			}
+			if c == '\'' {
+				emit(tokenString, text)
+			} else {
+				emit(tokenReference, text)
+			}
+			i += n
+		case c == '/' && !followsOperand(tokens):
+			j := i + 1
+			for ; j < len(src) && src[j] != '/'; j++ {
+				if src[j] == '\\' {
+					j++
+				}
+				if j < len(src) && src[j] == '\n' {
+					return nil, syntaxErrorf(line, "unterminated regex")
+				}
+			}
+			if j >= len(src) {
+				return nil, syntaxErrorf(line, "unterminated regex")
+			}
+			emit(tokenRegex, src[i+1:j])
+			i = j + 1
+		case isDigit(c):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			kind := tokenNumber
+			for _, unit := range durationUnits {
+				if strings.HasPrefix(src[j:], unit) && !isIdentByte(src, j+len(unit)) {
+					kind = tokenDuration
+					j += len(unit)
+					break
+				}
+			}
+			emit(kind, src[i:j])
+			i = j
+		case isLetter(c) || c == '_':
+			j := i
+			for isIdentByte(src, j) {
+				j++
+			}
+			emit(tokenIdent, src[i:j])
+			i = j
+		default:
+			if op := scanOperator(src[i:]); op != "" {
+				if op == "=" {
+					emit(tokenAssign, op)
+				} else {
+					emit(tokenOperator, op)
+				}
+				i += len(op)
+				continue
+			}
+
+			kind, ok := punctuation[c]
+			if !ok {
+				r, _ := utf8.DecodeRuneInString(src[i:])
+				return nil, syntaxErrorf(line, "unexpected character %q", r)
+			}
+			emit(kind, string(c))
+			i++
+		}
+	}
+	emit(tokenEOF, "")
+	return tokens, nil
+}
+
+var punctuation = map[byte]tokenKind{
+	'|': tokenPipe,
+	'@': tokenAt,
+	'.': tokenDot,
+	'(': tokenLParen,
+	')': tokenRParen,
+	',': tokenComma,
+	':': tokenColon,
+}
+
+// operators are the operators of lambda expressions, with two-character operators first.
+var operators = []string{"==", "!=", "<=", ">=", "=~", "!~", "<", ">", "+", "-", "*", "/", "!", "="}
+
+func scanOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// scanQuoted returns the unescaped contents of the string starting with quote at the beginning
+// of s, and the number of bytes it spans.
+func scanQuoted(s string, quote byte, line int) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 < len(s) && (s[i+1] == quote || s[i+1] == '\\') {
+				i++
+			}
+		case '\n':
+			return "", 0, syntaxErrorf(line, "unterminated string")
+		}
+		b.WriteByte(s[i])
+	}
+	return "", 0, syntaxErrorf(line, "unterminated string")
+}
+
+// followsOperand reports whether the last of tokens ends an operand,
+// in which case a '/' is a division rather than the start of a regex.
+func followsOperand(tokens []token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	switch last := tokens[len(tokens)-1]; last.kind {
+	case tokenReference, tokenString, tokenNumber, tokenDuration, tokenRegex, tokenRParen:
+		return true
+	case tokenIdent:
+		return last.text != "AND" && last.text != "OR" && last.text != "lambda"
+	}
+	return false
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isIdentByte(s string, i int) bool {
+	return i < len(s) && (isLetter(s[i]) || isDigit(s[i]) || s[i] == '_')
+}
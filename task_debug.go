@@ -0,0 +1,108 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limits of a debug run of a task.
+const (
+	// DefaultTaskDebugTimeout is how long a debug run may use the authorization
+	// of its task if the request sets no timeout.
+	DefaultTaskDebugTimeout = time.Minute
+	// MaxTaskDebugTimeout is the longest a debug run may use the authorization of its task.
+	MaxTaskDebugTimeout = 10 * time.Minute
+
+	// DefaultTaskDebugSampleRows is how many rows of each table are sampled
+	// if the request sets no limit.
+	DefaultTaskDebugSampleRows = 10
+	// MaxTaskDebugSampleRows is the most rows of each table that may be sampled.
+	MaxTaskDebugSampleRows = 1000
+)
+
+// TaskDebugRequest describes a debug run of a task.
+type TaskDebugRequest struct {
+	// ScheduledFor is the time the run acts as if it were scheduled for.
+	ScheduledFor time.Time
+
+	// ScratchBucket is the bucket the task writes to instead of its own buckets.
+	// If it is empty, the writes of the task are dropped.
+	ScratchBucket string
+
+	// Timeout bounds how long the run may use the authorization of the task.
+	Timeout time.Duration
+
+	// SampleRows is the most rows of each table that are sampled.
+	SampleRows int
+}
+
+// Validate returns an error if the request is invalid, and sets the defaults
+// of the fields that are not set.
+func (r *TaskDebugRequest) Validate() error {
+	if r.Timeout < 0 || r.Timeout > MaxTaskDebugTimeout {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("timeout must be between 0 and %s", MaxTaskDebugTimeout),
+		}
+	}
+	if r.SampleRows < 0 || r.SampleRows > MaxTaskDebugSampleRows {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("sample rows must be between 0 and %d", MaxTaskDebugSampleRows),
+		}
+	}
+	if r.Timeout == 0 {
+		r.Timeout = DefaultTaskDebugTimeout
+	}
+	if r.SampleRows == 0 {
+		r.SampleRows = DefaultTaskDebugSampleRows
+	}
+	return nil
+}
+
+// TaskDebugRun is the outcome of a debug run of a task.
+type TaskDebugRun struct {
+	TaskID       ID        `json:"taskID"`
+	ScheduledFor time.Time `json:"scheduledFor"`
+	StartedAt    time.Time `json:"startedAt"`
+	FinishedAt   time.Time `json:"finishedAt"`
+
+	// Script is the script of the task as it was rewritten for the debug run.
+	Script string `json:"script"`
+
+	// TraceID identifies the trace of the run, which is always sampled.
+	TraceID string `json:"traceID,omitempty"`
+
+	// Samples are the first rows of the tables after each stage of the
+	// pipelines of the task, and of its results.
+	Samples []*TaskDebugSample `json:"samples"`
+
+	// Error is the error the run failed with, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// TaskDebugSample is a sample of the tables of a result of a debug run.
+type TaskDebugSample struct {
+	// Name is the name of the result, which is the name of the stage the tables
+	// were sampled after for the stages of a pipeline.
+	Name   string            `json:"name"`
+	Tables []*TaskDebugTable `json:"tables"`
+}
+
+// TaskDebugTable is a sample of the rows of a table.
+type TaskDebugTable struct {
+	Group   map[string]interface{} `json:"group"`
+	Columns []string               `json:"columns"`
+	Rows    [][]interface{}        `json:"rows"`
+	// Truncated is true if the table has more rows than were sampled.
+	Truncated bool `json:"truncated"`
+}
+
+// TaskDebugService runs tasks once to debug them, without writing their results.
+type TaskDebugService interface {
+	// DebugRun runs the task once with its own authorization, for at most the
+	// timeout of the request, and returns samples of its tables.
+	// The writes of the task are dropped or written to the scratch bucket.
+	DebugRun(ctx context.Context, taskID ID, req TaskDebugRequest) (*TaskDebugRun, error)
+}
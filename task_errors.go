@@ -68,6 +68,13 @@ var (
 		Code: EConflict,
 	}
 
+	// ErrTaskRunOnceCreated is returned from CreateNextRun if the task runs once
+	// and its run has already been created.
+	ErrTaskRunOnceCreated = &Error{
+		Code: EInvalid,
+		Msg:  "task runs once and its run has already been created",
+	}
+
 	// ErrOutOfBoundsLimit is returned with FindRuns is called with an invalid filter limit.
 	ErrOutOfBoundsLimit = &Error{
 		Code: EUnprocessableEntity,
@@ -113,6 +120,26 @@ func ErrTaskOptionParse(err error) *Error {
 	}
 }
 
+// ErrRunMemoryLimitExceeded is returned as the result of a run that allocated more memory than its task allows.
+func ErrRunMemoryLimitExceeded(quota int64, err error) *Error {
+	return &Error{
+		Code: ELimitExceeded,
+		Msg:  fmt.Sprintf("run exceeded memory quota of %d bytes", quota),
+		Op:   "task/executor",
+		Err:  err,
+	}
+}
+
+// ErrRunDurationLimitExceeded is returned as the result of a run that executed longer than its task allows.
+func ErrRunDurationLimitExceeded(d time.Duration, err error) *Error {
+	return &Error{
+		Code: ELimitExceeded,
+		Msg:  fmt.Sprintf("run exceeded max duration of %s", d),
+		Op:   "task/executor",
+		Err:  err,
+	}
+}
+
 // ErrRunNotDueYet is returned from CreateNextRun if a run is not yet due.
 func ErrRunNotDueYet(dueAt int64) *Error {
 	return &Error{
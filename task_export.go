@@ -0,0 +1,175 @@
+package influxdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TaskExportVersion is the version of the task export format written by this instance.
+const TaskExportVersion = 1
+
+// TaskExportMaxRuns is the maximum number of recent runs included in a task export.
+const TaskExportMaxRuns = 100
+
+// TaskExport is the portable representation of a task.
+// It is used to migrate tasks between instances or to check them into version control.
+type TaskExport struct {
+	Version    int    `json:"version"`
+	ExportedAt string `json:"exportedAt,omitempty"`
+
+	// SourceTaskID and SourceOrg identify the task on the instance it was exported from.
+	SourceTaskID ID     `json:"sourceTaskID,omitempty"`
+	SourceOrg    string `json:"sourceOrg,omitempty"`
+
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Flux        string `json:"flux"`
+
+	// Every, Cron, Offset and At are informational; the options are read from Flux on import.
+	Every  string `json:"every,omitempty"`
+	Cron   string `json:"cron,omitempty"`
+	Offset string `json:"offset,omitempty"`
+	At     string `json:"at,omitempty"`
+
+	MemoryBytesQuota   int64  `json:"memoryBytesQuota,omitempty"`
+	MaxDuration        string `json:"maxDuration,omitempty"`
+	DeleteAfterSuccess bool   `json:"deleteAfterSuccess,omitempty"`
+
+	Labels []TaskExportLabel `json:"labels"`
+	Owners []TaskExportOwner `json:"owners"`
+
+	// Runs holds recent runs and their logs, if they were requested on export.
+	Runs []*Run `json:"runs,omitempty"`
+}
+
+// TaskExportLabel is a label of an exported task.
+// Labels are matched by name on import, and created if missing.
+type TaskExportLabel struct {
+	Name       string            `json:"name"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// TaskExportOwner is an owner of an exported task.
+// Owners are matched by user name on import; the ID refers to the source instance.
+type TaskExportOwner struct {
+	ID   ID     `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// NewTaskExport returns the export of t without labels, owners or runs.
+func NewTaskExport(t *Task, now time.Time) *TaskExport {
+	return &TaskExport{
+		Version:            TaskExportVersion,
+		ExportedAt:         now.UTC().Format(time.RFC3339),
+		SourceTaskID:       t.ID,
+		SourceOrg:          t.Organization,
+		Name:               t.Name,
+		Description:        t.Description,
+		Status:             t.Status,
+		Flux:               t.Flux,
+		Every:              t.Every,
+		Cron:               t.Cron,
+		Offset:             t.Offset,
+		At:                 t.At,
+		MemoryBytesQuota:   t.MemoryBytesQuota,
+		MaxDuration:        t.MaxDuration,
+		DeleteAfterSuccess: t.DeleteAfterSuccess,
+		Labels:             []TaskExportLabel{},
+		Owners:             []TaskExportOwner{},
+	}
+}
+
+// Validate returns an error if the export cannot be imported by this instance.
+func (e *TaskExport) Validate() error {
+	switch {
+	case e.Version < 1 || e.Version > TaskExportVersion:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unsupported task export version %d", e.Version),
+		}
+	case e.Flux == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "task export is missing flux",
+		}
+	}
+	for _, l := range e.Labels {
+		if l.Name == "" {
+			return &Error{
+				Code: EInvalid,
+				Msg:  "task export has a label without a name",
+			}
+		}
+	}
+	return nil
+}
+
+// TaskCreate returns the values to create the exported task in the organization.
+// A non-empty status overrides the exported status.
+func (e *TaskExport) TaskCreate(orgID ID, org, token, status string) TaskCreate {
+	if status == "" {
+		status = e.Status
+	}
+	return TaskCreate{
+		Flux:               e.Flux,
+		Description:        e.Description,
+		Status:             status,
+		OrganizationID:     orgID,
+		Organization:       org,
+		Token:              token,
+		MemoryBytesQuota:   e.MemoryBytesQuota,
+		MaxDuration:        e.MaxDuration,
+		DeleteAfterSuccess: e.DeleteAfterSuccess,
+	}
+}
+
+// TaskImport is the set of values to import exported tasks into an organization.
+type TaskImport struct {
+	OrganizationID ID     `json:"orgID,omitempty"`
+	Organization   string `json:"org,omitempty"`
+	Token          string `json:"token,omitempty"`
+	// Status overrides the status of every imported task, i.e. to import them inactive.
+	Status string `json:"status,omitempty"`
+	// SkipRuns leaves the run history of the exports behind.
+	SkipRuns bool         `json:"skipRuns,omitempty"`
+	Tasks    []TaskExport `json:"tasks"`
+}
+
+// Validate returns an error if any of the tasks cannot be imported.
+func (i TaskImport) Validate() error {
+	switch {
+	case !i.OrganizationID.Valid() && i.Organization == "":
+		return &Error{
+			Code: EInvalid,
+			Msg:  "missing orgID and org",
+		}
+	case len(i.Tasks) == 0:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "at least one task is required",
+		}
+	case i.Status != "" && i.Status != TaskStatusActive && i.Status != TaskStatusInactive:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid task status: %q", i.Status),
+		}
+	}
+	for n := range i.Tasks {
+		if err := i.Tasks[n].Validate(); err != nil {
+			return &Error{
+				Msg: fmt.Sprintf("task at index %d: %s", n, ErrorMessage(err)),
+				Err: err,
+			}
+		}
+	}
+	return nil
+}
+
+// TaskRunImporter records the history of runs that were executed elsewhere, i.e. on another instance.
+type TaskRunImporter interface {
+	// ImportRuns records the finished runs as runs of the task.
+	// Runs that have not finished are ignored. It returns the number of runs recorded.
+	ImportRuns(ctx context.Context, taskID ID, runs []*Run) (int, error)
+}
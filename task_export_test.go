@@ -0,0 +1,120 @@
+package influxdb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+)
+
+func TestNewTaskExport(t *testing.T) {
+	task := &influxdb.Task{
+		ID:               1,
+		OrganizationID:   2,
+		Organization:     "org",
+		Name:             "downsample",
+		Description:      "downsample raw data",
+		Status:           influxdb.TaskStatusInactive,
+		Flux:             `option task = {name: "downsample", every: 1h}`,
+		Every:            "1h",
+		MemoryBytesQuota: 1024,
+		MaxDuration:      "1m",
+	}
+	now := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	e := influxdb.NewTaskExport(task, now)
+	if err := e.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.ExportedAt != "2019-01-02T03:04:05Z" || e.SourceTaskID != task.ID || e.SourceOrg != task.Organization {
+		t.Errorf("unexpected export metadata %+v", e)
+	}
+
+	tc := e.TaskCreate(3, "other", "token", "")
+	if tc.Flux != task.Flux || tc.Description != task.Description || tc.Status != task.Status {
+		t.Errorf("unexpected task create %+v", tc)
+	}
+	if tc.OrganizationID != 3 || tc.Organization != "other" || tc.Token != "token" {
+		t.Errorf("unexpected task create organization %+v", tc)
+	}
+	if tc.MemoryBytesQuota != task.MemoryBytesQuota || tc.MaxDuration != task.MaxDuration {
+		t.Errorf("unexpected task create limits %+v", tc)
+	}
+
+	if tc := e.TaskCreate(3, "", "", influxdb.TaskStatusActive); tc.Status != influxdb.TaskStatusActive {
+		t.Errorf("expected status override, got %q", tc.Status)
+	}
+}
+
+func TestTaskImport_Validate(t *testing.T) {
+	valid := influxdb.TaskExport{Version: influxdb.TaskExportVersion, Flux: "x"}
+
+	tests := []struct {
+		name    string
+		imp     influxdb.TaskImport
+		wantErr string
+	}{
+		{
+			name: "valid",
+			imp:  influxdb.TaskImport{Organization: "org", Tasks: []influxdb.TaskExport{valid}},
+		},
+		{
+			name:    "missing org",
+			imp:     influxdb.TaskImport{Tasks: []influxdb.TaskExport{valid}},
+			wantErr: "missing orgID and org",
+		},
+		{
+			name:    "no tasks",
+			imp:     influxdb.TaskImport{OrganizationID: 1},
+			wantErr: "at least one task is required",
+		},
+		{
+			name:    "invalid status",
+			imp:     influxdb.TaskImport{OrganizationID: 1, Status: "paused", Tasks: []influxdb.TaskExport{valid}},
+			wantErr: `invalid task status: "paused"`,
+		},
+		{
+			name: "unsupported version",
+			imp: influxdb.TaskImport{OrganizationID: 1, Tasks: []influxdb.TaskExport{
+				valid,
+				{Version: influxdb.TaskExportVersion + 1, Flux: "x"},
+			}},
+			wantErr: "task at index 1: unsupported task export version 2",
+		},
+		{
+			name: "missing flux",
+			imp: influxdb.TaskImport{OrganizationID: 1, Tasks: []influxdb.TaskExport{
+				{Version: influxdb.TaskExportVersion},
+			}},
+			wantErr: "task at index 0: task export is missing flux",
+		},
+		{
+			name: "unnamed label",
+			imp: influxdb.TaskImport{OrganizationID: 1, Tasks: []influxdb.TaskExport{
+				{Version: influxdb.TaskExportVersion, Flux: "x", Labels: []influxdb.TaskExportLabel{{}}},
+			}},
+			wantErr: "task at index 0: task export has a label without a name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.imp.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tt.wantErr)
+			}
+			if got := influxdb.ErrorMessage(err); got != tt.wantErr {
+				t.Errorf("got error %q, want %q", got, tt.wantErr)
+			}
+			if code := influxdb.ErrorCode(err); code != influxdb.EInvalid {
+				t.Errorf("got code %q, want %q", code, influxdb.EInvalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package influxdb
+
+import "context"
+
+// ErrRunNotInProgress is returned when streaming the logs of a run that is not executing.
+var ErrRunNotInProgress = &Error{
+	Code: EInvalid,
+	Msg:  "run is not in progress",
+}
+
+// RunLogStreamer streams the log lines of runs while they execute,
+// so they can be watched live instead of polling FindLogs.
+type RunLogStreamer interface {
+	// StreamRunLogs returns a channel receiving the log lines added to an in-progress run from now on.
+	// The channel is closed once the run finishes or ctx is done.
+	// ErrRunNotInProgress is returned if the run is not executing.
+	StreamRunLogs(ctx context.Context, taskID, runID ID) (<-chan *Log, error)
+}
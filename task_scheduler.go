@@ -0,0 +1,74 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// Scheduling states of a claimed task, as reported in a TaskSchedulerState.
+const (
+	// TaskSchedulerStatusInactive means the task is claimed but inactive, so no runs are started.
+	TaskSchedulerStatusInactive = "inactive"
+	// TaskSchedulerStatusWaiting means the task is not due until its next due time.
+	TaskSchedulerStatusWaiting = "waiting"
+	// TaskSchedulerStatusDue means the task is due and a run starts on the next tick.
+	TaskSchedulerStatusDue = "due"
+	// TaskSchedulerStatusRunning means runs of the task are executing and it is not due.
+	TaskSchedulerStatusRunning = "running"
+	// TaskSchedulerStatusBlocked means the task is due, but every concurrency slot is busy.
+	TaskSchedulerStatusBlocked = "blocked"
+)
+
+// SchedulerState is a snapshot of the task scheduler of an instance,
+// used by operators to find out why a task did or did not run.
+type SchedulerState struct {
+	// InstanceID identifies the instance whose scheduler holds the claims.
+	InstanceID string
+
+	// Now is the time of the scheduler clock, which only advances on ticks.
+	Now time.Time
+
+	// LastTick is the wall time at which the last tick finished.
+	LastTick time.Time
+	// TickLatency is how long after the scheduled second the last tick finished.
+	TickLatency time.Duration
+	// TickDuration is how long the last tick took to start due runs.
+	TickDuration time.Duration
+
+	// Backlog is the number of claimed tasks that are due, or have queued manual runs,
+	// but have not started a run yet.
+	Backlog int
+
+	// Tasks are the tasks claimed by the scheduler, ordered by ID.
+	Tasks []TaskSchedulerState
+}
+
+// TaskSchedulerState is the scheduling state of a single claimed task.
+type TaskSchedulerState struct {
+	TaskID          ID
+	OrganizationID  ID
+	AuthorizationID ID
+	Name            string
+
+	// Status is one of the TaskSchedulerStatus constants.
+	Status string
+
+	// ClaimedBy is the instance holding the claim, and ClaimedAt when it was claimed.
+	ClaimedBy string
+	ClaimedAt time.Time
+
+	// NextDue is when the next scheduled run is due.
+	NextDue time.Time
+	// HasQueue is true if manual runs are queued for the task.
+	HasQueue bool
+
+	// RunsActive is the number of runs executing, out of at most MaxConcurrency.
+	RunsActive     int
+	MaxConcurrency int
+}
+
+// SchedulerStateService reports the state of the task scheduler.
+type SchedulerStateService interface {
+	// SchedulerState returns a snapshot of the scheduler and the tasks it has claimed.
+	SchedulerState(ctx context.Context) (*SchedulerState, error)
+}
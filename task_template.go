@@ -0,0 +1,358 @@
+package influxdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
+)
+
+// ErrTaskTemplateNotFound is the error msg for a missing task template.
+const ErrTaskTemplateNotFound = "task template not found"
+
+// ops for task template error.
+const (
+	OpFindTaskTemplateByID = "FindTaskTemplateByID"
+	OpFindTaskTemplates    = "FindTaskTemplates"
+	OpCreateTaskTemplate   = "CreateTaskTemplate"
+	OpUpdateTaskTemplate   = "UpdateTaskTemplate"
+	OpDeleteTaskTemplate   = "DeleteTaskTemplate"
+)
+
+// Task template parameter types.
+const (
+	TaskTemplateParamString   = "string"
+	TaskTemplateParamDuration = "duration"
+	TaskTemplateParamInt      = "int"
+	TaskTemplateParamFloat    = "float"
+)
+
+// TaskTemplateService describes a service for managing task templates.
+type TaskTemplateService interface {
+	// FindTaskTemplateByID finds a single task template by its ID.
+	FindTaskTemplateByID(ctx context.Context, id ID) (*TaskTemplate, error)
+
+	// FindTaskTemplates returns all task templates that match the filter.
+	FindTaskTemplates(ctx context.Context, filter TaskTemplateFilter) ([]*TaskTemplate, error)
+
+	// CreateTaskTemplate creates a new task template and assigns it an ID.
+	CreateTaskTemplate(ctx context.Context, tt *TaskTemplate) error
+
+	// UpdateTaskTemplate updates a single task template with a changeset.
+	UpdateTaskTemplate(ctx context.Context, id ID, upd TaskTemplateUpdate) (*TaskTemplate, error)
+
+	// DeleteTaskTemplate removes a task template by its ID.
+	DeleteTaskTemplate(ctx context.Context, id ID) error
+}
+
+// TaskTemplate is parameterized Flux that is instantiated into concrete tasks.
+//
+// Flux references parameters with Go template actions, i.e. {{ .bucket }}.
+// Parameter values are substituted verbatim, except that string values are
+// escaped so they can be placed inside a Flux string literal:
+//
+//	option task = {name: "downsample-{{ .bucket }}", every: {{ .every }}}
+//	from(bucket: "{{ .bucket }}")
+type TaskTemplate struct {
+	ID             ID                  `json:"id,omitempty"`
+	OrganizationID ID                  `json:"orgID"`
+	Name           string              `json:"name"`
+	Description    string              `json:"description,omitempty"`
+	Flux           string              `json:"flux"`
+	Params         []TaskTemplateParam `json:"params"`
+	CRUDLog
+}
+
+// TaskTemplateParam describes a parameter of a task template.
+// Parameters without a default must be supplied when instantiating the template.
+type TaskTemplateParam struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Description string  `json:"description,omitempty"`
+	Default     *string `json:"default,omitempty"`
+}
+
+var taskTemplateParamNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Valid returns an error if the parameter name, type or default value is invalid.
+func (p TaskTemplateParam) Valid() error {
+	if !taskTemplateParamNameRegexp.MatchString(p.Name) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid task template param name %q", p.Name),
+		}
+	}
+	if p.Default != nil {
+		if _, err := p.render(*p.Default); err != nil {
+			return err
+		}
+		return nil
+	}
+	// Check the type by rendering a value every type accepts.
+	_, err := p.render("0")
+	return err
+}
+
+// render validates value against the parameter type and returns the text
+// that is substituted into the Flux of the template.
+func (p TaskTemplateParam) render(value string) (string, error) {
+	invalid := func(err error) error {
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid value %q for %s param %q", value, p.Type, p.Name),
+			Err:  err,
+		}
+	}
+
+	switch p.Type {
+	case TaskTemplateParamString:
+		return escapeFluxString(value), nil
+	case TaskTemplateParamDuration:
+		if _, err := parser.ParseSignedDuration(value); err != nil {
+			return "", invalid(err)
+		}
+		return value, nil
+	case TaskTemplateParamInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return "", invalid(err)
+		}
+		return value, nil
+	case TaskTemplateParamFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", invalid(err)
+		}
+		return value, nil
+	}
+	return "", &Error{
+		Code: EInvalid,
+		Msg:  fmt.Sprintf("invalid type %q for task template param %q", p.Type, p.Name),
+	}
+}
+
+var fluxStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// escapeFluxString escapes s so it can be placed inside a Flux string literal
+// without terminating it.
+func escapeFluxString(s string) string {
+	return fluxStringEscaper.Replace(s)
+}
+
+// Valid returns an error if the template cannot be instantiated.
+// The Flux is checked by instantiating it with the defaults of its params,
+// or a placeholder for params without a default.
+func (tt *TaskTemplate) Valid() error {
+	if tt.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "task template name is required",
+		}
+	}
+	if !tt.OrganizationID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "task template orgID is required",
+		}
+	}
+	if tt.Flux == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "task template flux is required",
+		}
+	}
+
+	seen := make(map[string]bool, len(tt.Params))
+	placeholders := make(map[string]string, len(tt.Params))
+	for _, p := range tt.Params {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+		if seen[p.Name] {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("duplicate task template param %q", p.Name),
+			}
+		}
+		seen[p.Name] = true
+		if p.Default == nil {
+			placeholders[p.Name] = taskTemplatePlaceholder(p.Type)
+		}
+	}
+
+	_, err := tt.Instantiate(placeholders)
+	return err
+}
+
+func taskTemplatePlaceholder(typ string) string {
+	switch typ {
+	case TaskTemplateParamDuration:
+		return "1h"
+	case TaskTemplateParamString:
+		return "placeholder"
+	}
+	return "0"
+}
+
+// Instantiate substitutes params into the Flux of the template and returns the resulting script.
+// Params that are not supplied take their default value.
+func (tt *TaskTemplate) Instantiate(params map[string]string) (string, error) {
+	declared := make(map[string]bool, len(tt.Params))
+	data := make(map[string]string, len(tt.Params))
+	for _, p := range tt.Params {
+		declared[p.Name] = true
+
+		v, ok := params[p.Name]
+		if !ok {
+			if p.Default == nil {
+				return "", &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("missing value for task template param %q", p.Name),
+				}
+			}
+			v = *p.Default
+		}
+
+		s, err := p.render(v)
+		if err != nil {
+			return "", err
+		}
+		data[p.Name] = s
+	}
+
+	for name := range params {
+		if !declared[name] {
+			return "", &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("unknown task template param %q", name),
+			}
+		}
+	}
+
+	tmpl, err := template.New(tt.Name).Option("missingkey=error").Parse(tt.Flux)
+	if err != nil {
+		return "", &Error{
+			Code: EInvalid,
+			Msg:  "invalid task template flux",
+			Err:  err,
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", &Error{
+			Code: EInvalid,
+			Msg:  "failed to instantiate task template",
+			Err:  err,
+		}
+	}
+
+	flux := buf.String()
+	if pkg := parser.ParseSource(flux); ast.Check(pkg) > 0 {
+		return "", &Error{
+			Code: EInvalid,
+			Msg:  "task template does not instantiate to valid flux",
+			Err:  ast.GetError(pkg),
+		}
+	}
+	return flux, nil
+}
+
+// TaskTemplateFilter represents a set of filters that restrict the returned task templates.
+type TaskTemplateFilter struct {
+	OrganizationID *ID
+}
+
+// TaskTemplateUpdate is the set of changes to a task template.
+type TaskTemplateUpdate struct {
+	Name        *string             `json:"name,omitempty"`
+	Description *string             `json:"description,omitempty"`
+	Flux        *string             `json:"flux,omitempty"`
+	Params      []TaskTemplateParam `json:"params,omitempty"`
+}
+
+// Valid returns an error if the update is empty.
+func (u TaskTemplateUpdate) Valid() error {
+	if u.Name == nil && u.Description == nil && u.Flux == nil && u.Params == nil {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "task template update must change name, description, flux or params",
+		}
+	}
+	return nil
+}
+
+// Apply applies the update to the template and validates the result.
+func (u TaskTemplateUpdate) Apply(tt *TaskTemplate) error {
+	if u.Name != nil {
+		tt.Name = *u.Name
+	}
+	if u.Description != nil {
+		tt.Description = *u.Description
+	}
+	if u.Flux != nil {
+		tt.Flux = *u.Flux
+	}
+	if u.Params != nil {
+		tt.Params = u.Params
+	}
+	return tt.Valid()
+}
+
+// TasksFromTemplate is the set of values to create tasks from a template.
+// One task is created for each element of Params.
+type TasksFromTemplate struct {
+	TemplateID ID                  `json:"templateID"`
+	Status     string              `json:"status,omitempty"`
+	Token      string              `json:"token,omitempty"`
+	Params     []map[string]string `json:"params"`
+}
+
+// Validate returns an error if the request cannot create any tasks.
+func (t TasksFromTemplate) Validate() error {
+	switch {
+	case !t.TemplateID.Valid():
+		return &Error{
+			Code: EInvalid,
+			Msg:  "templateID is required",
+		}
+	case len(t.Params) == 0:
+		return &Error{
+			Code: EInvalid,
+			Msg:  "at least one set of params is required",
+		}
+	case t.Status != "" && t.Status != TaskStatusActive && t.Status != TaskStatusInactive:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("invalid task status: %q", t.Status),
+		}
+	}
+	return nil
+}
+
+// TaskCreates instantiates the template once per set of params in t.
+// No task create is returned if any set of params fails to instantiate.
+func (tt *TaskTemplate) TaskCreates(t TasksFromTemplate) ([]TaskCreate, error) {
+	tcs := make([]TaskCreate, 0, len(t.Params))
+	for i, params := range t.Params {
+		flux, err := tt.Instantiate(params)
+		if err != nil {
+			return nil, &Error{
+				Msg: fmt.Sprintf("params at index %d: %s", i, ErrorMessage(err)),
+				Err: err,
+			}
+		}
+		tcs = append(tcs, TaskCreate{
+			Flux:           flux,
+			Description:    tt.Description,
+			Status:         t.Status,
+			OrganizationID: tt.OrganizationID,
+			Token:          t.Token,
+		})
+	}
+	return tcs, nil
+}
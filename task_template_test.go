@@ -0,0 +1,142 @@
+package influxdb_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/influxdb"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestTaskTemplate_Instantiate(t *testing.T) {
+	tt := &influxdb.TaskTemplate{
+		OrganizationID: 1,
+		Name:           "downsample",
+		Flux: `option task = {name: "downsample-{{ .bucket }}", every: {{ .every }}}
+from(bucket: "{{ .bucket }}") |> range(start: -{{ .every }}) |> filter(fn: (r) => r._value > {{ .threshold }})`,
+		Params: []influxdb.TaskTemplateParam{
+			{Name: "bucket", Type: influxdb.TaskTemplateParamString},
+			{Name: "every", Type: influxdb.TaskTemplateParamDuration, Default: strPtr("1h")},
+			{Name: "threshold", Type: influxdb.TaskTemplateParamFloat, Default: strPtr("0.5")},
+		},
+	}
+	if err := tt.Valid(); err != nil {
+		t.Fatalf("unexpected invalid template: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			params: map[string]string{"bucket": "cpu"},
+			want: `option task = {name: "downsample-cpu", every: 1h}
+from(bucket: "cpu") |> range(start: -1h) |> filter(fn: (r) => r._value > 0.5)`,
+		},
+		{
+			name:   "overrides",
+			params: map[string]string{"bucket": "mem", "every": "5m", "threshold": "10"},
+			want: `option task = {name: "downsample-mem", every: 5m}
+from(bucket: "mem") |> range(start: -5m) |> filter(fn: (r) => r._value > 10)`,
+		},
+		{
+			name:   "string values cannot escape their literal",
+			params: map[string]string{"bucket": `x") |> drop(columns: ["${a}"]) //`},
+			want: `option task = {name: "downsample-x\") |> drop(columns: [\"${a}\"]) //", every: 1h}
+from(bucket: "x\") |> drop(columns: [\"${a}\"]) //") |> range(start: -1h) |> filter(fn: (r) => r._value > 0.5)`,
+		},
+		{
+			name:    "missing required param",
+			params:  map[string]string{},
+			wantErr: true,
+		},
+		{
+			name:    "unknown param",
+			params:  map[string]string{"bucket": "cpu", "other": "x"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			params:  map[string]string{"bucket": "cpu", "every": "1h) |> yield("},
+			wantErr: true,
+		},
+		{
+			name:    "invalid float",
+			params:  map[string]string{"bucket": "cpu", "threshold": "0 or true"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tt.Instantiate(tc.params)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got flux %q", got)
+				}
+				if code := influxdb.ErrorCode(err); code != influxdb.EInvalid {
+					t.Fatalf("expected invalid error, got %q: %v", code, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("unexpected flux:\ngot  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTaskTemplate_Valid(t *testing.T) {
+	tests := []struct {
+		name string
+		tt   influxdb.TaskTemplate
+	}{
+		{
+			name: "missing name",
+			tt:   influxdb.TaskTemplate{OrganizationID: 1, Flux: `from(bucket: "b")`},
+		},
+		{
+			name: "undeclared param",
+			tt:   influxdb.TaskTemplate{OrganizationID: 1, Name: "n", Flux: `from(bucket: "{{ .bucket }}")`},
+		},
+		{
+			name: "duplicate param",
+			tt: influxdb.TaskTemplate{OrganizationID: 1, Name: "n", Flux: `from(bucket: "{{ .b }}")`, Params: []influxdb.TaskTemplateParam{
+				{Name: "b", Type: influxdb.TaskTemplateParamString},
+				{Name: "b", Type: influxdb.TaskTemplateParamString},
+			}},
+		},
+		{
+			name: "unknown param type",
+			tt: influxdb.TaskTemplate{OrganizationID: 1, Name: "n", Flux: `from(bucket: "{{ .b }}")`, Params: []influxdb.TaskTemplateParam{
+				{Name: "b", Type: "bucket"},
+			}},
+		},
+		{
+			name: "invalid default",
+			tt: influxdb.TaskTemplate{OrganizationID: 1, Name: "n", Flux: `x = {{ .n }}`, Params: []influxdb.TaskTemplateParam{
+				{Name: "n", Type: influxdb.TaskTemplateParamInt, Default: strPtr("1.5")},
+			}},
+		},
+		{
+			name: "does not instantiate to flux",
+			tt: influxdb.TaskTemplate{OrganizationID: 1, Name: "n", Flux: `from(bucket: {{ .b }}`, Params: []influxdb.TaskTemplateParam{
+				{Name: "b", Type: influxdb.TaskTemplateParamString},
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.tt.Valid(); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
@@ -137,6 +137,41 @@ from(bucket: "x")
 			t.Fatalf(cmp.Diff(*tu.Flux, expscript))
 		}
 	})
+	t.Run("switching from every to at", func(t *testing.T) {
+		at := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+		tu := &platform.TaskUpdate{}
+		tu.Options.At = &at
+		if err := tu.UpdateFlux(`option task = {every: 20s, name: "foo"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(*tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !op.Every.IsZero() {
+			t.Fatalf("expected every to be 0 but was %s", op.Every)
+		}
+		if op.At == nil || !op.At.Equal(at) {
+			t.Fatalf("expected at to be %s but was %v", at, op.At)
+		}
+	})
+	t.Run("switching from at to cron", func(t *testing.T) {
+		tu := &platform.TaskUpdate{}
+		tu.Options.Cron = "* * * * *"
+		if err := tu.UpdateFlux(`option task = {at: 2019-06-01T00:00:00Z, name: "foo"} from(bucket:"x") |> range(start:-1h)`); err != nil {
+			t.Fatal(err)
+		}
+		op, err := options.FromScript(*tu.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.At != nil {
+			t.Fatalf("expected at to be unset but was %s", op.At)
+		}
+		if op.Cron != "* * * * *" {
+			t.Fatalf("expected Cron to be \"* * * * *\" but was %s", op.Cron)
+		}
+	})
 
 }
 
@@ -162,3 +197,46 @@ func TestRun(t *testing.T) {
 		}
 	})
 }
+
+func TestTaskCreate_Validate(t *testing.T) {
+	orgID := platform.ID(1)
+	for _, tc := range []struct {
+		name    string
+		create  platform.TaskCreate
+		wantErr bool
+	}{
+		{
+			name:   "no limits",
+			create: platform.TaskCreate{Flux: "x", OrganizationID: orgID},
+		},
+		{
+			name:   "valid limits",
+			create: platform.TaskCreate{Flux: "x", OrganizationID: orgID, MemoryBytesQuota: 1024, MaxDuration: "30s"},
+		},
+		{
+			name:    "negative memory quota",
+			create:  platform.TaskCreate{Flux: "x", OrganizationID: orgID, MemoryBytesQuota: -1},
+			wantErr: true,
+		},
+		{
+			name:    "unparsable max duration",
+			create:  platform.TaskCreate{Flux: "x", OrganizationID: orgID, MaxDuration: "forever"},
+			wantErr: true,
+		},
+		{
+			name:    "zero max duration",
+			create:  platform.TaskCreate{Flux: "x", OrganizationID: orgID, MaxDuration: "0s"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.create.Validate()
+			if tc.wantErr && err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package influxdb
+
+import (
+	"context"
+	"strings"
+)
+
+// ErrTeamNotFound is the error msg for a missing team.
+const ErrTeamNotFound = "team not found"
+
+// ops for team errors and op log.
+const (
+	OpFindTeamByID = "FindTeamByID"
+	OpFindTeams    = "FindTeams"
+	OpCreateTeam   = "CreateTeam"
+	OpUpdateTeam   = "UpdateTeam"
+	OpDeleteTeam   = "DeleteTeam"
+)
+
+// Team is a group of users of an organization. Resources are shared with all
+// the members of a team by mapping the team to them as an owner or member,
+// with a user resource mapping of the team mapping type.
+//
+// The users of a team are user resource mappings to the team.
+type Team struct {
+	ID          ID     `json:"id,omitempty"`
+	OrgID       ID     `json:"orgID,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	CRUDLog
+}
+
+// Valid returns an error if the team has no name or organization.
+func (t *Team) Valid() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "team name is required",
+		}
+	}
+	if !t.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is required",
+		}
+	}
+	return nil
+}
+
+// TeamUpdate is the changeset of a team.
+type TeamUpdate struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Apply applies the changeset to the team.
+func (u TeamUpdate) Apply(t *Team) {
+	if u.Name != nil {
+		t.Name = *u.Name
+	}
+	if u.Description != nil {
+		t.Description = *u.Description
+	}
+}
+
+// TeamFilter selects teams.
+type TeamFilter struct {
+	ID    *ID
+	OrgID *ID
+	Name  *string
+}
+
+// TeamService manages the teams of organizations.
+type TeamService interface {
+	// FindTeamByID returns a single team by ID.
+	FindTeamByID(ctx context.Context, id ID) (*Team, error)
+
+	// FindTeams returns the teams that match the filter, and the total count of matching teams.
+	FindTeams(ctx context.Context, filter TeamFilter, opt ...FindOptions) ([]*Team, int, error)
+
+	// CreateTeam creates a team and sets its ID. Team names are unique within an organization.
+	CreateTeam(ctx context.Context, t *Team) error
+
+	// UpdateTeam updates a team with the changeset.
+	UpdateTeam(ctx context.Context, id ID, upd TeamUpdate) (*Team, error)
+
+	// DeleteTeam deletes a team, its users and the mappings of the team to resources.
+	DeleteTeam(ctx context.Context, id ID) error
+}
@@ -2,6 +2,8 @@ package influxdb
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -133,6 +135,12 @@ func (tc TelegrafConfig) TOML() string {
 %s`, interval.String(), plugins)
 }
 
+// Version identifies the contents of the telegraf toml config, so that agents can report the version they run.
+func (tc TelegrafConfig) Version() string {
+	sum := sha256.Sum256([]byte(tc.TOML()))
+	return hex.EncodeToString(sum[:8])
+}
+
 // telegrafConfigEncode is the helper struct for json encoding.
 type telegrafConfigEncode struct {
 	ID          ID     `json:"id"`
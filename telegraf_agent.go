@@ -0,0 +1,70 @@
+package influxdb
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// ops for telegraf agent errors.
+const (
+	OpCheckInTelegrafAgent = "CheckInTelegrafAgent"
+	OpFindTelegrafAgents   = "FindTelegrafAgents"
+)
+
+// TelegrafAgent is the state a telegraf agent reported when it last checked in
+// with the telegraf config it runs. An agent is identified by its config and the
+// host it runs on.
+type TelegrafAgent struct {
+	ConfigID ID     `json:"telegrafID"`
+	OrgID    ID     `json:"orgID,omitempty"`
+	Hostname string `json:"hostname"`
+	// Version is the version of telegraf the agent runs.
+	Version string `json:"version,omitempty"`
+	// ConfigVersion is the version of the telegraf config the agent runs,
+	// as given by the Version of the telegraf config it downloaded.
+	ConfigVersion string `json:"configVersion,omitempty"`
+	// LastError is the last error the agent reported, if any.
+	LastError string `json:"lastError,omitempty"`
+
+	FirstCheckIn time.Time `json:"firstCheckIn"`
+	LastCheckIn  time.Time `json:"lastCheckIn"`
+}
+
+// Valid returns an error if the agent is missing what identifies it.
+func (a *TelegrafAgent) Valid() error {
+	if !a.ConfigID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "telegraf agent config ID is required",
+		}
+	}
+	if strings.TrimSpace(a.Hostname) == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "telegraf agent hostname is required",
+		}
+	}
+	return nil
+}
+
+// Stale reports whether the agent runs a version of the telegraf config other than its current one.
+func (a *TelegrafAgent) Stale(tc *TelegrafConfig) bool {
+	return a.ConfigVersion != tc.Version()
+}
+
+// TelegrafAgentFilter selects telegraf agents.
+type TelegrafAgentFilter struct {
+	ConfigID *ID
+	OrgID    *ID
+}
+
+// TelegrafAgentService keeps track of the telegraf agents that run telegraf configs.
+type TelegrafAgentService interface {
+	// CheckInTelegrafAgent records the state a telegraf agent reports, replacing
+	// the state it reported before. The agent belongs to the organization of its config.
+	CheckInTelegrafAgent(ctx context.Context, a *TelegrafAgent) error
+
+	// FindTelegrafAgents returns the telegraf agents that match the filter, ordered by hostname.
+	FindTelegrafAgents(ctx context.Context, filter TelegrafAgentFilter) ([]*TelegrafAgent, error)
+}
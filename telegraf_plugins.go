@@ -0,0 +1,209 @@
+package influxdb
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/influxdb/telegraf/plugins"
+	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+)
+
+// TelegrafPluginInfo describes a telegraf plugin that telegraf configs may contain.
+type TelegrafPluginInfo struct {
+	Name        string       `json:"name"`
+	Type        plugins.Type `json:"type"`
+	Description string       `json:"description"`
+	// Config lists the fields of the config of the plugin.
+	Config []TelegrafPluginField `json:"config"`
+	// Sample is the TOML of the plugin with its default config.
+	Sample string `json:"sample"`
+}
+
+// TelegrafPluginField describes a field of the config of a telegraf plugin.
+// Its type is one of string, boolean, integer, number, array or object.
+type TelegrafPluginField struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+	// Items describes the elements of an array.
+	Items *TelegrafPluginField `json:"items,omitempty"`
+	// Fields describes the fields of an object.
+	Fields []TelegrafPluginField `json:"fields,omitempty"`
+}
+
+var telegrafPluginDescriptions = map[plugins.Type]map[string]string{
+	plugins.Input: {
+		"cpu":          "Metrics about cpu usage",
+		"disk":         "Metrics about disk usage by mount point",
+		"diskio":       "Metrics about disk IO by device",
+		"docker":       "Metrics about docker containers",
+		"file":         "Metrics parsed from the contents of files",
+		"kernel":       "Metrics about the linux kernel",
+		"kubernetes":   "Metrics about pods and containers from the kubelet API",
+		"logparser":    "Metrics parsed from the lines of log files",
+		"mem":          "Metrics about memory usage",
+		"net_response": "Metrics about the response of TCP or UDP services",
+		"net":          "Metrics about network interface usage",
+		"nginx":        "Metrics from the status page of nginx servers",
+		"processes":    "Metrics about the number of processes by state",
+		"procstat":     "Metrics about the processes of an executable",
+		"prometheus":   "Metrics scraped from prometheus endpoints",
+		"redis":        "Metrics from redis servers",
+		"swap":         "Metrics about swap memory usage",
+		"syslog":       "Metrics from syslog messages received over the network",
+		"system":       "Metrics about system load and uptime",
+		"tail":         "Metrics parsed from lines appended to files",
+	},
+	plugins.Output: {
+		"file":        "Writes metrics to files",
+		"influxdb_v2": "Writes metrics to an InfluxDB 2.0 bucket",
+	},
+}
+
+// TelegrafPlugins returns the telegraf plugins that telegraf configs may contain,
+// ordered by type then name.
+func TelegrafPlugins() []TelegrafPluginInfo {
+	ps := make([]TelegrafPluginInfo, 0, len(availableInputPlugins)+len(availableOutputPlugins))
+	for _, available := range []map[string](func() plugins.Config){availableInputPlugins, availableOutputPlugins} {
+		for _, fn := range available {
+			ps = append(ps, newTelegrafPluginInfo(fn()))
+		}
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		if ps[i].Type != ps[j].Type {
+			return ps[i].Type < ps[j].Type
+		}
+		return ps[i].Name < ps[j].Name
+	})
+	return ps
+}
+
+func newTelegrafPluginInfo(p plugins.Config) TelegrafPluginInfo {
+	return TelegrafPluginInfo{
+		Name:        p.PluginName(),
+		Type:        p.Type(),
+		Description: telegrafPluginDescriptions[p.Type()][p.PluginName()],
+		Config:      telegrafPluginFields(reflect.TypeOf(p).Elem()),
+		Sample:      p.TOML(),
+	}
+}
+
+// telegrafPluginFields returns the fields of a plugin config struct that are encoded as JSON.
+func telegrafPluginFields(t reflect.Type) []TelegrafPluginField {
+	fields := []TelegrafPluginField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous || name == "" || name == "-" {
+			continue
+		}
+		field := telegrafPluginField(f.Type)
+		field.Name = name
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func telegrafPluginField(t reflect.Type) TelegrafPluginField {
+	switch t.Kind() {
+	case reflect.Bool:
+		return TelegrafPluginField{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TelegrafPluginField{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return TelegrafPluginField{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		items := telegrafPluginField(t.Elem())
+		return TelegrafPluginField{Type: "array", Items: &items}
+	case reflect.Struct:
+		return TelegrafPluginField{Type: "object", Fields: telegrafPluginFields(t)}
+	case reflect.Ptr:
+		return telegrafPluginField(t.Elem())
+	default:
+		return TelegrafPluginField{Type: "string"}
+	}
+}
+
+// TelegrafOutputDefaults is where the influxdb_v2 outputs of a rendered telegraf config write to,
+// unless the config of an output sets it.
+type TelegrafOutputDefaults struct {
+	URL          string
+	Token        string
+	Organization string
+	Bucket       string
+}
+
+// DefaultTelegrafCollectionInterval is the collection interval in milliseconds
+// of rendered telegraf configs that have none.
+const DefaultTelegrafCollectionInterval = 10000
+
+// RenderTelegrafConfig returns the telegraf.conf of the telegraf config. Its influxdb_v2
+// outputs write to the defaults wherever their config is empty, and the config is given
+// an influxdb_v2 output if it has none, so the rendered config writes to InfluxDB as is.
+func RenderTelegrafConfig(tc TelegrafConfig, defaults TelegrafOutputDefaults) (string, error) {
+	if tc.Agent.Interval <= 0 {
+		tc.Agent.Interval = DefaultTelegrafCollectionInterval
+	}
+
+	hasInput := false
+	for _, p := range tc.Plugins {
+		if p.Config.Type() == plugins.Input {
+			hasInput = true
+			break
+		}
+	}
+	if !hasInput {
+		return "", &Error{
+			Code: EInvalid,
+			Msg:  "at least one input plugin is required",
+		}
+	}
+
+	ps := make([]TelegrafPlugin, 0, len(tc.Plugins)+1)
+	hasOutput := false
+	for _, p := range tc.Plugins {
+		if o, ok := p.Config.(*outputs.InfluxDBV2); ok {
+			o = applyTelegrafOutputDefaults(*o, defaults)
+			p = TelegrafPlugin{Comment: p.Comment, Config: o}
+			hasOutput = true
+		}
+		ps = append(ps, p)
+	}
+	if !hasOutput {
+		ps = append(ps, TelegrafPlugin{Config: applyTelegrafOutputDefaults(outputs.InfluxDBV2{}, defaults)})
+	}
+
+	for _, p := range ps {
+		o, ok := p.Config.(*outputs.InfluxDBV2)
+		if !ok {
+			continue
+		}
+		if len(o.URLs) == 0 || o.Token == "" || o.Organization == "" || o.Bucket == "" {
+			return "", &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("the %s output requires urls, a token, an organization and a bucket", o.PluginName()),
+			}
+		}
+	}
+
+	tc.Plugins = ps
+	return tc.TOML(), nil
+}
+
+func applyTelegrafOutputDefaults(o outputs.InfluxDBV2, defaults TelegrafOutputDefaults) *outputs.InfluxDBV2 {
+	if len(o.URLs) == 0 && defaults.URL != "" {
+		o.URLs = []string{defaults.URL}
+	}
+	if o.Token == "" {
+		o.Token = defaults.Token
+	}
+	if o.Organization == "" {
+		o.Organization = defaults.Organization
+	}
+	if o.Bucket == "" {
+		o.Bucket = defaults.Bucket
+	}
+	return &o
+}
@@ -0,0 +1,81 @@
+package influxdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb/telegraf/plugins"
+	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
+	"github.com/influxdata/influxdb/telegraf/plugins/outputs"
+)
+
+func TestTelegrafPlugins(t *testing.T) {
+	ps := TelegrafPlugins()
+	if len(ps) != len(availableInputPlugins)+len(availableOutputPlugins) {
+		t.Fatalf("expected every available plugin, got %d", len(ps))
+	}
+	for i, p := range ps {
+		if p.Description == "" || p.Sample == "" {
+			t.Errorf("expected the %s %s plugin to be described, got %+v", p.Type, p.Name, p)
+		}
+		if i > 0 && ps[i-1].Type == p.Type && ps[i-1].Name >= p.Name {
+			t.Errorf("expected the plugins ordered by name, got %s before %s", ps[i-1].Name, p.Name)
+		}
+	}
+
+	var output TelegrafPluginInfo
+	for _, p := range ps {
+		if p.Type == plugins.Output && p.Name == "file" {
+			output = p
+		}
+	}
+	want := []TelegrafPluginField{{
+		Name: "files",
+		Type: "array",
+		Items: &TelegrafPluginField{
+			Type:   "object",
+			Fields: []TelegrafPluginField{{Name: "type", Type: "string"}, {Name: "path", Type: "string"}},
+		},
+	}}
+	if diff := cmp.Diff(want, output.Config); diff != "" {
+		t.Errorf("unexpected config of the file output: %s", diff)
+	}
+}
+
+func TestRenderTelegrafConfig(t *testing.T) {
+	defaults := TelegrafOutputDefaults{URL: "http://localhost:9999", Token: "$INFLUX_TOKEN", Organization: "o", Bucket: "b"}
+
+	conf, err := RenderTelegrafConfig(TelegrafConfig{
+		Plugins: []TelegrafPlugin{{Config: &inputs.CPUStats{}}},
+	}, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`interval = "10s"`, "[[inputs.cpu]]", "[[outputs.influxdb_v2]]", `urls = ["http://localhost:9999"]`, `token = "$INFLUX_TOKEN"`, `organization = "o"`, `bucket = "b"`} {
+		if !strings.Contains(conf, s) {
+			t.Errorf("expected the rendered config to contain %s, got:\n%s", s, conf)
+		}
+	}
+
+	output := &outputs.InfluxDBV2{Bucket: "other"}
+	conf, err = RenderTelegrafConfig(TelegrafConfig{
+		Plugins: []TelegrafPlugin{{Config: &inputs.CPUStats{}}, {Config: output}},
+	}, defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(conf, "[[outputs.influxdb_v2]]") != 1 || !strings.Contains(conf, `bucket = "other"`) || !strings.Contains(conf, `organization = "o"`) {
+		t.Errorf("expected the output of the config to be completed, got:\n%s", conf)
+	}
+	if output.Organization != "" {
+		t.Errorf("expected the output of the config to be left as is, got %+v", output)
+	}
+
+	if _, err := RenderTelegrafConfig(TelegrafConfig{Plugins: []TelegrafPlugin{{Config: &outputs.File{}}}}, defaults); ErrorCode(err) != EInvalid {
+		t.Errorf("expected a config without inputs to be rejected, got %v", err)
+	}
+	if _, err := RenderTelegrafConfig(TelegrafConfig{Plugins: []TelegrafPlugin{{Config: &inputs.CPUStats{}}}}, TelegrafOutputDefaults{URL: "http://localhost:9999"}); ErrorCode(err) != EInvalid {
+		t.Errorf("expected an output without a bucket to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,512 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	annotationOneID   = "020f755c3c08e000"
+	annotationTwoID   = "020f755c3c08e001"
+	annotationThreeID = "020f755c3c08e002"
+	annotationFourID  = "020f755c3c08e003"
+)
+
+// AnnotationFields will include the IDGenerator, TimeGenerator, and the organizations
+// and annotations to populate the store with.
+type AnnotationFields struct {
+	IDGenerator   influxdb.IDGenerator
+	TimeGenerator influxdb.TimeGenerator
+	Organizations []*influxdb.Organization
+	Annotations   []*influxdb.Annotation
+}
+
+type annotationServiceF func(
+	init func(AnnotationFields, *testing.T) (influxdb.AnnotationService, func()),
+	t *testing.T,
+)
+
+// AnnotationService tests all the service functions.
+func AnnotationService(
+	init func(AnnotationFields, *testing.T) (influxdb.AnnotationService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   annotationServiceF
+	}{
+		{
+			name: "CreateAnnotations",
+			fn:   CreateAnnotations,
+		},
+		{
+			name: "FindAnnotations",
+			fn:   FindAnnotations,
+		},
+		{
+			name: "UpdateAnnotation",
+			fn:   UpdateAnnotation,
+		},
+		{
+			name: "DeleteAnnotation",
+			fn:   DeleteAnnotation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var annotationTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// newAnnotation returns an annotation of the service between the offsets from
+// annotationTime.
+func newAnnotation(id, orgID, stream, summary, service string, start, end time.Duration) *influxdb.Annotation {
+	a := &influxdb.Annotation{
+		OrgID:     MustIDBase16(orgID),
+		Stream:    stream,
+		Summary:   summary,
+		Tags:      map[string]string{"service": service},
+		StartTime: annotationTime.Add(start),
+		EndTime:   annotationTime.Add(end),
+	}
+	if id != "" {
+		a.ID = MustIDBase16(id)
+		a.CRUDLog = influxdb.CRUDLog{
+			CreatedAt: annotationTime,
+			UpdatedAt: annotationTime,
+		}
+	}
+	return a
+}
+
+func incidentAnnotation() *influxdb.Annotation {
+	return newAnnotation(annotationOneID, orgOneID, "incidents", "database outage", "db", -2*time.Hour, -time.Hour)
+}
+
+func deployAnnotation() *influxdb.Annotation {
+	return newAnnotation(annotationTwoID, orgOneID, influxdb.DefaultAnnotationStream, "deployed v1.2", "api", 0, 0)
+}
+
+// annotationFields returns the fields of a store with the database outage incident
+// and the deploy of the first organization.
+func annotationFields(t *testing.T) AnnotationFields {
+	return AnnotationFields{
+		IDGenerator:   mock.NewIDGenerator(annotationThreeID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: annotationTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		Annotations: []*influxdb.Annotation{
+			incidentAnnotation(),
+			deployAnnotation(),
+		},
+	}
+}
+
+// annotationsOf returns the annotations in the store.
+func annotationsOf(ctx context.Context, s influxdb.AnnotationService, t *testing.T) []*influxdb.Annotation {
+	t.Helper()
+	as, _, err := s.FindAnnotations(ctx, influxdb.AnnotationFilter{})
+	if err != nil {
+		t.Fatalf("failed to retrieve annotations: %v", err)
+	}
+	return as
+}
+
+// CreateAnnotations testing
+func CreateAnnotations(
+	init func(AnnotationFields, *testing.T) (influxdb.AnnotationService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		userID      influxdb.ID
+		annotations []*influxdb.Annotation
+	}
+	type wants struct {
+		err         error
+		annotations []*influxdb.Annotation
+	}
+
+	restart := func() *influxdb.Annotation {
+		return &influxdb.Annotation{
+			OrgID:   MustIDBase16(orgOneID),
+			Summary: "restarted",
+			Tags:    map[string]string{"service": "db"},
+		}
+	}
+	restarted := newAnnotation(annotationThreeID, orgOneID, influxdb.DefaultAnnotationStream, "restarted", "db", 0, 0)
+	restarted.CreatedBy = MustIDBase16(userOneID)
+	sequential := annotationFields(t)
+	sequential.IDGenerator = func() influxdb.IDGenerator {
+		ids := []string{annotationThreeID, annotationFourID}
+		return mock.IDGenerator{
+			IDFn: func() influxdb.ID {
+				id := ids[0]
+				ids = ids[1:]
+				return MustIDBase16(id)
+			},
+		}
+	}()
+	endsEarly := newAnnotation("", orgOneID, "incidents", "ends early", "db", 0, -time.Second)
+
+	tests := []struct {
+		name   string
+		fields AnnotationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "annotations mark now in the default stream and are created by the user",
+			fields: annotationFields(t),
+			args: args{
+				userID:      MustIDBase16(userOneID),
+				annotations: []*influxdb.Annotation{restart()},
+			},
+			wants: wants{
+				annotations: []*influxdb.Annotation{
+					incidentAnnotation(),
+					deployAnnotation(),
+					restarted,
+				},
+			},
+		},
+		{
+			name:   "create several annotations at once",
+			fields: sequential,
+			args: args{
+				annotations: []*influxdb.Annotation{
+					newAnnotation("", orgOneID, "incidents", "disk full", "db", -3*time.Hour, -2*time.Hour),
+					newAnnotation("", orgTwoID, "deploys", "deployed v2.0", "web", -time.Hour, -time.Hour),
+				},
+			},
+			wants: wants{
+				annotations: []*influxdb.Annotation{
+					newAnnotation(annotationThreeID, orgOneID, "incidents", "disk full", "db", -3*time.Hour, -2*time.Hour),
+					incidentAnnotation(),
+					newAnnotation(annotationFourID, orgTwoID, "deploys", "deployed v2.0", "web", -time.Hour, -time.Hour),
+					deployAnnotation(),
+				},
+			},
+		},
+		{
+			name:   "either all annotations are created or none are",
+			fields: annotationFields(t),
+			args: args{
+				annotations: []*influxdb.Annotation{restart(), endsEarly},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "annotation must not end before it starts",
+				},
+				annotations: []*influxdb.Annotation{
+					incidentAnnotation(),
+					deployAnnotation(),
+				},
+			},
+		},
+		{
+			name:   "annotations of missing organizations are not found",
+			fields: annotationFields(t),
+			args: args{
+				annotations: []*influxdb.Annotation{
+					newAnnotation("", orgThreeID, "incidents", "lost", "db", 0, 0),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+				annotations: []*influxdb.Annotation{
+					incidentAnnotation(),
+					deployAnnotation(),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			createCtx := ctx
+			if tt.args.userID.Valid() {
+				createCtx = icontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: tt.args.userID})
+			}
+			err := s.CreateAnnotations(createCtx, tt.args.annotations)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(annotationsOf(ctx, s, t), tt.wants.annotations); diff != "" {
+				t.Errorf("annotations are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindAnnotations testing
+func FindAnnotations(
+	init func(AnnotationFields, *testing.T) (influxdb.AnnotationService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.AnnotationFilter
+		opts   []influxdb.FindOptions
+	}
+	type wants struct {
+		err         error
+		annotations []*influxdb.Annotation
+	}
+
+	start, stop := annotationTime.Add(-90*time.Minute), annotationTime.Add(-30*time.Minute)
+	tests := []struct {
+		name   string
+		fields AnnotationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the annotations of an organization ordered by start time",
+			fields: annotationFields(t),
+			args: args{
+				filter: influxdb.AnnotationFilter{OrgID: idPtr(MustIDBase16(orgOneID))},
+			},
+			wants: wants{
+				annotations: []*influxdb.Annotation{
+					incidentAnnotation(),
+					deployAnnotation(),
+				},
+			},
+		},
+		{
+			name:   "find the annotations that overlap a time range",
+			fields: annotationFields(t),
+			args: args{
+				filter: influxdb.AnnotationFilter{Start: &start, Stop: &stop},
+			},
+			wants: wants{
+				annotations: []*influxdb.Annotation{
+					incidentAnnotation(),
+				},
+			},
+		},
+		{
+			name:   "find the annotations of a stream with a tag",
+			fields: annotationFields(t),
+			args: args{
+				filter: influxdb.AnnotationFilter{
+					Streams: []string{influxdb.DefaultAnnotationStream},
+					Tags:    map[string]string{"service": "api"},
+				},
+			},
+			wants: wants{
+				annotations: []*influxdb.Annotation{
+					deployAnnotation(),
+				},
+			},
+		},
+		{
+			name:   "find a page of annotations",
+			fields: annotationFields(t),
+			args: args{
+				opts: []influxdb.FindOptions{{Offset: 1, Limit: 1}},
+			},
+			wants: wants{
+				annotations: []*influxdb.Annotation{
+					deployAnnotation(),
+				},
+			},
+		},
+		{
+			name:   "organizations without annotations have none",
+			fields: annotationFields(t),
+			args: args{
+				filter: influxdb.AnnotationFilter{OrgID: idPtr(MustIDBase16(orgTwoID))},
+			},
+			wants: wants{
+				annotations: []*influxdb.Annotation{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			as, n, err := s.FindAnnotations(ctx, tt.args.filter, tt.args.opts...)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if n != len(tt.wants.annotations) {
+				t.Errorf("expected %d annotations, got %d", len(tt.wants.annotations), n)
+			}
+			if diff := cmp.Diff(as, tt.wants.annotations); diff != "" {
+				t.Errorf("annotations are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateAnnotation testing
+func UpdateAnnotation(
+	init func(AnnotationFields, *testing.T) (influxdb.AnnotationService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  influxdb.ID
+		upd influxdb.AnnotationUpdate
+	}
+	type wants struct {
+		err        error
+		annotation *influxdb.Annotation
+	}
+
+	end := annotationTime.Add(5 * time.Minute)
+	early := annotationTime.Add(-time.Minute)
+	updated := deployAnnotation()
+	updated.Summary = "deployed v1.3"
+	updated.EndTime = end
+
+	tests := []struct {
+		name   string
+		fields AnnotationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "update the summary and end of an annotation",
+			fields: annotationFields(t),
+			args: args{
+				id: MustIDBase16(annotationTwoID),
+				upd: influxdb.AnnotationUpdate{
+					Summary: strPtr("deployed v1.3"),
+					EndTime: &end,
+				},
+			},
+			wants: wants{
+				annotation: updated,
+			},
+		},
+		{
+			name:   "annotations must not end before they start",
+			fields: annotationFields(t),
+			args: args{
+				id:  MustIDBase16(annotationTwoID),
+				upd: influxdb.AnnotationUpdate{EndTime: &early},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "annotation must not end before it starts",
+				},
+			},
+		},
+		{
+			name:   "missing annotations are not found",
+			fields: annotationFields(t),
+			args: args{
+				id:  MustIDBase16(annotationThreeID),
+				upd: influxdb.AnnotationUpdate{EndTime: &end},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrAnnotationNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			a, err := s.UpdateAnnotation(ctx, tt.args.id, tt.args.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(a, tt.wants.annotation); diff != "" {
+				t.Errorf("annotation is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteAnnotation testing
+func DeleteAnnotation(
+	init func(AnnotationFields, *testing.T) (influxdb.AnnotationService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err         error
+		annotations []*influxdb.Annotation
+	}
+
+	tests := []struct {
+		name   string
+		fields AnnotationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "delete an annotation",
+			fields: annotationFields(t),
+			args: args{
+				id: MustIDBase16(annotationTwoID),
+			},
+			wants: wants{
+				annotations: []*influxdb.Annotation{
+					incidentAnnotation(),
+				},
+			},
+		},
+		{
+			name:   "missing annotations are not found",
+			fields: annotationFields(t),
+			args: args{
+				id: MustIDBase16(annotationThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrAnnotationNotFound,
+				},
+				annotations: []*influxdb.Annotation{
+					incidentAnnotation(),
+					deployAnnotation(),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteAnnotation(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(annotationsOf(ctx, s, t), tt.wants.annotations); diff != "" {
+				t.Errorf("annotations are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
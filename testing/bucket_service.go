@@ -1285,3 +1285,232 @@ func UpdateBucket(
 		})
 	}
 }
+
+// BucketMetadata tests finding buckets by their metadata and updating it.
+func BucketMetadata(
+	init func(BucketFields, *testing.T) (platform.BucketService, string, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   bucketServiceF
+	}{
+		{
+			name: "FindBucketsByMetadata",
+			fn:   FindBucketsByMetadata,
+		},
+		{
+			name: "UpdateBucketMetadata",
+			fn:   UpdateBucketMetadata,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// FindBucketsByMetadata testing
+func FindBucketsByMetadata(
+	init func(BucketFields, *testing.T) (platform.BucketService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		metadata platform.Metadata
+	}
+	type wants struct {
+		err     error
+		buckets []*platform.Bucket
+	}
+
+	fields := func() BucketFields {
+		return BucketFields{
+			Organizations: []*platform.Organization{
+				{
+					Name: "theorg",
+					ID:   MustIDBase16(orgOneID),
+				},
+			},
+			Buckets: []*platform.Bucket{
+				{
+					ID:       MustIDBase16(bucketOneID),
+					OrgID:    MustIDBase16(orgOneID),
+					Name:     "billing",
+					Metadata: platform.Metadata{"costCenter": "42", "team": "ops"},
+				},
+				{
+					ID:       MustIDBase16(bucketTwoID),
+					OrgID:    MustIDBase16(orgOneID),
+					Name:     "other",
+					Metadata: platform.Metadata{"costCenter": "7"},
+				},
+				{
+					ID:    MustIDBase16(bucketThreeID),
+					OrgID: MustIDBase16(orgOneID),
+					Name:  "plain",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		fields BucketFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find buckets with a metadata value",
+			fields: fields(),
+			args: args{
+				metadata: platform.Metadata{"costCenter": "42"},
+			},
+			wants: wants{
+				buckets: []*platform.Bucket{
+					{
+						ID:       MustIDBase16(bucketOneID),
+						OrgID:    MustIDBase16(orgOneID),
+						Name:     "billing",
+						Metadata: platform.Metadata{"costCenter": "42", "team": "ops"},
+					},
+				},
+			},
+		},
+		{
+			name:   "find buckets with all metadata values",
+			fields: fields(),
+			args: args{
+				metadata: platform.Metadata{"costCenter": "7", "team": "ops"},
+			},
+			wants: wants{
+				buckets: []*platform.Bucket{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			orgID := MustIDBase16(orgOneID)
+			buckets, _, err := s.FindBuckets(ctx, platform.BucketFilter{
+				OrganizationID: &orgID,
+				Metadata:       tt.args.metadata,
+			})
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(buckets, tt.wants.buckets, bucketCmpOptions...); diff != "" {
+				t.Errorf("buckets are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateBucketMetadata testing
+func UpdateBucketMetadata(
+	init func(BucketFields, *testing.T) (platform.BucketService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id       platform.ID
+		metadata platform.MetadataUpdate
+	}
+	type wants struct {
+		err    error
+		bucket *platform.Bucket
+	}
+
+	ticket := "INC-1"
+
+	tests := []struct {
+		name   string
+		fields BucketFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "set and remove metadata keys",
+			fields: BucketFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Organizations: []*platform.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Buckets: []*platform.Bucket{
+					{
+						ID:       MustIDBase16(bucketOneID),
+						OrgID:    MustIDBase16(orgOneID),
+						Name:     "billing",
+						Metadata: platform.Metadata{"costCenter": "42", "team": "ops"},
+					},
+				},
+			},
+			args: args{
+				id:       MustIDBase16(bucketOneID),
+				metadata: platform.MetadataUpdate{"ticket": &ticket, "team": nil},
+			},
+			wants: wants{
+				bucket: &platform.Bucket{
+					ID:       MustIDBase16(bucketOneID),
+					OrgID:    MustIDBase16(orgOneID),
+					Name:     "billing",
+					Metadata: platform.Metadata{"costCenter": "42", "ticket": "INC-1"},
+					CRUDLog: platform.CRUDLog{
+						UpdatedAt: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			name: "invalid metadata keys are rejected",
+			fields: BucketFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Organizations: []*platform.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Buckets: []*platform.Bucket{
+					{
+						ID:    MustIDBase16(bucketOneID),
+						OrgID: MustIDBase16(orgOneID),
+						Name:  "billing",
+					},
+				},
+			},
+			args: args{
+				id:       MustIDBase16(bucketOneID),
+				metadata: platform.MetadataUpdate{"cost center": &ticket},
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EInvalid,
+					Msg:  `metadata key "cost center" must be letters, digits, "_", ".", "-" or "/"`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			bucket, err := s.UpdateBucket(ctx, tt.args.id, platform.BucketUpdate{
+				Metadata: tt.args.metadata,
+			})
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(bucket, tt.wants.bucket, bucketCmpOptions...); diff != "" {
+				t.Errorf("bucket is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,628 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	checkOneID   = "020f755c3c089000"
+	checkTwoID   = "020f755c3c089001"
+	checkThreeID = "020f755c3c089002"
+	checkTaskID  = "020f755c3c089100"
+)
+
+// CheckFields will include the IDGenerator, TimeGenerator, and the organizations and
+// checks to populate the store with.
+type CheckFields struct {
+	IDGenerator   influxdb.IDGenerator
+	TimeGenerator influxdb.TimeGenerator
+	Organizations []*influxdb.Organization
+	Checks        []*influxdb.Check
+}
+
+type checkServiceF func(
+	init func(CheckFields, *testing.T) (influxdb.CheckService, func()),
+	t *testing.T,
+)
+
+// CheckService tests all the service functions.
+func CheckService(
+	init func(CheckFields, *testing.T) (influxdb.CheckService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   checkServiceF
+	}{
+		{
+			name: "CreateCheck",
+			fn:   CreateCheck,
+		},
+		{
+			name: "FindChecks",
+			fn:   FindChecks,
+		},
+		{
+			name: "UpdateCheck",
+			fn:   UpdateCheck,
+		},
+		{
+			name: "ReplaceCheck",
+			fn:   ReplaceCheck,
+		},
+		{
+			name: "DeleteCheck",
+			fn:   DeleteCheck,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var checkTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func newDeadmanCheck(id, orgID, name string) *influxdb.Check {
+	c := &influxdb.Check{
+		OrgID:     MustIDBase16(orgID),
+		Name:      name,
+		Type:      influxdb.DeadmanCheckType,
+		Status:    influxdb.Active,
+		Query:     influxdb.DashboardQuery{Text: `from(bucket: "telegraf") |> range(start: -1h)`},
+		Every:     "1m",
+		TimeSince: 60,
+		Level:     influxdb.CheckLevelCrit,
+	}
+	if id != "" {
+		c.ID = MustIDBase16(id)
+		c.CRUDLog = influxdb.CRUDLog{
+			CreatedAt: checkTime,
+			UpdatedAt: checkTime,
+		}
+	}
+	return c
+}
+
+func newThresholdCheck(id, orgID, name string) *influxdb.Check {
+	c := newDeadmanCheck(id, orgID, name)
+	c.Type = influxdb.ThresholdCheckType
+	c.TimeSince = 0
+	c.Level = ""
+	c.Thresholds = []influxdb.Threshold{
+		{Type: influxdb.GreaterThreshold, Level: influxdb.CheckLevelCrit, Value: 90},
+	}
+	return c
+}
+
+// withTask returns the check run by the task of checkTaskID.
+func withTask(c *influxdb.Check) *influxdb.Check {
+	c.TaskID = MustIDBase16(checkTaskID)
+	return c
+}
+
+// checkFields returns the fields of a store with the heartbeat deadman check, run by
+// a task, and the cpu threshold check of the first organization.
+func checkFields(t *testing.T) CheckFields {
+	return CheckFields{
+		IDGenerator:   mock.NewIDGenerator(checkThreeID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: checkTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		Checks: []*influxdb.Check{
+			withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+			newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+		},
+	}
+}
+
+// checksOf returns the checks in the store.
+func checksOf(ctx context.Context, s influxdb.CheckService, t *testing.T) []*influxdb.Check {
+	t.Helper()
+	cs, _, err := s.FindChecks(ctx, influxdb.CheckFilter{})
+	if err != nil {
+		t.Fatalf("failed to retrieve checks: %v", err)
+	}
+	return cs
+}
+
+// CreateCheck testing
+func CreateCheck(
+	init func(CheckFields, *testing.T) (influxdb.CheckService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		check *influxdb.Check
+	}
+	type wants struct {
+		err    error
+		checks []*influxdb.Check
+	}
+
+	withLastStatus := newDeadmanCheck("", orgOneID, "disk")
+	withLastStatus.LastStatus = &influxdb.CheckStatus{Level: influxdb.CheckLevelOK}
+	withoutTimeSince := newDeadmanCheck("", orgOneID, "disk")
+	withoutTimeSince.TimeSince = 0
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "create a check of an organization",
+			fields: checkFields(t),
+			args: args{
+				check: newDeadmanCheck("", orgOneID, "disk"),
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+					newDeadmanCheck(checkThreeID, orgOneID, "disk"),
+				},
+			},
+		},
+		{
+			name:   "the last status is not stored",
+			fields: checkFields(t),
+			args: args{
+				check: withLastStatus,
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+					newDeadmanCheck(checkThreeID, orgOneID, "disk"),
+				},
+			},
+		},
+		{
+			name:   "checks of different organizations share names",
+			fields: checkFields(t),
+			args: args{
+				check: newDeadmanCheck("", orgTwoID, "heartbeat"),
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+					newDeadmanCheck(checkThreeID, orgTwoID, "heartbeat"),
+				},
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: checkFields(t),
+			args: args{
+				check: newDeadmanCheck("", orgOneID, "heartbeat"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "check with name heartbeat already exists",
+				},
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "checks require what their type needs",
+			fields: checkFields(t),
+			args: args{
+				check: withoutTimeSince,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "deadman check needs a positive timeSince",
+				},
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "checks of missing organizations are not found",
+			fields: checkFields(t),
+			args: args{
+				check: newDeadmanCheck("", orgThreeID, "disk"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateCheck(ctx, tt.args.check)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(checksOf(ctx, s, t), tt.wants.checks); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindChecks testing
+func FindChecks(
+	init func(CheckFields, *testing.T) (influxdb.CheckService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.CheckFilter
+		opts   []influxdb.FindOptions
+	}
+	type wants struct {
+		err    error
+		checks []*influxdb.Check
+	}
+
+	deadman := influxdb.DeadmanCheckType
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the checks of an organization",
+			fields: checkFields(t),
+			args: args{
+				filter: influxdb.CheckFilter{OrgID: idPtr(MustIDBase16(orgOneID))},
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "find checks by type",
+			fields: checkFields(t),
+			args: args{
+				filter: influxdb.CheckFilter{Type: &deadman},
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+				},
+			},
+		},
+		{
+			name:   "find checks by name",
+			fields: checkFields(t),
+			args: args{
+				filter: influxdb.CheckFilter{Name: strPtr("cpu")},
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "find a page of checks ordered by id",
+			fields: checkFields(t),
+			args: args{
+				filter: influxdb.CheckFilter{OrgID: idPtr(MustIDBase16(orgOneID))},
+				opts:   []influxdb.FindOptions{{Offset: 1, Limit: 1}},
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "organizations without checks have none",
+			fields: checkFields(t),
+			args: args{
+				filter: influxdb.CheckFilter{OrgID: idPtr(MustIDBase16(orgTwoID))},
+			},
+			wants: wants{
+				checks: []*influxdb.Check{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			cs, n, err := s.FindChecks(ctx, tt.args.filter, tt.args.opts...)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if n != len(tt.wants.checks) {
+				t.Errorf("expected %d checks, got %d", len(tt.wants.checks), n)
+			}
+			if diff := cmp.Diff(cs, tt.wants.checks); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateCheck testing
+func UpdateCheck(
+	init func(CheckFields, *testing.T) (influxdb.CheckService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  influxdb.ID
+		upd influxdb.CheckUpdate
+	}
+	type wants struct {
+		err   error
+		check *influxdb.Check
+	}
+
+	inactive := withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat"))
+	inactive.Status = influxdb.Inactive
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "deactivate a check",
+			fields: checkFields(t),
+			args: args{
+				id:  MustIDBase16(checkOneID),
+				upd: influxdb.CheckUpdate{Status: influxdb.Inactive.Ptr()},
+			},
+			wants: wants{
+				check: inactive,
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: checkFields(t),
+			args: args{
+				id:  MustIDBase16(checkTwoID),
+				upd: influxdb.CheckUpdate{Name: strPtr("heartbeat")},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "check with name heartbeat already exists",
+				},
+			},
+		},
+		{
+			name:   "names cannot be empty",
+			fields: checkFields(t),
+			args: args{
+				id:  MustIDBase16(checkTwoID),
+				upd: influxdb.CheckUpdate{Name: strPtr(" ")},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "check name cannot be empty",
+				},
+			},
+		},
+		{
+			name:   "missing checks are not found",
+			fields: checkFields(t),
+			args: args{
+				id:  MustIDBase16(checkThreeID),
+				upd: influxdb.CheckUpdate{Status: influxdb.Inactive.Ptr()},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrCheckNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			c, err := s.UpdateCheck(ctx, tt.args.id, tt.args.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(c, tt.wants.check); diff != "" {
+				t.Errorf("check is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// ReplaceCheck testing
+func ReplaceCheck(
+	init func(CheckFields, *testing.T) (influxdb.CheckService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		check *influxdb.Check
+	}
+	type wants struct {
+		err    error
+		checks []*influxdb.Check
+	}
+
+	// replacement returns the heartbeat check without the fields the store keeps.
+	replacement := func(timeSince int64) *influxdb.Check {
+		c := newDeadmanCheck(checkOneID, orgTwoID, "heartbeat")
+		c.OrgID = 0
+		c.Status = ""
+		c.CRUDLog = influxdb.CRUDLog{}
+		c.TimeSince = timeSince
+		return c
+	}
+	replaced := withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat"))
+	replaced.TimeSince = 120
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "replaced checks keep their organization, status and task",
+			fields: checkFields(t),
+			args: args{
+				check: replacement(120),
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					replaced,
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "checks require what their type needs",
+			fields: checkFields(t),
+			args: args{
+				check: replacement(0),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "deadman check needs a positive timeSince",
+				},
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "missing checks are not found",
+			fields: checkFields(t),
+			args: args{
+				check: newDeadmanCheck(checkThreeID, orgOneID, "disk"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrCheckNotFound,
+				},
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			_, err := s.ReplaceCheck(ctx, tt.args.check)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(checksOf(ctx, s, t), tt.wants.checks); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteCheck testing
+func DeleteCheck(
+	init func(CheckFields, *testing.T) (influxdb.CheckService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err    error
+		checks []*influxdb.Check
+	}
+
+	tests := []struct {
+		name   string
+		fields CheckFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "delete a check",
+			fields: checkFields(t),
+			args: args{
+				id: MustIDBase16(checkOneID),
+			},
+			wants: wants{
+				checks: []*influxdb.Check{
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "missing checks are not found",
+			fields: checkFields(t),
+			args: args{
+				id: MustIDBase16(checkThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrCheckNotFound,
+				},
+				checks: []*influxdb.Check{
+					withTask(newDeadmanCheck(checkOneID, orgOneID, "heartbeat")),
+					newThresholdCheck(checkTwoID, orgOneID, "cpu"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteCheck(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(checksOf(ctx, s, t), tt.wants.checks); diff != "" {
+				t.Errorf("checks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
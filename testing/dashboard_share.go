@@ -0,0 +1,515 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	dashboardShareOneID   = "020f755c3c08f000"
+	dashboardShareTwoID   = "020f755c3c08f001"
+	dashboardShareThreeID = "020f755c3c08f002"
+)
+
+// DashboardShareFields will include the IDGenerator, TokenGenerator, TimeGenerator, and
+// the organizations, dashboards and dashboard shares to populate the store with. The
+// dashboard shares are created at their CreatedAt with their token.
+type DashboardShareFields struct {
+	IDGenerator     influxdb.IDGenerator
+	TokenGenerator  influxdb.TokenGenerator
+	TimeGenerator   influxdb.TimeGenerator
+	Organizations   []*influxdb.Organization
+	Dashboards      []*influxdb.Dashboard
+	DashboardShares []*influxdb.DashboardShare
+}
+
+type dashboardShareServiceF func(
+	init func(DashboardShareFields, *testing.T) (influxdb.DashboardShareService, func()),
+	t *testing.T,
+)
+
+// DashboardShareService tests all the service functions.
+func DashboardShareService(
+	init func(DashboardShareFields, *testing.T) (influxdb.DashboardShareService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   dashboardShareServiceF
+	}{
+		{
+			name: "CreateDashboardShare",
+			fn:   CreateDashboardShare,
+		},
+		{
+			name: "FindDashboardShares",
+			fn:   FindDashboardShares,
+		},
+		{
+			name: "FindDashboardShareByToken",
+			fn:   FindDashboardShareByToken,
+		},
+		{
+			name: "RevokeDashboardShare",
+			fn:   RevokeDashboardShare,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// dashboardShareTime is the time the shares of the store are created at; the
+// services run an hour later.
+var dashboardShareTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// frozenDashboardShare returns the share of the hour before it was created, which
+// expires after the default TTL.
+func frozenDashboardShare() *influxdb.DashboardShare {
+	return &influxdb.DashboardShare{
+		ID:          MustIDBase16(dashboardShareOneID),
+		DashboardID: MustIDBase16(dashOneID),
+		OrgID:       MustIDBase16(orgOneID),
+		Mode:        influxdb.DashboardShareFrozen,
+		Token:       "frozen",
+		Status:      influxdb.DashboardShareActive,
+		Start:       dashboardShareTime.Add(-time.Hour),
+		Stop:        dashboardShareTime,
+		Cells:       []influxdb.DashboardShareCell{},
+		ExpiresAt:   dashboardShareTime.Add(influxdb.DefaultDashboardShareTTL),
+		CreatedAt:   dashboardShareTime,
+	}
+}
+
+// liveDashboardShare returns the share of the last hour that expired a minute after it
+// was created.
+func liveDashboardShare() *influxdb.DashboardShare {
+	return &influxdb.DashboardShare{
+		ID:          MustIDBase16(dashboardShareTwoID),
+		DashboardID: MustIDBase16(dashOneID),
+		OrgID:       MustIDBase16(orgOneID),
+		Mode:        influxdb.DashboardShareLive,
+		Token:       "live",
+		Status:      influxdb.DashboardShareExpired,
+		Range:       "1h",
+		Cells:       []influxdb.DashboardShareCell{},
+		ExpiresAt:   dashboardShareTime.Add(time.Minute),
+		CreatedAt:   dashboardShareTime,
+	}
+}
+
+// dashboardShareFields returns the fields of a store with the frozen and the live share
+// of the hosts dashboard of the first organization.
+func dashboardShareFields(t *testing.T) DashboardShareFields {
+	return DashboardShareFields{
+		IDGenerator:    mock.NewIDGenerator(dashboardShareThreeID, t),
+		TokenGenerator: mock.NewTokenGenerator("new", nil),
+		TimeGenerator:  mock.TimeGenerator{FakeValue: dashboardShareTime.Add(time.Hour)},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		Dashboards: []*influxdb.Dashboard{
+			{ID: MustIDBase16(dashOneID), OrganizationID: MustIDBase16(orgOneID), Name: "hosts"},
+		},
+		DashboardShares: []*influxdb.DashboardShare{
+			frozenDashboardShare(),
+			liveDashboardShare(),
+		},
+	}
+}
+
+// dashboardSharesOf returns the dashboard shares in the store.
+func dashboardSharesOf(ctx context.Context, s influxdb.DashboardShareService, t *testing.T) []*influxdb.DashboardShare {
+	t.Helper()
+	shares, err := s.FindDashboardShares(ctx, influxdb.DashboardShareFilter{})
+	if err != nil {
+		t.Fatalf("failed to retrieve dashboard shares: %v", err)
+	}
+	return shares
+}
+
+// CreateDashboardShare testing
+func CreateDashboardShare(
+	init func(DashboardShareFields, *testing.T) (influxdb.DashboardShareService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		userID influxdb.ID
+		share  *influxdb.DashboardShare
+	}
+	type wants struct {
+		err    error
+		shares []*influxdb.DashboardShare
+	}
+
+	now := dashboardShareTime.Add(time.Hour)
+	share := func(mode influxdb.DashboardShareMode) *influxdb.DashboardShare {
+		return &influxdb.DashboardShare{
+			DashboardID: MustIDBase16(dashOneID),
+			Mode:        mode,
+			Start:       now.Add(-time.Hour),
+			Stop:        now,
+		}
+	}
+	created := &influxdb.DashboardShare{
+		ID:          MustIDBase16(dashboardShareThreeID),
+		DashboardID: MustIDBase16(dashOneID),
+		OrgID:       MustIDBase16(orgOneID),
+		Mode:        influxdb.DashboardShareFrozen,
+		Token:       "new",
+		Status:      influxdb.DashboardShareActive,
+		Start:       now.Add(-time.Hour),
+		Stop:        now,
+		Cells:       []influxdb.DashboardShareCell{},
+		ExpiresAt:   now.Add(influxdb.DefaultDashboardShareTTL),
+		CreatedBy:   MustIDBase16(userOneID),
+		CreatedAt:   now,
+	}
+	emptyRange := share(influxdb.DashboardShareFrozen)
+	emptyRange.Stop = emptyRange.Start
+	expiresNow := share(influxdb.DashboardShareLive)
+	expiresNow.Range = "1h"
+	expiresNow.ExpiresAt = now
+	otherOrg := share(influxdb.DashboardShareFrozen)
+	otherOrg.OrgID = MustIDBase16(orgTwoID)
+	missingDashboard := share(influxdb.DashboardShareFrozen)
+	missingDashboard.DashboardID = MustIDBase16(dashTwoID)
+
+	tests := []struct {
+		name   string
+		fields DashboardShareFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "shares belong to the organization of their dashboard and expire after the default TTL",
+			fields: dashboardShareFields(t),
+			args: args{
+				userID: MustIDBase16(userOneID),
+				share:  share(influxdb.DashboardShareFrozen),
+			},
+			wants: wants{
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+					created,
+				},
+			},
+		},
+		{
+			name:   "live shares need a range",
+			fields: dashboardShareFields(t),
+			args: args{
+				share: share(influxdb.DashboardShareLive),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  `range of a live share must be a positive duration, got ""`,
+				},
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+				},
+			},
+		},
+		{
+			name:   "frozen shares need a time range",
+			fields: dashboardShareFields(t),
+			args: args{
+				share: emptyRange,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "start must be before stop",
+				},
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+				},
+			},
+		},
+		{
+			name:   "shares expire in the future",
+			fields: dashboardShareFields(t),
+			args: args{
+				share: expiresNow,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "dashboard share must expire in the future",
+				},
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+				},
+			},
+		},
+		{
+			name:   "shares are frozen or live",
+			fields: dashboardShareFields(t),
+			args: args{
+				share: share("forever"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  `invalid dashboard share mode "forever", must be frozen or live`,
+				},
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+				},
+			},
+		},
+		{
+			name:   "shares cannot belong to another organization",
+			fields: dashboardShareFields(t),
+			args: args{
+				share: otherOrg,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "dashboard share must belong to the organization of the dashboard",
+				},
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+				},
+			},
+		},
+		{
+			name:   "shares of missing dashboards are not found",
+			fields: dashboardShareFields(t),
+			args: args{
+				share: missingDashboard,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrDashboardNotFound,
+				},
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			createCtx := ctx
+			if tt.args.userID.Valid() {
+				createCtx = icontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: tt.args.userID})
+			}
+			err := s.CreateDashboardShare(createCtx, tt.args.share)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(dashboardSharesOf(ctx, s, t), tt.wants.shares); diff != "" {
+				t.Errorf("dashboard shares are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindDashboardShares testing
+func FindDashboardShares(
+	init func(DashboardShareFields, *testing.T) (influxdb.DashboardShareService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.DashboardShareFilter
+	}
+	type wants struct {
+		err    error
+		shares []*influxdb.DashboardShare
+	}
+
+	tests := []struct {
+		name   string
+		fields DashboardShareFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the shares of a dashboard with their status",
+			fields: dashboardShareFields(t),
+			args: args{
+				filter: influxdb.DashboardShareFilter{DashboardID: idPtr(MustIDBase16(dashOneID))},
+			},
+			wants: wants{
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+				},
+			},
+		},
+		{
+			name:   "organizations without shares have none",
+			fields: dashboardShareFields(t),
+			args: args{
+				filter: influxdb.DashboardShareFilter{OrgID: idPtr(MustIDBase16(orgTwoID))},
+			},
+			wants: wants{
+				shares: []*influxdb.DashboardShare{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			shares, err := s.FindDashboardShares(ctx, tt.args.filter)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(shares, tt.wants.shares); diff != "" {
+				t.Errorf("dashboard shares are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindDashboardShareByToken testing
+func FindDashboardShareByToken(
+	init func(DashboardShareFields, *testing.T) (influxdb.DashboardShareService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		token string
+	}
+	type wants struct {
+		err   error
+		share *influxdb.DashboardShare
+	}
+
+	tests := []struct {
+		name   string
+		fields DashboardShareFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find a share by its token",
+			fields: dashboardShareFields(t),
+			args: args{
+				token: "live",
+			},
+			wants: wants{
+				share: liveDashboardShare(),
+			},
+		},
+		{
+			name:   "shares of unknown tokens are not found",
+			fields: dashboardShareFields(t),
+			args: args{
+				token: "unknown",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrDashboardShareNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			share, err := s.FindDashboardShareByToken(ctx, tt.args.token)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(share, tt.wants.share); diff != "" {
+				t.Errorf("dashboard share is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// RevokeDashboardShare testing
+func RevokeDashboardShare(
+	init func(DashboardShareFields, *testing.T) (influxdb.DashboardShareService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err    error
+		shares []*influxdb.DashboardShare
+	}
+
+	tests := []struct {
+		name   string
+		fields DashboardShareFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "revoke a share",
+			fields: dashboardShareFields(t),
+			args: args{
+				id: MustIDBase16(dashboardShareTwoID),
+			},
+			wants: wants{
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+				},
+			},
+		},
+		{
+			name:   "missing shares are not found",
+			fields: dashboardShareFields(t),
+			args: args{
+				id: MustIDBase16(dashboardShareThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrDashboardShareNotFound,
+				},
+				shares: []*influxdb.DashboardShare{
+					frozenDashboardShare(),
+					liveDashboardShare(),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.RevokeDashboardShare(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(dashboardSharesOf(ctx, s, t), tt.wants.shares); diff != "" {
+				t.Errorf("dashboard shares are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -194,6 +194,67 @@ func CreateDBRPMapping(
 				},
 			},
 		},
+		{
+			name: "create default dbrpMapping replaces the default of the database",
+			fields: DBRPMappingFields{
+				DBRPMappings: []*platform.DBRPMapping{
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy1",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg1ID),
+						BucketID:        MustIDBase16(dbrpBucket1ID),
+					},
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy2",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg2ID),
+						BucketID:        MustIDBase16(dbrpBucketAID),
+					},
+				},
+			},
+			args: args{
+				dbrpMapping: &platform.DBRPMapping{
+					Cluster:         "cluster1",
+					Database:        "database1",
+					RetentionPolicy: "retention_policy3",
+					Default:         true,
+					OrganizationID:  MustIDBase16(dbrpOrg1ID),
+					BucketID:        MustIDBase16(dbrpBucket2ID),
+				},
+			},
+			wants: wants{
+				dbrpMappings: []*platform.DBRPMapping{
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy1",
+						Default:         false,
+						OrganizationID:  MustIDBase16(dbrpOrg1ID),
+						BucketID:        MustIDBase16(dbrpBucket1ID),
+					},
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy2",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg2ID),
+						BucketID:        MustIDBase16(dbrpBucketAID),
+					},
+					{
+						Cluster:         "cluster1",
+						Database:        "database1",
+						RetentionPolicy: "retention_policy3",
+						Default:         true,
+						OrganizationID:  MustIDBase16(dbrpOrg1ID),
+						BucketID:        MustIDBase16(dbrpBucket2ID),
+					},
+				},
+			},
+		},
 		{
 			name: "error on create existing dbrpMapping",
 			fields: DBRPMappingFields{
@@ -0,0 +1,191 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+// FindSortedFields will include the organizations and buckets to populate the store with.
+type FindSortedFields struct {
+	Organizations []*influxdb.Organization
+	Buckets       []*influxdb.Bucket
+}
+
+// FindSortedServices are the services whose resources are found sorted.
+type FindSortedServices interface {
+	influxdb.OrganizationService
+	influxdb.BucketService
+}
+
+type findSortedServiceF func(
+	init func(FindSortedFields, *testing.T) (FindSortedServices, func()),
+	t *testing.T,
+)
+
+// FindSorted tests finding organizations and buckets sorted by the find options.
+func FindSorted(
+	init func(FindSortedFields, *testing.T) (FindSortedServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   findSortedServiceF
+	}{
+		{
+			name: "FindOrganizationsSorted",
+			fn:   FindOrganizationsSorted,
+		},
+		{
+			name: "FindBucketsSorted",
+			fn:   FindBucketsSorted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// findSortedFields returns the fields of a store with the organizations b, c and a,
+// and the buckets y, z and x of the first organization.
+func findSortedFields() FindSortedFields {
+	return FindSortedFields{
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "b"},
+			{ID: MustIDBase16(orgTwoID), Name: "c"},
+			{ID: MustIDBase16(orgThreeID), Name: "a"},
+		},
+		Buckets: []*influxdb.Bucket{
+			{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "y"},
+			{ID: MustIDBase16(bucketTwoID), OrgID: MustIDBase16(orgOneID), Name: "z"},
+			{ID: MustIDBase16(bucketThreeID), OrgID: MustIDBase16(orgOneID), Name: "x"},
+		},
+	}
+}
+
+// FindOrganizationsSorted testing
+func FindOrganizationsSorted(
+	init func(FindSortedFields, *testing.T) (FindSortedServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		opts influxdb.FindOptions
+	}
+	type wants struct {
+		names []string
+	}
+
+	tests := []struct {
+		name   string
+		fields FindSortedFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find a page of organizations sorted by name",
+			fields: findSortedFields(),
+			args: args{
+				opts: influxdb.FindOptions{SortBy: "name", Offset: 1, Limit: 1},
+			},
+			wants: wants{
+				names: []string{"b"},
+			},
+		},
+		{
+			name:   "find organizations sorted by descending name",
+			fields: findSortedFields(),
+			args: args{
+				opts: influxdb.FindOptions{SortBy: "Name", Descending: true},
+			},
+			wants: wants{
+				names: []string{"c", "b", "a"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			os, _, err := s.FindOrganizations(ctx, influxdb.OrganizationFilter{}, tt.args.opts)
+			if err != nil {
+				t.Fatalf("failed to retrieve organizations: %v", err)
+			}
+			var names []string
+			for _, o := range os {
+				names = append(names, o.Name)
+			}
+			if diff := cmp.Diff(names, tt.wants.names); diff != "" {
+				t.Errorf("organizations are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindBucketsSorted testing
+func FindBucketsSorted(
+	init func(FindSortedFields, *testing.T) (FindSortedServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		opts influxdb.FindOptions
+	}
+	type wants struct {
+		names []string
+	}
+
+	tests := []struct {
+		name   string
+		fields FindSortedFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the first buckets sorted by name",
+			fields: findSortedFields(),
+			args: args{
+				opts: influxdb.FindOptions{SortBy: "Name", Limit: 2},
+			},
+			wants: wants{
+				names: []string{"x", "y"},
+			},
+		},
+		{
+			name:   "find buckets sorted by descending name",
+			fields: findSortedFields(),
+			args: args{
+				opts: influxdb.FindOptions{SortBy: "name", Descending: true},
+			},
+			wants: wants{
+				names: []string{"z", "y", "x"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			bs, _, err := s.FindBuckets(ctx, influxdb.BucketFilter{
+				OrganizationID: idPtr(MustIDBase16(orgOneID)),
+			}, tt.args.opts)
+			if err != nil {
+				t.Fatalf("failed to retrieve buckets: %v", err)
+			}
+			var names []string
+			for _, b := range bs {
+				names = append(names, b.Name)
+			}
+			if diff := cmp.Diff(names, tt.wants.names); diff != "" {
+				t.Errorf("buckets are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,623 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	inviteOneID = "020f755c3c085000"
+	inviteTwoID = "020f755c3c085001"
+)
+
+// InviteFields will include the IDGenerator, TimeGenerator, TokenGenerator, and the
+// organizations and invites to populate the store with.
+type InviteFields struct {
+	IDGenerator    influxdb.IDGenerator
+	TimeGenerator  influxdb.TimeGenerator
+	TokenGenerator influxdb.TokenGenerator
+	Organizations  []*influxdb.Organization
+	Invites        []*influxdb.Invite
+}
+
+// InviteServices are the invite service and the services that hold the user of an
+// accepted invite.
+type InviteServices interface {
+	influxdb.InviteService
+	influxdb.UserService
+	influxdb.PasswordsService
+	influxdb.UserResourceMappingService
+}
+
+type inviteServiceF func(
+	init func(InviteFields, *testing.T) (InviteServices, func()),
+	t *testing.T,
+)
+
+// InviteService tests all the service functions.
+func InviteService(
+	init func(InviteFields, *testing.T) (InviteServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   inviteServiceF
+	}{
+		{
+			name: "CreateInvite",
+			fn:   CreateInvite,
+		},
+		{
+			name: "FindInviteByToken",
+			fn:   FindInviteByToken,
+		},
+		{
+			name: "FindInvites",
+			fn:   FindInvites,
+		},
+		{
+			name: "RevokeInvite",
+			fn:   RevokeInvite,
+		},
+		{
+			name: "AcceptInvite",
+			fn:   AcceptInvite,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var inviteTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// newInvite returns a pending invite created at the inviteTime that expires after ttl.
+func newInvite(id, email string, role influxdb.UserType, token string, ttl time.Duration) *influxdb.Invite {
+	i := &influxdb.Invite{
+		OrgID: MustIDBase16(orgOneID),
+		Email: email,
+		Role:  role,
+	}
+	if id != "" {
+		i.ID = MustIDBase16(id)
+		i.Token = token
+		i.Status = influxdb.InvitePending
+		i.ExpiresAt = inviteTime.Add(ttl)
+		i.CreatedAt = inviteTime
+	}
+	return i
+}
+
+// inviteFields returns the fields of a store with an invite of ada that expires
+// after an hour.
+func inviteFields(t *testing.T) InviteFields {
+	return InviteFields{
+		IDGenerator:    mock.NewIDGenerator(inviteTwoID, t),
+		TimeGenerator:  mock.TimeGenerator{FakeValue: inviteTime},
+		TokenGenerator: mock.NewTokenGenerator("token2", nil),
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+		},
+		Invites: []*influxdb.Invite{
+			newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour),
+		},
+	}
+}
+
+// CreateInvite testing
+func CreateInvite(
+	init func(InviteFields, *testing.T) (InviteServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		invite *influxdb.Invite
+	}
+	type wants struct {
+		err     error
+		invites []*influxdb.Invite
+	}
+
+	expired := newInvite("", "bob@example.com", influxdb.Member, "", 0)
+	expired.ExpiresAt = inviteTime
+
+	tests := []struct {
+		name   string
+		fields InviteFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "create a pending invite that expires after the default TTL",
+			fields: inviteFields(t),
+			args: args{
+				invite: newInvite("", "bob@example.com", influxdb.Member, "", 0),
+			},
+			wants: wants{
+				invites: []*influxdb.Invite{
+					newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour),
+					newInvite(inviteTwoID, "bob@example.com", influxdb.Member, "token2", influxdb.DefaultInviteTTL),
+				},
+			},
+		},
+		{
+			name:   "invites require a valid email",
+			fields: inviteFields(t),
+			args: args{
+				invite: newInvite("", "bob", influxdb.Member, "", 0),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "a valid email is required",
+				},
+				invites: []*influxdb.Invite{
+					newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour),
+				},
+			},
+		},
+		{
+			name:   "invites must expire in the future",
+			fields: inviteFields(t),
+			args: args{
+				invite: expired,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "invite must expire in the future",
+				},
+				invites: []*influxdb.Invite{
+					newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour),
+				},
+			},
+		},
+		{
+			name:   "invites to missing organizations are not found",
+			fields: inviteFields(t),
+			args: args{
+				invite: &influxdb.Invite{
+					OrgID: MustIDBase16(orgTwoID),
+					Email: "bob@example.com",
+					Role:  influxdb.Member,
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+				invites: []*influxdb.Invite{
+					newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateInvite(ctx, tt.args.invite)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			is, err := s.FindInvites(ctx, influxdb.InviteFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve invites: %v", err)
+			}
+			if diff := cmp.Diff(is, tt.wants.invites); diff != "" {
+				t.Errorf("invites are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindInviteByToken testing
+func FindInviteByToken(
+	init func(InviteFields, *testing.T) (InviteServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		token string
+	}
+	type wants struct {
+		err    error
+		invite *influxdb.Invite
+	}
+
+	tests := []struct {
+		name   string
+		fields InviteFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find an invite by its token",
+			fields: inviteFields(t),
+			args: args{
+				token: "token1",
+			},
+			wants: wants{
+				invite: newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour),
+			},
+		},
+		{
+			name:   "invites of unknown tokens are not found",
+			fields: inviteFields(t),
+			args: args{
+				token: "token2",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrInviteNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			i, err := s.FindInviteByToken(ctx, tt.args.token)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(i, tt.wants.invite); diff != "" {
+				t.Errorf("invite is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindInvites testing
+func FindInvites(
+	init func(InviteFields, *testing.T) (InviteServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.InviteFilter
+		// elapsed is the time that passes after the store is populated.
+		elapsed time.Duration
+	}
+	type wants struct {
+		invites []*influxdb.Invite
+	}
+
+	expired := newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour)
+	expired.Status = influxdb.InviteExpired
+
+	tests := []struct {
+		name   string
+		fields InviteFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the invites of an organization",
+			fields: inviteFields(t),
+			args: args{
+				filter: influxdb.InviteFilter{
+					OrgID: idPtr(MustIDBase16(orgOneID)),
+				},
+			},
+			wants: wants{
+				invites: []*influxdb.Invite{
+					newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour),
+				},
+			},
+		},
+		{
+			name:   "pending invites expire at their expiration",
+			fields: inviteFields(t),
+			args: args{
+				filter: influxdb.InviteFilter{
+					OrgID: idPtr(MustIDBase16(orgOneID)),
+				},
+				elapsed: time.Hour,
+			},
+			wants: wants{
+				invites: []*influxdb.Invite{expired},
+			},
+		},
+		{
+			name:   "organizations without invites have none",
+			fields: inviteFields(t),
+			args: args{
+				filter: influxdb.InviteFilter{
+					OrgID: idPtr(MustIDBase16(orgTwoID)),
+				},
+			},
+			wants: wants{
+				invites: []*influxdb.Invite{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := &mock.TimeGenerator{FakeValue: inviteTime}
+			tt.fields.TimeGenerator = tg
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+			tg.FakeValue = inviteTime.Add(tt.args.elapsed)
+
+			is, err := s.FindInvites(ctx, tt.args.filter)
+			if err != nil {
+				t.Fatalf("failed to retrieve invites: %v", err)
+			}
+			if diff := cmp.Diff(is, tt.wants.invites); diff != "" {
+				t.Errorf("invites are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// RevokeInvite testing
+func RevokeInvite(
+	init func(InviteFields, *testing.T) (InviteServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err     error
+		invites []*influxdb.Invite
+	}
+
+	tests := []struct {
+		name   string
+		fields InviteFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "revoked invites are deleted",
+			fields: inviteFields(t),
+			args: args{
+				id: MustIDBase16(inviteOneID),
+			},
+			wants: wants{
+				invites: []*influxdb.Invite{},
+			},
+		},
+		{
+			name:   "revoking missing invites is not found",
+			fields: inviteFields(t),
+			args: args{
+				id: MustIDBase16(inviteTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrInviteNotFound,
+				},
+				invites: []*influxdb.Invite{
+					newInvite(inviteOneID, "ada@example.com", influxdb.Owner, "token1", time.Hour),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.RevokeInvite(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			is, err := s.FindInvites(ctx, influxdb.InviteFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve invites: %v", err)
+			}
+			if diff := cmp.Diff(is, tt.wants.invites); diff != "" {
+				t.Errorf("invites are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// AcceptInvite testing
+func AcceptInvite(
+	init func(InviteFields, *testing.T) (InviteServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		token      string
+		acceptance influxdb.InviteAcceptance
+		// accepted accepts the invite before, if true.
+		accepted bool
+		// elapsed is the time that passes after the store is populated.
+		elapsed time.Duration
+	}
+	type wants struct {
+		err error
+		// user is the name of the user of the accepted invite.
+		user     string
+		mappings []*influxdb.UserResourceMapping
+	}
+
+	fields := func() InviteFields {
+		f := inviteFields(t)
+		f.IDGenerator = mock.NewIDGenerator(userOneID, t)
+		return f
+	}
+	ownerMappings := []*influxdb.UserResourceMapping{
+		{
+			UserID:       MustIDBase16(userOneID),
+			UserType:     influxdb.Owner,
+			MappingType:  influxdb.UserMappingType,
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   MustIDBase16(orgOneID),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		fields InviteFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "accepting an invite creates a user named after its email",
+			fields: fields(),
+			args: args{
+				token: "token1",
+				acceptance: influxdb.InviteAcceptance{
+					Password: "password1",
+				},
+			},
+			wants: wants{
+				user:     "ada@example.com",
+				mappings: ownerMappings,
+			},
+		},
+		{
+			name:   "users choose their name",
+			fields: fields(),
+			args: args{
+				token: "token1",
+				acceptance: influxdb.InviteAcceptance{
+					Name:     "ada",
+					Password: "password1",
+				},
+			},
+			wants: wants{
+				user:     "ada",
+				mappings: ownerMappings,
+			},
+		},
+		{
+			name:   "passwords of the password policy are required",
+			fields: fields(),
+			args: args{
+				token: "token1",
+				acceptance: influxdb.InviteAcceptance{
+					Password: "short",
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "passwords must be at least 8 characters long",
+				},
+				mappings: []*influxdb.UserResourceMapping{},
+			},
+		},
+		{
+			name:   "invites are accepted once",
+			fields: fields(),
+			args: args{
+				token: "token1",
+				acceptance: influxdb.InviteAcceptance{
+					Name:     "eve",
+					Password: "password1",
+				},
+				accepted: true,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "invite has already been accepted",
+				},
+				mappings: ownerMappings,
+			},
+		},
+		{
+			name:   "expired invites are rejected",
+			fields: fields(),
+			args: args{
+				token: "token1",
+				acceptance: influxdb.InviteAcceptance{
+					Password: "password1",
+				},
+				elapsed: 2 * time.Hour,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EForbidden,
+					Msg:  "invite has expired",
+				},
+				mappings: []*influxdb.UserResourceMapping{},
+			},
+		},
+		{
+			name:   "invites of unknown tokens are not found",
+			fields: fields(),
+			args: args{
+				token: "token2",
+				acceptance: influxdb.InviteAcceptance{
+					Password: "password1",
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrInviteNotFound,
+				},
+				mappings: []*influxdb.UserResourceMapping{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := &mock.TimeGenerator{FakeValue: inviteTime}
+			tt.fields.TimeGenerator = tg
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			if tt.args.accepted {
+				if _, err := s.AcceptInvite(ctx, tt.args.token, influxdb.InviteAcceptance{Password: "password1"}); err != nil {
+					t.Fatalf("failed to accept invite: %v", err)
+				}
+			}
+			tg.FakeValue = inviteTime.Add(tt.args.elapsed)
+
+			u, err := s.AcceptInvite(ctx, tt.args.token, tt.args.acceptance)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if tt.wants.user != "" {
+				if u == nil || u.Name != tt.wants.user {
+					t.Fatalf("expected the user %q, got %+v", tt.wants.user, u)
+				}
+				if err := s.ComparePassword(ctx, u.Name, tt.args.acceptance.Password); err != nil {
+					t.Errorf("expected the user to sign in with their password: %v", err)
+				}
+
+				i, err := s.FindInviteByToken(ctx, tt.args.token)
+				if err != nil {
+					t.Fatalf("failed to retrieve invite: %v", err)
+				}
+				if i.Status != influxdb.InviteAccepted || i.UserID != u.ID || i.AcceptedAt == nil {
+					t.Errorf("expected the accepted invite to record its user, got %+v", i)
+				}
+			}
+
+			ms, _, err := s.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+				ResourceType: influxdb.OrgsResourceType,
+				ResourceID:   MustIDBase16(orgOneID),
+			})
+			if err != nil {
+				t.Fatalf("failed to retrieve user resource mappings: %v", err)
+			}
+			if diff := cmp.Diff(ms, tt.wants.mappings); diff != "" {
+				t.Errorf("members of the organization are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
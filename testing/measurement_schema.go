@@ -0,0 +1,449 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	measurementSchemaOneID = "020f755c3c082100"
+	measurementSchemaTwoID = "020f755c3c082101"
+)
+
+// MeasurementSchemaFields will include the IDGenerator, and the organizations, buckets
+// and measurement schemas to populate the store with.
+type MeasurementSchemaFields struct {
+	IDGenerator        influxdb.IDGenerator
+	TimeGenerator      influxdb.TimeGenerator
+	Organizations      []*influxdb.Organization
+	Buckets            []*influxdb.Bucket
+	MeasurementSchemas []*influxdb.MeasurementSchema
+}
+
+// MeasurementSchemaServices are the measurement schema service and the bucket service
+// that holds the buckets of the schemas.
+type MeasurementSchemaServices interface {
+	influxdb.MeasurementSchemaService
+	influxdb.BucketService
+}
+
+type measurementSchemaServiceF func(
+	init func(MeasurementSchemaFields, *testing.T) (MeasurementSchemaServices, func()),
+	t *testing.T,
+)
+
+// MeasurementSchemaService tests all the service functions.
+func MeasurementSchemaService(
+	init func(MeasurementSchemaFields, *testing.T) (MeasurementSchemaServices, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   measurementSchemaServiceF
+	}{
+		{
+			name: "CreateMeasurementSchema",
+			fn:   CreateMeasurementSchema,
+		},
+		{
+			name: "FindMeasurementSchemas",
+			fn:   FindMeasurementSchemas,
+		},
+		{
+			name: "UpdateMeasurementSchema",
+			fn:   UpdateMeasurementSchema,
+		},
+		{
+			name: "DeleteBucketMeasurementSchemas",
+			fn:   DeleteBucketMeasurementSchemas,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var measurementSchemaTime = time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+
+func measurementSchemaColumns(extra ...influxdb.MeasurementSchemaColumn) []influxdb.MeasurementSchemaColumn {
+	return append([]influxdb.MeasurementSchemaColumn{
+		{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+		{Name: "host", Type: influxdb.SemanticColumnTypeTag},
+		{Name: "usage_user", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeFloat},
+	}, extra...)
+}
+
+func newMeasurementSchema(id, bucketID, name string, columns []influxdb.MeasurementSchemaColumn) *influxdb.MeasurementSchema {
+	m := &influxdb.MeasurementSchema{
+		BucketID: MustIDBase16(bucketID),
+		Name:     name,
+		Columns:  columns,
+	}
+	if id != "" {
+		m.ID = MustIDBase16(id)
+		m.OrgID = MustIDBase16(orgOneID)
+		m.CRUDLog = influxdb.CRUDLog{
+			CreatedAt: measurementSchemaTime,
+			UpdatedAt: measurementSchemaTime,
+		}
+	}
+	return m
+}
+
+func measurementSchemaFields(t *testing.T) MeasurementSchemaFields {
+	return MeasurementSchemaFields{
+		IDGenerator:   mock.NewIDGenerator(measurementSchemaTwoID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: measurementSchemaTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+		},
+		Buckets: []*influxdb.Bucket{
+			{
+				ID:         MustIDBase16(bucketOneID),
+				OrgID:      MustIDBase16(orgOneID),
+				Name:       "explicit",
+				SchemaType: influxdb.BucketSchemaTypeExplicit,
+			},
+			{
+				ID:    MustIDBase16(bucketTwoID),
+				OrgID: MustIDBase16(orgOneID),
+				Name:  "implicit",
+			},
+		},
+		MeasurementSchemas: []*influxdb.MeasurementSchema{
+			newMeasurementSchema(measurementSchemaOneID, bucketOneID, "cpu", measurementSchemaColumns()),
+		},
+	}
+}
+
+// CreateMeasurementSchema testing
+func CreateMeasurementSchema(
+	init func(MeasurementSchemaFields, *testing.T) (MeasurementSchemaServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		measurementSchema *influxdb.MeasurementSchema
+	}
+	type wants struct {
+		err                error
+		measurementSchemas []*influxdb.MeasurementSchema
+	}
+
+	tests := []struct {
+		name   string
+		fields MeasurementSchemaFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "create a schema in the organization of its bucket",
+			fields: measurementSchemaFields(t),
+			args: args{
+				measurementSchema: newMeasurementSchema("", bucketOneID, "mem", measurementSchemaColumns()),
+			},
+			wants: wants{
+				measurementSchemas: []*influxdb.MeasurementSchema{
+					newMeasurementSchema(measurementSchemaOneID, bucketOneID, "cpu", measurementSchemaColumns()),
+					newMeasurementSchema(measurementSchemaTwoID, bucketOneID, "mem", measurementSchemaColumns()),
+				},
+			},
+		},
+		{
+			name:   "names are unique within a bucket",
+			fields: measurementSchemaFields(t),
+			args: args{
+				measurementSchema: newMeasurementSchema("", bucketOneID, "cpu", measurementSchemaColumns()),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "measurement schema with name cpu already exists",
+				},
+				measurementSchemas: []*influxdb.MeasurementSchema{
+					newMeasurementSchema(measurementSchemaOneID, bucketOneID, "cpu", measurementSchemaColumns()),
+				},
+			},
+		},
+		{
+			name:   "buckets with an implicit schema type have no schemas",
+			fields: measurementSchemaFields(t),
+			args: args{
+				measurementSchema: newMeasurementSchema("", bucketTwoID, "cpu", measurementSchemaColumns()),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "bucket implicit does not have an explicit schema type",
+				},
+				measurementSchemas: []*influxdb.MeasurementSchema{
+					newMeasurementSchema(measurementSchemaOneID, bucketOneID, "cpu", measurementSchemaColumns()),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateMeasurementSchema(ctx, tt.args.measurementSchema)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			mss, err := s.FindMeasurementSchemas(ctx, influxdb.MeasurementSchemaFilter{
+				BucketID: MustIDBase16(bucketOneID),
+			})
+			if err != nil {
+				t.Fatalf("failed to retrieve measurement schemas: %v", err)
+			}
+			if diff := cmp.Diff(mss, tt.wants.measurementSchemas); diff != "" {
+				t.Errorf("measurement schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindMeasurementSchemas testing
+func FindMeasurementSchemas(
+	init func(MeasurementSchemaFields, *testing.T) (MeasurementSchemaServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.MeasurementSchemaFilter
+	}
+	type wants struct {
+		measurementSchemas []*influxdb.MeasurementSchema
+	}
+
+	fields := func() MeasurementSchemaFields {
+		f := measurementSchemaFields(t)
+		f.MeasurementSchemas = append(f.MeasurementSchemas,
+			newMeasurementSchema(measurementSchemaTwoID, bucketOneID, "mem", measurementSchemaColumns()),
+		)
+		return f
+	}
+
+	tests := []struct {
+		name   string
+		fields MeasurementSchemaFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the schemas of a bucket",
+			fields: fields(),
+			args: args{
+				filter: influxdb.MeasurementSchemaFilter{
+					BucketID: MustIDBase16(bucketOneID),
+				},
+			},
+			wants: wants{
+				measurementSchemas: []*influxdb.MeasurementSchema{
+					newMeasurementSchema(measurementSchemaOneID, bucketOneID, "cpu", measurementSchemaColumns()),
+					newMeasurementSchema(measurementSchemaTwoID, bucketOneID, "mem", measurementSchemaColumns()),
+				},
+			},
+		},
+		{
+			name:   "find a schema of a bucket by name",
+			fields: fields(),
+			args: args{
+				filter: influxdb.MeasurementSchemaFilter{
+					BucketID: MustIDBase16(bucketOneID),
+					Name:     strPtr("mem"),
+				},
+			},
+			wants: wants{
+				measurementSchemas: []*influxdb.MeasurementSchema{
+					newMeasurementSchema(measurementSchemaTwoID, bucketOneID, "mem", measurementSchemaColumns()),
+				},
+			},
+		},
+		{
+			name:   "buckets without schemas have none",
+			fields: fields(),
+			args: args{
+				filter: influxdb.MeasurementSchemaFilter{
+					BucketID: MustIDBase16(bucketTwoID),
+				},
+			},
+			wants: wants{
+				measurementSchemas: []*influxdb.MeasurementSchema{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			mss, err := s.FindMeasurementSchemas(ctx, tt.args.filter)
+			if err != nil {
+				t.Fatalf("failed to retrieve measurement schemas: %v", err)
+			}
+			if diff := cmp.Diff(mss, tt.wants.measurementSchemas); diff != "" {
+				t.Errorf("measurement schemas are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateMeasurementSchema testing
+func UpdateMeasurementSchema(
+	init func(MeasurementSchemaFields, *testing.T) (MeasurementSchemaServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  influxdb.ID
+		upd influxdb.MeasurementSchemaUpdate
+	}
+	type wants struct {
+		err               error
+		measurementSchema *influxdb.MeasurementSchema
+	}
+
+	usageSystem := influxdb.MeasurementSchemaColumn{
+		Name:     "usage_system",
+		Type:     influxdb.SemanticColumnTypeField,
+		DataType: influxdb.SchemaColumnDataTypeFloat,
+	}
+
+	tests := []struct {
+		name   string
+		fields MeasurementSchemaFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "add a column",
+			fields: measurementSchemaFields(t),
+			args: args{
+				id: MustIDBase16(measurementSchemaOneID),
+				upd: influxdb.MeasurementSchemaUpdate{
+					Columns: measurementSchemaColumns(usageSystem),
+				},
+			},
+			wants: wants{
+				measurementSchema: newMeasurementSchema(measurementSchemaOneID, bucketOneID, "cpu", measurementSchemaColumns(usageSystem)),
+			},
+		},
+		{
+			name:   "the data types of columns can not be changed",
+			fields: measurementSchemaFields(t),
+			args: args{
+				id: MustIDBase16(measurementSchemaOneID),
+				upd: influxdb.MeasurementSchemaUpdate{
+					Columns: []influxdb.MeasurementSchemaColumn{
+						{Name: "time", Type: influxdb.SemanticColumnTypeTimestamp},
+						{Name: "host", Type: influxdb.SemanticColumnTypeTag},
+						{Name: "usage_user", Type: influxdb.SemanticColumnTypeField, DataType: influxdb.SchemaColumnDataTypeInteger},
+					},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("column %q can not be changed", "usage_user"),
+				},
+			},
+		},
+		{
+			name:   "updates of missing schemas are not found",
+			fields: measurementSchemaFields(t),
+			args: args{
+				id: MustIDBase16(measurementSchemaTwoID),
+				upd: influxdb.MeasurementSchemaUpdate{
+					Columns: measurementSchemaColumns(usageSystem),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrMeasurementSchemaNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			m, err := s.UpdateMeasurementSchema(ctx, MustIDBase16(bucketOneID), tt.args.id, tt.args.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(m, tt.wants.measurementSchema); diff != "" {
+				t.Errorf("measurement schema is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteBucketMeasurementSchemas testing
+func DeleteBucketMeasurementSchemas(
+	init func(MeasurementSchemaFields, *testing.T) (MeasurementSchemaServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		bucketID influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields MeasurementSchemaFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "the schemas of deleted buckets are deleted",
+			fields: measurementSchemaFields(t),
+			args: args{
+				bucketID: MustIDBase16(bucketOneID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrMeasurementSchemaNotFound,
+				},
+			},
+		},
+		{
+			name:   "the schemas of other buckets are kept",
+			fields: measurementSchemaFields(t),
+			args: args{
+				bucketID: MustIDBase16(bucketTwoID),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			if err := s.DeleteBucket(ctx, tt.args.bucketID); err != nil {
+				t.Fatalf("failed to delete bucket: %v", err)
+			}
+
+			_, err := s.FindMeasurementSchemaByID(ctx, MustIDBase16(bucketOneID), MustIDBase16(measurementSchemaOneID))
+			ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/task/options"
+)
+
+// NamePolicyFields will include the name policy of the service, and the
+// organizations and dashboards to populate the store with.
+type NamePolicyFields struct {
+	NamePolicy    *influxdb.NamePolicy
+	Organizations []*influxdb.Organization
+	Dashboards    []*influxdb.Dashboard
+}
+
+// NamePolicyServices are the organization service that holds the name policies of
+// organizations and the services of the resources whose names they restrict.
+type NamePolicyServices interface {
+	influxdb.OrganizationService
+	influxdb.UserService
+	influxdb.AuthorizationService
+	influxdb.TaskService
+	influxdb.DashboardService
+}
+
+type namePolicyServiceF func(
+	init func(NamePolicyFields, *testing.T) (NamePolicyServices, func()),
+	t *testing.T,
+)
+
+// NamePolicyService tests that the name policies of the service and of organizations
+// keep the names of resources unique.
+func NamePolicyService(
+	init func(NamePolicyFields, *testing.T) (NamePolicyServices, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   namePolicyServiceF
+	}{
+		{
+			name: "CreateOrganizationNamePolicy",
+			fn:   CreateOrganizationNamePolicy,
+		},
+		{
+			name: "DashboardNamePolicy",
+			fn:   DashboardNamePolicy,
+		},
+		{
+			name: "TaskNamePolicy",
+			fn:   TaskNamePolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// CreateOrganizationNamePolicy testing
+func CreateOrganizationNamePolicy(
+	init func(NamePolicyFields, *testing.T) (NamePolicyServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		namePolicy influxdb.NamePolicy
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		wants wants
+	}{
+		{
+			name: "create an organization with unique dashboard names",
+			args: args{
+				namePolicy: influxdb.NamePolicy{
+					UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.DashboardsResourceType},
+				},
+			},
+		},
+		{
+			name: "bucket names are always unique",
+			args: args{
+				namePolicy: influxdb.NamePolicy{
+					UniqueNames: []influxdb.ResourceType{influxdb.TasksResourceType},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "bucket names must be unique, since queries read buckets by name",
+				},
+			},
+		},
+		{
+			name: "policies only apply to resources of organizations",
+			args: args{
+				namePolicy: influxdb.NamePolicy{
+					UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.UsersResourceType},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "name policy can not apply to users; it applies to buckets, dashboards, tasks",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(NamePolicyFields{}, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateOrganization(ctx, &influxdb.Organization{
+				Name:       "theorg",
+				NamePolicy: &tt.args.namePolicy,
+			})
+			ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+// DashboardNamePolicy testing
+func DashboardNamePolicy(
+	init func(NamePolicyFields, *testing.T) (NamePolicyServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		dashboard *influxdb.Dashboard
+		// id is the dashboard that is renamed to the name of the dashboard
+		// instead of creating it, if it is valid.
+		id influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	fields := func() NamePolicyFields {
+		return NamePolicyFields{
+			Organizations: []*influxdb.Organization{
+				{
+					ID:   MustIDBase16(orgOneID),
+					Name: "unique-dashboards",
+					NamePolicy: &influxdb.NamePolicy{
+						UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.DashboardsResourceType},
+					},
+				},
+				{
+					ID:   MustIDBase16(orgTwoID),
+					Name: "shared-dashboards",
+				},
+			},
+			Dashboards: []*influxdb.Dashboard{
+				{
+					ID:             MustIDBase16(dashOneID),
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "cpu",
+				},
+				{
+					ID:             MustIDBase16(dashTwoID),
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "mem",
+				},
+				{
+					ID:             MustIDBase16(dashThreeID),
+					OrganizationID: MustIDBase16(orgTwoID),
+					Name:           "cpu",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		fields NamePolicyFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "dashboards of an organization with unique names can not share a name",
+			fields: fields(),
+			args: args{
+				dashboard: &influxdb.Dashboard{
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "cpu",
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  `name "cpu" is already used by other dashboards of the organization, and the names of dashboards must be unique`,
+				},
+			},
+		},
+		{
+			name:   "dashboards can not be renamed to the name of another dashboard",
+			fields: fields(),
+			args: args{
+				dashboard: &influxdb.Dashboard{
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "cpu",
+				},
+				id: MustIDBase16(dashTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  `name "cpu" is already used by other dashboards of the organization, and the names of dashboards must be unique`,
+				},
+			},
+		},
+		{
+			name:   "dashboards of an organization with the policy of the service share names",
+			fields: fields(),
+			args: args{
+				dashboard: &influxdb.Dashboard{
+					OrganizationID: MustIDBase16(orgTwoID),
+					Name:           "cpu",
+				},
+			},
+		},
+		{
+			name: "the service policy applies to organizations without one",
+			fields: NamePolicyFields{
+				NamePolicy: &influxdb.NamePolicy{
+					UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.DashboardsResourceType},
+				},
+				Organizations: []*influxdb.Organization{
+					{
+						ID:   MustIDBase16(orgOneID),
+						Name: "theorg",
+					},
+				},
+				Dashboards: []*influxdb.Dashboard{
+					{
+						ID:             MustIDBase16(dashOneID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "cpu",
+					},
+				},
+			},
+			args: args{
+				dashboard: &influxdb.Dashboard{
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "cpu",
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  `name "cpu" is already used by other dashboards of the organization, and the names of dashboards must be unique`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			var err error
+			if tt.args.id.Valid() {
+				_, err = s.UpdateDashboard(ctx, tt.args.id, influxdb.DashboardUpdate{
+					Name: &tt.args.dashboard.Name,
+				})
+			} else {
+				err = s.CreateDashboard(ctx, tt.args.dashboard)
+			}
+			ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+// TaskNamePolicy testing
+func TaskNamePolicy(
+	init func(NamePolicyFields, *testing.T) (NamePolicyServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		// tasks are the names of the tasks of the organization.
+		tasks []string
+		// rename is the index of the task that is renamed to the name
+		// instead of creating a task of the name, if it is not negative.
+		rename int
+		name   string
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields NamePolicyFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "tasks of an organization with unique names can not share a name",
+			fields: NamePolicyFields{
+				NamePolicy: &influxdb.NamePolicy{
+					UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.TasksResourceType},
+				},
+			},
+			args: args{
+				tasks:  []string{"a", "b"},
+				rename: -1,
+				name:   "a",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  `name "a" is already used by other tasks of the organization, and the names of tasks must be unique`,
+				},
+			},
+		},
+		{
+			name: "tasks can not be renamed to the name of another task",
+			fields: NamePolicyFields{
+				NamePolicy: &influxdb.NamePolicy{
+					UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.TasksResourceType},
+				},
+			},
+			args: args{
+				tasks:  []string{"a", "b"},
+				rename: 1,
+				name:   "a",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  `name "a" is already used by other tasks of the organization, and the names of tasks must be unique`,
+				},
+			},
+		},
+		{
+			name: "tasks keep their own name",
+			fields: NamePolicyFields{
+				NamePolicy: &influxdb.NamePolicy{
+					UniqueNames: []influxdb.ResourceType{influxdb.BucketsResourceType, influxdb.TasksResourceType},
+				},
+			},
+			args: args{
+				tasks:  []string{"a", "b"},
+				rename: 1,
+				name:   "b",
+			},
+		},
+		{
+			name: "tasks share names by default",
+			args: args{
+				tasks:  []string{"a", "b"},
+				rename: -1,
+				name:   "a",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			org := &influxdb.Organization{Name: "theorg"}
+			if err := s.CreateOrganization(ctx, org); err != nil {
+				t.Fatalf("failed to create organization: %v", err)
+			}
+			user := &influxdb.User{Name: "user1"}
+			if err := s.CreateUser(ctx, user); err != nil {
+				t.Fatalf("failed to create user: %v", err)
+			}
+			auth := &influxdb.Authorization{OrgID: org.ID, UserID: user.ID, Permissions: influxdb.OperPermissions()}
+			if err := s.CreateAuthorization(ctx, auth); err != nil {
+				t.Fatalf("failed to create authorization: %v", err)
+			}
+			ctx = icontext.SetAuthorizer(ctx, auth)
+
+			create := func(name string) (*influxdb.Task, error) {
+				return s.CreateTask(ctx, influxdb.TaskCreate{
+					OrganizationID: org.ID,
+					Token:          auth.Token,
+					Flux:           fmt.Sprintf("option task = {name: %q, every: 1m}\nfrom(bucket: \"b\") |> range(start: -1m)", name),
+				})
+			}
+			var tasks []*influxdb.Task
+			for _, name := range tt.args.tasks {
+				task, err := create(name)
+				if err != nil {
+					t.Fatalf("failed to create task: %v", err)
+				}
+				tasks = append(tasks, task)
+			}
+
+			var err error
+			if tt.args.rename >= 0 {
+				_, err = s.UpdateTask(ctx, tasks[tt.args.rename].ID, influxdb.TaskUpdate{
+					Options: options.Options{Name: tt.args.name},
+				})
+			} else {
+				_, err = create(tt.args.name)
+			}
+			ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
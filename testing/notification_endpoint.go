@@ -0,0 +1,673 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	endpointOneID   = "020f755c3c088000"
+	endpointTwoID   = "020f755c3c088001"
+	endpointThreeID = "020f755c3c088002"
+)
+
+// NotificationEndpointFields will include the IDGenerator, TimeGenerator, and the
+// organizations, secrets and notification endpoints to populate the store with.
+type NotificationEndpointFields struct {
+	IDGenerator           influxdb.IDGenerator
+	TimeGenerator         influxdb.TimeGenerator
+	Organizations         []*influxdb.Organization
+	Secrets               []Secret
+	NotificationEndpoints []*influxdb.NotificationEndpoint
+}
+
+// NotificationEndpointServices are the notification endpoint service and the secret
+// service that holds the secrets of the endpoints.
+type NotificationEndpointServices interface {
+	influxdb.NotificationEndpointService
+	influxdb.SecretService
+}
+
+type notificationEndpointServiceF func(
+	init func(NotificationEndpointFields, *testing.T) (NotificationEndpointServices, func()),
+	t *testing.T,
+)
+
+// NotificationEndpointService tests all the service functions.
+func NotificationEndpointService(
+	init func(NotificationEndpointFields, *testing.T) (NotificationEndpointServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   notificationEndpointServiceF
+	}{
+		{
+			name: "CreateNotificationEndpoint",
+			fn:   CreateNotificationEndpoint,
+		},
+		{
+			name: "FindNotificationEndpoints",
+			fn:   FindNotificationEndpoints,
+		},
+		{
+			name: "UpdateNotificationEndpoint",
+			fn:   UpdateNotificationEndpoint,
+		},
+		{
+			name: "ReplaceNotificationEndpoint",
+			fn:   ReplaceNotificationEndpoint,
+		},
+		{
+			name: "DeleteNotificationEndpoint",
+			fn:   DeleteNotificationEndpoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var notificationEndpointTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func newPagerDutyEndpoint(id, orgID, name string, routingKey influxdb.SecretField) *influxdb.NotificationEndpoint {
+	e := &influxdb.NotificationEndpoint{
+		OrgID:      MustIDBase16(orgID),
+		Name:       name,
+		Status:     influxdb.Active,
+		Type:       influxdb.PagerDutyNotificationEndpoint,
+		RoutingKey: &routingKey,
+	}
+	if id != "" {
+		e.ID = MustIDBase16(id)
+		e.CRUDLog = influxdb.CRUDLog{
+			CreatedAt: notificationEndpointTime,
+			UpdatedAt: notificationEndpointTime,
+		}
+	}
+	return e
+}
+
+func newSlackEndpoint(id, orgID, name string) *influxdb.NotificationEndpoint {
+	return &influxdb.NotificationEndpoint{
+		ID:     MustIDBase16(id),
+		OrgID:  MustIDBase16(orgID),
+		Name:   name,
+		Status: influxdb.Active,
+		Type:   influxdb.SlackNotificationEndpoint,
+		URL:    "https://hooks.slack.com/services/x",
+		CRUDLog: influxdb.CRUDLog{
+			CreatedAt: notificationEndpointTime,
+			UpdatedAt: notificationEndpointTime,
+		},
+	}
+}
+
+// routingKeyOf returns the secret field of a routing key kept in the secret named
+// after the endpoint id.
+func routingKeyOf(id string) influxdb.SecretField {
+	return influxdb.SecretField{Key: id + "-routingKey"}
+}
+
+// notificationEndpointFields returns the fields of a store with the oncall pagerduty
+// endpoint, whose routing key is kept in a secret named after it, and the chat slack
+// endpoint of the first organization, which also has the shared secret.
+func notificationEndpointFields(t *testing.T) NotificationEndpointFields {
+	return NotificationEndpointFields{
+		IDGenerator:   mock.NewIDGenerator(endpointThreeID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: notificationEndpointTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		Secrets: []Secret{
+			{
+				OrganizationID: MustIDBase16(orgOneID),
+				Env:            map[string]string{"shared": "def456"},
+			},
+		},
+		NotificationEndpoints: []*influxdb.NotificationEndpoint{
+			newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", influxdb.SecretField{Value: strPtr("abc123")}),
+			newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+		},
+	}
+}
+
+// notificationEndpointsOf returns the notification endpoints and the secret keys of
+// the first organization in the store.
+func notificationEndpointsOf(ctx context.Context, s NotificationEndpointServices, t *testing.T) ([]*influxdb.NotificationEndpoint, []string) {
+	t.Helper()
+	es, _, err := s.FindNotificationEndpoints(ctx, influxdb.NotificationEndpointFilter{})
+	if err != nil {
+		t.Fatalf("failed to retrieve notification endpoints: %v", err)
+	}
+	keys, err := s.GetSecretKeys(ctx, MustIDBase16(orgOneID))
+	if err != nil {
+		t.Fatalf("failed to retrieve secret keys: %v", err)
+	}
+	return es, keys
+}
+
+// CreateNotificationEndpoint testing
+func CreateNotificationEndpoint(
+	init func(NotificationEndpointFields, *testing.T) (NotificationEndpointServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		endpoint *influxdb.NotificationEndpoint
+	}
+	type wants struct {
+		err        error
+		endpoints  []*influxdb.NotificationEndpoint
+		secretKeys []string
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationEndpointFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "credentials given by value are kept in secrets named after the endpoint",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: newPagerDutyEndpoint("", orgOneID, "backup", influxdb.SecretField{Value: strPtr("xyz789")}),
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+					newPagerDutyEndpoint(endpointThreeID, orgOneID, "backup", routingKeyOf(endpointThreeID)),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", endpointThreeID + "-routingKey", "shared"},
+			},
+		},
+		{
+			name:   "credentials reference existing secrets by key",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: newPagerDutyEndpoint("", orgOneID, "backup", influxdb.SecretField{Key: "shared"}),
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+					newPagerDutyEndpoint(endpointThreeID, orgOneID, "backup", influxdb.SecretField{Key: "shared"}),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+		{
+			name:   "credentials referencing missing secrets are rejected",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: newPagerDutyEndpoint("", orgOneID, "backup", influxdb.SecretField{Key: "missing"}),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "routingKey references secret missing that does not exist",
+				},
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: newPagerDutyEndpoint("", orgOneID, "oncall", influxdb.SecretField{Key: "shared"}),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "notification endpoint with name oncall already exists",
+				},
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+		{
+			name:   "endpoints of missing organizations are not found",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: newPagerDutyEndpoint("", orgThreeID, "backup", influxdb.SecretField{Value: strPtr("xyz789")}),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+		{
+			name:   "endpoints require what their type needs",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: newPagerDutyEndpoint("", orgOneID, "backup", influxdb.SecretField{}),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "pagerduty notification endpoint needs a routing key",
+				},
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateNotificationEndpoint(ctx, tt.args.endpoint)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			es, keys := notificationEndpointsOf(ctx, s, t)
+			if diff := cmp.Diff(es, tt.wants.endpoints); diff != "" {
+				t.Errorf("notification endpoints are different -got/+want\ndiff %s", diff)
+			}
+			if diff := cmp.Diff(keys, tt.wants.secretKeys); diff != "" {
+				t.Errorf("secret keys are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindNotificationEndpoints testing
+func FindNotificationEndpoints(
+	init func(NotificationEndpointFields, *testing.T) (NotificationEndpointServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.NotificationEndpointFilter
+	}
+	type wants struct {
+		err       error
+		endpoints []*influxdb.NotificationEndpoint
+	}
+
+	pagerDuty := influxdb.PagerDutyNotificationEndpoint
+	tests := []struct {
+		name   string
+		fields NotificationEndpointFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the endpoints of an organization",
+			fields: notificationEndpointFields(t),
+			args: args{
+				filter: influxdb.NotificationEndpointFilter{OrgID: idPtr(MustIDBase16(orgOneID))},
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+			},
+		},
+		{
+			name:   "find endpoints by type",
+			fields: notificationEndpointFields(t),
+			args: args{
+				filter: influxdb.NotificationEndpointFilter{Type: &pagerDuty},
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+				},
+			},
+		},
+		{
+			name:   "find endpoints by name",
+			fields: notificationEndpointFields(t),
+			args: args{
+				filter: influxdb.NotificationEndpointFilter{Name: strPtr("chat")},
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+			},
+		},
+		{
+			name:   "find an endpoint by id",
+			fields: notificationEndpointFields(t),
+			args: args{
+				filter: influxdb.NotificationEndpointFilter{ID: idPtr(MustIDBase16(endpointTwoID))},
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+			},
+		},
+		{
+			name:   "organizations without endpoints have none",
+			fields: notificationEndpointFields(t),
+			args: args{
+				filter: influxdb.NotificationEndpointFilter{OrgID: idPtr(MustIDBase16(orgTwoID))},
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			es, n, err := s.FindNotificationEndpoints(ctx, tt.args.filter)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if n != len(tt.wants.endpoints) {
+				t.Errorf("expected %d notification endpoints, got %d", len(tt.wants.endpoints), n)
+			}
+			if diff := cmp.Diff(es, tt.wants.endpoints); diff != "" {
+				t.Errorf("notification endpoints are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateNotificationEndpoint testing
+func UpdateNotificationEndpoint(
+	init func(NotificationEndpointFields, *testing.T) (NotificationEndpointServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  influxdb.ID
+		upd influxdb.NotificationEndpointUpdate
+	}
+	type wants struct {
+		err      error
+		endpoint *influxdb.NotificationEndpoint
+	}
+
+	inactive := newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID))
+	inactive.Status = influxdb.Inactive
+
+	tests := []struct {
+		name   string
+		fields NotificationEndpointFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "deactivate an endpoint",
+			fields: notificationEndpointFields(t),
+			args: args{
+				id:  MustIDBase16(endpointOneID),
+				upd: influxdb.NotificationEndpointUpdate{Status: influxdb.Inactive.Ptr()},
+			},
+			wants: wants{
+				endpoint: inactive,
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: notificationEndpointFields(t),
+			args: args{
+				id:  MustIDBase16(endpointTwoID),
+				upd: influxdb.NotificationEndpointUpdate{Name: strPtr("oncall")},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "notification endpoint with name oncall already exists",
+				},
+			},
+		},
+		{
+			name:   "missing endpoints are not found",
+			fields: notificationEndpointFields(t),
+			args: args{
+				id:  MustIDBase16(endpointThreeID),
+				upd: influxdb.NotificationEndpointUpdate{Status: influxdb.Inactive.Ptr()},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrNotificationEndpointNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			e, err := s.UpdateNotificationEndpoint(ctx, tt.args.id, tt.args.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(e, tt.wants.endpoint); diff != "" {
+				t.Errorf("notification endpoint is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// ReplaceNotificationEndpoint testing
+func ReplaceNotificationEndpoint(
+	init func(NotificationEndpointFields, *testing.T) (NotificationEndpointServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		endpoint *influxdb.NotificationEndpoint
+	}
+	type wants struct {
+		err        error
+		endpoints  []*influxdb.NotificationEndpoint
+		secretKeys []string
+	}
+
+	// replacement returns the oncall endpoint without the fields the store keeps.
+	replacement := func(routingKey influxdb.SecretField) *influxdb.NotificationEndpoint {
+		return &influxdb.NotificationEndpoint{
+			ID:         MustIDBase16(endpointOneID),
+			Name:       "oncall",
+			Type:       influxdb.PagerDutyNotificationEndpoint,
+			RoutingKey: &routingKey,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationEndpointFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "secrets named after the endpoint it does not use anymore are deleted",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: replacement(influxdb.SecretField{Key: "shared"}),
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", influxdb.SecretField{Key: "shared"}),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{"shared"},
+			},
+		},
+		{
+			name:   "credentials given by value replace the secrets named after the endpoint",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: replacement(influxdb.SecretField{Value: strPtr("xyz789")}),
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+		{
+			name:   "credentials referencing missing secrets are rejected",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: replacement(influxdb.SecretField{Key: "missing"}),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "routingKey references secret missing that does not exist",
+				},
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+		{
+			name:   "missing endpoints are not found",
+			fields: notificationEndpointFields(t),
+			args: args{
+				endpoint: &influxdb.NotificationEndpoint{
+					ID:         MustIDBase16(endpointThreeID),
+					Name:       "backup",
+					Type:       influxdb.PagerDutyNotificationEndpoint,
+					RoutingKey: &influxdb.SecretField{Key: "shared"},
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrNotificationEndpointNotFound,
+				},
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			_, err := s.ReplaceNotificationEndpoint(ctx, tt.args.endpoint)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			es, keys := notificationEndpointsOf(ctx, s, t)
+			if diff := cmp.Diff(es, tt.wants.endpoints); diff != "" {
+				t.Errorf("notification endpoints are different -got/+want\ndiff %s", diff)
+			}
+			if diff := cmp.Diff(keys, tt.wants.secretKeys); diff != "" {
+				t.Errorf("secret keys are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteNotificationEndpoint testing
+func DeleteNotificationEndpoint(
+	init func(NotificationEndpointFields, *testing.T) (NotificationEndpointServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err        error
+		endpoints  []*influxdb.NotificationEndpoint
+		secretKeys []string
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationEndpointFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "delete an endpoint and the secrets named after it",
+			fields: notificationEndpointFields(t),
+			args: args{
+				id: MustIDBase16(endpointOneID),
+			},
+			wants: wants{
+				endpoints: []*influxdb.NotificationEndpoint{
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{"shared"},
+			},
+		},
+		{
+			name:   "missing endpoints are not found",
+			fields: notificationEndpointFields(t),
+			args: args{
+				id: MustIDBase16(endpointThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrNotificationEndpointNotFound,
+				},
+				endpoints: []*influxdb.NotificationEndpoint{
+					newPagerDutyEndpoint(endpointOneID, orgOneID, "oncall", routingKeyOf(endpointOneID)),
+					newSlackEndpoint(endpointTwoID, orgOneID, "chat"),
+				},
+				secretKeys: []string{endpointOneID + "-routingKey", "shared"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteNotificationEndpoint(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			es, keys := notificationEndpointsOf(ctx, s, t)
+			if diff := cmp.Diff(es, tt.wants.endpoints); diff != "" {
+				t.Errorf("notification endpoints are different -got/+want\ndiff %s", diff)
+			}
+			if diff := cmp.Diff(keys, tt.wants.secretKeys); diff != "" {
+				t.Errorf("secret keys are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,890 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	ruleOneID   = "020f755c3c08a000"
+	ruleTwoID   = "020f755c3c08a001"
+	ruleThreeID = "020f755c3c08a002"
+
+	sentNotificationOneID   = "020f755c3c08a100"
+	sentNotificationTwoID   = "020f755c3c08a101"
+	sentNotificationThreeID = "020f755c3c08a102"
+	sentNotificationFourID  = "020f755c3c08a103"
+	sentNotificationFiveID  = "020f755c3c08a104"
+)
+
+// NotificationRuleFields will include the IDGenerator, TimeGenerator, and the
+// organizations, notification endpoints, checks and notification rules to populate
+// the store with.
+type NotificationRuleFields struct {
+	IDGenerator           influxdb.IDGenerator
+	TimeGenerator         influxdb.TimeGenerator
+	Organizations         []*influxdb.Organization
+	NotificationEndpoints []*influxdb.NotificationEndpoint
+	Checks                []*influxdb.Check
+	NotificationRules     []*influxdb.NotificationRule
+}
+
+type notificationRuleServiceF func(
+	init func(NotificationRuleFields, *testing.T) (influxdb.NotificationRuleService, func()),
+	t *testing.T,
+)
+
+// NotificationRuleService tests all the service functions.
+func NotificationRuleService(
+	init func(NotificationRuleFields, *testing.T) (influxdb.NotificationRuleService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   notificationRuleServiceF
+	}{
+		{
+			name: "CreateNotificationRule",
+			fn:   CreateNotificationRule,
+		},
+		{
+			name: "FindNotificationRules",
+			fn:   FindNotificationRules,
+		},
+		{
+			name: "UpdateNotificationRule",
+			fn:   UpdateNotificationRule,
+		},
+		{
+			name: "ReplaceNotificationRule",
+			fn:   ReplaceNotificationRule,
+		},
+		{
+			name: "DeleteNotificationRule",
+			fn:   DeleteNotificationRule,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var notificationRuleTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// newNotificationRule returns a rule that notifies the endpoint of critical statuses,
+// with the default limits.
+func newNotificationRule(id, orgID, name, endpointID string) *influxdb.NotificationRule {
+	r := &influxdb.NotificationRule{
+		OrgID:      MustIDBase16(orgID),
+		Name:       name,
+		Status:     influxdb.Active,
+		EndpointID: MustIDBase16(endpointID),
+		StatusRules: []influxdb.StatusRule{
+			{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelCrit, Operation: influxdb.LevelRuleEqual}},
+		},
+		Limit:      influxdb.DefaultNotificationRuleLimit,
+		LimitEvery: influxdb.DefaultNotificationRuleLimitEvery,
+	}
+	if id != "" {
+		r.ID = MustIDBase16(id)
+		r.CRUDLog = influxdb.CRUDLog{
+			CreatedAt: notificationRuleTime,
+			UpdatedAt: notificationRuleTime,
+		}
+	}
+	return r
+}
+
+// withCheck returns the rule restricted to the statuses of the check.
+func withCheck(r *influxdb.NotificationRule, checkID string) *influxdb.NotificationRule {
+	r.CheckID = MustIDBase16(checkID)
+	return r
+}
+
+// inactiveRule returns the inactive recovery rule of the heartbeat check.
+func inactiveRule() *influxdb.NotificationRule {
+	r := withCheck(newNotificationRule(ruleTwoID, orgOneID, "recovery", endpointOneID), checkOneID)
+	r.Status = influxdb.Inactive
+	return r
+}
+
+// notificationRuleFields returns the fields of a store with the ops endpoints and the
+// heartbeat checks of both organizations, and the crit and the inactive recovery
+// rules of the first organization.
+func notificationRuleFields(t *testing.T) NotificationRuleFields {
+	return NotificationRuleFields{
+		IDGenerator:   mock.NewIDGenerator(ruleThreeID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: notificationRuleTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		NotificationEndpoints: []*influxdb.NotificationEndpoint{
+			newSlackEndpoint(endpointOneID, orgOneID, "ops"),
+			newSlackEndpoint(endpointTwoID, orgTwoID, "ops"),
+		},
+		Checks: []*influxdb.Check{
+			newDeadmanCheck(checkOneID, orgOneID, "heartbeat"),
+			newDeadmanCheck(checkTwoID, orgTwoID, "heartbeat"),
+		},
+		NotificationRules: []*influxdb.NotificationRule{
+			newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+			inactiveRule(),
+		},
+	}
+}
+
+// notificationRulesOf returns the notification rules in the store.
+func notificationRulesOf(ctx context.Context, s influxdb.NotificationRuleService, t *testing.T) []*influxdb.NotificationRule {
+	t.Helper()
+	rs, _, err := s.FindNotificationRules(ctx, influxdb.NotificationRuleFilter{})
+	if err != nil {
+		t.Fatalf("failed to retrieve notification rules: %v", err)
+	}
+	return rs
+}
+
+// CreateNotificationRule testing
+func CreateNotificationRule(
+	init func(NotificationRuleFields, *testing.T) (influxdb.NotificationRuleService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		rule *influxdb.NotificationRule
+	}
+	type wants struct {
+		err   error
+		rules []*influxdb.NotificationRule
+	}
+
+	withoutStatusRules := newNotificationRule("", orgOneID, "warn", endpointOneID)
+	withoutStatusRules.StatusRules = nil
+
+	tests := []struct {
+		name   string
+		fields NotificationRuleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "create a rule with the default limits",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: &influxdb.NotificationRule{
+					OrgID:      MustIDBase16(orgOneID),
+					Name:       "warn",
+					EndpointID: MustIDBase16(endpointOneID),
+					StatusRules: []influxdb.StatusRule{
+						{CurrentLevel: influxdb.LevelRule{Level: influxdb.CheckLevelCrit, Operation: influxdb.LevelRuleEqual}},
+					},
+				},
+			},
+			wants: wants{
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+					newNotificationRule(ruleThreeID, orgOneID, "warn", endpointOneID),
+				},
+			},
+		},
+		{
+			name:   "create a rule of a check",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: withCheck(newNotificationRule("", orgOneID, "warn", endpointOneID), checkOneID),
+			},
+			wants: wants{
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+					withCheck(newNotificationRule(ruleThreeID, orgOneID, "warn", endpointOneID), checkOneID),
+				},
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: newNotificationRule("", orgOneID, "crit", endpointOneID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "notification rule with name crit already exists",
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "endpoints of other organizations are rejected",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: newNotificationRule("", orgOneID, "warn", endpointTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "notification endpoint belongs to another organization",
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "checks of other organizations are rejected",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: withCheck(newNotificationRule("", orgOneID, "warn", endpointOneID), checkTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "check belongs to another organization",
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "missing endpoints are not found",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: newNotificationRule("", orgOneID, "warn", endpointThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrNotificationEndpointNotFound,
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "missing checks are not found",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: withCheck(newNotificationRule("", orgOneID, "warn", endpointOneID), checkThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrCheckNotFound,
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "rules require a status rule",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: withoutStatusRules,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "notification rule needs at least one status rule",
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateNotificationRule(ctx, tt.args.rule)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(notificationRulesOf(ctx, s, t), tt.wants.rules); diff != "" {
+				t.Errorf("notification rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindNotificationRules testing
+func FindNotificationRules(
+	init func(NotificationRuleFields, *testing.T) (influxdb.NotificationRuleService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.NotificationRuleFilter
+		opts   []influxdb.FindOptions
+	}
+	type wants struct {
+		err   error
+		rules []*influxdb.NotificationRule
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationRuleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the rules of an endpoint",
+			fields: notificationRuleFields(t),
+			args: args{
+				filter: influxdb.NotificationRuleFilter{EndpointID: idPtr(MustIDBase16(endpointOneID))},
+			},
+			wants: wants{
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "find the rules of a check",
+			fields: notificationRuleFields(t),
+			args: args{
+				filter: influxdb.NotificationRuleFilter{CheckID: idPtr(MustIDBase16(checkOneID))},
+			},
+			wants: wants{
+				rules: []*influxdb.NotificationRule{
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "find a page of rules ordered by id",
+			fields: notificationRuleFields(t),
+			args: args{
+				filter: influxdb.NotificationRuleFilter{OrgID: idPtr(MustIDBase16(orgOneID))},
+				opts:   []influxdb.FindOptions{{Limit: 1}},
+			},
+			wants: wants{
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+				},
+			},
+		},
+		{
+			name:   "organizations without rules have none",
+			fields: notificationRuleFields(t),
+			args: args{
+				filter: influxdb.NotificationRuleFilter{OrgID: idPtr(MustIDBase16(orgTwoID))},
+			},
+			wants: wants{
+				rules: []*influxdb.NotificationRule{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			rs, n, err := s.FindNotificationRules(ctx, tt.args.filter, tt.args.opts...)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if n != len(tt.wants.rules) {
+				t.Errorf("expected %d notification rules, got %d", len(tt.wants.rules), n)
+			}
+			if diff := cmp.Diff(rs, tt.wants.rules); diff != "" {
+				t.Errorf("notification rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateNotificationRule testing
+func UpdateNotificationRule(
+	init func(NotificationRuleFields, *testing.T) (influxdb.NotificationRuleService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  influxdb.ID
+		upd influxdb.NotificationRuleUpdate
+	}
+	type wants struct {
+		err  error
+		rule *influxdb.NotificationRule
+	}
+
+	inactive := newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID)
+	inactive.Status = influxdb.Inactive
+
+	tests := []struct {
+		name   string
+		fields NotificationRuleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "deactivate a rule",
+			fields: notificationRuleFields(t),
+			args: args{
+				id:  MustIDBase16(ruleOneID),
+				upd: influxdb.NotificationRuleUpdate{Status: influxdb.Inactive.Ptr()},
+			},
+			wants: wants{
+				rule: inactive,
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: notificationRuleFields(t),
+			args: args{
+				id:  MustIDBase16(ruleTwoID),
+				upd: influxdb.NotificationRuleUpdate{Name: strPtr("crit")},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "notification rule with name crit already exists",
+				},
+			},
+		},
+		{
+			name:   "missing rules are not found",
+			fields: notificationRuleFields(t),
+			args: args{
+				id:  MustIDBase16(ruleThreeID),
+				upd: influxdb.NotificationRuleUpdate{Status: influxdb.Inactive.Ptr()},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrNotificationRuleNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			r, err := s.UpdateNotificationRule(ctx, tt.args.id, tt.args.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(r, tt.wants.rule); diff != "" {
+				t.Errorf("notification rule is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// ReplaceNotificationRule testing
+func ReplaceNotificationRule(
+	init func(NotificationRuleFields, *testing.T) (influxdb.NotificationRuleService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		rule *influxdb.NotificationRule
+	}
+	type wants struct {
+		err   error
+		rules []*influxdb.NotificationRule
+	}
+
+	// replacement returns the recovery rule without the fields the store keeps.
+	replacement := func(endpointID string) *influxdb.NotificationRule {
+		r := newNotificationRule(ruleTwoID, orgOneID, "recovery", endpointID)
+		r.OrgID = 0
+		r.Status = ""
+		r.CRUDLog = influxdb.CRUDLog{}
+		r.Limit = 0
+		r.LimitEvery = 60
+		return r
+	}
+	replaced := newNotificationRule(ruleTwoID, orgOneID, "recovery", endpointOneID)
+	replaced.Status = influxdb.Inactive
+	replaced.LimitEvery = 60
+
+	tests := []struct {
+		name   string
+		fields NotificationRuleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "replaced rules keep their organization and status",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: replacement(endpointOneID),
+			},
+			wants: wants{
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					replaced,
+				},
+			},
+		},
+		{
+			name:   "endpoints of other organizations are rejected",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: replacement(endpointTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "notification endpoint belongs to another organization",
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "missing rules are not found",
+			fields: notificationRuleFields(t),
+			args: args{
+				rule: newNotificationRule(ruleThreeID, orgOneID, "warn", endpointOneID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrNotificationRuleNotFound,
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			_, err := s.ReplaceNotificationRule(ctx, tt.args.rule)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(notificationRulesOf(ctx, s, t), tt.wants.rules); diff != "" {
+				t.Errorf("notification rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteNotificationRule testing
+func DeleteNotificationRule(
+	init func(NotificationRuleFields, *testing.T) (influxdb.NotificationRuleService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err   error
+		rules []*influxdb.NotificationRule
+	}
+
+	tests := []struct {
+		name   string
+		fields NotificationRuleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "delete a rule",
+			fields: notificationRuleFields(t),
+			args: args{
+				id: MustIDBase16(ruleOneID),
+			},
+			wants: wants{
+				rules: []*influxdb.NotificationRule{
+					inactiveRule(),
+				},
+			},
+		},
+		{
+			name:   "missing rules are not found",
+			fields: notificationRuleFields(t),
+			args: args{
+				id: MustIDBase16(ruleThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrNotificationRuleNotFound,
+				},
+				rules: []*influxdb.NotificationRule{
+					newNotificationRule(ruleOneID, orgOneID, "crit", endpointOneID),
+					inactiveRule(),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteNotificationRule(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(notificationRulesOf(ctx, s, t), tt.wants.rules); diff != "" {
+				t.Errorf("notification rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// SentNotificationFields will include the IDGenerator, the number of notifications an
+// organization keeps, and the sent notifications to populate the store with.
+type SentNotificationFields struct {
+	IDGenerator       influxdb.IDGenerator
+	Limit             int
+	SentNotifications []*influxdb.SentNotification
+}
+
+type sentNotificationServiceF func(
+	init func(SentNotificationFields, *testing.T) (influxdb.SentNotificationService, func()),
+	t *testing.T,
+)
+
+// SentNotificationService tests all the service functions.
+func SentNotificationService(
+	init func(SentNotificationFields, *testing.T) (influxdb.SentNotificationService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   sentNotificationServiceF
+	}{
+		{
+			name: "AddSentNotification",
+			fn:   AddSentNotification,
+		},
+		{
+			name: "FindSentNotifications",
+			fn:   FindSentNotifications,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// newSentNotification returns a critical notification of the heartbeat check sent
+// the minutes after notificationRuleTime.
+func newSentNotification(id, orgID, ruleID string, minutes int) *influxdb.SentNotification {
+	return &influxdb.SentNotification{
+		ID:      MustIDBase16(id),
+		OrgID:   MustIDBase16(orgID),
+		RuleID:  MustIDBase16(ruleID),
+		CheckID: MustIDBase16(checkOneID),
+		Level:   influxdb.CheckLevelCrit,
+		SentAt:  notificationRuleTime.Add(time.Duration(minutes) * time.Minute),
+	}
+}
+
+// sentNotificationFields returns the fields of a store that keeps 3 notifications of
+// each organization, with notifications of the crit rule at minutes 0 and 2, of the
+// recovery rule at minute 1, and of the second organization at minute 0.
+func sentNotificationFields(t *testing.T) SentNotificationFields {
+	return SentNotificationFields{
+		IDGenerator: mock.NewIDGenerator(sentNotificationFiveID, t),
+		Limit:       3,
+		SentNotifications: []*influxdb.SentNotification{
+			newSentNotification(sentNotificationOneID, orgOneID, ruleOneID, 0),
+			newSentNotification(sentNotificationTwoID, orgOneID, ruleTwoID, 1),
+			newSentNotification(sentNotificationThreeID, orgOneID, ruleOneID, 2),
+			newSentNotification(sentNotificationFourID, orgTwoID, ruleThreeID, 0),
+		},
+	}
+}
+
+// AddSentNotification testing
+func AddSentNotification(
+	init func(SentNotificationFields, *testing.T) (influxdb.SentNotificationService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		notification *influxdb.SentNotification
+	}
+	type wants struct {
+		err error
+		// notifications are the notifications of the first organization afterwards.
+		notifications []*influxdb.SentNotification
+	}
+
+	added := newSentNotification(sentNotificationFiveID, orgOneID, ruleTwoID, 3)
+
+	tests := []struct {
+		name   string
+		fields SentNotificationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "add a notification",
+			fields: SentNotificationFields{
+				IDGenerator: mock.NewIDGenerator(sentNotificationFiveID, t),
+				Limit:       3,
+				SentNotifications: []*influxdb.SentNotification{
+					newSentNotification(sentNotificationOneID, orgOneID, ruleOneID, 0),
+				},
+			},
+			args: args{
+				notification: newSentNotification(sentNotificationFiveID, orgOneID, ruleTwoID, 3),
+			},
+			wants: wants{
+				notifications: []*influxdb.SentNotification{
+					added,
+					newSentNotification(sentNotificationOneID, orgOneID, ruleOneID, 0),
+				},
+			},
+		},
+		{
+			name:   "the oldest notifications beyond the limit of the organization are removed",
+			fields: sentNotificationFields(t),
+			args: args{
+				notification: newSentNotification(sentNotificationFiveID, orgOneID, ruleTwoID, 3),
+			},
+			wants: wants{
+				notifications: []*influxdb.SentNotification{
+					added,
+					newSentNotification(sentNotificationThreeID, orgOneID, ruleOneID, 2),
+					newSentNotification(sentNotificationTwoID, orgOneID, ruleTwoID, 1),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.AddSentNotification(ctx, tt.args.notification)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			ns, _, err := s.FindSentNotifications(ctx, influxdb.SentNotificationFilter{
+				OrgID: idPtr(MustIDBase16(orgOneID)),
+			})
+			if err != nil {
+				t.Fatalf("failed to retrieve sent notifications: %v", err)
+			}
+			if diff := cmp.Diff(ns, tt.wants.notifications); diff != "" {
+				t.Errorf("sent notifications are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindSentNotifications testing
+func FindSentNotifications(
+	init func(SentNotificationFields, *testing.T) (influxdb.SentNotificationService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.SentNotificationFilter
+		opts   []influxdb.FindOptions
+	}
+	type wants struct {
+		err           error
+		notifications []*influxdb.SentNotification
+	}
+
+	tests := []struct {
+		name   string
+		fields SentNotificationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the notifications of an organization most recent first",
+			fields: sentNotificationFields(t),
+			args: args{
+				filter: influxdb.SentNotificationFilter{OrgID: idPtr(MustIDBase16(orgOneID))},
+			},
+			wants: wants{
+				notifications: []*influxdb.SentNotification{
+					newSentNotification(sentNotificationThreeID, orgOneID, ruleOneID, 2),
+					newSentNotification(sentNotificationTwoID, orgOneID, ruleTwoID, 1),
+					newSentNotification(sentNotificationOneID, orgOneID, ruleOneID, 0),
+				},
+			},
+		},
+		{
+			name:   "find the notifications of a rule since a time",
+			fields: sentNotificationFields(t),
+			args: args{
+				filter: influxdb.SentNotificationFilter{
+					RuleID: idPtr(MustIDBase16(ruleOneID)),
+					Since:  notificationRuleTime.Add(time.Minute),
+				},
+			},
+			wants: wants{
+				notifications: []*influxdb.SentNotification{
+					newSentNotification(sentNotificationThreeID, orgOneID, ruleOneID, 2),
+				},
+			},
+		},
+		{
+			name:   "find a page of the notifications of every organization",
+			fields: sentNotificationFields(t),
+			args: args{
+				filter: influxdb.SentNotificationFilter{CheckID: idPtr(MustIDBase16(checkOneID))},
+				opts:   []influxdb.FindOptions{{Offset: 1, Limit: 2}},
+			},
+			wants: wants{
+				notifications: []*influxdb.SentNotification{
+					newSentNotification(sentNotificationTwoID, orgOneID, ruleTwoID, 1),
+					newSentNotification(sentNotificationOneID, orgOneID, ruleOneID, 0),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			ns, n, err := s.FindSentNotifications(ctx, tt.args.filter, tt.args.opts...)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if n != len(tt.wants.notifications) {
+				t.Errorf("expected %d sent notifications, got %d", len(tt.wants.notifications), n)
+			}
+			if diff := cmp.Diff(ns, tt.wants.notifications); diff != "" {
+				t.Errorf("sent notifications are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
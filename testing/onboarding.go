@@ -214,12 +214,208 @@ func Generate(
 
 }
 
+// Regenerate testing
+func Regenerate(
+	init func(OnboardingFields, *testing.T) (platform.OnboardingService, func()),
+	t *testing.T,
+) {
+	onboarding := platform.OnboardingRequest{
+		User:            "admin",
+		Org:             "org1",
+		Bucket:          "bucket1",
+		Password:        "password1",
+		RetentionPeriod: 24 * 7, // 1 week
+	}
+	tests := []struct {
+		name    string
+		request func(r *platform.OnboardingRequest)
+		errCode string
+	}{
+		{
+			name:    "same request gets the same results",
+			request: func(r *platform.OnboardingRequest) {},
+		},
+		{
+			name:    "same request with the token gets the same results",
+			request: func(r *platform.OnboardingRequest) { r.Token = oneToken },
+		},
+		{
+			name:    "wrong password",
+			request: func(r *platform.OnboardingRequest) { r.Password = "password2" },
+			errCode: platform.EConflict,
+		},
+		{
+			name:    "other org",
+			request: func(r *platform.OnboardingRequest) { r.Org = "org2" },
+			errCode: platform.EConflict,
+		},
+		{
+			name:    "other bucket",
+			request: func(r *platform.OnboardingRequest) { r.Bucket = "bucket2" },
+			errCode: platform.EConflict,
+		},
+		{
+			name:    "other retention period",
+			request: func(r *platform.OnboardingRequest) { r.RetentionPeriod = 24 },
+			errCode: platform.EConflict,
+		},
+		{
+			name:    "other token",
+			request: func(r *platform.OnboardingRequest) { r.Token = "other" },
+			errCode: platform.EConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(OnboardingFields{
+				IDGenerator: &loopIDGenerator{
+					s: []string{oneID, twoID, threeID, fourID},
+				},
+				TimeGenerator:  mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				TokenGenerator: mock.NewTokenGenerator(oneToken, nil),
+				IsOnboarding:   true,
+			}, t)
+			defer done()
+			ctx := context.Background()
+
+			req := onboarding
+			want, err := s.Generate(ctx, &req)
+			if err != nil {
+				t.Fatalf("failed to onboard: %v", err)
+			}
+
+			req = onboarding
+			tt.request(&req)
+			results, err := s.Generate(ctx, &req)
+			if tt.errCode != "" {
+				if code := platform.ErrorCode(err); code != tt.errCode {
+					t.Fatalf("expected error code to match '%s' got '%v'", tt.errCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to re-run onboarding: %v", err)
+			}
+			if diff := cmp.Diff(results, want); diff != "" {
+				t.Errorf("onboarding results are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// OnboardUser testing
+func OnboardUser(
+	init func(OnboardingFields, *testing.T) (platform.OnboardingService, func()),
+	t *testing.T,
+) {
+	s, done := init(OnboardingFields{
+		IDGenerator: &loopIDGenerator{
+			s: []string{oneID, twoID, threeID, fourID, fiveID, sixID, sevenID, eightID},
+		},
+		TimeGenerator:  mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+		TokenGenerator: mock.NewTokenGenerator(oneToken, nil),
+		IsOnboarding:   true,
+	}, t)
+	defer done()
+	ctx := context.Background()
+
+	if _, err := s.Generate(ctx, &platform.OnboardingRequest{
+		User:     "admin",
+		Org:      "org1",
+		Bucket:   "bucket1",
+		Password: "password1",
+	}); err != nil {
+		t.Fatalf("failed to onboard: %v", err)
+	}
+
+	if _, err := s.OnboardUser(ctx, &platform.OnboardingRequest{
+		User:   "user2",
+		Org:    "org2",
+		Bucket: "bucket2",
+	}); platform.ErrorCode(err) != platform.EEmptyValue {
+		t.Fatalf("expected a request without password to be invalid, got %v", err)
+	}
+
+	results, err := s.OnboardUser(ctx, &platform.OnboardingRequest{
+		User:            "user2",
+		Org:             "org2",
+		Bucket:          "bucket2",
+		Password:        "password2",
+		RetentionPeriod: 24,
+		Token:           twoToken,
+	})
+	if err != nil {
+		t.Fatalf("failed to onboard user: %v", err)
+	}
+	want := &platform.OnboardingResults{
+		User: &platform.User{
+			ID:   MustIDBase16(fiveID),
+			Name: "user2",
+		},
+		Org: &platform.Organization{
+			ID:   MustIDBase16(sixID),
+			Name: "org2",
+			CRUDLog: platform.CRUDLog{
+				CreatedAt: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+				UpdatedAt: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+			},
+		},
+		Bucket: &platform.Bucket{
+			ID:              MustIDBase16(sevenID),
+			Name:            "bucket2",
+			OrgID:           MustIDBase16(sixID),
+			RetentionPeriod: time.Hour * 24,
+			CRUDLog: platform.CRUDLog{
+				CreatedAt: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+				UpdatedAt: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+			},
+		},
+		Auth: &platform.Authorization{
+			ID:          MustIDBase16(eightID),
+			Token:       twoToken,
+			Status:      platform.Active,
+			UserID:      MustIDBase16(fiveID),
+			Description: "user2's Token",
+			OrgID:       MustIDBase16(sixID),
+			Permissions: platform.OnboardUserPermissions(MustIDBase16(sixID), MustIDBase16(fiveID)),
+		},
+	}
+	if diff := cmp.Diff(results, want); diff != "" {
+		t.Errorf("onboarding results are different -got/+want\ndiff %s", diff)
+	}
+	if err := s.ComparePassword(ctx, "user2", "password2"); err != nil {
+		t.Errorf("onboarding set password is wrong")
+	}
+
+	isOnboarding, err := s.IsOnboarding(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isOnboarding {
+		t.Errorf("expected onboarding to remain completed")
+	}
+
+	if _, err := s.OnboardUser(ctx, &platform.OnboardingRequest{
+		User:     "user2",
+		Org:      "org3",
+		Bucket:   "bucket3",
+		Password: "password3",
+	}); platform.ErrorCode(err) != platform.EConflict {
+		t.Errorf("expected an existing user name to be a conflict, got %v", err)
+	}
+}
+
 const (
 	oneID    = "020f755c3c082000"
 	twoID    = "020f755c3c082001"
 	threeID  = "020f755c3c082002"
 	fourID   = "020f755c3c082003"
+	fiveID   = "020f755c3c082004"
+	sixID    = "020f755c3c082005"
+	sevenID  = "020f755c3c082006"
+	eightID  = "020f755c3c082007"
 	oneToken = "020f755c3c082008"
+	twoToken = "020f755c3c082009"
 )
 
 type loopIDGenerator struct {
@@ -13,8 +13,9 @@ import (
 )
 
 const (
-	orgOneID = "020f755c3c083000"
-	orgTwoID = "020f755c3c083001"
+	orgOneID   = "020f755c3c083000"
+	orgTwoID   = "020f755c3c083001"
+	orgThreeID = "020f755c3c083002"
 )
 
 var organizationCmpOptions = cmp.Options{
@@ -807,6 +808,7 @@ func UpdateOrganization(
 		id          platform.ID
 		name        *string
 		description *string
+		timezone    *string
 	}
 	type wants struct {
 		err          error
@@ -980,6 +982,32 @@ func UpdateOrganization(
 				},
 			},
 		},
+		{
+			name: "update timezone",
+			fields: OrganizationFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)},
+				Organizations: []*platform.Organization{
+					{
+						ID:   MustIDBase16(orgOneID),
+						Name: "organization1",
+					},
+				},
+			},
+			args: args{
+				id:       MustIDBase16(orgOneID),
+				timezone: strPtr("Europe/Berlin"),
+			},
+			wants: wants{
+				organization: &platform.Organization{
+					ID:       MustIDBase16(orgOneID),
+					Name:     "organization1",
+					Timezone: "Europe/Berlin",
+					CRUDLog: platform.CRUDLog{
+						UpdatedAt: time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC),
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -991,6 +1019,7 @@ func UpdateOrganization(
 			upd := platform.OrganizationUpdate{}
 			upd.Name = tt.args.name
 			upd.Description = tt.args.description
+			upd.Timezone = tt.args.timezone
 
 			organization, err := s.UpdateOrganization(ctx, tt.args.id, upd)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
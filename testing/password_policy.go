@@ -0,0 +1,371 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+// PasswordPolicyFields will include the TimeGenerator, the password policy of the
+// service, the users to populate the store with, and the passwords that are set for
+// the users by name, oldest first.
+type PasswordPolicyFields struct {
+	TimeGenerator  influxdb.TimeGenerator
+	PasswordPolicy *influxdb.PasswordPolicy
+	Users          []*influxdb.User
+	Passwords      map[string][]string
+}
+
+type passwordPolicyServiceF func(
+	init func(PasswordPolicyFields, *testing.T) (influxdb.PasswordsService, func()),
+	t *testing.T,
+)
+
+// PasswordPolicyService tests that passwords are set and compared according to the
+// password policy of the service.
+func PasswordPolicyService(
+	init func(PasswordPolicyFields, *testing.T) (influxdb.PasswordsService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   passwordPolicyServiceF
+	}{
+		{
+			name: "SetPasswordPolicy",
+			fn:   SetPasswordPolicy,
+		},
+		{
+			name: "ComparePasswordPolicy",
+			fn:   ComparePasswordPolicy,
+		},
+		{
+			name: "CompareAndSetPasswordPolicy",
+			fn:   CompareAndSetPasswordPolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var passwordPolicyTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// passwordPolicyFields returns the fields of a store with ada, who set the passwords
+// in order, and a policy that keeps the last two passwords and expires passwords
+// after a day.
+func passwordPolicyFields(passwords ...string) PasswordPolicyFields {
+	return PasswordPolicyFields{
+		TimeGenerator: mock.TimeGenerator{FakeValue: passwordPolicyTime},
+		PasswordPolicy: &influxdb.PasswordPolicy{
+			MinLength:       8,
+			RequiredClasses: []influxdb.PasswordCharacterClass{influxdb.PasswordDigit},
+			DenyList:        []string{"password1"},
+			HistorySize:     2,
+			MaxAge:          24 * time.Hour,
+		},
+		Users: []*influxdb.User{
+			{ID: MustIDBase16(userOneID), Name: "ada"},
+		},
+		Passwords: map[string][]string{
+			"ada": passwords,
+		},
+	}
+}
+
+// passwordViolationRules returns the rules of the password policy err violates.
+func passwordViolationRules(err error) []string {
+	var rs []string
+	for _, v := range influxdb.PasswordViolations(err) {
+		rs = append(rs, v.Rule)
+	}
+	return rs
+}
+
+// SetPasswordPolicy testing
+func SetPasswordPolicy(
+	init func(PasswordPolicyFields, *testing.T) (influxdb.PasswordsService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		password string
+	}
+	type wants struct {
+		err   error
+		rules []string
+	}
+
+	tests := []struct {
+		name   string
+		fields PasswordPolicyFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "set a password of the policy",
+			fields: passwordPolicyFields(),
+			args: args{
+				password: "first-password1",
+			},
+		},
+		{
+			name:   "passwords require a character of each required class",
+			fields: passwordPolicyFields(),
+			args: args{
+				password: "nodigits",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "passwords must contain at least one digit character",
+				},
+				rules: []string{influxdb.PasswordRuleCharacterClass},
+			},
+		},
+		{
+			name:   "denied passwords are rejected regardless of case",
+			fields: passwordPolicyFields(),
+			args: args{
+				password: "PASSWORD1",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "password is too common",
+				},
+				rules: []string{influxdb.PasswordRuleDenyList},
+			},
+		},
+		{
+			name:   "the current password may not be set again",
+			fields: passwordPolicyFields("first-password1", "second-password2"),
+			args: args{
+				password: "second-password2",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "passwords must not be one of the last 2 passwords",
+				},
+				rules: []string{influxdb.PasswordRuleHistory},
+			},
+		},
+		{
+			name:   "recent passwords may not be set again",
+			fields: passwordPolicyFields("first-password1", "second-password2"),
+			args: args{
+				password: "first-password1",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "passwords must not be one of the last 2 passwords",
+				},
+				rules: []string{influxdb.PasswordRuleHistory},
+			},
+		},
+		{
+			name:   "passwords older than the history may be set again",
+			fields: passwordPolicyFields("first-password1", "second-password2", "third-password3"),
+			args: args{
+				password: "first-password1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.SetPassword(ctx, "ada", tt.args.password)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(passwordViolationRules(err), tt.wants.rules); diff != "" {
+				t.Errorf("violated rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// ComparePasswordPolicy testing
+func ComparePasswordPolicy(
+	init func(PasswordPolicyFields, *testing.T) (influxdb.PasswordsService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		password string
+		// elapsed is the time that passes after the passwords are set.
+		elapsed time.Duration
+	}
+	type wants struct {
+		err   error
+		rules []string
+	}
+
+	tests := []struct {
+		name   string
+		fields PasswordPolicyFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "compare the current password",
+			fields: passwordPolicyFields("first-password1"),
+			args: args{
+				password: "first-password1",
+			},
+		},
+		{
+			name:   "incorrect passwords are incorrect",
+			fields: passwordPolicyFields("first-password1"),
+			args: args{
+				password: "wrong-password1",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EForbidden,
+					Msg:  "your username or password is incorrect",
+				},
+			},
+		},
+		{
+			name:   "expired passwords are reported for the correct password",
+			fields: passwordPolicyFields("first-password1"),
+			args: args{
+				password: "first-password1",
+				elapsed:  25 * time.Hour,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EForbidden,
+					Msg:  "password has expired; passwords must be changed every 24h0m0s",
+				},
+				rules: []string{influxdb.PasswordRuleMaxAge},
+			},
+		},
+		{
+			name:   "expired passwords are not reported for incorrect passwords",
+			fields: passwordPolicyFields("first-password1"),
+			args: args{
+				password: "wrong-password1",
+				elapsed:  25 * time.Hour,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EForbidden,
+					Msg:  "your username or password is incorrect",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := &mock.TimeGenerator{FakeValue: passwordPolicyTime}
+			tt.fields.TimeGenerator = tg
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+			tg.FakeValue = passwordPolicyTime.Add(tt.args.elapsed)
+
+			err := s.ComparePassword(ctx, "ada", tt.args.password)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(passwordViolationRules(err), tt.wants.rules); diff != "" {
+				t.Errorf("violated rules are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// CompareAndSetPasswordPolicy testing
+func CompareAndSetPasswordPolicy(
+	init func(PasswordPolicyFields, *testing.T) (influxdb.PasswordsService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		old, new string
+		// elapsed is the time that passes after the passwords are set.
+		elapsed time.Duration
+	}
+	type wants struct {
+		err error
+		// password is the password of ada afterwards.
+		password string
+	}
+
+	tests := []struct {
+		name   string
+		fields PasswordPolicyFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "change the password",
+			fields: passwordPolicyFields("first-password1"),
+			args: args{
+				old: "first-password1",
+				new: "second-password2",
+			},
+			wants: wants{
+				password: "second-password2",
+			},
+		},
+		{
+			name:   "expired passwords may be changed",
+			fields: passwordPolicyFields("first-password1"),
+			args: args{
+				old:     "first-password1",
+				new:     "second-password2",
+				elapsed: 25 * time.Hour,
+			},
+			wants: wants{
+				password: "second-password2",
+			},
+		},
+		{
+			name:   "new passwords follow the policy",
+			fields: passwordPolicyFields("first-password1"),
+			args: args{
+				old: "first-password1",
+				new: "first-password1",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "passwords must not be one of the last 2 passwords",
+				},
+				password: "first-password1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := &mock.TimeGenerator{FakeValue: passwordPolicyTime}
+			tt.fields.TimeGenerator = tg
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+			tg.FakeValue = passwordPolicyTime.Add(tt.args.elapsed)
+
+			err := s.CompareAndSetPassword(ctx, "ada", tt.args.old, tt.args.new)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			// Compare an unchanged password before it expires.
+			if err != nil {
+				tg.FakeValue = passwordPolicyTime
+			}
+			if err := s.ComparePassword(ctx, "ada", tt.wants.password); err != nil {
+				t.Errorf("expected the password of the user to be %q: %v", tt.wants.password, err)
+			}
+		})
+	}
+}
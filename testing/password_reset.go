@@ -0,0 +1,264 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+// PasswordResetFields will include the TimeGenerator, TokenGenerator, and the users,
+// their passwords by name, and the password resets to populate the store with.
+type PasswordResetFields struct {
+	TimeGenerator  influxdb.TimeGenerator
+	TokenGenerator influxdb.TokenGenerator
+	Users          []*influxdb.User
+	Passwords      map[string]string
+	PasswordResets []*influxdb.PasswordReset
+}
+
+// PasswordResetServices are the password reset service and the passwords service that
+// holds the passwords of the users.
+type PasswordResetServices interface {
+	influxdb.PasswordResetService
+	influxdb.PasswordsService
+}
+
+type passwordResetServiceF func(
+	init func(PasswordResetFields, *testing.T) (PasswordResetServices, func()),
+	t *testing.T,
+)
+
+// PasswordResetService tests all the service functions.
+func PasswordResetService(
+	init func(PasswordResetFields, *testing.T) (PasswordResetServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   passwordResetServiceF
+	}{
+		{
+			name: "CreatePasswordReset",
+			fn:   CreatePasswordReset,
+		},
+		{
+			name: "ResetPassword",
+			fn:   ResetPassword,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var passwordResetTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func newPasswordReset(token string) *influxdb.PasswordReset {
+	return &influxdb.PasswordReset{
+		UserID:    MustIDBase16(userOneID),
+		Token:     token,
+		ExpiresAt: passwordResetTime.Add(influxdb.DefaultPasswordResetTTL),
+		CreatedAt: passwordResetTime,
+	}
+}
+
+// passwordResetFields returns the fields of a store with ada, who forgot their
+// password and has a password reset.
+func passwordResetFields() PasswordResetFields {
+	return PasswordResetFields{
+		TimeGenerator:  mock.TimeGenerator{FakeValue: passwordResetTime},
+		TokenGenerator: mock.NewTokenGenerator("token2", nil),
+		Users: []*influxdb.User{
+			{ID: MustIDBase16(userOneID), Name: "ada"},
+		},
+		Passwords: map[string]string{
+			"ada": "forgotten1",
+		},
+		PasswordResets: []*influxdb.PasswordReset{
+			newPasswordReset("token1"),
+		},
+	}
+}
+
+// CreatePasswordReset testing
+func CreatePasswordReset(
+	init func(PasswordResetFields, *testing.T) (PasswordResetServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		userID influxdb.ID
+	}
+	type wants struct {
+		err           error
+		passwordReset *influxdb.PasswordReset
+		// replaced is whether the password reset the user had is deleted.
+		replaced bool
+	}
+
+	tests := []struct {
+		name   string
+		fields PasswordResetFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "create a password reset that replaces the one of the user",
+			fields: passwordResetFields(),
+			args: args{
+				userID: MustIDBase16(userOneID),
+			},
+			wants: wants{
+				passwordReset: newPasswordReset("token2"),
+				replaced:      true,
+			},
+		},
+		{
+			name:   "password resets of missing users are not found",
+			fields: passwordResetFields(),
+			args: args{
+				userID: MustIDBase16(userTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "user not found",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			r, err := s.CreatePasswordReset(ctx, tt.args.userID)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(r, tt.wants.passwordReset); diff != "" {
+				t.Errorf("password reset is different -got/+want\ndiff %s", diff)
+			}
+
+			err = s.ResetPassword(ctx, "token1", "remembered1")
+			if replaced := influxdb.ErrorCode(err) == influxdb.ENotFound; replaced != tt.wants.replaced {
+				t.Errorf("expected the password reset of the user to be replaced %t, got %v", tt.wants.replaced, err)
+			}
+		})
+	}
+}
+
+// ResetPassword testing
+func ResetPassword(
+	init func(PasswordResetFields, *testing.T) (PasswordResetServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		token    string
+		password string
+		// elapsed is the time that passes after the store is populated.
+		elapsed time.Duration
+	}
+	type wants struct {
+		err error
+		// password is the password of ada afterwards.
+		password string
+		// used is whether the password reset is used up.
+		used bool
+	}
+
+	tests := []struct {
+		name   string
+		fields PasswordResetFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "reset the password of a user once",
+			fields: passwordResetFields(),
+			args: args{
+				token:    "token1",
+				password: "remembered1",
+			},
+			wants: wants{
+				password: "remembered1",
+				used:     true,
+			},
+		},
+		{
+			name:   "passwords of the password policy are required",
+			fields: passwordResetFields(),
+			args: args{
+				token:    "token1",
+				password: "short",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "passwords must be at least 8 characters long",
+				},
+				password: "forgotten1",
+			},
+		},
+		{
+			name:   "expired password resets are rejected",
+			fields: passwordResetFields(),
+			args: args{
+				token:    "token1",
+				password: "remembered1",
+				elapsed:  influxdb.DefaultPasswordResetTTL,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EForbidden,
+					Msg:  "password reset has expired",
+				},
+				password: "forgotten1",
+			},
+		},
+		{
+			name:   "password resets of unknown tokens are not found",
+			fields: passwordResetFields(),
+			args: args{
+				token:    "token2",
+				password: "remembered1",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrPasswordResetNotFound,
+				},
+				password: "forgotten1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := &mock.TimeGenerator{FakeValue: passwordResetTime}
+			tt.fields.TimeGenerator = tg
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+			tg.FakeValue = passwordResetTime.Add(tt.args.elapsed)
+
+			err := s.ResetPassword(ctx, tt.args.token, tt.args.password)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if err := s.ComparePassword(ctx, "ada", tt.wants.password); err != nil {
+				t.Errorf("expected the password of the user to be %q: %v", tt.wants.password, err)
+			}
+
+			tg.FakeValue = passwordResetTime
+			err = s.ResetPassword(ctx, "token1", "remembered2")
+			if used := influxdb.ErrorCode(err) == influxdb.ENotFound; used != tt.wants.used {
+				t.Errorf("expected the password reset to be used up %t, got %v", tt.wants.used, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	queryHistoryOneID   = "020f755c3c082000"
+	queryHistoryTwoID   = "020f755c3c082001"
+	queryHistoryThreeID = "020f755c3c082002"
+	queryHistoryFourID  = "020f755c3c082003"
+	queryHistoryFiveID  = "020f755c3c082004"
+)
+
+// QueryHistoryFields will include the IDGenerator, the number of entries an
+// organization keeps, and the entries to populate the store with.
+type QueryHistoryFields struct {
+	IDGenerator  influxdb.IDGenerator
+	Limit        int
+	QueryHistory []*influxdb.QueryHistoryEntry
+}
+
+type queryHistoryServiceF func(
+	init func(QueryHistoryFields, *testing.T) (influxdb.QueryHistoryService, func()),
+	t *testing.T,
+)
+
+// QueryHistoryService tests all the service functions.
+func QueryHistoryService(
+	init func(QueryHistoryFields, *testing.T) (influxdb.QueryHistoryService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   queryHistoryServiceF
+	}{
+		{
+			name: "AddQueryHistoryEntry",
+			fn:   AddQueryHistoryEntry,
+		},
+		{
+			name: "FindQueryHistory",
+			fn:   FindQueryHistory,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var queryHistoryTime = time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+// newQueryHistoryEntry returns an entry of a query that completed min minutes
+// after the queryHistoryTime and ran for d.
+func newQueryHistoryEntry(id, orgID string, min int, d time.Duration, outcome string) *influxdb.QueryHistoryEntry {
+	e := &influxdb.QueryHistoryEntry{
+		OrganizationID: MustIDBase16(orgID),
+		Query:          `from(bucket: "telegraf")`,
+		Outcome:        outcome,
+		CompletedAt:    queryHistoryTime.Add(time.Duration(min) * time.Minute),
+		Duration:       d,
+	}
+	if id != "" {
+		e.ID = MustIDBase16(id)
+	}
+	return e
+}
+
+// AddQueryHistoryEntry testing
+func AddQueryHistoryEntry(
+	init func(QueryHistoryFields, *testing.T) (influxdb.QueryHistoryService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		entry *influxdb.QueryHistoryEntry
+	}
+	type wants struct {
+		queryHistory []*influxdb.QueryHistoryEntry
+	}
+
+	tests := []struct {
+		name   string
+		fields QueryHistoryFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "add an entry to the history of an organization",
+			fields: QueryHistoryFields{
+				IDGenerator: mock.NewIDGenerator(queryHistoryTwoID, t),
+				Limit:       3,
+				QueryHistory: []*influxdb.QueryHistoryEntry{
+					newQueryHistoryEntry(queryHistoryOneID, orgOneID, 0, time.Second, influxdb.QuerySucceeded),
+				},
+			},
+			args: args{
+				entry: newQueryHistoryEntry("", orgOneID, 1, 5*time.Second, influxdb.QueryFailed),
+			},
+			wants: wants{
+				queryHistory: []*influxdb.QueryHistoryEntry{
+					newQueryHistoryEntry(queryHistoryTwoID, orgOneID, 1, 5*time.Second, influxdb.QueryFailed),
+					newQueryHistoryEntry(queryHistoryOneID, orgOneID, 0, time.Second, influxdb.QuerySucceeded),
+				},
+			},
+		},
+		{
+			name: "the oldest entries beyond the limit of an organization are removed",
+			fields: QueryHistoryFields{
+				IDGenerator: mock.NewIDGenerator(queryHistoryFiveID, t),
+				Limit:       3,
+				QueryHistory: []*influxdb.QueryHistoryEntry{
+					newQueryHistoryEntry(queryHistoryOneID, orgOneID, 0, time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryTwoID, orgOneID, 1, 5*time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryThreeID, orgOneID, 2, 2*time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryFourID, orgTwoID, 0, time.Hour, influxdb.QuerySucceeded),
+				},
+			},
+			args: args{
+				entry: newQueryHistoryEntry("", orgOneID, 3, 4*time.Second, influxdb.QueryFailed),
+			},
+			wants: wants{
+				queryHistory: []*influxdb.QueryHistoryEntry{
+					newQueryHistoryEntry(queryHistoryFiveID, orgOneID, 3, 4*time.Second, influxdb.QueryFailed),
+					newQueryHistoryEntry(queryHistoryThreeID, orgOneID, 2, 2*time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryTwoID, orgOneID, 1, 5*time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryFourID, orgTwoID, 0, time.Hour, influxdb.QuerySucceeded),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			if err := s.AddQueryHistoryEntry(ctx, tt.args.entry); err != nil {
+				t.Fatalf("failed to add the query history entry: %v", err)
+			}
+
+			es, err := s.FindQueryHistory(ctx, influxdb.QueryHistoryFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve the query history: %v", err)
+			}
+			if diff := cmp.Diff(es, tt.wants.queryHistory); diff != "" {
+				t.Errorf("query history is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindQueryHistory testing
+func FindQueryHistory(
+	init func(QueryHistoryFields, *testing.T) (influxdb.QueryHistoryService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.QueryHistoryFilter
+		opts   []influxdb.FindOptions
+	}
+	type wants struct {
+		queryHistory []*influxdb.QueryHistoryEntry
+	}
+
+	fields := func() QueryHistoryFields {
+		return QueryHistoryFields{
+			Limit: 10,
+			QueryHistory: []*influxdb.QueryHistoryEntry{
+				newQueryHistoryEntry(queryHistoryOneID, orgOneID, 0, time.Second, influxdb.QuerySucceeded),
+				newQueryHistoryEntry(queryHistoryTwoID, orgOneID, 1, 5*time.Second, influxdb.QuerySucceeded),
+				newQueryHistoryEntry(queryHistoryThreeID, orgOneID, 2, 2*time.Second, influxdb.QuerySucceeded),
+				newQueryHistoryEntry(queryHistoryFourID, orgOneID, 3, 4*time.Second, influxdb.QueryFailed),
+				newQueryHistoryEntry(queryHistoryFiveID, orgTwoID, 0, time.Hour, influxdb.QuerySucceeded),
+			},
+		}
+	}
+	succeeded := influxdb.QuerySucceeded
+
+	tests := []struct {
+		name   string
+		fields QueryHistoryFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the history of an organization most recent first",
+			fields: fields(),
+			args: args{
+				filter: influxdb.QueryHistoryFilter{
+					OrganizationID: idPtr(MustIDBase16(orgOneID)),
+				},
+			},
+			wants: wants{
+				queryHistory: []*influxdb.QueryHistoryEntry{
+					newQueryHistoryEntry(queryHistoryFourID, orgOneID, 3, 4*time.Second, influxdb.QueryFailed),
+					newQueryHistoryEntry(queryHistoryThreeID, orgOneID, 2, 2*time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryTwoID, orgOneID, 1, 5*time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryOneID, orgOneID, 0, time.Second, influxdb.QuerySucceeded),
+				},
+			},
+		},
+		{
+			name:   "find the slowest queries of an organization",
+			fields: fields(),
+			args: args{
+				filter: influxdb.QueryHistoryFilter{
+					OrganizationID: idPtr(MustIDBase16(orgOneID)),
+				},
+				opts: []influxdb.FindOptions{
+					{SortBy: "duration", Limit: 2},
+				},
+			},
+			wants: wants{
+				queryHistory: []*influxdb.QueryHistoryEntry{
+					newQueryHistoryEntry(queryHistoryTwoID, orgOneID, 1, 5*time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryFourID, orgOneID, 3, 4*time.Second, influxdb.QueryFailed),
+				},
+			},
+		},
+		{
+			name:   "find the successful queries slower than a minimum duration",
+			fields: fields(),
+			args: args{
+				filter: influxdb.QueryHistoryFilter{
+					OrganizationID: idPtr(MustIDBase16(orgOneID)),
+					Outcome:        &succeeded,
+					MinDuration:    3 * time.Second,
+				},
+			},
+			wants: wants{
+				queryHistory: []*influxdb.QueryHistoryEntry{
+					newQueryHistoryEntry(queryHistoryTwoID, orgOneID, 1, 5*time.Second, influxdb.QuerySucceeded),
+				},
+			},
+		},
+		{
+			name:   "page through the history",
+			fields: fields(),
+			args: args{
+				filter: influxdb.QueryHistoryFilter{
+					OrganizationID: idPtr(MustIDBase16(orgOneID)),
+				},
+				opts: []influxdb.FindOptions{
+					{Offset: 1, Limit: 2},
+				},
+			},
+			wants: wants{
+				queryHistory: []*influxdb.QueryHistoryEntry{
+					newQueryHistoryEntry(queryHistoryThreeID, orgOneID, 2, 2*time.Second, influxdb.QuerySucceeded),
+					newQueryHistoryEntry(queryHistoryTwoID, orgOneID, 1, 5*time.Second, influxdb.QuerySucceeded),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			es, err := s.FindQueryHistory(ctx, tt.args.filter, tt.args.opts...)
+			if err != nil {
+				t.Fatalf("failed to retrieve the query history: %v", err)
+			}
+			if diff := cmp.Diff(es, tt.wants.queryHistory); diff != "" {
+				t.Errorf("query history is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,442 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	queryViewOneID   = "020f755c3c082000"
+	queryViewTwoID   = "020f755c3c082001"
+	queryViewThreeID = "020f755c3c082002"
+)
+
+// QueryViewFields will include the IDGenerator, and the organizations and query
+// views to populate the store with.
+type QueryViewFields struct {
+	IDGenerator   influxdb.IDGenerator
+	TimeGenerator influxdb.TimeGenerator
+	Organizations []*influxdb.Organization
+	QueryViews    []*influxdb.QueryView
+}
+
+type queryViewServiceF func(
+	init func(QueryViewFields, *testing.T) (influxdb.QueryViewService, func()),
+	t *testing.T,
+)
+
+// QueryViewService tests all the service functions.
+func QueryViewService(
+	init func(QueryViewFields, *testing.T) (influxdb.QueryViewService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   queryViewServiceF
+	}{
+		{
+			name: "CreateQueryView",
+			fn:   CreateQueryView,
+		},
+		{
+			name: "FindQueryViewByID",
+			fn:   FindQueryViewByID,
+		},
+		{
+			name: "FindQueryViews",
+			fn:   FindQueryViews,
+		},
+		{
+			name: "DeleteQueryView",
+			fn:   DeleteQueryView,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var queryViewTime = time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+
+func newQueryView(id, orgID, name string, ttl int64) *influxdb.QueryView {
+	qv := &influxdb.QueryView{
+		OrganizationID: MustIDBase16(orgID),
+		Name:           name,
+		Query:          `from(bucket: "telegraf") |> range(start: -1d)`,
+		TTLSeconds:     ttl,
+	}
+	if id != "" {
+		qv.ID = MustIDBase16(id)
+		qv.CreatedAt = queryViewTime
+		qv.ExpiresAt = queryViewTime.Add(qv.TTL())
+	}
+	return qv
+}
+
+// CreateQueryView testing
+func CreateQueryView(
+	init func(QueryViewFields, *testing.T) (influxdb.QueryViewService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		queryView *influxdb.QueryView
+	}
+	type wants struct {
+		err        error
+		queryViews []*influxdb.QueryView
+	}
+
+	tests := []struct {
+		name   string
+		fields QueryViewFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "create a query view that expires after its ttl",
+			fields: QueryViewFields{
+				IDGenerator:   mock.NewIDGenerator(queryViewTwoID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				QueryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+				},
+			},
+			args: args{
+				queryView: newQueryView("", orgOneID, "cpu_daily", 600),
+			},
+			wants: wants{
+				queryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+					newQueryView(queryViewTwoID, orgOneID, "cpu_daily", 600),
+				},
+			},
+		},
+		{
+			name: "names are unique within an organization",
+			fields: QueryViewFields{
+				IDGenerator:   mock.NewIDGenerator(queryViewTwoID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				QueryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_daily", 0),
+				},
+			},
+			args: args{
+				queryView: newQueryView("", orgOneID, "cpu_daily", 600),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "query view with name cpu_daily already exists",
+				},
+				queryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_daily", 0),
+				},
+			},
+		},
+		{
+			name: "views may not live longer than the maximum ttl",
+			fields: QueryViewFields{
+				IDGenerator:   mock.NewIDGenerator(queryViewOneID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+			},
+			args: args{
+				queryView: newQueryView("", orgOneID, "long", int64(influxdb.MaxQueryViewTTL/time.Second)+1),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("ttlSeconds must be between 0 and %d", int64(influxdb.MaxQueryViewTTL/time.Second)),
+				},
+				queryViews: []*influxdb.QueryView{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateQueryView(ctx, tt.args.queryView)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			qvs, err := s.FindQueryViews(ctx, influxdb.QueryViewFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve query views: %v", err)
+			}
+			if diff := cmp.Diff(qvs, tt.wants.queryViews); diff != "" {
+				t.Errorf("query views are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindQueryViewByID testing
+func FindQueryViewByID(
+	init func(QueryViewFields, *testing.T) (influxdb.QueryViewService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err       error
+		queryView *influxdb.QueryView
+	}
+
+	tests := []struct {
+		name   string
+		fields QueryViewFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "find a query view by id",
+			fields: QueryViewFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				QueryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+					newQueryView(queryViewTwoID, orgOneID, "cpu_daily", 600),
+				},
+			},
+			args: args{
+				id: MustIDBase16(queryViewTwoID),
+			},
+			wants: wants{
+				queryView: newQueryView(queryViewTwoID, orgOneID, "cpu_daily", 600),
+			},
+		},
+		{
+			name: "missing query views are not found",
+			fields: QueryViewFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				QueryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+				},
+			},
+			args: args{
+				id: MustIDBase16(queryViewThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrQueryViewNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			qv, err := s.FindQueryViewByID(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(qv, tt.wants.queryView); diff != "" {
+				t.Errorf("query view is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindQueryViews testing
+func FindQueryViews(
+	init func(QueryViewFields, *testing.T) (influxdb.QueryViewService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.QueryViewFilter
+	}
+	type wants struct {
+		queryViews []*influxdb.QueryView
+	}
+
+	tests := []struct {
+		name   string
+		fields QueryViewFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "find the query views of an organization",
+			fields: QueryViewFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+					{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+				},
+				QueryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+					newQueryView(queryViewTwoID, orgTwoID, "cpu_hourly", 0),
+					newQueryView(queryViewThreeID, orgOneID, "cpu_daily", 600),
+				},
+			},
+			args: args{
+				filter: influxdb.QueryViewFilter{
+					OrganizationID: idPtr(MustIDBase16(orgOneID)),
+				},
+			},
+			wants: wants{
+				queryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+					newQueryView(queryViewThreeID, orgOneID, "cpu_daily", 600),
+				},
+			},
+		},
+		{
+			name: "find a query view of an organization by name",
+			fields: QueryViewFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+					{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+				},
+				QueryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+					newQueryView(queryViewTwoID, orgTwoID, "cpu_daily", 0),
+					newQueryView(queryViewThreeID, orgOneID, "cpu_daily", 600),
+				},
+			},
+			args: args{
+				filter: influxdb.QueryViewFilter{
+					OrganizationID: idPtr(MustIDBase16(orgOneID)),
+					Name:           strPtr("cpu_daily"),
+				},
+			},
+			wants: wants{
+				queryViews: []*influxdb.QueryView{
+					newQueryView(queryViewThreeID, orgOneID, "cpu_daily", 600),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			qvs, err := s.FindQueryViews(ctx, tt.args.filter)
+			if err != nil {
+				t.Fatalf("failed to retrieve query views: %v", err)
+			}
+			if diff := cmp.Diff(qvs, tt.wants.queryViews); diff != "" {
+				t.Errorf("query views are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteQueryView testing
+func DeleteQueryView(
+	init func(QueryViewFields, *testing.T) (influxdb.QueryViewService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err        error
+		queryViews []*influxdb.QueryView
+	}
+
+	tests := []struct {
+		name   string
+		fields QueryViewFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "delete a query view",
+			fields: QueryViewFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				QueryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+					newQueryView(queryViewTwoID, orgOneID, "cpu_daily", 600),
+				},
+			},
+			args: args{
+				id: MustIDBase16(queryViewOneID),
+			},
+			wants: wants{
+				queryViews: []*influxdb.QueryView{
+					newQueryView(queryViewTwoID, orgOneID, "cpu_daily", 600),
+				},
+			},
+		},
+		{
+			name: "deletes of missing query views are not found",
+			fields: QueryViewFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: queryViewTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				QueryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+				},
+			},
+			args: args{
+				id: MustIDBase16(queryViewTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrQueryViewNotFound,
+				},
+				queryViews: []*influxdb.QueryView{
+					newQueryView(queryViewOneID, orgOneID, "cpu_hourly", 0),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteQueryView(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			qvs, err := s.FindQueryViews(ctx, influxdb.QueryViewFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve query views: %v", err)
+			}
+			if diff := cmp.Diff(qvs, tt.wants.queryViews); diff != "" {
+				t.Errorf("query views are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,651 @@
+package testing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const revisionCellOneID = "020f755c3c087000"
+
+// RevisionFields will include the IDGenerator, TimeGenerator, and the users,
+// organizations, authorizations and dashboards to populate the store with.
+type RevisionFields struct {
+	IDGenerator    influxdb.IDGenerator
+	TimeGenerator  influxdb.TimeGenerator
+	Users          []*influxdb.User
+	Organizations  []*influxdb.Organization
+	Authorizations []*influxdb.Authorization
+	Dashboards     []*influxdb.Dashboard
+}
+
+// DashboardRevisionServices are the revision service and the dashboard service whose
+// changes it records.
+type DashboardRevisionServices interface {
+	influxdb.RevisionService
+	influxdb.DashboardService
+}
+
+// TaskRevisionServices are the revision service and the task service whose changes it
+// records.
+type TaskRevisionServices interface {
+	influxdb.RevisionService
+	influxdb.TaskService
+}
+
+type dashboardRevisionServiceF func(
+	init func(RevisionFields, *testing.T) (DashboardRevisionServices, func()),
+	t *testing.T,
+)
+
+type taskRevisionServiceF func(
+	init func(RevisionFields, *testing.T) (TaskRevisionServices, func()),
+	t *testing.T,
+)
+
+// DashboardRevisionService tests recording, finding and restoring the revisions of
+// dashboards.
+func DashboardRevisionService(
+	init func(RevisionFields, *testing.T) (DashboardRevisionServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   dashboardRevisionServiceF
+	}{
+		{
+			name: "FindDashboardRevisions",
+			fn:   FindDashboardRevisions,
+		},
+		{
+			name: "FindDashboardRevision",
+			fn:   FindDashboardRevision,
+		},
+		{
+			name: "RestoreDashboardRevision",
+			fn:   RestoreDashboardRevision,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// TaskRevisionService tests recording, finding and restoring the revisions of tasks.
+func TaskRevisionService(
+	init func(RevisionFields, *testing.T) (TaskRevisionServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   taskRevisionServiceF
+	}{
+		{
+			name: "FindTaskRevisions",
+			fn:   FindTaskRevisions,
+		},
+		{
+			name: "RestoreTaskRevision",
+			fn:   RestoreTaskRevision,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// revisionFields returns the fields of a store with the user that makes the changes,
+// their authorization, and the overview dashboard of their organization.
+func revisionFields(t *testing.T) RevisionFields {
+	return RevisionFields{
+		IDGenerator:   mock.NewIDGenerator(revisionCellOneID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)},
+		Users: []*influxdb.User{
+			{ID: MustIDBase16(userOneID), Name: "user"},
+		},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "org"},
+		},
+		Authorizations: []*influxdb.Authorization{
+			{OrgID: MustIDBase16(orgOneID), UserID: MustIDBase16(userOneID)},
+		},
+		Dashboards: []*influxdb.Dashboard{
+			{ID: MustIDBase16(dashOneID), OrganizationID: MustIDBase16(orgOneID), Name: "overview"},
+		},
+	}
+}
+
+// revisionVersion is the version of a revision and the user who made the change.
+type revisionVersion struct {
+	Version int
+	UserID  influxdb.ID
+}
+
+func revisionVersions(rs []*influxdb.Revision) []revisionVersion {
+	var vs []revisionVersion
+	for _, r := range rs {
+		vs = append(vs, revisionVersion{Version: r.Version, UserID: r.UserID})
+	}
+	return vs
+}
+
+// dashboardChange changes the overview dashboard as the user of the authorization.
+type dashboardChange func(ctx context.Context, s DashboardRevisionServices) error
+
+func renameDashboard(name string) dashboardChange {
+	return func(ctx context.Context, s DashboardRevisionServices) error {
+		_, err := s.UpdateDashboard(ctx, MustIDBase16(dashOneID), influxdb.DashboardUpdate{Name: &name})
+		return err
+	}
+}
+
+func addDashboardCell(view string) dashboardChange {
+	return func(ctx context.Context, s DashboardRevisionServices) error {
+		return s.AddDashboardCell(ctx, MustIDBase16(dashOneID), &influxdb.Cell{
+			CellProperty: influxdb.CellProperty{W: 4, H: 4},
+		}, influxdb.AddDashboardCellOptions{
+			View: &influxdb.View{
+				ViewContents: influxdb.ViewContents{Name: view},
+				Properties:   influxdb.EmptyViewProperties{},
+			},
+		})
+	}
+}
+
+func restoreDashboardRevision(version int) dashboardChange {
+	return func(ctx context.Context, s DashboardRevisionServices) error {
+		return s.RestoreRevision(ctx, influxdb.DashboardsResourceType, MustIDBase16(dashOneID), version)
+	}
+}
+
+// changeDashboard applies the changes as the user of the first authorization of the fields.
+func changeDashboard(t *testing.T, s DashboardRevisionServices, f RevisionFields, changes []dashboardChange) context.Context {
+	t.Helper()
+	ctx := icontext.SetAuthorizer(context.Background(), f.Authorizations[0])
+	for _, change := range changes {
+		if err := change(ctx, s); err != nil {
+			t.Fatalf("failed to change dashboard: %v", err)
+		}
+	}
+	return ctx
+}
+
+// FindDashboardRevisions testing
+func FindDashboardRevisions(
+	init func(RevisionFields, *testing.T) (DashboardRevisionServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		changes      []dashboardChange
+		resourceType influxdb.ResourceType
+	}
+	type wants struct {
+		err      error
+		versions []revisionVersion
+	}
+
+	tests := []struct {
+		name   string
+		fields RevisionFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "nothing is recorded until the dashboard changes",
+			fields: revisionFields(t),
+			args: args{
+				resourceType: influxdb.DashboardsResourceType,
+			},
+		},
+		{
+			name:   "the dashboard as created and each change are recorded latest first",
+			fields: revisionFields(t),
+			args: args{
+				changes: []dashboardChange{
+					renameDashboard("cpu overview"),
+					addDashboardCell("cpu"),
+				},
+				resourceType: influxdb.DashboardsResourceType,
+			},
+			wants: wants{
+				versions: []revisionVersion{
+					{Version: 3, UserID: MustIDBase16(userOneID)},
+					{Version: 2, UserID: MustIDBase16(userOneID)},
+					{Version: 1},
+				},
+			},
+		},
+		{
+			name:   "the revisions of deleted dashboards are deleted",
+			fields: revisionFields(t),
+			args: args{
+				changes: []dashboardChange{
+					renameDashboard("cpu overview"),
+					func(ctx context.Context, s DashboardRevisionServices) error {
+						return s.DeleteDashboard(ctx, MustIDBase16(dashOneID))
+					},
+				},
+				resourceType: influxdb.DashboardsResourceType,
+			},
+		},
+		{
+			name:   "revisions of buckets are not recorded",
+			fields: revisionFields(t),
+			args: args{
+				resourceType: influxdb.BucketsResourceType,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "revisions of resources of type buckets are not recorded",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := changeDashboard(t, s, tt.fields, tt.args.changes)
+
+			rs, n, err := s.FindRevisions(ctx, tt.args.resourceType, MustIDBase16(dashOneID))
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if n != len(tt.wants.versions) {
+				t.Errorf("expected %d revisions, got %d", len(tt.wants.versions), n)
+			}
+			if diff := cmp.Diff(revisionVersions(rs), tt.wants.versions); diff != "" {
+				t.Errorf("revisions are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindDashboardRevision testing
+func FindDashboardRevision(
+	init func(RevisionFields, *testing.T) (DashboardRevisionServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		changes []dashboardChange
+		version int
+	}
+	type wants struct {
+		err error
+		// diff are the lines the diff of the revision contains.
+		diff []string
+	}
+
+	tests := []struct {
+		name   string
+		fields RevisionFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "revisions record the diff of the change",
+			fields: revisionFields(t),
+			args: args{
+				changes: []dashboardChange{
+					renameDashboard("cpu overview"),
+				},
+				version: 2,
+			},
+			wants: wants{
+				diff: []string{
+					`-  "name": "overview",`,
+					`+  "name": "cpu overview",`,
+				},
+			},
+		},
+		{
+			name:   "missing revisions are not found",
+			fields: revisionFields(t),
+			args: args{
+				changes: []dashboardChange{
+					renameDashboard("cpu overview"),
+				},
+				version: 10,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrRevisionNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := changeDashboard(t, s, tt.fields, tt.args.changes)
+
+			r, err := s.FindRevision(ctx, influxdb.DashboardsResourceType, MustIDBase16(dashOneID), tt.args.version)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			for _, line := range tt.wants.diff {
+				if !strings.Contains(r.Diff, line) {
+					t.Errorf("expected the diff to contain %q:\n%s", line, r.Diff)
+				}
+			}
+		})
+	}
+}
+
+// RestoreDashboardRevision testing
+func RestoreDashboardRevision(
+	init func(RevisionFields, *testing.T) (DashboardRevisionServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		changes []dashboardChange
+		version int
+	}
+	type wants struct {
+		err  error
+		name string
+		// view is the name of the view of the cell, or empty if the dashboard
+		// has no cell.
+		view string
+	}
+
+	tests := []struct {
+		name   string
+		fields RevisionFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "restoring the dashboard as created drops the cells added since",
+			fields: revisionFields(t),
+			args: args{
+				changes: []dashboardChange{
+					renameDashboard("cpu overview"),
+					addDashboardCell("cpu"),
+				},
+				version: 1,
+			},
+			wants: wants{
+				name: "overview",
+			},
+		},
+		{
+			name:   "restoring a revision with a cell brings back its view",
+			fields: revisionFields(t),
+			args: args{
+				changes: []dashboardChange{
+					renameDashboard("cpu overview"),
+					addDashboardCell("cpu"),
+					restoreDashboardRevision(1),
+				},
+				version: 3,
+			},
+			wants: wants{
+				name: "cpu overview",
+				view: "cpu",
+			},
+		},
+		{
+			name:   "missing revisions are not found",
+			fields: revisionFields(t),
+			args: args{
+				changes: []dashboardChange{
+					renameDashboard("cpu overview"),
+				},
+				version: 10,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrRevisionNotFound,
+				},
+				name: "cpu overview",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := changeDashboard(t, s, tt.fields, tt.args.changes)
+
+			err := s.RestoreRevision(ctx, influxdb.DashboardsResourceType, MustIDBase16(dashOneID), tt.args.version)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			d, err := s.FindDashboardByID(ctx, MustIDBase16(dashOneID))
+			if err != nil {
+				t.Fatalf("failed to retrieve dashboard: %v", err)
+			}
+			if d.Name != tt.wants.name {
+				t.Errorf("expected dashboard %q, got %q", tt.wants.name, d.Name)
+			}
+
+			v, err := s.GetDashboardCellView(ctx, MustIDBase16(dashOneID), MustIDBase16(revisionCellOneID))
+			if tt.wants.view == "" {
+				if len(d.Cells) != 0 || influxdb.ErrorCode(err) != influxdb.ENotFound {
+					t.Errorf("expected the dashboard to have no cell, got %v and %v", d.Cells, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to retrieve the view of the cell: %v", err)
+			}
+			if v.Name != tt.wants.view {
+				t.Errorf("expected view %q, got %q", tt.wants.view, v.Name)
+			}
+
+			if err == nil && tt.wants.err == nil {
+				// The restore is itself recorded with the content of the revision.
+				rs, _, err := s.FindRevisions(ctx, influxdb.DashboardsResourceType, MustIDBase16(dashOneID))
+				if err != nil {
+					t.Fatalf("failed to retrieve revisions: %v", err)
+				}
+				restored, err := s.FindRevision(ctx, influxdb.DashboardsResourceType, MustIDBase16(dashOneID), tt.args.version)
+				if err != nil {
+					t.Fatalf("failed to retrieve revision: %v", err)
+				}
+				if rs[0].Content != restored.Content {
+					t.Errorf("expected the restore to be recorded with the content of the revision, got %s", rs[0].Content)
+				}
+			}
+		})
+	}
+}
+
+const (
+	revisionTaskFlux       = `option task = {name: "downsample", every: 1h} from(bucket: "b") |> range(start: -1h)`
+	revisionTaskEditedFlux = `option task = {name: "downsample hourly", every: 2h} from(bucket: "b") |> range(start: -2h)`
+)
+
+// changeTask creates the downsample task as the user of the first authorization of the
+// fields and updates it.
+func changeTask(t *testing.T, s TaskRevisionServices, f RevisionFields, updates []influxdb.TaskUpdate) (context.Context, *influxdb.Task) {
+	t.Helper()
+	auth := f.Authorizations[0]
+	ctx := icontext.SetAuthorizer(context.Background(), auth)
+	task, err := s.CreateTask(ctx, influxdb.TaskCreate{
+		OrganizationID: auth.OrgID,
+		Flux:           revisionTaskFlux,
+		Token:          auth.Token,
+	})
+	if err != nil {
+		t.Fatalf("failed to create task: %v", err)
+	}
+	for _, upd := range updates {
+		if _, err := s.UpdateTask(ctx, task.ID, upd); err != nil {
+			t.Fatalf("failed to update task: %v", err)
+		}
+	}
+	return ctx, task
+}
+
+// FindTaskRevisions testing
+func FindTaskRevisions(
+	init func(RevisionFields, *testing.T) (TaskRevisionServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		updates []influxdb.TaskUpdate
+	}
+	type wants struct {
+		contents []string
+		diff     string
+	}
+
+	tests := []struct {
+		name   string
+		fields RevisionFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "edits of the flux are recorded",
+			fields: revisionFields(t),
+			args: args{
+				updates: []influxdb.TaskUpdate{
+					{Flux: strPtr(revisionTaskEditedFlux)},
+				},
+			},
+			wants: wants{
+				contents: []string{revisionTaskEditedFlux, revisionTaskFlux},
+				diff:     "-" + revisionTaskFlux + "\n+" + revisionTaskEditedFlux,
+			},
+		},
+		{
+			name:   "changes that leave the flux as it is are not recorded",
+			fields: revisionFields(t),
+			args: args{
+				updates: []influxdb.TaskUpdate{
+					{Description: strPtr("hourly downsampling")},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx, task := changeTask(t, s, tt.fields, tt.args.updates)
+
+			rs, _, err := s.FindRevisions(ctx, influxdb.TasksResourceType, task.ID)
+			if err != nil {
+				t.Fatalf("failed to retrieve revisions: %v", err)
+			}
+			var contents []string
+			for _, r := range rs {
+				contents = append(contents, r.Content)
+			}
+			if diff := cmp.Diff(contents, tt.wants.contents); diff != "" {
+				t.Errorf("contents of the revisions are different -got/+want\ndiff %s", diff)
+			}
+			if tt.wants.diff != "" && rs[0].Diff != tt.wants.diff {
+				t.Errorf("unexpected diff of the latest revision:\n%s", rs[0].Diff)
+			}
+		})
+	}
+}
+
+// RestoreTaskRevision testing
+func RestoreTaskRevision(
+	init func(RevisionFields, *testing.T) (TaskRevisionServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		updates []influxdb.TaskUpdate
+		version int
+	}
+	type wants struct {
+		err         error
+		flux        string
+		name        string
+		every       string
+		description string
+		revisions   int
+	}
+
+	updates := []influxdb.TaskUpdate{
+		{Flux: strPtr(revisionTaskEditedFlux)},
+		{Description: strPtr("hourly downsampling")},
+	}
+
+	tests := []struct {
+		name   string
+		fields RevisionFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "restoring the task as created restores its options and keeps its description",
+			fields: revisionFields(t),
+			args: args{
+				updates: updates,
+				version: 1,
+			},
+			wants: wants{
+				flux:        revisionTaskFlux,
+				name:        "downsample",
+				every:       "1h",
+				description: "hourly downsampling",
+				revisions:   3,
+			},
+		},
+		{
+			name:   "missing revisions are not found",
+			fields: revisionFields(t),
+			args: args{
+				updates: updates,
+				version: 10,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrRevisionNotFound,
+				},
+				flux:        revisionTaskEditedFlux,
+				name:        "downsample hourly",
+				every:       "2h",
+				description: "hourly downsampling",
+				revisions:   2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx, task := changeTask(t, s, tt.fields, tt.args.updates)
+
+			err := s.RestoreRevision(ctx, influxdb.TasksResourceType, task.ID, tt.args.version)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			got, err := s.FindTaskByID(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("failed to retrieve task: %v", err)
+			}
+			if got.Flux != tt.wants.flux || got.Name != tt.wants.name || got.Every != tt.wants.every {
+				t.Errorf("expected task %q every %s, got %q every %s", tt.wants.name, tt.wants.every, got.Name, got.Every)
+			}
+			if got.Description != tt.wants.description {
+				t.Errorf("expected description %q, got %q", tt.wants.description, got.Description)
+			}
+
+			if _, n, err := s.FindRevisions(ctx, influxdb.TasksResourceType, task.ID); err != nil || n != tt.wants.revisions {
+				t.Errorf("expected %d revisions, got %d: %v", tt.wants.revisions, n, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+// SchemaDescriptionFields will include the TimeGenerator, and the organizations, buckets
+// and schema descriptions to populate the store with.
+type SchemaDescriptionFields struct {
+	TimeGenerator      influxdb.TimeGenerator
+	Organizations      []*influxdb.Organization
+	Buckets            []*influxdb.Bucket
+	SchemaDescriptions []*influxdb.SchemaDescriptions
+}
+
+// SchemaDescriptionServices are the schema description service and the bucket service
+// that holds the buckets the schemas describe.
+type SchemaDescriptionServices interface {
+	influxdb.SchemaDescriptionService
+	influxdb.BucketService
+}
+
+type schemaDescriptionServiceF func(
+	init func(SchemaDescriptionFields, *testing.T) (SchemaDescriptionServices, func()),
+	t *testing.T,
+)
+
+// SchemaDescriptionService tests all the service functions.
+func SchemaDescriptionService(
+	init func(SchemaDescriptionFields, *testing.T) (SchemaDescriptionServices, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   schemaDescriptionServiceF
+	}{
+		{
+			name: "PutSchemaDescriptions",
+			fn:   PutSchemaDescriptions,
+		},
+		{
+			name: "FindSchemaDescriptions",
+			fn:   FindSchemaDescriptions,
+		},
+		{
+			name: "DeleteBucketSchemaDescriptions",
+			fn:   DeleteBucketSchemaDescriptions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var schemaDescriptionTime = time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+
+// schemaDescriptionFields returns the fields of a store with a bucket that is
+// described if described is true.
+func schemaDescriptionFields(described bool) SchemaDescriptionFields {
+	f := SchemaDescriptionFields{
+		TimeGenerator: mock.TimeGenerator{FakeValue: schemaDescriptionTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+		},
+		Buckets: []*influxdb.Bucket{
+			{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "telegraf"},
+			{ID: MustIDBase16(bucketTwoID), OrgID: MustIDBase16(orgOneID), Name: "other"},
+		},
+	}
+	if described {
+		f.SchemaDescriptions = []*influxdb.SchemaDescriptions{
+			newSchemaDescriptions(bucketOneID, schemaDescriptionTime, schemaDescriptionTime),
+		}
+	}
+	return f
+}
+
+func newSchemaDescriptions(bucketID string, createdAt, updatedAt time.Time) *influxdb.SchemaDescriptions {
+	return &influxdb.SchemaDescriptions{
+		OrgID:       MustIDBase16(orgOneID),
+		BucketID:    MustIDBase16(bucketID),
+		Description: "metrics of the hosts",
+		Measurements: map[string]influxdb.MeasurementDescriptions{
+			"cpu": {
+				Description: "cpu usage",
+				Tags:        map[string]string{"host": "hostname"},
+				Fields:      map[string]string{"usage_user": "time in user space"},
+			},
+		},
+		CRUDLog: influxdb.CRUDLog{
+			CreatedAt: createdAt,
+			UpdatedAt: updatedAt,
+		},
+	}
+}
+
+// PutSchemaDescriptions testing
+func PutSchemaDescriptions(
+	init func(SchemaDescriptionFields, *testing.T) (SchemaDescriptionServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		schemaDescriptions *influxdb.SchemaDescriptions
+	}
+	type wants struct {
+		err                error
+		schemaDescriptions *influxdb.SchemaDescriptions
+	}
+
+	// The descriptions are put an hour after the store was populated.
+	later := schemaDescriptionTime.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		fields SchemaDescriptionFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "describe a bucket in its organization",
+			fields: schemaDescriptionFields(false),
+			args: args{
+				schemaDescriptions: &influxdb.SchemaDescriptions{
+					BucketID:    MustIDBase16(bucketOneID),
+					Description: "host metrics",
+				},
+			},
+			wants: wants{
+				schemaDescriptions: &influxdb.SchemaDescriptions{
+					OrgID:       MustIDBase16(orgOneID),
+					BucketID:    MustIDBase16(bucketOneID),
+					Description: "host metrics",
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: later,
+						UpdatedAt: later,
+					},
+				},
+			},
+		},
+		{
+			name:   "replaced descriptions keep their creation time",
+			fields: schemaDescriptionFields(true),
+			args: args{
+				schemaDescriptions: &influxdb.SchemaDescriptions{
+					BucketID:    MustIDBase16(bucketOneID),
+					Description: "host metrics",
+				},
+			},
+			wants: wants{
+				schemaDescriptions: &influxdb.SchemaDescriptions{
+					OrgID:       MustIDBase16(orgOneID),
+					BucketID:    MustIDBase16(bucketOneID),
+					Description: "host metrics",
+					CRUDLog: influxdb.CRUDLog{
+						CreatedAt: schemaDescriptionTime,
+						UpdatedAt: later,
+					},
+				},
+			},
+		},
+		{
+			name:   "descriptions that are too long are rejected",
+			fields: schemaDescriptionFields(true),
+			args: args{
+				schemaDescriptions: &influxdb.SchemaDescriptions{
+					BucketID:    MustIDBase16(bucketOneID),
+					Description: strings.Repeat("x", influxdb.MaxSchemaDescriptionLength+1),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  fmt.Sprintf("description of bucket is longer than %d characters", influxdb.MaxSchemaDescriptionLength),
+				},
+				schemaDescriptions: newSchemaDescriptions(bucketOneID, schemaDescriptionTime, schemaDescriptionTime),
+			},
+		},
+		{
+			name:   "descriptions of another organization are rejected",
+			fields: schemaDescriptionFields(true),
+			args: args{
+				schemaDescriptions: &influxdb.SchemaDescriptions{
+					OrgID:    MustIDBase16(orgTwoID),
+					BucketID: MustIDBase16(bucketOneID),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "orgID does not match the organization of the bucket",
+				},
+				schemaDescriptions: newSchemaDescriptions(bucketOneID, schemaDescriptionTime, schemaDescriptionTime),
+			},
+		},
+		{
+			name:   "descriptions of missing buckets are not found",
+			fields: schemaDescriptionFields(true),
+			args: args{
+				schemaDescriptions: &influxdb.SchemaDescriptions{
+					BucketID: MustIDBase16(bucketThreeID),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "bucket not found",
+				},
+				schemaDescriptions: newSchemaDescriptions(bucketOneID, schemaDescriptionTime, schemaDescriptionTime),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tg := &mock.TimeGenerator{FakeValue: schemaDescriptionTime}
+			tt.fields.TimeGenerator = tg
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+			tg.FakeValue = later
+
+			err := s.PutSchemaDescriptions(ctx, tt.args.schemaDescriptions)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			d, err := s.FindSchemaDescriptions(ctx, MustIDBase16(bucketOneID))
+			if err != nil {
+				t.Fatalf("failed to retrieve schema descriptions: %v", err)
+			}
+			if diff := cmp.Diff(d, tt.wants.schemaDescriptions); diff != "" {
+				t.Errorf("schema descriptions are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindSchemaDescriptions testing
+func FindSchemaDescriptions(
+	init func(SchemaDescriptionFields, *testing.T) (SchemaDescriptionServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		bucketID influxdb.ID
+	}
+	type wants struct {
+		err                error
+		schemaDescriptions *influxdb.SchemaDescriptions
+	}
+
+	tests := []struct {
+		name   string
+		fields SchemaDescriptionFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the descriptions of a bucket",
+			fields: schemaDescriptionFields(true),
+			args: args{
+				bucketID: MustIDBase16(bucketOneID),
+			},
+			wants: wants{
+				schemaDescriptions: newSchemaDescriptions(bucketOneID, schemaDescriptionTime, schemaDescriptionTime),
+			},
+		},
+		{
+			name:   "buckets without descriptions have none",
+			fields: schemaDescriptionFields(true),
+			args: args{
+				bucketID: MustIDBase16(bucketTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrSchemaDescriptionsNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			d, err := s.FindSchemaDescriptions(ctx, tt.args.bucketID)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(d, tt.wants.schemaDescriptions); diff != "" {
+				t.Errorf("schema descriptions are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteBucketSchemaDescriptions testing
+func DeleteBucketSchemaDescriptions(
+	init func(SchemaDescriptionFields, *testing.T) (SchemaDescriptionServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		bucketID influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields SchemaDescriptionFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "the descriptions of deleted buckets are deleted",
+			fields: schemaDescriptionFields(true),
+			args: args{
+				bucketID: MustIDBase16(bucketOneID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrSchemaDescriptionsNotFound,
+				},
+			},
+		},
+		{
+			name:   "the descriptions of other buckets are kept",
+			fields: schemaDescriptionFields(true),
+			args: args{
+				bucketID: MustIDBase16(bucketTwoID),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			if err := s.DeleteBucket(ctx, tt.args.bucketID); err != nil {
+				t.Fatalf("failed to delete bucket: %v", err)
+			}
+
+			_, err := s.FindSchemaDescriptions(ctx, MustIDBase16(bucketOneID))
+			ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+// ScraperTargetStatusFields will include the TimeGenerator, and the organizations, scraper
+// targets and target statuses to populate the store with. The statuses are recorded in order.
+type ScraperTargetStatusFields struct {
+	TimeGenerator  influxdb.TimeGenerator
+	Organizations  []*influxdb.Organization
+	Targets        []*influxdb.ScraperTarget
+	TargetStatuses []*influxdb.ScraperTargetStatus
+}
+
+// ScraperTargetStatusServices are the scraper target status service and the scraper
+// target service that holds the targets.
+type ScraperTargetStatusServices interface {
+	influxdb.ScraperTargetStatusService
+	influxdb.ScraperTargetStoreService
+}
+
+type scraperTargetStatusServiceF func(
+	init func(ScraperTargetStatusFields, *testing.T) (ScraperTargetStatusServices, func()),
+	t *testing.T,
+)
+
+// ScraperTargetStatusService tests all the service functions.
+func ScraperTargetStatusService(
+	init func(ScraperTargetStatusFields, *testing.T) (ScraperTargetStatusServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   scraperTargetStatusServiceF
+	}{
+		{
+			name: "PutTargetStatus",
+			fn:   PutTargetStatus,
+		},
+		{
+			name: "FindTargetStatus",
+			fn:   FindTargetStatus,
+		},
+		{
+			name: "RemoveTargetStatus",
+			fn:   RemoveTargetStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var scrapeTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// scraperTargetStatusFields returns the fields of a store with the node target, that
+// was scraped successfully and then failed, and the redis target that was not scraped.
+func scraperTargetStatusFields() ScraperTargetStatusFields {
+	return ScraperTargetStatusFields{
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+		},
+		Targets: []*influxdb.ScraperTarget{
+			{
+				ID:       MustIDBase16(targetOneID),
+				Name:     "node",
+				Type:     influxdb.PrometheusScraperType,
+				URL:      "http://node:9100/metrics",
+				OrgID:    MustIDBase16(orgOneID),
+				BucketID: MustIDBase16(bucketOneID),
+			},
+			{
+				ID:       MustIDBase16(targetTwoID),
+				Name:     "redis",
+				Type:     influxdb.PrometheusScraperType,
+				URL:      "http://redis:9121/metrics",
+				OrgID:    MustIDBase16(orgOneID),
+				BucketID: MustIDBase16(bucketOneID),
+			},
+		},
+		TargetStatuses: []*influxdb.ScraperTargetStatus{
+			{
+				TargetID:   MustIDBase16(targetOneID),
+				LastScrape: scrapeTime,
+				Duration:   12,
+				Samples:    40,
+			},
+			{
+				TargetID:   MustIDBase16(targetOneID),
+				LastScrape: scrapeTime.Add(time.Minute),
+				LastError:  "401 Unauthorized",
+			},
+		},
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// PutTargetStatus testing
+func PutTargetStatus(
+	init func(ScraperTargetStatusFields, *testing.T) (ScraperTargetStatusServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		status *influxdb.ScraperTargetStatus
+	}
+	type wants struct {
+		err    error
+		status *influxdb.ScraperTargetStatus
+	}
+
+	tests := []struct {
+		name   string
+		fields ScraperTargetStatusFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "successful scrapes reset the failures",
+			fields: scraperTargetStatusFields(),
+			args: args{
+				status: &influxdb.ScraperTargetStatus{
+					TargetID:   MustIDBase16(targetOneID),
+					LastScrape: scrapeTime.Add(2 * time.Minute),
+					Duration:   10,
+					Samples:    41,
+				},
+			},
+			wants: wants{
+				status: &influxdb.ScraperTargetStatus{
+					TargetID:    MustIDBase16(targetOneID),
+					OrgID:       MustIDBase16(orgOneID),
+					LastScrape:  scrapeTime.Add(2 * time.Minute),
+					Duration:    10,
+					Samples:     41,
+					LastSuccess: timePtr(scrapeTime.Add(2 * time.Minute)),
+				},
+			},
+		},
+		{
+			name:   "failures keep the last successful scrape and are counted",
+			fields: scraperTargetStatusFields(),
+			args: args{
+				status: &influxdb.ScraperTargetStatus{
+					TargetID:   MustIDBase16(targetOneID),
+					LastScrape: scrapeTime.Add(2 * time.Minute),
+					LastError:  "401 Unauthorized",
+				},
+			},
+			wants: wants{
+				status: &influxdb.ScraperTargetStatus{
+					TargetID:            MustIDBase16(targetOneID),
+					OrgID:               MustIDBase16(orgOneID),
+					LastScrape:          scrapeTime.Add(2 * time.Minute),
+					LastError:           "401 Unauthorized",
+					LastSuccess:         timePtr(scrapeTime),
+					ConsecutiveFailures: 2,
+				},
+			},
+		},
+		{
+			name:   "the first scrape of a target can fail",
+			fields: scraperTargetStatusFields(),
+			args: args{
+				status: &influxdb.ScraperTargetStatus{
+					TargetID:   MustIDBase16(targetTwoID),
+					LastScrape: scrapeTime,
+					LastError:  "connection refused",
+				},
+			},
+			wants: wants{
+				status: &influxdb.ScraperTargetStatus{
+					TargetID:            MustIDBase16(targetTwoID),
+					OrgID:               MustIDBase16(orgOneID),
+					LastScrape:          scrapeTime,
+					LastError:           "connection refused",
+					ConsecutiveFailures: 1,
+				},
+			},
+		},
+		{
+			name:   "statuses of missing targets are not found",
+			fields: scraperTargetStatusFields(),
+			args: args{
+				status: &influxdb.ScraperTargetStatus{
+					TargetID:   MustIDBase16(targetThreeID),
+					LastScrape: scrapeTime,
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "scraper target is not found",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.PutTargetStatus(ctx, tt.args.status)
+			ErrorsEqual(t, err, tt.wants.err)
+			if err != nil {
+				return
+			}
+
+			st, err := s.FindTargetStatus(ctx, tt.args.status.TargetID)
+			if err != nil {
+				t.Fatalf("failed to retrieve target status: %v", err)
+			}
+			if diff := cmp.Diff(st, tt.wants.status); diff != "" {
+				t.Errorf("target status is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindTargetStatus testing
+func FindTargetStatus(
+	init func(ScraperTargetStatusFields, *testing.T) (ScraperTargetStatusServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		targetID influxdb.ID
+	}
+	type wants struct {
+		err    error
+		status *influxdb.ScraperTargetStatus
+	}
+
+	tests := []struct {
+		name   string
+		fields ScraperTargetStatusFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the status of the last scrape of a target",
+			fields: scraperTargetStatusFields(),
+			args: args{
+				targetID: MustIDBase16(targetOneID),
+			},
+			wants: wants{
+				status: &influxdb.ScraperTargetStatus{
+					TargetID:            MustIDBase16(targetOneID),
+					OrgID:               MustIDBase16(orgOneID),
+					LastScrape:          scrapeTime.Add(time.Minute),
+					LastError:           "401 Unauthorized",
+					LastSuccess:         timePtr(scrapeTime),
+					ConsecutiveFailures: 1,
+				},
+			},
+		},
+		{
+			name:   "targets that were not scraped have no status",
+			fields: scraperTargetStatusFields(),
+			args: args{
+				targetID: MustIDBase16(targetTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrScraperTargetStatusNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			st, err := s.FindTargetStatus(ctx, tt.args.targetID)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(st, tt.wants.status); diff != "" {
+				t.Errorf("target status is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// RemoveTargetStatus testing
+func RemoveTargetStatus(
+	init func(ScraperTargetStatusFields, *testing.T) (ScraperTargetStatusServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		targetID influxdb.ID
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields ScraperTargetStatusFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "removing a target forgets its status",
+			fields: scraperTargetStatusFields(),
+			args: args{
+				targetID: MustIDBase16(targetOneID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrScraperTargetStatusNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			if err := s.RemoveTarget(ctx, tt.args.targetID); err != nil {
+				t.Fatalf("failed to remove target: %v", err)
+			}
+
+			_, err := s.FindTargetStatus(ctx, tt.args.targetID)
+			ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
@@ -974,3 +974,414 @@ func UpdateTarget(
 		})
 	}
 }
+
+// ScraperTargetSecretFields will include the IDGenerator, and the organizations, the
+// secrets of the first organization and the scraper targets to populate the store with.
+// The targets are added so that their credentials are kept in secrets.
+type ScraperTargetSecretFields struct {
+	IDGenerator   influxdb.IDGenerator
+	Organizations []*influxdb.Organization
+	Secrets       map[string]string
+	Targets       []*influxdb.ScraperTarget
+}
+
+// ScraperTargetSecretServices are the scraper target service and the secret service that
+// holds the credentials of the targets.
+type ScraperTargetSecretServices interface {
+	influxdb.ScraperTargetStoreService
+	influxdb.SecretService
+}
+
+type scraperTargetSecretServiceF func(
+	init func(ScraperTargetSecretFields, *testing.T) (ScraperTargetSecretServices, func()),
+	t *testing.T,
+)
+
+// ScraperTargetSecrets tests that the credentials of targets are kept in the secrets of
+// their organizations.
+func ScraperTargetSecrets(
+	init func(ScraperTargetSecretFields, *testing.T) (ScraperTargetSecretServices, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   scraperTargetSecretServiceF
+	}{
+		{
+			name: "AddTargetSecrets",
+			fn:   AddTargetSecrets,
+		},
+		{
+			name: "UpdateTargetSecrets",
+			fn:   UpdateTargetSecrets,
+		},
+		{
+			name: "RemoveTargetSecrets",
+			fn:   RemoveTargetSecrets,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// targetTokenKey is the key of the secret of the token of the node target.
+var targetTokenKey = targetOneID + "-token"
+
+// nodeTarget returns the node target that authenticates with the token of its secret.
+func nodeTarget() *influxdb.ScraperTarget {
+	return &influxdb.ScraperTarget{
+		ID:         MustIDBase16(targetOneID),
+		Name:       "node",
+		Type:       influxdb.PrometheusScraperType,
+		URL:        "https://node:9100/metrics",
+		OrgID:      MustIDBase16(orgOneID),
+		BucketID:   MustIDBase16(bucketOneID),
+		Interval:   "30s",
+		AuthMethod: influxdb.HTTPAuthBearer,
+		Token:      &influxdb.SecretField{Key: targetTokenKey},
+		Headers:    map[string]string{"X-Scope": "metrics"},
+	}
+}
+
+// scraperTargetSecretFields returns the fields of a store with the shared secret and the
+// node target, whose token abc123 was given by value.
+func scraperTargetSecretFields(t *testing.T) ScraperTargetSecretFields {
+	node := nodeTarget()
+	node.Token = &influxdb.SecretField{Value: strPtr("abc123")}
+	return ScraperTargetSecretFields{
+		IDGenerator: mock.NewIDGenerator(targetTwoID, t),
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+		},
+		Secrets: map[string]string{
+			"shared": "def456",
+		},
+		Targets: []*influxdb.ScraperTarget{node},
+	}
+}
+
+// targetSecretsOf returns the secrets of the first organization.
+func targetSecretsOf(ctx context.Context, s ScraperTargetSecretServices, t *testing.T) map[string]string {
+	t.Helper()
+	orgID := MustIDBase16(orgOneID)
+	keys, err := s.GetSecretKeys(ctx, orgID)
+	if err != nil {
+		t.Fatalf("failed to retrieve secret keys: %v", err)
+	}
+	secrets := map[string]string{}
+	for _, k := range keys {
+		v, err := s.LoadSecret(ctx, orgID, k)
+		if err != nil {
+			t.Fatalf("failed to load secret %s: %v", k, err)
+		}
+		secrets[k] = v
+	}
+	return secrets
+}
+
+// AddTargetSecrets testing
+func AddTargetSecrets(
+	init func(ScraperTargetSecretFields, *testing.T) (ScraperTargetSecretServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		target *influxdb.ScraperTarget
+	}
+	type wants struct {
+		err     error
+		target  *influxdb.ScraperTarget
+		secrets map[string]string
+	}
+
+	target := func() *influxdb.ScraperTarget {
+		return &influxdb.ScraperTarget{
+			Name:     "redis",
+			Type:     influxdb.PrometheusScraperType,
+			URL:      "https://redis:9121/metrics",
+			OrgID:    MustIDBase16(orgOneID),
+			BucketID: MustIDBase16(bucketOneID),
+		}
+	}
+	basic := target()
+	basic.AuthMethod = influxdb.HTTPAuthBasic
+	basic.Username = &influxdb.SecretField{Value: strPtr("scraper")}
+	basic.Password = &influxdb.SecretField{Key: "shared"}
+	added := target()
+	added.ID = MustIDBase16(targetTwoID)
+	added.AuthMethod = influxdb.HTTPAuthBasic
+	added.Username = &influxdb.SecretField{Key: targetTwoID + "-username"}
+	added.Password = &influxdb.SecretField{Key: "shared"}
+	invalidInterval := target()
+	invalidInterval.Interval = "often"
+	withoutPassword := target()
+	withoutPassword.AuthMethod = influxdb.HTTPAuthBasic
+	withoutPassword.Username = &influxdb.SecretField{Value: strPtr("scraper")}
+	missingSecret := target()
+	missingSecret.AuthMethod = influxdb.HTTPAuthBearer
+	missingSecret.Token = &influxdb.SecretField{Key: "missing"}
+	authorizationHeader := target()
+	authorizationHeader.AuthMethod = influxdb.HTTPAuthBearer
+	authorizationHeader.Token = &influxdb.SecretField{Key: targetTokenKey}
+	authorizationHeader.Headers = map[string]string{"authorization": "x"}
+	invalidCACert := target()
+	invalidCACert.TLS = &influxdb.ScraperTLSConfig{CACert: "not a certificate"}
+
+	secrets := map[string]string{
+		"shared":       "def456",
+		targetTokenKey: "abc123",
+	}
+	tests := []struct {
+		name   string
+		fields ScraperTargetSecretFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "credentials given by value are kept in secrets named after the target",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				target: basic,
+			},
+			wants: wants{
+				target: added,
+				secrets: map[string]string{
+					"shared":                  "def456",
+					targetTokenKey:            "abc123",
+					targetTwoID + "-username": "scraper",
+				},
+			},
+		},
+		{
+			name:   "intervals are positive durations",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				target: invalidInterval,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  `invalid scraper target interval "often": must be a positive duration such as 30s`,
+				},
+				secrets: secrets,
+			},
+		},
+		{
+			name:   "basic auth needs a username and a password",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				target: withoutPassword,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "basic auth needs a username and a password",
+				},
+				secrets: secrets,
+			},
+		},
+		{
+			name:   "credentials reference secrets that exist",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				target: missingSecret,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "token references secret missing that does not exist",
+				},
+				secrets: secrets,
+			},
+		},
+		{
+			name:   "the authorization header cannot be set with an auth method",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				target: authorizationHeader,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "the Authorization header cannot be set with an auth method",
+				},
+				secrets: secrets,
+			},
+		},
+		{
+			name:   "ca certificates are PEM encoded",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				target: invalidCACert,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "scraper target caCert has no PEM encoded certificate",
+				},
+				secrets: secrets,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.AddTarget(ctx, tt.args.target, MustIDBase16(threeID))
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if tt.wants.target != nil {
+				target, err := s.GetTargetByID(ctx, tt.wants.target.ID)
+				if err != nil {
+					t.Fatalf("failed to retrieve target: %v", err)
+				}
+				if diff := cmp.Diff(target, tt.wants.target, targetCmpOptions...); diff != "" {
+					t.Errorf("target is different -got/+want\ndiff %s", diff)
+				}
+			}
+			if diff := cmp.Diff(targetSecretsOf(ctx, s, t), tt.wants.secrets); diff != "" {
+				t.Errorf("secrets are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateTargetSecrets testing
+func UpdateTargetSecrets(
+	init func(ScraperTargetSecretFields, *testing.T) (ScraperTargetSecretServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		target *influxdb.ScraperTarget
+	}
+	type wants struct {
+		err     error
+		target  *influxdb.ScraperTarget
+		secrets map[string]string
+	}
+
+	toBasic := nodeTarget()
+	toBasic.OrgID = 0
+	toBasic.BucketID = 0
+	toBasic.Headers = nil
+	toBasic.AuthMethod = influxdb.HTTPAuthBasic
+	toBasic.Token = nil
+	toBasic.Username = &influxdb.SecretField{Value: strPtr("scraper")}
+	toBasic.Password = &influxdb.SecretField{Key: "shared"}
+	basic := nodeTarget()
+	basic.Headers = nil
+	basic.AuthMethod = influxdb.HTTPAuthBasic
+	basic.Token = nil
+	basic.Username = &influxdb.SecretField{Key: targetOneID + "-username"}
+	basic.Password = &influxdb.SecretField{Key: "shared"}
+	newToken := nodeTarget()
+	newToken.Token = &influxdb.SecretField{Value: strPtr("xyz789")}
+
+	tests := []struct {
+		name   string
+		fields ScraperTargetSecretFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "secrets named after the target that it does not use anymore are deleted",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				target: toBasic,
+			},
+			wants: wants{
+				target: basic,
+				secrets: map[string]string{
+					"shared":                  "def456",
+					targetOneID + "-username": "scraper",
+				},
+			},
+		},
+		{
+			name:   "credentials given by value replace their secrets",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				target: newToken,
+			},
+			wants: wants{
+				target: nodeTarget(),
+				secrets: map[string]string{
+					"shared":       "def456",
+					targetTokenKey: "xyz789",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			target, err := s.UpdateTarget(ctx, tt.args.target, MustIDBase16(threeID))
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(target, tt.wants.target, targetCmpOptions...); diff != "" {
+				t.Errorf("target is different -got/+want\ndiff %s", diff)
+			}
+			if diff := cmp.Diff(targetSecretsOf(ctx, s, t), tt.wants.secrets); diff != "" {
+				t.Errorf("secrets are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// RemoveTargetSecrets testing
+func RemoveTargetSecrets(
+	init func(ScraperTargetSecretFields, *testing.T) (ScraperTargetSecretServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err     error
+		secrets map[string]string
+	}
+
+	tests := []struct {
+		name   string
+		fields ScraperTargetSecretFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "removing a target deletes the secrets named after it",
+			fields: scraperTargetSecretFields(t),
+			args: args{
+				id: MustIDBase16(targetOneID),
+			},
+			wants: wants{
+				secrets: map[string]string{
+					"shared": "def456",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.RemoveTarget(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(targetSecretsOf(ctx, s, t), tt.wants.secrets); diff != "" {
+				t.Errorf("secrets are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package testing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+)
+
+const (
+	searchTelegrafOneID = "020f755c3c086000"
+	searchLabelOneID    = "020f755c3c086100"
+)
+
+// SearchFields will include the TimeGenerator, and the organizations, buckets, telegraf
+// configs, dashboards and labels to populate the store with.
+type SearchFields struct {
+	TimeGenerator   influxdb.TimeGenerator
+	Organizations   []*influxdb.Organization
+	Buckets         []*influxdb.Bucket
+	TelegrafConfigs []*influxdb.TelegrafConfig
+	Dashboards      []*influxdb.Dashboard
+	Labels          []*influxdb.Label
+}
+
+// SearchServices are the search service and the services whose changes it follows.
+type SearchServices interface {
+	influxdb.SearchService
+	influxdb.BucketService
+	influxdb.TelegrafConfigStore
+}
+
+type searchServiceF func(
+	init func(SearchFields, *testing.T) (SearchServices, func()),
+	t *testing.T,
+)
+
+// SearchService tests all the service functions.
+func SearchService(
+	init func(SearchFields, *testing.T) (SearchServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   searchServiceF
+	}{
+		{
+			name: "Search",
+			fn:   Search,
+		},
+		{
+			name: "SearchIndex",
+			fn:   SearchIndex,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+// searchFields returns the fields of a store with resources of two organizations
+// whose names and descriptions are searched.
+func searchFields() SearchFields {
+	return SearchFields{
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "org"},
+			{ID: MustIDBase16(orgTwoID), Name: "other"},
+		},
+		Buckets: []*influxdb.Bucket{
+			{
+				ID:          MustIDBase16(bucketOneID),
+				OrgID:       MustIDBase16(orgOneID),
+				Name:        "cpu metrics",
+				Description: "usage of every host",
+			},
+		},
+		TelegrafConfigs: []*influxdb.TelegrafConfig{
+			{
+				ID:    MustIDBase16(searchTelegrafOneID),
+				OrgID: MustIDBase16(orgOneID),
+				Name:  "system metrics",
+			},
+		},
+		Dashboards: []*influxdb.Dashboard{
+			{
+				ID:             MustIDBase16(dashOneID),
+				OrganizationID: MustIDBase16(orgOneID),
+				Name:           "Memory overview",
+			},
+		},
+		Labels: []*influxdb.Label{
+			{
+				ID:    MustIDBase16(searchLabelOneID),
+				OrgID: MustIDBase16(orgTwoID),
+				Name:  "metrics",
+			},
+		},
+	}
+}
+
+// searchResultNames returns the types and names of the results.
+func searchResultNames(rs []*influxdb.SearchResult) []string {
+	var names []string
+	for _, r := range rs {
+		names = append(names, string(r.Type)+":"+r.Name)
+	}
+	return names
+}
+
+// Search testing
+func Search(
+	init func(SearchFields, *testing.T) (SearchServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.SearchFilter
+	}
+	type wants struct {
+		err   error
+		names []string
+	}
+
+	tests := []struct {
+		name   string
+		fields SearchFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find resources by an exact word regardless of case",
+			fields: searchFields(),
+			args: args{
+				filter: influxdb.SearchFilter{Query: "Metrics"},
+			},
+			wants: wants{
+				names: []string{"buckets:cpu metrics", "labels:metrics", "telegrafs:system metrics"},
+			},
+		},
+		{
+			name:   "find resources that match every word",
+			fields: searchFields(),
+			args: args{
+				filter: influxdb.SearchFilter{Query: "cpu metrics"},
+			},
+			wants: wants{
+				names: []string{"buckets:cpu metrics"},
+			},
+		},
+		{
+			name:   "find resources by prefix",
+			fields: searchFields(),
+			args: args{
+				filter: influxdb.SearchFilter{Query: "mem"},
+			},
+			wants: wants{
+				names: []string{"dashboards:Memory overview"},
+			},
+		},
+		{
+			name:   "find resources with a typo",
+			fields: searchFields(),
+			args: args{
+				filter: influxdb.SearchFilter{Query: "metrcs"},
+			},
+			wants: wants{
+				names: []string{"buckets:cpu metrics", "labels:metrics", "telegrafs:system metrics"},
+			},
+		},
+		{
+			name:   "find resources by description",
+			fields: searchFields(),
+			args: args{
+				filter: influxdb.SearchFilter{Query: "host"},
+			},
+			wants: wants{
+				names: []string{"buckets:cpu metrics"},
+			},
+		},
+		{
+			name:   "find the resources of an organization",
+			fields: searchFields(),
+			args: args{
+				filter: influxdb.SearchFilter{
+					Query: "metrics",
+					OrgID: idPtr(MustIDBase16(orgTwoID)),
+				},
+			},
+			wants: wants{
+				names: []string{"labels:metrics"},
+			},
+		},
+		{
+			name:   "find resources of types",
+			fields: searchFields(),
+			args: args{
+				filter: influxdb.SearchFilter{
+					Query: "metrics",
+					Types: []influxdb.ResourceType{influxdb.TelegrafsResourceType},
+				},
+			},
+			wants: wants{
+				names: []string{"telegrafs:system metrics"},
+			},
+		},
+		{
+			name:   "queries require a word",
+			fields: searchFields(),
+			args: args{
+				filter: influxdb.SearchFilter{Query: " - "},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "search query must have at least one word",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			rs, err := s.Search(ctx, tt.args.filter)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(searchResultNames(rs), tt.wants.names); diff != "" {
+				t.Errorf("results are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// SearchIndex testing
+func SearchIndex(
+	init func(SearchFields, *testing.T) (SearchServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		// update changes the resources of the store before searching.
+		update func(context.Context, SearchServices) error
+		query  string
+	}
+	type wants struct {
+		names []string
+	}
+
+	tests := []struct {
+		name   string
+		fields SearchFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "renamed resources are found by their new name",
+			fields: searchFields(),
+			args: args{
+				update: func(ctx context.Context, s SearchServices) error {
+					_, err := s.UpdateBucket(ctx, MustIDBase16(bucketOneID), influxdb.BucketUpdate{
+						Name: strPtr("disk"),
+					})
+					return err
+				},
+				query: "disk",
+			},
+			wants: wants{
+				names: []string{"buckets:disk"},
+			},
+		},
+		{
+			name:   "renamed resources are not found by their old name",
+			fields: searchFields(),
+			args: args{
+				update: func(ctx context.Context, s SearchServices) error {
+					_, err := s.UpdateBucket(ctx, MustIDBase16(bucketOneID), influxdb.BucketUpdate{
+						Name: strPtr("disk"),
+					})
+					return err
+				},
+				query: "cpu",
+			},
+		},
+		{
+			name:   "deleted resources are not found",
+			fields: searchFields(),
+			args: args{
+				update: func(ctx context.Context, s SearchServices) error {
+					return s.DeleteTelegrafConfig(ctx, MustIDBase16(searchTelegrafOneID))
+				},
+				query: "metrics",
+			},
+			wants: wants{
+				names: []string{"buckets:cpu metrics", "labels:metrics"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			if err := tt.args.update(ctx, s); err != nil {
+				t.Fatalf("failed to update resources: %v", err)
+			}
+
+			rs, err := s.Search(ctx, influxdb.SearchFilter{Query: tt.args.query})
+			if err != nil {
+				t.Fatalf("failed to search: %v", err)
+			}
+			if diff := cmp.Diff(searchResultNames(rs), tt.wants.names); diff != "" {
+				t.Errorf("results are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -173,6 +173,16 @@ func PutSecret(
 			},
 			wants: wants{},
 		},
+		{
+			name:   "put secret whose length is not a multiple of three",
+			fields: SecretServiceFields{},
+			args: args{
+				orgID: platform.ID(1),
+				key:   "api_key",
+				value: "abc123xy",
+			},
+			wants: wants{},
+		},
 	}
 
 	for _, tt := range tests {
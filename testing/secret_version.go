@@ -0,0 +1,354 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+// SecretRotationFields will include the TimeGenerator, the hooks notified of rotated
+// secrets, and the values of the secrets of the organization to populate the store
+// with, oldest first.
+type SecretRotationFields struct {
+	TimeGenerator  influxdb.TimeGenerator
+	RotationHooks  []influxdb.SecretRotationHook
+	OrganizationID influxdb.ID
+	Secrets        map[string][]string
+}
+
+// SecretRotationServices are the secret service and the secret rotation service that
+// keeps the versions of its secrets.
+type SecretRotationServices interface {
+	influxdb.SecretService
+	influxdb.SecretRotationService
+}
+
+type secretRotationServiceF func(
+	init func(SecretRotationFields, *testing.T) (SecretRotationServices, func()),
+	t *testing.T,
+)
+
+// SecretRotationService tests all the service functions.
+func SecretRotationService(
+	init func(SecretRotationFields, *testing.T) (SecretRotationServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   secretRotationServiceF
+	}{
+		{
+			name: "RotateSecret",
+			fn:   RotateSecret,
+		},
+		{
+			name: "FindSecretVersions",
+			fn:   FindSecretVersions,
+		},
+		{
+			name: "LoadSecretVersion",
+			fn:   LoadSecretVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var secretVersionTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func newSecretVersions(k string, versions ...int) []*influxdb.SecretVersion {
+	vs := make([]*influxdb.SecretVersion, 0, len(versions))
+	for _, v := range versions {
+		vs = append(vs, &influxdb.SecretVersion{
+			OrgID:     MustIDBase16(orgOneID),
+			Key:       k,
+			Version:   v,
+			CreatedAt: secretVersionTime,
+		})
+	}
+	return vs
+}
+
+// RotateSecret testing
+func RotateSecret(
+	init func(SecretRotationFields, *testing.T) (SecretRotationServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		key   string
+		value string
+	}
+	type wants struct {
+		err     error
+		version *influxdb.SecretVersion
+		// rotated are the versions the rotation hooks are notified of.
+		rotated []*influxdb.SecretVersion
+		value   string
+	}
+
+	tests := []struct {
+		name   string
+		fields SecretRotationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "rotate a secret to a new version",
+			fields: SecretRotationFields{
+				TimeGenerator:  mock.TimeGenerator{FakeValue: secretVersionTime},
+				OrganizationID: MustIDBase16(orgOneID),
+				Secrets: map[string][]string{
+					"api_key": {"abc"},
+				},
+			},
+			args: args{
+				key:   "api_key",
+				value: "def",
+			},
+			wants: wants{
+				version: newSecretVersions("api_key", 2)[0],
+				rotated: newSecretVersions("api_key", 2),
+				value:   "def",
+			},
+		},
+		{
+			name: "missing secrets are not rotated",
+			fields: SecretRotationFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: secretVersionTime},
+			},
+			args: args{
+				key:   "api_key",
+				value: "def",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrSecretNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rotated []*influxdb.SecretVersion
+			tt.fields.RotationHooks = append(tt.fields.RotationHooks, func(ctx context.Context, v *influxdb.SecretVersion) {
+				rotated = append(rotated, v)
+			})
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			v, err := s.RotateSecret(ctx, MustIDBase16(orgOneID), tt.args.key, tt.args.value)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(v, tt.wants.version); diff != "" {
+				t.Errorf("secret versions are different -got/+want\ndiff %s", diff)
+			}
+			if diff := cmp.Diff(rotated, tt.wants.rotated); diff != "" {
+				t.Errorf("rotated secret versions are different -got/+want\ndiff %s", diff)
+			}
+			if tt.wants.err != nil {
+				return
+			}
+
+			val, err := s.LoadSecret(ctx, MustIDBase16(orgOneID), tt.args.key)
+			if err != nil {
+				t.Fatalf("failed to load secret: %v", err)
+			}
+			if val != tt.wants.value {
+				t.Errorf("expected secret value %q, got %q", tt.wants.value, val)
+			}
+		})
+	}
+}
+
+// FindSecretVersions testing
+func FindSecretVersions(
+	init func(SecretRotationFields, *testing.T) (SecretRotationServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		key string
+	}
+	type wants struct {
+		err      error
+		versions []*influxdb.SecretVersion
+	}
+
+	tests := []struct {
+		name   string
+		fields SecretRotationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "find the versions of a secret latest first",
+			fields: SecretRotationFields{
+				TimeGenerator:  mock.TimeGenerator{FakeValue: secretVersionTime},
+				OrganizationID: MustIDBase16(orgOneID),
+				Secrets: map[string][]string{
+					"api_key": {"abc", "def"},
+				},
+			},
+			args: args{
+				key: "api_key",
+			},
+			wants: wants{
+				versions: newSecretVersions("api_key", 2, 1),
+			},
+		},
+		{
+			name: "values equal to the latest version are not versioned",
+			fields: SecretRotationFields{
+				TimeGenerator:  mock.TimeGenerator{FakeValue: secretVersionTime},
+				OrganizationID: MustIDBase16(orgOneID),
+				Secrets: map[string][]string{
+					"api_key": {"abc", "abc", "def", "def"},
+				},
+			},
+			args: args{
+				key: "api_key",
+			},
+			wants: wants{
+				versions: newSecretVersions("api_key", 2, 1),
+			},
+		},
+		{
+			name: "only the latest versions are kept",
+			fields: SecretRotationFields{
+				TimeGenerator:  mock.TimeGenerator{FakeValue: secretVersionTime},
+				OrganizationID: MustIDBase16(orgOneID),
+				Secrets: map[string][]string{
+					"k": {"a", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"},
+				},
+			},
+			args: args{
+				key: "k",
+			},
+			wants: wants{
+				versions: newSecretVersions("k", 12, 11, 10, 9, 8, 7, 6, 5, 4, 3),
+			},
+		},
+		{
+			name: "versions of missing secrets are not found",
+			fields: SecretRotationFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: secretVersionTime},
+			},
+			args: args{
+				key: "api_key",
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrSecretNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			vs, err := s.FindSecretVersions(ctx, MustIDBase16(orgOneID), tt.args.key)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(vs, tt.wants.versions); diff != "" {
+				t.Errorf("secret versions are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// LoadSecretVersion testing
+func LoadSecretVersion(
+	init func(SecretRotationFields, *testing.T) (SecretRotationServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		key     string
+		version int
+	}
+	type wants struct {
+		err   error
+		value string
+	}
+
+	fields := func() SecretRotationFields {
+		return SecretRotationFields{
+			TimeGenerator:  mock.TimeGenerator{FakeValue: secretVersionTime},
+			OrganizationID: MustIDBase16(orgOneID),
+			Secrets: map[string][]string{
+				"api_key": {"abc", "def"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		fields SecretRotationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "load the first version of a secret",
+			fields: fields(),
+			args: args{
+				key:     "api_key",
+				version: 1,
+			},
+			wants: wants{
+				value: "abc",
+			},
+		},
+		{
+			name:   "load the latest version of a secret",
+			fields: fields(),
+			args: args{
+				key:     "api_key",
+				version: 2,
+			},
+			wants: wants{
+				value: "def",
+			},
+		},
+		{
+			name:   "missing versions are not found",
+			fields: fields(),
+			args: args{
+				key:     "api_key",
+				version: 3,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrSecretVersionNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			val, err := s.LoadSecretVersion(ctx, MustIDBase16(orgOneID), tt.args.key, tt.args.version)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if val != tt.wants.value {
+				t.Errorf("expected secret value %q, got %q", tt.wants.value, val)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	silenceOneID   = "020f755c3c08b000"
+	silenceTwoID   = "020f755c3c08b001"
+	silenceThreeID = "020f755c3c08b002"
+)
+
+// SilenceFields will include the IDGenerator, TimeGenerator, and the organizations,
+// checks and silences to populate the store with.
+type SilenceFields struct {
+	IDGenerator   influxdb.IDGenerator
+	TimeGenerator influxdb.TimeGenerator
+	Organizations []*influxdb.Organization
+	Checks        []*influxdb.Check
+	Silences      []*influxdb.Silence
+}
+
+type silenceServiceF func(
+	init func(SilenceFields, *testing.T) (influxdb.SilenceService, func()),
+	t *testing.T,
+)
+
+// SilenceService tests all the service functions.
+func SilenceService(
+	init func(SilenceFields, *testing.T) (influxdb.SilenceService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   silenceServiceF
+	}{
+		{
+			name: "CreateSilence",
+			fn:   CreateSilence,
+		},
+		{
+			name: "FindSilences",
+			fn:   FindSilences,
+		},
+		{
+			name: "ExpireSilence",
+			fn:   ExpireSilence,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var silenceHosts = []influxdb.TagRule{
+	{Key: "host", Value: "^web", Operator: influxdb.TagRuleEqualRegex},
+}
+
+// newSilence returns a silence of the web hosts of the organization between
+// the offsets from checkTime.
+func newSilence(id, orgID, comment string, startsAt, endsAt time.Duration) *influxdb.Silence {
+	sl := &influxdb.Silence{
+		OrgID:    MustIDBase16(orgID),
+		TagRules: silenceHosts,
+		StartsAt: checkTime.Add(startsAt),
+		EndsAt:   checkTime.Add(endsAt),
+		Comment:  comment,
+	}
+	if id != "" {
+		sl.ID = MustIDBase16(id)
+		sl.CRUDLog = influxdb.CRUDLog{
+			CreatedAt: checkTime,
+			UpdatedAt: checkTime,
+		}
+	}
+	return sl
+}
+
+// silenceFields returns the fields of a store with the active maintenance silence and
+// the pending upgrade silence of the first organization, and a check of the second.
+func silenceFields(t *testing.T) SilenceFields {
+	return SilenceFields{
+		IDGenerator:   mock.NewIDGenerator(silenceThreeID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: checkTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		Checks: []*influxdb.Check{
+			newDeadmanCheck(checkOneID, orgTwoID, "cpu"),
+		},
+		Silences: []*influxdb.Silence{
+			newSilence(silenceOneID, orgOneID, "maintenance", -time.Hour, time.Hour),
+			newSilence(silenceTwoID, orgOneID, "upgrade", time.Hour, 2*time.Hour),
+		},
+	}
+}
+
+// silencesOf returns the silences in the store.
+func silencesOf(ctx context.Context, s influxdb.SilenceService, t *testing.T) []*influxdb.Silence {
+	t.Helper()
+	ss, _, err := s.FindSilences(ctx, influxdb.SilenceFilter{})
+	if err != nil {
+		t.Fatalf("failed to retrieve silences: %v", err)
+	}
+	return ss
+}
+
+// CreateSilence testing
+func CreateSilence(
+	init func(SilenceFields, *testing.T) (influxdb.SilenceService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		userID  influxdb.ID
+		silence *influxdb.Silence
+	}
+	type wants struct {
+		err      error
+		silences []*influxdb.Silence
+	}
+
+	startsNow := newSilence("", orgOneID, "deploy", 0, time.Hour)
+	startsNow.StartsAt = time.Time{}
+	created := newSilence(silenceThreeID, orgOneID, "deploy", 0, time.Hour)
+	created.CreatedBy = MustIDBase16(userOneID)
+	withoutMatchers := newSilence("", orgOneID, "all", 0, time.Hour)
+	withoutMatchers.TagRules = nil
+	foreignCheck := newSilence("", orgOneID, "foreign", 0, time.Hour)
+	foreignCheck.CheckID = MustIDBase16(checkOneID)
+
+	tests := []struct {
+		name   string
+		fields SilenceFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "silences start now and are created by the user",
+			fields: silenceFields(t),
+			args: args{
+				userID:  MustIDBase16(userOneID),
+				silence: startsNow,
+			},
+			wants: wants{
+				silences: []*influxdb.Silence{
+					newSilence(silenceOneID, orgOneID, "maintenance", -time.Hour, time.Hour),
+					newSilence(silenceTwoID, orgOneID, "upgrade", time.Hour, 2*time.Hour),
+					created,
+				},
+			},
+		},
+		{
+			name:   "silences need matchers",
+			fields: silenceFields(t),
+			args: args{
+				silence: withoutMatchers,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "silence needs a checkID, a ruleID or at least one tag rule",
+				},
+				silences: []*influxdb.Silence{
+					newSilence(silenceOneID, orgOneID, "maintenance", -time.Hour, time.Hour),
+					newSilence(silenceTwoID, orgOneID, "upgrade", time.Hour, 2*time.Hour),
+				},
+			},
+		},
+		{
+			name:   "silences end after they start",
+			fields: silenceFields(t),
+			args: args{
+				silence: newSilence("", orgOneID, "past", 0, -time.Hour),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "silence must end after it starts",
+				},
+				silences: []*influxdb.Silence{
+					newSilence(silenceOneID, orgOneID, "maintenance", -time.Hour, time.Hour),
+					newSilence(silenceTwoID, orgOneID, "upgrade", time.Hour, 2*time.Hour),
+				},
+			},
+		},
+		{
+			name:   "silences cannot silence checks of other organizations",
+			fields: silenceFields(t),
+			args: args{
+				silence: foreignCheck,
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "check belongs to another organization",
+				},
+				silences: []*influxdb.Silence{
+					newSilence(silenceOneID, orgOneID, "maintenance", -time.Hour, time.Hour),
+					newSilence(silenceTwoID, orgOneID, "upgrade", time.Hour, 2*time.Hour),
+				},
+			},
+		},
+		{
+			name:   "silences of missing organizations are not found",
+			fields: silenceFields(t),
+			args: args{
+				silence: newSilence("", orgThreeID, "deploy", 0, time.Hour),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+				silences: []*influxdb.Silence{
+					newSilence(silenceOneID, orgOneID, "maintenance", -time.Hour, time.Hour),
+					newSilence(silenceTwoID, orgOneID, "upgrade", time.Hour, 2*time.Hour),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			createCtx := ctx
+			if tt.args.userID.Valid() {
+				createCtx = icontext.SetAuthorizer(ctx, &influxdb.Authorization{UserID: tt.args.userID})
+			}
+			err := s.CreateSilence(createCtx, tt.args.silence)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(silencesOf(ctx, s, t), tt.wants.silences); diff != "" {
+				t.Errorf("silences are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindSilences testing
+func FindSilences(
+	init func(SilenceFields, *testing.T) (influxdb.SilenceService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.SilenceFilter
+		opts   []influxdb.FindOptions
+	}
+	type wants struct {
+		err      error
+		silences []*influxdb.Silence
+	}
+
+	active := influxdb.SilenceActive
+	pending := influxdb.SilencePending
+	expired := influxdb.SilenceExpired
+	tests := []struct {
+		name   string
+		fields SilenceFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the active silences of an organization",
+			fields: silenceFields(t),
+			args: args{
+				filter: influxdb.SilenceFilter{OrgID: idPtr(MustIDBase16(orgOneID)), State: &active},
+			},
+			wants: wants{
+				silences: []*influxdb.Silence{
+					newSilence(silenceOneID, orgOneID, "maintenance", -time.Hour, time.Hour),
+				},
+			},
+		},
+		{
+			name:   "find the pending silences",
+			fields: silenceFields(t),
+			args: args{
+				filter: influxdb.SilenceFilter{State: &pending},
+			},
+			wants: wants{
+				silences: []*influxdb.Silence{
+					newSilence(silenceTwoID, orgOneID, "upgrade", time.Hour, 2*time.Hour),
+				},
+			},
+		},
+		{
+			name:   "find no expired silences",
+			fields: silenceFields(t),
+			args: args{
+				filter: influxdb.SilenceFilter{State: &expired},
+			},
+			wants: wants{
+				silences: []*influxdb.Silence{},
+			},
+		},
+		{
+			name:   "find a page of silences ordered by id",
+			fields: silenceFields(t),
+			args: args{
+				filter: influxdb.SilenceFilter{OrgID: idPtr(MustIDBase16(orgOneID))},
+				opts:   []influxdb.FindOptions{{Offset: 1, Limit: 1}},
+			},
+			wants: wants{
+				silences: []*influxdb.Silence{
+					newSilence(silenceTwoID, orgOneID, "upgrade", time.Hour, 2*time.Hour),
+				},
+			},
+		},
+		{
+			name:   "organizations without silences have none",
+			fields: silenceFields(t),
+			args: args{
+				filter: influxdb.SilenceFilter{OrgID: idPtr(MustIDBase16(orgTwoID))},
+			},
+			wants: wants{
+				silences: []*influxdb.Silence{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			ss, n, err := s.FindSilences(ctx, tt.args.filter, tt.args.opts...)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if n != len(tt.wants.silences) {
+				t.Errorf("expected %d silences, got %d", len(tt.wants.silences), n)
+			}
+			if diff := cmp.Diff(ss, tt.wants.silences); diff != "" {
+				t.Errorf("silences are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// ExpireSilence testing
+func ExpireSilence(
+	init func(SilenceFields, *testing.T) (influxdb.SilenceService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err     error
+		silence *influxdb.Silence
+	}
+
+	tests := []struct {
+		name   string
+		fields SilenceFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "expiring an active silence ends it now",
+			fields: silenceFields(t),
+			args: args{
+				id: MustIDBase16(silenceOneID),
+			},
+			wants: wants{
+				silence: newSilence(silenceOneID, orgOneID, "maintenance", -time.Hour, 0),
+			},
+		},
+		{
+			name:   "expiring a pending silence ends it before it starts",
+			fields: silenceFields(t),
+			args: args{
+				id: MustIDBase16(silenceTwoID),
+			},
+			wants: wants{
+				silence: newSilence(silenceTwoID, orgOneID, "upgrade", 0, 0),
+			},
+		},
+		{
+			name:   "missing silences are not found",
+			fields: silenceFields(t),
+			args: args{
+				id: MustIDBase16(silenceThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrSilenceNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			sl, err := s.ExpireSilence(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(sl, tt.wants.silence); diff != "" {
+				t.Errorf("silence is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
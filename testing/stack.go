@@ -0,0 +1,484 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	stackOneID   = "020f755c3c08c000"
+	stackTwoID   = "020f755c3c08c001"
+	stackThreeID = "020f755c3c08c002"
+)
+
+// StackFields will include the IDGenerator, TimeGenerator, and the organizations and
+// stacks to populate the store with.
+type StackFields struct {
+	IDGenerator   influxdb.IDGenerator
+	TimeGenerator influxdb.TimeGenerator
+	Organizations []*influxdb.Organization
+	Stacks        []*influxdb.Stack
+}
+
+type stackServiceF func(
+	init func(StackFields, *testing.T) (influxdb.StackService, func()),
+	t *testing.T,
+)
+
+// StackService tests all the service functions.
+func StackService(
+	init func(StackFields, *testing.T) (influxdb.StackService, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   stackServiceF
+	}{
+		{
+			name: "CreateStack",
+			fn:   CreateStack,
+		},
+		{
+			name: "FindStacks",
+			fn:   FindStacks,
+		},
+		{
+			name: "UpdateStack",
+			fn:   UpdateStack,
+		},
+		{
+			name: "DeleteStack",
+			fn:   DeleteStack,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var stackTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+func newStack(id, orgID, name string, resources ...influxdb.StackResource) *influxdb.Stack {
+	st := &influxdb.Stack{
+		OrgID:     MustIDBase16(orgID),
+		Name:      name,
+		Resources: resources,
+	}
+	if id != "" {
+		st.ID = MustIDBase16(id)
+		st.CRUDLog = influxdb.CRUDLog{
+			CreatedAt: stackTime,
+			UpdatedAt: stackTime,
+		}
+		if st.Resources == nil {
+			st.Resources = []influxdb.StackResource{}
+		}
+	}
+	return st
+}
+
+var stackBucketResource = influxdb.StackResource{
+	Kind: "Bucket",
+	Name: "telegraf",
+	ID:   MustIDBase16(bucketOneID),
+}
+
+// stackFields returns the fields of a store with the system stack, that owns the
+// telegraf bucket, and the empty monitoring stack of the first organization.
+func stackFields(t *testing.T) StackFields {
+	return StackFields{
+		IDGenerator:   mock.NewIDGenerator(stackThreeID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: stackTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		Stacks: []*influxdb.Stack{
+			newStack(stackOneID, orgOneID, "system", stackBucketResource),
+			newStack(stackTwoID, orgOneID, "monitoring"),
+		},
+	}
+}
+
+// stacksOf returns the stacks in the store.
+func stacksOf(ctx context.Context, s influxdb.StackService, t *testing.T) []*influxdb.Stack {
+	t.Helper()
+	ss, _, err := s.FindStacks(ctx, influxdb.StackFilter{})
+	if err != nil {
+		t.Fatalf("failed to retrieve stacks: %v", err)
+	}
+	return ss
+}
+
+// CreateStack testing
+func CreateStack(
+	init func(StackFields, *testing.T) (influxdb.StackService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		stack *influxdb.Stack
+	}
+	type wants struct {
+		err    error
+		stacks []*influxdb.Stack
+	}
+
+	tests := []struct {
+		name   string
+		fields StackFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "create a stack without resources",
+			fields: stackFields(t),
+			args: args{
+				stack: newStack("", orgOneID, "dashboards"),
+			},
+			wants: wants{
+				stacks: []*influxdb.Stack{
+					newStack(stackOneID, orgOneID, "system", stackBucketResource),
+					newStack(stackTwoID, orgOneID, "monitoring"),
+					newStack(stackThreeID, orgOneID, "dashboards"),
+				},
+			},
+		},
+		{
+			name:   "stacks of different organizations share names",
+			fields: stackFields(t),
+			args: args{
+				stack: newStack("", orgTwoID, "system"),
+			},
+			wants: wants{
+				stacks: []*influxdb.Stack{
+					newStack(stackOneID, orgOneID, "system", stackBucketResource),
+					newStack(stackTwoID, orgOneID, "monitoring"),
+					newStack(stackThreeID, orgTwoID, "system"),
+				},
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: stackFields(t),
+			args: args{
+				stack: newStack("", orgOneID, "system"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "stack with name system already exists",
+				},
+				stacks: []*influxdb.Stack{
+					newStack(stackOneID, orgOneID, "system", stackBucketResource),
+					newStack(stackTwoID, orgOneID, "monitoring"),
+				},
+			},
+		},
+		{
+			name:   "stacks need a name",
+			fields: stackFields(t),
+			args: args{
+				stack: newStack("", orgOneID, " "),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "stack name is required",
+				},
+				stacks: []*influxdb.Stack{
+					newStack(stackOneID, orgOneID, "system", stackBucketResource),
+					newStack(stackTwoID, orgOneID, "monitoring"),
+				},
+			},
+		},
+		{
+			name:   "stacks of missing organizations are not found",
+			fields: stackFields(t),
+			args: args{
+				stack: newStack("", orgThreeID, "dashboards"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+				stacks: []*influxdb.Stack{
+					newStack(stackOneID, orgOneID, "system", stackBucketResource),
+					newStack(stackTwoID, orgOneID, "monitoring"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateStack(ctx, tt.args.stack)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(stacksOf(ctx, s, t), tt.wants.stacks); diff != "" {
+				t.Errorf("stacks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindStacks testing
+func FindStacks(
+	init func(StackFields, *testing.T) (influxdb.StackService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.StackFilter
+		opts   []influxdb.FindOptions
+	}
+	type wants struct {
+		err    error
+		stacks []*influxdb.Stack
+	}
+
+	tests := []struct {
+		name   string
+		fields StackFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the stacks of an organization",
+			fields: stackFields(t),
+			args: args{
+				filter: influxdb.StackFilter{OrgID: idPtr(MustIDBase16(orgOneID))},
+			},
+			wants: wants{
+				stacks: []*influxdb.Stack{
+					newStack(stackOneID, orgOneID, "system", stackBucketResource),
+					newStack(stackTwoID, orgOneID, "monitoring"),
+				},
+			},
+		},
+		{
+			name:   "find stacks by name",
+			fields: stackFields(t),
+			args: args{
+				filter: influxdb.StackFilter{Name: strPtr("monitoring")},
+			},
+			wants: wants{
+				stacks: []*influxdb.Stack{
+					newStack(stackTwoID, orgOneID, "monitoring"),
+				},
+			},
+		},
+		{
+			name:   "find a page of stacks ordered by id",
+			fields: stackFields(t),
+			args: args{
+				opts: []influxdb.FindOptions{{Offset: 1, Limit: 1}},
+			},
+			wants: wants{
+				stacks: []*influxdb.Stack{
+					newStack(stackTwoID, orgOneID, "monitoring"),
+				},
+			},
+		},
+		{
+			name:   "organizations without stacks have none",
+			fields: stackFields(t),
+			args: args{
+				filter: influxdb.StackFilter{OrgID: idPtr(MustIDBase16(orgTwoID))},
+			},
+			wants: wants{
+				stacks: []*influxdb.Stack{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			ss, n, err := s.FindStacks(ctx, tt.args.filter, tt.args.opts...)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if n != len(tt.wants.stacks) {
+				t.Errorf("expected %d stacks, got %d", len(tt.wants.stacks), n)
+			}
+			if diff := cmp.Diff(ss, tt.wants.stacks); diff != "" {
+				t.Errorf("stacks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateStack testing
+func UpdateStack(
+	init func(StackFields, *testing.T) (influxdb.StackService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  influxdb.ID
+		upd influxdb.StackUpdate
+	}
+	type wants struct {
+		err   error
+		stack *influxdb.Stack
+	}
+
+	pkg := json.RawMessage(`{"apiVersion":"0.1.0"}`)
+	dashboard := influxdb.StackResource{Kind: "Dashboard", Name: "hosts", ID: MustIDBase16(dashOneID)}
+	applied := newStack(stackTwoID, orgOneID, "monitoring", dashboard)
+	applied.Package = pkg
+
+	tests := []struct {
+		name   string
+		fields StackFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "record the package applied and the resources it owns",
+			fields: stackFields(t),
+			args: args{
+				id: MustIDBase16(stackTwoID),
+				upd: influxdb.StackUpdate{
+					Package:   pkg,
+					Resources: []influxdb.StackResource{dashboard},
+				},
+			},
+			wants: wants{
+				stack: applied,
+			},
+		},
+		{
+			name:   "resources are kept unless replaced",
+			fields: stackFields(t),
+			args: args{
+				id:  MustIDBase16(stackOneID),
+				upd: influxdb.StackUpdate{Name: strPtr("platform")},
+			},
+			wants: wants{
+				stack: newStack(stackOneID, orgOneID, "platform", stackBucketResource),
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: stackFields(t),
+			args: args{
+				id:  MustIDBase16(stackTwoID),
+				upd: influxdb.StackUpdate{Name: strPtr("system")},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "stack with name system already exists",
+				},
+			},
+		},
+		{
+			name:   "missing stacks are not found",
+			fields: stackFields(t),
+			args: args{
+				id:  MustIDBase16(stackThreeID),
+				upd: influxdb.StackUpdate{Name: strPtr("platform")},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrStackNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			st, err := s.UpdateStack(ctx, tt.args.id, tt.args.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(st, tt.wants.stack); diff != "" {
+				t.Errorf("stack is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteStack testing
+func DeleteStack(
+	init func(StackFields, *testing.T) (influxdb.StackService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err    error
+		stacks []*influxdb.Stack
+	}
+
+	tests := []struct {
+		name   string
+		fields StackFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "delete a stack",
+			fields: stackFields(t),
+			args: args{
+				id: MustIDBase16(stackOneID),
+			},
+			wants: wants{
+				stacks: []*influxdb.Stack{
+					newStack(stackTwoID, orgOneID, "monitoring"),
+				},
+			},
+		},
+		{
+			name:   "missing stacks are not found",
+			fields: stackFields(t),
+			args: args{
+				id: MustIDBase16(stackThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrStackNotFound,
+				},
+				stacks: []*influxdb.Stack{
+					newStack(stackOneID, orgOneID, "system", stackBucketResource),
+					newStack(stackTwoID, orgOneID, "monitoring"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteStack(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(stacksOf(ctx, s, t), tt.wants.stacks); diff != "" {
+				t.Errorf("stacks are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
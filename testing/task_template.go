@@ -0,0 +1,549 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	taskTemplateOneID   = "020f755c3c082000"
+	taskTemplateTwoID   = "020f755c3c082001"
+	taskTemplateThreeID = "020f755c3c082002"
+)
+
+// TaskTemplateFields will include the IDGenerator, and the organizations and task
+// templates to populate the store with.
+type TaskTemplateFields struct {
+	IDGenerator   influxdb.IDGenerator
+	TimeGenerator influxdb.TimeGenerator
+	Organizations []*influxdb.Organization
+	TaskTemplates []*influxdb.TaskTemplate
+}
+
+type taskTemplateServiceF func(
+	init func(TaskTemplateFields, *testing.T) (influxdb.TaskTemplateService, func()),
+	t *testing.T,
+)
+
+// TaskTemplateService tests all the service functions.
+func TaskTemplateService(
+	init func(TaskTemplateFields, *testing.T) (influxdb.TaskTemplateService, func()),
+	t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   taskTemplateServiceF
+	}{
+		{
+			name: "CreateTaskTemplate",
+			fn:   CreateTaskTemplate,
+		},
+		{
+			name: "FindTaskTemplateByID",
+			fn:   FindTaskTemplateByID,
+		},
+		{
+			name: "FindTaskTemplates",
+			fn:   FindTaskTemplates,
+		},
+		{
+			name: "UpdateTaskTemplate",
+			fn:   UpdateTaskTemplate,
+		},
+		{
+			name: "DeleteTaskTemplate",
+			fn:   DeleteTaskTemplate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var taskTemplateTime = time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+
+func newTaskTemplate(id, orgID, name string) *influxdb.TaskTemplate {
+	tt := &influxdb.TaskTemplate{
+		OrganizationID: MustIDBase16(orgID),
+		Name:           name,
+		Flux:           `from(bucket: "{{ .bucket }}")`,
+		Params: []influxdb.TaskTemplateParam{
+			{Name: "bucket", Type: influxdb.TaskTemplateParamString},
+		},
+		CRUDLog: influxdb.CRUDLog{
+			CreatedAt: taskTemplateTime,
+			UpdatedAt: taskTemplateTime,
+		},
+	}
+	if id != "" {
+		tt.ID = MustIDBase16(id)
+	}
+	return tt
+}
+
+// CreateTaskTemplate testing
+func CreateTaskTemplate(
+	init func(TaskTemplateFields, *testing.T) (influxdb.TaskTemplateService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		taskTemplate *influxdb.TaskTemplate
+	}
+	type wants struct {
+		err           error
+		taskTemplates []*influxdb.TaskTemplate
+	}
+
+	tests := []struct {
+		name   string
+		fields TaskTemplateFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "create a task template",
+			fields: TaskTemplateFields{
+				IDGenerator:   mock.NewIDGenerator(taskTemplateTwoID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+				},
+			},
+			args: args{
+				taskTemplate: newTaskTemplate("", orgOneID, "rollup"),
+			},
+			wants: wants{
+				taskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+					newTaskTemplate(taskTemplateTwoID, orgOneID, "rollup"),
+				},
+			},
+		},
+		{
+			name: "templates referencing undeclared params are invalid",
+			fields: TaskTemplateFields{
+				IDGenerator:   mock.NewIDGenerator(taskTemplateOneID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+			},
+			args: args{
+				taskTemplate: &influxdb.TaskTemplate{
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "bad",
+					Flux:           `from(bucket: "{{ .missing }}")`,
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "failed to instantiate task template",
+				},
+				taskTemplates: []*influxdb.TaskTemplate{},
+			},
+		},
+		{
+			name: "templates of a missing organization are not found",
+			fields: TaskTemplateFields{
+				IDGenerator:   mock.NewIDGenerator(taskTemplateOneID, t),
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+			},
+			args: args{
+				taskTemplate: newTaskTemplate("", orgTwoID, "downsample"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+				taskTemplates: []*influxdb.TaskTemplate{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateTaskTemplate(ctx, tt.args.taskTemplate)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			tts, err := s.FindTaskTemplates(ctx, influxdb.TaskTemplateFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve task templates: %v", err)
+			}
+			if diff := cmp.Diff(tts, tt.wants.taskTemplates); diff != "" {
+				t.Errorf("task templates are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindTaskTemplateByID testing
+func FindTaskTemplateByID(
+	init func(TaskTemplateFields, *testing.T) (influxdb.TaskTemplateService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err          error
+		taskTemplate *influxdb.TaskTemplate
+	}
+
+	tests := []struct {
+		name   string
+		fields TaskTemplateFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "find a task template by id",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+					newTaskTemplate(taskTemplateTwoID, orgOneID, "rollup"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(taskTemplateTwoID),
+			},
+			wants: wants{
+				taskTemplate: newTaskTemplate(taskTemplateTwoID, orgOneID, "rollup"),
+			},
+		},
+		{
+			name: "missing task templates are not found",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(taskTemplateThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTaskTemplateNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			taskTemplate, err := s.FindTaskTemplateByID(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(taskTemplate, tt.wants.taskTemplate); diff != "" {
+				t.Errorf("task template is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindTaskTemplates testing
+func FindTaskTemplates(
+	init func(TaskTemplateFields, *testing.T) (influxdb.TaskTemplateService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.TaskTemplateFilter
+	}
+	type wants struct {
+		taskTemplates []*influxdb.TaskTemplate
+	}
+
+	tests := []struct {
+		name   string
+		fields TaskTemplateFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "find the task templates of an organization",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+					{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+					newTaskTemplate(taskTemplateTwoID, orgTwoID, "downsample"),
+					newTaskTemplate(taskTemplateThreeID, orgOneID, "rollup"),
+				},
+			},
+			args: args{
+				filter: influxdb.TaskTemplateFilter{
+					OrganizationID: idPtr(MustIDBase16(orgOneID)),
+				},
+			},
+			wants: wants{
+				taskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+					newTaskTemplate(taskTemplateThreeID, orgOneID, "rollup"),
+				},
+			},
+		},
+		{
+			name: "organizations without task templates have none",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+					{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+				},
+			},
+			args: args{
+				filter: influxdb.TaskTemplateFilter{
+					OrganizationID: idPtr(MustIDBase16(orgTwoID)),
+				},
+			},
+			wants: wants{
+				taskTemplates: []*influxdb.TaskTemplate{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			tts, err := s.FindTaskTemplates(ctx, tt.args.filter)
+			if err != nil {
+				t.Fatalf("failed to retrieve task templates: %v", err)
+			}
+			if diff := cmp.Diff(tts, tt.wants.taskTemplates); diff != "" {
+				t.Errorf("task templates are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateTaskTemplate testing
+func UpdateTaskTemplate(
+	init func(TaskTemplateFields, *testing.T) (influxdb.TaskTemplateService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  influxdb.ID
+		upd influxdb.TaskTemplateUpdate
+	}
+	type wants struct {
+		err          error
+		taskTemplate *influxdb.TaskTemplate
+	}
+
+	badFlux := `from(bucket: {{ .bucket }}`
+
+	tests := []struct {
+		name   string
+		fields TaskTemplateFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "rename a task template",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(taskTemplateOneID),
+				upd: influxdb.TaskTemplateUpdate{
+					Name: strPtr("downsample-hourly"),
+				},
+			},
+			wants: wants{
+				taskTemplate: newTaskTemplate(taskTemplateOneID, orgOneID, "downsample-hourly"),
+			},
+		},
+		{
+			name: "updates to invalid flux are rejected",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(taskTemplateOneID),
+				upd: influxdb.TaskTemplateUpdate{
+					Flux: &badFlux,
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "task template does not instantiate to valid flux",
+				},
+			},
+		},
+		{
+			name: "updates of missing task templates are not found",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+			},
+			args: args{
+				id: MustIDBase16(taskTemplateOneID),
+				upd: influxdb.TaskTemplateUpdate{
+					Name: strPtr("downsample-hourly"),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTaskTemplateNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			taskTemplate, err := s.UpdateTaskTemplate(ctx, tt.args.id, tt.args.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(taskTemplate, tt.wants.taskTemplate); diff != "" {
+				t.Errorf("task template is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteTaskTemplate testing
+func DeleteTaskTemplate(
+	init func(TaskTemplateFields, *testing.T) (influxdb.TaskTemplateService, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err           error
+		taskTemplates []*influxdb.TaskTemplate
+	}
+
+	tests := []struct {
+		name   string
+		fields TaskTemplateFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "delete a task template",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+					newTaskTemplate(taskTemplateTwoID, orgOneID, "rollup"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(taskTemplateOneID),
+			},
+			wants: wants{
+				taskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateTwoID, orgOneID, "rollup"),
+				},
+			},
+		},
+		{
+			name: "deletes of missing task templates are not found",
+			fields: TaskTemplateFields{
+				TimeGenerator: mock.TimeGenerator{FakeValue: taskTemplateTime},
+				Organizations: []*influxdb.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				TaskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+				},
+			},
+			args: args{
+				id: MustIDBase16(taskTemplateTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTaskTemplateNotFound,
+				},
+				taskTemplates: []*influxdb.TaskTemplate{
+					newTaskTemplate(taskTemplateOneID, orgOneID, "downsample"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteTaskTemplate(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			tts, err := s.FindTaskTemplates(ctx, influxdb.TaskTemplateFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve task templates: %v", err)
+			}
+			if diff := cmp.Diff(tts, tt.wants.taskTemplates); diff != "" {
+				t.Errorf("task templates are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,713 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+)
+
+const (
+	teamOneID   = "020f755c3c084000"
+	teamTwoID   = "020f755c3c084001"
+	teamThreeID = "020f755c3c084002"
+)
+
+// TeamFields will include the IDGenerator, TimeGenerator, and the organizations,
+// users, buckets, teams and user resource mappings to populate the store with.
+type TeamFields struct {
+	IDGenerator          influxdb.IDGenerator
+	TimeGenerator        influxdb.TimeGenerator
+	Organizations        []*influxdb.Organization
+	Users                []*influxdb.User
+	Buckets              []*influxdb.Bucket
+	Teams                []*influxdb.Team
+	UserResourceMappings []*influxdb.UserResourceMapping
+}
+
+// TeamServices are the team service and the services that map teams to their users and
+// resources.
+type TeamServices interface {
+	influxdb.TeamService
+	influxdb.UserResourceMappingService
+	influxdb.UserPermissionService
+}
+
+type teamServiceF func(
+	init func(TeamFields, *testing.T) (TeamServices, func()),
+	t *testing.T,
+)
+
+// TeamService tests all the service functions.
+func TeamService(
+	init func(TeamFields, *testing.T) (TeamServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   teamServiceF
+	}{
+		{
+			name: "CreateTeam",
+			fn:   CreateTeam,
+		},
+		{
+			name: "FindTeams",
+			fn:   FindTeams,
+		},
+		{
+			name: "UpdateTeam",
+			fn:   UpdateTeam,
+		},
+		{
+			name: "DeleteTeam",
+			fn:   DeleteTeam,
+		},
+		{
+			name: "CreateTeamUserResourceMapping",
+			fn:   CreateTeamUserResourceMapping,
+		},
+		{
+			name: "FindTeamUserPermissions",
+			fn:   FindTeamUserPermissions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var teamTime = time.Date(2006, 5, 4, 1, 2, 3, 0, time.UTC)
+
+func newTeam(id, orgID, name string) *influxdb.Team {
+	team := &influxdb.Team{
+		OrgID: MustIDBase16(orgID),
+		Name:  name,
+	}
+	if id != "" {
+		team.ID = MustIDBase16(id)
+		team.CRUDLog = influxdb.CRUDLog{
+			CreatedAt: teamTime,
+			UpdatedAt: teamTime,
+		}
+	}
+	return team
+}
+
+// teamFields returns the fields of a store with the ops and dev teams of the
+// first organization, and the ops team of the second one.
+func teamFields(t *testing.T) TeamFields {
+	return TeamFields{
+		IDGenerator:   mock.NewIDGenerator(teamThreeID, t),
+		TimeGenerator: mock.TimeGenerator{FakeValue: teamTime},
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		Users: []*influxdb.User{
+			{ID: MustIDBase16(userOneID), Name: "user1"},
+		},
+		Buckets: []*influxdb.Bucket{
+			{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "telegraf"},
+		},
+		Teams: []*influxdb.Team{
+			newTeam(teamOneID, orgOneID, "ops"),
+			newTeam(teamTwoID, orgOneID, "dev"),
+		},
+	}
+}
+
+// teamMappings returns the mappings of the first user to the ops team, and of the
+// ops team to the first organization.
+func teamMappings() []*influxdb.UserResourceMapping {
+	return []*influxdb.UserResourceMapping{
+		{
+			UserID:       MustIDBase16(userOneID),
+			UserType:     influxdb.Member,
+			MappingType:  influxdb.UserMappingType,
+			ResourceType: influxdb.TeamsResourceType,
+			ResourceID:   MustIDBase16(teamOneID),
+		},
+		{
+			UserID:       MustIDBase16(teamOneID),
+			UserType:     influxdb.Member,
+			MappingType:  influxdb.TeamMappingType,
+			ResourceType: influxdb.OrgsResourceType,
+			ResourceID:   MustIDBase16(orgOneID),
+		},
+	}
+}
+
+// CreateTeam testing
+func CreateTeam(
+	init func(TeamFields, *testing.T) (TeamServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		team *influxdb.Team
+	}
+	type wants struct {
+		err   error
+		teams []*influxdb.Team
+	}
+
+	tests := []struct {
+		name   string
+		fields TeamFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "create a team of an organization",
+			fields: teamFields(t),
+			args: args{
+				team: newTeam("", orgOneID, "qa"),
+			},
+			wants: wants{
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamTwoID, orgOneID, "dev"),
+					newTeam(teamThreeID, orgOneID, "qa"),
+				},
+			},
+		},
+		{
+			name:   "teams of different organizations share names",
+			fields: teamFields(t),
+			args: args{
+				team: newTeam("", orgTwoID, "ops"),
+			},
+			wants: wants{
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamTwoID, orgOneID, "dev"),
+					newTeam(teamThreeID, orgTwoID, "ops"),
+				},
+			},
+		},
+		{
+			name:   "names are unique within an organization",
+			fields: teamFields(t),
+			args: args{
+				team: newTeam("", orgOneID, "ops"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "team with name ops already exists",
+				},
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamTwoID, orgOneID, "dev"),
+				},
+			},
+		},
+		{
+			name:   "teams of missing organizations are not found",
+			fields: teamFields(t),
+			args: args{
+				team: newTeam("", orgThreeID, "qa"),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  "organization not found",
+				},
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamTwoID, orgOneID, "dev"),
+				},
+			},
+		},
+		{
+			name:   "teams require a name",
+			fields: teamFields(t),
+			args: args{
+				team: newTeam("", orgOneID, " "),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "team name is required",
+				},
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamTwoID, orgOneID, "dev"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateTeam(ctx, tt.args.team)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			teams, _, err := s.FindTeams(ctx, influxdb.TeamFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve teams: %v", err)
+			}
+			if diff := cmp.Diff(teams, tt.wants.teams); diff != "" {
+				t.Errorf("teams are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindTeams testing
+func FindTeams(
+	init func(TeamFields, *testing.T) (TeamServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.TeamFilter
+	}
+	type wants struct {
+		teams []*influxdb.Team
+	}
+
+	fields := func() TeamFields {
+		f := teamFields(t)
+		f.Teams = append(f.Teams, newTeam(teamThreeID, orgTwoID, "ops"))
+		return f
+	}
+
+	tests := []struct {
+		name   string
+		fields TeamFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find all teams",
+			fields: fields(),
+			wants: wants{
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamTwoID, orgOneID, "dev"),
+					newTeam(teamThreeID, orgTwoID, "ops"),
+				},
+			},
+		},
+		{
+			name:   "find the teams of an organization",
+			fields: fields(),
+			args: args{
+				filter: influxdb.TeamFilter{
+					OrgID: idPtr(MustIDBase16(orgOneID)),
+				},
+			},
+			wants: wants{
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamTwoID, orgOneID, "dev"),
+				},
+			},
+		},
+		{
+			name:   "find teams by name",
+			fields: fields(),
+			args: args{
+				filter: influxdb.TeamFilter{
+					Name: strPtr("ops"),
+				},
+			},
+			wants: wants{
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamThreeID, orgTwoID, "ops"),
+				},
+			},
+		},
+		{
+			name:   "find a team by ID",
+			fields: fields(),
+			args: args{
+				filter: influxdb.TeamFilter{
+					ID: idPtr(MustIDBase16(teamTwoID)),
+				},
+			},
+			wants: wants{
+				teams: []*influxdb.Team{
+					newTeam(teamTwoID, orgOneID, "dev"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			teams, n, err := s.FindTeams(ctx, tt.args.filter)
+			if err != nil {
+				t.Fatalf("failed to retrieve teams: %v", err)
+			}
+			if n != len(tt.wants.teams) {
+				t.Errorf("expected %d teams, got %d", len(tt.wants.teams), n)
+			}
+			if diff := cmp.Diff(teams, tt.wants.teams); diff != "" {
+				t.Errorf("teams are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateTeam testing
+func UpdateTeam(
+	init func(TeamFields, *testing.T) (TeamServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  influxdb.ID
+		upd influxdb.TeamUpdate
+	}
+	type wants struct {
+		err  error
+		team *influxdb.Team
+	}
+
+	tests := []struct {
+		name   string
+		fields TeamFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "rename a team",
+			fields: teamFields(t),
+			args: args{
+				id: MustIDBase16(teamOneID),
+				upd: influxdb.TeamUpdate{
+					Name: strPtr("operations"),
+				},
+			},
+			wants: wants{
+				team: newTeam(teamOneID, orgOneID, "operations"),
+			},
+		},
+		{
+			name:   "teams can not be renamed to the name of another team",
+			fields: teamFields(t),
+			args: args{
+				id: MustIDBase16(teamOneID),
+				upd: influxdb.TeamUpdate{
+					Name: strPtr("dev"),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "team with name dev already exists",
+				},
+			},
+		},
+		{
+			name:   "updates of missing teams are not found",
+			fields: teamFields(t),
+			args: args{
+				id: MustIDBase16(teamThreeID),
+				upd: influxdb.TeamUpdate{
+					Name: strPtr("qa"),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTeamNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			team, err := s.UpdateTeam(ctx, tt.args.id, tt.args.upd)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(team, tt.wants.team); diff != "" {
+				t.Errorf("team is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteTeam testing
+func DeleteTeam(
+	init func(TeamFields, *testing.T) (TeamServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err                  error
+		teams                []*influxdb.Team
+		userResourceMappings []*influxdb.UserResourceMapping
+	}
+
+	fields := func() TeamFields {
+		f := teamFields(t)
+		f.UserResourceMappings = teamMappings()
+		return f
+	}
+
+	tests := []struct {
+		name   string
+		fields TeamFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "deleting a team deletes its users and its mappings",
+			fields: fields(),
+			args: args{
+				id: MustIDBase16(teamOneID),
+			},
+			wants: wants{
+				teams: []*influxdb.Team{
+					newTeam(teamTwoID, orgOneID, "dev"),
+				},
+				userResourceMappings: []*influxdb.UserResourceMapping{},
+			},
+		},
+		{
+			name:   "deleting missing teams is not found",
+			fields: fields(),
+			args: args{
+				id: MustIDBase16(teamThreeID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTeamNotFound,
+				},
+				teams: []*influxdb.Team{
+					newTeam(teamOneID, orgOneID, "ops"),
+					newTeam(teamTwoID, orgOneID, "dev"),
+				},
+				// Mapping the team to the organization maps it to the buckets of
+				// the organization.
+				userResourceMappings: []*influxdb.UserResourceMapping{
+					{
+						UserID:       MustIDBase16(teamOneID),
+						UserType:     influxdb.Member,
+						MappingType:  influxdb.TeamMappingType,
+						ResourceType: influxdb.BucketsResourceType,
+						ResourceID:   MustIDBase16(bucketOneID),
+					},
+					teamMappings()[1],
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteTeam(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			teams, _, err := s.FindTeams(ctx, influxdb.TeamFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve teams: %v", err)
+			}
+			if diff := cmp.Diff(teams, tt.wants.teams); diff != "" {
+				t.Errorf("teams are different -got/+want\ndiff %s", diff)
+			}
+
+			ms, _, err := s.FindUserResourceMappings(ctx, influxdb.UserResourceMappingFilter{
+				UserID: MustIDBase16(teamOneID),
+			})
+			if err != nil {
+				t.Fatalf("failed to retrieve user resource mappings: %v", err)
+			}
+			if diff := cmp.Diff(ms, tt.wants.userResourceMappings); diff != "" {
+				t.Errorf("mappings of the team are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// CreateTeamUserResourceMapping testing
+func CreateTeamUserResourceMapping(
+	init func(TeamFields, *testing.T) (TeamServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		mapping *influxdb.UserResourceMapping
+	}
+	type wants struct {
+		err error
+	}
+
+	tests := []struct {
+		name   string
+		fields TeamFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "users are members of teams",
+			fields: teamFields(t),
+			args: args{
+				mapping: teamMappings()[0],
+			},
+		},
+		{
+			name:   "teams are mapped to resources",
+			fields: teamFields(t),
+			args: args{
+				mapping: teamMappings()[1],
+			},
+		},
+		{
+			name:   "only users are members of teams",
+			fields: teamFields(t),
+			args: args{
+				mapping: &influxdb.UserResourceMapping{
+					UserID:       MustIDBase16(orgOneID),
+					UserType:     influxdb.Member,
+					MappingType:  influxdb.OrgMappingType,
+					ResourceType: influxdb.TeamsResourceType,
+					ResourceID:   MustIDBase16(teamOneID),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Err:  influxdb.ErrInvalidTeamMapping,
+				},
+			},
+		},
+		{
+			name:   "mappings of missing teams are not found",
+			fields: teamFields(t),
+			args: args{
+				mapping: &influxdb.UserResourceMapping{
+					UserID:       MustIDBase16(teamThreeID),
+					UserType:     influxdb.Member,
+					MappingType:  influxdb.TeamMappingType,
+					ResourceType: influxdb.BucketsResourceType,
+					ResourceID:   MustIDBase16(bucketOneID),
+				},
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTeamNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateUserResourceMapping(ctx, tt.args.mapping)
+			ErrorsEqual(t, err, tt.wants.err)
+		})
+	}
+}
+
+// FindTeamUserPermissions testing
+func FindTeamUserPermissions(
+	init func(TeamFields, *testing.T) (TeamServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		userID influxdb.ID
+		// deleteTeam is the team that is deleted before finding the permissions
+		// of the user, if it is valid.
+		deleteTeam influxdb.ID
+	}
+	type wants struct {
+		readBucket bool
+	}
+
+	fields := func() TeamFields {
+		f := teamFields(t)
+		f.UserResourceMappings = teamMappings()
+		return f
+	}
+
+	tests := []struct {
+		name   string
+		fields TeamFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "users of a team read the buckets of organizations shared with the team",
+			fields: fields(),
+			args: args{
+				userID: MustIDBase16(userOneID),
+			},
+			wants: wants{
+				readBucket: true,
+			},
+		},
+		{
+			name:   "users lose the permissions of deleted teams",
+			fields: fields(),
+			args: args{
+				userID:     MustIDBase16(userOneID),
+				deleteTeam: MustIDBase16(teamOneID),
+			},
+		},
+		{
+			name:   "users of other teams do not get the permissions",
+			fields: teamFields(t),
+			args: args{
+				userID: MustIDBase16(userOneID),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			if tt.args.deleteTeam.Valid() {
+				if err := s.DeleteTeam(ctx, tt.args.deleteTeam); err != nil {
+					t.Fatalf("failed to delete team: %v", err)
+				}
+			}
+
+			ps, err := s.FindUserPermissions(ctx, tt.args.userID)
+			if err != nil {
+				t.Fatalf("failed to retrieve user permissions: %v", err)
+			}
+			readBucket, err := influxdb.NewPermissionAtID(MustIDBase16(bucketOneID), influxdb.ReadAction, influxdb.BucketsResourceType, MustIDBase16(orgOneID))
+			if err != nil {
+				t.Fatal(err)
+			}
+			auth := &influxdb.Authorization{Status: influxdb.Active, Permissions: ps}
+			if got := auth.Allowed(*readBucket); got != tt.wants.readBucket {
+				t.Errorf("expected reading the bucket to be allowed %t, got %t: %v", tt.wants.readBucket, got, ps)
+			}
+		})
+	}
+}
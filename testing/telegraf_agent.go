@@ -0,0 +1,368 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/mock"
+	"github.com/influxdata/influxdb/telegraf/plugins/inputs"
+)
+
+const (
+	agentConfigOneID   = "020f755c3c08d000"
+	agentConfigTwoID   = "020f755c3c08d001"
+	agentConfigThreeID = "020f755c3c08d002"
+)
+
+// TelegrafAgentFields will include the TimeGenerator, and the telegraf configs and
+// agents to populate the store with. The agents check in at their LastCheckIn.
+type TelegrafAgentFields struct {
+	TimeGenerator   influxdb.TimeGenerator
+	TelegrafConfigs []*influxdb.TelegrafConfig
+	TelegrafAgents  []*influxdb.TelegrafAgent
+}
+
+// TelegrafAgentServices are the telegraf agent service and the telegraf config service
+// that holds the configs the agents run.
+type TelegrafAgentServices interface {
+	influxdb.TelegrafAgentService
+	influxdb.TelegrafConfigStore
+}
+
+type telegrafAgentServiceF func(
+	init func(TelegrafAgentFields, *testing.T) (TelegrafAgentServices, func()),
+	t *testing.T,
+)
+
+// TelegrafAgentService tests all the service functions.
+func TelegrafAgentService(
+	init func(TelegrafAgentFields, *testing.T) (TelegrafAgentServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   telegrafAgentServiceF
+	}{
+		{
+			name: "CheckInTelegrafAgent",
+			fn:   CheckInTelegrafAgent,
+		},
+		{
+			name: "FindTelegrafAgents",
+			fn:   FindTelegrafAgents,
+		},
+		{
+			name: "DeleteTelegrafConfigAgents",
+			fn:   DeleteTelegrafConfigAgents,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var agentTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// agentHostsConfig returns the config of the hosts that the web1 and db1 agents run.
+func agentHostsConfig() *influxdb.TelegrafConfig {
+	return &influxdb.TelegrafConfig{
+		ID:      MustIDBase16(agentConfigOneID),
+		OrgID:   MustIDBase16(orgOneID),
+		Name:    "hosts",
+		Plugins: []influxdb.TelegrafPlugin{{Config: &inputs.CPUStats{}}},
+	}
+}
+
+// agentCacheConfig returns the config of the caches that the cache1 agent runs.
+func agentCacheConfig() *influxdb.TelegrafConfig {
+	return &influxdb.TelegrafConfig{
+		ID:      MustIDBase16(agentConfigTwoID),
+		OrgID:   MustIDBase16(orgTwoID),
+		Name:    "caches",
+		Plugins: []influxdb.TelegrafPlugin{{Config: &inputs.MemStats{}}},
+	}
+}
+
+func newTelegrafAgent(configID, orgID, hostname, configVersion string, checkIn time.Time) *influxdb.TelegrafAgent {
+	return &influxdb.TelegrafAgent{
+		ConfigID:      MustIDBase16(configID),
+		OrgID:         MustIDBase16(orgID),
+		Hostname:      hostname,
+		Version:       "1.11.0",
+		ConfigVersion: configVersion,
+		FirstCheckIn:  checkIn,
+		LastCheckIn:   checkIn,
+	}
+}
+
+// telegrafAgentFields returns the fields of a store with the web1 agent, that runs
+// an old version of the hosts config, the db1 agent, that runs its current version,
+// and the cache1 agent of the second organization. The time is a minute after they
+// checked in.
+func telegrafAgentFields() TelegrafAgentFields {
+	return TelegrafAgentFields{
+		TimeGenerator: mock.TimeGenerator{FakeValue: agentTime.Add(time.Minute)},
+		TelegrafConfigs: []*influxdb.TelegrafConfig{
+			agentHostsConfig(),
+			agentCacheConfig(),
+		},
+		TelegrafAgents: []*influxdb.TelegrafAgent{
+			newTelegrafAgent(agentConfigOneID, orgOneID, "web1", "old", agentTime),
+			newTelegrafAgent(agentConfigOneID, orgOneID, "db1", agentHostsConfig().Version(), agentTime),
+			newTelegrafAgent(agentConfigTwoID, orgTwoID, "cache1", agentCacheConfig().Version(), agentTime),
+		},
+	}
+}
+
+// telegrafAgentsOf returns the telegraf agents in the store.
+func telegrafAgentsOf(ctx context.Context, s TelegrafAgentServices, t *testing.T) []*influxdb.TelegrafAgent {
+	t.Helper()
+	as, err := s.FindTelegrafAgents(ctx, influxdb.TelegrafAgentFilter{})
+	if err != nil {
+		t.Fatalf("failed to retrieve telegraf agents: %v", err)
+	}
+	return as
+}
+
+// CheckInTelegrafAgent testing
+func CheckInTelegrafAgent(
+	init func(TelegrafAgentFields, *testing.T) (TelegrafAgentServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		agent *influxdb.TelegrafAgent
+	}
+	type wants struct {
+		err    error
+		agents []*influxdb.TelegrafAgent
+	}
+
+	now := agentTime.Add(time.Minute)
+	current := agentHostsConfig().Version()
+	upgraded := newTelegrafAgent(agentConfigOneID, orgOneID, "web1", current, agentTime)
+	upgraded.LastCheckIn = now
+	upgraded.LastError = "connection refused"
+	checkIn := func(configID, hostname, configVersion string) *influxdb.TelegrafAgent {
+		a := newTelegrafAgent(configID, orgOneID, hostname, configVersion, time.Time{})
+		a.OrgID = 0
+		return a
+	}
+
+	tests := []struct {
+		name   string
+		fields TelegrafAgentFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "agents keep their first check in",
+			fields: telegrafAgentFields(),
+			args: args{
+				agent: func() *influxdb.TelegrafAgent {
+					a := checkIn(agentConfigOneID, "web1", current)
+					a.LastError = "connection refused"
+					return a
+				}(),
+			},
+			wants: wants{
+				agents: []*influxdb.TelegrafAgent{
+					newTelegrafAgent(agentConfigTwoID, orgTwoID, "cache1", agentCacheConfig().Version(), agentTime),
+					newTelegrafAgent(agentConfigOneID, orgOneID, "db1", current, agentTime),
+					upgraded,
+				},
+			},
+		},
+		{
+			name:   "agents belong to the organization of their config",
+			fields: telegrafAgentFields(),
+			args: args{
+				agent: checkIn(agentConfigTwoID, "cache2", "old"),
+			},
+			wants: wants{
+				agents: []*influxdb.TelegrafAgent{
+					newTelegrafAgent(agentConfigTwoID, orgTwoID, "cache1", agentCacheConfig().Version(), agentTime),
+					newTelegrafAgent(agentConfigTwoID, orgTwoID, "cache2", "old", now),
+					newTelegrafAgent(agentConfigOneID, orgOneID, "db1", current, agentTime),
+					newTelegrafAgent(agentConfigOneID, orgOneID, "web1", "old", agentTime),
+				},
+			},
+		},
+		{
+			name:   "agents need a hostname",
+			fields: telegrafAgentFields(),
+			args: args{
+				agent: checkIn(agentConfigOneID, " ", current),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EInvalid,
+					Msg:  "telegraf agent hostname is required",
+				},
+				agents: []*influxdb.TelegrafAgent{
+					newTelegrafAgent(agentConfigTwoID, orgTwoID, "cache1", agentCacheConfig().Version(), agentTime),
+					newTelegrafAgent(agentConfigOneID, orgOneID, "db1", current, agentTime),
+					newTelegrafAgent(agentConfigOneID, orgOneID, "web1", "old", agentTime),
+				},
+			},
+		},
+		{
+			name:   "agents of missing configs are not found",
+			fields: telegrafAgentFields(),
+			args: args{
+				agent: checkIn(agentConfigThreeID, "web1", current),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTelegrafConfigNotFound,
+				},
+				agents: []*influxdb.TelegrafAgent{
+					newTelegrafAgent(agentConfigTwoID, orgTwoID, "cache1", agentCacheConfig().Version(), agentTime),
+					newTelegrafAgent(agentConfigOneID, orgOneID, "db1", current, agentTime),
+					newTelegrafAgent(agentConfigOneID, orgOneID, "web1", "old", agentTime),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CheckInTelegrafAgent(ctx, tt.args.agent)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(telegrafAgentsOf(ctx, s, t), tt.wants.agents); diff != "" {
+				t.Errorf("telegraf agents are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindTelegrafAgents testing
+func FindTelegrafAgents(
+	init func(TelegrafAgentFields, *testing.T) (TelegrafAgentServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.TelegrafAgentFilter
+	}
+	type wants struct {
+		err    error
+		agents []*influxdb.TelegrafAgent
+	}
+
+	current := agentHostsConfig().Version()
+	tests := []struct {
+		name   string
+		fields TelegrafAgentFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the agents of a config ordered by hostname",
+			fields: telegrafAgentFields(),
+			args: args{
+				filter: influxdb.TelegrafAgentFilter{ConfigID: idPtr(MustIDBase16(agentConfigOneID))},
+			},
+			wants: wants{
+				agents: []*influxdb.TelegrafAgent{
+					newTelegrafAgent(agentConfigOneID, orgOneID, "db1", current, agentTime),
+					newTelegrafAgent(agentConfigOneID, orgOneID, "web1", "old", agentTime),
+				},
+			},
+		},
+		{
+			name:   "find the agents of an organization",
+			fields: telegrafAgentFields(),
+			args: args{
+				filter: influxdb.TelegrafAgentFilter{OrgID: idPtr(MustIDBase16(orgTwoID))},
+			},
+			wants: wants{
+				agents: []*influxdb.TelegrafAgent{
+					newTelegrafAgent(agentConfigTwoID, orgTwoID, "cache1", agentCacheConfig().Version(), agentTime),
+				},
+			},
+		},
+		{
+			name:   "configs without agents have none",
+			fields: telegrafAgentFields(),
+			args: args{
+				filter: influxdb.TelegrafAgentFilter{ConfigID: idPtr(MustIDBase16(agentConfigThreeID))},
+			},
+			wants: wants{
+				agents: []*influxdb.TelegrafAgent{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			as, err := s.FindTelegrafAgents(ctx, tt.args.filter)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(as, tt.wants.agents); diff != "" {
+				t.Errorf("telegraf agents are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteTelegrafConfigAgents testing
+func DeleteTelegrafConfigAgents(
+	init func(TelegrafAgentFields, *testing.T) (TelegrafAgentServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id influxdb.ID
+	}
+	type wants struct {
+		err    error
+		agents []*influxdb.TelegrafAgent
+	}
+
+	tests := []struct {
+		name   string
+		fields TelegrafAgentFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "the agents of deleted configs are forgotten",
+			fields: telegrafAgentFields(),
+			args: args{
+				id: MustIDBase16(agentConfigOneID),
+			},
+			wants: wants{
+				agents: []*influxdb.TelegrafAgent{
+					newTelegrafAgent(agentConfigTwoID, orgTwoID, "cache1", agentCacheConfig().Version(), agentTime),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteTelegrafConfig(ctx, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if diff := cmp.Diff(telegrafAgentsOf(ctx, s, t), tt.wants.agents); diff != "" {
+				t.Errorf("telegraf agents are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
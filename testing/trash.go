@@ -0,0 +1,475 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/influxdb"
+	icontext "github.com/influxdata/influxdb/context"
+	"github.com/influxdata/influxdb/mock"
+)
+
+// TrashFields will include the TimeGenerator, how long trashed resources are kept, and
+// the users, organizations, authorizations, buckets and dashboards to populate the store
+// with. The trashed buckets and dashboards are created and deleted before the others
+// are created.
+type TrashFields struct {
+	TimeGenerator     influxdb.TimeGenerator
+	Retention         time.Duration
+	Users             []*influxdb.User
+	Organizations     []*influxdb.Organization
+	Authorizations    []*influxdb.Authorization
+	Buckets           []*influxdb.Bucket
+	Dashboards        []*influxdb.Dashboard
+	TrashedBuckets    []*influxdb.Bucket
+	TrashedDashboards []*influxdb.Dashboard
+}
+
+// TrashServices are the trash service and the bucket and dashboard services whose
+// deleted resources it keeps.
+type TrashServices interface {
+	influxdb.TrashService
+	influxdb.BucketService
+	influxdb.DashboardService
+}
+
+// TaskTrashServices are the trash service and the task service whose deleted tasks it
+// keeps.
+type TaskTrashServices interface {
+	influxdb.TrashService
+	influxdb.TaskService
+}
+
+type trashServiceF func(
+	init func(TrashFields, *testing.T) (TrashServices, func()),
+	t *testing.T,
+)
+
+// TrashService tests all the service functions of trashed buckets and dashboards.
+func TrashService(
+	init func(TrashFields, *testing.T) (TrashServices, func()), t *testing.T,
+) {
+	tests := []struct {
+		name string
+		fn   trashServiceF
+	}{
+		{
+			name: "FindTrashedResources",
+			fn:   FindTrashedResources,
+		},
+		{
+			name: "RestoreTrashedResource",
+			fn:   RestoreTrashedResource,
+		},
+		{
+			name: "PurgeTrashedResource",
+			fn:   PurgeTrashedResource,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(init, t)
+		})
+	}
+}
+
+var trashTime = time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+
+const trashRetention = 7 * 24 * time.Hour
+
+// trashFields returns the fields of a store with the trashed cpu bucket and
+// overview dashboard of an organization.
+func trashFields() TrashFields {
+	return TrashFields{
+		TimeGenerator: mock.TimeGenerator{FakeValue: trashTime},
+		Retention:     trashRetention,
+		Organizations: []*influxdb.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "org"},
+		},
+		TrashedBuckets: []*influxdb.Bucket{
+			{ID: MustIDBase16(bucketOneID), OrgID: MustIDBase16(orgOneID), Name: "cpu"},
+		},
+		TrashedDashboards: []*influxdb.Dashboard{
+			{ID: MustIDBase16(dashTwoID), OrganizationID: MustIDBase16(orgOneID), Name: "overview"},
+		},
+	}
+}
+
+func newTrashedResource(typ influxdb.ResourceType, id, name string) *influxdb.TrashedResource {
+	return &influxdb.TrashedResource{
+		Type:      typ,
+		ID:        MustIDBase16(id),
+		OrgID:     MustIDBase16(orgOneID),
+		Name:      name,
+		DeletedAt: trashTime,
+		ExpiresAt: trashTime.Add(trashRetention),
+	}
+}
+
+// FindTrashedResources testing
+func FindTrashedResources(
+	init func(TrashFields, *testing.T) (TrashServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		filter influxdb.TrashFilter
+	}
+	type wants struct {
+		trashedResources []*influxdb.TrashedResource
+	}
+
+	bucketsType := influxdb.BucketsResourceType
+
+	tests := []struct {
+		name   string
+		fields TrashFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find the trashed resources of an organization",
+			fields: trashFields(),
+			args: args{
+				filter: influxdb.TrashFilter{
+					OrgID: idPtr(MustIDBase16(orgOneID)),
+				},
+			},
+			wants: wants{
+				trashedResources: []*influxdb.TrashedResource{
+					newTrashedResource(influxdb.BucketsResourceType, bucketOneID, "cpu"),
+					newTrashedResource(influxdb.DashboardsResourceType, dashTwoID, "overview"),
+				},
+			},
+		},
+		{
+			name:   "find the trashed resources of a type",
+			fields: trashFields(),
+			args: args{
+				filter: influxdb.TrashFilter{
+					Type: &bucketsType,
+				},
+			},
+			wants: wants{
+				trashedResources: []*influxdb.TrashedResource{
+					newTrashedResource(influxdb.BucketsResourceType, bucketOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "organizations without trashed resources have none",
+			fields: trashFields(),
+			args: args{
+				filter: influxdb.TrashFilter{
+					OrgID: idPtr(MustIDBase16(orgTwoID)),
+				},
+			},
+			wants: wants{
+				trashedResources: []*influxdb.TrashedResource{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			rs, n, err := s.FindTrashedResources(ctx, tt.args.filter)
+			if err != nil {
+				t.Fatalf("failed to retrieve trashed resources: %v", err)
+			}
+			if n != len(tt.wants.trashedResources) {
+				t.Errorf("expected %d trashed resources, got %d", len(tt.wants.trashedResources), n)
+			}
+			if diff := cmp.Diff(rs, tt.wants.trashedResources); diff != "" {
+				t.Errorf("trashed resources are different -got/+want\ndiff %s", diff)
+			}
+
+			if _, err := s.FindBucketByID(ctx, MustIDBase16(bucketOneID)); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Errorf("expected a trashed bucket to not be found, got %v", err)
+			}
+		})
+	}
+}
+
+// RestoreTrashedResource testing
+func RestoreTrashedResource(
+	init func(TrashFields, *testing.T) (TrashServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		resourceType influxdb.ResourceType
+		id           influxdb.ID
+	}
+	type wants struct {
+		err              error
+		trashedResources []*influxdb.TrashedResource
+	}
+
+	tests := []struct {
+		name   string
+		fields TrashFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "restore a trashed bucket",
+			fields: trashFields(),
+			args: args{
+				resourceType: influxdb.BucketsResourceType,
+				id:           MustIDBase16(bucketOneID),
+			},
+			wants: wants{
+				trashedResources: []*influxdb.TrashedResource{
+					newTrashedResource(influxdb.DashboardsResourceType, dashTwoID, "overview"),
+				},
+			},
+		},
+		{
+			name: "buckets whose name was taken while they were trashed can not be restored",
+			fields: func() TrashFields {
+				f := trashFields()
+				f.Buckets = []*influxdb.Bucket{
+					{ID: MustIDBase16(bucketTwoID), OrgID: MustIDBase16(orgOneID), Name: "cpu"},
+				}
+				return f
+			}(),
+			args: args{
+				resourceType: influxdb.BucketsResourceType,
+				id:           MustIDBase16(bucketOneID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.EConflict,
+					Msg:  "bucket with name cpu already exists",
+				},
+				trashedResources: []*influxdb.TrashedResource{
+					newTrashedResource(influxdb.BucketsResourceType, bucketOneID, "cpu"),
+					newTrashedResource(influxdb.DashboardsResourceType, dashTwoID, "overview"),
+				},
+			},
+		},
+		{
+			name:   "resources that are not trashed are not found",
+			fields: trashFields(),
+			args: args{
+				resourceType: influxdb.DashboardsResourceType,
+				id:           MustIDBase16(dashOneID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTrashedResourceNotFound,
+				},
+				trashedResources: []*influxdb.TrashedResource{
+					newTrashedResource(influxdb.BucketsResourceType, bucketOneID, "cpu"),
+					newTrashedResource(influxdb.DashboardsResourceType, dashTwoID, "overview"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.RestoreTrashedResource(ctx, tt.args.resourceType, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			if err == nil {
+				b, err := s.FindBucket(ctx, influxdb.BucketFilter{
+					OrganizationID: idPtr(MustIDBase16(orgOneID)),
+					Name:           strPtr("cpu"),
+				})
+				if err != nil || b.ID != tt.args.id {
+					t.Errorf("expected the bucket to be restored, got %v, %v", b, err)
+				}
+			}
+
+			rs, _, err := s.FindTrashedResources(ctx, influxdb.TrashFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve trashed resources: %v", err)
+			}
+			if diff := cmp.Diff(rs, tt.wants.trashedResources); diff != "" {
+				t.Errorf("trashed resources are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// PurgeTrashedResource testing
+func PurgeTrashedResource(
+	init func(TrashFields, *testing.T) (TrashServices, func()),
+	t *testing.T,
+) {
+	type args struct {
+		resourceType influxdb.ResourceType
+		id           influxdb.ID
+	}
+	type wants struct {
+		err              error
+		trashedResources []*influxdb.TrashedResource
+	}
+
+	tests := []struct {
+		name   string
+		fields TrashFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "purged dashboards leave the trash",
+			fields: trashFields(),
+			args: args{
+				resourceType: influxdb.DashboardsResourceType,
+				id:           MustIDBase16(dashTwoID),
+			},
+			wants: wants{
+				trashedResources: []*influxdb.TrashedResource{
+					newTrashedResource(influxdb.BucketsResourceType, bucketOneID, "cpu"),
+				},
+			},
+		},
+		{
+			name:   "resources are purged by their type",
+			fields: trashFields(),
+			args: args{
+				resourceType: influxdb.BucketsResourceType,
+				id:           MustIDBase16(dashTwoID),
+			},
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTrashedResourceNotFound,
+				},
+				trashedResources: []*influxdb.TrashedResource{
+					newTrashedResource(influxdb.BucketsResourceType, bucketOneID, "cpu"),
+					newTrashedResource(influxdb.DashboardsResourceType, dashTwoID, "overview"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.PurgeTrashedResource(ctx, tt.args.resourceType, tt.args.id)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			rs, _, err := s.FindTrashedResources(ctx, influxdb.TrashFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve trashed resources: %v", err)
+			}
+			if diff := cmp.Diff(rs, tt.wants.trashedResources); diff != "" {
+				t.Errorf("trashed resources are different -got/+want\ndiff %s", diff)
+			}
+
+			if err := s.RestoreTrashedResource(ctx, tt.args.resourceType, tt.args.id); influxdb.ErrorCode(err) != influxdb.ENotFound {
+				t.Errorf("expected a purged resource to not be restored, got %v", err)
+			}
+		})
+	}
+}
+
+// TaskTrashService tests restoring trashed tasks.
+func TaskTrashService(
+	init func(TrashFields, *testing.T) (TaskTrashServices, func()), t *testing.T,
+) {
+	type args struct {
+		// trash is whether the task is deleted before it is restored.
+		trash bool
+	}
+	type wants struct {
+		err    error
+		status string
+	}
+
+	fields := func() TrashFields {
+		return TrashFields{
+			Retention: time.Hour,
+			Users: []*influxdb.User{
+				{ID: MustIDBase16(userOneID), Name: "user"},
+			},
+			Organizations: []*influxdb.Organization{
+				{ID: MustIDBase16(orgOneID), Name: "org"},
+			},
+			Authorizations: []*influxdb.Authorization{
+				{OrgID: MustIDBase16(orgOneID), UserID: MustIDBase16(userOneID)},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		fields TrashFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "restored tasks are inactive until they are activated again",
+			fields: fields(),
+			args: args{
+				trash: true,
+			},
+			wants: wants{
+				status: string(influxdb.TaskStatusInactive),
+			},
+		},
+		{
+			name:   "tasks that are not trashed are not found",
+			fields: fields(),
+			wants: wants{
+				err: &influxdb.Error{
+					Code: influxdb.ENotFound,
+					Msg:  influxdb.ErrTrashedResourceNotFound,
+				},
+				status: string(influxdb.TaskStatusActive),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, done := init(tt.fields, t)
+			defer done()
+			auth := tt.fields.Authorizations[0]
+			ctx := icontext.SetAuthorizer(context.Background(), auth)
+
+			task, err := s.CreateTask(ctx, influxdb.TaskCreate{
+				OrganizationID: auth.OrgID,
+				Flux:           `option task = {name: "downsample", every: 1h} from(bucket: "b") |> range(start: -1h)`,
+				Token:          auth.Token,
+			})
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			if tt.args.trash {
+				if err := s.DeleteTask(ctx, task.ID); err != nil {
+					t.Fatalf("failed to delete task: %v", err)
+				}
+				if _, err := s.FindTaskByID(ctx, task.ID); err == nil {
+					t.Fatal("expected a trashed task to not be found")
+				}
+			}
+
+			err = s.RestoreTrashedResource(ctx, influxdb.TasksResourceType, task.ID)
+			ErrorsEqual(t, err, tt.wants.err)
+
+			restored, err := s.FindTaskByID(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("failed to retrieve task: %v", err)
+			}
+			if restored.Name != "downsample" || restored.Status != tt.wants.status {
+				t.Errorf("expected task downsample with status %q, got %q with status %q", tt.wants.status, restored.Name, restored.Status)
+			}
+		})
+	}
+}
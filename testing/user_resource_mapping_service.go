@@ -55,6 +55,10 @@ func UserResourceMappingService(
 			name: "DeleteUserResourceMapping",
 			fn:   DeleteUserResourceMapping,
 		},
+		{
+			name: "ApplyUserResourceMappings",
+			fn:   ApplyUserResourceMappings,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -474,3 +478,122 @@ func FindUserResourceMappings(
 		})
 	}
 }
+
+// ApplyUserResourceMappings tests the batch changes of a service that also
+// implements platform.UserResourceMappingBatchService.
+func ApplyUserResourceMappings(
+	init func(UserResourceFields, *testing.T) (platform.UserResourceMappingService, func()),
+	t *testing.T,
+) {
+	bucketOne := &platform.UserResourceMapping{
+		ResourceID:   MustIDBase16(bucketOneID),
+		UserID:       MustIDBase16(userOneID),
+		UserType:     platform.Member,
+		ResourceType: platform.BucketsResourceType,
+	}
+	bucketTwo := &platform.UserResourceMapping{
+		ResourceID:   MustIDBase16(bucketTwoID),
+		UserID:       MustIDBase16(userTwoID),
+		UserType:     platform.Owner,
+		ResourceType: platform.BucketsResourceType,
+	}
+
+	type wants struct {
+		// errs holds the error code of the result of each op.
+		errs     []string
+		err      string
+		mappings []*platform.UserResourceMapping
+	}
+
+	tests := []struct {
+		name   string
+		fields UserResourceFields
+		ops    []platform.UserResourceMappingOp
+		wants  wants
+	}{
+		{
+			name: "adds and removes mappings",
+			fields: UserResourceFields{
+				UserResourceMappings: []*platform.UserResourceMapping{bucketOne},
+			},
+			ops: []platform.UserResourceMappingOp{
+				{Action: platform.AddMapping, UserResourceMapping: *bucketTwo},
+				{Action: platform.RemoveMapping, UserResourceMapping: *bucketOne},
+			},
+			wants: wants{
+				errs:     []string{"", ""},
+				mappings: []*platform.UserResourceMapping{bucketTwo},
+			},
+		},
+		{
+			name: "ops see the changes of earlier ops",
+			fields: UserResourceFields{
+				UserResourceMappings: []*platform.UserResourceMapping{bucketOne},
+			},
+			ops: []platform.UserResourceMappingOp{
+				{Action: platform.RemoveMapping, UserResourceMapping: *bucketOne},
+				{Action: platform.AddMapping, UserResourceMapping: *bucketOne},
+				{Action: platform.AddMapping, UserResourceMapping: *bucketTwo},
+				{Action: platform.RemoveMapping, UserResourceMapping: *bucketTwo},
+			},
+			wants: wants{
+				errs:     []string{"", "", "", ""},
+				mappings: []*platform.UserResourceMapping{bucketOne},
+			},
+		},
+		{
+			name: "applies no op if any fails",
+			fields: UserResourceFields{
+				UserResourceMappings: []*platform.UserResourceMapping{bucketOne},
+			},
+			ops: []platform.UserResourceMappingOp{
+				{Action: platform.AddMapping, UserResourceMapping: *bucketTwo},
+				{Action: platform.AddMapping, UserResourceMapping: *bucketOne},
+				{Action: platform.RemoveMapping, UserResourceMapping: *bucketOne},
+				{Action: platform.RemoveMapping, UserResourceMapping: *bucketOne},
+				{Action: "move", UserResourceMapping: *bucketTwo},
+			},
+			wants: wants{
+				errs:     []string{"", platform.EConflict, "", platform.ENotFound, platform.EInvalid},
+				err:      platform.EConflict,
+				mappings: []*platform.UserResourceMapping{bucketOne},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, done := init(tt.fields, t)
+			defer done()
+			s, ok := svc.(platform.UserResourceMappingBatchService)
+			if !ok {
+				t.Fatalf("%T does not implement platform.UserResourceMappingBatchService", svc)
+			}
+			ctx := context.Background()
+
+			rs, err := s.ApplyUserResourceMappings(ctx, tt.ops)
+			if code := platform.ErrorCode(err); code != tt.wants.err {
+				t.Fatalf("expected error code %q got %q: %v", tt.wants.err, code, err)
+			}
+			if len(rs) != len(tt.ops) {
+				t.Fatalf("expected %d results got %d", len(tt.ops), len(rs))
+			}
+			for i, r := range rs {
+				if r.UserResourceMappingOp != tt.ops[i] {
+					t.Errorf("result %d is of op %+v, want %+v", i, r.UserResourceMappingOp, tt.ops[i])
+				}
+				if code := platform.ErrorCode(r.Err); code != tt.wants.errs[i] {
+					t.Errorf("expected error code %q for op %d got %q: %v", tt.wants.errs[i], i, code, r.Err)
+				}
+			}
+
+			mappings, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve mappings: %v", err)
+			}
+			if diff := cmp.Diff(mappings, tt.wants.mappings, mappingCmpOptions...); diff != "" {
+				t.Errorf("mappings are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package influxdb
+
+import (
+	"context"
+	"time"
+)
+
+// ErrTrashedResourceNotFound is the error msg for a missing trashed resource.
+const ErrTrashedResourceNotFound = "trashed resource not found"
+
+// ops for trash errors.
+const (
+	OpFindTrashedResource    = "FindTrashedResource"
+	OpFindTrashedResources   = "FindTrashedResources"
+	OpRestoreTrashedResource = "RestoreTrashedResource"
+	OpPurgeTrashedResource   = "PurgeTrashedResource"
+)
+
+// TrashResourceTypes are the types of resources that are moved to the trash
+// when they are deleted.
+var TrashResourceTypes = []ResourceType{
+	BucketsResourceType,
+	DashboardsResourceType,
+	TasksResourceType,
+}
+
+// TrashService manages deleted resources, which can be restored until they
+// are purged from the trash.
+//
+// Resources are only moved to the trash when the services that delete them
+// are configured with a trash retention; otherwise they are deleted at once.
+type TrashService interface {
+	// FindTrashedResource returns a single trashed resource by its type and ID.
+	FindTrashedResource(ctx context.Context, typ ResourceType, id ID) (*TrashedResource, error)
+
+	// FindTrashedResources returns the trashed resources that match filter and
+	// the total count of matching resources, most recently deleted first.
+	FindTrashedResources(ctx context.Context, filter TrashFilter, opt ...FindOptions) ([]*TrashedResource, int, error)
+
+	// RestoreTrashedResource moves a resource out of the trash, as it was when
+	// it was deleted.
+	RestoreTrashedResource(ctx context.Context, typ ResourceType, id ID) error
+
+	// PurgeTrashedResource permanently deletes a trashed resource.
+	PurgeTrashedResource(ctx context.Context, typ ResourceType, id ID) error
+}
+
+// TrashedResource is a deleted resource that can still be restored.
+type TrashedResource struct {
+	Type      ResourceType `json:"type"`
+	ID        ID           `json:"id"`
+	OrgID     ID           `json:"orgID"`
+	Name      string       `json:"name"`
+	DeletedAt time.Time    `json:"deletedAt"`
+	// ExpiresAt is when the resource is purged from the trash.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Expired returns whether the resource is due to be purged at now.
+func (r *TrashedResource) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// TrashFilter represents a set of filters that restrict the returned trashed resources.
+type TrashFilter struct {
+	OrgID *ID
+	Type  *ResourceType
+}
+
+// QueryParams converts TrashFilter fields to url query params.
+func (f TrashFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.OrgID != nil {
+		qp["orgID"] = []string{f.OrgID.String()}
+	}
+	if f.Type != nil {
+		qp["type"] = []string{string(*f.Type)}
+	}
+	return qp
+}
+
+// Valid returns an error if the filter is for resources that are never trashed.
+func (f TrashFilter) Valid() error {
+	if f.Type != nil && !containsResourceType(TrashResourceTypes, *f.Type) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "resources of type " + string(*f.Type) + " are not moved to the trash",
+		}
+	}
+	return nil
+}
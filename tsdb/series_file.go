@@ -51,6 +51,10 @@ type SeriesFile struct {
 
 	LargeWriteThreshold int
 
+	// CompactionsAllowed, if set, reports whether series partitions may compact,
+	// which rebuilds their on-disk index. It must be set before Open.
+	CompactionsAllowed func() bool
+
 	Logger *zap.Logger
 }
 
@@ -127,6 +131,7 @@ func (f *SeriesFile) Open(ctx context.Context) error {
 		// TODO(edd): These partition initialisation should be moved up to NewSeriesFile.
 		p := NewSeriesPartition(i, f.SeriesPartitionPath(i))
 		p.LargeWriteThreshold = f.LargeWriteThreshold
+		p.CompactionsAllowed = f.CompactionsAllowed
 		p.Logger = f.Logger.With(zap.Int("partition", p.ID()))
 
 		// For each series file index, rhh trackers are used to track the RHH Hashmap.
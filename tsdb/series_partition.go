@@ -49,6 +49,10 @@ type SeriesPartition struct {
 	CompactThreshold    int
 	LargeWriteThreshold int
 
+	// CompactionsAllowed, if set, reports whether a compaction may start.
+	// Compactions that are not allowed start with the next insert of series once they are.
+	CompactionsAllowed func() bool
+
 	tracker *seriesPartitionTracker
 	Logger  *zap.Logger
 }
@@ -306,7 +310,8 @@ func (p *SeriesPartition) CreateSeriesListIfNotExists(collection *SeriesCollecti
 	p.tracker.AddSeries(uint64(len(newKeyRanges)))
 
 	// Check if we've crossed the compaction threshold.
-	if p.compactionsEnabled() && !p.compacting && p.CompactThreshold != 0 && p.index.InMemCount() >= uint64(p.CompactThreshold) {
+	if p.compactionsEnabled() && !p.compacting && p.CompactThreshold != 0 && p.index.InMemCount() >= uint64(p.CompactThreshold) &&
+		(p.CompactionsAllowed == nil || p.CompactionsAllowed()) {
 		p.compacting = true
 		log, logEnd := logger.NewOperation(ctx, p.Logger, "Series partition compaction", "series_partition_compaction", zap.String("path", p.path))
 
@@ -101,6 +101,14 @@ func WithSnapshotter(snapshotter Snapshotter) EngineOption {
 	}
 }
 
+// WithMaintenanceGate makes the engine run full and optimize compactions, which
+// are heavy, only while allowed returns true.
+func WithMaintenanceGate(allowed func() bool) EngineOption {
+	return func(e *Engine) {
+		e.maintenanceAllowed = allowed
+	}
+}
+
 // Engine represents a storage engine with compressed blocks.
 type Engine struct {
 	mu sync.RWMutex
@@ -163,6 +171,9 @@ type Engine struct {
 
 	scheduler   *scheduler
 	snapshotter Snapshotter
+
+	// maintenanceAllowed reports whether full and optimize compactions may run.
+	maintenanceAllowed func() bool
 }
 
 // NewEngine returns a new instance of Engine.
@@ -963,13 +974,18 @@ func (e *Engine) compact(wg *sync.WaitGroup) {
 			level1Groups := e.CompactionPlan.PlanLevel(1)
 			level2Groups := e.CompactionPlan.PlanLevel(2)
 			level3Groups := e.CompactionPlan.PlanLevel(3)
-			level4Groups := e.CompactionPlan.Plan(e.lastModified())
-			e.compactionTracker.SetOptimiseQueue(uint64(len(level4Groups)))
 
-			// If no full compactions are need, see if an optimize is needed
-			if len(level4Groups) == 0 {
-				level4Groups = e.CompactionPlan.PlanOptimize()
+			// Full and optimize compactions are only planned while maintenance is allowed.
+			var level4Groups []CompactionGroup
+			if e.maintenanceAllowed == nil || e.maintenanceAllowed() {
+				level4Groups = e.CompactionPlan.Plan(e.lastModified())
 				e.compactionTracker.SetOptimiseQueue(uint64(len(level4Groups)))
+
+				// If no full compactions are need, see if an optimize is needed
+				if len(level4Groups) == 0 {
+					level4Groups = e.CompactionPlan.PlanOptimize()
+					e.compactionTracker.SetOptimiseQueue(uint64(len(level4Groups)))
+				}
 			}
 
 			// Update the level plan queue stats
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"math"
 	"sync"
+	"sync/atomic"
 
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/pkg/bytesutil"
@@ -223,3 +224,28 @@ func (e *Engine) DeletePrefixRange(name []byte, min, max int64, pred Predicate)
 
 	return nil
 }
+
+// PrefixRangeBlockBytes returns the size of the TSM blocks of the keys with the
+// prefix name whose last timestamp is in [min, max]. Once the data of the prefix
+// up to max is deleted, those blocks only hold deleted data, and compactions
+// remove them from the TSM files.
+func (e *Engine) PrefixRangeBlockBytes(name []byte, min, max int64) (int64, error) {
+	var n int64
+	err := e.FileStore.Apply(func(r TSMFile) error {
+		var size int64
+		iter := r.Iterator(name)
+		for iter.Next() {
+			if !bytes.HasPrefix(iter.Key(), name) {
+				break
+			}
+			for _, entry := range iter.Entries() {
+				if entry.MaxTime >= min && entry.MaxTime <= max {
+					size += int64(entry.Size)
+				}
+			}
+		}
+		atomic.AddInt64(&n, size)
+		return iter.Err()
+	})
+	return n, err
+}
@@ -3,6 +3,7 @@ package tsm1_test
 import (
 	"bytes"
 	"context"
+	"math"
 	"reflect"
 	"testing"
 
@@ -118,3 +119,52 @@ func TestEngine_DeletePrefix(t *testing.T) {
 		}
 	}
 }
+
+func TestEngine_PrefixRangeBlockBytes(t *testing.T) {
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	if err := e.writePoints(
+		MustParsePointString("cpu,host=A value=1.1 1", "mm0"),
+		MustParsePointString("cpu,host=A value=1.2 2", "mm0"),
+		MustParsePointString("cpu,host=B value=1.3 5", "mm0"),
+		MustParsePointString("mem,host=A value=1.4 2", "mm1"),
+	); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	if err := e.WriteSnapshot(context.Background()); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+
+	size := func(name string, min, max int64) int64 {
+		n, err := e.PrefixRangeBlockBytes([]byte(name), min, max)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	expired := size("mm0", math.MinInt64, 3)
+	if expired == 0 {
+		t.Fatal("expected the block of host A to be counted")
+	}
+	later := size("mm0", 4, math.MaxInt64)
+	if later == 0 {
+		t.Fatal("expected the block of host B to be counted")
+	}
+	if all := size("mm0", math.MinInt64, math.MaxInt64); all != expired+later {
+		t.Errorf("got %d bytes for all blocks, expected %d", all, expired+later)
+	}
+	if n := size("mm0", math.MinInt64, 1); n != 0 {
+		t.Errorf("expected blocks that end after the range not to be counted, got %d bytes", n)
+	}
+	if n := size("mm2", math.MinInt64, math.MaxInt64); n != 0 {
+		t.Errorf("expected no blocks of another prefix, got %d bytes", n)
+	}
+}
@@ -23,6 +23,11 @@ const (
 	UsageQueryRequestCount UsageMetric = "usage_query_request_count"
 	// UsageQueryRequestBytes is the name of the metrics for tracking the number of query bytes.
 	UsageQueryRequestBytes UsageMetric = "usage_query_request_bytes"
+
+	// UsageStorageBytes is the name of the metrics for tracking the number of bytes stored.
+	UsageStorageBytes UsageMetric = "usage_storage_bytes"
+	// UsageTaskRunCount is the name of the metrics for tracking the number of task runs.
+	UsageTaskRunCount UsageMetric = "usage_task_run_count"
 )
 
 // Usage is a metric associated with the utilization of a particular resource.
@@ -50,3 +55,59 @@ type Timespan struct {
 	Start time.Time `json:"start"`
 	Stop  time.Time `json:"stop"`
 }
+
+// UsageRecorder records the usage of organizations.
+type UsageRecorder interface {
+	// RecordUsage adds n to the usage metric m of the organization.
+	RecordUsage(ctx context.Context, orgID ID, m UsageMetric, n int64)
+}
+
+// OrgUsageReport is the usage of an organization over a time range, as recorded by a UsageRecorder.
+type OrgUsageReport struct {
+	OrgID ID        `json:"orgID"`
+	Start time.Time `json:"start"`
+	Stop  time.Time `json:"stop"`
+
+	// WriteRequestCount is the number of successful write requests.
+	WriteRequestCount int64 `json:"writeRequestCount"`
+	// WriteRequestBytes is the number of bytes written by successful write requests.
+	WriteRequestBytes int64 `json:"writeRequestBytes"`
+	// QueryRequestCount is the number of successful query requests.
+	QueryRequestCount int64 `json:"queryRequestCount"`
+	// QueryRequestBytes is the number of bytes of the successful query requests.
+	QueryRequestBytes int64 `json:"queryRequestBytes"`
+	// TaskRunCount is the number of finished task runs.
+	TaskRunCount int64 `json:"taskRunCount"`
+	// StorageBytes is the number of bytes stored as of the last recording in the time range.
+	StorageBytes int64 `json:"storageBytes"`
+}
+
+// OrgUsageService returns the usage recorded for organizations.
+type OrgUsageService interface {
+	// FindOrgUsage returns the usage of an organization in the time range of the filter.
+	FindOrgUsage(ctx context.Context, filter OrgUsageFilter) (*OrgUsageReport, error)
+}
+
+// OrgUsageFilter selects the usage of an organization.
+type OrgUsageFilter struct {
+	OrgID ID
+	Start time.Time
+	Stop  time.Time
+}
+
+// Valid returns an error if the filter does not select an organization and a time range.
+func (f OrgUsageFilter) Valid() error {
+	if !f.OrgID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "orgID is invalid",
+		}
+	}
+	if !f.Start.Before(f.Stop) {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "start must be before stop",
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package influxdb
 
 import (
 	"context"
+	"sort"
 )
 
 // User is a user. 🎉
@@ -51,8 +52,37 @@ type UserUpdate struct {
 	Name *string `json:"name"`
 }
 
+// UserSortKeys are the keys users can be sorted by.
+var UserSortKeys = []string{"ID", "Name"}
+
+// SortUsers sorts users by the sort key of the options, or by ID if they have none.
+func SortUsers(opts FindOptions, us []*User) {
+	var less func(i, j int) bool
+	switch opts.sortKey() {
+	case "name":
+		less = func(i, j int) bool { return us[i].Name < us[j].Name }
+	default:
+		less = func(i, j int) bool { return us[i].ID < us[j].ID }
+	}
+	sort.SliceStable(us, opts.ordered(less))
+}
+
 // UserFilter represents a set of filter that restrict the returned results.
 type UserFilter struct {
 	ID   *ID
 	Name *string
 }
+
+// QueryParams converts UserFilter fields to url query params.
+func (f UserFilter) QueryParams() map[string][]string {
+	qp := map[string][]string{}
+	if f.ID != nil {
+		qp["id"] = []string{f.ID.String()}
+	}
+
+	if f.Name != nil {
+		qp["name"] = []string{*f.Name}
+	}
+
+	return qp
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 )
 
 var (
@@ -15,6 +16,8 @@ var (
 	ErrUserIDRequired = errors.New("user id is required")
 	// ErrResourceIDRequired notes that the provided ID was not provided
 	ErrResourceIDRequired = errors.New("resource id is required")
+	// ErrInvalidTeamMapping notes that only users can be mapped to a team
+	ErrInvalidTeamMapping = errors.New("only users can be members or owners of a team")
 )
 
 // UserType can either be owner or member.
@@ -44,11 +47,13 @@ type MappingType uint8
 const (
 	UserMappingType = 0
 	OrgMappingType  = 1
+	// TeamMappingType maps a team to a resource, which maps all the users of the team to it.
+	TeamMappingType = 2
 )
 
 func (mt MappingType) Valid() error {
 	switch mt {
-	case UserMappingType, OrgMappingType:
+	case UserMappingType, OrgMappingType, TeamMappingType:
 		return nil
 	}
 
@@ -61,6 +66,8 @@ func (mt MappingType) String() string {
 		return "user"
 	case OrgMappingType:
 		return "org"
+	case TeamMappingType:
+		return "team"
 	}
 
 	return "unknown"
@@ -83,6 +90,9 @@ func (mt *MappingType) UnmarshalJSON(b []byte) error {
 	case "org":
 		*mt = OrgMappingType
 		return nil
+	case "team":
+		*mt = TeamMappingType
+		return nil
 	}
 
 	return ErrInvalidMappingType
@@ -100,7 +110,89 @@ type UserResourceMappingService interface {
 	DeleteUserResourceMapping(ctx context.Context, resourceID, userID ID) error
 }
 
+// UserResourceMappingBatchService changes many user resource mappings at once.
+type UserResourceMappingBatchService interface {
+	// ApplyUserResourceMappings applies ops in order within a single transaction and returns
+	// the result of each op. If any op fails, none are applied and an error is returned as well.
+	ApplyUserResourceMappings(ctx context.Context, ops []UserResourceMappingOp) ([]UserResourceMappingResult, error)
+}
+
+// MappingAction is the change an op makes to a user resource mapping.
+type MappingAction string
+
+const (
+	// AddMapping creates a mapping.
+	AddMapping MappingAction = "add"
+	// RemoveMapping deletes a mapping.
+	RemoveMapping MappingAction = "remove"
+)
+
+// UserResourceMappingOp is a change to a user resource mapping.
+type UserResourceMappingOp struct {
+	Action MappingAction `json:"action"`
+	UserResourceMapping
+}
+
+// Validate reports any validation errors for the op.
+// A removal only needs the resource, its type and the user of the mapping.
+func (op UserResourceMappingOp) Validate() error {
+	var err error
+	switch op.Action {
+	case AddMapping:
+		err = op.UserResourceMapping.Validate()
+	case RemoveMapping:
+		if !op.ResourceID.Valid() {
+			err = ErrResourceIDRequired
+		} else if !op.UserID.Valid() {
+			err = ErrUserIDRequired
+		} else {
+			err = op.ResourceType.Valid()
+		}
+	default:
+		err = fmt.Errorf("unknown mapping action %q", op.Action)
+	}
+	if err != nil {
+		return &Error{
+			Code: EInvalid,
+			Err:  err,
+		}
+	}
+
+	return nil
+}
+
+// UserResourceMappingResult is the result of an op.
+type UserResourceMappingResult struct {
+	UserResourceMappingOp
+	// Err is the reason the op failed, if it did.
+	Err error
+}
+
+// ErrMappingOpsFailed is the error returned when a batch of mapping ops is not applied
+// because some of them failed.
+func ErrMappingOpsFailed(rs []UserResourceMappingResult) error {
+	var failed int
+	var code string
+	for _, r := range rs {
+		if r.Err != nil {
+			if failed == 0 {
+				code = ErrorCode(r.Err)
+			}
+			failed++
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+
+	return &Error{
+		Code: code,
+		Msg:  fmt.Sprintf("%d of %d mapping operations failed; no mappings were changed", failed, len(rs)),
+	}
+}
+
 // UserResourceMapping represents a mapping of a resource to its user.
+// The UserID is the ID of the organization or team of an org or team mapping.
 type UserResourceMapping struct {
 	UserID       ID           `json:"userID"`
 	UserType     UserType     `json:"userType"`
@@ -131,6 +223,10 @@ func (m UserResourceMapping) Validate() error {
 		return err
 	}
 
+	if m.ResourceType == TeamsResourceType && m.MappingType != UserMappingType {
+		return ErrInvalidTeamMapping
+	}
+
 	return nil
 }
 
@@ -47,7 +47,10 @@ func (s *SecretService) LoadSecret(ctx context.Context, orgID platform.ID, k str
 		return v, nil
 	}
 
-	return "", fmt.Errorf("secret not found")
+	return "", &platform.Error{
+		Code: platform.ENotFound,
+		Msg:  platform.ErrSecretNotFound,
+	}
 }
 
 // loadSecrets retrieves a map of secrets for an organization and the version of the secrets retrieved.
@@ -112,12 +115,7 @@ func (s *SecretService) GetSecretKeys(ctx context.Context, orgID platform.ID) ([
 		return nil, err
 	}
 
-	keys := make([]string, 0, len(data))
-	for k := range data {
-		keys = append(keys, k)
-	}
-
-	return keys, nil
+	return platform.SecretKeys(data), nil
 }
 
 // PutSecret stores the secret pair (k,v) for the organization orgID.
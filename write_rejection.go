@@ -0,0 +1,45 @@
+package influxdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Reasons a write, or a line of it, is rejected for.
+const (
+	WriteRejectionInvalidLine   = "invalid_line"
+	WriteRejectionLineTooLong   = "line_too_long"
+	WriteRejectionForbidden     = "forbidden"
+	WriteRejectionNoBucket      = "bucket_not_found"
+	WriteRejectionOverQuota     = "over_quota"
+	WriteRejectionInvalidFormat = "invalid_format"
+)
+
+// WriteRejection is a write, or a line of it, that was rejected.
+type WriteRejection struct {
+	OrgID ID
+	// BucketID is invalid if the bucket of the write could not be found.
+	BucketID ID
+	// Reason is one of the WriteRejection reasons.
+	Reason string
+	// TokenHash identifies the token the write was made with without revealing it.
+	TokenHash string
+	// Line is the offending line, if the rejection is caused by one.
+	Line []byte
+	// Message describes the error of the rejection.
+	Message string
+}
+
+// WriteRejectionRecorder records rejected writes, so operators can find the clients
+// sending data that is not accepted.
+type WriteRejectionRecorder interface {
+	// RecordWriteRejection records the rejection. It must not block the write.
+	RecordWriteRejection(ctx context.Context, r WriteRejection)
+}
+
+// HashToken returns the hash the rejections of writes made with the token are recorded under.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,131 @@
+// Package writehook is the registry of the hooks that inspect, transform or
+// reject the points written to buckets.
+//
+// Hooks are compiled in: a build that needs custom validation of writes
+// registers its hooks from the init function of a package it imports, and
+// buckets opt into hooks by name, so that the write path does not have to be
+// forked to enforce the rules of a deployment:
+//
+//	func init() {
+//		writehook.Register("require-host", writehook.HookFunc(requireHost))
+//	}
+package writehook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+)
+
+// A Hook processes the points of a line of line protocol written to a bucket
+// before they are validated and stored.
+//
+// The points are the ones of the line as written, with their measurements,
+// tags and fields. A hook returns the points to write instead, which may be
+// fewer, more or modified points, or an error to reject the line. Hooks are
+// called concurrently for different writes.
+type Hook interface {
+	ProcessPoints(ctx context.Context, b *influxdb.Bucket, points []models.Point) ([]models.Point, error)
+}
+
+// HookFunc adapts a function to a Hook.
+type HookFunc func(ctx context.Context, b *influxdb.Bucket, points []models.Point) ([]models.Point, error)
+
+// ProcessPoints calls f.
+func (f HookFunc) ProcessPoints(ctx context.Context, b *influxdb.Bucket, points []models.Point) ([]models.Point, error) {
+	return f(ctx, b, points)
+}
+
+var (
+	mu    sync.RWMutex
+	hooks = make(map[string]Hook)
+)
+
+// Register makes a hook available to buckets by name.
+// It panics if a hook is registered twice with the same name, or is nil.
+func Register(name string, h Hook) {
+	mu.Lock()
+	defer mu.Unlock()
+	if h == nil {
+		panic("writehook: Register hook is nil")
+	}
+	if _, dup := hooks[name]; dup {
+		panic("writehook: Register called twice for hook " + name)
+	}
+	hooks[name] = h
+}
+
+// Lookup returns the hook registered with a name.
+func Lookup(name string) (Hook, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	h, ok := hooks[name]
+	return h, ok
+}
+
+// Names returns the sorted names of the registered hooks.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Valid returns an error if a name is not the one of a registered hook.
+func Valid(names []string) error {
+	for _, name := range names {
+		if _, ok := Lookup(name); !ok {
+			return &influxdb.Error{
+				Code: influxdb.EInvalid,
+				Msg:  fmt.Sprintf("write hook %q is not registered", name),
+			}
+		}
+	}
+	return nil
+}
+
+// Chain returns a hook that runs the hooks registered with names in order,
+// each on the points the one before it returned, or nil if there are no names.
+// The error of a hook is prefixed with its name.
+func Chain(names []string) (Hook, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	type named struct {
+		name string
+		hook Hook
+	}
+	chain := make([]named, 0, len(names))
+	for _, name := range names {
+		h, ok := Lookup(name)
+		if !ok {
+			return nil, &influxdb.Error{
+				Code: influxdb.EInternal,
+				Msg:  fmt.Sprintf("write hook %q is not registered", name),
+			}
+		}
+		chain = append(chain, named{name: name, hook: h})
+	}
+
+	return HookFunc(func(ctx context.Context, b *influxdb.Bucket, points []models.Point) ([]models.Point, error) {
+		for _, h := range chain {
+			var err error
+			if points, err = h.hook.ProcessPoints(ctx, b, points); err != nil {
+				return nil, fmt.Errorf("write hook %q: %v", h.name, err)
+			}
+			if len(points) == 0 {
+				break
+			}
+		}
+		return points, nil
+	}), nil
+}
@@ -0,0 +1,91 @@
+package writehook_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/writehook"
+)
+
+func TestRegister(t *testing.T) {
+	writehook.Register("test-register", writehook.HookFunc(func(_ context.Context, _ *influxdb.Bucket, points []models.Point) ([]models.Point, error) {
+		return points, nil
+	}))
+
+	if _, ok := writehook.Lookup("test-register"); !ok {
+		t.Error("expected the hook to be registered")
+	}
+	if _, ok := writehook.Lookup("test-unregistered"); ok {
+		t.Error("expected no hook to be registered with an unknown name")
+	}
+
+	if err := writehook.Valid([]string{"test-register"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := writehook.Valid([]string{"test-register", "test-unregistered"}); influxdb.ErrorCode(err) != influxdb.EInvalid {
+		t.Errorf("got error %v, want an invalid error", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a hook twice to panic")
+		}
+	}()
+	writehook.Register("test-register", writehook.HookFunc(nil))
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	writehook.Register("test-chain-drop", writehook.HookFunc(func(_ context.Context, _ *influxdb.Bucket, points []models.Point) ([]models.Point, error) {
+		calls = append(calls, "drop")
+		return points[1:], nil
+	}))
+	writehook.Register("test-chain-reject", writehook.HookFunc(func(_ context.Context, _ *influxdb.Bucket, points []models.Point) ([]models.Point, error) {
+		calls = append(calls, "reject")
+		if len(points) > 1 {
+			return nil, errors.New("too many points")
+		}
+		return points, nil
+	}))
+
+	points := []models.Point{
+		models.MustNewPoint("m", nil, models.Fields{"f": 1.0}, time.Unix(0, 1)),
+		models.MustNewPoint("m", nil, models.Fields{"f": 2.0}, time.Unix(0, 2)),
+		models.MustNewPoint("m", nil, models.Fields{"f": 3.0}, time.Unix(0, 3)),
+	}
+
+	hook, err := writehook.Chain([]string{"test-chain-drop", "test-chain-reject"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hook.ProcessPoints(context.Background(), &influxdb.Bucket{}, points); err == nil || err.Error() != `write hook "test-chain-reject": too many points` {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	hook, err = writehook.Chain([]string{"test-chain-drop", "test-chain-drop", "test-chain-reject"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := hook.ProcessPoints(context.Background(), &influxdb.Bucket{}, points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, points[2:]) {
+		t.Errorf("got points %v, want %v", got, points[2:])
+	}
+	if want := []string{"drop", "reject", "drop", "drop", "reject"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("got calls %v, want %v", calls, want)
+	}
+
+	if hook, err := writehook.Chain(nil); hook != nil || err != nil {
+		t.Errorf("got hook %v and error %v, want neither", hook, err)
+	}
+	if _, err := writehook.Chain([]string{"test-unregistered"}); influxdb.ErrorCode(err) != influxdb.EInternal {
+		t.Errorf("got error %v, want an internal error", err)
+	}
+}